@@ -24,24 +24,42 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+// READ implies WATCH and TXN_COMPARE, WRITE implies DELETE and LEASE_ATTACH.
 type Permission_Type int32
 
 const (
 	READ      Permission_Type = 0
 	WRITE     Permission_Type = 1
 	READWRITE Permission_Type = 2
+	// WATCH allows watching keys from the current revision on, without
+	// previous key-values.
+	WATCH Permission_Type = 3
+	// DELETE allows deleting keys.
+	DELETE Permission_Type = 4
+	// LEASE_ATTACH allows attaching leases to keys with puts ignoring the value.
+	LEASE_ATTACH Permission_Type = 5
+	// TXN_COMPARE allows comparing keys in transactions.
+	TXN_COMPARE Permission_Type = 6
 )
 
 var Permission_Type_name = map[int32]string{
 	0: "READ",
 	1: "WRITE",
 	2: "READWRITE",
+	3: "WATCH",
+	4: "DELETE",
+	5: "LEASE_ATTACH",
+	6: "TXN_COMPARE",
 }
 
 var Permission_Type_value = map[string]int32{
-	"READ":      0,
-	"WRITE":     1,
-	"READWRITE": 2,
+	"READ":         0,
+	"WRITE":        1,
+	"READWRITE":    2,
+	"WATCH":        3,
+	"DELETE":       4,
+	"LEASE_ATTACH": 5,
+	"TXN_COMPARE":  6,
 }
 
 func (x Permission_Type) String() string {
@@ -94,13 +112,15 @@ var xxx_messageInfo_UserAddOptions proto.InternalMessageInfo
 
 // User is a single entry in the bucket authUsers
 type User struct {
-	Name                 []byte          `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Password             []byte          `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
-	Roles                []string        `protobuf:"bytes,3,rep,name=roles,proto3" json:"roles,omitempty"`
-	Options              *UserAddOptions `protobuf:"bytes,4,opt,name=options,proto3" json:"options,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
-	XXX_unrecognized     []byte          `json:"-"`
-	XXX_sizecache        int32           `json:"-"`
+	Name     []byte          `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Password []byte          `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	Roles    []string        `protobuf:"bytes,3,rep,name=roles,proto3" json:"roles,omitempty"`
+	Options  *UserAddOptions `protobuf:"bytes,4,opt,name=options,proto3" json:"options,omitempty"`
+	// password_changed_time is the unix time the password was last set, 0 if unknown.
+	PasswordChangedTime  int64    `protobuf:"varint,5,opt,name=password_changed_time,json=passwordChangedTime,proto3" json:"password_changed_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *User) Reset()         { *m = User{} }
@@ -232,30 +252,35 @@ func init() {
 func init() { proto.RegisterFile("auth.proto", fileDescriptor_8bbd6f3875b0e874) }
 
 var fileDescriptor_8bbd6f3875b0e874 = []byte{
-	// 359 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xcf, 0x4e, 0xc2, 0x40,
-	0x10, 0xc6, 0xbb, 0xb4, 0x60, 0x3b, 0x08, 0x21, 0x1b, 0xa2, 0x0d, 0xc6, 0xda, 0xf4, 0xd4, 0x78,
-	0x68, 0x15, 0x0e, 0x7a, 0xc5, 0xc8, 0xc1, 0x93, 0x64, 0x83, 0x31, 0xf1, 0x42, 0x8a, 0xdd, 0xd4,
-	0x06, 0xd8, 0x6d, 0xda, 0xaa, 0xe1, 0xe2, 0x73, 0x78, 0xf0, 0x81, 0x38, 0xf2, 0x08, 0x82, 0x2f,
-	0x62, 0xba, 0xcb, 0x9f, 0x10, 0x3d, 0xed, 0x37, 0xdf, 0x7c, 0x33, 0xfb, 0xcb, 0x2e, 0x40, 0xf0,
-	0x9a, 0xbf, 0x78, 0x49, 0xca, 0x73, 0x8e, 0x2b, 0x85, 0x4e, 0x46, 0xad, 0x66, 0xc4, 0x23, 0x2e,
-	0x2c, 0xbf, 0x50, 0xb2, 0xeb, 0x5c, 0x42, 0xfd, 0x21, 0xa3, 0x69, 0x37, 0x0c, 0xef, 0x93, 0x3c,
-	0xe6, 0x2c, 0xc3, 0x67, 0x50, 0x65, 0x7c, 0x98, 0x04, 0x59, 0xf6, 0xce, 0xd3, 0xd0, 0x44, 0x36,
-	0x72, 0x75, 0x02, 0x8c, 0xf7, 0xd7, 0x8e, 0xf3, 0x01, 0x5a, 0x31, 0x82, 0x31, 0x68, 0x2c, 0x98,
-	0x52, 0x91, 0x38, 0x24, 0x42, 0xe3, 0x16, 0xe8, 0xdb, 0xc9, 0x92, 0xf0, 0xb7, 0x35, 0x6e, 0x42,
-	0x39, 0xe5, 0x13, 0x9a, 0x99, 0xaa, 0xad, 0xba, 0x06, 0x91, 0x05, 0xbe, 0x80, 0x03, 0x2e, 0x6f,
-	0x36, 0x35, 0x1b, 0xb9, 0xd5, 0xf6, 0x91, 0x27, 0x81, 0xbd, 0x7d, 0x2e, 0xb2, 0x89, 0x39, 0x5f,
-	0x08, 0xa0, 0x4f, 0xd3, 0x69, 0x9c, 0x65, 0x31, 0x67, 0xb8, 0x03, 0x7a, 0x42, 0xd3, 0xe9, 0x60,
-	0x96, 0x48, 0x94, 0x7a, 0xfb, 0x78, 0xb3, 0x61, 0x97, 0xf2, 0x8a, 0x36, 0xd9, 0x06, 0x71, 0x03,
-	0xd4, 0x31, 0x9d, 0xad, 0x11, 0x0b, 0x89, 0x4f, 0xc0, 0x48, 0x03, 0x16, 0xd1, 0x21, 0x65, 0xa1,
-	0xa9, 0x4a, 0x74, 0x61, 0xf4, 0x58, 0xe8, 0x9c, 0x83, 0x26, 0xc6, 0x74, 0xd0, 0x48, 0xaf, 0x7b,
-	0xdb, 0x50, 0xb0, 0x01, 0xe5, 0x47, 0x72, 0x37, 0xe8, 0x35, 0x10, 0xae, 0x81, 0x51, 0x98, 0xb2,
-	0x2c, 0x39, 0x03, 0xd0, 0x08, 0x9f, 0xd0, 0x7f, 0x9f, 0xe7, 0x1a, 0x6a, 0x63, 0x3a, 0xdb, 0x61,
-	0x99, 0x25, 0x5b, 0x75, 0xab, 0x6d, 0xfc, 0x17, 0x98, 0xec, 0x07, 0x6f, 0xae, 0xe6, 0x4b, 0x4b,
-	0x59, 0x2c, 0x2d, 0x65, 0xbe, 0xb2, 0xd0, 0x62, 0x65, 0xa1, 0xef, 0x95, 0x85, 0x3e, 0x7f, 0x2c,
-	0xe5, 0xe9, 0x34, 0xe2, 0x1e, 0xcd, 0x9f, 0x43, 0x2f, 0xe6, 0x7e, 0x71, 0xfa, 0x41, 0x12, 0xfb,
-	0x6f, 0x1d, 0x5f, 0xae, 0x1c, 0x55, 0xc4, 0x3f, 0x77, 0x7e, 0x03, 0x00, 0x00, 0xff, 0xff, 0x61,
-	0x5a, 0xfe, 0x48, 0x13, 0x02, 0x00, 0x00,
+	// 434 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x52, 0xcd, 0x6e, 0xd3, 0x40,
+	0x18, 0xcc, 0xc6, 0x4e, 0x48, 0xbe, 0xb4, 0xc5, 0xfa, 0x28, 0x60, 0x15, 0x61, 0x2c, 0x9f, 0x7c,
+	0xb2, 0x21, 0x39, 0xc0, 0xd5, 0xa4, 0x2b, 0x15, 0xa9, 0xd0, 0x68, 0x59, 0x04, 0xe2, 0x62, 0xb9,
+	0xf5, 0x2a, 0xb5, 0x5a, 0x7b, 0x2d, 0xdb, 0x80, 0xf2, 0x26, 0x3c, 0x07, 0x4f, 0xd1, 0x63, 0x4f,
+	0x9c, 0x69, 0x78, 0x11, 0xe4, 0xdd, 0x3a, 0x55, 0x44, 0x4f, 0x9e, 0x6f, 0x66, 0xbe, 0x9f, 0x91,
+	0x17, 0x20, 0xf9, 0xd6, 0x9c, 0x07, 0x65, 0x25, 0x1b, 0x89, 0xc3, 0x16, 0x97, 0xa7, 0x07, 0xfb,
+	0x4b, 0xb9, 0x94, 0x8a, 0x0a, 0x5b, 0xa4, 0x55, 0xef, 0x15, 0xec, 0x7d, 0xaa, 0x45, 0x15, 0xa5,
+	0xe9, 0x49, 0xd9, 0x64, 0xb2, 0xa8, 0xf1, 0x05, 0x4c, 0x0a, 0x19, 0x97, 0x49, 0x5d, 0xff, 0x90,
+	0x55, 0x6a, 0x13, 0x97, 0xf8, 0x23, 0x06, 0x85, 0x5c, 0xdc, 0x32, 0xde, 0x2f, 0x02, 0x66, 0xdb,
+	0x83, 0x08, 0x66, 0x91, 0xe4, 0x42, 0x59, 0x76, 0x98, 0xc2, 0x78, 0x00, 0xa3, 0x4d, 0x6b, 0x5f,
+	0xf1, 0x9b, 0x1a, 0xf7, 0x61, 0x50, 0xc9, 0x4b, 0x51, 0xdb, 0x86, 0x6b, 0xf8, 0x63, 0xa6, 0x0b,
+	0x7c, 0x09, 0x0f, 0xa4, 0x5e, 0x6d, 0x9b, 0x2e, 0xf1, 0x27, 0xd3, 0x27, 0x81, 0xbe, 0x38, 0xd8,
+	0x3e, 0x8c, 0x75, 0x36, 0x9c, 0xc2, 0xe3, 0x6e, 0x66, 0x7c, 0x76, 0x9e, 0x14, 0x4b, 0x91, 0xc6,
+	0x4d, 0x96, 0x0b, 0x7b, 0xe0, 0x12, 0xdf, 0x60, 0x8f, 0x3a, 0x71, 0xae, 0x35, 0x9e, 0xe5, 0xc2,
+	0xfb, 0x4d, 0x00, 0x16, 0xa2, 0xca, 0xb3, 0xba, 0xce, 0x64, 0x81, 0x33, 0x18, 0x95, 0xa2, 0xca,
+	0xf9, 0xaa, 0xd4, 0xe7, 0xef, 0x4d, 0x9f, 0x76, 0x5b, 0xef, 0x5c, 0x41, 0x2b, 0xb3, 0x8d, 0x11,
+	0x2d, 0x30, 0x2e, 0xc4, 0xea, 0x36, 0x56, 0x0b, 0xf1, 0x19, 0x8c, 0xab, 0x76, 0x47, 0x2c, 0x8a,
+	0xd4, 0x36, 0x74, 0x5c, 0x45, 0xd0, 0x22, 0xf5, 0x52, 0x30, 0x55, 0xdb, 0x08, 0x4c, 0x46, 0xa3,
+	0x43, 0xab, 0x87, 0x63, 0x18, 0x7c, 0x66, 0xef, 0x38, 0xb5, 0x08, 0xee, 0xc2, 0xb8, 0x25, 0x75,
+	0xd9, 0x57, 0x4a, 0xc4, 0xe7, 0x47, 0x96, 0x81, 0x00, 0xc3, 0x43, 0x7a, 0x4c, 0x39, 0xb5, 0x4c,
+	0xb4, 0x60, 0xe7, 0x98, 0x46, 0x1f, 0x69, 0x1c, 0x71, 0x1e, 0xcd, 0x8f, 0xac, 0x01, 0x3e, 0x84,
+	0x09, 0xff, 0xf2, 0x21, 0x9e, 0x9f, 0xbc, 0x5f, 0x44, 0x8c, 0x5a, 0x43, 0x8f, 0x83, 0xc9, 0xe4,
+	0xa5, 0xb8, 0xf7, 0x67, 0xbc, 0x81, 0xdd, 0x0b, 0xb1, 0xba, 0x0b, 0x64, 0xf7, 0x5d, 0xc3, 0x9f,
+	0x4c, 0xf1, 0xff, 0xa8, 0x6c, 0xdb, 0xf8, 0xf6, 0xf5, 0xd5, 0x8d, 0xd3, 0xbb, 0xbe, 0x71, 0x7a,
+	0x57, 0x6b, 0x87, 0x5c, 0xaf, 0x1d, 0xf2, 0x67, 0xed, 0x90, 0x9f, 0x7f, 0x9d, 0xde, 0xd7, 0xe7,
+	0x4b, 0x19, 0x88, 0xe6, 0x2c, 0x0d, 0x32, 0x19, 0xb6, 0xdf, 0x30, 0x29, 0xb3, 0xf0, 0xfb, 0x2c,
+	0xd4, 0x23, 0x4f, 0x87, 0xea, 0x59, 0xcd, 0xfe, 0x0d, 0x00, 0x8b, 0x98, 0xe4, 0xa7, 0x82, 0x02,
+	0x00, 0x00,
 }
 
 func (m *UserAddOptions) Marshal() (dAtA []byte, err error) {
@@ -319,6 +344,11 @@ func (m *User) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.PasswordChangedTime != 0 {
+		i = encodeVarintAuth(dAtA, i, uint64(m.PasswordChangedTime))
+		i--
+		dAtA[i] = 0x28
+	}
 	if m.Options != nil {
 		{
 			size, err := m.Options.MarshalToSizedBuffer(dAtA[:i])
@@ -501,6 +531,9 @@ func (m *User) Size() (n int) {
 		l = m.Options.Size()
 		n += 1 + l + sovAuth(uint64(l))
 	}
+	if m.PasswordChangedTime != 0 {
+		n += 1 + sovAuth(uint64(m.PasswordChangedTime))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -794,6 +827,25 @@ func (m *User) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PasswordChangedTime", wireType)
+			}
+			m.PasswordChangedTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAuth
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PasswordChangedTime |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipAuth(dAtA[iNdEx:])
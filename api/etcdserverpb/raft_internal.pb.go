@@ -31,7 +31,13 @@ type RequestHeader struct {
 	// username is a username that is associated with an auth token of gRPC connection
 	Username string `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
 	// auth_revision is a revision number of auth.authStore. It is not related to mvcc
-	AuthRevision         uint64   `protobuf:"varint,3,opt,name=auth_revision,json=authRevision,proto3" json:"auth_revision,omitempty"`
+	AuthRevision uint64 `protobuf:"varint,3,opt,name=auth_revision,json=authRevision,proto3" json:"auth_revision,omitempty"`
+	// trace_context carries the OpenTelemetry trace context of the proposing
+	// request, so the members applying it can continue its trace.
+	TraceContext map[string]string `protobuf:"bytes,4,rep,name=trace_context,json=traceContext,proto3" json:"trace_context,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// roles are the roles of a user authenticated by an external identity
+	// provider. When set, username does not refer to a user of auth.authStore.
+	Roles                []string `protobuf:"bytes,5,rep,name=roles,proto3" json:"roles,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -89,6 +95,8 @@ type InternalRaftRequest struct {
 	AuthDisable              *AuthDisableRequest                       `protobuf:"bytes,1011,opt,name=auth_disable,json=authDisable,proto3" json:"auth_disable,omitempty"`
 	AuthStatus               *AuthStatusRequest                        `protobuf:"bytes,1013,opt,name=auth_status,json=authStatus,proto3" json:"auth_status,omitempty"`
 	Authenticate             *InternalAuthenticateRequest              `protobuf:"bytes,1012,opt,name=authenticate,proto3" json:"authenticate,omitempty"`
+	AuthTokenRevoke          *InternalAuthTokenRevokeRequest           `protobuf:"bytes,1014,opt,name=auth_token_revoke,json=authTokenRevoke,proto3" json:"auth_token_revoke,omitempty"`
+	ReadOnly                 *ReadOnlyRequest                          `protobuf:"bytes,1015,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
 	AuthUserAdd              *AuthUserAddRequest                       `protobuf:"bytes,1100,opt,name=auth_user_add,json=authUserAdd,proto3" json:"auth_user_add,omitempty"`
 	AuthUserDelete           *AuthUserDeleteRequest                    `protobuf:"bytes,1101,opt,name=auth_user_delete,json=authUserDelete,proto3" json:"auth_user_delete,omitempty"`
 	AuthUserGet              *AuthUserGetRequest                       `protobuf:"bytes,1102,opt,name=auth_user_get,json=authUserGet,proto3" json:"auth_user_get,omitempty"`
@@ -229,86 +237,153 @@ func (m *InternalAuthenticateRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_InternalAuthenticateRequest proto.InternalMessageInfo
 
+// InternalAuthTokenRevokeRequest is an AuthTokenRevokeRequest filled by
+// etcdserver, so that the raft log records a digest of the revoked token
+// instead of the token.
+type InternalAuthTokenRevokeRequest struct {
+	// name is the user the token was issued to.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// token_hash is the SHA-256 digest of the token.
+	TokenHash []byte `protobuf:"bytes,2,opt,name=token_hash,json=tokenHash,proto3" json:"token_hash,omitempty"`
+	// expire_time is the unix time in seconds the token expires at, after
+	// which its revocation is forgotten.
+	ExpireTime int64 `protobuf:"varint,3,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	// revoke_time is the unix time in seconds the revocation was requested
+	// at. The revocations of tokens expired before it are purged.
+	RevokeTime           int64    `protobuf:"varint,4,opt,name=revoke_time,json=revokeTime,proto3" json:"revoke_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InternalAuthTokenRevokeRequest) Reset()         { *m = InternalAuthTokenRevokeRequest{} }
+func (m *InternalAuthTokenRevokeRequest) String() string { return proto.CompactTextString(m) }
+func (*InternalAuthTokenRevokeRequest) ProtoMessage()    {}
+func (*InternalAuthTokenRevokeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b4c9a9be0cfca103, []int{4}
+}
+func (m *InternalAuthTokenRevokeRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *InternalAuthTokenRevokeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_InternalAuthTokenRevokeRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *InternalAuthTokenRevokeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InternalAuthTokenRevokeRequest.Merge(m, src)
+}
+func (m *InternalAuthTokenRevokeRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *InternalAuthTokenRevokeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_InternalAuthTokenRevokeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InternalAuthTokenRevokeRequest proto.InternalMessageInfo
+
 func init() {
 	proto.RegisterType((*RequestHeader)(nil), "etcdserverpb.RequestHeader")
+	proto.RegisterMapType((map[string]string)(nil), "etcdserverpb.RequestHeader.TraceContextEntry")
 	proto.RegisterType((*InternalRaftRequest)(nil), "etcdserverpb.InternalRaftRequest")
 	proto.RegisterType((*EmptyResponse)(nil), "etcdserverpb.EmptyResponse")
 	proto.RegisterType((*InternalAuthenticateRequest)(nil), "etcdserverpb.InternalAuthenticateRequest")
+	proto.RegisterType((*InternalAuthTokenRevokeRequest)(nil), "etcdserverpb.InternalAuthTokenRevokeRequest")
 }
 
 func init() { proto.RegisterFile("raft_internal.proto", fileDescriptor_b4c9a9be0cfca103) }
 
 var fileDescriptor_b4c9a9be0cfca103 = []byte{
-	// 1101 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x56, 0xcb, 0x72, 0x1b, 0x45,
-	0x14, 0x8d, 0x6c, 0xc7, 0xb6, 0x5a, 0xb6, 0xe3, 0xb4, 0x9d, 0xa4, 0xb1, 0xab, 0x8c, 0xe3, 0x90,
-	0x60, 0x20, 0xc8, 0xc1, 0x06, 0xaa, 0x60, 0x03, 0x8a, 0xe5, 0x72, 0x4c, 0x25, 0x29, 0xd7, 0xc4,
-	0x50, 0x29, 0x28, 0x6a, 0x68, 0xcd, 0x5c, 0x4b, 0x13, 0x8f, 0x66, 0x86, 0xee, 0x96, 0xe2, 0x6c,
-	0x59, 0xb2, 0x06, 0x8a, 0x8f, 0x60, 0xc1, 0x2b, 0xff, 0x90, 0x05, 0x8f, 0x00, 0x3f, 0x00, 0x66,
-	0xc3, 0x1e, 0xd8, 0xa7, 0xfa, 0x31, 0x2f, 0xa9, 0xe5, 0xdd, 0xe8, 0xde, 0x73, 0xcf, 0x39, 0xdd,
-	0x7d, 0xbb, 0x75, 0xd1, 0x02, 0xa3, 0x87, 0xc2, 0x0d, 0x22, 0x01, 0x2c, 0xa2, 0x61, 0x3d, 0x61,
-	0xb1, 0x88, 0xf1, 0x0c, 0x08, 0xcf, 0xe7, 0xc0, 0xfa, 0xc0, 0x92, 0xd6, 0xd2, 0x62, 0x3b, 0x6e,
-	0xc7, 0x2a, 0xb1, 0x21, 0xbf, 0x34, 0x66, 0x69, 0x3e, 0xc7, 0x98, 0x48, 0x95, 0x25, 0x9e, 0xf9,
-	0x5c, 0x95, 0xc9, 0x0d, 0x9a, 0x04, 0x1b, 0x7d, 0x60, 0x3c, 0x88, 0xa3, 0xa4, 0x95, 0x7e, 0x19,
-	0xc4, 0xb5, 0x0c, 0xd1, 0x85, 0x6e, 0x0b, 0x18, 0xef, 0x04, 0x49, 0xd2, 0x2a, 0xfc, 0xd0, 0xb8,
-	0x35, 0x86, 0x66, 0x1d, 0xf8, 0xb4, 0x07, 0x5c, 0xdc, 0x02, 0xea, 0x03, 0xc3, 0x73, 0x68, 0x6c,
-	0xaf, 0x49, 0x2a, 0xab, 0x95, 0xf5, 0x09, 0x67, 0x6c, 0xaf, 0x89, 0x97, 0xd0, 0x74, 0x8f, 0x4b,
-	0xf3, 0x5d, 0x20, 0x63, 0xab, 0x95, 0xf5, 0xaa, 0x93, 0xfd, 0xc6, 0xd7, 0xd1, 0x2c, 0xed, 0x89,
-	0x8e, 0xcb, 0xa0, 0x1f, 0x48, 0x6d, 0x32, 0x2e, 0xcb, 0x6e, 0x4e, 0x7d, 0xfe, 0x98, 0x8c, 0x6f,
-	0xd5, 0x5f, 0x73, 0x66, 0x64, 0xd6, 0x31, 0xc9, 0xb7, 0xa7, 0x3e, 0x53, 0xe1, 0x1b, 0x6b, 0x8f,
-	0x17, 0xd0, 0xc2, 0x9e, 0xd9, 0x11, 0x87, 0x1e, 0x0a, 0x63, 0x00, 0x6f, 0xa1, 0xc9, 0x8e, 0x32,
-	0x41, 0xfc, 0xd5, 0xca, 0x7a, 0x6d, 0x73, 0xb9, 0x5e, 0xdc, 0xa7, 0x7a, 0xc9, 0xa7, 0x63, 0xa0,
-	0x43, 0x7e, 0xaf, 0xa2, 0xb1, 0xfe, 0xa6, 0x72, 0x5a, 0xdb, 0xbc, 0x60, 0x25, 0x70, 0xc6, 0xfa,
-	0x9b, 0xf8, 0x06, 0x3a, 0xcb, 0x68, 0xd4, 0x06, 0x65, 0xb9, 0xb6, 0xb9, 0x34, 0x80, 0x94, 0xa9,
-	0x14, 0xae, 0x81, 0xf8, 0x65, 0x34, 0x9e, 0xf4, 0x04, 0x99, 0x50, 0x78, 0x52, 0xc6, 0xef, 0xf7,
-	0xd2, 0x45, 0x38, 0x12, 0x84, 0xb7, 0xd1, 0x8c, 0x0f, 0x21, 0x08, 0x70, 0xb5, 0xc8, 0x59, 0x55,
-	0xb4, 0x5a, 0x2e, 0x6a, 0x2a, 0x44, 0x49, 0xaa, 0xe6, 0xe7, 0x31, 0x29, 0x28, 0x8e, 0x23, 0x32,
-	0x69, 0x13, 0x3c, 0x38, 0x8e, 0x32, 0x41, 0x71, 0x1c, 0xe1, 0x77, 0x10, 0xf2, 0xe2, 0x6e, 0x42,
-	0x3d, 0x21, 0x8f, 0x61, 0x4a, 0x95, 0x3c, 0x5f, 0x2e, 0xd9, 0xce, 0xf2, 0x69, 0x65, 0xa1, 0x04,
-	0xbf, 0x8b, 0x6a, 0x21, 0x50, 0x0e, 0x6e, 0x9b, 0xd1, 0x48, 0x90, 0x69, 0x1b, 0xc3, 0x6d, 0x09,
-	0xd8, 0x95, 0xf9, 0x8c, 0x21, 0xcc, 0x42, 0x72, 0xcd, 0x9a, 0x81, 0x41, 0x3f, 0x3e, 0x02, 0x52,
-	0xb5, 0xad, 0x59, 0x51, 0x38, 0x0a, 0x90, 0xad, 0x39, 0xcc, 0x63, 0xf2, 0x58, 0x68, 0x48, 0x59,
-	0x97, 0x20, 0xdb, 0xb1, 0x34, 0x64, 0x2a, 0x3b, 0x16, 0x05, 0xc4, 0xf7, 0xd1, 0xbc, 0x96, 0xf5,
-	0x3a, 0xe0, 0x1d, 0x25, 0x71, 0x10, 0x09, 0x52, 0x53, 0xc5, 0x2f, 0x58, 0xa4, 0xb7, 0x33, 0x90,
-	0xa1, 0x49, 0x9b, 0xf5, 0x75, 0xe7, 0x5c, 0x58, 0x06, 0xe0, 0x06, 0xaa, 0xa9, 0xee, 0x86, 0x88,
-	0xb6, 0x42, 0x20, 0xff, 0x58, 0x77, 0xb5, 0xd1, 0x13, 0x9d, 0x1d, 0x05, 0xc8, 0xf6, 0x84, 0x66,
-	0x21, 0xdc, 0x44, 0xea, 0x0a, 0xb8, 0x7e, 0xc0, 0x15, 0xc7, 0xbf, 0x53, 0xb6, 0x4d, 0x91, 0x1c,
-	0x4d, 0x8d, 0xc8, 0x36, 0x85, 0xe6, 0x31, 0xfc, 0x9e, 0x31, 0xc2, 0x05, 0x15, 0x3d, 0x4e, 0xfe,
-	0x1f, 0x69, 0xe4, 0x9e, 0x02, 0x0c, 0xac, 0xec, 0x0d, 0xed, 0x48, 0xe7, 0xf0, 0x5d, 0xed, 0x08,
-	0x22, 0x11, 0x78, 0x54, 0x00, 0xf9, 0x4f, 0x93, 0xbd, 0x54, 0x26, 0x4b, 0x6f, 0x67, 0xa3, 0x00,
-	0x4d, 0xad, 0x95, 0xea, 0xf1, 0x8e, 0x79, 0x02, 0xe4, 0x9b, 0xe0, 0x52, 0xdf, 0x27, 0x3f, 0x4d,
-	0x8f, 0x5a, 0xe2, 0xfb, 0x1c, 0x58, 0xc3, 0xf7, 0x4b, 0x4b, 0x34, 0x31, 0x7c, 0x17, 0xcd, 0xe7,
-	0x34, 0xfa, 0x12, 0x90, 0x9f, 0x35, 0xd3, 0x15, 0x3b, 0x93, 0xb9, 0x3d, 0x86, 0x6c, 0x8e, 0x96,
-	0xc2, 0x65, 0x5b, 0x6d, 0x10, 0xe4, 0x97, 0x53, 0x6d, 0xed, 0x82, 0x18, 0xb2, 0xb5, 0x0b, 0x02,
-	0xb7, 0xd1, 0x73, 0x39, 0x8d, 0xd7, 0x91, 0xd7, 0xd2, 0x4d, 0x28, 0xe7, 0x0f, 0x63, 0xe6, 0x93,
-	0x5f, 0x35, 0xe5, 0x2b, 0x76, 0xca, 0x6d, 0x85, 0xde, 0x37, 0xe0, 0x94, 0xfd, 0x22, 0xb5, 0xa6,
-	0xf1, 0x7d, 0xb4, 0x58, 0xf0, 0x2b, 0xef, 0x93, 0xcb, 0xe2, 0x10, 0xc8, 0x53, 0xad, 0x71, 0x6d,
-	0x84, 0x6d, 0x75, 0x17, 0xe3, 0xbc, 0x6d, 0xce, 0xd3, 0xc1, 0x0c, 0xfe, 0x08, 0x5d, 0xc8, 0x99,
-	0xf5, 0xd5, 0xd4, 0xd4, 0xbf, 0x69, 0xea, 0x17, 0xed, 0xd4, 0xe6, 0x8e, 0x16, 0xb8, 0x31, 0x1d,
-	0x4a, 0xe1, 0x5b, 0x68, 0x2e, 0x27, 0x0f, 0x03, 0x2e, 0xc8, 0xef, 0x9a, 0xf5, 0xb2, 0x9d, 0xf5,
-	0x76, 0xc0, 0x45, 0xa9, 0x8f, 0xd2, 0x60, 0xc6, 0x24, 0xad, 0x69, 0xa6, 0x3f, 0x46, 0x32, 0x49,
-	0xe9, 0x21, 0xa6, 0x34, 0x98, 0x1d, 0xbd, 0x62, 0x92, 0x1d, 0xf9, 0x6d, 0x75, 0xd4, 0xd1, 0xcb,
-	0x9a, 0xc1, 0x8e, 0x34, 0xb1, 0xac, 0x23, 0x15, 0x8d, 0xe9, 0xc8, 0xef, 0xaa, 0xa3, 0x3a, 0x52,
-	0x56, 0x59, 0x3a, 0x32, 0x0f, 0x97, 0x6d, 0xc9, 0x8e, 0xfc, 0xfe, 0x54, 0x5b, 0x83, 0x1d, 0x69,
-	0x62, 0xf8, 0x01, 0x5a, 0x2a, 0xd0, 0xa8, 0x46, 0x49, 0x80, 0x75, 0x03, 0xae, 0xfe, 0x7f, 0x7f,
-	0xd0, 0x9c, 0xd7, 0x47, 0x70, 0x4a, 0xf8, 0x7e, 0x86, 0x4e, 0xf9, 0x2f, 0x51, 0x7b, 0x1e, 0x77,
-	0xd1, 0x72, 0xae, 0x65, 0x5a, 0xa7, 0x20, 0xf6, 0xa3, 0x16, 0x7b, 0xd5, 0x2e, 0xa6, 0xbb, 0x64,
-	0x58, 0x8d, 0xd0, 0x11, 0x00, 0xfc, 0x09, 0x5a, 0xf0, 0xc2, 0x1e, 0x17, 0xc0, 0x5c, 0x33, 0xcb,
-	0xb8, 0x1c, 0x04, 0xf9, 0x02, 0x99, 0x2b, 0x50, 0x1c, 0x64, 0xea, 0xdb, 0x1a, 0xf9, 0x81, 0x06,
-	0xde, 0x03, 0x31, 0xf4, 0xea, 0x9d, 0xf7, 0x06, 0x21, 0xf8, 0x01, 0xba, 0x94, 0x2a, 0x68, 0x32,
-	0x97, 0x0a, 0xc1, 0x94, 0xca, 0x97, 0xc8, 0xbc, 0x83, 0x36, 0x95, 0x3b, 0x2a, 0xd6, 0x10, 0x82,
-	0xd9, 0x84, 0x16, 0x3d, 0x0b, 0x0a, 0x7f, 0x8c, 0xb0, 0x1f, 0x3f, 0x8c, 0xda, 0x8c, 0xfa, 0xe0,
-	0x06, 0xd1, 0x61, 0xac, 0x64, 0xbe, 0xd2, 0x32, 0x57, 0xcb, 0x32, 0xcd, 0x14, 0xb8, 0x17, 0x1d,
-	0xc6, 0x36, 0x89, 0x79, 0x7f, 0x00, 0x81, 0x03, 0x74, 0x31, 0xa7, 0x4f, 0xb7, 0x4b, 0x00, 0x17,
-	0xe4, 0x9b, 0x3b, 0xb6, 0x17, 0x3d, 0x93, 0x30, 0xdb, 0x71, 0x00, 0x7c, 0x50, 0xe6, 0x4d, 0x67,
-	0xd1, 0xb7, 0xa0, 0xf2, 0xb9, 0xed, 0x1c, 0x9a, 0xdd, 0xe9, 0x26, 0xe2, 0x91, 0x03, 0x3c, 0x89,
-	0x23, 0x0e, 0x6b, 0x8f, 0xd0, 0xf2, 0x29, 0xff, 0x14, 0x18, 0xa3, 0x09, 0x35, 0x36, 0x56, 0xd4,
-	0xd8, 0xa8, 0xbe, 0xe5, 0x38, 0x99, 0x3d, 0xa0, 0x66, 0x9c, 0x4c, 0x7f, 0xe3, 0xcb, 0x68, 0x86,
-	0x07, 0xdd, 0x24, 0x04, 0x57, 0xc4, 0x47, 0xa0, 0xa7, 0xc9, 0xaa, 0x53, 0xd3, 0xb1, 0x03, 0x19,
-	0xca, 0xbc, 0xdc, 0x7c, 0xeb, 0xc9, 0x5f, 0x2b, 0x67, 0x9e, 0x9c, 0xac, 0x54, 0x9e, 0x9e, 0xac,
-	0x54, 0xfe, 0x3c, 0x59, 0xa9, 0x7c, 0xfd, 0xf7, 0xca, 0x99, 0x0f, 0xaf, 0xb4, 0x63, 0xb5, 0xec,
-	0x7a, 0x10, 0x6f, 0xe4, 0x23, 0xf2, 0xd6, 0x46, 0x71, 0x2b, 0x5a, 0x93, 0x6a, 0xf2, 0xdd, 0x7a,
-	0x16, 0x00, 0x00, 0xff, 0xff, 0xb2, 0xa0, 0x15, 0x1f, 0x9b, 0x0b, 0x00, 0x00,
+	// 1305 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x57, 0x4b, 0x73, 0x1b, 0x45,
+	0x10, 0x8e, 0x24, 0xbf, 0x34, 0xb2, 0x13, 0x7b, 0xe2, 0x24, 0x83, 0x53, 0x71, 0x94, 0x84, 0x04,
+	0x03, 0x89, 0x1c, 0x6c, 0x20, 0x90, 0x4b, 0x70, 0x2c, 0x57, 0x62, 0x2a, 0x09, 0xa9, 0x8d, 0xa1,
+	0x52, 0xa4, 0xa8, 0x65, 0xa4, 0x6d, 0x4b, 0x1b, 0xaf, 0x76, 0x97, 0x99, 0x91, 0x62, 0x5f, 0x39,
+	0x72, 0x06, 0x8a, 0x13, 0xbf, 0x80, 0x2a, 0xde, 0xff, 0x21, 0x07, 0x1e, 0x01, 0x7e, 0x00, 0x60,
+	0x2e, 0xdc, 0x79, 0x5d, 0xa9, 0x79, 0xec, 0xae, 0x56, 0x1a, 0xf9, 0x36, 0xdb, 0xfd, 0xf5, 0xf7,
+	0xf5, 0x4c, 0xf7, 0x3c, 0x16, 0x1d, 0x65, 0x74, 0x5b, 0xb8, 0x7e, 0x28, 0x80, 0x85, 0x34, 0xa8,
+	0xc5, 0x2c, 0x12, 0x11, 0x9e, 0x06, 0xd1, 0xf4, 0x38, 0xb0, 0x1e, 0xb0, 0xb8, 0xb1, 0x30, 0xdf,
+	0x8a, 0x5a, 0x91, 0x72, 0x2c, 0xcb, 0x91, 0xc6, 0x2c, 0xcc, 0x66, 0x18, 0x63, 0x29, 0xb3, 0xb8,
+	0x69, 0x86, 0x55, 0xe9, 0x5c, 0xa6, 0xb1, 0xbf, 0xdc, 0x03, 0xc6, 0xfd, 0x28, 0x8c, 0x1b, 0xc9,
+	0xc8, 0x20, 0x2e, 0xa4, 0x88, 0x0e, 0x74, 0x1a, 0xc0, 0x78, 0xdb, 0x8f, 0xe3, 0x46, 0xdf, 0x87,
+	0xc6, 0x9d, 0xfd, 0xbc, 0x88, 0x66, 0x1c, 0x78, 0xaf, 0x0b, 0x5c, 0xdc, 0x04, 0xea, 0x01, 0xc3,
+	0x87, 0x51, 0x71, 0xb3, 0x4e, 0x0a, 0xd5, 0xc2, 0xd2, 0x98, 0x53, 0xdc, 0xac, 0xe3, 0x05, 0x34,
+	0xd5, 0xe5, 0x32, 0xfb, 0x0e, 0x90, 0x62, 0xb5, 0xb0, 0x54, 0x76, 0xd2, 0x6f, 0x7c, 0x11, 0xcd,
+	0xd0, 0xae, 0x68, 0xbb, 0x0c, 0x7a, 0xbe, 0x14, 0x27, 0x25, 0x19, 0x76, 0x7d, 0xf2, 0x83, 0x6f,
+	0x49, 0x69, 0xb5, 0xf6, 0x82, 0x33, 0x2d, 0xbd, 0x8e, 0x71, 0xe2, 0x07, 0x68, 0x46, 0x30, 0xda,
+	0x04, 0xb7, 0x19, 0x85, 0x02, 0x76, 0x05, 0x19, 0xab, 0x96, 0x96, 0x2a, 0x2b, 0x97, 0x6a, 0xfd,
+	0xcb, 0x51, 0xcb, 0x65, 0x53, 0xdb, 0x92, 0x01, 0xeb, 0x1a, 0xbf, 0x11, 0x0a, 0xb6, 0x97, 0x90,
+	0x5f, 0x71, 0xa6, 0x45, 0x9f, 0x0f, 0x9f, 0x42, 0xe3, 0x2c, 0x0a, 0x80, 0x93, 0xf1, 0x6a, 0x69,
+	0xa9, 0x9c, 0xa1, 0xb4, 0x75, 0xe1, 0x1a, 0x9a, 0x1b, 0xa2, 0xc2, 0xb3, 0xa8, 0xb4, 0x03, 0x7b,
+	0x6a, 0xae, 0x65, 0x47, 0x0e, 0xf1, 0x3c, 0x1a, 0xef, 0xd1, 0xa0, 0x9b, 0xcc, 0x54, 0x7f, 0x5c,
+	0x2d, 0xbe, 0x52, 0xb8, 0x3a, 0xf9, 0xbe, 0x22, 0xbc, 0x7c, 0xf6, 0xd7, 0x79, 0x74, 0x74, 0xd3,
+	0xd4, 0xd3, 0xa1, 0xdb, 0xc2, 0xe4, 0x8b, 0x57, 0xd1, 0x44, 0x5b, 0xe5, 0x4c, 0xbc, 0x6a, 0x61,
+	0xa9, 0xb2, 0x72, 0xf2, 0x80, 0x69, 0x39, 0x13, 0x6d, 0xfb, 0x62, 0x9f, 0x47, 0xc5, 0xde, 0x8a,
+	0x12, 0xaf, 0xac, 0x1c, 0xb3, 0x12, 0x38, 0xc5, 0xde, 0x0a, 0xbe, 0x8c, 0xc6, 0x19, 0x0d, 0x5b,
+	0xa0, 0xd6, 0xbb, 0xb2, 0xb2, 0x30, 0x80, 0x94, 0xae, 0x04, 0xae, 0x81, 0xf8, 0x39, 0x54, 0x8a,
+	0xbb, 0x72, 0xc5, 0x25, 0x9e, 0xe4, 0xf1, 0x77, 0xbb, 0xc9, 0x24, 0x1c, 0x09, 0xc2, 0xeb, 0x68,
+	0xda, 0x83, 0x00, 0x04, 0xb8, 0x5a, 0x64, 0x5c, 0x05, 0x55, 0xf3, 0x41, 0x75, 0x85, 0xc8, 0x49,
+	0x55, 0xbc, 0xcc, 0x26, 0x05, 0xc5, 0x6e, 0x48, 0x26, 0x6c, 0x82, 0x5b, 0xbb, 0x61, 0x2a, 0x28,
+	0x76, 0x43, 0x7c, 0x0d, 0xa1, 0x66, 0xd4, 0x89, 0x69, 0x53, 0xc8, 0x1e, 0x9a, 0x54, 0x21, 0xa7,
+	0xf3, 0x21, 0xeb, 0xa9, 0x3f, 0x89, 0xec, 0x0b, 0xc1, 0xaf, 0xa1, 0x4a, 0x00, 0x94, 0x83, 0xdb,
+	0x62, 0x34, 0x14, 0x64, 0xca, 0xc6, 0x70, 0x4b, 0x02, 0x6e, 0x48, 0x7f, 0xca, 0x10, 0xa4, 0x26,
+	0x39, 0x67, 0xcd, 0xc0, 0xa0, 0x17, 0xed, 0x00, 0x29, 0xdb, 0xe6, 0xac, 0x28, 0x1c, 0x05, 0x48,
+	0xe7, 0x1c, 0x64, 0x36, 0x59, 0x16, 0x1a, 0x50, 0xd6, 0x21, 0xc8, 0x56, 0x96, 0x35, 0xe9, 0x4a,
+	0xcb, 0xa2, 0x80, 0xf8, 0x3e, 0x9a, 0xd5, 0xb2, 0xcd, 0x36, 0x34, 0x77, 0xe2, 0xc8, 0x0f, 0x05,
+	0xa9, 0xa8, 0xe0, 0xa7, 0x2d, 0xd2, 0xeb, 0x29, 0xc8, 0xd0, 0x24, 0x6d, 0xfe, 0xa2, 0x73, 0x24,
+	0xc8, 0x03, 0xf0, 0x1a, 0xaa, 0xa8, 0xad, 0x09, 0x21, 0x6d, 0x04, 0x40, 0xfe, 0xb4, 0xae, 0xea,
+	0x5a, 0x57, 0xb4, 0x37, 0x14, 0x20, 0x5d, 0x13, 0x9a, 0x9a, 0x70, 0x1d, 0xa9, 0xfd, 0xeb, 0x7a,
+	0x3e, 0x57, 0x1c, 0x7f, 0x4d, 0xda, 0x16, 0x45, 0x72, 0xd4, 0x7d, 0xde, 0x4f, 0x52, 0xa1, 0x99,
+	0x0d, 0xbf, 0x6e, 0x12, 0xe1, 0x82, 0x8a, 0x2e, 0x27, 0xff, 0x8c, 0x4c, 0xe4, 0x9e, 0x02, 0x0c,
+	0xcc, 0xec, 0x25, 0x9d, 0x91, 0xf6, 0xe1, 0x3b, 0x3a, 0x23, 0x08, 0x85, 0xdf, 0xa4, 0x02, 0xc8,
+	0xdf, 0x9a, 0xec, 0xd9, 0x3c, 0x59, 0xb2, 0x3b, 0xd7, 0xfa, 0xa0, 0x49, 0x6a, 0xb9, 0x78, 0xdc,
+	0x40, 0x73, 0x2a, 0x37, 0x11, 0xed, 0x40, 0x98, 0x94, 0xfe, 0x5f, 0x4d, 0x7a, 0x71, 0x34, 0xe9,
+	0x96, 0x84, 0xe7, 0xfa, 0x20, 0x3b, 0x6f, 0x8e, 0xd0, 0x3c, 0x00, 0xd7, 0x51, 0x99, 0x01, 0xf5,
+	0xdc, 0x28, 0x0c, 0xf6, 0xc8, 0x7f, 0x9a, 0xfb, 0xd4, 0xe0, 0xd6, 0xa6, 0xde, 0x1b, 0x61, 0xb0,
+	0x37, 0x44, 0x36, 0xc5, 0x8c, 0x07, 0x6f, 0x98, 0x93, 0xb6, 0xcb, 0x81, 0xb9, 0xd4, 0xf3, 0xc8,
+	0x77, 0x53, 0xa3, 0x8a, 0xf1, 0x26, 0x07, 0xb6, 0xe6, 0x79, 0xb9, 0x62, 0x18, 0x1b, 0xbe, 0x83,
+	0x66, 0x33, 0x1a, 0xbd, 0x5d, 0xc9, 0xf7, 0x9a, 0xe9, 0x9c, 0x9d, 0xc9, 0xec, 0x73, 0x43, 0x76,
+	0x98, 0xe6, 0xcc, 0xf9, 0xb4, 0x5a, 0x20, 0xc8, 0x0f, 0x07, 0xa6, 0x75, 0x03, 0xc4, 0x50, 0x5a,
+	0x37, 0x40, 0xe0, 0x16, 0x7a, 0x2a, 0xa3, 0x69, 0xb6, 0xe5, 0x01, 0xe2, 0xc6, 0x94, 0xf3, 0x47,
+	0x11, 0xf3, 0xc8, 0x8f, 0x9a, 0xf2, 0x79, 0x3b, 0xe5, 0xba, 0x42, 0xdf, 0x35, 0xe0, 0x84, 0xfd,
+	0x38, 0xb5, 0xba, 0xf1, 0x7d, 0x34, 0xdf, 0x97, 0xaf, 0xdc, 0xf9, 0xae, 0xbc, 0x1f, 0xc8, 0x13,
+	0xad, 0x71, 0x61, 0x44, 0xda, 0x12, 0xe8, 0x44, 0x59, 0x83, 0xcf, 0xd1, 0x41, 0x0f, 0x7e, 0x80,
+	0x8e, 0x65, 0xcc, 0xba, 0x93, 0x34, 0xf5, 0x4f, 0x9a, 0xfa, 0x19, 0x3b, 0xb5, 0xe9, 0xa2, 0x3e,
+	0x6e, 0x4c, 0x87, 0x5c, 0xf8, 0x26, 0x3a, 0x9c, 0x91, 0x07, 0x3e, 0x17, 0xe4, 0x67, 0xcd, 0x7a,
+	0xc6, 0xce, 0x7a, 0xcb, 0xe7, 0x22, 0xd7, 0xf1, 0x89, 0x31, 0x65, 0x92, 0xa9, 0x69, 0xa6, 0x5f,
+	0x46, 0x32, 0x49, 0xe9, 0x21, 0xa6, 0xc4, 0x98, 0x96, 0x5e, 0x31, 0xc9, 0x8e, 0xfc, 0xa2, 0x3c,
+	0xaa, 0xf4, 0x32, 0x66, 0xb0, 0x23, 0x8d, 0x2d, 0xed, 0x48, 0x45, 0x63, 0x3a, 0xf2, 0xcb, 0xf2,
+	0xa8, 0x8e, 0x94, 0x51, 0x96, 0x8e, 0xcc, 0xcc, 0xf9, 0xb4, 0x64, 0x47, 0x7e, 0x75, 0x60, 0x5a,
+	0x83, 0x1d, 0x69, 0x6c, 0xf8, 0x21, 0x5a, 0xe8, 0xa3, 0x51, 0x8d, 0x12, 0x03, 0xeb, 0xf8, 0x5c,
+	0x3d, 0x73, 0xbe, 0x2e, 0xdb, 0x8e, 0x88, 0x94, 0x53, 0xc2, 0xef, 0xa6, 0xe8, 0x84, 0xff, 0x04,
+	0xb5, 0xfb, 0x71, 0x07, 0x9d, 0xcc, 0xb4, 0x4c, 0xeb, 0xf4, 0x89, 0x7d, 0xa3, 0xc5, 0x2e, 0xd9,
+	0xc5, 0x74, 0x97, 0x0c, 0xab, 0x11, 0x3a, 0x02, 0x80, 0xdf, 0x45, 0x47, 0x9b, 0x41, 0x97, 0x0b,
+	0x60, 0xae, 0x79, 0x33, 0xba, 0x1c, 0x04, 0xf9, 0x10, 0x99, 0x2d, 0xd0, 0xff, 0x60, 0xac, 0xad,
+	0x6b, 0xe4, 0x5b, 0x1a, 0x78, 0x0f, 0xc4, 0xd0, 0xf9, 0x3c, 0xd7, 0x1c, 0x84, 0xe0, 0x87, 0xe8,
+	0x44, 0xa2, 0xa0, 0xc9, 0x5c, 0x2a, 0x04, 0x53, 0x2a, 0x1f, 0x21, 0x73, 0x62, 0xdb, 0x54, 0x6e,
+	0x2b, 0xdb, 0x9a, 0x10, 0xcc, 0x26, 0x34, 0xdf, 0xb4, 0xa0, 0xf0, 0x3b, 0x08, 0x7b, 0xd1, 0xa3,
+	0xb0, 0xc5, 0xa8, 0x07, 0xae, 0x1f, 0x6e, 0x47, 0x4a, 0xe6, 0x63, 0x2d, 0x73, 0x3e, 0x2f, 0x53,
+	0x4f, 0x80, 0x9b, 0xe1, 0x76, 0x64, 0x93, 0x98, 0xf5, 0x06, 0x10, 0xd8, 0x47, 0xc7, 0x33, 0xfa,
+	0x64, 0xb9, 0x04, 0x70, 0x41, 0x3e, 0xbb, 0x6d, 0xbb, 0x7b, 0x52, 0x09, 0xb3, 0x1c, 0x5b, 0xc0,
+	0x07, 0x65, 0x5e, 0x76, 0xe6, 0x3d, 0x0b, 0x2a, 0x7b, 0x61, 0x1e, 0x41, 0x33, 0x1b, 0x9d, 0x58,
+	0xec, 0x39, 0xc0, 0xe3, 0x28, 0xe4, 0x70, 0x76, 0x0f, 0x9d, 0x3c, 0xe0, 0x4e, 0xc3, 0x18, 0x8d,
+	0xa9, 0xd7, 0xb9, 0x7e, 0xc7, 0xaa, 0xb1, 0x7c, 0xb5, 0xa7, 0x07, 0xa8, 0x79, 0xb5, 0x27, 0xdf,
+	0xf8, 0x0c, 0x9a, 0xe6, 0x7e, 0x27, 0x0e, 0x40, 0xdf, 0x7b, 0xea, 0x11, 0x59, 0x76, 0x2a, 0xda,
+	0xa6, 0xae, 0xae, 0x2c, 0x97, 0x4f, 0x0b, 0x68, 0xf1, 0xe0, 0xab, 0xcf, 0x2a, 0x7f, 0x0a, 0x21,
+	0x7d, 0xa7, 0xb6, 0x29, 0x6f, 0xab, 0x04, 0xa6, 0x9d, 0xb2, 0xb2, 0xdc, 0xa4, 0xbc, 0x8d, 0x4f,
+	0xa3, 0x0a, 0xec, 0xc6, 0x3e, 0x03, 0x57, 0xf8, 0x1d, 0xfd, 0x8a, 0x2d, 0x39, 0x48, 0x9b, 0xb6,
+	0xfc, 0x0e, 0x48, 0x80, 0xd9, 0x08, 0x0a, 0x30, 0xa6, 0x01, 0xda, 0x24, 0x01, 0x49, 0x82, 0x57,
+	0xae, 0xbf, 0xfa, 0xf8, 0xf7, 0xc5, 0x43, 0x8f, 0xf7, 0x17, 0x0b, 0x4f, 0xf6, 0x17, 0x0b, 0xbf,
+	0xed, 0x2f, 0x16, 0x3e, 0xf9, 0x63, 0xf1, 0xd0, 0xdb, 0xe7, 0x5a, 0x91, 0xaa, 0x4b, 0xcd, 0x8f,
+	0x96, 0xb3, 0x7f, 0xa5, 0xd5, 0xe5, 0xfe, 0x5a, 0x35, 0x26, 0xd4, 0x2f, 0xd0, 0xea, 0xff, 0x03,
+	0x00, 0x42, 0x3a, 0xc9, 0xee, 0xa4, 0x0d, 0x00, 0x00,
 }
 
 func (m *RequestHeader) Marshal() (dAtA []byte, err error) {
@@ -335,6 +410,34 @@ func (m *RequestHeader) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Roles) > 0 {
+		for iNdEx := len(m.Roles) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Roles[iNdEx])
+			copy(dAtA[i:], m.Roles[iNdEx])
+			i = encodeVarintRaftInternal(dAtA, i, uint64(len(m.Roles[iNdEx])))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if len(m.TraceContext) > 0 {
+		for k := range m.TraceContext {
+			v := m.TraceContext[k]
+			baseI := i
+			i -= len(v)
+			copy(dAtA[i:], v)
+			i = encodeVarintRaftInternal(dAtA, i, uint64(len(v)))
+			i--
+			dAtA[i] = 0x12
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintRaftInternal(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintRaftInternal(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x22
+		}
+	}
 	if m.AuthRevision != 0 {
 		i = encodeVarintRaftInternal(dAtA, i, uint64(m.AuthRevision))
 		i--
@@ -619,6 +722,34 @@ func (m *InternalRaftRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0xe2
 	}
+	if m.ReadOnly != nil {
+		{
+			size, err := m.ReadOnly.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x3f
+		i--
+		dAtA[i] = 0xba
+	}
+	if m.AuthTokenRevoke != nil {
+		{
+			size, err := m.AuthTokenRevoke.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x3f
+		i--
+		dAtA[i] = 0xb2
+	}
 	if m.AuthStatus != nil {
 		{
 			size, err := m.AuthStatus.MarshalToSizedBuffer(dAtA[:i])
@@ -892,6 +1023,57 @@ func (m *InternalAuthenticateRequest) MarshalToSizedBuffer(dAtA []byte) (int, er
 	return len(dAtA) - i, nil
 }
 
+func (m *InternalAuthTokenRevokeRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *InternalAuthTokenRevokeRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *InternalAuthTokenRevokeRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.RevokeTime != 0 {
+		i = encodeVarintRaftInternal(dAtA, i, uint64(m.RevokeTime))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.ExpireTime != 0 {
+		i = encodeVarintRaftInternal(dAtA, i, uint64(m.ExpireTime))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.TokenHash) > 0 {
+		i -= len(m.TokenHash)
+		copy(dAtA[i:], m.TokenHash)
+		i = encodeVarintRaftInternal(dAtA, i, uint64(len(m.TokenHash)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintRaftInternal(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func encodeVarintRaftInternal(dAtA []byte, offset int, v uint64) int {
 	offset -= sovRaftInternal(v)
 	base := offset
@@ -919,6 +1101,20 @@ func (m *RequestHeader) Size() (n int) {
 	if m.AuthRevision != 0 {
 		n += 1 + sovRaftInternal(uint64(m.AuthRevision))
 	}
+	if len(m.TraceContext) > 0 {
+		for k, v := range m.TraceContext {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovRaftInternal(uint64(len(k))) + 1 + len(v) + sovRaftInternal(uint64(len(v)))
+			n += mapEntrySize + 1 + sovRaftInternal(uint64(mapEntrySize))
+		}
+	}
+	if len(m.Roles) > 0 {
+		for _, s := range m.Roles {
+			l = len(s)
+			n += 1 + l + sovRaftInternal(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -994,6 +1190,14 @@ func (m *InternalRaftRequest) Size() (n int) {
 		l = m.AuthStatus.Size()
 		n += 2 + l + sovRaftInternal(uint64(l))
 	}
+	if m.AuthTokenRevoke != nil {
+		l = m.AuthTokenRevoke.Size()
+		n += 2 + l + sovRaftInternal(uint64(l))
+	}
+	if m.ReadOnly != nil {
+		l = m.ReadOnly.Size()
+		n += 2 + l + sovRaftInternal(uint64(l))
+	}
 	if m.AuthUserAdd != nil {
 		l = m.AuthUserAdd.Size()
 		n += 2 + l + sovRaftInternal(uint64(l))
@@ -1104,6 +1308,32 @@ func (m *InternalAuthenticateRequest) Size() (n int) {
 	return n
 }
 
+func (m *InternalAuthTokenRevokeRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Name)
+	if l > 0 {
+		n += 1 + l + sovRaftInternal(uint64(l))
+	}
+	l = len(m.TokenHash)
+	if l > 0 {
+		n += 1 + l + sovRaftInternal(uint64(l))
+	}
+	if m.ExpireTime != 0 {
+		n += 1 + sovRaftInternal(uint64(m.ExpireTime))
+	}
+	if m.RevokeTime != 0 {
+		n += 1 + sovRaftInternal(uint64(m.RevokeTime))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func sovRaftInternal(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -1209,6 +1439,165 @@ func (m *RequestHeader) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceContext", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.TraceContext == nil {
+				m.TraceContext = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowRaftInternal
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowRaftInternal
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthRaftInternal
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthRaftInternal
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowRaftInternal
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthRaftInternal
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue < 0 {
+						return ErrInvalidLengthRaftInternal
+					}
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipRaftInternal(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if (skippy < 0) || (iNdEx+skippy) < 0 {
+						return ErrInvalidLengthRaftInternal
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.TraceContext[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Roles", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Roles = append(m.Roles, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRaftInternal(dAtA[iNdEx:])
@@ -1819,6 +2208,78 @@ func (m *InternalRaftRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 1014:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AuthTokenRevoke", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.AuthTokenRevoke == nil {
+				m.AuthTokenRevoke = &InternalAuthTokenRevokeRequest{}
+			}
+			if err := m.AuthTokenRevoke.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 1015:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadOnly", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.ReadOnly == nil {
+				m.ReadOnly = &ReadOnlyRequest{}
+			}
+			if err := m.ReadOnly.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 1100:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field AuthUserAdd", wireType)
@@ -2651,6 +3112,161 @@ func (m *InternalAuthenticateRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *InternalAuthTokenRevokeRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRaftInternal
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: InternalAuthTokenRevokeRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: InternalAuthTokenRevokeRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Name", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Name = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TokenHash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.TokenHash = append(m.TokenHash[:0], dAtA[iNdEx:postIndex]...)
+			if m.TokenHash == nil {
+				m.TokenHash = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpireTime", wireType)
+			}
+			m.ExpireTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ExpireTime |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RevokeTime", wireType)
+			}
+			m.RevokeTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RevokeTime |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRaftInternal(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipRaftInternal(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
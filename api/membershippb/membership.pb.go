@@ -73,6 +73,7 @@ var xxx_messageInfo_RaftAttributes proto.InternalMessageInfo
 type Attributes struct {
 	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	ClientUrls           []string `protobuf:"bytes,2,rep,name=client_urls,json=clientUrls,proto3" json:"client_urls,omitempty"`
+	IsWitness            bool     `protobuf:"varint,3,opt,name=is_witness,json=isWitness,proto3" json:"is_witness,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -287,34 +288,35 @@ func init() {
 func init() { proto.RegisterFile("membership.proto", fileDescriptor_949fe0d019050ef5) }
 
 var fileDescriptor_949fe0d019050ef5 = []byte{
-	// 422 bytes of a gzipped FileDescriptorProto
+	// 441 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x52, 0xc1, 0x6e, 0xd3, 0x40,
-	0x10, 0xed, 0x3a, 0x55, 0x6b, 0x4f, 0x51, 0x28, 0x2b, 0x24, 0xac, 0x06, 0x8c, 0x55, 0x2e, 0x39,
-	0xd9, 0x12, 0x51, 0x0f, 0x70, 0x03, 0xd2, 0x43, 0x10, 0xe5, 0xb0, 0xa8, 0x1c, 0xb8, 0x44, 0xeb,
-	0x66, 0x62, 0x56, 0x72, 0xbc, 0x66, 0x77, 0x5d, 0xee, 0x1c, 0xf9, 0x02, 0xfe, 0x82, 0x13, 0xff,
-	0xd0, 0x23, 0x9f, 0x00, 0xe1, 0x47, 0x90, 0x77, 0x9d, 0xd8, 0x11, 0x9c, 0x7a, 0x1b, 0x3f, 0xcf,
-	0xbc, 0x79, 0xef, 0xed, 0xc0, 0xf1, 0x0a, 0x57, 0x19, 0x2a, 0xfd, 0x51, 0x54, 0x49, 0xa5, 0xa4,
-	0x91, 0xf4, 0x4e, 0x87, 0x54, 0xd9, 0xc9, 0xfd, 0x5c, 0xe6, 0xd2, 0xfe, 0x48, 0x9b, 0xca, 0xf5,
-	0x9c, 0xc4, 0x68, 0xae, 0x16, 0x29, 0xaf, 0x44, 0x7a, 0x8d, 0x4a, 0x0b, 0x59, 0x56, 0xd9, 0xa6,
-	0x72, 0x1d, 0xa7, 0x97, 0x30, 0x64, 0x7c, 0x69, 0x5e, 0x18, 0xa3, 0x44, 0x56, 0x1b, 0xd4, 0x74,
-	0x04, 0x41, 0x85, 0xa8, 0xe6, 0xb5, 0x2a, 0x74, 0x48, 0xe2, 0xc1, 0x38, 0x60, 0x7e, 0x03, 0x5c,
-	0xaa, 0x42, 0xd3, 0x47, 0x00, 0x42, 0xcf, 0x0b, 0xe4, 0xaa, 0x44, 0x15, 0x7a, 0x31, 0x19, 0xfb,
-	0x2c, 0x10, 0xfa, 0x8d, 0x03, 0x9e, 0x1f, 0x7e, 0xf9, 0x11, 0x0e, 0x26, 0xc9, 0xd9, 0xe9, 0x6b,
-	0x80, 0x1e, 0x25, 0x85, 0xfd, 0x92, 0xaf, 0x30, 0x24, 0x31, 0x19, 0x07, 0xcc, 0xd6, 0xf4, 0x31,
-	0x1c, 0x5d, 0x15, 0x02, 0x4b, 0xe3, 0x16, 0x79, 0x76, 0x11, 0x38, 0xa8, 0x59, 0xd5, 0x71, 0x7d,
-	0x27, 0x70, 0x70, 0x61, 0xbd, 0xd2, 0x21, 0x78, 0xb3, 0xa9, 0xa5, 0xd9, 0x67, 0xde, 0x6c, 0x4a,
-	0xcf, 0xe1, 0xae, 0xe2, 0x4b, 0x33, 0xe7, 0xdb, 0x5d, 0x56, 0xd3, 0xd1, 0xd3, 0x87, 0x49, 0x3f,
-	0x9d, 0x64, 0xd7, 0x22, 0x1b, 0xaa, 0x5d, 0xcb, 0xe7, 0x70, 0xcf, 0xb5, 0xf7, 0x89, 0x06, 0x96,
-	0x28, 0xdc, 0x25, 0xea, 0x91, 0xb4, 0x2f, 0xd2, 0x21, 0x9d, 0xe2, 0x33, 0x08, 0x5f, 0x15, 0xb5,
-	0x36, 0xa8, 0xde, 0xbb, 0xb0, 0xdf, 0xa1, 0x61, 0xf8, 0xa9, 0x46, 0x6d, 0xe8, 0x31, 0x0c, 0xae,
-	0x51, 0xb5, 0x51, 0x34, 0x65, 0x37, 0xf6, 0x95, 0xc0, 0xa8, 0x9d, 0xbb, 0xd8, 0x72, 0xf7, 0x46,
-	0x47, 0x10, 0xb4, 0x32, 0xb7, 0x21, 0xf8, 0x0e, 0xb0, 0x51, 0xfc, 0xc7, 0x83, 0x77, 0x7b, 0x0f,
-	0x6f, 0xe1, 0xc1, 0x54, 0x7e, 0x2e, 0x73, 0xc5, 0x17, 0x38, 0x2b, 0x97, 0xb2, 0xa7, 0x23, 0x84,
-	0x43, 0x2c, 0x79, 0x56, 0xe0, 0xc2, 0xaa, 0xf0, 0xd9, 0xe6, 0x73, 0x63, 0xce, 0xfb, 0xd7, 0xdc,
-	0xcb, 0x67, 0x37, 0xbf, 0xa3, 0xbd, 0x9b, 0x75, 0x44, 0x7e, 0xae, 0x23, 0xf2, 0x6b, 0x1d, 0x91,
-	0x6f, 0x7f, 0xa2, 0xbd, 0x0f, 0x4f, 0x72, 0x99, 0x34, 0x37, 0x9a, 0x08, 0x99, 0x76, 0xb7, 0x3a,
-	0x49, 0xfb, 0x82, 0xb3, 0x03, 0x7b, 0xaa, 0x93, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xcf, 0x56,
-	0x21, 0x97, 0x04, 0x03, 0x00, 0x00,
+	0x10, 0xed, 0xda, 0x55, 0x13, 0x4f, 0x51, 0x28, 0x16, 0x12, 0xab, 0x06, 0x4c, 0x54, 0x24, 0x94,
+	0x93, 0x2d, 0x11, 0x55, 0x08, 0x6e, 0x94, 0xf4, 0x10, 0x89, 0x72, 0x58, 0x54, 0x90, 0xb8, 0x44,
+	0xeb, 0x66, 0x12, 0x56, 0x72, 0x76, 0xcd, 0xee, 0xa6, 0xbd, 0x73, 0xec, 0x17, 0xf0, 0x17, 0x9c,
+	0xf8, 0x87, 0x1c, 0xf9, 0x04, 0x08, 0x3f, 0x82, 0xbc, 0x9b, 0xc4, 0x8e, 0xe0, 0xc4, 0x6d, 0xfc,
+	0x3c, 0xf3, 0xde, 0x9b, 0xb7, 0x03, 0x47, 0x73, 0x9c, 0xe7, 0xa8, 0xcd, 0x27, 0x51, 0xa6, 0xa5,
+	0x56, 0x56, 0xc5, 0x77, 0x6a, 0xa4, 0xcc, 0x8f, 0xef, 0xcf, 0xd4, 0x4c, 0xb9, 0x1f, 0x59, 0x55,
+	0xf9, 0x9e, 0xe3, 0x1e, 0xda, 0xab, 0x49, 0xc6, 0x4b, 0x91, 0x5d, 0xa3, 0x36, 0x42, 0xc9, 0x32,
+	0xdf, 0x54, 0xbe, 0xe3, 0xe4, 0x12, 0x3a, 0x8c, 0x4f, 0xed, 0x2b, 0x6b, 0xb5, 0xc8, 0x17, 0x16,
+	0x4d, 0xdc, 0x85, 0xa8, 0x44, 0xd4, 0xe3, 0x85, 0x2e, 0x0c, 0x25, 0xbd, 0xb0, 0x1f, 0xb1, 0x76,
+	0x05, 0x5c, 0xea, 0xc2, 0xc4, 0x8f, 0x00, 0x84, 0x19, 0x17, 0xc8, 0xb5, 0x44, 0x4d, 0x83, 0x1e,
+	0xe9, 0xb7, 0x59, 0x24, 0xcc, 0x1b, 0x0f, 0xbc, 0x6c, 0x7d, 0xf9, 0x4e, 0xc3, 0x41, 0x7a, 0x7a,
+	0xa2, 0x01, 0x1a, 0x94, 0x31, 0xec, 0x4b, 0x3e, 0x47, 0x4a, 0x7a, 0xa4, 0x1f, 0x31, 0x57, 0xc7,
+	0x8f, 0xe1, 0xf0, 0xaa, 0x10, 0x28, 0xad, 0x17, 0x0a, 0x9c, 0x10, 0x78, 0xc8, 0x49, 0x3d, 0x75,
+	0x52, 0x37, 0xc2, 0x4a, 0x34, 0x86, 0x86, 0x95, 0xd4, 0x59, 0xeb, 0xd6, 0xf1, 0x3f, 0xaf, 0x34,
+	0x3f, 0xf8, 0x3f, 0xb5, 0xe6, 0x37, 0x02, 0x07, 0x17, 0x2e, 0x93, 0xb8, 0x03, 0xc1, 0x68, 0xe8,
+	0xe4, 0xf6, 0x59, 0x30, 0x1a, 0xc6, 0xe7, 0x70, 0x57, 0xf3, 0xa9, 0x1d, 0xf3, 0xad, 0x27, 0xe7,
+	0xfd, 0xf0, 0xd9, 0xc3, 0xb4, 0x99, 0x62, 0xba, 0x1b, 0x05, 0xeb, 0xe8, 0xdd, 0x68, 0xce, 0xe1,
+	0x9e, 0x6f, 0x6f, 0x12, 0x85, 0x8e, 0x88, 0xee, 0x12, 0x35, 0x48, 0xd6, 0x2f, 0x57, 0x23, 0xb5,
+	0xe3, 0x53, 0xa0, 0xaf, 0x8b, 0x85, 0xb1, 0xa8, 0xdf, 0xfb, 0x47, 0x79, 0x87, 0x96, 0xe1, 0xe7,
+	0x05, 0x1a, 0x1b, 0x1f, 0x41, 0x78, 0x8d, 0x7a, 0x1d, 0x59, 0x55, 0xd6, 0x63, 0xb7, 0x04, 0xba,
+	0xeb, 0xb9, 0x8b, 0x2d, 0x77, 0x63, 0xb4, 0x0b, 0xd1, 0xda, 0xe6, 0x36, 0x84, 0xb6, 0x07, 0x46,
+	0xc3, 0x7f, 0xef, 0x10, 0xfc, 0xff, 0x0e, 0x6f, 0xe1, 0xc1, 0x50, 0xdd, 0xc8, 0x99, 0xe6, 0x13,
+	0x1c, 0xc9, 0xa9, 0x6a, 0xf8, 0xa0, 0xd0, 0x42, 0xc9, 0xf3, 0x02, 0x27, 0xce, 0x45, 0x9b, 0x6d,
+	0x3e, 0x37, 0xcb, 0x05, 0x7f, 0x2f, 0x77, 0xf6, 0x62, 0xf9, 0x2b, 0xd9, 0x5b, 0xae, 0x12, 0xf2,
+	0x63, 0x95, 0x90, 0x9f, 0xab, 0x84, 0x7c, 0xfd, 0x9d, 0xec, 0x7d, 0x7c, 0x32, 0x53, 0x69, 0x75,
+	0xcb, 0xa9, 0x50, 0x59, 0x7d, 0xd3, 0x83, 0xac, 0x69, 0x38, 0x3f, 0x70, 0x27, 0x3d, 0xf8, 0x33,
+	0x00, 0x76, 0xd9, 0xa9, 0x7b, 0x2c, 0x03, 0x00, 0x00,
 }
 
 func (m *RaftAttributes) Marshal() (dAtA []byte, err error) {
@@ -387,6 +389,16 @@ func (m *Attributes) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.IsWitness {
+		i--
+		if m.IsWitness {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
 	if len(m.ClientUrls) > 0 {
 		for iNdEx := len(m.ClientUrls) - 1; iNdEx >= 0; iNdEx-- {
 			i -= len(m.ClientUrls[iNdEx])
@@ -632,6 +644,9 @@ func (m *Attributes) Size() (n int) {
 			n += 1 + l + sovMembership(uint64(l))
 		}
 	}
+	if m.IsWitness {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -917,6 +932,26 @@ func (m *Attributes) Unmarshal(dAtA []byte) error {
 			}
 			m.ClientUrls = append(m.ClientUrls, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IsWitness", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMembership
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.IsWitness = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMembership(dAtA[iNdEx:])
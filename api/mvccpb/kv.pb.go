@@ -65,7 +65,11 @@ type KeyValue struct {
 	// lease is the ID of the lease that attached to key.
 	// When the attached lease expires, the key will be deleted.
 	// If lease is 0, then no lease is attached to the key.
-	Lease                int64    `protobuf:"varint,6,opt,name=lease,proto3" json:"lease,omitempty"`
+	Lease int64 `protobuf:"varint,6,opt,name=lease,proto3" json:"lease,omitempty"`
+	// ttl is the time to live of the key in seconds, set by the put that
+	// last modified it. The key is deleted once it expires.
+	// If ttl is 0, the key does not expire.
+	Ttl                  int64    `protobuf:"varint,7,opt,name=ttl,proto3" json:"ttl,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -164,28 +168,28 @@ func init() {
 func init() { proto.RegisterFile("kv.proto", fileDescriptor_2216fe83c9c12408) }
 
 var fileDescriptor_2216fe83c9c12408 = []byte{
-	// 327 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x91, 0xc1, 0x6a, 0xfa, 0x40,
-	0x10, 0xc6, 0xb3, 0x46, 0xa3, 0xff, 0x51, 0xfc, 0x87, 0x45, 0x68, 0x28, 0x34, 0xa4, 0x5e, 0x6a,
-	0x29, 0x24, 0xa0, 0x87, 0xde, 0x4b, 0x73, 0xb2, 0x87, 0x12, 0x6c, 0x0f, 0xbd, 0x48, 0x8c, 0x83,
-	0x84, 0xa8, 0x1b, 0x62, 0xba, 0x90, 0x37, 0xe9, 0xbd, 0xf7, 0x3e, 0x87, 0x47, 0x1f, 0xa1, 0xda,
-	0x17, 0x29, 0x3b, 0x5b, 0xed, 0xa5, 0x97, 0xdd, 0x99, 0xef, 0xfb, 0xb1, 0xf3, 0x0d, 0x0b, 0xad,
-	0x4c, 0xfa, 0x79, 0x21, 0x4a, 0xc1, 0xad, 0x95, 0x4c, 0x92, 0x7c, 0x76, 0xde, 0x5b, 0x88, 0x85,
-	0x20, 0x29, 0x50, 0x95, 0x76, 0xfb, 0x1f, 0x0c, 0x5a, 0x63, 0xac, 0x9e, 0xe3, 0xe5, 0x2b, 0x72,
-	0x1b, 0xcc, 0x0c, 0x2b, 0x87, 0x79, 0x6c, 0xd0, 0x89, 0x54, 0xc9, 0xaf, 0xe0, 0x7f, 0x52, 0x60,
-	0x5c, 0xe2, 0xb4, 0x40, 0x99, 0x6e, 0x52, 0xb1, 0x76, 0x6a, 0x1e, 0x1b, 0x98, 0x51, 0x57, 0xcb,
-	0xd1, 0x8f, 0xca, 0x2f, 0xa1, 0xb3, 0x12, 0xf3, 0x5f, 0xca, 0x24, 0xaa, 0xbd, 0x12, 0xf3, 0x13,
-	0xe2, 0x40, 0x53, 0x62, 0x41, 0x6e, 0x9d, 0xdc, 0x63, 0xcb, 0x7b, 0xd0, 0x90, 0x2a, 0x80, 0xd3,
-	0xa0, 0xc9, 0xba, 0x51, 0xea, 0x12, 0xe3, 0x0d, 0x3a, 0x16, 0xd1, 0xba, 0xe9, 0xbf, 0x33, 0x68,
-	0x84, 0x12, 0xd7, 0x25, 0xbf, 0x81, 0x7a, 0x59, 0xe5, 0x48, 0x71, 0xbb, 0xc3, 0x33, 0x5f, 0xef,
-	0xe9, 0x93, 0xa9, 0xcf, 0x49, 0x95, 0x63, 0x44, 0x10, 0xf7, 0xa0, 0x96, 0x49, 0xca, 0xde, 0x1e,
-	0xda, 0x47, 0xf4, 0xb8, 0x78, 0x54, 0xcb, 0x24, 0xbf, 0x86, 0x66, 0x5e, 0xa0, 0x9c, 0x66, 0x92,
-	0xc2, 0xff, 0x85, 0x59, 0x0a, 0x18, 0xcb, 0xbe, 0x07, 0xff, 0x4e, 0xef, 0xf3, 0x26, 0x98, 0x8f,
-	0x4f, 0x13, 0xdb, 0xe0, 0x00, 0xd6, 0x7d, 0xf8, 0x10, 0x4e, 0x42, 0x9b, 0xdd, 0xdd, 0x6e, 0xf7,
-	0xae, 0xb1, 0xdb, 0xbb, 0xc6, 0xf6, 0xe0, 0xb2, 0xdd, 0xc1, 0x65, 0x9f, 0x07, 0x97, 0xbd, 0x7d,
-	0xb9, 0xc6, 0xcb, 0xc5, 0x42, 0xf8, 0x58, 0x26, 0x73, 0x3f, 0x15, 0x81, 0xba, 0x83, 0x38, 0x4f,
-	0x03, 0x39, 0x0a, 0xf4, 0xac, 0x99, 0x45, 0xdf, 0x32, 0xfa, 0x0e, 0x00, 0x00, 0xff, 0xff, 0x78,
-	0x06, 0x46, 0xf5, 0xc0, 0x01, 0x00, 0x00,
+	// 334 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x91, 0xc1, 0x6a, 0xea, 0x40,
+	0x14, 0x86, 0x33, 0x46, 0x13, 0xef, 0x51, 0xbc, 0x61, 0x10, 0xee, 0x70, 0xe1, 0x86, 0x5c, 0x37,
+	0xb5, 0x14, 0x12, 0xd0, 0x45, 0xf7, 0xa5, 0x59, 0xd9, 0x45, 0x09, 0xb6, 0x8b, 0x6e, 0x24, 0xc6,
+	0x83, 0x84, 0xa8, 0x13, 0xe2, 0x74, 0x20, 0x6f, 0xd2, 0x7d, 0x5f, 0xa4, 0x4b, 0x97, 0x3e, 0x42,
+	0xb5, 0x2f, 0x52, 0x66, 0xa6, 0xda, 0x55, 0x37, 0xc9, 0x39, 0xff, 0xff, 0x31, 0xe7, 0xfc, 0x33,
+	0xd0, 0x2e, 0x64, 0x58, 0x56, 0x5c, 0x70, 0xea, 0xac, 0x65, 0x96, 0x95, 0xf3, 0xbf, 0xfd, 0x25,
+	0x5f, 0x72, 0x2d, 0x45, 0xaa, 0x32, 0xee, 0xe0, 0x8d, 0x40, 0x7b, 0x82, 0xf5, 0x63, 0xba, 0x7a,
+	0x46, 0xea, 0x81, 0x5d, 0x60, 0xcd, 0x48, 0x40, 0x86, 0xdd, 0x44, 0x95, 0xf4, 0x02, 0x7e, 0x67,
+	0x15, 0xa6, 0x02, 0x67, 0x15, 0xca, 0x7c, 0x9b, 0xf3, 0x0d, 0x6b, 0x04, 0x64, 0x68, 0x27, 0x3d,
+	0x23, 0x27, 0x5f, 0x2a, 0xfd, 0x0f, 0xdd, 0x35, 0x5f, 0x7c, 0x53, 0xb6, 0xa6, 0x3a, 0x6b, 0xbe,
+	0x38, 0x23, 0x0c, 0x5c, 0x89, 0x95, 0x76, 0x9b, 0xda, 0x3d, 0xb5, 0xb4, 0x0f, 0x2d, 0xa9, 0x16,
+	0x60, 0x2d, 0x3d, 0xd9, 0x34, 0x4a, 0x5d, 0x61, 0xba, 0x45, 0xe6, 0x68, 0xda, 0x34, 0x6a, 0x47,
+	0x21, 0x56, 0xcc, 0xd5, 0x9a, 0x2a, 0x07, 0xaf, 0x04, 0x5a, 0xb1, 0xc4, 0x8d, 0xa0, 0x57, 0xd0,
+	0x14, 0x75, 0x89, 0x3a, 0x40, 0x6f, 0xf4, 0x27, 0x34, 0xc9, 0x43, 0x6d, 0x9a, 0xef, 0xb4, 0x2e,
+	0x31, 0xd1, 0x10, 0x0d, 0xa0, 0x51, 0x48, 0x9d, 0xa6, 0x33, 0xf2, 0x4e, 0xe8, 0xe9, 0x2a, 0x92,
+	0x46, 0x21, 0xe9, 0x25, 0xb8, 0x65, 0x85, 0x72, 0x56, 0x48, 0x66, 0xff, 0x80, 0x39, 0x0a, 0x98,
+	0xc8, 0x41, 0x00, 0xbf, 0xce, 0xe7, 0x53, 0x17, 0xec, 0xfb, 0x87, 0xa9, 0x67, 0x51, 0x00, 0xe7,
+	0x36, 0xbe, 0x8b, 0xa7, 0xb1, 0x47, 0x6e, 0xae, 0x77, 0x07, 0xdf, 0xda, 0x1f, 0x7c, 0x6b, 0x77,
+	0xf4, 0xc9, 0xfe, 0xe8, 0x93, 0xf7, 0xa3, 0x4f, 0x5e, 0x3e, 0x7c, 0xeb, 0xe9, 0xdf, 0x92, 0x87,
+	0x28, 0xb2, 0x45, 0x98, 0xf3, 0x48, 0xfd, 0xa3, 0xb4, 0xcc, 0x23, 0x39, 0x8e, 0xcc, 0xac, 0xb9,
+	0xa3, 0x1f, 0x6a, 0xfc, 0x39, 0x00, 0x06, 0x19, 0x94, 0xde, 0xd2, 0x01, 0x00, 0x00,
 }
 
 func (m *KeyValue) Marshal() (dAtA []byte, err error) {
@@ -212,6 +216,11 @@ func (m *KeyValue) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Ttl != 0 {
+		i = encodeVarintKv(dAtA, i, uint64(m.Ttl))
+		i--
+		dAtA[i] = 0x38
+	}
 	if m.Lease != 0 {
 		i = encodeVarintKv(dAtA, i, uint64(m.Lease))
 		i--
@@ -342,6 +351,9 @@ func (m *KeyValue) Size() (n int) {
 	if m.Lease != 0 {
 		n += 1 + sovKv(uint64(m.Lease))
 	}
+	if m.Ttl != 0 {
+		n += 1 + sovKv(uint64(m.Ttl))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -550,6 +562,25 @@ func (m *KeyValue) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Ttl", wireType)
+			}
+			m.Ttl = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowKv
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Ttl |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipKv(dAtA[iNdEx:])
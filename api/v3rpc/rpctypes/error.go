@@ -32,12 +32,18 @@ var (
 	ErrGRPCCompacted               = status.Error(codes.OutOfRange, "etcdserver: mvcc: required revision has been compacted")
 	ErrGRPCFutureRev               = status.Error(codes.OutOfRange, "etcdserver: mvcc: required revision is a future revision")
 	ErrGRPCNoSpace                 = status.Error(codes.ResourceExhausted, "etcdserver: mvcc: database space exceeded")
+	ErrGRPCKeyQuotaExceeded        = status.Error(codes.ResourceExhausted, "etcdserver: key quota exceeded")
+	ErrGRPCReadOnly                = status.Error(codes.FailedPrecondition, "etcdserver: cluster is in read-only mode")
 
-	ErrGRPCLeaseNotFound    = status.Error(codes.NotFound, "etcdserver: requested lease not found")
-	ErrGRPCLeaseExist       = status.Error(codes.FailedPrecondition, "etcdserver: lease already exists")
-	ErrGRPCLeaseTTLTooLarge = status.Error(codes.OutOfRange, "etcdserver: too large lease TTL")
+	ErrGRPCLeaseNotFound         = status.Error(codes.NotFound, "etcdserver: requested lease not found")
+	ErrGRPCLeaseExist            = status.Error(codes.FailedPrecondition, "etcdserver: lease already exists")
+	ErrGRPCLeaseTTLTooLarge      = status.Error(codes.OutOfRange, "etcdserver: too large lease TTL")
+	ErrGRPCLeaseMetadataTooLarge = status.Error(codes.InvalidArgument, "etcdserver: too large lease metadata")
 
-	ErrGRPCWatchCanceled = status.Error(codes.Canceled, "etcdserver: watch canceled")
+	ErrGRPCWatchCanceled       = status.Error(codes.Canceled, "etcdserver: watch canceled")
+	ErrGRPCSlowWatcher         = status.Error(codes.ResourceExhausted, "etcdserver: watcher canceled for falling behind")
+	ErrGRPCTooManyWatchStreams = status.Error(codes.ResourceExhausted, "etcdserver: too many watch streams")
+	ErrGRPCTooManyWatchers     = status.Error(codes.ResourceExhausted, "etcdserver: too many watchers on the watch stream")
 
 	ErrGRPCMemberExist            = status.Error(codes.FailedPrecondition, "etcdserver: member ID already exist")
 	ErrGRPCPeerURLExist           = status.Error(codes.FailedPrecondition, "etcdserver: Peer URLs already exists")
@@ -47,6 +53,7 @@ var (
 	ErrGRPCMemberNotLearner       = status.Error(codes.FailedPrecondition, "etcdserver: can only promote a learner member")
 	ErrGRPCLearnerNotReady        = status.Error(codes.FailedPrecondition, "etcdserver: can only promote a learner member which is in sync with leader")
 	ErrGRPCTooManyLearners        = status.Error(codes.FailedPrecondition, "etcdserver: too many learner members in cluster")
+	ErrGRPCMemberStandby          = status.Error(codes.FailedPrecondition, "etcdserver: cannot promote a standby member")
 	ErrGRPCClusterIDMismatch      = status.Error(codes.FailedPrecondition, "etcdserver: cluster ID mismatch")
 	//revive:disable:var-naming
 	// Deprecated: Please use ErrGRPCClusterIDMismatch.
@@ -55,6 +62,12 @@ var (
 
 	ErrGRPCRequestTooLarge        = status.Error(codes.InvalidArgument, "etcdserver: request is too large")
 	ErrGRPCRequestTooManyRequests = status.Error(codes.ResourceExhausted, "etcdserver: too many requests")
+	ErrGRPCRateLimited            = status.Error(codes.ResourceExhausted, "etcdserver: client rate limit exceeded")
+	ErrGRPCAdmissionDenied        = status.Error(codes.PermissionDenied, "etcdserver: request denied by the admission webhook")
+	ErrGRPCAdmissionFailed        = status.Error(codes.Unavailable, "etcdserver: admission webhook failed")
+
+	ErrGRPCUnknownNamespace        = status.Error(codes.InvalidArgument, "etcdserver: unknown namespace")
+	ErrGRPCNotSupportedInNamespace = status.Error(codes.PermissionDenied, "etcdserver: request not supported in a namespace")
 
 	ErrGRPCRootUserNotExist     = status.Error(codes.FailedPrecondition, "etcdserver: root user does not exist")
 	ErrGRPCRootRoleNotExist     = status.Error(codes.FailedPrecondition, "etcdserver: root user does not have root role")
@@ -73,6 +86,8 @@ var (
 	ErrGRPCInvalidAuthToken     = status.Error(codes.Unauthenticated, "etcdserver: invalid auth token")
 	ErrGRPCInvalidAuthMgmt      = status.Error(codes.InvalidArgument, "etcdserver: invalid auth management")
 	ErrGRPCAuthOldRevision      = status.Error(codes.InvalidArgument, "etcdserver: revision of auth store is old")
+	ErrGRPCAuthLockedOut        = status.Error(codes.ResourceExhausted, "etcdserver: authentication locked out after too many failures, try again later")
+	ErrGRPCAuthPasswordExpired  = status.Error(codes.FailedPrecondition, "etcdserver: password has expired")
 
 	ErrGRPCNoLeader                   = status.Error(codes.Unavailable, "etcdserver: no leader")
 	ErrGRPCNotLeader                  = status.Error(codes.FailedPrecondition, "etcdserver: not leader")
@@ -84,9 +99,19 @@ var (
 	ErrGRPCTimeoutDueToConnectionLost = status.Error(codes.Unavailable, "etcdserver: request timed out, possibly due to connection lost")
 	ErrGRPCTimeoutWaitAppliedIndex    = status.Error(codes.Unavailable, "etcdserver: request timed out, waiting for the applied index took too long")
 	ErrGRPCUnhealthy                  = status.Error(codes.Unavailable, "etcdserver: unhealthy cluster")
+	ErrGRPCDeadlineTooShort           = status.Error(codes.DeadlineExceeded, "etcdserver: not enough time left before request deadline")
 	ErrGRPCCorrupt                    = status.Error(codes.DataLoss, "etcdserver: corrupt cluster")
 	ErrGRPCNotSupportedForLearner     = status.Error(codes.FailedPrecondition, "etcdserver: rpc not supported for learner")
+	ErrGRPCNotSupportedForWitness     = status.Error(codes.FailedPrecondition, "etcdserver: rpc not supported for witness")
+	ErrGRPCClusterVersionTooLow       = status.Error(codes.FailedPrecondition, "etcdserver: request not supported by the cluster version")
+	ErrGRPCMemberQuarantined          = status.Error(codes.Unavailable, "etcdserver: member quarantined after its data diverged")
 	ErrGRPCBadLeaderTransferee        = status.Error(codes.FailedPrecondition, "etcdserver: bad leader transferee")
+	ErrGRPCKeyAccessTrackingDisabled  = status.Error(codes.FailedPrecondition, "etcdserver: key access tracking is disabled")
+	ErrGRPCEncryptionDisabled         = status.Error(codes.FailedPrecondition, "etcdserver: backend encryption is disabled")
+	ErrGRPCInvalidContinueToken       = status.Error(codes.InvalidArgument, "etcdserver: invalid continue token")
+	ErrGRPCInvalidKeyFilter           = status.Error(codes.InvalidArgument, "etcdserver: invalid key filter")
+	ErrGRPCInvalidTTL                 = status.Error(codes.InvalidArgument, "etcdserver: invalid ttl")
+	ErrGRPCInvalidReadOnlyAction      = status.Error(codes.InvalidArgument, "etcdserver: invalid read-only action")
 
 	ErrGRPCWrongDowngradeVersionFormat   = status.Error(codes.InvalidArgument, "etcdserver: wrong downgrade target version format")
 	ErrGRPCInvalidDowngradeTargetVersion = status.Error(codes.InvalidArgument, "etcdserver: invalid downgrade target version")
@@ -109,10 +134,17 @@ var (
 		ErrorDesc(ErrGRPCCompacted):         ErrGRPCCompacted,
 		ErrorDesc(ErrGRPCFutureRev):         ErrGRPCFutureRev,
 		ErrorDesc(ErrGRPCNoSpace):           ErrGRPCNoSpace,
+		ErrorDesc(ErrGRPCKeyQuotaExceeded):  ErrGRPCKeyQuotaExceeded,
+		ErrorDesc(ErrGRPCReadOnly):          ErrGRPCReadOnly,
+
+		ErrorDesc(ErrGRPCLeaseNotFound):         ErrGRPCLeaseNotFound,
+		ErrorDesc(ErrGRPCLeaseExist):            ErrGRPCLeaseExist,
+		ErrorDesc(ErrGRPCLeaseTTLTooLarge):      ErrGRPCLeaseTTLTooLarge,
+		ErrorDesc(ErrGRPCLeaseMetadataTooLarge): ErrGRPCLeaseMetadataTooLarge,
 
-		ErrorDesc(ErrGRPCLeaseNotFound):    ErrGRPCLeaseNotFound,
-		ErrorDesc(ErrGRPCLeaseExist):       ErrGRPCLeaseExist,
-		ErrorDesc(ErrGRPCLeaseTTLTooLarge): ErrGRPCLeaseTTLTooLarge,
+		ErrorDesc(ErrGRPCSlowWatcher):         ErrGRPCSlowWatcher,
+		ErrorDesc(ErrGRPCTooManyWatchStreams): ErrGRPCTooManyWatchStreams,
+		ErrorDesc(ErrGRPCTooManyWatchers):     ErrGRPCTooManyWatchers,
 
 		ErrorDesc(ErrGRPCMemberExist):            ErrGRPCMemberExist,
 		ErrorDesc(ErrGRPCPeerURLExist):           ErrGRPCPeerURLExist,
@@ -122,10 +154,17 @@ var (
 		ErrorDesc(ErrGRPCMemberNotLearner):       ErrGRPCMemberNotLearner,
 		ErrorDesc(ErrGRPCLearnerNotReady):        ErrGRPCLearnerNotReady,
 		ErrorDesc(ErrGRPCTooManyLearners):        ErrGRPCTooManyLearners,
+		ErrorDesc(ErrGRPCMemberStandby):          ErrGRPCMemberStandby,
 		ErrorDesc(ErrGRPCClusterIDMismatch):      ErrGRPCClusterIDMismatch,
 
 		ErrorDesc(ErrGRPCRequestTooLarge):        ErrGRPCRequestTooLarge,
 		ErrorDesc(ErrGRPCRequestTooManyRequests): ErrGRPCRequestTooManyRequests,
+		ErrorDesc(ErrGRPCRateLimited):            ErrGRPCRateLimited,
+		ErrorDesc(ErrGRPCAdmissionDenied):        ErrGRPCAdmissionDenied,
+		ErrorDesc(ErrGRPCAdmissionFailed):        ErrGRPCAdmissionFailed,
+
+		ErrorDesc(ErrGRPCUnknownNamespace):        ErrGRPCUnknownNamespace,
+		ErrorDesc(ErrGRPCNotSupportedInNamespace): ErrGRPCNotSupportedInNamespace,
 
 		ErrorDesc(ErrGRPCRootUserNotExist):     ErrGRPCRootUserNotExist,
 		ErrorDesc(ErrGRPCRootRoleNotExist):     ErrGRPCRootRoleNotExist,
@@ -143,6 +182,8 @@ var (
 		ErrorDesc(ErrGRPCInvalidAuthToken):     ErrGRPCInvalidAuthToken,
 		ErrorDesc(ErrGRPCInvalidAuthMgmt):      ErrGRPCInvalidAuthMgmt,
 		ErrorDesc(ErrGRPCAuthOldRevision):      ErrGRPCAuthOldRevision,
+		ErrorDesc(ErrGRPCAuthLockedOut):        ErrGRPCAuthLockedOut,
+		ErrorDesc(ErrGRPCAuthPasswordExpired):  ErrGRPCAuthPasswordExpired,
 
 		ErrorDesc(ErrGRPCNoLeader):                   ErrGRPCNoLeader,
 		ErrorDesc(ErrGRPCNotLeader):                  ErrGRPCNotLeader,
@@ -153,9 +194,19 @@ var (
 		ErrorDesc(ErrGRPCTimeoutDueToLeaderFail):     ErrGRPCTimeoutDueToLeaderFail,
 		ErrorDesc(ErrGRPCTimeoutDueToConnectionLost): ErrGRPCTimeoutDueToConnectionLost,
 		ErrorDesc(ErrGRPCUnhealthy):                  ErrGRPCUnhealthy,
+		ErrorDesc(ErrGRPCDeadlineTooShort):           ErrGRPCDeadlineTooShort,
 		ErrorDesc(ErrGRPCCorrupt):                    ErrGRPCCorrupt,
 		ErrorDesc(ErrGRPCNotSupportedForLearner):     ErrGRPCNotSupportedForLearner,
+		ErrorDesc(ErrGRPCNotSupportedForWitness):     ErrGRPCNotSupportedForWitness,
+		ErrorDesc(ErrGRPCClusterVersionTooLow):       ErrGRPCClusterVersionTooLow,
+		ErrorDesc(ErrGRPCMemberQuarantined):          ErrGRPCMemberQuarantined,
 		ErrorDesc(ErrGRPCBadLeaderTransferee):        ErrGRPCBadLeaderTransferee,
+		ErrorDesc(ErrGRPCKeyAccessTrackingDisabled):  ErrGRPCKeyAccessTrackingDisabled,
+		ErrorDesc(ErrGRPCEncryptionDisabled):         ErrGRPCEncryptionDisabled,
+		ErrorDesc(ErrGRPCInvalidContinueToken):       ErrGRPCInvalidContinueToken,
+		ErrorDesc(ErrGRPCInvalidKeyFilter):           ErrGRPCInvalidKeyFilter,
+		ErrorDesc(ErrGRPCInvalidTTL):                 ErrGRPCInvalidTTL,
+		ErrorDesc(ErrGRPCInvalidReadOnlyAction):      ErrGRPCInvalidReadOnlyAction,
 
 		ErrorDesc(ErrGRPCClusterVersionUnavailable):     ErrGRPCClusterVersionUnavailable,
 		ErrorDesc(ErrGRPCWrongDowngradeVersionFormat):   ErrGRPCWrongDowngradeVersionFormat,
@@ -177,10 +228,17 @@ var (
 	ErrCompacted         = Error(ErrGRPCCompacted)
 	ErrFutureRev         = Error(ErrGRPCFutureRev)
 	ErrNoSpace           = Error(ErrGRPCNoSpace)
+	ErrKeyQuotaExceeded  = Error(ErrGRPCKeyQuotaExceeded)
+	ErrReadOnly          = Error(ErrGRPCReadOnly)
 
-	ErrLeaseNotFound    = Error(ErrGRPCLeaseNotFound)
-	ErrLeaseExist       = Error(ErrGRPCLeaseExist)
-	ErrLeaseTTLTooLarge = Error(ErrGRPCLeaseTTLTooLarge)
+	ErrLeaseNotFound         = Error(ErrGRPCLeaseNotFound)
+	ErrLeaseExist            = Error(ErrGRPCLeaseExist)
+	ErrLeaseTTLTooLarge      = Error(ErrGRPCLeaseTTLTooLarge)
+	ErrLeaseMetadataTooLarge = Error(ErrGRPCLeaseMetadataTooLarge)
+
+	ErrSlowWatcher         = Error(ErrGRPCSlowWatcher)
+	ErrTooManyWatchStreams = Error(ErrGRPCTooManyWatchStreams)
+	ErrTooManyWatchers     = Error(ErrGRPCTooManyWatchers)
 
 	ErrMemberExist            = Error(ErrGRPCMemberExist)
 	ErrPeerURLExist           = Error(ErrGRPCPeerURLExist)
@@ -190,9 +248,16 @@ var (
 	ErrMemberNotLearner       = Error(ErrGRPCMemberNotLearner)
 	ErrMemberLearnerNotReady  = Error(ErrGRPCLearnerNotReady)
 	ErrTooManyLearners        = Error(ErrGRPCTooManyLearners)
+	ErrMemberStandby          = Error(ErrGRPCMemberStandby)
 
 	ErrRequestTooLarge = Error(ErrGRPCRequestTooLarge)
 	ErrTooManyRequests = Error(ErrGRPCRequestTooManyRequests)
+	ErrRateLimited     = Error(ErrGRPCRateLimited)
+	ErrAdmissionDenied = Error(ErrGRPCAdmissionDenied)
+	ErrAdmissionFailed = Error(ErrGRPCAdmissionFailed)
+
+	ErrUnknownNamespace        = Error(ErrGRPCUnknownNamespace)
+	ErrNotSupportedInNamespace = Error(ErrGRPCNotSupportedInNamespace)
 
 	ErrRootUserNotExist     = Error(ErrGRPCRootUserNotExist)
 	ErrRootRoleNotExist     = Error(ErrGRPCRootRoleNotExist)
@@ -210,6 +275,8 @@ var (
 	ErrInvalidAuthToken     = Error(ErrGRPCInvalidAuthToken)
 	ErrAuthOldRevision      = Error(ErrGRPCAuthOldRevision)
 	ErrInvalidAuthMgmt      = Error(ErrGRPCInvalidAuthMgmt)
+	ErrAuthLockedOut        = Error(ErrGRPCAuthLockedOut)
+	ErrAuthPasswordExpired  = Error(ErrGRPCAuthPasswordExpired)
 	ErrClusterIDMismatch    = Error(ErrGRPCClusterIDMismatch)
 	//revive:disable:var-naming
 	// Deprecated: Please use ErrClusterIDMismatch.
@@ -226,8 +293,18 @@ var (
 	ErrTimeoutDueToConnectionLost = Error(ErrGRPCTimeoutDueToConnectionLost)
 	ErrTimeoutWaitAppliedIndex    = Error(ErrGRPCTimeoutWaitAppliedIndex)
 	ErrUnhealthy                  = Error(ErrGRPCUnhealthy)
+	ErrDeadlineTooShort           = Error(ErrGRPCDeadlineTooShort)
 	ErrCorrupt                    = Error(ErrGRPCCorrupt)
+	ErrNotSupportedForWitness     = Error(ErrGRPCNotSupportedForWitness)
+	ErrClusterVersionTooLow       = Error(ErrGRPCClusterVersionTooLow)
+	ErrMemberQuarantined          = Error(ErrGRPCMemberQuarantined)
 	ErrBadLeaderTransferee        = Error(ErrGRPCBadLeaderTransferee)
+	ErrKeyAccessTrackingDisabled  = Error(ErrGRPCKeyAccessTrackingDisabled)
+	ErrEncryptionDisabled         = Error(ErrGRPCEncryptionDisabled)
+	ErrInvalidContinueToken       = Error(ErrGRPCInvalidContinueToken)
+	ErrInvalidKeyFilter           = Error(ErrGRPCInvalidKeyFilter)
+	ErrInvalidTTL                 = Error(ErrGRPCInvalidTTL)
+	ErrInvalidReadOnlyAction      = Error(ErrGRPCInvalidReadOnlyAction)
 
 	ErrClusterVersionUnavailable     = Error(ErrGRPCClusterVersionUnavailable)
 	ErrWrongDowngradeVersionFormat   = Error(ErrGRPCWrongDowngradeVersionFormat)
@@ -19,4 +19,20 @@ var (
 	MetadataHasLeader        = "true"
 
 	MetadataClientAPIVersionKey = "client-api-version"
+
+	// MetadataCallerKey carries the label of the client the request is
+	// attributed to in the per-caller server metrics.
+	MetadataCallerKey = "caller"
+
+	// MetadataNamespaceKey carries the server-side namespace the keys of the
+	// request are isolated in.
+	MetadataNamespaceKey = "namespace"
+
+	// MetadataPriorityKey carries the QoS class the request is scheduled in
+	// by the server: PriorityCritical, PriorityNormal or PriorityBackground.
+	// The requests without a valid priority are in PriorityNormal.
+	MetadataPriorityKey = "priority"
+	PriorityCritical    = "critical"
+	PriorityNormal      = "normal"
+	PriorityBackground  = "background"
 )
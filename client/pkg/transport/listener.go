@@ -50,13 +50,17 @@ func NewListenerWithOpts(addr, scheme string, opts ...ListenerOption) (net.Liste
 }
 
 func newListener(addr, scheme string, opts ...ListenerOption) (net.Listener, error) {
+	lnOpts := newListenOpts(opts...)
+
 	if scheme == "unix" || scheme == "unixs" {
+		if lnOpts.inheritedListener != nil {
+			// the socket file is owned by whoever passed the listener.
+			return lnOpts.inheritedListener, nil
+		}
 		// unix sockets via unix://laddr
 		return NewUnixListener(addr)
 	}
 
-	lnOpts := newListenOpts(opts...)
-
 	switch {
 	case lnOpts.IsSocketOpts():
 		// new ListenConfig with socket options.
@@ -65,7 +69,7 @@ func newListener(addr, scheme string, opts ...ListenerOption) (net.Listener, err
 		fallthrough
 	case lnOpts.IsTimeout(), lnOpts.IsSocketOpts():
 		// timeout listener with socket options.
-		ln, err := newKeepAliveListener(&lnOpts.ListenConfig, addr)
+		ln, err := lnOpts.listen(&lnOpts.ListenConfig, addr)
 		if err != nil {
 			return nil, err
 		}
@@ -75,7 +79,7 @@ func newListener(addr, scheme string, opts ...ListenerOption) (net.Listener, err
 			writeTimeout: lnOpts.writeTimeout,
 		}
 	case lnOpts.IsTimeout():
-		ln, err := newKeepAliveListener(nil, addr)
+		ln, err := lnOpts.listen(nil, addr)
 		if err != nil {
 			return nil, err
 		}
@@ -85,13 +89,20 @@ func newListener(addr, scheme string, opts ...ListenerOption) (net.Listener, err
 			writeTimeout: lnOpts.writeTimeout,
 		}
 	default:
-		ln, err := newKeepAliveListener(nil, addr)
+		ln, err := lnOpts.listen(nil, addr)
 		if err != nil {
 			return nil, err
 		}
 		lnOpts.Listener = ln
 	}
 
+	if len(lnOpts.proxyProtocolTrusted) > 0 {
+		lnOpts.Listener = NewProxyProtocolListener(lnOpts.Listener, lnOpts.proxyProtocolTrusted)
+	}
+	if lnOpts.connTracker != nil {
+		lnOpts.Listener = lnOpts.connTracker.Listener(lnOpts.Listener)
+	}
+
 	//  only skip if not passing TLSInfo
 	if lnOpts.skipTLSInfoCheck && !lnOpts.IsTLS() {
 		return lnOpts.Listener, nil
@@ -99,6 +110,15 @@ func newListener(addr, scheme string, opts ...ListenerOption) (net.Listener, err
 	return wrapTLS(scheme, lnOpts.tlsInfo, lnOpts.Listener)
 }
 
+// listen returns a keepalive listener on addr, or on the inherited listener
+// if any.
+func (lo *ListenerOptions) listen(cfg *net.ListenConfig, addr string) (net.Listener, error) {
+	if lo.inheritedListener != nil {
+		return NewKeepAliveListener(lo.inheritedListener, "tcp", nil)
+	}
+	return newKeepAliveListener(cfg, addr)
+}
+
 func newKeepAliveListener(cfg *net.ListenConfig, addr string) (net.Listener, error) {
 	var ln net.Listener
 	var err error
@@ -153,6 +173,15 @@ type TLSInfo struct {
 	InsecureSkipVerify  bool
 	SkipClientSANVerify bool
 
+	// CRLRefreshInterval is the time the CRLFile is cached for before it is
+	// read again. If zero, the file is read on each handshake.
+	CRLRefreshInterval time.Duration
+
+	// OCSPCheck queries the OCSP responder of the verified client
+	// certificates, and rejects the revoked ones: OCSPCheckSoftFail or
+	// OCSPCheckHardFail. If empty, OCSP is not checked.
+	OCSPCheck string
+
 	// ServerName ensures the cert matches the given host in case of discovery / virtual hosting
 	ServerName string
 
@@ -197,6 +226,15 @@ type TLSInfo struct {
 	// TLS certificate provided by a client.
 	AllowedHostnames []string
 
+	// AllowedSPIFFETrustDomains is a list of acceptable trust domains of the
+	// SPIFFE ID of the X.509-SVID provided by a client.
+	AllowedSPIFFETrustDomains []string
+
+	// AllowedSPIFFEIDs is a list of acceptable SPIFFE IDs of the X.509-SVID
+	// provided by a client. They are patterns in the syntax of path.Match,
+	// e.g. "spiffe://example.org/ns/etcd/*".
+	AllowedSPIFFEIDs []string
+
 	// Logger logs TLS errors.
 	// If nil, all logs are discarded.
 	Logger *zap.Logger
@@ -423,6 +461,13 @@ func (info TLSInfo) baseConfig() (*tls.Config, error) {
 	if len(info.AllowedCNs) > 0 && len(info.AllowedHostnames) > 0 {
 		return nil, fmt.Errorf("AllowedCNs and AllowedHostnames are mutually exclusive (cns=%q, hostnames=%q)", info.AllowedCNs, info.AllowedHostnames)
 	}
+	spiffe := len(info.AllowedSPIFFETrustDomains) > 0 || len(info.AllowedSPIFFEIDs) > 0
+	if spiffe && (info.AllowedCN != "" || info.AllowedHostname != "" || len(info.AllowedCNs) > 0 || len(info.AllowedHostnames) > 0) {
+		return nil, errors.New("AllowedSPIFFETrustDomains and AllowedSPIFFEIDs are mutually exclusive with the allowed CNs and hostnames")
+	}
+	if err := info.validateSPIFFE(); err != nil {
+		return nil, err
+	}
 
 	if info.AllowedCN != "" {
 		info.Logger.Warn("AllowedCN is deprecated, use AllowedCNs instead")
@@ -456,6 +501,9 @@ func (info TLSInfo) baseConfig() (*tls.Config, error) {
 			return false
 		}
 	}
+	if spiffe {
+		verifyCertificate = info.spiffeIDAllowed
+	}
 	if verifyCertificate != nil {
 		cfg.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
 			for _, chains := range verifiedChains {
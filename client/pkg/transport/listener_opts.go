@@ -28,6 +28,13 @@ type ListenerOptions struct {
 	skipTLSInfoCheck bool
 	writeTimeout     time.Duration
 	readTimeout      time.Duration
+	// inheritedListener is listening already, e.g. passed by systemd socket
+	// activation.
+	inheritedListener net.Listener
+	// proxyProtocolTrusted are the networks the PROXY protocol header is
+	// read from, see NewProxyProtocolListener.
+	proxyProtocolTrusted []*net.IPNet
+	connTracker          *ConnTracker
 }
 
 func newListenOpts(opts ...ListenerOption) *ListenerOptions {
@@ -88,3 +95,23 @@ func WithTLSInfo(t *TLSInfo) ListenerOption {
 func WithSkipTLSInfoCheck(skip bool) ListenerOption {
 	return func(lo *ListenerOptions) { lo.skipTLSInfoCheck = skip }
 }
+
+// WithInheritedListener makes the listener serve ln, which is listening
+// already on the address, e.g. passed by systemd socket activation, instead
+// of listening on the address. The socket options are then ignored.
+func WithInheritedListener(ln net.Listener) ListenerOption {
+	return func(lo *ListenerOptions) { lo.inheritedListener = ln }
+}
+
+// WithProxyProtocol reads the PROXY protocol header sent by the load
+// balancers of the trusted networks at the start of the TCP connections, see
+// NewProxyProtocolListener. No header is read if trusted is empty.
+func WithProxyProtocol(trusted []*net.IPNet) ListenerOption {
+	return func(lo *ListenerOptions) { lo.proxyProtocolTrusted = trusted }
+}
+
+// WithConnTracker tracks the connections accepted by the listener with t,
+// to close them when the certificates are renewed.
+func WithConnTracker(t *ConnTracker) ListenerOption {
+	return func(lo *ListenerOptions) { lo.connTracker = t }
+}
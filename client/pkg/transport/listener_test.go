@@ -498,6 +498,33 @@ func TestNewListenerUnixSocket(t *testing.T) {
 	l.Close()
 }
 
+// TestNewListenerWithInheritedListener tests that the listener serves the
+// inherited listener instead of listening again on its address.
+func TestNewListenerWithInheritedListener(t *testing.T) {
+	inherited, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer inherited.Close()
+
+	addr := inherited.Addr().String()
+	l, err := NewListenerWithOpts(addr, "http",
+		WithInheritedListener(inherited),
+		WithTimeout(time.Second, time.Second),
+	)
+	require.NoError(t, err)
+	defer l.Close()
+	require.Equal(t, addr, l.Addr().String())
+
+	go func() {
+		conn, derr := net.Dial("tcp", addr)
+		if derr == nil {
+			conn.Close()
+		}
+	}()
+	conn, err := l.Accept()
+	require.NoError(t, err)
+	conn.Close()
+}
+
 // TestNewListenerTLSInfoSelfCert tests that a new certificate accepts connections.
 func TestNewListenerTLSInfoSelfCert(t *testing.T) {
 	tmpdir := t.TempDir()
@@ -20,13 +20,12 @@ import (
 	"crypto/x509"
 	"fmt"
 	"net"
-	"os"
 	"strings"
 	"sync"
 )
 
 // tlsListener overrides a TLS listener so it will reject client
-// certificates with insufficient SAN credentials or CRL revoked
+// certificates with insufficient SAN credentials or CRL or OCSP revoked
 // certificates.
 type tlsListener struct {
 	net.Listener
@@ -39,7 +38,8 @@ type tlsListener struct {
 
 type tlsCheckFunc func(context.Context, *tls.Conn) error
 
-// NewTLSListener handshakes TLS connections and performs optional CRL checking.
+// NewTLSListener handshakes TLS connections and performs optional CRL and OCSP
+// checking.
 func NewTLSListener(l net.Listener, tlsinfo *TLSInfo) (net.Listener, error) {
 	check := func(context.Context, *tls.Conn) error { return nil }
 	return newTLSListener(l, tlsinfo, check)
@@ -61,6 +61,7 @@ func newTLSListener(l net.Listener, tlsinfo *TLSInfo, check tlsCheckFunc) (net.L
 	}
 
 	if len(tlsinfo.CRLFile) > 0 {
+		crl := newCRLChecker(tlsinfo.CRLFile, tlsinfo.CRLRefreshInterval)
 		prevCheck := check
 		check = func(ctx context.Context, tlsConn *tls.Conn) error {
 			if err := prevCheck(ctx, tlsConn); err != nil {
@@ -68,11 +69,24 @@ func newTLSListener(l net.Listener, tlsinfo *TLSInfo, check tlsCheckFunc) (net.L
 			}
 			st := tlsConn.ConnectionState()
 			if certs := st.PeerCertificates; len(certs) > 0 {
-				return checkCRL(tlsinfo.CRLFile, certs)
+				return crl.check(certs)
 			}
 			return nil
 		}
 	}
+	if len(tlsinfo.OCSPCheck) > 0 {
+		ocspc, err := newOCSPChecker(tlsinfo.Logger, tlsinfo.OCSPCheck)
+		if err != nil {
+			return nil, err
+		}
+		prevCheck := check
+		check = func(ctx context.Context, tlsConn *tls.Conn) error {
+			if err := prevCheck(ctx, tlsConn); err != nil {
+				return err
+			}
+			return ocspc.check(ctx, tlsConn.ConnectionState().VerifiedChains)
+		}
+	}
 
 	tlsl := &tlsListener{
 		Listener:         tls.NewListener(l, tlscfg),
@@ -166,29 +180,6 @@ func (l *tlsListener) acceptLoop() {
 	}
 }
 
-func checkCRL(crlPath string, cert []*x509.Certificate) error {
-	// TODO: cache
-	crlBytes, err := os.ReadFile(crlPath)
-	if err != nil {
-		return err
-	}
-	certList, err := x509.ParseRevocationList(crlBytes)
-	if err != nil {
-		return err
-	}
-	revokedSerials := make(map[string]struct{})
-	for _, rc := range certList.RevokedCertificateEntries {
-		revokedSerials[string(rc.SerialNumber.Bytes())] = struct{}{}
-	}
-	for _, c := range cert {
-		serial := string(c.SerialNumber.Bytes())
-		if _, ok := revokedSerials[serial]; ok {
-			return fmt.Errorf("transport: certificate serial %x revoked", serial)
-		}
-	}
-	return nil
-}
-
 func checkCertSAN(ctx context.Context, cert *x509.Certificate, remoteAddr string) error {
 	if len(cert.IPAddresses) == 0 && len(cert.DNSNames) == 0 {
 		return nil
@@ -0,0 +1,240 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// proxyHeaderTimeout is the time the connections accepted by a PROXY protocol
+// listener have to send their header.
+const proxyHeaderTimeout = 5 * time.Second
+
+var (
+	proxyV1Prefix    = []byte("PROXY ")
+	proxyV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+	ErrInvalidProxyHeader = errors.New("transport: invalid PROXY protocol header")
+)
+
+// NewProxyProtocolListener returns a listener whose connections from the
+// trusted networks, e.g. of an L4 load balancer or proxy, start with a PROXY
+// protocol (version 1 or 2) header giving the address of the client the
+// connection is proxied for. The RemoteAddr of these connections is the
+// address of the client, and the ones without a valid header are rejected.
+// The connections from the other networks are served as is, since their
+// header could claim any address.
+//
+// The header is read on the first Read or RemoteAddr call, rather than in
+// Accept, so that a slow client does not hold up the other connections.
+func NewProxyProtocolListener(l net.Listener, trusted []*net.IPNet) net.Listener {
+	return &proxyProtocolListener{Listener: l, trusted: trusted}
+}
+
+type proxyProtocolListener struct {
+	net.Listener
+	trusted []*net.IPNet
+}
+
+func (l *proxyProtocolListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	if !l.isTrusted(c.RemoteAddr()) {
+		return c, nil
+	}
+	return &proxyProtocolConn{Conn: c}, nil
+}
+
+// isTrusted returns whether addr is in a trusted network.
+func (l *proxyProtocolListener) isTrusted(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	for _, n := range l.trusted {
+		if n.Contains(tcpAddr.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+type proxyProtocolConn struct {
+	net.Conn
+
+	once   sync.Once
+	remote net.Addr
+	err    error
+
+	// readDeadline is the read deadline set before the header is read, which
+	// is restored once it is.
+	mu           sync.Mutex
+	readDeadline time.Time
+}
+
+// readHeader reads the PROXY protocol header once.
+func (c *proxyProtocolConn) readHeader() error {
+	c.once.Do(func() {
+		c.remote = c.Conn.RemoteAddr()
+		if err := c.Conn.SetReadDeadline(time.Now().Add(proxyHeaderTimeout)); err != nil {
+			c.err = err
+			return
+		}
+		addr, err := readProxyHeader(c.Conn)
+		if err != nil {
+			c.err = fmt.Errorf("%w from %s: %w", ErrInvalidProxyHeader, c.remote, err)
+			return
+		}
+		if addr != nil {
+			c.remote = addr
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.err = c.Conn.SetReadDeadline(c.readDeadline)
+	})
+	return c.err
+}
+
+func (c *proxyProtocolConn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readDeadline = t
+	return c.Conn.SetDeadline(t)
+}
+
+func (c *proxyProtocolConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readDeadline = t
+	return c.Conn.SetReadDeadline(t)
+}
+
+func (c *proxyProtocolConn) Read(b []byte) (int, error) {
+	if err := c.readHeader(); err != nil {
+		return 0, err
+	}
+	return c.Conn.Read(b)
+}
+
+// RemoteAddr returns the address of the client given by the header, or the
+// address of the proxy if the header has none.
+func (c *proxyProtocolConn) RemoteAddr() net.Addr {
+	c.readHeader()
+	return c.remote
+}
+
+// readProxyHeader reads a PROXY protocol header from r, and returns the source
+// address it gives, nil for the LOCAL and UNKNOWN connections.
+func readProxyHeader(r io.Reader) (net.Addr, error) {
+	prefix := make([]byte, len(proxyV1Prefix))
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		return nil, err
+	}
+	if bytes.Equal(prefix, proxyV1Prefix) {
+		return readProxyHeaderV1(r)
+	}
+	if !bytes.Equal(prefix, proxyV2Signature[:len(prefix)]) {
+		return nil, errors.New("missing header")
+	}
+	return readProxyHeaderV2(r)
+}
+
+// readProxyHeaderV1 reads the human-readable header following "PROXY ", e.g.
+// "TCP4 192.0.2.1 192.0.2.2 56324 2379\r\n".
+func readProxyHeaderV1(r io.Reader) (net.Addr, error) {
+	// the header is at most 107 bytes long, including the prefix.
+	var line []byte
+	b := make([]byte, 1)
+	for !bytes.HasSuffix(line, []byte("\r\n")) {
+		if len(line) >= 107-len(proxyV1Prefix) {
+			return nil, errors.New("header too long")
+		}
+		if _, err := io.ReadFull(r, b); err != nil {
+			return nil, err
+		}
+		line = append(line, b[0])
+	}
+	fields := strings.Split(string(line[:len(line)-2]), " ")
+	switch fields[0] {
+	case "UNKNOWN":
+		return nil, nil
+	case "TCP4", "TCP6":
+	default:
+		return nil, fmt.Errorf("unsupported protocol %q", fields[0])
+	}
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("malformed header %q", line)
+	}
+	ip := net.ParseIP(fields[1])
+	port, err := strconv.ParseUint(fields[3], 10, 16)
+	if ip == nil || err != nil {
+		return nil, fmt.Errorf("malformed source address in %q", line)
+	}
+	return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+}
+
+// readProxyHeaderV2 reads the binary header following the first bytes of the
+// signature.
+func readProxyHeaderV2(r io.Reader) (net.Addr, error) {
+	hdr := make([]byte, len(proxyV2Signature)-len(proxyV1Prefix)+4)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		return nil, err
+	}
+	sigLen := len(proxyV2Signature) - len(proxyV1Prefix)
+	if !bytes.Equal(hdr[:sigLen], proxyV2Signature[len(proxyV1Prefix):]) {
+		return nil, errors.New("missing header")
+	}
+	verCmd, famProto := hdr[sigLen], hdr[sigLen+1]
+	if verCmd>>4 != 2 {
+		return nil, fmt.Errorf("unsupported version %d", verCmd>>4)
+	}
+	body := make([]byte, binary.BigEndian.Uint16(hdr[sigLen+2:]))
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	switch verCmd & 0xf {
+	case 0x0: // LOCAL, e.g. health checks of the proxy.
+		return nil, nil
+	case 0x1: // PROXY
+	default:
+		return nil, fmt.Errorf("unsupported command %d", verCmd&0xf)
+	}
+	// the addresses are followed by TLVs, which are ignored.
+	switch famProto >> 4 {
+	case 0x1: // AF_INET
+		if len(body) < 12 {
+			return nil, errors.New("truncated IPv4 addresses")
+		}
+		return &net.TCPAddr{IP: net.IP(body[0:4]), Port: int(binary.BigEndian.Uint16(body[8:10]))}, nil
+	case 0x2: // AF_INET6
+		if len(body) < 36 {
+			return nil, errors.New("truncated IPv6 addresses")
+		}
+		return &net.TCPAddr{IP: net.IP(body[0:16]), Port: int(binary.BigEndian.Uint16(body[32:34]))}, nil
+	default: // AF_UNSPEC, AF_UNIX
+		return nil, nil
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func proxyV2Header(cmd, fam byte, addrs []byte) []byte {
+	var b bytes.Buffer
+	b.Write(proxyV2Signature)
+	b.WriteByte(0x20 | cmd)
+	b.WriteByte(fam<<4 | 0x1)
+	binary.Write(&b, binary.BigEndian, uint16(len(addrs)))
+	b.Write(addrs)
+	return b.Bytes()
+}
+
+func TestReadProxyHeader(t *testing.T) {
+	ipv4 := append(append([]byte{192, 0, 2, 1, 192, 0, 2, 2}, 0xdc, 0x04), 0x09, 0x4b)
+	ipv6 := append(append(append(net.ParseIP("2001:db8::1").To16(), net.ParseIP("2001:db8::2").To16()...), 0xdc, 0x04), 0x09, 0x4b)
+	tests := []struct {
+		name     string
+		header   []byte
+		expected string
+		err      bool
+	}{
+		{name: "v1 tcp4", header: []byte("PROXY TCP4 192.0.2.1 192.0.2.2 56324 2379\r\n"), expected: "192.0.2.1:56324"},
+		{name: "v1 tcp6", header: []byte("PROXY TCP6 2001:db8::1 2001:db8::2 56324 2379\r\n"), expected: "[2001:db8::1]:56324"},
+		{name: "v1 unknown", header: []byte("PROXY UNKNOWN\r\n")},
+		{name: "v1 malformed", header: []byte("PROXY TCP4 192.0.2.1\r\n"), err: true},
+		{name: "v1 too long", header: append([]byte("PROXY TCP4 "), bytes.Repeat([]byte("1"), 120)...), err: true},
+		{name: "v2 ipv4", header: proxyV2Header(0x1, 0x1, ipv4), expected: "192.0.2.1:56324"},
+		{name: "v2 ipv6", header: proxyV2Header(0x1, 0x2, ipv6), expected: "[2001:db8::1]:56324"},
+		{name: "v2 ipv4 with TLVs", header: proxyV2Header(0x1, 0x1, append(ipv4, 0x04, 0x00, 0x01, 0x00)), expected: "192.0.2.1:56324"},
+		{name: "v2 local", header: proxyV2Header(0x0, 0x0, nil)},
+		{name: "v2 truncated", header: proxyV2Header(0x1, 0x1, ipv4[:6]), err: true},
+		{name: "missing header", header: []byte("GET / HTTP/1.1\r\n\r\n"), err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, err := readProxyHeader(bytes.NewReader(tt.header))
+			if tt.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.expected == "" {
+				assert.Nil(t, addr)
+				return
+			}
+			assert.Equal(t, tt.expected, addr.String())
+		})
+	}
+}
+
+func TestNewListenerWithProxyProtocol(t *testing.T) {
+	_, loopback, err := net.ParseCIDR("127.0.0.0/8")
+	require.NoError(t, err)
+	l, err := NewListenerWithOpts("127.0.0.1:0", "http", WithProxyProtocol([]*net.IPNet{loopback}))
+	require.NoError(t, err)
+	defer l.Close()
+
+	for _, tt := range []struct {
+		header   string
+		expected string
+	}{
+		{header: "PROXY TCP4 192.0.2.1 192.0.2.2 56324 2379\r\n", expected: "192.0.2.1:56324"},
+		{header: "PROXY UNKNOWN\r\n"},
+	} {
+		go func() {
+			conn, derr := net.Dial("tcp", l.Addr().String())
+			if derr != nil {
+				return
+			}
+			defer conn.Close()
+			conn.Write([]byte(tt.header + "hello"))
+		}()
+		conn, err := l.Accept()
+		require.NoError(t, err)
+		expected := tt.expected
+		if expected == "" {
+			expected = conn.(*proxyProtocolConn).Conn.RemoteAddr().String()
+		}
+		assert.Equal(t, expected, conn.RemoteAddr().String())
+		b, err := io.ReadAll(conn)
+		require.NoError(t, err)
+		assert.Equal(t, "hello", string(b))
+		conn.Close()
+	}
+
+	go func() {
+		conn, derr := net.Dial("tcp", l.Addr().String())
+		if derr != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte("hello, no header\r\n"))
+	}()
+	conn, err := l.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Read(make([]byte, 1))
+	require.ErrorIs(t, err, ErrInvalidProxyHeader)
+}
+
+func TestNewListenerWithProxyProtocolUntrusted(t *testing.T) {
+	_, untrusted, err := net.ParseCIDR("192.0.2.0/24")
+	require.NoError(t, err)
+	l, err := NewListenerWithOpts("127.0.0.1:0", "http", WithProxyProtocol([]*net.IPNet{untrusted}))
+	require.NoError(t, err)
+	defer l.Close()
+
+	// the header of an untrusted source is not read, so it cannot claim
+	// another address.
+	header := "PROXY TCP4 192.0.2.1 192.0.2.2 56324 2379\r\n"
+	go func() {
+		conn, derr := net.Dial("tcp", l.Addr().String())
+		if derr != nil {
+			return
+		}
+		defer conn.Close()
+		conn.Write([]byte(header))
+	}()
+	conn, err := l.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, "127.0.0.1", conn.RemoteAddr().(*net.TCPAddr).IP.String())
+	b, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	assert.Equal(t, header, string(b))
+}
@@ -0,0 +1,285 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/net/quic"
+)
+
+const (
+	// quicALPN is the application protocol negotiated by the QUIC
+	// connections, which carry a gRPC HTTP/2 connection on their first
+	// bidirectional stream.
+	quicALPN = "etcd-grpc"
+
+	quicHandshakeTimeout = 10 * time.Second
+	quicKeepAlivePeriod  = 10 * time.Second
+	quicCloseTimeout     = time.Second
+)
+
+func quicConfig(tlscfg *tls.Config) *quic.Config {
+	tlscfg.MinVersion = tls.VersionTLS13
+	tlscfg.NextProtos = []string{quicALPN}
+	return &quic.Config{
+		TLSConfig:        tlscfg,
+		HandshakeTimeout: quicHandshakeTimeout,
+		KeepAlivePeriod:  quicKeepAlivePeriod,
+	}
+}
+
+// NewQUICListener returns a listener accepting the QUIC connections on the
+// UDP address addr. The connections are secured by the TLS configuration of
+// tlsinfo, and each accepted net.Conn is the first stream of a connection.
+//
+// The net.Conns implement ConnectionState, so that the gRPC transport
+// credentials of the client/v3/credentials package do not secure them again.
+func NewQUICListener(addr string, tlsinfo *TLSInfo) (net.Listener, error) {
+	if tlsinfo == nil || tlsinfo.Empty() {
+		return nil, errors.New("QUIC listener requires TLS")
+	}
+	tlscfg, err := tlsinfo.ServerConfig()
+	if err != nil {
+		return nil, err
+	}
+	ep, err := quic.Listen("udp", addr, quicConfig(tlscfg))
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &quicListener{
+		ep:     ep,
+		ctx:    ctx,
+		cancel: cancel,
+		connc:  make(chan net.Conn),
+		donec:  make(chan struct{}),
+	}
+	go l.acceptLoop()
+	return l, nil
+}
+
+type quicListener struct {
+	ep     *quic.Endpoint
+	ctx    context.Context
+	cancel context.CancelFunc
+	connc  chan net.Conn
+
+	closeOnce sync.Once
+	donec     chan struct{}
+}
+
+func (l *quicListener) acceptLoop() {
+	for {
+		c, err := l.ep.Accept(l.ctx)
+		if err != nil {
+			return
+		}
+		go l.acceptStream(c)
+	}
+}
+
+// acceptStream accepts the first stream of c, and closes c if the client
+// does not open it in time.
+func (l *quicListener) acceptStream(c *quic.Conn) {
+	ctx, cancel := context.WithTimeout(l.ctx, quicHandshakeTimeout)
+	defer cancel()
+	s, err := c.AcceptStream(ctx)
+	if err != nil {
+		c.Abort(err)
+		return
+	}
+	select {
+	case l.connc <- newQUICConn(c, s, nil):
+	case <-l.donec:
+		s.Reset(0)
+		c.Abort(nil)
+	}
+}
+
+func (l *quicListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.connc:
+		return c, nil
+	case <-l.donec:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *quicListener) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		l.cancel()
+		close(l.donec)
+		ctx, cancel := context.WithTimeout(context.Background(), quicCloseTimeout)
+		defer cancel()
+		err = l.ep.Close(ctx)
+	})
+	return err
+}
+
+func (l *quicListener) Addr() net.Addr {
+	return net.UDPAddrFromAddrPort(l.ep.LocalAddr())
+}
+
+// DialQUIC opens a QUIC connection to the UDP address addr, and returns its
+// first stream. The server name of tlscfg defaults to the host of addr.
+func DialQUIC(ctx context.Context, addr string, tlscfg *tls.Config) (net.Conn, error) {
+	if tlscfg == nil {
+		tlscfg = &tls.Config{}
+	} else {
+		tlscfg = tlscfg.Clone()
+	}
+	if tlscfg.ServerName == "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		tlscfg.ServerName = host
+	}
+	ep, err := quic.Listen("udp", ":0", nil)
+	if err != nil {
+		return nil, err
+	}
+	c, err := ep.Dial(ctx, "udp", addr, quicConfig(tlscfg))
+	if err != nil {
+		go closeQUICEndpoint(ep)
+		return nil, err
+	}
+	s, err := c.NewStream(ctx)
+	if err != nil {
+		c.Abort(err)
+		go closeQUICEndpoint(ep)
+		return nil, err
+	}
+	return newQUICConn(c, s, ep), nil
+}
+
+func closeQUICEndpoint(ep *quic.Endpoint) {
+	ctx, cancel := context.WithTimeout(context.Background(), quicCloseTimeout)
+	defer cancel()
+	ep.Close(ctx)
+}
+
+// quicConn is a net.Conn over a QUIC stream. Closing it closes the QUIC
+// connection, and the endpoint of the dialed connections.
+type quicConn struct {
+	conn   *quic.Conn
+	stream *quic.Stream
+	ep     *quic.Endpoint
+
+	mu            sync.Mutex
+	readDeadline  time.Time
+	writeDeadline time.Time
+
+	closeOnce sync.Once
+}
+
+func newQUICConn(c *quic.Conn, s *quic.Stream, ep *quic.Endpoint) *quicConn {
+	return &quicConn{conn: c, stream: s, ep: ep}
+}
+
+// deadlineContext returns the context expiring at deadline, if it is set.
+func deadlineContext(deadline time.Time) (context.Context, context.CancelFunc) {
+	if deadline.IsZero() {
+		return context.Background(), func() {}
+	}
+	return context.WithDeadline(context.Background(), deadline)
+}
+
+func deadlineError(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return os.ErrDeadlineExceeded
+	}
+	return err
+}
+
+func (c *quicConn) Read(b []byte) (int, error) {
+	c.mu.Lock()
+	ctx, cancel := deadlineContext(c.readDeadline)
+	c.mu.Unlock()
+	defer cancel()
+	c.stream.SetReadContext(ctx)
+	n, err := c.stream.Read(b)
+	return n, deadlineError(err)
+}
+
+func (c *quicConn) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	ctx, cancel := deadlineContext(c.writeDeadline)
+	c.mu.Unlock()
+	defer cancel()
+	c.stream.SetWriteContext(ctx)
+	n, err := c.stream.Write(b)
+	if err == nil {
+		err = c.stream.Flush()
+	}
+	return n, deadlineError(err)
+}
+
+func (c *quicConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.stream.CloseRead()
+		c.stream.CloseWrite()
+		c.conn.Abort(nil)
+		if c.ep != nil {
+			go closeQUICEndpoint(c.ep)
+		}
+	})
+	return nil
+}
+
+func (c *quicConn) LocalAddr() net.Addr {
+	return net.UDPAddrFromAddrPort(c.conn.LocalAddr())
+}
+
+func (c *quicConn) RemoteAddr() net.Addr {
+	return net.UDPAddrFromAddrPort(c.conn.RemoteAddr())
+}
+
+// SetDeadline sets the deadlines of the following reads and writes; unlike
+// for the other net.Conns, the reads and writes in progress are not affected.
+func (c *quicConn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readDeadline, c.writeDeadline = t, t
+	return nil
+}
+
+func (c *quicConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readDeadline = t
+	return nil
+}
+
+func (c *quicConn) SetWriteDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.writeDeadline = t
+	return nil
+}
+
+// ConnectionState returns the state of the TLS handshake of the QUIC
+// connection.
+func (c *quicConn) ConnectionState() tls.ConnectionState {
+	return c.conn.ConnectionState()
+}
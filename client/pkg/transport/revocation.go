@@ -0,0 +1,207 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ocsp"
+)
+
+// The modes of TLSInfo.OCSPCheck.
+const (
+	// OCSPCheckSoftFail rejects the certificates their OCSP responder reports
+	// revoked, and accepts them when the responder cannot be queried.
+	OCSPCheckSoftFail = "soft-fail"
+	// OCSPCheckHardFail rejects the certificates their OCSP responder reports
+	// revoked or unknown, or cannot be queried for.
+	OCSPCheckHardFail = "hard-fail"
+)
+
+// ocspRequestTimeout bounds the OCSP requests made during a handshake.
+const ocspRequestTimeout = 5 * time.Second
+
+// crlChecker rejects the certificates revoked by a CRL file, which is read
+// again once the refresh interval has elapsed, or on each check if it is zero.
+type crlChecker struct {
+	path     string
+	interval time.Duration
+
+	mu      sync.Mutex
+	revoked map[string]struct{}
+	loaded  time.Time
+}
+
+func newCRLChecker(path string, interval time.Duration) *crlChecker {
+	return &crlChecker{path: path, interval: interval}
+}
+
+func (c *crlChecker) check(certs []*x509.Certificate) error {
+	revoked, err := c.revokedSerials()
+	if err != nil {
+		return err
+	}
+	for _, cert := range certs {
+		serial := string(cert.SerialNumber.Bytes())
+		if _, ok := revoked[serial]; ok {
+			return fmt.Errorf("transport: certificate serial %x revoked", serial)
+		}
+	}
+	return nil
+}
+
+func (c *crlChecker) revokedSerials() (map[string]struct{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.revoked != nil && c.interval > 0 && time.Since(c.loaded) < c.interval {
+		return c.revoked, nil
+	}
+	crlBytes, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, err
+	}
+	certList, err := x509.ParseRevocationList(crlBytes)
+	if err != nil {
+		return nil, err
+	}
+	revoked := make(map[string]struct{}, len(certList.RevokedCertificateEntries))
+	for _, rc := range certList.RevokedCertificateEntries {
+		revoked[string(rc.SerialNumber.Bytes())] = struct{}{}
+	}
+	c.revoked, c.loaded = revoked, time.Now()
+	return revoked, nil
+}
+
+// ocspChecker queries the OCSP responders of the verified certificates, and
+// caches their responses until their next update.
+type ocspChecker struct {
+	lg       *zap.Logger
+	hardFail bool
+	client   *http.Client
+
+	mu    sync.Mutex
+	cache map[string]*ocsp.Response
+}
+
+func newOCSPChecker(lg *zap.Logger, mode string) (*ocspChecker, error) {
+	if mode != OCSPCheckSoftFail && mode != OCSPCheckHardFail {
+		return nil, fmt.Errorf("transport: unknown OCSP check mode %q (expected %q or %q)", mode, OCSPCheckSoftFail, OCSPCheckHardFail)
+	}
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	return &ocspChecker{
+		lg:       lg,
+		hardFail: mode == OCSPCheckHardFail,
+		client:   &http.Client{Timeout: ocspRequestTimeout},
+		cache:    make(map[string]*ocsp.Response),
+	}, nil
+}
+
+// check checks the status of the leaf certificate of the first verified chain.
+// The certificates without an OCSP responder are accepted.
+func (c *ocspChecker) check(ctx context.Context, chains [][]*x509.Certificate) error {
+	if len(chains) == 0 || len(chains[0]) == 0 {
+		return nil
+	}
+	leaf := chains[0][0]
+	if len(leaf.OCSPServer) == 0 {
+		return nil
+	}
+	// a self-signed certificate is its own issuer.
+	issuer := leaf
+	if len(chains[0]) > 1 {
+		issuer = chains[0][1]
+	}
+	resp, err := c.status(ctx, leaf, issuer)
+	if err != nil {
+		if c.hardFail {
+			return fmt.Errorf("transport: OCSP status of certificate serial %x unavailable: %w", leaf.SerialNumber.Bytes(), err)
+		}
+		c.lg.Warn("accepting certificate without OCSP status", zap.String("serial", fmt.Sprintf("%x", leaf.SerialNumber.Bytes())), zap.Error(err))
+		return nil
+	}
+	switch resp.Status {
+	case ocsp.Good:
+		return nil
+	case ocsp.Revoked:
+		return fmt.Errorf("transport: certificate serial %x revoked", leaf.SerialNumber.Bytes())
+	default:
+		if c.hardFail {
+			return fmt.Errorf("transport: certificate serial %x unknown to its OCSP responder", leaf.SerialNumber.Bytes())
+		}
+		return nil
+	}
+}
+
+func (c *ocspChecker) status(ctx context.Context, leaf, issuer *x509.Certificate) (*ocsp.Response, error) {
+	key := string(issuer.RawSubjectPublicKeyInfo) + string(leaf.SerialNumber.Bytes())
+	c.mu.Lock()
+	resp, ok := c.cache[key]
+	c.mu.Unlock()
+	if ok && time.Now().Before(resp.NextUpdate) {
+		return resp, nil
+	}
+
+	req, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return nil, err
+	}
+	hreq, err := http.NewRequestWithContext(ctx, http.MethodPost, leaf.OCSPServer[0], bytes.NewReader(req))
+	if err != nil {
+		return nil, err
+	}
+	hreq.Header.Set("Content-Type", "application/ocsp-request")
+	hresp, err := c.client.Do(hreq)
+	if err != nil {
+		return nil, err
+	}
+	defer hresp.Body.Close()
+	if hresp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OCSP responder returned %s", hresp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(hresp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	resp, err = ocsp.ParseResponseForCert(body, leaf, issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	// the responses without a next update are not cached, as newer
+	// information is always available.
+	c.mu.Lock()
+	now := time.Now()
+	for k, r := range c.cache {
+		if !now.Before(r.NextUpdate) {
+			delete(c.cache, k)
+		}
+	}
+	if !resp.NextUpdate.IsZero() {
+		c.cache[key] = resp
+	}
+	c.mu.Unlock()
+	return resp, nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key}
+}
+
+func (ca *testCA) issue(t *testing.T, serial int64, ocspServer string) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if ocspServer != "" {
+		tmpl.OCSPServer = []string{ocspServer}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func (ca *testCA) writeCRL(t *testing.T, path string, revoked ...*x509.Certificate) {
+	tmpl := &x509.RevocationList{
+		ThisUpdate: time.Now(),
+		NextUpdate: time.Now().Add(time.Hour),
+		Number:     big.NewInt(time.Now().UnixNano()),
+	}
+	for _, c := range revoked {
+		tmpl.RevokedCertificateEntries = append(tmpl.RevokedCertificateEntries, x509.RevocationListEntry{SerialNumber: c.SerialNumber, RevocationTime: time.Now()})
+	}
+	crl, err := x509.CreateRevocationList(rand.Reader, tmpl, ca.cert, ca.key)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, crl, 0o600))
+}
+
+func TestCRLCheckerRefresh(t *testing.T) {
+	ca := newTestCA(t)
+	cert := ca.issue(t, 2, "")
+	path := filepath.Join(t.TempDir(), "revoked.crl")
+	ca.writeCRL(t, path)
+
+	cached := newCRLChecker(path, time.Hour)
+	uncached := newCRLChecker(path, 0)
+	require.NoError(t, cached.check([]*x509.Certificate{cert}))
+	require.NoError(t, uncached.check([]*x509.Certificate{cert}))
+
+	ca.writeCRL(t, path, cert)
+	require.NoError(t, cached.check([]*x509.Certificate{cert}), "expected the cached CRL to be used until the refresh interval elapses")
+	require.Error(t, uncached.check([]*x509.Certificate{cert}))
+
+	cached.loaded = time.Now().Add(-time.Hour)
+	require.Error(t, cached.check([]*x509.Certificate{cert}))
+
+	require.NoError(t, os.WriteFile(path, []byte("@invalidcontent"), 0o600))
+	require.Error(t, uncached.check([]*x509.Certificate{cert}))
+}
+
+func TestOCSPChecker(t *testing.T) {
+	ca := newTestCA(t)
+	var status atomic.Int32
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		req, err := ocsp.ParseRequest(body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		resp, err := ocsp.CreateResponse(ca.cert, ca.cert, ocsp.Response{
+			Status:       int(status.Load()),
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   time.Now().Add(-time.Minute),
+			NextUpdate:   time.Now().Add(time.Hour),
+			RevokedAt:    time.Now().Add(-time.Minute),
+		}, ca.key)
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/ocsp-response")
+		w.Write(resp)
+	}))
+	defer srv.Close()
+
+	_, err := newOCSPChecker(nil, "strict")
+	require.Error(t, err)
+
+	tests := []struct {
+		name        string
+		mode        string
+		status      int
+		ocspServer  string
+		expectedErr bool
+	}{
+		{name: "good", mode: OCSPCheckHardFail, status: ocsp.Good, ocspServer: srv.URL},
+		{name: "revoked", mode: OCSPCheckSoftFail, status: ocsp.Revoked, ocspServer: srv.URL, expectedErr: true},
+		{name: "unknown soft-fail", mode: OCSPCheckSoftFail, status: ocsp.Unknown, ocspServer: srv.URL},
+		{name: "unknown hard-fail", mode: OCSPCheckHardFail, status: ocsp.Unknown, ocspServer: srv.URL, expectedErr: true},
+		{name: "unreachable soft-fail", mode: OCSPCheckSoftFail, ocspServer: "http://127.0.0.1:1"},
+		{name: "unreachable hard-fail", mode: OCSPCheckHardFail, ocspServer: "http://127.0.0.1:1", expectedErr: true},
+		{name: "no responder", mode: OCSPCheckHardFail},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := newOCSPChecker(nil, tt.mode)
+			require.NoError(t, err)
+			status.Store(int32(tt.status))
+			cert := ca.issue(t, int64(i+2), tt.ocspServer)
+			err = c.check(context.Background(), [][]*x509.Certificate{{cert, ca.cert}})
+			if tt.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+
+	t.Run("cached until next update", func(t *testing.T) {
+		c, err := newOCSPChecker(nil, OCSPCheckHardFail)
+		require.NoError(t, err)
+		status.Store(ocsp.Good)
+		cert := ca.issue(t, 100, srv.URL)
+		before := requests.Load()
+		for range 3 {
+			require.NoError(t, c.check(context.Background(), [][]*x509.Certificate{{cert, ca.cert}}))
+		}
+		require.Equal(t, before+1, requests.Load())
+	})
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"crypto/x509"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// SPIFFEID returns the SPIFFE ID of an X.509-SVID, the spiffe:// URI which is
+// its only URI SAN, or an empty string if cert is not an X.509-SVID.
+func SPIFFEID(cert *x509.Certificate) string {
+	if len(cert.URIs) != 1 {
+		return ""
+	}
+	u := cert.URIs[0]
+	if u.Scheme != "spiffe" || u.Host == "" || u.User != nil || u.Port() != "" || u.RawQuery != "" || u.Fragment != "" {
+		return ""
+	}
+	return u.String()
+}
+
+// spiffeTrustDomain returns the trust domain of a SPIFFE ID.
+func spiffeTrustDomain(id string) string {
+	td, _, _ := strings.Cut(strings.TrimPrefix(id, "spiffe://"), "/")
+	return td
+}
+
+// validateSPIFFE checks the AllowedSPIFFETrustDomains and AllowedSPIFFEIDs.
+func (info TLSInfo) validateSPIFFE() error {
+	for _, td := range info.AllowedSPIFFETrustDomains {
+		if td == "" || strings.ContainsAny(td, "/:") {
+			return fmt.Errorf("invalid SPIFFE trust domain %q", td)
+		}
+	}
+	for _, id := range info.AllowedSPIFFEIDs {
+		if !strings.HasPrefix(id, "spiffe://") {
+			return fmt.Errorf("invalid SPIFFE ID pattern %q: missing spiffe:// scheme", id)
+		}
+		if _, err := path.Match(id, ""); err != nil {
+			return fmt.Errorf("invalid SPIFFE ID pattern %q: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// spiffeIDAllowed returns true if cert is an X.509-SVID whose SPIFFE ID is in
+// one of the AllowedSPIFFETrustDomains, if any, and matches one of the
+// AllowedSPIFFEIDs, if any.
+func (info TLSInfo) spiffeIDAllowed(cert *x509.Certificate) bool {
+	id := SPIFFEID(cert)
+	if id == "" {
+		return false
+	}
+	if len(info.AllowedSPIFFETrustDomains) > 0 && !slices.Contains(info.AllowedSPIFFETrustDomains, spiffeTrustDomain(id)) {
+		return false
+	}
+	if len(info.AllowedSPIFFEIDs) == 0 {
+		return true
+	}
+	for _, pattern := range info.AllowedSPIFFEIDs {
+		if ok, _ := path.Match(pattern, id); ok {
+			return true
+		}
+	}
+	return false
+}
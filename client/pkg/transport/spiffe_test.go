@@ -0,0 +1,114 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"crypto/x509"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func certWithURIs(t *testing.T, uris ...string) *x509.Certificate {
+	cert := &x509.Certificate{}
+	for _, s := range uris {
+		u, err := url.Parse(s)
+		require.NoError(t, err)
+		cert.URIs = append(cert.URIs, u)
+	}
+	return cert
+}
+
+func TestSPIFFEID(t *testing.T) {
+	tests := []struct {
+		name     string
+		uris     []string
+		expected string
+	}{
+		{name: "svid", uris: []string{"spiffe://example.org/ns/etcd/sa/member"}, expected: "spiffe://example.org/ns/etcd/sa/member"},
+		{name: "no uri"},
+		{name: "not spiffe", uris: []string{"https://example.org/etcd"}},
+		{name: "several uris", uris: []string{"spiffe://example.org/a", "spiffe://example.org/b"}},
+		{name: "port", uris: []string{"spiffe://example.org:8443/a"}},
+		{name: "query", uris: []string{"spiffe://example.org/a?b=c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, SPIFFEID(certWithURIs(t, tt.uris...)))
+		})
+	}
+}
+
+func TestSPIFFEIDAllowed(t *testing.T) {
+	tests := []struct {
+		name         string
+		trustDomains []string
+		ids          []string
+		uri          string
+		expected     bool
+	}{
+		{name: "trust domain", trustDomains: []string{"example.org"}, uri: "spiffe://example.org/etcd/member", expected: true},
+		{name: "other trust domain", trustDomains: []string{"example.org"}, uri: "spiffe://example.com/etcd/member"},
+		{name: "id", ids: []string{"spiffe://example.org/etcd/member"}, uri: "spiffe://example.org/etcd/member", expected: true},
+		{name: "id pattern", ids: []string{"spiffe://example.org/etcd/*"}, uri: "spiffe://example.org/etcd/member", expected: true},
+		{name: "id pattern matches one segment", ids: []string{"spiffe://example.org/etcd/*"}, uri: "spiffe://example.org/etcd/member/0"},
+		{name: "id in other trust domain", trustDomains: []string{"example.com"}, ids: []string{"spiffe://example.org/*"}, uri: "spiffe://example.org/etcd"},
+		{name: "not svid", trustDomains: []string{"example.org"}, uri: "https://example.org/etcd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := TLSInfo{AllowedSPIFFETrustDomains: tt.trustDomains, AllowedSPIFFEIDs: tt.ids}
+			require.NoError(t, info.validateSPIFFE())
+			assert.Equal(t, tt.expected, info.spiffeIDAllowed(certWithURIs(t, tt.uri)))
+		})
+	}
+}
+
+func TestServerConfigSPIFFE(t *testing.T) {
+	tlsInfo, err := createSelfCert(t)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		update      func(*TLSInfo)
+		expectedErr bool
+	}{
+		{name: "trust domains", update: func(info *TLSInfo) { info.AllowedSPIFFETrustDomains = []string{"example.org"} }},
+		{name: "invalid trust domain", update: func(info *TLSInfo) { info.AllowedSPIFFETrustDomains = []string{"spiffe://example.org"} }, expectedErr: true},
+		{name: "invalid id", update: func(info *TLSInfo) { info.AllowedSPIFFEIDs = []string{"example.org/etcd"} }, expectedErr: true},
+		{name: "invalid id pattern", update: func(info *TLSInfo) { info.AllowedSPIFFEIDs = []string{"spiffe://example.org/["} }, expectedErr: true},
+		{name: "with allowed cns", update: func(info *TLSInfo) {
+			info.AllowedSPIFFEIDs = []string{"spiffe://example.org/etcd"}
+			info.AllowedCNs = []string{"etcd"}
+		}, expectedErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := *tlsInfo
+			tt.update(&info)
+			cfg, err := info.ServerConfig()
+			if tt.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, cfg.VerifyPeerCertificate)
+			require.NoError(t, cfg.VerifyPeerCertificate(nil, [][]*x509.Certificate{{certWithURIs(t, "spiffe://example.org/etcd")}}))
+			require.Error(t, cfg.VerifyPeerCertificate(nil, [][]*x509.Certificate{{certWithURIs(t, "spiffe://example.com/etcd")}}))
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// ConnTracker tracks the connections accepted by the listeners it wraps, so
+// that the connections established with a former certificate can be closed
+// once the certificate files are renewed. The certificates are read again on
+// each handshake: the clients reconnect with the renewed certificate.
+type ConnTracker struct {
+	mu    sync.Mutex
+	conns map[*trackedConn]struct{}
+}
+
+func NewConnTracker() *ConnTracker {
+	return &ConnTracker{conns: make(map[*trackedConn]struct{})}
+}
+
+// Listener returns a listener tracking the connections accepted by l. It
+// must wrap the listener below TLS, for the servers to get *tls.Conn
+// connections.
+func (t *ConnTracker) Listener(l net.Listener) net.Listener {
+	return &trackedListener{Listener: l, t: t}
+}
+
+// Len returns the number of connections open.
+func (t *ConnTracker) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.conns)
+}
+
+// Recycle closes the connections open, spread evenly over window so that the
+// clients do not all reconnect at once. It returns the number of connections
+// to close; the connections accepted after the call are kept.
+func (t *ConnTracker) Recycle(window time.Duration) int {
+	t.mu.Lock()
+	conns := make([]*trackedConn, 0, len(t.conns))
+	for c := range t.conns {
+		conns = append(conns, c)
+	}
+	t.mu.Unlock()
+
+	for i, c := range conns {
+		delay := window * time.Duration(i) / time.Duration(len(conns))
+		if delay == 0 {
+			c.Close()
+			continue
+		}
+		time.AfterFunc(delay, func() { c.Close() })
+	}
+	return len(conns)
+}
+
+func (t *ConnTracker) add(c *trackedConn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.conns[c] = struct{}{}
+}
+
+func (t *ConnTracker) remove(c *trackedConn) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.conns, c)
+}
+
+type trackedListener struct {
+	net.Listener
+	t *ConnTracker
+}
+
+func (l *trackedListener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	tc := &trackedConn{Conn: c, t: l.t}
+	l.t.add(tc)
+	return tc, nil
+}
+
+type trackedConn struct {
+	net.Conn
+	t         *ConnTracker
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func (c *trackedConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.closeErr = c.Conn.Close()
+		c.t.remove(c)
+	})
+	return c.closeErr
+}
+
+// certFiles returns the certificate and key files of info.
+func (info TLSInfo) certFiles() []string {
+	var files []string
+	for _, f := range []string{info.CertFile, info.KeyFile, info.ClientCertFile, info.ClientKeyFile} {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// certFilesDigest returns the sha256 digest of the certificate and key
+// files of info.
+func (info TLSInfo) certFilesDigest() ([]byte, error) {
+	h := sha256.New()
+	for _, f := range info.certFiles() {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		h.Write(b)
+	}
+	return h.Sum(nil), nil
+}
+
+// WatchCertFiles reads the certificate and key files of info every interval
+// until ctx is done, and calls onChange when they are renewed. The files
+// which cannot be read, e.g. while they are being replaced, are read again
+// on the next interval. The trusted CA files are not watched: they are only
+// read on start.
+func WatchCertFiles(ctx context.Context, info TLSInfo, interval time.Duration, onChange func()) {
+	last, _ := info.certFilesDigest()
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+		digest, err := info.certFilesDigest()
+		if err != nil {
+			continue
+		}
+		if last != nil && !bytes.Equal(digest, last) {
+			onChange()
+		}
+		last = digest
+	}
+}
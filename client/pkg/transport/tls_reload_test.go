@@ -0,0 +1,97 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"crypto/tls"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnTrackerRecycle(t *testing.T) {
+	tlsInfo, err := createSelfCert(t)
+	require.NoError(t, err)
+	tracker := NewConnTracker()
+	l, err := NewListenerWithOpts("127.0.0.1:0", "https", WithTLSInfo(tlsInfo), WithConnTracker(tracker))
+	require.NoError(t, err)
+	defer l.Close()
+
+	dial := func() *tls.Conn {
+		go func() {
+			conn, aerr := l.Accept()
+			if aerr != nil {
+				return
+			}
+			// the servers get TLS connections
+			conn.(*tls.Conn).Handshake()
+		}()
+		conn, derr := tls.Dial("tcp", l.Addr().String(), &tls.Config{InsecureSkipVerify: true})
+		require.NoError(t, derr)
+		return conn
+	}
+	old := dial()
+	defer old.Close()
+	require.Eventually(t, func() bool { return tracker.Len() == 1 }, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, 1, tracker.Recycle(0))
+	old.SetReadDeadline(time.Now().Add(time.Second))
+	_, err = old.Read(make([]byte, 1))
+	require.Error(t, err)
+	require.Eventually(t, func() bool { return tracker.Len() == 0 }, time.Second, 10*time.Millisecond)
+
+	// the connections accepted after the recycle are kept, and the
+	// connections are closed over the window.
+	for range 2 {
+		defer dial().Close()
+	}
+	require.Eventually(t, func() bool { return tracker.Len() == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 2, tracker.Recycle(time.Hour))
+	assert.Equal(t, 1, tracker.Len())
+}
+
+func TestWatchCertFiles(t *testing.T) {
+	tlsInfo, err := createSelfCert(t)
+	require.NoError(t, err)
+	changed := make(chan struct{}, 1)
+	go WatchCertFiles(t.Context(), *tlsInfo, 10*time.Millisecond, func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+
+	select {
+	case <-changed:
+		t.Fatal("unexpected change before the certificate is renewed")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	renewed, err := createSelfCert(t)
+	require.NoError(t, err)
+	for _, f := range [][2]string{{renewed.CertFile, tlsInfo.CertFile}, {renewed.KeyFile, tlsInfo.KeyFile}} {
+		b, rerr := os.ReadFile(f[0])
+		require.NoError(t, rerr)
+		require.NoError(t, os.WriteFile(f[1], b, 0o600))
+	}
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the renewed certificate was not detected")
+	}
+}
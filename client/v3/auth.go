@@ -43,15 +43,20 @@ type (
 	AuthRoleDeleteResponse           pb.AuthRoleDeleteResponse
 	AuthUserListResponse             pb.AuthUserListResponse
 	AuthRoleListResponse             pb.AuthRoleListResponse
+	AuthTokenRevokeResponse          pb.AuthTokenRevokeResponse
 
 	PermissionType authpb.Permission_Type
 	Permission     authpb.Permission
 )
 
 const (
-	PermRead      = authpb.READ
-	PermWrite     = authpb.WRITE
-	PermReadWrite = authpb.READWRITE
+	PermRead        = authpb.READ
+	PermWrite       = authpb.WRITE
+	PermReadWrite   = authpb.READWRITE
+	PermWatch       = authpb.WATCH
+	PermDelete      = authpb.DELETE
+	PermLeaseAttach = authpb.LEASE_ATTACH
+	PermTxnCompare  = authpb.TXN_COMPARE
 )
 
 type UserAddOptions authpb.UserAddOptions
@@ -110,6 +115,9 @@ type Auth interface {
 
 	// RoleDelete deletes a role.
 	RoleDelete(ctx context.Context, role string) (*AuthRoleDeleteResponse, error)
+
+	// TokenRevoke revokes an issued auth token before it expires.
+	TokenRevoke(ctx context.Context, token string) (*AuthTokenRevokeResponse, error)
 }
 
 type authClient struct {
@@ -228,8 +236,13 @@ func (auth *authClient) RoleDelete(ctx context.Context, role string) (*AuthRoleD
 	return (*AuthRoleDeleteResponse)(resp), ContextError(ctx, err)
 }
 
+func (auth *authClient) TokenRevoke(ctx context.Context, token string) (*AuthTokenRevokeResponse, error) {
+	resp, err := auth.remote.TokenRevoke(ctx, &pb.AuthTokenRevokeRequest{Token: token}, auth.callOpts...)
+	return (*AuthTokenRevokeResponse)(resp), ContextError(ctx, err)
+}
+
 func StrToPermissionType(s string) (PermissionType, error) {
-	val, ok := authpb.Permission_Type_value[strings.ToUpper(s)]
+	val, ok := authpb.Permission_Type_value[strings.ReplaceAll(strings.ToUpper(s), "-", "_")]
 	if ok {
 		return PermissionType(val), nil
 	}
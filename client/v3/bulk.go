@@ -0,0 +1,377 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+const (
+	// DefaultBulkFlushInterval is the default time a queued operation waits
+	// for others to be batched with.
+	DefaultBulkFlushInterval = 10 * time.Millisecond
+	// DefaultBulkMaxOps is the default maximum number of operations of a
+	// batch, matching the default --max-txn-ops of the server.
+	DefaultBulkMaxOps = 128
+	// DefaultBulkMaxBytes is the default maximum size of a batch, below the
+	// default --max-request-bytes of the server.
+	DefaultBulkMaxBytes = 1024 * 1024
+
+	// bulkMaxReadyBatches is the number of full batches waiting to be
+	// committed beyond which queuing blocks.
+	bulkMaxReadyBatches = 2
+)
+
+var (
+	ErrBulkClosed        = errors.New("etcdclient: bulk kv is closed")
+	ErrBulkUnsupportedOp = errors.New("etcdclient: bulk kv only supports put and delete operations")
+)
+
+// BulkConfig configures the batching of a BulkKV. Zero fields take their
+// default value.
+type BulkConfig struct {
+	// FlushInterval is the longest a queued operation waits for others
+	// before its batch is committed.
+	FlushInterval time.Duration
+	// MaxOps is the maximum number of operations of a batch. It must not
+	// exceed the --max-txn-ops of the server.
+	MaxOps int
+	// MaxBytes is the maximum size of the operations of a batch. It must be
+	// below the --max-request-bytes of the server.
+	MaxBytes int
+}
+
+// BulkKV batches Puts and Deletes into Txns, trading latency for throughput
+// when writing many small keys.
+//
+// Operations are committed in the order they were queued: a batch is
+// committed once the previous one completed, and an operation touching a key
+// written by the batch being filled starts a new batch. An error committing a
+// batch fails all of its operations.
+type BulkKV interface {
+	// Put queues a put of the given key and waits for its batch to be
+	// committed.
+	Put(ctx context.Context, key, val string, opts ...OpOption) (*PutResponse, error)
+
+	// Delete queues a delete of the given key and waits for its batch to be
+	// committed.
+	Delete(ctx context.Context, key string, opts ...OpOption) (*DeleteResponse, error)
+
+	// Enqueue queues a put or delete operation without waiting for it to be
+	// committed. The operation is dropped if ctx is done before its batch is
+	// sent. Enqueue blocks while several full batches wait to be committed.
+	Enqueue(ctx context.Context, op Op) (*BulkResult, error)
+
+	// Flush commits the queued operations and waits for them to complete.
+	Flush(ctx context.Context) error
+
+	// Close commits the queued operations, waits for them to complete and
+	// releases the resources of the BulkKV.
+	Close()
+}
+
+// BulkResult is the result of an operation queued in a BulkKV.
+type BulkResult struct {
+	op   Op
+	ctx  context.Context
+	done chan struct{}
+	resp OpResponse
+	err  error
+}
+
+// Done returns a channel closed once the operation completed.
+func (r *BulkResult) Done() <-chan struct{} { return r.done }
+
+// Wait waits for the operation to complete and returns its response.
+func (r *BulkResult) Wait(ctx context.Context) (OpResponse, error) {
+	select {
+	case <-r.done:
+		return r.resp, r.err
+	case <-ctx.Done():
+		return OpResponse{}, ctx.Err()
+	}
+}
+
+func (r *BulkResult) complete(resp OpResponse, err error) {
+	r.resp, r.err = resp, err
+	close(r.done)
+}
+
+type bulkKV struct {
+	kv  KV
+	cfg BulkConfig
+
+	donec chan struct{}
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	// batch is the batch being filled.
+	batch *bulkBatch
+	// ready are the batches waiting to be committed, in order.
+	ready []*bulkBatch
+	// last is the last queued operation.
+	last   *BulkResult
+	closed bool
+}
+
+// NewBulkKV returns a BulkKV committing its batches through kv.
+func NewBulkKV(kv KV, cfg BulkConfig) BulkKV {
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultBulkFlushInterval
+	}
+	if cfg.MaxOps <= 0 {
+		cfg.MaxOps = DefaultBulkMaxOps
+	}
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = DefaultBulkMaxBytes
+	}
+	b := &bulkKV{
+		kv:    kv,
+		cfg:   cfg,
+		donec: make(chan struct{}),
+	}
+	b.cond = sync.NewCond(&b.mu)
+	go b.run()
+	return b
+}
+
+func (b *bulkKV) Put(ctx context.Context, key, val string, opts ...OpOption) (*PutResponse, error) {
+	resp, err := b.do(ctx, OpPut(key, val, opts...))
+	return resp.Put(), err
+}
+
+func (b *bulkKV) Delete(ctx context.Context, key string, opts ...OpOption) (*DeleteResponse, error) {
+	resp, err := b.do(ctx, OpDelete(key, opts...))
+	return resp.Del(), err
+}
+
+func (b *bulkKV) do(ctx context.Context, op Op) (OpResponse, error) {
+	r, err := b.Enqueue(ctx, op)
+	if err != nil {
+		return OpResponse{}, err
+	}
+	return r.Wait(ctx)
+}
+
+func (b *bulkKV) Enqueue(ctx context.Context, op Op) (*BulkResult, error) {
+	if !op.IsPut() && !op.IsDelete() {
+		return nil, ErrBulkUnsupportedOp
+	}
+	r := &BulkResult{op: op, ctx: ctx, done: make(chan struct{})}
+	size := op.toRequestOp().Size()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for !b.closed && len(b.ready) >= bulkMaxReadyBatches {
+		b.cond.Wait()
+	}
+	if b.closed {
+		return nil, ErrBulkClosed
+	}
+	if b.batch != nil && (b.batch.size+size > b.cfg.MaxBytes || b.batch.conflicts(op)) {
+		b.cutLocked()
+	}
+	if b.batch == nil {
+		batch := &bulkBatch{putKeys: make(map[string]struct{})}
+		batch.timer = time.AfterFunc(b.cfg.FlushInterval, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if b.batch == batch {
+				b.cutLocked()
+			}
+		})
+		b.batch = batch
+	}
+	b.batch.add(r, size)
+	b.last = r
+	if len(b.batch.ops) >= b.cfg.MaxOps {
+		b.cutLocked()
+	}
+	return r, nil
+}
+
+func (b *bulkKV) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	last := b.last
+	if b.batch != nil {
+		b.cutLocked()
+	}
+	b.mu.Unlock()
+	if last == nil {
+		return nil
+	}
+	// batches complete in order, so the last operation completes last.
+	select {
+	case <-last.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *bulkKV) Close() {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		if b.batch != nil {
+			b.cutLocked()
+		}
+		b.cond.Broadcast()
+	}
+	b.mu.Unlock()
+	<-b.donec
+}
+
+// cutLocked moves the batch being filled to the batches to commit.
+func (b *bulkKV) cutLocked() {
+	b.batch.timer.Stop()
+	b.ready = append(b.ready, b.batch)
+	b.batch = nil
+	b.cond.Broadcast()
+}
+
+// run commits the batches one after the other, so that they are applied in
+// order.
+func (b *bulkKV) run() {
+	defer close(b.donec)
+	for {
+		b.mu.Lock()
+		for len(b.ready) == 0 && !b.closed {
+			b.cond.Wait()
+		}
+		if len(b.ready) == 0 {
+			b.mu.Unlock()
+			return
+		}
+		batch := b.ready[0]
+		b.ready = b.ready[1:]
+		b.cond.Broadcast()
+		b.mu.Unlock()
+
+		b.commit(batch)
+	}
+}
+
+func (b *bulkKV) commit(batch *bulkBatch) {
+	var ops []Op
+	var results []*BulkResult
+	for _, r := range batch.ops {
+		if err := r.ctx.Err(); err != nil {
+			r.complete(OpResponse{}, err)
+			continue
+		}
+		ops = append(ops, r.op)
+		results = append(results, r)
+	}
+	if len(ops) == 0 {
+		return
+	}
+
+	resp, err := b.kv.Txn(context.Background()).Then(ops...).Commit()
+	if err != nil {
+		for _, r := range results {
+			r.complete(OpResponse{}, err)
+		}
+		return
+	}
+	for i, r := range results {
+		r.complete(bulkOpResponse(resp, i), nil)
+	}
+}
+
+// bulkOpResponse returns the response of the i-th operation of a batch,
+// with the header of the batch.
+func bulkOpResponse(txn *TxnResponse, i int) OpResponse {
+	switch r := txn.Responses[i].Response.(type) {
+	case *pb.ResponseOp_ResponsePut:
+		resp := (*PutResponse)(r.ResponsePut)
+		resp.Header = txn.Header
+		return resp.OpResponse()
+	case *pb.ResponseOp_ResponseDeleteRange:
+		resp := (*DeleteResponse)(r.ResponseDeleteRange)
+		resp.Header = txn.Header
+		return resp.OpResponse()
+	}
+	return OpResponse{}
+}
+
+// bulkBatch is a batch of operations committed in a single Txn.
+type bulkBatch struct {
+	ops  []*BulkResult
+	size int
+	// putKeys and deletes are the keys written by the batch. A Txn may not
+	// write a key more than once.
+	putKeys map[string]struct{}
+	deletes []Op
+	timer   *time.Timer
+}
+
+func (batch *bulkBatch) add(r *BulkResult, size int) {
+	batch.ops = append(batch.ops, r)
+	batch.size += size
+	if r.op.IsPut() {
+		batch.putKeys[string(r.op.KeyBytes())] = struct{}{}
+	} else {
+		batch.deletes = append(batch.deletes, r.op)
+	}
+}
+
+// conflicts tells whether op writes a key already written by the batch.
+func (batch *bulkBatch) conflicts(op Op) bool {
+	if op.IsPut() {
+		if _, ok := batch.putKeys[string(op.KeyBytes())]; ok {
+			return true
+		}
+	}
+	for _, del := range batch.deletes {
+		if keyRangesOverlap(op.KeyBytes(), op.RangeBytes(), del.KeyBytes(), del.RangeBytes()) {
+			return true
+		}
+	}
+	if op.IsDelete() {
+		for key := range batch.putKeys {
+			if keyRangesOverlap([]byte(key), nil, op.KeyBytes(), op.RangeBytes()) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// keyRangesOverlap tells whether the ranges [key1, end1) and [key2, end2)
+// intersect, with the end of a range interpreted as in range requests.
+func keyRangesOverlap(key1, end1, key2, end2 []byte) bool {
+	lo1, hi1 := keyInterval(key1, end1)
+	lo2, hi2 := keyInterval(key2, end2)
+	return (hi2 == nil || bytes.Compare(lo1, hi2) < 0) && (hi1 == nil || bytes.Compare(lo2, hi1) < 0)
+}
+
+// keyInterval returns the half-open interval of the keys of a range. A nil
+// hi means the range has no upper bound.
+func keyInterval(key, end []byte) (lo, hi []byte) {
+	switch {
+	case len(end) == 0:
+		return key, append(append([]byte{}, key...), 0)
+	case len(end) == 1 && end[0] == 0:
+		return key, nil
+	}
+	return key, end
+}
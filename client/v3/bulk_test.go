@@ -0,0 +1,217 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+// recordingKV records the operations of the committed Txns.
+type recordingKV struct {
+	KV
+
+	mu   sync.Mutex
+	txns [][]Op
+	rev  int64
+	err  error
+}
+
+func (kv *recordingKV) Txn(ctx context.Context) Txn {
+	return &recordingTxn{kv: kv}
+}
+
+func (kv *recordingKV) batches() [][]Op {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	return kv.txns
+}
+
+type recordingTxn struct {
+	Txn
+	kv  *recordingKV
+	ops []Op
+}
+
+func (txn *recordingTxn) Then(ops ...Op) Txn {
+	txn.ops = append(txn.ops, ops...)
+	return txn
+}
+
+func (txn *recordingTxn) Commit() (*TxnResponse, error) {
+	kv := txn.kv
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	if kv.err != nil {
+		return nil, kv.err
+	}
+	kv.txns = append(kv.txns, txn.ops)
+	kv.rev++
+	resp := &TxnResponse{Header: &pb.ResponseHeader{Revision: kv.rev}, Succeeded: true}
+	for _, op := range txn.ops {
+		if op.IsPut() {
+			resp.Responses = append(resp.Responses, &pb.ResponseOp{Response: &pb.ResponseOp_ResponsePut{ResponsePut: &pb.PutResponse{}}})
+		} else {
+			resp.Responses = append(resp.Responses, &pb.ResponseOp{Response: &pb.ResponseOp_ResponseDeleteRange{ResponseDeleteRange: &pb.DeleteRangeResponse{Deleted: 1}}})
+		}
+	}
+	return resp, nil
+}
+
+func keysOf(ops []Op) []string {
+	var keys []string
+	for _, op := range ops {
+		keys = append(keys, string(op.KeyBytes()))
+	}
+	return keys
+}
+
+func TestBulkKVBatchesOps(t *testing.T) {
+	kv := &recordingKV{}
+	bkv := NewBulkKV(kv, BulkConfig{FlushInterval: time.Hour, MaxOps: 3})
+	defer bkv.Close()
+	ctx := context.Background()
+
+	var results []*BulkResult
+	for i := 0; i < 7; i++ {
+		r, err := bkv.Enqueue(ctx, OpPut(fmt.Sprintf("k%d", i), "v"))
+		require.NoError(t, err)
+		results = append(results, r)
+	}
+	require.NoError(t, bkv.Flush(ctx))
+
+	batches := kv.batches()
+	require.Len(t, batches, 3)
+	assert.Equal(t, []string{"k0", "k1", "k2"}, keysOf(batches[0]))
+	assert.Equal(t, []string{"k3", "k4", "k5"}, keysOf(batches[1]))
+	assert.Equal(t, []string{"k6"}, keysOf(batches[2]))
+	for i, r := range results {
+		resp, err := r.Wait(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, resp.Put())
+		assert.Equal(t, int64(i/3+1), resp.Put().Header.Revision)
+	}
+}
+
+func TestBulkKVFlushInterval(t *testing.T) {
+	kv := &recordingKV{}
+	bkv := NewBulkKV(kv, BulkConfig{FlushInterval: 10 * time.Millisecond})
+	defer bkv.Close()
+
+	resp, err := bkv.Delete(context.Background(), "foo")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.Deleted)
+	assert.Equal(t, int64(1), resp.Header.Revision)
+}
+
+func TestBulkKVMaxBytes(t *testing.T) {
+	kv := &recordingKV{}
+	size := OpPut("k0", "value").toRequestOp().Size()
+	bkv := NewBulkKV(kv, BulkConfig{FlushInterval: time.Hour, MaxBytes: 2 * size})
+	defer bkv.Close()
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		_, err := bkv.Enqueue(ctx, OpPut(fmt.Sprintf("k%d", i), "value"))
+		require.NoError(t, err)
+	}
+	require.NoError(t, bkv.Flush(ctx))
+
+	batches := kv.batches()
+	require.Len(t, batches, 3)
+	assert.Equal(t, []string{"k0", "k1"}, keysOf(batches[0]))
+	assert.Equal(t, []string{"k2", "k3"}, keysOf(batches[1]))
+	assert.Equal(t, []string{"k4"}, keysOf(batches[2]))
+}
+
+func TestBulkKVConflictingOpsStartNewBatch(t *testing.T) {
+	kv := &recordingKV{}
+	bkv := NewBulkKV(kv, BulkConfig{FlushInterval: time.Hour})
+	defer bkv.Close()
+	ctx := context.Background()
+
+	ops := []Op{
+		OpPut("a", "1"),
+		OpPut("b", "1"),
+		OpPut("a", "2"),
+		OpDelete("c"),
+		OpDelete("a", WithPrefix()),
+		OpPut("d", "1"),
+		OpPut("ab", "1"),
+	}
+	for _, op := range ops {
+		_, err := bkv.Enqueue(ctx, op)
+		require.NoError(t, err)
+	}
+	require.NoError(t, bkv.Flush(ctx))
+
+	batches := kv.batches()
+	require.Len(t, batches, 4)
+	assert.Equal(t, []string{"a", "b"}, keysOf(batches[0]))
+	assert.Equal(t, []string{"a", "c"}, keysOf(batches[1]))
+	assert.Equal(t, []string{"a", "d"}, keysOf(batches[2]))
+	assert.Equal(t, []string{"ab"}, keysOf(batches[3]))
+}
+
+func TestBulkKVErrors(t *testing.T) {
+	errTxn := errors.New("txn failed")
+	kv := &recordingKV{err: errTxn}
+	bkv := NewBulkKV(kv, BulkConfig{FlushInterval: time.Millisecond})
+
+	_, err := bkv.Enqueue(context.Background(), OpGet("foo"))
+	require.ErrorIs(t, err, ErrBulkUnsupportedOp)
+
+	_, err = bkv.Put(context.Background(), "foo", "bar")
+	require.ErrorIs(t, err, errTxn)
+
+	// operations whose context is done when their batch is sent are dropped.
+	kv.mu.Lock()
+	kv.err = nil
+	kv.mu.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = bkv.Put(ctx, "foo", "bar")
+	require.ErrorIs(t, err, context.Canceled)
+
+	bkv.Close()
+	assert.Empty(t, kv.batches())
+	_, err = bkv.Put(context.Background(), "foo", "bar")
+	require.ErrorIs(t, err, ErrBulkClosed)
+}
+
+func TestBulkKVCloseCommitsQueuedOps(t *testing.T) {
+	kv := &recordingKV{}
+	bkv := NewBulkKV(kv, BulkConfig{FlushInterval: time.Hour})
+
+	r, err := bkv.Enqueue(context.Background(), OpPut("foo", "bar"))
+	require.NoError(t, err)
+	bkv.Close()
+
+	select {
+	case <-r.Done():
+	default:
+		t.Fatal("queued operation not completed on close")
+	}
+	require.Len(t, kv.batches(), 1)
+}
@@ -36,7 +36,9 @@ import (
 	"go.etcd.io/etcd/client/pkg/v3/logutil"
 	"go.etcd.io/etcd/client/pkg/v3/verify"
 	"go.etcd.io/etcd/client/v3/credentials"
+	"go.etcd.io/etcd/client/v3/internal/circuitbreaker"
 	"go.etcd.io/etcd/client/v3/internal/endpoint"
+	"go.etcd.io/etcd/client/v3/internal/followerread"
 	"go.etcd.io/etcd/client/v3/internal/resolver"
 )
 
@@ -78,6 +80,17 @@ type Client struct {
 
 	callOpts []grpc.CallOption
 
+	// maxRequestBytes caches the smallest server request size limit when
+	// cfg.AutoMaxRequestBytes is set.
+	maxRequestBytes *requestSizeLimit
+
+	// retryPolicy is the retry policy of the requests, filled with the
+	// defaults. Requests may override it through WithRetryPolicy.
+	retryPolicy RetryPolicy
+
+	// quic is set if the client was given QUIC endpoints.
+	quic bool
+
 	lgMu *sync.RWMutex
 	lg   *zap.Logger
 }
@@ -96,7 +109,7 @@ func New(cfg Config) (*Client, error) {
 // service interface implementations and do not need connection management.
 func NewCtxClient(ctx context.Context, opts ...Option) *Client {
 	cctx, cancel := context.WithCancel(ctx)
-	c := &Client{ctx: cctx, cancel: cancel, lgMu: new(sync.RWMutex), epMu: new(sync.RWMutex)}
+	c := &Client{ctx: cctx, cancel: cancel, lgMu: new(sync.RWMutex), epMu: new(sync.RWMutex), maxRequestBytes: new(requestSizeLimit)}
 	for _, opt := range opts {
 		opt(c)
 	}
@@ -196,7 +209,7 @@ func (c *Client) Sync(ctx context.Context) error {
 	var eps []string
 	for _, m := range mresp.Members {
 		if len(m.Name) != 0 && !m.IsLearner {
-			eps = append(eps, m.ClientURLs...)
+			eps = append(eps, syncedEndpoints(m.ClientURLs, c.quic)...)
 		}
 	}
 	// The linearizable `MemberList` returned successfully, so the
@@ -243,6 +256,9 @@ func (c *Client) dialSetupOpts(creds grpccredentials.TransportCredentials, dopts
 		}
 		opts = append(opts, grpc.WithKeepaliveParams(params))
 	}
+	if c.quic {
+		opts = append(opts, grpc.WithContextDialer(c.dialQUIC))
+	}
 	opts = append(opts, dopts...)
 
 	if creds != nil {
@@ -251,31 +267,19 @@ func (c *Client) dialSetupOpts(creds grpccredentials.TransportCredentials, dopts
 		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
 
-	unaryMaxRetries := defaultUnaryMaxRetries
-	if c.cfg.MaxUnaryRetries > 0 {
-		unaryMaxRetries = c.cfg.MaxUnaryRetries
-	}
-
-	backoffWaitBetween := defaultBackoffWaitBetween
-	if c.cfg.BackoffWaitBetween > 0 {
-		backoffWaitBetween = c.cfg.BackoffWaitBetween
-	}
-
-	backoffJitterFraction := defaultBackoffJitterFraction
-	if c.cfg.BackoffJitterFraction > 0 {
-		backoffJitterFraction = c.cfg.BackoffJitterFraction
-	}
-
 	// Interceptor retry and backoff.
 	// TODO: Replace all of clientv3/retry.go with RetryPolicy:
 	// https://github.com/grpc/grpc-proto/blob/cdd9ed5c3d3f87aef62f373b93361cf7bddc620d/grpc/service_config/service_config.proto#L130
-	rrBackoff := withBackoff(c.roundRobinQuorumBackoff(backoffWaitBetween, backoffJitterFraction))
+	retryPolicy := c.withRetryPolicy(c.retryPolicy)
 	opts = append(opts,
 		// Disable stream retry by default since go-grpc-middleware/retry does not support client streams.
 		// Streams that are safe to retry are enabled individually.
-		grpc.WithStreamInterceptor(c.streamClientInterceptor(withMax(0), rrBackoff)),
-		grpc.WithUnaryInterceptor(c.unaryClientInterceptor(withMax(unaryMaxRetries), rrBackoff)),
+		grpc.WithStreamInterceptor(c.streamClientInterceptor(retryPolicy, withMax(0))),
+		grpc.WithUnaryInterceptor(c.unaryClientInterceptor(retryPolicy)),
 	)
+	if c.cfg.FollowerReads != nil {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(followerread.UnaryClientInterceptor))
+	}
 
 	return opts
 }
@@ -309,6 +313,9 @@ func (c *Client) getToken(ctx context.Context) error {
 		}
 		return err
 	}
+	if resp.PasswordExpireTime != 0 && time.Now().Unix() >= resp.PasswordExpireTime {
+		c.GetLogger().Warn("password has expired, change it before its grace period ends", zap.String("user", c.Username))
+	}
 	c.authTokenBundle.UpdateAuthToken(resp.Token)
 	return nil
 }
@@ -397,14 +404,17 @@ func newClient(cfg *Config) (*Client, error) {
 
 	ctx, cancel := context.WithCancel(baseCtx)
 	client := &Client{
-		conn:     nil,
-		cfg:      *cfg,
-		creds:    creds,
-		ctx:      ctx,
-		cancel:   cancel,
-		epMu:     new(sync.RWMutex),
-		callOpts: defaultCallOpts,
-		lgMu:     new(sync.RWMutex),
+		conn:            nil,
+		cfg:             *cfg,
+		creds:           creds,
+		ctx:             ctx,
+		cancel:          cancel,
+		epMu:            new(sync.RWMutex),
+		callOpts:        defaultCallOpts,
+		lgMu:            new(sync.RWMutex),
+		maxRequestBytes: new(requestSizeLimit),
+		retryPolicy:     cfg.retryPolicy(),
+		quic:            usesQUIC(cfg.Endpoints),
 	}
 
 	var err error
@@ -451,7 +461,33 @@ func newClient(cfg *Config) (*Client, error) {
 		client.callOpts = callOpts
 	}
 
-	client.resolver = resolver.New(cfg.Endpoints...)
+	switch {
+	case cfg.CircuitBreaker != nil && cfg.FollowerReads != nil:
+		client.cancel()
+		return nil, errors.New("CircuitBreaker and FollowerReads configurations are mutually exclusive")
+	case cfg.CircuitBreaker != nil:
+		sc, err := circuitbreaker.ServiceConfig(circuitbreaker.Config{
+			FailureThreshold: cfg.CircuitBreaker.FailureThreshold,
+			Cooldown:         cfg.CircuitBreaker.Cooldown,
+		})
+		if err != nil {
+			client.cancel()
+			return nil, err
+		}
+		client.resolver = resolver.NewWithServiceConfig(sc, cfg.Endpoints...)
+	case cfg.FollowerReads != nil:
+		sc, err := followerread.ServiceConfig(followerread.Config{
+			ProbeInterval: cfg.FollowerReads.ProbeInterval,
+		})
+		if err != nil {
+			client.cancel()
+			return nil, err
+		}
+		client.resolver = resolver.NewWithServiceConfig(sc, cfg.Endpoints...)
+		client.resolver.SetAttributes(followerread.WithDialFunc(nil, client.dialFollowerReadProber))
+	default:
+		client.resolver = resolver.New(cfg.Endpoints...)
+	}
 
 	if len(cfg.Endpoints) < 1 {
 		client.cancel()
@@ -502,22 +538,6 @@ func newClient(cfg *Config) (*Client, error) {
 	return client, nil
 }
 
-// roundRobinQuorumBackoff retries against quorum between each backoff.
-// This is intended for use with a round robin load balancer.
-func (c *Client) roundRobinQuorumBackoff(waitBetween time.Duration, jitterFraction float64) backoffFunc {
-	return func(attempt uint) time.Duration {
-		// after each round robin across quorum, backoff for our wait between duration
-		n := uint(len(c.Endpoints()))
-		quorum := (n/2 + 1)
-		if attempt%quorum == 0 {
-			c.lg.Debug("backoff", zap.Uint("attempt", attempt), zap.Uint("quorum", quorum), zap.Duration("waitBetween", waitBetween), zap.Float64("jitterFraction", jitterFraction))
-			return jitterUp(waitBetween, jitterFraction)
-		}
-		c.lg.Debug("backoff skipped", zap.Uint("attempt", attempt), zap.Uint("quorum", quorum))
-		return 0
-	}
-}
-
 // minSupportedVersion returns the minimum version supported, which is the previous minor release.
 func minSupportedVersion() *semver.Version {
 	ver := semver.Must(semver.NewVersion(version.Version))
@@ -525,6 +525,18 @@ func (mm mockMaintenance) Defragment(ctx context.Context, endpoint string) (*Def
 	return nil, nil
 }
 
+func (mm mockMaintenance) DefragmentAsync(ctx context.Context, endpoint string) (*DefragmentResponse, error) {
+	return nil, nil
+}
+
+func (mm mockMaintenance) DefragmentStatus(ctx context.Context, endpoint string) (*DefragmentStatusResponse, error) {
+	return nil, nil
+}
+
+func (mm mockMaintenance) WatchDefragmentStatus(ctx context.Context, endpoint string, fn func(*DefragmentStatusResponse)) error {
+	return nil
+}
+
 func (mm mockMaintenance) HashKV(ctx context.Context, endpoint string, rev int64) (*HashKVResponse, error) {
 	return nil, nil
 }
@@ -545,6 +557,46 @@ func (mm mockMaintenance) Downgrade(ctx context.Context, action DowngradeAction,
 	return nil, nil
 }
 
+func (mm mockMaintenance) Config(ctx context.Context, endpoint string) (*ConfigResponse, error) {
+	return nil, nil
+}
+
+func (mm mockMaintenance) KeyAccessTimes(ctx context.Context, endpoint, key string, opts ...OpOption) (*KeyAccessTimesResponse, error) {
+	return nil, nil
+}
+
+func (mm mockMaintenance) MembershipCheck(ctx context.Context, endpoint string) (*MembershipCheckResponse, error) {
+	return nil, nil
+}
+
+func (mm mockMaintenance) RotateEncryptionKey(ctx context.Context, endpoint string, reencrypt bool) (*RotateEncryptionKeyResponse, error) {
+	return nil, nil
+}
+
+func (mm mockMaintenance) ReadOnly(ctx context.Context, action ReadOnlyAction) (*ReadOnlyResponse, error) {
+	return nil, nil
+}
+
+func (mm mockMaintenance) FollowerLag(ctx context.Context, endpoint string) (*FollowerLagResponse, error) {
+	return nil, nil
+}
+
+func (mm mockMaintenance) HashKVCheck(ctx context.Context, endpoint string, rev int64) (*HashKVCheckResponse, error) {
+	return nil, nil
+}
+
+func (mm mockMaintenance) MirrorStatus(ctx context.Context, endpoint string) (*MirrorStatusResponse, error) {
+	return nil, nil
+}
+
+func (mm mockMaintenance) Reclaim(ctx context.Context, endpoint string, rev int64, fn func(*ReclaimResponse)) error {
+	return nil
+}
+
+func (mm mockMaintenance) DowngradeCheck(ctx context.Context, version string) (*DowngradeCheckResponse, error) {
+	return nil, nil
+}
+
 type mockFailingAuthServer struct {
 	*etcdserverpb.UnimplementedAuthServer
 }
@@ -577,6 +629,10 @@ func (mc *mockCluster) MemberAddAsLearner(ctx context.Context, peerAddrs []strin
 	return nil, nil
 }
 
+func (mc *mockCluster) MemberAddAsStandby(ctx context.Context, peerAddrs []string) (*MemberAddResponse, error) {
+	return nil, nil
+}
+
 func (mc *mockCluster) MemberRemove(ctx context.Context, id uint64) (*MemberRemoveResponse, error) {
 	return nil, nil
 }
@@ -588,3 +644,7 @@ func (mc *mockCluster) MemberUpdate(ctx context.Context, id uint64, peerAddrs []
 func (mc *mockCluster) MemberPromote(ctx context.Context, id uint64) (*MemberPromoteResponse, error) {
 	return nil, nil
 }
+
+func (mc *mockCluster) MemberReplace(ctx context.Context, id uint64, peerAddrs []string) (*MemberReplaceResponse, error) {
+	return nil, nil
+}
@@ -0,0 +1,34 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package clientv3test provides an in-memory implementation of the clientv3
+// KV, Watcher and Lease interfaces, to unit test applications against the
+// behavior of etcd without running a server.
+//
+// The fake keeps the revision semantics of etcd: every write transaction
+// creates a revision, the keys keep their create and mod revisions and
+// versions, past revisions can be read and watched until compacted, and
+// deleting a lease deletes its keys. It does not implement authentication,
+// membership or maintenance, nor the limits of a server, such as the
+// request size or the number of operations of a transaction.
+//
+// Create a fake with New, and pass it where a KV, Watcher or Lease is
+// expected, or use its Client for code taking a *clientv3.Client:
+//
+//	fake := clientv3test.New()
+//	cli := fake.Client()
+//	defer cli.Close()
+//
+//	cli.Put(ctx, "foo", "bar")
+package clientv3test
@@ -0,0 +1,191 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3test
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+var (
+	_ clientv3.KV      = (*Fake)(nil)
+	_ clientv3.Watcher = (*Fake)(nil)
+	_ clientv3.Lease   = (*Fake)(nil)
+)
+
+// Fake is an in-memory etcd implementing the KV, Watcher and Lease
+// interfaces. It is safe for concurrent use.
+type Fake struct {
+	mu sync.Mutex
+	// rev is the current revision, and compactRev the last compaction.
+	rev        int64
+	compactRev int64
+	// keys maps the keys to their versions not compacted, oldest first.
+	keys map[string][]version
+
+	leases    map[clientv3.LeaseID]*lease
+	lastLease clientv3.LeaseID
+
+	watchers map[*watcher]struct{}
+
+	closed bool
+	stopc  chan struct{}
+	wg     sync.WaitGroup
+}
+
+// version is a change of a key, with its sub-revision within its revision.
+type version struct {
+	ev  *clientv3.Event
+	sub int
+}
+
+func (v version) rev() int64 { return v.ev.Kv.ModRevision }
+
+// New returns an empty fake at revision 1, like a new cluster.
+func New() *Fake {
+	return &Fake{
+		rev:      1,
+		keys:     make(map[string][]version),
+		leases:   make(map[clientv3.LeaseID]*lease),
+		watchers: make(map[*watcher]struct{}),
+		stopc:    make(chan struct{}),
+	}
+}
+
+// Client returns a client whose KV, Watcher and Lease are served by the
+// fake, for the code taking a *clientv3.Client. Its other interfaces are
+// not set. Closing the client closes the fake.
+func (f *Fake) Client() *clientv3.Client {
+	c := clientv3.NewCtxClient(context.Background())
+	c.KV, c.Watcher, c.Lease = f, f, f
+	return c
+}
+
+// Close closes the watches and stops the keep-alives and the expiry of the
+// leases and keys. The fake keeps serving the other requests.
+func (f *Fake) Close() error {
+	f.mu.Lock()
+	if !f.closed {
+		f.closed = true
+		close(f.stopc)
+		for _, l := range f.leases {
+			l.timer.Stop()
+		}
+	}
+	f.mu.Unlock()
+	f.wg.Wait()
+	return nil
+}
+
+func (f *Fake) header() *pb.ResponseHeader {
+	return &pb.ResponseHeader{Revision: f.rev}
+}
+
+// rangeKeys returns the key-values of the range at the given revision, or
+// at the latest revision if rev is 0, in key order.
+func (f *Fake) rangeKeys(key, end []byte, rev int64) []*mvccpb.KeyValue {
+	var kvs []*mvccpb.KeyValue
+	for k, versions := range f.keys {
+		if !inRange([]byte(k), key, end) {
+			continue
+		}
+		if kv := latest(versions, rev); kv != nil {
+			kvs = append(kvs, kv)
+		}
+	}
+	sort.Slice(kvs, func(i, j int) bool { return bytes.Compare(kvs[i].Key, kvs[j].Key) < 0 })
+	return kvs
+}
+
+// get returns the key-value of a key at the latest revision, if it exists.
+func (f *Fake) get(key string) *mvccpb.KeyValue {
+	return latest(f.keys[key], 0)
+}
+
+// latest returns the key-value of the versions at the given revision, or at
+// the latest revision if rev is 0, if the key existed.
+func latest(versions []version, rev int64) *mvccpb.KeyValue {
+	for i := len(versions) - 1; i >= 0; i-- {
+		if rev > 0 && versions[i].rev() > rev {
+			continue
+		}
+		if versions[i].ev.Type == mvccpb.DELETE {
+			return nil
+		}
+		return versions[i].ev.Kv
+	}
+	return nil
+}
+
+// inRange tells whether k is in the range of a request on key and end.
+func inRange(k, key, end []byte) bool {
+	switch {
+	case len(end) == 0:
+		return bytes.Equal(k, key)
+	case len(end) == 1 && end[0] == 0:
+		return bytes.Compare(k, key) >= 0
+	}
+	return bytes.Compare(k, key) >= 0 && bytes.Compare(k, end) < 0
+}
+
+// compact drops the versions only needed to read or watch the revisions
+// before rev.
+func (f *Fake) compact(rev int64) {
+	for k, versions := range f.keys {
+		i := sort.Search(len(versions), func(i int) bool { return versions[i].rev() >= rev })
+		// keep the version current at rev, unless a deletion.
+		if i > 0 && versions[i-1].ev.Type == mvccpb.PUT {
+			i--
+		}
+		if versions = versions[i:]; len(versions) == 0 {
+			delete(f.keys, k)
+			continue
+		}
+		f.keys[k] = append([]version(nil), versions...)
+	}
+	f.compactRev = rev
+}
+
+// history returns the changes of the range since rev, in order.
+func (f *Fake) history(key, end []byte, rev int64) []*clientv3.Event {
+	var versions []version
+	for k, vs := range f.keys {
+		if !inRange([]byte(k), key, end) {
+			continue
+		}
+		for _, v := range vs {
+			if v.rev() >= rev {
+				versions = append(versions, v)
+			}
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		if versions[i].rev() != versions[j].rev() {
+			return versions[i].rev() < versions[j].rev()
+		}
+		return versions[i].sub < versions[j].sub
+	})
+	events := make([]*clientv3.Event, len(versions))
+	for i, v := range versions {
+		events[i] = v.ev
+	}
+	return events
+}
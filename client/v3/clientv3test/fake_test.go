@@ -0,0 +1,434 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	v3rpc "go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+)
+
+func keys(kvs []*mvccpb.KeyValue) []string {
+	var ks []string
+	for _, kv := range kvs {
+		ks = append(ks, string(kv.Key))
+	}
+	return ks
+}
+
+func TestKVRevisions(t *testing.T) {
+	f := New()
+	defer f.Close()
+	ctx := context.Background()
+
+	_, err := f.Put(ctx, "a", "1")
+	require.NoError(t, err)
+	presp, err := f.Put(ctx, "a", "2", clientv3.WithPrevKV())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), presp.Header.Revision)
+	assert.Equal(t, "1", string(presp.PrevKv.Value))
+	_, err = f.Put(ctx, "b", "1")
+	require.NoError(t, err)
+
+	resp, err := f.Get(ctx, "a")
+	require.NoError(t, err)
+	kv := resp.Kvs[0]
+	assert.Equal(t, []int64{2, 3, 2}, []int64{kv.CreateRevision, kv.ModRevision, kv.Version})
+
+	// deleting nothing does not create a revision.
+	dresp, err := f.Delete(ctx, "c")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), dresp.Deleted)
+	assert.Equal(t, int64(4), dresp.Header.Revision)
+	dresp, err = f.Delete(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), dresp.Header.Revision)
+
+	resp, err = f.Get(ctx, "", clientv3.WithPrefix(), clientv3.WithRev(3))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, keys(resp.Kvs))
+	assert.Equal(t, "2", string(resp.Kvs[0].Value))
+	resp, err = f.Get(ctx, "", clientv3.WithPrefix())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, keys(resp.Kvs))
+	_, err = f.Get(ctx, "a", clientv3.WithRev(6))
+	require.ErrorIs(t, err, v3rpc.ErrFutureRev)
+
+	// the revision current at the compaction can still be read.
+	_, err = f.Compact(ctx, 3)
+	require.NoError(t, err)
+	_, err = f.Get(ctx, "a", clientv3.WithRev(2))
+	require.ErrorIs(t, err, v3rpc.ErrCompacted)
+	resp, err = f.Get(ctx, "a", clientv3.WithRev(3))
+	require.NoError(t, err)
+	assert.Equal(t, "2", string(resp.Kvs[0].Value))
+}
+
+func TestKVGetOptions(t *testing.T) {
+	f := New()
+	defer f.Close()
+	ctx := context.Background()
+	for _, kv := range [][2]string{{"k3", "a"}, {"k1", "c"}, {"k2", "b"}, {"x", "d"}} {
+		_, err := f.Put(ctx, kv[0], kv[1])
+		require.NoError(t, err)
+	}
+
+	resp, err := f.Get(ctx, "k", clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByValue, clientv3.SortAscend), clientv3.WithLimit(2))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"k3", "k2"}, keys(resp.Kvs))
+	assert.True(t, resp.More)
+	assert.Equal(t, int64(3), resp.Count)
+
+	resp, err = f.Get(ctx, "k", clientv3.WithFirstCreate()...)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"k3"}, keys(resp.Kvs))
+
+	resp, err = f.Get(ctx, "k2", clientv3.WithFromKey(), clientv3.WithKeysOnly())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"k2", "k3", "x"}, keys(resp.Kvs))
+	assert.Nil(t, resp.Kvs[0].Value)
+
+	resp, err = f.Get(ctx, "", clientv3.WithFromKey(), clientv3.WithKeyFilterRegex(`^k[12]$`), clientv3.WithLimit(1))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"k1"}, keys(resp.Kvs))
+	assert.True(t, resp.More)
+	_, err = f.Get(ctx, "k", clientv3.WithPrefix(), clientv3.WithKeyFilterRegex("("))
+	require.ErrorIs(t, err, v3rpc.ErrInvalidKeyFilter)
+
+	resp, err = f.Get(ctx, "k", clientv3.WithPrefix(), clientv3.WithCountOnly())
+	require.NoError(t, err)
+	assert.Empty(t, resp.Kvs)
+	assert.Equal(t, int64(3), resp.Count)
+
+	lresp, err := f.Grant(ctx, 60)
+	require.NoError(t, err)
+	_, err = f.Put(ctx, "k2", "b", clientv3.WithLease(lresp.ID))
+	require.NoError(t, err)
+	resp, err = f.Get(ctx, "", clientv3.WithFromKey(), clientv3.WithLeaseFilter(lresp.ID))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"k2"}, keys(resp.Kvs))
+	resp, err = f.Get(ctx, "k", clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByLease, clientv3.SortDescend))
+	require.NoError(t, err)
+	assert.Equal(t, "k2", string(resp.Kvs[0].Key))
+
+	resp, err = f.Get(ctx, "k", clientv3.WithPrefix(), clientv3.WithSizeOnly())
+	require.NoError(t, err)
+	assert.Empty(t, resp.Kvs)
+	assert.Equal(t, int64(3), resp.Count)
+	assert.Positive(t, resp.TotalSize)
+}
+
+func TestKVPager(t *testing.T) {
+	f := New()
+	defer f.Close()
+	ctx := context.Background()
+	for _, k := range []string{"k1", "k2", "k3", "k4", "k5"} {
+		_, err := f.Put(ctx, k, "a")
+		require.NoError(t, err)
+	}
+
+	// the pages are read at the revision of the first one.
+	p := clientv3.NewPager(f, "k", 2, clientv3.WithPrefix())
+	var got []string
+	for !p.Done() {
+		resp, err := p.Next(ctx)
+		require.NoError(t, err)
+		got = append(got, keys(resp.Kvs)...)
+		_, err = f.Put(ctx, "k0", "b")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"k1", "k2", "k3", "k4", "k5"}, got)
+
+	_, err := f.Get(ctx, "k", clientv3.WithPrefix(), clientv3.WithContinueToken([]byte("garbage")))
+	require.ErrorIs(t, err, v3rpc.ErrInvalidContinueToken)
+	resp, err := f.Get(ctx, "k", clientv3.WithPrefix(), clientv3.WithLimit(1))
+	require.NoError(t, err)
+	_, err = f.Put(ctx, "k6", "a")
+	require.NoError(t, err)
+	_, err = f.Compact(ctx, resp.Header.Revision+1)
+	require.NoError(t, err)
+	_, err = f.Get(ctx, "k", clientv3.WithPrefix(), clientv3.WithContinueToken(resp.NextToken))
+	require.ErrorIs(t, err, v3rpc.ErrCompacted)
+}
+
+func TestTxn(t *testing.T) {
+	f := New()
+	defer f.Close()
+	ctx := context.Background()
+	_, err := f.Put(ctx, "a", "1")
+	require.NoError(t, err)
+
+	resp, err := f.Txn(ctx).If(
+		clientv3.Compare(clientv3.Value("a"), "=", "1"),
+		clientv3.Compare(clientv3.CreateRevision("b"), "=", 0),
+	).Then(
+		clientv3.OpPut("b", "1"),
+		clientv3.OpPut("c", "1"),
+		clientv3.OpGet("", clientv3.WithPrefix()),
+	).Commit()
+	require.NoError(t, err)
+	require.True(t, resp.Succeeded)
+	assert.Equal(t, int64(3), resp.Header.Revision)
+	// the operations see the changes of the previous ones.
+	assert.Equal(t, []string{"a", "b", "c"}, keys(resp.Responses[2].GetResponseRange().Kvs))
+
+	resp, err = f.Txn(ctx).If(clientv3.Compare(clientv3.Version("").WithPrefix(), ">", 1)).Else(clientv3.OpGet("a")).Commit()
+	require.NoError(t, err)
+	require.False(t, resp.Succeeded)
+	assert.Len(t, resp.Responses[0].GetResponseRange().Kvs, 1)
+
+	resp, err = f.Txn(ctx).If(
+		clientv3.Compare(clientv3.Count("").WithPrefix(), "=", 3),
+		clientv3.Compare(clientv3.Count("x"), "=", 0),
+		clientv3.Compare(clientv3.ValuePrefix("a"), "=", ""),
+	).Commit()
+	require.NoError(t, err)
+	require.True(t, resp.Succeeded)
+	resp, err = f.Txn(ctx).If(clientv3.Compare(clientv3.ValuePrefix("x"), "!=", "1")).Commit()
+	require.NoError(t, err)
+	// a value prefix comparison fails on a missing key.
+	require.False(t, resp.Succeeded)
+
+	// a failing operation undoes the previous ones.
+	_, err = f.Txn(ctx).Then(clientv3.OpPut("d", "1"), clientv3.OpPut("e", "1", clientv3.WithLease(42))).Commit()
+	require.ErrorIs(t, err, v3rpc.ErrLeaseNotFound)
+	_, err = f.Txn(ctx).Then(clientv3.OpPut("d", "1"), clientv3.OpDelete("d")).Commit()
+	require.ErrorIs(t, err, v3rpc.ErrDuplicateKey)
+	gresp, err := f.Get(ctx, "d")
+	require.NoError(t, err)
+	assert.Empty(t, gresp.Kvs)
+	assert.Equal(t, int64(3), gresp.Header.Revision)
+}
+
+func TestWatch(t *testing.T) {
+	f := New()
+	defer f.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := f.Put(ctx, "a", "1")
+	require.NoError(t, err)
+	_, err = f.Put(ctx, "b", "1")
+	require.NoError(t, err)
+
+	// the history is replayed from the start revision.
+	wch := f.Watch(ctx, "", clientv3.WithPrefix(), clientv3.WithRev(2), clientv3.WithPrevKV())
+	wr := <-wch
+	require.Len(t, wr.Events, 2)
+	assert.Equal(t, int64(3), wr.Header.Revision)
+
+	_, err = f.Txn(ctx).Then(clientv3.OpDelete("a"), clientv3.OpPut("b", "2")).Commit()
+	require.NoError(t, err)
+	wr = <-wch
+	require.Len(t, wr.Events, 2)
+	assert.Equal(t, mvccpb.DELETE, wr.Events[0].Type)
+	assert.Equal(t, "1", string(wr.Events[0].PrevKv.Value))
+	assert.Equal(t, int64(4), wr.Events[1].Kv.ModRevision)
+
+	fch := f.Watch(ctx, "b", clientv3.WithFilterPut(), clientv3.WithCreatedNotify())
+	require.True(t, (<-fch).Created)
+	_, err = f.Put(ctx, "b", "3")
+	require.NoError(t, err)
+	_, err = f.Delete(ctx, "b")
+	require.NoError(t, err)
+	wr = <-fch
+	require.Len(t, wr.Events, 1)
+	assert.Equal(t, mvccpb.DELETE, wr.Events[0].Type)
+	assert.Nil(t, wr.Events[0].PrevKv)
+
+	// value filters only apply to put events.
+	vch := f.Watch(ctx, "v", clientv3.WithPrefix(), clientv3.WithValuePrefix("x"), clientv3.WithMaxValueSize(2))
+	_, err = f.Put(ctx, "v1", "y")
+	require.NoError(t, err)
+	_, err = f.Put(ctx, "v1", "xyz")
+	require.NoError(t, err)
+	_, err = f.Put(ctx, "v2", "xy")
+	require.NoError(t, err)
+	_, err = f.Delete(ctx, "v1")
+	require.NoError(t, err)
+	wr = <-vch
+	require.Len(t, wr.Events, 1)
+	assert.Equal(t, "v2", string(wr.Events[0].Kv.Key))
+	wr = <-vch
+	require.Len(t, wr.Events, 1)
+	assert.Equal(t, mvccpb.DELETE, wr.Events[0].Type)
+
+	// watching compacted revisions fails.
+	_, err = f.Compact(ctx, 9)
+	require.NoError(t, err)
+	cch := f.Watch(ctx, "a", clientv3.WithRev(2))
+	wr = <-cch
+	require.ErrorIs(t, wr.Err(), v3rpc.ErrCompacted)
+	assert.Equal(t, int64(9), wr.CompactRevision)
+	_, ok := <-cch
+	require.False(t, ok)
+
+	// unless asked for a snapshot of the range, followed by the events.
+	sch := f.Watch(ctx, "", clientv3.WithPrefix(), clientv3.WithRev(2), clientv3.WithSnapshotOnCompaction())
+	wr = <-sch
+	require.NoError(t, wr.Err())
+	assert.True(t, wr.Snapshot)
+	assert.Equal(t, int64(10), wr.Header.Revision)
+	require.Len(t, wr.Events, 1)
+	assert.Equal(t, "v2", string(wr.Events[0].Kv.Key))
+	_, err = f.Put(ctx, "c", "1")
+	require.NoError(t, err)
+	wr = <-sch
+	assert.False(t, wr.Snapshot)
+	require.Len(t, wr.Events, 1)
+	assert.Equal(t, int64(11), wr.Events[0].Kv.ModRevision)
+
+	// canceling the watch closes its channel.
+	cancel()
+	for range wch {
+	}
+}
+
+func TestWatchBookmark(t *testing.T) {
+	f := New()
+	defer f.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wch := f.Watch(ctx, "a", clientv3.WithBookmarkInterval(10*time.Millisecond))
+	_, err := f.Put(ctx, "b", "1")
+	require.NoError(t, err)
+	_, err = f.Put(ctx, "a", "1")
+	require.NoError(t, err)
+	wr := <-wch
+	require.Len(t, wr.Events, 1)
+
+	// the bookmark covers revisions without events for the watch.
+	_, err = f.Put(ctx, "b", "2")
+	require.NoError(t, err)
+	for wr = range wch {
+		if wr.Bookmark && wr.Header.Revision == 4 {
+			break
+		}
+		require.Empty(t, wr.Events)
+		require.True(t, wr.IsProgressNotify())
+	}
+}
+
+func TestLease(t *testing.T) {
+	f := New()
+	defer f.Close()
+	ctx := context.Background()
+
+	lresp, err := f.Grant(ctx, 60)
+	require.NoError(t, err)
+	_, err = f.Put(ctx, "a", "1", clientv3.WithLease(lresp.ID))
+	require.NoError(t, err)
+	_, err = f.Put(ctx, "b", "1", clientv3.WithLease(lresp.ID))
+	require.NoError(t, err)
+	// moving a key to another lease detaches it.
+	_, err = f.Put(ctx, "b", "2")
+	require.NoError(t, err)
+
+	tresp, err := f.TimeToLive(ctx, lresp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(60), tresp.GrantedTTL)
+	assert.Equal(t, [][]byte{[]byte("a")}, tresp.Keys)
+
+	wch := f.Watch(ctx, "a")
+	_, err = f.Revoke(ctx, lresp.ID)
+	require.NoError(t, err)
+	wr := <-wch
+	require.Len(t, wr.Events, 1)
+	assert.Equal(t, mvccpb.DELETE, wr.Events[0].Type)
+
+	tresp, err = f.TimeToLive(ctx, lresp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(-1), tresp.TTL)
+	_, err = f.KeepAliveOnce(ctx, lresp.ID)
+	require.ErrorIs(t, err, v3rpc.ErrLeaseNotFound)
+}
+
+func TestLeaseExpiry(t *testing.T) {
+	f := New()
+	defer f.Close()
+	ctx := context.Background()
+
+	lresp, err := f.Grant(ctx, 1)
+	require.NoError(t, err)
+	_, err = f.Put(ctx, "a", "1", clientv3.WithLease(lresp.ID))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		resp, err := f.Get(ctx, "a")
+		return err == nil && len(resp.Kvs) == 0
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestKVTTL(t *testing.T) {
+	f := New()
+	defer f.Close()
+	ctx := context.Background()
+
+	_, err := f.Put(ctx, "a", "1", clientv3.WithTTL(1))
+	require.NoError(t, err)
+	_, err = f.Put(ctx, "b", "1", clientv3.WithTTL(1))
+	require.NoError(t, err)
+	// modifying the key cancels its expiry.
+	_, err = f.Put(ctx, "b", "2")
+	require.NoError(t, err)
+	resp, err := f.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.Kvs[0].Ttl)
+	_, err = f.Put(ctx, "c", "1", clientv3.WithTTL(1), clientv3.WithLease(1))
+	require.ErrorIs(t, err, v3rpc.ErrInvalidTTL)
+
+	require.Eventually(t, func() bool {
+		resp, err := f.Get(ctx, "", clientv3.WithPrefix())
+		return err == nil && len(resp.Kvs) == 1
+	}, 5*time.Second, 50*time.Millisecond)
+	resp, err = f.Get(ctx, "b")
+	require.NoError(t, err)
+	assert.Len(t, resp.Kvs, 1)
+}
+
+func TestClientConcurrency(t *testing.T) {
+	cli := New().Client()
+	defer cli.Close()
+
+	s1, err := concurrency.NewSession(cli)
+	require.NoError(t, err)
+	defer s1.Close()
+	s2, err := concurrency.NewSession(cli)
+	require.NoError(t, err)
+	defer s2.Close()
+
+	m1, m2 := concurrency.NewMutex(s1, "/lock"), concurrency.NewMutex(s2, "/lock")
+	require.NoError(t, m1.Lock(context.TODO()))
+	require.ErrorIs(t, m2.TryLock(context.TODO()), concurrency.ErrLocked)
+
+	locked := make(chan error, 1)
+	go func() { locked <- m2.Lock(context.TODO()) }()
+	require.NoError(t, m1.Unlock(context.TODO()))
+	select {
+	case err := <-locked:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("lock not acquired")
+	}
+}
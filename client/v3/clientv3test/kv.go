@@ -0,0 +1,544 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	v3rpc "go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+var errUnknownOp = errors.New("clientv3test: unknown operation")
+
+func (f *Fake) Put(ctx context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	resp, err := f.Do(ctx, clientv3.OpPut(key, val, opts...))
+	return resp.Put(), err
+}
+
+func (f *Fake) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	resp, err := f.Do(ctx, clientv3.OpGet(key, opts...))
+	return resp.Get(), err
+}
+
+func (f *Fake) Delete(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+	resp, err := f.Do(ctx, clientv3.OpDelete(key, opts...))
+	return resp.Del(), err
+}
+
+func (f *Fake) Compact(ctx context.Context, rev int64, opts ...clientv3.CompactOption) (*clientv3.CompactResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch {
+	case rev > f.rev:
+		return nil, v3rpc.ErrFutureRev
+	case rev <= f.compactRev:
+		return nil, v3rpc.ErrCompacted
+	}
+	f.compact(rev)
+	return &clientv3.CompactResponse{Header: f.header()}, nil
+}
+
+func (f *Fake) Do(ctx context.Context, op clientv3.Op) (clientv3.OpResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return clientv3.OpResponse{}, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := f.begin()
+	// like the server, only a Get outside of a transaction is paginated
+	w.paged = op.IsGet()
+	resp, err := w.do(op)
+	if err != nil {
+		w.rollback()
+		return clientv3.OpResponse{}, err
+	}
+	w.commit()
+	return resp, nil
+}
+
+func (f *Fake) Txn(ctx context.Context) clientv3.Txn {
+	return &txn{f: f, ctx: ctx}
+}
+
+type txn struct {
+	f   *Fake
+	ctx context.Context
+
+	cmps    []clientv3.Cmp
+	thenOps []clientv3.Op
+	elseOps []clientv3.Op
+}
+
+func (t *txn) If(cs ...clientv3.Cmp) clientv3.Txn {
+	t.cmps = append(t.cmps, cs...)
+	return t
+}
+
+func (t *txn) Then(ops ...clientv3.Op) clientv3.Txn {
+	t.thenOps = append(t.thenOps, ops...)
+	return t
+}
+
+func (t *txn) Else(ops ...clientv3.Op) clientv3.Txn {
+	t.elseOps = append(t.elseOps, ops...)
+	return t
+}
+
+func (t *txn) Commit() (*clientv3.TxnResponse, error) {
+	resp, err := t.f.Do(t.ctx, clientv3.OpTxn(t.cmps, t.thenOps, t.elseOps))
+	return resp.Txn(), err
+}
+
+// writeTxn applies operations at the next revision. The changes are visible
+// to the following operations, and undone if one fails.
+type writeTxn struct {
+	f   *Fake
+	rev int64
+	// hdr is the header of all the responses, set on commit.
+	hdr    *pb.ResponseHeader
+	events []*clientv3.Event
+	undo   []func()
+	// paged tells whether a Get honors and issues continue tokens.
+	paged bool
+}
+
+func (f *Fake) begin() *writeTxn {
+	return &writeTxn{f: f, rev: f.rev + 1, hdr: &pb.ResponseHeader{}}
+}
+
+func (w *writeTxn) commit() {
+	f := w.f
+	if len(w.events) > 0 {
+		f.rev = w.rev
+		f.notify(w.events)
+	}
+	for _, ev := range w.events {
+		if ev.Type == mvccpb.PUT && ev.Kv.Ttl > 0 {
+			f.expire(string(ev.Kv.Key), ev.Kv.ModRevision, ev.Kv.Ttl)
+		}
+	}
+	w.hdr.Revision = f.rev
+}
+
+func (w *writeTxn) rollback() {
+	for i := len(w.undo) - 1; i >= 0; i-- {
+		w.undo[i]()
+	}
+}
+
+func (w *writeTxn) do(op clientv3.Op) (clientv3.OpResponse, error) {
+	switch {
+	case op.IsGet():
+		resp, err := w.get(op)
+		if err != nil {
+			return clientv3.OpResponse{}, err
+		}
+		return resp.OpResponse(), nil
+	case op.IsPut():
+		resp, err := w.put(op)
+		if err != nil {
+			return clientv3.OpResponse{}, err
+		}
+		return resp.OpResponse(), nil
+	case op.IsDelete():
+		return w.delete(op).OpResponse(), nil
+	case op.IsTxn():
+		resp, err := w.txn(op)
+		if err != nil {
+			return clientv3.OpResponse{}, err
+		}
+		return resp.OpResponse(), nil
+	}
+	return clientv3.OpResponse{}, errUnknownOp
+}
+
+func (w *writeTxn) get(op clientv3.Op) (*clientv3.GetResponse, error) {
+	f := w.f
+	key, rev := op.KeyBytes(), op.Rev()
+	if token := op.ContinueToken(); w.paged && len(token) > 0 {
+		trev, lastKey, ok := decodeContinueToken(token)
+		end := op.RangeBytes()
+		if !ok || len(end) == 0 || !keyAscending(op.Sort()) || (rev != 0 && rev != trev) {
+			return nil, v3rpc.ErrInvalidContinueToken
+		}
+		rev = trev
+		if start := append(append([]byte{}, lastKey...), 0); bytes.Compare(start, key) > 0 {
+			// like the server, never resume outside of the requested range.
+			if !bytes.Equal(end, []byte{0}) && bytes.Compare(start, end) >= 0 {
+				return nil, v3rpc.ErrInvalidContinueToken
+			}
+			key = start
+		}
+	}
+	switch {
+	case rev > f.rev:
+		return nil, v3rpc.ErrFutureRev
+	case rev > 0 && rev < f.compactRev:
+		return nil, v3rpc.ErrCompacted
+	}
+	match := func([]byte) bool { return true }
+	if filter := op.KeyFilter(); len(filter) > 0 {
+		match = func(k []byte) bool { return bytes.Contains(k, filter) }
+		if op.IsKeyFilterRegex() {
+			re, err := regexp.Compile(string(filter))
+			if err != nil {
+				return nil, v3rpc.ErrInvalidKeyFilter
+			}
+			match = re.Match
+		}
+	}
+	kvs := f.rangeKeys(key, op.RangeBytes(), rev)
+	resp := &clientv3.GetResponse{Header: w.hdr, Count: int64(len(kvs))}
+	if op.IsSizeOnly() {
+		for _, kv := range kvs {
+			resp.TotalSize += int64(kv.Size())
+		}
+		return resp, nil
+	}
+
+	var filtered []*mvccpb.KeyValue
+	for _, kv := range kvs {
+		if !match(kv.Key) ||
+			(op.LeaseFilter() != 0 && kv.Lease != int64(op.LeaseFilter())) ||
+			(op.MinModRev() > 0 && kv.ModRevision < op.MinModRev()) ||
+			(op.MaxModRev() > 0 && kv.ModRevision > op.MaxModRev()) ||
+			(op.MinCreateRev() > 0 && kv.CreateRevision < op.MinCreateRev()) ||
+			(op.MaxCreateRev() > 0 && kv.CreateRevision > op.MaxCreateRev()) {
+			continue
+		}
+		filtered = append(filtered, kv)
+	}
+	sortKeyValues(filtered, op.Sort())
+	if limit := op.Limit(); limit > 0 && int64(len(filtered)) > limit {
+		filtered, resp.More = filtered[:limit], true
+	}
+	if op.IsCountOnly() {
+		return resp, nil
+	}
+	if resp.More && w.paged && keyAscending(op.Sort()) {
+		if rev == 0 {
+			rev = f.rev
+		}
+		resp.NextToken = encodeContinueToken(rev, filtered[len(filtered)-1].Key)
+	}
+	for _, kv := range filtered {
+		if op.IsKeysOnly() {
+			kv = &mvccpb.KeyValue{Key: kv.Key, CreateRevision: kv.CreateRevision, ModRevision: kv.ModRevision, Version: kv.Version, Lease: kv.Lease}
+		}
+		resp.Kvs = append(resp.Kvs, kv)
+	}
+	return resp, nil
+}
+
+// encodeContinueToken returns a token to resume a range after lastKey at rev.
+func encodeContinueToken(rev int64, lastKey []byte) []byte {
+	return append(strconv.AppendInt(nil, rev, 10), append([]byte{':'}, lastKey...)...)
+}
+
+func decodeContinueToken(token []byte) (rev int64, lastKey []byte, ok bool) {
+	i := bytes.IndexByte(token, ':')
+	if i < 0 {
+		return 0, nil, false
+	}
+	rev, err := strconv.ParseInt(string(token[:i]), 10, 64)
+	if err != nil || rev <= 0 {
+		return 0, nil, false
+	}
+	return rev, token[i+1:], true
+}
+
+// keyAscending tells whether a range returns its keys in ascending key
+// order, which a continue token relies on.
+func keyAscending(so *clientv3.SortOption) bool {
+	return so == nil || so.Target == clientv3.SortByKey && so.Order != clientv3.SortDescend
+}
+
+func sortKeyValues(kvs []*mvccpb.KeyValue, so *clientv3.SortOption) {
+	if so == nil {
+		return
+	}
+	order := so.Order
+	if order == clientv3.SortNone {
+		if so.Target == clientv3.SortByKey {
+			return
+		}
+		order = clientv3.SortAscend
+	}
+	cmp := func(a, b *mvccpb.KeyValue) int {
+		switch so.Target {
+		case clientv3.SortByVersion:
+			return compareInt64(a.Version, b.Version)
+		case clientv3.SortByCreateRevision:
+			return compareInt64(a.CreateRevision, b.CreateRevision)
+		case clientv3.SortByModRevision:
+			return compareInt64(a.ModRevision, b.ModRevision)
+		case clientv3.SortByValue:
+			return bytes.Compare(a.Value, b.Value)
+		case clientv3.SortByLease:
+			return compareInt64(a.Lease, b.Lease)
+		}
+		return bytes.Compare(a.Key, b.Key)
+	}
+	sort.SliceStable(kvs, func(i, j int) bool {
+		if order == clientv3.SortDescend {
+			return cmp(kvs[i], kvs[j]) > 0
+		}
+		return cmp(kvs[i], kvs[j]) < 0
+	})
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func (w *writeTxn) put(op clientv3.Op) (*clientv3.PutResponse, error) {
+	f := w.f
+	key := string(op.KeyBytes())
+	if key == "" {
+		return nil, v3rpc.ErrEmptyKey
+	}
+	prev := f.get(key)
+	val, leaseID := op.ValueBytes(), op.LeaseID()
+	if op.IsIgnoreValue() || op.IsIgnoreLease() {
+		if prev == nil {
+			return nil, v3rpc.ErrKeyNotFound
+		}
+		if op.IsIgnoreValue() {
+			val = prev.Value
+		}
+		if op.IsIgnoreLease() {
+			leaseID = clientv3.LeaseID(prev.Lease)
+		}
+	}
+	if op.TTL() < 0 || (op.TTL() != 0 && (op.LeaseID() != clientv3.NoLease || op.IsIgnoreLease())) {
+		return nil, v3rpc.ErrInvalidTTL
+	}
+	if leaseID != clientv3.NoLease && f.leases[leaseID] == nil {
+		return nil, v3rpc.ErrLeaseNotFound
+	}
+
+	kv := &mvccpb.KeyValue{
+		Key:            []byte(key),
+		Value:          val,
+		CreateRevision: w.rev,
+		ModRevision:    w.rev,
+		Version:        1,
+		Lease:          int64(leaseID),
+		Ttl:            op.TTL(),
+	}
+	if prev != nil {
+		kv.CreateRevision, kv.Version = prev.CreateRevision, prev.Version+1
+	}
+	w.apply(&clientv3.Event{Type: mvccpb.PUT, Kv: kv, PrevKv: prev})
+
+	resp := &clientv3.PutResponse{Header: w.hdr}
+	if op.IsPrevKV() {
+		resp.PrevKv = prev
+	}
+	return resp, nil
+}
+
+// expire deletes key once ttl seconds elapsed, unless it was modified
+// meanwhile or the fake is closed.
+func (f *Fake) expire(key string, modRev, ttl int64) {
+	time.AfterFunc(time.Duration(ttl)*time.Second, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if kv := f.get(key); f.closed || kv == nil || kv.ModRevision != modRev {
+			return
+		}
+		w := f.begin()
+		w.delete(clientv3.OpDelete(key))
+		w.commit()
+	})
+}
+
+func (w *writeTxn) delete(op clientv3.Op) *clientv3.DeleteResponse {
+	kvs := w.f.rangeKeys(op.KeyBytes(), op.RangeBytes(), 0)
+	for _, kv := range kvs {
+		w.apply(&clientv3.Event{Type: mvccpb.DELETE, Kv: &mvccpb.KeyValue{Key: kv.Key, ModRevision: w.rev}, PrevKv: kv})
+	}
+	resp := &clientv3.DeleteResponse{Header: w.hdr, Deleted: int64(len(kvs))}
+	if op.IsPrevKV() {
+		resp.PrevKvs = kvs
+	}
+	return resp
+}
+
+func (w *writeTxn) txn(op clientv3.Op) (*clientv3.TxnResponse, error) {
+	cmps, thenOps, elseOps := op.Txn()
+	resp := &clientv3.TxnResponse{Header: w.hdr, Succeeded: true}
+	for _, cmp := range cmps {
+		if !w.f.compare(cmp) {
+			resp.Succeeded = false
+			break
+		}
+	}
+	ops := thenOps
+	if !resp.Succeeded {
+		ops = elseOps
+	}
+	if err := checkDuplicateKeys(ops); err != nil {
+		return nil, err
+	}
+	for _, op := range ops {
+		r, err := w.do(op)
+		if err != nil {
+			return nil, err
+		}
+		resp.Responses = append(resp.Responses, responseOp(r))
+	}
+	return resp, nil
+}
+
+// checkDuplicateKeys fails if ops write a key more than once, as the server
+// does.
+func checkDuplicateKeys(ops []clientv3.Op) error {
+	puts := make(map[string]struct{})
+	for _, op := range ops {
+		if !op.IsPut() {
+			continue
+		}
+		key := string(op.KeyBytes())
+		if _, ok := puts[key]; ok {
+			return v3rpc.ErrDuplicateKey
+		}
+		puts[key] = struct{}{}
+	}
+	for _, op := range ops {
+		if !op.IsDelete() {
+			continue
+		}
+		for key := range puts {
+			if inRange([]byte(key), op.KeyBytes(), op.RangeBytes()) {
+				return v3rpc.ErrDuplicateKey
+			}
+		}
+	}
+	return nil
+}
+
+func responseOp(r clientv3.OpResponse) *pb.ResponseOp {
+	switch {
+	case r.Get() != nil:
+		return &pb.ResponseOp{Response: &pb.ResponseOp_ResponseRange{ResponseRange: (*pb.RangeResponse)(r.Get())}}
+	case r.Put() != nil:
+		return &pb.ResponseOp{Response: &pb.ResponseOp_ResponsePut{ResponsePut: (*pb.PutResponse)(r.Put())}}
+	case r.Del() != nil:
+		return &pb.ResponseOp{Response: &pb.ResponseOp_ResponseDeleteRange{ResponseDeleteRange: (*pb.DeleteRangeResponse)(r.Del())}}
+	}
+	return &pb.ResponseOp{Response: &pb.ResponseOp_ResponseTxn{ResponseTxn: (*pb.TxnResponse)(r.Txn())}}
+}
+
+// apply records a change of a key, and how to undo it.
+func (w *writeTxn) apply(ev *clientv3.Event) {
+	f := w.f
+	key := string(ev.Kv.Key)
+	n := len(f.keys[key])
+	f.keys[key] = append(f.keys[key], version{ev: ev, sub: len(w.events)})
+	w.events = append(w.events, ev)
+	if ev.PrevKv != nil {
+		f.detach(key, clientv3.LeaseID(ev.PrevKv.Lease))
+	}
+	if ev.Type == mvccpb.PUT {
+		f.attach(key, clientv3.LeaseID(ev.Kv.Lease))
+	}
+	w.undo = append(w.undo, func() {
+		if n == 0 {
+			delete(f.keys, key)
+		} else {
+			f.keys[key] = f.keys[key][:n]
+		}
+		w.events = w.events[:len(w.events)-1]
+		if ev.Type == mvccpb.PUT {
+			f.detach(key, clientv3.LeaseID(ev.Kv.Lease))
+		}
+		if ev.PrevKv != nil {
+			f.attach(key, clientv3.LeaseID(ev.PrevKv.Lease))
+		}
+	})
+}
+
+// compare evaluates cmp at the latest revision. A comparison on a range
+// holds if it holds for all the keys of the range.
+func (f *Fake) compare(cmp clientv3.Cmp) bool {
+	kvs := f.rangeKeys(cmp.Key, cmp.RangeEnd, 0)
+	if u, ok := cmp.TargetUnion.(*pb.Compare_Count); ok {
+		return compareResult(cmp.Result, compareInt64(int64(len(kvs)), u.Count))
+	}
+	if len(kvs) == 0 {
+		if cmp.Target == pb.Compare_VALUE || cmp.Target == pb.Compare_VALUE_PREFIX {
+			return false
+		}
+		return compareKV(cmp, &mvccpb.KeyValue{})
+	}
+	for _, kv := range kvs {
+		if !compareKV(cmp, kv) {
+			return false
+		}
+	}
+	return true
+}
+
+func compareKV(cmp clientv3.Cmp, kv *mvccpb.KeyValue) bool {
+	var r int
+	switch u := cmp.TargetUnion.(type) {
+	case *pb.Compare_Value:
+		r = bytes.Compare(kv.Value, u.Value)
+	case *pb.Compare_Version:
+		r = compareInt64(kv.Version, u.Version)
+	case *pb.Compare_CreateRevision:
+		r = compareInt64(kv.CreateRevision, u.CreateRevision)
+	case *pb.Compare_ModRevision:
+		r = compareInt64(kv.ModRevision, u.ModRevision)
+	case *pb.Compare_Lease:
+		r = compareInt64(kv.Lease, u.Lease)
+	case *pb.Compare_ValuePrefix:
+		r = bytes.Compare(kv.Value[:min(len(kv.Value), len(u.ValuePrefix))], u.ValuePrefix)
+	}
+	return compareResult(cmp.Result, r)
+}
+
+func compareResult(result pb.Compare_CompareResult, r int) bool {
+	switch result {
+	case pb.Compare_EQUAL:
+		return r == 0
+	case pb.Compare_NOT_EQUAL:
+		return r != 0
+	case pb.Compare_GREATER:
+		return r > 0
+	case pb.Compare_LESS:
+		return r < 0
+	}
+	return false
+}
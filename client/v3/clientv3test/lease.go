@@ -0,0 +1,275 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3test
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	v3rpc "go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// maxLeaseMetadataSize is the maximum size of the metadata of a lease, like
+// on the server.
+const maxLeaseMetadataSize = 256
+
+type lease struct {
+	id       clientv3.LeaseID
+	ttl      int64
+	metadata []byte
+	expiry   time.Time
+	timer    *time.Timer
+	keys     map[string]struct{}
+}
+
+func (l *lease) remaining() int64 {
+	return int64(math.Ceil(time.Until(l.expiry).Seconds()))
+}
+
+// Grant creates a lease expiring after ttl seconds unless kept alive.
+func (f *Fake) Grant(ctx context.Context, ttl int64, opts ...clientv3.LeaseOption) (*clientv3.LeaseGrantResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	metadata := clientv3.NewLeaseOp(opts...).Metadata()
+	if len(metadata) > maxLeaseMetadataSize {
+		return nil, v3rpc.ErrLeaseMetadataTooLarge
+	}
+	// like the server, grant a minimum time to live.
+	if ttl < 1 {
+		ttl = 1
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lastLease++
+	l := &lease{id: f.lastLease, ttl: ttl, metadata: metadata, keys: make(map[string]struct{})}
+	f.leases[l.id] = l
+	f.refresh(l)
+	return &clientv3.LeaseGrantResponse{ResponseHeader: f.header(), ID: l.id, TTL: ttl}, nil
+}
+
+// refresh restarts the time to live of the lease.
+func (f *Fake) refresh(l *lease) {
+	ttl := time.Duration(l.ttl) * time.Second
+	l.expiry = time.Now().Add(ttl)
+	if l.timer != nil {
+		l.timer.Stop()
+	}
+	if f.closed {
+		return
+	}
+	l.timer = time.AfterFunc(ttl, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		// the lease may have been refreshed meanwhile.
+		if f.leases[l.id] == l && !time.Now().Before(l.expiry) {
+			f.revoke(l)
+		}
+	})
+}
+
+func (f *Fake) Revoke(ctx context.Context, id clientv3.LeaseID) (*clientv3.LeaseRevokeResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	l, ok := f.leases[id]
+	if !ok {
+		return nil, v3rpc.ErrLeaseNotFound
+	}
+	f.revoke(l)
+	return &clientv3.LeaseRevokeResponse{Header: f.header()}, nil
+}
+
+// revoke deletes the lease and its keys, in a single revision.
+func (f *Fake) revoke(l *lease) {
+	if l.timer != nil {
+		l.timer.Stop()
+	}
+	keys := make([]string, 0, len(l.keys))
+	for key := range l.keys {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	w := f.begin()
+	for _, key := range keys {
+		w.delete(clientv3.OpDelete(key))
+	}
+	w.commit()
+	delete(f.leases, l.id)
+}
+
+func (f *Fake) TimeToLive(ctx context.Context, id clientv3.LeaseID, opts ...clientv3.LeaseOption) (*clientv3.LeaseTimeToLiveResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	l, ok := f.leases[id]
+	if !ok {
+		return &clientv3.LeaseTimeToLiveResponse{ResponseHeader: f.header(), ID: id, TTL: -1}, nil
+	}
+	resp := &clientv3.LeaseTimeToLiveResponse{ResponseHeader: f.header(), ID: id, TTL: l.remaining(), GrantedTTL: l.ttl, Metadata: l.metadata}
+	for key := range l.keys {
+		resp.Keys = append(resp.Keys, []byte(key))
+	}
+	sort.Slice(resp.Keys, func(i, j int) bool { return string(resp.Keys[i]) < string(resp.Keys[j]) })
+	return resp, nil
+}
+
+func (f *Fake) Leases(ctx context.Context, opts ...clientv3.LeaseOption) (*clientv3.LeaseLeasesResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	op := clientv3.NewLeaseOp(opts...)
+	minTTL, maxTTL := op.GrantedTTLRange()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	resp := &clientv3.LeaseLeasesResponse{ResponseHeader: f.header()}
+	for id, l := range f.leases {
+		if !bytes.HasPrefix(l.metadata, op.MetadataPrefix()) || minTTL > 0 && l.ttl < minTTL || maxTTL > 0 && l.ttl > maxTTL {
+			continue
+		}
+		resp.Leases = append(resp.Leases, clientv3.LeaseStatus{ID: id, GrantedTTL: l.ttl, Metadata: l.metadata})
+	}
+	sort.Slice(resp.Leases, func(i, j int) bool { return resp.Leases[i].ID < resp.Leases[j].ID })
+	return resp, nil
+}
+
+// KeepAlive keeps the lease alive until ctx is done, the lease is revoked or
+// the fake is closed, sending a response on every renewal unless the
+// channel is full.
+func (f *Fake) KeepAlive(ctx context.Context, id clientv3.LeaseID) (<-chan *clientv3.LeaseKeepAliveResponse, error) {
+	ch := make(chan *clientv3.LeaseKeepAliveResponse, 16)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		close(ch)
+		return ch, clientv3.ErrKeepAliveHalted{Reason: context.Canceled}
+	}
+	f.wg.Add(1)
+	go f.keepAlive(ctx, id, ch)
+	return ch, nil
+}
+
+func (f *Fake) keepAlive(ctx context.Context, id clientv3.LeaseID, ch chan<- *clientv3.LeaseKeepAliveResponse) {
+	defer f.wg.Done()
+	defer close(ch)
+	for {
+		resp, err := f.KeepAliveOnce(ctx, id)
+		if err != nil {
+			return
+		}
+		select {
+		case ch <- resp:
+		default:
+		}
+
+		t := time.NewTimer(time.Duration(resp.TTL) * time.Second / 3)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-f.stopc:
+			t.Stop()
+			return
+		}
+	}
+}
+
+func (f *Fake) KeepAliveOnce(ctx context.Context, id clientv3.LeaseID) (*clientv3.LeaseKeepAliveResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	l, ok := f.leases[id]
+	if !ok {
+		return nil, v3rpc.ErrLeaseNotFound
+	}
+	f.refresh(l)
+	return &clientv3.LeaseKeepAliveResponse{ResponseHeader: f.header(), ID: id, TTL: l.ttl}, nil
+}
+
+// GrantBatch grants a lease per ttl, like Grant.
+func (f *Fake) GrantBatch(ctx context.Context, ttls []int64) (*clientv3.LeaseGrantBatchResponse, error) {
+	resp := &clientv3.LeaseGrantBatchResponse{}
+	for _, ttl := range ttls {
+		gr, err := f.Grant(ctx, ttl)
+		if err != nil {
+			return nil, err
+		}
+		resp.ResponseHeader = gr.ResponseHeader
+		resp.Leases = append(resp.Leases, *gr)
+	}
+	return resp, nil
+}
+
+func (f *Fake) RevokeBatch(ctx context.Context, ids []clientv3.LeaseID) (*clientv3.LeaseRevokeBatchResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	resp := &clientv3.LeaseRevokeBatchResponse{}
+	for _, id := range ids {
+		l, ok := f.leases[id]
+		if !ok {
+			resp.NotFound = append(resp.NotFound, id)
+			continue
+		}
+		f.revoke(l)
+	}
+	resp.ResponseHeader = f.header()
+	return resp, nil
+}
+
+// KeepAliveBatch renews the leases once, reporting a TTL of 0 for the leases
+// that do not exist.
+func (f *Fake) KeepAliveBatch(ctx context.Context, ids []clientv3.LeaseID) (*clientv3.LeaseKeepAliveBatchResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	resp := &clientv3.LeaseKeepAliveBatchResponse{ResponseHeader: f.header()}
+	for _, id := range ids {
+		kr := clientv3.LeaseKeepAliveResponse{ResponseHeader: resp.ResponseHeader, ID: id}
+		if l, ok := f.leases[id]; ok {
+			f.refresh(l)
+			kr.TTL = l.ttl
+		}
+		resp.Leases = append(resp.Leases, kr)
+	}
+	return resp, nil
+}
+
+func (f *Fake) attach(key string, id clientv3.LeaseID) {
+	if l, ok := f.leases[id]; ok {
+		l.keys[key] = struct{}{}
+	}
+}
+
+func (f *Fake) detach(key string, id clientv3.LeaseID) {
+	if l, ok := f.leases[id]; ok {
+		delete(l.keys, key)
+	}
+}
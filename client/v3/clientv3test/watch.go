@@ -0,0 +1,238 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3test
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// watcher queues the responses of a watch, so that writes never wait for
+// the watch to be read.
+type watcher struct {
+	ctx context.Context
+	op  clientv3.Op
+	// start is the first revision of the watch.
+	start int64
+
+	mu    sync.Mutex
+	queue []clientv3.WatchResponse
+	// canceled closes the watch once the queue is drained.
+	canceled bool
+	notifyc  chan struct{}
+
+	out chan clientv3.WatchResponse
+}
+
+// Watch watches key like the clientv3 Watcher. Progress notifications are
+// only sent on RequestProgress and as bookmarks, and events are never
+// fragmented nor batched.
+func (f *Fake) Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan {
+	w := &watcher{
+		ctx:     ctx,
+		op:      clientv3.OpGet(key, opts...),
+		notifyc: make(chan struct{}, 1),
+		out:     make(chan clientv3.WatchResponse),
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed || ctx.Err() != nil {
+		close(w.out)
+		return w.out
+	}
+
+	w.start = w.op.Rev()
+	var snapshot *clientv3.WatchResponse
+	switch {
+	case w.start == 0:
+		w.start = f.rev + 1
+	case w.start < f.compactRev && w.op.IsSnapshotOnCompaction():
+		// the snapshot replaces the compacted events
+		snapshot = &clientv3.WatchResponse{
+			Header:   *f.header(),
+			Events:   w.snapshot(f.rangeKeys(w.op.KeyBytes(), w.op.RangeBytes(), 0)),
+			Snapshot: true,
+		}
+		w.start = f.rev + 1
+	case w.start < f.compactRev:
+		w.enqueue(clientv3.WatchResponse{Header: *f.header(), CompactRevision: f.compactRev, Canceled: true})
+		w.canceled = true
+	}
+	if !w.canceled {
+		if w.op.IsCreatedNotify() {
+			w.enqueue(clientv3.WatchResponse{Header: *f.header(), Created: true})
+		}
+		if snapshot != nil {
+			w.enqueue(*snapshot)
+		}
+		if w.start <= f.rev {
+			if events := w.filter(f.history(w.op.KeyBytes(), w.op.RangeBytes(), w.start)); len(events) > 0 {
+				w.enqueue(clientv3.WatchResponse{Header: *f.header(), Events: events})
+			}
+		}
+		f.watchers[w] = struct{}{}
+	}
+
+	f.wg.Add(1)
+	go f.runWatcher(w)
+	return w.out
+}
+
+func (f *Fake) RequestProgress(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for w := range f.watchers {
+		w.enqueue(clientv3.WatchResponse{Header: *f.header()})
+	}
+	return nil
+}
+
+// notify queues the events of a revision to the watches.
+func (f *Fake) notify(events []*clientv3.Event) {
+	for w := range f.watchers {
+		if wevents := w.filter(events); len(wevents) > 0 {
+			w.enqueue(clientv3.WatchResponse{Header: pb.ResponseHeader{Revision: f.rev}, Events: wevents})
+		}
+	}
+}
+
+// bookmark queues a bookmark to the watch, at the current revision since
+// the events of all the revisions are queued on commit.
+func (f *Fake) bookmark(w *watcher) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.watchers[w]; ok {
+		w.enqueue(clientv3.WatchResponse{Header: *f.header(), Bookmark: true})
+	}
+}
+
+func (f *Fake) runWatcher(w *watcher) {
+	defer f.wg.Done()
+	defer close(w.out)
+	defer func() {
+		f.mu.Lock()
+		delete(f.watchers, w)
+		f.mu.Unlock()
+	}()
+	var bookmarkc <-chan time.Time
+	if interval := w.op.BookmarkInterval(); interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		bookmarkc = ticker.C
+	}
+	for {
+		select {
+		case <-bookmarkc:
+			f.bookmark(w)
+		default:
+		}
+		w.mu.Lock()
+		if len(w.queue) == 0 {
+			canceled := w.canceled
+			w.mu.Unlock()
+			if canceled {
+				return
+			}
+			select {
+			case <-w.notifyc:
+				continue
+			case <-bookmarkc:
+				f.bookmark(w)
+				continue
+			case <-w.ctx.Done():
+				return
+			case <-f.stopc:
+				return
+			}
+		}
+		wr := w.queue[0]
+		w.queue = w.queue[1:]
+		w.mu.Unlock()
+
+		select {
+		case w.out <- wr:
+		case <-w.ctx.Done():
+			return
+		case <-f.stopc:
+			return
+		}
+	}
+}
+
+func (w *watcher) enqueue(wr clientv3.WatchResponse) {
+	w.mu.Lock()
+	w.queue = append(w.queue, wr)
+	w.mu.Unlock()
+	select {
+	case w.notifyc <- struct{}{}:
+	default:
+	}
+}
+
+// matchValue reports whether the value of a put event matches the value
+// filters of the watch.
+func (w *watcher) matchValue(v []byte) bool {
+	size := int64(len(v))
+	switch {
+	case w.op.MinValueSize() > 0 && size < w.op.MinValueSize():
+		return false
+	case w.op.MaxValueSize() > 0 && size > w.op.MaxValueSize():
+		return false
+	}
+	return bytes.HasPrefix(v, w.op.ValuePrefix()) && bytes.Contains(v, w.op.ValueContains())
+}
+
+// filter returns the events matching the watch, as the watch reports them.
+// snapshot returns the put events of the key-values of a snapshot, filtered
+// like the events of the watch.
+func (w *watcher) snapshot(kvs []*mvccpb.KeyValue) []*clientv3.Event {
+	var events []*clientv3.Event
+	for _, kv := range kvs {
+		if w.op.IsFilterPut() || !w.matchValue(kv.Value) {
+			continue
+		}
+		events = append(events, &clientv3.Event{Type: mvccpb.PUT, Kv: kv})
+	}
+	return events
+}
+
+func (w *watcher) filter(events []*clientv3.Event) []*clientv3.Event {
+	var filtered []*clientv3.Event
+	for _, ev := range events {
+		if ev.Kv.ModRevision < w.start || !inRange(ev.Kv.Key, w.op.KeyBytes(), w.op.RangeBytes()) {
+			continue
+		}
+		if (ev.Type == mvccpb.PUT && w.op.IsFilterPut()) || (ev.Type == mvccpb.DELETE && w.op.IsFilterDelete()) {
+			continue
+		}
+		if ev.Type == mvccpb.PUT && !w.matchValue(ev.Kv.Value) {
+			continue
+		}
+		if !w.op.IsPrevKV() && ev.PrevKv != nil {
+			ev = &clientv3.Event{Type: ev.Type, Kv: ev.Kv}
+		}
+		filtered = append(filtered, ev)
+	}
+	return filtered
+}
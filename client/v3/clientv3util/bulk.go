@@ -0,0 +1,102 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// DefaultBulkPutChunkSize is the number of puts per transaction, matching the
+// default "--max-txn-ops" of the etcd server.
+const DefaultBulkPutChunkSize = 128
+
+var (
+	// ErrBulkPutTooLarge is returned by an atomic BulkPut whose puts do not
+	// fit in a single transaction.
+	ErrBulkPutTooLarge = errors.New("clientv3util: puts do not fit in a single transaction")
+	errBulkPutNotPut   = errors.New("clientv3util: bulk put only accepts put operations")
+)
+
+// BulkPutOption configures a BulkPut.
+type BulkPutOption func(*bulkPutConfig)
+
+type bulkPutConfig struct {
+	chunkSize int
+	atomic    bool
+	progress  func(done, total int)
+}
+
+// WithChunkSize sets the maximum number of puts per transaction. It must not
+// exceed the "--max-txn-ops" of the etcd server. Sizes of zero or less are ignored.
+func WithChunkSize(n int) BulkPutOption {
+	return func(cfg *bulkPutConfig) {
+		if n > 0 {
+			cfg.chunkSize = n
+		}
+	}
+}
+
+// WithAtomic makes the BulkPut all-or-nothing: the puts are applied in a
+// single transaction, and ErrBulkPutTooLarge is returned without writing
+// anything if they do not fit in one.
+func WithAtomic() BulkPutOption {
+	return func(cfg *bulkPutConfig) { cfg.atomic = true }
+}
+
+// WithProgress sets a function called after every committed transaction
+// with the number of puts applied so far and the total number of puts.
+func WithProgress(f func(done, total int)) BulkPutOption {
+	return func(cfg *bulkPutConfig) { cfg.progress = f }
+}
+
+// BulkPut applies the given put operations in order, split into as many
+// transactions as the chunk size requires. It returns the number of puts
+// that were applied.
+//
+// Unless WithAtomic is given, the transactions are committed one after the
+// other, so a failure leaves the puts of the earlier transactions applied.
+// The returned count tells how far the BulkPut got, and the puts from
+// that index on can be retried.
+func BulkPut(ctx context.Context, kv clientv3.KV, puts []clientv3.Op, opts ...BulkPutOption) (int, error) {
+	cfg := bulkPutConfig{chunkSize: DefaultBulkPutChunkSize}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	for _, op := range puts {
+		if !op.IsPut() {
+			return 0, errBulkPutNotPut
+		}
+	}
+	if cfg.atomic && len(puts) > cfg.chunkSize {
+		return 0, fmt.Errorf("%w: %d puts exceed the chunk size of %d", ErrBulkPutTooLarge, len(puts), cfg.chunkSize)
+	}
+
+	done := 0
+	for done < len(puts) {
+		end := min(done+cfg.chunkSize, len(puts))
+		if _, err := kv.Txn(ctx).Then(puts[done:end]...).Commit(); err != nil {
+			return done, fmt.Errorf("clientv3util: bulk put failed after %d of %d puts: %w", done, len(puts), err)
+		}
+		done = end
+		if cfg.progress != nil {
+			cfg.progress(done, len(puts))
+		}
+	}
+	return done, nil
+}
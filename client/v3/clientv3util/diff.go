@@ -0,0 +1,119 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3util
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// diffPageSize is the number of keys fetched per historical range request.
+const diffPageSize = 1000
+
+// RevisionDiff is the set of keys that changed between two revisions.
+// Every slice is sorted by key.
+type RevisionDiff struct {
+	// Added holds the keys that exist only at the later revision, as of
+	// that revision.
+	Added []*mvccpb.KeyValue
+	// Modified holds the keys that exist at both revisions but were written
+	// in between, as of the later revision.
+	Modified []*mvccpb.KeyValue
+	// Deleted holds the keys that exist only at the earlier revision, as of
+	// that revision.
+	Deleted []*mvccpb.KeyValue
+}
+
+// DiffRevisions returns the keys under prefix that were added, modified, and
+// deleted between revisions revA and revB. A revision of 0 stands for the
+// current revision.
+//
+// Both revisions are read through historical ranges, so they must not have
+// been compacted; otherwise an error wrapping rpctypes.ErrCompacted is returned.
+// A key that was deleted and created again in between is reported as modified.
+func DiffRevisions(ctx context.Context, kv clientv3.KV, prefix string, revA, revB int64) (*RevisionDiff, error) {
+	a, err := rangeAtRev(ctx, kv, prefix, revA)
+	if err != nil {
+		return nil, err
+	}
+	b, err := rangeAtRev(ctx, kv, prefix, revB)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &RevisionDiff{}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		var c int
+		switch {
+		case i == len(a):
+			c = 1
+		case j == len(b):
+			c = -1
+		default:
+			c = bytes.Compare(a[i].Key, b[j].Key)
+		}
+		switch {
+		case c < 0:
+			diff.Deleted = append(diff.Deleted, a[i])
+			i++
+		case c > 0:
+			diff.Added = append(diff.Added, b[j])
+			j++
+		default:
+			if a[i].ModRevision != b[j].ModRevision {
+				diff.Modified = append(diff.Modified, b[j])
+			}
+			i++
+			j++
+		}
+	}
+	return diff, nil
+}
+
+// rangeAtRev returns all keys under prefix as of revision rev, sorted by key.
+func rangeAtRev(ctx context.Context, kv clientv3.KV, prefix string, rev int64) ([]*mvccpb.KeyValue, error) {
+	var kvs []*mvccpb.KeyValue
+	key, end := prefix, clientv3.GetPrefixRangeEnd(prefix)
+	if prefix == "" {
+		// an empty prefix covers the whole keyspace.
+		key, end = "\x00", "\x00"
+	}
+	for {
+		resp, err := kv.Get(ctx, key, clientv3.WithRange(end), clientv3.WithRev(rev), clientv3.WithLimit(diffPageSize))
+		if err != nil {
+			if errors.Is(err, rpctypes.ErrCompacted) {
+				return nil, fmt.Errorf("clientv3util: revision %d of prefix '%s' is compacted: %w", rev, clientv3.EscapeKey(prefix), err)
+			}
+			return nil, err
+		}
+		kvs = append(kvs, resp.Kvs...)
+		if !resp.More || len(resp.Kvs) == 0 {
+			return kvs, nil
+		}
+		if rev == 0 {
+			// pin the current revision so that every page is read at the
+			// same revision.
+			rev = resp.Header.Revision
+		}
+		key = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// ErrNoLeaderEndpoint is returned by GetFromLeader when none of the client
+// endpoints is served by the current leader.
+var ErrNoLeaderEndpoint = errors.New("clientv3util: no client endpoint is the leader")
+
+// GetFromLeader performs a linearizable Get on the current leader, whatever
+// endpoint the client balancer would pick. The leader is resolved by asking
+// the client endpoints for their status, so it must be among them.
+//
+// A new connection to the leader endpoint is dialed for every call, which
+// makes GetFromLeader suited for occasional reads only. If leadership moves
+// after the leader was resolved, the read is served by the former leader,
+// which is then a follower; a linearizable read stays linearizable, but it is
+// no longer served by the leader.
+func GetFromLeader(ctx context.Context, c *clientv3.Client, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	ep, err := LeaderEndpoint(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := c.Dial(ep)
+	if err != nil {
+		return nil, fmt.Errorf("clientv3util: failed to dial leader endpoint %s: %w", ep, err)
+	}
+	defer conn.Close()
+	return clientv3.NewKVFromKVClient(pb.NewKVClient(conn), c).Get(ctx, key, opts...)
+}
+
+// LeaderEndpoint returns the client endpoint served by the current leader.
+func LeaderEndpoint(ctx context.Context, c *clientv3.Client) (string, error) {
+	var errs []error
+	for _, ep := range c.Endpoints() {
+		resp, err := c.Status(ctx, ep)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if resp.Leader != 0 && resp.Header.MemberId == resp.Leader {
+			return ep, nil
+		}
+	}
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	return "", errors.Join(append([]error{ErrNoLeaderEndpoint}, errs...)...)
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3util
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	getOrWaitMinBackoff = 50 * time.Millisecond
+	getOrWaitMaxBackoff = 2 * time.Second
+)
+
+var errWatchClosed = errors.New("clientv3util: watch channel closed")
+
+// GetOrWait returns the given key if it exists. Otherwise it blocks until the
+// key is created or the context is done.
+//
+// The key is watched starting right after the revision of the initial Get, so
+// a key created between the Get and the establishment of the watch is not
+// missed. Failed requests, for instance while the cluster is temporarily
+// unavailable, or when a compaction runs past the revision of the Get before the
+// watch starts, are retried with exponential backoff. Errors retrying cannot
+// resolve, such as a denied permission, are returned.
+func GetOrWait(ctx context.Context, c *clientv3.Client, key string) (*mvccpb.KeyValue, error) {
+	backoff := getOrWaitMinBackoff
+	for {
+		kv, err := getOrWatch(ctx, c, key)
+		if err == nil {
+			return kv, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if isPermanentErr(err) {
+			return nil, err
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		backoff *= 2
+		if backoff > getOrWaitMaxBackoff {
+			backoff = getOrWaitMaxBackoff
+		}
+	}
+}
+
+// isPermanentErr returns true if the given error fails every retry of the
+// same request.
+func isPermanentErr(err error) bool {
+	return errors.Is(err, rpctypes.ErrPermissionDenied) ||
+		errors.Is(err, rpctypes.ErrUserEmpty) ||
+		errors.Is(err, rpctypes.ErrFutureRev)
+}
+
+func getOrWatch(ctx context.Context, c *clientv3.Client, key string) (*mvccpb.KeyValue, error) {
+	resp, err := c.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Kvs) > 0 {
+		return resp.Kvs[0], nil
+	}
+
+	wctx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+	defer cancel()
+	wch := c.Watch(wctx, key, clientv3.WithRev(resp.Header.Revision+1), clientv3.WithFilterDelete())
+	for wresp := range wch {
+		if err := wresp.Err(); err != nil {
+			return nil, err
+		}
+		for _, ev := range wresp.Events {
+			if ev.Type == clientv3.EventTypePut {
+				return ev.Kv, nil
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return nil, errWatchClosed
+}
@@ -30,6 +30,7 @@ type (
 	MemberRemoveResponse  pb.MemberRemoveResponse
 	MemberUpdateResponse  pb.MemberUpdateResponse
 	MemberPromoteResponse pb.MemberPromoteResponse
+	MemberReplaceResponse pb.MemberReplaceResponse
 )
 
 type Cluster interface {
@@ -42,6 +43,11 @@ type Cluster interface {
 	// MemberAddAsLearner adds a new learner member into the cluster.
 	MemberAddAsLearner(ctx context.Context, peerAddrs []string) (*MemberAddResponse, error)
 
+	// MemberAddAsStandby adds a new standby member into the cluster. A standby
+	// member is a learner that serves serializable reads and watches, and is
+	// never promoted.
+	MemberAddAsStandby(ctx context.Context, peerAddrs []string) (*MemberAddResponse, error)
+
 	// MemberRemove removes an existing member from the cluster.
 	MemberRemove(ctx context.Context, id uint64) (*MemberRemoveResponse, error)
 
@@ -50,6 +56,12 @@ type Cluster interface {
 
 	// MemberPromote promotes a member from raft learner (non-voting) to raft voting member.
 	MemberPromote(ctx context.Context, id uint64) (*MemberPromoteResponse, error)
+
+	// MemberReplace replaces an existing member by a new member with the given
+	// peer addresses. The new member is added as a learner, and must be started
+	// and catch up with the leader before ctx is done; it is then promoted and
+	// the replaced member is removed.
+	MemberReplace(ctx context.Context, id uint64, peerAddrs []string) (*MemberReplaceResponse, error)
 }
 
 type cluster struct {
@@ -74,14 +86,18 @@ func NewClusterFromClusterClient(remote pb.ClusterClient, c *Client) Cluster {
 }
 
 func (c *cluster) MemberAdd(ctx context.Context, peerAddrs []string) (*MemberAddResponse, error) {
-	return c.memberAdd(ctx, peerAddrs, false)
+	return c.memberAdd(ctx, peerAddrs, false, false)
 }
 
 func (c *cluster) MemberAddAsLearner(ctx context.Context, peerAddrs []string) (*MemberAddResponse, error) {
-	return c.memberAdd(ctx, peerAddrs, true)
+	return c.memberAdd(ctx, peerAddrs, true, false)
+}
+
+func (c *cluster) MemberAddAsStandby(ctx context.Context, peerAddrs []string) (*MemberAddResponse, error) {
+	return c.memberAdd(ctx, peerAddrs, true, true)
 }
 
-func (c *cluster) memberAdd(ctx context.Context, peerAddrs []string, isLearner bool) (*MemberAddResponse, error) {
+func (c *cluster) memberAdd(ctx context.Context, peerAddrs []string, isLearner, isStandby bool) (*MemberAddResponse, error) {
 	// fail-fast before panic in rafthttp
 	if _, err := types.NewURLs(peerAddrs); err != nil {
 		return nil, err
@@ -90,6 +106,7 @@ func (c *cluster) memberAdd(ctx context.Context, peerAddrs []string, isLearner b
 	r := &pb.MemberAddRequest{
 		PeerURLs:  peerAddrs,
 		IsLearner: isLearner,
+		IsStandby: isStandby,
 	}
 	resp, err := c.remote.MemberAdd(ctx, r, c.callOpts...)
 	if err != nil {
@@ -139,3 +156,17 @@ func (c *cluster) MemberPromote(ctx context.Context, id uint64) (*MemberPromoteR
 	}
 	return (*MemberPromoteResponse)(resp), nil
 }
+
+func (c *cluster) MemberReplace(ctx context.Context, id uint64, peerAddrs []string) (*MemberReplaceResponse, error) {
+	// fail-fast before panic in rafthttp
+	if _, err := types.NewURLs(peerAddrs); err != nil {
+		return nil, err
+	}
+
+	r := &pb.MemberReplaceRequest{ID: id, PeerURLs: peerAddrs}
+	resp, err := c.remote.MemberReplace(ctx, r, c.callOpts...)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	return (*MemberReplaceResponse)(resp), nil
+}
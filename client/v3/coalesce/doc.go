@@ -0,0 +1,32 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package coalesce is a clientv3 wrapper that merges concurrent identical
+// serializable Get requests into a single RPC.
+//
+// When many goroutines read the same hot key at once, each Get normally
+// issues its own Range RPC. With the wrapper, a serializable Get that is
+// identical to one already in flight waits for that request instead, and
+// every caller receives the same response:
+//
+//	cli.KV = coalesce.NewKV(cli.KV)
+//	resp, err := cli.Get(ctx, "foo", clientv3.WithSerializable())
+//
+// Responses are shared between callers and must not be modified.
+//
+// A Get joining a request that is already in flight may observe a revision
+// older than a write that completed before the Get was issued. Linearizable
+// Gets must not, so they are never coalesced and always issue their own
+// request.
+package coalesce
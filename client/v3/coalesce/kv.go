@@ -0,0 +1,110 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coalesce
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// kvCoalescing merges concurrent identical serializable Gets into one request.
+type kvCoalescing struct {
+	clientv3.KV
+
+	mu    sync.Mutex
+	calls map[string]*call
+}
+
+// call is a Get in flight, shared by its waiters.
+type call struct {
+	donec   chan struct{}
+	resp    *clientv3.GetResponse
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+func NewKV(kv clientv3.KV) clientv3.KV {
+	return &kvCoalescing{KV: kv, calls: make(map[string]*call)}
+}
+
+func (kv *kvCoalescing) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	op := clientv3.OpGet(key, opts...)
+	if !op.IsSerializable() {
+		// a linearizable Get must observe every write completed before it
+		// is issued, which the request in flight may not.
+		return kv.KV.Get(ctx, key, opts...)
+	}
+	k := callKey(op)
+
+	kv.mu.Lock()
+	c, ok := kv.calls[k]
+	if !ok {
+		// the request outlives the caller that starts it if others wait for
+		// it, so it is only canceled once every waiter has given up.
+		cctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		c = &call{donec: make(chan struct{}), cancel: cancel}
+		kv.calls[k] = c
+		go kv.do(cctx, k, c, op)
+	}
+	c.waiters++
+	kv.mu.Unlock()
+
+	select {
+	case <-c.donec:
+		return c.resp, c.err
+	case <-ctx.Done():
+		kv.mu.Lock()
+		c.waiters--
+		if c.waiters == 0 {
+			c.cancel()
+			if kv.calls[k] == c {
+				delete(kv.calls, k)
+			}
+		}
+		kv.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+func (kv *kvCoalescing) do(ctx context.Context, k string, c *call, op clientv3.Op) {
+	resp, err := kv.KV.Do(ctx, op)
+	c.cancel()
+
+	kv.mu.Lock()
+	if kv.calls[k] == c {
+		delete(kv.calls, k)
+	}
+	kv.mu.Unlock()
+
+	c.resp, c.err = resp.Get(), err
+	close(c.donec)
+}
+
+// callKey identifies a Get by every field that affects its response.
+func callKey(op clientv3.Op) string {
+	var sort clientv3.SortOption
+	if s := op.Sort(); s != nil {
+		sort = *s
+	}
+	return fmt.Sprintf("%q/%q/%d/%d/%t/%t/%t/%t/%d/%d/%d/%d/%d/%d/%q/%q/%t/%d",
+		op.KeyBytes(), op.RangeBytes(), op.Rev(), op.Limit(),
+		op.IsSerializable(), op.IsKeysOnly(), op.IsCountOnly(), op.IsSizeOnly(),
+		op.MinModRev(), op.MaxModRev(), op.MinCreateRev(), op.MaxCreateRev(),
+		sort.Target, sort.Order, op.ContinueToken(), op.KeyFilter(), op.IsKeyFilterRegex(), op.LeaseFilter())
+}
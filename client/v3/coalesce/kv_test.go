@@ -0,0 +1,200 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coalesce
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// blockingKV answers every Get and Do once released, counting the requests.
+type blockingKV struct {
+	clientv3.KV
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (kv *blockingKV) Do(ctx context.Context, op clientv3.Op) (clientv3.OpResponse, error) {
+	kv.calls.Add(1)
+	select {
+	case <-kv.release:
+	case <-ctx.Done():
+		return clientv3.OpResponse{}, ctx.Err()
+	}
+	resp := &clientv3.GetResponse{
+		Header: &pb.ResponseHeader{Revision: 1},
+		Kvs:    []*mvccpb.KeyValue{{Key: op.KeyBytes(), Value: []byte("bar")}},
+	}
+	return resp.OpResponse(), nil
+}
+
+func (kv *blockingKV) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	resp, err := kv.Do(ctx, clientv3.OpGet(key, opts...))
+	return resp.Get(), err
+}
+
+func newBlockingKV() *blockingKV {
+	return &blockingKV{KV: clientv3.NewKVFromKVClient(nil, nil), release: make(chan struct{})}
+}
+
+// waitCalls waits until the underlying KV received n requests.
+func waitCalls(t *testing.T, kv *blockingKV, n int32) {
+	for i := 0; kv.calls.Load() < n; i++ {
+		if i == 1000 {
+			t.Fatalf("got %d requests, want %d", kv.calls.Load(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCoalesceConcurrentGets(t *testing.T) {
+	mkv := newBlockingKV()
+	kv := NewKV(mkv)
+
+	var started, wg sync.WaitGroup
+	n := 100
+	resps := make([]*clientv3.GetResponse, n)
+	for i := 0; i < n; i++ {
+		started.Add(1)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			started.Done()
+			var err error
+			resps[i], err = kv.Get(t.Context(), "foo", clientv3.WithSerializable())
+			if err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	started.Wait()
+	waitCalls(t, mkv, 1)
+	// give the remaining Gets time to join the request in flight.
+	time.Sleep(50 * time.Millisecond)
+	close(mkv.release)
+	wg.Wait()
+
+	if c := mkv.calls.Load(); c != 1 {
+		t.Fatalf("got %d requests, want 1", c)
+	}
+	for i, resp := range resps {
+		if resp != resps[0] || string(resp.Kvs[0].Value) != "bar" {
+			t.Fatalf("#%d: unexpected response %+v", i, resp)
+		}
+	}
+
+	// a Get after the shared request completed issues a new request.
+	if _, err := kv.Get(t.Context(), "foo", clientv3.WithSerializable()); err != nil {
+		t.Fatal(err)
+	}
+	if c := mkv.calls.Load(); c != 2 {
+		t.Fatalf("got %d requests, want 2", c)
+	}
+}
+
+func TestCoalesceDistinctGets(t *testing.T) {
+	mkv := newBlockingKV()
+	kv := NewKV(mkv)
+
+	gets := []func() error{
+		func() error { _, err := kv.Get(t.Context(), "foo"); return err },
+		func() error { _, err := kv.Get(t.Context(), "bar"); return err },
+		func() error { _, err := kv.Get(t.Context(), "foo", clientv3.WithPrefix()); return err },
+		func() error { _, err := kv.Get(t.Context(), "foo", clientv3.WithSerializable()); return err },
+		func() error { _, err := kv.Get(t.Context(), "foo", clientv3.WithRev(3)); return err },
+		func() error {
+			_, err := kv.Get(t.Context(), "foo", clientv3.WithSort(clientv3.SortByKey, clientv3.SortDescend))
+			return err
+		},
+	}
+	var wg sync.WaitGroup
+	for _, get := range gets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := get(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	waitCalls(t, mkv, int32(len(gets)))
+	close(mkv.release)
+	wg.Wait()
+}
+
+func TestCoalesceLinearizableGets(t *testing.T) {
+	mkv := newBlockingKV()
+	kv := NewKV(mkv)
+
+	var wg sync.WaitGroup
+	n := 10
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := kv.Get(t.Context(), "foo"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	// every linearizable Get issues its own request.
+	waitCalls(t, mkv, int32(n))
+	close(mkv.release)
+	wg.Wait()
+}
+
+func TestCoalesceCanceledWaiter(t *testing.T) {
+	mkv := newBlockingKV()
+	kv := NewKV(mkv)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	errc := make(chan error, 1)
+	go func() {
+		_, err := kv.Get(ctx, "foo", clientv3.WithSerializable())
+		errc <- err
+	}()
+	waitCalls(t, mkv, 1)
+
+	respc := make(chan *clientv3.GetResponse, 1)
+	go func() {
+		resp, err := kv.Get(t.Context(), "foo", clientv3.WithSerializable())
+		if err != nil {
+			t.Error(err)
+		}
+		respc <- resp
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	// the request started by the canceled caller still serves the other one.
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+	close(mkv.release)
+	if resp := <-respc; resp == nil || string(resp.Kvs[0].Value) != "bar" {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	if c := mkv.calls.Load(); c != 1 {
+		t.Fatalf("got %d requests, want 1", c)
+	}
+}
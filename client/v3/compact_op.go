@@ -22,6 +22,7 @@ import (
 type CompactOp struct {
 	revision int64
 	physical bool
+	dryRun   bool
 }
 
 // CompactOption configures compact operation.
@@ -41,7 +42,7 @@ func OpCompact(rev int64, opts ...CompactOption) CompactOp {
 }
 
 func (op CompactOp) toRequest() *pb.CompactionRequest {
-	return &pb.CompactionRequest{Revision: op.revision, Physical: op.physical}
+	return &pb.CompactionRequest{Revision: op.revision, Physical: op.physical, DryRun: op.dryRun}
 }
 
 // WithCompactPhysical makes Compact wait until all compacted entries are
@@ -49,3 +50,10 @@ func (op CompactOp) toRequest() *pb.CompactionRequest {
 func WithCompactPhysical() CompactOption {
 	return func(op *CompactOp) { op.physical = true }
 }
+
+// WithCompactDryRun makes Compact estimate the key revisions and the bytes
+// the compaction would delete, without compacting. The estimate is reported
+// in the ReclaimableRevisions and ReclaimableBytes of the response.
+func WithCompactDryRun() CompactOption {
+	return func(op *CompactOp) { op.dryRun = true }
+}
@@ -28,3 +28,9 @@ func TestCompactOp(t *testing.T) {
 	req2 := &etcdserverpb.CompactionRequest{Revision: 100, Physical: true}
 	require.Truef(t, reflect.DeepEqual(req1, req2), "expected %+v, got %+v", req2, req1)
 }
+
+func TestCompactOpDryRun(t *testing.T) {
+	req1 := OpCompact(100, WithCompactDryRun()).toRequest()
+	req2 := &etcdserverpb.CompactionRequest{Revision: 100, DryRun: true}
+	require.Truef(t, reflect.DeepEqual(req1, req2), "expected %+v, got %+v", req2, req1)
+}
@@ -64,6 +64,14 @@ func Compare(cmp Cmp, result string, v any) Cmp {
 		cmp.TargetUnion = &pb.Compare_ModRevision{ModRevision: mustInt64(v)}
 	case pb.Compare_LEASE:
 		cmp.TargetUnion = &pb.Compare_Lease{Lease: mustInt64orLeaseID(v)}
+	case pb.Compare_COUNT:
+		cmp.TargetUnion = &pb.Compare_Count{Count: mustInt64(v)}
+	case pb.Compare_VALUE_PREFIX:
+		val, ok := v.(string)
+		if !ok {
+			panic("bad compare value")
+		}
+		cmp.TargetUnion = &pb.Compare_ValuePrefix{ValuePrefix: []byte(val)}
 	default:
 		panic("Unknown compare type")
 	}
@@ -92,6 +100,20 @@ func LeaseValue(key string) Cmp {
 	return Cmp{Key: []byte(key), Target: pb.Compare_LEASE}
 }
 
+// Count compares the number of keys in the range of the comparison, set with
+// WithRange or WithPrefix, to a value of your choosing. Unlike the other
+// targets, the range is compared once rather than key by key.
+func Count(key string) Cmp {
+	return Cmp{Key: []byte(key), Target: pb.Compare_COUNT}
+}
+
+// ValuePrefix compares the leading bytes of a key's value to a prefix of your
+// choosing; "=" holds if the value starts with the prefix. As with Value, the
+// comparison fails if the key does not exist.
+func ValuePrefix(key string) Cmp {
+	return Cmp{Key: []byte(key), Target: pb.Compare_VALUE_PREFIX}
+}
+
 // KeyBytes returns the byte slice holding with the comparison key.
 func (cmp *Cmp) KeyBytes() []byte { return cmp.Key }
 
@@ -0,0 +1,198 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	v3 "go.etcd.io/etcd/client/v3"
+)
+
+var (
+	ErrBarrierAborted = errors.New("barrier: aborted after a participant failed")
+	ErrBarrierFull    = errors.New("barrier: all participants already arrived")
+)
+
+const (
+	barrierReleased = "released"
+	barrierAborted  = "aborted"
+)
+
+// BarrierFailurePolicy decides what happens to a barrier when the session of
+// a participant that arrived at it expires before the barrier is released.
+type BarrierFailurePolicy int
+
+const (
+	// BarrierAbortOnFailure aborts the barrier for all participants.
+	BarrierAbortOnFailure BarrierFailurePolicy = iota
+	// BarrierProceedOnFailure keeps counting the failed participant as
+	// arrived, so the barrier is released once the others arrive.
+	BarrierProceedOnFailure
+)
+
+// Barrier blocks participants until a given number of them arrived at it.
+// Arrivals are counted on a counter key, and the last participant to arrive
+// releases all others through a release key they watch. A barrier is used
+// once; use a new prefix for every synchronization round. Every participant
+// must use its own session.
+type Barrier struct {
+	s      *Session
+	pfx    string
+	n      int
+	policy BarrierFailurePolicy
+}
+
+// NewBarrier creates a barrier on the given prefix that is released once n
+// participants arrived at it.
+func NewBarrier(s *Session, pfx string, n int, policy BarrierFailurePolicy) *Barrier {
+	return &Barrier{s: s, pfx: pfx + "/", n: n, policy: policy}
+}
+
+func (b *Barrier) countKey() string   { return b.pfx + "count" }
+func (b *Barrier) releaseKey() string { return b.pfx + "release" }
+func (b *Barrier) waitersKey() string { return b.pfx + "waiters/" }
+func (b *Barrier) myKey() string      { return fmt.Sprintf("%s%x", b.waitersKey(), b.s.Lease()) }
+
+// Wait arrives at the barrier and blocks until all participants arrived,
+// the barrier is aborted, or the context is canceled. A participant who
+// arrived stays counted even if its context is canceled.
+func (b *Barrier) Wait(ctx context.Context) error {
+	client := b.s.Client()
+
+	var arrivedRev int64
+	for {
+		resp, err := client.Txn(ctx).Then(v3.OpGet(b.countKey()), v3.OpGet(b.releaseKey())).Commit()
+		if err != nil {
+			return err
+		}
+		if kvs := resp.Responses[1].GetResponseRange().Kvs; len(kvs) > 0 {
+			if err = releaseError(kvs[0].Value); err != nil {
+				return err
+			}
+			return ErrBarrierFull
+		}
+		count, modRev := 0, int64(0)
+		if kvs := resp.Responses[0].GetResponseRange().Kvs; len(kvs) > 0 {
+			if count, err = strconv.Atoi(string(kvs[0].Value)); err != nil {
+				return fmt.Errorf("barrier: invalid count %q: %w", kvs[0].Value, err)
+			}
+			modRev = kvs[0].ModRevision
+		}
+		if count >= b.n {
+			return ErrBarrierFull
+		}
+
+		ops := []v3.Op{
+			v3.OpPut(b.countKey(), strconv.Itoa(count+1)),
+			v3.OpPut(b.myKey(), "", v3.WithLease(b.s.Lease())),
+			v3.OpGet(b.waitersKey(), v3.WithPrefix(), v3.WithCountOnly()),
+		}
+		last := count+1 == b.n
+		if last && b.policy == BarrierProceedOnFailure {
+			ops = append(ops, v3.OpPut(b.releaseKey(), barrierReleased))
+		}
+		tresp, err := client.Txn(ctx).If(
+			v3.Compare(v3.ModRevision(b.countKey()), "=", modRev),
+			v3.Compare(v3.CreateRevision(b.releaseKey()), "=", 0),
+		).Then(ops...).Commit()
+		if err != nil {
+			return err
+		}
+		if !tresp.Succeeded {
+			// another participant arrived first, count again
+			continue
+		}
+		if b.policy == BarrierAbortOnFailure {
+			// a participant whose session expired before this arrival is
+			// only visible as a missing waiter key
+			if tresp.Responses[2].GetResponseRange().Count < int64(count+1) {
+				return b.abort(ctx)
+			}
+			if last {
+				return b.release(ctx)
+			}
+		} else if last {
+			return nil
+		}
+		arrivedRev = tresp.Header.Revision
+		break
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	wch := client.Watch(cctx, b.pfx, v3.WithPrefix(), v3.WithRev(arrivedRev+1))
+	var wr v3.WatchResponse
+	for wr = range wch {
+		for _, ev := range wr.Events {
+			key := string(ev.Kv.Key)
+			switch {
+			case key == b.releaseKey() && ev.Type == mvccpb.PUT:
+				return releaseError(ev.Kv.Value)
+			case strings.HasPrefix(key, b.waitersKey()) && ev.Type == mvccpb.DELETE:
+				if b.policy == BarrierAbortOnFailure {
+					return b.abort(ctx)
+				}
+			}
+		}
+	}
+	if err := wr.Err(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return errors.New("lost watcher waiting for barrier release")
+}
+
+// abort releases all participants with ErrBarrierAborted, unless the barrier
+// has been released or aborted already.
+func (b *Barrier) abort(ctx context.Context) error {
+	return b.finish(ctx, barrierAborted)
+}
+
+// release releases all participants, unless the barrier has been aborted
+// already by a participant observing a failed one.
+func (b *Barrier) release(ctx context.Context) error {
+	return b.finish(ctx, barrierReleased)
+}
+
+// finish puts the given outcome on the release key if none is there yet, and
+// returns the error of the outcome the barrier ends up with.
+func (b *Barrier) finish(ctx context.Context, outcome string) error {
+	resp, err := b.s.Client().Txn(ctx).
+		If(v3.Compare(v3.CreateRevision(b.releaseKey()), "=", 0)).
+		Then(v3.OpPut(b.releaseKey(), outcome)).
+		Else(v3.OpGet(b.releaseKey())).
+		Commit()
+	if err != nil {
+		return err
+	}
+	if resp.Succeeded {
+		return releaseError([]byte(outcome))
+	}
+	return releaseError(resp.Responses[0].GetResponseRange().Kvs[0].Value)
+}
+
+func releaseError(v []byte) error {
+	if string(v) == barrierAborted {
+		return ErrBarrierAborted
+	}
+	return nil
+}
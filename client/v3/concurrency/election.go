@@ -18,6 +18,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/api/v3/mvccpb"
@@ -106,6 +108,112 @@ func (e *Election) Campaign(ctx context.Context, val string) error {
 	return nil
 }
 
+// CampaignWithPriority is like Campaign, except that a vacant leadership goes
+// to the live candidate with the highest priority instead of the one that
+// campaigned first; candidates of equal priority are elected in campaign
+// order. A leader is never preempted by a candidate of higher priority.
+//
+// Candidates wait under a separate "<prefix>-candidates/" prefix until they
+// are elected, so the candidates of an election must either all campaign with
+// a priority or all campaign with Campaign.
+func (e *Election) CampaignWithPriority(ctx context.Context, val string, priority int64) error {
+	s := e.session
+	client := e.session.Client()
+
+	k := fmt.Sprintf("%s%x", e.keyPrefix, s.Lease())
+	cpfx := e.candidatePrefix()
+	ck := fmt.Sprintf("%s%x", cpfx, s.Lease())
+	if _, err := client.Put(ctx, ck, strconv.FormatInt(priority, 10), v3.WithLease(s.Lease())); err != nil {
+		return err
+	}
+	withdraw := func() { client.Delete(client.Ctx(), ck) }
+
+	for {
+		resp, err := client.Txn(ctx).Then(
+			v3.OpGet(k),
+			v3.OpGet(e.keyPrefix, v3.WithPrefix(), v3.WithCountOnly()),
+			v3.OpGet(cpfx, v3.WithPrefix()),
+		).Commit()
+		if err != nil {
+			withdraw()
+			return err
+		}
+		if len(resp.Responses[0].GetResponseRange().Kvs) > 0 {
+			// already campaigning, e.g. on a resumed election.
+			withdraw()
+			return e.Campaign(ctx, val)
+		}
+		rev := resp.Header.Revision
+		best, ok := electedCandidate(resp.Responses[2].GetResponseRange().Kvs, ck)
+		if !ok {
+			// the candidacy expired with the session.
+			return ErrSessionExpired
+		}
+		if resp.Responses[1].GetResponseRange().Count == 0 && best == ck {
+			// take the leadership unless it was taken, or a candidate
+			// joined, since the read.
+			tresp, err := client.Txn(ctx).If(
+				v3.Compare(v3.CreateRevision(e.keyPrefix).WithPrefix(), "=", 0),
+				v3.Compare(v3.ModRevision(cpfx).WithPrefix(), "<", rev+1),
+			).Then(
+				v3.OpPut(k, val, v3.WithLease(s.Lease())),
+				v3.OpDelete(ck),
+			).Commit()
+			if err != nil {
+				withdraw()
+				return err
+			}
+			if tresp.Succeeded {
+				e.leaderKey, e.leaderRev, e.leaderSession = k, tresp.Header.Revision, s
+				e.hdr = tresp.Header
+				return nil
+			}
+			continue
+		}
+
+		// wait for the leader or the candidates to change.
+		cctx, cancel := context.WithCancel(ctx)
+		wch := client.Watch(cctx, cpfx, v3.WithRange(v3.GetPrefixRangeEnd(e.keyPrefix)), v3.WithRev(rev+1))
+		wr, ok := <-wch
+		cancel()
+		if err = wr.Err(); err == nil && !ok {
+			err = ctx.Err()
+		}
+		if err != nil {
+			withdraw()
+			return err
+		}
+	}
+}
+
+// candidatePrefix is the prefix the candidates campaigning with a priority
+// wait under. It sorts right before the key prefix of the election.
+func (e *Election) candidatePrefix() string {
+	return strings.TrimSuffix(e.keyPrefix, "/") + "-candidates/"
+}
+
+// electedCandidate returns the key of the candidate of highest priority,
+// earliest first, and whether the candidate ck is among the candidates.
+func electedCandidate(kvs []*mvccpb.KeyValue, ck string) (string, bool) {
+	var best *mvccpb.KeyValue
+	var bestPriority int64
+	found := false
+	for _, kv := range kvs {
+		found = found || string(kv.Key) == ck
+		priority, err := strconv.ParseInt(string(kv.Value), 10, 64)
+		if err != nil {
+			continue
+		}
+		if best == nil || priority > bestPriority || (priority == bestPriority && kv.CreateRevision < best.CreateRevision) {
+			best, bestPriority = kv, priority
+		}
+	}
+	if best == nil {
+		return "", found
+	}
+	return string(best.Key), found
+}
+
 // Proclaim lets the leader announce a new value without another election.
 func (e *Election) Proclaim(ctx context.Context, val string) error {
 	if e.leaderSession == nil {
@@ -0,0 +1,241 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	v3 "go.etcd.io/etcd/client/v3"
+)
+
+// ErrDeadlock is returned by WriteIntents.Lock when waiting for the key would
+// complete a wait-for cycle and this session was chosen to break it.
+var ErrDeadlock = errors.New("concurrency: deadlock detected, write intents released")
+
+// WriteIntents records write intents on keys for a session, so that
+// transactional clients can serialize conflicting writes across sessions.
+//
+// An intent on a key is exclusive: the oldest session that recorded an intent
+// on it holds it, the others wait in order. While waiting, a session
+// publishes which session it waits for. When the published edges form a cycle,
+// the session with the highest lease ID in the cycle aborts: Lock releases
+// every intent it holds and returns ErrDeadlock, letting the others proceed.
+// The caller is expected to abandon its transaction and retry it.
+//
+// Only one key may be locked at a time per WriteIntents, and a session must
+// not use more than one WriteIntents with the same prefix.
+type WriteIntents struct {
+	s   *Session
+	pfx string
+
+	mu   sync.Mutex
+	held map[string]string // locked key -> intent key
+}
+
+func NewWriteIntents(s *Session, pfx string) *WriteIntents {
+	return &WriteIntents{s: s, pfx: pfx + "/", held: make(map[string]string)}
+}
+
+// Lock records a write intent on key and blocks until this session holds it.
+// If the context is canceled while waiting, the pending intent is removed
+// but already held intents are kept.
+func (w *WriteIntents) Lock(ctx context.Context, key string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.held[key]; ok {
+		return nil
+	}
+	client := w.s.Client()
+	lockPfx := w.lockPrefix(key)
+	myKey := fmt.Sprintf("%s%x", lockPfx, w.s.Lease())
+	resp, err := client.Txn(ctx).
+		If(v3.Compare(v3.CreateRevision(myKey), "=", 0)).
+		Then(v3.OpPut(myKey, "", v3.WithLease(w.s.Lease()))).
+		Else(v3.OpGet(myKey)).
+		Commit()
+	if err != nil {
+		return err
+	}
+	myRev := resp.Header.Revision
+	if !resp.Succeeded {
+		myRev = resp.Responses[0].GetResponseRange().Kvs[0].CreateRevision
+	}
+
+	err = w.wait(ctx, lockPfx, myRev)
+	if _, derr := client.Delete(client.Ctx(), w.waitKey(w.s.Lease())); derr != nil && err == nil {
+		err = derr
+	}
+	if err != nil {
+		client.Delete(client.Ctx(), myKey)
+		if errors.Is(err, ErrDeadlock) {
+			w.unlockAll(client.Ctx())
+		}
+		return err
+	}
+	w.held[key] = myKey
+	return nil
+}
+
+// wait blocks until the intent created at myRev is the oldest under lockPfx.
+func (w *WriteIntents) wait(ctx context.Context, lockPfx string, myRev int64) error {
+	client := w.s.Client()
+	for {
+		gresp, err := client.Get(ctx, lockPfx, v3.WithFirstCreate()...)
+		if err != nil {
+			return err
+		}
+		if len(gresp.Kvs) == 0 {
+			return ErrSessionExpired
+		}
+		owner := gresp.Kvs[0]
+		if owner.CreateRevision == myRev {
+			return nil
+		}
+		holder, err := leaseFromKey(string(owner.Key))
+		if err != nil {
+			return err
+		}
+
+		// publish the wait-for edge, then look for a cycle in a consistent
+		// snapshot of all edges.
+		presp, err := client.Put(ctx, w.waitKey(w.s.Lease()), fmt.Sprintf("%x", holder), v3.WithLease(w.s.Lease()))
+		if err != nil {
+			return err
+		}
+		wresp, err := client.Get(ctx, w.pfx+"waits/", v3.WithPrefix(), v3.WithRev(presp.Header.Revision))
+		if err != nil {
+			return err
+		}
+		edges := make(map[v3.LeaseID]v3.LeaseID, len(wresp.Kvs))
+		for _, kv := range wresp.Kvs {
+			from, ferr := leaseFromKey(string(kv.Key))
+			to, terr := strconv.ParseInt(string(kv.Value), 16, 64)
+			if ferr != nil || terr != nil {
+				continue
+			}
+			edges[from] = v3.LeaseID(to)
+		}
+		if victim, ok := findCycle(edges, w.s.Lease()); ok && victim == w.s.Lease() {
+			return ErrDeadlock
+		}
+
+		// any change to the intents or the edges may release the key or
+		// close a cycle, so re-evaluate on every event.
+		wctx, cancel := context.WithCancel(ctx)
+		wch := client.Watch(wctx, w.pfx, v3.WithPrefix(), v3.WithRev(presp.Header.Revision+1))
+		select {
+		case wr, ok := <-wch:
+			cancel()
+			if !ok {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				continue
+			}
+			if err = wr.Err(); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			cancel()
+			return ctx.Err()
+		}
+	}
+}
+
+// Unlock releases the write intent on key.
+func (w *WriteIntents) Unlock(ctx context.Context, key string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	myKey, ok := w.held[key]
+	if !ok {
+		return ErrLockReleased
+	}
+	if _, err := w.s.Client().Delete(ctx, myKey); err != nil {
+		return err
+	}
+	delete(w.held, key)
+	return nil
+}
+
+// UnlockAll releases every write intent held by this session.
+func (w *WriteIntents) UnlockAll(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.unlockAll(ctx)
+}
+
+func (w *WriteIntents) unlockAll(ctx context.Context) error {
+	for key, myKey := range w.held {
+		if _, err := w.s.Client().Delete(ctx, myKey); err != nil {
+			return err
+		}
+		delete(w.held, key)
+	}
+	return nil
+}
+
+// Held returns whether this session holds the write intent on key.
+func (w *WriteIntents) Held(key string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, ok := w.held[key]
+	return ok
+}
+
+// lockPrefix hex-encodes the key so that intents on a key never share a
+// prefix with intents on another key.
+func (w *WriteIntents) lockPrefix(key string) string {
+	return fmt.Sprintf("%slocks/%x/", w.pfx, key)
+}
+
+func (w *WriteIntents) waitKey(id v3.LeaseID) string {
+	return fmt.Sprintf("%swaits/%x", w.pfx, id)
+}
+
+func leaseFromKey(key string) (v3.LeaseID, error) {
+	id, err := strconv.ParseInt(key[strings.LastIndex(key, "/")+1:], 16, 64)
+	if err != nil {
+		return v3.NoLease, fmt.Errorf("concurrency: invalid intent key '%s': %w", v3.EscapeKey(key), err)
+	}
+	return v3.LeaseID(id), nil
+}
+
+// findCycle follows the wait-for edges from start. If they lead back to start,
+// it returns the highest lease ID on the cycle, which is the one to abort.
+func findCycle(edges map[v3.LeaseID]v3.LeaseID, start v3.LeaseID) (v3.LeaseID, bool) {
+	victim := start
+	for cur, n := start, 0; n <= len(edges); n++ {
+		next, ok := edges[cur]
+		if !ok {
+			return v3.NoLease, false
+		}
+		if next == start {
+			return victim, true
+		}
+		if next > victim {
+			victim = next
+		}
+		cur = next
+	}
+	// the path ends in a cycle that does not include start.
+	return v3.NoLease, false
+}
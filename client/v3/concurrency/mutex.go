@@ -137,7 +137,7 @@ func (m *Mutex) Unlock(ctx context.Context) error {
 	}
 
 	if !strings.HasPrefix(m.myKey, m.pfx) {
-		return fmt.Errorf("invalid key %q, it should have prefix %q", m.myKey, m.pfx)
+		return fmt.Errorf("invalid key '%s', it should have prefix '%s'", v3.EscapeKey(m.myKey), v3.EscapeKey(m.pfx))
 	}
 
 	client := m.s.Client()
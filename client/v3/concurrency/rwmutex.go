@@ -0,0 +1,243 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrency
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	v3 "go.etcd.io/etcd/client/v3"
+)
+
+// RWMutexMetrics receives the lock events of an RWMutex, e.g. to export them
+// as metrics. The write argument tells whether the event is about the write
+// lock or a read lock.
+type RWMutexMetrics interface {
+	// Acquired is called once a lock is acquired, with the time spent
+	// waiting for it.
+	Acquired(write bool, waited time.Duration)
+	// Contended is called when TryLock or TryRLock fails with ErrLocked.
+	Contended(write bool)
+	// Released is called once a lock is released, with the time it was
+	// held.
+	Released(write bool, held time.Duration)
+}
+
+type nopRWMutexMetrics struct{}
+
+func (nopRWMutexMetrics) Acquired(bool, time.Duration) {}
+func (nopRWMutexMetrics) Contended(bool)               {}
+func (nopRWMutexMetrics) Released(bool, time.Duration) {}
+
+// RWMutexOption configures an RWMutex.
+type RWMutexOption func(*RWMutex)
+
+// WithRWMutexMetrics reports the lock events of the RWMutex to m.
+func WithRWMutexMetrics(m RWMutexMetrics) RWMutexOption {
+	return func(rw *RWMutex) { rw.metrics = m }
+}
+
+// RWMutex is a reader/writer mutual exclusion lock held by sessions. Every
+// locker puts a key attached to its session lease under the prefix, and the
+// locks are granted in the order of the keys' creation: a write lock once
+// all earlier keys are deleted, a read lock once all earlier write keys are
+// deleted. A waiting writer therefore blocks the readers arriving after it,
+// so that writers are not starved by a stream of readers.
+//
+// An RWMutex holds a single lock at a time; use an RWMutex per session.
+type RWMutex struct {
+	s       *Session
+	pfx     string
+	metrics RWMutexMetrics
+
+	myKey    string
+	myRev    int64
+	write    bool
+	acquired time.Time
+	hdr      *pb.ResponseHeader
+}
+
+// NewRWMutex creates a reader/writer lock on the given prefix.
+func NewRWMutex(s *Session, pfx string, opts ...RWMutexOption) *RWMutex {
+	rw := &RWMutex{s: s, pfx: pfx + "/", metrics: nopRWMutexMetrics{}, myRev: -1}
+	for _, opt := range opts {
+		opt(rw)
+	}
+	return rw
+}
+
+func (rw *RWMutex) readPrefix() string  { return rw.pfx + "read/" }
+func (rw *RWMutex) writePrefix() string { return rw.pfx + "write/" }
+
+// lockKey returns the key of the session for the given kind of lock.
+func (rw *RWMutex) lockKey(write bool) string {
+	if write {
+		return fmt.Sprintf("%s%x", rw.writePrefix(), rw.s.Lease())
+	}
+	return fmt.Sprintf("%s%x", rw.readPrefix(), rw.s.Lease())
+}
+
+// blockers returns the prefix of the keys a lock of the given kind waits on.
+func (rw *RWMutex) blockers(write bool) string {
+	if write {
+		return rw.pfx
+	}
+	return rw.writePrefix()
+}
+
+// Lock acquires the write lock, waiting for the holders and the earlier
+// waiters of the lock to release it. If the context is canceled while
+// waiting, the mutex tries to clean its stale lock entry.
+func (rw *RWMutex) Lock(ctx context.Context) error { return rw.lock(ctx, true) }
+
+// RLock acquires a read lock, waiting for the holder and the earlier waiters
+// of the write lock to release it. If the context is canceled while waiting,
+// the mutex tries to clean its stale lock entry.
+func (rw *RWMutex) RLock(ctx context.Context) error { return rw.lock(ctx, false) }
+
+// TryLock acquires the write lock if it can be granted right away, and
+// returns ErrLocked otherwise.
+func (rw *RWMutex) TryLock(ctx context.Context) error { return rw.tryLock(ctx, true) }
+
+// TryRLock acquires a read lock if it can be granted right away, and returns
+// ErrLocked otherwise.
+func (rw *RWMutex) TryRLock(ctx context.Context) error { return rw.tryLock(ctx, false) }
+
+// Unlock releases the write lock.
+func (rw *RWMutex) Unlock(ctx context.Context) error { return rw.unlock(ctx, true) }
+
+// RUnlock releases the read lock.
+func (rw *RWMutex) RUnlock(ctx context.Context) error { return rw.unlock(ctx, false) }
+
+func (rw *RWMutex) lock(ctx context.Context, write bool) error {
+	start := time.Now()
+	resp, err := rw.tryAcquire(ctx, write)
+	if err != nil {
+		return err
+	}
+	if !rw.blocked(resp) {
+		rw.granted(resp.Header, start)
+		return nil
+	}
+	client := rw.s.Client()
+	if err = waitDeletes(ctx, client, rw.blockers(write), rw.myRev-1); err != nil {
+		rw.release(client.Ctx())
+		return err
+	}
+
+	// make sure the session is not expired, and the lock key still exists.
+	gresp, err := client.Get(ctx, rw.myKey)
+	if err != nil {
+		rw.release(client.Ctx())
+		return err
+	}
+	if len(gresp.Kvs) == 0 {
+		rw.reset()
+		return ErrSessionExpired
+	}
+	rw.granted(gresp.Header, start)
+	return nil
+}
+
+func (rw *RWMutex) tryLock(ctx context.Context, write bool) error {
+	resp, err := rw.tryAcquire(ctx, write)
+	if err != nil {
+		return err
+	}
+	if !rw.blocked(resp) {
+		rw.granted(resp.Header, time.Now())
+		return nil
+	}
+	if err = rw.release(ctx); err != nil {
+		return err
+	}
+	rw.metrics.Contended(write)
+	return ErrLocked
+}
+
+// tryAcquire puts the lock key of the session, and fetches the earliest key
+// the lock waits on.
+func (rw *RWMutex) tryAcquire(ctx context.Context, write bool) (*v3.TxnResponse, error) {
+	if rw.myRev > 0 {
+		return nil, fmt.Errorf("rwmutex: lock already held on '%s'", v3.EscapeKey(rw.myKey))
+	}
+	client := rw.s.Client()
+	key := rw.lockKey(write)
+	cmp := v3.Compare(v3.CreateRevision(key), "=", 0)
+	put := v3.OpPut(key, "", v3.WithLease(rw.s.Lease()))
+	// reuse key in case this session already holds the lock
+	get := v3.OpGet(key)
+	getBlocker := v3.OpGet(rw.blockers(write), v3.WithFirstCreate()...)
+	resp, err := client.Txn(ctx).If(cmp).Then(put, getBlocker).Else(get, getBlocker).Commit()
+	if err != nil {
+		return nil, err
+	}
+	rw.myKey, rw.myRev, rw.write = key, resp.Header.Revision, write
+	if !resp.Succeeded {
+		rw.myRev = resp.Responses[0].GetResponseRange().Kvs[0].CreateRevision
+	}
+	return resp, nil
+}
+
+// blocked tells whether a key created before the lock key blocks the lock.
+func (rw *RWMutex) blocked(resp *v3.TxnResponse) bool {
+	kvs := resp.Responses[1].GetResponseRange().Kvs
+	return len(kvs) > 0 && kvs[0].CreateRevision < rw.myRev
+}
+
+func (rw *RWMutex) granted(hdr *pb.ResponseHeader, start time.Time) {
+	rw.hdr = hdr
+	rw.acquired = time.Now()
+	rw.metrics.Acquired(rw.write, rw.acquired.Sub(start))
+}
+
+func (rw *RWMutex) unlock(ctx context.Context, write bool) error {
+	if rw.myRev <= 0 || rw.write != write || rw.acquired.IsZero() {
+		return ErrLockReleased
+	}
+	held := time.Since(rw.acquired)
+	if err := rw.release(ctx); err != nil {
+		return err
+	}
+	rw.metrics.Released(write, held)
+	return nil
+}
+
+// release deletes the lock key.
+func (rw *RWMutex) release(ctx context.Context) error {
+	if _, err := rw.s.Client().Delete(ctx, rw.myKey); err != nil {
+		return err
+	}
+	rw.reset()
+	return nil
+}
+
+func (rw *RWMutex) reset() {
+	rw.myKey, rw.myRev, rw.acquired = "", -1, time.Time{}
+}
+
+// IsOwner returns a comparison that holds while the lock is held, to guard
+// Txns with.
+func (rw *RWMutex) IsOwner() v3.Cmp {
+	return v3.Compare(v3.CreateRevision(rw.myKey), "=", rw.myRev)
+}
+
+// Key returns the lock key of the session, if the lock is held.
+func (rw *RWMutex) Key() string { return rw.myKey }
+
+// Header is the response header received from etcd on acquiring the lock.
+func (rw *RWMutex) Header() *pb.ResponseHeader { return rw.hdr }
@@ -27,6 +27,7 @@ import (
 
 type Config struct {
 	// Endpoints is a list of URLs.
+	// The quic:// URLs are dialed over QUIC (experimental), secured by TLS.
 	Endpoints []string `json:"endpoints"`
 
 	// AutoSyncInterval is the interval to update endpoints with its latest members.
@@ -102,9 +103,62 @@ type Config struct {
 	// BackoffJitterFraction is the jitter fraction to randomize backoff wait time.
 	BackoffJitterFraction float64 `json:"backoff-jitter-fraction"`
 
+	// RetryPolicy configures the retries of the requests. Its non-zero fields
+	// take precedence over MaxUnaryRetries, BackoffWaitBetween and
+	// BackoffJitterFraction.
+	RetryPolicy *RetryPolicy `json:"retry-policy"`
+
+	// CircuitBreaker, when set, stops balancing requests to the endpoints
+	// whose requests keep failing, until they are probed successfully.
+	CircuitBreaker *CircuitBreakerConfig `json:"circuit-breaker"`
+
+	// FollowerReads, when set, routes the serializable reads to the endpoint
+	// with the lowest round trip time, and the writes and linearizable reads
+	// to the leader. It is meant for clusters spanning several regions, and
+	// cannot be combined with CircuitBreaker.
+	FollowerReads *FollowerReadsConfig `json:"follower-reads"`
+
+	// CallerLabel is sent along with every request so that the server can
+	// attribute the request in its per-caller metrics. It can be overridden
+	// per request with WithCallerLabel.
+	CallerLabel string `json:"caller-label"`
+
+	// Namespace isolates the keys of the requests in a namespace of the
+	// server, configured with its "--namespaces" flag: the keys are
+	// relative to the namespace, and the keys outside of it are invisible.
+	// It can be overridden per request with WithNamespace.
+	Namespace string `json:"namespace"`
+
+	// AutoMaxRequestBytes makes the client discover the maximum request size
+	// the servers accept ("--max-request-bytes") and fail larger Put, Delete
+	// and Txn requests with rpctypes.ErrRequestTooLarge before sending them.
+	AutoMaxRequestBytes bool `json:"auto-max-request-bytes"`
+
 	// TODO: support custom balancer picker
 }
 
+// CircuitBreakerConfig configures the ejection of failing endpoints from the
+// load balancing.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of requests in a row failing with
+	// Unavailable or DeadlineExceeded that ejects an endpoint. Defaults to 5.
+	FailureThreshold uint `json:"failure-threshold"`
+
+	// Cooldown is how long an ejected endpoint receives no request. A single
+	// probe request is then sent to it, reinstating the endpoint if it
+	// succeeds. Defaults to 30s.
+	Cooldown time.Duration `json:"cooldown"`
+}
+
+// FollowerReadsConfig configures the routing of the requests to the leader or
+// to the nearest endpoint.
+type FollowerReadsConfig struct {
+	// ProbeInterval is the interval between two probes of an endpoint,
+	// measuring its round trip time and whether it is the leader. Defaults
+	// to 5s.
+	ProbeInterval time.Duration `json:"probe-interval"`
+}
+
 // ConfigSpec is the configuration from users, which comes from command-line flags,
 // environment variables or config file. It is a fully declarative configuration,
 // and can be serialized & deserialized to/from JSON.
@@ -118,6 +172,7 @@ type ConfigSpec struct {
 	MaxCallRecvMsgSize int           `json:"max-recv-bytes"`
 	Secure             *SecureConfig `json:"secure"`
 	Auth               *AuthConfig   `json:"auth"`
+	Namespace          string        `json:"namespace"`
 }
 
 type SecureConfig struct {
@@ -179,6 +234,7 @@ func NewClientConfig(confSpec *ConfigSpec, lg *zap.Logger) (*Config, error) {
 		MaxCallSendMsgSize:   confSpec.MaxCallSendMsgSize,
 		MaxCallRecvMsgSize:   confSpec.MaxCallRecvMsgSize,
 		TLS:                  tlsCfg,
+		Namespace:            confSpec.Namespace,
 	}
 
 	if confSpec.Auth != nil {
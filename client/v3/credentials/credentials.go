@@ -19,6 +19,7 @@ package credentials
 import (
 	"context"
 	"crypto/tls"
+	"net"
 	"sync"
 
 	grpccredentials "google.golang.org/grpc/credentials"
@@ -26,8 +27,48 @@ import (
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 )
 
+// NewTransportCredential returns the TLS transport credentials of cfg. The
+// connections that are already secured by TLS, such as the QUIC connections,
+// are not secured again.
 func NewTransportCredential(cfg *tls.Config) grpccredentials.TransportCredentials {
-	return grpccredentials.NewTLS(cfg)
+	return &transportCredential{TransportCredentials: grpccredentials.NewTLS(cfg)}
+}
+
+// secureConn is implemented by the connections secured by TLS.
+type secureConn interface {
+	net.Conn
+	ConnectionState() tls.ConnectionState
+}
+
+// transportCredential implements `grpccredentials.TransportCredentials`
+// interface.
+type transportCredential struct {
+	grpccredentials.TransportCredentials
+}
+
+func secureAuthInfo(conn secureConn) grpccredentials.AuthInfo {
+	return grpccredentials.TLSInfo{
+		State:          conn.ConnectionState(),
+		CommonAuthInfo: grpccredentials.CommonAuthInfo{SecurityLevel: grpccredentials.PrivacyAndIntegrity},
+	}
+}
+
+func (tc *transportCredential) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, grpccredentials.AuthInfo, error) {
+	if conn, ok := rawConn.(secureConn); ok {
+		return conn, secureAuthInfo(conn), nil
+	}
+	return tc.TransportCredentials.ClientHandshake(ctx, authority, rawConn)
+}
+
+func (tc *transportCredential) ServerHandshake(rawConn net.Conn) (net.Conn, grpccredentials.AuthInfo, error) {
+	if conn, ok := rawConn.(secureConn); ok {
+		return conn, secureAuthInfo(conn), nil
+	}
+	return tc.TransportCredentials.ServerHandshake(rawConn)
+}
+
+func (tc *transportCredential) Clone() grpccredentials.TransportCredentials {
+	return &transportCredential{TransportCredentials: tc.TransportCredentials.Clone()}
 }
 
 // PerRPCCredentialsBundle defines gRPC credential interface.
@@ -15,9 +15,14 @@
 package credentials
 
 import (
+	"context"
+	"crypto/tls"
+	"net"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	grpccredentials "google.golang.org/grpc/credentials"
 
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 )
@@ -34,3 +39,32 @@ func TestUpdateAuthToken(t *testing.T) {
 	metadataAfterUpdate, _ := bundle.PerRPCCredentials().GetRequestMetadata(ctx)
 	assert.Equal(t, "abcdefg", metadataAfterUpdate[rpctypes.TokenFieldNameGRPC])
 }
+
+type fakeSecureConn struct {
+	net.Conn
+}
+
+func (fakeSecureConn) ConnectionState() tls.ConnectionState {
+	return tls.ConnectionState{Version: tls.VersionTLS13, NegotiatedProtocol: "etcd-grpc"}
+}
+
+func TestTransportCredentialSecureConn(t *testing.T) {
+	creds := NewTransportCredential(nil)
+	conn := fakeSecureConn{}
+
+	for _, handshake := range []func() (net.Conn, grpccredentials.AuthInfo, error){
+		func() (net.Conn, grpccredentials.AuthInfo, error) {
+			return creds.ClientHandshake(context.Background(), "localhost", conn)
+		},
+		func() (net.Conn, grpccredentials.AuthInfo, error) { return creds.ServerHandshake(conn) },
+		func() (net.Conn, grpccredentials.AuthInfo, error) { return creds.Clone().ServerHandshake(conn) },
+	} {
+		c, info, err := handshake()
+		require.NoError(t, err)
+		assert.Equal(t, conn, c)
+		tlsInfo, ok := info.(grpccredentials.TLSInfo)
+		require.True(t, ok)
+		assert.Equal(t, "etcd-grpc", tlsInfo.State.NegotiatedProtocol)
+		assert.Equal(t, grpccredentials.PrivacyAndIntegrity, tlsInfo.SecurityLevel)
+	}
+}
@@ -37,6 +37,88 @@ func WithRequireLeader(ctx context.Context) context.Context {
 	return metadata.NewOutgoingContext(ctx, copied)
 }
 
+// WithCallerLabel attributes the requests made with the returned context to
+// the given caller in the server's per-caller metrics, overriding the
+// CallerLabel of the client configuration.
+func WithCallerLabel(ctx context.Context, label string) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok { // no outgoing metadata ctx key, create one
+		md = metadata.Pairs(rpctypes.MetadataCallerKey, label)
+		return metadata.NewOutgoingContext(ctx, md)
+	}
+	copied := md.Copy() // avoid racey updates
+	// overwrite/add 'caller' key/value
+	copied.Set(rpctypes.MetadataCallerKey, label)
+	return metadata.NewOutgoingContext(ctx, copied)
+}
+
+// WithNamespace isolates the keys of the requests made with the returned
+// context in the given server-side namespace, overriding the Namespace of
+// the client configuration.
+func WithNamespace(ctx context.Context, namespace string) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok { // no outgoing metadata ctx key, create one
+		md = metadata.Pairs(rpctypes.MetadataNamespaceKey, namespace)
+		return metadata.NewOutgoingContext(ctx, md)
+	}
+	copied := md.Copy() // avoid racey updates
+	// overwrite/add 'namespace' key/value
+	copied.Set(rpctypes.MetadataNamespaceKey, namespace)
+	return metadata.NewOutgoingContext(ctx, copied)
+}
+
+// WithPriority schedules the requests made with the returned context in the
+// given QoS class of the server, one of rpctypes.PriorityCritical,
+// rpctypes.PriorityNormal and rpctypes.PriorityBackground. While overloaded,
+// the server serves the requests of the higher classes first.
+func WithPriority(ctx context.Context, priority string) context.Context {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok { // no outgoing metadata ctx key, create one
+		md = metadata.Pairs(rpctypes.MetadataPriorityKey, priority)
+		return metadata.NewOutgoingContext(ctx, md)
+	}
+	copied := md.Copy() // avoid racey updates
+	// overwrite/add 'priority' key/value
+	copied.Set(rpctypes.MetadataPriorityKey, priority)
+	return metadata.NewOutgoingContext(ctx, copied)
+}
+
+type retryPolicyKey struct{}
+
+// WithRetryPolicy overrides the retry policy of the client for the requests
+// made with the returned context. Zero fields of p keep the value of the
+// client's policy.
+func WithRetryPolicy(ctx context.Context, p RetryPolicy) context.Context {
+	return context.WithValue(ctx, retryPolicyKey{}, p)
+}
+
+func retryPolicyFromContext(ctx context.Context) (RetryPolicy, bool) {
+	p, ok := ctx.Value(retryPolicyKey{}).(RetryPolicy)
+	return p, ok
+}
+
+// embeds the default caller label unless the request already carries one
+func withCaller(ctx context.Context, label string) context.Context {
+	if label == "" {
+		return ctx
+	}
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(rpctypes.MetadataCallerKey)) > 0 {
+		return ctx
+	}
+	return WithCallerLabel(ctx, label)
+}
+
+// embeds the default namespace unless the request already carries one
+func withNamespace(ctx context.Context, namespace string) context.Context {
+	if namespace == "" {
+		return ctx
+	}
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(rpctypes.MetadataNamespaceKey)) > 0 {
+		return ctx
+	}
+	return WithNamespace(ctx, namespace)
+}
+
 // embeds client version
 func withVersion(ctx context.Context) context.Context {
 	md, ok := metadata.FromOutgoingContext(ctx)
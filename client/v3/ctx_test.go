@@ -56,3 +56,39 @@ func TestMetadataWithClientAPIVersion(t *testing.T) {
 	ss = md.Get(rpctypes.MetadataClientAPIVersionKey)
 	require.Truef(t, reflect.DeepEqual(ss, []string{version.APIVersion}), "unexpected metadata for %q %v", rpctypes.MetadataClientAPIVersionKey, ss)
 }
+
+func TestMetadataWithCaller(t *testing.T) {
+	ctx := withCaller(t.Context(), "")
+	_, ok := metadata.FromOutgoingContext(ctx)
+	require.Falsef(t, ok, "expected no outgoing metadata ctx key without a caller label")
+
+	ctx = withCaller(WithRequireLeader(t.Context()), "tenant-a")
+	md, ok := metadata.FromOutgoingContext(ctx)
+	require.Truef(t, ok, "expected outgoing metadata ctx key")
+	require.Equal(t, []string{"tenant-a"}, md.Get(rpctypes.MetadataCallerKey))
+	require.Equal(t, []string{rpctypes.MetadataHasLeader}, md.Get(rpctypes.MetadataRequireLeaderKey))
+
+	// the label set on the request wins over the client default
+	ctx = withCaller(WithCallerLabel(t.Context(), "tenant-b"), "tenant-a")
+	md, ok = metadata.FromOutgoingContext(ctx)
+	require.Truef(t, ok, "expected outgoing metadata ctx key")
+	require.Equal(t, []string{"tenant-b"}, md.Get(rpctypes.MetadataCallerKey))
+}
+
+func TestMetadataWithNamespace(t *testing.T) {
+	ctx := withNamespace(t.Context(), "")
+	_, ok := metadata.FromOutgoingContext(ctx)
+	require.Falsef(t, ok, "expected no outgoing metadata ctx key without a namespace")
+
+	ctx = withNamespace(WithRequireLeader(t.Context()), "team-a")
+	md, ok := metadata.FromOutgoingContext(ctx)
+	require.Truef(t, ok, "expected outgoing metadata ctx key")
+	require.Equal(t, []string{"team-a"}, md.Get(rpctypes.MetadataNamespaceKey))
+	require.Equal(t, []string{rpctypes.MetadataHasLeader}, md.Get(rpctypes.MetadataRequireLeaderKey))
+
+	// the namespace set on the request wins over the client default
+	ctx = withNamespace(WithNamespace(t.Context(), "team-b"), "team-a")
+	md, ok = metadata.FromOutgoingContext(ctx)
+	require.Truef(t, ok, "expected outgoing metadata ctx key")
+	require.Equal(t, []string{"team-b"}, md.Get(rpctypes.MetadataNamespaceKey))
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/client/v3/internal/endpoint"
+	"go.etcd.io/etcd/client/v3/internal/followerread"
+)
+
+// followerReadProber probes an endpoint with Status requests over its own
+// connection, so that the round trip time it measures is not delayed by the
+// requests queued on the balanced connection.
+type followerReadProber struct {
+	conn   *grpc.ClientConn
+	remote pb.MaintenanceClient
+}
+
+func (p *followerReadProber) Probe(ctx context.Context) (bool, error) {
+	resp, err := p.remote.Status(ctx, &pb.StatusRequest{})
+	if err != nil {
+		return false, err
+	}
+	return resp.Leader == resp.Header.MemberId, nil
+}
+
+func (p *followerReadProber) Close() error {
+	return p.conn.Close()
+}
+
+// dialFollowerReadProber returns the prober of the endpoint with the given
+// address for the follower read balancer.
+func (c *Client) dialFollowerReadProber(addr string) (followerread.Prober, error) {
+	for _, ep := range c.Endpoints() {
+		if a, _ := endpoint.Interpret(ep); a != addr {
+			continue
+		}
+		conn, err := c.Dial(ep)
+		if err != nil {
+			return nil, err
+		}
+		return &followerReadProber{conn: conn, remote: pb.NewMaintenanceClient(conn)}, nil
+	}
+	return nil, fmt.Errorf("unknown endpoint address %s", addr)
+}
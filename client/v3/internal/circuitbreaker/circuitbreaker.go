@@ -0,0 +1,325 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package circuitbreaker implements a gRPC balancer that balances requests
+// round robin across the endpoints, and ejects the endpoints whose requests
+// keep failing.
+//
+// An endpoint is ejected once FailureThreshold requests in a row failed with
+// Unavailable or DeadlineExceeded. It receives no request for Cooldown, then
+// a single probe request is sent to it: the endpoint is reinstated if the
+// probe succeeds, and ejected again otherwise. When every endpoint is ejected,
+// requests are balanced across all of them rather than failed.
+package circuitbreaker
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/roundrobin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/grpclog"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/serviceconfig"
+	"google.golang.org/grpc/status"
+)
+
+// Name is the name of the balancer.
+const Name = "etcd_circuit_breaker"
+
+const (
+	DefaultFailureThreshold = 5
+	DefaultCooldown         = 30 * time.Second
+)
+
+var logger = grpclog.Component("etcd-circuit-breaker")
+
+func init() {
+	balancer.Register(builder{})
+}
+
+// Config is the load balancing config of the balancer.
+type Config struct {
+	serviceconfig.LoadBalancingConfig `json:"-"`
+
+	// FailureThreshold is the number of consecutive failed requests that
+	// ejects an endpoint.
+	FailureThreshold uint `json:"failureThreshold"`
+	// Cooldown is how long an ejected endpoint receives no request before
+	// being probed.
+	Cooldown time.Duration `json:"cooldown"`
+}
+
+// ServiceConfig returns the service config selecting the balancer with the
+// given config.
+func ServiceConfig(cfg Config) (string, error) {
+	b, err := json.Marshal(map[string]any{
+		"loadBalancingConfig": []map[string]Config{{Name: cfg}},
+	})
+	return string(b), err
+}
+
+type builder struct{}
+
+func (builder) Name() string {
+	return Name
+}
+
+func (builder) ParseConfig(js json.RawMessage) (serviceconfig.LoadBalancingConfig, error) {
+	cfg := &Config{}
+	if err := json.Unmarshal(js, cfg); err != nil {
+		return nil, fmt.Errorf("%s: invalid config %s: %w", Name, js, err)
+	}
+	if cfg.FailureThreshold == 0 {
+		cfg.FailureThreshold = DefaultFailureThreshold
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = DefaultCooldown
+	}
+	return cfg, nil
+}
+
+func (builder) Build(cc balancer.ClientConn, opts balancer.BuildOptions) balancer.Balancer {
+	b := &cbBalancer{
+		cfg:      Config{FailureThreshold: DefaultFailureThreshold, Cooldown: DefaultCooldown},
+		now:      time.Now,
+		addrs:    make(map[balancer.SubConn]string),
+		breakers: make(map[string]*breaker),
+	}
+	b.Balancer = balancer.Get(roundrobin.Name).Build(&ccWrapper{ClientConn: cc, b: b}, opts)
+	return b
+}
+
+// cbBalancer wraps the round robin balancer, filtering the endpoints it
+// picks through their circuit breakers.
+type cbBalancer struct {
+	balancer.Balancer
+	now func() time.Time
+
+	mu  sync.Mutex
+	cfg Config
+	// addrs maps the SubConns to the address they connect to.
+	addrs map[balancer.SubConn]string
+	// breakers holds the circuit breakers of the addresses, kept across
+	// reconnections.
+	breakers map[string]*breaker
+}
+
+func (b *cbBalancer) UpdateClientConnState(s balancer.ClientConnState) error {
+	b.mu.Lock()
+	if cfg, ok := s.BalancerConfig.(*Config); ok {
+		b.cfg = *cfg
+	}
+	known := make(map[string]struct{})
+	for _, ep := range s.ResolverState.Endpoints {
+		for _, addr := range ep.Addresses {
+			known[addr.Addr] = struct{}{}
+		}
+	}
+	for addr := range b.breakers {
+		if _, ok := known[addr]; !ok {
+			delete(b.breakers, addr)
+		}
+	}
+	b.mu.Unlock()
+
+	s.BalancerConfig = nil
+	return b.Balancer.UpdateClientConnState(s)
+}
+
+func (b *cbBalancer) ExitIdle() {
+	if ei, ok := b.Balancer.(balancer.ExitIdler); ok {
+		ei.ExitIdle()
+	}
+}
+
+// breaker returns the circuit breaker of the address sc connects to, or nil
+// if unknown.
+func (b *cbBalancer) breaker(sc balancer.SubConn) *breaker {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	addr, ok := b.addrs[sc]
+	if !ok {
+		return nil
+	}
+	br, ok := b.breakers[addr]
+	if !ok {
+		br = &breaker{addr: addr}
+		b.breakers[addr] = br
+	}
+	return br
+}
+
+func (b *cbBalancer) config() Config {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.cfg
+}
+
+// ccWrapper tracks the SubConns created by the round robin balancer, and
+// wraps its pickers.
+type ccWrapper struct {
+	balancer.ClientConn
+	b *cbBalancer
+}
+
+func (w *ccWrapper) NewSubConn(addrs []resolver.Address, opts balancer.NewSubConnOptions) (balancer.SubConn, error) {
+	var sc balancer.SubConn
+	listener := opts.StateListener
+	opts.StateListener = func(s balancer.SubConnState) {
+		if s.ConnectivityState == connectivity.Shutdown {
+			w.b.mu.Lock()
+			delete(w.b.addrs, sc)
+			w.b.mu.Unlock()
+		}
+		if listener != nil {
+			listener(s)
+		}
+	}
+	sc, err := w.ClientConn.NewSubConn(addrs, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) > 0 {
+		w.b.mu.Lock()
+		w.b.addrs[sc] = addrs[0].Addr
+		w.b.mu.Unlock()
+	}
+	return sc, nil
+}
+
+func (w *ccWrapper) UpdateState(s balancer.State) {
+	s.Picker = &picker{Picker: s.Picker, b: w.b}
+	w.ClientConn.UpdateState(s)
+}
+
+// picker skips the ejected endpoints picked by the round robin picker.
+type picker struct {
+	balancer.Picker
+	b *cbBalancer
+}
+
+func (p *picker) Pick(info balancer.PickInfo) (balancer.PickResult, error) {
+	cfg := p.b.config()
+	p.b.mu.Lock()
+	maxPicks := len(p.b.addrs)
+	p.b.mu.Unlock()
+
+	var first balancer.PickResult
+	var firstBreaker *breaker
+	for i := 0; i <= maxPicks; i++ {
+		res, err := p.Picker.Pick(info)
+		if err != nil {
+			return res, err
+		}
+		br := p.b.breaker(res.SubConn)
+		if br == nil || br.allow(p.b.now(), cfg.Cooldown) {
+			return p.track(res, br), nil
+		}
+		if i == 0 {
+			first, firstBreaker = res, br
+		}
+	}
+	// every endpoint is ejected, fall back to round robin.
+	return p.track(first, firstBreaker), nil
+}
+
+// track records the outcome of the request in the circuit breaker.
+func (p *picker) track(res balancer.PickResult, br *breaker) balancer.PickResult {
+	if br == nil {
+		return res
+	}
+	done := res.Done
+	res.Done = func(info balancer.DoneInfo) {
+		br.record(info.Err, p.b.now(), p.b.config().FailureThreshold)
+		if done != nil {
+			done(info)
+		}
+	}
+	return res
+}
+
+type state int
+
+const (
+	closed state = iota
+	open
+	halfOpen
+)
+
+// breaker is the circuit breaker of an endpoint.
+type breaker struct {
+	addr string
+
+	mu       sync.Mutex
+	state    state
+	failures uint
+	openedAt time.Time
+	// probing is set while the probe request of a half open breaker is in
+	// flight.
+	probing bool
+}
+
+// allow tells whether a request can be sent to the endpoint.
+func (br *breaker) allow(now time.Time, cooldown time.Duration) bool {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	switch br.state {
+	case closed:
+		return true
+	case open:
+		if now.Sub(br.openedAt) < cooldown {
+			return false
+		}
+		br.state = halfOpen
+		br.probing = false
+	}
+	if br.probing {
+		return false
+	}
+	br.probing = true
+	return true
+}
+
+// record updates the breaker with the outcome of a request.
+func (br *breaker) record(err error, now time.Time, threshold uint) {
+	br.mu.Lock()
+	defer br.mu.Unlock()
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		br.failures++
+		if br.state == halfOpen || (br.state == closed && br.failures >= threshold) {
+			logger.Warningf("ejecting endpoint %s after %d failed requests, last error: %v", br.addr, br.failures, err)
+			br.state = open
+			br.openedAt = now
+			br.probing = false
+		}
+	case codes.Canceled:
+		// says nothing about the endpoint.
+		if br.state == halfOpen {
+			br.probing = false
+		}
+	default:
+		if br.state != closed {
+			logger.Infof("reinstating endpoint %s", br.addr)
+		}
+		br.state = closed
+		br.failures = 0
+		br.probing = false
+	}
+}
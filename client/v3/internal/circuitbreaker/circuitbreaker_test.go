@@ -0,0 +1,196 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circuitbreaker
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errUnavailable = status.Error(codes.Unavailable, "unavailable")
+	errNotFound    = status.Error(codes.NotFound, "not found")
+)
+
+func TestParseConfig(t *testing.T) {
+	sc, err := ServiceConfig(Config{FailureThreshold: 3, Cooldown: time.Second})
+	require.NoError(t, err)
+	var parsed struct {
+		LoadBalancingConfig []map[string]json.RawMessage `json:"loadBalancingConfig"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(sc), &parsed))
+	require.Len(t, parsed.LoadBalancingConfig, 1)
+
+	cfg, err := builder{}.ParseConfig(parsed.LoadBalancingConfig[0][Name])
+	require.NoError(t, err)
+	assert.Equal(t, &Config{FailureThreshold: 3, Cooldown: time.Second}, cfg)
+
+	cfg, err = builder{}.ParseConfig(json.RawMessage(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, &Config{FailureThreshold: DefaultFailureThreshold, Cooldown: DefaultCooldown}, cfg)
+
+	_, err = builder{}.ParseConfig(json.RawMessage(`{"cooldown": "soon"}`))
+	require.Error(t, err)
+}
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	cooldown := time.Second
+	br := &breaker{addr: "a"}
+
+	// failures below the threshold, or interleaved with successes, keep the
+	// endpoint.
+	br.record(errUnavailable, now, 3)
+	br.record(errUnavailable, now, 3)
+	br.record(errNotFound, now, 3)
+	br.record(errUnavailable, now, 3)
+	br.record(status.Error(codes.Canceled, "canceled"), now, 3)
+	br.record(errUnavailable, now, 3)
+	require.True(t, br.allow(now, cooldown))
+
+	br.record(status.Error(codes.DeadlineExceeded, "deadline exceeded"), now, 3)
+	require.False(t, br.allow(now, cooldown), "ejected")
+	require.False(t, br.allow(now.Add(cooldown/2), cooldown), "cooling down")
+
+	// a single probe is allowed after the cooldown, and its failure ejects
+	// the endpoint again.
+	now = now.Add(cooldown)
+	require.True(t, br.allow(now, cooldown), "probe")
+	require.False(t, br.allow(now, cooldown), "probe in flight")
+	br.record(errUnavailable, now, 3)
+	require.False(t, br.allow(now, cooldown), "ejected again")
+
+	// a canceled probe says nothing about the endpoint.
+	now = now.Add(cooldown)
+	require.True(t, br.allow(now, cooldown), "probe")
+	br.record(status.Error(codes.Canceled, "canceled"), now, 3)
+	require.True(t, br.allow(now, cooldown), "probe")
+
+	// a successful probe reinstates the endpoint.
+	br.record(nil, now, 3)
+	require.True(t, br.allow(now, cooldown))
+	require.True(t, br.allow(now, cooldown))
+}
+
+type fakeSubConn struct {
+	balancer.SubConn
+	addr string
+}
+
+// roundRobinPicker picks the SubConns in turn.
+type roundRobinPicker struct {
+	scs  []balancer.SubConn
+	next int
+}
+
+func (p *roundRobinPicker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	sc := p.scs[p.next%len(p.scs)]
+	p.next++
+	return balancer.PickResult{SubConn: sc}, nil
+}
+
+func TestPickerSkipsEjectedEndpoints(t *testing.T) {
+	now := time.Now()
+	b := &cbBalancer{
+		cfg:      Config{FailureThreshold: 2, Cooldown: time.Second},
+		now:      func() time.Time { return now },
+		addrs:    make(map[balancer.SubConn]string),
+		breakers: make(map[string]*breaker),
+	}
+	var scs []balancer.SubConn
+	for _, addr := range []string{"a", "b", "c"} {
+		sc := &fakeSubConn{addr: addr}
+		b.addrs[sc] = addr
+		scs = append(scs, sc)
+	}
+	p := &picker{Picker: &roundRobinPicker{scs: scs}, b: b}
+	pick := func() string {
+		res, err := p.Pick(balancer.PickInfo{})
+		require.NoError(t, err)
+		res.Done(balancer.DoneInfo{})
+		return res.SubConn.(*fakeSubConn).addr
+	}
+
+	// "b" fails twice and gets ejected.
+	var picked []string
+	for i := 0; i < 6; i++ {
+		res, err := p.Pick(balancer.PickInfo{})
+		require.NoError(t, err)
+		addr := res.SubConn.(*fakeSubConn).addr
+		if addr == "b" {
+			res.Done(balancer.DoneInfo{Err: errUnavailable})
+		} else {
+			res.Done(balancer.DoneInfo{})
+		}
+		picked = append(picked, addr)
+	}
+	assert.Equal(t, []string{"a", "b", "c", "a", "b", "c"}, picked)
+	for i := 0; i < 4; i++ {
+		assert.NotEqual(t, "b", pick())
+	}
+
+	// after the cooldown "b" is probed, and reinstated once the probe
+	// succeeds.
+	now = now.Add(time.Second)
+	picked = nil
+	for i := 0; i < 6; i++ {
+		picked = append(picked, pick())
+	}
+	assert.Equal(t, 2, count(picked, "b"))
+}
+
+func TestPickerFailsOpen(t *testing.T) {
+	now := time.Now()
+	b := &cbBalancer{
+		cfg:      Config{FailureThreshold: 1, Cooldown: time.Minute},
+		now:      func() time.Time { return now },
+		addrs:    make(map[balancer.SubConn]string),
+		breakers: make(map[string]*breaker),
+	}
+	a, c := &fakeSubConn{addr: "a"}, &fakeSubConn{addr: "c"}
+	b.addrs[a], b.addrs[c] = "a", "c"
+	p := &picker{Picker: &roundRobinPicker{scs: []balancer.SubConn{a, c}}, b: b}
+
+	for i := 0; i < 2; i++ {
+		res, err := p.Pick(balancer.PickInfo{})
+		require.NoError(t, err)
+		res.Done(balancer.DoneInfo{Err: errUnavailable})
+	}
+
+	// every endpoint is ejected, requests still go through.
+	res, err := p.Pick(balancer.PickInfo{})
+	require.NoError(t, err)
+	require.NotNil(t, res.SubConn)
+	// and a success reinstates the endpoint.
+	res.Done(balancer.DoneInfo{})
+	assert.True(t, b.breaker(res.SubConn).allow(now, time.Minute))
+}
+
+func count(s []string, v string) int {
+	n := 0
+	for _, e := range s {
+		if e == v {
+			n++
+		}
+	}
+	return n
+}
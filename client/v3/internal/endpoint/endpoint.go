@@ -53,7 +53,7 @@ func mustSplit2(s, sep string) (string, string) {
 
 func schemeToCredsRequirement(schema string) CredsRequirement {
 	switch schema {
-	case "https", "unixs":
+	case "https", "unixs", "quic":
 		return CredsRequire
 	case "http":
 		return CredsDrop
@@ -117,6 +117,11 @@ func translateEndpoint(ep string) (addr string, serverName string, requireCreds
 	return ep, ep, CredsOptional
 }
 
+// IsQUIC returns whether given endpoint is dialed with QUIC.
+func IsQUIC(ep string) bool {
+	return strings.HasPrefix(ep, "quic://")
+}
+
 // RequiresCredentials returns whether given endpoint requires
 // credentials/certificates for connection.
 func RequiresCredentials(ep string) CredsRequirement {
@@ -45,6 +45,7 @@ func Test_interpret(t *testing.T) {
 		{"https://127.0.0.1", "127.0.0.1", "127.0.0.1", CredsRequire},
 		{"https://127.0.0.1:8080", "127.0.0.1:8080", "127.0.0.1:8080", CredsRequire},
 		{"https://localhost:20000", "localhost:20000", "localhost:20000", CredsRequire},
+		{"quic://127.0.0.1:8080", "quic://127.0.0.1:8080", "127.0.0.1:8080", CredsRequire},
 
 		{"unix:///tmp/abc", "unix:///tmp/abc", "abc", CredsOptional},
 		{"unixs:///tmp/abc", "unix:///tmp/abc", "abc", CredsRequire},
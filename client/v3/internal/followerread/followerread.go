@@ -0,0 +1,418 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package followerread implements a gRPC balancer that routes the serializable
+// reads to the endpoint with the lowest round trip time, and the writes and
+// linearizable reads to the leader.
+//
+// The balancer probes every endpoint each ProbeInterval with the Prober the
+// client provides through the resolver state, measuring its round trip time
+// and whether it is the leader. UnaryClientInterceptor tells the balancer the
+// route of each request. The other requests, and the requests whose endpoint
+// is not known yet, are balanced round robin.
+package followerread
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/attributes"
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/roundrobin"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/grpclog"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/serviceconfig"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+// Name is the name of the balancer.
+const Name = "etcd_follower_read"
+
+const DefaultProbeInterval = 5 * time.Second
+
+var logger = grpclog.Component("etcd-follower-read")
+
+func init() {
+	balancer.Register(builder{})
+}
+
+// Config is the load balancing config of the balancer.
+type Config struct {
+	serviceconfig.LoadBalancingConfig `json:"-"`
+
+	// ProbeInterval is the interval between two probes of an endpoint.
+	ProbeInterval time.Duration `json:"probeInterval"`
+}
+
+// ServiceConfig returns the service config selecting the balancer with the
+// given config.
+func ServiceConfig(cfg Config) (string, error) {
+	b, err := json.Marshal(map[string]any{
+		"loadBalancingConfig": []map[string]Config{{Name: cfg}},
+	})
+	return string(b), err
+}
+
+// Prober probes an endpoint.
+type Prober interface {
+	// Probe tells whether the endpoint is the leader.
+	Probe(ctx context.Context) (leader bool, err error)
+	Close() error
+}
+
+// DialFunc returns the prober of the endpoint with the given address.
+type DialFunc func(addr string) (Prober, error)
+
+type dialFuncKey struct{}
+
+// dialer holds the DialFunc in the attributes, whose values have to be
+// comparable.
+type dialer struct {
+	dial DialFunc
+}
+
+// WithDialFunc returns the resolver attributes providing the balancer with
+// the dial function of the probers.
+func WithDialFunc(a *attributes.Attributes, dial DialFunc) *attributes.Attributes {
+	return a.WithValue(dialFuncKey{}, &dialer{dial: dial})
+}
+
+type route int
+
+const (
+	routeAny route = iota
+	routeLeader
+	routeNearest
+)
+
+type routeKey struct{}
+
+// UnaryClientInterceptor tells the balancer the route of the KV and lease
+// requests: the serializable reads go to the nearest endpoint, the other KV
+// requests and the lease grants and revokes to the leader.
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if r := routeOf(req); r != routeAny {
+		ctx = context.WithValue(ctx, routeKey{}, r)
+	}
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+func routeOf(req any) route {
+	switch r := req.(type) {
+	case *pb.RangeRequest:
+		if r.Serializable {
+			return routeNearest
+		}
+		return routeLeader
+	case *pb.TxnRequest:
+		if isTxnSerializable(r) {
+			return routeNearest
+		}
+		return routeLeader
+	case *pb.PutRequest, *pb.DeleteRangeRequest, *pb.CompactionRequest, *pb.LeaseGrantRequest, *pb.LeaseRevokeRequest:
+		return routeLeader
+	}
+	return routeAny
+}
+
+// isTxnSerializable tells whether the txn only has serializable ranges, which
+// the server serves without a linearizable read.
+func isTxnSerializable(r *pb.TxnRequest) bool {
+	for _, ops := range [][]*pb.RequestOp{r.Success, r.Failure} {
+		for _, op := range ops {
+			if rr := op.GetRequestRange(); rr == nil || !rr.Serializable {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+type builder struct{}
+
+func (builder) Name() string {
+	return Name
+}
+
+func (builder) ParseConfig(js json.RawMessage) (serviceconfig.LoadBalancingConfig, error) {
+	cfg := &Config{}
+	if err := json.Unmarshal(js, cfg); err != nil {
+		return nil, fmt.Errorf("%s: invalid config %s: %w", Name, js, err)
+	}
+	if cfg.ProbeInterval <= 0 {
+		cfg.ProbeInterval = DefaultProbeInterval
+	}
+	return cfg, nil
+}
+
+func (builder) Build(cc balancer.ClientConn, opts balancer.BuildOptions) balancer.Balancer {
+	b := &frBalancer{
+		cfg:    Config{ProbeInterval: DefaultProbeInterval},
+		addrs:  make(map[balancer.SubConn]string),
+		probes: make(map[string]*probe),
+	}
+	b.Balancer = balancer.Get(roundrobin.Name).Build(&ccWrapper{ClientConn: cc, b: b}, opts)
+	return b
+}
+
+// frBalancer wraps the round robin balancer, picking among its endpoints
+// according to the route of the requests.
+type frBalancer struct {
+	balancer.Balancer
+
+	mu   sync.Mutex
+	cfg  Config
+	dial DialFunc
+	// addrs maps the SubConns to the address they connect to.
+	addrs map[balancer.SubConn]string
+	// probes holds the probes of the addresses, kept across reconnections.
+	probes map[string]*probe
+}
+
+func (b *frBalancer) UpdateClientConnState(s balancer.ClientConnState) error {
+	b.mu.Lock()
+	if cfg, ok := s.BalancerConfig.(*Config); ok {
+		b.cfg = *cfg
+	}
+	if d, ok := s.ResolverState.Attributes.Value(dialFuncKey{}).(*dialer); ok {
+		b.dial = d.dial
+	}
+	known := make(map[string]struct{})
+	for _, ep := range s.ResolverState.Endpoints {
+		for _, addr := range ep.Addresses {
+			known[addr.Addr] = struct{}{}
+			if _, ok := b.probes[addr.Addr]; !ok && b.dial != nil {
+				p := newProbe(addr.Addr)
+				b.probes[addr.Addr] = p
+				go p.run(b.dial, b.probeInterval)
+			}
+		}
+	}
+	for addr, p := range b.probes {
+		if _, ok := known[addr]; !ok {
+			p.stop()
+			delete(b.probes, addr)
+		}
+	}
+	b.mu.Unlock()
+
+	s.BalancerConfig = nil
+	return b.Balancer.UpdateClientConnState(s)
+}
+
+func (b *frBalancer) ExitIdle() {
+	if ei, ok := b.Balancer.(balancer.ExitIdler); ok {
+		ei.ExitIdle()
+	}
+}
+
+func (b *frBalancer) Close() {
+	b.mu.Lock()
+	for addr, p := range b.probes {
+		p.stop()
+		delete(b.probes, addr)
+	}
+	b.mu.Unlock()
+	b.Balancer.Close()
+}
+
+func (b *frBalancer) probeInterval() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.cfg.ProbeInterval
+}
+
+// probeResult returns the last probe result of the address sc connects to.
+func (b *frBalancer) probeResult(sc balancer.SubConn) (rtt time.Duration, leader bool) {
+	b.mu.Lock()
+	p, ok := b.probes[b.addrs[sc]]
+	b.mu.Unlock()
+	if !ok {
+		return 0, false
+	}
+	return p.result()
+}
+
+// ccWrapper tracks the SubConns created by the round robin balancer, and
+// wraps its pickers.
+type ccWrapper struct {
+	balancer.ClientConn
+	b *frBalancer
+}
+
+func (w *ccWrapper) NewSubConn(addrs []resolver.Address, opts balancer.NewSubConnOptions) (balancer.SubConn, error) {
+	var sc balancer.SubConn
+	listener := opts.StateListener
+	opts.StateListener = func(s balancer.SubConnState) {
+		if s.ConnectivityState == connectivity.Shutdown {
+			w.b.mu.Lock()
+			delete(w.b.addrs, sc)
+			w.b.mu.Unlock()
+		}
+		if listener != nil {
+			listener(s)
+		}
+	}
+	sc, err := w.ClientConn.NewSubConn(addrs, opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) > 0 {
+		w.b.mu.Lock()
+		w.b.addrs[sc] = addrs[0].Addr
+		w.b.mu.Unlock()
+	}
+	return sc, nil
+}
+
+func (w *ccWrapper) UpdateState(s balancer.State) {
+	s.Picker = &picker{Picker: s.Picker, b: w.b}
+	w.ClientConn.UpdateState(s)
+}
+
+// picker picks, among the endpoints picked by the round robin picker, the
+// leader or the nearest endpoint according to the route of the request.
+type picker struct {
+	balancer.Picker
+	b *frBalancer
+}
+
+func (p *picker) Pick(info balancer.PickInfo) (balancer.PickResult, error) {
+	r := routeAny
+	if info.Ctx != nil {
+		r, _ = info.Ctx.Value(routeKey{}).(route)
+	}
+	if r == routeAny {
+		return p.Picker.Pick(info)
+	}
+	p.b.mu.Lock()
+	maxPicks := len(p.b.addrs)
+	p.b.mu.Unlock()
+
+	var nearest balancer.PickResult
+	var nearestRTT time.Duration
+	for i := 0; i < maxPicks; i++ {
+		res, err := p.Picker.Pick(info)
+		if err != nil {
+			return res, err
+		}
+		rtt, leader := p.b.probeResult(res.SubConn)
+		if r == routeLeader && leader {
+			return res, nil
+		}
+		if r == routeNearest && rtt > 0 && (nearest.SubConn == nil || rtt < nearestRTT) {
+			nearest, nearestRTT = res, rtt
+		}
+	}
+	if nearest.SubConn != nil {
+		return nearest, nil
+	}
+	// the leader or the round trip times are not known yet, fall back to
+	// round robin.
+	return p.Picker.Pick(info)
+}
+
+// probe periodically probes an endpoint.
+type probe struct {
+	addr   string
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu sync.Mutex
+	// rtt is the smoothed round trip time of the endpoint, 0 until measured
+	// or after a failed probe.
+	rtt    time.Duration
+	leader bool
+	// warm is set once the connection of the prober is established, so that
+	// its setup is not taken for the round trip time.
+	warm bool
+}
+
+func newProbe(addr string) *probe {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &probe{addr: addr, ctx: ctx, cancel: cancel}
+}
+
+func (p *probe) run(dial DialFunc, interval func() time.Duration) {
+	var pr Prober
+	defer func() {
+		if pr != nil {
+			pr.Close()
+		}
+	}()
+	for {
+		if pr == nil {
+			var err error
+			if pr, err = dial(p.addr); err != nil {
+				logger.Warningf("failed to dial the prober of endpoint %s: %v", p.addr, err)
+			}
+		}
+		if pr != nil && p.probeOnce(pr, interval()) {
+			// measure the round trip time right away.
+			continue
+		}
+		select {
+		case <-time.After(interval()):
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+// probeOnce probes the endpoint, and returns true if the probe only
+// established the connection of the prober.
+func (p *probe) probeOnce(pr Prober, timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(p.ctx, timeout)
+	defer cancel()
+	start := time.Now()
+	leader, err := pr.Probe(ctx)
+	rtt := time.Since(start)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err != nil {
+		if p.ctx.Err() == nil {
+			logger.Infof("failed to probe endpoint %s: %v", p.addr, err)
+		}
+		p.rtt, p.leader, p.warm = 0, false, false
+		return false
+	}
+	p.leader = leader
+	switch {
+	case !p.warm:
+		p.warm = true
+		return true
+	case p.rtt == 0:
+		p.rtt = rtt
+	default:
+		p.rtt = (3*p.rtt + rtt) / 4
+	}
+	return false
+}
+
+func (p *probe) result() (rtt time.Duration, leader bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.rtt, p.leader
+}
+
+func (p *probe) stop() {
+	p.cancel()
+}
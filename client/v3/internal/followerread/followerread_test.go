@@ -0,0 +1,164 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package followerread
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/balancer"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+func TestParseConfig(t *testing.T) {
+	sc, err := ServiceConfig(Config{ProbeInterval: time.Second})
+	require.NoError(t, err)
+	var parsed struct {
+		LoadBalancingConfig []map[string]json.RawMessage `json:"loadBalancingConfig"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(sc), &parsed))
+	require.Len(t, parsed.LoadBalancingConfig, 1)
+
+	cfg, err := builder{}.ParseConfig(parsed.LoadBalancingConfig[0][Name])
+	require.NoError(t, err)
+	assert.Equal(t, &Config{ProbeInterval: time.Second}, cfg)
+
+	cfg, err = builder{}.ParseConfig(json.RawMessage(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, &Config{ProbeInterval: DefaultProbeInterval}, cfg)
+
+	_, err = builder{}.ParseConfig(json.RawMessage(`{"probeInterval": "often"}`))
+	require.Error(t, err)
+}
+
+func TestRouteOf(t *testing.T) {
+	serializableRange := &pb.RequestOp{Request: &pb.RequestOp_RequestRange{RequestRange: &pb.RangeRequest{Serializable: true}}}
+	put := &pb.RequestOp{Request: &pb.RequestOp_RequestPut{RequestPut: &pb.PutRequest{}}}
+	tests := []struct {
+		name string
+		req  any
+		want route
+	}{
+		{"serializable range", &pb.RangeRequest{Serializable: true}, routeNearest},
+		{"linearizable range", &pb.RangeRequest{}, routeLeader},
+		{"put", &pb.PutRequest{}, routeLeader},
+		{"delete", &pb.DeleteRangeRequest{}, routeLeader},
+		{"serializable txn", &pb.TxnRequest{Success: []*pb.RequestOp{serializableRange}}, routeNearest},
+		{"txn with a write", &pb.TxnRequest{Success: []*pb.RequestOp{serializableRange}, Failure: []*pb.RequestOp{put}}, routeLeader},
+		{"lease grant", &pb.LeaseGrantRequest{}, routeLeader},
+		{"member list", &pb.MemberListRequest{}, routeAny},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, routeOf(tt.req))
+		})
+	}
+}
+
+type fakeSubConn struct {
+	balancer.SubConn
+	addr string
+}
+
+// roundRobinPicker picks the SubConns in turn.
+type roundRobinPicker struct {
+	scs  []balancer.SubConn
+	next int
+}
+
+func (p *roundRobinPicker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	sc := p.scs[p.next%len(p.scs)]
+	p.next++
+	return balancer.PickResult{SubConn: sc}, nil
+}
+
+func TestPicker(t *testing.T) {
+	b := &frBalancer{
+		addrs:  make(map[balancer.SubConn]string),
+		probes: make(map[string]*probe),
+	}
+	var scs []balancer.SubConn
+	for _, addr := range []string{"a", "b", "c"} {
+		sc := &fakeSubConn{addr: addr}
+		b.addrs[sc] = addr
+		b.probes[addr] = newProbe(addr)
+		scs = append(scs, sc)
+	}
+	p := &picker{Picker: &roundRobinPicker{scs: scs}, b: b}
+	pick := func(r route) string {
+		info := balancer.PickInfo{Ctx: context.WithValue(context.Background(), routeKey{}, r)}
+		res, err := p.Pick(info)
+		require.NoError(t, err)
+		return res.SubConn.(*fakeSubConn).addr
+	}
+
+	// nothing is known yet, the requests are balanced round robin.
+	assert.Equal(t, []string{"a", "b", "c"}, []string{pick(routeLeader), pick(routeNearest), pick(routeAny)})
+
+	b.probes["a"].rtt = 30 * time.Millisecond
+	b.probes["b"].rtt, b.probes["b"].leader = 50*time.Millisecond, true
+	b.probes["c"].rtt = 10 * time.Millisecond
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, "b", pick(routeLeader))
+		assert.Equal(t, "c", pick(routeNearest))
+	}
+	var picked []string
+	for i := 0; i < 3; i++ {
+		picked = append(picked, pick(routeAny))
+	}
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, picked)
+}
+
+type fakeProber struct {
+	leader bool
+	err    error
+	delay  time.Duration
+}
+
+func (p *fakeProber) Probe(ctx context.Context) (bool, error) {
+	time.Sleep(p.delay)
+	return p.leader, p.err
+}
+
+func (p *fakeProber) Close() error { return nil }
+
+func TestProbe(t *testing.T) {
+	p := newProbe("a")
+	defer p.stop()
+	pr := &fakeProber{leader: true, delay: 10 * time.Millisecond}
+
+	// the first probe establishes the connection.
+	require.True(t, p.probeOnce(pr, time.Second))
+	rtt, leader := p.result()
+	assert.Zero(t, rtt)
+	assert.True(t, leader)
+
+	require.False(t, p.probeOnce(pr, time.Second))
+	rtt, _ = p.result()
+	assert.GreaterOrEqual(t, rtt, 10*time.Millisecond)
+
+	// a failed probe forgets the endpoint.
+	pr.err = errors.New("unavailable")
+	require.False(t, p.probeOnce(pr, time.Second))
+	rtt, leader = p.result()
+	assert.Zero(t, rtt)
+	assert.False(t, leader)
+}
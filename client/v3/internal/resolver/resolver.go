@@ -15,6 +15,7 @@
 package resolver
 
 import (
+	"google.golang.org/grpc/attributes"
 	"google.golang.org/grpc/resolver"
 	"google.golang.org/grpc/resolver/manual"
 	"google.golang.org/grpc/serviceconfig"
@@ -24,24 +25,34 @@ import (
 
 const (
 	Schema = "etcd-endpoints"
+
+	defaultServiceConfig = `{"loadBalancingPolicy": "round_robin"}`
 )
 
 // EtcdManualResolver is a Resolver (and resolver.Builder) that can be updated
 // using SetEndpoints.
 type EtcdManualResolver struct {
 	*manual.Resolver
-	endpoints     []string
-	serviceConfig *serviceconfig.ParseResult
+	endpoints         []string
+	serviceConfigJSON string
+	serviceConfig     *serviceconfig.ParseResult
+	attributes        *attributes.Attributes
 }
 
 func New(endpoints ...string) *EtcdManualResolver {
+	return NewWithServiceConfig(defaultServiceConfig, endpoints...)
+}
+
+// NewWithServiceConfig returns a resolver providing the given service config
+// instead of the default round robin balancing.
+func NewWithServiceConfig(serviceConfig string, endpoints ...string) *EtcdManualResolver {
 	r := manual.NewBuilderWithScheme(Schema)
-	return &EtcdManualResolver{Resolver: r, endpoints: endpoints, serviceConfig: nil}
+	return &EtcdManualResolver{Resolver: r, endpoints: endpoints, serviceConfigJSON: serviceConfig, serviceConfig: nil}
 }
 
 // Build returns itself for Resolver, because it's both a builder and a resolver.
 func (r *EtcdManualResolver) Build(target resolver.Target, cc resolver.ClientConn, opts resolver.BuildOptions) (resolver.Resolver, error) {
-	r.serviceConfig = cc.ParseServiceConfig(`{"loadBalancingPolicy": "round_robin"}`)
+	r.serviceConfig = cc.ParseServiceConfig(r.serviceConfigJSON)
 	if r.serviceConfig.Err != nil {
 		return nil, r.serviceConfig.Err
 	}
@@ -54,6 +65,13 @@ func (r *EtcdManualResolver) Build(target resolver.Target, cc resolver.ClientCon
 	return res, nil
 }
 
+// SetAttributes sets the attributes passed along with the endpoints to the
+// balancer.
+func (r *EtcdManualResolver) SetAttributes(a *attributes.Attributes) {
+	r.attributes = a
+	r.updateState()
+}
+
 func (r *EtcdManualResolver) SetEndpoints(endpoints []string) {
 	r.endpoints = endpoints
 	r.updateState()
@@ -71,6 +89,7 @@ func (r EtcdManualResolver) updateState() {
 		state := resolver.State{
 			Endpoints:     eps,
 			ServiceConfig: r.serviceConfig,
+			Attributes:    r.attributes,
 		}
 		r.UpdateState(state)
 	}
@@ -61,12 +61,43 @@ type LeaseTimeToLiveResponse struct {
 
 	// Keys is the list of keys attached to this lease.
 	Keys [][]byte `json:"keys"`
+
+	// Metadata is the metadata the lease was granted with.
+	Metadata []byte `json:"metadata,omitempty"`
+}
+
+// LeaseGrantBatchResponse wraps the protobuf message LeaseGrantBatchResponse.
+type LeaseGrantBatchResponse struct {
+	*pb.ResponseHeader
+	// Leases are the granted leases, in the order of the requested TTLs. The
+	// Error of a lease that could not be granted is set.
+	Leases []LeaseGrantResponse
+}
+
+// LeaseRevokeBatchResponse wraps the protobuf message LeaseRevokeBatchResponse.
+type LeaseRevokeBatchResponse struct {
+	*pb.ResponseHeader
+	// NotFound are the requested leases that did not exist.
+	NotFound []LeaseID
+}
+
+// LeaseKeepAliveBatchResponse wraps the protobuf message LeaseKeepAliveBatchResponse.
+type LeaseKeepAliveBatchResponse struct {
+	*pb.ResponseHeader
+	// Leases are the kept alive leases, in the requested order. The TTL of a
+	// lease that did not exist is 0.
+	Leases []LeaseKeepAliveResponse
 }
 
 // LeaseStatus represents a lease status.
 type LeaseStatus struct {
 	ID LeaseID `json:"id"`
 	// TODO: TTL int64
+
+	// GrantedTTL is the TTL in seconds the lease was granted with.
+	GrantedTTL int64 `json:"granted-ttl"`
+	// Metadata is the metadata the lease was granted with.
+	Metadata []byte `json:"metadata,omitempty"`
 }
 
 // LeaseLeasesResponse wraps the protobuf message LeaseLeasesResponse.
@@ -107,8 +138,9 @@ func (e ErrKeepAliveHalted) Error() string {
 }
 
 type Lease interface {
-	// Grant creates a new lease.
-	Grant(ctx context.Context, ttl int64) (*LeaseGrantResponse, error)
+	// Grant creates a new lease. WithLeaseMetadata stores metadata with it,
+	// supported since etcd 3.7.
+	Grant(ctx context.Context, ttl int64, opts ...LeaseOption) (*LeaseGrantResponse, error)
 
 	// Revoke revokes the given lease.
 	Revoke(ctx context.Context, id LeaseID) (*LeaseRevokeResponse, error)
@@ -116,8 +148,9 @@ type Lease interface {
 	// TimeToLive retrieves the lease information of the given lease ID.
 	TimeToLive(ctx context.Context, id LeaseID, opts ...LeaseOption) (*LeaseTimeToLiveResponse, error)
 
-	// Leases retrieves all leases.
-	Leases(ctx context.Context) (*LeaseLeasesResponse, error)
+	// Leases retrieves all leases, or those selected by WithMetadataPrefix and
+	// WithGrantedTTLRange, supported since etcd 3.7.
+	Leases(ctx context.Context, opts ...LeaseOption) (*LeaseLeasesResponse, error)
 
 	// KeepAlive attempts to keep the given lease alive forever. If the keepalive responses posted
 	// to the channel are not consumed promptly the channel may become full. When full, the lease
@@ -143,6 +176,21 @@ type Lease interface {
 	// In most of the cases, Keepalive should be used instead of KeepAliveOnce.
 	KeepAliveOnce(ctx context.Context, id LeaseID) (*LeaseKeepAliveResponse, error)
 
+	// GrantBatch creates a lease for each of the given TTLs in one round trip.
+	// The leases failing on their own, e.g. for a too large TTL, report it in
+	// their Error. If the request fails as a whole, the server revokes the
+	// leases it already granted. Supported since etcd 3.7.
+	GrantBatch(ctx context.Context, ttls []int64) (*LeaseGrantBatchResponse, error)
+
+	// RevokeBatch revokes the given leases in one round trip.
+	// Supported since etcd 3.7.
+	RevokeBatch(ctx context.Context, ids []LeaseID) (*LeaseRevokeBatchResponse, error)
+
+	// KeepAliveBatch renews the given leases once in one round trip, without
+	// the retries of KeepAliveOnce.
+	// Supported since etcd 3.7.
+	KeepAliveBatch(ctx context.Context, ids []LeaseID) (*LeaseKeepAliveBatchResponse, error)
+
 	// Close releases all resources Lease keeps for efficient communication
 	// with the etcd server.
 	Close() error
@@ -212,8 +260,8 @@ func NewLeaseFromLeaseClient(remote pb.LeaseClient, c *Client, keepAliveTimeout
 	return l
 }
 
-func (l *lessor) Grant(ctx context.Context, ttl int64) (*LeaseGrantResponse, error) {
-	r := &pb.LeaseGrantRequest{TTL: ttl}
+func (l *lessor) Grant(ctx context.Context, ttl int64, opts ...LeaseOption) (*LeaseGrantResponse, error) {
+	r := toLeaseGrantRequest(ttl, opts...)
 	resp, err := l.remote.LeaseGrant(ctx, r, l.callOpts...)
 	if err == nil {
 		gresp := &LeaseGrantResponse{
@@ -248,22 +296,71 @@ func (l *lessor) TimeToLive(ctx context.Context, id LeaseID, opts ...LeaseOption
 		TTL:            resp.TTL,
 		GrantedTTL:     resp.GrantedTTL,
 		Keys:           resp.Keys,
+		Metadata:       resp.Metadata,
 	}
 	return gresp, nil
 }
 
-func (l *lessor) Leases(ctx context.Context) (*LeaseLeasesResponse, error) {
-	resp, err := l.remote.LeaseLeases(ctx, &pb.LeaseLeasesRequest{}, l.callOpts...)
+func (l *lessor) Leases(ctx context.Context, opts ...LeaseOption) (*LeaseLeasesResponse, error) {
+	resp, err := l.remote.LeaseLeases(ctx, toLeaseLeasesRequest(opts...), l.callOpts...)
 	if err == nil {
 		leases := make([]LeaseStatus, len(resp.Leases))
-		for i := range resp.Leases {
-			leases[i] = LeaseStatus{ID: LeaseID(resp.Leases[i].ID)}
+		for i, ls := range resp.Leases {
+			leases[i] = LeaseStatus{ID: LeaseID(ls.ID), GrantedTTL: ls.GrantedTTL, Metadata: ls.Metadata}
 		}
 		return &LeaseLeasesResponse{ResponseHeader: resp.GetHeader(), Leases: leases}, nil
 	}
 	return nil, ContextError(ctx, err)
 }
 
+func (l *lessor) GrantBatch(ctx context.Context, ttls []int64) (*LeaseGrantBatchResponse, error) {
+	r := &pb.LeaseGrantBatchRequest{Requests: make([]*pb.LeaseGrantRequest, len(ttls))}
+	for i, ttl := range ttls {
+		r.Requests[i] = &pb.LeaseGrantRequest{TTL: ttl}
+	}
+	resp, err := l.remote.LeaseGrantBatch(ctx, r, l.callOpts...)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	leases := make([]LeaseGrantResponse, len(resp.Responses))
+	for i, gr := range resp.Responses {
+		leases[i] = LeaseGrantResponse{ResponseHeader: resp.GetHeader(), ID: LeaseID(gr.ID), TTL: gr.TTL, Error: gr.Error}
+	}
+	return &LeaseGrantBatchResponse{ResponseHeader: resp.GetHeader(), Leases: leases}, nil
+}
+
+func (l *lessor) RevokeBatch(ctx context.Context, ids []LeaseID) (*LeaseRevokeBatchResponse, error) {
+	r := &pb.LeaseRevokeBatchRequest{IDs: make([]int64, len(ids))}
+	for i, id := range ids {
+		r.IDs[i] = int64(id)
+	}
+	resp, err := l.remote.LeaseRevokeBatch(ctx, r, l.callOpts...)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	var notFound []LeaseID
+	for _, id := range resp.NotFound {
+		notFound = append(notFound, LeaseID(id))
+	}
+	return &LeaseRevokeBatchResponse{ResponseHeader: resp.GetHeader(), NotFound: notFound}, nil
+}
+
+func (l *lessor) KeepAliveBatch(ctx context.Context, ids []LeaseID) (*LeaseKeepAliveBatchResponse, error) {
+	r := &pb.LeaseKeepAliveBatchRequest{IDs: make([]int64, len(ids))}
+	for i, id := range ids {
+		r.IDs[i] = int64(id)
+	}
+	resp, err := l.remote.LeaseKeepAliveBatch(ctx, r, l.callOpts...)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	leases := make([]LeaseKeepAliveResponse, len(resp.Responses))
+	for i, kr := range resp.Responses {
+		leases[i] = LeaseKeepAliveResponse{ResponseHeader: resp.GetHeader(), ID: LeaseID(kr.ID), TTL: kr.TTL}
+	}
+	return &LeaseKeepAliveBatchResponse{ResponseHeader: resp.GetHeader(), Leases: leases}, nil
+}
+
 // To identify the context passed to `KeepAlive`, a key/value pair is
 // attached to the context. The key is a `keepAliveCtxKey` object, and
 // the value is the pointer to the context object itself, ensuring
@@ -34,7 +34,9 @@ func compareInt64(a, b int64) int {
 
 func evalCmp(resp *v3.GetResponse, tcmp v3.Cmp) bool {
 	var result int
-	if len(resp.Kvs) != 0 {
+	if tv, _ := tcmp.TargetUnion.(*v3pb.Compare_Count); tv != nil {
+		result = compareInt64(int64(len(resp.Kvs)), tv.Count)
+	} else if len(resp.Kvs) != 0 {
 		kv := resp.Kvs[0]
 		switch tcmp.Target {
 		case v3pb.Compare_VALUE:
@@ -53,6 +55,10 @@ func evalCmp(resp *v3.GetResponse, tcmp v3.Cmp) bool {
 			if tv, _ := tcmp.TargetUnion.(*v3pb.Compare_Version); tv != nil {
 				result = compareInt64(kv.Version, tv.Version)
 			}
+		case v3pb.Compare_VALUE_PREFIX:
+			if tv, _ := tcmp.TargetUnion.(*v3pb.Compare_ValuePrefix); tv != nil {
+				result = bytes.Compare(kv.Value[:min(len(kv.Value), len(tv.ValuePrefix))], tv.ValuePrefix)
+			}
 		}
 	}
 	switch tcmp.Result {
@@ -27,21 +27,37 @@ import (
 )
 
 type (
-	DefragmentResponse pb.DefragmentResponse
-	AlarmResponse      pb.AlarmResponse
-	AlarmMember        pb.AlarmMember
-	StatusResponse     pb.StatusResponse
-	HashKVResponse     pb.HashKVResponse
-	MoveLeaderResponse pb.MoveLeaderResponse
-	DowngradeResponse  pb.DowngradeResponse
+	DefragmentResponse          pb.DefragmentResponse
+	AlarmResponse               pb.AlarmResponse
+	AlarmMember                 pb.AlarmMember
+	StatusResponse              pb.StatusResponse
+	HashKVResponse              pb.HashKVResponse
+	MoveLeaderResponse          pb.MoveLeaderResponse
+	DowngradeResponse           pb.DowngradeResponse
+	ConfigResponse              pb.ConfigResponse
+	KeyAccessTimesResponse      pb.KeyAccessTimesResponse
+	MembershipCheckResponse     pb.MembershipCheckResponse
+	RotateEncryptionKeyResponse pb.RotateEncryptionKeyResponse
+	DefragmentStatusResponse    pb.DefragmentStatusResponse
+	ReadOnlyResponse            pb.ReadOnlyResponse
+	FollowerLagResponse         pb.FollowerLagResponse
+	HashKVCheckResponse         pb.HashKVCheckResponse
+	MirrorStatusResponse        pb.MirrorStatusResponse
+	ReclaimResponse             pb.ReclaimResponse
+	DowngradeCheckResponse      pb.DowngradeCheckResponse
 
 	DowngradeAction pb.DowngradeRequest_DowngradeAction
+	ReadOnlyAction  pb.ReadOnlyRequest_ReadOnlyAction
 )
 
 const (
 	DowngradeValidate = DowngradeAction(pb.DowngradeRequest_VALIDATE)
 	DowngradeEnable   = DowngradeAction(pb.DowngradeRequest_ENABLE)
 	DowngradeCancel   = DowngradeAction(pb.DowngradeRequest_CANCEL)
+
+	ReadOnlyGet     = ReadOnlyAction(pb.ReadOnlyRequest_GET)
+	ReadOnlyEnable  = ReadOnlyAction(pb.ReadOnlyRequest_ENABLE)
+	ReadOnlyDisable = ReadOnlyAction(pb.ReadOnlyRequest_DISABLE)
 )
 
 type Maintenance interface {
@@ -60,6 +76,25 @@ type Maintenance interface {
 	// times with different endpoints.
 	Defragment(ctx context.Context, endpoint string) (*DefragmentResponse, error)
 
+	// DefragmentAsync starts defragmenting the endpoint like Defragment, but
+	// returns without waiting for the defragmentation to finish. Its
+	// progress is reported by DefragmentStatus. No defragmentation is
+	// started if one is already running on the endpoint.
+	// Supported since etcd 3.7.
+	DefragmentAsync(ctx context.Context, endpoint string) (*DefragmentResponse, error)
+
+	// DefragmentStatus gets the progress of the running defragmentation of
+	// the endpoint, or the result of the last one.
+	// Supported since etcd 3.7.
+	DefragmentStatus(ctx context.Context, endpoint string) (*DefragmentStatusResponse, error)
+
+	// WatchDefragmentStatus calls fn with the progress of the running
+	// defragmentation of the endpoint about every second, until it has
+	// finished and fn was called with its result. If no defragmentation is
+	// running, fn is only called with the result of the last one.
+	// Supported since etcd 3.7.
+	WatchDefragmentStatus(ctx context.Context, endpoint string, fn func(*DefragmentStatusResponse)) error
+
 	// Status gets the status of the endpoint.
 	Status(ctx context.Context, endpoint string) (*StatusResponse, error)
 
@@ -87,6 +122,72 @@ type Maintenance interface {
 	// on the cluster version.
 	// Supported since etcd 3.5.
 	Downgrade(ctx context.Context, action DowngradeAction, version string) (*DowngradeResponse, error)
+
+	// Config gets the effective auto compaction, backend quota and maximum
+	// request size configuration of the endpoint.
+	// Supported since etcd 3.7.
+	Config(ctx context.Context, endpoint string) (*ConfigResponse, error)
+
+	// KeyAccessTimes gets when the keys were last read on the endpoint, least
+	// recently read first. The keys are selected by the range options, such
+	// as WithPrefix, WithRange, WithFromKey and WithLimit. The endpoint
+	// must run with --key-access-sample-rate.
+	// Supported since etcd 3.7.
+	KeyAccessTimes(ctx context.Context, endpoint, key string, opts ...OpOption) (*KeyAccessTimesResponse, error)
+
+	// MembershipCheck collects the member list every member of the cluster sees
+	// through the endpoint, and reports where they disagree with the member
+	// list of the endpoint.
+	// Supported since etcd 3.7.
+	MembershipCheck(ctx context.Context, endpoint string) (*MembershipCheckResponse, error)
+
+	// RotateEncryptionKey wraps the data encryption keys of the backend of
+	// the endpoint with its current key encryption key. If reencrypt is set,
+	// the backend is re-encrypted with a new data encryption key by
+	// defragmenting it, which is as expensive as Defragment. The endpoint
+	// must run with --encryption-kek-file or --encryption-kms-url.
+	// Supported since etcd 3.7.
+	RotateEncryptionKey(ctx context.Context, endpoint string, reencrypt bool) (*RotateEncryptionKeyResponse, error)
+
+	// ReadOnly gets, enables or disables the read-only mode of the cluster.
+	// In read-only mode the cluster rejects the requests writing to it with
+	// rpctypes.ErrReadOnly, but serves reads and watches.
+	// Supported since etcd 3.7.
+	ReadOnly(ctx context.Context, action ReadOnlyAction) (*ReadOnlyResponse, error)
+
+	// FollowerLag reports how far each follower lags behind the leader. The
+	// endpoint must be the leader, it fails with rpctypes.ErrNotLeader
+	// otherwise.
+	// Supported since etcd 3.7.
+	FollowerLag(ctx context.Context, endpoint string) (*FollowerLagResponse, error)
+
+	// HashKVCheck computes the hash of the key-value store of every member of
+	// the cluster through the endpoint, up to the same revision, and reports
+	// whether they match. If rev is 0, the latest compacted revision of the
+	// endpoint is hashed, or its latest revision if it was never compacted.
+	// Supported since etcd 3.7.
+	HashKVCheck(ctx context.Context, endpoint string, rev int64) (*HashKVCheckResponse, error)
+
+	// MirrorStatus reports the progress of the mirroring of the keys to the
+	// target cluster of the endpoint. Only the leader serves the request; it
+	// returns rpctypes.ErrNotLeader otherwise, unless the mirroring is not
+	// configured.
+	// Supported since etcd 3.7.
+	MirrorStatus(ctx context.Context, endpoint string) (*MirrorStatusResponse, error)
+
+	// Reclaim compacts the key-value store to rev, waits for the endpoint to
+	// finish the compaction, then defragments the endpoint. fn is called with
+	// the phase of the reclamation and the progress of the defragmentation
+	// about every second, and last with the bytes reclaimed once it is DONE.
+	// The compaction is skipped if rev is zero or already compacted.
+	// Supported since etcd 3.7.
+	Reclaim(ctx context.Context, endpoint string, rev int64, fn func(*ReclaimResponse)) error
+
+	// DowngradeCheck checks the storage schema, the enabled features and the
+	// WAL entries of every member of the cluster against the downgrade target
+	// version, and reports what blocks the downgrade, without enabling it.
+	// Supported since etcd 3.7.
+	DowngradeCheck(ctx context.Context, version string) (*DowngradeCheckResponse, error)
 }
 
 // SnapshotResponse is aggregated response from the snapshot stream.
@@ -201,6 +302,54 @@ func (m *maintenance) Defragment(ctx context.Context, endpoint string) (*Defragm
 	return (*DefragmentResponse)(resp), nil
 }
 
+func (m *maintenance) DefragmentAsync(ctx context.Context, endpoint string) (*DefragmentResponse, error) {
+	remote, cancel, err := m.dial(endpoint)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	defer cancel()
+	resp, err := remote.Defragment(ctx, &pb.DefragmentRequest{Async: true}, m.callOpts...)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	return (*DefragmentResponse)(resp), nil
+}
+
+func (m *maintenance) DefragmentStatus(ctx context.Context, endpoint string) (*DefragmentStatusResponse, error) {
+	var resp *DefragmentStatusResponse
+	err := m.defragmentStatus(ctx, endpoint, false, func(r *DefragmentStatusResponse) { resp = r })
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (m *maintenance) WatchDefragmentStatus(ctx context.Context, endpoint string, fn func(*DefragmentStatusResponse)) error {
+	return m.defragmentStatus(ctx, endpoint, true, fn)
+}
+
+func (m *maintenance) defragmentStatus(ctx context.Context, endpoint string, watch bool, fn func(*DefragmentStatusResponse)) error {
+	remote, cancel, err := m.dial(endpoint)
+	if err != nil {
+		return ContextError(ctx, err)
+	}
+	defer cancel()
+	ss, err := remote.DefragmentStatus(ctx, &pb.DefragmentStatusRequest{Watch: watch}, append(m.callOpts, withMax(defaultStreamMaxRetries))...)
+	if err != nil {
+		return ContextError(ctx, err)
+	}
+	for {
+		resp, err := ss.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return ContextError(ctx, err)
+		}
+		fn((*DefragmentStatusResponse)(resp))
+	}
+}
+
 func (m *maintenance) Status(ctx context.Context, endpoint string) (*StatusResponse, error) {
 	remote, cancel, err := m.dial(endpoint)
 	if err != nil {
@@ -348,3 +497,139 @@ func (m *maintenance) Downgrade(ctx context.Context, action DowngradeAction, ver
 	resp, err := m.remote.Downgrade(ctx, &pb.DowngradeRequest{Action: actionType, Version: version}, m.callOpts...)
 	return (*DowngradeResponse)(resp), ContextError(ctx, err)
 }
+
+func (m *maintenance) Config(ctx context.Context, endpoint string) (*ConfigResponse, error) {
+	remote, cancel, err := m.dial(endpoint)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	defer cancel()
+	resp, err := remote.Config(ctx, &pb.ConfigRequest{}, m.callOpts...)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	return (*ConfigResponse)(resp), nil
+}
+
+func (m *maintenance) KeyAccessTimes(ctx context.Context, endpoint, key string, opts ...OpOption) (*KeyAccessTimesResponse, error) {
+	remote, cancel, err := m.dial(endpoint)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	defer cancel()
+	op := OpGet(key, opts...)
+	req := &pb.KeyAccessTimesRequest{Key: op.key, RangeEnd: op.end, Limit: op.limit}
+	resp, err := remote.KeyAccessTimes(ctx, req, m.callOpts...)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	return (*KeyAccessTimesResponse)(resp), nil
+}
+
+func (m *maintenance) MembershipCheck(ctx context.Context, endpoint string) (*MembershipCheckResponse, error) {
+	remote, cancel, err := m.dial(endpoint)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	defer cancel()
+	resp, err := remote.MembershipCheck(ctx, &pb.MembershipCheckRequest{}, m.callOpts...)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	return (*MembershipCheckResponse)(resp), nil
+}
+
+func (m *maintenance) RotateEncryptionKey(ctx context.Context, endpoint string, reencrypt bool) (*RotateEncryptionKeyResponse, error) {
+	remote, cancel, err := m.dial(endpoint)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	defer cancel()
+	resp, err := remote.RotateEncryptionKey(ctx, &pb.RotateEncryptionKeyRequest{Reencrypt: reencrypt}, m.callOpts...)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	return (*RotateEncryptionKeyResponse)(resp), nil
+}
+
+func (m *maintenance) ReadOnly(ctx context.Context, action ReadOnlyAction) (*ReadOnlyResponse, error) {
+	switch action {
+	case ReadOnlyGet, ReadOnlyEnable, ReadOnlyDisable:
+	default:
+		return nil, errors.New("etcdclient: unknown read-only action")
+	}
+	resp, err := m.remote.ReadOnly(ctx, &pb.ReadOnlyRequest{Action: pb.ReadOnlyRequest_ReadOnlyAction(action)}, m.callOpts...)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	return (*ReadOnlyResponse)(resp), nil
+}
+
+func (m *maintenance) FollowerLag(ctx context.Context, endpoint string) (*FollowerLagResponse, error) {
+	remote, cancel, err := m.dial(endpoint)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	defer cancel()
+	resp, err := remote.FollowerLag(ctx, &pb.FollowerLagRequest{}, m.callOpts...)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	return (*FollowerLagResponse)(resp), nil
+}
+
+func (m *maintenance) HashKVCheck(ctx context.Context, endpoint string, rev int64) (*HashKVCheckResponse, error) {
+	remote, cancel, err := m.dial(endpoint)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	defer cancel()
+	resp, err := remote.HashKVCheck(ctx, &pb.HashKVCheckRequest{Revision: rev}, m.callOpts...)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	return (*HashKVCheckResponse)(resp), nil
+}
+
+func (m *maintenance) MirrorStatus(ctx context.Context, endpoint string) (*MirrorStatusResponse, error) {
+	remote, cancel, err := m.dial(endpoint)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	defer cancel()
+	resp, err := remote.MirrorStatus(ctx, &pb.MirrorStatusRequest{}, m.callOpts...)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	return (*MirrorStatusResponse)(resp), nil
+}
+
+func (m *maintenance) Reclaim(ctx context.Context, endpoint string, rev int64, fn func(*ReclaimResponse)) error {
+	remote, cancel, err := m.dial(endpoint)
+	if err != nil {
+		return ContextError(ctx, err)
+	}
+	defer cancel()
+	ss, err := remote.Reclaim(ctx, &pb.ReclaimRequest{Revision: rev}, m.callOpts...)
+	if err != nil {
+		return ContextError(ctx, err)
+	}
+	for {
+		resp, err := ss.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return ContextError(ctx, err)
+		}
+		fn((*ReclaimResponse)(resp))
+	}
+}
+
+func (m *maintenance) DowngradeCheck(ctx context.Context, version string) (*DowngradeCheckResponse, error) {
+	resp, err := m.remote.DowngradeCheck(ctx, &pb.DowngradeCheckRequest{Version: version}, m.callOpts...)
+	if err != nil {
+		return nil, ContextError(ctx, err)
+	}
+	return (*DowngradeCheckResponse)(resp), nil
+}
@@ -171,6 +171,10 @@ func (m *mockKVServer) Range(context.Context, *pb.RangeRequest) (*pb.RangeRespon
 	return &pb.RangeResponse{}, nil
 }
 
+func (m *mockKVServer) RangeStream(*pb.RangeRequest, pb.KV_RangeStreamServer) error {
+	return nil
+}
+
 func (m *mockKVServer) Put(context.Context, *pb.PutRequest) (*pb.PutResponse, error) {
 	return &pb.PutResponse{}, nil
 }
@@ -212,3 +216,15 @@ func (s *mockLeaseServer) LeaseTimeToLive(context.Context, *pb.LeaseTimeToLiveRe
 func (s *mockLeaseServer) LeaseLeases(context.Context, *pb.LeaseLeasesRequest) (*pb.LeaseLeasesResponse, error) {
 	return &pb.LeaseLeasesResponse{}, nil
 }
+
+func (s *mockLeaseServer) LeaseGrantBatch(context.Context, *pb.LeaseGrantBatchRequest) (*pb.LeaseGrantBatchResponse, error) {
+	return &pb.LeaseGrantBatchResponse{}, nil
+}
+
+func (s *mockLeaseServer) LeaseRevokeBatch(context.Context, *pb.LeaseRevokeBatchRequest) (*pb.LeaseRevokeBatchResponse, error) {
+	return &pb.LeaseRevokeBatchResponse{}, nil
+}
+
+func (s *mockLeaseServer) LeaseKeepAliveBatch(context.Context, *pb.LeaseKeepAliveBatchRequest) (*pb.LeaseKeepAliveBatchResponse, error) {
+	return &pb.LeaseKeepAliveBatchResponse{}, nil
+}
@@ -57,7 +57,7 @@ func (m *endpointManager) Update(ctx context.Context, updates []*UpdateWithOpts)
 	ops := make([]clientv3.Op, 0, len(updates))
 	for _, update := range updates {
 		if !strings.HasPrefix(update.Key, m.target+"/") {
-			return status.Errorf(codes.InvalidArgument, "endpoints: endpoint key should be prefixed with '%s/' got: '%s'", m.target, update.Key)
+			return status.Errorf(codes.InvalidArgument, "endpoints: endpoint key should be prefixed with '%s/' got: '%s'", clientv3.EscapeKey(m.target), clientv3.EscapeKey(update.Key))
 		}
 
 		switch update.Op {
@@ -103,7 +103,7 @@ func (m *endpointManager) NewWatchChannel(ctx context.Context) (WatchChannel, er
 	for _, kv := range resp.Kvs {
 		var iup internal.Update
 		if err := json.Unmarshal(kv.Value, &iup); err != nil {
-			lg.Warn("unmarshal endpoint update failed", zap.String("key", string(kv.Key)), zap.Error(err))
+			lg.Warn("unmarshal endpoint update failed", zap.String("key", clientv3.EscapeKey(string(kv.Key))), zap.Error(err))
 			continue
 		}
 		up := &Update{
@@ -153,7 +153,7 @@ func (m *endpointManager) watch(ctx context.Context, rev int64, upch chan []*Upd
 					err = json.Unmarshal(e.Kv.Value, &iup)
 					op = Add
 					if err != nil {
-						lg.Warn("unmarshal endpoint update failed", zap.String("key", string(e.Kv.Key)), zap.Error(err))
+						lg.Warn("unmarshal endpoint update failed", zap.String("key", clientv3.EscapeKey(string(e.Kv.Key))), zap.Error(err))
 						continue
 					}
 				case clientv3.EventTypeDelete:
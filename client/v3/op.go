@@ -14,7 +14,11 @@
 
 package clientv3
 
-import pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+import (
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
 
 type opType int
 
@@ -40,10 +44,19 @@ type Op struct {
 	serializable bool
 	keysOnly     bool
 	countOnly    bool
+	sizeOnly     bool
 	minModRev    int64
 	maxModRev    int64
 	minCreateRev int64
 	maxCreateRev int64
+	// continueToken resumes a paginated range
+	continueToken []byte
+	// keyFilter filters the keys of the range server-side, as a substring
+	// or as a regular expression if keyFilterRegex is set
+	keyFilter      []byte
+	keyFilterRegex bool
+	// leaseFilter filters the keys of the range server-side by their lease
+	leaseFilter LeaseID
 
 	// for range, watch
 	rev int64
@@ -56,6 +69,16 @@ type Op struct {
 	// if true, split watch events when total exceeds
 	// "--max-request-bytes" flag value + 512-byte
 	fragment bool
+	// maxEventRate is the maximum number of events per second the server sends
+	maxEventRate int64
+	// bookmarkInterval is the interval between bookmarks the server sends
+	bookmarkInterval time.Duration
+	// batchInterval and batchMaxEvents batch the events the server sends
+	batchInterval  time.Duration
+	batchMaxEvents int64
+	// snapshotOnCompaction asks for a snapshot of the range instead of the
+	// compacted events
+	snapshotOnCompaction bool
 
 	// for put
 	ignoreValue bool
@@ -68,10 +91,16 @@ type Op struct {
 	// filters for watchers
 	filterPut    bool
 	filterDelete bool
+	// value filters for the put events of watchers
+	valuePrefix   []byte
+	valueContains []byte
+	minValueSize  int64
+	maxValueSize  int64
 
 	// for put
 	val     []byte
 	leaseID LeaseID
+	ttl     int64
 
 	// txn
 	cmps    []Cmp
@@ -127,6 +156,9 @@ func (op Op) IsKeysOnly() bool { return op.keysOnly }
 // IsCountOnly returns whether countOnly is set.
 func (op Op) IsCountOnly() bool { return op.countOnly }
 
+// IsSizeOnly returns whether sizeOnly is set.
+func (op Op) IsSizeOnly() bool { return op.sizeOnly }
+
 func (op Op) IsOptsWithFromKey() bool { return op.isOptsWithFromKey }
 
 func (op Op) IsOptsWithPrefix() bool { return op.isOptsWithPrefix }
@@ -143,6 +175,67 @@ func (op Op) MinCreateRev() int64 { return op.minCreateRev }
 // MaxCreateRev returns the operation's maximum create revision.
 func (op Op) MaxCreateRev() int64 { return op.maxCreateRev }
 
+// Sort returns the operation's sort option, if any.
+func (op Op) Sort() *SortOption { return op.sort }
+
+// ContinueToken returns the operation's continue token, if any.
+func (op Op) ContinueToken() []byte { return op.continueToken }
+
+// KeyFilter returns the operation's key filter, if any.
+func (op Op) KeyFilter() []byte { return op.keyFilter }
+
+// IsKeyFilterRegex returns whether the key filter is a regular expression.
+func (op Op) IsKeyFilterRegex() bool { return op.keyFilterRegex }
+
+// LeaseFilter returns the operation's lease filter, if any.
+func (op Op) LeaseFilter() LeaseID { return op.leaseFilter }
+
+// IsPrevKV returns whether the previous key-value is requested.
+func (op Op) IsPrevKV() bool { return op.prevKV }
+
+// IsIgnoreValue returns whether a put keeps the current value of the key.
+func (op Op) IsIgnoreValue() bool { return op.ignoreValue }
+
+// IsIgnoreLease returns whether a put keeps the current lease of the key.
+func (op Op) IsIgnoreLease() bool { return op.ignoreLease }
+
+// LeaseID returns the lease a put attaches the key to, if any.
+func (op Op) LeaseID() LeaseID { return op.leaseID }
+
+// TTL returns the time to live in seconds a put sets on the key, if any.
+func (op Op) TTL() int64 { return op.ttl }
+
+// IsFilterPut returns whether a watch filters out put events.
+func (op Op) IsFilterPut() bool { return op.filterPut }
+
+// IsFilterDelete returns whether a watch filters out delete events.
+func (op Op) IsFilterDelete() bool { return op.filterDelete }
+
+// ValuePrefix returns the prefix the values of the put events of a watch
+// must start with, if any.
+func (op Op) ValuePrefix() []byte { return op.valuePrefix }
+
+// ValueContains returns the substring the values of the put events of a
+// watch must contain, if any.
+func (op Op) ValueContains() []byte { return op.valueContains }
+
+// MinValueSize returns the minimum value size of the put events of a watch.
+func (op Op) MinValueSize() int64 { return op.minValueSize }
+
+// MaxValueSize returns the maximum value size of the put events of a watch.
+func (op Op) MaxValueSize() int64 { return op.maxValueSize }
+
+// BookmarkInterval returns the interval between the bookmarks of a watch,
+// if any.
+func (op Op) BookmarkInterval() time.Duration { return op.bookmarkInterval }
+
+// IsSnapshotOnCompaction returns whether a watch from a compacted revision
+// receives a snapshot of the range instead of being canceled.
+func (op Op) IsSnapshotOnCompaction() bool { return op.snapshotOnCompaction }
+
+// IsCreatedNotify returns whether a watch notifies its creation.
+func (op Op) IsCreatedNotify() bool { return op.createdNotify }
+
 // WithRangeBytes sets the byte slice for the Op's range end.
 func (op *Op) WithRangeBytes(end []byte) { op.end = end }
 
@@ -164,10 +257,15 @@ func (op Op) toRangeRequest() *pb.RangeRequest {
 		Serializable:      op.serializable,
 		KeysOnly:          op.keysOnly,
 		CountOnly:         op.countOnly,
+		SizeOnly:          op.sizeOnly,
 		MinModRevision:    op.minModRev,
 		MaxModRevision:    op.maxModRev,
 		MinCreateRevision: op.minCreateRev,
 		MaxCreateRevision: op.maxCreateRev,
+		ContinueToken:     op.continueToken,
+		KeyFilter:         op.keyFilter,
+		KeyFilterRegex:    op.keyFilterRegex,
+		LeaseFilter:       int64(op.leaseFilter),
 	}
 	if op.sort != nil {
 		r.SortOrder = pb.RangeRequest_SortOrder(op.sort.Order)
@@ -197,7 +295,7 @@ func (op Op) toRequestOp() *pb.RequestOp {
 	case tRange:
 		return &pb.RequestOp{Request: &pb.RequestOp_RequestRange{RequestRange: op.toRangeRequest()}}
 	case tPut:
-		r := &pb.PutRequest{Key: op.key, Value: op.val, Lease: int64(op.leaseID), PrevKv: op.prevKV, IgnoreValue: op.ignoreValue, IgnoreLease: op.ignoreLease}
+		r := &pb.PutRequest{Key: op.key, Value: op.val, Lease: int64(op.leaseID), PrevKv: op.prevKV, IgnoreValue: op.ignoreValue, IgnoreLease: op.ignoreLease, Ttl: op.ttl}
 		return &pb.RequestOp{Request: &pb.RequestOp_RequestPut{RequestPut: r}}
 	case tDeleteRange:
 		r := &pb.DeleteRangeRequest{Key: op.key, RangeEnd: op.end, PrevKv: op.prevKV}
@@ -252,6 +350,8 @@ func OpDelete(key string, opts ...OpOption) Op {
 	switch {
 	case ret.leaseID != 0:
 		panic("unexpected lease in delete")
+	case ret.ttl != 0:
+		panic("unexpected ttl in delete")
 	case ret.limit != 0:
 		panic("unexpected limit in delete")
 	case ret.rev != 0:
@@ -262,12 +362,20 @@ func OpDelete(key string, opts ...OpOption) Op {
 		panic("unexpected serializable in delete")
 	case ret.countOnly:
 		panic("unexpected countOnly in delete")
+	case ret.sizeOnly:
+		panic("unexpected sizeOnly in delete")
 	case ret.minModRev != 0, ret.maxModRev != 0:
 		panic("unexpected mod revision filter in delete")
 	case ret.minCreateRev != 0, ret.maxCreateRev != 0:
 		panic("unexpected create revision filter in delete")
+	case ret.keyFilter != nil:
+		panic("unexpected key filter in delete")
+	case ret.leaseFilter != 0:
+		panic("unexpected lease filter in delete")
 	case ret.filterDelete, ret.filterPut:
 		panic("unexpected filter in delete")
+	case ret.valuePrefix != nil, ret.valueContains != nil, ret.minValueSize != 0, ret.maxValueSize != 0:
+		panic("unexpected value filter in delete")
 	case ret.createdNotify:
 		panic("unexpected createdNotify in delete")
 	}
@@ -291,12 +399,20 @@ func OpPut(key, val string, opts ...OpOption) Op {
 		panic("unexpected serializable in put")
 	case ret.countOnly:
 		panic("unexpected countOnly in put")
+	case ret.sizeOnly:
+		panic("unexpected sizeOnly in put")
 	case ret.minModRev != 0, ret.maxModRev != 0:
 		panic("unexpected mod revision filter in put")
 	case ret.minCreateRev != 0, ret.maxCreateRev != 0:
 		panic("unexpected create revision filter in put")
+	case ret.keyFilter != nil:
+		panic("unexpected key filter in put")
+	case ret.leaseFilter != 0:
+		panic("unexpected lease filter in put")
 	case ret.filterDelete, ret.filterPut:
 		panic("unexpected filter in put")
+	case ret.valuePrefix != nil, ret.valueContains != nil, ret.minValueSize != 0, ret.maxValueSize != 0:
+		panic("unexpected value filter in put")
 	case ret.createdNotify:
 		panic("unexpected createdNotify in put")
 	}
@@ -314,6 +430,8 @@ func opWatch(key string, opts ...OpOption) Op {
 	switch {
 	case ret.leaseID != 0:
 		panic("unexpected lease in watch")
+	case ret.ttl != 0:
+		panic("unexpected ttl in watch")
 	case ret.limit != 0:
 		panic("unexpected limit in watch")
 	case ret.sort != nil:
@@ -322,6 +440,8 @@ func opWatch(key string, opts ...OpOption) Op {
 		panic("unexpected serializable in watch")
 	case ret.countOnly:
 		panic("unexpected countOnly in watch")
+	case ret.sizeOnly:
+		panic("unexpected sizeOnly in watch")
 	case ret.minModRev != 0, ret.maxModRev != 0:
 		panic("unexpected mod revision filter in watch")
 	case ret.minCreateRev != 0, ret.maxCreateRev != 0:
@@ -348,6 +468,16 @@ func WithLease(leaseID LeaseID) OpOption {
 // If WithLimit is given a 0 limit, it is treated as no limit.
 func WithLimit(n int64) OpOption { return func(op *Op) { op.limit = n } }
 
+// WithContinueToken resumes a paginated 'Get' request with the NextToken
+// of the previous page's response. The request reads the keys following
+// the last key of the previous page, at the revision of the first page, and
+// fails with rpctypes.ErrCompacted once that revision has been compacted.
+// The key, range end and options other than the limit must be those of the
+// previous request. It has no effect in a transaction.
+func WithContinueToken(token []byte) OpOption {
+	return func(op *Op) { op.continueToken = token }
+}
+
 // WithRev specifies the store revision for 'Get' request.
 // Or the start revision of 'Watch' request.
 func WithRev(rev int64) OpOption { return func(op *Op) { op.rev = rev } }
@@ -449,6 +579,12 @@ func WithCountOnly() OpOption {
 	return func(op *Op) { op.countOnly = true }
 }
 
+// WithSizeOnly makes the 'Get' request return only the count of keys and
+// their approximate total size in bytes.
+func WithSizeOnly() OpOption {
+	return func(op *Op) { op.sizeOnly = true }
+}
+
 // WithMinModRev filters out keys for Get with modification revisions less than the given revision.
 func WithMinModRev(rev int64) OpOption { return func(op *Op) { op.minModRev = rev } }
 
@@ -461,6 +597,26 @@ func WithMinCreateRev(rev int64) OpOption { return func(op *Op) { op.minCreateRe
 // WithMaxCreateRev filters out keys for Get with creation revisions greater than the given revision.
 func WithMaxCreateRev(rev int64) OpOption { return func(op *Op) { op.maxCreateRev = rev } }
 
+// WithKeyFilter filters out keys for Get not containing the given substring.
+// The keys are filtered by the server before the limit is applied.
+func WithKeyFilter(substr string) OpOption {
+	return func(op *Op) { op.keyFilter, op.keyFilterRegex = []byte(substr), false }
+}
+
+// WithKeyFilterRegex filters out keys for Get not matching the given RE2
+// regular expression. The keys are filtered by the server before the limit
+// is applied. An invalid expression fails the request with
+// rpctypes.ErrInvalidKeyFilter.
+func WithKeyFilterRegex(expr string) OpOption {
+	return func(op *Op) { op.keyFilter, op.keyFilterRegex = []byte(expr), true }
+}
+
+// WithLeaseFilter filters out keys for Get not attached to the given lease.
+// The keys are filtered by the server before the limit is applied.
+func WithLeaseFilter(leaseID LeaseID) OpOption {
+	return func(op *Op) { op.leaseFilter = leaseID }
+}
+
 // WithFirstCreate gets the key with the oldest creation revision in the request range.
 func WithFirstCreate() []OpOption { return withTop(SortByCreateRevision, SortAscend) }
 
@@ -510,6 +666,30 @@ func WithFilterDelete() OpOption {
 	return func(op *Op) { op.filterDelete = true }
 }
 
+// WithValuePrefix discards PUT events from the watcher whose value does not
+// start with the given prefix. DELETE events are not filtered by value.
+func WithValuePrefix(prefix string) OpOption {
+	return func(op *Op) { op.valuePrefix = []byte(prefix) }
+}
+
+// WithValueContains discards PUT events from the watcher whose value does not
+// contain the given substring. DELETE events are not filtered by value.
+func WithValueContains(substr string) OpOption {
+	return func(op *Op) { op.valueContains = []byte(substr) }
+}
+
+// WithMinValueSize discards PUT events from the watcher whose value is
+// smaller than size bytes. DELETE events are not filtered by value.
+func WithMinValueSize(size int64) OpOption {
+	return func(op *Op) { op.minValueSize = size }
+}
+
+// WithMaxValueSize discards PUT events from the watcher whose value is
+// larger than size bytes. DELETE events are not filtered by value.
+func WithMaxValueSize(size int64) OpOption {
+	return func(op *Op) { op.maxValueSize = size }
+}
+
 // WithPrevKV gets the previous key-value pair before the event happens. If the previous KV is already compacted,
 // nothing will be returned.
 func WithPrevKV() OpOption {
@@ -529,6 +709,47 @@ func WithFragment() OpOption {
 	return func(op *Op) { op.fragment = true }
 }
 
+// WithMaxEventRate limits the number of events per second the etcd server sends
+// to the watcher. Events arriving faster are held back by the server and coalesced
+// per key, so that only the latest event of each held back key is delivered once
+// the rate allows it. The watcher may therefore miss intermediate revisions of a key.
+// A rate of zero or less means no limit.
+func WithMaxEventRate(rate int64) OpOption {
+	return func(op *Op) { op.maxEventRate = rate }
+}
+
+// WithBookmarkInterval makes the etcd server send a bookmark to the watcher
+// every interval, rounded up to whole seconds: a WatchResponse without events
+// with Bookmark set, guaranteeing that all the events up to its header revision
+// have been received. Unlike progress notifications, bookmarks are sent even
+// while events keep arriving, so that the resume revision of the watcher can be
+// checkpointed at a bounded lag. A bookmark is skipped while the watcher is
+// catching up with the store.
+func WithBookmarkInterval(interval time.Duration) OpOption {
+	return func(op *Op) { op.bookmarkInterval = interval }
+}
+
+// WithEventBatching makes the etcd server buffer the events of the watcher and
+// send them together, at most interval after the first buffered event, or earlier
+// once maxEvents events are buffered or they reach the request size limit of the
+// server. It trades latency for fewer watch responses on busy ranges. A maxEvents
+// of zero or less means no limit on the number of events, and an interval below
+// one millisecond disables batching.
+func WithEventBatching(interval time.Duration, maxEvents int64) OpOption {
+	return func(op *Op) { op.batchInterval, op.batchMaxEvents = interval, maxEvents }
+}
+
+// WithSnapshotOnCompaction makes the etcd server answer a watch from a compacted
+// revision, including a watch resumed after a disconnection, with a snapshot of
+// the watched range instead of canceling it with ErrCompacted: a WatchResponse
+// with Snapshot set whose events are put events of all the keys of the range at
+// its header revision, followed by the events after that revision. The keys
+// missing from the snapshot were deleted meanwhile, so that the watcher can
+// replace its state instead of listing the range again.
+func WithSnapshotOnCompaction() OpOption {
+	return func(op *Op) { op.snapshotOnCompaction = true }
+}
+
 // WithIgnoreValue updates the key using its current value.
 // This option can not be combined with non-empty values.
 // Returns an error if the key does not exist.
@@ -547,12 +768,27 @@ func WithIgnoreLease() OpOption {
 	}
 }
 
+// WithTTL deletes the key once ttl seconds elapsed, unless it is modified
+// meanwhile. Unlike a lease, the ttl needs no keep-alive and costs nothing
+// beyond the key. This option can not be combined with WithLease or
+// WithIgnoreLease.
+func WithTTL(ttl int64) OpOption {
+	return func(op *Op) { op.ttl = ttl }
+}
+
 // LeaseOp represents an Operation that lease can execute.
 type LeaseOp struct {
 	id LeaseID
 
 	// for TimeToLive
 	attachedKeys bool
+
+	// for Grant
+	metadata []byte
+
+	// for Leases
+	metadataPrefix []byte
+	minTTL, maxTTL int64
 }
 
 // LeaseOption configures lease operations.
@@ -564,11 +800,56 @@ func (op *LeaseOp) applyOpts(opts []LeaseOption) {
 	}
 }
 
+// NewLeaseOp returns the LeaseOp configured by the given options, e.g. for
+// implementations of Lease.
+func NewLeaseOp(opts ...LeaseOption) *LeaseOp {
+	op := &LeaseOp{}
+	op.applyOpts(opts)
+	return op
+}
+
+// Metadata returns the metadata set by WithLeaseMetadata.
+func (op *LeaseOp) Metadata() []byte { return op.metadata }
+
+// MetadataPrefix returns the prefix set by WithMetadataPrefix.
+func (op *LeaseOp) MetadataPrefix() []byte { return op.metadataPrefix }
+
+// GrantedTTLRange returns the bounds set by WithGrantedTTLRange.
+func (op *LeaseOp) GrantedTTLRange() (minTTL, maxTTL int64) { return op.minTTL, op.maxTTL }
+
 // WithAttachedKeys makes TimeToLive list the keys attached to the given lease ID.
 func WithAttachedKeys() LeaseOption {
 	return func(op *LeaseOp) { op.attachedKeys = true }
 }
 
+// WithLeaseMetadata makes Grant store the given metadata, of at most 256
+// bytes, with the lease. It is returned by TimeToLive and Leases.
+func WithLeaseMetadata(metadata []byte) LeaseOption {
+	return func(op *LeaseOp) { op.metadata = metadata }
+}
+
+// WithMetadataPrefix makes Leases list only the leases whose metadata starts
+// with the given prefix.
+func WithMetadataPrefix(prefix []byte) LeaseOption {
+	return func(op *LeaseOp) { op.metadataPrefix = prefix }
+}
+
+// WithGrantedTTLRange makes Leases list only the leases granted with a TTL
+// between minTTL and maxTTL seconds, inclusive. A bound of 0 is unset.
+func WithGrantedTTLRange(minTTL, maxTTL int64) LeaseOption {
+	return func(op *LeaseOp) { op.minTTL, op.maxTTL = minTTL, maxTTL }
+}
+
+func toLeaseGrantRequest(ttl int64, opts ...LeaseOption) *pb.LeaseGrantRequest {
+	ret := NewLeaseOp(opts...)
+	return &pb.LeaseGrantRequest{TTL: ttl, Metadata: ret.metadata}
+}
+
+func toLeaseLeasesRequest(opts ...LeaseOption) *pb.LeaseLeasesRequest {
+	ret := NewLeaseOp(opts...)
+	return &pb.LeaseLeasesRequest{MetadataPrefix: ret.metadataPrefix, MinTTL: ret.minTTL, MaxTTL: ret.maxTTL}
+}
+
 func toLeaseTimeToLiveRequest(id LeaseID, opts ...LeaseOption) *pb.LeaseTimeToLiveRequest {
 	ret := &LeaseOp{id: id}
 	ret.applyOpts(opts)
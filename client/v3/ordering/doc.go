@@ -38,4 +38,13 @@
 //	cli.KV = ordering.NewKV(cli.KV, vf)
 //
 // Now calls using 'cli' will reject order violations with an error.
+//
+// The last seen revision only lives as long as the process. To keep reads
+// monotonic across restarts of the client, persist it with a RevisionStore:
+//
+//	kv, err := ordering.NewKVWithStore(cli.KV, vf, ordering.NewFileRevisionStore("/var/lib/app/etcd-rev"))
+//	if err != nil {
+//		// handle error!
+//	}
+//	cli.KV = kv
 package ordering
@@ -16,6 +16,7 @@ package ordering
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -29,10 +30,22 @@ type kvOrdering struct {
 	orderViolationFunc OrderViolationFunc
 	prevRev            int64
 	revMu              sync.RWMutex
+	store              RevisionStore
 }
 
 func NewKV(kv clientv3.KV, orderViolationFunc OrderViolationFunc) *kvOrdering {
-	return &kvOrdering{kv, orderViolationFunc, 0, sync.RWMutex{}}
+	return &kvOrdering{kv, orderViolationFunc, 0, sync.RWMutex{}, nil}
+}
+
+// NewKVWithStore is like NewKV, but starts from the revision loaded from
+// store and persists the revisions it observes to it. This keeps reads
+// monotonic across restarts of the client.
+func NewKVWithStore(kv clientv3.KV, orderViolationFunc OrderViolationFunc, store RevisionStore) (*kvOrdering, error) {
+	rev, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("ordering: failed to load revision: %w", err)
+	}
+	return &kvOrdering{kv, orderViolationFunc, rev, sync.RWMutex{}, store}, nil
 }
 
 func (kv *kvOrdering) getPrevRev() int64 {
@@ -41,12 +54,18 @@ func (kv *kvOrdering) getPrevRev() int64 {
 	return kv.prevRev
 }
 
-func (kv *kvOrdering) setPrevRev(currRev int64) {
+func (kv *kvOrdering) setPrevRev(currRev int64) error {
 	kv.revMu.Lock()
 	defer kv.revMu.Unlock()
 	if currRev > kv.prevRev {
+		if kv.store != nil {
+			if err := kv.store.Store(currRev); err != nil {
+				return fmt.Errorf("ordering: failed to persist revision %d: %w", currRev, err)
+			}
+		}
 		kv.prevRev = currRev
 	}
+	return nil
 }
 
 func (kv *kvOrdering) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
@@ -65,7 +84,9 @@ func (kv *kvOrdering) Get(ctx context.Context, key string, opts ...clientv3.OpOp
 		if resp.Header.Revision == prevRev {
 			return resp, nil
 		} else if resp.Header.Revision > prevRev {
-			kv.setPrevRev(resp.Header.Revision)
+			if err = kv.setPrevRev(resp.Header.Revision); err != nil {
+				return nil, err
+			}
 			return resp, nil
 		}
 		err = kv.orderViolationFunc(op, r, prevRev)
@@ -138,7 +159,9 @@ func (txn *txnOrdering) Commit() (*clientv3.TxnResponse, error) {
 		}
 		txnResp := opResp.Txn()
 		if txnResp.Header.Revision >= prevRev {
-			txn.setPrevRev(txnResp.Header.Revision)
+			if err = txn.setPrevRev(txnResp.Header.Revision); err != nil {
+				return nil, err
+			}
 			return txnResp, nil
 		}
 		err = txn.orderViolationFunc(opTxn, opResp, prevRev)
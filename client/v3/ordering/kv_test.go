@@ -75,6 +75,7 @@ func TestKvOrdering(t *testing.T) {
 			}(tt.response),
 			tt.prevRev,
 			sync.RWMutex{},
+			nil,
 		}
 		res, err := kv.Get(t.Context(), "mockKey")
 		if err != nil {
@@ -129,6 +130,7 @@ func TestTxnOrdering(t *testing.T) {
 			}(tt.response),
 			tt.prevRev,
 			sync.RWMutex{},
+			nil,
 		}
 		txn := &txnOrdering{
 			kv.Txn(t.Context()),
@@ -0,0 +1,78 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ordering
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// RevisionStore persists the last revision observed by the ordering
+// wrapper, so that it is restored when the client restarts.
+type RevisionStore interface {
+	// Load returns the persisted revision, or 0 if none was persisted.
+	Load() (int64, error)
+	// Store persists rev. It is called with increasing revisions, and the
+	// response revealing rev is only returned once Store succeeded.
+	Store(rev int64) error
+}
+
+// fileRevisionStore persists the revision in a file, replaced atomically.
+type fileRevisionStore struct {
+	path string
+}
+
+// NewFileRevisionStore returns a RevisionStore persisting the revision in
+// the file at path.
+func NewFileRevisionStore(path string) RevisionStore {
+	return &fileRevisionStore{path: path}
+}
+
+func (s *fileRevisionStore) Load() (int64, error) {
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	rev, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("ordering: invalid revision in %s: %w", s.path, err)
+	}
+	return rev, nil
+}
+
+func (s *fileRevisionStore) Store(rev int64) error {
+	f, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err = f.WriteString(strconv.FormatInt(rev, 10)); err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.path)
+}
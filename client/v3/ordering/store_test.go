@@ -0,0 +1,86 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ordering
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func TestFileRevisionStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rev")
+	s := NewFileRevisionStore(path)
+
+	rev, err := s.Load()
+	require.NoError(t, err)
+	require.Zero(t, rev)
+
+	require.NoError(t, s.Store(5))
+	require.NoError(t, s.Store(7))
+	rev, err = NewFileRevisionStore(path).Load()
+	require.NoError(t, err)
+	require.Equal(t, int64(7), rev)
+
+	require.NoError(t, os.WriteFile(path, []byte("garbage"), 0o600))
+	_, err = s.Load()
+	require.Error(t, err)
+}
+
+type memRevisionStore struct {
+	rev int64
+	err error
+}
+
+func (s *memRevisionStore) Load() (int64, error) { return s.rev, nil }
+
+func (s *memRevisionStore) Store(rev int64) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.rev = rev
+	return nil
+}
+
+func TestKVWithStore(t *testing.T) {
+	store := &memRevisionStore{rev: 5}
+	mKV := &mockKV{clientv3.NewKVFromKVClient(nil, nil), (&clientv3.GetResponse{Header: &pb.ResponseHeader{Revision: 4}}).OpResponse()}
+	errViolation := errors.New("violation")
+	kv, err := NewKVWithStore(mKV, func(clientv3.Op, clientv3.OpResponse, int64) error { return errViolation }, store)
+	require.NoError(t, err)
+
+	// the revision restored from the store is enforced.
+	_, err = kv.Get(t.Context(), "foo")
+	require.ErrorIs(t, err, errViolation)
+
+	// newer revisions are persisted.
+	mKV.response = (&clientv3.GetResponse{Header: &pb.ResponseHeader{Revision: 8}}).OpResponse()
+	_, err = kv.Get(t.Context(), "foo")
+	require.NoError(t, err)
+	require.Equal(t, int64(8), store.rev)
+
+	// responses are not returned unless their revision is persisted.
+	store.err = errors.New("disk full")
+	mKV.response = (&clientv3.GetResponse{Header: &pb.ResponseHeader{Revision: 9}}).OpResponse()
+	_, err = kv.Get(t.Context(), "foo")
+	require.ErrorIs(t, err, store.err)
+	require.Equal(t, int64(8), kv.getPrevRev())
+}
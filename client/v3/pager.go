@@ -0,0 +1,90 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3
+
+import (
+	"context"
+	"errors"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+)
+
+// ErrPagerDone is returned by Pager.Next once the range is exhausted.
+var ErrPagerDone = errors.New("etcdclient: no more pages")
+
+// Pager pages through the keys of a range with the continue tokens of the
+// server, so that every page is read at the revision of the first one
+// without the caller deriving the key the next page starts from.
+type Pager struct {
+	kv   KV
+	key  string
+	opts []OpOption
+
+	token []byte
+	done  bool
+}
+
+// NewPager returns a Pager reading the range of key, as selected by opts,
+// in pages of at most pageSize keys; a limit in opts is ignored. The keys
+// are read in ascending key order; other sort options fail with
+// ErrInvalidSortOption.
+func NewPager(kv KV, key string, pageSize int64, opts ...OpOption) *Pager {
+	return &Pager{kv: kv, key: key, opts: append(opts[:len(opts):len(opts)], WithLimit(pageSize))}
+}
+
+// ResumePager returns a Pager continuing after the page whose NextToken is
+// token, e.g. saved by a previous process. The key, page size and options
+// must be those of the Pager that read the page.
+func ResumePager(kv KV, key string, pageSize int64, token []byte, opts ...OpOption) *Pager {
+	p := NewPager(kv, key, pageSize, opts...)
+	p.token = token
+	return p
+}
+
+// Next returns the next page of the range, or ErrPagerDone once the range
+// is exhausted. Pages fail with ErrCompacted once the revision of the first
+// page has been compacted.
+func (p *Pager) Next(ctx context.Context) (*GetResponse, error) {
+	if p.done {
+		return nil, ErrPagerDone
+	}
+	opts := p.opts
+	if p.token != nil {
+		opts = append(opts[:len(opts):len(opts)], WithContinueToken(p.token))
+	}
+	op := OpGet(p.key, opts...)
+	if op.sort != nil && (op.sort.Target != SortByKey || op.sort.Order == SortDescend) {
+		return nil, rpctypes.ErrInvalidSortOption
+	}
+	resp, err := p.kv.Get(ctx, p.key, opts...)
+	if err != nil {
+		return nil, err
+	}
+	p.token = resp.NextToken
+	p.done = len(resp.NextToken) == 0
+	return resp, nil
+}
+
+// Done reports whether the range is exhausted.
+func (p *Pager) Done() bool { return p.done }
+
+// Token returns the token resuming the range after the last page read, or
+// nil before the first page and once the range is exhausted.
+func (p *Pager) Token() []byte {
+	if p.done {
+		return nil
+	}
+	return p.token
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+)
+
+func TestPager(t *testing.T) {
+	kv := &scriptedKV{gets: []*GetResponse{
+		{Kvs: []*mvccpb.KeyValue{keyValue("a", 1), keyValue("b", 2)}, More: true, NextToken: []byte("t1")},
+		{Kvs: []*mvccpb.KeyValue{keyValue("c", 3)}},
+	}}
+	p := NewPager(kv, "", 2, WithPrefix(), WithLimit(10))
+	assert.Nil(t, p.Token())
+
+	resp, err := p.Next(context.TODO())
+	require.NoError(t, err)
+	assert.Len(t, resp.Kvs, 2)
+	assert.False(t, p.Done())
+	assert.Equal(t, []byte("t1"), p.Token())
+
+	resp, err = p.Next(context.TODO())
+	require.NoError(t, err)
+	assert.Len(t, resp.Kvs, 1)
+	assert.True(t, p.Done())
+	assert.Nil(t, p.Token())
+	_, err = p.Next(context.TODO())
+	require.ErrorIs(t, err, ErrPagerDone)
+
+	// the page size overrides the limit, and the token follows the first page.
+	require.Len(t, kv.ops, 2)
+	assert.Equal(t, int64(2), kv.ops[0].Limit())
+	assert.Nil(t, kv.ops[0].ContinueToken())
+	assert.Equal(t, []byte("t1"), kv.ops[1].ContinueToken())
+	assert.Equal(t, kv.ops[0].RangeBytes(), kv.ops[1].RangeBytes())
+}
+
+func TestPagerResume(t *testing.T) {
+	kv := &scriptedKV{gets: []*GetResponse{{Header: &pb.ResponseHeader{}}}}
+	p := ResumePager(kv, "a", 5, []byte("t1"), WithFromKey())
+	_, err := p.Next(context.TODO())
+	require.NoError(t, err)
+	assert.Equal(t, []byte("t1"), kv.ops[0].ContinueToken())
+	assert.True(t, p.Done())
+
+	p = NewPager(kv, "a", 5, WithFromKey(), WithSort(SortByModRevision, SortAscend))
+	_, err = p.Next(context.TODO())
+	require.ErrorIs(t, err, rpctypes.ErrInvalidSortOption)
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+	"go.etcd.io/etcd/client/v3/internal/endpoint"
+)
+
+// usesQUIC returns true if any of the endpoints is a quic:// URL, in which
+// case the client dials with dialQUIC and prefers the QUIC client URLs of the
+// members on Sync.
+func usesQUIC(eps []string) bool {
+	for _, ep := range eps {
+		if endpoint.IsQUIC(ep) {
+			return true
+		}
+	}
+	return false
+}
+
+// dialQUIC dials the QUIC endpoints over QUIC and the others over TCP or unix
+// sockets, as the default dialer of gRPC.
+func (c *Client) dialQUIC(ctx context.Context, addr string) (net.Conn, error) {
+	if endpoint.IsQUIC(addr) {
+		return transport.DialQUIC(ctx, strings.TrimPrefix(addr, "quic://"), c.cfg.TLS)
+	}
+	network := "tcp"
+	if strings.HasPrefix(addr, "unix:") {
+		network = "unix"
+		addr = strings.TrimPrefix(strings.TrimPrefix(addr, "unix:"), "//")
+	}
+	return (&net.Dialer{}).DialContext(ctx, network, addr)
+}
+
+// syncedEndpoints returns the endpoints of a member advertising the given
+// client URLs: its QUIC URLs if the client uses QUIC and the member advertises
+// any, and its other URLs otherwise.
+func syncedEndpoints(clientURLs []string, quic bool) []string {
+	var quicURLs, others []string
+	for _, u := range clientURLs {
+		if endpoint.IsQUIC(u) {
+			quicURLs = append(quicURLs, u)
+		} else {
+			others = append(others, u)
+		}
+	}
+	if quic && len(quicURLs) > 0 {
+		return quicURLs
+	}
+	return others
+}
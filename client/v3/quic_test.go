@@ -0,0 +1,34 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncedEndpoints(t *testing.T) {
+	mixed := []string{"https://10.0.0.1:2379", "quic://10.0.0.1:2379"}
+	tcpOnly := []string{"https://10.0.0.2:2379"}
+
+	assert.Equal(t, []string{"quic://10.0.0.1:2379"}, syncedEndpoints(mixed, true))
+	assert.Equal(t, []string{"https://10.0.0.1:2379"}, syncedEndpoints(mixed, false))
+	assert.Equal(t, tcpOnly, syncedEndpoints(tcpOnly, true))
+	assert.Empty(t, syncedEndpoints([]string{"quic://10.0.0.3:2379"}, false))
+
+	assert.True(t, usesQUIC([]string{"unix://localhost:2379", "quic://10.0.0.1:2379"}))
+	assert.False(t, usesQUIC(tcpOnly))
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+)
+
+// RangeIterator iterates over the keys of a range streamed by the server.
+type RangeIterator struct {
+	stream pb.KV_RangeStreamClient
+	cancel context.CancelFunc
+
+	header *pb.ResponseHeader
+	count  int64
+	more   bool
+
+	kvs []*mvccpb.KeyValue
+	kv  *mvccpb.KeyValue
+	err error
+}
+
+// RangeStream gets the keys of a range like KV.Get, but streams them from
+// the server in pages read at the same revision, so that ranges too large
+// for a single response can be read. The keys are always sorted in
+// ascending key order; other sort options fail with ErrInvalidSortOption.
+//
+// The iterator must be closed once done with.
+func RangeStream(ctx context.Context, c *Client, key string, opts ...OpOption) (*RangeIterator, error) {
+	op := OpGet(key, opts...)
+	if !op.IsSortOptionValid() || op.sort != nil && (op.sort.Target != SortByKey || op.sort.Order == SortDescend) {
+		return nil, rpctypes.ErrInvalidSortOption
+	}
+	sctx, cancel := context.WithCancel(ctx)
+	stream, err := RetryKVClient(c).RangeStream(sctx, op.toRangeRequest(), c.callOpts...)
+	if err != nil {
+		cancel()
+		return nil, ContextError(ctx, err)
+	}
+	it := &RangeIterator{stream: stream, cancel: cancel}
+	// the first page holds the header and count of the range
+	if !it.recv() && it.err != nil {
+		cancel()
+		return nil, ContextError(ctx, it.err)
+	}
+	return it, nil
+}
+
+// recv reads the next page of the range, returning false at the end of the
+// stream or on error.
+func (it *RangeIterator) recv() bool {
+	resp, err := it.stream.Recv()
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			it.err = ContextError(it.stream.Context(), err)
+		}
+		return false
+	}
+	rr := resp.RangeResponse
+	if rr == nil {
+		return true
+	}
+	if it.header == nil {
+		it.header, it.count = rr.Header, rr.Count
+	}
+	it.kvs, it.more = rr.Kvs, rr.More
+	return true
+}
+
+// Next advances the iterator to the next key, returning false once the
+// range is exhausted or on error.
+func (it *RangeIterator) Next() bool {
+	for len(it.kvs) == 0 {
+		if it.err != nil || !it.recv() {
+			it.kv = nil
+			return false
+		}
+	}
+	it.kv, it.kvs = it.kvs[0], it.kvs[1:]
+	return true
+}
+
+// KeyValue returns the current key.
+func (it *RangeIterator) KeyValue() *mvccpb.KeyValue { return it.kv }
+
+// Header returns the header of the first page, whose revision is the one
+// the whole range is read at.
+func (it *RangeIterator) Header() *pb.ResponseHeader { return it.header }
+
+// Count returns the number of keys in the range.
+func (it *RangeIterator) Count() int64 { return it.count }
+
+// More reports whether the limit of the request truncated the range. It is
+// only meaningful once Next returned false.
+func (it *RangeIterator) More() bool { return it.more }
+
+// Err returns the error that ended the iteration, if any.
+func (it *RangeIterator) Err() error { return it.err }
+
+// Close stops the stream.
+func (it *RangeIterator) Close() {
+	it.cancel()
+}
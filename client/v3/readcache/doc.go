@@ -0,0 +1,36 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package readcache is a clientv3 wrapper that serves repeated serializable
+// Gets from a local cache.
+//
+// Read-heavy clients often issue the same Get over and over. With the
+// wrapper, the response of a serializable Get is cached and returned to the
+// following identical Gets until a change to the range it read is observed:
+//
+//	kv, closeCache := readcache.NewKV(cli.KV, cli.Watcher, readcache.DefaultMaxRanges)
+//	defer closeCache()
+//	resp, err := kv.Get(ctx, "foo", clientv3.WithSerializable())
+//
+// Every cached range is watched from the revision of its cached responses,
+// and they are dropped on the first change, compaction or watch failure. At
+// most maxRanges ranges are cached, the least recently read being dropped
+// first. Writes issued through the wrapper drop the cached ranges they touch
+// right away, so the writer reads its own writes.
+//
+// A cached response may lag behind the cluster until the watch delivers the
+// change, exactly like a serializable read served by a lagging member. Gets
+// that are linearizable, or that read at a given revision, bypass the cache.
+// Responses are shared between callers and must not be modified.
+package readcache
@@ -0,0 +1,287 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package readcache
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// DefaultMaxRanges is the default number of ranges cached, each of them
+// being watched.
+const DefaultMaxRanges = 1024
+
+// kvCache serves serializable Gets from a cache invalidated by watches.
+type kvCache struct {
+	clientv3.KV
+	w         clientv3.Watcher
+	maxRanges int
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	ranges map[string]*cachedRange
+	// lru orders the cached ranges, most recently read first.
+	lru *list.List
+	// minRev is the revision of the latest write issued through the cache.
+	// Older responses may not reflect it, so they are not cached.
+	minRev int64
+}
+
+// cachedRange holds the cached responses of the Gets reading a range.
+type cachedRange struct {
+	rangeKey string
+	key, end []byte
+	// rev is the revision of the oldest cached response. The range is
+	// watched for changes after it.
+	rev    int64
+	resps  map[string]*clientv3.GetResponse
+	elem   *list.Element
+	cancel context.CancelFunc
+}
+
+// NewKV wraps kv so that serializable Gets are served from a cache of at
+// most maxRanges ranges, invalidated by watches issued through w. The
+// returned function stops the watches and drops the cache.
+func NewKV(kv clientv3.KV, w clientv3.Watcher, maxRanges int) (clientv3.KV, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ckv := &kvCache{
+		KV:        kv,
+		w:         w,
+		maxRanges: maxRanges,
+		ctx:       ctx,
+		cancel:    cancel,
+		ranges:    make(map[string]*cachedRange),
+		lru:       list.New(),
+	}
+	return ckv, ckv.Close
+}
+
+func (kv *kvCache) Close() {
+	kv.cancel()
+	kv.wg.Wait()
+}
+
+func (kv *kvCache) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	resp, err := kv.Do(ctx, clientv3.OpGet(key, opts...))
+	return resp.Get(), err
+}
+
+func (kv *kvCache) Put(ctx context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	resp, err := kv.Do(ctx, clientv3.OpPut(key, val, opts...))
+	return resp.Put(), err
+}
+
+func (kv *kvCache) Delete(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+	resp, err := kv.Do(ctx, clientv3.OpDelete(key, opts...))
+	return resp.Del(), err
+}
+
+func (kv *kvCache) Do(ctx context.Context, op clientv3.Op) (clientv3.OpResponse, error) {
+	if !op.IsGet() {
+		resp, err := kv.KV.Do(ctx, op)
+		kv.invalidate(op, writeRevision(resp))
+		return resp, err
+	}
+	if !isCacheable(op) {
+		return kv.KV.Do(ctx, op)
+	}
+
+	rk, ck := rangeKey(op), callKey(op)
+	if resp := kv.lookup(rk, ck); resp != nil {
+		return resp.OpResponse(), nil
+	}
+	resp, err := kv.KV.Do(ctx, op)
+	if err != nil {
+		return resp, err
+	}
+	kv.add(op, rk, ck, resp.Get())
+	return resp, nil
+}
+
+func (kv *kvCache) Txn(ctx context.Context) clientv3.Txn {
+	return &txnCache{Txn: kv.KV.Txn(ctx), kv: kv}
+}
+
+// isCacheable tells whether the response of a Get can be cached. Other
+// Gets either read at a fixed revision or must reflect the latest writes.
+func isCacheable(op clientv3.Op) bool {
+	return op.IsSerializable() && op.Rev() == 0 && len(op.ContinueToken()) == 0
+}
+
+func (kv *kvCache) lookup(rk, ck string) *clientv3.GetResponse {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	r, ok := kv.ranges[rk]
+	if !ok {
+		return nil
+	}
+	resp, ok := r.resps[ck]
+	if !ok {
+		return nil
+	}
+	kv.lru.MoveToFront(r.elem)
+	return resp
+}
+
+func (kv *kvCache) add(op clientv3.Op, rk, ck string, resp *clientv3.GetResponse) {
+	rev := resp.Header.Revision
+
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	if kv.ctx.Err() != nil || rev < kv.minRev {
+		return
+	}
+	r, ok := kv.ranges[rk]
+	switch {
+	case !ok:
+		ctx, cancel := context.WithCancel(kv.ctx)
+		r = &cachedRange{
+			rangeKey: rk,
+			key:      op.KeyBytes(),
+			end:      op.RangeBytes(),
+			rev:      rev,
+			resps:    make(map[string]*clientv3.GetResponse),
+			cancel:   cancel,
+		}
+		r.elem = kv.lru.PushFront(r)
+		kv.ranges[rk] = r
+		kv.wg.Add(1)
+		go kv.watch(ctx, r)
+		for kv.lru.Len() > kv.maxRanges {
+			kv.removeLocked(kv.lru.Back().Value.(*cachedRange))
+		}
+	case rev < r.rev:
+		// the range is only watched for changes after r.rev, so changes
+		// between rev and r.rev would go unnoticed.
+		return
+	default:
+		kv.lru.MoveToFront(r.elem)
+	}
+	r.resps[ck] = resp
+}
+
+// watch drops the range on its first change, or once it can no longer be
+// watched.
+func (kv *kvCache) watch(ctx context.Context, r *cachedRange) {
+	defer kv.wg.Done()
+	wch := kv.w.Watch(ctx, string(r.key), clientv3.WithRange(string(r.end)), clientv3.WithRev(r.rev+1))
+	for wr := range wch {
+		if wr.Created || wr.IsProgressNotify() {
+			continue
+		}
+		break
+	}
+
+	kv.mu.Lock()
+	if kv.ranges[r.rangeKey] == r {
+		kv.removeLocked(r)
+	}
+	kv.mu.Unlock()
+}
+
+func (kv *kvCache) removeLocked(r *cachedRange) {
+	delete(kv.ranges, r.rangeKey)
+	kv.lru.Remove(r.elem)
+	r.cancel()
+}
+
+// invalidate drops the cached ranges the write op may have changed. rev is
+// the revision of the write, or 0 if unknown.
+func (kv *kvCache) invalidate(op clientv3.Op, rev int64) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	if rev > kv.minRev {
+		kv.minRev = rev
+	}
+	for _, r := range kv.ranges {
+		if writeOverlaps(op, r.key, r.end) {
+			kv.removeLocked(r)
+		}
+	}
+}
+
+func writeRevision(resp clientv3.OpResponse) int64 {
+	switch {
+	case resp.Put() != nil:
+		return resp.Put().Header.GetRevision()
+	case resp.Del() != nil:
+		return resp.Del().Header.GetRevision()
+	case resp.Txn() != nil:
+		return resp.Txn().Header.GetRevision()
+	}
+	return 0
+}
+
+// txnCache invalidates the ranges written by either branch of the txn.
+type txnCache struct {
+	clientv3.Txn
+	kv       *kvCache
+	thenOps  []clientv3.Op
+	elseOps  []clientv3.Op
+	compares []clientv3.Cmp
+}
+
+func (txn *txnCache) If(cs ...clientv3.Cmp) clientv3.Txn {
+	txn.Txn = txn.Txn.If(cs...)
+	txn.compares = append(txn.compares, cs...)
+	return txn
+}
+
+func (txn *txnCache) Then(ops ...clientv3.Op) clientv3.Txn {
+	txn.Txn = txn.Txn.Then(ops...)
+	txn.thenOps = append(txn.thenOps, ops...)
+	return txn
+}
+
+func (txn *txnCache) Else(ops ...clientv3.Op) clientv3.Txn {
+	txn.Txn = txn.Txn.Else(ops...)
+	txn.elseOps = append(txn.elseOps, ops...)
+	return txn
+}
+
+func (txn *txnCache) Commit() (*clientv3.TxnResponse, error) {
+	resp, err := txn.Txn.Commit()
+	var rev int64
+	if resp != nil {
+		rev = resp.Header.GetRevision()
+	}
+	txn.kv.invalidate(clientv3.OpTxn(txn.compares, txn.thenOps, txn.elseOps), rev)
+	return resp, err
+}
+
+// rangeKey identifies the range read by a Get.
+func rangeKey(op clientv3.Op) string {
+	return fmt.Sprintf("%q/%q", op.KeyBytes(), op.RangeBytes())
+}
+
+// callKey identifies a Get by every field that affects its response.
+func callKey(op clientv3.Op) string {
+	var sort clientv3.SortOption
+	if s := op.Sort(); s != nil {
+		sort = *s
+	}
+	return fmt.Sprintf("%q/%q/%d/%t/%t/%t/%d/%d/%d/%d/%d/%d/%q/%t/%d",
+		op.KeyBytes(), op.RangeBytes(), op.Limit(),
+		op.IsKeysOnly(), op.IsCountOnly(), op.IsSizeOnly(),
+		op.MinModRev(), op.MaxModRev(), op.MinCreateRev(), op.MaxCreateRev(),
+		sort.Target, sort.Order, op.KeyFilter(), op.IsKeyFilterRegex(), op.LeaseFilter())
+}
@@ -0,0 +1,337 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package readcache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// fakeKV answers every Get with the current revision, which writes bump.
+type fakeKV struct {
+	clientv3.KV
+
+	mu   sync.Mutex
+	rev  int64
+	gets int
+}
+
+func (kv *fakeKV) Do(ctx context.Context, op clientv3.Op) (clientv3.OpResponse, error) {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	if op.IsGet() {
+		kv.gets++
+		resp := &clientv3.GetResponse{
+			Header: &pb.ResponseHeader{Revision: kv.rev},
+			Kvs:    []*mvccpb.KeyValue{{Key: op.KeyBytes(), ModRevision: kv.rev}},
+		}
+		return resp.OpResponse(), nil
+	}
+	kv.rev++
+	resp := &clientv3.PutResponse{Header: &pb.ResponseHeader{Revision: kv.rev}}
+	return resp.OpResponse(), nil
+}
+
+func (kv *fakeKV) Txn(ctx context.Context) clientv3.Txn { return &fakeTxn{kv: kv} }
+
+// fakeTxn always succeeds and bumps the revision.
+type fakeTxn struct{ kv *fakeKV }
+
+func (txn *fakeTxn) If(cs ...clientv3.Cmp) clientv3.Txn   { return txn }
+func (txn *fakeTxn) Then(ops ...clientv3.Op) clientv3.Txn { return txn }
+func (txn *fakeTxn) Else(ops ...clientv3.Op) clientv3.Txn { return txn }
+
+func (txn *fakeTxn) Commit() (*clientv3.TxnResponse, error) {
+	txn.kv.mu.Lock()
+	defer txn.kv.mu.Unlock()
+	txn.kv.rev++
+	return &clientv3.TxnResponse{Header: &pb.ResponseHeader{Revision: txn.kv.rev}, Succeeded: true}, nil
+}
+
+func (kv *fakeKV) getCount() int {
+	kv.mu.Lock()
+	defer kv.mu.Unlock()
+	return kv.gets
+}
+
+type fakeWatch struct {
+	ctx      context.Context
+	key, end string
+	rev      int64
+	ch       chan clientv3.WatchResponse
+}
+
+// fakeWatcher records the watches, whose responses are sent by the test.
+type fakeWatcher struct {
+	clientv3.Watcher
+
+	mu      sync.Mutex
+	watches []*fakeWatch
+}
+
+func (w *fakeWatcher) Watch(ctx context.Context, key string, opts ...clientv3.OpOption) clientv3.WatchChan {
+	op := clientv3.OpGet(key, opts...)
+	fw := &fakeWatch{ctx: ctx, key: key, end: string(op.RangeBytes()), rev: op.Rev(), ch: make(chan clientv3.WatchResponse)}
+	go func() {
+		<-ctx.Done()
+		close(fw.ch)
+	}()
+	w.mu.Lock()
+	w.watches = append(w.watches, fw)
+	w.mu.Unlock()
+	return fw.ch
+}
+
+// waitWatches waits until n watches were issued and returns them.
+func (w *fakeWatcher) waitWatches(t *testing.T, n int) []*fakeWatch {
+	for i := 0; ; i++ {
+		w.mu.Lock()
+		watches := w.watches
+		w.mu.Unlock()
+		if len(watches) >= n {
+			return watches
+		}
+		if i == 1000 {
+			t.Fatalf("got %d watches, want %d", len(watches), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// waitUncached waits until a Get of key is no longer served from the cache.
+func waitUncached(t *testing.T, kv clientv3.KV, mkv *fakeKV, key string) {
+	for i := 0; ; i++ {
+		gets := mkv.getCount()
+		if _, err := kv.Get(t.Context(), key, clientv3.WithSerializable()); err != nil {
+			t.Fatal(err)
+		}
+		if mkv.getCount() > gets {
+			return
+		}
+		if i == 1000 {
+			t.Fatalf("%q is still cached", key)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func newTestKV(t *testing.T, maxRanges int) (clientv3.KV, *fakeKV, *fakeWatcher) {
+	mkv := &fakeKV{rev: 1}
+	w := &fakeWatcher{}
+	kv, closeCache := NewKV(mkv, w, maxRanges)
+	t.Cleanup(closeCache)
+	return kv, mkv, w
+}
+
+func TestCacheServesSerializableGets(t *testing.T) {
+	kv, mkv, w := newTestKV(t, DefaultMaxRanges)
+
+	for i := 0; i < 3; i++ {
+		resp, err := kv.Get(t.Context(), "foo", clientv3.WithSerializable())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(resp.Kvs[0].Key) != "foo" {
+			t.Fatalf("unexpected response %+v", resp)
+		}
+	}
+	if gets := mkv.getCount(); gets != 1 {
+		t.Fatalf("got %d requests, want 1", gets)
+	}
+	watches := w.waitWatches(t, 1)
+	if watches[0].key != "foo" || watches[0].end != "" || watches[0].rev != 2 {
+		t.Fatalf("unexpected watch %+v", watches[0])
+	}
+
+	// Gets differing in their options are cached separately, on the same watch.
+	if _, err := kv.Get(t.Context(), "foo", clientv3.WithSerializable(), clientv3.WithKeysOnly()); err != nil {
+		t.Fatal(err)
+	}
+	if gets := mkv.getCount(); gets != 2 {
+		t.Fatalf("got %d requests, want 2", gets)
+	}
+
+	// linearizable Gets and Gets at a revision bypass the cache.
+	if _, err := kv.Get(t.Context(), "foo"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kv.Get(t.Context(), "foo", clientv3.WithSerializable(), clientv3.WithRev(1)); err != nil {
+		t.Fatal(err)
+	}
+	if gets := mkv.getCount(); gets != 4 {
+		t.Fatalf("got %d requests, want 4", gets)
+	}
+	if watches := w.waitWatches(t, 1); len(watches) != 1 {
+		t.Fatalf("got %d watches, want 1", len(watches))
+	}
+}
+
+func TestCacheInvalidatedByWatch(t *testing.T) {
+	kv, mkv, w := newTestKV(t, DefaultMaxRanges)
+
+	if _, err := kv.Get(t.Context(), "foo", clientv3.WithSerializable(), clientv3.WithPrefix()); err != nil {
+		t.Fatal(err)
+	}
+	fw := w.waitWatches(t, 1)[0]
+	if fw.key != "foo" || fw.end != "fop" {
+		t.Fatalf("unexpected watch %+v", fw)
+	}
+
+	// the creation of the watch leaves the cache as is.
+	fw.ch <- clientv3.WatchResponse{Created: true}
+	if _, err := kv.Get(t.Context(), "foo", clientv3.WithSerializable(), clientv3.WithPrefix()); err != nil {
+		t.Fatal(err)
+	}
+	if gets := mkv.getCount(); gets != 1 {
+		t.Fatalf("got %d requests, want 1", gets)
+	}
+
+	fw.ch <- clientv3.WatchResponse{Events: []*clientv3.Event{{Type: mvccpb.PUT, Kv: &mvccpb.KeyValue{Key: []byte("foo/bar")}}}}
+	<-fw.ctx.Done()
+	waitUncached(t, kv, mkv, "foo")
+}
+
+func TestCacheInvalidatedByWrites(t *testing.T) {
+	kv, mkv, _ := newTestKV(t, DefaultMaxRanges)
+	get := func(key string, opts ...clientv3.OpOption) {
+		t.Helper()
+		if _, err := kv.Get(t.Context(), key, append(opts, clientv3.WithSerializable())...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	get("a")
+	get("b", clientv3.WithPrefix())
+	get("c", clientv3.WithFromKey())
+
+	tcs := []struct {
+		name  string
+		write func() error
+		gets  int
+	}{
+		{
+			name:  "put outside of every range",
+			write: func() error { _, err := kv.Put(t.Context(), "a0", "v"); return err },
+			gets:  0,
+		},
+		{
+			name:  "put in a prefix",
+			write: func() error { _, err := kv.Put(t.Context(), "b/x", "v"); return err },
+			gets:  1,
+		},
+		{
+			name:  "delete of a range",
+			write: func() error { _, err := kv.Delete(t.Context(), "", clientv3.WithRange("b")); return err },
+			gets:  1,
+		},
+		{
+			name: "txn writing from a key",
+			write: func() error {
+				_, err := kv.Txn(t.Context()).Then(clientv3.OpGet("a")).Else(clientv3.OpPut("z", "v")).Commit()
+				return err
+			},
+			gets: 1,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.write(); err != nil {
+				t.Fatal(err)
+			}
+			gets := mkv.getCount()
+			get("a")
+			get("b", clientv3.WithPrefix())
+			get("c", clientv3.WithFromKey())
+			if got := mkv.getCount() - gets; got != tc.gets {
+				t.Fatalf("got %d requests, want %d", got, tc.gets)
+			}
+		})
+	}
+}
+
+func TestCacheEvictsLeastRecentlyRead(t *testing.T) {
+	kv, mkv, w := newTestKV(t, 2)
+	for _, key := range []string{"a", "b", "a", "c"} {
+		if _, err := kv.Get(t.Context(), key, clientv3.WithSerializable()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// "b" was evicted, and its watch canceled.
+	for _, fw := range w.waitWatches(t, 3) {
+		if fw.key == "b" {
+			<-fw.ctx.Done()
+		}
+	}
+	gets := mkv.getCount()
+	for _, key := range []string{"a", "c", "b"} {
+		if _, err := kv.Get(t.Context(), key, clientv3.WithSerializable()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := mkv.getCount() - gets; got != 1 {
+		t.Fatalf("got %d requests, want 1", got)
+	}
+}
+
+func TestCacheClose(t *testing.T) {
+	mkv := &fakeKV{rev: 1}
+	w := &fakeWatcher{}
+	kv, closeCache := NewKV(mkv, w, DefaultMaxRanges)
+	if _, err := kv.Get(t.Context(), "foo", clientv3.WithSerializable()); err != nil {
+		t.Fatal(err)
+	}
+	closeCache()
+	<-w.waitWatches(t, 1)[0].ctx.Done()
+
+	// Gets are still served, but no longer cached.
+	for i := 0; i < 2; i++ {
+		if _, err := kv.Get(t.Context(), "foo", clientv3.WithSerializable()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if gets := mkv.getCount(); gets != 3 {
+		t.Fatalf("got %d requests, want 3", gets)
+	}
+}
+
+func TestOverlaps(t *testing.T) {
+	tcs := []struct {
+		key1, end1, key2, end2 string
+		want                   bool
+	}{
+		{key1: "a", key2: "a", want: true},
+		{key1: "a", key2: "b", want: false},
+		{key1: "a", key2: "a", end2: "b", want: true},
+		{key1: "b", key2: "a", end2: "b", want: false},
+		{key1: "a", end1: "c", key2: "b", end2: "d", want: true},
+		{key1: "a", end1: "b", key2: "b", end2: "c", want: false},
+		{key1: "z", key2: "b", end2: "\x00", want: true},
+		{key1: "a", key2: "b", end2: "\x00", want: false},
+		{key1: "a", end1: "\x00", key2: "b", end2: "\x00", want: true},
+	}
+	for _, tc := range tcs {
+		if got := overlaps([]byte(tc.key1), []byte(tc.end1), []byte(tc.key2), []byte(tc.end2)); got != tc.want {
+			t.Errorf("overlaps(%q, %q, %q, %q) = %t, want %t", tc.key1, tc.end1, tc.key2, tc.end2, got, tc.want)
+		}
+		if got := overlaps([]byte(tc.key2), []byte(tc.end2), []byte(tc.key1), []byte(tc.end1)); got != tc.want {
+			t.Errorf("overlaps(%q, %q, %q, %q) = %t, want %t", tc.key2, tc.end2, tc.key1, tc.end1, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package readcache
+
+import (
+	"bytes"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// writeOverlaps tells whether op may write a key of the range [key, end).
+func writeOverlaps(op clientv3.Op, key, end []byte) bool {
+	switch {
+	case op.IsPut():
+		return overlaps(op.KeyBytes(), nil, key, end)
+	case op.IsDelete():
+		return overlaps(op.KeyBytes(), op.RangeBytes(), key, end)
+	case op.IsTxn():
+		_, thenOps, elseOps := op.Txn()
+		for _, ops := range [][]clientv3.Op{thenOps, elseOps} {
+			for _, o := range ops {
+				if writeOverlaps(o, key, end) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// overlaps tells whether the ranges [key1, end1) and [key2, end2) intersect,
+// with the end of a range interpreted as in range requests.
+func overlaps(key1, end1, key2, end2 []byte) bool {
+	lo1, hi1 := interval(key1, end1)
+	lo2, hi2 := interval(key2, end2)
+	return (hi2 == nil || bytes.Compare(lo1, hi2) < 0) && (hi1 == nil || bytes.Compare(lo2, hi1) < 0)
+}
+
+// interval returns the half-open interval of keys of a range. A nil hi
+// means the range has no upper bound.
+func interval(key, end []byte) (lo, hi []byte) {
+	switch {
+	case len(end) == 0:
+		return key, append(append([]byte{}, key...), 0)
+	case len(end) == 1 && end[0] == 0:
+		return key, nil
+	}
+	return key, end
+}
@@ -0,0 +1,49 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package replay records the unary RPCs issued by a clientv3 client, together
+// with their responses, and replays them later without a live cluster. It is
+// intended for deterministic tests of code built on top of clientv3.
+//
+// First, record a session against a real cluster:
+//
+//	f, err := os.Create("session.jsonl")
+//	if err != nil {
+//		// handle error!
+//	}
+//	rec := replay.NewRecorder(f)
+//	cli, err := clientv3.New(clientv3.Config{
+//		Endpoints:   []string{"localhost:2379"},
+//		DialOptions: rec.DialOptions(),
+//	})
+//
+// Then, replay it in a test. The endpoint is never contacted; every request
+// must match the recorded one, in order:
+//
+//	f, err := os.Open("session.jsonl")
+//	if err != nil {
+//		// handle error!
+//	}
+//	p, err := replay.NewPlayer(f)
+//	if err != nil {
+//		// handle error!
+//	}
+//	cli, err := clientv3.New(clientv3.Config{
+//		Endpoints:   []string{"localhost:2379"},
+//		DialOptions: p.DialOptions(),
+//	})
+//
+// Streaming RPCs such as Watch and LeaseKeepAlive are not recorded, and fail
+// with codes.Unimplemented during replay.
+package replay
@@ -0,0 +1,221 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrMismatch is returned by a Player when a request differs from the recorded one.
+	ErrMismatch = errors.New("replay: request does not match the recording")
+	// ErrExhausted is returned by a Player when more requests are issued than recorded.
+	ErrExhausted = errors.New("replay: no more recorded requests")
+)
+
+// Entry is a single recorded unary RPC.
+type Entry struct {
+	// Method is the full gRPC method name, e.g. "/etcdserverpb.KV/Put".
+	Method string `json:"method"`
+	// Request is the protobuf encoded request.
+	Request []byte `json:"request"`
+	// Response is the protobuf encoded response, empty if the call failed.
+	Response []byte `json:"response,omitempty"`
+	// Code and Message hold the gRPC status of a failed call.
+	Code    codes.Code `json:"code,omitempty"`
+	Message string     `json:"message,omitempty"`
+}
+
+type marshaler interface {
+	Marshal() ([]byte, error)
+}
+
+type unmarshaler interface {
+	Unmarshal([]byte) error
+}
+
+func marshal(msg any) ([]byte, error) {
+	m, ok := msg.(marshaler)
+	if !ok {
+		return nil, fmt.Errorf("replay: %T is not a protobuf message", msg)
+	}
+	return m.Marshal()
+}
+
+// Recorder is a gRPC client interceptor that writes every unary RPC and its
+// result, as one JSON encoded Entry per line, to the underlying writer.
+type Recorder struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+}
+
+// NewRecorder returns a Recorder writing entries to w.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{enc: json.NewEncoder(w)}
+}
+
+// DialOptions returns the dial options installing the recorder on a client,
+// to be used as clientv3.Config.DialOptions.
+func (r *Recorder) DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{grpc.WithChainUnaryInterceptor(r.UnaryClientInterceptor())}
+}
+
+// UnaryClientInterceptor returns the interceptor recording unary RPCs.
+func (r *Recorder) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		r.record(method, req, reply, err)
+		return err
+	}
+}
+
+// Err returns the first error encountered while recording, if any.
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+func (r *Recorder) record(method string, req, reply any, callErr error) {
+	e := Entry{Method: method}
+	var err error
+	if e.Request, err = marshal(req); err != nil {
+		r.setErr(err)
+		return
+	}
+	if callErr != nil {
+		st := status.Convert(callErr)
+		e.Code, e.Message = st.Code(), st.Message()
+	} else if e.Response, err = marshal(reply); err != nil {
+		r.setErr(err)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = r.enc.Encode(&e)
+	}
+}
+
+func (r *Recorder) setErr(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// Player is a gRPC client interceptor answering unary RPCs from a recording
+// instead of sending them to the server.
+type Player struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+}
+
+// NewPlayer reads a recording written by a Recorder.
+func NewPlayer(rd io.Reader) (*Player, error) {
+	p := &Player{}
+	sc := bufio.NewScanner(rd)
+	sc.Buffer(nil, 64*1024*1024)
+	for sc.Scan() {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("replay: invalid entry %d: %w", len(p.entries), err)
+		}
+		p.entries = append(p.entries, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// DialOptions returns the dial options installing the player on a client,
+// to be used as clientv3.Config.DialOptions.
+func (p *Player) DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(p.UnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(p.StreamClientInterceptor()),
+	}
+}
+
+// UnaryClientInterceptor returns the interceptor replaying unary RPCs. The
+// server is never contacted.
+func (p *Player) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return p.play(method, req, reply)
+	}
+}
+
+// StreamClientInterceptor returns the interceptor rejecting streaming RPCs,
+// which cannot be replayed.
+func (p *Player) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return nil, status.Errorf(codes.Unimplemented, "replay: streaming RPC %s cannot be replayed", method)
+	}
+}
+
+// Remaining returns the number of recorded entries not replayed yet.
+func (p *Player) Remaining() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.entries) - p.next
+}
+
+func (p *Player) play(method string, req, reply any) error {
+	b, err := marshal(req)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	if p.next >= len(p.entries) {
+		p.mu.Unlock()
+		return fmt.Errorf("%w: %s", ErrExhausted, method)
+	}
+	e := p.entries[p.next]
+	if e.Method != method || !bytes.Equal(e.Request, b) {
+		p.mu.Unlock()
+		return fmt.Errorf("%w: entry %d is %s, got %s", ErrMismatch, p.next, e.Method, method)
+	}
+	p.next++
+	p.mu.Unlock()
+
+	if e.Code != codes.OK {
+		return status.Error(e.Code, e.Message)
+	}
+	u, ok := reply.(unmarshaler)
+	if !ok {
+		return fmt.Errorf("replay: %T is not a protobuf message", reply)
+	}
+	return u.Unmarshal(e.Response)
+}
@@ -0,0 +1,97 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package replay
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+const putMethod = "/etcdserverpb.KV/Put"
+
+func fakeInvoker(rev int64, err error) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		if err != nil {
+			return err
+		}
+		reply.(*pb.PutResponse).Header = &pb.ResponseHeader{Revision: rev}
+		return nil
+	}
+}
+
+func failingInvoker(t *testing.T) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		t.Fatalf("unexpected call to %s during replay", method)
+		return nil
+	}
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	var buf bytes.Buffer
+	rec := NewRecorder(&buf)
+	ri := rec.UnaryClientInterceptor()
+
+	ctx := context.Background()
+	err := ri(ctx, putMethod, &pb.PutRequest{Key: []byte("foo"), Value: []byte("bar")}, &pb.PutResponse{}, nil, fakeInvoker(2, nil))
+	require.NoError(t, err)
+	unavailable := status.Error(codes.Unavailable, "etcdserver: request timed out")
+	err = ri(ctx, putMethod, &pb.PutRequest{Key: []byte("foo"), Value: []byte("baz")}, &pb.PutResponse{}, nil, fakeInvoker(0, unavailable))
+	require.Equal(t, unavailable, err)
+	require.NoError(t, rec.Err())
+
+	p, err := NewPlayer(&buf)
+	require.NoError(t, err)
+	require.Equal(t, 2, p.Remaining())
+	pi := p.UnaryClientInterceptor()
+
+	resp := &pb.PutResponse{}
+	err = pi(ctx, putMethod, &pb.PutRequest{Key: []byte("foo"), Value: []byte("bar")}, resp, nil, failingInvoker(t))
+	require.NoError(t, err)
+	require.Equal(t, int64(2), resp.Header.Revision)
+
+	err = pi(ctx, putMethod, &pb.PutRequest{Key: []byte("foo"), Value: []byte("baz")}, &pb.PutResponse{}, nil, failingInvoker(t))
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.Equal(t, "etcdserver: request timed out", status.Convert(err).Message())
+
+	err = pi(ctx, putMethod, &pb.PutRequest{Key: []byte("foo")}, &pb.PutResponse{}, nil, failingInvoker(t))
+	require.ErrorIs(t, err, ErrExhausted)
+	require.Equal(t, 0, p.Remaining())
+}
+
+func TestReplayMismatch(t *testing.T) {
+	var buf bytes.Buffer
+	rec := NewRecorder(&buf)
+	err := rec.UnaryClientInterceptor()(context.Background(), putMethod, &pb.PutRequest{Key: []byte("foo")}, &pb.PutResponse{}, nil, fakeInvoker(2, nil))
+	require.NoError(t, err)
+
+	p, err := NewPlayer(&buf)
+	require.NoError(t, err)
+	pi := p.UnaryClientInterceptor()
+
+	err = pi(context.Background(), "/etcdserverpb.KV/DeleteRange", &pb.DeleteRangeRequest{Key: []byte("foo")}, &pb.DeleteRangeResponse{}, nil, failingInvoker(t))
+	require.ErrorIs(t, err, ErrMismatch)
+	err = pi(context.Background(), putMethod, &pb.PutRequest{Key: []byte("bar")}, &pb.PutResponse{}, nil, failingInvoker(t))
+	require.ErrorIs(t, err, ErrMismatch)
+	// a mismatch does not consume the recorded entry.
+	require.Equal(t, 1, p.Remaining())
+}
@@ -0,0 +1,124 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+)
+
+// requestSizeLimit is the smallest maximum request size reported by the
+// servers. It is discovered on first use and then cached.
+type requestSizeLimit struct {
+	mu    sync.Mutex
+	known bool
+	// limit is 0 when no server reports a limit.
+	limit uint64
+	// discovering is closed once the discovery in flight, if any, is done.
+	discovering chan struct{}
+}
+
+// checkRequestSize fails write requests that the servers would reject for
+// being larger than their maximum request size.
+func (c *Client) checkRequestSize(ctx context.Context, req any) error {
+	if !c.cfg.AutoMaxRequestBytes {
+		return nil
+	}
+	var size int
+	switch r := req.(type) {
+	case *pb.PutRequest:
+		size = r.Size()
+	case *pb.DeleteRangeRequest:
+		size = r.Size()
+	case *pb.TxnRequest:
+		size = r.Size()
+	default:
+		return nil
+	}
+	limit := c.discoverMaxRequestBytes(ctx)
+	if limit == 0 || uint64(size) <= limit {
+		return nil
+	}
+	return fmt.Errorf("%w: request of %d bytes exceeds the server limit of %d bytes (--max-request-bytes)",
+		rpctypes.ErrRequestTooLarge, size, limit)
+}
+
+// discoverMaxRequestBytes returns the smallest maximum request size of the
+// client endpoints, or 0 if it is not known. Servers that do not report the
+// limit are treated as having none; the lookup is retried on the next
+// request if no endpoint answered. Concurrent requests share the lookup in
+// flight instead of blocking on each other behind the lock.
+func (c *Client) discoverMaxRequestBytes(ctx context.Context) uint64 {
+	l := c.maxRequestBytes
+	l.mu.Lock()
+	if l.known {
+		l.mu.Unlock()
+		return l.limit
+	}
+	if donec := l.discovering; donec != nil {
+		l.mu.Unlock()
+		select {
+		case <-donec:
+		case <-ctx.Done():
+			return 0
+		}
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return l.limit
+	}
+	donec := make(chan struct{})
+	l.discovering = donec
+	l.mu.Unlock()
+
+	limit, answered := c.fetchMaxRequestBytes(ctx)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if answered {
+		l.known, l.limit = true, limit
+	}
+	l.discovering = nil
+	close(donec)
+	return limit
+}
+
+// fetchMaxRequestBytes asks every client endpoint for its maximum request
+// size, returning the smallest one and whether any endpoint answered.
+func (c *Client) fetchMaxRequestBytes(ctx context.Context) (limit uint64, answered bool) {
+	for _, ep := range c.Endpoints() {
+		resp, err := c.Maintenance.Config(ctx, ep)
+		if err != nil {
+			if status.Code(err) == codes.Unimplemented {
+				answered = true
+				continue
+			}
+			c.GetLogger().Debug("failed to get server config", zap.String("endpoint", ep), zap.Error(err))
+			continue
+		}
+		answered = true
+		if resp.MaxRequestBytes > 0 && (limit == 0 || resp.MaxRequestBytes < limit) {
+			limit = resp.MaxRequestBytes
+		}
+	}
+	return limit, answered
+}
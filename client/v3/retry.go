@@ -108,6 +108,10 @@ func (rkv *retryKVClient) Range(ctx context.Context, in *pb.RangeRequest, opts .
 	return rkv.kc.Range(ctx, in, append(opts, withRepeatablePolicy())...)
 }
 
+func (rkv *retryKVClient) RangeStream(ctx context.Context, in *pb.RangeRequest, opts ...grpc.CallOption) (stream pb.KV_RangeStreamClient, err error) {
+	return rkv.kc.RangeStream(ctx, in, append(opts, withRepeatablePolicy())...)
+}
+
 func (rkv *retryKVClient) Put(ctx context.Context, in *pb.PutRequest, opts ...grpc.CallOption) (resp *pb.PutResponse, err error) {
 	return rkv.kc.Put(ctx, in, opts...)
 }
@@ -155,6 +159,18 @@ func (rlc *retryLeaseClient) LeaseKeepAlive(ctx context.Context, opts ...grpc.Ca
 	return rlc.lc.LeaseKeepAlive(ctx, append(opts, withRepeatablePolicy())...)
 }
 
+func (rlc *retryLeaseClient) LeaseGrantBatch(ctx context.Context, in *pb.LeaseGrantBatchRequest, opts ...grpc.CallOption) (resp *pb.LeaseGrantBatchResponse, err error) {
+	return rlc.lc.LeaseGrantBatch(ctx, in, append(opts, withRepeatablePolicy())...)
+}
+
+func (rlc *retryLeaseClient) LeaseRevokeBatch(ctx context.Context, in *pb.LeaseRevokeBatchRequest, opts ...grpc.CallOption) (resp *pb.LeaseRevokeBatchResponse, err error) {
+	return rlc.lc.LeaseRevokeBatch(ctx, in, append(opts, withRepeatablePolicy())...)
+}
+
+func (rlc *retryLeaseClient) LeaseKeepAliveBatch(ctx context.Context, in *pb.LeaseKeepAliveBatchRequest, opts ...grpc.CallOption) (resp *pb.LeaseKeepAliveBatchResponse, err error) {
+	return rlc.lc.LeaseKeepAliveBatch(ctx, in, append(opts, withRepeatablePolicy())...)
+}
+
 type retryClusterClient struct {
 	cc pb.ClusterClient
 }
@@ -186,6 +202,11 @@ func (rcc *retryClusterClient) MemberPromote(ctx context.Context, in *pb.MemberP
 	return rcc.cc.MemberPromote(ctx, in, opts...)
 }
 
+// MemberReplace is not retried, since the member may have been replaced already.
+func (rcc *retryClusterClient) MemberReplace(ctx context.Context, in *pb.MemberReplaceRequest, opts ...grpc.CallOption) (resp *pb.MemberReplaceResponse, err error) {
+	return rcc.cc.MemberReplace(ctx, in, opts...)
+}
+
 type retryMaintenanceClient struct {
 	mc pb.MaintenanceClient
 }
@@ -229,6 +250,50 @@ func (rmc *retryMaintenanceClient) Downgrade(ctx context.Context, in *pb.Downgra
 	return rmc.mc.Downgrade(ctx, in, opts...)
 }
 
+func (rmc *retryMaintenanceClient) Config(ctx context.Context, in *pb.ConfigRequest, opts ...grpc.CallOption) (resp *pb.ConfigResponse, err error) {
+	return rmc.mc.Config(ctx, in, append(opts, withRepeatablePolicy())...)
+}
+
+func (rmc *retryMaintenanceClient) KeyAccessTimes(ctx context.Context, in *pb.KeyAccessTimesRequest, opts ...grpc.CallOption) (resp *pb.KeyAccessTimesResponse, err error) {
+	return rmc.mc.KeyAccessTimes(ctx, in, append(opts, withRepeatablePolicy())...)
+}
+
+func (rmc *retryMaintenanceClient) MembershipCheck(ctx context.Context, in *pb.MembershipCheckRequest, opts ...grpc.CallOption) (resp *pb.MembershipCheckResponse, err error) {
+	return rmc.mc.MembershipCheck(ctx, in, append(opts, withRepeatablePolicy())...)
+}
+
+func (rmc *retryMaintenanceClient) RotateEncryptionKey(ctx context.Context, in *pb.RotateEncryptionKeyRequest, opts ...grpc.CallOption) (resp *pb.RotateEncryptionKeyResponse, err error) {
+	return rmc.mc.RotateEncryptionKey(ctx, in, opts...)
+}
+
+func (rmc *retryMaintenanceClient) ReadOnly(ctx context.Context, in *pb.ReadOnlyRequest, opts ...grpc.CallOption) (resp *pb.ReadOnlyResponse, err error) {
+	return rmc.mc.ReadOnly(ctx, in, append(opts, withRepeatablePolicy())...)
+}
+
+func (rmc *retryMaintenanceClient) FollowerLag(ctx context.Context, in *pb.FollowerLagRequest, opts ...grpc.CallOption) (resp *pb.FollowerLagResponse, err error) {
+	return rmc.mc.FollowerLag(ctx, in, append(opts, withRepeatablePolicy())...)
+}
+
+func (rmc *retryMaintenanceClient) HashKVCheck(ctx context.Context, in *pb.HashKVCheckRequest, opts ...grpc.CallOption) (resp *pb.HashKVCheckResponse, err error) {
+	return rmc.mc.HashKVCheck(ctx, in, append(opts, withRepeatablePolicy())...)
+}
+
+func (rmc *retryMaintenanceClient) MirrorStatus(ctx context.Context, in *pb.MirrorStatusRequest, opts ...grpc.CallOption) (resp *pb.MirrorStatusResponse, err error) {
+	return rmc.mc.MirrorStatus(ctx, in, append(opts, withRepeatablePolicy())...)
+}
+
+func (rmc *retryMaintenanceClient) Reclaim(ctx context.Context, in *pb.ReclaimRequest, opts ...grpc.CallOption) (stream pb.Maintenance_ReclaimClient, err error) {
+	return rmc.mc.Reclaim(ctx, in, opts...)
+}
+
+func (rmc *retryMaintenanceClient) DowngradeCheck(ctx context.Context, in *pb.DowngradeCheckRequest, opts ...grpc.CallOption) (resp *pb.DowngradeCheckResponse, err error) {
+	return rmc.mc.DowngradeCheck(ctx, in, append(opts, withRepeatablePolicy())...)
+}
+
+func (rmc *retryMaintenanceClient) DefragmentStatus(ctx context.Context, in *pb.DefragmentStatusRequest, opts ...grpc.CallOption) (stream pb.Maintenance_DefragmentStatusClient, err error) {
+	return rmc.mc.DefragmentStatus(ctx, in, append(opts, withRepeatablePolicy())...)
+}
+
 type retryAuthClient struct {
 	ac pb.AuthClient
 }
@@ -304,6 +369,10 @@ func (rac *retryAuthClient) RoleRevokePermission(ctx context.Context, in *pb.Aut
 	return rac.ac.RoleRevokePermission(ctx, in, opts...)
 }
 
+func (rac *retryAuthClient) TokenRevoke(ctx context.Context, in *pb.AuthTokenRevokeRequest, opts ...grpc.CallOption) (resp *pb.AuthTokenRevokeResponse, err error) {
+	return rac.ac.TokenRevoke(ctx, in, opts...)
+}
+
 func (rac *retryAuthClient) Authenticate(ctx context.Context, in *pb.AuthenticateRequest, opts ...grpc.CallOption) (resp *pb.AuthenticateResponse, err error) {
 	return rac.ac.Authenticate(ctx, in, opts...)
 }
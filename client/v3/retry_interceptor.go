@@ -41,7 +41,15 @@ func (c *Client) unaryClientInterceptor(optFuncs ...retryOption) grpc.UnaryClien
 	intOpts := reuseOrNewWithCallOptions(defaultOptions, optFuncs)
 	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
 		ctx = withVersion(ctx)
+		ctx = withCaller(ctx, c.cfg.CallerLabel)
+		ctx = withNamespace(ctx, c.cfg.Namespace)
+		if err := c.checkRequestSize(ctx, req); err != nil {
+			return err
+		}
 		grpcOpts, retryOpts := filterCallOptions(opts)
+		if p, ok := retryPolicyFromContext(ctx); ok {
+			retryOpts = append(retryOpts, c.withRetryPolicy(c.retryPolicy.override(p)))
+		}
 		callOpts := reuseOrNewWithCallOptions(intOpts, retryOpts)
 		// short circuit for simplicity, and avoiding allocations.
 		if callOpts.max == 0 {
@@ -109,6 +117,8 @@ func (c *Client) streamClientInterceptor(optFuncs ...retryOption) grpc.StreamCli
 	intOpts := reuseOrNewWithCallOptions(defaultOptions, optFuncs)
 	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
 		ctx = withVersion(ctx)
+		ctx = withCaller(ctx, c.cfg.CallerLabel)
+		ctx = withNamespace(ctx, c.cfg.Namespace)
 		// getToken automatically. Otherwise, auth token may be invalid after watch reconnection because the token has expired
 		// (see https://github.com/etcd-io/etcd/issues/11954 for more).
 		err := c.getToken(ctx)
@@ -332,10 +342,15 @@ func isSafeRetry(c *Client, err error, callOpts *options) bool {
 	if errors.Is(err, rpctypes.ErrGRPCNotSupportedForLearner) && len(c.Endpoints()) > 1 {
 		return true
 	}
+	// Likewise, a witness serves no request, the next attempt may pick a
+	// member which does.
+	if errors.Is(err, rpctypes.ErrGRPCNotSupportedForWitness) && len(c.Endpoints()) > 1 {
+		return true
+	}
 
 	switch callOpts.retryPolicy {
 	case repeatable:
-		return isSafeRetryImmutableRPC(err)
+		return isSafeRetryImmutableRPC(err) || isRetryableCode(err, callOpts.retryableCodes)
 	case nonRepeatable:
 		return isSafeRetryMutableRPC(err)
 	default:
@@ -396,10 +411,11 @@ func withBackoff(bf backoffFunc) retryOption {
 }
 
 type options struct {
-	retryPolicy retryPolicy
-	max         uint
-	backoffFunc backoffFunc
-	retryAuth   bool
+	retryPolicy    retryPolicy
+	max            uint
+	backoffFunc    backoffFunc
+	retryAuth      bool
+	retryableCodes []codes.Code
 }
 
 // retryOption is a grpc.CallOption that is local to clientv3's retry interceptor.
@@ -0,0 +1,152 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy configures how the client retries the requests that failed
+// with a retryable error. Zero fields take their default value.
+//
+// Requests are retried against the other endpoints of a quorum right away,
+// and the client backs off once each round across a quorum failed. The
+// n-th backoff waits InitialBackoff*BackoffMultiplier^(n-1), capped at
+// MaxBackoff and randomized by JitterFraction.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of a unary request, the
+	// first one included. 1 disables retries. Defaults to 100.
+	MaxAttempts uint `json:"max-attempts"`
+
+	// InitialBackoff is the wait of the first backoff. Defaults to 25ms.
+	InitialBackoff time.Duration `json:"initial-backoff"`
+
+	// MaxBackoff caps the wait of the backoffs. 0 means no cap.
+	MaxBackoff time.Duration `json:"max-backoff"`
+
+	// BackoffMultiplier is the factor the wait grows by after each backoff.
+	// Values below 1 keep the wait constant.
+	BackoffMultiplier float64 `json:"backoff-multiplier"`
+
+	// JitterFraction randomizes each wait by up to the given fraction of it.
+	// Defaults to 0.10.
+	JitterFraction float64 `json:"jitter-fraction"`
+
+	// RetryableCodes are the status codes on which read-only requests are
+	// retried, in addition to Unavailable. Requests that may modify the
+	// cluster are only retried when they were not sent to the server,
+	// whatever the codes.
+	RetryableCodes []codes.Code `json:"retryable-codes"`
+}
+
+// retryPolicy returns the retry policy of the client, filled with the
+// legacy retry fields and the defaults.
+func (cfg *Config) retryPolicy() RetryPolicy {
+	p := RetryPolicy{
+		MaxAttempts:       defaultUnaryMaxRetries,
+		InitialBackoff:    defaultBackoffWaitBetween,
+		BackoffMultiplier: 1,
+		JitterFraction:    defaultBackoffJitterFraction,
+	}
+	if cfg.MaxUnaryRetries > 0 {
+		p.MaxAttempts = cfg.MaxUnaryRetries
+	}
+	if cfg.BackoffWaitBetween > 0 {
+		p.InitialBackoff = cfg.BackoffWaitBetween
+	}
+	if cfg.BackoffJitterFraction > 0 {
+		p.JitterFraction = cfg.BackoffJitterFraction
+	}
+	if cfg.RetryPolicy != nil {
+		p = p.override(*cfg.RetryPolicy)
+	}
+	return p
+}
+
+// override returns p with the non-zero fields of o.
+func (p RetryPolicy) override(o RetryPolicy) RetryPolicy {
+	if o.MaxAttempts > 0 {
+		p.MaxAttempts = o.MaxAttempts
+	}
+	if o.InitialBackoff > 0 {
+		p.InitialBackoff = o.InitialBackoff
+	}
+	if o.MaxBackoff > 0 {
+		p.MaxBackoff = o.MaxBackoff
+	}
+	if o.BackoffMultiplier > 0 {
+		p.BackoffMultiplier = o.BackoffMultiplier
+	}
+	if o.JitterFraction > 0 {
+		p.JitterFraction = o.JitterFraction
+	}
+	if o.RetryableCodes != nil {
+		p.RetryableCodes = o.RetryableCodes
+	}
+	return p
+}
+
+// withRetryPolicy applies the retry policy p to this call, or interceptor.
+func (c *Client) withRetryPolicy(p RetryPolicy) retryOption {
+	bf := c.roundRobinQuorumBackoff(p)
+	return retryOption{applyFunc: func(o *options) {
+		o.max = p.MaxAttempts
+		o.backoffFunc = bf
+		o.retryableCodes = p.RetryableCodes
+	}}
+}
+
+// roundRobinQuorumBackoff retries against quorum between each backoff.
+// This is intended for use with a round robin load balancer.
+func (c *Client) roundRobinQuorumBackoff(p RetryPolicy) backoffFunc {
+	return func(attempt uint) time.Duration {
+		// after each round robin across quorum, backoff for our wait between duration
+		n := uint(len(c.Endpoints()))
+		quorum := (n/2 + 1)
+		if attempt%quorum == 0 {
+			waitBetween := p.backoff(attempt / quorum)
+			c.lg.Debug("backoff", zap.Uint("attempt", attempt), zap.Uint("quorum", quorum), zap.Duration("waitBetween", waitBetween), zap.Float64("jitterFraction", p.JitterFraction))
+			return jitterUp(waitBetween, p.JitterFraction)
+		}
+		c.lg.Debug("backoff skipped", zap.Uint("attempt", attempt), zap.Uint("quorum", quorum))
+		return 0
+	}
+}
+
+// backoff returns the wait of the n-th backoff, before jitter.
+func (p RetryPolicy) backoff(n uint) time.Duration {
+	wait := float64(p.InitialBackoff)
+	if p.BackoffMultiplier > 1 && n > 1 {
+		wait *= math.Pow(p.BackoffMultiplier, float64(n-1))
+	}
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		return p.MaxBackoff
+	}
+	if wait > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(wait)
+}
+
+// isRetryableCode tells whether err carries one of the given status codes.
+func isRetryableCode(err error, retryableCodes []codes.Code) bool {
+	return len(retryableCodes) > 0 && slices.Contains(retryableCodes, status.Code(err))
+}
@@ -0,0 +1,171 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConfigRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want RetryPolicy
+	}{
+		{
+			name: "defaults",
+			want: RetryPolicy{
+				MaxAttempts:       defaultUnaryMaxRetries,
+				InitialBackoff:    defaultBackoffWaitBetween,
+				BackoffMultiplier: 1,
+				JitterFraction:    defaultBackoffJitterFraction,
+			},
+		},
+		{
+			name: "legacy fields",
+			cfg: Config{
+				MaxUnaryRetries:       5,
+				BackoffWaitBetween:    time.Second,
+				BackoffJitterFraction: 0.5,
+			},
+			want: RetryPolicy{
+				MaxAttempts:       5,
+				InitialBackoff:    time.Second,
+				BackoffMultiplier: 1,
+				JitterFraction:    0.5,
+			},
+		},
+		{
+			name: "policy takes precedence",
+			cfg: Config{
+				MaxUnaryRetries: 5,
+				RetryPolicy: &RetryPolicy{
+					MaxAttempts:       3,
+					MaxBackoff:        time.Second,
+					BackoffMultiplier: 2,
+					RetryableCodes:    []codes.Code{codes.ResourceExhausted},
+				},
+			},
+			want: RetryPolicy{
+				MaxAttempts:       3,
+				InitialBackoff:    defaultBackoffWaitBetween,
+				MaxBackoff:        time.Second,
+				BackoffMultiplier: 2,
+				JitterFraction:    defaultBackoffJitterFraction,
+				RetryableCodes:    []codes.Code{codes.ResourceExhausted},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.cfg.retryPolicy())
+		})
+	}
+}
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	p := RetryPolicy{
+		InitialBackoff:    100 * time.Millisecond,
+		MaxBackoff:        time.Second,
+		BackoffMultiplier: 2,
+	}
+	want := []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		800 * time.Millisecond,
+		time.Second,
+		time.Second,
+	}
+	for i, w := range want {
+		assert.Equalf(t, w, p.backoff(uint(i+1)), "backoff %d", i+1)
+	}
+
+	p.BackoffMultiplier = 0
+	assert.Equal(t, 100*time.Millisecond, p.backoff(5))
+}
+
+func TestIsSafeRetryRetryableCodes(t *testing.T) {
+	c := &Client{cfg: Config{}, epMu: new(sync.RWMutex), lg: zaptest.NewLogger(t)}
+	err := status.Error(codes.ResourceExhausted, "too many requests")
+
+	assert.False(t, isSafeRetry(c, err, &options{retryPolicy: repeatable}))
+	assert.True(t, isSafeRetry(c, err, &options{retryPolicy: repeatable, retryableCodes: []codes.Code{codes.ResourceExhausted}}))
+	assert.False(t, isSafeRetry(c, err, &options{retryPolicy: nonRepeatable, retryableCodes: []codes.Code{codes.ResourceExhausted}}))
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	c, err := NewClient(t, Config{
+		Endpoints:   []string{"127.0.0.1:12345"},
+		RetryPolicy: &RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Millisecond},
+	})
+	require.NoError(t, err)
+	defer c.Close()
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		err          error
+		wantAttempts int
+	}{
+		{
+			name:         "client policy",
+			ctx:          context.Background(),
+			err:          status.Error(codes.Unavailable, "unavailable"),
+			wantAttempts: 5,
+		},
+		{
+			name:         "per-call max attempts",
+			ctx:          WithRetryPolicy(context.Background(), RetryPolicy{MaxAttempts: 2}),
+			err:          status.Error(codes.Unavailable, "unavailable"),
+			wantAttempts: 2,
+		},
+		{
+			name:         "code not retryable",
+			ctx:          context.Background(),
+			err:          status.Error(codes.ResourceExhausted, "too many requests"),
+			wantAttempts: 1,
+		},
+		{
+			name:         "per-call retryable code",
+			ctx:          WithRetryPolicy(context.Background(), RetryPolicy{RetryableCodes: []codes.Code{codes.ResourceExhausted}}),
+			err:          status.Error(codes.ResourceExhausted, "too many requests"),
+			wantAttempts: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				attempts++
+				return tt.err
+			}
+			interceptor := c.unaryClientInterceptor(c.withRetryPolicy(c.retryPolicy))
+			err := interceptor(tt.ctx, "/etcdserverpb.KV/Range", nil, nil, c.ActiveConnection(), invoker, withRepeatablePolicy())
+			require.Equal(t, tt.err, err)
+			assert.Equal(t, tt.wantAttempts, attempts)
+		})
+	}
+}
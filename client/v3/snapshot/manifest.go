@@ -0,0 +1,280 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"time"
+
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+)
+
+const (
+	// ManifestSuffix is appended to the path of a snapshot file to get the
+	// path of its manifest.
+	ManifestSuffix = ".manifest"
+
+	// ManifestVersion is the version of the manifest format.
+	ManifestVersion = 1
+
+	// DefaultManifestChunkSize is the size of the snapshot chunks hashed in
+	// a manifest.
+	DefaultManifestChunkSize = 4 * 1024 * 1024
+)
+
+var (
+	ErrManifestMismatch     = errors.New("snapshot does not match its manifest")
+	ErrManifestNotSigned    = errors.New("snapshot manifest is not signed")
+	ErrManifestBadSignature = errors.New("snapshot manifest signature is invalid")
+)
+
+// Manifest describes a snapshot file, to detect a truncated or a modified
+// snapshot before it is restored. It holds the sha256 hash of each chunk of
+// the file and, if signed, the ed25519 signature of the manifest.
+type Manifest struct {
+	Version   int      `json:"version"`
+	Size      int64    `json:"size"`
+	ChunkSize int64    `json:"chunk-size"`
+	Chunks    []string `json:"chunks"`
+
+	// Revision is the revision of the member when the snapshot was taken.
+	Revision int64 `json:"revision,omitempty"`
+	// ClusterID and MemberID are in hexadecimal, empty if unknown.
+	ClusterID string `json:"cluster-id,omitempty"`
+	MemberID  string `json:"member-id,omitempty"`
+	// EtcdVersion is the storage version of the member.
+	EtcdVersion string    `json:"etcd-version,omitempty"`
+	CreatedAt   time.Time `json:"created-at"`
+
+	// Signature is the base64 ed25519 signature of the JSON encoding of the
+	// manifest without its signature.
+	Signature string `json:"signature,omitempty"`
+}
+
+// chunkHasher hashes the chunks of the bytes written to it.
+type chunkHasher struct {
+	chunkSize int64
+	h         hash.Hash
+	n         int64
+	size      int64
+	chunks    []string
+}
+
+func newChunkHasher(chunkSize int64) *chunkHasher {
+	return &chunkHasher{chunkSize: chunkSize, h: sha256.New()}
+}
+
+func (c *chunkHasher) Write(p []byte) (int, error) {
+	written := len(p)
+	for len(p) > 0 {
+		n := min(int64(len(p)), c.chunkSize-c.n)
+		c.h.Write(p[:n])
+		c.n += n
+		c.size += n
+		p = p[n:]
+		if c.n == c.chunkSize {
+			c.sum()
+		}
+	}
+	return written, nil
+}
+
+func (c *chunkHasher) sum() {
+	c.chunks = append(c.chunks, hex.EncodeToString(c.h.Sum(nil)))
+	c.h.Reset()
+	c.n = 0
+}
+
+// manifest returns the manifest of the bytes written.
+func (c *chunkHasher) manifest() *Manifest {
+	if c.n > 0 {
+		c.sum()
+	}
+	return &Manifest{
+		Version:   ManifestVersion,
+		Size:      c.size,
+		ChunkSize: c.chunkSize,
+		Chunks:    c.chunks,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// NewManifest returns the unsigned manifest of the snapshot read from r,
+// hashed in chunks of chunkSize bytes.
+func NewManifest(r io.Reader, chunkSize int64) (*Manifest, error) {
+	if chunkSize <= 0 {
+		return nil, fmt.Errorf("invalid manifest chunk size %d", chunkSize)
+	}
+	c := newChunkHasher(chunkSize)
+	if _, err := io.Copy(c, r); err != nil {
+		return nil, err
+	}
+	return c.manifest(), nil
+}
+
+// Verify checks that the snapshot read from r matches the manifest.
+func (m *Manifest) Verify(r io.Reader) error {
+	if m.ChunkSize <= 0 {
+		return fmt.Errorf("invalid manifest chunk size %d", m.ChunkSize)
+	}
+	c := newChunkHasher(m.ChunkSize)
+	if _, err := io.Copy(c, r); err != nil {
+		return err
+	}
+	got := c.manifest()
+	if got.Size != m.Size {
+		return fmt.Errorf("%w: %d bytes, %d expected", ErrManifestMismatch, got.Size, m.Size)
+	}
+	if len(got.Chunks) != len(m.Chunks) {
+		return fmt.Errorf("%w: %d chunks, %d expected", ErrManifestMismatch, len(got.Chunks), len(m.Chunks))
+	}
+	for i := range got.Chunks {
+		if got.Chunks[i] != m.Chunks[i] {
+			return fmt.Errorf("%w: chunk %d at offset %d has sha256 %s, %s expected",
+				ErrManifestMismatch, i, int64(i)*m.ChunkSize, got.Chunks[i], m.Chunks[i])
+		}
+	}
+	return nil
+}
+
+// VerifyFile checks that the snapshot file at path matches the manifest.
+func (m *Manifest) VerifyFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return m.Verify(f)
+}
+
+func (m *Manifest) signedBytes() ([]byte, error) {
+	unsigned := *m
+	unsigned.Signature = ""
+	return json.Marshal(&unsigned)
+}
+
+// Sign signs the manifest with key.
+func (m *Manifest) Sign(key ed25519.PrivateKey) error {
+	b, err := m.signedBytes()
+	if err != nil {
+		return err
+	}
+	m.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, b))
+	return nil
+}
+
+// VerifySignature checks that the manifest is signed by the private key of
+// key.
+func (m *Manifest) VerifySignature(key ed25519.PublicKey) error {
+	if m.Signature == "" {
+		return ErrManifestNotSigned
+	}
+	sig, err := base64.StdEncoding.DecodeString(m.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrManifestBadSignature, err)
+	}
+	b, err := m.signedBytes()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(key, b, sig) {
+		return ErrManifestBadSignature
+	}
+	return nil
+}
+
+// WriteManifest writes m to path.
+func WriteManifest(path string, m *Manifest) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(b, '\n'), fileutil.PrivateFileMode)
+}
+
+// ReadManifest reads the manifest at path.
+func ReadManifest(path string) (*Manifest, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err = json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("invalid snapshot manifest %s: %w", path, err)
+	}
+	if m.Version != ManifestVersion {
+		return nil, fmt.Errorf("unsupported snapshot manifest version %d", m.Version)
+	}
+	return &m, nil
+}
+
+// ReadSigningKey reads the PEM encoded PKCS #8 ed25519 private key at path,
+// as written by "openssl genpkey -algorithm ed25519".
+func ReadSigningKey(path string) (ed25519.PrivateKey, error) {
+	der, err := readPEM(path, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key %s: %w", path, err)
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key %s is not an ed25519 key", path)
+	}
+	return edKey, nil
+}
+
+// ReadVerifyKey reads the PEM encoded PKIX ed25519 public key at path, as
+// written by "openssl pkey -pubout".
+func ReadVerifyKey(path string) (ed25519.PublicKey, error) {
+	der, err := readPEM(path, "PUBLIC KEY")
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key %s: %w", path, err)
+	}
+	edKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not an ed25519 key", path)
+	}
+	return edKey, nil
+}
+
+func readPEM(path, typ string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != typ {
+		return nil, fmt.Errorf("%s has no PEM %q block", path, typ)
+	}
+	return block.Bytes, nil
+}
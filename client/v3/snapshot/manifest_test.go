@@ -0,0 +1,79 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifestVerify(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 10)
+	m, err := NewManifest(bytes.NewReader(data), 32)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), m.Size)
+	assert.Len(t, m.Chunks, 4)
+
+	require.NoError(t, m.Verify(bytes.NewReader(data)))
+	require.ErrorIs(t, m.Verify(bytes.NewReader(data[:99])), ErrManifestMismatch)
+	modified := bytes.Clone(data)
+	modified[40] = 'x'
+	err = m.Verify(bytes.NewReader(modified))
+	require.ErrorIs(t, err, ErrManifestMismatch)
+	assert.ErrorContains(t, err, "chunk 1 at offset 32")
+}
+
+func TestManifestSignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	dir := t.TempDir()
+	writePEM := func(name, typ string, der []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600))
+		return path
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	signKey, err := ReadSigningKey(writePEM("key.pem", "PRIVATE KEY", der))
+	require.NoError(t, err)
+	der, err = x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	verifyKey, err := ReadVerifyKey(writePEM("pub.pem", "PUBLIC KEY", der))
+	require.NoError(t, err)
+
+	m, err := NewManifest(bytes.NewReader([]byte("snapshot")), DefaultManifestChunkSize)
+	require.NoError(t, err)
+	m.Revision = 5
+	require.ErrorIs(t, m.VerifySignature(verifyKey), ErrManifestNotSigned)
+	require.NoError(t, m.Sign(signKey))
+
+	// the signature is kept by the manifest file
+	path := filepath.Join(dir, "snapshot.db"+ManifestSuffix)
+	require.NoError(t, WriteManifest(path, m))
+	m, err = ReadManifest(path)
+	require.NoError(t, err)
+	require.NoError(t, m.VerifySignature(verifyKey))
+
+	m.Revision = 6
+	require.ErrorIs(t, m.VerifySignature(verifyKey), ErrManifestBadSignature)
+}
@@ -16,6 +16,7 @@ package snapshot
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/sha256"
 	"errors"
 	"fmt"
@@ -26,6 +27,7 @@ import (
 	"github.com/dustin/go-humanize"
 	"go.uber.org/zap"
 
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/client/pkg/v3/fileutil"
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
@@ -47,13 +49,55 @@ func hasChecksum(n int64) bool {
 // the selected node.
 // Etcd <v3.6 will return "" as version.
 func SaveWithVersion(ctx context.Context, lg *zap.Logger, cfg clientv3.Config, dbPath string) (string, error) {
+	version, _, err := save(ctx, lg, cfg, dbPath, nil)
+	return version, err
+}
+
+// SaveWithManifest is SaveWithVersion also writing the manifest of the
+// snapshot to dbPath+ManifestSuffix, signed with key unless it is nil. The
+// manifest hashes the snapshot as it is fetched, and records the revision,
+// the cluster and the member of the snapshot, and the server version.
+func SaveWithManifest(ctx context.Context, lg *zap.Logger, cfg clientv3.Config, dbPath string, key ed25519.PrivateKey) (*Manifest, error) {
+	// a manifest left by a previous snapshot at dbPath must not describe
+	// the new one.
+	manifestPath := dbPath + ManifestSuffix
+	if err := os.Remove(manifestPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	hasher := newChunkHasher(DefaultManifestChunkSize)
+	version, header, err := save(ctx, lg, cfg, dbPath, hasher)
+	if err != nil {
+		return nil, err
+	}
+	m := hasher.manifest()
+	m.EtcdVersion = version
+	if header != nil {
+		m.Revision = header.Revision
+		m.ClusterID = fmt.Sprintf("%x", header.ClusterId)
+		m.MemberID = fmt.Sprintf("%x", header.MemberId)
+	}
+	if key != nil {
+		if err = m.Sign(key); err != nil {
+			return nil, err
+		}
+	}
+	if err = WriteManifest(manifestPath, m); err != nil {
+		return nil, fmt.Errorf("could not write manifest %s (%w)", manifestPath, err)
+	}
+	lg.Info("saved manifest", zap.String("path", manifestPath), zap.Bool("signed", key != nil))
+	return m, nil
+}
+
+// save fetches the snapshot to dbPath, also writing it to w if not nil, and
+// returns the server version and the header of the snapshot.
+func save(ctx context.Context, lg *zap.Logger, cfg clientv3.Config, dbPath string, w io.Writer) (string, *pb.ResponseHeader, error) {
 	cfg.Logger = lg.Named("client")
 	if len(cfg.Endpoints) != 1 {
-		return "", fmt.Errorf("snapshot must be requested to one selected node, not multiple %v", cfg.Endpoints)
+		return "", nil, fmt.Errorf("snapshot must be requested to one selected node, not multiple %v", cfg.Endpoints)
 	}
 	cli, err := clientv3.New(cfg)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 	defer func() {
 		err = cli.Close()
@@ -72,7 +116,7 @@ func SaveWithVersion(ctx context.Context, lg *zap.Logger, cfg clientv3.Config, d
 
 	f, err := os.OpenFile(partpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fileutil.PrivateFileMode)
 	if err != nil {
-		return "", fmt.Errorf("could not open %s (%w)", partpath, err)
+		return "", nil, fmt.Errorf("could not open %s (%w)", partpath, err)
 	}
 	defer func() {
 		err = f.Close()
@@ -85,7 +129,7 @@ func SaveWithVersion(ctx context.Context, lg *zap.Logger, cfg clientv3.Config, d
 	start := time.Now()
 	resp, err := cli.SnapshotWithVersion(ctx)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 	defer func() {
 		err = resp.Snapshot.Close()
@@ -95,18 +139,22 @@ func SaveWithVersion(ctx context.Context, lg *zap.Logger, cfg clientv3.Config, d
 	}()
 	lg.Info("fetching snapshot", zap.String("endpoint", cfg.Endpoints[0]))
 	var size int64
-	size, err = io.Copy(f, resp.Snapshot)
+	var dst io.Writer = f
+	if w != nil {
+		dst = io.MultiWriter(f, w)
+	}
+	size, err = io.Copy(dst, resp.Snapshot)
 	if err != nil {
-		return resp.Version, fmt.Errorf("could not write snapshot: %w", err)
+		return resp.Version, nil, fmt.Errorf("could not write snapshot: %w", err)
 	}
 	if !hasChecksum(size) {
-		return resp.Version, fmt.Errorf("sha256 checksum not found [bytes: %d]", size)
+		return resp.Version, nil, fmt.Errorf("sha256 checksum not found [bytes: %d]", size)
 	}
 	if err = fileutil.Fsync(f); err != nil {
-		return resp.Version, fmt.Errorf("could not fsync snapshot: %w", err)
+		return resp.Version, nil, fmt.Errorf("could not fsync snapshot: %w", err)
 	}
 	if err = f.Close(); err != nil {
-		return resp.Version, fmt.Errorf("could not close file descriptor: %w", err)
+		return resp.Version, nil, fmt.Errorf("could not close file descriptor: %w", err)
 	}
 	lg.Info("fetched snapshot",
 		zap.String("endpoint", cfg.Endpoints[0]),
@@ -116,8 +164,8 @@ func SaveWithVersion(ctx context.Context, lg *zap.Logger, cfg clientv3.Config, d
 	)
 
 	if err = os.Rename(partpath, dbPath); err != nil {
-		return resp.Version, fmt.Errorf("could not rename %s to %s (%w)", partpath, dbPath, err)
+		return resp.Version, nil, fmt.Errorf("could not rename %s to %s (%w)", partpath, dbPath, err)
 	}
 	lg.Info("saved", zap.String("path", dbPath))
-	return resp.Version, nil
+	return resp.Version, resp.Header, nil
 }
@@ -31,6 +31,7 @@ const (
 	SortByCreateRevision
 	SortByModRevision
 	SortByValue
+	SortByLease
 )
 
 type SortOption struct {
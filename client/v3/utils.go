@@ -15,8 +15,12 @@
 package clientv3
 
 import (
+	"fmt"
 	"math/rand"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 )
 
 // jitterUp adds random jitter to the duration.
@@ -29,3 +33,38 @@ func jitterUp(duration time.Duration, jitter float64) time.Duration {
 	multiplier := jitter * (rand.Float64()*2 - 1)
 	return time.Duration(float64(duration) * (1 + multiplier))
 }
+
+// EscapeKey returns a form of the given key, or value, that is safe to print
+// in logs and terminals. Printable characters are kept as is, while control
+// characters and bytes that are not valid UTF-8 are rendered as \xNN. A
+// backslash is rendered as \\ so that the escaped form is unambiguous.
+func EscapeKey(key string) string {
+	if isPrintable(key) {
+		return key
+	}
+	var sb strings.Builder
+	for i := 0; i < len(key); {
+		r, size := utf8.DecodeRuneInString(key[i:])
+		switch {
+		case r == '\\':
+			sb.WriteString(`\\`)
+		case r == utf8.RuneError && size <= 1, !unicode.IsPrint(r):
+			for j := i; j < i+size; j++ {
+				fmt.Fprintf(&sb, `\x%02x`, key[j])
+			}
+		default:
+			sb.WriteString(key[i : i+size])
+		}
+		i += size
+	}
+	return sb.String()
+}
+
+func isPrintable(s string) bool {
+	for _, r := range s {
+		if r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
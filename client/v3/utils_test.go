@@ -0,0 +1,55 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3
+
+import (
+	"testing"
+	"unicode"
+)
+
+func TestEscapeKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"", ""},
+		{"foo/bar", "foo/bar"},
+		{"with space", "with space"},
+		{"héllo/世界", "héllo/世界"},
+		{"foo\x00bar", `foo\x00bar`},
+		{"\x1b[31mred\x1b[0m", `\x1b[31mred\x1b[0m`},
+		{"line\nbreak\r\t", `line\x0abreak\x0d\x09`},
+		{"\xff\xfe", `\xff\xfe`},
+		{"a\\x00", `a\\x00`},
+		{"\u2028", `\xe2\x80\xa8`},
+	}
+	for _, tt := range tests {
+		if got := EscapeKey(tt.key); got != tt.want {
+			t.Errorf("EscapeKey(%q) = %q, want %q", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestEscapeKeyAllBytesPrintable(t *testing.T) {
+	var key []byte
+	for b := 0; b < 256; b++ {
+		key = append(key, byte(b))
+	}
+	for _, r := range EscapeKey(string(key)) {
+		if !unicode.IsPrint(r) {
+			t.Fatalf("escaped key contains non-printable rune %U", r)
+		}
+	}
+}
@@ -103,6 +103,17 @@ type WatchResponse struct {
 	// Created is used to indicate the creation of the watcher.
 	Created bool
 
+	// Bookmark is set on the progress notifications sent on behalf of a watcher
+	// created with WithBookmarkInterval. All the events of the watcher up to the
+	// header revision have been received, even under constant load.
+	Bookmark bool
+
+	// Snapshot is set on the response sent instead of the compacted events to a
+	// watcher created with WithSnapshotOnCompaction. Its events are put events of
+	// all the keys of the watched range at the header revision, in key order; the
+	// keys missing from it were deleted.
+	Snapshot bool
+
 	closeErr error
 
 	// cancelReason is a reason of canceling watch
@@ -211,9 +222,23 @@ type watchRequest struct {
 	// if true, split watch events when total exceeds
 	// "--max-request-bytes" flag value + 512-byte
 	fragment bool
+	// maxEventRate is the maximum number of events per second the server sends
+	maxEventRate int64
+	// bookmarkInterval is the interval in seconds between bookmarks
+	bookmarkInterval int64
+	// batchIntervalMs and batchMaxEvents batch the events the server sends
+	batchIntervalMs int64
+	batchMaxEvents  int64
+	// snapshotOnCompaction asks for a snapshot instead of the compacted events
+	snapshotOnCompaction bool
 
 	// filters is the list of events to filter out
 	filters []pb.WatchCreateRequest_FilterType
+	// value filters the values of put events must match
+	valuePrefix   []byte
+	valueContains []byte
+	minValueSize  int64
+	maxValueSize  int64
 	// get the previous key-value pair before the event happens
 	prevKV bool
 	// retc receives a chan WatchResponse once the watcher is established
@@ -307,16 +332,25 @@ func (w *watcher) Watch(ctx context.Context, key string, opts ...OpOption) Watch
 	}
 
 	wr := &watchRequest{
-		ctx:            ctx,
-		createdNotify:  ow.createdNotify,
-		key:            string(ow.key),
-		end:            string(ow.end),
-		rev:            ow.rev,
-		progressNotify: ow.progressNotify,
-		fragment:       ow.fragment,
-		filters:        filters,
-		prevKV:         ow.prevKV,
-		retc:           make(chan chan WatchResponse, 1),
+		ctx:                  ctx,
+		createdNotify:        ow.createdNotify,
+		key:                  string(ow.key),
+		end:                  string(ow.end),
+		rev:                  ow.rev,
+		progressNotify:       ow.progressNotify,
+		fragment:             ow.fragment,
+		maxEventRate:         ow.maxEventRate,
+		filters:              filters,
+		bookmarkInterval:     bookmarkIntervalSeconds(ow.bookmarkInterval),
+		batchIntervalMs:      ow.batchInterval.Milliseconds(),
+		batchMaxEvents:       ow.batchMaxEvents,
+		snapshotOnCompaction: ow.snapshotOnCompaction,
+		valuePrefix:          ow.valuePrefix,
+		valueContains:        ow.valueContains,
+		minValueSize:         ow.minValueSize,
+		maxValueSize:         ow.maxValueSize,
+		prevKV:               ow.prevKV,
+		retc:                 make(chan chan WatchResponse, 1),
 	}
 
 	ok := false
@@ -723,6 +757,8 @@ func (w *watchGRPCStream) dispatchEvent(pbresp *pb.WatchResponse) bool {
 		CompactRevision: pbresp.CompactRevision,
 		Created:         pbresp.Created,
 		Canceled:        pbresp.Canceled,
+		Bookmark:        pbresp.Bookmark,
+		Snapshot:        pbresp.Snapshot,
 		cancelReason:    pbresp.CancelReason,
 	}
 
@@ -851,7 +887,9 @@ func (w *watchGRPCStream) serveSubstream(ws *watcherStream, resumec chan struct{
 				nextRev = wr.Header.Revision + 1
 			}
 
-			if len(wr.Events) > 0 {
+			// the events of a snapshot are in key order, and it
+			// holds all the revisions up to its header one
+			if len(wr.Events) > 0 && !wr.Snapshot {
 				nextRev = wr.Events[len(wr.Events)-1].Kv.ModRevision + 1
 			}
 
@@ -1023,11 +1061,30 @@ func (wr *watchRequest) toPB() *pb.WatchRequest {
 		Filters:        wr.filters,
 		PrevKv:         wr.prevKV,
 		Fragment:       wr.fragment,
+		MaxEventRate:   wr.maxEventRate,
+		ValuePrefix:    wr.valuePrefix,
+		ValueContains:  wr.valueContains,
+		MinValueSize:   wr.minValueSize,
+		MaxValueSize:   wr.maxValueSize,
+
+		BookmarkInterval: wr.bookmarkInterval,
+		BatchIntervalMs:  wr.batchIntervalMs,
+		BatchMaxEvents:   wr.batchMaxEvents,
+
+		SnapshotOnCompaction: wr.snapshotOnCompaction,
 	}
 	cr := &pb.WatchRequest_CreateRequest{CreateRequest: req}
 	return &pb.WatchRequest{RequestUnion: cr}
 }
 
+// bookmarkIntervalSeconds rounds a bookmark interval up to whole seconds.
+func bookmarkIntervalSeconds(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return int64((d + time.Second - 1) / time.Second)
+}
+
 // toPB converts an internal progress request structure to its protobuf WatchRequest structure.
 func (pr *progressRequest) toPB() *pb.WatchRequest {
 	req := &pb.WatchProgressRequest{}
@@ -0,0 +1,223 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	v3rpc "go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+)
+
+// WatchWithRecovery watches key like Watcher.Watch, but recovers from the
+// compaction of the revisions the watch must resume from instead of failing
+// with ErrCompacted.
+//
+// On compaction, the watched range is read at the current revision, and a
+// single response synthesizing the changes missed since the last delivered
+// revision is sent: a put event for every key created or modified since,
+// and a delete event for every key known to the watch that no longer
+// exists. The watch then continues after the revision of the read, so that
+// every change is reported at most once.
+//
+// Keys are known to the watch from the events it delivered and, unless
+// compacted, from a read of the range right before the start revision. The
+// synthesized events carry no previous key-value, and the deleted keys are
+// only reported with their key and the revision of the read.
+func WatchWithRecovery(ctx context.Context, kv KV, w Watcher, key string, opts ...OpOption) WatchChan {
+	op := Op{t: tRange, key: []byte(key)}
+	op.applyOpts(opts)
+	rw := &recoveringWatch{
+		kv:    kv,
+		w:     w,
+		key:   key,
+		opts:  opts,
+		op:    op,
+		known: make(map[string]int64),
+		out:   make(chan WatchResponse),
+	}
+	go rw.run(ctx)
+	return rw.out
+}
+
+type recoveringWatch struct {
+	kv   KV
+	w    Watcher
+	key  string
+	opts []OpOption
+	// op holds the watch options, for the range and filters.
+	op Op
+
+	// known maps the keys known to exist to their mod revision.
+	known   map[string]int64
+	created bool
+
+	out chan WatchResponse
+}
+
+func (rw *recoveringWatch) run(ctx context.Context) {
+	defer close(rw.out)
+
+	rev, err := rw.seed(ctx)
+	compacted := errors.Is(err, v3rpc.ErrCompacted)
+	if err != nil && !compacted {
+		rw.send(ctx, WatchResponse{Canceled: true, closeErr: err})
+		return
+	}
+	for {
+		if compacted {
+			if rev, err = rw.recover(ctx); err != nil {
+				rw.send(ctx, WatchResponse{Canceled: true, closeErr: err})
+				return
+			}
+		}
+		if compacted = rw.watch(ctx, rev); !compacted {
+			return
+		}
+	}
+}
+
+// seed learns the keys existing before the start revision of the watch, and
+// returns the revision to watch from.
+func (rw *recoveringWatch) seed(ctx context.Context) (int64, error) {
+	if rw.op.rev == 1 {
+		return 1, nil
+	}
+	getOpts := []OpOption{WithRange(string(rw.op.end)), WithKeysOnly()}
+	if rw.op.rev > 1 {
+		getOpts = append(getOpts, WithRev(rw.op.rev-1))
+	}
+	resp, err := rw.kv.Get(ctx, rw.key, getOpts...)
+	if err != nil {
+		return 0, err
+	}
+	for _, kv := range resp.Kvs {
+		rw.known[string(kv.Key)] = kv.ModRevision
+	}
+	if rw.op.rev > 1 {
+		return rw.op.rev, nil
+	}
+	return resp.Header.Revision + 1, nil
+}
+
+// watch forwards the responses of a watch from rev until it fails, and
+// returns whether it failed on compaction.
+func (rw *recoveringWatch) watch(ctx context.Context, rev int64) bool {
+	wctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// the filters are applied here, as the watch must see every change to
+	// track the existing keys.
+	noFilter := func(op *Op) { op.filterPut, op.filterDelete = false, false }
+	wch := rw.w.Watch(wctx, rw.key, append(rw.opts[:len(rw.opts):len(rw.opts)], WithRev(rev), noFilter)...)
+	for wr := range wch {
+		if wr.CompactRevision != 0 {
+			return true
+		}
+		if wr.Err() != nil {
+			rw.send(ctx, wr)
+			return false
+		}
+		if wr.Created {
+			if rw.created {
+				continue
+			}
+			rw.created = true
+		}
+		for _, ev := range wr.Events {
+			rw.observe(ev)
+		}
+		n := len(wr.Events)
+		wr.Events = rw.filter(wr.Events)
+		if n > 0 && len(wr.Events) == 0 {
+			continue
+		}
+		if !rw.send(ctx, wr) {
+			return false
+		}
+	}
+	return false
+}
+
+// recover reads the watched range at the current revision, sends the
+// changes to the known keys, and returns the revision to resume from.
+func (rw *recoveringWatch) recover(ctx context.Context) (int64, error) {
+	resp, err := rw.kv.Get(ctx, rw.key, WithRange(string(rw.op.end)))
+	if err != nil {
+		return 0, err
+	}
+	var events []*Event
+	exists := make(map[string]struct{}, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		exists[string(kv.Key)] = struct{}{}
+		if modRev, ok := rw.known[string(kv.Key)]; !ok || modRev != kv.ModRevision {
+			events = append(events, &Event{Type: mvccpb.PUT, Kv: kv})
+		}
+	}
+	var deleted []string
+	for key := range rw.known {
+		if _, ok := exists[key]; !ok {
+			deleted = append(deleted, key)
+		}
+	}
+	sort.Strings(deleted)
+	for _, key := range deleted {
+		events = append(events, &Event{Type: mvccpb.DELETE, Kv: &mvccpb.KeyValue{Key: []byte(key), ModRevision: resp.Header.Revision}})
+	}
+	for _, ev := range events {
+		rw.observe(ev)
+	}
+
+	if events = rw.filter(events); len(events) > 0 {
+		if !rw.send(ctx, WatchResponse{Header: *resp.Header, Events: events}) {
+			return 0, ctx.Err()
+		}
+	}
+	return resp.Header.Revision + 1, nil
+}
+
+func (rw *recoveringWatch) observe(ev *Event) {
+	switch ev.Type {
+	case mvccpb.PUT:
+		rw.known[string(ev.Kv.Key)] = ev.Kv.ModRevision
+	case mvccpb.DELETE:
+		delete(rw.known, string(ev.Kv.Key))
+	}
+}
+
+// filter drops the events filtered out by the watch options.
+func (rw *recoveringWatch) filter(events []*Event) []*Event {
+	if !rw.op.filterPut && !rw.op.filterDelete {
+		return events
+	}
+	var filtered []*Event
+	for _, ev := range events {
+		if (ev.Type == mvccpb.PUT && rw.op.filterPut) || (ev.Type == mvccpb.DELETE && rw.op.filterDelete) {
+			continue
+		}
+		filtered = append(filtered, ev)
+	}
+	return filtered
+}
+
+func (rw *recoveringWatch) send(ctx context.Context, wr WatchResponse) bool {
+	select {
+	case rw.out <- wr:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+)
+
+// scriptedKV answers the Gets with the given responses, in order.
+type scriptedKV struct {
+	KV
+	gets []*GetResponse
+	ops  []Op
+}
+
+func (kv *scriptedKV) Get(ctx context.Context, key string, opts ...OpOption) (*GetResponse, error) {
+	kv.ops = append(kv.ops, OpGet(key, opts...))
+	resp := kv.gets[0]
+	kv.gets = kv.gets[1:]
+	return resp, nil
+}
+
+// scriptedWatcher serves the Watches with the responses sent to the given
+// channels, in order.
+type scriptedWatcher struct {
+	Watcher
+	chans []chan WatchResponse
+	revs  chan int64
+}
+
+func (w *scriptedWatcher) Watch(ctx context.Context, key string, opts ...OpOption) WatchChan {
+	op := OpGet(key, opts...)
+	w.revs <- op.rev
+	ch := w.chans[0]
+	w.chans = w.chans[1:]
+
+	out := make(chan WatchResponse)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case wr, ok := <-ch:
+				if !ok {
+					return
+				}
+				select {
+				case out <- wr:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func keyValue(key string, modRev int64) *mvccpb.KeyValue {
+	return &mvccpb.KeyValue{Key: []byte(key), ModRevision: modRev}
+}
+
+func TestWatchWithRecovery(t *testing.T) {
+	kv := &scriptedKV{gets: []*GetResponse{
+		// the keys before the start revision.
+		{Header: &pb.ResponseHeader{Revision: 10}, Kvs: []*mvccpb.KeyValue{keyValue("a", 2), keyValue("b", 3)}},
+		// the keys after the compaction.
+		{Header: &pb.ResponseHeader{Revision: 20}, Kvs: []*mvccpb.KeyValue{keyValue("b", 3), keyValue("c", 15), keyValue("d", 18)}},
+	}}
+	ch1, ch2 := make(chan WatchResponse, 2), make(chan WatchResponse, 1)
+	w := &scriptedWatcher{chans: []chan WatchResponse{ch1, ch2}, revs: make(chan int64, 2)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wch := WatchWithRecovery(ctx, kv, w, "", WithPrefix(), WithRev(5))
+	require.Equal(t, int64(5), <-w.revs)
+	assert.Equal(t, int64(4), kv.ops[0].Rev())
+
+	ch1 <- WatchResponse{Header: pb.ResponseHeader{Revision: 6}, Events: []*Event{{Type: mvccpb.PUT, Kv: keyValue("c", 6)}}}
+	wr := <-wch
+	require.Len(t, wr.Events, 1)
+	assert.Equal(t, "c", string(wr.Events[0].Kv.Key))
+
+	// "a" was deleted, "c" modified and "d" created while compacted.
+	ch1 <- WatchResponse{CompactRevision: 12, Canceled: true}
+	close(ch1)
+	wr = <-wch
+	require.NoError(t, wr.Err())
+	assert.Equal(t, int64(20), wr.Header.Revision)
+	var got []string
+	for _, ev := range wr.Events {
+		got = append(got, ev.Type.String()+" "+string(ev.Kv.Key))
+	}
+	assert.Equal(t, []string{"PUT c", "PUT d", "DELETE a"}, got)
+
+	// the watch continues after the revision of the read.
+	require.Equal(t, int64(21), <-w.revs)
+	ch2 <- WatchResponse{Header: pb.ResponseHeader{Revision: 21}, Events: []*Event{{Type: mvccpb.DELETE, Kv: keyValue("b", 21)}}}
+	wr = <-wch
+	require.Len(t, wr.Events, 1)
+	assert.Equal(t, mvccpb.DELETE, wr.Events[0].Type)
+}
+
+func TestWatchWithRecoveryFilters(t *testing.T) {
+	kv := &scriptedKV{gets: []*GetResponse{
+		{Header: &pb.ResponseHeader{Revision: 10}, Kvs: []*mvccpb.KeyValue{keyValue("a", 2)}},
+		{Header: &pb.ResponseHeader{Revision: 20}, Kvs: []*mvccpb.KeyValue{keyValue("b", 15)}},
+	}}
+	ch1 := make(chan WatchResponse, 2)
+	w := &scriptedWatcher{chans: []chan WatchResponse{ch1, make(chan WatchResponse)}, revs: make(chan int64, 2)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	wch := WatchWithRecovery(ctx, kv, w, "", WithPrefix(), WithFilterPut())
+	require.Equal(t, int64(11), <-w.revs)
+
+	// puts are tracked, but not delivered.
+	ch1 <- WatchResponse{Header: pb.ResponseHeader{Revision: 11}, Events: []*Event{{Type: mvccpb.PUT, Kv: keyValue("c", 11)}}}
+	ch1 <- WatchResponse{CompactRevision: 12, Canceled: true}
+	close(ch1)
+	wr := <-wch
+	var got []string
+	for _, ev := range wr.Events {
+		got = append(got, ev.Type.String()+" "+string(ev.Kv.Key))
+	}
+	assert.Equal(t, []string{"DELETE a", "DELETE c"}, got)
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/pkg/v3/cobrautl"
 )
 
@@ -34,6 +35,7 @@ func NewAuthCommand() *cobra.Command {
 	ac.AddCommand(newAuthEnableCommand())
 	ac.AddCommand(newAuthDisableCommand())
 	ac.AddCommand(newAuthStatusCommand())
+	ac.AddCommand(newAuthTokenRevokeCommand())
 
 	return ac
 }
@@ -78,9 +80,10 @@ func authEnableCommandFunc(cmd *cobra.Command, args []string) {
 
 	ctx, cancel := commandCtx(cmd)
 	cli := mustClientFromCmd(cmd)
+	var resp *clientv3.AuthEnableResponse
 	var err error
 	for err == nil {
-		if _, err = cli.AuthEnable(ctx); err == nil {
+		if resp, err = cli.AuthEnable(ctx); err == nil {
 			break
 		}
 		if errors.Is(err, rpctypes.ErrRootRoleNotExist) {
@@ -97,7 +100,7 @@ func authEnableCommandFunc(cmd *cobra.Command, args []string) {
 		cobrautl.ExitWithError(cobrautl.ExitError, err)
 	}
 
-	fmt.Println("Authentication Enabled")
+	display.AuthEnable(*resp)
 }
 
 func newAuthDisableCommand() *cobra.Command {
@@ -115,11 +118,35 @@ func authDisableCommandFunc(cmd *cobra.Command, args []string) {
 	}
 
 	ctx, cancel := commandCtx(cmd)
-	_, err := mustClientFromCmd(cmd).Auth.AuthDisable(ctx)
+	resp, err := mustClientFromCmd(cmd).Auth.AuthDisable(ctx)
 	cancel()
 	if err != nil {
 		cobrautl.ExitWithError(cobrautl.ExitError, err)
 	}
 
-	fmt.Println("Authentication Disabled")
+	display.AuthDisable(*resp)
+}
+
+func newAuthTokenRevokeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "token-revoke <token>",
+		Short: "Revokes an authentication token before its expiry",
+		Run:   authTokenRevokeCommandFunc,
+	}
+}
+
+// authTokenRevokeCommandFunc executes the "auth token-revoke" command.
+func authTokenRevokeCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("auth token-revoke command requires the token as its argument"))
+	}
+
+	ctx, cancel := commandCtx(cmd)
+	resp, err := mustClientFromCmd(cmd).Auth.TokenRevoke(ctx, args[0])
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+
+	display.TokenRevoke(*resp)
 }
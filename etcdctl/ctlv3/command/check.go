@@ -38,16 +38,26 @@ import (
 var (
 	checkPerfLoad        string
 	checkPerfPrefix      string
+	checkPerfQPS         int
+	checkPerfClients     int
+	checkPerfDuration    time.Duration
+	checkPerfValSize     int
+	checkPerfKeyCount    int
 	checkDatascaleLoad   string
 	checkDatascalePrefix string
 	autoCompact          bool
 	autoDefrag           bool
+	checkHashesRevision  int64
 )
 
 type checkPerfCfg struct {
 	limit    int
 	clients  int
-	duration int
+	duration time.Duration
+	valSize  int
+	// keyCount is the number of keys the puts are spread over; 0 writes a
+	// new key with every put.
+	keyCount int
 }
 
 var checkPerfCfgMap = map[string]checkPerfCfg{
@@ -55,25 +65,59 @@ var checkPerfCfgMap = map[string]checkPerfCfg{
 	"s": {
 		limit:    150,
 		clients:  50,
-		duration: 60,
+		duration: 60 * time.Second,
+		valSize:  1024,
 	},
 	"m": {
 		limit:    1000,
 		clients:  200,
-		duration: 60,
+		duration: 60 * time.Second,
+		valSize:  1024,
 	},
 	"l": {
 		limit:    8000,
 		clients:  500,
-		duration: 60,
+		duration: 60 * time.Second,
+		valSize:  1024,
 	},
 	"xl": {
 		limit:    15000,
 		clients:  1000,
-		duration: 60,
+		duration: 60 * time.Second,
+		valSize:  1024,
 	},
 }
 
+// checkPerfWorkload is the workload "check perf" ran.
+type checkPerfWorkload struct {
+	QPS       int    `json:"qps"`
+	Clients   int    `json:"clients"`
+	Duration  string `json:"duration"`
+	ValueSize int    `json:"value_size"`
+	KeyCount  int    `json:"key_count"`
+}
+
+// checkPerfCheck is a condition the performance of the cluster is checked
+// against.
+type checkPerfCheck struct {
+	Name      string  `json:"name"`
+	Pass      bool    `json:"pass"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold"`
+}
+
+// checkPerfResult is the verdict of "check perf".
+type checkPerfResult struct {
+	Workload       checkPerfWorkload `json:"workload"`
+	Throughput     float64           `json:"throughput"`
+	AverageSeconds float64           `json:"average_seconds"`
+	SlowestSeconds float64           `json:"slowest_seconds"`
+	StddevSeconds  float64           `json:"stddev_seconds"`
+	Errors         map[string]int    `json:"errors,omitempty"`
+	Checks         []checkPerfCheck  `json:"checks"`
+	Pass           bool              `json:"pass"`
+}
+
 type checkDatascaleCfg struct {
 	limit   int
 	kvSize  int
@@ -113,6 +157,7 @@ func NewCheckCommand() *cobra.Command {
 
 	cc.AddCommand(NewCheckPerfCommand())
 	cc.AddCommand(NewCheckDatascaleCommand())
+	cc.AddCommand(NewCheckHashesCommand())
 
 	return cc
 }
@@ -125,8 +170,12 @@ func NewCheckPerfCommand() *cobra.Command {
 		Run:   newCheckPerfCommand,
 	}
 
-	// TODO: support customized configuration
 	cmd.Flags().StringVar(&checkPerfLoad, "load", "s", "The performance check's workload model. Accepted workloads: s(small), m(medium), l(large), xl(xLarge). Different workload models use different configurations in terms of number of clients and expected throughput.")
+	cmd.Flags().IntVar(&checkPerfQPS, "qps", 0, "The expected throughput of puts per second, overriding the one of the workload model.")
+	cmd.Flags().IntVar(&checkPerfClients, "clients", 0, "The number of concurrent clients, overriding the one of the workload model.")
+	cmd.Flags().DurationVar(&checkPerfDuration, "duration", 0, "The duration of the performance check, overriding the one of the workload model (at least 1s).")
+	cmd.Flags().IntVar(&checkPerfValSize, "val-size", 0, "The size of the values of the puts, overriding the one of the workload model.")
+	cmd.Flags().IntVar(&checkPerfKeyCount, "key-count", 0, "The number of keys the puts are spread over. By default every put writes a new key.")
 	cmd.Flags().StringVar(&checkPerfPrefix, "prefix", "/etcdctl-check-perf/", "The prefix for writing the performance check's keys.")
 	cmd.Flags().BoolVar(&autoCompact, "auto-compact", false, "Compact storage with last revision after test is finished.")
 	cmd.Flags().BoolVar(&autoDefrag, "auto-defrag", false, "Defragment storage after test is finished.")
@@ -151,6 +200,33 @@ func newCheckPerfCommand(cmd *cobra.Command, args []string) {
 		cobrautl.ExitWithError(cobrautl.ExitBadFeature, fmt.Errorf("unknown load option %v", checkPerfLoad))
 	}
 	cfg := checkPerfCfgMap[model]
+	if cmd.Flags().Changed("qps") {
+		cfg.limit = checkPerfQPS
+	}
+	if cmd.Flags().Changed("clients") {
+		cfg.clients = checkPerfClients
+	}
+	if cmd.Flags().Changed("duration") {
+		cfg.duration = checkPerfDuration
+	}
+	if cmd.Flags().Changed("val-size") {
+		cfg.valSize = checkPerfValSize
+	}
+	if cmd.Flags().Changed("key-count") {
+		cfg.keyCount = checkPerfKeyCount
+	}
+	switch {
+	case cfg.limit <= 0:
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("--qps must be positive (set to %d)", cfg.limit))
+	case cfg.clients <= 0:
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("--clients must be positive (set to %d)", cfg.clients))
+	case cfg.duration < time.Second:
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("--duration must be at least 1s (set to %v)", cfg.duration))
+	case cfg.valSize < 0:
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("--val-size must not be negative (set to %d)", cfg.valSize))
+	case cfg.keyCount < 0:
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("--key-count must not be negative (set to %d)", cfg.keyCount))
+	}
 
 	requests := make(chan v3.Op, cfg.clients)
 	limit := rate.NewLimiter(rate.Limit(cfg.limit), 1)
@@ -161,7 +237,7 @@ func newCheckPerfCommand(cmd *cobra.Command, args []string) {
 		clients[i] = mustClient(cc)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.duration)*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.duration)
 	defer cancel()
 	ctx, icancel := interruptableContext(ctx, func() { attemptCleanup(clients[0], false) })
 	defer icancel()
@@ -176,10 +252,11 @@ func newCheckPerfCommand(cmd *cobra.Command, args []string) {
 		cobrautl.ExitWithError(cobrautl.ExitInvalidInput, fmt.Errorf("prefix %q has keys. Delete with 'etcdctl del --prefix %s' first", checkPerfPrefix, checkPerfPrefix))
 	}
 
-	ksize, vsize := 256, 1024
-	k, v := make([]byte, ksize), string(make([]byte, vsize))
+	ksize := 256
+	k, v := make([]byte, ksize), string(make([]byte, cfg.valSize))
 
-	bar := pb.New(cfg.duration)
+	seconds := int(cfg.duration / time.Second)
+	bar := pb.New(seconds)
 	bar.Start()
 
 	r := report.NewReport("%4.4f")
@@ -201,14 +278,18 @@ func newCheckPerfCommand(cmd *cobra.Command, args []string) {
 		cctx, ccancel := context.WithCancel(ctx)
 		defer ccancel()
 		for limit.Wait(cctx) == nil {
-			binary.PutVarint(k, rand.Int63n(math.MaxInt64))
+			if cfg.keyCount > 0 {
+				binary.PutVarint(k, rand.Int63n(int64(cfg.keyCount)))
+			} else {
+				binary.PutVarint(k, rand.Int63n(math.MaxInt64))
+			}
 			requests <- v3.OpPut(checkPerfPrefix+string(k), v)
 		}
 		close(requests)
 	}()
 
 	go func() {
-		for i := 0; i < cfg.duration; i++ {
+		for i := 0; i < seconds; i++ {
 			time.Sleep(time.Second)
 			bar.Add(1)
 		}
@@ -229,39 +310,48 @@ func newCheckPerfCommand(cmd *cobra.Command, args []string) {
 		}
 	}
 
-	ok = true
-	if len(s.ErrorDist) != 0 {
-		fmt.Println("FAIL: too many errors")
-		for k, v := range s.ErrorDist {
-			fmt.Printf("FAIL: ERROR(%v) -> %d\n", k, v)
-		}
-		ok = false
+	res := newCheckPerfResult(cfg, s)
+	display.CheckPerf(res)
+	if !res.Pass {
+		os.Exit(cobrautl.ExitError)
 	}
+}
 
-	if s.RPS/float64(cfg.limit) <= 0.9 {
-		fmt.Printf("FAIL: Throughput too low: %d writes/s\n", int(s.RPS)+1)
-		ok = false
-	} else {
-		fmt.Printf("PASS: Throughput is %d writes/s\n", int(s.RPS)+1)
-	}
-	if s.Slowest > 0.5 { // slowest request > 500ms
-		fmt.Printf("Slowest request took too long: %fs\n", s.Slowest)
-		ok = false
-	} else {
-		fmt.Printf("PASS: Slowest request took %fs\n", s.Slowest)
+// newCheckPerfResult checks the stats of the puts of the workload against
+// the conditions of the performance check.
+func newCheckPerfResult(cfg checkPerfCfg, s report.Stats) checkPerfResult {
+	var errs int
+	for _, n := range s.ErrorDist {
+		errs += n
 	}
-	if s.Stddev > 0.1 { // stddev > 100ms
-		fmt.Printf("Stddev too high: %fs\n", s.Stddev)
-		ok = false
-	} else {
-		fmt.Printf("PASS: Stddev is %fs\n", s.Stddev)
+	res := checkPerfResult{
+		Workload: checkPerfWorkload{
+			QPS:       cfg.limit,
+			Clients:   cfg.clients,
+			Duration:  cfg.duration.String(),
+			ValueSize: cfg.valSize,
+			KeyCount:  cfg.keyCount,
+		},
+		Throughput:     s.RPS,
+		AverageSeconds: s.Average,
+		SlowestSeconds: s.Slowest,
+		StddevSeconds:  s.Stddev,
+		Errors:         s.ErrorDist,
+		Checks: []checkPerfCheck{
+			{Name: "errors", Pass: errs == 0, Value: float64(errs), Threshold: 0},
+			// the throughput must be above 90% of the expected one
+			{Name: "throughput", Pass: s.RPS/float64(cfg.limit) > 0.9, Value: s.RPS, Threshold: 0.9 * float64(cfg.limit)},
+			// the slowest request must take at most 500ms
+			{Name: "slowest", Pass: s.Slowest <= 0.5, Value: s.Slowest, Threshold: 0.5},
+			// the stddev of the requests must be at most 100ms
+			{Name: "stddev", Pass: s.Stddev <= 0.1, Value: s.Stddev, Threshold: 0.1},
+		},
+		Pass: true,
 	}
-
-	if !ok {
-		fmt.Println("FAIL")
-		os.Exit(cobrautl.ExitError)
+	for _, c := range res.Checks {
+		res.Pass = res.Pass && c.Pass
 	}
-	fmt.Println("PASS")
+	return res
 }
 
 func attemptCleanup(client *v3.Client, autoCompact bool) {
@@ -436,3 +526,49 @@ func newCheckDatascaleCommand(cmd *cobra.Command, args []string) {
 	}
 	fmt.Printf("PASS: Approximate system memory used : %v MB.\n", strconv.FormatFloat(mbUsed, 'f', 2, 64))
 }
+
+// NewCheckHashesCommand returns the cobra command for "check hashes".
+func NewCheckHashesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hashes [options]",
+		Short: "Check that the key-value stores of all members of the etcd cluster have the same hash",
+		Long: `Has the member behind the first reachable endpoint compute the hash of the key-value store of
+every member of the cluster up to the same revision, and fails if the hashes do not match.
+By default the latest compacted revision is hashed, since its hash does not change with later
+writes, or the latest revision if the key-value store was never compacted.
+`,
+		Run: newCheckHashesCommand,
+	}
+
+	cmd.Flags().Int64Var(&checkHashesRevision, "revision", 0, "The revision to hash the key-value stores up to. Defaults to the latest compacted revision.")
+
+	return cmd
+}
+
+// newCheckHashesCommand executes the "check hashes" command.
+func newCheckHashesCommand(cmd *cobra.Command, args []string) {
+	c := mustClientFromCmd(cmd)
+	defer c.Close()
+
+	var (
+		resp *v3.HashKVCheckResponse
+		err  error
+	)
+	for _, ep := range c.Endpoints() {
+		ctx, cancel := commandCtx(cmd)
+		resp, err = c.HashKVCheck(ctx, ep, checkHashesRevision)
+		cancel()
+		if err == nil {
+			break
+		}
+		fmt.Fprintf(os.Stderr, "Failed to check the hashes through endpoint %s (%v)\n", ep, err)
+	}
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+
+	display.HashKVCheck(*resp)
+	if !resp.Consistent {
+		cobrautl.ExitWithError(cobrautl.ExitError, fmt.Errorf("hashes of the members do not match at revision %d", resp.Revision))
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.etcd.io/etcd/pkg/v3/report"
+)
+
+func TestNewCheckPerfResult(t *testing.T) {
+	cfg := checkPerfCfg{limit: 100, clients: 10, duration: 10 * time.Second, valSize: 64, keyCount: 1000}
+	tests := []struct {
+		name  string
+		stats report.Stats
+
+		wantFailed []string
+	}{
+		{
+			name:  "pass",
+			stats: report.Stats{RPS: 95, Slowest: 0.2, Stddev: 0.01},
+		},
+		{
+			name:       "low throughput",
+			stats:      report.Stats{RPS: 90, Slowest: 0.2, Stddev: 0.01},
+			wantFailed: []string{"throughput"},
+		},
+		{
+			name:       "slow and errors",
+			stats:      report.Stats{RPS: 100, Slowest: 0.6, Stddev: 0.2, ErrorDist: map[string]int{"timeout": 2}},
+			wantFailed: []string{"errors", "slowest", "stddev"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := newCheckPerfResult(cfg, tt.stats)
+			assert.Equal(t, checkPerfWorkload{QPS: 100, Clients: 10, Duration: "10s", ValueSize: 64, KeyCount: 1000}, res.Workload)
+			var failed []string
+			for _, c := range res.Checks {
+				if !c.Pass {
+					failed = append(failed, c.Name)
+				}
+			}
+			assert.Equal(t, tt.wantFailed, failed)
+			assert.Equal(t, len(tt.wantFailed) == 0, res.Pass)
+		})
+	}
+}
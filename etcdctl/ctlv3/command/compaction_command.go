@@ -15,16 +15,24 @@
 package command
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strconv"
 
+	"github.com/coreos/go-semver/semver"
+	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 
+	"go.etcd.io/etcd/api/v3/version"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/pkg/v3/cobrautl"
 )
 
-var compactPhysical bool
+var (
+	compactPhysical bool
+	compactDryRun   bool
+)
 
 // NewCompactionCommand returns the cobra command for "compaction".
 func NewCompactionCommand() *cobra.Command {
@@ -34,6 +42,7 @@ func NewCompactionCommand() *cobra.Command {
 		Run:   compactionCommandFunc,
 	}
 	cmd.Flags().BoolVar(&compactPhysical, "physical", false, "'true' to wait for compaction to physically remove all old revisions")
+	cmd.Flags().BoolVar(&compactDryRun, "dry-run", false, "Report the revisions and the approximate space the compaction would reclaim, without compacting")
 	return cmd
 }
 
@@ -47,18 +56,54 @@ func compactionCommandFunc(cmd *cobra.Command, args []string) {
 	if err != nil {
 		cobrautl.ExitWithError(cobrautl.ExitError, err)
 	}
+	if compactDryRun && compactPhysical {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, errors.New("--dry-run and --physical cannot be combined"))
+	}
 
 	var opts []clientv3.CompactOption
 	if compactPhysical {
 		opts = append(opts, clientv3.WithCompactPhysical())
 	}
+	if compactDryRun {
+		opts = append(opts, clientv3.WithCompactDryRun())
+	}
 
 	c := mustClientFromCmd(cmd)
 	ctx, cancel := commandCtx(cmd)
-	_, cerr := c.Compact(ctx, rev, opts...)
+	if compactDryRun {
+		if err = checkCompactDryRun(ctx, c); err != nil {
+			cancel()
+			cobrautl.ExitWithError(cobrautl.ExitError, err)
+		}
+	}
+	resp, cerr := c.Compact(ctx, rev, opts...)
 	cancel()
 	if cerr != nil {
 		cobrautl.ExitWithError(cobrautl.ExitError, cerr)
 	}
+	if compactDryRun {
+		fmt.Printf("compacting revision %d would delete %d revisions, reclaiming approximately %s (%d bytes)\n",
+			rev, resp.ReclaimableRevisions, humanize.Bytes(uint64(resp.ReclaimableBytes)), resp.ReclaimableBytes)
+		return
+	}
 	fmt.Println("compacted revision", rev)
 }
+
+// checkCompactDryRun returns an error unless every endpoint runs 3.7 or later:
+// the older members ignore the dry run and compact.
+func checkCompactDryRun(ctx context.Context, c *clientv3.Client) error {
+	for _, ep := range c.Endpoints() {
+		resp, err := c.Status(ctx, ep)
+		if err != nil {
+			return fmt.Errorf("failed to get the version of endpoint %s (%w)", ep, err)
+		}
+		v, err := semver.NewVersion(resp.Version)
+		if err != nil {
+			return fmt.Errorf("failed to parse the version %q of endpoint %s (%w)", resp.Version, ep, err)
+		}
+		if version.LessThan(semver.Version{Major: v.Major, Minor: v.Minor}, version.V3_7) {
+			return fmt.Errorf("endpoint %s runs version %s, which compacts instead of the dry run; --dry-run needs version %s or later", ep, resp.Version, version.V3_7)
+		}
+	}
+	return nil
+}
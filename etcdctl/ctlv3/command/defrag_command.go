@@ -15,15 +15,22 @@
 package command
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/pkg/v3/cobrautl"
 )
 
+var (
+	defragAsync       bool
+	defragStatusWatch bool
+)
+
 // NewDefragCommand returns the cobra command for "Defrag".
 func NewDefragCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -32,9 +39,37 @@ func NewDefragCommand() *cobra.Command {
 		Run:   defragCommandFunc,
 	}
 	cmd.PersistentFlags().BoolVar(&epClusterEndpoints, "cluster", false, "use all endpoints from the cluster member list")
+	cmd.Flags().BoolVar(&defragAsync, "async", false, "start the defragmentation without waiting for it to finish, see 'defrag status'")
+	cmd.AddCommand(newDefragStatusCommand())
 	return cmd
 }
 
+func newDefragStatusCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Prints the progress of the defragmentation of the etcd members with given endpoints",
+		Run:   defragStatusCommandFunc,
+	}
+	cmd.Flags().BoolVar(&defragStatusWatch, "watch", false, "print the progress until the running defragmentations have finished")
+	return cmd
+}
+
+// epDefrag is the result of the defragmentation of an endpoint.
+type epDefrag struct {
+	Ep string `json:"endpoint"`
+	// Async is set if the defragmentation was only started.
+	Async bool   `json:"async"`
+	Took  string `json:"took"`
+	Error string `json:"error,omitempty"`
+}
+
+// epDefragStatus is the progress of the defragmentation of an endpoint.
+type epDefragStatus struct {
+	Ep    string                             `json:"endpoint"`
+	Resp  *clientv3.DefragmentStatusResponse `json:"status,omitempty"`
+	Error string                             `json:"error,omitempty"`
+}
+
 func defragCommandFunc(cmd *cobra.Command, args []string) {
 	failures := 0
 	cfg := clientConfigFromCmd(cmd)
@@ -43,14 +78,55 @@ func defragCommandFunc(cmd *cobra.Command, args []string) {
 		c := mustClient(cfg)
 		ctx, cancel := commandCtx(cmd)
 		start := time.Now()
-		_, err := c.Defragment(ctx, ep)
-		d := time.Since(start)
+		var err error
+		if defragAsync {
+			_, err = c.DefragmentAsync(ctx, ep)
+		} else {
+			_, err = c.Defragment(ctx, ep)
+		}
 		cancel()
+		d := epDefrag{Ep: ep, Async: defragAsync, Took: time.Since(start).String()}
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to defragment etcd member[%s]. took %s. (%v)\n", ep, d.String(), err)
+			d.Error = err.Error()
 			failures++
+		}
+		display.Defrag(d)
+		c.Close()
+	}
+
+	if failures != 0 {
+		os.Exit(cobrautl.ExitError)
+	}
+}
+
+func defragStatusCommandFunc(cmd *cobra.Command, args []string) {
+	failures := 0
+	cfg := clientConfigFromCmd(cmd)
+	for _, ep := range endpointsFromCluster(cmd) {
+		cfg.Endpoints = []string{ep}
+		c := mustClient(cfg)
+		var err error
+		failed := false
+		show := func(resp *clientv3.DefragmentStatusResponse) {
+			display.DefragStatus(epDefragStatus{Ep: ep, Resp: resp})
+			failed = resp.Error != ""
+		}
+		if defragStatusWatch {
+			// the defragmentation may outlast the command timeout
+			err = c.WatchDefragmentStatus(context.Background(), ep, show)
 		} else {
-			fmt.Printf("Finished defragmenting etcd member[%s]. took %s\n", ep, d.String())
+			ctx, cancel := commandCtx(cmd)
+			var resp *clientv3.DefragmentStatusResponse
+			if resp, err = c.DefragmentStatus(ctx, ep); err == nil {
+				show(resp)
+			}
+			cancel()
+		}
+		if err != nil {
+			display.DefragStatus(epDefragStatus{Ep: ep, Error: err.Error()})
+		}
+		if err != nil || failed {
+			failures++
 		}
 		c.Close()
 	}
@@ -59,3 +135,17 @@ func defragCommandFunc(cmd *cobra.Command, args []string) {
 		os.Exit(cobrautl.ExitError)
 	}
 }
+
+func defragStatusString(resp *clientv3.DefragmentStatusResponse) string {
+	start := time.Unix(resp.StartTime, 0)
+	switch {
+	case resp.InProgress:
+		return fmt.Sprintf("defragmenting, %d%% complete, started %s ago", resp.PercentComplete, time.Since(start).Round(time.Second))
+	case resp.StartTime == 0:
+		return "not defragmented since it started"
+	case resp.Error != "":
+		return fmt.Sprintf("failed to defragment at %s (%s)", time.Unix(resp.FinishTime, 0).Format(time.RFC3339), resp.Error)
+	}
+	finish := time.Unix(resp.FinishTime, 0)
+	return fmt.Sprintf("finished defragmenting at %s, took %s", finish.Format(time.RFC3339), finish.Sub(start))
+}
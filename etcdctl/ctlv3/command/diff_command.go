@@ -0,0 +1,235 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/pkg/v3/cobrautl"
+)
+
+const (
+	diffAdded   = '+'
+	diffRemoved = '-'
+	diffChanged = '~'
+)
+
+var (
+	diffRevs       []int64
+	diffEndpoint2  string
+	diffBatchSize  int64
+	diffShowValues bool
+)
+
+// NewDiffCommand returns the cobra command for "diff".
+func NewDiffCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "diff [options] [<prefix>]",
+		Short: "Reports the keys added, removed or changed between two revisions or two clusters",
+		Long: `Reports the keys of the prefix added, removed or changed between two revisions or two clusters.
+
+Without --endpoint2, the keys at the first --rev are compared to the keys at the
+second --rev, or at the current revision if it's omitted. With --endpoint2, the
+keys of the cluster at --endpoints are compared to the keys of the cluster at
+--endpoint2, at their respective --rev or at their current revision if omitted.
+
+A key is changed if its value differs. Revisions must not be compacted.
+`,
+		Run: diffCommandFunc,
+	}
+
+	c.Flags().Int64SliceVar(&diffRevs, "rev", nil, "Revision to compare, given once or twice")
+	c.Flags().StringVar(&diffEndpoint2, "endpoint2", "", "Endpoint of a second cluster to compare to the cluster at --endpoints")
+	c.Flags().Int64Var(&diffBatchSize, "batch-size", defaultExportBatchSize, "Number of keys fetched per range request")
+	c.Flags().BoolVar(&diffShowValues, "show-values", false, "Print the values of the keys reported")
+
+	return c
+}
+
+func diffCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) > 1 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, errors.New("diff command accepts at most one prefix argument"))
+	}
+	prefix := ""
+	if len(args) == 1 {
+		prefix = args[0]
+	}
+	if len(diffRevs) > 2 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, errors.New("--rev must be given at most twice"))
+	}
+	if diffEndpoint2 == "" && len(diffRevs) == 0 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, errors.New("diff command requires --rev or --endpoint2"))
+	}
+	if diffBatchSize <= 0 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, errors.New("--batch-size must be positive"))
+	}
+	revs := make([]int64, 2)
+	copy(revs, diffRevs)
+
+	c1 := mustClientFromCmd(cmd)
+	c2 := c1
+	if diffEndpoint2 != "" {
+		cfg := clientConfigFromCmd(cmd)
+		cfg.Endpoints = []string{diffEndpoint2}
+		c2 = mustClient(cfg)
+	}
+
+	a := newKVPager(c1, prefix, revs[0], diffBatchSize)
+	b := newKVPager(c2, prefix, revs[1], diffBatchSize)
+	var added, removed, changed int
+	err := diffKeys(context.TODO(), a, b, func(op byte, from, to *mvccpb.KeyValue) {
+		switch op {
+		case diffAdded:
+			added++
+		case diffRemoved:
+			removed++
+		case diffChanged:
+			changed++
+		}
+		printDiff(os.Stdout, op, from, to)
+	})
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	fmt.Fprintf(os.Stderr, "%d added, %d removed, %d changed between revision %d and revision %d\n", added, removed, changed, a.rev, b.rev)
+}
+
+func printDiff(w io.Writer, op byte, from, to *mvccpb.KeyValue) {
+	kv := to
+	if op == diffRemoved {
+		kv = from
+	}
+	fmt.Fprintf(w, "%c %s\n", op, kv.Key)
+	if !diffShowValues {
+		return
+	}
+	switch op {
+	case diffAdded:
+		fmt.Fprintf(w, "  + %q\n", to.Value)
+	case diffRemoved:
+		fmt.Fprintf(w, "  - %q\n", from.Value)
+	case diffChanged:
+		fmt.Fprintf(w, "  - %q\n  + %q\n", from.Value, to.Value)
+	}
+}
+
+// kvIterator iterates over key-values in key order.
+type kvIterator interface {
+	// next returns the next key-value, or nil after the last one.
+	next(ctx context.Context) (*mvccpb.KeyValue, error)
+}
+
+// diffKeys merges the key-values of a and b, and reports the keys only in b
+// as added, the keys only in a as removed and the keys whose value differs as
+// changed.
+func diffKeys(ctx context.Context, a, b kvIterator, report func(op byte, from, to *mvccpb.KeyValue)) error {
+	from, err := a.next(ctx)
+	if err != nil {
+		return err
+	}
+	to, err := b.next(ctx)
+	if err != nil {
+		return err
+	}
+	for from != nil || to != nil {
+		cmp := 0
+		switch {
+		case from == nil:
+			cmp = 1
+		case to == nil:
+			cmp = -1
+		default:
+			cmp = bytes.Compare(from.Key, to.Key)
+		}
+
+		switch {
+		case cmp < 0:
+			report(diffRemoved, from, nil)
+		case cmp > 0:
+			report(diffAdded, nil, to)
+		case !bytes.Equal(from.Value, to.Value):
+			report(diffChanged, from, to)
+		}
+
+		if cmp <= 0 {
+			if from, err = a.next(ctx); err != nil {
+				return err
+			}
+		}
+		if cmp >= 0 {
+			if to, err = b.next(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// kvPager iterates over the keys of a prefix at a revision, fetching them a
+// page at a time. Without a revision, the pages are pinned to the revision of
+// the first one.
+type kvPager struct {
+	c        *clientv3.Client
+	key, end string
+	rev      int64
+	limit    int64
+
+	kvs  []*mvccpb.KeyValue
+	more bool
+}
+
+func newKVPager(c *clientv3.Client, prefix string, rev, limit int64) *kvPager {
+	key, end := prefix, clientv3.GetPrefixRangeEnd(prefix)
+	if prefix == "" {
+		key, end = "\x00", "\x00"
+	}
+	return &kvPager{c: c, key: key, end: end, rev: rev, limit: limit, more: true}
+}
+
+func (p *kvPager) next(ctx context.Context) (*mvccpb.KeyValue, error) {
+	if len(p.kvs) == 0 && p.more {
+		opts := []clientv3.OpOption{clientv3.WithRange(p.end), clientv3.WithLimit(p.limit)}
+		if p.rev > 0 {
+			opts = append(opts, clientv3.WithRev(p.rev))
+		}
+		resp, err := p.c.Get(ctx, p.key, opts...)
+		if err != nil {
+			return nil, err
+		}
+		if p.rev == 0 {
+			p.rev = resp.Header.Revision
+		}
+		p.kvs = resp.Kvs
+		p.more = resp.More && len(resp.Kvs) > 0
+		if p.more {
+			p.key = string(append(resp.Kvs[len(resp.Kvs)-1].Key, 0))
+		}
+	}
+	if len(p.kvs) == 0 {
+		return nil, nil
+	}
+	kv := p.kvs[0]
+	p.kvs = p.kvs[1:]
+	return kv, nil
+}
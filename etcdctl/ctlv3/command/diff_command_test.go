@@ -0,0 +1,76 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+)
+
+type sliceKVIterator []*mvccpb.KeyValue
+
+func (it *sliceKVIterator) next(context.Context) (*mvccpb.KeyValue, error) {
+	if len(*it) == 0 {
+		return nil, nil
+	}
+	kv := (*it)[0]
+	*it = (*it)[1:]
+	return kv, nil
+}
+
+func kvs(pairs ...string) *sliceKVIterator {
+	it := sliceKVIterator{}
+	for i := 0; i < len(pairs); i += 2 {
+		it = append(it, &mvccpb.KeyValue{Key: []byte(pairs[i]), Value: []byte(pairs[i+1])})
+	}
+	return &it
+}
+
+func TestDiffKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *sliceKVIterator
+		want []string
+	}{
+		{name: "empty", a: kvs(), b: kvs(), want: nil},
+		{name: "equal", a: kvs("a", "1", "b", "2"), b: kvs("a", "1", "b", "2"), want: nil},
+		{name: "all added", a: kvs(), b: kvs("a", "1", "b", "2"), want: []string{"+a", "+b"}},
+		{name: "all removed", a: kvs("a", "1", "b", "2"), b: kvs(), want: []string{"-a", "-b"}},
+		{
+			name: "mixed",
+			a:    kvs("a", "1", "c", "3", "d", "4", "f", "6"),
+			b:    kvs("b", "2", "c", "3", "d", "x", "e", "5", "g", "7"),
+			want: []string{"-a", "+b", "~d", "+e", "-f", "+g"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := diffKeys(context.Background(), tt.a, tt.b, func(op byte, from, to *mvccpb.KeyValue) {
+				kv := to
+				if op == diffRemoved {
+					kv = from
+				}
+				got = append(got, string(op)+string(kv.Key))
+			})
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
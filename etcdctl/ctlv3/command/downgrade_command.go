@@ -16,6 +16,7 @@ package command
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/spf13/cobra"
 
@@ -23,6 +24,8 @@ import (
 	"go.etcd.io/etcd/pkg/v3/cobrautl"
 )
 
+var downgradeDryRun bool
+
 // NewDowngradeCommand returns the cobra command for "downgrade".
 func NewDowngradeCommand() *cobra.Command {
 	dc := &cobra.Command{
@@ -42,9 +45,16 @@ func NewDowngradeValidateCommand() *cobra.Command {
 	cc := &cobra.Command{
 		Use:   "validate <TARGET_VERSION>",
 		Short: "Validate downgrade capability before starting downgrade",
+		Long: `Validates the downgrade capability before starting the downgrade.
+
+With --dry-run, the storage schema, the enabled features and the WAL entries of
+every member are checked against the target version too, and everything that
+blocks the downgrade is reported. Nothing is changed on the cluster.
+`,
 
 		Run: downgradeValidateCommandFunc,
 	}
+	cc.Flags().BoolVar(&downgradeDryRun, "dry-run", false, "Check every member for what blocks the downgrade, without enabling it")
 	return cc
 }
 
@@ -87,6 +97,19 @@ func downgradeValidateCommandFunc(cmd *cobra.Command, args []string) {
 	ctx, cancel := commandCtx(cmd)
 	cli := mustClientFromCmd(cmd)
 
+	if downgradeDryRun {
+		resp, err := cli.DowngradeCheck(ctx, targetVersion)
+		cancel()
+		if err != nil {
+			cobrautl.ExitWithError(cobrautl.ExitError, err)
+		}
+		display.DowngradeCheck(*resp)
+		if !resp.Downgradable {
+			cobrautl.ExitWithError(cobrautl.ExitError, fmt.Errorf("downgrade to %s is blocked", targetVersion))
+		}
+		return
+	}
+
 	resp, err := cli.Downgrade(ctx, clientv3.DowngradeValidate, targetVersion)
 	cancel()
 	if err != nil {
@@ -0,0 +1,73 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"go.etcd.io/etcd/pkg/v3/cobrautl"
+)
+
+var encryptionReencrypt bool
+
+// NewEncryptionCommand returns the cobra command for "encryption".
+func NewEncryptionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "encryption <subcommand>",
+		Short: "Manages the encryption at rest of the storage of the etcd members",
+	}
+	cmd.AddCommand(newEncryptionRotateKeyCommand())
+	return cmd
+}
+
+func newEncryptionRotateKeyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rotate-key",
+		Short: "Wraps the data encryption keys of the etcd members with given endpoints with their current key encryption key",
+		Run:   encryptionRotateKeyCommandFunc,
+	}
+	cmd.Flags().BoolVar(&encryptionReencrypt, "reencrypt", false, "re-encrypt the storage with a new data encryption key, which defragments it")
+	cmd.Flags().BoolVar(&epClusterEndpoints, "cluster", false, "use all endpoints from the cluster member list")
+	return cmd
+}
+
+func encryptionRotateKeyCommandFunc(cmd *cobra.Command, args []string) {
+	failures := 0
+	cfg := clientConfigFromCmd(cmd)
+	for _, ep := range endpointsFromCluster(cmd) {
+		cfg.Endpoints = []string{ep}
+		c := mustClient(cfg)
+		ctx, cancel := commandCtx(cmd)
+		start := time.Now()
+		resp, err := c.RotateEncryptionKey(ctx, ep, encryptionReencrypt)
+		d := time.Since(start)
+		cancel()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to rotate the encryption key of etcd member[%s]. took %s. (%v)\n", ep, d.String(), err)
+			failures++
+		} else {
+			fmt.Printf("Rotated the encryption key of etcd member[%s]: data encryption key %x wrapped by key encryption key %q. took %s\n", ep, resp.DekId, resp.KekId, d.String())
+		}
+		c.Close()
+	}
+
+	if failures != 0 {
+		os.Exit(cobrautl.ExitError)
+	}
+}
@@ -48,6 +48,8 @@ func NewEndpointCommand() *cobra.Command {
 	ec.AddCommand(newEpHealthCommand())
 	ec.AddCommand(newEpStatusCommand())
 	ec.AddCommand(newEpHashKVCommand())
+	ec.AddCommand(newEpLagCommand())
+	ec.AddCommand(newEpMirrorCommand())
 
 	return ec
 }
@@ -83,6 +85,30 @@ func newEpHashKVCommand() *cobra.Command {
 	return hc
 }
 
+func newEpLagCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lag",
+		Short: "Prints how far each follower lags behind the leader",
+		Long: `Queries the endpoints specified in --endpoints for the leader and prints, for each follower,
+its raft match index and how many committed entries it lags behind the leader.
+Use --cluster to find the leader among all the members of the cluster.
+`,
+		Run: epLagCommandFunc,
+	}
+}
+
+func newEpMirrorCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "mirror",
+		Short: "Prints the progress of the mirroring of the keys to the mirror target cluster",
+		Long: `Queries the endpoints specified in --endpoints for the leader, which mirrors the keys to the cluster
+of --mirror-target-endpoints, and prints the revision up to which the keys were mirrored and how many revisions it lags.
+Use --cluster to find the leader among all the members of the cluster.
+`,
+		Run: epMirrorCommandFunc,
+	}
+}
+
 type epHealth struct {
 	Ep     string `json:"endpoint"`
 	Health bool   `json:"health"`
@@ -125,41 +151,8 @@ func epHealthCommandFunc(cmd *cobra.Command, args []string) {
 				hch <- epHealth{Ep: ep, Health: false, Error: err.Error()}
 				return
 			}
-			st := time.Now()
-			// get a random key. As long as we can get the response without an error, the
-			// endpoint is health.
-			ctx, cancel := commandCtx(cmd)
-			_, err = cli.Get(ctx, "health")
-			eh := epHealth{Ep: ep, Health: false, Took: time.Since(st).String()}
-			// permission denied is OK since proposal goes through consensus to get it
-			if err == nil || errors.Is(err, rpctypes.ErrPermissionDenied) {
-				eh.Health = true
-			} else {
-				eh.Error = err.Error()
-			}
-
-			if eh.Health {
-				resp, err := cli.AlarmList(ctx)
-				if err == nil && len(resp.Alarms) > 0 {
-					eh.Health = false
-					eh.Error = "Active Alarm(s): "
-					for _, v := range resp.Alarms {
-						switch v.Alarm {
-						case etcdserverpb.AlarmType_NOSPACE:
-							eh.Error = eh.Error + "NOSPACE "
-						case etcdserverpb.AlarmType_CORRUPT:
-							eh.Error = eh.Error + "CORRUPT "
-						default:
-							eh.Error = eh.Error + "UNKNOWN "
-						}
-					}
-				} else if err != nil {
-					eh.Health = false
-					eh.Error = "Unable to fetch the alarm list"
-				}
-			}
-			cancel()
-			hch <- eh
+			defer cli.Close()
+			hch <- checkEndpointHealth(cmd, cli, ep)
 		}(cfg)
 	}
 
@@ -180,6 +173,50 @@ func epHealthCommandFunc(cmd *cobra.Command, args []string) {
 	}
 }
 
+// checkEndpointHealth checks the health of the endpoint ep of cli: it is
+// healthy if it serves a linearizable read and has no active alarm.
+func checkEndpointHealth(cmd *cobra.Command, cli *clientv3.Client, ep string) epHealth {
+	st := time.Now()
+	// get a random key. As long as we can get the response without an error, the
+	// endpoint is health.
+	ctx, cancel := commandCtx(cmd)
+	_, err := cli.Get(ctx, "health")
+	eh := epHealth{Ep: ep, Health: false, Took: time.Since(st).String()}
+	// permission denied is OK since proposal goes through consensus to get it
+	if err == nil || errors.Is(err, rpctypes.ErrPermissionDenied) {
+		eh.Health = true
+	} else {
+		eh.Error = err.Error()
+	}
+
+	if eh.Health {
+		resp, err := cli.AlarmList(ctx)
+		if err == nil && len(resp.Alarms) > 0 {
+			eh.Health = false
+			eh.Error = "Active Alarm(s): "
+			for _, v := range resp.Alarms {
+				switch v.Alarm {
+				case etcdserverpb.AlarmType_NOSPACE:
+					eh.Error = eh.Error + "NOSPACE "
+				case etcdserverpb.AlarmType_CORRUPT:
+					eh.Error = eh.Error + "CORRUPT "
+				case etcdserverpb.AlarmType_FOLLOWER_LAG:
+					eh.Error = eh.Error + "FOLLOWER_LAG "
+				case etcdserverpb.AlarmType_QUARANTINE:
+					eh.Error = eh.Error + "QUARANTINE "
+				default:
+					eh.Error = eh.Error + "UNKNOWN "
+				}
+			}
+		} else if err != nil {
+			eh.Health = false
+			eh.Error = "Unable to fetch the alarm list"
+		}
+	}
+	cancel()
+	return eh
+}
+
 type epStatus struct {
 	Ep   string                   `json:"Endpoint"`
 	Resp *clientv3.StatusResponse `json:"Status"`
@@ -244,6 +281,54 @@ func epHashKVCommandFunc(cmd *cobra.Command, args []string) {
 	}
 }
 
+// epLagCommandFunc executes the "endpoint lag" command.
+func epLagCommandFunc(cmd *cobra.Command, args []string) {
+	cfg := clientConfigFromCmd(cmd)
+
+	for _, ep := range endpointsFromCluster(cmd) {
+		cfg.Endpoints = []string{ep}
+		c := mustClient(cfg)
+		ctx, cancel := commandCtx(cmd)
+		resp, err := c.FollowerLag(ctx, ep)
+		cancel()
+		c.Close()
+		if err != nil {
+			if !errors.Is(err, rpctypes.ErrNotLeader) {
+				fmt.Fprintf(os.Stderr, "Failed to get the follower lag from endpoint %s (%v)\n", ep, err)
+			}
+			continue
+		}
+		display.FollowerLag(*resp)
+		return
+	}
+
+	cobrautl.ExitWithError(cobrautl.ExitError, fmt.Errorf("no leader found among the endpoints; use --cluster to query all members"))
+}
+
+// epMirrorCommandFunc executes the "endpoint mirror" command.
+func epMirrorCommandFunc(cmd *cobra.Command, args []string) {
+	cfg := clientConfigFromCmd(cmd)
+
+	for _, ep := range endpointsFromCluster(cmd) {
+		cfg.Endpoints = []string{ep}
+		c := mustClient(cfg)
+		ctx, cancel := commandCtx(cmd)
+		resp, err := c.MirrorStatus(ctx, ep)
+		cancel()
+		c.Close()
+		if err != nil {
+			if !errors.Is(err, rpctypes.ErrNotLeader) {
+				fmt.Fprintf(os.Stderr, "Failed to get the mirror status from endpoint %s (%v)\n", ep, err)
+			}
+			continue
+		}
+		display.MirrorStatus(*resp)
+		return
+	}
+
+	cobrautl.ExitWithError(cobrautl.ExitError, fmt.Errorf("no leader found among the endpoints; use --cluster to query all members"))
+}
+
 func endpointsFromCluster(cmd *cobra.Command) []string {
 	if !epClusterEndpoints {
 		endpoints, err := cmd.Flags().GetStringSlice("endpoints")
@@ -0,0 +1,165 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/time/rate"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/pkg/v3/cobrautl"
+)
+
+const defaultExportBatchSize = int64(1000)
+
+var (
+	exportPrefix    string
+	exportRev       int64
+	exportFormat    string
+	exportOutput    string
+	exportBatchSize int64
+	exportRateLimit float64
+)
+
+// NewExportCommand returns the cobra command for "export".
+func NewExportCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "export [options]",
+		Short: "Exports the keys of a prefix at a revision, with their leases",
+		Run:   exportCommandFunc,
+	}
+
+	c.Flags().StringVar(&exportPrefix, "prefix", "", "Key prefix to export, all keys if empty")
+	c.Flags().Int64Var(&exportRev, "rev", 0, "Revision to export the keys at, the current revision if 0")
+	c.Flags().StringVar(&exportFormat, "format", exportFormatJSON, "Export format (json, protobuf)")
+	c.Flags().StringVarP(&exportOutput, "output", "o", "-", "File to write the export to, stdout if '-'")
+	c.Flags().Int64Var(&exportBatchSize, "batch-size", defaultExportBatchSize, "Number of keys fetched per range request")
+	c.Flags().Float64Var(&exportRateLimit, "rate-limit", 0, "Maximum number of keys exported per second, unlimited if 0")
+
+	return c
+}
+
+func exportCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 0 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, errors.New("export command does not accept any arguments"))
+	}
+	if exportBatchSize <= 0 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, errors.New("--batch-size must be positive"))
+	}
+
+	out := os.Stdout
+	if exportOutput != "-" {
+		f, err := os.Create(exportOutput)
+		if err != nil {
+			cobrautl.ExitWithError(cobrautl.ExitError, err)
+		}
+		out = f
+	}
+	bw := bufio.NewWriter(out)
+	w, err := newExportWriter(exportFormat, bw)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+	}
+
+	n, rev, err := exportKeys(context.TODO(), mustClientFromCmd(cmd), w)
+	if err == nil {
+		err = bw.Flush()
+	}
+	if out != os.Stdout {
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d keys at revision %d\n", n, rev)
+}
+
+// exportKeys writes the keys of the prefix at the revision, and returns the
+// number of keys and the revision exported. The keys are written with their
+// leases, unless the leases expired: such keys are skipped.
+func exportKeys(ctx context.Context, c *clientv3.Client, w exportWriter) (n int64, rev int64, err error) {
+	key, end := exportPrefix, clientv3.GetPrefixRangeEnd(exportPrefix)
+	if exportPrefix == "" {
+		key, end = "\x00", "\x00"
+	}
+	rev = exportRev
+
+	var limiter *rate.Limiter
+	if exportRateLimit > 0 {
+		limiter = rate.NewLimiter(rate.Limit(exportRateLimit), 1)
+	}
+	// whether the leases written so far are alive
+	leases := make(map[int64]bool)
+
+	for {
+		opts := []clientv3.OpOption{clientv3.WithRange(end), clientv3.WithLimit(exportBatchSize)}
+		if rev > 0 {
+			opts = append(opts, clientv3.WithRev(rev))
+		}
+		resp, err := c.Get(ctx, key, opts...)
+		if err != nil {
+			return n, rev, err
+		}
+		if rev == 0 {
+			// pin the following pages to the revision of the first one
+			rev = resp.Header.Revision
+		}
+
+		for _, kv := range resp.Kvs {
+			if limiter != nil {
+				if err = limiter.Wait(ctx); err != nil {
+					return n, rev, err
+				}
+			}
+			if kv.Lease != 0 {
+				alive, ok := leases[kv.Lease]
+				if !ok {
+					ttl, err := c.TimeToLive(ctx, clientv3.LeaseID(kv.Lease))
+					if err != nil {
+						return n, rev, err
+					}
+					alive = ttl.TTL > 0
+					leases[kv.Lease] = alive
+					if alive {
+						if err = w.Write(exportRecord{Lease: &pb.LeaseGrantRequest{ID: kv.Lease, TTL: ttl.TTL}}); err != nil {
+							return n, rev, err
+						}
+					}
+				}
+				if !alive {
+					continue
+				}
+			}
+			if err = w.Write(exportRecord{KV: kv}); err != nil {
+				return n, rev, err
+			}
+			n++
+		}
+
+		if !resp.More || len(resp.Kvs) == 0 {
+			return n, rev, nil
+		}
+		key = string(append(resp.Kvs[len(resp.Kvs)-1].Key, 0))
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+)
+
+const (
+	exportFormatJSON     = "json"
+	exportFormatProtobuf = "protobuf"
+
+	// frame types of the protobuf format
+	exportFrameLease = byte(1)
+	exportFrameKV    = byte(2)
+
+	// maxExportFrameSize bounds the frames read, so that a corrupted export
+	// doesn't allocate unbounded memory.
+	maxExportFrameSize = 64 * 1024 * 1024
+)
+
+// exportRecord is a record of an export: either a lease, written before the
+// first key attached to it, or a key-value.
+//
+// The json format writes a record as a JSON object per line. The protobuf
+// format writes a record as a frame of its type, its length as a uvarint and
+// the protobuf encoding of the lease or of the key-value.
+type exportRecord struct {
+	Lease *pb.LeaseGrantRequest `json:"lease,omitempty"`
+	KV    *mvccpb.KeyValue      `json:"kv,omitempty"`
+}
+
+type exportWriter interface {
+	Write(r exportRecord) error
+}
+
+type exportReader interface {
+	// Read returns the next record, or io.EOF at the end of the export.
+	Read() (exportRecord, error)
+}
+
+func newExportWriter(format string, w io.Writer) (exportWriter, error) {
+	switch format {
+	case exportFormatJSON:
+		return &jsonExportWriter{enc: json.NewEncoder(w)}, nil
+	case exportFormatProtobuf:
+		return &protobufExportWriter{w: w}, nil
+	}
+	return nil, fmt.Errorf("unknown export format %q, expected %q or %q", format, exportFormatJSON, exportFormatProtobuf)
+}
+
+func newExportReader(format string, r io.Reader) (exportReader, error) {
+	switch format {
+	case exportFormatJSON:
+		return &jsonExportReader{dec: json.NewDecoder(r)}, nil
+	case exportFormatProtobuf:
+		return &protobufExportReader{r: bufio.NewReader(r)}, nil
+	}
+	return nil, fmt.Errorf("unknown export format %q, expected %q or %q", format, exportFormatJSON, exportFormatProtobuf)
+}
+
+func validateExportRecord(r exportRecord) error {
+	if (r.Lease == nil) == (r.KV == nil) {
+		return errors.New("invalid export record: expected either a lease or a key-value")
+	}
+	return nil
+}
+
+type jsonExportWriter struct {
+	enc *json.Encoder
+}
+
+func (w *jsonExportWriter) Write(r exportRecord) error {
+	return w.enc.Encode(r)
+}
+
+type jsonExportReader struct {
+	dec *json.Decoder
+}
+
+func (r *jsonExportReader) Read() (exportRecord, error) {
+	var rec exportRecord
+	if err := r.dec.Decode(&rec); err != nil {
+		return rec, err
+	}
+	return rec, validateExportRecord(rec)
+}
+
+type protobufExportWriter struct {
+	w   io.Writer
+	buf []byte
+}
+
+func (w *protobufExportWriter) Write(r exportRecord) error {
+	var (
+		typ     byte
+		payload []byte
+		err     error
+	)
+	switch {
+	case r.Lease != nil:
+		typ = exportFrameLease
+		payload, err = r.Lease.Marshal()
+	case r.KV != nil:
+		typ = exportFrameKV
+		payload, err = r.KV.Marshal()
+	}
+	if err != nil {
+		return err
+	}
+	w.buf = append(w.buf[:0], typ)
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(payload)))
+	w.buf = append(w.buf, payload...)
+	_, err = w.w.Write(w.buf)
+	return err
+}
+
+type protobufExportReader struct {
+	r *bufio.Reader
+}
+
+func (r *protobufExportReader) Read() (exportRecord, error) {
+	var rec exportRecord
+	typ, err := r.r.ReadByte()
+	if err != nil {
+		return rec, err
+	}
+	size, err := binary.ReadUvarint(r.r)
+	if err != nil {
+		return rec, noEOF(err)
+	}
+	if size > maxExportFrameSize {
+		return rec, fmt.Errorf("invalid export frame of %d bytes", size)
+	}
+	payload := make([]byte, size)
+	if _, err = io.ReadFull(r.r, payload); err != nil {
+		return rec, noEOF(err)
+	}
+	switch typ {
+	case exportFrameLease:
+		rec.Lease = &pb.LeaseGrantRequest{}
+		err = rec.Lease.Unmarshal(payload)
+	case exportFrameKV:
+		rec.KV = &mvccpb.KeyValue{}
+		err = rec.KV.Unmarshal(payload)
+	default:
+		err = fmt.Errorf("invalid export frame type %d", typ)
+	}
+	return rec, err
+}
+
+// noEOF reports a frame cut short as such rather than as the end of the
+// export.
+func noEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
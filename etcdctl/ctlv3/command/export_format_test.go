@@ -0,0 +1,72 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+)
+
+func TestExportFormats(t *testing.T) {
+	records := []exportRecord{
+		{Lease: &pb.LeaseGrantRequest{ID: 0x1234, TTL: 30}},
+		{KV: &mvccpb.KeyValue{Key: []byte("foo"), Value: []byte("bar\n\x00"), CreateRevision: 2, ModRevision: 3, Version: 2, Lease: 0x1234}},
+		{KV: &mvccpb.KeyValue{Key: []byte("zoo"), CreateRevision: 4, ModRevision: 4, Version: 1}},
+	}
+	for _, format := range []string{exportFormatJSON, exportFormatProtobuf} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := newExportWriter(format, &buf)
+			require.NoError(t, err)
+			for _, r := range records {
+				require.NoError(t, w.Write(r))
+			}
+			data := buf.Bytes()
+
+			r, err := newExportReader(format, bytes.NewReader(data))
+			require.NoError(t, err)
+			for _, want := range records {
+				got, err := r.Read()
+				require.NoError(t, err)
+				require.Equal(t, want, got)
+			}
+			_, err = r.Read()
+			require.ErrorIs(t, err, io.EOF)
+
+			// a truncated export is not mistaken for a complete one
+			r, err = newExportReader(format, bytes.NewReader(data[:len(data)-2]))
+			require.NoError(t, err)
+			for range 2 {
+				_, err = r.Read()
+				require.NoError(t, err)
+			}
+			_, err = r.Read()
+			require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		})
+	}
+
+	_, err := newExportWriter("yaml", io.Discard)
+	require.Error(t, err)
+	r, err := newExportReader(exportFormatJSON, bytes.NewReader([]byte(`{}`)))
+	require.NoError(t, err)
+	_, err = r.Read()
+	require.Error(t, err)
+}
@@ -16,6 +16,7 @@ package command
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -34,11 +35,15 @@ var (
 	getRev          int64
 	getKeysOnly     bool
 	getCountOnly    bool
+	getSizeOnly     bool
 	printValueOnly  bool
 	getMinCreateRev int64
 	getMaxCreateRev int64
 	getMinModRev    int64
 	getMaxModRev    int64
+	getFilterKey    string
+	getFilterRegex  bool
+	getLease        string
 )
 
 // NewGetCommand returns the cobra command for "get".
@@ -51,18 +56,22 @@ func NewGetCommand() *cobra.Command {
 
 	cmd.Flags().StringVar(&getConsistency, "consistency", "l", "Linearizable(l) or Serializable(s)")
 	cmd.Flags().StringVar(&getSortOrder, "order", "", "Order of results; ASCEND or DESCEND (ASCEND by default)")
-	cmd.Flags().StringVar(&getSortTarget, "sort-by", "", "Sort target; CREATE, KEY, MODIFY, VALUE, or VERSION")
+	cmd.Flags().StringVar(&getSortTarget, "sort-by", "", "Sort target; CREATE, KEY, LEASE, MODIFY, VALUE, or VERSION")
 	cmd.Flags().Int64Var(&getLimit, "limit", 0, "Maximum number of results")
 	cmd.Flags().BoolVar(&getPrefix, "prefix", false, "Get keys with matching prefix")
 	cmd.Flags().BoolVar(&getFromKey, "from-key", false, "Get keys that are greater than or equal to the given key using byte compare")
 	cmd.Flags().Int64Var(&getRev, "rev", 0, "Specify the kv revision")
 	cmd.Flags().BoolVar(&getKeysOnly, "keys-only", false, "Get only the keys")
 	cmd.Flags().BoolVar(&getCountOnly, "count-only", false, "Get only the count")
+	cmd.Flags().BoolVar(&getSizeOnly, "size-only", false, "Get only the count and the approximate total size in bytes")
 	cmd.Flags().BoolVar(&printValueOnly, "print-value-only", false, `Only write values when using the "simple" output format`)
 	cmd.Flags().Int64Var(&getMinCreateRev, "min-create-rev", 0, "Minimum create revision")
 	cmd.Flags().Int64Var(&getMaxCreateRev, "max-create-rev", 0, "Maximum create revision")
 	cmd.Flags().Int64Var(&getMinModRev, "min-mod-rev", 0, "Minimum modification revision")
 	cmd.Flags().Int64Var(&getMaxModRev, "max-mod-rev", 0, "Maximum modification revision")
+	cmd.Flags().StringVar(&getFilterKey, "filter-key", "", "Get only the keys containing the given substring, filtered by the server")
+	cmd.Flags().BoolVar(&getFilterRegex, "filter-key-regex", false, "Interpret --filter-key as a regular expression the keys must match")
+	cmd.Flags().StringVar(&getLease, "lease", "", "Get only the keys attached to the lease ID (in hexadecimal), filtered by the server")
 
 	cmd.RegisterFlagCompletionFunc("consistency", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		return []string{"l", "s"}, cobra.ShellCompDirectiveDefault
@@ -71,7 +80,7 @@ func NewGetCommand() *cobra.Command {
 		return []string{"ASCEND", "DESCEND"}, cobra.ShellCompDirectiveDefault
 	})
 	cmd.RegisterFlagCompletionFunc("sort-by", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
-		return []string{"CREATE", "KEY", "MODIFY", "VALUE", "VERSION"}, cobra.ShellCompDirectiveDefault
+		return []string{"CREATE", "KEY", "LEASE", "MODIFY", "VALUE", "VERSION"}, cobra.ShellCompDirectiveDefault
 	})
 
 	return cmd
@@ -93,6 +102,12 @@ func getCommandFunc(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	if getSizeOnly {
+		if _, fields := display.(*fieldsPrinter); !fields {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("--size-only is only for `--write-out=fields`"))
+		}
+	}
+
 	if printValueOnly {
 		dp, simple := (display).(*simplePrinter)
 		if !simple {
@@ -116,6 +131,10 @@ func getGetOp(args []string) (string, []clientv3.OpOption) {
 		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("`--keys-only` and `--count-only` cannot be set at the same time, choose one"))
 	}
 
+	if getKeysOnly && getSizeOnly {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("`--keys-only` and `--size-only` cannot be set at the same time, choose one"))
+	}
+
 	var opts []clientv3.OpOption
 	if IsSerializable(getConsistency) {
 		opts = append(opts, clientv3.WithSerializable())
@@ -154,6 +173,8 @@ func getGetOp(args []string) (string, []clientv3.OpOption) {
 		sortByTarget = clientv3.SortByCreateRevision
 	case sortTarget == "KEY":
 		sortByTarget = clientv3.SortByKey
+	case sortTarget == "LEASE":
+		sortByTarget = clientv3.SortByLease
 	case sortTarget == "MODIFY":
 		sortByTarget = clientv3.SortByModRevision
 	case sortTarget == "VALUE":
@@ -192,6 +213,10 @@ func getGetOp(args []string) (string, []clientv3.OpOption) {
 		opts = append(opts, clientv3.WithCountOnly())
 	}
 
+	if getSizeOnly {
+		opts = append(opts, clientv3.WithSizeOnly())
+	}
+
 	if getMinCreateRev > 0 {
 		opts = append(opts, clientv3.WithMinCreateRev(getMinCreateRev))
 	}
@@ -216,5 +241,25 @@ func getGetOp(args []string) (string, []clientv3.OpOption) {
 		opts = append(opts, clientv3.WithMaxModRev(getMaxModRev))
 	}
 
+	if getFilterRegex && getFilterKey == "" {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("`--filter-key-regex` requires `--filter-key`"))
+	}
+
+	if getFilterKey != "" {
+		if getFilterRegex {
+			opts = append(opts, clientv3.WithKeyFilterRegex(getFilterKey))
+		} else {
+			opts = append(opts, clientv3.WithKeyFilter(getFilterKey))
+		}
+	}
+
+	if getLease != "" {
+		id, err := strconv.ParseInt(getLease, 16, 64)
+		if err != nil {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("bad lease ID (%w), expecting ID in Hex", err))
+		}
+		opts = append(opts, clientv3.WithLeaseFilter(clientv3.LeaseID(id)))
+	}
+
 	return key, opts
 }
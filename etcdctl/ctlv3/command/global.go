@@ -60,6 +60,8 @@ type GlobalFlags struct {
 	Password string
 	Token    string
 
+	Namespace string
+
 	Debug bool
 }
 
@@ -80,9 +82,29 @@ func initDisplayFromCmd(cmd *cobra.Command) {
 	if err != nil {
 		cobrautl.ExitWithError(cobrautl.ExitError, err)
 	}
-	if display = NewPrinter(outputType, isHex); display == nil {
+	p := NewPrinter(outputType, isHex)
+	if p == nil {
 		cobrautl.ExitWithError(cobrautl.ExitBadFeature, errors.New("unsupported output format"))
 	}
+	setDisplay(p)
+}
+
+// InitOutputFromCmd sets the output format from the flags of the command
+// before it runs, for the errors it exits with early to be printed in it
+// too. An unsupported output format is left for the command to report.
+func InitOutputFromCmd(cmd *cobra.Command) {
+	isHex, _ := cmd.Flags().GetBool("hex")
+	outputType, _ := cmd.Flags().GetString("write-out")
+	if p := NewPrinter(outputType, isHex); p != nil {
+		setDisplay(p)
+	}
+}
+
+// setDisplay prints the results, and the errors the command exits with,
+// with p.
+func setDisplay(p printer) {
+	display = p
+	cobrautl.ErrorPrinter = func(code int, err error) { p.Error(newCmdError(code, err)) }
 }
 
 type discardValue struct{}
@@ -136,6 +158,10 @@ func clientConfigFromCmd(cmd *cobra.Command) *clientv3.ConfigSpec {
 
 	cfg.Secure = secureCfgFromCmd(cmd)
 	cfg.Auth = authCfgFromCmd(cmd)
+	cfg.Namespace, err = cmd.Flags().GetString("namespace")
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
 
 	initDisplayFromCmd(cmd)
 	return cfg
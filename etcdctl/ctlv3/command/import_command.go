@@ -0,0 +1,165 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/time/rate"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/pkg/v3/cobrautl"
+)
+
+const (
+	importLeaseModeRemap = "remap"
+	importLeaseModeDrop  = "drop"
+)
+
+var (
+	importPrefix    string
+	importFormat    string
+	importBatchSize uint
+	importRateLimit float64
+	importLeaseMode string
+)
+
+// NewImportCommand returns the cobra command for "import".
+func NewImportCommand() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "import [options] [<file>]",
+		Short: "Imports the keys of an export, from the file or stdin",
+		Run:   importCommandFunc,
+	}
+
+	c.Flags().StringVar(&importPrefix, "prefix", "", "Key prefix to import, all the keys of the export if empty")
+	c.Flags().StringVar(&importFormat, "format", exportFormatJSON, "Export format (json, protobuf)")
+	c.Flags().UintVar(&importBatchSize, "batch-size", defaultMaxTxnOps, "Number of keys put per transaction")
+	c.Flags().Float64Var(&importRateLimit, "rate-limit", 0, "Maximum number of keys imported per second, unlimited if 0")
+	c.Flags().StringVar(&importLeaseMode, "lease-mode", importLeaseModeRemap, "Handling of the leases of the keys: 'remap' grants a new lease for each exported lease with its remaining TTL, 'drop' puts the keys without lease")
+
+	return c
+}
+
+func importCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) > 1 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, errors.New("import command accepts at most one file argument"))
+	}
+	if importBatchSize == 0 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, errors.New("--batch-size must be positive"))
+	}
+	if importLeaseMode != importLeaseModeRemap && importLeaseMode != importLeaseModeDrop {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("unknown --lease-mode %q, expected %q or %q", importLeaseMode, importLeaseModeRemap, importLeaseModeDrop))
+	}
+
+	in := os.Stdin
+	if len(args) == 1 && args[0] != "-" {
+		f, err := os.Open(args[0])
+		if err != nil {
+			cobrautl.ExitWithError(cobrautl.ExitError, err)
+		}
+		defer f.Close()
+		in = f
+	}
+	r, err := newExportReader(importFormat, in)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+	}
+
+	n, err := importKeys(context.TODO(), mustClientFromCmd(cmd), r)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, fmt.Errorf("imported %d keys before failing: %w", n, err))
+	}
+	fmt.Printf("Imported %d keys\n", n)
+}
+
+// importKeys puts the keys of the export, and returns the number of keys
+// put. With the remap lease mode, the leases of the export are granted anew
+// when their first key is imported.
+func importKeys(ctx context.Context, c *clientv3.Client, r exportReader) (n int64, err error) {
+	var limiter *rate.Limiter
+	if importRateLimit > 0 {
+		limiter = rate.NewLimiter(rate.Limit(importRateLimit), 1)
+	}
+	ttls := make(map[int64]int64)
+	leases := make(map[int64]clientv3.LeaseID)
+
+	var ops []clientv3.Op
+	flush := func() error {
+		if len(ops) == 0 {
+			return nil
+		}
+		if _, err := c.Txn(ctx).Then(ops...).Commit(); err != nil {
+			return err
+		}
+		n += int64(len(ops))
+		ops = ops[:0]
+		return nil
+	}
+
+	for {
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return n, err
+		}
+		if rec.Lease != nil {
+			ttls[rec.Lease.ID] = rec.Lease.TTL
+			continue
+		}
+
+		kv := rec.KV
+		if !bytes.HasPrefix(kv.Key, []byte(importPrefix)) {
+			continue
+		}
+		var opts []clientv3.OpOption
+		if kv.Lease != 0 && importLeaseMode == importLeaseModeRemap {
+			id, ok := leases[kv.Lease]
+			if !ok {
+				ttl, ok := ttls[kv.Lease]
+				if !ok {
+					return n, fmt.Errorf("key %q is attached to lease %016x missing from the export", kv.Key, kv.Lease)
+				}
+				resp, err := c.Grant(ctx, ttl)
+				if err != nil {
+					return n, err
+				}
+				id = resp.ID
+				leases[kv.Lease] = id
+			}
+			opts = append(opts, clientv3.WithLease(id))
+		}
+		if limiter != nil {
+			if err = limiter.Wait(ctx); err != nil {
+				return n, err
+			}
+		}
+		ops = append(ops, clientv3.OpPut(string(kv.Key), string(kv.Value), opts...))
+		if len(ops) >= int(importBatchSize) {
+			if err = flush(); err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, flush()
+}
@@ -41,14 +41,17 @@ func NewLeaseCommand() *cobra.Command {
 	return lc
 }
 
+var leaseGrantMetadata string
+
 // NewLeaseGrantCommand returns the cobra command for "lease grant".
 func NewLeaseGrantCommand() *cobra.Command {
 	lc := &cobra.Command{
-		Use:   "grant <ttl>",
+		Use:   "grant <ttl> [options]",
 		Short: "Creates leases",
 
 		Run: leaseGrantCommandFunc,
 	}
+	lc.Flags().StringVar(&leaseGrantMetadata, "metadata", "", "Metadata stored with the lease, e.g. its owner")
 
 	return lc
 }
@@ -65,7 +68,11 @@ func leaseGrantCommandFunc(cmd *cobra.Command, args []string) {
 	}
 
 	ctx, cancel := commandCtx(cmd)
-	resp, err := mustClientFromCmd(cmd).Grant(ctx, ttl)
+	var opts []v3.LeaseOption
+	if leaseGrantMetadata != "" {
+		opts = append(opts, v3.WithLeaseMetadata([]byte(leaseGrantMetadata)))
+	}
+	resp, err := mustClientFromCmd(cmd).Grant(ctx, ttl, opts...)
 	cancel()
 	if err != nil {
 		cobrautl.ExitWithError(cobrautl.ExitError, fmt.Errorf("failed to grant lease (%w)", err))
@@ -132,19 +139,32 @@ func leaseTimeToLiveCommandFunc(cmd *cobra.Command, args []string) {
 	display.TimeToLive(*resp, timeToLiveKeys)
 }
 
+var (
+	leaseListMetadataPrefix string
+	leaseListMinTTL         int64
+	leaseListMaxTTL         int64
+)
+
 // NewLeaseListCommand returns the cobra command for "lease list".
 func NewLeaseListCommand() *cobra.Command {
 	lc := &cobra.Command{
-		Use:   "list",
+		Use:   "list [options]",
 		Short: "List all active leases",
 		Run:   leaseListCommandFunc,
 	}
+	lc.Flags().StringVar(&leaseListMetadataPrefix, "metadata-prefix", "", "List only the leases whose metadata starts with the given prefix")
+	lc.Flags().Int64Var(&leaseListMinTTL, "min-ttl", 0, "List only the leases granted with a TTL of at least the given seconds")
+	lc.Flags().Int64Var(&leaseListMaxTTL, "max-ttl", 0, "List only the leases granted with a TTL of at most the given seconds")
 	return lc
 }
 
 // leaseListCommandFunc executes the "lease list" command.
 func leaseListCommandFunc(cmd *cobra.Command, args []string) {
-	resp, rerr := mustClientFromCmd(cmd).Leases(context.TODO())
+	opts := []v3.LeaseOption{v3.WithGrantedTTLRange(leaseListMinTTL, leaseListMaxTTL)}
+	if leaseListMetadataPrefix != "" {
+		opts = append(opts, v3.WithMetadataPrefix([]byte(leaseListMetadataPrefix)))
+	}
+	resp, rerr := mustClientFromCmd(cmd).Leases(context.TODO(), opts...)
 	if rerr != nil {
 		cobrautl.ExitWithError(cobrautl.ExitBadConnection, rerr)
 	}
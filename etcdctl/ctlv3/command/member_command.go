@@ -15,10 +15,12 @@
 package command
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -29,7 +31,10 @@ import (
 var (
 	memberPeerURLs    string
 	isLearner         bool
+	isStandby         bool
 	memberConsistency string
+	memberWaitTimeout time.Duration
+	memberListHealth  bool
 )
 
 // NewMemberCommand returns the cobra command for "member".
@@ -44,6 +49,7 @@ func NewMemberCommand() *cobra.Command {
 	mc.AddCommand(NewMemberUpdateCommand())
 	mc.AddCommand(NewMemberListCommand())
 	mc.AddCommand(NewMemberPromoteCommand())
+	mc.AddCommand(NewMemberReplaceCommand())
 
 	return mc
 }
@@ -59,6 +65,7 @@ func NewMemberAddCommand() *cobra.Command {
 
 	cc.Flags().StringVar(&memberPeerURLs, "peer-urls", "", "comma separated peer URLs for the new member.")
 	cc.Flags().BoolVar(&isLearner, "learner", false, "indicates if the new member is raft learner")
+	cc.Flags().BoolVar(&isStandby, "standby", false, "indicates if the new member is a standby member, a learner serving reads and watches that is never promoted")
 
 	return cc
 }
@@ -96,12 +103,16 @@ func NewMemberListCommand() *cobra.Command {
 		Short: "Lists all members in the cluster",
 		Long: `When --write-out is set to simple, this command prints out comma-separated member lists for each endpoint.
 The items in the lists are ID, Status, Name, Peer Addrs, Client Addrs, Is Learner.
+
+With --health, the started members are queried concurrently on their client URLs, and the items in the lists
+are followed by Health, DB Size, Raft Index, Raft Lag, Took, Error.
 `,
 
 		Run: memberListCommandFunc,
 	}
 
 	cc.Flags().StringVar(&memberConsistency, "consistency", "l", "Linearizable(l) or Serializable(s)")
+	cc.Flags().BoolVar(&memberListHealth, "health", false, "query each member for its health, db size and raft lag")
 
 	return cc
 }
@@ -120,6 +131,26 @@ func NewMemberPromoteCommand() *cobra.Command {
 	return cc
 }
 
+// NewMemberReplaceCommand returns the cobra command for "member replace".
+func NewMemberReplaceCommand() *cobra.Command {
+	cc := &cobra.Command{
+		Use:   "replace <memberID> [options]",
+		Short: "Replaces a member in the cluster by a new member",
+		Long: `Replaces a member in the cluster by a new member, which is added as a learner.
+Once the new member is started and caught up with the leader, it is promoted and
+the replaced member is removed. The new member is removed again if it is not
+promoted within --wait-timeout.
+`,
+
+		Run: memberReplaceCommandFunc,
+	}
+
+	cc.Flags().StringVar(&memberPeerURLs, "peer-urls", "", "comma separated peer URLs for the new member.")
+	cc.Flags().DurationVar(&memberWaitTimeout, "wait-timeout", 5*time.Minute, "timeout for the new member to be started and catch up with the leader.")
+
+	return cc
+}
+
 // memberAddCommandFunc executes the "member add" command.
 func memberAddCommandFunc(cmd *cobra.Command, args []string) {
 	if len(args) < 1 {
@@ -147,9 +178,12 @@ func memberAddCommandFunc(cmd *cobra.Command, args []string) {
 		resp *clientv3.MemberAddResponse
 		err  error
 	)
-	if isLearner {
+	switch {
+	case isStandby:
+		resp, err = cli.MemberAddAsStandby(ctx, urls)
+	case isLearner:
 		resp, err = cli.MemberAddAsLearner(ctx, urls)
-	} else {
+	default:
 		resp, err = cli.MemberAdd(ctx, urls)
 	}
 	cancel()
@@ -240,7 +274,17 @@ func memberListCommandFunc(cmd *cobra.Command, args []string) {
 		cobrautl.ExitWithError(cobrautl.ExitError, err)
 	}
 
-	display.MemberList(*resp)
+	if !memberListHealth {
+		display.MemberList(*resp)
+		return
+	}
+	hs := membersHealth(cmd, resp.Members)
+	display.MemberListHealth(hs)
+	for _, h := range hs {
+		if !h.Health {
+			cobrautl.ExitWithError(cobrautl.ExitError, fmt.Errorf("unhealthy cluster"))
+		}
+	}
 }
 
 // memberPromoteCommandFunc executes the "member promote" command.
@@ -262,3 +306,29 @@ func memberPromoteCommandFunc(cmd *cobra.Command, args []string) {
 	}
 	display.MemberPromote(id, *resp)
 }
+
+// memberReplaceCommandFunc executes the "member replace" command.
+func memberReplaceCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("member ID is not provided"))
+	}
+
+	id, err := strconv.ParseUint(args[0], 16, 64)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("bad member ID arg (%w), expecting ID in Hex", err))
+	}
+
+	if len(memberPeerURLs) == 0 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("member peer urls not provided"))
+	}
+
+	urls := strings.Split(memberPeerURLs, ",")
+
+	ctx, cancel := context.WithTimeout(context.Background(), memberWaitTimeout)
+	resp, err := mustClientFromCmd(cmd).MemberReplace(ctx, id, urls)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	display.MemberReplace(id, *resp)
+}
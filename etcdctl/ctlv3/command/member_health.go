@@ -0,0 +1,110 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"sync"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/client/pkg/v3/logutil"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/pkg/v3/cobrautl"
+)
+
+// memberHealth is a member of the cluster with its health and status, as
+// reported on its client URLs.
+type memberHealth struct {
+	Member *etcdserverpb.Member     `json:"member"`
+	Health bool                     `json:"health"`
+	Took   string                   `json:"took,omitempty"`
+	Status *clientv3.StatusResponse `json:"status,omitempty"`
+	// RaftLag is the number of raft entries the member lags behind the
+	// leader, if the status of both is known.
+	RaftLag uint64 `json:"raftLag"`
+	Error   string `json:"error,omitempty"`
+}
+
+// membersHealth concurrently checks the health and gets the status of the
+// members, in the order of the members.
+func membersHealth(cmd *cobra.Command, members []*etcdserverpb.Member) []memberHealth {
+	lg, err := logutil.CreateDefaultZapLogger(zap.InfoLevel)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	cfgSpec := clientConfigFromCmd(cmd)
+
+	hs := make([]memberHealth, len(members))
+	var wg sync.WaitGroup
+	for i, m := range members {
+		hs[i].Member = m
+		if len(m.Name) == 0 || len(m.ClientURLs) == 0 {
+			hs[i].Error = "unstarted"
+			continue
+		}
+		spec := cfgSpec.Clone()
+		spec.Endpoints = m.ClientURLs
+		cfg, err := clientv3.NewClientConfig(spec, lg)
+		if err != nil {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+		}
+		cfg.Logger = lg.Named("client")
+		wg.Add(1)
+		go func(h *memberHealth) {
+			defer wg.Done()
+			cli, err := clientv3.New(*cfg)
+			if err != nil {
+				h.Error = err.Error()
+				return
+			}
+			defer cli.Close()
+			ep := cfg.Endpoints[0]
+			eh := checkEndpointHealth(cmd, cli, ep)
+			h.Health, h.Took, h.Error = eh.Health, eh.Took, eh.Error
+			ctx, cancel := commandCtx(cmd)
+			defer cancel()
+			if h.Status, err = cli.Status(ctx, ep); err != nil && h.Error == "" {
+				h.Health = false
+				h.Error = err.Error()
+			}
+		}(&hs[i])
+	}
+	wg.Wait()
+
+	// the leader is the one most members agree on
+	votes := make(map[uint64]int)
+	for _, h := range hs {
+		if h.Status != nil {
+			votes[h.Status.Leader]++
+		}
+	}
+	var leader *clientv3.StatusResponse
+	for _, h := range hs {
+		if h.Status != nil && h.Status.Header.MemberId == h.Status.Leader &&
+			(leader == nil || votes[h.Status.Leader] > votes[leader.Leader]) {
+			leader = h.Status
+		}
+	}
+	if leader != nil {
+		for i := range hs {
+			if st := hs[i].Status; st != nil && st.RaftIndex < leader.RaftIndex {
+				hs[i].RaftLag = leader.RaftIndex - st.RaftIndex
+			}
+		}
+	}
+	return hs
+}
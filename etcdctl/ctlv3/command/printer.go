@@ -15,14 +15,19 @@
 package command
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
 	"github.com/dustin/go-humanize"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	v3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/pkg/v3/cobrautl"
 )
@@ -44,18 +49,28 @@ type printer interface {
 	MemberRemove(id uint64, r v3.MemberRemoveResponse)
 	MemberUpdate(id uint64, r v3.MemberUpdateResponse)
 	MemberPromote(id uint64, r v3.MemberPromoteResponse)
+	MemberReplace(id uint64, r v3.MemberReplaceResponse)
 	MemberList(v3.MemberListResponse)
+	MemberListHealth([]memberHealth)
 
 	EndpointHealth([]epHealth)
 	EndpointStatus([]epStatus)
 	EndpointHashKV([]epHashKV)
+	Defrag(epDefrag)
+	DefragStatus(epDefragStatus)
 	MoveLeader(leader, target uint64, r v3.MoveLeaderResponse)
 
 	DowngradeValidate(r v3.DowngradeResponse)
 	DowngradeEnable(r v3.DowngradeResponse)
 	DowngradeCancel(r v3.DowngradeResponse)
+	DowngradeCheck(r v3.DowngradeCheckResponse)
 
 	Alarm(v3.AlarmResponse)
+	ReadOnly(v3.ReadOnlyResponse)
+	FollowerLag(v3.FollowerLagResponse)
+	HashKVCheck(v3.HashKVCheckResponse)
+	MirrorStatus(v3.MirrorStatusResponse)
+	CheckPerf(checkPerfResult)
 
 	RoleAdd(role string, r v3.AuthRoleAddResponse)
 	RoleGet(role string, r v3.AuthRoleGetResponse)
@@ -73,6 +88,12 @@ type printer interface {
 	UserDelete(user string, r v3.AuthUserDeleteResponse)
 
 	AuthStatus(r v3.AuthStatusResponse)
+	AuthEnable(r v3.AuthEnableResponse)
+	AuthDisable(r v3.AuthDisableResponse)
+	TokenRevoke(r v3.AuthTokenRevokeResponse)
+
+	// Error prints the error a command exits with.
+	Error(cmdError)
 }
 
 func NewPrinter(printerType string, isHex bool) printer {
@@ -120,8 +141,24 @@ func (p *printerRPC) MemberUpdate(id uint64, r v3.MemberUpdateResponse) {
 func (p *printerRPC) MemberPromote(id uint64, r v3.MemberPromoteResponse) {
 	p.p((*pb.MemberPromoteResponse)(&r))
 }
+func (p *printerRPC) MemberReplace(id uint64, r v3.MemberReplaceResponse) {
+	p.p((*pb.MemberReplaceResponse)(&r))
+}
 func (p *printerRPC) MemberList(r v3.MemberListResponse) { p.p((*pb.MemberListResponse)(&r)) }
 func (p *printerRPC) Alarm(r v3.AlarmResponse)           { p.p((*pb.AlarmResponse)(&r)) }
+func (p *printerRPC) ReadOnly(r v3.ReadOnlyResponse)     { p.p((*pb.ReadOnlyResponse)(&r)) }
+func (p *printerRPC) FollowerLag(r v3.FollowerLagResponse) {
+	p.p((*pb.FollowerLagResponse)(&r))
+}
+func (p *printerRPC) HashKVCheck(r v3.HashKVCheckResponse) {
+	p.p((*pb.HashKVCheckResponse)(&r))
+}
+func (p *printerRPC) MirrorStatus(r v3.MirrorStatusResponse) {
+	p.p((*pb.MirrorStatusResponse)(&r))
+}
+func (p *printerRPC) DowngradeCheck(r v3.DowngradeCheckResponse) {
+	p.p((*pb.DowngradeCheckResponse)(&r))
+}
 func (p *printerRPC) MoveLeader(leader, target uint64, r v3.MoveLeaderResponse) {
 	p.p((*pb.MoveLeaderResponse)(&r))
 }
@@ -164,6 +201,20 @@ func (p *printerRPC) AuthStatus(r v3.AuthStatusResponse) {
 	p.p((*pb.AuthStatusResponse)(&r))
 }
 
+func (p *printerRPC) AuthEnable(r v3.AuthEnableResponse) {
+	p.p((*pb.AuthEnableResponse)(&r))
+}
+
+func (p *printerRPC) AuthDisable(r v3.AuthDisableResponse) {
+	p.p((*pb.AuthDisableResponse)(&r))
+}
+
+func (p *printerRPC) TokenRevoke(r v3.AuthTokenRevokeResponse) {
+	p.p((*pb.AuthTokenRevokeResponse)(&r))
+}
+
+func (p *printerRPC) Error(e cmdError) { printErrorLine(e) }
+
 type printerUnsupported struct{ printerRPC }
 
 func newPrinterUnsupported(n string) printer {
@@ -173,34 +224,93 @@ func newPrinterUnsupported(n string) printer {
 	return &printerUnsupported{printerRPC{nil, f}}
 }
 
-func (p *printerUnsupported) EndpointHealth([]epHealth) { p.p(nil) }
-func (p *printerUnsupported) EndpointStatus([]epStatus) { p.p(nil) }
-func (p *printerUnsupported) EndpointHashKV([]epHashKV) { p.p(nil) }
+func (p *printerUnsupported) EndpointHealth([]epHealth)   { p.p(nil) }
+func (p *printerUnsupported) EndpointStatus([]epStatus)   { p.p(nil) }
+func (p *printerUnsupported) EndpointHashKV([]epHashKV)   { p.p(nil) }
+func (p *printerUnsupported) Defrag(epDefrag)             { p.p(nil) }
+func (p *printerUnsupported) DefragStatus(epDefragStatus) { p.p(nil) }
+func (p *printerUnsupported) CheckPerf(checkPerfResult)   { p.p(nil) }
+
+func (p *printerUnsupported) MemberListHealth([]memberHealth) { p.p(nil) }
 
 func (p *printerUnsupported) MoveLeader(leader, target uint64, r v3.MoveLeaderResponse) { p.p(nil) }
 func (p *printerUnsupported) DowngradeValidate(r v3.DowngradeResponse)                  { p.p(nil) }
 func (p *printerUnsupported) DowngradeEnable(r v3.DowngradeResponse)                    { p.p(nil) }
 func (p *printerUnsupported) DowngradeCancel(r v3.DowngradeResponse)                    { p.p(nil) }
 
+// cmdError is the error a command exits with, as printed by the structured
+// output formats.
+type cmdError struct {
+	// ExitCode is the exit code of etcdctl.
+	ExitCode int    `json:"exitCode"`
+	Message  string `json:"message"`
+	// GRPCCode is the gRPC status code of the error, if returned by etcd.
+	GRPCCode string `json:"grpcCode,omitempty"`
+}
+
+func newCmdError(code int, err error) cmdError {
+	e := cmdError{ExitCode: code, Message: err.Error()}
+	var ee rpctypes.EtcdError
+	switch s, ok := status.FromError(err); {
+	case errors.As(err, &ee):
+		e.GRPCCode = ee.Code().String()
+	case ok && s.Code() != codes.OK:
+		e.GRPCCode = s.Code().String()
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled):
+		e.GRPCCode = status.FromContextError(err).Code().String()
+	}
+	return e
+}
+
+// printErrorLine prints the error as the simple output format does.
+func printErrorLine(e cmdError) {
+	fmt.Fprintln(os.Stderr, "Error:", e.Message)
+}
+
 func makeMemberListTable(r v3.MemberListResponse) (hdr []string, rows [][]string) {
 	hdr = []string{"ID", "Status", "Name", "Peer Addrs", "Client Addrs", "Is Learner"}
 	for _, m := range r.Members {
-		status := "started"
-		if len(m.Name) == 0 {
-			status = "unstarted"
-		}
-		isLearner := "false"
-		if m.IsLearner {
-			isLearner = "true"
+		rows = append(rows, makeMemberRow(m))
+	}
+	return hdr, rows
+}
+
+func makeMemberRow(m *pb.Member) []string {
+	status := "started"
+	if len(m.Name) == 0 {
+		status = "unstarted"
+	}
+	isLearner := "false"
+	if m.IsLearner {
+		isLearner = "true"
+	}
+	return []string{
+		fmt.Sprintf("%x", m.ID),
+		status,
+		m.Name,
+		strings.Join(m.PeerURLs, ","),
+		strings.Join(m.ClientURLs, ","),
+		isLearner,
+	}
+}
+
+func makeMemberListHealthTable(hs []memberHealth) (hdr []string, rows [][]string) {
+	hdr = []string{"ID", "Status", "Name", "Peer Addrs", "Client Addrs", "Is Learner", "Health", "DB Size", "Raft Index", "Raft Lag", "Took", "Error"}
+	for _, h := range hs {
+		dbSize, raftIndex, raftLag := "", "", ""
+		if h.Status != nil {
+			dbSize = humanize.Bytes(uint64(h.Status.DbSize))
+			raftIndex = fmt.Sprint(h.Status.RaftIndex)
+			raftLag = fmt.Sprint(h.RaftLag)
 		}
-		rows = append(rows, []string{
-			fmt.Sprintf("%x", m.ID),
-			status,
-			m.Name,
-			strings.Join(m.PeerURLs, ","),
-			strings.Join(m.ClientURLs, ","),
-			isLearner,
-		})
+		rows = append(rows, append(makeMemberRow(h.Member),
+			fmt.Sprint(h.Health),
+			dbSize,
+			raftIndex,
+			raftLag,
+			h.Took,
+			h.Error,
+		))
 	}
 	return hdr, rows
 }
@@ -246,6 +356,82 @@ func makeEndpointStatusTable(statusList []epStatus) (hdr []string, rows [][]stri
 	return hdr, rows
 }
 
+func makeFollowerLagTable(r v3.FollowerLagResponse) (hdr []string, rows [][]string) {
+	hdr = []string{"ID", "is learner", "match index", "lag", "state", "active"}
+	for _, f := range r.Followers {
+		rows = append(rows, []string{
+			fmt.Sprintf("%x", f.ID),
+			fmt.Sprint(f.IsLearner),
+			fmt.Sprint(f.MatchIndex),
+			fmt.Sprint(f.Lag),
+			f.State,
+			fmt.Sprint(f.Active),
+		})
+	}
+	return hdr, rows
+}
+
+func makeMirrorStatusTable(r v3.MirrorStatusResponse) (hdr []string, rows [][]string) {
+	hdr = []string{"target endpoints", "source prefix", "dest prefix", "mirrored revision", "lag", "resyncs", "error"}
+	rows = append(rows, []string{
+		strings.Join(r.TargetEndpoints, ","),
+		r.SourcePrefix,
+		r.DestPrefix,
+		fmt.Sprint(r.MirroredRevision),
+		fmt.Sprint(r.Lag),
+		fmt.Sprint(r.Resyncs),
+		r.Error,
+	})
+	return hdr, rows
+}
+
+func makeHashKVCheckTable(r v3.HashKVCheckResponse) (hdr []string, rows [][]string) {
+	hdr = []string{"member ID", "hash", "compact revision", "error"}
+	for _, h := range r.Hashes {
+		rows = append(rows, []string{
+			fmt.Sprintf("%x", h.MemberId),
+			fmt.Sprint(h.Hash),
+			fmt.Sprint(h.CompactRevision),
+			h.Error,
+		})
+	}
+	return hdr, rows
+}
+
+// hashKVCheckResult describes whether the hashes of a HashKVCheck matched.
+func hashKVCheckResult(r v3.HashKVCheckResponse) string {
+	if r.Consistent {
+		return fmt.Sprintf("PASSED: hashes of all members match at revision %d", r.Revision)
+	}
+	return fmt.Sprintf("FAILED: hashes of the members do not match at revision %d", r.Revision)
+}
+
+func makeDowngradeCheckTable(r v3.DowngradeCheckResponse) (hdr []string, rows [][]string) {
+	hdr = []string{"member ID", "storage version", "WAL version", "features", "blockers"}
+	for _, m := range r.Members {
+		rows = append(rows, []string{
+			fmt.Sprintf("%x", m.MemberId),
+			m.StorageVersion,
+			m.WalVersion,
+			strings.Join(m.Features, " "),
+			strings.Join(m.Blockers, "; "),
+		})
+	}
+	return hdr, rows
+}
+
+// downgradeCheckResult describes whether a DowngradeCheck found anything
+// blocking the downgrade.
+func downgradeCheckResult(r v3.DowngradeCheckResponse) string {
+	switch {
+	case r.Downgradable:
+		return "PASSED: nothing blocks the downgrade"
+	case len(r.Blockers) != 0:
+		return "FAILED: " + strings.Join(r.Blockers, "; ")
+	}
+	return "FAILED: members block the downgrade"
+}
+
 func makeEndpointHashKVTable(hashList []epHashKV) (hdr []string, rows [][]string) {
 	hdr = []string{"endpoint", "hash", "hash_revision"}
 	for _, h := range hashList {
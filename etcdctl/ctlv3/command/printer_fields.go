@@ -16,6 +16,7 @@ package command
 
 import (
 	"fmt"
+	"os"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	spb "go.etcd.io/etcd/api/v3/mvccpb"
@@ -39,6 +40,9 @@ func (p *fieldsPrinter) kv(pfx string, kv *spb.KeyValue) {
 	} else {
 		fmt.Printf("\"%sLease\" : %d\n", pfx, kv.Lease)
 	}
+	if kv.Ttl != 0 {
+		fmt.Printf("\"%sTTL\" : %d\n", pfx, kv.Ttl)
+	}
 }
 
 func (p *fieldsPrinter) hdr(h *pb.ResponseHeader) {
@@ -73,6 +77,9 @@ func (p *fieldsPrinter) Get(r v3.GetResponse) {
 	}
 	fmt.Println(`"More" :`, r.More)
 	fmt.Println(`"Count" :`, r.Count)
+	if r.TotalSize != 0 {
+		fmt.Println(`"TotalSize" :`, r.TotalSize)
+	}
 }
 
 func (p *fieldsPrinter) Put(r v3.PutResponse) {
@@ -146,6 +153,9 @@ func (p *fieldsPrinter) TimeToLive(r v3.LeaseTimeToLiveResponse, keys bool) {
 	for _, k := range r.Keys {
 		fmt.Printf("\"Key\" : %q\n", string(k))
 	}
+	if len(r.Metadata) > 0 {
+		fmt.Printf("\"Metadata\" : %q\n", string(r.Metadata))
+	}
 }
 
 func (p *fieldsPrinter) Leases(r v3.LeaseLeasesResponse) {
@@ -159,22 +169,79 @@ func (p *fieldsPrinter) Leases(r v3.LeaseLeasesResponse) {
 	}
 }
 
+func (p *fieldsPrinter) id(name string, id uint64) {
+	if p.isHex {
+		fmt.Printf("\"%s\" : %s\n", name, types.ID(id))
+	} else {
+		fmt.Printf("\"%s\" : %d\n", name, id)
+	}
+}
+
+func (p *fieldsPrinter) member(m *pb.Member) {
+	p.id("ID", m.ID)
+	fmt.Printf("\"Name\" : %q\n", m.Name)
+	for _, u := range m.PeerURLs {
+		fmt.Printf("\"PeerURL\" : %q\n", u)
+	}
+	for _, u := range m.ClientURLs {
+		fmt.Printf("\"ClientURL\" : %q\n", u)
+	}
+	fmt.Println(`"IsLearner" :`, m.IsLearner)
+	fmt.Println(`"IsStandby" :`, m.IsStandby)
+}
+
+func (p *fieldsPrinter) MemberAdd(r v3.MemberAddResponse) {
+	p.hdr(r.Header)
+	p.member(r.Member)
+}
+
+func (p *fieldsPrinter) MemberRemove(id uint64, r v3.MemberRemoveResponse) {
+	p.hdr(r.Header)
+	p.id("ID", id)
+}
+
+func (p *fieldsPrinter) MemberUpdate(id uint64, r v3.MemberUpdateResponse) {
+	p.hdr(r.Header)
+	p.id("ID", id)
+}
+
+func (p *fieldsPrinter) MemberPromote(id uint64, r v3.MemberPromoteResponse) {
+	p.hdr(r.Header)
+	p.id("ID", id)
+}
+
+func (p *fieldsPrinter) MemberReplace(id uint64, r v3.MemberReplaceResponse) {
+	p.hdr(r.Header)
+	p.id("ReplacedID", id)
+	p.member(r.Member)
+}
+
 func (p *fieldsPrinter) MemberList(r v3.MemberListResponse) {
 	p.hdr(r.Header)
 	for _, m := range r.Members {
+		p.member(m)
+		fmt.Println()
+	}
+}
+
+func (p *fieldsPrinter) MemberListHealth(hs []memberHealth) {
+	for _, h := range hs {
 		if p.isHex {
-			fmt.Println(`"ID" :`, types.ID(m.ID))
+			fmt.Println(`"ID" :`, types.ID(h.Member.ID))
 		} else {
-			fmt.Println(`"ID" :`, m.ID)
-		}
-		fmt.Printf("\"Name\" : %q\n", m.Name)
-		for _, u := range m.PeerURLs {
-			fmt.Printf("\"PeerURL\" : %q\n", u)
+			fmt.Println(`"ID" :`, h.Member.ID)
 		}
-		for _, u := range m.ClientURLs {
-			fmt.Printf("\"ClientURL\" : %q\n", u)
+		fmt.Printf("\"Name\" : %q\n", h.Member.Name)
+		fmt.Println(`"IsLearner" :`, h.Member.IsLearner)
+		fmt.Println(`"IsStandby" :`, h.Member.IsStandby)
+		fmt.Println(`"Health" :`, h.Health)
+		if h.Status != nil {
+			fmt.Println(`"DbSize" :`, h.Status.DbSize)
+			fmt.Println(`"RaftIndex" :`, h.Status.RaftIndex)
+			fmt.Println(`"RaftLag" :`, h.RaftLag)
 		}
-		fmt.Println(`"IsLearner" :`, m.IsLearner)
+		fmt.Println(`"Took" :`, h.Took)
+		fmt.Println(`"Error" :`, h.Error)
 		fmt.Println()
 	}
 }
@@ -219,6 +286,121 @@ func (p *fieldsPrinter) EndpointHashKV(hs []epHashKV) {
 	}
 }
 
+func (p *fieldsPrinter) Defrag(d epDefrag) {
+	fmt.Printf("\"Endpoint\" : %q\n", d.Ep)
+	fmt.Println(`"Async" :`, d.Async)
+	fmt.Println(`"Took" :`, d.Took)
+	fmt.Printf("\"Error\" : %q\n", d.Error)
+	fmt.Println()
+}
+
+func (p *fieldsPrinter) DefragStatus(d epDefragStatus) {
+	fmt.Printf("\"Endpoint\" : %q\n", d.Ep)
+	if d.Resp != nil {
+		p.hdr(d.Resp.Header)
+		fmt.Println(`"InProgress" :`, d.Resp.InProgress)
+		fmt.Println(`"PercentComplete" :`, d.Resp.PercentComplete)
+		fmt.Println(`"StartTime" :`, d.Resp.StartTime)
+		fmt.Println(`"FinishTime" :`, d.Resp.FinishTime)
+		fmt.Printf("\"DefragError\" : %q\n", d.Resp.Error)
+	}
+	fmt.Printf("\"Error\" : %q\n", d.Error)
+	fmt.Println()
+}
+
+func (p *fieldsPrinter) MoveLeader(leader, target uint64, r v3.MoveLeaderResponse) {
+	p.hdr(r.Header)
+	p.id("Leader", leader)
+	p.id("Target", target)
+}
+
+func (p *fieldsPrinter) DowngradeValidate(r v3.DowngradeResponse) { p.downgrade(r) }
+func (p *fieldsPrinter) DowngradeEnable(r v3.DowngradeResponse)   { p.downgrade(r) }
+func (p *fieldsPrinter) DowngradeCancel(r v3.DowngradeResponse)   { p.downgrade(r) }
+
+func (p *fieldsPrinter) downgrade(r v3.DowngradeResponse) {
+	p.hdr(r.Header)
+	fmt.Printf("\"Version\" : %q\n", r.Version)
+}
+
+func (p *fieldsPrinter) ReadOnly(r v3.ReadOnlyResponse) {
+	p.hdr(r.Header)
+	fmt.Println(`"Enabled" :`, r.Enabled)
+}
+
+func (p *fieldsPrinter) HashKVCheck(r v3.HashKVCheckResponse) {
+	p.hdr(r.Header)
+	fmt.Println(`"Revision" :`, r.Revision)
+	fmt.Println(`"Consistent" :`, r.Consistent)
+	for _, h := range r.Hashes {
+		if p.isHex {
+			fmt.Println(`"MemberID" :`, types.ID(h.MemberId))
+		} else {
+			fmt.Println(`"MemberID" :`, h.MemberId)
+		}
+		fmt.Println(`"Hash" :`, h.Hash)
+		fmt.Println(`"CompactRevision" :`, h.CompactRevision)
+		fmt.Printf("\"Error\" : %q\n", h.Error)
+		fmt.Println()
+	}
+}
+
+func (p *fieldsPrinter) DowngradeCheck(r v3.DowngradeCheckResponse) {
+	p.hdr(r.Header)
+	fmt.Println(`"Downgradable" :`, r.Downgradable)
+	for _, b := range r.Blockers {
+		fmt.Printf("\"Blocker\" : %q\n", b)
+	}
+	fmt.Println()
+	for _, m := range r.Members {
+		if p.isHex {
+			fmt.Println(`"MemberID" :`, types.ID(m.MemberId))
+		} else {
+			fmt.Println(`"MemberID" :`, m.MemberId)
+		}
+		fmt.Printf("\"StorageVersion\" : %q\n", m.StorageVersion)
+		fmt.Printf("\"WALVersion\" : %q\n", m.WalVersion)
+		for _, f := range m.Features {
+			fmt.Printf("\"Feature\" : %q\n", f)
+		}
+		for _, b := range m.Blockers {
+			fmt.Printf("\"Blocker\" : %q\n", b)
+		}
+		fmt.Println()
+	}
+}
+
+func (p *fieldsPrinter) MirrorStatus(r v3.MirrorStatusResponse) {
+	p.hdr(r.Header)
+	for _, ep := range r.TargetEndpoints {
+		fmt.Printf("\"TargetEndpoint\" : %q\n", ep)
+	}
+	fmt.Printf("\"SourcePrefix\" : %q\n", r.SourcePrefix)
+	fmt.Printf("\"DestPrefix\" : %q\n", r.DestPrefix)
+	fmt.Println(`"MirroredRevision" :`, r.MirroredRevision)
+	fmt.Println(`"Lag" :`, r.Lag)
+	fmt.Println(`"Resyncs" :`, r.Resyncs)
+	fmt.Printf("\"Error\" : %q\n", r.Error)
+}
+
+func (p *fieldsPrinter) FollowerLag(r v3.FollowerLagResponse) {
+	p.hdr(r.Header)
+	fmt.Println(`"CommitIndex" :`, r.CommitIndex)
+	for _, f := range r.Followers {
+		if p.isHex {
+			fmt.Println(`"ID" :`, types.ID(f.ID))
+		} else {
+			fmt.Println(`"ID" :`, f.ID)
+		}
+		fmt.Println(`"IsLearner" :`, f.IsLearner)
+		fmt.Println(`"MatchIndex" :`, f.MatchIndex)
+		fmt.Println(`"Lag" :`, f.Lag)
+		fmt.Printf("\"State\" : %q\n", f.State)
+		fmt.Println(`"Active" :`, f.Active)
+		fmt.Println()
+	}
+}
+
 func (p *fieldsPrinter) Alarm(r v3.AlarmResponse) {
 	p.hdr(r.Header)
 	for _, a := range r.Alarms {
@@ -258,7 +440,29 @@ func (p *fieldsPrinter) RoleGrantPermission(role string, r v3.AuthRoleGrantPermi
 func (p *fieldsPrinter) RoleRevokePermission(role string, key string, end string, r v3.AuthRoleRevokePermissionResponse) {
 	p.hdr(r.Header)
 }
-func (p *fieldsPrinter) UserAdd(user string, r v3.AuthUserAddResponse)          { p.hdr(r.Header) }
+func (p *fieldsPrinter) UserAdd(user string, r v3.AuthUserAddResponse) { p.hdr(r.Header) }
+func (p *fieldsPrinter) UserGet(user string, r v3.AuthUserGetResponse) {
+	p.hdr(r.Header)
+	fmt.Printf("\"User\" : %q\n", user)
+	fmt.Print(`"Roles" :`)
+	for _, role := range r.Roles {
+		fmt.Printf(" %q", role)
+	}
+	fmt.Println()
+	if r.PasswordExpireTime != 0 {
+		fmt.Println(`"PasswordExpireTime" :`, r.PasswordExpireTime)
+	}
+}
+
+func (p *fieldsPrinter) UserList(r v3.AuthUserListResponse) {
+	p.hdr(r.Header)
+	fmt.Print(`"Users" :`)
+	for _, user := range r.Users {
+		fmt.Printf(" %q", user)
+	}
+	fmt.Println()
+}
+
 func (p *fieldsPrinter) UserChangePassword(r v3.AuthUserChangePasswordResponse) { p.hdr(r.Header) }
 func (p *fieldsPrinter) UserGrantRole(user string, role string, r v3.AuthUserGrantRoleResponse) {
 	p.hdr(r.Header)
@@ -268,3 +472,22 @@ func (p *fieldsPrinter) UserRevokeRole(user string, role string, r v3.AuthUserRe
 	p.hdr(r.Header)
 }
 func (p *fieldsPrinter) UserDelete(user string, r v3.AuthUserDeleteResponse) { p.hdr(r.Header) }
+
+func (p *fieldsPrinter) AuthStatus(r v3.AuthStatusResponse) {
+	p.hdr(r.Header)
+	fmt.Println(`"Enabled" :`, r.Enabled)
+	fmt.Println(`"AuthRevision" :`, r.AuthRevision)
+}
+
+func (p *fieldsPrinter) AuthEnable(r v3.AuthEnableResponse)       { p.hdr(r.Header) }
+func (p *fieldsPrinter) AuthDisable(r v3.AuthDisableResponse)     { p.hdr(r.Header) }
+func (p *fieldsPrinter) TokenRevoke(r v3.AuthTokenRevokeResponse) { p.hdr(r.Header) }
+
+// Error prints the error to stderr, for stdout to hold only the results.
+func (p *fieldsPrinter) Error(e cmdError) {
+	fmt.Fprintf(os.Stderr, "\"Error\" : %q\n", e.Message)
+	fmt.Fprintln(os.Stderr, `"ExitCode" :`, e.ExitCode)
+	if e.GRPCCode != "" {
+		fmt.Fprintf(os.Stderr, "\"GRPCCode\" : %q\n", e.GRPCCode)
+	}
+}
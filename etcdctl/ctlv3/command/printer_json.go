@@ -21,6 +21,7 @@ import (
 	"strconv"
 	"strings"
 
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
 
@@ -36,9 +37,36 @@ func newJSONPrinter(isHex bool) printer {
 	}
 }
 
-func (p *jsonPrinter) EndpointHealth(r []epHealth) { printJSON(r) }
-func (p *jsonPrinter) EndpointStatus(r []epStatus) { printJSON(r) }
-func (p *jsonPrinter) EndpointHashKV(r []epHashKV) { printJSON(r) }
+func (p *jsonPrinter) EndpointHealth(r []epHealth)   { printJSON(r) }
+func (p *jsonPrinter) EndpointStatus(r []epStatus)   { printJSON(r) }
+func (p *jsonPrinter) EndpointHashKV(r []epHashKV)   { printJSON(r) }
+func (p *jsonPrinter) CheckPerf(r checkPerfResult)   { printJSON(r) }
+func (p *jsonPrinter) Defrag(r epDefrag)             { printJSON(r) }
+func (p *jsonPrinter) DefragStatus(r epDefragStatus) { printJSON(r) }
+
+// MoveLeader prints the members the leadership was transferred between along
+// with the response, which has only its header.
+func (p *jsonPrinter) MoveLeader(leader, target uint64, r clientv3.MoveLeaderResponse) {
+	printJSON(struct {
+		Header *pb.ResponseHeader `json:"header"`
+		Leader uint64             `json:"leader"`
+		Target uint64             `json:"target"`
+	}{r.Header, leader, target})
+}
+
+// Error prints the error to stderr, for stdout to hold only the results.
+func (p *jsonPrinter) Error(e cmdError) {
+	b, err := json.Marshal(struct {
+		Error cmdError `json:"error"`
+	}{e})
+	if err != nil {
+		printErrorLine(e)
+		return
+	}
+	fmt.Fprintln(os.Stderr, string(b))
+}
+
+func (p *jsonPrinter) MemberListHealth(r []memberHealth) { printJSON(r) }
 
 func (p *jsonPrinter) MemberList(r clientv3.MemberListResponse) {
 	if p.isHex {
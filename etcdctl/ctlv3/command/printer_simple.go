@@ -18,7 +18,9 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"go.etcd.io/etcd/api/v3/authpb"
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/client/pkg/v3/types"
 	v3 "go.etcd.io/etcd/client/v3"
@@ -109,12 +111,19 @@ func (s *simplePrinter) TimeToLive(resp v3.LeaseTimeToLiveResponse, keys bool) {
 		}
 		txt += fmt.Sprintf(", attached keys(%v)", ks)
 	}
+	if len(resp.Metadata) > 0 {
+		txt += fmt.Sprintf(", metadata(%q)", resp.Metadata)
+	}
 	fmt.Println(txt)
 }
 
 func (s *simplePrinter) Leases(resp v3.LeaseLeasesResponse) {
 	fmt.Printf("found %d leases\n", len(resp.Leases))
 	for _, item := range resp.Leases {
+		if len(item.Metadata) > 0 {
+			fmt.Printf("%016x metadata(%q)\n", item.ID, item.Metadata)
+			continue
+		}
 		fmt.Printf("%016x\n", item.ID)
 	}
 }
@@ -125,9 +134,34 @@ func (s *simplePrinter) Alarm(resp v3.AlarmResponse) {
 	}
 }
 
+func (s *simplePrinter) ReadOnly(r v3.ReadOnlyResponse) {
+	fmt.Println("Read-only mode:", r.Enabled)
+}
+
+func (s *simplePrinter) FollowerLag(r v3.FollowerLagResponse) {
+	_, rows := makeFollowerLagTable(r)
+	for _, row := range rows {
+		fmt.Println(strings.Join(row, ", "))
+	}
+}
+
+func (s *simplePrinter) MirrorStatus(r v3.MirrorStatusResponse) {
+	if len(r.TargetEndpoints) == 0 {
+		fmt.Println("Mirroring is not configured")
+		return
+	}
+	_, rows := makeMirrorStatusTable(r)
+	for _, row := range rows {
+		fmt.Println(strings.Join(row, ", "))
+	}
+}
+
 func (s *simplePrinter) MemberAdd(r v3.MemberAddResponse) {
 	asLearner := " "
-	if r.Member.IsLearner {
+	switch {
+	case r.Member.IsStandby:
+		asLearner = " as standby "
+	case r.Member.IsLearner:
 		asLearner = " as learner "
 	}
 	fmt.Printf("Member %16x added%sto cluster %16x\n", r.Member.ID, asLearner, r.Header.ClusterId)
@@ -145,6 +179,10 @@ func (s *simplePrinter) MemberPromote(id uint64, r v3.MemberPromoteResponse) {
 	fmt.Printf("Member %16x promoted in cluster %16x\n", id, r.Header.ClusterId)
 }
 
+func (s *simplePrinter) MemberReplace(id uint64, r v3.MemberReplaceResponse) {
+	fmt.Printf("Member %16x replaced by member %16x in cluster %16x\n", id, r.Member.ID, r.Header.ClusterId)
+}
+
 func (s *simplePrinter) MemberList(resp v3.MemberListResponse) {
 	_, rows := makeMemberListTable(resp)
 	for _, row := range rows {
@@ -152,6 +190,13 @@ func (s *simplePrinter) MemberList(resp v3.MemberListResponse) {
 	}
 }
 
+func (s *simplePrinter) MemberListHealth(hs []memberHealth) {
+	_, rows := makeMemberListHealthTable(hs)
+	for _, row := range rows {
+		fmt.Println(strings.Join(row, ", "))
+	}
+}
+
 func (s *simplePrinter) EndpointHealth(hs []epHealth) {
 	for _, h := range hs {
 		if h.Error == "" {
@@ -169,6 +214,43 @@ func (s *simplePrinter) EndpointStatus(statusList []epStatus) {
 	}
 }
 
+func (s *simplePrinter) HashKVCheck(r v3.HashKVCheckResponse) {
+	_, rows := makeHashKVCheckTable(r)
+	for _, row := range rows {
+		fmt.Println(strings.Join(row, ", "))
+	}
+	fmt.Println(hashKVCheckResult(r))
+}
+
+func (s *simplePrinter) CheckPerf(r checkPerfResult) {
+	for _, c := range r.Checks {
+		switch {
+		case c.Name == "errors" && !c.Pass:
+			fmt.Println("FAIL: too many errors")
+			for k, v := range r.Errors {
+				fmt.Printf("FAIL: ERROR(%v) -> %d\n", k, v)
+			}
+		case c.Name == "throughput" && c.Pass:
+			fmt.Printf("PASS: Throughput is %d writes/s\n", int(c.Value)+1)
+		case c.Name == "throughput":
+			fmt.Printf("FAIL: Throughput too low: %d writes/s\n", int(c.Value)+1)
+		case c.Name == "slowest" && c.Pass:
+			fmt.Printf("PASS: Slowest request took %fs\n", c.Value)
+		case c.Name == "slowest":
+			fmt.Printf("Slowest request took too long: %fs\n", c.Value)
+		case c.Name == "stddev" && c.Pass:
+			fmt.Printf("PASS: Stddev is %fs\n", c.Value)
+		case c.Name == "stddev":
+			fmt.Printf("Stddev too high: %fs\n", c.Value)
+		}
+	}
+	if !r.Pass {
+		fmt.Println("FAIL")
+		return
+	}
+	fmt.Println("PASS")
+}
+
 func (s *simplePrinter) EndpointHashKV(hashList []epHashKV) {
 	_, rows := makeEndpointHashKVTable(hashList)
 	for _, row := range rows {
@@ -176,6 +258,25 @@ func (s *simplePrinter) EndpointHashKV(hashList []epHashKV) {
 	}
 }
 
+func (s *simplePrinter) Defrag(d epDefrag) {
+	switch {
+	case d.Error != "":
+		fmt.Fprintf(os.Stderr, "Failed to defragment etcd member[%s]. took %s. (%v)\n", d.Ep, d.Took, d.Error)
+	case d.Async:
+		fmt.Printf("Started defragmenting etcd member[%s]\n", d.Ep)
+	default:
+		fmt.Printf("Finished defragmenting etcd member[%s]. took %s\n", d.Ep, d.Took)
+	}
+}
+
+func (s *simplePrinter) DefragStatus(d epDefragStatus) {
+	if d.Error != "" {
+		fmt.Fprintf(os.Stderr, "Failed to get the defragmentation status of etcd member[%s]. (%v)\n", d.Ep, d.Error)
+		return
+	}
+	fmt.Printf("etcd member[%s]: %s\n", d.Ep, defragStatusString(d.Resp))
+}
+
 func (s *simplePrinter) MoveLeader(leader, target uint64, r v3.MoveLeaderResponse) {
 	fmt.Printf("Leadership transferred from %s to %s\n", types.ID(leader), types.ID(target))
 }
@@ -192,6 +293,14 @@ func (s *simplePrinter) DowngradeCancel(r v3.DowngradeResponse) {
 	fmt.Printf("Downgrade cancel success, cluster version %s\n", r.Version)
 }
 
+func (s *simplePrinter) DowngradeCheck(r v3.DowngradeCheckResponse) {
+	_, rows := makeDowngradeCheckTable(r)
+	for _, row := range rows {
+		fmt.Println(strings.Join(row, ", "))
+	}
+	fmt.Println(downgradeCheckResult(r))
+}
+
 func (s *simplePrinter) RoleAdd(role string, r v3.AuthRoleAddResponse) {
 	fmt.Printf("Role %s created\n", role)
 }
@@ -241,6 +350,33 @@ func (s *simplePrinter) RoleGet(role string, r v3.AuthRoleGetResponse) {
 			}
 		}
 	}
+
+	// the finer grained permissions are only listed when granted
+	for _, section := range []struct {
+		title    string
+		permType authpb.Permission_Type
+	}{
+		{"KV Watch:", v3.PermWatch},
+		{"KV Delete:", v3.PermDelete},
+		{"KV Lease Attach:", v3.PermLeaseAttach},
+		{"KV Txn Compare:", v3.PermTxnCompare},
+	} {
+		titled := false
+		for _, perm := range r.Perm {
+			if perm.PermType != section.permType {
+				continue
+			}
+			if !titled {
+				fmt.Println(section.title)
+				titled = true
+			}
+			if len(perm.RangeEnd) == 0 {
+				fmt.Printf("\t%s\n", perm.Key)
+			} else {
+				printRange((*v3.Permission)(perm))
+			}
+		}
+	}
 }
 
 func (s *simplePrinter) RoleList(r v3.AuthRoleListResponse) {
@@ -280,6 +416,9 @@ func (s *simplePrinter) UserGet(name string, r v3.AuthUserGetResponse) {
 		fmt.Printf(" %s", role)
 	}
 	fmt.Print("\n")
+	if r.PasswordExpireTime != 0 {
+		fmt.Printf("Password expires: %s\n", time.Unix(r.PasswordExpireTime, 0).UTC().Format(time.RFC3339))
+	}
 }
 
 func (s *simplePrinter) UserChangePassword(v3.AuthUserChangePasswordResponse) {
@@ -308,3 +447,17 @@ func (s *simplePrinter) AuthStatus(r v3.AuthStatusResponse) {
 	fmt.Println("Authentication Status:", r.Enabled)
 	fmt.Println("AuthRevision:", r.AuthRevision)
 }
+
+func (s *simplePrinter) AuthEnable(v3.AuthEnableResponse) {
+	fmt.Println("Authentication Enabled")
+}
+
+func (s *simplePrinter) AuthDisable(v3.AuthDisableResponse) {
+	fmt.Println("Authentication Disabled")
+}
+
+func (s *simplePrinter) TokenRevoke(v3.AuthTokenRevokeResponse) {
+	fmt.Println("Token revoked")
+}
+
+func (s *simplePrinter) Error(e cmdError) { printErrorLine(e) }
@@ -15,6 +15,7 @@
 package command
 
 import (
+	"fmt"
 	"os"
 
 	"github.com/olekukonko/tablewriter"
@@ -35,6 +36,17 @@ func (tp *tablePrinter) MemberList(r v3.MemberListResponse) {
 	table.Render()
 }
 
+func (tp *tablePrinter) MemberListHealth(r []memberHealth) {
+	hdr, rows := makeMemberListHealthTable(r)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(hdr)
+	for _, row := range rows {
+		table.Append(row)
+	}
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+	table.Render()
+}
+
 func (tp *tablePrinter) EndpointHealth(r []epHealth) {
 	hdr, rows := makeEndpointHealthTable(r)
 	table := tablewriter.NewWriter(os.Stdout)
@@ -67,3 +79,49 @@ func (tp *tablePrinter) EndpointHashKV(r []epHashKV) {
 	table.SetAlignment(tablewriter.ALIGN_RIGHT)
 	table.Render()
 }
+
+func (tp *tablePrinter) FollowerLag(r v3.FollowerLagResponse) {
+	hdr, rows := makeFollowerLagTable(r)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(hdr)
+	for _, row := range rows {
+		table.Append(row)
+	}
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+	table.Render()
+}
+
+func (tp *tablePrinter) MirrorStatus(r v3.MirrorStatusResponse) {
+	hdr, rows := makeMirrorStatusTable(r)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(hdr)
+	for _, row := range rows {
+		table.Append(row)
+	}
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+	table.Render()
+}
+
+func (tp *tablePrinter) HashKVCheck(r v3.HashKVCheckResponse) {
+	hdr, rows := makeHashKVCheckTable(r)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(hdr)
+	for _, row := range rows {
+		table.Append(row)
+	}
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+	table.Render()
+	fmt.Println(hashKVCheckResult(r))
+}
+
+func (tp *tablePrinter) DowngradeCheck(r v3.DowngradeCheckResponse) {
+	hdr, rows := makeDowngradeCheckTable(r)
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(hdr)
+	for _, row := range rows {
+		table.Append(row)
+	}
+	table.SetAlignment(tablewriter.ALIGN_RIGHT)
+	table.Render()
+	fmt.Println(downgradeCheckResult(r))
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/pkg/v3/cobrautl"
+)
+
+func TestNewCmdError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want cmdError
+	}{
+		{
+			name: "etcd error",
+			err:  rpctypes.ErrPermissionDenied,
+			want: cmdError{ExitCode: cobrautl.ExitError, Message: "etcdserver: permission denied", GRPCCode: "PermissionDenied"},
+		},
+		{
+			name: "gRPC error",
+			err:  status.Error(codes.Unavailable, "unavailable"),
+			want: cmdError{ExitCode: cobrautl.ExitError, Message: "rpc error: code = Unavailable desc = unavailable", GRPCCode: "Unavailable"},
+		},
+		{
+			name: "context error",
+			err:  fmt.Errorf("get: %w", context.DeadlineExceeded),
+			want: cmdError{ExitCode: cobrautl.ExitError, Message: "get: context deadline exceeded", GRPCCode: "DeadlineExceeded"},
+		},
+		{
+			name: "other error",
+			err:  errors.New("bad"),
+			want: cmdError{ExitCode: cobrautl.ExitError, Message: "bad"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, newCmdError(cobrautl.ExitError, tt.err))
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	v3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/pkg/v3/cobrautl"
+)
+
+// NewReadOnlyCommand returns the cobra command for "read-only".
+func NewReadOnlyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "read-only <subcommand>",
+		Short: "Read-only mode related commands",
+	}
+
+	cmd.AddCommand(newReadOnlyCommand("enable", "Makes the cluster reject the requests writing to it", v3.ReadOnlyEnable))
+	cmd.AddCommand(newReadOnlyCommand("disable", "Makes the cluster accept the requests writing to it again", v3.ReadOnlyDisable))
+	cmd.AddCommand(newReadOnlyCommand("status", "Returns whether the cluster is in read-only mode", v3.ReadOnlyGet))
+
+	return cmd
+}
+
+func newReadOnlyCommand(use, short string, action v3.ReadOnlyAction) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Run: func(cmd *cobra.Command, args []string) {
+			readOnlyCommandFunc(cmd, args, action)
+		},
+	}
+}
+
+// readOnlyCommandFunc executes the "read-only" subcommands.
+func readOnlyCommandFunc(cmd *cobra.Command, args []string, action v3.ReadOnlyAction) {
+	if len(args) != 0 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("read-only %s command accepts no arguments", cmd.Name()))
+	}
+	ctx, cancel := commandCtx(cmd)
+	resp, err := mustClientFromCmd(cmd).ReadOnly(ctx, action)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	display.ReadOnly(*resp)
+}
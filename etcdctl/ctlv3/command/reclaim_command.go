@@ -0,0 +1,86 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/pkg/v3/cobrautl"
+)
+
+// NewReclaimCommand returns the cobra command for "reclaim".
+func NewReclaimCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reclaim [options] [<revision>]",
+		Short: "Compacts the event history to a revision, then defragments the etcd members with given endpoints",
+		Run:   reclaimCommandFunc,
+	}
+	cmd.PersistentFlags().BoolVar(&epClusterEndpoints, "cluster", false, "use all endpoints from the cluster member list")
+	return cmd
+}
+
+func reclaimCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) > 1 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("reclaim command needs at most 1 argument"))
+	}
+	var rev int64
+	if len(args) == 1 {
+		var err error
+		if rev, err = strconv.ParseInt(args[0], 10, 64); err != nil {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+		}
+	}
+
+	failures := 0
+	cfg := clientConfigFromCmd(cmd)
+	for _, ep := range endpointsFromCluster(cmd) {
+		cfg.Endpoints = []string{ep}
+		c := mustClient(cfg)
+		start := time.Now()
+		// the defragmentation may outlast the command timeout
+		err := c.Reclaim(context.Background(), ep, rev, func(resp *clientv3.ReclaimResponse) {
+			fmt.Printf("etcd member[%s]: %s\n", ep, reclaimString(resp))
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to reclaim the space of etcd member[%s]. took %s. (%v)\n", ep, time.Since(start), err)
+			failures++
+		}
+		c.Close()
+	}
+
+	if failures != 0 {
+		os.Exit(cobrautl.ExitError)
+	}
+}
+
+func reclaimString(resp *clientv3.ReclaimResponse) string {
+	switch resp.Phase {
+	case pb.ReclaimResponse_COMPACTING:
+		return fmt.Sprintf("compacting, database size %s", humanize.Bytes(uint64(resp.DbSizeBefore)))
+	case pb.ReclaimResponse_DEFRAGMENTING:
+		return fmt.Sprintf("defragmenting, %d%% complete", resp.PercentComplete)
+	}
+	reclaimed := humanize.Bytes(uint64(max(resp.ReclaimedBytes, 0)))
+	return fmt.Sprintf("reclaimed %s, database size %s -> %s", reclaimed, humanize.Bytes(uint64(resp.DbSizeBefore)), humanize.Bytes(uint64(resp.DbSizeAfter)))
+}
@@ -0,0 +1,595 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/bgentry/speakeasy"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/client/pkg/v3/logutil"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/pkg/v3/cobrautl"
+	"go.etcd.io/etcd/pkg/v3/flags"
+)
+
+const (
+	// shellHistoryLimit bounds the number of commands kept in the history.
+	shellHistoryLimit = 1000
+	// shellCompletionLimit bounds the number of keys fetched to complete a
+	// key.
+	shellCompletionLimit = 100
+)
+
+var shellBuiltins = []string{"context", "exit", "help", "history", "pager", "quit", "set", "unset"}
+
+var (
+	shellHistoryFile string
+	shellPager       string
+)
+
+// NewShellCommand returns the cobra command for "shell".
+func NewShellCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "shell [options]",
+		Short: "Starts an interactive shell running etcdctl commands",
+		Long: `Starts an interactive shell running etcdctl commands, with command history, completion of
+commands, flags and keys, and paging of the output.
+
+The global flags given to the shell, such as --endpoints or --user, apply to all the commands of the
+session, and can be changed with the set and unset commands. The password is asked for once, when the
+shell starts or the user is set. Type help for the commands of the shell.
+`,
+		Run: shellCommandFunc,
+	}
+
+	historyFile := ""
+	if home, err := os.UserHomeDir(); err == nil {
+		historyFile = filepath.Join(home, ".etcdctl_history")
+	}
+	cmd.Flags().StringVar(&shellHistoryFile, "history-file", historyFile, "File to save the command history to, no history saved if empty")
+	cmd.Flags().StringVar(&shellPager, "pager", "", "Command to pipe the output of the commands to, such as 'less -FRX', no paging if empty")
+
+	return cmd
+}
+
+func shellCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 0 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, errors.New("shell command does not accept any arguments"))
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+
+	sh := &shell{cmd: cmd, exe: exe, pager: shellPager, historyFile: shellHistoryFile}
+	// ask for the password once for the session, rather than for each command
+	sh.setAuth(clientConfigFromCmd(cmd).Auth)
+	// the global flags set in the environment are passed to the commands as
+	// command line flags from now on, which they must not conflict with
+	cmd.InheritedFlags().VisitAll(func(f *pflag.Flag) {
+		os.Unsetenv(flags.FlagToEnv("ETCDCTL", f.Name))
+	})
+
+	if restore, err := makeRaw(int(os.Stdin.Fd())); err == nil {
+		sh.terminal = true
+		restore()
+	}
+	sh.editor = newLineEditor(os.Stdin, os.Stdout, sh.complete)
+	if sh.terminal {
+		sh.loadHistory()
+	}
+	// Ctrl-C interrupts the command running, not the shell
+	signal.Notify(make(chan os.Signal, 1), os.Interrupt)
+
+	sh.run()
+	if sh.client != nil {
+		sh.client.Close()
+	}
+}
+
+// shell runs the commands of an interactive session. Each command runs in its
+// own etcdctl process, with the global flags of the session.
+type shell struct {
+	// cmd holds the global flags of the session
+	cmd         *cobra.Command
+	exe         string
+	pager       string
+	historyFile string
+	terminal    bool
+	editor      *lineEditor
+	// client completes keys, created on the first completion
+	client *clientv3.Client
+}
+
+func (sh *shell) run() {
+	for {
+		line, err := sh.readLine()
+		if errors.Is(err, errLineInterrupted) {
+			continue
+		}
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			return
+		}
+
+		// like shells do, a line starting with a space is not recorded
+		record := !strings.HasPrefix(line, " ")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "!") {
+			if line, err = sh.expandHistory(line); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				continue
+			}
+			fmt.Println(line)
+		}
+		args := Argify(line)
+		if record && !hasCredentials(args) {
+			sh.addHistory(line)
+		}
+
+		if exit, ok := sh.builtin(args); ok {
+			if exit {
+				return
+			}
+			continue
+		}
+		sh.exec(args)
+	}
+}
+
+func (sh *shell) readLine() (string, error) {
+	if !sh.terminal {
+		line, err := sh.editor.in.ReadString('\n')
+		if errors.Is(err, io.EOF) && line != "" {
+			err = nil
+		}
+		return line, err
+	}
+	restore, err := makeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return "", err
+	}
+	defer restore()
+	return sh.editor.readLine(sh.prompt())
+}
+
+// prompt shows the user and the endpoints of the session.
+func (sh *shell) prompt() string {
+	endpoints, _ := sh.cmd.Flags().GetStringSlice("endpoints")
+	user, _ := sh.cmd.Flags().GetString("user")
+	prompt := strings.Join(endpoints, ",")
+	if user != "" {
+		prompt = user + "@" + prompt
+	}
+	return prompt + "> "
+}
+
+// builtin runs the line if it's a command of the shell, and returns whether
+// it was and whether the shell exits.
+func (sh *shell) builtin(args []string) (exit bool, ok bool) {
+	switch args[0] {
+	case "exit", "quit":
+		return true, true
+	case "help":
+		fmt.Print(`Shell commands:
+  context                 print the global flags of the session
+  exit, quit              exit the shell, as Ctrl-D does
+  help                    print this help
+  history                 print the command history; !! runs the last command again, !<n> the command n
+  pager [<command>|off]   print, set or disable the command the output is piped to
+  set <flag> <value>      set a global flag for the following commands, such as endpoints or user
+  unset <flag>            reset a global flag to its default
+
+Any other line runs an etcdctl command, such as "get --prefix /app". Tab completes commands, flags and keys.
+`)
+	case "history":
+		for i, line := range sh.editor.history {
+			fmt.Printf("%5d  %s\n", i+1, line)
+		}
+	case "context":
+		sh.cmd.InheritedFlags().VisitAll(func(f *pflag.Flag) {
+			if f.Changed {
+				fmt.Printf("%s = %s\n", f.Name, sh.displayFlag(f))
+			}
+		})
+		fmt.Printf("pager = %s\n", sh.pager)
+	case "pager":
+		switch {
+		case len(args) == 1:
+			fmt.Println(sh.pager)
+		case len(args) == 2 && args[1] == "off":
+			sh.pager = ""
+		default:
+			sh.pager = strings.Join(args[1:], " ")
+		}
+	case "set":
+		if len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "usage: set <flag> <value>")
+			break
+		}
+		if err := sh.setFlag(args[1], args[2]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	case "unset":
+		if len(args) != 2 {
+			fmt.Fprintln(os.Stderr, "usage: unset <flag>")
+			break
+		}
+		if err := sh.unsetFlag(args[1]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+	case "shell":
+		fmt.Fprintln(os.Stderr, "already in a shell")
+	default:
+		return false, false
+	}
+	return false, true
+}
+
+// exec runs the etcdctl command in its own process, piping its output to the
+// pager if there is one.
+func (sh *shell) exec(args []string) {
+	c := exec.Command(sh.exe, append(sh.globalArgs(), args...)...)
+	c.Env = append(os.Environ(), sh.env()...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if sh.pager == "" {
+		// the command prints its own errors
+		_ = c.Run()
+		return
+	}
+
+	pager := exec.Command("sh", "-c", sh.pager)
+	pager.Stdout, pager.Stderr = os.Stdout, os.Stderr
+	r, w, err := os.Pipe()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	c.Stdout, pager.Stdin = w, r
+	err = c.Start()
+	if err == nil {
+		err = pager.Start()
+	}
+	w.Close()
+	r.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pager %q error (%v)\n", sh.pager, err)
+	}
+	_ = c.Wait()
+	if pager.Process != nil {
+		_ = pager.Wait()
+	}
+}
+
+// globalArgs returns the global flags set for the session. The password is
+// passed in the environment, out of the process list.
+func (sh *shell) globalArgs() (args []string) {
+	sh.cmd.InheritedFlags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed && f.Name != "password" {
+			args = append(args, fmt.Sprintf("--%s=%s", f.Name, flagValue(f)))
+		}
+	})
+	return args
+}
+
+func (sh *shell) env() []string {
+	password, _ := sh.cmd.Flags().GetString("password")
+	if password == "" {
+		return nil
+	}
+	return []string{"ETCDCTL_PASSWORD=" + password}
+}
+
+func (sh *shell) displayFlag(f *pflag.Flag) string {
+	if f.Name == "password" && f.Value.String() != "" {
+		return "********"
+	}
+	return flagValue(f)
+}
+
+func flagValue(f *pflag.Flag) string {
+	if sv, ok := f.Value.(pflag.SliceValue); ok {
+		return strings.Join(sv.GetSlice(), ",")
+	}
+	return f.Value.String()
+}
+
+// setFlag sets the global flag for the session. Setting the user asks for
+// its password, unless given as user:password.
+func (sh *shell) setFlag(name, value string) error {
+	f := sh.cmd.InheritedFlags().Lookup(strings.TrimLeft(name, "-"))
+	if f == nil {
+		return fmt.Errorf("unknown global flag %q", name)
+	}
+	if f.Name == "user" {
+		auth := &clientv3.AuthConfig{Username: value}
+		if user, password, ok := strings.Cut(value, ":"); ok {
+			auth.Username, auth.Password = user, password
+		} else if value != "" {
+			var err error
+			if auth.Password, err = speakeasy.Ask("Password: "); err != nil {
+				return err
+			}
+		}
+		sh.setAuth(auth)
+	} else if err := setFlagValue(f, value); err != nil {
+		return err
+	}
+	sh.closeClient()
+	return nil
+}
+
+// unsetFlag resets the global flag to its default for the session, even if
+// set in the environment.
+func (sh *shell) unsetFlag(name string) error {
+	f := sh.cmd.InheritedFlags().Lookup(strings.TrimLeft(name, "-"))
+	if f == nil {
+		return fmt.Errorf("unknown global flag %q", name)
+	}
+	if f.Name == "user" {
+		sh.setAuth(&clientv3.AuthConfig{})
+	} else if _, ok := f.Value.(pflag.SliceValue); ok {
+		if err := setFlagValue(f, strings.Trim(f.DefValue, "[]")); err != nil {
+			return err
+		}
+	} else if err := setFlagValue(f, f.DefValue); err != nil {
+		return err
+	}
+	sh.closeClient()
+	return nil
+}
+
+// setAuth sets the user and the password flags of the authentication, so
+// that the commands don't ask for the password again.
+func (sh *shell) setAuth(auth *clientv3.AuthConfig) {
+	if auth == nil || auth.Token != "" {
+		return
+	}
+	fs := sh.cmd.InheritedFlags()
+	_ = setFlagValue(fs.Lookup("user"), auth.Username)
+	_ = setFlagValue(fs.Lookup("password"), auth.Password)
+}
+
+func setFlagValue(f *pflag.Flag, value string) error {
+	var err error
+	if sv, ok := f.Value.(pflag.SliceValue); ok {
+		var vals []string
+		if value != "" {
+			vals = strings.Split(value, ",")
+		}
+		err = sv.Replace(vals)
+	} else {
+		err = f.Value.Set(value)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value %q for flag %q: %w", value, f.Name, err)
+	}
+	f.Changed = true
+	return nil
+}
+
+func (sh *shell) closeClient() {
+	if sh.client != nil {
+		sh.client.Close()
+		sh.client = nil
+	}
+}
+
+func (sh *shell) addHistory(line string) {
+	if !sh.editor.addHistory(line) {
+		return
+	}
+	if n := len(sh.editor.history); n > shellHistoryLimit {
+		sh.editor.history = sh.editor.history[n-shellHistoryLimit:]
+	}
+	if !sh.terminal || sh.historyFile == "" {
+		return
+	}
+	f, err := os.OpenFile(sh.historyFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	fmt.Fprintln(f, line)
+}
+
+// hasCredentials returns true if the command line carries a password, so
+// that the history doesn't record it.
+func hasCredentials(args []string) bool {
+	if len(args) >= 3 && args[0] == "set" {
+		switch strings.TrimLeft(args[1], "-") {
+		case "password":
+			return true
+		case "user":
+			return strings.Contains(args[2], ":")
+		}
+	}
+	isUserAdd := len(args) >= 2 && args[0] == "user" && args[1] == "add"
+	for i, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			if isUserAdd && i >= 2 && strings.Contains(arg, ":") {
+				return true
+			}
+			continue
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		switch name {
+		case "password", "new-user-password":
+			return true
+		case "user":
+			if !hasValue && i+1 < len(args) {
+				value = args[i+1]
+			}
+			if strings.Contains(value, ":") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// loadHistory loads the history saved by the previous sessions, truncating
+// the file when it holds more than shellHistoryLimit commands.
+func (sh *shell) loadHistory() {
+	if sh.historyFile == "" {
+		return
+	}
+	f, err := os.Open(sh.historyFile)
+	if err != nil {
+		return
+	}
+	var lines []string
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		lines = append(lines, s.Text())
+	}
+	f.Close()
+	if len(lines) > shellHistoryLimit {
+		lines = lines[len(lines)-shellHistoryLimit:]
+		_ = os.WriteFile(sh.historyFile, []byte(strings.Join(lines, "\n")+"\n"), 0o600)
+	}
+	sh.editor.history = lines
+}
+
+// expandHistory expands !! to the last command and !<n> to the command n of
+// the history.
+func (sh *shell) expandHistory(line string) (string, error) {
+	history := sh.editor.history
+	if line == "!!" {
+		if len(history) == 0 {
+			return "", errors.New("!!: event not found")
+		}
+		return history[len(history)-1], nil
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 1 || n > len(history) {
+		return "", fmt.Errorf("%s: event not found", line)
+	}
+	return history[n-1], nil
+}
+
+// complete completes the command names at the start of the line, the flags
+// and the subcommands of the command, and otherwise the keys.
+func (sh *shell) complete(line []rune, pos int) (int, []string) {
+	start := pos
+	for start > 0 && !unicode.IsSpace(line[start-1]) {
+		start--
+	}
+	word := string(line[start:pos])
+	words := strings.Fields(string(line[:start]))
+	root := sh.cmd.Root()
+
+	var names []string
+	switch {
+	case len(words) == 0:
+		names = append(names, shellBuiltins...)
+		for _, c := range root.Commands() {
+			if c.IsAvailableCommand() && c != sh.cmd {
+				names = append(names, c.Name())
+			}
+		}
+	case words[0] == "set" || words[0] == "unset":
+		if len(words) == 1 {
+			sh.cmd.InheritedFlags().VisitAll(func(f *pflag.Flag) { names = append(names, f.Name) })
+		}
+	case strings.HasPrefix(word, "-"):
+		c, _, err := root.Find(words)
+		if err != nil {
+			return start, nil
+		}
+		c.Flags().VisitAll(func(f *pflag.Flag) { names = append(names, "--"+f.Name) })
+		c.InheritedFlags().VisitAll(func(f *pflag.Flag) { names = append(names, "--"+f.Name) })
+	default:
+		c, rest, err := root.Find(words)
+		if err == nil && len(rest) == 0 && c.HasAvailableSubCommands() {
+			for _, sub := range c.Commands() {
+				if sub.IsAvailableCommand() {
+					names = append(names, sub.Name())
+				}
+			}
+			break
+		}
+		return start, sh.completeKey(word)
+	}
+
+	var candidates []string
+	for _, name := range names {
+		if strings.HasPrefix(name, word) {
+			candidates = append(candidates, name)
+		}
+	}
+	sort.Strings(candidates)
+	return start, candidates
+}
+
+// completeKey returns the keys starting with the prefix, up to the next '/'.
+// Failing to fetch them completes nothing.
+func (sh *shell) completeKey(prefix string) []string {
+	if sh.client == nil {
+		lg, _ := logutil.CreateDefaultZapLogger(zap.ErrorLevel)
+		cfg, err := clientv3.NewClientConfig(clientConfigFromCmd(sh.cmd), lg)
+		if err != nil {
+			return nil
+		}
+		if sh.client, err = clientv3.New(*cfg); err != nil {
+			return nil
+		}
+	}
+	ctx, cancel := commandCtx(sh.cmd)
+	defer cancel()
+	resp, err := sh.client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithKeysOnly(), clientv3.WithLimit(shellCompletionLimit))
+	if err != nil {
+		return nil
+	}
+	keys := make([]string, len(resp.Kvs))
+	for i, kv := range resp.Kvs {
+		keys[i] = string(kv.Key)
+	}
+	return keyCandidates(prefix, keys)
+}
+
+// keyCandidates returns the sorted keys truncated after the first '/'
+// following the prefix, so that completion proceeds a path segment at a time.
+func keyCandidates(prefix string, keys []string) (candidates []string) {
+	for _, key := range keys {
+		if i := strings.IndexByte(key[len(prefix):], '/'); i >= 0 {
+			key = key[:len(prefix)+i+1]
+		}
+		if n := len(candidates); n == 0 || candidates[n-1] != key {
+			candidates = append(candidates, key)
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyCandidates(t *testing.T) {
+	keys := []string{"/app/a/x", "/app/a/y", "/app/b", "/app/c/d/e", "/apple"}
+	require.Equal(t, []string{"/app/", "/apple"}, keyCandidates("/ap", keys))
+	require.Equal(t, []string{"/app/a/", "/app/b", "/app/c/"}, keyCandidates("/app/", keys[:4]))
+	require.Equal(t, []string{"/"}, keyCandidates("", keys))
+	require.Empty(t, keyCandidates("/x", nil))
+}
+
+func TestShellExpandHistory(t *testing.T) {
+	sh := &shell{editor: newLineEditor(nil, nil, nil)}
+	_, err := sh.expandHistory("!!")
+	require.ErrorContains(t, err, "event not found")
+
+	sh.editor.history = []string{"get a", "get b"}
+	line, err := sh.expandHistory("!!")
+	require.NoError(t, err)
+	require.Equal(t, "get b", line)
+	line, err = sh.expandHistory("!1")
+	require.NoError(t, err)
+	require.Equal(t, "get a", line)
+	_, err = sh.expandHistory("!3")
+	require.ErrorContains(t, err, "event not found")
+	_, err = sh.expandHistory("!x")
+	require.ErrorContains(t, err, "event not found")
+}
+
+func TestHasCredentials(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{line: "get foo"},
+		{line: "set user root"},
+		{line: "set endpoints a:2379"},
+		{line: "set user root:pw", want: true},
+		{line: "set --password pw", want: true},
+		{line: "user add alice:pw", want: true},
+		{line: "user add alice --new-user-password=pw", want: true},
+		{line: "user add alice"},
+		{line: "get foo --user root:pw", want: true},
+		{line: "get foo --user=root:pw", want: true},
+		{line: "get foo --user=root --password=pw", want: true},
+		{line: "put a:b c"},
+	}
+	for _, tt := range tests {
+		require.Equalf(t, tt.want, hasCredentials(Argify(tt.line)), "line %q", tt.line)
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// errLineInterrupted is returned by lineEditor.readLine when the line is
+// discarded with Ctrl-C.
+var errLineInterrupted = errors.New("line interrupted")
+
+// completeFunc returns the candidates completing the word ending at pos in
+// line, and the start of that word.
+type completeFunc func(line []rune, pos int) (start int, candidates []string)
+
+// lineEditor reads lines from a terminal in raw mode, with the usual emacs
+// key bindings, history navigation and tab completion.
+type lineEditor struct {
+	in       *bufio.Reader
+	out      io.Writer
+	history  []string
+	complete completeFunc
+
+	prompt string
+	buf    []rune
+	pos    int
+}
+
+func newLineEditor(in io.Reader, out io.Writer, complete completeFunc) *lineEditor {
+	return &lineEditor{in: bufio.NewReader(in), out: out, complete: complete}
+}
+
+// readLine reads a line after printing the prompt. It returns io.EOF on
+// Ctrl-D on an empty line, and errLineInterrupted on Ctrl-C.
+func (e *lineEditor) readLine(prompt string) (string, error) {
+	e.prompt, e.buf, e.pos = prompt, e.buf[:0], 0
+	// index of the history entry shown, len(e.history) for the edited line
+	hidx, edited := len(e.history), ""
+	e.refresh()
+
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		switch r {
+		case '\r', '\n':
+			fmt.Fprint(e.out, "\r\n")
+			return string(e.buf), nil
+		case 0x03: // Ctrl-C
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", errLineInterrupted
+		case 0x04: // Ctrl-D
+			if len(e.buf) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			e.deleteAt(e.pos)
+		case 0x7f, 0x08: // Backspace
+			if e.pos > 0 {
+				e.pos--
+				e.deleteAt(e.pos)
+			}
+		case 0x01: // Ctrl-A
+			e.pos = 0
+		case 0x05: // Ctrl-E
+			e.pos = len(e.buf)
+		case 0x02: // Ctrl-B
+			e.move(-1)
+		case 0x06: // Ctrl-F
+			e.move(1)
+		case 0x0b: // Ctrl-K
+			e.buf = e.buf[:e.pos]
+		case 0x15: // Ctrl-U
+			e.buf = append(e.buf[:0], e.buf[e.pos:]...)
+			e.pos = 0
+		case 0x17: // Ctrl-W
+			start := e.pos
+			for start > 0 && unicode.IsSpace(e.buf[start-1]) {
+				start--
+			}
+			for start > 0 && !unicode.IsSpace(e.buf[start-1]) {
+				start--
+			}
+			e.buf = append(e.buf[:start], e.buf[e.pos:]...)
+			e.pos = start
+		case 0x10: // Ctrl-P
+			hidx, edited = e.recall(hidx, hidx-1, edited)
+		case 0x0e: // Ctrl-N
+			hidx, edited = e.recall(hidx, hidx+1, edited)
+		case '\t':
+			e.completeWord()
+		case 0x1b: // escape sequence
+			switch e.readEscape() {
+			case 'A':
+				hidx, edited = e.recall(hidx, hidx-1, edited)
+			case 'B':
+				hidx, edited = e.recall(hidx, hidx+1, edited)
+			case 'C':
+				e.move(1)
+			case 'D':
+				e.move(-1)
+			case 'H':
+				e.pos = 0
+			case 'F':
+				e.pos = len(e.buf)
+			case '3': // Delete
+				e.deleteAt(e.pos)
+			}
+		default:
+			if !unicode.IsPrint(r) {
+				continue
+			}
+			e.buf = append(e.buf, 0)
+			copy(e.buf[e.pos+1:], e.buf[e.pos:])
+			e.buf[e.pos] = r
+			e.pos++
+		}
+		e.refresh()
+	}
+}
+
+// addHistory appends the line to the history, unless it repeats the last
+// entry, and returns whether it did.
+func (e *lineEditor) addHistory(line string) bool {
+	if n := len(e.history); n > 0 && e.history[n-1] == line {
+		return false
+	}
+	e.history = append(e.history, line)
+	return true
+}
+
+// readEscape reads the rest of an escape sequence, and returns its final
+// byte: 'A' to 'D' for the arrows, 'H' and 'F' for Home and End, '3' for
+// Delete, or 0 for the sequences not handled.
+func (e *lineEditor) readEscape() byte {
+	b, err := e.in.ReadByte()
+	if err != nil || (b != '[' && b != 'O') {
+		return 0
+	}
+	b, err = e.in.ReadByte()
+	if err != nil {
+		return 0
+	}
+	if b >= '0' && b <= '9' {
+		// "ESC [ n ~" sequences
+		final := b
+		for b != '~' {
+			if b, err = e.in.ReadByte(); err != nil {
+				return 0
+			}
+		}
+		switch final {
+		case '1', '7':
+			return 'H'
+		case '4', '8':
+			return 'F'
+		}
+		return final
+	}
+	return b
+}
+
+func (e *lineEditor) move(n int) {
+	e.pos = min(max(e.pos+n, 0), len(e.buf))
+}
+
+func (e *lineEditor) deleteAt(i int) {
+	if i < len(e.buf) {
+		e.buf = append(e.buf[:i], e.buf[i+1:]...)
+	}
+}
+
+// recall replaces the line with the history entry at index to, keeping the
+// line being edited to come back to it past the last entry.
+func (e *lineEditor) recall(from, to int, edited string) (int, string) {
+	if to < 0 || to > len(e.history) {
+		return from, edited
+	}
+	if from == len(e.history) {
+		edited = string(e.buf)
+	}
+	line := edited
+	if to < len(e.history) {
+		line = e.history[to]
+	}
+	e.buf = append(e.buf[:0], []rune(line)...)
+	e.pos = len(e.buf)
+	return to, edited
+}
+
+// completeWord completes the word before the cursor with the longest prefix
+// common to the candidates, and lists the candidates when it can't extend
+// the word.
+func (e *lineEditor) completeWord() {
+	if e.complete == nil {
+		return
+	}
+	start, candidates := e.complete(e.buf, e.pos)
+	if len(candidates) == 0 {
+		return
+	}
+	word := string(e.buf[start:e.pos])
+	common := commonPrefix(candidates)
+	if len(candidates) == 1 && !strings.HasSuffix(common, "/") {
+		common += " "
+	}
+	if len(common) > len(word) && strings.HasPrefix(common, word) {
+		rest := []rune(common[len(word):])
+		e.buf = append(e.buf[:e.pos], append(rest, e.buf[e.pos:]...)...)
+		e.pos += len(rest)
+		return
+	}
+	fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+}
+
+// refresh redraws the line and moves the cursor to its position.
+func (e *lineEditor) refresh() {
+	fmt.Fprintf(e.out, "\r%s%s\x1b[K", e.prompt, string(e.buf))
+	if n := len(e.buf) - e.pos; n > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", n)
+	}
+}
+
+func commonPrefix(ss []string) string {
+	if len(ss) == 0 {
+		return ""
+	}
+	prefix := ss[0]
+	for _, s := range ss[1:] {
+		for !strings.HasPrefix(s, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineEditor(t *testing.T) {
+	tests := []struct {
+		name    string
+		history []string
+		input   string
+		want    string
+	}{
+		{name: "plain", input: "get foo\r", want: "get foo"},
+		{name: "backspace", input: "get fooo\x7f\r", want: "get foo"},
+		{name: "insert after moving left", input: "get fo\x1b[Do\r", want: "get foo"},
+		{name: "home and end", input: "et foo\x01g\x05!\r", want: "get foo!"},
+		{name: "home and end sequences", input: "et foo\x1b[Hg\x1b[4~!\r", want: "get foo!"},
+		{name: "delete", input: "gget\x01\x1b[3~\r", want: "get"},
+		{name: "kill to end", input: "get foo\x01\x06\x06\x06\x0b\r", want: "get"},
+		{name: "kill to start", input: "xxx get\x1b[D\x1b[D\x1b[D\x15\r", want: "get"},
+		{name: "delete word", input: "get foo bar\x17\r", want: "get foo "},
+		{name: "previous history", history: []string{"get a", "get b"}, input: "\x1b[A\x1b[A\r", want: "get a"},
+		{name: "next history", history: []string{"get a", "get b"}, input: "put\x10\x10\x0e\x0e\r", want: "put"},
+		{name: "history bounds", history: []string{"get a"}, input: "\x1b[A\x1b[A\x1b[B\x1b[B\x1b[A\r", want: "get a"},
+		{name: "non printable ignored", input: "get\x07 foo\r", want: "get foo"},
+		{name: "unicode", input: "put clé\x7f\x7fé\r", want: "put cé"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			e := newLineEditor(strings.NewReader(tt.input), &out, nil)
+			e.history = tt.history
+			line, err := e.readLine("> ")
+			require.NoError(t, err)
+			require.Equal(t, tt.want, line)
+		})
+	}
+}
+
+func TestLineEditorEOFAndInterrupt(t *testing.T) {
+	var out strings.Builder
+	e := newLineEditor(strings.NewReader("get\x03\x04"), &out, nil)
+	_, err := e.readLine("> ")
+	require.ErrorIs(t, err, errLineInterrupted)
+	_, err = e.readLine("> ")
+	require.ErrorIs(t, err, io.EOF)
+
+	// Ctrl-D deletes the character under the cursor of a line
+	e = newLineEditor(strings.NewReader("gett\x02\x04\r"), &out, nil)
+	line, err := e.readLine("> ")
+	require.NoError(t, err)
+	require.Equal(t, "get", line)
+}
+
+func TestLineEditorCompletion(t *testing.T) {
+	complete := func(line []rune, pos int) (int, []string) {
+		start := strings.LastIndex(string(line[:pos]), " ") + 1
+		var candidates []string
+		for _, c := range []string{"/app/a/", "/app/b", "/other"} {
+			if strings.HasPrefix(c, string(line[start:pos])) {
+				candidates = append(candidates, c)
+			}
+		}
+		return start, candidates
+	}
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		// the single candidate completes the word, followed by a space
+		{input: "get /o\t\r", want: "get /other "},
+		// a directory-like candidate is not followed by a space
+		{input: "get /app/a\t\r", want: "get /app/a/"},
+		// the candidates complete their common prefix
+		{input: "get /a\t\r", want: "get /app/"},
+		// no candidate leaves the word unchanged
+		{input: "get /x\t\r", want: "get /x"},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		e := newLineEditor(strings.NewReader(tt.input), &out, complete)
+		line, err := e.readLine("> ")
+		require.NoError(t, err)
+		require.Equal(t, tt.want, line, tt.input)
+	}
+
+	// the candidates are listed when they can't extend the word
+	var out strings.Builder
+	e := newLineEditor(strings.NewReader("get /app/\t\r"), &out, complete)
+	_, err := e.readLine("> ")
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "/app/a/  /app/b")
+}
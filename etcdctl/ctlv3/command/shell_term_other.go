@@ -0,0 +1,25 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+
+package command
+
+import "errors"
+
+// makeRaw fails on the platforms where the shell doesn't support line
+// editing: it reads plain lines instead.
+func makeRaw(int) (restore func() error, err error) {
+	return nil, errors.New("line editing is not supported on this platform")
+}
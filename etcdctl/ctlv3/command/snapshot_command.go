@@ -16,12 +16,15 @@ package command
 
 import (
 	"context"
+	"crypto/ed25519"
 	"fmt"
 
+	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
 	"go.etcd.io/etcd/client/pkg/v3/logutil"
+	clientv3 "go.etcd.io/etcd/client/v3"
 	snapshot "go.etcd.io/etcd/client/v3/snapshot"
 	"go.etcd.io/etcd/etcdctl/v3/util"
 	"go.etcd.io/etcd/pkg/v3/cobrautl"
@@ -43,8 +46,24 @@ var snapshotExample = util.Normalize(`
 	# Get snapshot from given address with timeout
 	etcdctl --endpoints=https://127.0.0.1:2379 --dial-timeout=20s snapshot save /backup/etcd-snapshot.db
 
+	# Save snapshot with a manifest signed with an ed25519 key, verified by etcdutl snapshot restore
+	etcdctl snapshot save --manifest-sign-key /etc/etcd/backup-signing.pem /backup/etcd-snapshot.db
+
 	# Save snapshot with desirable time format
-	etcdctl snapshot save /mnt/backup/etcd/backup_$(date +%Y%m%d_%H%M%S).db`)
+	etcdctl snapshot save /mnt/backup/etcd/backup_$(date +%Y%m%d_%H%M%S).db
+
+	# Upload snapshot to S3 encrypted with a KMS key, without local space
+	etcdctl snapshot save s3://my-bucket/etcd/backup_$(date +%Y%m%d_%H%M%S).db --sse aws:kms --sse-kms-key-id alias/etcd
+
+	# Upload snapshot to Google Cloud Storage with an HMAC key
+	GCS_HMAC_ACCESS_KEY_ID=... GCS_HMAC_SECRET=... etcdctl snapshot save gs://my-bucket/etcd/backup.db`)
+
+var (
+	objectStoreCfg objectStoreConfig
+
+	saveManifest        bool
+	saveManifestSignKey string
+)
 
 // NewSnapshotCommand returns the cobra command for "snapshot".
 func NewSnapshotCommand() *cobra.Command {
@@ -58,12 +77,37 @@ func NewSnapshotCommand() *cobra.Command {
 }
 
 func NewSnapshotSaveCommand() *cobra.Command {
-	return &cobra.Command{
-		Use:     "save <filename>",
-		Short:   "Stores an etcd node backend snapshot to a given file",
+	cmd := &cobra.Command{
+		Use:   "save <filename|s3://bucket/key|gs://bucket/key>",
+		Short: "Stores an etcd node backend snapshot to a given file or object",
+		Long: `Stores an etcd node backend snapshot to a given file, or uploads it to an S3 or a Google
+Cloud Storage object while it is streamed from the server, a part at a time.
+
+The S3 credentials are read from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN,
+and the region from AWS_REGION unless --s3-region is set. Google Cloud Storage is accessed
+through its S3 compatible API with the HMAC key in GCS_HMAC_ACCESS_KEY_ID and GCS_HMAC_SECRET.
+
+The uploaded object keeps the sha256 hash appended to the snapshot, which is checked before
+the upload is completed, and the store checks the MD5 digest of each part. The etcd version
+is stored in the etcd-version metadata of the object.
+
+With --manifest, a manifest is written next to the saved file, with a .manifest suffix. It
+holds the sha256 hash of each chunk of the snapshot, its revision, its cluster ID and the
+etcd version, and is signed with --manifest-sign-key if set. "etcdutl snapshot restore"
+checks the snapshot against it, to detect a truncated or a modified backup.
+`,
 		Run:     snapshotSaveCommandFunc,
 		Example: snapshotExample,
 	}
+	cmd.Flags().StringVar(&objectStoreCfg.Endpoint, "s3-endpoint", "", "Endpoint of an S3 compatible store, the bucket is addressed in the path")
+	cmd.Flags().StringVar(&objectStoreCfg.Region, "s3-region", "", "Region of the S3 bucket")
+	cmd.Flags().Int64Var(&objectStoreCfg.PartSize, "part-size", defaultPartSize, "Size in bytes of the parts uploaded to the object store, buffered in memory")
+	cmd.Flags().StringVar(&objectStoreCfg.SSE, "sse", "", "Server-side encryption of the S3 object: AES256 or aws:kms")
+	cmd.Flags().StringVar(&objectStoreCfg.KMSKeyID, "sse-kms-key-id", "", "KMS key of the aws:kms server-side encryption (default: the AWS managed key)")
+	cmd.Flags().BoolVar(&saveManifest, "manifest", false, "Write the integrity manifest of the snapshot next to the saved file")
+	cmd.Flags().StringVar(&saveManifestSignKey, "manifest-sign-key", "", "Path of the PEM ed25519 private key the manifest is signed with (implies --manifest)")
+	cmd.MarkFlagFilename("manifest-sign-key")
+	return cmd
 }
 
 func snapshotSaveCommandFunc(cmd *cobra.Command, args []string) {
@@ -86,6 +130,18 @@ func snapshotSaveCommandFunc(cmd *cobra.Command, args []string) {
 	defer cancel()
 
 	path := args[0]
+	withManifest := saveManifest || saveManifestSignKey != ""
+	if isObjectStoreURL(path) {
+		if withManifest {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("--manifest is not supported with object stores, use etcdutl snapshot manifest on the downloaded snapshot"))
+		}
+		saveSnapshotToObjectStore(ctx, cfg, path)
+		return
+	}
+	if withManifest {
+		saveSnapshotWithManifest(ctx, lg, cfg, path)
+		return
+	}
 	version, err := snapshot.SaveWithVersion(ctx, lg, *cfg, path)
 	if err != nil {
 		cobrautl.ExitWithError(cobrautl.ExitInterrupted, err)
@@ -95,3 +151,55 @@ func snapshotSaveCommandFunc(cmd *cobra.Command, args []string) {
 		fmt.Printf("Server version %s\n", version)
 	}
 }
+
+func saveSnapshotWithManifest(ctx context.Context, lg *zap.Logger, cfg *clientv3.Config, path string) {
+	var key ed25519.PrivateKey
+	if saveManifestSignKey != "" {
+		var err error
+		if key, err = snapshot.ReadSigningKey(saveManifestSignKey); err != nil {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+		}
+	}
+	m, err := snapshot.SaveWithManifest(ctx, lg, *cfg, path, key)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitInterrupted, err)
+	}
+	fmt.Printf("Snapshot saved at %s\n", path)
+	fmt.Printf("Manifest saved at %s: revision %d, %d chunks, signed %t\n", path+snapshot.ManifestSuffix, m.Revision, len(m.Chunks), m.Signature != "")
+	if m.EtcdVersion != "" {
+		fmt.Printf("Server version %s\n", m.EtcdVersion)
+	}
+}
+
+func saveSnapshotToObjectStore(ctx context.Context, cfg *clientv3.Config, dest string) {
+	store, err := newObjectStore(dest, objectStoreCfg)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+	}
+	if len(cfg.Endpoints) != 1 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("snapshot must be requested to one selected node, not multiple %v", cfg.Endpoints))
+	}
+	cli, err := clientv3.New(*cfg)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitBadConnection, err)
+	}
+	defer cli.Close()
+
+	resp, err := cli.SnapshotWithVersion(ctx)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitInterrupted, err)
+	}
+	defer resp.Snapshot.Close()
+	meta := map[string]string{}
+	if resp.Version != "" {
+		meta["etcd-version"] = resp.Version
+	}
+	size, err := store.upload(ctx, resp.Snapshot, meta)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitInterrupted, err)
+	}
+	fmt.Printf("Snapshot saved at %s (%s)\n", dest, humanize.Bytes(uint64(size)))
+	if resp.Version != "" {
+		fmt.Printf("Server version %s\n", resp.Version)
+	}
+}
@@ -0,0 +1,430 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// minPartSize is the minimum size of the parts of a multipart upload,
+	// but the last one.
+	minPartSize     = 5 * 1024 * 1024
+	defaultPartSize = 64 * 1024 * 1024
+)
+
+// objectStoreConfig configures the upload of a snapshot to an object store.
+type objectStoreConfig struct {
+	// Endpoint overrides the endpoint of the store, e.g. for S3 compatible
+	// stores. The bucket is then addressed in the path.
+	Endpoint string
+	Region   string
+	PartSize int64
+	// SSE is the server-side encryption of S3 objects: "AES256" or
+	// "aws:kms".
+	SSE      string
+	KMSKeyID string
+}
+
+// objectStore uploads objects with the multipart upload API of S3, which
+// Google Cloud Storage supports through its XML API with HMAC keys.
+type objectStore struct {
+	client   *http.Client
+	endpoint *url.URL
+	// virtualHost addresses the bucket in the host of the endpoint, instead
+	// of the path.
+	virtualHost bool
+	bucket      string
+	key         string
+	cfg         objectStoreConfig
+
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+	now             func() time.Time
+}
+
+// isObjectStoreURL returns whether dest is the URL of an object instead of a
+// file path.
+func isObjectStoreURL(dest string) bool {
+	return strings.HasPrefix(dest, "s3://") || strings.HasPrefix(dest, "gs://")
+}
+
+// newObjectStore returns the store of the s3:// or gs:// destination URL.
+// The credentials are read from the environment: AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN for S3, and the HMAC key in
+// GCS_HMAC_ACCESS_KEY_ID and GCS_HMAC_SECRET for Google Cloud Storage.
+func newObjectStore(dest string, cfg objectStoreConfig) (*objectStore, error) {
+	u, err := url.Parse(dest)
+	if err != nil {
+		return nil, err
+	}
+	key := strings.TrimPrefix(u.Path, "/")
+	if u.Host == "" || key == "" {
+		return nil, fmt.Errorf("destination %q must be of the form %s://<bucket>/<key>", dest, u.Scheme)
+	}
+	if cfg.PartSize == 0 {
+		cfg.PartSize = defaultPartSize
+	}
+	if cfg.PartSize < minPartSize {
+		return nil, fmt.Errorf("part size must be at least %d bytes", minPartSize)
+	}
+	switch cfg.SSE {
+	case "", "AES256":
+		if cfg.KMSKeyID != "" {
+			return nil, errors.New("a KMS key requires the aws:kms server-side encryption")
+		}
+	case "aws:kms":
+	default:
+		return nil, fmt.Errorf("unsupported server-side encryption %q", cfg.SSE)
+	}
+
+	s := &objectStore{client: http.DefaultClient, bucket: u.Host, key: key, cfg: cfg, now: time.Now}
+	switch u.Scheme {
+	case "s3":
+		if s.cfg.Region == "" {
+			s.cfg.Region = os.Getenv("AWS_REGION")
+		}
+		if s.cfg.Region == "" {
+			s.cfg.Region = os.Getenv("AWS_DEFAULT_REGION")
+		}
+		if s.cfg.Region == "" {
+			s.cfg.Region = "us-east-1"
+		}
+		s.accessKeyID, s.secretAccessKey = os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+		s.sessionToken = os.Getenv("AWS_SESSION_TOKEN")
+		if cfg.Endpoint == "" {
+			cfg.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", s.cfg.Region)
+			s.virtualHost = true
+		}
+	case "gs":
+		if cfg.SSE != "" {
+			return nil, errors.New("server-side encryption options are only supported for s3 destinations, Google Cloud Storage encrypts objects by default")
+		}
+		if s.cfg.Region == "" {
+			s.cfg.Region = "auto"
+		}
+		s.accessKeyID, s.secretAccessKey = os.Getenv("GCS_HMAC_ACCESS_KEY_ID"), os.Getenv("GCS_HMAC_SECRET")
+		if cfg.Endpoint == "" {
+			cfg.Endpoint = "https://storage.googleapis.com"
+		}
+	default:
+		return nil, fmt.Errorf("unsupported destination scheme %q", u.Scheme)
+	}
+	if s.accessKeyID == "" || s.secretAccessKey == "" {
+		return nil, fmt.Errorf("missing credentials for %s:// destinations", u.Scheme)
+	}
+	if s.endpoint, err = url.Parse(cfg.Endpoint); err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", cfg.Endpoint, err)
+	}
+	return s, nil
+}
+
+// objectURL returns the URL of the object with the given query.
+func (s *objectStore) objectURL(query url.Values) *url.URL {
+	u := *s.endpoint
+	prefix := strings.TrimSuffix(u.Path, "/")
+	if s.virtualHost {
+		u.Host = s.bucket + "." + u.Host
+	} else {
+		prefix += "/" + s.bucket
+	}
+	u.Path = prefix + "/" + s.key
+	u.RawPath = uriEncode(prefix, false) + "/" + uriEncode(s.key, false)
+	u.RawQuery = query.Encode()
+	return &u
+}
+
+// uriEncode encodes s as required by the signature: all the bytes but the
+// unreserved characters, and the slashes unless encodeSlash is set, are
+// percent-encoded.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !encodeSlash) {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// upload uploads the snapshot read from r as the object, a part at a time,
+// so that no local space is needed. The sha256 hash the server appends to the
+// snapshot is checked before the upload is completed, and the store checks
+// the MD5 digest of each part. The upload is aborted on error. meta is stored
+// as the user metadata of the object.
+func (s *objectStore) upload(ctx context.Context, r io.Reader, meta map[string]string) (size int64, err error) {
+	dr := &digestReader{r: r, h: sha256.New()}
+	buf := make([]byte, s.cfg.PartSize)
+	var uploadID string
+	var parts []completedPart
+	defer func() {
+		if err != nil && uploadID != "" {
+			// abort with a fresh context, ctx may be the cause of the error.
+			actx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			s.do(actx, http.MethodDelete, url.Values{"uploadId": {uploadID}}, nil, nil, nil)
+		}
+	}()
+	for {
+		n, rerr := io.ReadFull(dr, buf)
+		if rerr != nil && !errors.Is(rerr, io.EOF) && !errors.Is(rerr, io.ErrUnexpectedEOF) {
+			return size, fmt.Errorf("could not read snapshot: %w", rerr)
+		}
+		if n > 0 {
+			if uploadID == "" {
+				if uploadID, err = s.initiate(ctx, meta); err != nil {
+					return size, err
+				}
+			}
+			part := completedPart{PartNumber: len(parts) + 1}
+			if part.ETag, err = s.uploadPart(ctx, uploadID, part.PartNumber, buf[:n]); err != nil {
+				return size, err
+			}
+			parts = append(parts, part)
+			size += int64(n)
+		}
+		if rerr != nil {
+			break
+		}
+	}
+	if err = dr.verify(); err != nil {
+		return size, err
+	}
+	return size, s.complete(ctx, uploadID, parts)
+}
+
+func (s *objectStore) initiate(ctx context.Context, meta map[string]string) (string, error) {
+	h := http.Header{}
+	for k, v := range meta {
+		h.Set("X-Amz-Meta-"+k, v)
+	}
+	if s.cfg.SSE != "" {
+		h.Set("X-Amz-Server-Side-Encryption", s.cfg.SSE)
+	}
+	if s.cfg.KMSKeyID != "" {
+		h.Set("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id", s.cfg.KMSKeyID)
+	}
+	var res struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := s.do(ctx, http.MethodPost, url.Values{"uploads": {""}}, h, nil, &res); err != nil {
+		return "", fmt.Errorf("could not initiate upload: %w", err)
+	}
+	if res.UploadID == "" {
+		return "", errors.New("could not initiate upload: no upload ID returned")
+	}
+	return res.UploadID, nil
+}
+
+func (s *objectStore) uploadPart(ctx context.Context, uploadID string, number int, data []byte) (string, error) {
+	sum := md5.Sum(data)
+	h := http.Header{}
+	h.Set("Content-Md5", base64.StdEncoding.EncodeToString(sum[:]))
+	q := url.Values{"partNumber": {fmt.Sprint(number)}, "uploadId": {uploadID}}
+	var etag string
+	err := s.doWithResponse(ctx, http.MethodPut, q, h, data, func(resp *http.Response) error {
+		etag = resp.Header.Get("ETag")
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("could not upload part %d: %w", number, err)
+	}
+	return etag, nil
+}
+
+type completedPart struct {
+	PartNumber int
+	ETag       string
+}
+
+func (s *objectStore) complete(ctx context.Context, uploadID string, parts []completedPart) error {
+	body, err := xml.Marshal(struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}{Parts: parts})
+	if err != nil {
+		return err
+	}
+	// the completion may fail after a 200 OK status, the error is then in
+	// the body.
+	var res struct {
+		XMLName xml.Name
+		Code    string
+		Message string
+	}
+	if err = s.do(ctx, http.MethodPost, url.Values{"uploadId": {uploadID}}, nil, body, &res); err != nil {
+		return fmt.Errorf("could not complete upload: %w", err)
+	}
+	if res.XMLName.Local == "Error" {
+		return fmt.Errorf("could not complete upload: %s: %s", res.Code, res.Message)
+	}
+	return nil
+}
+
+// do sends a signed request for the object and decodes the XML response
+// body into res if it is not nil.
+func (s *objectStore) do(ctx context.Context, method string, query url.Values, h http.Header, body []byte, res any) error {
+	return s.doWithResponse(ctx, method, query, h, body, func(resp *http.Response) error {
+		if res == nil {
+			return nil
+		}
+		return xml.NewDecoder(resp.Body).Decode(res)
+	})
+}
+
+func (s *objectStore) doWithResponse(ctx context.Context, method string, query url.Values, h http.Header, body []byte, f func(*http.Response) error) error {
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(query).String(), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range h {
+		req.Header[k] = v
+	}
+	s.sign(req, body)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return f(resp)
+}
+
+// sign signs req with the AWS signature version 4, signing all its headers.
+func (s *objectStore) sign(req *http.Request, body []byte) {
+	t := s.now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	payloadHash := sha256.Sum256(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+	scope := date + "/" + s.cfg.Region + "/s3/aws4_request"
+	crHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(crHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.secretAccessKey), date)
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", s.accessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes q sorted by key.
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var pairs []string
+	for _, k := range keys {
+		for _, v := range q[k] {
+			pairs = append(pairs, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// digestReader hashes the snapshot read from r but its last sha256.Size
+// bytes, which are the hash the server appends to the snapshot.
+type digestReader struct {
+	r    io.Reader
+	h    hash.Hash
+	n    int64
+	tail []byte
+}
+
+func (d *digestReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	d.n += int64(n)
+	d.tail = append(d.tail, p[:n]...)
+	if k := len(d.tail) - sha256.Size; k > 0 {
+		d.h.Write(d.tail[:k])
+		d.tail = append(d.tail[:0], d.tail[k:]...)
+	}
+	return n, err
+}
+
+// verify checks the hash appended to the snapshot.
+func (d *digestReader) verify() error {
+	// 512 is the minimum disk sector size, which the size of the snapshot
+	// is a multiple of.
+	if d.n%512 != sha256.Size {
+		return fmt.Errorf("sha256 checksum not found [bytes: %d]", d.n)
+	}
+	if !bytes.Equal(d.h.Sum(nil), d.tail) {
+		return errors.New("sha256 checksum mismatch, the snapshot is corrupted")
+	}
+	return nil
+}
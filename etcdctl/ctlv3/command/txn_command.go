@@ -29,7 +29,11 @@ import (
 	"go.etcd.io/etcd/pkg/v3/cobrautl"
 )
 
-var txnInteractive bool
+var (
+	txnInteractive bool
+	txnJSONInput   bool
+	txnFile        string
+)
 
 // NewTxnCommand returns the cobra command for "txn".
 func NewTxnCommand() *cobra.Command {
@@ -58,10 +62,23 @@ put key1 "created-key1"
 put key2 "some extra key"
 ---
 
+With --json or --file, the transaction is read from a JSON document instead:
+
+---
+etcdctl txn --file txn.json
+{
+  "compare": [{"key": "key1", "target": "mod", "result": ">", "value": 0}],
+  "success": [{"put": {"key": "key1", "value": "overwrote-key1"}}],
+  "failure": [{"put": {"key": "key1", "value": "created-key1"}}]
+}
+---
+
 Refer to https://github.com/etcd-io/etcd/blob/main/etcdctl/README.md#txn-options.`,
 		Run: txnCommandFunc,
 	}
 	cmd.Flags().BoolVarP(&txnInteractive, "interactive", "i", false, "Input transaction in interactive mode")
+	cmd.Flags().BoolVar(&txnJSONInput, "json", false, "Read the transaction from standard input as a JSON document")
+	cmd.Flags().StringVar(&txnFile, "file", "", "Read the transaction from the JSON document in the file, standard input if '-'")
 	return cmd
 }
 
@@ -70,6 +87,13 @@ func txnCommandFunc(cmd *cobra.Command, args []string) {
 	if len(args) != 0 {
 		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("txn command does not accept argument"))
 	}
+	if txnJSONInput || txnFile != "" {
+		if txnInteractive {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("--interactive cannot be used with --json or --file"))
+		}
+		txnJSONCommandFunc(cmd)
+		return
+	}
 
 	reader := bufio.NewReader(os.Stdin)
 
@@ -89,6 +113,30 @@ func txnCommandFunc(cmd *cobra.Command, args []string) {
 	display.Txn(*resp)
 }
 
+// txnJSONCommandFunc executes the "txn" command with a JSON document.
+func txnJSONCommandFunc(cmd *cobra.Command) {
+	in := os.Stdin
+	if txnFile != "" && txnFile != "-" {
+		f, err := os.Open(txnFile)
+		if err != nil {
+			cobrautl.ExitWithError(cobrautl.ExitError, err)
+		}
+		defer f.Close()
+		in = f
+	}
+	cmps, thenOps, elseOps, err := readTxnJSON(in)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitInvalidInput, err)
+	}
+
+	resp, err := mustClientFromCmd(cmd).Txn(context.Background()).If(cmps...).Then(thenOps...).Else(elseOps...).Commit()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+
+	display.Txn(*resp)
+}
+
 func promptInteractive(s string) {
 	if txnInteractive {
 		fmt.Println(s)
@@ -219,6 +267,12 @@ func ParseCompare(line string) (*clientv3.Cmp, error) {
 		cmp = clientv3.Compare(clientv3.Value(key), op, val)
 	case "lease":
 		cmp = clientv3.Compare(clientv3.Cmp{Target: pb.Compare_LEASE}, op, val)
+	case "count":
+		if v, err = strconv.ParseInt(val, 10, 64); err == nil {
+			cmp = clientv3.Compare(clientv3.Count(key).WithPrefix(), op, v)
+		}
+	case "val_prefix", "value_prefix":
+		cmp = clientv3.Compare(clientv3.ValuePrefix(key), op, val)
 	default:
 		return nil, fmt.Errorf("malformed comparison: %s (unknown target %s)", line, target)
 	}
@@ -0,0 +1,300 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// txnJSON is the JSON document of a transaction read by "txn --json".
+type txnJSON struct {
+	Compare []txnJSONCompare `json:"compare"`
+	Success []txnJSONOp      `json:"success"`
+	Failure []txnJSONOp      `json:"failure"`
+}
+
+// txnJSONCompare compares the target of a key, or of the keys of a range,
+// with a value: a string for the value and value_prefix targets, an integer
+// for the version, create, mod and count targets, and a hex lease ID string
+// for the lease target.
+type txnJSONCompare struct {
+	Key      string          `json:"key"`
+	RangeEnd string          `json:"range_end,omitempty"`
+	Prefix   bool            `json:"prefix,omitempty"`
+	Target   string          `json:"target"`
+	Result   string          `json:"result"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// txnJSONOp is a request of a transaction; exactly one of its fields is set.
+type txnJSONOp struct {
+	Put    *txnJSONPut    `json:"put,omitempty"`
+	Get    *txnJSONGet    `json:"get,omitempty"`
+	Delete *txnJSONDelete `json:"delete,omitempty"`
+}
+
+type txnJSONPut struct {
+	Key         string `json:"key"`
+	Value       string `json:"value"`
+	Lease       string `json:"lease,omitempty"`
+	PrevKV      bool   `json:"prev_kv,omitempty"`
+	IgnoreValue bool   `json:"ignore_value,omitempty"`
+	IgnoreLease bool   `json:"ignore_lease,omitempty"`
+}
+
+type txnJSONGet struct {
+	Key          string `json:"key"`
+	RangeEnd     string `json:"range_end,omitempty"`
+	Prefix       bool   `json:"prefix,omitempty"`
+	FromKey      bool   `json:"from_key,omitempty"`
+	Rev          int64  `json:"rev,omitempty"`
+	Limit        int64  `json:"limit,omitempty"`
+	KeysOnly     bool   `json:"keys_only,omitempty"`
+	CountOnly    bool   `json:"count_only,omitempty"`
+	MinCreateRev int64  `json:"min_create_rev,omitempty"`
+	MaxCreateRev int64  `json:"max_create_rev,omitempty"`
+	MinModRev    int64  `json:"min_mod_rev,omitempty"`
+	MaxModRev    int64  `json:"max_mod_rev,omitempty"`
+}
+
+type txnJSONDelete struct {
+	Key      string `json:"key"`
+	RangeEnd string `json:"range_end,omitempty"`
+	Prefix   bool   `json:"prefix,omitempty"`
+	FromKey  bool   `json:"from_key,omitempty"`
+	PrevKV   bool   `json:"prev_kv,omitempty"`
+}
+
+// readTxnJSON reads a transaction from its JSON document. Unknown fields are
+// rejected, so that a typo doesn't silently change the transaction.
+func readTxnJSON(r io.Reader) (cmps []clientv3.Cmp, thenOps []clientv3.Op, elseOps []clientv3.Op, err error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var doc txnJSON
+	if err = dec.Decode(&doc); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid txn document: %w", err)
+	}
+	if dec.More() {
+		return nil, nil, nil, errors.New("invalid txn document: unexpected data after the document")
+	}
+
+	for i, c := range doc.Compare {
+		cmp, err := c.compare()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("invalid txn compare %d: %w", i, err)
+		}
+		cmps = append(cmps, cmp)
+	}
+	if thenOps, err = txnJSONOps(doc.Success); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid txn success request %w", err)
+	}
+	if elseOps, err = txnJSONOps(doc.Failure); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid txn failure request %w", err)
+	}
+	return cmps, thenOps, elseOps, nil
+}
+
+func (c txnJSONCompare) compare() (clientv3.Cmp, error) {
+	switch c.Result {
+	case "=", "!=", "<", ">":
+	default:
+		return clientv3.Cmp{}, fmt.Errorf("unknown result %q, expected =, !=, < or >", c.Result)
+	}
+	if len(c.Value) == 0 {
+		return clientv3.Cmp{}, errors.New("missing value")
+	}
+	if c.RangeEnd != "" && c.Prefix {
+		return clientv3.Cmp{}, errors.New("range_end and prefix are mutually exclusive")
+	}
+
+	cmp, err := c.target()
+	if err != nil {
+		return clientv3.Cmp{}, err
+	}
+	switch {
+	case c.RangeEnd != "":
+		cmp = cmp.WithRange(c.RangeEnd)
+	case c.Prefix:
+		cmp = cmp.WithPrefix()
+	}
+	return cmp, nil
+}
+
+func (c txnJSONCompare) target() (clientv3.Cmp, error) {
+	switch c.Target {
+	case "value", "value_prefix":
+		var v string
+		if err := json.Unmarshal(c.Value, &v); err != nil {
+			return clientv3.Cmp{}, fmt.Errorf("%s target expects a string value: %w", c.Target, err)
+		}
+		if c.Target == "value_prefix" {
+			return clientv3.Compare(clientv3.ValuePrefix(c.Key), c.Result, v), nil
+		}
+		return clientv3.Compare(clientv3.Value(c.Key), c.Result, v), nil
+	case "version", "create", "mod", "count":
+		var v int64
+		if err := json.Unmarshal(c.Value, &v); err != nil {
+			return clientv3.Cmp{}, fmt.Errorf("%s target expects an integer value: %w", c.Target, err)
+		}
+		cmp := clientv3.Version(c.Key)
+		if c.Target == "create" {
+			cmp = clientv3.CreateRevision(c.Key)
+		} else if c.Target == "mod" {
+			cmp = clientv3.ModRevision(c.Key)
+		} else if c.Target == "count" {
+			cmp = clientv3.Count(c.Key)
+		}
+		return clientv3.Compare(cmp, c.Result, v), nil
+	case "lease":
+		var v string
+		if err := json.Unmarshal(c.Value, &v); err != nil {
+			return clientv3.Cmp{}, fmt.Errorf("lease target expects a hex lease ID string: %w", err)
+		}
+		id, err := strconv.ParseInt(v, 16, 64)
+		if err != nil {
+			return clientv3.Cmp{}, fmt.Errorf("bad lease ID (%w), expecting ID in Hex", err)
+		}
+		return clientv3.Compare(clientv3.LeaseValue(c.Key), c.Result, clientv3.LeaseID(id)), nil
+	}
+	return clientv3.Cmp{}, fmt.Errorf("unknown target %q, expected value, value_prefix, version, create, mod, count or lease", c.Target)
+}
+
+func txnJSONOps(jops []txnJSONOp) (ops []clientv3.Op, err error) {
+	for i, jop := range jops {
+		op, err := jop.op()
+		if err != nil {
+			return nil, fmt.Errorf("%d: %w", i, err)
+		}
+		ops = append(ops, op)
+	}
+	return ops, nil
+}
+
+func (o txnJSONOp) op() (clientv3.Op, error) {
+	n := 0
+	for _, set := range []bool{o.Put != nil, o.Get != nil, o.Delete != nil} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return clientv3.Op{}, errors.New("expected exactly one of put, get or delete")
+	}
+
+	switch {
+	case o.Put != nil:
+		p := o.Put
+		var opts []clientv3.OpOption
+		if p.Lease != "" {
+			id, err := strconv.ParseInt(p.Lease, 16, 64)
+			if err != nil {
+				return clientv3.Op{}, fmt.Errorf("bad lease ID (%w), expecting ID in Hex", err)
+			}
+			opts = append(opts, clientv3.WithLease(clientv3.LeaseID(id)))
+		}
+		if p.PrevKV {
+			opts = append(opts, clientv3.WithPrevKV())
+		}
+		if p.IgnoreValue {
+			if p.Value != "" {
+				return clientv3.Op{}, errors.New("put sets a value and ignore_value")
+			}
+			opts = append(opts, clientv3.WithIgnoreValue())
+		}
+		if p.IgnoreLease {
+			opts = append(opts, clientv3.WithIgnoreLease())
+		}
+		return clientv3.OpPut(p.Key, p.Value, opts...), nil
+	case o.Get != nil:
+		g := o.Get
+		opts, err := txnJSONRange(g.Key, g.RangeEnd, g.Prefix, g.FromKey)
+		if err != nil {
+			return clientv3.Op{}, err
+		}
+		if g.Rev > 0 {
+			opts = append(opts, clientv3.WithRev(g.Rev))
+		}
+		if g.Limit > 0 {
+			opts = append(opts, clientv3.WithLimit(g.Limit))
+		}
+		if g.KeysOnly {
+			opts = append(opts, clientv3.WithKeysOnly())
+		}
+		if g.CountOnly {
+			opts = append(opts, clientv3.WithCountOnly())
+		}
+		if g.MaxCreateRev > 0 && g.MinCreateRev > g.MaxCreateRev {
+			return clientv3.Op{}, fmt.Errorf("get min_create_rev(=%v) > max_create_rev(=%v)", g.MinCreateRev, g.MaxCreateRev)
+		}
+		if g.MaxModRev > 0 && g.MinModRev > g.MaxModRev {
+			return clientv3.Op{}, fmt.Errorf("get min_mod_rev(=%v) > max_mod_rev(=%v)", g.MinModRev, g.MaxModRev)
+		}
+		if g.MinCreateRev > 0 {
+			opts = append(opts, clientv3.WithMinCreateRev(g.MinCreateRev))
+		}
+		if g.MaxCreateRev > 0 {
+			opts = append(opts, clientv3.WithMaxCreateRev(g.MaxCreateRev))
+		}
+		if g.MinModRev > 0 {
+			opts = append(opts, clientv3.WithMinModRev(g.MinModRev))
+		}
+		if g.MaxModRev > 0 {
+			opts = append(opts, clientv3.WithMaxModRev(g.MaxModRev))
+		}
+		return clientv3.OpGet(g.Key, opts...), nil
+	default:
+		d := o.Delete
+		opts, err := txnJSONRange(d.Key, d.RangeEnd, d.Prefix, d.FromKey)
+		if err != nil {
+			return clientv3.Op{}, err
+		}
+		if d.PrevKV {
+			opts = append(opts, clientv3.WithPrevKV())
+		}
+		return clientv3.OpDelete(d.Key, opts...), nil
+	}
+}
+
+// txnJSONRange returns the options of the range of a get or delete request.
+func txnJSONRange(key, end string, prefix, fromKey bool) ([]clientv3.OpOption, error) {
+	n := 0
+	for _, set := range []bool{end != "", prefix, fromKey} {
+		if set {
+			n++
+		}
+	}
+	if n > 1 {
+		return nil, errors.New("range_end, prefix and from_key are mutually exclusive")
+	}
+
+	switch {
+	case end != "":
+		return []clientv3.OpOption{clientv3.WithRange(end)}, nil
+	case prefix:
+		if key == "" {
+			return []clientv3.OpOption{clientv3.WithFromKey()}, nil
+		}
+		return []clientv3.OpOption{clientv3.WithPrefix()}, nil
+	case fromKey:
+		return []clientv3.OpOption{clientv3.WithFromKey()}, nil
+	}
+	return nil, nil
+}
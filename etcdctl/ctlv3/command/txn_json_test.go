@@ -0,0 +1,88 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func TestReadTxnJSON(t *testing.T) {
+	doc := `{
+		"compare": [
+			{"key": "k1", "target": "mod", "result": ">", "value": 3},
+			{"key": "k2", "target": "value", "result": "!=", "value": "v"},
+			{"key": "k3", "target": "lease", "result": "=", "value": "1f"},
+			{"key": "k4", "target": "value_prefix", "result": "=", "value": "v"},
+			{"key": "k5/", "prefix": true, "target": "count", "result": "<", "value": 10}
+		],
+		"success": [
+			{"put": {"key": "k1", "value": "v1", "lease": "1f", "prev_kv": true}},
+			{"get": {"key": "k", "prefix": true, "limit": 10}},
+			{"get": {"key": "k", "prefix": true, "min_create_rev": 5, "max_mod_rev": 9}}
+		],
+		"failure": [
+			{"delete": {"key": "a", "range_end": "c"}}
+		]
+	}`
+	cmps, thenOps, elseOps, err := readTxnJSON(strings.NewReader(doc))
+	require.NoError(t, err)
+
+	require.Equal(t, []clientv3.Cmp{
+		clientv3.Compare(clientv3.ModRevision("k1"), ">", 3),
+		clientv3.Compare(clientv3.Value("k2"), "!=", "v"),
+		clientv3.Compare(clientv3.LeaseValue("k3"), "=", clientv3.LeaseID(0x1f)),
+		clientv3.Compare(clientv3.ValuePrefix("k4"), "=", "v"),
+		clientv3.Compare(clientv3.Count("k5/"), "<", 10).WithPrefix(),
+	}, cmps)
+
+	require.Len(t, thenOps, 3)
+	require.Equal(t, clientv3.OpPut("k1", "v1", clientv3.WithLease(0x1f), clientv3.WithPrevKV()), thenOps[0])
+	require.Equal(t, clientv3.OpGet("k", clientv3.WithPrefix(), clientv3.WithLimit(10)), thenOps[1])
+	require.Equal(t, clientv3.OpGet("k", clientv3.WithPrefix(), clientv3.WithMinCreateRev(5), clientv3.WithMaxModRev(9)), thenOps[2])
+
+	require.Equal(t, []clientv3.Op{clientv3.OpDelete("a", clientv3.WithRange("c"))}, elseOps)
+}
+
+func TestReadTxnJSONInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		err  string
+	}{
+		{name: "unknown field", doc: `{"compares": []}`, err: "unknown field"},
+		{name: "trailing data", doc: `{} {}`, err: "unexpected data"},
+		{name: "unknown target", doc: `{"compare": [{"key": "k", "target": "size", "result": "=", "value": 1}]}`, err: "unknown target"},
+		{name: "unknown result", doc: `{"compare": [{"key": "k", "target": "mod", "result": "<=", "value": 1}]}`, err: "unknown result"},
+		{name: "missing value", doc: `{"compare": [{"key": "k", "target": "mod", "result": "="}]}`, err: "missing value"},
+		{name: "string revision", doc: `{"compare": [{"key": "k", "target": "mod", "result": "=", "value": "1"}]}`, err: "integer value"},
+		{name: "compare prefix and range", doc: `{"compare": [{"key": "k", "prefix": true, "range_end": "l", "target": "count", "result": "=", "value": 0}]}`, err: "mutually exclusive"},
+		{name: "no request", doc: `{"success": [{}]}`, err: "exactly one of"},
+		{name: "two requests", doc: `{"failure": [{"get": {"key": "k"}, "delete": {"key": "k"}}]}`, err: "exactly one of"},
+		{name: "prefix and range", doc: `{"success": [{"get": {"key": "k", "prefix": true, "range_end": "l"}}]}`, err: "mutually exclusive"},
+		{name: "create revision bounds", doc: `{"success": [{"get": {"key": "k", "min_create_rev": 5, "max_create_rev": 4}}]}`, err: "min_create_rev(=5) > max_create_rev(=4)"},
+		{name: "bad lease", doc: `{"success": [{"put": {"key": "k", "value": "v", "lease": "xyz"}}]}`, err: "bad lease ID"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := readTxnJSON(strings.NewReader(tt.doc))
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -196,9 +196,16 @@ func userGetCommandFunc(cmd *cobra.Command, args []string) {
 	}
 
 	if userShowDetail {
-		fmt.Printf("User: %s\n", name)
+		_, simple := (display).(*simplePrinter)
+		if simple {
+			fmt.Printf("User: %s\n", name)
+		} else {
+			display.UserGet(name, *resp)
+		}
 		for _, role := range resp.Roles {
-			fmt.Print("\n")
+			if simple {
+				fmt.Print("\n")
+			}
 			roleResp, err := client.Auth.RoleGet(context.TODO(), role)
 			if err != nil {
 				cobrautl.ExitWithError(cobrautl.ExitError, err)
@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -42,6 +44,7 @@ var (
 	watchInteractive bool
 	watchPrevKey     bool
 	progressNotify   bool
+	watchResumeFile  string
 )
 
 // NewWatchCommand returns the cobra command for "watch".
@@ -57,6 +60,7 @@ func NewWatchCommand() *cobra.Command {
 	cmd.Flags().Int64Var(&watchRev, "rev", 0, "Revision to start watching")
 	cmd.Flags().BoolVar(&watchPrevKey, "prev-kv", false, "get the previous key-value pair before the event happens")
 	cmd.Flags().BoolVar(&progressNotify, "progress-notify", false, "get periodic watch progress notification from server")
+	cmd.Flags().StringVar(&watchResumeFile, "resume-file", "", "File to checkpoint the last delivered revision to, and to resume watching from after a restart")
 
 	return cmd
 }
@@ -69,6 +73,9 @@ func watchCommandFunc(cmd *cobra.Command, args []string) {
 	}
 
 	if watchInteractive {
+		if watchResumeFile != "" {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, errors.New("--resume-file is not supported in interactive mode"))
+		}
 		watchInteractiveFunc(cmd, os.Args, envKey, envRange)
 		return
 	}
@@ -79,12 +86,15 @@ func watchCommandFunc(cmd *cobra.Command, args []string) {
 	}
 
 	c := mustClientFromCmd(cmd)
-	wc, err := getWatchChan(c, watchArgs)
-	if err != nil {
-		cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+	if watchResumeFile != "" {
+		watchResumeFunc(c, watchArgs, execArgs)
+	} else {
+		wc, err := getWatchChan(c, watchArgs)
+		if err != nil {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+		}
+		printWatchCh(c, wc, execArgs)
 	}
-
-	printWatchCh(c, wc, execArgs)
 	if err = c.Close(); err != nil {
 		cobrautl.ExitWithError(cobrautl.ExitBadConnection, err)
 	}
@@ -164,30 +174,124 @@ func getWatchChan(c *clientv3.Client, args []string) (clientv3.WatchChan, error)
 
 func printWatchCh(c *clientv3.Client, ch clientv3.WatchChan, execArgs []string) {
 	for resp := range ch {
-		if resp.Canceled {
-			fmt.Fprintf(os.Stderr, "watch was canceled (%v)\n", resp.Err())
+		printWatchResp(c, resp, execArgs)
+	}
+}
+
+func printWatchResp(c *clientv3.Client, resp clientv3.WatchResponse, execArgs []string) {
+	if resp.Canceled {
+		fmt.Fprintf(os.Stderr, "watch was canceled (%v)\n", resp.Err())
+	}
+	if resp.IsProgressNotify() {
+		fmt.Fprintf(os.Stdout, "progress notify: %d\n", resp.Header.Revision)
+	}
+	display.Watch(resp)
+
+	if len(execArgs) > 0 {
+		for _, ev := range resp.Events {
+			cmd := exec.CommandContext(c.Ctx(), execArgs[0], execArgs[1:]...)
+			cmd.Env = os.Environ()
+			cmd.Env = append(cmd.Env, fmt.Sprintf("ETCD_WATCH_REVISION=%d", resp.Header.Revision))
+			cmd.Env = append(cmd.Env, fmt.Sprintf("ETCD_WATCH_EVENT_TYPE=%q", ev.Type))
+			cmd.Env = append(cmd.Env, fmt.Sprintf("ETCD_WATCH_KEY=%q", ev.Kv.Key))
+			cmd.Env = append(cmd.Env, fmt.Sprintf("ETCD_WATCH_VALUE=%q", ev.Kv.Value))
+			cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+			if err := cmd.Run(); err != nil {
+				fmt.Fprintf(os.Stderr, "command %q error (%v)\n", execArgs, err)
+				os.Exit(1)
+			}
 		}
-		if resp.IsProgressNotify() {
-			fmt.Fprintf(os.Stdout, "progress notify: %d\n", resp.Header.Revision)
+	}
+}
+
+// watchResumeFunc watches from the revision following the one checkpointed
+// in the resume file, or from --rev if there is none yet, and checkpoints the
+// last delivered revision after each response is printed and its commands
+// run. If the revision to resume from was compacted, the events up to the
+// compaction are lost: the watch resumes from the compact revision.
+func watchResumeFunc(c *clientv3.Client, watchArgs, execArgs []string) {
+	rev, err := readWatchResumeFile(watchResumeFile)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	if rev > 0 {
+		watchRev = rev + 1
+	}
+
+	for {
+		wc, err := getWatchChan(c, watchArgs)
+		if err != nil {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
 		}
-		display.Watch(resp)
-
-		if len(execArgs) > 0 {
-			for _, ev := range resp.Events {
-				cmd := exec.CommandContext(c.Ctx(), execArgs[0], execArgs[1:]...)
-				cmd.Env = os.Environ()
-				cmd.Env = append(cmd.Env, fmt.Sprintf("ETCD_WATCH_REVISION=%d", resp.Header.Revision))
-				cmd.Env = append(cmd.Env, fmt.Sprintf("ETCD_WATCH_EVENT_TYPE=%q", ev.Type))
-				cmd.Env = append(cmd.Env, fmt.Sprintf("ETCD_WATCH_KEY=%q", ev.Kv.Key))
-				cmd.Env = append(cmd.Env, fmt.Sprintf("ETCD_WATCH_VALUE=%q", ev.Kv.Value))
-				cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
-				if err := cmd.Run(); err != nil {
-					fmt.Fprintf(os.Stderr, "command %q error (%v)\n", execArgs, err)
-					os.Exit(1)
+		var compactRev int64
+		for resp := range wc {
+			if resp.CompactRevision != 0 {
+				compactRev = resp.CompactRevision
+				continue
+			}
+			printWatchResp(c, resp, execArgs)
+			if rev := watchRespRevision(resp); rev > 0 {
+				if err = writeWatchResumeFile(watchResumeFile, rev); err != nil {
+					cobrautl.ExitWithError(cobrautl.ExitError, err)
 				}
 			}
 		}
+		if compactRev == 0 {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "revision %d has been compacted, resuming the watch from the compact revision %d\n", watchRev, compactRev)
+		watchRev = compactRev
+	}
+}
+
+// watchRespRevision returns the revision up to which the events of the
+// watch were delivered by the response, or 0 if it doesn't tell.
+func watchRespRevision(resp clientv3.WatchResponse) int64 {
+	if n := len(resp.Events); n > 0 {
+		return resp.Events[n-1].Kv.ModRevision
+	}
+	if resp.IsProgressNotify() {
+		return resp.Header.Revision
+	}
+	return 0
+}
+
+// readWatchResumeFile returns the revision checkpointed in the resume file,
+// or 0 if the file doesn't exist.
+func readWatchResumeFile(path string) (int64, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	rev, err := strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil || rev < 0 {
+		return 0, fmt.Errorf("invalid watch resume file %q: expected a revision", path)
+	}
+	return rev, nil
+}
+
+// writeWatchResumeFile checkpoints the revision to the resume file. The
+// revision is written to a temporary file renamed over the resume file, so
+// that a crash leaves either the previous or the new revision.
+func writeWatchResumeFile(path string, rev int64) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err = fmt.Fprintf(f, "%d\n", rev); err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
 	}
+	return os.Rename(f.Name(), path)
 }
 
 // "commandArgs" is the command arguments after "spf13/cobra" parses
@@ -15,10 +15,16 @@
 package command
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
 )
 
 func Test_parseWatchArgs(t *testing.T) {
@@ -546,3 +552,42 @@ func Test_parseWatchArgs(t *testing.T) {
 		}
 	}
 }
+
+func TestWatchResumeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resume")
+
+	rev, err := readWatchResumeFile(path)
+	require.NoError(t, err)
+	require.Zero(t, rev)
+
+	require.NoError(t, writeWatchResumeFile(path, 42))
+	require.NoError(t, writeWatchResumeFile(path, 43))
+	rev, err = readWatchResumeFile(path)
+	require.NoError(t, err)
+	require.Equal(t, int64(43), rev)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "temporary files are left behind")
+
+	require.NoError(t, os.WriteFile(path, []byte("garbage"), 0o600))
+	_, err = readWatchResumeFile(path)
+	require.ErrorContains(t, err, "invalid watch resume file")
+}
+
+func TestWatchRespRevision(t *testing.T) {
+	events := clientv3.WatchResponse{
+		Header: pb.ResponseHeader{Revision: 10},
+		Events: []*clientv3.Event{
+			{Kv: &mvccpb.KeyValue{ModRevision: 5}},
+			{Kv: &mvccpb.KeyValue{ModRevision: 7}},
+		},
+	}
+	require.Equal(t, int64(7), watchRespRevision(events))
+
+	progress := clientv3.WatchResponse{Header: pb.ResponseHeader{Revision: 10}}
+	require.Equal(t, int64(10), watchRespRevision(progress))
+
+	created := clientv3.WatchResponse{Header: pb.ResponseHeader{Revision: 10}, Created: true}
+	require.Zero(t, watchRespRevision(created))
+}
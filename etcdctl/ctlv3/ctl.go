@@ -43,6 +43,9 @@ var (
 		Use:        cliName,
 		Short:      cliDescription,
 		SuggestFor: []string{"etcdctl"},
+		PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+			command.InitOutputFromCmd(cmd)
+		},
 	}
 )
 
@@ -73,6 +76,7 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&globalFlags.Token, "auth-jwt-token", "", "JWT token used for authentication (if this option is used, --user and --password should not be set)")
 	rootCmd.PersistentFlags().StringVar(&globalFlags.User, "user", "", "username[:password] for authentication (prompt if password is not supplied)")
 	rootCmd.PersistentFlags().StringVar(&globalFlags.Password, "password", "", "password for authentication (if this option is used, --user option shouldn't include password)")
+	rootCmd.PersistentFlags().StringVar(&globalFlags.Namespace, "namespace", "", "server-side namespace isolating the keys of the requests")
 	rootCmd.PersistentFlags().StringVarP(&globalFlags.TLS.ServerName, "discovery-srv", "d", "", "domain name to query for SRV records describing cluster endpoints")
 	rootCmd.PersistentFlags().StringVarP(&globalFlags.DNSClusterServiceName, "discovery-srv-name", "", "", "service name to query when using DNS discovery")
 
@@ -83,7 +87,10 @@ func init() {
 		command.NewTxnCommand(),
 		command.NewCompactionCommand(),
 		command.NewAlarmCommand(),
+		command.NewReadOnlyCommand(),
 		command.NewDefragCommand(),
+		command.NewReclaimCommand(),
+		command.NewEncryptionCommand(),
 		command.NewEndpointCommand(),
 		command.NewMoveLeaderCommand(),
 		command.NewWatchCommand(),
@@ -92,6 +99,10 @@ func init() {
 		command.NewMemberCommand(),
 		command.NewSnapshotCommand(),
 		command.NewMakeMirrorCommand(),
+		command.NewExportCommand(),
+		command.NewImportCommand(),
+		command.NewDiffCommand(),
+		command.NewShellCommand(),
 		command.NewLockCommand(),
 		command.NewElectCommand(),
 		command.NewAuthCommand(),
@@ -45,6 +45,9 @@ func init() {
 		etcdutl.NewVersionCommand(),
 		etcdutl.NewCompletionCommand(),
 		etcdutl.NewMigrateCommand(),
+		etcdutl.NewWALCommand(),
+		etcdutl.NewVerifyCommand(),
+		etcdutl.NewBackupCommand(),
 	)
 }
 
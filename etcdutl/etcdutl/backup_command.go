@@ -0,0 +1,234 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdutl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	"go.etcd.io/etcd/etcdutl/v3/snapshot"
+	"go.etcd.io/etcd/pkg/v3/cobrautl"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3backup"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+)
+
+var (
+	backupOutput     string
+	backupToRevision int64
+	backupToTime     string
+)
+
+// NewBackupCommand returns the cobra command for "backup".
+func NewBackupCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup <subcommand>",
+		Short: "Inspects and restores the continuous backups shipped by the --backup-url of etcd",
+	}
+	cmd.AddCommand(newBackupListCommand())
+	cmd.AddCommand(newBackupSnapshotCommand())
+	return cmd
+}
+
+func newBackupListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list <backup url>",
+		Short: "Lists the base snapshots of a backup and the entries following them",
+		Args:  cobra.ExactArgs(1),
+		Run:   backupListCommandFunc,
+	}
+}
+
+func newBackupSnapshotCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot <backup url> --output {output file} [options]",
+		Short: "Writes a snapshot of a backup at a point in time",
+		Long: `Writes a snapshot of a backup at a point in time, which can be restored like a saved
+snapshot with "snapshot restore".
+
+The latest base snapshot before the point in time is replayed with the raft entries shipped
+after it. With --to-revision, the replay stops at the entry reaching the revision. With
+--to-time, only the entries shipped before the time are replayed: the entries are shipped
+every --backup-interval, which bounds the precision of the point in time.
+`,
+		Args: cobra.ExactArgs(1),
+		Run:  backupSnapshotCommandFunc,
+	}
+	cmd.Flags().StringVar(&backupOutput, "output", "", "Path of the snapshot file, which must not exist")
+	cmd.Flags().Int64Var(&backupToRevision, "to-revision", 0, "Revision of the snapshot (default: latest revision)")
+	cmd.Flags().StringVar(&backupToTime, "to-time", "", "Time of the snapshot, in RFC 3339 format (default: latest shipment)")
+	cmd.MarkFlagRequired("output")
+	cmd.MarkFlagFilename("output")
+	cmd.MarkFlagsMutuallyExclusive("to-revision", "to-time")
+	return cmd
+}
+
+func backupListCommandFunc(_ *cobra.Command, args []string) {
+	sink, err := v3backup.NewSink(GetLogger(), args[0])
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+	}
+	c, err := v3backup.ListCatalog(context.Background(), sink)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	for i := len(c.Bases) - 1; i >= 0; i-- {
+		b := c.Bases[i]
+		fmt.Printf("base at index %d taken at %s", b.Index, b.Time.UTC().Format(time.RFC3339))
+		if segs := c.SegmentsAfter(b, time.Time{}); len(segs) > 0 {
+			last := segs[len(segs)-1]
+			fmt.Printf(", followed by the entries up to index %d shipped at %s", last.Last, last.Time.UTC().Format(time.RFC3339))
+		}
+		fmt.Println()
+	}
+}
+
+func backupSnapshotCommandFunc(_ *cobra.Command, args []string) {
+	var toTime time.Time
+	if backupToTime != "" {
+		var err error
+		if toTime, err = time.Parse(time.RFC3339, backupToTime); err != nil {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("invalid --to-time: %w", err))
+		}
+	}
+	if backupToRevision < 0 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("--to-revision must not be negative"))
+	}
+	lg := GetLogger()
+	sink, err := v3backup.NewSink(lg, args[0])
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+	}
+	res, err := backupSnapshot(context.Background(), lg, sink, backupOutput, backupToRevision, toTime)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	fmt.Printf("Backup snapshot saved at %s: revision %d, index %d (base at index %d)\n", backupOutput, res.Revision, res.Index, res.BaseIndex)
+}
+
+// BackupSnapshotResult describes a snapshot written from a backup.
+type BackupSnapshotResult struct {
+	// BaseIndex is the index of the replayed base snapshot.
+	BaseIndex uint64
+	// Index is the index of the last replayed entry.
+	Index    uint64
+	Revision int64
+}
+
+// backupSnapshot writes to out a snapshot of the backup in sink at toRev, or
+// at the latest revision shipped before toTime. The latest revision is used
+// if both are zero.
+func backupSnapshot(ctx context.Context, lg *zap.Logger, sink v3backup.Sink, out string, toRev int64, toTime time.Time) (res BackupSnapshotResult, err error) {
+	if fileutil.Exist(out) {
+		return res, fmt.Errorf("output file %q already exists", out)
+	}
+	c, err := v3backup.ListCatalog(ctx, sink)
+	if err != nil {
+		return res, err
+	}
+	bases := c.BasesBefore(toTime)
+	if len(bases) == 0 {
+		return res, errors.New("no base snapshot to restore the backup from")
+	}
+
+	partPath := out + ".part"
+	defer func() {
+		if err != nil {
+			os.Remove(partPath)
+		}
+	}()
+	var (
+		base v3backup.Base
+		segs []v3backup.Segment
+		be   backend.Backend
+		r    *replayer
+	)
+	// the bases are tried from the newest, until one is not beyond toRev.
+	for _, b := range bases {
+		os.Remove(partPath)
+		if err = v3backup.ReadBase(ctx, sink, b, partPath); err != nil {
+			return res, err
+		}
+		base, segs = b, c.SegmentsAfter(b, toTime)
+		commit := b.Index
+		if len(segs) > 0 {
+			commit = segs[len(segs)-1].Last
+		}
+		be = backend.NewDefaultBackend(lg, partPath)
+		if r, err = newReplayer(lg, be, pb.Metadata{}, commit); err != nil {
+			be.Close()
+			return res, err
+		}
+		if toRev == 0 || r.rev() <= toRev {
+			break
+		}
+		r.close()
+		be.Close()
+		r = nil
+	}
+	if r == nil {
+		return res, fmt.Errorf("revision %d is older than the oldest base snapshot", toRev)
+	}
+
+	res.BaseIndex, res.Index = base.Index, base.Index
+	err = replaySegments(ctx, sink, r, segs, &res.Index, toRev)
+	res.Revision = r.rev()
+	r.close()
+	if cerr := be.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return res, err
+	}
+	if toRev > res.Revision {
+		return res, fmt.Errorf("revision %d is beyond the last backed up revision %d", toRev, res.Revision)
+	}
+	if err = snapshot.AppendDBHash(partPath); err != nil {
+		return res, err
+	}
+	return res, os.Rename(partPath, out)
+}
+
+// replaySegments replays the entries of segs after index, until the revision
+// reaches toRev if it is not zero. index is updated to the last replayed
+// entry.
+func replaySegments(ctx context.Context, sink v3backup.Sink, r *replayer, segs []v3backup.Segment, index *uint64, toRev int64) error {
+	for _, seg := range segs {
+		ents, err := v3backup.ReadSegment(ctx, sink, seg)
+		if err != nil {
+			return err
+		}
+		for _, e := range ents {
+			if e.Index <= *index {
+				continue
+			}
+			if toRev > 0 && r.rev() >= toRev {
+				return nil
+			}
+			if _, divergence := r.apply(e); divergence != "" {
+				return errors.New(divergence)
+			}
+			*index = e.Index
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdutl
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/etcdutl/v3/snapshot"
+	"go.etcd.io/etcd/server/v3/embed"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3backup"
+)
+
+func TestBackupSnapshot(t *testing.T) {
+	backupURL := "file://" + t.TempDir()
+	cfg := embed.NewConfig()
+	cfg.LogLevel = "fatal"
+	cfg.Dir = t.TempDir()
+	cfg.BackupURL = backupURL
+	cfg.BackupInterval = 10 * time.Millisecond
+	etcd, err := embed.StartEtcd(cfg)
+	require.NoError(t, err)
+	defer etcd.Close()
+	select {
+	case <-etcd.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		t.FailNow()
+	}
+	srv := etcd.Server
+	sink, err := v3backup.NewSink(zaptest.NewLogger(t), backupURL)
+	require.NoError(t, err)
+	// the keys are put after the first base snapshot, to be replayed.
+	require.Eventually(t, func() bool {
+		c, lerr := v3backup.ListCatalog(t.Context(), sink)
+		return lerr == nil && len(c.Bases) > 0
+	}, 10*time.Second, 10*time.Millisecond)
+
+	var revs []int64
+	for _, key := range []string{"a", "b", "c"} {
+		resp, perr := srv.Put(t.Context(), &etcdserverpb.PutRequest{Key: []byte(key), Value: []byte("v")})
+		require.NoError(t, perr)
+		revs = append(revs, resp.Header.Revision)
+	}
+	applied := srv.AppliedIndex()
+	require.Eventually(t, func() bool {
+		c, lerr := v3backup.ListCatalog(t.Context(), sink)
+		if lerr != nil {
+			return false
+		}
+		segs := c.SegmentsAfter(c.Bases[0], time.Time{})
+		return len(segs) > 0 && segs[len(segs)-1].Last >= applied
+	}, 10*time.Second, 10*time.Millisecond)
+
+	lg := zaptest.NewLogger(t)
+	out := filepath.Join(t.TempDir(), "snapshot.db")
+	res, err := backupSnapshot(t.Context(), lg, sink, out, revs[1], time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, revs[1], res.Revision)
+	st, err := snapshot.NewV3(lg).Status(out)
+	require.NoError(t, err)
+	assert.Equal(t, revs[1], st.Revision)
+
+	out = filepath.Join(t.TempDir(), "snapshot.db")
+	res, err = backupSnapshot(t.Context(), lg, sink, out, 0, time.Time{})
+	require.NoError(t, err)
+	assert.Equal(t, revs[2], res.Revision)
+
+	_, err = backupSnapshot(t.Context(), lg, sink, filepath.Join(t.TempDir(), "snapshot.db"), revs[2]+1, time.Time{})
+	require.ErrorContains(t, err, "is beyond the last backed up revision")
+}
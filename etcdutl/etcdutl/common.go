@@ -17,6 +17,7 @@ package etcdutl
 import (
 	"errors"
 
+	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
@@ -24,11 +25,35 @@ import (
 	"go.etcd.io/etcd/pkg/v3/cobrautl"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/snap"
 	"go.etcd.io/etcd/server/v3/storage/datadir"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
 	"go.etcd.io/etcd/server/v3/storage/wal"
 	"go.etcd.io/etcd/server/v3/storage/wal/walpb"
 	"go.etcd.io/raft/v3/raftpb"
 )
 
+var (
+	encryptionKEKFile string
+	encryptionKMSURL  string
+)
+
+// addEncryptionFlags adds the flags of the key encryption keys of the
+// encrypted backends, which are required to read them.
+func addEncryptionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&encryptionKEKFile, "encryption-kek-file", "", "File of the key encryption keys of an encrypted backend, as given to etcd")
+	cmd.Flags().StringVar(&encryptionKMSURL, "encryption-kms-url", "", "URL of the KMS webhook wrapping the data encryption keys of an encrypted backend, as given to etcd")
+	cmd.MarkFlagsMutuallyExclusive("encryption-kek-file", "encryption-kms-url")
+}
+
+// mustKEKProvider returns the provider of the key encryption keys of the
+// flags, nil if they are not set.
+func mustKEKProvider(kekFile, kmsURL string) encryption.KEKProvider {
+	p, err := encryption.NewKEKProvider(kekFile, kmsURL)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+	}
+	return p
+}
+
 func GetLogger() *zap.Logger {
 	config := logutil.DefaultZapLoggerConfig
 	config.Encoding = "console"
@@ -15,11 +15,13 @@
 package etcdutl
 
 import (
+	"context"
+
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
 	"go.etcd.io/etcd/pkg/v3/cobrautl"
-	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
 	"go.etcd.io/etcd/server/v3/storage/mvcc"
 )
 
@@ -34,13 +36,14 @@ func NewHashKVCommand() *cobra.Command {
 		Run:   hashKVCommandFunc,
 	}
 	cmd.Flags().Int64Var(&hashKVRevision, "rev", 0, "maximum revision to hash (default: latest revision)")
+	addEncryptionFlags(cmd)
 	return cmd
 }
 
 func hashKVCommandFunc(cmd *cobra.Command, args []string) {
 	printer := initPrinterFromCmd(cmd)
 
-	ds, err := calculateHashKV(args[0], hashKVRevision)
+	ds, err := calculateHashKV(args[0], hashKVRevision, mustKEKProvider(encryptionKEKFile, encryptionKMSURL))
 	if err != nil {
 		cobrautl.ExitWithError(cobrautl.ExitError, err)
 	}
@@ -53,10 +56,11 @@ type HashKV struct {
 	CompactRevision int64  `json:"compactRevision"`
 }
 
-func calculateHashKV(dbPath string, rev int64) (HashKV, error) {
-	cfg := backend.DefaultBackendConfig(zap.NewNop())
-	cfg.Path = dbPath
-	b := backend.New(cfg)
+func calculateHashKV(dbPath string, rev int64, kek encryption.KEKProvider) (HashKV, error) {
+	b, err := encryption.OpenBackend(context.Background(), zap.NewNop(), dbPath, kek)
+	if err != nil {
+		return HashKV{}, err
+	}
 	st := mvcc.NewStore(zap.NewNop(), b, nil, mvcc.StoreConfig{})
 	hst := mvcc.NewHashStorage(zap.NewNop(), st)
 
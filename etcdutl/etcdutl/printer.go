@@ -30,6 +30,8 @@ var OutputFormat string
 type printer interface {
 	DBStatus(snapshot.Status)
 	DBHashKV(HashKV)
+	WALRecord(WALRecord)
+	Verify(VerifyResult)
 }
 
 func NewPrinter(printerType string) printer {
@@ -64,6 +66,8 @@ func newPrinterUnsupported(n string) printer {
 
 func (p *printerUnsupported) DBStatus(snapshot.Status) { p.p(nil) }
 func (p *printerUnsupported) DBHashKV(HashKV)          { p.p(nil) }
+func (p *printerUnsupported) WALRecord(WALRecord)      { p.p(nil) }
+func (p *printerUnsupported) Verify(VerifyResult)      { p.p(nil) }
 
 func makeDBStatusTable(ds snapshot.Status) (hdr []string, rows [][]string) {
 	hdr = []string{"hash", "revision", "total keys", "total size", "version"}
@@ -87,6 +91,42 @@ func makeDBHashKVTable(ds HashKV) (hdr []string, rows [][]string) {
 	return hdr, rows
 }
 
+func makeWALRecordFields(r WALRecord) []string {
+	fields := []string{fmt.Sprintf("%s:%d", r.File, r.Offset), r.Type}
+	switch r.Type {
+	case "metadata":
+		fields = append(fields, fmt.Sprintf("member=%x", r.MemberID), fmt.Sprintf("cluster=%x", r.ClusterID))
+	case "entry":
+		fields = append(fields, fmt.Sprintf("term=%d", r.Term), fmt.Sprintf("index=%d", r.Index), r.EntryType)
+	case "state":
+		fields = append(fields, fmt.Sprintf("term=%d", r.Term), fmt.Sprintf("commit=%d", r.Index), fmt.Sprintf("vote=%x", r.Vote))
+	case "crc":
+		fields = append(fields, fmt.Sprintf("crc=%08x", r.CRC))
+	case "snapshot":
+		fields = append(fields, fmt.Sprintf("term=%d", r.Term), fmt.Sprintf("index=%d", r.Index))
+	}
+	if r.CRCMismatch {
+		fields = append(fields, "CRC-MISMATCH")
+	}
+	if r.Data != "" {
+		fields = append(fields, r.Data)
+	}
+	return fields
+}
+
+func makeVerifyTable(r VerifyResult) (hdr []string, rows [][]string) {
+	hdr = []string{"consistent index", "wal commit", "replayed entries", "hash", "hash revision", "compact revision"}
+	rows = append(rows, []string{
+		fmt.Sprint(r.ConsistentIndex),
+		fmt.Sprint(r.WALCommit),
+		fmt.Sprint(r.ReplayedEntries),
+		fmt.Sprint(r.Hash),
+		fmt.Sprint(r.HashRevision),
+		fmt.Sprint(r.CompactRevision),
+	})
+	return hdr, rows
+}
+
 func initPrinterFromCmd(cmd *cobra.Command) (p printer) {
 	outputType, err := cmd.Flags().GetString("write-out")
 	if err != nil {
@@ -35,3 +35,19 @@ func (p *fieldsPrinter) DBHashKV(r HashKV) {
 	fmt.Println(`"Hash revision" :`, r.HashRevision)
 	fmt.Println(`"Compact revision" :`, r.CompactRevision)
 }
+
+func (p *fieldsPrinter) Verify(r VerifyResult) {
+	fmt.Println(`"ConsistentIndex" :`, r.ConsistentIndex)
+	fmt.Println(`"ConsistentTerm" :`, r.ConsistentTerm)
+	fmt.Println(`"WALSnapshotIndex" :`, r.WALSnapshotIndex)
+	fmt.Println(`"WALCommit" :`, r.WALCommit)
+	fmt.Println(`"WALTerm" :`, r.WALTerm)
+	fmt.Println(`"WALLastIndex" :`, r.WALLastIndex)
+	fmt.Println(`"ReplayedEntries" :`, r.ReplayedEntries)
+	fmt.Println(`"Hash" :`, r.Hash)
+	fmt.Println(`"Hash revision" :`, r.HashRevision)
+	fmt.Println(`"Compact revision" :`, r.CompactRevision)
+	for _, d := range r.Divergences {
+		fmt.Printf("\"Divergence\" : %q\n", d)
+	}
+}
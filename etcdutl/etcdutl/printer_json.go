@@ -34,6 +34,8 @@ func newJSONPrinter() printer {
 
 func (p *jsonPrinter) DBStatus(r snapshot.Status) { printJSON(r) }
 func (p *jsonPrinter) DBHashKV(r HashKV)          { printJSON(r) }
+func (p *jsonPrinter) WALRecord(r WALRecord)      { printJSON(r) }
+func (p *jsonPrinter) Verify(r VerifyResult)      { printJSON(r) }
 
 // !!! Share ??
 func printJSON(v any) {
@@ -36,3 +36,17 @@ func (s *simplePrinter) DBHashKV(ds HashKV) {
 		fmt.Println(strings.Join(row, ", "))
 	}
 }
+
+func (s *simplePrinter) WALRecord(r WALRecord) {
+	fmt.Println(strings.Join(makeWALRecordFields(r), " "))
+}
+
+func (s *simplePrinter) Verify(r VerifyResult) {
+	_, rows := makeVerifyTable(r)
+	for _, row := range rows {
+		fmt.Println(strings.Join(row, ", "))
+	}
+	for _, d := range r.Divergences {
+		fmt.Println("divergence:", d)
+	}
+}
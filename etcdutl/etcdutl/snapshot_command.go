@@ -15,11 +15,19 @@
 package etcdutl
 
 import (
+	"crypto/ed25519"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"strings"
+	"time"
 
+	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	clientsnapshot "go.etcd.io/etcd/client/v3/snapshot"
 	"go.etcd.io/etcd/etcdutl/v3/snapshot"
 	"go.etcd.io/etcd/pkg/v3/cobrautl"
 	"go.etcd.io/etcd/server/v3/storage/backend"
@@ -42,6 +50,22 @@ var (
 	initialMmapSize     = backend.InitialMmapSize
 	markCompacted       bool
 	revisionBump        uint64
+	restoreResume       bool
+	restoreProgress     bool
+	restoreManifest     string
+	restoreVerifyKey    string
+
+	manifestSignKey string
+
+	trimOutput         string
+	trimRemovePrefixes []string
+	trimDropAuth       bool
+	trimLeaseTTL       int64
+	trimSkipHashCheck  bool
+
+	exportFormat        string
+	exportOutput        string
+	exportSkipHashCheck bool
 )
 
 // NewSnapshotCommand returns the cobra command for "snapshot".
@@ -52,6 +76,10 @@ func NewSnapshotCommand() *cobra.Command {
 	}
 	cmd.AddCommand(NewSnapshotRestoreCommand())
 	cmd.AddCommand(newSnapshotStatusCommand())
+	cmd.AddCommand(newSnapshotTrimCommand())
+	cmd.AddCommand(newSnapshotExportCommand())
+	cmd.AddCommand(newSnapshotImportCommand())
+	cmd.AddCommand(newSnapshotManifestCommand())
 	return cmd
 }
 
@@ -82,13 +110,176 @@ func NewSnapshotRestoreCommand() *cobra.Command {
 	cmd.Flags().Uint64Var(&initialMmapSize, "initial-memory-map-size", initialMmapSize, "Initial memory map size of the database in bytes. It uses the default value if not defined or defined to 0")
 	cmd.Flags().Uint64Var(&revisionBump, "bump-revision", 0, "How much to increase the latest revision after restore")
 	cmd.Flags().BoolVar(&markCompacted, "mark-compacted", false, "Mark the latest revision after restore as the point of scheduled compaction (required if --bump-revision > 0, disallowed otherwise)")
+	cmd.Flags().BoolVar(&restoreResume, "resume", false, "Resume the restore interrupted in the data directory rather than failing on it not being empty")
+	cmd.Flags().BoolVar(&restoreProgress, "progress", false, "Report the progress of the restore on stderr, as JSON lines with --write-out=json")
+	cmd.Flags().StringVar(&restoreManifest, "manifest", "", "Path of the manifest the snapshot is verified against (default: the snapshot path with a .manifest suffix, if it exists)")
+	cmd.Flags().StringVar(&restoreVerifyKey, "verify-key", "", "Path of the PEM ed25519 public key the manifest must be signed with")
+	addEncryptionFlags(cmd)
 
 	cmd.MarkFlagDirname("data-dir")
 	cmd.MarkFlagDirname("wal-dir")
+	cmd.MarkFlagFilename("manifest")
+	cmd.MarkFlagFilename("verify-key")
+
+	return cmd
+}
+
+func newSnapshotTrimCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "trim <filename> --output {output file} [options]",
+		Short: "Writes a copy of a snapshot without some keys, the auth data or the lease TTLs",
+		Long: `Writes a copy of a snapshot without the keys of the given prefixes, without the users, roles
+and revoked tokens, or with the lease TTLs reset. The copy is defragmented and can be restored
+like a saved snapshot; the revision of the restored cluster does not go backwards even if the
+latest revision is removed.
+`,
+		Args: cobra.ExactArgs(1),
+		Run:  snapshotTrimCommandFunc,
+	}
+	cmd.Flags().StringVar(&trimOutput, "output", "", "Path of the trimmed snapshot file, which must not exist")
+	cmd.Flags().StringArrayVar(&trimRemovePrefixes, "remove-prefix", nil, "Prefix of the keys to remove with all their revisions, can be repeated")
+	cmd.Flags().BoolVar(&trimDropAuth, "drop-auth", false, "Disable authentication and remove the users, roles and revoked tokens")
+	cmd.Flags().Int64Var(&trimLeaseTTL, "reset-lease-ttl", 0, "Reset the TTL of all the leases to the given number of seconds, leases are kept as they are if 0")
+	cmd.Flags().BoolVar(&trimSkipHashCheck, "skip-hash-check", false, "Ignore snapshot integrity hash value (required if copied from data directory)")
+	addEncryptionFlags(cmd)
+	cmd.MarkFlagRequired("output")
+	cmd.MarkFlagFilename("output")
+	return cmd
+}
+
+func snapshotTrimCommandFunc(_ *cobra.Command, args []string) {
+	for _, prefix := range trimRemovePrefixes {
+		if prefix == "" {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("--remove-prefix must not be empty"))
+		}
+	}
+
+	lg := GetLogger()
+	sp := snapshot.NewV3(lg)
+	res, err := sp.Trim(snapshot.TrimConfig{
+		SnapshotPath:   args[0],
+		OutputPath:     trimOutput,
+		SkipHashCheck:  trimSkipHashCheck,
+		RemovePrefixes: trimRemovePrefixes,
+		DropAuth:       trimDropAuth,
+		LeaseTTL:       trimLeaseTTL,
+		KEKProvider:    mustKEKProvider(encryptionKEKFile, encryptionKMSURL),
+	})
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	fmt.Printf("Trimmed snapshot saved at %s: removed %d key revisions, reset %d leases\n", trimOutput, res.RemovedRevisions, res.ResetLeases)
+}
 
+func newSnapshotExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export <filename> --output {output file} [options]",
+		Short: "Exports the keys, leases and auth data of a snapshot to a portable format",
+		Long: `Exports the latest key-values, the leases and the auth data of a snapshot to a format
+independent of the storage schema, so that they can be inspected with standard tooling or
+imported by another etcd version with "snapshot import". The history of the keys is not
+exported.
+
+The json format writes a header line with the format version and the revision of the
+snapshot, followed by a JSON object per auth state, role, user, lease and key. Keys and
+values are base64 encoded.
+`,
+		Args: cobra.ExactArgs(1),
+		Run:  snapshotExportCommandFunc,
+	}
+	cmd.Flags().StringVar(&exportFormat, "format", snapshot.FormatJSON, "Format of the exported file (json)")
+	cmd.Flags().StringVar(&exportOutput, "output", "", "Path of the exported file, which must not exist")
+	cmd.Flags().BoolVar(&exportSkipHashCheck, "skip-hash-check", false, "Ignore snapshot integrity hash value (required if copied from data directory)")
+	addEncryptionFlags(cmd)
+	cmd.MarkFlagRequired("output")
+	cmd.MarkFlagFilename("output")
 	return cmd
 }
 
+func newSnapshotImportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <filename> --output {output file} [options]",
+		Short: "Writes a snapshot from a file written by snapshot export",
+		Long: `Writes a snapshot with the storage schema of this etcd version from a file written by
+"snapshot export". The keys keep their revisions, and the latest revision is marked
+compacted as the history of the keys is not exported. The snapshot can be restored like
+a saved snapshot.
+`,
+		Args: cobra.ExactArgs(1),
+		Run:  snapshotImportCommandFunc,
+	}
+	cmd.Flags().StringVar(&exportFormat, "format", snapshot.FormatJSON, "Format of the imported file (json)")
+	cmd.Flags().StringVar(&exportOutput, "output", "", "Path of the snapshot file, which must not exist")
+	cmd.MarkFlagRequired("output")
+	cmd.MarkFlagFilename("output")
+	return cmd
+}
+
+func newSnapshotManifestCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "manifest <filename> [options]",
+		Short: "Writes the integrity manifest of a snapshot",
+		Long: `Writes the manifest of a snapshot to the snapshot path with a .manifest suffix, as
+"etcdctl snapshot save --manifest" does, for the snapshots saved without it. The manifest
+holds the sha256 hash of each chunk of the snapshot, its revision and its storage version,
+and is checked by "snapshot restore" to detect a truncated or modified snapshot. It is
+signed with an ed25519 key if --sign-key is set.
+`,
+		Args: cobra.ExactArgs(1),
+		Run:  snapshotManifestCommandFunc,
+	}
+	cmd.Flags().StringVar(&manifestSignKey, "sign-key", "", "Path of the PEM ed25519 private key the manifest is signed with")
+	cmd.MarkFlagFilename("sign-key")
+	return cmd
+}
+
+func snapshotManifestCommandFunc(_ *cobra.Command, args []string) {
+	var key ed25519.PrivateKey
+	if manifestSignKey != "" {
+		var err error
+		if key, err = clientsnapshot.ReadSigningKey(manifestSignKey); err != nil {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+		}
+	}
+	lg := GetLogger()
+	sp := snapshot.NewV3(lg)
+	m, err := sp.WriteManifest(args[0], key)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	fmt.Printf("Manifest saved at %s: revision %d, %d chunks, signed %t\n", args[0]+clientsnapshot.ManifestSuffix, m.Revision, len(m.Chunks), m.Signature != "")
+}
+
+func snapshotExportCommandFunc(_ *cobra.Command, args []string) {
+	lg := GetLogger()
+	sp := snapshot.NewV3(lg)
+	res, err := sp.Export(snapshot.ExportConfig{
+		SnapshotPath:  args[0],
+		OutputPath:    exportOutput,
+		Format:        exportFormat,
+		SkipHashCheck: exportSkipHashCheck,
+		KEKProvider:   mustKEKProvider(encryptionKEKFile, encryptionKMSURL),
+	})
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	fmt.Printf("Snapshot exported at %s: revision %d, %d keys, %d leases, %d users, %d roles\n", exportOutput, res.Revision, res.Keys, res.Leases, res.Users, res.Roles)
+}
+
+func snapshotImportCommandFunc(_ *cobra.Command, args []string) {
+	lg := GetLogger()
+	sp := snapshot.NewV3(lg)
+	res, err := sp.Import(snapshot.ImportConfig{
+		InputPath:  args[0],
+		OutputPath: exportOutput,
+		Format:     exportFormat,
+	})
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	fmt.Printf("Snapshot imported at %s: revision %d, %d keys, %d leases, %d users, %d roles\n", exportOutput, res.Revision, res.Keys, res.Leases, res.Users, res.Roles)
+}
+
 func SnapshotStatusCommandFunc(cmd *cobra.Command, args []string) {
 	if len(args) != 1 {
 		err := fmt.Errorf("snapshot status requires exactly one argument")
@@ -107,7 +298,8 @@ func SnapshotStatusCommandFunc(cmd *cobra.Command, args []string) {
 
 func snapshotRestoreCommandFunc(_ *cobra.Command, args []string) {
 	SnapshotRestoreCommandFunc(restoreCluster, restoreClusterToken, restoreDataDir, restoreWALDir,
-		restorePeerURLs, restoreName, skipHashCheck, initialMmapSize, revisionBump, markCompacted, args)
+		restorePeerURLs, restoreName, skipHashCheck, initialMmapSize, revisionBump, markCompacted, restoreResume, restoreProgress,
+		restoreManifest, restoreVerifyKey, encryptionKEKFile, encryptionKMSURL, args)
 }
 
 func SnapshotRestoreCommandFunc(restoreCluster string,
@@ -120,6 +312,12 @@ func SnapshotRestoreCommandFunc(restoreCluster string,
 	initialMmapSize uint64,
 	revisionBump uint64,
 	markCompacted bool,
+	resume bool,
+	progress bool,
+	manifestPath string,
+	verifyKeyPath string,
+	kekFile string,
+	kmsURL string,
 	args []string,
 ) {
 	if len(args) != 1 {
@@ -142,9 +340,24 @@ func SnapshotRestoreCommandFunc(restoreCluster string,
 		walDir = datadir.ToWALDir(dataDir)
 	}
 
+	if manifestPath == "" && fileutil.Exist(args[0]+clientsnapshot.ManifestSuffix) {
+		manifestPath = args[0] + clientsnapshot.ManifestSuffix
+	}
+	var verifyKey ed25519.PublicKey
+	if verifyKeyPath != "" {
+		var err error
+		if verifyKey, err = clientsnapshot.ReadVerifyKey(verifyKeyPath); err != nil {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+		}
+	}
+
 	lg := GetLogger()
 	sp := snapshot.NewV3(lg)
 
+	var report func(snapshot.RestoreProgress)
+	if progress {
+		report = printRestoreProgress(os.Stderr, OutputFormat)
+	}
 	if err := sp.Restore(snapshot.RestoreConfig{
 		SnapshotPath:        args[0],
 		Name:                restoreName,
@@ -154,9 +367,14 @@ func SnapshotRestoreCommandFunc(restoreCluster string,
 		InitialCluster:      restoreCluster,
 		InitialClusterToken: restoreClusterToken,
 		SkipHashCheck:       skipHashCheck,
+		ManifestPath:        manifestPath,
+		VerifyKey:           verifyKey,
 		InitialMmapSize:     initialMmapSize,
 		RevisionBump:        revisionBump,
 		MarkCompacted:       markCompacted,
+		Resume:              resume,
+		Progress:            report,
+		KEKProvider:         mustKEKProvider(kekFile, kmsURL),
 	}); err != nil {
 		cobrautl.ExitWithError(cobrautl.ExitError, err)
 	}
@@ -169,3 +387,33 @@ func initialClusterFromName(name string) string {
 	}
 	return fmt.Sprintf("%s=http://localhost:2380", n)
 }
+
+// printRestoreProgress returns a function printing the progress of a restore
+// to w, as JSON lines if format is "json".
+func printRestoreProgress(w io.Writer, format string) func(snapshot.RestoreProgress) {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		return func(p snapshot.RestoreProgress) {
+			enc.Encode(struct {
+				Phase          string  `json:"phase"`
+				BytesWritten   int64   `json:"bytesWritten"`
+				TotalBytes     int64   `json:"totalBytes"`
+				KeysProcessed  int64   `json:"keysProcessed"`
+				ElapsedSeconds float64 `json:"elapsedSeconds"`
+				ETASeconds     float64 `json:"etaSeconds"`
+			}{p.Phase, p.BytesWritten, p.TotalBytes, p.KeysProcessed, p.Elapsed.Seconds(), p.ETA.Seconds()})
+		}
+	}
+	return func(p snapshot.RestoreProgress) {
+		switch p.Phase {
+		case snapshot.RestorePhaseKeys:
+			fmt.Fprintf(w, "%s: %d keys, elapsed %v\n", p.Phase, p.KeysProcessed, p.Elapsed.Round(time.Second))
+		case snapshot.RestorePhaseDone:
+			fmt.Fprintf(w, "%s: elapsed %v\n", p.Phase, p.Elapsed.Round(time.Second))
+		default:
+			fmt.Fprintf(w, "%s: %s / %s (%.0f%%), elapsed %v, ETA %v\n", p.Phase,
+				humanize.Bytes(uint64(p.BytesWritten)), humanize.Bytes(uint64(p.TotalBytes)),
+				100*float64(p.BytesWritten)/float64(max(p.TotalBytes, 1)), p.Elapsed.Round(time.Second), p.ETA)
+		}
+	}
+}
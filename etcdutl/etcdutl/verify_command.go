@@ -0,0 +1,354 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdutl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	"go.etcd.io/etcd/client/pkg/v3/types"
+	"go.etcd.io/etcd/pkg/v3/cobrautl"
+	"go.etcd.io/etcd/pkg/v3/pbutil"
+	"go.etcd.io/etcd/server/v3/auth"
+	"go.etcd.io/etcd/server/v3/etcdserver/api"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3alarm"
+	"go.etcd.io/etcd/server/v3/etcdserver/apply"
+	"go.etcd.io/etcd/server/v3/etcdserver/cindex"
+	"go.etcd.io/etcd/server/v3/lease"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/datadir"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+	"go.etcd.io/etcd/server/v3/storage/wal"
+	"go.etcd.io/raft/v3/raftpb"
+)
+
+var (
+	verifyDataDir      string
+	verifyRevision     int64
+	verifyExpectedHash uint32
+)
+
+// NewVerifyCommand returns the cobra command for "verify".
+func NewVerifyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verifies the consistency of the data directory of a stopped member",
+		Long: `Verifies the consistency of the data directory of a stopped member before it rejoins
+its cluster.
+
+The consistent index of the backend is cross-checked with the WAL. The committed WAL
+entries not yet applied to the backend are then replayed, like the member does when it
+starts, on a copy of the backend, and the KV hash of the result is computed. The data
+directory is not modified.
+
+The hash can be compared to the one a healthy member reports for the same revision
+with "etcdctl endpoint hashkv --rev", by passing it to --expected-hash.
+
+The command exits with an error after printing the report if a divergence is found.
+`,
+		Args: cobra.NoArgs,
+		Run:  verifyCommandFunc,
+	}
+	cmd.Flags().StringVar(&verifyDataDir, "data-dir", "", "Path to the etcd data dir")
+	cmd.MarkFlagRequired("data-dir")
+	cmd.MarkFlagDirname("data-dir")
+	cmd.Flags().Int64Var(&verifyRevision, "rev", 0, "Maximum revision to hash (default: latest revision)")
+	cmd.Flags().Uint32Var(&verifyExpectedHash, "expected-hash", 0, "KV hash expected at the hashed revision, not checked if 0")
+	addEncryptionFlags(cmd)
+	return cmd
+}
+
+func verifyCommandFunc(cmd *cobra.Command, _ []string) {
+	printer := initPrinterFromCmd(cmd)
+
+	r, err := verifyDataDirectory(GetLogger(), verifyDataDir, verifyRevision, mustKEKProvider(encryptionKEKFile, encryptionKMSURL))
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	if verifyExpectedHash != 0 && r.Hash != verifyExpectedHash {
+		r.Divergences = append(r.Divergences, fmt.Sprintf("KV hash %d at revision %d differs from the expected hash %d", r.Hash, r.HashRevision, verifyExpectedHash))
+	}
+	printer.Verify(r)
+	if len(r.Divergences) > 0 {
+		cobrautl.ExitWithError(cobrautl.ExitError, fmt.Errorf("found %d divergences in %s", len(r.Divergences), verifyDataDir))
+	}
+}
+
+// VerifyResult is the report of the verification of a data directory.
+type VerifyResult struct {
+	// ConsistentIndex and ConsistentTerm are read from the backend before the
+	// replay.
+	ConsistentIndex uint64 `json:"consistentIndex"`
+	ConsistentTerm  uint64 `json:"consistentTerm"`
+
+	WALSnapshotIndex uint64 `json:"walSnapshotIndex"`
+	WALCommit        uint64 `json:"walCommit"`
+	WALTerm          uint64 `json:"walTerm"`
+	WALLastIndex     uint64 `json:"walLastIndex"`
+
+	// ReplayedEntries is the number of committed WAL entries replayed on the
+	// backend.
+	ReplayedEntries int `json:"replayedEntries"`
+
+	Hash            uint32 `json:"hash"`
+	HashRevision    int64  `json:"hashRevision"`
+	CompactRevision int64  `json:"compactRevision"`
+
+	Divergences []string `json:"divergences,omitempty"`
+}
+
+// verifyDataDirectory cross-checks the backend of dataDir with its WAL,
+// replays the committed WAL entries the backend lacks on a copy of the
+// backend, and hashes the KV history of the copy up to rev. The KEKs of kek
+// decrypt the backend and the WAL if they are encrypted.
+func verifyDataDirectory(lg *zap.Logger, dataDir string, rev int64, kek encryption.KEKProvider) (r VerifyResult, err error) {
+	dbPath := datadir.ToBackendFileName(dataDir)
+	if !fileutil.Exist(dbPath) {
+		return r, fmt.Errorf("backend %q does not exist", dbPath)
+	}
+	walSnap, err := getLatestWALSnap(lg, dataDir)
+	if err != nil {
+		return r, fmt.Errorf("failed to get the latest snapshot: %w", err)
+	}
+	w, err := wal.OpenForRead(lg, datadir.ToWALDir(dataDir), walSnap)
+	if err != nil {
+		return r, fmt.Errorf("failed to open wal: %w", err)
+	}
+	if kek != nil {
+		w.SetEncryption(kek)
+	}
+	metadata, hardState, ents, walErr := w.ReadAll()
+	w.Close()
+	r.WALSnapshotIndex, r.WALCommit, r.WALTerm = walSnap.Index, hardState.Commit, hardState.Term
+	r.WALLastIndex = walSnap.Index
+	if len(ents) > 0 {
+		r.WALLastIndex = ents[len(ents)-1].Index
+	}
+
+	tmpDir, err := os.MkdirTemp("", "etcdutl-verify")
+	if err != nil {
+		return r, err
+	}
+	defer os.RemoveAll(tmpDir)
+	tmpDBPath := filepath.Join(tmpDir, "db")
+	if err = copyFile(dbPath, tmpDBPath); err != nil {
+		return r, err
+	}
+	be, err := encryption.OpenBackend(context.Background(), lg, tmpDBPath, kek)
+	if err != nil {
+		return r, err
+	}
+	defer be.Close()
+	r.ConsistentIndex, r.ConsistentTerm = schema.ReadConsistentIndex(be.ReadTx())
+
+	replay := true
+	if walErr != nil {
+		r.Divergences = append(r.Divergences, fmt.Sprintf("failed to read wal: %v", walErr))
+		replay = false
+	}
+	if r.ConsistentIndex > r.WALCommit {
+		r.Divergences = append(r.Divergences, fmt.Sprintf("backend consistent index %d is ahead of the WAL commit index %d", r.ConsistentIndex, r.WALCommit))
+	}
+	if r.ConsistentTerm > r.WALTerm {
+		r.Divergences = append(r.Divergences, fmt.Sprintf("backend term %d is ahead of the WAL term %d", r.ConsistentTerm, r.WALTerm))
+	}
+	if r.ConsistentIndex < r.WALSnapshotIndex {
+		// the entries between the consistent index and the snapshot are no
+		// longer in the WAL.
+		r.Divergences = append(r.Divergences, fmt.Sprintf("backend consistent index %d is behind the WAL snapshot index %d", r.ConsistentIndex, r.WALSnapshotIndex))
+		replay = false
+	}
+	if walErr == nil && r.WALCommit > r.WALLastIndex {
+		r.Divergences = append(r.Divergences, fmt.Sprintf("WAL commit index %d is beyond the last WAL entry %d", r.WALCommit, r.WALLastIndex))
+	}
+
+	if replay {
+		var md pb.Metadata
+		pbutil.MustUnmarshal(&md, metadata)
+		var divergences []string
+		r.ReplayedEntries, divergences, err = replayEntries(lg, be, md, r.ConsistentIndex, r.WALCommit, ents)
+		if err != nil {
+			return r, err
+		}
+		r.Divergences = append(r.Divergences, divergences...)
+	}
+
+	// the lessor is needed to attach the keys to their leases.
+	le := lease.NewLessor(lg, be, nil, lease.LessorConfig{})
+	defer le.Stop()
+	st := mvcc.NewStore(lg, be, le, mvcc.StoreConfig{})
+	defer st.Close()
+	h, _, err := mvcc.NewHashStorage(lg, st).HashByRev(rev)
+	if err != nil {
+		return r, fmt.Errorf("failed to hash the KV history: %w", err)
+	}
+	r.Hash, r.HashRevision, r.CompactRevision = h.Hash, h.Revision, h.CompactRevision
+	return r, nil
+}
+
+// replayEntries applies the normal entries of ents after index and up to
+// commit to be, with the applier of the server. Configuration changes do not
+// affect the KV history and are skipped. It returns the number of replayed
+// entries, and the divergences of the entries that cannot be decoded.
+func replayEntries(lg *zap.Logger, be backend.Backend, md pb.Metadata, index, commit uint64, ents []raftpb.Entry) (replayed int, divergences []string, err error) {
+	r, err := newReplayer(lg, be, md, commit)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer r.close()
+	for _, e := range ents {
+		if e.Index <= index || e.Index > commit {
+			continue
+		}
+		ok, divergence := r.apply(e)
+		if divergence != "" {
+			divergences = append(divergences, divergence)
+		}
+		if ok {
+			replayed++
+		}
+	}
+	return replayed, divergences, nil
+}
+
+// replayer applies raft entries to a backend with the applier of the server.
+type replayer struct {
+	be     backend.Backend
+	as     auth.AuthStore
+	le     lease.Lessor
+	kv     mvcc.KV
+	ci     cindex.ConsistentIndexer
+	ua     apply.UberApplier
+	status *replayStatus
+	physc  []<-chan struct{}
+}
+
+func newReplayer(lg *zap.Logger, be backend.Backend, md pb.Metadata, commit uint64) (*replayer, error) {
+	cl := membership.NewCluster(lg)
+	cl.SetID(types.ID(md.NodeID), types.ID(md.ClusterID))
+	cl.SetBackend(schema.NewMembershipBackend(lg, be))
+	cl.Recover(api.UpdateCapability)
+
+	alarms, err := v3alarm.NewAlarmStore(lg, schema.NewAlarmBackend(lg, be))
+	if err != nil {
+		return nil, err
+	}
+	tp, err := auth.NewTokenProvider(lg, "", nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	r := &replayer{be: be, status: &replayStatus{memberID: types.ID(md.NodeID), commit: commit}}
+	r.as = auth.NewAuthStore(lg, schema.NewAuthBackend(lg, be), tp, 0)
+	// always recover lessor before kv, so that the keys are attached to the
+	// recovered leases.
+	r.le = lease.NewLessor(lg, be, cl, lease.LessorConfig{})
+	r.kv = mvcc.NewStore(lg, be, r.le, mvcc.StoreConfig{})
+	r.ci = cindex.NewConsistentIndex(be)
+	r.ua = apply.NewUberApplier(apply.ApplierOptions{
+		Logger:                       lg,
+		KV:                           r.kv,
+		AlarmStore:                   alarms,
+		AuthStore:                    r.as,
+		Lessor:                       r.le,
+		Cluster:                      cl,
+		RaftStatus:                   r.status,
+		SnapshotServer:               r.status,
+		ConsistentIndex:              r.ci,
+		TxnModeWriteWithSharedBuffer: true,
+		Backend:                      be,
+	})
+	return r, nil
+}
+
+// apply applies e if it is a normal entry. Configuration changes do not
+// affect the KV history and are skipped. It returns whether e was applied,
+// and the divergence of e if it cannot be decoded.
+func (r *replayer) apply(e raftpb.Entry) (bool, string) {
+	if e.Type != raftpb.EntryNormal || len(e.Data) == 0 {
+		return false, ""
+	}
+	var req pb.InternalRaftRequest
+	if !pbutil.MaybeUnmarshal(&req, e.Data) {
+		return false, fmt.Sprintf("cannot decode the request of the WAL entry %d", e.Index)
+	}
+	if req.V2 != nil {
+		// v2 requests only update the membership of the cluster.
+		return false, ""
+	}
+	r.status.applied, r.status.term = e.Index, e.Term
+	r.ci.SetConsistentIndex(e.Index, e.Term)
+	if ar := r.ua.Apply(context.Background(), &req, membership.ApplyBoth); ar != nil && ar.Physc != nil {
+		r.physc = append(r.physc, ar.Physc)
+	}
+	return true, ""
+}
+
+// rev returns the current revision of the replayed KV.
+func (r *replayer) rev() int64 { return r.kv.Rev() }
+
+// close waits for the compactions to finish and commits the backend.
+func (r *replayer) close() {
+	for _, c := range r.physc {
+		<-c
+	}
+	r.be.ForceCommit()
+	r.kv.Close()
+	r.le.Stop()
+	r.as.Close()
+}
+
+// replayStatus stands for the raft status of the member while its WAL is
+// replayed.
+type replayStatus struct {
+	memberID types.ID
+	commit   uint64
+	applied  uint64
+	term     uint64
+}
+
+func (s *replayStatus) MemberID() types.ID     { return s.memberID }
+func (s *replayStatus) Leader() types.ID       { return types.ID(0) }
+func (s *replayStatus) CommittedIndex() uint64 { return s.commit }
+func (s *replayStatus) AppliedIndex() uint64   { return s.applied }
+func (s *replayStatus) Term() uint64           { return s.term }
+func (s *replayStatus) ForceSnapshot()         {}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fileutil.PrivateFileMode)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdutl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/embed"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/datadir"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+func runEtcd(t *testing.T, dataDir string, f func(*etcdserver.EtcdServer)) {
+	t.Helper()
+	cfg := embed.NewConfig()
+	cfg.LogLevel = "fatal"
+	cfg.Dir = dataDir
+	etcd, err := embed.StartEtcd(cfg)
+	require.NoError(t, err)
+	defer etcd.Close()
+	select {
+	case <-etcd.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		t.FailNow()
+	}
+	f(etcd.Server)
+}
+
+func putKeys(t *testing.T, keys ...string) func(*etcdserver.EtcdServer) {
+	return func(srv *etcdserver.EtcdServer) {
+		for _, key := range keys {
+			_, err := srv.Put(t.Context(), &etcdserverpb.PutRequest{Key: []byte(key), Value: []byte("v")})
+			require.NoError(t, err)
+		}
+	}
+}
+
+func TestVerifyDataDirectory(t *testing.T) {
+	dataDir := t.TempDir()
+	runEtcd(t, dataDir, putKeys(t, "a", "b"))
+	dbPath := datadir.ToBackendFileName(dataDir)
+	stale := filepath.Join(t.TempDir(), "db")
+	require.NoError(t, copyFile(dbPath, stale))
+	runEtcd(t, dataDir, func(srv *etcdserver.EtcdServer) {
+		putKeys(t, "c", "a")(srv)
+		lresp, err := srv.LeaseGrant(t.Context(), &etcdserverpb.LeaseGrantRequest{TTL: 60})
+		require.NoError(t, err)
+		_, err = srv.Put(t.Context(), &etcdserverpb.PutRequest{Key: []byte("d"), Value: []byte("v"), Lease: lresp.ID})
+		require.NoError(t, err)
+	})
+
+	r, err := verifyDataDirectory(zaptest.NewLogger(t), dataDir, 0, nil)
+	require.NoError(t, err)
+	assert.Empty(t, r.Divergences)
+	assert.Equal(t, r.WALCommit, r.ConsistentIndex)
+	assert.Zero(t, r.ReplayedEntries)
+	want := HashKV{Hash: r.Hash, HashRevision: r.HashRevision, CompactRevision: r.CompactRevision}
+
+	// the stale backend lacks the entries applied after the first restart.
+	require.NoError(t, os.Remove(dbPath))
+	require.NoError(t, copyFile(stale, dbPath))
+	r, err = verifyDataDirectory(zaptest.NewLogger(t), dataDir, 0, nil)
+	require.NoError(t, err)
+	assert.Empty(t, r.Divergences)
+	assert.Less(t, r.ConsistentIndex, r.WALCommit)
+	assert.GreaterOrEqual(t, r.ReplayedEntries, 4)
+	assert.Equal(t, want, HashKV{Hash: r.Hash, HashRevision: r.HashRevision, CompactRevision: r.CompactRevision})
+
+	be := backend.NewDefaultBackend(zaptest.NewLogger(t), dbPath)
+	tx := be.BatchTx()
+	tx.LockOutsideApply()
+	schema.UnsafeUpdateConsistentIndexForce(tx, r.WALCommit+1, r.WALTerm)
+	tx.Unlock()
+	be.ForceCommit()
+	require.NoError(t, be.Close())
+	r, err = verifyDataDirectory(zaptest.NewLogger(t), dataDir, 0, nil)
+	require.NoError(t, err)
+	require.Len(t, r.Divergences, 1)
+	assert.Contains(t, r.Divergences[0], "is ahead of the WAL commit index")
+}
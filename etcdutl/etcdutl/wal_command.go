@@ -0,0 +1,316 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdutl
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	"go.etcd.io/etcd/pkg/v3/cobrautl"
+	"go.etcd.io/etcd/server/v3/storage/datadir"
+	"go.etcd.io/etcd/server/v3/storage/wal"
+	"go.etcd.io/etcd/server/v3/storage/wal/walpb"
+	"go.etcd.io/raft/v3/raftpb"
+)
+
+var (
+	walRecordTypes = map[int64]string{
+		wal.MetadataType: "metadata",
+		wal.EntryType:    "entry",
+		wal.StateType:    "state",
+		wal.CrcType:      "crc",
+		wal.SnapshotType: "snapshot",
+	}
+	walRecordTypeNames = []string{"metadata", "entry", "state", "crc", "snapshot"}
+)
+
+var (
+	walDumpTypes      []string
+	walDumpStartIndex uint64
+	walDumpEndIndex   uint64
+	walDumpTerm       uint64
+)
+
+// NewWALCommand returns the cobra command for "wal".
+func NewWALCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wal <subcommand>",
+		Short: "Inspects the write-ahead log of an etcd member",
+	}
+	cmd.AddCommand(newWALDumpCommand())
+	return cmd
+}
+
+func newWALDumpCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dump <dir>",
+		Short: "Decodes and verifies the records of a WAL directory",
+		Long: `Decodes the records of the WAL files of a data directory or of a WAL directory, and
+verifies their CRCs. The CRC of a record is chained to the CRC of the previous record,
+so the records following a corrupted record of a WAL file fail verification too.
+
+Entries, snapshots and hard states are filtered by --start-index, --end-index and
+--term; the index of a hard state is its commit index. Metadata and CRC records are
+only filtered by --type.
+
+The command exits with an error after printing the records if a record fails CRC
+verification or the WAL is truncated.
+`,
+		Args: cobra.ExactArgs(1),
+		Run:  walDumpCommandFunc,
+	}
+	cmd.Flags().StringSliceVar(&walDumpTypes, "type", nil, "Record types to print (metadata, entry, state, crc, snapshot), all if empty")
+	cmd.Flags().Uint64Var(&walDumpStartIndex, "start-index", 0, "Minimum index of the records to print")
+	cmd.Flags().Uint64Var(&walDumpEndIndex, "end-index", 0, "Maximum index of the records to print, unbounded if 0")
+	cmd.Flags().Uint64Var(&walDumpTerm, "term", 0, "Term of the records to print, any term if 0")
+	return cmd
+}
+
+func walDumpCommandFunc(cmd *cobra.Command, args []string) {
+	for _, t := range walDumpTypes {
+		if !slices.Contains(walRecordTypeNames, t) {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("unknown --type %q, expected one of %v", t, walRecordTypeNames))
+		}
+	}
+	if walDumpEndIndex != 0 && walDumpEndIndex < walDumpStartIndex {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, errors.New("--end-index must not be lower than --start-index"))
+	}
+	printer := initPrinterFromCmd(cmd)
+
+	f := walDumpFilter{types: walDumpTypes, startIndex: walDumpStartIndex, endIndex: walDumpEndIndex, term: walDumpTerm}
+	sum, err := dumpWAL(walDirOf(args[0]), f, printer.WALRecord)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	if sum.CRCMismatches > 0 {
+		cobrautl.ExitWithError(cobrautl.ExitError, fmt.Errorf("%d of %d records failed CRC verification", sum.CRCMismatches, sum.Records))
+	}
+	if sum.Truncated != "" {
+		cobrautl.ExitWithError(cobrautl.ExitError, errors.New(sum.Truncated))
+	}
+}
+
+// walDirOf returns the WAL directory of dir if it's a data directory, and dir
+// otherwise.
+func walDirOf(dir string) string {
+	if walDir := datadir.ToWALDir(dir); wal.Exist(walDir) {
+		return walDir
+	}
+	return dir
+}
+
+// WALRecord is a decoded record of a WAL file.
+type WALRecord struct {
+	File   string `json:"file"`
+	Offset int64  `json:"offset"`
+	Type   string `json:"type"`
+
+	// Term and Index are set for entries, snapshots and hard states.
+	Term  uint64 `json:"term,omitempty"`
+	Index uint64 `json:"index,omitempty"`
+	// EntryType and Data are set for entries. Data is the request or the
+	// configuration change of the entry in the protobuf text format.
+	EntryType string `json:"entryType,omitempty"`
+	Data      string `json:"data,omitempty"`
+	// Vote is set for hard states.
+	Vote uint64 `json:"vote,omitempty"`
+	// MemberID and ClusterID are set for metadata.
+	MemberID  uint64 `json:"memberID,omitempty"`
+	ClusterID uint64 `json:"clusterID,omitempty"`
+	// CRC is set for CRC records.
+	CRC uint32 `json:"crc,omitempty"`
+
+	CRCMismatch bool `json:"crcMismatch,omitempty"`
+}
+
+type walDumpFilter struct {
+	types      []string
+	startIndex uint64
+	endIndex   uint64
+	term       uint64
+}
+
+func (f walDumpFilter) match(r WALRecord) bool {
+	if len(f.types) > 0 && !slices.Contains(f.types, r.Type) {
+		return false
+	}
+	switch r.Type {
+	case "metadata", "crc":
+		return true
+	}
+	if r.Index < f.startIndex || (f.endIndex != 0 && r.Index > f.endIndex) {
+		return false
+	}
+	return f.term == 0 || r.Term == f.term
+}
+
+// walDumpSummary counts the records of a WAL.
+type walDumpSummary struct {
+	Records       int
+	CRCMismatches int
+	// Truncated describes the record cut short at the end of a WAL file, if
+	// any.
+	Truncated string
+}
+
+// dumpWAL decodes the records of the WAL files of walDir in order, and calls
+// print with the ones matching the filter. Each file is decoded on its own,
+// so that the file and offset of each record are known, and the CRC record
+// starting a file is checked against the CRC of the previous file.
+func dumpWAL(walDir string, f walDumpFilter, print func(WALRecord)) (sum walDumpSummary, err error) {
+	dirEntries, err := os.ReadDir(walDir)
+	if err != nil {
+		return sum, err
+	}
+	var names []string
+	for _, e := range dirEntries {
+		if filepath.Ext(e.Name()) == ".wal" {
+			names = append(names, e.Name())
+		}
+	}
+	if len(names) == 0 {
+		return sum, fmt.Errorf("no WAL files found in %q", walDir)
+	}
+
+	var prevCRC uint32
+	for _, name := range names {
+		if prevCRC, err = dumpWALFile(filepath.Join(walDir, name), prevCRC, f, print, &sum); err != nil {
+			return sum, err
+		}
+		if sum.Truncated != "" {
+			break
+		}
+	}
+	return sum, nil
+}
+
+func dumpWALFile(path string, prevCRC uint32, f walDumpFilter, print func(WALRecord), sum *walDumpSummary) (uint32, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	name := filepath.Base(path)
+	dec := wal.NewDecoderAdvanced(true, fileutil.NewFileReader(file))
+	for {
+		offset := dec.LastOffset()
+		var rec walpb.Record
+		err = dec.Decode(&rec)
+		if errors.Is(err, io.EOF) {
+			return dec.LastCRC(), nil
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			sum.Truncated = fmt.Sprintf("record at offset %d of %s is truncated", offset, name)
+			return dec.LastCRC(), nil
+		}
+		if err != nil && !errors.Is(err, walpb.ErrCRCMismatch) {
+			return 0, fmt.Errorf("failed to decode the record at offset %d of %s: %w", offset, name, err)
+		}
+
+		r := walRecordOf(&rec)
+		r.File, r.Offset = name, offset
+		r.CRCMismatch = err != nil
+		if rec.Type == wal.CrcType {
+			// the first file has no previous CRC to chain to
+			r.CRCMismatch = prevCRC != 0 && rec.Crc != prevCRC
+			dec.UpdateCRC(rec.Crc)
+		}
+		sum.Records++
+		if r.CRCMismatch {
+			sum.CRCMismatches++
+		}
+		if f.match(r) {
+			print(r)
+		}
+	}
+}
+
+func walRecordOf(rec *walpb.Record) WALRecord {
+	r := WALRecord{Type: walRecordTypes[rec.Type]}
+	if r.Type == "" {
+		r.Type = fmt.Sprintf("unknown(%d)", rec.Type)
+	}
+	switch rec.Type {
+	case wal.MetadataType:
+		var m pb.Metadata
+		if err := m.Unmarshal(rec.Data); err != nil {
+			r.Data = fmt.Sprintf("undecodable metadata: %v", err)
+			break
+		}
+		r.MemberID, r.ClusterID = m.NodeID, m.ClusterID
+	case wal.EntryType:
+		var e raftpb.Entry
+		if err := e.Unmarshal(rec.Data); err != nil {
+			r.Data = fmt.Sprintf("undecodable entry: %v", err)
+			break
+		}
+		r.Term, r.Index, r.EntryType = e.Term, e.Index, e.Type.String()
+		r.Data = walEntryData(e)
+	case wal.StateType:
+		var s raftpb.HardState
+		if err := s.Unmarshal(rec.Data); err != nil {
+			r.Data = fmt.Sprintf("undecodable hard state: %v", err)
+			break
+		}
+		r.Term, r.Index, r.Vote = s.Term, s.Commit, s.Vote
+	case wal.CrcType:
+		r.CRC = rec.Crc
+	case wal.SnapshotType:
+		var s walpb.Snapshot
+		if err := s.Unmarshal(rec.Data); err != nil {
+			r.Data = fmt.Sprintf("undecodable snapshot: %v", err)
+			break
+		}
+		r.Term, r.Index = s.Term, s.Index
+	}
+	return r
+}
+
+// walEntryData returns the request or the configuration change of the entry
+// in the protobuf text format.
+func walEntryData(e raftpb.Entry) string {
+	if len(e.Data) == 0 {
+		return ""
+	}
+	switch e.Type {
+	case raftpb.EntryNormal:
+		var req pb.InternalRaftRequest
+		if err := req.Unmarshal(e.Data); err == nil {
+			return strings.TrimSpace(req.String())
+		}
+		return fmt.Sprintf("undecodable request: %q", e.Data)
+	case raftpb.EntryConfChange:
+		var cc raftpb.ConfChange
+		if err := cc.Unmarshal(e.Data); err == nil {
+			return strings.TrimSpace(cc.String())
+		}
+	case raftpb.EntryConfChangeV2:
+		var cc raftpb.ConfChangeV2
+		if err := cc.Unmarshal(e.Data); err == nil {
+			return strings.TrimSpace(cc.String())
+		}
+	}
+	return fmt.Sprintf("undecodable configuration change: %q", e.Data)
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdutl
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/pkg/v3/pbutil"
+	"go.etcd.io/etcd/server/v3/storage/wal"
+	"go.etcd.io/etcd/server/v3/storage/wal/walpb"
+	"go.etcd.io/raft/v3/raftpb"
+)
+
+func createTestWAL(t *testing.T) string {
+	dir := t.TempDir()
+	w, err := wal.Create(zaptest.NewLogger(t), dir, pbutil.MustMarshal(&etcdserverpb.Metadata{NodeID: 1, ClusterID: 2}))
+	require.NoError(t, err)
+	defer w.Close()
+
+	var ents []raftpb.Entry
+	for i := uint64(1); i <= 4; i++ {
+		req := etcdserverpb.InternalRaftRequest{Put: &etcdserverpb.PutRequest{Key: []byte("foo"), Value: []byte("corruptme")}}
+		ents = append(ents, raftpb.Entry{Term: 1 + i/3, Index: i, Data: pbutil.MustMarshal(&req)})
+	}
+	require.NoError(t, w.Save(raftpb.HardState{Term: 2, Vote: 1, Commit: 4}, ents))
+	require.NoError(t, w.SaveSnapshot(walpb.Snapshot{Index: 3, Term: 2, ConfState: &raftpb.ConfState{Voters: []uint64{1}}}))
+	return dir
+}
+
+func TestDumpWAL(t *testing.T) {
+	dir := createTestWAL(t)
+
+	var recs []WALRecord
+	sum, err := dumpWAL(dir, walDumpFilter{}, func(r WALRecord) { recs = append(recs, r) })
+	require.NoError(t, err)
+	assert.Equal(t, walDumpSummary{Records: 9}, sum)
+	var types []string
+	for _, r := range recs {
+		types = append(types, r.Type)
+	}
+	assert.Equal(t, []string{"crc", "metadata", "snapshot", "entry", "entry", "entry", "entry", "state", "snapshot"}, types)
+	assert.Equal(t, uint64(1), recs[1].MemberID)
+	assert.Equal(t, uint64(2), recs[1].ClusterID)
+	assert.Equal(t, "EntryNormal", recs[3].EntryType)
+	assert.Contains(t, recs[3].Data, `put:<key:"foo" value:"corruptme" >`)
+
+	tcs := []struct {
+		name    string
+		filter  walDumpFilter
+		indexes []uint64
+	}{
+		{name: "entries", filter: walDumpFilter{types: []string{"entry"}}, indexes: []uint64{1, 2, 3, 4}},
+		{name: "index range", filter: walDumpFilter{types: []string{"entry", "state"}, startIndex: 2, endIndex: 3}, indexes: []uint64{2, 3}},
+		{name: "term", filter: walDumpFilter{types: []string{"entry", "snapshot"}, term: 2}, indexes: []uint64{3, 4, 3}},
+		{name: "hard state commit", filter: walDumpFilter{types: []string{"state"}, startIndex: 4}, indexes: []uint64{4}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			var indexes []uint64
+			_, err := dumpWAL(dir, tc.filter, func(r WALRecord) { indexes = append(indexes, r.Index) })
+			require.NoError(t, err)
+			assert.Equal(t, tc.indexes, indexes)
+		})
+	}
+}
+
+func TestDumpWALCRCMismatch(t *testing.T) {
+	dir := createTestWAL(t)
+	names, err := filepath.Glob(filepath.Join(dir, "*.wal"))
+	require.NoError(t, err)
+	require.Len(t, names, 1)
+	b, err := os.ReadFile(names[0])
+	require.NoError(t, err)
+	// corrupt the value of the third entry.
+	i := 0
+	for n := 0; n < 3; n++ {
+		j := bytes.Index(b[i:], []byte("corruptme"))
+		require.GreaterOrEqual(t, j, 0)
+		i += j + 1
+	}
+	b[i] = 'X'
+	require.NoError(t, os.WriteFile(names[0], b, 0o600))
+
+	var recs []WALRecord
+	sum, err := dumpWAL(dir, walDumpFilter{}, func(r WALRecord) { recs = append(recs, r) })
+	require.NoError(t, err)
+	assert.Equal(t, 9, sum.Records)
+	// the records following the corrupted record fail verification too.
+	assert.Equal(t, 4, sum.CRCMismatches)
+	assert.False(t, recs[4].CRCMismatch)
+	assert.True(t, recs[5].CRCMismatch)
+	assert.Contains(t, recs[5].Data, `value:"cXrruptme"`)
+}
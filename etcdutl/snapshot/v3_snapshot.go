@@ -17,15 +17,16 @@ package snapshot
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"hash/crc32"
-	"io"
 	"os"
 	"path/filepath"
-	"reflect"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -42,6 +43,7 @@ import (
 	"go.etcd.io/etcd/server/v3/etcdserver/api/snap"
 	"go.etcd.io/etcd/server/v3/etcdserver/cindex"
 	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
 	"go.etcd.io/etcd/server/v3/storage/mvcc"
 	"go.etcd.io/etcd/server/v3/storage/schema"
 	"go.etcd.io/etcd/server/v3/storage/wal"
@@ -69,6 +71,23 @@ type Manager interface {
 	// file. It returns an error if specified data directory already
 	// exists, to prevent unintended data directory overwrites.
 	Restore(cfg RestoreConfig) error
+
+	// Trim writes a copy of the snapshot file trimmed as configured, that
+	// can be restored like a saved snapshot.
+	Trim(cfg TrimConfig) (TrimResult, error)
+
+	// Export writes the latest key-values, the leases and the auth data of
+	// the snapshot file in a portable format.
+	Export(cfg ExportConfig) (ExportResult, error)
+
+	// Import writes a snapshot file from a file written by Export, that can
+	// be restored like a saved snapshot.
+	Import(cfg ImportConfig) (ExportResult, error)
+
+	// WriteManifest writes the manifest of the snapshot file to
+	// dbPath+snapshot.ManifestSuffix, signed with key unless it is nil, for
+	// the snapshots not saved with their manifest.
+	WriteManifest(dbPath string, key ed25519.PrivateKey) (*snapshot.Manifest, error)
 }
 
 // NewV3 returns a new snapshot Manager for v3.x snapshot.
@@ -87,6 +106,11 @@ type v3Manager struct {
 
 	skipHashCheck   bool
 	initialMmapSize uint64
+	kek             encryption.KEKProvider
+
+	dataDir  string
+	state    *restoreState
+	progress *restoreProgress
 }
 
 // hasChecksum returns "true" if the file size "n"
@@ -199,6 +223,38 @@ func (s *v3Manager) Status(dbPath string) (ds Status, err error) {
 	return ds, nil
 }
 
+// WriteManifest writes the manifest of the snapshot file, with the revision
+// and the storage version of the snapshot. The cluster and the member of the
+// snapshot are unknown.
+func (s *v3Manager) WriteManifest(dbPath string, key ed25519.PrivateKey) (*snapshot.Manifest, error) {
+	ds, err := s.Status(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m, err := snapshot.NewManifest(f, snapshot.DefaultManifestChunkSize)
+	if err != nil {
+		return nil, err
+	}
+	m.Revision = ds.Revision
+	m.EtcdVersion = ds.Version
+	if key != nil {
+		if err = m.Sign(key); err != nil {
+			return nil, err
+		}
+	}
+	manifestPath := dbPath + snapshot.ManifestSuffix
+	if err = snapshot.WriteManifest(manifestPath, m); err != nil {
+		return nil, err
+	}
+	s.lg.Info("saved manifest", zap.String("path", manifestPath), zap.Bool("signed", key != nil))
+	return m, nil
+}
+
 func bytesToRev(b []byte) (rev mvcc.Revision, err error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -238,9 +294,22 @@ type RestoreConfig struct {
 	// (required if copied from data directory).
 	SkipHashCheck bool
 
+	// ManifestPath is the path of the manifest the snapshot file is checked
+	// against before it is restored, as written by snapshot save. If empty,
+	// the snapshot is not checked against a manifest.
+	ManifestPath string
+	// VerifyKey, if set, is the public key the manifest must be signed with.
+	// (requires ManifestPath)
+	VerifyKey ed25519.PublicKey
+
 	// InitialMmapSize is the database initial memory map size.
 	InitialMmapSize uint64
 
+	// KEKProvider, if set, unwraps the data encryption keys of an encrypted
+	// snapshot. Restoring an encrypted snapshot requires it, so that a
+	// snapshot the member cannot decrypt is not restored.
+	KEKProvider encryption.KEKProvider
+
 	// RevisionBump is the amount to increase the latest revision after restore,
 	// to allow administrators to trick clients into thinking that revision never decreased.
 	// If 0, revision bumping is skipped.
@@ -250,6 +319,16 @@ type RestoreConfig struct {
 	// MarkCompacted is "true" to mark the latest revision as compacted.
 	// (required if RevisionBump > 0)
 	MarkCompacted bool
+
+	// Resume is "true" to resume the restore interrupted in OutputDataDir,
+	// rather than to return an error for the data directory not being empty.
+	// A fresh restore is done if there is none to resume.
+	Resume bool
+
+	// Progress, if set, is called with the progress of the restore at most
+	// once per ProgressInterval (defaults to 1s), and at the end of each phase.
+	Progress         func(RestoreProgress)
+	ProgressInterval time.Duration
 }
 
 // Restore restores a new etcd data directory from given snapshot file.
@@ -274,6 +353,9 @@ func (s *v3Manager) Restore(cfg RestoreConfig) error {
 	if err = srv.VerifyBootstrap(); err != nil {
 		return err
 	}
+	if err = s.verifyManifest(cfg); err != nil {
+		return err
+	}
 
 	s.cl, err = membership.NewClusterFromURLsMap(s.lg, cfg.InitialClusterToken, ics)
 	if err != nil {
@@ -284,15 +366,39 @@ func (s *v3Manager) Restore(cfg RestoreConfig) error {
 	if dataDir == "" {
 		dataDir = cfg.Name + ".etcd"
 	}
+	state, err := newRestoreState(cfg.SnapshotPath)
+	if err != nil {
+		return err
+	}
 	if fileutil.Exist(dataDir) && !fileutil.DirEmpty(dataDir) {
-		return fmt.Errorf("data-dir %q not empty or could not be read", dataDir)
+		prev, rerr := readRestoreState(dataDir)
+		switch {
+		case rerr == nil && !cfg.Resume:
+			return fmt.Errorf("data-dir %q holds an interrupted restore, resume it or remove the data-dir", dataDir)
+		case rerr == nil:
+			if err = prev.sameSnapshot(state); err != nil {
+				return err
+			}
+			state = prev
+		case errors.Is(rerr, os.ErrNotExist):
+			return fmt.Errorf("data-dir %q not empty or could not be read", dataDir)
+		default:
+			return rerr
+		}
 	}
 
 	walDir := cfg.OutputWALDir
 	if walDir == "" {
 		walDir = filepath.Join(dataDir, "member", "wal")
-	} else if fileutil.Exist(walDir) {
-		return fmt.Errorf("wal-dir %q exists", walDir)
+	}
+	if fileutil.Exist(walDir) {
+		if state.Stage < restoreStagePrepared {
+			return fmt.Errorf("wal-dir %q exists", walDir)
+		}
+		// the WAL left by the interrupted restore is written again.
+		if err = os.RemoveAll(walDir); err != nil {
+			return err
+		}
 	}
 
 	s.name = cfg.Name
@@ -301,6 +407,10 @@ func (s *v3Manager) Restore(cfg RestoreConfig) error {
 	s.snapDir = filepath.Join(dataDir, "member", "snap")
 	s.skipHashCheck = cfg.SkipHashCheck
 	s.initialMmapSize = cfg.InitialMmapSize
+	s.kek = cfg.KEKProvider
+	s.dataDir = dataDir
+	s.state = state
+	s.progress = newRestoreProgress(cfg.Progress, cfg.ProgressInterval)
 
 	s.lg.Info(
 		"restoring snapshot",
@@ -309,14 +419,21 @@ func (s *v3Manager) Restore(cfg RestoreConfig) error {
 		zap.String("data-dir", dataDir),
 		zap.String("snap-dir", s.snapDir),
 		zap.Uint64("initial-memory-map-size", s.initialMmapSize),
+		zap.Int("resumed-stage", state.Stage),
 	)
 
-	if err = s.saveDB(); err != nil {
-		return err
-	}
+	if state.Stage < restoreStagePrepared {
+		if err = s.saveDB(); err != nil {
+			return err
+		}
 
-	if cfg.MarkCompacted && cfg.RevisionBump > 0 {
-		if err = s.modifyLatestRevision(cfg.RevisionBump); err != nil {
+		if cfg.MarkCompacted && cfg.RevisionBump > 0 {
+			if err = s.modifyLatestRevision(cfg.RevisionBump); err != nil {
+				return err
+			}
+		}
+		state.Stage = restoreStagePrepared
+		if err = state.save(dataDir); err != nil {
 			return err
 		}
 	}
@@ -329,6 +446,11 @@ func (s *v3Manager) Restore(cfg RestoreConfig) error {
 	if err := s.updateCIndex(hardstate.Commit, hardstate.Term); err != nil {
 		return err
 	}
+	if err = removeRestoreState(dataDir); err != nil {
+		return err
+	}
+	s.progress.begin(RestorePhaseDone, 0, 0)
+	s.progress.end()
 
 	s.lg.Info(
 		"restored snapshot",
@@ -346,18 +468,65 @@ func (s *v3Manager) Restore(cfg RestoreConfig) error {
 	})
 }
 
+// verifyManifest checks the snapshot file against its manifest, and the
+// signature of the manifest if a verify key is configured.
+func (s *v3Manager) verifyManifest(cfg RestoreConfig) error {
+	if cfg.ManifestPath == "" {
+		if cfg.VerifyKey != nil {
+			return errors.New("a snapshot manifest is required to verify its signature")
+		}
+		return nil
+	}
+	m, err := snapshot.ReadManifest(cfg.ManifestPath)
+	if err != nil {
+		return err
+	}
+	if cfg.VerifyKey != nil {
+		if err = m.VerifySignature(cfg.VerifyKey); err != nil {
+			return fmt.Errorf("%s: %w", cfg.ManifestPath, err)
+		}
+	}
+	if err = m.VerifyFile(cfg.SnapshotPath); err != nil {
+		return fmt.Errorf("%s: %w", cfg.SnapshotPath, err)
+	}
+	s.lg.Info(
+		"verified snapshot manifest",
+		zap.String("path", cfg.SnapshotPath),
+		zap.String("manifest-path", cfg.ManifestPath),
+		zap.Bool("signed", cfg.VerifyKey != nil),
+		zap.Int64("revision", m.Revision),
+		zap.String("cluster-id", m.ClusterID),
+		zap.String("etcd-version", m.EtcdVersion),
+	)
+	return nil
+}
+
+// openBackend opens the backend at path, decrypting its values with the KEKs
+// of kek if it is encrypted.
+func openBackend(lg *zap.Logger, path string, kek encryption.KEKProvider, opts ...backend.BackendConfigOption) (backend.Backend, error) {
+	return encryption.OpenBackend(context.Background(), lg, path, kek, opts...)
+}
+
 func (s *v3Manager) outDbPath() string {
 	return filepath.Join(s.snapDir, "db")
 }
 
 // saveDB copies the database snapshot to the snapshot directory
 func (s *v3Manager) saveDB() error {
-	err := s.copyAndVerifyDB()
+	if s.state.Stage < restoreStageCopied {
+		if err := s.copyAndVerifyDB(); err != nil {
+			return err
+		}
+		s.state.Stage = restoreStageCopied
+		if err := s.state.save(s.dataDir); err != nil {
+			return err
+		}
+	}
+
+	be, err := openBackend(s.lg, s.outDbPath(), s.kek, backend.WithMmapSize(s.initialMmapSize))
 	if err != nil {
 		return err
 	}
-
-	be := backend.NewDefaultBackend(s.lg, s.outDbPath(), backend.WithMmapSize(s.initialMmapSize))
 	defer be.Close()
 
 	err = schema.NewMembershipBackend(s.lg, be).TrimMembershipFromBackend()
@@ -365,13 +534,19 @@ func (s *v3Manager) saveDB() error {
 		return err
 	}
 
+	if s.progress.report != nil {
+		return countKeys(be, s.progress)
+	}
 	return nil
 }
 
 // modifyLatestRevision can increase the latest revision by the given amount and sets the scheduled compaction
 // to that revision so that the server will consider this revision compacted.
 func (s *v3Manager) modifyLatestRevision(bumpAmount uint64) error {
-	be := backend.NewDefaultBackend(s.lg, s.outDbPath())
+	be, err := openBackend(s.lg, s.outDbPath(), s.kek)
+	if err != nil {
+		return err
+	}
 	defer func() {
 		be.ForceCommit()
 		be.Close()
@@ -432,75 +607,26 @@ func (s *v3Manager) unsafeGetLatestRevision(tx backend.UnsafeReader) (mvcc.Revis
 	return latest, err
 }
 
+// copyAndVerifyDB copies the snapshot file from where the interrupted restore
+// stopped, checkpointing the copy in the restore state.
 func (s *v3Manager) copyAndVerifyDB() error {
-	srcf, ferr := os.Open(s.srcDbPath)
-	if ferr != nil {
-		return ferr
-	}
-	defer srcf.Close()
-
-	// get snapshot integrity hash
-	if _, err := srcf.Seek(-sha256.Size, io.SeekEnd); err != nil {
-		return err
-	}
-	sha := make([]byte, sha256.Size)
-	if _, err := srcf.Read(sha); err != nil {
-		return err
-	}
-	if _, err := srcf.Seek(0, io.SeekStart); err != nil {
+	// the data directory is empty or holds the interrupted restore.
+	if err := fileutil.TouchDirAll(s.lg, s.snapDir); err != nil {
 		return err
 	}
-
-	if err := fileutil.CreateDirAll(s.lg, s.snapDir); err != nil {
+	if err := s.state.save(s.dataDir); err != nil {
 		return err
 	}
+	return copyAndVerifyDBFileFrom(s.srcDbPath, s.outDbPath(), s.skipHashCheck, s.state.Copied, s.progress, func(copied int64) error {
+		s.state.Copied = copied
+		return s.state.save(s.dataDir)
+	})
+}
 
-	outDbPath := s.outDbPath()
-
-	db, dberr := os.OpenFile(outDbPath, os.O_RDWR|os.O_CREATE, 0o600)
-	if dberr != nil {
-		return dberr
-	}
-	defer db.Close()
-
-	if _, err := io.Copy(db, srcf); err != nil {
-		return err
-	}
-
-	// truncate away integrity hash, if any.
-	off, serr := db.Seek(0, io.SeekEnd)
-	if serr != nil {
-		return serr
-	}
-	hasHash := hasChecksum(off)
-	if hasHash {
-		if err := db.Truncate(off - sha256.Size); err != nil {
-			return err
-		}
-	}
-
-	if !hasHash && !s.skipHashCheck {
-		return fmt.Errorf("snapshot missing hash but --skip-hash-check=false")
-	}
-
-	if hasHash && !s.skipHashCheck {
-		// check for match
-		if _, err := db.Seek(0, io.SeekStart); err != nil {
-			return err
-		}
-		h := sha256.New()
-		if _, err := io.Copy(h, db); err != nil {
-			return err
-		}
-		dbsha := h.Sum(nil)
-		if !reflect.DeepEqual(sha, dbsha) {
-			return fmt.Errorf("expected sha256 %v, got %v", sha, dbsha)
-		}
-	}
-
-	// db hash is OK, can now modify DB so it can be part of a new cluster
-
-	return nil
+// copyAndVerifyDBFile copies the snapshot file to outDbPath without its
+// integrity hash, after checking the hash unless skipHashCheck is set.
+func copyAndVerifyDBFile(srcDbPath, outDbPath string, skipHashCheck bool) error {
+	return copyAndVerifyDBFileFrom(srcDbPath, outDbPath, skipHashCheck, 0, newRestoreProgress(nil, 0), nil)
 }
 
 // saveWALAndSnap creates a WAL for the initial cluster
@@ -0,0 +1,567 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/coreos/go-semver/semver"
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/version"
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	"go.etcd.io/etcd/server/v3/lease"
+	"go.etcd.io/etcd/server/v3/lease/leasepb"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+const (
+	// FormatJSON is the JSON Lines export format: a header line followed by a
+	// line per record.
+	FormatJSON = "json"
+
+	// jsonFormatName identifies the header of a JSON export.
+	jsonFormatName = "etcd-snapshot"
+	// jsonFormatVersion is the version of the JSON export format. It is
+	// increased on incompatible changes only; fields may be added without
+	// increasing it.
+	jsonFormatVersion = 1
+
+	// exportBatchLimit is the number of keys read or written per backend
+	// transaction.
+	exportBatchLimit = 10000
+)
+
+// JSON export record types.
+const (
+	jsonTypeHeader = "header"
+	jsonTypeAuth   = "auth"
+	jsonTypeRole   = "role"
+	jsonTypeUser   = "user"
+	jsonTypeLease  = "lease"
+	jsonTypeKey    = "key"
+)
+
+// ExportConfig configures snapshot export operation.
+type ExportConfig struct {
+	// SnapshotPath is the path of snapshot file to export.
+	SnapshotPath string
+	// OutputPath is the path of the exported file. It must not exist.
+	OutputPath string
+	// Format is the format of the exported file. Only FormatJSON is
+	// supported.
+	Format string
+
+	// SkipHashCheck is "true" to ignore snapshot integrity hash value
+	// (required if copied from data directory).
+	SkipHashCheck bool
+
+	// KEKProvider, if set, unwraps the data encryption keys of an encrypted
+	// snapshot, which cannot be exported without it.
+	KEKProvider encryption.KEKProvider
+}
+
+// ImportConfig configures snapshot import operation.
+type ImportConfig struct {
+	// InputPath is the path of the exported file to import.
+	InputPath string
+	// OutputPath is the path of the snapshot file to write. It must not
+	// exist.
+	OutputPath string
+	// Format is the format of the exported file. Only FormatJSON is
+	// supported.
+	Format string
+}
+
+// ExportResult counts the records of an exported snapshot.
+type ExportResult struct {
+	Revision int64 `json:"revision"`
+	Keys     int   `json:"keys"`
+	Leases   int   `json:"leases"`
+	Users    int   `json:"users"`
+	Roles    int   `json:"roles"`
+}
+
+type jsonRecordType struct {
+	Type string `json:"type"`
+}
+
+type jsonHeader struct {
+	Type    string `json:"type"`
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+	// Revision is the latest revision of the snapshot. It may be greater
+	// than the mod revisions of all keys if the latest change was a deletion.
+	Revision int64 `json:"revision"`
+	// StorageVersion is the storage version of the exported snapshot, for
+	// information only.
+	StorageVersion string `json:"storageVersion,omitempty"`
+}
+
+type jsonAuth struct {
+	Type     string `json:"type"`
+	Enabled  bool   `json:"enabled"`
+	Revision uint64 `json:"revision"`
+}
+
+type jsonPermission struct {
+	// Type is "read", "write" or "readwrite".
+	Type     string `json:"type"`
+	Key      []byte `json:"key"`
+	RangeEnd []byte `json:"rangeEnd,omitempty"`
+}
+
+type jsonRole struct {
+	Type        string           `json:"type"`
+	Name        string           `json:"name"`
+	Permissions []jsonPermission `json:"permissions,omitempty"`
+}
+
+type jsonUser struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	// Password is the bcrypt hash of the password.
+	Password            string   `json:"password,omitempty"`
+	Roles               []string `json:"roles,omitempty"`
+	NoPassword          bool     `json:"noPassword,omitempty"`
+	PasswordChangedTime int64    `json:"passwordChangedTime,omitempty"`
+}
+
+type jsonLease struct {
+	Type         string `json:"type"`
+	ID           int64  `json:"id"`
+	TTL          int64  `json:"ttl"`
+	RemainingTTL int64  `json:"remainingTTL,omitempty"`
+	Metadata     []byte `json:"metadata,omitempty"`
+}
+
+type jsonKey struct {
+	Type           string `json:"type"`
+	Key            []byte `json:"key"`
+	Value          []byte `json:"value"`
+	CreateRevision int64  `json:"createRevision"`
+	ModRevision    int64  `json:"modRevision"`
+	Version        int64  `json:"version"`
+	Lease          int64  `json:"lease,omitempty"`
+	TTL            int64  `json:"ttl,omitempty"`
+}
+
+var (
+	permTypeNames = map[authpb.Permission_Type]string{
+		authpb.READ:      "read",
+		authpb.WRITE:     "write",
+		authpb.READWRITE: "readwrite",
+	}
+	permTypesByName = map[string]authpb.Permission_Type{
+		"read":      authpb.READ,
+		"write":     authpb.WRITE,
+		"readwrite": authpb.READWRITE,
+	}
+)
+
+func checkFormat(format string) error {
+	if format != FormatJSON {
+		return fmt.Errorf("unsupported format %q, expected %q", format, FormatJSON)
+	}
+	return nil
+}
+
+// Export writes the latest key-values, the leases and the auth data of the
+// snapshot file in a format independent of the storage schema, so that they
+// can be inspected or imported by another etcd version. The history of the
+// keys is not exported.
+func (s *v3Manager) Export(cfg ExportConfig) (res ExportResult, err error) {
+	if err = checkFormat(cfg.Format); err != nil {
+		return res, err
+	}
+	if fileutil.Exist(cfg.OutputPath) {
+		return res, fmt.Errorf("output %q exists", cfg.OutputPath)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "etcdutl-export")
+	if err != nil {
+		return res, err
+	}
+	defer os.RemoveAll(tmpDir)
+	dbPath := filepath.Join(tmpDir, "db")
+	if err = copyAndVerifyDBFile(cfg.SnapshotPath, dbPath, cfg.SkipHashCheck); err != nil {
+		return res, err
+	}
+
+	partPath := cfg.OutputPath + ".part"
+	f, err := os.OpenFile(partPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, fileutil.PrivateFileMode)
+	if err != nil {
+		return res, err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(partPath)
+		}
+	}()
+	w := bufio.NewWriter(f)
+
+	be, err := openBackend(s.lg, dbPath, cfg.KEKProvider)
+	if err != nil {
+		return res, err
+	}
+	res, err = exportJSON(s.lg, be, json.NewEncoder(w))
+	be.Close()
+	if err != nil {
+		return res, err
+	}
+	if err = w.Flush(); err != nil {
+		return res, err
+	}
+	if err = f.Sync(); err != nil {
+		return res, err
+	}
+	if err = f.Close(); err != nil {
+		return res, err
+	}
+	if err = os.Rename(partPath, cfg.OutputPath); err != nil {
+		return res, err
+	}
+
+	s.lg.Info(
+		"exported snapshot",
+		zap.String("path", cfg.SnapshotPath),
+		zap.String("output", cfg.OutputPath),
+		zap.Int64("revision", res.Revision),
+		zap.Int("keys", res.Keys),
+	)
+	return res, nil
+}
+
+func exportJSON(lg *zap.Logger, be backend.Backend, enc *json.Encoder) (res ExportResult, err error) {
+	// the lessor is needed to attach the keys to their leases.
+	le := lease.NewLessor(lg, be, nil, lease.LessorConfig{})
+	defer le.Stop()
+	st := mvcc.NewStore(lg, be, le, mvcc.StoreConfig{})
+	defer st.Close()
+	res.Revision = st.Rev()
+
+	h := jsonHeader{Type: jsonTypeHeader, Format: jsonFormatName, Version: jsonFormatVersion, Revision: res.Revision}
+	if v := schema.ReadStorageVersion(be.ReadTx()); v != nil {
+		h.StorageVersion = v.String()
+	}
+	if err = enc.Encode(h); err != nil {
+		return res, err
+	}
+
+	// the auth and lease buckets are read with the batch transaction, as
+	// they cannot be ranged by read transactions.
+	tx := be.BatchTx()
+	tx.LockOutsideApply()
+	atx := schema.NewAuthBackend(lg, be).BatchTx()
+	enabled, authRev := atx.UnsafeReadAuthEnabled(), atx.UnsafeReadAuthRevision()
+	roles, users := atx.UnsafeGetAllRoles(), atx.UnsafeGetAllUsers()
+	leases := schema.MustUnsafeGetAllLeases(tx)
+	tx.Unlock()
+
+	if err = enc.Encode(jsonAuth{Type: jsonTypeAuth, Enabled: enabled, Revision: authRev}); err != nil {
+		return res, err
+	}
+	for _, r := range roles {
+		jr := jsonRole{Type: jsonTypeRole, Name: string(r.Name)}
+		for _, p := range r.KeyPermission {
+			jr.Permissions = append(jr.Permissions, jsonPermission{Type: permTypeNames[p.PermType], Key: p.Key, RangeEnd: p.RangeEnd})
+		}
+		if err = enc.Encode(jr); err != nil {
+			return res, err
+		}
+	}
+	for _, u := range users {
+		ju := jsonUser{Type: jsonTypeUser, Name: string(u.Name), Password: string(u.Password), Roles: u.Roles, PasswordChangedTime: u.PasswordChangedTime}
+		if u.Options != nil {
+			ju.NoPassword = u.Options.NoPassword
+		}
+		if err = enc.Encode(ju); err != nil {
+			return res, err
+		}
+	}
+	res.Roles, res.Users = len(roles), len(users)
+
+	for _, l := range leases {
+		if err = enc.Encode(jsonLease{Type: jsonTypeLease, ID: l.ID, TTL: l.TTL, RemainingTTL: l.RemainingTTL, Metadata: l.Metadata}); err != nil {
+			return res, err
+		}
+	}
+	res.Leases = len(leases)
+
+	// an empty range end ranges over all the keys from key on.
+	key, end := []byte{0}, []byte{}
+	for {
+		rr, err := st.Range(context.Background(), key, end, mvcc.RangeOptions{Limit: exportBatchLimit, Rev: res.Revision})
+		if err != nil {
+			return res, err
+		}
+		for _, kv := range rr.KVs {
+			jk := jsonKey{
+				Type:           jsonTypeKey,
+				Key:            kv.Key,
+				Value:          kv.Value,
+				CreateRevision: kv.CreateRevision,
+				ModRevision:    kv.ModRevision,
+				Version:        kv.Version,
+				Lease:          kv.Lease,
+				TTL:            kv.Ttl,
+			}
+			if err = enc.Encode(jk); err != nil {
+				return res, err
+			}
+		}
+		res.Keys += len(rr.KVs)
+		if len(rr.KVs) < exportBatchLimit {
+			return res, nil
+		}
+		key = append(bytes.Clone(rr.KVs[len(rr.KVs)-1].Key), 0)
+	}
+}
+
+// Import writes a snapshot file from an exported file, with the storage
+// schema of this etcd version. The keys keep their revisions, and the latest
+// revision of the export is marked compacted as the history of the keys is
+// not exported. The snapshot file ends with its integrity hash, so that it
+// can be restored like a saved snapshot.
+func (s *v3Manager) Import(cfg ImportConfig) (res ExportResult, err error) {
+	if err = checkFormat(cfg.Format); err != nil {
+		return res, err
+	}
+	if fileutil.Exist(cfg.OutputPath) {
+		return res, fmt.Errorf("output %q exists", cfg.OutputPath)
+	}
+	f, err := os.Open(cfg.InputPath)
+	if err != nil {
+		return res, err
+	}
+	defer f.Close()
+
+	partPath := cfg.OutputPath + ".part"
+	if fileutil.Exist(partPath) {
+		return res, fmt.Errorf("%q exists", partPath)
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(partPath)
+		}
+	}()
+
+	be := backend.NewDefaultBackend(s.lg, partPath)
+	res, err = importJSON(s.lg, be, bufio.NewReader(f))
+	if err != nil {
+		be.Close()
+		return res, fmt.Errorf("failed to import %q: %w", cfg.InputPath, err)
+	}
+	be.ForceCommit()
+	if err = be.Defrag(); err != nil {
+		be.Close()
+		return res, err
+	}
+	if err = be.Close(); err != nil {
+		return res, err
+	}
+	if err = AppendDBHash(partPath); err != nil {
+		return res, err
+	}
+	if err = os.Rename(partPath, cfg.OutputPath); err != nil {
+		return res, err
+	}
+
+	s.lg.Info(
+		"imported snapshot",
+		zap.String("path", cfg.InputPath),
+		zap.String("output", cfg.OutputPath),
+		zap.Int64("revision", res.Revision),
+		zap.Int("keys", res.Keys),
+	)
+	return res, nil
+}
+
+// jsonImporter writes the records of a JSON export to a backend.
+type jsonImporter struct {
+	lg  *zap.Logger
+	be  backend.Backend
+	tx  backend.BatchTx
+	res ExportResult
+
+	// subs is the number of keys written per main revision, to give distinct
+	// sub revisions to the keys modified by the same transaction.
+	subs        map[int64]int64
+	maxRevision int64
+	pending     int
+}
+
+func importJSON(lg *zap.Logger, be backend.Backend, r io.Reader) (ExportResult, error) {
+	dec := json.NewDecoder(r)
+	var h jsonHeader
+	if err := dec.Decode(&h); err != nil {
+		return ExportResult{}, fmt.Errorf("failed to decode the header: %w", err)
+	}
+	if h.Type != jsonTypeHeader || h.Format != jsonFormatName {
+		return ExportResult{}, errors.New("missing the header")
+	}
+	if h.Version > jsonFormatVersion {
+		return ExportResult{}, fmt.Errorf("unsupported format version %d, expected at most %d", h.Version, jsonFormatVersion)
+	}
+	if h.Revision < 1 {
+		return ExportResult{}, fmt.Errorf("invalid revision %d", h.Revision)
+	}
+
+	im := &jsonImporter{lg: lg, be: be, tx: be.BatchTx(), subs: make(map[int64]int64)}
+	im.res.Revision = h.Revision
+	im.tx.LockOutsideApply()
+	for _, b := range schema.AllBuckets {
+		im.tx.UnsafeCreateBucket(b)
+	}
+	v := semver.New(version.Version)
+	schema.UnsafeSetStorageVersion(im.tx, &semver.Version{Major: v.Major, Minor: v.Minor})
+
+	for {
+		var raw json.RawMessage
+		err := dec.Decode(&raw)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err == nil {
+			err = im.importRecord(raw)
+		}
+		if err != nil {
+			im.tx.Unlock()
+			return im.res, err
+		}
+	}
+
+	if im.maxRevision > h.Revision {
+		im.tx.Unlock()
+		return im.res, fmt.Errorf("key mod revision %d is greater than the revision %d", im.maxRevision, h.Revision)
+	}
+	if im.maxRevision < h.Revision {
+		// keep the latest revision, like a revision bump does.
+		im.tx.UnsafePut(schema.Key, mvcc.RevToBytes(mvcc.Revision{Main: h.Revision}, mvcc.NewRevBytes()), []byte{})
+	}
+	mvcc.UnsafeSetScheduledCompact(im.tx, h.Revision)
+	im.tx.Unlock()
+	return im.res, nil
+}
+
+func (im *jsonImporter) importRecord(raw json.RawMessage) error {
+	var t jsonRecordType
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return err
+	}
+	switch t.Type {
+	case jsonTypeAuth:
+		var a jsonAuth
+		if err := json.Unmarshal(raw, &a); err != nil {
+			return err
+		}
+		atx := schema.NewAuthBackend(im.lg, im.be).BatchTx()
+		atx.UnsafeSaveAuthEnabled(a.Enabled)
+		atx.UnsafeSaveAuthRevision(a.Revision)
+	case jsonTypeRole:
+		var jr jsonRole
+		if err := json.Unmarshal(raw, &jr); err != nil {
+			return err
+		}
+		role := &authpb.Role{Name: []byte(jr.Name)}
+		for _, p := range jr.Permissions {
+			permType, ok := permTypesByName[p.Type]
+			if !ok {
+				return fmt.Errorf("invalid permission type %q of role %q", p.Type, jr.Name)
+			}
+			role.KeyPermission = append(role.KeyPermission, &authpb.Permission{PermType: permType, Key: p.Key, RangeEnd: p.RangeEnd})
+		}
+		schema.NewAuthBackend(im.lg, im.be).BatchTx().UnsafePutRole(role)
+		im.res.Roles++
+	case jsonTypeUser:
+		var ju jsonUser
+		if err := json.Unmarshal(raw, &ju); err != nil {
+			return err
+		}
+		user := &authpb.User{
+			Name:                []byte(ju.Name),
+			Password:            []byte(ju.Password),
+			Roles:               ju.Roles,
+			Options:             &authpb.UserAddOptions{NoPassword: ju.NoPassword},
+			PasswordChangedTime: ju.PasswordChangedTime,
+		}
+		schema.NewAuthBackend(im.lg, im.be).BatchTx().UnsafePutUser(user)
+		im.res.Users++
+	case jsonTypeLease:
+		var jl jsonLease
+		if err := json.Unmarshal(raw, &jl); err != nil {
+			return err
+		}
+		if jl.ID == 0 || jl.TTL < 0 {
+			return fmt.Errorf("invalid lease %d with TTL %d", jl.ID, jl.TTL)
+		}
+		schema.MustUnsafePutLease(im.tx, &leasepb.Lease{ID: jl.ID, TTL: jl.TTL, RemainingTTL: jl.RemainingTTL, Metadata: jl.Metadata})
+		im.res.Leases++
+	case jsonTypeKey:
+		var jk jsonKey
+		if err := json.Unmarshal(raw, &jk); err != nil {
+			return err
+		}
+		if len(jk.Key) == 0 || jk.ModRevision < 1 || jk.CreateRevision < 1 || jk.CreateRevision > jk.ModRevision || jk.Version < 1 {
+			return fmt.Errorf("invalid key %q with create revision %d, mod revision %d and version %d", jk.Key, jk.CreateRevision, jk.ModRevision, jk.Version)
+		}
+		kv := mvccpb.KeyValue{
+			Key:            jk.Key,
+			Value:          jk.Value,
+			CreateRevision: jk.CreateRevision,
+			ModRevision:    jk.ModRevision,
+			Version:        jk.Version,
+			Lease:          jk.Lease,
+			Ttl:            jk.TTL,
+		}
+		d, err := kv.Marshal()
+		if err != nil {
+			return err
+		}
+		rev := mvcc.Revision{Main: jk.ModRevision, Sub: im.subs[jk.ModRevision]}
+		im.subs[jk.ModRevision]++
+		im.tx.UnsafePut(schema.Key, mvcc.RevToBytes(rev, mvcc.NewRevBytes()), d)
+		im.maxRevision = max(im.maxRevision, jk.ModRevision)
+		im.res.Keys++
+		im.pending++
+		if im.pending >= exportBatchLimit {
+			im.pending = 0
+			im.tx.Unlock()
+			im.be.ForceCommit()
+			im.tx.LockOutsideApply()
+		}
+	case jsonTypeHeader:
+		return errors.New("unexpected header")
+	default:
+		// records of unknown types are added by later versions of the format,
+		// and are skipped.
+		im.lg.Warn("skipping record of unknown type", zap.String("type", t.Type))
+	}
+	return nil
+}
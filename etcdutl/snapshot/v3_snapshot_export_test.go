@@ -0,0 +1,183 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/embed"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
+)
+
+// TestSnapshotExportImport ensures a snapshot imported from an export
+// restores with the latest keys, their revisions, the leases and the auth
+// data of the exported snapshot.
+func TestSnapshotExportImport(t *testing.T) {
+	var leaseID int64
+	dbpath := createDB(t, func(srv *etcdserver.EtcdServer) {
+		lresp, err := srv.LeaseGrant(t.Context(), &etcdserverpb.LeaseGrantRequest{TTL: 3600})
+		require.NoError(t, err)
+		leaseID = lresp.ID
+		_, err = srv.Put(t.Context(), &etcdserverpb.PutRequest{Key: []byte("a"), Value: []byte("1"), Lease: leaseID})
+		require.NoError(t, err)
+		// b and c are modified at the same revision.
+		_, err = srv.Txn(t.Context(), &etcdserverpb.TxnRequest{Success: []*etcdserverpb.RequestOp{
+			{Request: &etcdserverpb.RequestOp_RequestPut{RequestPut: &etcdserverpb.PutRequest{Key: []byte("b"), Value: []byte("2")}}},
+			{Request: &etcdserverpb.RequestOp_RequestPut{RequestPut: &etcdserverpb.PutRequest{Key: []byte("c"), Value: []byte("3")}}},
+		}})
+		require.NoError(t, err)
+		_, err = srv.Put(t.Context(), &etcdserverpb.PutRequest{Key: []byte("a"), Value: []byte("4"), Lease: leaseID})
+		require.NoError(t, err)
+		_, err = srv.DeleteRange(t.Context(), &etcdserverpb.DeleteRangeRequest{Key: []byte("c")})
+		require.NoError(t, err)
+		_, err = srv.RoleAdd(t.Context(), &etcdserverpb.AuthRoleAddRequest{Name: "reader"})
+		require.NoError(t, err)
+		_, err = srv.RoleGrantPermission(t.Context(), &etcdserverpb.AuthRoleGrantPermissionRequest{
+			Name: "reader",
+			Perm: &authpb.Permission{PermType: authpb.READ, Key: []byte("a"), RangeEnd: []byte("c")},
+		})
+		require.NoError(t, err)
+		_, err = srv.UserAdd(t.Context(), &etcdserverpb.AuthUserAddRequest{Name: "alice", Password: "pass"})
+		require.NoError(t, err)
+		_, err = srv.UserGrantRole(t.Context(), &etcdserverpb.AuthUserGrantRoleRequest{User: "alice", Role: "reader"})
+		require.NoError(t, err)
+	})
+	// revision 1 is the initial revision.
+	const latestRev = 5
+
+	m := NewV3(zap.NewNop())
+	exported := filepath.Join(t.TempDir(), "snapshot.json")
+	res, err := m.Export(ExportConfig{SnapshotPath: dbpath, OutputPath: exported, Format: FormatJSON, SkipHashCheck: true})
+	require.NoError(t, err)
+	assert.Equal(t, ExportResult{Revision: latestRev, Keys: 2, Leases: 1, Users: 1, Roles: 1}, res)
+	b, err := os.ReadFile(exported)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	require.Len(t, lines, 7)
+	assert.Contains(t, lines[0], `"type":"header","format":"etcd-snapshot","version":1,"revision":5`)
+	assert.Contains(t, lines[2], `"permissions":[{"type":"read","key":"YQ==","rangeEnd":"Yw=="}]`)
+
+	imported := filepath.Join(t.TempDir(), "snapshot.db")
+	res, err = m.Import(ImportConfig{InputPath: exported, OutputPath: imported, Format: FormatJSON})
+	require.NoError(t, err)
+	assert.Equal(t, ExportResult{Revision: latestRev, Keys: 2, Leases: 1, Users: 1, Roles: 1}, res)
+
+	dataDir := filepath.Join(t.TempDir(), "default.etcd")
+	require.NoError(t, m.Restore(RestoreConfig{
+		SnapshotPath:        imported,
+		Name:                "default",
+		OutputDataDir:       dataDir,
+		PeerURLs:            []string{"http://localhost:2380"},
+		InitialCluster:      "default=http://localhost:2380",
+		InitialClusterToken: "etcd-cluster",
+	}))
+
+	cfg := embed.NewConfig()
+	cfg.LogLevel = "fatal"
+	cfg.Dir = dataDir
+	etcd, err := embed.StartEtcd(cfg)
+	require.NoError(t, err)
+	defer etcd.Close()
+	select {
+	case <-etcd.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		t.FailNow()
+	}
+	srv := etcd.Server
+
+	resp, err := srv.Range(t.Context(), &etcdserverpb.RangeRequest{Key: []byte{0}, RangeEnd: []byte{0}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(latestRev), resp.Header.Revision)
+	require.Len(t, resp.Kvs, 2)
+	assert.Equal(t, "a", string(resp.Kvs[0].Key))
+	assert.Equal(t, "4", string(resp.Kvs[0].Value))
+	assert.Equal(t, int64(2), resp.Kvs[0].CreateRevision)
+	assert.Equal(t, int64(4), resp.Kvs[0].ModRevision)
+	assert.Equal(t, int64(2), resp.Kvs[0].Version)
+	assert.Equal(t, leaseID, resp.Kvs[0].Lease)
+	assert.Equal(t, "b", string(resp.Kvs[1].Key))
+	assert.Equal(t, int64(3), resp.Kvs[1].ModRevision)
+
+	_, err = srv.Range(t.Context(), &etcdserverpb.RangeRequest{Key: []byte("a"), Revision: latestRev - 1})
+	require.ErrorContains(t, err, "compacted")
+
+	lresp, err := srv.LeaseTimeToLive(t.Context(), &etcdserverpb.LeaseTimeToLiveRequest{ID: leaseID, Keys: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3600), lresp.GrantedTTL)
+	assert.Equal(t, [][]byte{[]byte("a")}, lresp.Keys)
+
+	uresp, err := srv.UserGet(t.Context(), &etcdserverpb.AuthUserGetRequest{Name: "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"reader"}, uresp.Roles)
+	rresp, err := srv.RoleGet(t.Context(), &etcdserverpb.AuthRoleGetRequest{Role: "reader"})
+	require.NoError(t, err)
+	require.Len(t, rresp.Perm, 1)
+	assert.Equal(t, "c", string(rresp.Perm[0].RangeEnd))
+}
+
+func TestSnapshotImportFormatVersion(t *testing.T) {
+	input := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, os.WriteFile(input, []byte(`{"type":"header","format":"etcd-snapshot","version":2,"revision":1}`+"\n"), 0o600))
+	out := filepath.Join(t.TempDir(), "snapshot.db")
+	_, err := NewV3(zap.NewNop()).Import(ImportConfig{InputPath: input, OutputPath: out, Format: FormatJSON})
+	require.ErrorContains(t, err, "unsupported format version 2")
+	assert.NoFileExists(t, out)
+	assert.NoFileExists(t, out+".part")
+}
+
+// TestSnapshotExportEncrypted ensures an encrypted snapshot is only exported
+// with the key encryption keys it was encrypted with.
+func TestSnapshotExportEncrypted(t *testing.T) {
+	kekPath := filepath.Join(t.TempDir(), "kek")
+	require.NoError(t, os.WriteFile(kekPath, []byte("kek1:"+base64.StdEncoding.EncodeToString(make([]byte, 32))+"\n"), 0o600))
+
+	cfg := embed.NewConfig()
+	cfg.LogLevel = "fatal"
+	cfg.Dir = t.TempDir()
+	cfg.EncryptionKEKFile = kekPath
+	etcd, err := embed.StartEtcd(cfg)
+	require.NoError(t, err)
+	select {
+	case <-etcd.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		t.FailNow()
+	}
+	_, err = etcd.Server.Put(t.Context(), &etcdserverpb.PutRequest{Key: []byte("a"), Value: []byte("1")})
+	require.NoError(t, err)
+	etcd.Close()
+	dbpath := filepath.Join(cfg.Dir, "member", "snap", "db")
+
+	m := NewV3(zap.NewNop())
+	_, err = m.Export(ExportConfig{SnapshotPath: dbpath, OutputPath: filepath.Join(t.TempDir(), "snapshot.json"), Format: FormatJSON, SkipHashCheck: true})
+	require.ErrorIs(t, err, encryption.ErrKEKRequired)
+
+	kek, err := encryption.NewFileKEKProvider(kekPath)
+	require.NoError(t, err)
+	res, err := m.Export(ExportConfig{SnapshotPath: dbpath, OutputPath: filepath.Join(t.TempDir(), "snapshot.json"), Format: FormatJSON, SkipHashCheck: true, KEKProvider: kek})
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.Keys)
+}
@@ -0,0 +1,354 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+// The phases of a restore reported by RestoreProgress.
+const (
+	// RestorePhaseCopy copies the snapshot file to the data directory.
+	RestorePhaseCopy = "copy"
+	// RestorePhaseVerify checks the integrity hash of the copy.
+	RestorePhaseVerify = "verify"
+	// RestorePhaseKeys goes through the keys of the copy.
+	RestorePhaseKeys = "keys"
+	// RestorePhaseDone is reported once the restore is over.
+	RestorePhaseDone = "done"
+)
+
+const (
+	// restoreStateFile is the file of the data directory holding the state
+	// of a restore until it is over.
+	restoreStateFile = "restore.state"
+
+	// restoreCheckpointBytes is the number of bytes copied between two
+	// checkpoints of the copy a resumed restore starts from.
+	restoreCheckpointBytes = 64 * 1024 * 1024
+
+	restoreCopyBufferSize = 1024 * 1024
+
+	defaultRestoreProgressInterval = time.Second
+)
+
+// RestoreProgress is the progress of a restore, reported to
+// RestoreConfig.Progress.
+type RestoreProgress struct {
+	// Phase is the phase of the restore, one of the RestorePhase constants.
+	Phase string
+	// BytesWritten is the number of bytes of the snapshot copied or verified
+	// so far, out of TotalBytes.
+	BytesWritten int64
+	TotalBytes   int64
+	// KeysProcessed is the number of key revisions gone through so far.
+	KeysProcessed int64
+	// Elapsed is the time since the restore started.
+	Elapsed time.Duration
+	// ETA is the estimated time left for the phase, 0 if unknown.
+	ETA time.Duration
+}
+
+// restoreProgress reports the progress of a restore at most once per
+// interval, and at the end of each phase. It reports nothing if report is nil.
+type restoreProgress struct {
+	report   func(RestoreProgress)
+	interval time.Duration
+
+	start      time.Time
+	phaseStart time.Time
+	phaseFrom  int64
+	last       time.Time
+	p          RestoreProgress
+}
+
+func newRestoreProgress(report func(RestoreProgress), interval time.Duration) *restoreProgress {
+	if interval <= 0 {
+		interval = defaultRestoreProgressInterval
+	}
+	return &restoreProgress{report: report, interval: interval, start: time.Now()}
+}
+
+// begin starts a phase, from the given bytes out of total.
+func (r *restoreProgress) begin(phase string, from, total int64) {
+	now := time.Now()
+	r.phaseStart, r.phaseFrom, r.last = now, from, now
+	r.p = RestoreProgress{Phase: phase, BytesWritten: from, TotalBytes: total}
+}
+
+// update records the bytes and the keys processed so far in the phase.
+func (r *restoreProgress) update(bytes, keys int64) {
+	r.p.BytesWritten, r.p.KeysProcessed = bytes, keys
+	if now := time.Now(); now.Sub(r.last) >= r.interval {
+		r.last = now
+		r.send(now)
+	}
+}
+
+// end reports the progress at the end of the phase.
+func (r *restoreProgress) end() {
+	r.send(time.Now())
+}
+
+func (r *restoreProgress) send(now time.Time) {
+	if r.report == nil {
+		return
+	}
+	p := r.p
+	p.Elapsed = now.Sub(r.start)
+	if done := p.BytesWritten - r.phaseFrom; done > 0 && p.TotalBytes > p.BytesWritten {
+		rate := float64(done) / now.Sub(r.phaseStart).Seconds()
+		p.ETA = time.Duration(float64(p.TotalBytes-p.BytesWritten) / rate * float64(time.Second)).Round(time.Second)
+	}
+	r.report(p)
+}
+
+// progressReader reports the bytes read through it.
+type progressReader struct {
+	io.Reader
+	progress *restoreProgress
+	n        int64
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.n += int64(n)
+	r.progress.update(r.n, 0)
+	return n, err
+}
+
+// The stages of a restore, each one over once saved in its restoreState.
+const (
+	// restoreStageCopy copies the snapshot file, from restoreState.Copied.
+	restoreStageCopy = iota
+	// restoreStageCopied has the snapshot file copied and verified.
+	restoreStageCopied
+	// restoreStagePrepared has the copy ready to be part of the new
+	// cluster; only the WAL and the raft snapshot are left to write.
+	restoreStagePrepared
+)
+
+// restoreState is the state of a restore, saved in the data directory so that
+// an interrupted restore can be resumed rather than started over.
+type restoreState struct {
+	SnapshotPath    string    `json:"snapshot-path"`
+	SnapshotSize    int64     `json:"snapshot-size"`
+	SnapshotModTime time.Time `json:"snapshot-mod-time"`
+
+	Stage int `json:"stage"`
+	// Copied is the number of bytes of the snapshot file copied and synced.
+	Copied int64 `json:"copied"`
+}
+
+func newRestoreState(snapshotPath string) (*restoreState, error) {
+	fi, err := os.Stat(snapshotPath)
+	if err != nil {
+		return nil, err
+	}
+	return &restoreState{SnapshotPath: snapshotPath, SnapshotSize: fi.Size(), SnapshotModTime: fi.ModTime().UTC()}, nil
+}
+
+// readRestoreState returns the state of the restore interrupted in dataDir,
+// or os.ErrNotExist if there is none.
+func readRestoreState(dataDir string) (*restoreState, error) {
+	b, err := os.ReadFile(filepath.Join(dataDir, restoreStateFile))
+	if err != nil {
+		return nil, err
+	}
+	st := &restoreState{}
+	if err = json.Unmarshal(b, st); err != nil {
+		return nil, fmt.Errorf("invalid restore state %q: %w", filepath.Join(dataDir, restoreStateFile), err)
+	}
+	return st, nil
+}
+
+// sameSnapshot returns an error if st is not the state of a restore of the
+// same snapshot file as cur.
+func (st *restoreState) sameSnapshot(cur *restoreState) error {
+	if st.SnapshotSize != cur.SnapshotSize || !st.SnapshotModTime.Equal(cur.SnapshotModTime) {
+		return fmt.Errorf("the interrupted restore is of snapshot %q (size %d, modified %v), not of %q (size %d, modified %v)",
+			st.SnapshotPath, st.SnapshotSize, st.SnapshotModTime, cur.SnapshotPath, cur.SnapshotSize, cur.SnapshotModTime)
+	}
+	return nil
+}
+
+// save atomically writes the state to dataDir.
+func (st *restoreState) save(dataDir string) error {
+	b, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dataDir, restoreStateFile)
+	f, err := os.OpenFile(path+".tmp", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(b); err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(path+".tmp", path)
+}
+
+func removeRestoreState(dataDir string) error {
+	err := os.Remove(filepath.Join(dataDir, restoreStateFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// copyAndVerifyDBFileFrom is copyAndVerifyDBFile resuming the copy at the
+// offset from of the snapshot file, the bytes before it being already copied
+// to outDbPath. checkpoint, if not nil, is called with the offset of the copy
+// every restoreCheckpointBytes, once the bytes before it are synced.
+func copyAndVerifyDBFileFrom(srcDbPath, outDbPath string, skipHashCheck bool, from int64, progress *restoreProgress, checkpoint func(int64) error) error {
+	srcf, ferr := os.Open(srcDbPath)
+	if ferr != nil {
+		return ferr
+	}
+	defer srcf.Close()
+	fi, ferr := srcf.Stat()
+	if ferr != nil {
+		return ferr
+	}
+	total := fi.Size()
+
+	// get snapshot integrity hash
+	if _, err := srcf.Seek(-sha256.Size, io.SeekEnd); err != nil {
+		return err
+	}
+	sha := make([]byte, sha256.Size)
+	if _, err := srcf.Read(sha); err != nil {
+		return err
+	}
+	if _, err := srcf.Seek(from, io.SeekStart); err != nil {
+		return err
+	}
+
+	db, dberr := os.OpenFile(outDbPath, os.O_RDWR|os.O_CREATE, 0o600)
+	if dberr != nil {
+		return dberr
+	}
+	defer db.Close()
+	if err := db.Truncate(from); err != nil {
+		return err
+	}
+	if _, err := db.Seek(from, io.SeekStart); err != nil {
+		return err
+	}
+
+	progress.begin(RestorePhaseCopy, from, total)
+	buf := make([]byte, restoreCopyBufferSize)
+	copied, checkpointed := from, from
+	for {
+		n, err := srcf.Read(buf)
+		if n > 0 {
+			if _, werr := db.Write(buf[:n]); werr != nil {
+				return werr
+			}
+			copied += int64(n)
+			progress.update(copied, 0)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if checkpoint != nil && copied-checkpointed >= restoreCheckpointBytes {
+			if err = db.Sync(); err != nil {
+				return err
+			}
+			if err = checkpoint(copied); err != nil {
+				return err
+			}
+			checkpointed = copied
+		}
+	}
+	progress.end()
+
+	// truncate away integrity hash, if any.
+	off, serr := db.Seek(0, io.SeekEnd)
+	if serr != nil {
+		return serr
+	}
+	hasHash := hasChecksum(off)
+	if hasHash {
+		if err := db.Truncate(off - sha256.Size); err != nil {
+			return err
+		}
+	}
+
+	if !hasHash && !skipHashCheck {
+		return fmt.Errorf("snapshot missing hash but --skip-hash-check=false")
+	}
+
+	if hasHash && !skipHashCheck {
+		// check for match
+		if _, err := db.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		progress.begin(RestorePhaseVerify, 0, off-sha256.Size)
+		h := sha256.New()
+		if _, err := io.Copy(h, &progressReader{Reader: db, progress: progress}); err != nil {
+			return err
+		}
+		progress.end()
+		dbsha := h.Sum(nil)
+		if !reflect.DeepEqual(sha, dbsha) {
+			return fmt.Errorf("expected sha256 %v, got %v", sha, dbsha)
+		}
+	}
+
+	// db hash is OK, can now modify DB so it can be part of a new cluster
+
+	return nil
+}
+
+// countKeys goes through the key revisions of the backend, reporting them.
+func countKeys(be backend.Backend, progress *restoreProgress) error {
+	progress.begin(RestorePhaseKeys, 0, 0)
+	var keys int64
+	tx := be.ReadTx()
+	tx.RLock()
+	defer tx.RUnlock()
+	err := tx.UnsafeForEach(schema.Key, func(_, _ []byte) error {
+		keys++
+		progress.update(0, keys)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	progress.end()
+	return nil
+}
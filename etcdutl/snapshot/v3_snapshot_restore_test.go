@@ -0,0 +1,148 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"crypto/ed25519"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/client/v3/snapshot"
+	"go.etcd.io/etcd/server/v3/embed"
+)
+
+func TestSnapshotRestoreResume(t *testing.T) {
+	dbpath := createDB(t, insertKeys(t, 10, 100))
+	src, err := os.ReadFile(dbpath)
+	require.NoError(t, err)
+
+	// an interrupted restore, with bytes copied past its last checkpoint.
+	dataDir := filepath.Join(t.TempDir(), "default.etcd")
+	snapDir := filepath.Join(dataDir, "member", "snap")
+	require.NoError(t, os.MkdirAll(snapDir, 0o700))
+	copied := int64(len(src) / 2)
+	require.NoError(t, os.WriteFile(filepath.Join(snapDir, "db"), append(src[:copied:copied], "garbage"...), 0o600))
+	state, err := newRestoreState(dbpath)
+	require.NoError(t, err)
+	state.Copied = copied
+	require.NoError(t, state.save(dataDir))
+
+	m := NewV3(zaptest.NewLogger(t))
+	cfg := RestoreConfig{
+		SnapshotPath:        dbpath,
+		Name:                "default",
+		OutputDataDir:       dataDir,
+		PeerURLs:            []string{"http://localhost:2380"},
+		InitialCluster:      "default=http://localhost:2380",
+		InitialClusterToken: "etcd-cluster",
+		SkipHashCheck:       true,
+	}
+	require.ErrorContains(t, m.Restore(cfg), "interrupted restore")
+
+	var progress []RestoreProgress
+	cfg.Resume = true
+	cfg.Progress = func(p RestoreProgress) { progress = append(progress, p) }
+	require.NoError(t, m.Restore(cfg))
+	require.NoFileExists(t, filepath.Join(dataDir, restoreStateFile))
+
+	phases := make(map[string]RestoreProgress)
+	for _, p := range progress {
+		phases[p.Phase] = p
+	}
+	assert.Equal(t, int64(len(src)), phases[RestorePhaseCopy].BytesWritten)
+	assert.Equal(t, int64(len(src)), phases[RestorePhaseCopy].TotalBytes)
+	assert.Equal(t, int64(10), phases[RestorePhaseKeys].KeysProcessed)
+	assert.Equal(t, RestorePhaseDone, progress[len(progress)-1].Phase)
+
+	ecfg := embed.NewConfig()
+	ecfg.LogLevel = "fatal"
+	ecfg.Dir = dataDir
+	etcd, err := embed.StartEtcd(ecfg)
+	require.NoError(t, err)
+	defer etcd.Close()
+	select {
+	case <-etcd.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		t.FailNow()
+	}
+	resp, err := etcd.Server.Range(t.Context(), &etcdserverpb.RangeRequest{Key: []byte{0}, RangeEnd: []byte{0}, CountOnly: true})
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), resp.Count)
+}
+
+func TestSnapshotRestoreResumeOtherSnapshot(t *testing.T) {
+	dbpath := createDB(t, insertKeys(t, 1, 0))
+
+	dataDir := filepath.Join(t.TempDir(), "default.etcd")
+	require.NoError(t, os.MkdirAll(dataDir, 0o700))
+	state, err := newRestoreState(dbpath)
+	require.NoError(t, err)
+	state.SnapshotSize++
+	require.NoError(t, state.save(dataDir))
+
+	err = NewV3(zaptest.NewLogger(t)).Restore(RestoreConfig{
+		SnapshotPath:        dbpath,
+		Name:                "default",
+		OutputDataDir:       dataDir,
+		PeerURLs:            []string{"http://localhost:2380"},
+		InitialCluster:      "default=http://localhost:2380",
+		InitialClusterToken: "etcd-cluster",
+		SkipHashCheck:       true,
+		Resume:              true,
+	})
+	require.ErrorContains(t, err, "the interrupted restore is of snapshot")
+}
+
+func TestSnapshotRestoreManifest(t *testing.T) {
+	dbpath := createDB(t, insertKeys(t, 10, 100))
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	otherPub, _, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	m := NewV3(zaptest.NewLogger(t))
+	manifest, err := m.WriteManifest(dbpath, priv)
+	require.NoError(t, err)
+	assert.Equal(t, int64(11), manifest.Revision)
+
+	restore := func(verifyKey ed25519.PublicKey) error {
+		return m.Restore(RestoreConfig{
+			SnapshotPath:        dbpath,
+			Name:                "default",
+			OutputDataDir:       filepath.Join(t.TempDir(), "default.etcd"),
+			PeerURLs:            []string{"http://localhost:2380"},
+			InitialCluster:      "default=http://localhost:2380",
+			InitialClusterToken: "etcd-cluster",
+			SkipHashCheck:       true,
+			ManifestPath:        dbpath + snapshot.ManifestSuffix,
+			VerifyKey:           verifyKey,
+		})
+	}
+	require.ErrorIs(t, restore(otherPub), snapshot.ErrManifestBadSignature)
+	require.NoError(t, restore(pub))
+
+	// a truncated snapshot is not restored
+	src, err := os.ReadFile(dbpath)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(dbpath, src[:len(src)-1], 0o600))
+	require.ErrorIs(t, restore(pub), snapshot.ErrManifestMismatch)
+}
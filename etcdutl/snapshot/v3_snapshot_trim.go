@@ -0,0 +1,256 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+// trimBatchLimit is the number of key revisions deleted per backend
+// transaction.
+const trimBatchLimit = 10000
+
+// TrimConfig configures snapshot trim operation.
+type TrimConfig struct {
+	// SnapshotPath is the path of snapshot file to trim.
+	SnapshotPath string
+	// OutputPath is the path of the trimmed snapshot file. It must not exist.
+	OutputPath string
+
+	// SkipHashCheck is "true" to ignore snapshot integrity hash value
+	// (required if copied from data directory).
+	SkipHashCheck bool
+
+	// RemovePrefixes are the prefixes of the keys removed with all their
+	// revisions.
+	RemovePrefixes []string
+	// DropAuth is "true" to disable authentication and remove the users, the
+	// roles and the revoked tokens.
+	DropAuth bool
+	// LeaseTTL is the TTL in seconds the leases are reset to, clearing their
+	// checkpointed remaining TTL. If 0, leases are kept as they are.
+	LeaseTTL int64
+
+	// KEKProvider, if set, unwraps the data encryption keys of an encrypted
+	// snapshot, which cannot be trimmed without it.
+	KEKProvider encryption.KEKProvider
+}
+
+// TrimResult is the outcome of a snapshot trim.
+type TrimResult struct {
+	// RemovedRevisions is the number of key revisions removed.
+	RemovedRevisions int
+	// ResetLeases is the number of leases whose TTL was reset.
+	ResetLeases int
+}
+
+// Trim writes a copy of the snapshot file without the keys of the given
+// prefixes, the auth data or the lease TTLs, depending on the config. The
+// copy is defragmented and ends with its integrity hash, so that it can be
+// restored like a saved snapshot.
+func (s *v3Manager) Trim(cfg TrimConfig) (res TrimResult, err error) {
+	if len(cfg.RemovePrefixes) == 0 && !cfg.DropAuth && cfg.LeaseTTL == 0 {
+		return res, errors.New("nothing to trim")
+	}
+	if cfg.LeaseTTL < 0 {
+		return res, fmt.Errorf("invalid lease TTL %d", cfg.LeaseTTL)
+	}
+	if fileutil.Exist(cfg.OutputPath) {
+		return res, fmt.Errorf("output %q exists", cfg.OutputPath)
+	}
+
+	partPath := cfg.OutputPath + ".part"
+	defer func() {
+		if err != nil {
+			os.Remove(partPath)
+		}
+	}()
+	if err = copyAndVerifyDBFile(cfg.SnapshotPath, partPath, cfg.SkipHashCheck); err != nil {
+		return res, err
+	}
+
+	s.lg.Info(
+		"trimming snapshot",
+		zap.String("path", cfg.SnapshotPath),
+		zap.String("output", cfg.OutputPath),
+		zap.Strings("remove-prefixes", cfg.RemovePrefixes),
+		zap.Bool("drop-auth", cfg.DropAuth),
+		zap.Int64("lease-ttl", cfg.LeaseTTL),
+	)
+
+	be, err := openBackend(s.lg, partPath, cfg.KEKProvider)
+	if err != nil {
+		return res, err
+	}
+	if len(cfg.RemovePrefixes) > 0 {
+		if res.RemovedRevisions, err = s.removePrefixes(be, cfg.RemovePrefixes); err != nil {
+			be.Close()
+			return res, err
+		}
+	}
+	if cfg.DropAuth {
+		dropAuth(s.lg, be)
+	}
+	if cfg.LeaseTTL > 0 {
+		res.ResetLeases = resetLeaseTTLs(be, cfg.LeaseTTL)
+	}
+	be.ForceCommit()
+	if err = be.Defrag(); err != nil {
+		be.Close()
+		return res, err
+	}
+	if err = be.Close(); err != nil {
+		return res, err
+	}
+
+	if err = AppendDBHash(partPath); err != nil {
+		return res, err
+	}
+	if err = os.Rename(partPath, cfg.OutputPath); err != nil {
+		return res, err
+	}
+
+	s.lg.Info(
+		"trimmed snapshot",
+		zap.String("path", cfg.SnapshotPath),
+		zap.String("output", cfg.OutputPath),
+		zap.Int("removed-revisions", res.RemovedRevisions),
+		zap.Int("reset-leases", res.ResetLeases),
+	)
+	return res, nil
+}
+
+// removePrefixes deletes all the revisions of the keys with one of the
+// prefixes. If the latest revision is deleted, an empty revision is put in its
+// place, like a revision bump does, so that the revision of the restored
+// cluster does not go backwards.
+func (s *v3Manager) removePrefixes(be backend.Backend, prefixes []string) (int, error) {
+	var latest mvcc.Revision
+	var revs [][]byte
+	tx := be.BatchTx()
+	tx.LockOutsideApply()
+	err := tx.UnsafeForEach(schema.Key, func(k, v []byte) error {
+		rev := mvcc.BytesToRev(k)
+		if rev.GreaterThan(latest) {
+			latest = rev
+		}
+		if len(v) == 0 {
+			return nil
+		}
+		var kv mvccpb.KeyValue
+		if err := kv.Unmarshal(v); err != nil {
+			return fmt.Errorf("cannot unmarshal value, key: %q err: %w", k, err)
+		}
+		for _, prefix := range prefixes {
+			if bytes.HasPrefix(kv.Key, []byte(prefix)) {
+				revs = append(revs, bytes.Clone(k))
+				break
+			}
+		}
+		return nil
+	})
+	tx.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := len(revs)
+	latestRemoved := removed > 0 && mvcc.BytesToRev(revs[removed-1]) == latest
+	for len(revs) > 0 {
+		n := min(len(revs), trimBatchLimit)
+		tx.LockOutsideApply()
+		for _, k := range revs[:n] {
+			tx.UnsafeDelete(schema.Key, k)
+		}
+		tx.Unlock()
+		be.ForceCommit()
+		revs = revs[n:]
+	}
+	if latestRemoved {
+		s.lg.Info("keeping the latest revision", zap.Int64("revision", latest.Main))
+		tx.LockOutsideApply()
+		tx.UnsafePut(schema.Key, mvcc.RevToBytes(latest, mvcc.NewRevBytes()), []byte{})
+		tx.Unlock()
+	}
+	return removed, nil
+}
+
+// dropAuth disables authentication, and deletes the users, the roles and the
+// revoked tokens.
+func dropAuth(lg *zap.Logger, be backend.Backend) {
+	// the auth batch transaction wraps the backend batch transaction, locked
+	// here as it's used outside of the apply loop.
+	tx := be.BatchTx()
+	tx.LockOutsideApply()
+	defer tx.Unlock()
+	atx := schema.NewAuthBackend(lg, be).BatchTx()
+	atx.UnsafeSaveAuthEnabled(false)
+	for _, u := range atx.UnsafeGetAllUsers() {
+		atx.UnsafeDeleteUser(string(u.Name))
+	}
+	for _, r := range atx.UnsafeGetAllRoles() {
+		atx.UnsafeDeleteRole(string(r.Name))
+	}
+	for hash := range atx.UnsafeGetAllRevokedTokens() {
+		atx.UnsafeDeleteRevokedToken([]byte(hash))
+	}
+	atx.UnsafeSaveAuthRevision(atx.UnsafeReadAuthRevision() + 1)
+}
+
+// resetLeaseTTLs sets the TTL of all the leases to ttl, and clears their
+// checkpointed remaining TTL. It returns the number of leases.
+func resetLeaseTTLs(be backend.Backend, ttl int64) int {
+	tx := be.BatchTx()
+	tx.LockOutsideApply()
+	defer tx.Unlock()
+	leases := schema.MustUnsafeGetAllLeases(tx)
+	for _, l := range leases {
+		l.TTL, l.RemainingTTL = ttl, 0
+		schema.MustUnsafePutLease(tx, l)
+	}
+	return len(leases)
+}
+
+// AppendDBHash appends the sha256 integrity hash of the db file to it, so
+// that it can be restored like a saved snapshot.
+func AppendDBHash(dbPath string) error {
+	f, err := os.OpenFile(dbPath, os.O_RDWR, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return err
+	}
+	if _, err = f.Write(h.Sum(nil)); err != nil {
+		return err
+	}
+	return f.Sync()
+}
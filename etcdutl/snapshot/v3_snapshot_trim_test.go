@@ -0,0 +1,111 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/embed"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+)
+
+// TestSnapshotTrim ensures a trimmed snapshot restores without the removed
+// keys and the auth data, with the lease TTLs reset and without the revision
+// going backwards.
+func TestSnapshotTrim(t *testing.T) {
+	var leaseID int64
+	dbpath := createDB(t, func(srv *etcdserver.EtcdServer) {
+		lresp, err := srv.LeaseGrant(t.Context(), &etcdserverpb.LeaseGrantRequest{TTL: 5})
+		require.NoError(t, err)
+		leaseID = lresp.ID
+		for _, key := range []string{"a/1", "b/1", "a/2", "a/1"} {
+			_, err = srv.Put(t.Context(), &etcdserverpb.PutRequest{Key: []byte(key), Value: []byte("v"), Lease: leaseID})
+			require.NoError(t, err)
+		}
+		_, err = srv.UserAdd(t.Context(), &etcdserverpb.AuthUserAddRequest{Name: "root", Password: "pass"})
+		require.NoError(t, err)
+		_, err = srv.RoleAdd(t.Context(), &etcdserverpb.AuthRoleAddRequest{Name: "root"})
+		require.NoError(t, err)
+		_, err = srv.UserGrantRole(t.Context(), &etcdserverpb.AuthUserGrantRoleRequest{User: "root", Role: "root"})
+		require.NoError(t, err)
+		_, err = srv.AuthEnable(t.Context(), &etcdserverpb.AuthEnableRequest{})
+		require.NoError(t, err)
+	})
+	// revision 1 is the initial revision.
+	const latestRev = 5
+
+	out := filepath.Join(t.TempDir(), "trimmed.db")
+	m := NewV3(zap.NewNop())
+	res, err := m.Trim(TrimConfig{
+		SnapshotPath:   dbpath,
+		OutputPath:     out,
+		SkipHashCheck:  true,
+		RemovePrefixes: []string{"a/"},
+		DropAuth:       true,
+		LeaseTTL:       3600,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, TrimResult{RemovedRevisions: 3, ResetLeases: 1}, res)
+
+	_, err = m.Trim(TrimConfig{SnapshotPath: dbpath, OutputPath: out, SkipHashCheck: true, DropAuth: true})
+	require.ErrorContains(t, err, "exists")
+
+	dataDir := filepath.Join(t.TempDir(), "default.etcd")
+	require.NoError(t, m.Restore(RestoreConfig{
+		SnapshotPath:        out,
+		Name:                "default",
+		OutputDataDir:       dataDir,
+		PeerURLs:            []string{"http://localhost:2380"},
+		InitialCluster:      "default=http://localhost:2380",
+		InitialClusterToken: "etcd-cluster",
+	}))
+
+	cfg := embed.NewConfig()
+	cfg.LogLevel = "fatal"
+	cfg.Dir = dataDir
+	etcd, err := embed.StartEtcd(cfg)
+	require.NoError(t, err)
+	defer etcd.Close()
+	select {
+	case <-etcd.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		t.FailNow()
+	}
+	srv := etcd.Server
+
+	resp, err := srv.Range(t.Context(), &etcdserverpb.RangeRequest{Key: []byte{0}, RangeEnd: []byte{0}})
+	require.NoError(t, err)
+	require.Len(t, resp.Kvs, 1)
+	assert.Equal(t, "b/1", string(resp.Kvs[0].Key))
+	assert.GreaterOrEqual(t, resp.Header.Revision, int64(latestRev))
+
+	aresp, err := srv.AuthStatus(t.Context(), &etcdserverpb.AuthStatusRequest{})
+	require.NoError(t, err)
+	assert.False(t, aresp.Enabled)
+	_, err = srv.UserGet(t.Context(), &etcdserverpb.AuthUserGetRequest{Name: "root"})
+	require.Error(t, err)
+
+	lresp, err := srv.LeaseTimeToLive(t.Context(), &etcdserverpb.LeaseTimeToLiveRequest{ID: leaseID})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3600), lresp.GrantedTTL)
+	assert.Greater(t, lresp.TTL, int64(3000))
+}
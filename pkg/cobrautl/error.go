@@ -34,7 +34,16 @@ const (
 	ExitClusterNotHealthy = 5
 )
 
+// ErrorPrinter, if set, prints the error ExitWithError exits with instead
+// of the "Error:" line, e.g. in the machine-readable output format of a
+// command.
+var ErrorPrinter func(code int, err error)
+
 func ExitWithError(code int, err error) {
-	fmt.Fprintln(os.Stderr, "Error:", err)
+	if ErrorPrinter != nil {
+		ErrorPrinter(code, err)
+	} else {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+	}
 	os.Exit(code)
 }
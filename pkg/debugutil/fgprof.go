@@ -0,0 +1,317 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debugutil
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const HTTPPathFGProf = "/debug/fgprof"
+
+// fgprofHz is the rate the goroutines are sampled at by the full goroutine
+// profiler.
+const fgprofHz = 99
+
+// FGProfHandler returns the handler of the full goroutine profile, sampling
+// the stacks of all the goroutines, running or waiting (on I/O, locks,
+// channels...), for the number of seconds of the 'seconds' parameter
+// (default 30). The profile is in the pprof format, or in the folded stacks
+// format with the 'format=folded' parameter.
+func FGProfHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seconds := 30.0
+		if s := r.FormValue("seconds"); s != "" {
+			var err error
+			if seconds, err = strconv.ParseFloat(s, 64); err != nil || seconds <= 0 {
+				http.Error(w, "invalid seconds", http.StatusBadRequest)
+				return
+			}
+		}
+		format := r.FormValue("format")
+		if format != "" && format != "pprof" && format != "folded" {
+			http.Error(w, "invalid format, expected pprof or folded", http.StatusBadRequest)
+			return
+		}
+
+		d := time.Duration(seconds * float64(time.Second))
+		p := FGProfile(r.Context(), d)
+		if format == "folded" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			p.WriteFolded(w)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Disposition", `attachment; filename="fgprof"`)
+		p.WritePprof(w)
+	})
+}
+
+// WallclockProfile is the number of times each goroutine stack is sampled.
+type WallclockProfile struct {
+	start    time.Time
+	duration time.Duration
+	counts   map[[32]uintptr]int64
+}
+
+// FGProfile samples the stacks of all the goroutines but the calling one for
+// d, or until ctx is done.
+func FGProfile(ctx context.Context, d time.Duration) *WallclockProfile {
+	p := &WallclockProfile{start: time.Now(), counts: make(map[[32]uintptr]int64)}
+	ticker := time.NewTicker(time.Second / fgprofHz)
+	defer ticker.Stop()
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	var records []runtime.StackRecord
+	for {
+		select {
+		case <-ticker.C:
+			records = goroutineProfile(records)
+			// the goroutine calling runtime.GoroutineProfile is the first
+			// record, it is the profiler.
+			for _, r := range records[1:] {
+				p.counts[r.Stack0]++
+			}
+		case <-timer.C:
+			p.duration = time.Since(p.start)
+			return p
+		case <-ctx.Done():
+			p.duration = time.Since(p.start)
+			return p
+		}
+	}
+}
+
+func goroutineProfile(records []runtime.StackRecord) []runtime.StackRecord {
+	for {
+		n := runtime.NumGoroutine()
+		if cap(records) < n+10 {
+			records = make([]runtime.StackRecord, n+10)
+		}
+		records = records[:cap(records)]
+		if n, ok := runtime.GoroutineProfile(records); ok {
+			return records[:n]
+		}
+	}
+}
+
+// stackFrames returns the frames of stack, from the caller to the callee.
+func stackFrames(stack [32]uintptr) []runtime.Frame {
+	var pcs []uintptr
+	for _, pc := range stack {
+		if pc == 0 {
+			break
+		}
+		pcs = append(pcs, pc)
+	}
+	var fs []runtime.Frame
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		fs = append(fs, f)
+		if !more {
+			break
+		}
+	}
+	for i, j := 0, len(fs)-1; i < j; i, j = i+1, j-1 {
+		fs[i], fs[j] = fs[j], fs[i]
+	}
+	return fs
+}
+
+// WriteFolded writes p in the folded stacks format: a line per stack, with
+// the functions from the caller to the callee separated by semicolons,
+// followed by the number of samples of the stack.
+func (p *WallclockProfile) WriteFolded(w io.Writer) error {
+	lines := make([]string, 0, len(p.counts))
+	for stack, n := range p.counts {
+		var names []string
+		for _, f := range stackFrames(stack) {
+			names = append(names, strings.ReplaceAll(f.Function, ";", ":"))
+		}
+		lines = append(lines, fmt.Sprintf("%s %d", strings.Join(names, ";"), n))
+	}
+	sort.Strings(lines)
+	bw := bufio.NewWriter(w)
+	for _, l := range lines {
+		bw.WriteString(l)
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// WritePprof writes p in the gzipped protobuf pprof format, with the
+// number of samples and the wall clock time of each stack.
+func (p *WallclockProfile) WritePprof(w io.Writer) error {
+	e := newPprofEncoder()
+	period := int64(time.Second / fgprofHz)
+	e.valueType(pprofSampleType, "samples", "count")
+	e.valueType(pprofSampleType, "time", "nanoseconds")
+	for stack, n := range p.counts {
+		fs := stackFrames(stack)
+		ids := make([]uint64, 0, len(fs))
+		// the locations of a sample are from the callee to the caller
+		for i := len(fs) - 1; i >= 0; i-- {
+			ids = append(ids, e.location(fs[i]))
+		}
+		e.sample(ids, n, n*period)
+	}
+	e.int(pprofTimeNanos, p.start.UnixNano())
+	e.int(pprofDurationNanos, int64(p.duration))
+	e.valueType(pprofPeriodType, "wallclock", "nanoseconds")
+	e.int(pprofPeriod, period)
+
+	gz := gzip.NewWriter(w)
+	if _, err := gz.Write(e.bytes()); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// the field numbers of the pprof profile.proto messages
+const (
+	pprofSampleType    = 1
+	pprofSample        = 2
+	pprofLocation      = 4
+	pprofFunction      = 5
+	pprofStringTable   = 6
+	pprofTimeNanos     = 9
+	pprofDurationNanos = 10
+	pprofPeriodType    = 11
+	pprofPeriod        = 12
+)
+
+// pprofEncoder encodes a pprof profile, with a location and a function per
+// distinct frame.
+type pprofEncoder struct {
+	b         []byte
+	strings   map[string]int64
+	stringTab []string
+	locations map[pprofLine]uint64
+	functions map[string]uint64
+}
+
+type pprofLine struct {
+	function, file string
+	line           int
+}
+
+func newPprofEncoder() *pprofEncoder {
+	e := &pprofEncoder{
+		strings:   make(map[string]int64),
+		locations: make(map[pprofLine]uint64),
+		functions: make(map[string]uint64),
+	}
+	e.str("")
+	return e
+}
+
+func (e *pprofEncoder) str(s string) int64 {
+	if i, ok := e.strings[s]; ok {
+		return i
+	}
+	i := int64(len(e.stringTab))
+	e.strings[s] = i
+	e.stringTab = append(e.stringTab, s)
+	return i
+}
+
+func (e *pprofEncoder) int(field protowire.Number, v int64) {
+	e.b = protowire.AppendTag(e.b, field, protowire.VarintType)
+	e.b = protowire.AppendVarint(e.b, uint64(v))
+}
+
+func (e *pprofEncoder) message(field protowire.Number, m []byte) {
+	e.b = protowire.AppendTag(e.b, field, protowire.BytesType)
+	e.b = protowire.AppendBytes(e.b, m)
+}
+
+func (e *pprofEncoder) valueType(field protowire.Number, typ, unit string) {
+	var m []byte
+	m = appendVarintField(m, 1, uint64(e.str(typ)))
+	m = appendVarintField(m, 2, uint64(e.str(unit)))
+	e.message(field, m)
+}
+
+func (e *pprofEncoder) sample(locationIDs []uint64, values ...int64) {
+	var ids, vs []byte
+	for _, id := range locationIDs {
+		ids = protowire.AppendVarint(ids, id)
+	}
+	for _, v := range values {
+		vs = protowire.AppendVarint(vs, uint64(v))
+	}
+	var m []byte
+	m = protowire.AppendTag(m, 1, protowire.BytesType)
+	m = protowire.AppendBytes(m, ids)
+	m = protowire.AppendTag(m, 2, protowire.BytesType)
+	m = protowire.AppendBytes(m, vs)
+	e.message(pprofSample, m)
+}
+
+func (e *pprofEncoder) location(f runtime.Frame) uint64 {
+	l := pprofLine{function: f.Function, file: f.File, line: f.Line}
+	if id, ok := e.locations[l]; ok {
+		return id
+	}
+	fid, ok := e.functions[f.Function]
+	if !ok {
+		fid = uint64(len(e.functions) + 1)
+		e.functions[f.Function] = fid
+		var m []byte
+		m = appendVarintField(m, 1, fid)
+		m = appendVarintField(m, 2, uint64(e.str(f.Function)))
+		m = appendVarintField(m, 3, uint64(e.str(f.Function)))
+		m = appendVarintField(m, 4, uint64(e.str(f.File)))
+		e.message(pprofFunction, m)
+	}
+	id := uint64(len(e.locations) + 1)
+	e.locations[l] = id
+	var line []byte
+	line = appendVarintField(line, 1, fid)
+	line = appendVarintField(line, 2, uint64(f.Line))
+	var m []byte
+	m = appendVarintField(m, 1, id)
+	m = protowire.AppendTag(m, 4, protowire.BytesType)
+	m = protowire.AppendBytes(m, line)
+	e.message(pprofLocation, m)
+	return id
+}
+
+func (e *pprofEncoder) bytes() []byte {
+	b := e.b
+	for _, s := range e.stringTab {
+		b = protowire.AppendTag(b, pprofStringTable, protowire.BytesType)
+		b = protowire.AppendString(b, s)
+	}
+	return b
+}
+
+func appendVarintField(b []byte, field protowire.Number, v uint64) []byte {
+	b = protowire.AppendTag(b, field, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package debugutil
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func blockedOnChannel(c chan struct{}) {
+	<-c
+}
+
+func TestFGProfile(t *testing.T) {
+	c := make(chan struct{})
+	defer close(c)
+	go blockedOnChannel(c)
+
+	p := FGProfile(t.Context(), 100*time.Millisecond)
+
+	var folded bytes.Buffer
+	require.NoError(t, p.WriteFolded(&folded))
+	// the waiting goroutines are sampled too
+	assert.Contains(t, folded.String(), "debugutil.blockedOnChannel")
+	assert.NotContains(t, folded.String(), "debugutil.FGProfile ")
+
+	var b bytes.Buffer
+	require.NoError(t, p.WritePprof(&b))
+	gz, err := gzip.NewReader(&b)
+	require.NoError(t, err)
+	pb, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	assert.Contains(t, string(pb), "debugutil.blockedOnChannel")
+	assert.Contains(t, string(pb), "wallclock")
+}
+
+func TestProfileRates(t *testing.T) {
+	h := PProfHandlers()[HTTPPrefixPProf+"/rates"]
+	serve := func(method, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, HTTPPrefixPProf+"/rates", strings.NewReader(body)))
+		return w
+	}
+	defer serve(http.MethodPut, `{"block-profile-rate":0,"mutex-profile-fraction":5}`)
+
+	w := serve(http.MethodPut, `{"block-profile-rate":1000}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"block-profile-rate":1000,"mutex-profile-fraction":5}`, w.Body.String())
+
+	w = serve(http.MethodPut, `{"mutex-profile-fraction":0}`)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"block-profile-rate":1000,"mutex-profile-fraction":0}`, w.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodPut, `{"block-profile-rate":-1}`).Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodDelete, "").Code)
+}
@@ -15,9 +15,13 @@
 package debugutil
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/pprof"
 	"runtime"
+	"strconv"
+	"sync"
+	"time"
 )
 
 const HTTPPrefixPProf = "/debug/pprof"
@@ -36,12 +40,87 @@ func PProfHandlers() map[string]http.Handler {
 	m[HTTPPrefixPProf+"/profile"] = http.HandlerFunc(pprof.Profile)
 	m[HTTPPrefixPProf+"/symbol"] = http.HandlerFunc(pprof.Symbol)
 	m[HTTPPrefixPProf+"/cmdline"] = http.HandlerFunc(pprof.Cmdline)
-	m[HTTPPrefixPProf+"/trace"] = http.HandlerFunc(pprof.Trace)
+	m[HTTPPrefixPProf+"/trace"] = http.HandlerFunc(trace)
 	m[HTTPPrefixPProf+"/heap"] = pprof.Handler("heap")
 	m[HTTPPrefixPProf+"/goroutine"] = pprof.Handler("goroutine")
 	m[HTTPPrefixPProf+"/threadcreate"] = pprof.Handler("threadcreate")
 	m[HTTPPrefixPProf+"/block"] = pprof.Handler("block")
 	m[HTTPPrefixPProf+"/mutex"] = pprof.Handler("mutex")
+	m[HTTPPrefixPProf+"/rates"] = http.HandlerFunc(profileRates)
+	m[HTTPPathFGProf] = FGProfHandler()
 
 	return m
 }
+
+// trace serves the execution trace for the duration of the 'duration'
+// parameter (e.g. '500ms'), or of the 'seconds' parameter.
+func trace(w http.ResponseWriter, r *http.Request) {
+	if ds := r.FormValue("duration"); ds != "" {
+		d, err := time.ParseDuration(ds)
+		if err != nil || d <= 0 {
+			http.Error(w, "invalid duration", http.StatusBadRequest)
+			return
+		}
+		q := r.URL.Query()
+		q.Del("duration")
+		q.Set("seconds", strconv.FormatFloat(d.Seconds(), 'f', -1, 64))
+		r.URL.RawQuery = q.Encode()
+		r.Form = nil
+	}
+	pprof.Trace(w, r)
+}
+
+// ProfileRates are the rates of the block and mutex profiles.
+type ProfileRates struct {
+	// BlockProfileRate is the rate of the block profile, see
+	// runtime.SetBlockProfileRate. 0 disables the profile.
+	BlockProfileRate *int `json:"block-profile-rate,omitempty"`
+	// MutexProfileFraction is the rate of the mutex profile, see
+	// runtime.SetMutexProfileFraction. 0 disables the profile.
+	MutexProfileFraction *int `json:"mutex-profile-fraction,omitempty"`
+}
+
+var (
+	// blockProfileRateMu guards blockProfileRate, the runtime does not
+	// report the rate of the block profile.
+	blockProfileRateMu sync.Mutex
+	blockProfileRate   int
+)
+
+// profileRates returns the rates of the block and mutex profiles on GET and
+// changes the rates set in the body on PUT.
+func profileRates(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var rates ProfileRates
+		if err := json.NewDecoder(r.Body).Decode(&rates); err != nil {
+			http.Error(w, "error unmarshalling request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if (rates.BlockProfileRate != nil && *rates.BlockProfileRate < 0) || (rates.MutexProfileFraction != nil && *rates.MutexProfileFraction < 0) {
+			http.Error(w, "profile rates must not be negative", http.StatusBadRequest)
+			return
+		}
+		if rates.BlockProfileRate != nil {
+			blockProfileRateMu.Lock()
+			runtime.SetBlockProfileRate(*rates.BlockProfileRate)
+			blockProfileRate = *rates.BlockProfileRate
+			blockProfileRateMu.Unlock()
+		}
+		if rates.MutexProfileFraction != nil {
+			runtime.SetMutexProfileFraction(*rates.MutexProfileFraction)
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	blockProfileRateMu.Lock()
+	block := blockProfileRate
+	blockProfileRateMu.Unlock()
+	mutex := runtime.SetMutexProfileFraction(-1)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ProfileRates{BlockProfileRate: &block, MutexProfileFraction: &mutex})
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"flag"
+	"strconv"
+	"strings"
+)
+
+// Float64sValue implements a comma-separated float64 slice as "flag.Value"
+// interface.
+type Float64sValue []float64
+
+// Set parses a command line set of float64 values, separated by comma.
+// Implements "flag.Value" interface.
+func (fs *Float64sValue) Set(s string) error {
+	if s == "" {
+		*fs = Float64sValue{}
+		return nil
+	}
+	strs := strings.Split(s, ",")
+	vals := make(Float64sValue, 0, len(strs))
+	for _, str := range strs {
+		v, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+		if err != nil {
+			return err
+		}
+		vals = append(vals, v)
+	}
+	*fs = vals
+	return nil
+}
+
+// String implements "flag.Value" interface.
+func (fs *Float64sValue) String() string {
+	strs := make([]string, len(*fs))
+	for i, v := range *fs {
+		strs[i] = strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return strings.Join(strs, ",")
+}
+
+// NewFloat64sValue creates a Float64sValue with the given values.
+func NewFloat64sValue(vals ...float64) *Float64sValue {
+	fs := Float64sValue(vals)
+	return &fs
+}
+
+// Float64sFromFlag returns a float64 slice from the flag.
+func Float64sFromFlag(fs *flag.FlagSet, flagName string) []float64 {
+	return *fs.Lookup(flagName).Value.(*Float64sValue)
+}
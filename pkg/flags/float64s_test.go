@@ -0,0 +1,60 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package flags
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFloat64sValue(t *testing.T) {
+	cases := []struct {
+		s           string
+		expectedVal []float64
+		expectError bool
+	}{
+		{s: "0.0001,0.001,1", expectedVal: []float64{0.0001, 0.001, 1}},
+		{s: "0.5, 2", expectedVal: []float64{0.5, 2}},
+		{s: "", expectedVal: []float64{}},
+		{s: "0.1,fast", expectError: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.s, func(t *testing.T) {
+			var val Float64sValue
+			err := val.Set(tc.s)
+			if tc.expectError {
+				assert.Errorf(t, err, "Expected failure on parsing float64 values from %s", tc.s)
+			} else {
+				require.NoErrorf(t, err, "Unexpected error when parsing %s: %v", tc.s, err)
+				assert.Equal(t, tc.expectedVal, []float64(val))
+			}
+		})
+	}
+}
+
+func TestFloat64sFromFlag(t *testing.T) {
+	const flagName = "buckets"
+
+	fs := flag.NewFlagSet("etcd", flag.ContinueOnError)
+	fs.Var(NewFloat64sValue(1, 2), flagName, "")
+	assert.Equal(t, []float64{1, 2}, Float64sFromFlag(fs, flagName))
+	assert.Equal(t, "1,2", fs.Lookup(flagName).Value.String())
+
+	require.NoError(t, fs.Parse([]string{"--buckets=0.0005,0.25"}))
+	assert.Equal(t, []float64{0.0005, 0.25}, Float64sFromFlag(fs, flagName))
+}
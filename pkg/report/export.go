@@ -0,0 +1,104 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// hdrTicksPerHalfDistance is the number of percentiles reported between a
+// percentile and the one halfway to 100%, as HdrHistogram reports them.
+const hdrTicksPerHalfDistance = 5
+
+// WriteHDR writes the latencies, in milliseconds, in the percentile
+// distribution format of HdrHistogram, so that the results of runs can be
+// plotted and compared with the HdrHistogram tools.
+func (s Stats) WriteHDR(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "%12s %14s %10s %14s\n\n", "Value", "Percentile", "TotalCount", "1/(1-Percentile)")
+	n := len(s.Lats)
+	if n > 0 {
+		for p := 0.0; ; {
+			count := int(math.Ceil(p / 100 * float64(n)))
+			count = min(max(count, 1), n)
+			value := s.Lats[count-1] * 1000
+			if count == n {
+				fmt.Fprintf(bw, "%12.3f %2.12f %10d\n", value, 1.0, count)
+				break
+			}
+			fmt.Fprintf(bw, "%12.3f %2.12f %10d %14.2f\n", value, p/100, count, 1/(1-p/100))
+			// halve the distance to 100% every hdrTicksPerHalfDistance
+			// percentiles, as HdrHistogram does.
+			halvings := math.Floor(math.Log2(100/(100-p))) + 1
+			p += 100 / (hdrTicksPerHalfDistance * math.Pow(2, halvings))
+		}
+	}
+	fmt.Fprintf(bw, "#[Mean    = %12.3f, StdDeviation   = %12.3f]\n", s.Average*1000, s.Stddev*1000)
+	fmt.Fprintf(bw, "#[Max     = %12.3f, Total count    = %12d]\n", s.Slowest*1000, n)
+	return bw.Flush()
+}
+
+// openMetricsBuckets are the upper bounds, in seconds, of the buckets of
+// the latency histograms written by WriteOpenMetrics.
+var openMetricsBuckets = []float64{
+	0.0001, 0.0002, 0.0005, 0.001, 0.002, 0.005, 0.01, 0.02, 0.05,
+	0.1, 0.2, 0.5, 1, 2, 5, 10,
+}
+
+// WriteOpenMetrics writes the latencies of the series of stats in the
+// OpenMetrics text format, as the histogram '<name>_seconds' and the counter
+// '<name>_errors' of the failed requests, with the key of each series as
+// the value of the label, so that the results of runs can be compared
+// programmatically.
+func WriteOpenMetrics(w io.Writer, name, label string, series map[string]Stats) error {
+	bw := bufio.NewWriter(w)
+	keys := slices.Sorted(maps.Keys(series))
+
+	fmt.Fprintf(bw, "# TYPE %s_seconds histogram\n", name)
+	fmt.Fprintf(bw, "# UNIT %s_seconds seconds\n", name)
+	for _, k := range keys {
+		s := series[k]
+		lv := fmt.Sprintf("%s=%s", label, strconv.Quote(k))
+		i := 0
+		for _, le := range openMetricsBuckets {
+			for i < len(s.Lats) && s.Lats[i] <= le {
+				i++
+			}
+			fmt.Fprintf(bw, "%s_seconds_bucket{%s,le=\"%s\"} %d\n", name, lv, formatFloat(le), i)
+		}
+		fmt.Fprintf(bw, "%s_seconds_bucket{%s,le=\"+Inf\"} %d\n", name, lv, len(s.Lats))
+		fmt.Fprintf(bw, "%s_seconds_count{%s} %d\n", name, lv, len(s.Lats))
+		fmt.Fprintf(bw, "%s_seconds_sum{%s} %s\n", name, lv, formatFloat(s.AvgTotal))
+	}
+
+	fmt.Fprintf(bw, "# TYPE %s_errors counter\n", name)
+	for _, k := range keys {
+		var errs int
+		for _, n := range series[k].ErrorDist {
+			errs += n
+		}
+		fmt.Fprintf(bw, "%s_errors_total{%s=%s} %d\n", name, label, strconv.Quote(k), errs)
+	}
+	fmt.Fprintln(bw, "# EOF")
+	return bw.Flush()
+}
+
+func formatFloat(f float64) string { return strconv.FormatFloat(f, 'g', -1, 64) }
@@ -0,0 +1,96 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func statsOf(t *testing.T, lats []time.Duration, errs int) Stats {
+	r := NewReport("%f")
+	go func() {
+		start := time.Now()
+		for _, lat := range lats {
+			r.Results() <- Result{Start: start, End: start.Add(lat)}
+		}
+		for i := 0; i < errs; i++ {
+			r.Results() <- Result{Start: start, End: start, Err: errors.New("oops")}
+		}
+		close(r.Results())
+	}()
+	select {
+	case s := <-r.Stats():
+		return s
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the stats")
+	}
+	return Stats{}
+}
+
+func TestWriteHDR(t *testing.T) {
+	var lats []time.Duration
+	for i := 1; i <= 100; i++ {
+		lats = append(lats, time.Duration(i)*time.Millisecond)
+	}
+	var buf bytes.Buffer
+	require.NoError(t, statsOf(t, lats, 0).WriteHDR(&buf))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, strings.Fields("Value Percentile TotalCount 1/(1-Percentile)"), strings.Fields(lines[0]))
+	assert.Empty(t, lines[1])
+	assert.Equal(t, []string{"1.000", "0.000000000000", "1", "1.00"}, strings.Fields(lines[2]))
+	assert.Equal(t, []string{"50.000", "0.500000000000", "50", "2.00"}, strings.Fields(lines[7]))
+	// the last percentile is the slowest request
+	assert.Equal(t, []string{"100.000", "1.000000000000", "100"}, strings.Fields(lines[len(lines)-3]))
+	assert.Equal(t, "#[Mean    =       50.500, StdDeviation   =       28.866]", lines[len(lines)-2])
+	assert.Equal(t, "#[Max     =      100.000, Total count    =          100]", lines[len(lines)-1])
+}
+
+func TestWriteOpenMetrics(t *testing.T) {
+	series := map[string]Stats{
+		"put": statsOf(t, []time.Duration{time.Millisecond, 3 * time.Millisecond, 3 * time.Second}, 0),
+		"get": statsOf(t, []time.Duration{50 * time.Microsecond}, 2),
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WriteOpenMetrics(&buf, "benchmark_request", "op", series))
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "# TYPE benchmark_request_seconds histogram\n# UNIT benchmark_request_seconds seconds\n"))
+	for _, line := range []string{
+		`benchmark_request_seconds_bucket{op="get",le="0.0001"} 1`,
+		`benchmark_request_seconds_count{op="get"} 1`,
+		`benchmark_request_seconds_bucket{op="put",le="0.001"} 1`,
+		`benchmark_request_seconds_bucket{op="put",le="0.005"} 2`,
+		`benchmark_request_seconds_bucket{op="put",le="2"} 2`,
+		`benchmark_request_seconds_bucket{op="put",le="5"} 3`,
+		`benchmark_request_seconds_bucket{op="put",le="+Inf"} 3`,
+		`benchmark_request_seconds_sum{op="put"} 3.004`,
+		`# TYPE benchmark_request_errors counter`,
+		`benchmark_request_errors_total{op="get"} 2`,
+		`benchmark_request_errors_total{op="put"} 0`,
+	} {
+		assert.Contains(t, out, line+"\n")
+	}
+	// the series are sorted by their label
+	assert.Less(t, strings.Index(out, `op="get"`), strings.Index(out, `op="put"`))
+	assert.True(t, strings.HasSuffix(out, "# EOF\n"))
+}
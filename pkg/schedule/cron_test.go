@@ -0,0 +1,70 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCronInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+	} {
+		_, err := ParseCron(expr)
+		assert.Errorf(t, err, "expression %q", expr)
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// a Wednesday
+	now := time.Date(2025, time.January, 15, 10, 30, 20, 0, time.UTC)
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2025, time.January, 15, 10, 31, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2025, time.January, 16, 10, 30, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2025, time.January, 16, 2, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, time.January, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 1-5/2 * * *", time.Date(2025, time.January, 16, 1, 0, 0, 0, time.UTC)},
+		{"0 3 * * 0", time.Date(2025, time.January, 19, 3, 0, 0, 0, time.UTC)},
+		{"0 3 * * 7", time.Date(2025, time.January, 19, 3, 0, 0, 0, time.UTC)},
+		{"0 3 * * 1-5", time.Date(2025, time.January, 16, 3, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		// either the day of the month or the day of the week
+		{"0 0 20 * 5", time.Date(2025, time.January, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		c, err := ParseCron(tt.expr)
+		require.NoError(t, err)
+		assert.Equalf(t, tt.want, c.Next(now), "expression %q", tt.expr)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package watermark tracks the high-watermark of a value over time.
+package watermark
+
+import "sync/atomic"
+
+// Watermark records the current value of a quantity and the highest value it
+// has reached since the last Reset. It is safe for concurrent use and its zero
+// value is ready to use.
+type Watermark struct {
+	cur  atomic.Int64
+	peak atomic.Int64
+}
+
+// Add adjusts the current value by delta, raising the peak if needed.
+func (w *Watermark) Add(delta int64) {
+	w.raise(w.cur.Add(delta))
+}
+
+// Set replaces the current value with v, raising the peak if needed.
+func (w *Watermark) Set(v int64) {
+	w.cur.Store(v)
+	w.raise(v)
+}
+
+// Value returns the current value.
+func (w *Watermark) Value() int64 {
+	return w.cur.Load()
+}
+
+// Peak returns the highest value observed since the last Reset.
+func (w *Watermark) Peak() int64 {
+	return w.peak.Load()
+}
+
+// Reset lowers the peak to the current value and returns the previous peak.
+func (w *Watermark) Reset() int64 {
+	return w.peak.Swap(w.cur.Load())
+}
+
+func (w *Watermark) raise(v int64) {
+	for {
+		p := w.peak.Load()
+		if v <= p || w.peak.CompareAndSwap(p, v) {
+			return
+		}
+	}
+}
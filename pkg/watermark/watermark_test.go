@@ -0,0 +1,59 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package watermark
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestWatermark(t *testing.T) {
+	var w Watermark
+	w.Add(3)
+	w.Add(-2)
+	if w.Value() != 1 || w.Peak() != 3 {
+		t.Fatalf("value/peak = %d/%d, want 1/3", w.Value(), w.Peak())
+	}
+	w.Set(2)
+	if w.Peak() != 3 {
+		t.Fatalf("peak = %d, want 3", w.Peak())
+	}
+	if p := w.Reset(); p != 3 {
+		t.Fatalf("reset returned %d, want 3", p)
+	}
+	if w.Peak() != 2 {
+		t.Fatalf("peak after reset = %d, want 2", w.Peak())
+	}
+	w.Set(5)
+	if w.Peak() != 5 {
+		t.Fatalf("peak = %d, want 5", w.Peak())
+	}
+}
+
+func TestWatermarkConcurrentAdd(t *testing.T) {
+	var w Watermark
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.Add(1)
+		}()
+	}
+	wg.Wait()
+	if w.Value() != 100 || w.Peak() != 100 {
+		t.Fatalf("value/peak = %d/%d, want 100/100", w.Value(), w.Peak())
+	}
+}
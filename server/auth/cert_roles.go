@@ -0,0 +1,115 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/x509"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+)
+
+const (
+	CertAttributeCN  = "cn"
+	CertAttributeOU  = "ou"
+	CertAttributeSAN = "san"
+
+	// CertAttributeSPIFFE is the SPIFFE ID of an X.509-SVID.
+	CertAttributeSPIFFE = "spiffe"
+)
+
+// CertRoleRule grants Role to the clients authenticated by a TLS certificate
+// whose Attribute matches Pattern. Patterns use the syntax of path.Match.
+type CertRoleRule struct {
+	Attribute string
+	Pattern   string
+	Role      string
+}
+
+// ParseCertRoleRules parses rules given as '<attribute>:<pattern>=<role>',
+// where attribute is one of 'cn', 'ou', 'san' or 'spiffe'. The SAN of a
+// certificate are its DNS names, email addresses, IP addresses and URIs.
+func ParseCertRoleRules(rules []string) ([]CertRoleRule, error) {
+	var parsed []CertRoleRule
+	for _, s := range rules {
+		attr, rest, ok := strings.Cut(s, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid certificate role rule %q: missing attribute", s)
+		}
+		i := strings.LastIndex(rest, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid certificate role rule %q: missing role", s)
+		}
+		r := CertRoleRule{Attribute: strings.ToLower(attr), Pattern: rest[:i], Role: rest[i+1:]}
+		switch r.Attribute {
+		case CertAttributeCN, CertAttributeOU, CertAttributeSAN, CertAttributeSPIFFE:
+		default:
+			return nil, fmt.Errorf("invalid certificate role rule %q: unknown attribute %q", s, attr)
+		}
+		if r.Pattern == "" || r.Role == "" {
+			return nil, fmt.Errorf("invalid certificate role rule %q: empty pattern or role", s)
+		}
+		if _, err := path.Match(r.Pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid certificate role rule %q: %w", s, err)
+		}
+		parsed = append(parsed, r)
+	}
+	return parsed, nil
+}
+
+func (r *CertRoleRule) values(cert *x509.Certificate) []string {
+	switch r.Attribute {
+	case CertAttributeCN:
+		return []string{cert.Subject.CommonName}
+	case CertAttributeOU:
+		return cert.Subject.OrganizationalUnit
+	case CertAttributeSAN:
+		values := slices.Concat(cert.DNSNames, cert.EmailAddresses)
+		for _, ip := range cert.IPAddresses {
+			values = append(values, ip.String())
+		}
+		for _, u := range cert.URIs {
+			values = append(values, u.String())
+		}
+		return values
+	case CertAttributeSPIFFE:
+		return []string{transport.SPIFFEID(cert)}
+	}
+	return nil
+}
+
+// matchCertRoles returns the sorted roles granted to cert by rules, and the
+// first attribute value that matched.
+func matchCertRoles(rules []CertRoleRule, cert *x509.Certificate) (roles []string, matched string) {
+	for _, r := range rules {
+		for _, v := range r.values(cert) {
+			if ok, _ := path.Match(r.Pattern, v); !ok || v == "" {
+				continue
+			}
+			if matched == "" {
+				matched = v
+			}
+			if !slices.Contains(roles, r.Role) {
+				roles = append(roles, r.Role)
+			}
+			break
+		}
+	}
+	slices.Sort(roles)
+	return roles, matched
+}
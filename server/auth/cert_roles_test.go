@@ -0,0 +1,155 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestParseCertRoleRules(t *testing.T) {
+	rules, err := ParseCertRoleRules([]string{"cn:app-*=app", "OU:ops=root", "san:spiffe://cluster/ns/*/sa/reader?a=b=reader", "spiffe:spiffe://cluster/*=spiffe"})
+	require.NoError(t, err)
+	require.Equal(t, []CertRoleRule{
+		{Attribute: CertAttributeCN, Pattern: "app-*", Role: "app"},
+		{Attribute: CertAttributeOU, Pattern: "ops", Role: "root"},
+		{Attribute: CertAttributeSAN, Pattern: "spiffe://cluster/ns/*/sa/reader?a=b", Role: "reader"},
+		{Attribute: CertAttributeSPIFFE, Pattern: "spiffe://cluster/*", Role: "spiffe"},
+	}, rules)
+
+	for _, rule := range []string{
+		"app-*=app",
+		"cn:app-*",
+		"o:app-*=app",
+		"cn:=app",
+		"cn:app-*=",
+		"cn:app-[=app",
+	} {
+		_, err = ParseCertRoleRules([]string{rule})
+		require.Errorf(t, err, "expected rule %q to be invalid", rule)
+	}
+}
+
+func TestMatchCertRoles(t *testing.T) {
+	rules, err := ParseCertRoleRules([]string{
+		"cn:app-*=app",
+		"ou:ops=root",
+		"san:*.reader.example.com=reader",
+		"san:spiffe://cluster/*=spiffe",
+		"ou:*=app",
+		"spiffe:spiffe://prod/ns/*=prod",
+	})
+	require.NoError(t, err)
+
+	u, err := url.Parse("spiffe://cluster/workload")
+	require.NoError(t, err)
+	prod, err := url.Parse("spiffe://prod/ns/etcd")
+	require.NoError(t, err)
+	tests := []struct {
+		name        string
+		cert        *x509.Certificate
+		wantRoles   []string
+		wantMatched string
+	}{
+		{
+			name:        "common name",
+			cert:        &x509.Certificate{Subject: pkix.Name{CommonName: "app-1"}},
+			wantRoles:   []string{"app"},
+			wantMatched: "app-1",
+		},
+		{
+			name:        "organizational units",
+			cert:        &x509.Certificate{Subject: pkix.Name{CommonName: "alice", OrganizationalUnit: []string{"dev", "ops"}}},
+			wantRoles:   []string{"app", "root"},
+			wantMatched: "ops",
+		},
+		{
+			name:        "DNS name",
+			cert:        &x509.Certificate{DNSNames: []string{"a.reader.example.com"}},
+			wantRoles:   []string{"reader"},
+			wantMatched: "a.reader.example.com",
+		},
+		{
+			name:        "URI",
+			cert:        &x509.Certificate{URIs: []*url.URL{u}},
+			wantRoles:   []string{"spiffe"},
+			wantMatched: "spiffe://cluster/workload",
+		},
+		{
+			name:        "SPIFFE ID",
+			cert:        &x509.Certificate{URIs: []*url.URL{prod}},
+			wantRoles:   []string{"prod"},
+			wantMatched: "spiffe://prod/ns/etcd",
+		},
+		{
+			name:        "several URIs are not a SPIFFE ID, matched by SAN rules only",
+			cert:        &x509.Certificate{URIs: []*url.URL{prod, u}},
+			wantRoles:   []string{"spiffe"},
+			wantMatched: "spiffe://cluster/workload",
+		},
+		{
+			name: "no match",
+			cert: &x509.Certificate{Subject: pkix.Name{CommonName: "alice"}, DNSNames: []string{"reader.example.com"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roles, matched := matchCertRoles(rules, tt.cert)
+			require.Equal(t, tt.wantRoles, roles)
+			require.Equal(t, tt.wantMatched, matched)
+		})
+	}
+}
+
+func TestAuthInfoFromTLSCertRoles(t *testing.T) {
+	tp, err := NewTokenProvider(zaptest.NewLogger(t), tokenTypeSimple, dummyIndexWaiter, simpleTokenTTLDefault)
+	require.NoError(t, err)
+	rules, err := ParseCertRoleRules([]string{"san:*.example.com=role-test"})
+	require.NoError(t, err)
+	as := NewAuthStore(zaptest.NewLogger(t), newBackendMock(), tp, bcrypt.MinCost, WithCertRoleRules(rules))
+	defer as.Close()
+
+	tlsCtx := func(cert *x509.Certificate) context.Context {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{
+			State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+		}})
+		return metadata.NewIncomingContext(ctx, metadata.MD{})
+	}
+
+	ai := as.AuthInfoFromTLS(tlsCtx(&x509.Certificate{DNSNames: []string{"app.example.com"}}))
+	require.Equal(t, &AuthInfo{Username: "app.example.com", Revision: as.Revision(), Roles: []string{"role-test"}}, ai)
+
+	ai = as.AuthInfoFromTLS(tlsCtx(&x509.Certificate{Subject: pkix.Name{CommonName: "app"}, DNSNames: []string{"app.example.com"}}))
+	require.Equal(t, &AuthInfo{Username: "app", Revision: as.Revision(), Roles: []string{"role-test"}}, ai)
+
+	ai = as.AuthInfoFromTLS(tlsCtx(&x509.Certificate{Subject: pkix.Name{CommonName: "foo"}}))
+	require.Equal(t, &AuthInfo{Username: "foo", Revision: as.Revision()}, ai)
+
+	svid, err := url.Parse("spiffe://example.org/ns/etcd/sa/client")
+	require.NoError(t, err)
+	ai = as.AuthInfoFromTLS(tlsCtx(&x509.Certificate{URIs: []*url.URL{svid}}))
+	require.Equal(t, &AuthInfo{Username: "spiffe://example.org/ns/etcd/sa/client", Revision: as.Revision()}, ai)
+}
@@ -38,6 +38,25 @@ func (t *tokenJWT) enable()                         {}
 func (t *tokenJWT) disable()                        {}
 func (t *tokenJWT) invalidateUser(string)           {}
 func (t *tokenJWT) genTokenPrefix() (string, error) { return "", nil }
+func (t *tokenJWT) expiry(token string) time.Time   { return jwtExpiry(token) }
+
+// setTTL does nothing, as the TTL of the JWT tokens is set by the ttl option
+// of --auth-token.
+func (t *tokenJWT) setTTL(time.Duration) {}
+
+// jwtExpiry returns the expiration time of a verified JWT token, without
+// verifying it again.
+func jwtExpiry(token string) time.Time {
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, jwt.MapClaims{})
+	if err != nil {
+		return time.Time{}
+	}
+	exp, err := parsed.Claims.GetExpirationTime()
+	if err != nil || exp == nil {
+		return time.Time{}
+	}
+	return exp.Time
+}
 
 func (t *tokenJWT) info(ctx context.Context, token string, rev uint64) (*AuthInfo, bool) {
 	// rev isn't used in JWT, it is only used in simple token
@@ -0,0 +1,174 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// maxLockoutRecords bounds the failure records kept per kind, so that
+	// failures with random user names or from many sources don't exhaust
+	// the memory.
+	maxLockoutRecords = 100000
+
+	DefaultLockoutDuration    = time.Second
+	DefaultLockoutMaxDuration = 15 * time.Minute
+)
+
+// LockoutConfig configures the lockout of the users and the source addresses
+// failing to authenticate repeatedly.
+type LockoutConfig struct {
+	// UserFailures is the number of consecutive failures locking out a
+	// user. 0 disables the lockout of users.
+	UserFailures int
+	// SourceFailures is the number of consecutive failures locking out a
+	// source address. 0 disables the lockout of sources.
+	SourceFailures int
+	// Duration is the duration of the first lockout, doubled on each
+	// further failure up to MaxDuration.
+	Duration time.Duration
+	// MaxDuration caps the duration of a lockout. The failures are also
+	// forgotten after MaxDuration without failure.
+	MaxDuration time.Duration
+}
+
+type failureRecord struct {
+	failures    int
+	last        time.Time
+	lockedUntil time.Time
+}
+
+// lockoutTracker tracks the authentication failures of the member. Its
+// records are local: members count the failures of the requests they serve.
+type lockoutTracker struct {
+	cfg LockoutConfig
+	now func() time.Time
+
+	mu      sync.Mutex
+	users   map[string]*failureRecord
+	sources map[string]*failureRecord
+}
+
+func newLockoutTracker(cfg LockoutConfig) *lockoutTracker {
+	if cfg.Duration <= 0 {
+		cfg.Duration = DefaultLockoutDuration
+	}
+	if cfg.MaxDuration < cfg.Duration {
+		cfg.MaxDuration = max(cfg.Duration, DefaultLockoutMaxDuration)
+	}
+	return &lockoutTracker{
+		cfg:     cfg,
+		now:     time.Now,
+		users:   make(map[string]*failureRecord),
+		sources: make(map[string]*failureRecord),
+	}
+}
+
+func (lt *lockoutTracker) enabled() bool {
+	return lt != nil && (lt.cfg.UserFailures > 0 || lt.cfg.SourceFailures > 0)
+}
+
+// check returns ErrAuthLockedOut if the user or the source is locked out.
+func (lt *lockoutTracker) check(user, source string) error {
+	if !lt.enabled() {
+		return nil
+	}
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	now := lt.now()
+	if r, ok := lt.users[user]; ok && now.Before(r.lockedUntil) {
+		authLockoutRejectedTotal.WithLabelValues("user").Inc()
+		return ErrAuthLockedOut
+	}
+	if r, ok := lt.sources[source]; ok && now.Before(r.lockedUntil) {
+		authLockoutRejectedTotal.WithLabelValues("source").Inc()
+		return ErrAuthLockedOut
+	}
+	return nil
+}
+
+// fail records a failure of the user from the source, and returns true if
+// it locked out either of them.
+func (lt *lockoutTracker) fail(user, source string) bool {
+	if !lt.enabled() {
+		return false
+	}
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	now := lt.now()
+	locked := false
+	if lt.cfg.UserFailures > 0 && lt.record(lt.users, user, lt.cfg.UserFailures, now) {
+		authLockoutsTotal.WithLabelValues("user").Inc()
+		locked = true
+	}
+	if lt.cfg.SourceFailures > 0 && source != "" && lt.record(lt.sources, source, lt.cfg.SourceFailures, now) {
+		authLockoutsTotal.WithLabelValues("source").Inc()
+		locked = true
+	}
+	return locked
+}
+
+// succeed forgets the failures of the user and of the source, so that the
+// typos of the clients sharing a source don't add up to a lockout.
+func (lt *lockoutTracker) succeed(user, source string) {
+	if !lt.enabled() {
+		return
+	}
+	lt.mu.Lock()
+	defer lt.mu.Unlock()
+	delete(lt.users, user)
+	if source != "" {
+		delete(lt.sources, source)
+	}
+}
+
+func (lt *lockoutTracker) record(records map[string]*failureRecord, key string, threshold int, now time.Time) bool {
+	r, ok := records[key]
+	if ok && now.Sub(r.last) > lt.cfg.MaxDuration {
+		r.failures = 0
+	}
+	if !ok {
+		if len(records) >= maxLockoutRecords {
+			lt.prune(records, now)
+			if len(records) >= maxLockoutRecords {
+				return false
+			}
+		}
+		r = &failureRecord{}
+		records[key] = r
+	}
+	r.failures++
+	r.last = now
+	if r.failures < threshold {
+		return false
+	}
+	d := lt.cfg.Duration << min(r.failures-threshold, 30)
+	if d <= 0 || d > lt.cfg.MaxDuration {
+		d = lt.cfg.MaxDuration
+	}
+	r.lockedUntil = now.Add(d)
+	return true
+}
+
+// prune drops the records whose failures are forgotten.
+func (lt *lockoutTracker) prune(records map[string]*failureRecord, now time.Time) {
+	for k, r := range records {
+		if now.Sub(r.last) > lt.cfg.MaxDuration && !now.Before(r.lockedUntil) {
+			delete(records, k)
+		}
+	}
+}
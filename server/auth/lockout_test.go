@@ -0,0 +1,102 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"golang.org/x/crypto/bcrypt"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+func TestLockoutTracker(t *testing.T) {
+	lt := newLockoutTracker(LockoutConfig{UserFailures: 2, SourceFailures: 3, Duration: time.Second, MaxDuration: 4 * time.Second})
+	now := time.Unix(0, 0)
+	lt.now = func() time.Time { return now }
+
+	require.False(t, lt.fail("alice", "10.0.0.1"))
+	require.NoError(t, lt.check("alice", "10.0.0.1"))
+	require.True(t, lt.fail("alice", "10.0.0.1"))
+	require.ErrorIs(t, lt.check("alice", "10.0.0.2"), ErrAuthLockedOut)
+	require.NoError(t, lt.check("bob", "10.0.0.2"))
+
+	now = now.Add(time.Second)
+	require.NoError(t, lt.check("alice", "10.0.0.2"))
+
+	// the source reaches its threshold while the lockout of the user doubles
+	require.True(t, lt.fail("alice", "10.0.0.1"))
+	require.ErrorIs(t, lt.check("bob", "10.0.0.1"), ErrAuthLockedOut)
+	now = now.Add(time.Second)
+	require.ErrorIs(t, lt.check("alice", "10.0.0.2"), ErrAuthLockedOut)
+	now = now.Add(time.Second)
+	require.NoError(t, lt.check("alice", "10.0.0.2"))
+
+	// lockouts are capped by the maximum duration
+	for range 5 {
+		lt.fail("alice", "")
+	}
+	now = now.Add(4 * time.Second)
+	require.NoError(t, lt.check("alice", ""))
+
+	// a success forgets the failures of the user and of the source
+	lt.succeed("alice", "")
+	require.False(t, lt.fail("alice", ""))
+	lt.fail("carol", "10.0.0.3")
+	lt.fail("dave", "10.0.0.3")
+	lt.succeed("dave", "10.0.0.3")
+	require.False(t, lt.fail("erin", "10.0.0.3"))
+	require.NoError(t, lt.check("dave", "10.0.0.3"))
+	lt.fail("carol", "10.0.0.3")
+	require.True(t, lt.fail("erin", "10.0.0.3"))
+	require.ErrorIs(t, lt.check("dave", "10.0.0.3"), ErrAuthLockedOut)
+
+	// failures are forgotten after the maximum duration without failure
+	now = now.Add(5 * time.Second)
+	require.False(t, lt.fail("alice", ""))
+}
+
+func TestCheckPasswordFromLockout(t *testing.T) {
+	tp, err := NewTokenProvider(zaptest.NewLogger(t), tokenTypeSimple, dummyIndexWaiter, simpleTokenTTLDefault)
+	require.NoError(t, err)
+	as := NewAuthStore(zaptest.NewLogger(t), newBackendMock(), tp, bcrypt.MinCost,
+		WithLockout(LockoutConfig{UserFailures: 2, Duration: time.Hour, MaxDuration: time.Hour}))
+	defer as.Close()
+
+	_, err = as.UserAdd(&pb.AuthUserAddRequest{Name: "root", HashedPassword: encodePassword("root"), Options: &authpb.UserAddOptions{NoPassword: false}})
+	require.NoError(t, err)
+	_, err = as.UserGrantRole(&pb.AuthUserGrantRoleRequest{User: "root", Role: "root"})
+	require.NoError(t, err)
+	require.NoError(t, as.AuthEnable())
+
+	_, err = as.CheckPasswordFrom("root", "wrong", "10.0.0.1")
+	require.ErrorIs(t, err, ErrAuthFailed)
+	_, err = as.CheckPasswordFrom("root", "root", "10.0.0.1")
+	require.NoError(t, err)
+
+	for range 2 {
+		_, err = as.CheckPasswordFrom("root", "wrong", "10.0.0.1")
+		require.ErrorIs(t, err, ErrAuthFailed)
+	}
+	// the user is locked out even with the right password
+	_, err = as.CheckPasswordFrom("root", "root", "10.0.0.2")
+	require.ErrorIs(t, err, ErrAuthLockedOut)
+	_, err = as.CheckPassword("root", "root")
+	require.NoError(t, err)
+}
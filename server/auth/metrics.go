@@ -34,6 +34,25 @@ var (
 			return reportCurrentAuthRev()
 		},
 	)
+	authFailuresTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "etcd_debugging",
+		Subsystem: "auth",
+		Name:      "authenticate_failures_total",
+		Help:      "The total number of failed authentications due to invalid credentials.",
+	})
+	authLockoutsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "etcd_debugging",
+		Subsystem: "auth",
+		Name:      "lockouts_total",
+		Help:      "The total number of lockouts after repeated authentication failures, by user or source.",
+	}, []string{"kind"})
+	authLockoutRejectedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "etcd_debugging",
+		Subsystem: "auth",
+		Name:      "lockout_rejected_total",
+		Help:      "The total number of authentications rejected while the user or source was locked out.",
+	}, []string{"kind"})
+
 	// overridden by auth store initialization
 	reportCurrentAuthRevMu sync.RWMutex
 	reportCurrentAuthRev   = func() float64 { return 0 }
@@ -41,4 +60,7 @@ var (
 
 func init() {
 	prometheus.MustRegister(currentAuthRevision)
+	prometheus.MustRegister(authFailuresTotal)
+	prometheus.MustRegister(authLockoutsTotal)
+	prometheus.MustRegister(authLockoutRejectedTotal)
 }
@@ -16,6 +16,7 @@ package auth
 
 import (
 	"context"
+	"time"
 )
 
 type tokenNop struct{}
@@ -24,6 +25,8 @@ func (t *tokenNop) enable()                         {}
 func (t *tokenNop) disable()                        {}
 func (t *tokenNop) invalidateUser(string)           {}
 func (t *tokenNop) genTokenPrefix() (string, error) { return "", nil }
+func (t *tokenNop) expiry(string) time.Time         { return time.Time{} }
+func (t *tokenNop) setTTL(time.Duration)            {}
 func (t *tokenNop) info(ctx context.Context, token string, rev uint64) (*AuthInfo, bool) {
 	return nil, false
 }
@@ -0,0 +1,530 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	optOIDCIssuer         = "issuer"
+	optOIDCAudience       = "audience"
+	optOIDCCAFile         = "ca-file"
+	optOIDCUsernameClaim  = "username-claim"
+	optOIDCUsernamePrefix = "username-prefix"
+	optOIDCRolesClaim     = "roles-claim"
+	optOIDCRolePrefix     = "role-prefix"
+	optOIDCRoleMap        = "role-map"
+	optOIDCRefresh        = "jwks-refresh-interval"
+
+	defaultOIDCUsernameClaim = "sub"
+	defaultOIDCRolesClaim    = "groups"
+
+	// DefaultJWKSRefreshInterval is the interval of the JWKS refreshes when
+	// 'jwks-refresh-interval' is not specified.
+	DefaultJWKSRefreshInterval = time.Hour
+
+	// minJWKSRefreshInterval rate limits the JWKS refreshes triggered by
+	// tokens signed with unknown keys.
+	minJWKSRefreshInterval = 10 * time.Second
+
+	oidcRequestTimeout  = 10 * time.Second
+	maxOIDCResponseSize = 1 << 20
+)
+
+var knownOIDCOptions = map[string]bool{
+	optOIDCIssuer:         true,
+	optOIDCAudience:       true,
+	optOIDCCAFile:         true,
+	optOIDCUsernameClaim:  true,
+	optOIDCUsernamePrefix: true,
+	optOIDCRolesClaim:     true,
+	optOIDCRolePrefix:     true,
+	optOIDCRoleMap:        true,
+	optOIDCRefresh:        true,
+}
+
+var oidcSigningMethods = []string{
+	"RS256", "RS384", "RS512",
+	"PS256", "PS384", "PS512",
+	"ES256", "ES384", "ES512",
+	"EdDSA",
+}
+
+type oidcOptions struct {
+	Issuer         string
+	Audience       string
+	CAFile         string
+	UsernameClaim  string
+	UsernamePrefix string
+	RolesClaim     string
+	RolePrefix     string
+	// RoleMap maps the values of the roles claim to etcd roles. When set,
+	// the values missing from the map grant no role.
+	RoleMap         map[string]string
+	RefreshInterval time.Duration
+}
+
+// Parse will load options from the specified map or set defaults where appropriate.
+// The role map is given as 'claim-value:role' pairs separated by ';'.
+func (opts *oidcOptions) Parse(optMap map[string]string) error {
+	opts.Issuer = strings.TrimSuffix(optMap[optOIDCIssuer], "/")
+	if opts.Issuer == "" {
+		return errors.New("missing OIDC issuer")
+	}
+	opts.Audience = optMap[optOIDCAudience]
+	if opts.Audience == "" {
+		return errors.New("missing OIDC audience")
+	}
+	opts.CAFile = optMap[optOIDCCAFile]
+
+	opts.UsernameClaim = optMap[optOIDCUsernameClaim]
+	if opts.UsernameClaim == "" {
+		opts.UsernameClaim = defaultOIDCUsernameClaim
+	}
+	opts.UsernamePrefix = optMap[optOIDCUsernamePrefix]
+	opts.RolesClaim = optMap[optOIDCRolesClaim]
+	if opts.RolesClaim == "" {
+		opts.RolesClaim = defaultOIDCRolesClaim
+	}
+	opts.RolePrefix = optMap[optOIDCRolePrefix]
+
+	if rm := optMap[optOIDCRoleMap]; rm != "" {
+		opts.RoleMap = make(map[string]string)
+		for _, pair := range strings.Split(rm, ";") {
+			value, role, ok := strings.Cut(pair, ":")
+			if !ok || value == "" || role == "" {
+				return fmt.Errorf("invalid OIDC role mapping %q", pair)
+			}
+			opts.RoleMap[value] = role
+		}
+	}
+
+	opts.RefreshInterval = DefaultJWKSRefreshInterval
+	if ri := optMap[optOIDCRefresh]; ri != "" {
+		var err error
+		opts.RefreshInterval, err = time.ParseDuration(ri)
+		if err != nil {
+			return err
+		}
+		if opts.RefreshInterval < minJWKSRefreshInterval {
+			return fmt.Errorf("OIDC JWKS refresh interval must be at least %v", minJWKSRefreshInterval)
+		}
+	}
+	return nil
+}
+
+// tokenOIDC verifies the ID tokens issued by an OpenID Connect provider.
+// The signing keys are discovered from the provider configuration and
+// refreshed periodically, or when a token is signed by an unknown key, so
+// that key rotations of the provider are followed. It cannot assign tokens:
+// users authenticate against the provider rather than against etcd.
+type tokenOIDC struct {
+	lg     *zap.Logger
+	opts   oidcOptions
+	client *http.Client
+
+	mu          sync.RWMutex
+	keys        map[string]any
+	lastRefresh time.Time
+
+	// refreshMu serializes the refreshes of keys.
+	refreshMu sync.Mutex
+
+	stopMu sync.Mutex
+	stopc  chan struct{}
+	donec  chan struct{}
+}
+
+func (t *tokenOIDC) invalidateUser(string)           {}
+func (t *tokenOIDC) genTokenPrefix() (string, error) { return "", nil }
+func (t *tokenOIDC) expiry(token string) time.Time   { return jwtExpiry(token) }
+func (t *tokenOIDC) setTTL(time.Duration)            {}
+
+func (t *tokenOIDC) assign(ctx context.Context, username string, revision uint64) (string, error) {
+	return "", ErrVerifyOnly
+}
+
+// enable starts refreshing the signing keys of the provider.
+func (t *tokenOIDC) enable() {
+	t.stopMu.Lock()
+	defer t.stopMu.Unlock()
+	if t.stopc != nil { // already enabled
+		return
+	}
+	t.stopc, t.donec = make(chan struct{}), make(chan struct{})
+	go t.run(t.stopc, t.donec)
+}
+
+func (t *tokenOIDC) disable() {
+	t.stopMu.Lock()
+	stopc, donec := t.stopc, t.donec
+	t.stopc, t.donec = nil, nil
+	t.stopMu.Unlock()
+	if stopc != nil {
+		close(stopc)
+		<-donec
+	}
+}
+
+func (t *tokenOIDC) run(stopc <-chan struct{}, donec chan<- struct{}) {
+	defer close(donec)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-stopc
+		cancel()
+	}()
+
+	ticker := time.NewTicker(t.opts.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		if err := t.refreshKeys(ctx); err != nil && ctx.Err() == nil {
+			t.lg.Warn("failed to refresh OIDC signing keys", zap.String("issuer", t.opts.Issuer), zap.Error(err))
+		}
+		select {
+		case <-ticker.C:
+		case <-stopc:
+			return
+		}
+	}
+}
+
+func (t *tokenOIDC) info(ctx context.Context, token string, rev uint64) (*AuthInfo, bool) {
+	parsed, err := jwt.Parse(token, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return t.key(ctx, kid)
+	},
+		jwt.WithValidMethods(oidcSigningMethods),
+		jwt.WithIssuer(t.opts.Issuer),
+		jwt.WithAudience(t.opts.Audience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		t.lg.Warn("failed to verify an OIDC token", zap.Error(err))
+		return nil, false
+	}
+
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !parsed.Valid || !ok {
+		t.lg.Warn("failed to obtain claims from an OIDC token")
+		return nil, false
+	}
+
+	username, ok := claims[t.opts.UsernameClaim].(string)
+	if !ok || username == "" {
+		t.lg.Warn("failed to obtain user claims from an OIDC token", zap.String("claim", t.opts.UsernameClaim))
+		return nil, false
+	}
+	username = t.opts.UsernamePrefix + username
+
+	// a federated user without role would be checked against the user of the
+	// same name in the store, so it is rejected instead.
+	roles := t.mapRoles(claims[t.opts.RolesClaim])
+	if len(roles) == 0 {
+		t.lg.Warn("OIDC token grants no role", zap.String("user-name", username))
+		return nil, false
+	}
+
+	return &AuthInfo{Username: username, Revision: rev, Roles: roles}, true
+}
+
+// mapRoles returns the roles granted by the value of the roles claim, which
+// is either a string or a list of strings. The root role is only granted by
+// an explicit mapping of the role map, never by a claim value of its name.
+func (t *tokenOIDC) mapRoles(claim any) []string {
+	var values []string
+	switch v := claim.(type) {
+	case string:
+		values = []string{v}
+	case []any:
+		for _, e := range v {
+			if s, ok := e.(string); ok {
+				values = append(values, s)
+			}
+		}
+	}
+
+	var roles []string
+	for _, v := range values {
+		if t.opts.RoleMap != nil {
+			if role, ok := t.opts.RoleMap[v]; ok {
+				roles = append(roles, role)
+			}
+			continue
+		}
+		if role := t.opts.RolePrefix + v; role != rootRole {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
+// key returns the signing key of the given id. An unknown key triggers a
+// refresh of the keys, unless they were refreshed recently.
+func (t *tokenOIDC) key(ctx context.Context, kid string) (any, error) {
+	if k, ok := t.lookupKey(kid); ok {
+		return k, nil
+	}
+
+	if err := t.refreshStaleKeys(ctx); err != nil {
+		return nil, err
+	}
+	if k, ok := t.lookupKey(kid); ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown OIDC signing key %q", kid)
+}
+
+func (t *tokenOIDC) lookupKey(kid string) (any, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if kid == "" && len(t.keys) == 1 {
+		for _, k := range t.keys {
+			return k, true
+		}
+	}
+	k, ok := t.keys[kid]
+	return k, ok
+}
+
+func (t *tokenOIDC) refreshKeys(ctx context.Context) error {
+	t.refreshMu.Lock()
+	defer t.refreshMu.Unlock()
+	return t.fetchKeys(ctx)
+}
+
+// refreshStaleKeys refreshes the keys unless they were refreshed within
+// minJWKSRefreshInterval.
+func (t *tokenOIDC) refreshStaleKeys(ctx context.Context) error {
+	t.refreshMu.Lock()
+	defer t.refreshMu.Unlock()
+	t.mu.RLock()
+	stale := time.Since(t.lastRefresh) >= minJWKSRefreshInterval
+	t.mu.RUnlock()
+	if !stale {
+		return nil
+	}
+	return t.fetchKeys(ctx)
+}
+
+// fetchKeys fetches the signing keys from the JWKS endpoint found in the
+// provider configuration. refreshMu must be held.
+func (t *tokenOIDC) fetchKeys(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, oidcRequestTimeout)
+	defer cancel()
+
+	var discovery struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+	if err := t.get(ctx, t.opts.Issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return err
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != t.opts.Issuer {
+		return fmt.Errorf("OIDC discovery returned issuer %q, expected %q", discovery.Issuer, t.opts.Issuer)
+	}
+	if discovery.JWKSURI == "" {
+		return errors.New("OIDC discovery returned no jwks_uri")
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := t.get(ctx, discovery.JWKSURI, &jwks); err != nil {
+		return err
+	}
+	keys := make(map[string]any, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		k, err := jwk.publicKey()
+		if err != nil {
+			t.lg.Warn("ignored an invalid OIDC signing key", zap.String("kid", jwk.Kid), zap.Error(err))
+			continue
+		}
+		keys[jwk.Kid] = k
+	}
+
+	t.mu.Lock()
+	t.keys = keys
+	t.lastRefresh = time.Now()
+	t.mu.Unlock()
+	t.lg.Debug("refreshed OIDC signing keys", zap.String("issuer", t.opts.Issuer), zap.Int("keys", len(keys)))
+	return nil
+}
+
+func (t *tokenOIDC) get(ctx context.Context, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %s", url, resp.Status)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, maxOIDCResponseSize)).Decode(v)
+}
+
+// jsonWebKey is a public key of a JSON Web Key Set (RFC 7517).
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k *jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeJWKInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeJWKInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeJWKInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeJWKInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+		// ECDH validates that the point is on the curve
+		if _, err = pub.ECDH(); err != nil {
+			return nil, err
+		}
+		return pub, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeJWKInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty key parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func newTokenProviderOIDC(lg *zap.Logger, optMap map[string]string) (*tokenOIDC, error) {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	var opts oidcOptions
+	if err := opts.Parse(optMap); err != nil {
+		lg.Error("problem loading OIDC options", zap.Error(err))
+		return nil, ErrInvalidAuthOpts
+	}
+
+	keys := make([]string, 0, len(optMap))
+	for k := range optMap {
+		if !knownOIDCOptions[k] {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) > 0 {
+		lg.Warn("unknown OIDC options", zap.Strings("keys", keys))
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			lg.Error("problem loading OIDC CA file", zap.Error(err))
+			return nil, ErrInvalidAuthOpts
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			lg.Error("no certificate found in OIDC CA file", zap.String("ca-file", opts.CAFile))
+			return nil, ErrInvalidAuthOpts
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return &tokenOIDC{
+		lg:     lg,
+		opts:   opts,
+		client: &http.Client{Transport: transport, Timeout: oidcRequestTimeout},
+	}, nil
+}
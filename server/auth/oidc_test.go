@@ -0,0 +1,237 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+// fakeOIDCProvider serves the discovery document and the signing keys of an
+// OpenID Connect provider.
+type fakeOIDCProvider struct {
+	*httptest.Server
+
+	mu   sync.Mutex
+	keys map[string]*ecdsa.PrivateKey
+}
+
+func newFakeOIDCProvider(t *testing.T) *fakeOIDCProvider {
+	p := &fakeOIDCProvider{keys: make(map[string]*ecdsa.PrivateKey)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   p.URL,
+			"jwks_uri": p.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		var keys []map[string]string
+		for kid, k := range p.keys {
+			keys = append(keys, map[string]string{
+				"kty": "EC",
+				"kid": kid,
+				"use": "sig",
+				"crv": "P-256",
+				"x":   base64.RawURLEncoding.EncodeToString(k.X.FillBytes(make([]byte, 32))),
+				"y":   base64.RawURLEncoding.EncodeToString(k.Y.FillBytes(make([]byte, 32))),
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+	})
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+	return p
+}
+
+// rotate replaces the signing keys with a new key of the given id.
+func (p *fakeOIDCProvider) rotate(t *testing.T, kid string) {
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.keys = map[string]*ecdsa.PrivateKey{kid: k}
+}
+
+func (p *fakeOIDCProvider) sign(t *testing.T, kid string, claims jwt.MapClaims) string {
+	p.mu.Lock()
+	k := p.keys[kid]
+	p.mu.Unlock()
+	tk := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	tk.Header["kid"] = kid
+	token, err := tk.SignedString(k)
+	require.NoError(t, err)
+	return token
+}
+
+func TestOIDCInfo(t *testing.T) {
+	p := newFakeOIDCProvider(t)
+	p.rotate(t, "key-1")
+
+	tp, err := NewTokenProvider(zaptest.NewLogger(t),
+		"oidc,issuer="+p.URL+",audience=etcd,username-prefix=oidc:,role-map=admins:root;devs:dev",
+		dummyIndexWaiter, time.Minute)
+	require.NoError(t, err)
+	tp.enable()
+	defer tp.disable()
+
+	claims := func(mod func(jwt.MapClaims)) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"iss":    p.URL,
+			"aud":    "etcd",
+			"sub":    "alice",
+			"exp":    time.Now().Add(time.Hour).Unix(),
+			"groups": []string{"devs", "others"},
+		}
+		if mod != nil {
+			mod(c)
+		}
+		return c
+	}
+
+	ctx := context.Background()
+	ai, ok := tp.info(ctx, p.sign(t, "key-1", claims(nil)), 5)
+	require.True(t, ok)
+	require.Equal(t, &AuthInfo{Username: "oidc:alice", Revision: 5, Roles: []string{"dev"}}, ai)
+
+	ai, ok = tp.info(ctx, p.sign(t, "key-1", claims(func(c jwt.MapClaims) { c["groups"] = "admins" })), 5)
+	require.True(t, ok)
+	require.Equal(t, []string{"root"}, ai.Roles)
+
+	for name, mod := range map[string]func(jwt.MapClaims){
+		"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://example.com" },
+		"wrong audience": func(c jwt.MapClaims) { c["aud"] = "other" },
+		"expired":        func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() },
+		"no expiry":      func(c jwt.MapClaims) { delete(c, "exp") },
+		"no username":    func(c jwt.MapClaims) { delete(c, "sub") },
+		"no role":        func(c jwt.MapClaims) { c["groups"] = []string{"others"} },
+	} {
+		_, ok = tp.info(ctx, p.sign(t, "key-1", claims(mod)), 5)
+		require.Falsef(t, ok, "expected %s token to be rejected", name)
+	}
+
+	_, err = tp.assign(ctx, "alice", 5)
+	require.ErrorIs(t, err, ErrVerifyOnly)
+}
+
+func TestOIDCInfoNoImplicitRoot(t *testing.T) {
+	p := newFakeOIDCProvider(t)
+	p.rotate(t, "key-1")
+
+	tp, err := NewTokenProvider(zaptest.NewLogger(t), "oidc,issuer="+p.URL+",audience=etcd", dummyIndexWaiter, time.Minute)
+	require.NoError(t, err)
+	tp.enable()
+	defer tp.disable()
+
+	claims := jwt.MapClaims{
+		"iss":    p.URL,
+		"aud":    "etcd",
+		"sub":    "alice",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"groups": []string{"root", "dev"},
+	}
+	// without a role map, a claim value naming the root role grants nothing.
+	ai, ok := tp.info(context.Background(), p.sign(t, "key-1", claims), 5)
+	require.True(t, ok)
+	require.Equal(t, []string{"dev"}, ai.Roles)
+}
+
+// TestOIDCKeyRotation ensures that a token signed by a key unknown to the
+// provider triggers a refresh of the signing keys.
+func TestOIDCKeyRotation(t *testing.T) {
+	p := newFakeOIDCProvider(t)
+	p.rotate(t, "key-1")
+
+	tp, err := newTokenProviderOIDC(zaptest.NewLogger(t), map[string]string{"issuer": p.URL, "audience": "etcd"})
+	require.NoError(t, err)
+	tp.enable()
+	defer tp.disable()
+
+	claims := jwt.MapClaims{
+		"iss":    p.URL,
+		"aud":    "etcd",
+		"sub":    "alice",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"groups": []string{"dev"},
+	}
+	ctx := context.Background()
+	_, ok := tp.info(ctx, p.sign(t, "key-1", claims), 1)
+	require.True(t, ok)
+
+	p.rotate(t, "key-2")
+	token := p.sign(t, "key-2", claims)
+	// the keys were refreshed too recently for an unknown key to refresh them
+	_, ok = tp.info(ctx, token, 1)
+	require.False(t, ok)
+
+	tp.mu.Lock()
+	tp.lastRefresh = time.Now().Add(-minJWKSRefreshInterval)
+	tp.mu.Unlock()
+	ai, ok := tp.info(ctx, token, 1)
+	require.True(t, ok)
+	require.Equal(t, &AuthInfo{Username: "alice", Revision: 1, Roles: []string{"dev"}}, ai)
+}
+
+func TestOIDCOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    map[string]string
+		wantErr bool
+	}{
+		{name: "minimal", opts: map[string]string{"issuer": "https://example.com", "audience": "etcd"}},
+		{name: "no issuer", opts: map[string]string{"audience": "etcd"}, wantErr: true},
+		{name: "no audience", opts: map[string]string{"issuer": "https://example.com"}, wantErr: true},
+		{
+			name:    "invalid role map",
+			opts:    map[string]string{"issuer": "https://example.com", "audience": "etcd", "role-map": "admins"},
+			wantErr: true,
+		},
+		{
+			name:    "too short refresh interval",
+			opts:    map[string]string{"issuer": "https://example.com", "audience": "etcd", "jwks-refresh-interval": "1s"},
+			wantErr: true,
+		},
+		{
+			name:    "missing CA file",
+			opts:    map[string]string{"issuer": "https://example.com", "audience": "etcd", "ca-file": "/nonexistent"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTokenProviderOIDC(zaptest.NewLogger(t), tt.opts)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidAuthOpts)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
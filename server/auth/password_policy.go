@@ -0,0 +1,57 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"time"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+)
+
+// PasswordPolicy configures the ageing of the passwords of the users.
+type PasswordPolicy struct {
+	// MaxAge is the duration a password is valid for after it is set. 0
+	// disables the expiry of passwords.
+	MaxAge time.Duration
+	// GracePeriod is the duration an expired password still authenticates
+	// its user for, so that the user can change it.
+	GracePeriod time.Duration
+}
+
+// expireTime returns the unix time the password of the user expires at, 0 if
+// it never expires. The passwords set before their change times were
+// recorded never expire.
+func (p PasswordPolicy) expireTime(user *authpb.User) int64 {
+	if p.MaxAge <= 0 || user.PasswordChangedTime == 0 {
+		return 0
+	}
+	if user.Options != nil && user.Options.NoPassword {
+		return 0
+	}
+	return time.Unix(user.PasswordChangedTime, 0).Add(p.MaxAge).Unix()
+}
+
+// check returns ErrAuthPasswordExpired if the password of the user expired
+// and its grace period ended, and whether the password is in its grace period.
+func (p PasswordPolicy) check(user *authpb.User, now time.Time) (inGrace bool, err error) {
+	exp := p.expireTime(user)
+	if exp == 0 || now.Unix() < exp {
+		return false, nil
+	}
+	if now.Before(time.Unix(exp, 0).Add(p.GracePeriod)) {
+		return true, nil
+	}
+	return false, ErrAuthPasswordExpired
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"golang.org/x/crypto/bcrypt"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+func TestPasswordPolicy(t *testing.T) {
+	p := PasswordPolicy{MaxAge: time.Hour, GracePeriod: time.Minute}
+	now := time.Unix(100000, 0)
+	tests := []struct {
+		name        string
+		user        *authpb.User
+		wantExpire  int64
+		wantInGrace bool
+		wantErr     error
+	}{
+		{
+			name: "unknown change time",
+			user: &authpb.User{},
+		},
+		{
+			name: "no password",
+			user: &authpb.User{PasswordChangedTime: 1, Options: &authpb.UserAddOptions{NoPassword: true}},
+		},
+		{
+			name:       "valid",
+			user:       &authpb.User{PasswordChangedTime: now.Unix() - 3599},
+			wantExpire: now.Unix() + 1,
+		},
+		{
+			name:        "in grace period",
+			user:        &authpb.User{PasswordChangedTime: now.Unix() - 3600},
+			wantExpire:  now.Unix(),
+			wantInGrace: true,
+		},
+		{
+			name:       "expired",
+			user:       &authpb.User{PasswordChangedTime: now.Unix() - 3660},
+			wantExpire: now.Unix() - 60,
+			wantErr:    ErrAuthPasswordExpired,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.wantExpire, p.expireTime(tt.user))
+			inGrace, err := p.check(tt.user, now)
+			require.Equal(t, tt.wantInGrace, inGrace)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	inGrace, err := PasswordPolicy{}.check(&authpb.User{PasswordChangedTime: 1}, now)
+	require.False(t, inGrace)
+	require.NoError(t, err)
+}
+
+func TestCheckPasswordExpiry(t *testing.T) {
+	tp, err := NewTokenProvider(zaptest.NewLogger(t), tokenTypeSimple, dummyIndexWaiter, simpleTokenTTLDefault)
+	require.NoError(t, err)
+	as := NewAuthStore(zaptest.NewLogger(t), newBackendMock(), tp, bcrypt.MinCost,
+		WithPasswordPolicy(PasswordPolicy{MaxAge: time.Hour, GracePeriod: time.Hour}))
+	defer as.Close()
+	require.NoError(t, enableAuthAndCreateRoot(as))
+
+	now := time.Now().Unix()
+	_, err = as.UserAdd(&pb.AuthUserAddRequest{Name: "foo", HashedPassword: encodePassword("bar"), PasswordChangedTime: now - 3*3600})
+	require.NoError(t, err)
+	_, err = as.CheckPassword("foo", "bar")
+	require.ErrorIs(t, err, ErrAuthPasswordExpired)
+	_, err = as.CheckPassword("foo", "wrong")
+	require.ErrorIs(t, err, ErrAuthFailed)
+
+	// an expired password authenticates during its grace period
+	_, err = as.UserChangePassword(&pb.AuthUserChangePasswordRequest{Name: "foo", HashedPassword: encodePassword("bar"), PasswordChangedTime: now - 3600})
+	require.NoError(t, err)
+	_, err = as.CheckPassword("foo", "bar")
+	require.NoError(t, err)
+	ctx := context.WithValue(context.WithValue(t.Context(), AuthenticateParamIndex{}, uint64(1)), AuthenticateParamSimpleTokenPrefix{}, "dummy")
+	resp, err := as.Authenticate(ctx, "foo", "bar")
+	require.NoError(t, err)
+	require.Equal(t, now, resp.PasswordExpireTime)
+
+	// the change time is kept when the roles of the user change
+	_, err = as.RoleAdd(&pb.AuthRoleAddRequest{Name: "role-test"})
+	require.NoError(t, err)
+	_, err = as.UserGrantRole(&pb.AuthUserGrantRoleRequest{User: "foo", Role: "role-test"})
+	require.NoError(t, err)
+	_, err = as.UserRevokeRole(&pb.AuthUserRevokeRoleRequest{Name: "foo", Role: "role-test"})
+	require.NoError(t, err)
+	ur, err := as.UserGet(&pb.AuthUserGetRequest{Name: "foo"})
+	require.NoError(t, err)
+	require.Equal(t, now, ur.PasswordExpireTime)
+
+	// users without recorded change times keep authenticating
+	_, err = as.CheckPassword("root", "root")
+	require.NoError(t, err)
+}
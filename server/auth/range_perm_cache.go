@@ -26,11 +26,22 @@ func getMergedPerms(tx UnsafeAuthReader, userName string) *unifiedRangePermissio
 	if user == nil {
 		return nil
 	}
+	return mergeRolePerms(tx, user.Roles)
+}
 
-	readPerms := adt.NewIntervalTree()
-	writePerms := adt.NewIntervalTree()
+// mergeRolePerms merges the key permissions of the given roles, ignoring the
+// roles that don't exist.
+func mergeRolePerms(tx UnsafeAuthReader, roles []string) *unifiedRangePermissions {
+	perms := &unifiedRangePermissions{
+		readPerms:        adt.NewIntervalTree(),
+		writePerms:       adt.NewIntervalTree(),
+		watchPerms:       adt.NewIntervalTree(),
+		deletePerms:      adt.NewIntervalTree(),
+		leaseAttachPerms: adt.NewIntervalTree(),
+		comparePerms:     adt.NewIntervalTree(),
+	}
 
-	for _, roleName := range user.Roles {
+	for _, roleName := range roles {
 		role := tx.UnsafeGetRole(roleName)
 		if role == nil {
 			continue
@@ -50,24 +61,31 @@ func getMergedPerms(tx UnsafeAuthReader, userName string) *unifiedRangePermissio
 				ivl = adt.NewBytesAffinePoint(perm.Key)
 			}
 
+			// READ and WRITE imply the finer grained permissions of their kind
+			var trees []adt.IntervalTree
 			switch perm.PermType {
 			case authpb.READWRITE:
-				readPerms.Insert(ivl, struct{}{})
-				writePerms.Insert(ivl, struct{}{})
+				trees = []adt.IntervalTree{perms.readPerms, perms.watchPerms, perms.comparePerms,
+					perms.writePerms, perms.deletePerms, perms.leaseAttachPerms}
 
 			case authpb.READ:
-				readPerms.Insert(ivl, struct{}{})
+				trees = []adt.IntervalTree{perms.readPerms, perms.watchPerms, perms.comparePerms}
 
 			case authpb.WRITE:
-				writePerms.Insert(ivl, struct{}{})
+				trees = []adt.IntervalTree{perms.writePerms, perms.deletePerms, perms.leaseAttachPerms}
+
+			default:
+				if tree := perms.tree(perm.PermType); tree != nil {
+					trees = []adt.IntervalTree{tree}
+				}
+			}
+			for _, tree := range trees {
+				tree.Insert(ivl, struct{}{})
 			}
 		}
 	}
 
-	return &unifiedRangePermissions{
-		readPerms:  readPerms,
-		writePerms: writePerms,
-	}
+	return perms
 }
 
 func checkKeyInterval(
@@ -83,28 +101,20 @@ func checkKeyInterval(
 	}
 
 	ivl := adt.NewBytesAffineInterval(key, rangeEnd)
-	switch permtyp {
-	case authpb.READ:
-		return cachedPerms.readPerms.Contains(ivl)
-	case authpb.WRITE:
-		return cachedPerms.writePerms.Contains(ivl)
-	default:
+	tree := cachedPerms.tree(permtyp)
+	if tree == nil {
 		lg.Panic("unknown auth type", zap.String("auth-type", permtyp.String()))
 	}
-	return false
+	return tree.Contains(ivl)
 }
 
 func checkKeyPoint(lg *zap.Logger, cachedPerms *unifiedRangePermissions, key []byte, permtyp authpb.Permission_Type) bool {
 	pt := adt.NewBytesAffinePoint(key)
-	switch permtyp {
-	case authpb.READ:
-		return cachedPerms.readPerms.Intersects(pt)
-	case authpb.WRITE:
-		return cachedPerms.writePerms.Intersects(pt)
-	default:
+	tree := cachedPerms.tree(permtyp)
+	if tree == nil {
 		lg.Panic("unknown auth type", zap.String("auth-type", permtyp.String()))
 	}
-	return false
+	return tree.Intersects(pt)
 }
 
 func (as *authStore) isRangeOpPermitted(userName string, key, rangeEnd []byte, permtyp authpb.Permission_Type) bool {
@@ -121,11 +131,15 @@ func (as *authStore) isRangeOpPermitted(userName string, key, rangeEnd []byte, p
 		return false
 	}
 
+	return checkRangePerms(as.lg, rangePerm, key, rangeEnd, permtyp)
+}
+
+func checkRangePerms(lg *zap.Logger, perms *unifiedRangePermissions, key, rangeEnd []byte, permtyp authpb.Permission_Type) bool {
 	if len(rangeEnd) == 0 {
-		return checkKeyPoint(as.lg, rangePerm, key, permtyp)
+		return checkKeyPoint(lg, perms, key, permtyp)
 	}
 
-	return checkKeyInterval(as.lg, rangePerm, key, rangeEnd, permtyp)
+	return checkKeyInterval(lg, perms, key, rangeEnd, permtyp)
 }
 
 func (as *authStore) refreshRangePermCache(tx UnsafeAuthReader) {
@@ -155,8 +169,32 @@ func (as *authStore) refreshRangePermCache(tx UnsafeAuthReader) {
 }
 
 type unifiedRangePermissions struct {
-	readPerms  adt.IntervalTree
-	writePerms adt.IntervalTree
+	readPerms        adt.IntervalTree
+	writePerms       adt.IntervalTree
+	watchPerms       adt.IntervalTree
+	deletePerms      adt.IntervalTree
+	leaseAttachPerms adt.IntervalTree
+	comparePerms     adt.IntervalTree
+}
+
+// tree returns the ranges on which the operations of permtyp are permitted,
+// or nil for READWRITE and unknown types.
+func (perms *unifiedRangePermissions) tree(permtyp authpb.Permission_Type) adt.IntervalTree {
+	switch permtyp {
+	case authpb.READ:
+		return perms.readPerms
+	case authpb.WRITE:
+		return perms.writePerms
+	case authpb.WATCH:
+		return perms.watchPerms
+	case authpb.DELETE:
+		return perms.deletePerms
+	case authpb.LEASE_ATTACH:
+		return perms.leaseAttachPerms
+	case authpb.TXN_COMPARE:
+		return perms.comparePerms
+	}
+	return nil
 }
 
 // Constraints related to key range
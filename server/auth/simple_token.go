@@ -107,6 +107,28 @@ type tokenSimple struct {
 	simpleTokenTTL    time.Duration
 }
 
+// expiry returns the time the token expires at if it isn't used anymore, as
+// simple tokens expire after the TTL since their last use.
+func (t *tokenSimple) expiry(string) time.Time {
+	t.simpleTokensMu.Lock()
+	defer t.simpleTokensMu.Unlock()
+	return time.Now().Add(t.simpleTokenTTL)
+}
+
+// setTTL changes the TTL of the tokens, which applies to the existing tokens
+// from their next use.
+func (t *tokenSimple) setTTL(ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = simpleTokenTTLDefault
+	}
+	t.simpleTokensMu.Lock()
+	defer t.simpleTokensMu.Unlock()
+	t.simpleTokenTTL = ttl
+	if t.simpleTokenKeeper != nil {
+		t.simpleTokenKeeper.simpleTokenTTL = ttl
+	}
+}
+
 func (t *tokenSimple) genTokenPrefix() (string, error) {
 	ret := make([]byte, defaultSimpleTokenLength)
 
@@ -17,8 +17,10 @@ package auth
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
 	"errors"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -34,6 +36,7 @@ import (
 	"go.etcd.io/etcd/api/v3/authpb"
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/client/pkg/v3/transport"
 )
 
 var _ AuthStore = (*authStore)(nil)
@@ -64,6 +67,8 @@ var (
 	ErrMissingKey           = errors.New("auth: missing key data")
 	ErrKeyMismatch          = errors.New("auth: public and private keys don't match")
 	ErrVerifyOnly           = errors.New("auth: token signing attempted with verify-only key")
+	ErrAuthLockedOut        = errors.New("auth: authentication locked out after too many failures, try again later")
+	ErrAuthPasswordExpired  = errors.New("auth: password has expired")
 )
 
 const (
@@ -72,11 +77,16 @@ const (
 
 	tokenTypeSimple = "simple"
 	tokenTypeJWT    = "jwt"
+	tokenTypeOIDC   = "oidc"
 )
 
 type AuthInfo struct {
 	Username string
 	Revision uint64
+	// Roles are the roles of a user authenticated by an external identity
+	// provider. When set, Username does not refer to a user of the store and
+	// permissions are checked against the roles instead.
+	Roles []string
 }
 
 // AuthenticateParamIndex is used for a key of context in the parameters of Authenticate()
@@ -99,6 +109,13 @@ type AuthStore interface {
 	// Authenticate does authentication based on given user name and password
 	Authenticate(ctx context.Context, username, password string) (*pb.AuthenticateResponse, error)
 
+	// PrepareTokenRevoke validates a token to revoke, and returns the request
+	// revoking it to apply through raft
+	PrepareTokenRevoke(ctx context.Context, token string) (*pb.InternalAuthTokenRevokeRequest, error)
+
+	// TokenRevoke revokes a token until it expires
+	TokenRevoke(r *pb.InternalAuthTokenRevokeRequest) (*pb.AuthTokenRevokeResponse, error)
+
 	// Recover recovers the state of auth store from the given backend
 	Recover(be AuthBackend)
 
@@ -150,6 +167,15 @@ type AuthStore interface {
 	// IsDeleteRangePermitted checks delete-range permission of the user
 	IsDeleteRangePermitted(authInfo *AuthInfo, key, rangeEnd []byte) error
 
+	// IsWatchPermitted checks watch permission of the user
+	IsWatchPermitted(authInfo *AuthInfo, key, rangeEnd []byte) error
+
+	// IsLeaseAttachPermitted checks lease attach permission of the user
+	IsLeaseAttachPermitted(authInfo *AuthInfo, key []byte) error
+
+	// IsTxnComparePermitted checks txn compare permission of the user
+	IsTxnComparePermitted(authInfo *AuthInfo, key, rangeEnd []byte) error
+
 	// IsAdminPermitted checks admin permission of the user
 	IsAdminPermitted(authInfo *AuthInfo) error
 
@@ -163,6 +189,11 @@ type AuthStore interface {
 	// CheckPassword checks a given pair of username and password is correct
 	CheckPassword(username, password string) (uint64, error)
 
+	// CheckPasswordFrom checks the password like CheckPassword for a request
+	// from the given source address, failing with ErrAuthLockedOut while the
+	// user or the source is locked out after repeated failures
+	CheckPasswordFrom(username, password, source string) (uint64, error)
+
 	// Close does cleanup of AuthStore
 	Close() error
 
@@ -180,6 +211,12 @@ type AuthStore interface {
 
 	// BcryptCost gets strength of hashing bcrypted auth password
 	BcryptCost() int
+
+	// PasswordExpiryEnabled returns true if the passwords of the users expire.
+	PasswordExpiryEnabled() bool
+
+	// SetTokenTTL changes the TTL of the simple tokens.
+	SetTokenTTL(ttl time.Duration)
 }
 
 type TokenProvider interface {
@@ -190,6 +227,11 @@ type TokenProvider interface {
 
 	invalidateUser(string)
 	genTokenPrefix() (string, error)
+	// expiry returns the time a valid token expires at, or the zero time if
+	// it doesn't expire.
+	expiry(token string) time.Time
+	// setTTL changes the TTL of the tokens, if the provider supports it.
+	setTTL(ttl time.Duration)
 }
 
 type AuthBackend interface {
@@ -217,6 +259,7 @@ type UnsafeAuthReader interface {
 	UnsafeGetRole(string) *authpb.Role
 	UnsafeGetAllUsers() []*authpb.User
 	UnsafeGetAllRoles() []*authpb.Role
+	UnsafeGetAllRevokedTokens() map[string]int64
 }
 
 type AuthBatchTx interface {
@@ -237,6 +280,8 @@ type UnsafeAuthWriter interface {
 	UnsafeDeleteUser(string)
 	UnsafePutRole(*authpb.Role)
 	UnsafeDeleteRole(string)
+	UnsafePutRevokedToken(hash []byte, expireTime int64)
+	UnsafeDeleteRevokedToken(hash []byte)
 }
 
 type authStore struct {
@@ -259,6 +304,42 @@ type authStore struct {
 
 	tokenProvider TokenProvider
 	bcryptCost    int // the algorithm cost / strength for hashing auth passwords
+
+	// certRoleRules map the attributes of TLS client certificates to roles
+	certRoleRules []CertRoleRule
+
+	// lockout tracks the authentication failures of this member
+	lockout *lockoutTracker
+
+	// passwordPolicy configures the expiry of the passwords
+	passwordPolicy PasswordPolicy
+
+	// revokedTokens maps the digests of the revoked tokens to the unix time
+	// they expire at, 0 for tokens that don't expire
+	revokedTokens   map[string]int64
+	revokedTokensMu sync.RWMutex
+}
+
+// StoreOption configures the AuthStore created by NewAuthStore.
+type StoreOption func(*authStore)
+
+// WithCertRoleRules grants roles to the clients authenticated by TLS
+// certificates matching the rules, so that they don't need a user of their
+// common name.
+func WithCertRoleRules(rules []CertRoleRule) StoreOption {
+	return func(as *authStore) { as.certRoleRules = rules }
+}
+
+// WithLockout locks out the users and the source addresses failing to
+// authenticate repeatedly.
+func WithLockout(cfg LockoutConfig) StoreOption {
+	return func(as *authStore) { as.lockout = newLockoutTracker(cfg) }
+}
+
+// WithPasswordPolicy expires the passwords of the users after the maximum age
+// of the policy.
+func WithPasswordPolicy(p PasswordPolicy) StoreOption {
+	return func(as *authStore) { as.passwordPolicy = p }
 }
 
 func (as *authStore) AuthEnable() error {
@@ -354,7 +435,7 @@ func (as *authStore) Authenticate(ctx context.Context, username, password string
 		zap.String("user-name", username),
 		zap.String("token", token),
 	)
-	return &pb.AuthenticateResponse{Token: token}, nil
+	return &pb.AuthenticateResponse{Token: token, PasswordExpireTime: as.passwordPolicy.expireTime(user)}, nil
 }
 
 func (as *authStore) CheckPassword(username, password string) (uint64, error) {
@@ -389,9 +470,44 @@ func (as *authStore) CheckPassword(username, password string) (uint64, error) {
 		as.lg.Info("invalid password", zap.String("user-name", username))
 		return 0, ErrAuthFailed
 	}
+
+	inGrace, err := as.passwordPolicy.check(user, time.Now())
+	if err != nil {
+		as.lg.Warn("rejected authentication with expired password", zap.String("user-name", username))
+		return 0, err
+	}
+	if inGrace {
+		as.lg.Warn("authenticated with expired password in grace period", zap.String("user-name", username))
+	}
 	return revision, nil
 }
 
+func (as *authStore) CheckPasswordFrom(username, password, source string) (uint64, error) {
+	if err := as.lockout.check(username, source); err != nil {
+		as.lg.Warn(
+			"rejected authentication of locked out user or source",
+			zap.String("user-name", username),
+			zap.String("source", source),
+		)
+		return 0, err
+	}
+	revision, err := as.CheckPassword(username, password)
+	switch {
+	case err == nil:
+		as.lockout.succeed(username, source)
+	case errors.Is(err, ErrAuthFailed), errors.Is(err, ErrNoPasswordUser):
+		authFailuresTotal.Inc()
+		if as.lockout.fail(username, source) {
+			as.lg.Warn(
+				"locked out authentication after repeated failures",
+				zap.String("user-name", username),
+				zap.String("source", source),
+			)
+		}
+	}
+	return revision, err
+}
+
 func (as *authStore) Recover(be AuthBackend) {
 	as.be = be
 	tx := be.ReadTx()
@@ -400,9 +516,14 @@ func (as *authStore) Recover(be AuthBackend) {
 	enabled := tx.UnsafeReadAuthEnabled()
 	as.setRevision(tx.UnsafeReadAuthRevision())
 	as.refreshRangePermCache(tx)
+	revokedTokens := tx.UnsafeGetAllRevokedTokens()
 
 	tx.RUnlock()
 
+	as.revokedTokensMu.Lock()
+	as.revokedTokens = revokedTokens
+	as.revokedTokensMu.Unlock()
+
 	as.enabledMu.Lock()
 	as.enabled = enabled
 	if enabled {
@@ -455,6 +576,9 @@ func (as *authStore) UserAdd(r *pb.AuthUserAddRequest) (*pb.AuthUserAddResponse,
 		Password: password,
 		Options:  options,
 	}
+	if password != nil {
+		newUser.PasswordChangedTime = r.PasswordChangedTime
+	}
 	tx.UnsafePutUser(newUser)
 
 	as.commitRevision(tx)
@@ -520,6 +644,9 @@ func (as *authStore) UserChangePassword(r *pb.AuthUserChangePasswordRequest) (*p
 		Password: password,
 		Options:  user.Options,
 	}
+	if password != nil {
+		updatedUser.PasswordChangedTime = r.PasswordChangedTime
+	}
 	tx.UnsafePutUser(updatedUser)
 
 	as.commitRevision(tx)
@@ -589,6 +716,7 @@ func (as *authStore) UserGet(r *pb.AuthUserGetRequest) (*pb.AuthUserGetResponse,
 
 	var resp pb.AuthUserGetResponse
 	resp.Roles = append(resp.Roles, user.Roles...)
+	resp.PasswordExpireTime = as.passwordPolicy.expireTime(user)
 	return &resp, nil
 }
 
@@ -622,9 +750,10 @@ func (as *authStore) UserRevokeRole(r *pb.AuthUserRevokeRoleRequest) (*pb.AuthUs
 	}
 
 	updatedUser := &authpb.User{
-		Name:     user.Name,
-		Password: user.Password,
-		Options:  user.Options,
+		Name:                user.Name,
+		Password:            user.Password,
+		Options:             user.Options,
+		PasswordChangedTime: user.PasswordChangedTime,
 	}
 
 	for _, role := range user.Roles {
@@ -735,9 +864,10 @@ func (as *authStore) RoleDelete(r *pb.AuthRoleDeleteRequest) (*pb.AuthRoleDelete
 	users := tx.UnsafeGetAllUsers()
 	for _, user := range users {
 		updatedUser := &authpb.User{
-			Name:     user.Name,
-			Password: user.Password,
-			Options:  user.Options,
+			Name:                user.Name,
+			Password:            user.Password,
+			Options:             user.Options,
+			PasswordChangedTime: user.PasswordChangedTime,
 		}
 
 		for _, role := range user.Roles {
@@ -787,9 +917,76 @@ func (as *authStore) RoleAdd(r *pb.AuthRoleAddRequest) (*pb.AuthRoleAddResponse,
 }
 
 func (as *authStore) authInfoFromToken(ctx context.Context, token string) (*AuthInfo, bool) {
+	if as.isTokenRevoked(token) {
+		return nil, false
+	}
 	return as.tokenProvider.info(ctx, token, as.Revision())
 }
 
+func (as *authStore) isTokenRevoked(token string) bool {
+	as.revokedTokensMu.RLock()
+	defer as.revokedTokensMu.RUnlock()
+	if len(as.revokedTokens) == 0 {
+		return false
+	}
+	hash := sha256.Sum256([]byte(token))
+	_, ok := as.revokedTokens[string(hash[:])]
+	return ok
+}
+
+func (as *authStore) PrepareTokenRevoke(ctx context.Context, token string) (*pb.InternalAuthTokenRevokeRequest, error) {
+	if !as.IsAuthEnabled() {
+		return nil, ErrAuthNotEnabled
+	}
+	ai, ok := as.authInfoFromToken(ctx, token)
+	if !ok {
+		return nil, ErrInvalidAuthToken
+	}
+	var expireTime int64
+	if exp := as.tokenProvider.expiry(token); !exp.IsZero() {
+		expireTime = exp.Unix()
+	}
+	hash := sha256.Sum256([]byte(token))
+	return &pb.InternalAuthTokenRevokeRequest{
+		Name:       ai.Username,
+		TokenHash:  hash[:],
+		ExpireTime: expireTime,
+		RevokeTime: time.Now().Unix(),
+	}, nil
+}
+
+func (as *authStore) TokenRevoke(r *pb.InternalAuthTokenRevokeRequest) (*pb.AuthTokenRevokeResponse, error) {
+	if len(r.TokenHash) != sha256.Size {
+		return nil, ErrInvalidAuthMgmt
+	}
+
+	tx := as.be.BatchTx()
+	tx.Lock()
+	defer tx.Unlock()
+
+	as.revokedTokensMu.Lock()
+	// purge by the time of the request, so that all members purge the same
+	for hash, expireTime := range as.revokedTokens {
+		if expireTime != 0 && expireTime < r.RevokeTime {
+			tx.UnsafeDeleteRevokedToken([]byte(hash))
+			delete(as.revokedTokens, hash)
+		}
+	}
+	tx.UnsafePutRevokedToken(r.TokenHash, r.ExpireTime)
+	as.revokedTokens[string(r.TokenHash)] = r.ExpireTime
+	as.revokedTokensMu.Unlock()
+
+	// fail the requests in flight authenticated by the token
+	as.commitRevision(tx)
+
+	as.lg.Info(
+		"revoked an auth token",
+		zap.String("user-name", r.Name),
+		zap.Int64("expire-time", r.ExpireTime),
+	)
+	return &pb.AuthTokenRevokeResponse{}, nil
+}
+
 type permSlice []*authpb.Permission
 
 func (perms permSlice) Len() int {
@@ -811,6 +1008,9 @@ func (as *authStore) RoleGrantPermission(r *pb.AuthRoleGrantPermissionRequest) (
 	if !isValidPermissionRange(r.Perm.Key, r.Perm.RangeEnd) {
 		return nil, ErrInvalidAuthMgmt
 	}
+	if _, ok := authpb.Permission_Type_name[int32(r.Perm.PermType)]; !ok {
+		return nil, ErrInvalidAuthMgmt
+	}
 
 	tx := as.be.BatchTx()
 	tx.Lock()
@@ -855,12 +1055,13 @@ func (as *authStore) RoleGrantPermission(r *pb.AuthRoleGrantPermissionRequest) (
 	return &pb.AuthRoleGrantPermissionResponse{}, nil
 }
 
-func (as *authStore) isOpPermitted(userName string, revision uint64, key, rangeEnd []byte, permTyp authpb.Permission_Type) error {
+func (as *authStore) isOpPermitted(authInfo *AuthInfo, key, rangeEnd []byte, permTyp authpb.Permission_Type) error {
 	// TODO(mitake): this function would be costly so we need a caching mechanism
 	if !as.IsAuthEnabled() {
 		return nil
 	}
 
+	userName, revision := authInfo.Username, authInfo.Revision
 	// only gets rev == 0 when passed AuthInfo{}; no user given
 	if revision == 0 {
 		return ErrUserEmpty
@@ -879,6 +1080,16 @@ func (as *authStore) isOpPermitted(userName string, revision uint64, key, rangeE
 	tx.RLock()
 	defer tx.RUnlock()
 
+	if len(authInfo.Roles) > 0 {
+		if slices.Contains(authInfo.Roles, rootRole) {
+			return nil
+		}
+		if checkRangePerms(as.lg, mergeRolePerms(tx, authInfo.Roles), key, rangeEnd, permTyp) {
+			return nil
+		}
+		return ErrPermissionDenied
+	}
+
 	user := tx.UnsafeGetUser(userName)
 	if user == nil {
 		as.lg.Error("cannot find a user for permission check", zap.String("user-name", userName))
@@ -898,15 +1109,27 @@ func (as *authStore) isOpPermitted(userName string, revision uint64, key, rangeE
 }
 
 func (as *authStore) IsPutPermitted(authInfo *AuthInfo, key []byte) error {
-	return as.isOpPermitted(authInfo.Username, authInfo.Revision, key, nil, authpb.WRITE)
+	return as.isOpPermitted(authInfo, key, nil, authpb.WRITE)
 }
 
 func (as *authStore) IsRangePermitted(authInfo *AuthInfo, key, rangeEnd []byte) error {
-	return as.isOpPermitted(authInfo.Username, authInfo.Revision, key, rangeEnd, authpb.READ)
+	return as.isOpPermitted(authInfo, key, rangeEnd, authpb.READ)
 }
 
 func (as *authStore) IsDeleteRangePermitted(authInfo *AuthInfo, key, rangeEnd []byte) error {
-	return as.isOpPermitted(authInfo.Username, authInfo.Revision, key, rangeEnd, authpb.WRITE)
+	return as.isOpPermitted(authInfo, key, rangeEnd, authpb.DELETE)
+}
+
+func (as *authStore) IsWatchPermitted(authInfo *AuthInfo, key, rangeEnd []byte) error {
+	return as.isOpPermitted(authInfo, key, rangeEnd, authpb.WATCH)
+}
+
+func (as *authStore) IsLeaseAttachPermitted(authInfo *AuthInfo, key []byte) error {
+	return as.isOpPermitted(authInfo, key, nil, authpb.LEASE_ATTACH)
+}
+
+func (as *authStore) IsTxnComparePermitted(authInfo *AuthInfo, key, rangeEnd []byte) error {
+	return as.isOpPermitted(authInfo, key, rangeEnd, authpb.TXN_COMPARE)
 }
 
 func (as *authStore) IsAdminPermitted(authInfo *AuthInfo) error {
@@ -916,6 +1139,12 @@ func (as *authStore) IsAdminPermitted(authInfo *AuthInfo) error {
 	if authInfo == nil || authInfo.Username == "" {
 		return ErrUserEmpty
 	}
+	if len(authInfo.Roles) > 0 {
+		if !slices.Contains(authInfo.Roles, rootRole) {
+			return ErrPermissionDenied
+		}
+		return nil
+	}
 
 	tx := as.be.ReadTx()
 	tx.RLock()
@@ -940,7 +1169,7 @@ func (as *authStore) IsAuthEnabled() bool {
 }
 
 // NewAuthStore creates a new AuthStore.
-func NewAuthStore(lg *zap.Logger, be AuthBackend, tp TokenProvider, bcryptCost int) AuthStore {
+func NewAuthStore(lg *zap.Logger, be AuthBackend, tp TokenProvider, bcryptCost int, opts ...StoreOption) AuthStore {
 	if lg == nil {
 		lg = zap.NewNop()
 	}
@@ -970,6 +1199,10 @@ func NewAuthStore(lg *zap.Logger, be AuthBackend, tp TokenProvider, bcryptCost i
 		rangePermCache: make(map[string]*unifiedRangePermissions),
 		tokenProvider:  tp,
 		bcryptCost:     bcryptCost,
+		revokedTokens:  tx.UnsafeGetAllRevokedTokens(),
+	}
+	for _, opt := range opts {
+		opt(as)
 	}
 
 	if enabled {
@@ -1024,6 +1257,10 @@ func (as *authStore) AuthInfoFromTLS(ctx context.Context) (ai *AuthInfo) {
 			Username: chains[0].Subject.CommonName,
 			Revision: as.Revision(),
 		}
+		if ai.Username == "" {
+			// the X.509-SVIDs of SPIFFE usually have no common name.
+			ai.Username = transport.SPIFFEID(chains[0])
+		}
 		md, ok := metadata.FromIncomingContext(ctx)
 		if !ok {
 			return nil
@@ -1041,6 +1278,13 @@ func (as *authStore) AuthInfoFromTLS(ctx context.Context) (ai *AuthInfo) {
 			)
 			return nil
 		}
+		if roles, matched := matchCertRoles(as.certRoleRules, chains[0]); len(roles) > 0 {
+			// the roles are checked instead of the user of the common name
+			ai.Roles = roles
+			if ai.Username == "" {
+				ai.Username = matched
+			}
+		}
 		as.lg.Debug(
 			"found command name",
 			zap.String("common-name", ai.Username),
@@ -1139,6 +1383,9 @@ func NewTokenProvider(
 	case tokenTypeJWT:
 		return newTokenProviderJWT(lg, typeSpecificOpts)
 
+	case tokenTypeOIDC:
+		return newTokenProviderOIDC(lg, typeSpecificOpts)
+
 	case "":
 		return newTokenProviderNop()
 
@@ -1221,6 +1468,14 @@ func (as *authStore) BcryptCost() int {
 	return as.bcryptCost
 }
 
+func (as *authStore) PasswordExpiryEnabled() bool {
+	return as.passwordPolicy.MaxAge > 0
+}
+
+func (as *authStore) SetTokenTTL(ttl time.Duration) {
+	as.tokenProvider.setTTL(ttl)
+}
+
 func (as *authStore) setupMetricsReporter() {
 	reportCurrentAuthRevMu.Lock()
 	reportCurrentAuthRev = func() float64 {
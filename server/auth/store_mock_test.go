@@ -17,16 +17,18 @@ package auth
 import "go.etcd.io/etcd/api/v3/authpb"
 
 type backendMock struct {
-	users    map[string]*authpb.User
-	roles    map[string]*authpb.Role
-	enabled  bool
-	revision uint64
+	users         map[string]*authpb.User
+	roles         map[string]*authpb.Role
+	revokedTokens map[string]int64
+	enabled       bool
+	revision      uint64
 }
 
 func newBackendMock() *backendMock {
 	return &backendMock{
-		users: make(map[string]*authpb.User),
-		roles: make(map[string]*authpb.Role),
+		users:         make(map[string]*authpb.User),
+		roles:         make(map[string]*authpb.Role),
+		revokedTokens: make(map[string]int64),
 	}
 }
 
@@ -100,6 +102,14 @@ func (t txMock) UnsafeGetAllRoles() []*authpb.Role {
 	return roles
 }
 
+func (t txMock) UnsafeGetAllRevokedTokens() map[string]int64 {
+	tokens := make(map[string]int64, len(t.be.revokedTokens))
+	for hash, expireTime := range t.be.revokedTokens {
+		tokens[hash] = expireTime
+	}
+	return tokens
+}
+
 func (t txMock) Lock() {
 }
 
@@ -135,3 +145,11 @@ func (t txMock) UnsafePutRole(role *authpb.Role) {
 func (t txMock) UnsafeDeleteRole(s string) {
 	delete(t.be.roles, s)
 }
+
+func (t txMock) UnsafePutRevokedToken(hash []byte, expireTime int64) {
+	t.be.revokedTokens[string(hash)] = expireTime
+}
+
+func (t txMock) UnsafeDeleteRevokedToken(hash []byte) {
+	delete(t.be.revokedTokens, string(hash))
+}
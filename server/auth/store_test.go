@@ -16,6 +16,7 @@ package auth
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -401,7 +402,7 @@ func TestIsOpPermitted(t *testing.T) {
 
 	// check permission reflected to user
 
-	err = as.isOpPermitted("foo", as.Revision(), perm.Key, perm.RangeEnd, perm.PermType)
+	err = as.isOpPermitted(&AuthInfo{Username: "foo", Revision: as.Revision()}, perm.Key, perm.RangeEnd, perm.PermType)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -411,11 +412,67 @@ func TestIsOpPermitted(t *testing.T) {
 	as.rangePermCacheMu.Lock()
 	delete(as.rangePermCache, "foo")
 	as.rangePermCacheMu.Unlock()
-	if err := as.isOpPermitted("foo", as.Revision(), perm.Key, perm.RangeEnd, perm.PermType); !errors.Is(err, ErrPermissionDenied) {
+	if err := as.isOpPermitted(&AuthInfo{Username: "foo", Revision: as.Revision()}, perm.Key, perm.RangeEnd, perm.PermType); !errors.Is(err, ErrPermissionDenied) {
 		t.Fatal(err)
 	}
 }
 
+// TestIsOpPermittedFederated ensures that the permissions of a user
+// authenticated by an external identity provider come from the roles of its
+// token rather than from the user of the same name in the store.
+func TestIsOpPermittedFederated(t *testing.T) {
+	as, tearDown := setupAuthStore(t)
+	defer tearDown(t)
+
+	perm := &authpb.Permission{PermType: authpb.READWRITE, Key: []byte("foo"), RangeEnd: []byte("fop")}
+	_, err := as.RoleGrantPermission(&pb.AuthRoleGrantPermissionRequest{Name: "role-test", Perm: perm})
+	require.NoError(t, err)
+
+	// "root" is a local user with the root role
+	ai := &AuthInfo{Username: "root", Revision: as.Revision(), Roles: []string{"role-test"}}
+	require.NoError(t, as.IsPutPermitted(ai, []byte("foo1")))
+	require.NoError(t, as.IsRangePermitted(ai, []byte("foo"), []byte("fop")))
+	require.ErrorIs(t, as.IsPutPermitted(ai, []byte("bar")), ErrPermissionDenied)
+	require.ErrorIs(t, as.IsAdminPermitted(ai), ErrPermissionDenied)
+
+	ai.Roles = []string{"non-existent-role"}
+	require.ErrorIs(t, as.IsPutPermitted(ai, []byte("foo1")), ErrPermissionDenied)
+
+	ai = &AuthInfo{Username: "nouser", Revision: as.Revision(), Roles: []string{"role-test", rootRole}}
+	require.NoError(t, as.IsPutPermitted(ai, []byte("bar")))
+	require.NoError(t, as.IsAdminPermitted(ai))
+}
+
+// TestIsOpPermittedImplied ensures that READ and WRITE imply the finer grained
+// permissions of their kind, but not the other way around.
+func TestIsOpPermittedImplied(t *testing.T) {
+	as, tearDown := setupAuthStore(t)
+	defer tearDown(t)
+
+	for key, permType := range map[string]authpb.Permission_Type{"r": authpb.READ, "w": authpb.WRITE, "x": authpb.WATCH} {
+		perm := &authpb.Permission{PermType: permType, Key: []byte(key)}
+		_, err := as.RoleGrantPermission(&pb.AuthRoleGrantPermissionRequest{Name: "role-test", Perm: perm})
+		require.NoError(t, err)
+	}
+	_, err := as.UserGrantRole(&pb.AuthUserGrantRoleRequest{User: "foo", Role: "role-test"})
+	require.NoError(t, err)
+
+	ai := &AuthInfo{Username: "foo", Revision: as.Revision()}
+	require.NoError(t, as.IsWatchPermitted(ai, []byte("r"), nil))
+	require.NoError(t, as.IsTxnComparePermitted(ai, []byte("r"), nil))
+	require.ErrorIs(t, as.IsDeleteRangePermitted(ai, []byte("r"), nil), ErrPermissionDenied)
+	require.NoError(t, as.IsDeleteRangePermitted(ai, []byte("w"), nil))
+	require.NoError(t, as.IsLeaseAttachPermitted(ai, []byte("w")))
+	require.ErrorIs(t, as.IsWatchPermitted(ai, []byte("w"), nil), ErrPermissionDenied)
+	require.NoError(t, as.IsWatchPermitted(ai, []byte("x"), nil))
+	require.ErrorIs(t, as.IsRangePermitted(ai, []byte("x"), nil), ErrPermissionDenied)
+	require.ErrorIs(t, as.IsTxnComparePermitted(ai, []byte("x"), nil), ErrPermissionDenied)
+
+	perm := &authpb.Permission{PermType: authpb.Permission_Type(100), Key: []byte("y")}
+	_, err = as.RoleGrantPermission(&pb.AuthRoleGrantPermissionRequest{Name: "role-test", Perm: perm})
+	require.ErrorIs(t, err, ErrInvalidAuthMgmt)
+}
+
 func TestGetUser(t *testing.T) {
 	as, tearDown := setupAuthStore(t)
 	defer tearDown(t)
@@ -852,6 +909,43 @@ func TestAuthInfoFromCtx(t *testing.T) {
 	}
 }
 
+func TestTokenRevoke(t *testing.T) {
+	as, tearDown := setupAuthStore(t)
+	defer tearDown(t)
+
+	ctx := context.WithValue(context.WithValue(t.Context(), AuthenticateParamIndex{}, uint64(1)), AuthenticateParamSimpleTokenPrefix{}, "dummy")
+	resp, err := as.Authenticate(ctx, "foo", "bar")
+	require.NoError(t, err)
+
+	r, err := as.PrepareTokenRevoke(t.Context(), resp.Token)
+	require.NoError(t, err)
+	require.Equal(t, "foo", r.Name)
+	require.NotZero(t, r.ExpireTime)
+
+	// revocations past their expiry are purged
+	_, err = as.TokenRevoke(&pb.InternalAuthTokenRevokeRequest{TokenHash: make([]byte, sha256.Size), ExpireTime: r.RevokeTime - 1})
+	require.NoError(t, err)
+	_, err = as.TokenRevoke(&pb.InternalAuthTokenRevokeRequest{TokenHash: []byte("short")})
+	require.ErrorIs(t, err, ErrInvalidAuthMgmt)
+
+	rev := as.Revision()
+	_, err = as.TokenRevoke(r)
+	require.NoError(t, err)
+	require.Greater(t, as.Revision(), rev)
+	require.Len(t, as.revokedTokens, 1)
+
+	ctx = metadata.NewIncomingContext(t.Context(), metadata.New(map[string]string{rpctypes.TokenFieldNameGRPC: resp.Token}))
+	_, err = as.AuthInfoFromCtx(ctx)
+	require.ErrorIs(t, err, ErrInvalidAuthToken)
+	_, err = as.PrepareTokenRevoke(t.Context(), resp.Token)
+	require.ErrorIs(t, err, ErrInvalidAuthToken)
+
+	// the revocations survive a recovery
+	as.Recover(as.be)
+	_, err = as.AuthInfoFromCtx(ctx)
+	require.ErrorIs(t, err, ErrInvalidAuthToken)
+}
+
 func TestAuthDisable(t *testing.T) {
 	as, tearDown := setupAuthStore(t)
 	defer tearDown(t)
@@ -16,6 +16,7 @@ package config
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"path/filepath"
 	"sort"
@@ -23,6 +24,7 @@ import (
 	"time"
 
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	bolt "go.etcd.io/bbolt"
@@ -30,8 +32,10 @@ import (
 	"go.etcd.io/etcd/client/pkg/v3/types"
 	"go.etcd.io/etcd/pkg/v3/featuregate"
 	"go.etcd.io/etcd/pkg/v3/netutil"
+	"go.etcd.io/etcd/server/v3/etcdserver/admission"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3discovery"
 	"go.etcd.io/etcd/server/v3/storage/datadir"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
 )
 
 const (
@@ -62,14 +66,49 @@ type ServerConfig struct {
 	// follower to catch up.
 	SnapshotCatchUpEntries uint64
 
+	// SnapshotSendRateLimit is the number of bytes per second of the
+	// snapshots sent to clients, and PeerSnapshotSendRateLimit of the
+	// snapshots sent to peers catching up. 0 is no limit.
+	SnapshotSendRateLimit     int64
+	PeerSnapshotSendRateLimit int64
+
+	// PeerAddressRefreshInterval is the interval at which the host names of
+	// the peer URLs are re-resolved. 0 disables the re-resolution.
+	PeerAddressRefreshInterval time.Duration
+
+	// PeerNetworkFaults are the latencies, jitters and drop rates injected
+	// in the messages sent to the peers, given as
+	// '<peer-url>=<latency>/<jitter>/<drop-rate>', for testing only. The
+	// random faults are drawn from a source seeded with PeerNetworkFaultsSeed.
+	PeerNetworkFaults     []string
+	PeerNetworkFaultsSeed int64
+
 	MaxSnapFiles uint
 	MaxWALFiles  uint
+	// WALCompression is the compression of the entries saved to the WAL,
+	// one of "none", "snappy" and "zstd".
+	WALCompression string
 
 	// BackendBatchInterval is the maximum time before commit the backend transaction.
 	BackendBatchInterval time.Duration
 	// BackendBatchLimit is the maximum operations before commit the backend transaction.
 	BackendBatchLimit int
-
+	// BackendBatchAdaptive adapts the backend batch interval and limit within
+	// their bounds.
+	BackendBatchAdaptive    bool
+	BackendBatchIntervalMin time.Duration
+	BackendBatchIntervalMax time.Duration
+	BackendBatchLimitMin    int
+	BackendBatchLimitMax    int
+	// BackendReclaimFreePages returns the free pages of the backend to the
+	// filesystem in the background, every BackendReclaimInterval, once at
+	// least BackendReclaimThreshold of the backend is free.
+	BackendReclaimFreePages bool
+	BackendReclaimThreshold float64
+	BackendReclaimInterval  time.Duration
+
+	// StorageEngine is the storage engine of a new backend.
+	StorageEngine string
 	// BackendFreelistType is the type of the backend boltdb freelist.
 	BackendFreelistType bolt.FreelistType
 
@@ -123,8 +162,42 @@ type ServerConfig struct {
 	AutoCompactionMode      string
 	CompactionBatchLimit    int
 	CompactionSleepInterval time.Duration
-	QuotaBackendBytes       int64
-	MaxTxnOps               uint
+	// CompactionTargetCommitLatency is the latency of the compaction batch
+	// commits above which the sleep between the batches grows.
+	CompactionTargetCommitLatency time.Duration
+	CompactionWorkers             int
+	SlowWatcherMaxBacklog         int64
+	SlowWatcherPolicy             string
+	// MaxWatchStreams is the maximum number of watch streams the server
+	// serves, and MaxWatchersPerStream the maximum number of watchers on
+	// each of them. 0 is no limit.
+	MaxWatchStreams      int
+	MaxWatchersPerStream int
+	QuotaBackendBytes    int64
+	MaxTxnOps            uint
+
+	// QuotaBackendGrace checks the quota against the size of the backend in
+	// use, and rejects the writes exceeding it without raising the NOSPACE
+	// alarm, so that compactions make room for writes again.
+	QuotaBackendGrace bool
+	// QuotaBackendWarningThresholds are the fractions of the quota above
+	// which the size of the backend is warned about.
+	QuotaBackendWarningThresholds []float64
+
+	// KeyQuotas bound the keys of key prefixes, namespaces or auth roles,
+	// given as '<prefix|namespace|role>:<name>=<max-bytes>/<max-keys>'.
+	KeyQuotas []string
+
+	// Namespaces are the namespaces the keys of the client requests may be
+	// isolated in, each under the key prefix NamespaceKeyPrefix returns.
+	Namespaces []string
+	// NamespacesKeyPrefix is the prefix of the key prefixes of the
+	// namespaces.
+	NamespacesKeyPrefix string
+
+	// CompactionControlKey is a key whose value, when written, is the
+	// revision the leader compacts the key-value store to.
+	CompactionControlKey string
 
 	// MaxRequestBytes is the maximum request size to send over raft.
 	MaxRequestBytes uint
@@ -133,23 +206,221 @@ type ServerConfig struct {
 	// streams that each client can open at a time.
 	MaxConcurrentStreams uint32
 
+	// RequestDeadlineMargin rejects a unary request with DeadlineExceeded
+	// before doing any work if less than this much time is left before its
+	// deadline. 0 disables the check.
+	RequestDeadlineMargin time.Duration
+
+	// MaxConcurrentExpensiveRanges bounds the number of expensive ranges
+	// read at a time. 0 is no limit.
+	MaxConcurrentExpensiveRanges int
+	// ExpensiveRangeLimit is the limit above which a range over multiple
+	// keys is expensive.
+	ExpensiveRangeLimit int64
+
+	// MaxCallerLabels is the maximum number of distinct caller labels that
+	// get their own series in the per-caller request metrics. Requests of
+	// any further caller are counted under the "other" label.
+	MaxCallerLabels int
+
+	// ClientIdentityMetrics labels the request metrics and the slow request
+	// logs with the client identity: its auth user, or else the common name
+	// of its client certificate. Past MaxCallerLabels identities, the
+	// requests of any further identity are counted under the "other" label.
+	ClientIdentityMetrics bool
+
+	// ClientRateLimitQPS is the number of requests per second, and
+	// ClientRateLimitBurst the burst of requests, each client identity may
+	// send. ClientRateLimitBytes is the number of request and response
+	// bytes per second of each client identity. 0 disables a limit.
+	ClientRateLimitQPS   float64
+	ClientRateLimitBurst int
+	ClientRateLimitBytes int64
+
+	// QoSMaxInflightRequests is the number of unary client requests served
+	// at a time; the requests over it wait in a queue per QoS class. 0
+	// disables the QoS scheduling.
+	QoSMaxInflightRequests int
+
+	// SerializableHealthCheck makes /health use a serializable read by
+	// default.
+	SerializableHealthCheck bool
+	// HealthCheckTimeout bounds the read done by /health. 0 means the
+	// request timeout (ReqTimeout) is used.
+	HealthCheckTimeout time.Duration
+
 	WarningApplyDuration        time.Duration
 	WarningUnaryRequestDuration time.Duration
+	// LogSlowRequestsAbove logs the latency breakdown of the unary requests
+	// slower than it. 0 disables the logging.
+	LogSlowRequestsAbove time.Duration
+	// LogSlowRequestsSampleInitial and LogSlowRequestsSampleThereafter sample
+	// the slow request logs: each second, the first LogSlowRequestsSampleInitial
+	// ones are logged, then every LogSlowRequestsSampleThereafter-th one.
+	LogSlowRequestsSampleInitial    int
+	LogSlowRequestsSampleThereafter int
+
+	// ApplyBacklogAlertThreshold is the number of committed but not yet applied
+	// entries above which the apply backlog is counted as dangerously backed up.
+	// 0 disables the alert.
+	ApplyBacklogAlertThreshold uint64
+	// ApplyBacklogBackpressureThreshold is the number of committed but not yet
+	// applied entries above which new proposals wait for the backlog to drain
+	// back to it. 0 disables the backpressure.
+	ApplyBacklogBackpressureThreshold uint64
+
+	// KeyAccessSampleRate is the fraction of range requests whose keys get
+	// their last access time recorded. 0 disables the tracking.
+	KeyAccessSampleRate float64
+
+	// BackupURL is the URL of the sink the leader continuously backs up to.
+	// Empty disables the backup.
+	BackupURL string
+	// BackupInterval is the interval between two shipments of the committed
+	// raft entries to the backup sink.
+	BackupInterval time.Duration
+	// BackupSnapshotInterval is the interval between two base snapshots
+	// shipped to the backup sink.
+	BackupSnapshotInterval time.Duration
+	// BackupRetention is the number of base snapshots kept in the backup
+	// sink. 0 keeps all of them.
+	BackupRetention int
+
+	// CDCSinkURLs are the URLs of the sinks the leader publishes the
+	// key-value events to.
+	CDCSinkURLs []string
+	// CDCCheckpointKeyPrefix is the prefix of the keys checkpointing the
+	// events published to each CDC sink. The events of these keys are not
+	// published.
+	CDCCheckpointKeyPrefix string
+
+	// MirrorTargetEndpoints are the endpoints of the cluster the leader
+	// mirrors the keys to.
+	MirrorTargetEndpoints []string
+	// MirrorTargetTLS is the TLS configuration of the connections to the
+	// mirror target cluster, nil for insecure connections.
+	MirrorTargetTLS *tls.Config
+	// MirrorPrefix is the prefix of the keys mirrored, replaced by
+	// MirrorDestPrefix in the keys of the target cluster.
+	MirrorPrefix     string
+	MirrorDestPrefix string
+	// MirrorCheckpointKey is the key of the target cluster holding the
+	// revision up to which the keys were mirrored.
+	MirrorCheckpointKey string
+	// MirrorMaxTxnOps bounds the operations of a txn to the target cluster.
+	MirrorMaxTxnOps int
+
+	// AutoSnapshotInterval is the interval between two snapshots of the
+	// backend saved to AutoSnapshotDir. 0 disables the auto snapshots.
+	AutoSnapshotInterval time.Duration
+	// AutoSnapshotDir is the directory of the auto snapshots.
+	AutoSnapshotDir string
+	// AutoSnapshotRetention is the number of auto snapshots of the member
+	// kept in AutoSnapshotDir. 0 keeps all of them.
+	AutoSnapshotRetention uint
+	// AutoSnapshotLeaderOnly saves the auto snapshots only while the member
+	// is the leader.
+	AutoSnapshotLeaderOnly bool
+
+	// AutoDefragSchedule is the cron expression of the times the member
+	// defragments its backend. Empty disables the auto defragmentation.
+	AutoDefragSchedule string
+	// AutoDefragRatio is the minimum ratio of the backend size not in use
+	// for the auto defragmentation to run.
+	AutoDefragRatio float64
+	// AutoDefragTransferLeadership moves the leadership to another member
+	// before the auto defragmentation of the leader, which skips it otherwise.
+	AutoDefragTransferLeadership bool
+	// AutoDefragLockKey is the key locked by the member defragmenting, so
+	// that the members defragment one at a time.
+	AutoDefragLockKey string
+
+	// LeaseStewardPrefixes are the lock and election key prefixes the leader
+	// looks for keys without a live lease in. Empty disables the lease
+	// steward.
+	LeaseStewardPrefixes []string
+	// LeaseStewardInterval is the interval between two scans of the
+	// LeaseStewardPrefixes.
+	LeaseStewardInterval time.Duration
+	// LeaseStewardDelete deletes the keys found without a live lease by two
+	// scans in a row.
+	LeaseStewardDelete bool
+	// LearnerAutoPromoteDuration is the time a learner has to stay caught up
+	// with the leader before the leader promotes it. 0 disables the auto
+	// promotion.
+	LearnerAutoPromoteDuration time.Duration
+	// LearnerAutoPromoteMaxLag is the maximum number of raft entries a
+	// learner caught up with the leader lags behind it.
+	LearnerAutoPromoteMaxLag uint64
+	// FollowerLagAlarmThreshold is the number of raft entries a voting
+	// follower may lag behind the leader before the leader raises a
+	// FOLLOWER_LAG alarm for it. 0 disables the alarm.
+	FollowerLagAlarmThreshold uint64
+	// FollowerLagAlarmDuration is the time a follower has to stay behind
+	// FollowerLagAlarmThreshold before the alarm is raised.
+	FollowerLagAlarmDuration time.Duration
+	// LeaderLeaseReads lets the leader serve linearizable reads from its
+	// commit index while it holds the leader lease, instead of confirming its
+	// leadership with a ReadIndex round trip.
+	LeaderLeaseReads bool
+	// LeaderLeaseClockDrift is the maximum clock drift between the members
+	// over an election timeout, by which the leader lease is shortened.
+	LeaderLeaseClockDrift time.Duration
+	// Witness runs the member as a witness, voting in raft but storing no
+	// keys and serving no client requests.
+	Witness bool
+	// EncryptionKEKFile is the file of the key encryption keys of the
+	// backend encryption.
+	EncryptionKEKFile string
+	// EncryptionKMSURL is the URL of the KMS webhook wrapping the data
+	// encryption keys of the backend encryption.
+	EncryptionKMSURL string
+	// EncryptionCodec, if set, encrypts the values of the backend.
+	EncryptionCodec *encryption.Codec
+
+	// EnableLeaderChangeEvents emits a structured log event with the old leader,
+	// the new leader and the term on every leadership change.
+	EnableLeaderChangeEvents bool
+	// LeaderChangeEventKey is the key the newly elected leader writes the leader
+	// change event to, so that clients can watch for leadership changes.
+	// Leader change events are not written to the keyspace if empty.
+	LeaderChangeEventKey string
 
 	StrictReconfigCheck bool
 
 	// ClientCertAuthEnabled is true when cert has been signed by the client CA.
 	ClientCertAuthEnabled bool
+	// ClientCertRoleRules map the attributes of client certificates to roles.
+	ClientCertRoleRules []string
 
 	AuthToken  string
 	BcryptCost uint
 	TokenTTL   uint
 
+	// AuthLockoutUserFailures and AuthLockoutSourceFailures are the numbers
+	// of consecutive authentication failures locking out a user or a client
+	// address, 0 disabling the lockout.
+	AuthLockoutUserFailures   int
+	AuthLockoutSourceFailures int
+	// AuthLockoutDuration is the duration of a first lockout, doubled on each
+	// further failure up to AuthLockoutMaxDuration.
+	AuthLockoutDuration    time.Duration
+	AuthLockoutMaxDuration time.Duration
+
+	// AuthPasswordMaxAge is the duration a password is valid for after it is
+	// set, 0 disabling the expiry. Expired passwords still authenticate
+	// during AuthPasswordGracePeriod, so that their users can change them.
+	AuthPasswordMaxAge      time.Duration
+	AuthPasswordGracePeriod time.Duration
+
 	// InitialCorruptCheck is true to check data corruption on boot
 	// before serving any peer/client traffic.
 	InitialCorruptCheck  bool
 	CorruptCheckTime     time.Duration
 	CompactHashCheckTime time.Duration
+	// CompactHashCheckQuarantine quarantines the members whose compaction
+	// hash diverged from the majority.
+	CompactHashCheckQuarantine bool
 
 	// PreVote is true to enable Raft Pre-Vote.
 	PreVote bool
@@ -159,6 +430,12 @@ type ServerConfig struct {
 
 	// Logger logs server-side operations.
 	Logger *zap.Logger
+	// AuditLogger, if set, receives a structured record of every mutating
+	// client request.
+	AuditLogger *zap.Logger
+	// AdmissionWebhook, if set, reviews the puts, deletes and txns with
+	// writes before they are proposed.
+	AdmissionWebhook *admission.Webhook
 
 	ForceNewCluster bool
 
@@ -167,10 +444,16 @@ type ServerConfig struct {
 
 	EnableGRPCGateway bool
 
+	// EnableGRPCReflection registers the gRPC server reflection service.
+	EnableGRPCReflection bool
+
 	// EnableDistributedTracing enables distributed tracing using OpenTelemetry protocol.
 	EnableDistributedTracing bool
 	// TracerOptions are options for OpenTelemetry gRPC interceptor.
 	TracerOptions []otelgrpc.Option
+	// TracerProvider creates the spans of the raft proposal and apply path of
+	// traced requests. Nil disables them.
+	TracerProvider trace.TracerProvider
 
 	WatchProgressNotifyInterval time.Duration
 
@@ -206,6 +489,12 @@ type ServerConfig struct {
 
 	// Metrics types of metrics - should be either 'basic' or 'extensive'
 	Metrics string
+	// GRPCHistogramBuckets are the bucket boundaries in seconds of the gRPC
+	// handling time histogram. Empty means the prometheus default buckets.
+	GRPCHistogramBuckets []float64
+	// MetricsKeyPrefixes are the key prefixes whose number of keys and total
+	// size of values are exported as gauges.
+	MetricsKeyPrefixes []string
 }
 
 // VerifyBootstrap sanity-checks the initial config for bootstrap case
@@ -305,6 +594,12 @@ func (c *ServerConfig) advertiseMatchesCluster() error {
 
 func (c *ServerConfig) MemberDir() string { return datadir.ToMemberDir(c.DataDir) }
 
+// NamespaceKeyPrefix returns the key prefix the keys of namespace ns are
+// isolated under.
+func (c *ServerConfig) NamespaceKeyPrefix(ns string) string {
+	return c.NamespacesKeyPrefix + ns + "/"
+}
+
 func (c *ServerConfig) WALDir() string {
 	if c.DedicatedWALDir != "" {
 		return c.DedicatedWALDir
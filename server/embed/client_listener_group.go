@@ -0,0 +1,145 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embed
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"google.golang.org/grpc"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+)
+
+// ClientListenerGroup is a group of client URLs served with their own TLS
+// configuration instead of ClientTLSInfo, and optionally restricted to a set
+// of RPCs, e.g. a plaintext listener only serving the metrics next to the
+// mutual TLS listeners of ListenClientUrls.
+type ClientListenerGroup struct {
+	// Name identifies the group in the logs.
+	Name string `json:"name"`
+
+	ListenURLs     []url.URL `json:"-"`
+	ListenURLsJSON string    `json:"listen-urls"`
+
+	// TLSInfo configures the TLS of the https and unixs ListenURLs. The
+	// cipher suites and TLS versions are the ones of the client listeners.
+	TLSInfo      transport.TLSInfo `json:"-"`
+	SecurityJSON securityConfig    `json:"client-transport-security"`
+
+	// AllowedRPCs restricts the requests served by the group to the gRPC
+	// methods, e.g. "/etcdserverpb.KV/Range", and the HTTP paths, e.g.
+	// "/metrics", it lists. A trailing "*" allows all the methods or paths
+	// prefixed by the pattern, e.g. "/etcdserverpb.KV/*". All the requests
+	// are served if empty.
+	AllowedRPCs []string `json:"allowed-rpcs"`
+}
+
+func (g *ClientListenerGroup) validate() error {
+	if g.Name == "" {
+		return fmt.Errorf("client listener group without name")
+	}
+	if len(g.ListenURLs) == 0 {
+		return fmt.Errorf("client listener group %q has no listen URLs", g.Name)
+	}
+	if err := checkBindURLs(g.ListenURLs); err != nil {
+		return fmt.Errorf("client listener group %q: %w", g.Name, err)
+	}
+	for _, u := range g.ListenURLs {
+		if (u.Scheme == "https" || u.Scheme == "unixs" || u.Scheme == "quic") && g.TLSInfo.Empty() {
+			return fmt.Errorf("client listener group %q: TLS key/cert must be provided for client url %s with HTTPS scheme", g.Name, u.String())
+		}
+	}
+	for _, p := range g.AllowedRPCs {
+		if !strings.HasPrefix(p, "/") {
+			return fmt.Errorf("client listener group %q: allowed RPC %q must start with '/'", g.Name, p)
+		}
+	}
+	return nil
+}
+
+// allowed returns true if the gRPC method or HTTP path is allowed by
+// patterns, which allow everything if empty.
+func allowed(patterns []string, path string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		} else if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+// restrictedGRPCOptions returns the options of the gRPC servers of sctx
+// rejecting the methods not allowed by its group.
+func (sctx *serveCtx) restrictedGRPCOptions() []grpc.ServerOption {
+	if len(sctx.allowedRPCs) == 0 {
+		return nil
+	}
+	patterns := sctx.allowedRPCs
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			if !allowed(patterns, info.FullMethod) {
+				return nil, rpctypes.ErrGRPCPermissionDenied
+			}
+			return handler(ctx, req)
+		}),
+		grpc.ChainStreamInterceptor(func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if !allowed(patterns, info.FullMethod) {
+				return rpctypes.ErrGRPCPermissionDenied
+			}
+			return handler(srv, ss)
+		}),
+	}
+}
+
+// restrictHTTP wraps h to reject the paths not allowed by the group of sctx.
+func (sctx *serveCtx) restrictHTTP(h http.Handler) http.Handler {
+	if len(sctx.allowedRPCs) == 0 {
+		return h
+	}
+	patterns := sctx.allowedRPCs
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowed(patterns, r.URL.Path) {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// clientCertAuthEnabled returns true if a client listener requires client
+// certificates, whose common names then authenticate the requests.
+func (cfg *Config) clientCertAuthEnabled() bool {
+	if cfg.ClientTLSInfo.ClientCertAuth {
+		return true
+	}
+	for _, g := range cfg.ClientListenerGroups {
+		if g.TLSInfo.ClientCertAuth {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embed
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func TestAllowed(t *testing.T) {
+	patterns := []string{"/metrics", "/etcdserverpb.KV/*"}
+	assert.True(t, allowed(nil, "/etcdserverpb.Maintenance/Status"))
+	assert.True(t, allowed(patterns, "/metrics"))
+	assert.False(t, allowed(patterns, "/metrics/extra"))
+	assert.True(t, allowed(patterns, "/etcdserverpb.KV/Range"))
+	assert.False(t, allowed(patterns, "/etcdserverpb.Maintenance/Status"))
+}
+
+func TestClientListenerGroupValidate(t *testing.T) {
+	u, err := url.Parse("https://127.0.0.1:2381")
+	require.NoError(t, err)
+	tests := []struct {
+		name    string
+		g       ClientListenerGroup
+		wantErr bool
+	}{
+		{"valid", ClientListenerGroup{Name: "metrics", ListenURLs: []url.URL{{Scheme: "http", Host: "127.0.0.1:2381"}}, AllowedRPCs: []string{"/metrics"}}, false},
+		{"no name", ClientListenerGroup{ListenURLs: []url.URL{{Scheme: "http", Host: "127.0.0.1:2381"}}}, true},
+		{"no urls", ClientListenerGroup{Name: "metrics"}, true},
+		{"https without certs", ClientListenerGroup{Name: "data", ListenURLs: []url.URL{*u}}, true},
+		{"relative pattern", ClientListenerGroup{Name: "metrics", ListenURLs: []url.URL{{Scheme: "http", Host: "127.0.0.1:2381"}}, AllowedRPCs: []string{"metrics"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.g.validate()
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClientListenerGroupFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "etcd.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+client-listener-groups:
+- name: data
+  listen-urls: https://127.0.0.1:2383
+  client-transport-security:
+    cert-file: server.crt
+    key-file: server.key
+    trusted-ca-file: ca.crt
+    client-cert-auth: true
+- name: metrics
+  listen-urls: http://127.0.0.1:2381
+  allowed-rpcs: [/metrics, /health]
+`), 0o600))
+
+	cfg, err := ConfigFromFile(path)
+	require.NoError(t, err)
+	require.Len(t, cfg.ClientListenerGroups, 2)
+	data, metrics := cfg.ClientListenerGroups[0], cfg.ClientListenerGroups[1]
+	assert.Equal(t, "https://127.0.0.1:2383", data.ListenURLs[0].String())
+	assert.Equal(t, "server.crt", data.TLSInfo.CertFile)
+	assert.True(t, data.TLSInfo.ClientCertAuth)
+	assert.Empty(t, data.AllowedRPCs)
+	assert.Equal(t, "http://127.0.0.1:2381", metrics.ListenURLs[0].String())
+	assert.Equal(t, []string{"/metrics", "/health"}, metrics.AllowedRPCs)
+	assert.True(t, cfg.clientCertAuthEnabled())
+}
+
+func TestClientListenerGroupAllowedRPCs(t *testing.T) {
+	cfg := NewConfig()
+	urls := newEmbedURLs(3)
+	curls := []url.URL{urls[0]}
+	purls := []url.URL{urls[1]}
+	cfg.ListenClientUrls, cfg.AdvertiseClientUrls = curls, curls
+	cfg.ListenPeerUrls, cfg.AdvertisePeerUrls = purls, purls
+	cfg.InitialCluster = "default=" + purls[0].String()
+	cfg.Dir = t.TempDir()
+	cfg.ClientListenerGroups = []ClientListenerGroup{{
+		Name:        "metrics",
+		ListenURLs:  []url.URL{urls[2]},
+		AllowedRPCs: []string{"/metrics", "/etcdserverpb.Maintenance/*"},
+	}}
+
+	e, err := StartEtcd(cfg)
+	require.NoError(t, err)
+	defer e.Close()
+	<-e.Server.ReadyNotify()
+
+	hc := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", urls[2].Host)
+		},
+	}}
+	for path, code := range map[string]int{"/metrics": http.StatusOK, "/version": http.StatusForbidden} {
+		resp, err := hc.Get("http://localhost" + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equalf(t, code, resp.StatusCode, "GET %s", path)
+	}
+
+	cli, err := clientv3.New(clientv3.Config{Endpoints: []string{urls[2].String()}, DialTimeout: 5 * time.Second})
+	require.NoError(t, err)
+	defer cli.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = cli.Status(ctx, urls[2].String())
+	require.NoError(t, err)
+	_, err = cli.Get(ctx, "foo")
+	require.ErrorIs(t, err, rpctypes.ErrPermissionDenied)
+}
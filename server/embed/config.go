@@ -26,6 +26,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -45,13 +46,22 @@ import (
 	"go.etcd.io/etcd/pkg/v3/featuregate"
 	"go.etcd.io/etcd/pkg/v3/flags"
 	"go.etcd.io/etcd/pkg/v3/netutil"
+	"go.etcd.io/etcd/pkg/v3/schedule"
+	"go.etcd.io/etcd/server/v3/auth"
 	"go.etcd.io/etcd/server/v3/config"
 	"go.etcd.io/etcd/server/v3/etcdserver"
+	"go.etcd.io/etcd/server/v3/etcdserver/admission"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/etcdhttp"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/k8sdiscovery"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/rafthttp"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3compactor"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3discovery"
 	"go.etcd.io/etcd/server/v3/features"
+	"go.etcd.io/etcd/server/v3/storage"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+	"go.etcd.io/etcd/server/v3/storage/wal"
 )
 
 const (
@@ -66,6 +76,9 @@ const (
 	DefaultWarningUnaryRequestDuration = 300 * time.Millisecond
 	DefaultMaxRequestBytes             = 1.5 * 1024 * 1024
 	DefaultMaxConcurrentStreams        = math.MaxUint32
+	DefaultExpensiveRangeLimit         = 1000
+	DefaultMaxCallerLabels             = 64
+	DefaultAdmissionWebhookTimeout     = 5 * time.Second
 	DefaultGRPCKeepAliveMinTime        = 5 * time.Second
 	DefaultGRPCKeepAliveInterval       = 2 * time.Hour
 	DefaultGRPCKeepAliveTimeout        = 20 * time.Second
@@ -74,8 +87,43 @@ const (
 	DefaultAutoCompactionRetention     = "0"
 	DefaultAuthToken                   = "simple"
 	DefaultCompactHashCheckTime        = time.Minute
+	DefaultBackupInterval              = 10 * time.Second
+	DefaultBackupSnapshotInterval      = 24 * time.Hour
+	DefaultBackupRetention             = 7
+	DefaultCDCCheckpointKeyPrefix      = "/etcd/cdc-checkpoint/"
+	DefaultMirrorCheckpointKey         = "/etcd/mirror-checkpoint"
+	DefaultMirrorMaxTxnOps             = 128
+	DefaultNamespacesKeyPrefix         = "/namespaces/"
+	DefaultAutoSnapshotRetention       = 5
+	DefaultAutoDefragRatio             = 0.5
+	DefaultLearnerAutoPromoteMaxLag    = 1000
+	DefaultFollowerLagAlarmDuration    = time.Minute
+	DefaultLeaderLeaseClockDrift       = 100 * time.Millisecond
+	DefaultPeerAddressRefreshInterval  = 30 * time.Second
+	DefaultAutoDefragLockKey           = "/etcd/auto-defrag-lock"
+	DefaultLeaseStewardInterval        = time.Minute
+	DefaultTLSReloadRecycleWindow      = time.Minute
 	DefaultLoggingFormat               = "json"
 
+	// DefaultBackendBatchIntervalMin, DefaultBackendBatchIntervalMax,
+	// DefaultBackendBatchLimitMin and DefaultBackendBatchLimitMax are the
+	// bounds of the backend batch interval and limit adapted by
+	// --backend-batch-adaptive.
+	DefaultBackendBatchIntervalMin = 10 * time.Millisecond
+	DefaultBackendBatchIntervalMax = time.Second
+	DefaultBackendBatchLimitMin    = 1000
+	DefaultBackendBatchLimitMax    = 100000
+
+	// DefaultBackendReclaimThreshold and DefaultBackendReclaimInterval are
+	// when --backend-reclaim-free-pages reclaims the free pages.
+	DefaultBackendReclaimThreshold = 0.5
+	DefaultBackendReclaimInterval  = 10 * time.Second
+
+	// DefaultLogSlowRequestsSampleInitial and DefaultLogSlowRequestsSampleThereafter
+	// log the first 10 slow requests of each second, then every 100th one.
+	DefaultLogSlowRequestsSampleInitial    = 10
+	DefaultLogSlowRequestsSampleThereafter = 100
+
 	DefaultDiscoveryDialTimeout       = 2 * time.Second
 	DefaultDiscoveryRequestTimeOut    = 5 * time.Second
 	DefaultDiscoveryKeepAliveTime     = 2 * time.Second
@@ -105,6 +153,10 @@ const (
 	DefaultDistributedTracingAddress = "localhost:4317"
 	// DefaultDistributedTracingServiceName is the default etcd service name.
 	DefaultDistributedTracingServiceName = "etcd"
+	// DefaultOTLPMetricsAddress is the default collector address for metrics.
+	DefaultOTLPMetricsAddress = "localhost:4317"
+	// DefaultOTLPMetricsInterval is the default interval between metrics pushes.
+	DefaultOTLPMetricsInterval = time.Minute
 
 	// DefaultStrictReconfigCheck is the default value for "--strict-reconfig-check" flag.
 	// It's enabled by default.
@@ -132,7 +184,8 @@ var (
 	defaultHostStatus error
 
 	// indirection for testing
-	getCluster = srv.GetCluster
+	getCluster    = srv.GetCluster
+	getK8sCluster = k8sdiscovery.GetCluster
 )
 
 var (
@@ -150,6 +203,15 @@ var (
 	// revision 5000 when the current revision is 6000.
 	// This runs every 5-minute if enough of logs have proceeded.
 	CompactorModeRevision = v3compactor.ModeRevision
+
+	// CompactorModeSize is size-based compaction mode
+	// for "Config.AutoCompactionMode" field.
+	// If "AutoCompactionMode" is CompactorModeSize and
+	// "AutoCompactionRetention" is "1GB", it compacts log on
+	// the current revision once the revisions such a compaction
+	// deletes take more than 1GB in the backend.
+	// This is checked every 5-minute.
+	CompactorModeSize = v3compactor.ModeSize
 )
 
 func init() {
@@ -175,12 +237,38 @@ type Config struct {
 	// follower to catch up.
 	SnapshotCatchUpEntries uint64 `json:"snapshot-catchup-entries"`
 
+	// SnapshotSendRateLimit is the number of bytes per second of all the
+	// snapshots sent to clients by the Maintenance Snapshot RPC, so that a
+	// snapshot does not starve the other traffic. 0 disables the limit.
+	SnapshotSendRateLimit int64 `json:"snapshot-send-rate-limit"`
+	// PeerSnapshotSendRateLimit is the number of bytes per second of all the
+	// snapshots sent to the peers catching up. 0 disables the limit.
+	PeerSnapshotSendRateLimit int64 `json:"peer-snapshot-send-rate-limit"`
+	// PeerAddressRefreshInterval is the interval at which the host names of
+	// the peer URLs are re-resolved, so that the connections with the peers
+	// rescheduled to new addresses are re-established. The host names of the
+	// unreachable peers are re-resolved more often. 0 disables the
+	// re-resolution.
+	PeerAddressRefreshInterval time.Duration `json:"peer-address-refresh-interval"`
+	// PeerNetworkFaults are the latencies, jitters and drop rates injected
+	// in the raft messages sent to the peers, given as
+	// '<peer-url>=<latency>/<jitter>/<drop-rate>', so that the behavior of a
+	// cluster under asymmetric network faults can be reproduced. They are for
+	// testing only. The same PeerNetworkFaultsSeed injects the same faults in
+	// the same messages.
+	PeerNetworkFaults     []string `json:"peer-network-faults"`
+	PeerNetworkFaultsSeed int64    `json:"peer-network-faults-seed"`
+
 	// MaxSnapFiles is the maximum number of snapshot files.
 	// TODO: remove it in 3.7.
 	// Deprecated: Will be removed in v3.7.
 	MaxSnapFiles uint `json:"max-snapshots"`
 	//revive:disable-next-line:var-naming
 	MaxWalFiles uint `json:"max-wals"`
+	// WALCompression is the compression of the entries saved to the WAL,
+	// one of "none", "snappy" and "zstd". The files written before keep
+	// their compression.
+	WALCompression string `json:"wal-compression"`
 
 	// TickMs is the number of milliseconds between heartbeat ticks.
 	// TODO: decouple tickMs and heartbeat tick (current heartbeat tick = 1).
@@ -221,16 +309,119 @@ type Config struct {
 	BackendBatchInterval time.Duration `json:"backend-batch-interval"`
 	// BackendBatchLimit is the maximum operations before commit the backend transaction.
 	BackendBatchLimit int `json:"backend-batch-limit"`
+	// BackendBatchAdaptive adapts the backend batch interval to the commit latency, and
+	// the batch limit to the write rate, starting from BackendBatchInterval and
+	// BackendBatchLimit, within the bounds below.
+	BackendBatchAdaptive    bool          `json:"backend-batch-adaptive"`
+	BackendBatchIntervalMin time.Duration `json:"backend-batch-interval-min"`
+	BackendBatchIntervalMax time.Duration `json:"backend-batch-interval-max"`
+	BackendBatchLimitMin    int           `json:"backend-batch-limit-min"`
+	BackendBatchLimitMax    int           `json:"backend-batch-limit-max"`
+	// BackendReclaimFreePages punches holes in the free pages of the backend
+	// file in the background, every BackendReclaimInterval, once at least
+	// BackendReclaimThreshold of the file is free, so that their space is
+	// returned to the filesystem without a defragmentation. Linux only.
+	BackendReclaimFreePages bool          `json:"backend-reclaim-free-pages"`
+	BackendReclaimThreshold float64       `json:"backend-reclaim-threshold"`
+	BackendReclaimInterval  time.Duration `json:"backend-reclaim-interval"`
+	// ExperimentalStorageEngine is the storage engine of a new backend, one of
+	// "bbolt" and "log". An existing backend keeps the engine it was created
+	// with.
+	ExperimentalStorageEngine string `json:"experimental-storage-engine"`
 	// BackendFreelistType specifies the type of freelist that boltdb backend uses (array and map are supported types).
 	BackendFreelistType string `json:"backend-bbolt-freelist-type"`
 	QuotaBackendBytes   int64  `json:"quota-backend-bytes"`
 	MaxTxnOps           uint   `json:"max-txn-ops"`
 	MaxRequestBytes     uint   `json:"max-request-bytes"`
+	// QuotaBackendGrace checks the quota against the size of the backend in
+	// use rather than its physical size, and rejects the writes exceeding it
+	// without raising the NOSPACE alarm. The deletes and the compactions are
+	// still admitted, and once they make room, writes are admitted again
+	// without a defragmentation or an alarm to disarm.
+	QuotaBackendGrace bool `json:"quota-backend-grace"`
+	// QuotaBackendWarningThresholds are the fractions of the quota, between 0
+	// and 1, above which the size of the backend is logged as a warning and
+	// reported by the etcd_server_quota_backend_warning_threshold metric.
+	QuotaBackendWarningThresholds []float64 `json:"quota-backend-warning-thresholds"`
+
+	// KeyQuotas bound the bytes and the number of the keys of key prefixes,
+	// of namespaces, or of the key ranges auth roles are permitted to write,
+	// given as '<prefix|namespace|role>:<name>=<max-bytes>/<max-keys>'. Like
+	// the backend quota, they are checked by the member serving a write
+	// before proposing it.
+	KeyQuotas []string `json:"key-quotas"`
+
+	// Namespaces are the namespaces a client request may name in its
+	// metadata to isolate its keys under the key prefix
+	// NamespacesKeyPrefix + '<namespace>/'. The auth permissions and the key
+	// quotas of a namespace are those of its key prefix. They must be the
+	// same on all members.
+	Namespaces          []string `json:"namespaces"`
+	NamespacesKeyPrefix string   `json:"namespaces-key-prefix"`
 
 	// MaxConcurrentStreams specifies the maximum number of concurrent
 	// streams that each client can open at a time.
 	MaxConcurrentStreams uint32 `json:"max-concurrent-streams"`
 
+	// RequestDeadlineMargin rejects a unary request with DeadlineExceeded
+	// before doing any work if less than this much time is left before its
+	// deadline. 0 disables the check.
+	RequestDeadlineMargin time.Duration `json:"request-deadline-margin"`
+
+	// MaxConcurrentExpensiveRanges bounds the number of expensive ranges
+	// read at a time, the others waiting for their turn, so that large
+	// ranges do not hold the backend read transactions long enough to
+	// degrade the commit latency. 0 is no limit.
+	MaxConcurrentExpensiveRanges int `json:"max-concurrent-expensive-ranges"`
+	// ExpensiveRangeLimit is the limit above which a range over multiple
+	// keys is expensive. The ranges over multiple keys without a limit are
+	// expensive, the ranges returning only the count are not.
+	ExpensiveRangeLimit int64 `json:"expensive-range-limit"`
+
+	// MaxCallerLabels is the maximum number of distinct caller labels that
+	// get their own series in the per-caller request metrics.
+	MaxCallerLabels int `json:"max-caller-labels"`
+
+	// ClientIdentityMetrics labels the request metrics and the slow request
+	// logs with the client identity, its auth user or else the common name of
+	// its certificate. The metrics track up to MaxCallerLabels identities.
+	ClientIdentityMetrics bool `json:"client-identity-metrics"`
+
+	// ClientRateLimitQPS is the number of requests per second each client
+	// identity, its auth user or else the common name of its certificate,
+	// may send, in bursts of up to ClientRateLimitBurst requests.
+	// ClientRateLimitBytes is the number of request and response bytes per
+	// second of each client identity. The requests over a limit are
+	// rejected with ResourceExhausted. 0 disables a limit.
+	ClientRateLimitQPS   float64 `json:"client-rate-limit-qps"`
+	ClientRateLimitBurst int     `json:"client-rate-limit-burst"`
+	ClientRateLimitBytes int64   `json:"client-rate-limit-bytes"`
+
+	// QoSMaxInflightRequests is the number of unary client requests served
+	// at a time. The requests over it wait in a queue per QoS class, the
+	// priority in their metadata, and the classes are served by weighted
+	// round robin: critical, normal and background in 8:4:1 shares. 0
+	// disables the QoS scheduling.
+	QoSMaxInflightRequests int `json:"qos-max-inflight-requests"`
+
+	// AdmissionWebhookURL is the HTTPS URL of a webhook reviewing the puts,
+	// deletes and txns with writes before they are committed. The webhook
+	// may reject a request or annotate its audit record. Empty disables
+	// admission review.
+	AdmissionWebhookURL string `json:"admission-webhook-url"`
+	// AdmissionWebhookCAFile verifies the certificate of the webhook,
+	// instead of the system roots.
+	AdmissionWebhookCAFile string `json:"admission-webhook-ca-file"`
+	// AdmissionWebhookCertFile and AdmissionWebhookKeyFile are the client
+	// certificate authenticating etcd to the webhook.
+	AdmissionWebhookCertFile string `json:"admission-webhook-cert-file"`
+	AdmissionWebhookKeyFile  string `json:"admission-webhook-key-file"`
+	// AdmissionWebhookTimeout bounds each review.
+	AdmissionWebhookTimeout time.Duration `json:"admission-webhook-timeout"`
+	// AdmissionWebhookFailurePolicy is "fail" to reject, or "ignore" to
+	// admit, the requests the webhook failed to review.
+	AdmissionWebhookFailurePolicy string `json:"admission-webhook-failure-policy"`
+
 	//revive:disable:var-naming
 	ListenPeerUrls, ListenClientUrls, ListenClientHttpUrls []url.URL
 	AdvertisePeerUrls, AdvertiseClientUrls                 []url.URL
@@ -241,6 +432,10 @@ type Config struct {
 	PeerTLSInfo   transport.TLSInfo
 	PeerAutoTLS   bool
 
+	// ClientListenerGroups are client listeners served with their own TLS
+	// configuration and allowed RPCs, in addition to ListenClientUrls.
+	ClientListenerGroups []ClientListenerGroup `json:"client-listener-groups"`
+
 	// SelfSignedCertValidity specifies the validity period of the client and peer certificates
 	// that are automatically generated by etcd when you specify ClientAutoTLS and PeerAutoTLS,
 	// the unit is year, and the default is 1
@@ -259,6 +454,38 @@ type Config struct {
 	//revive:disable-next-line:var-naming
 	TlsMaxVersion string `json:"tls-max-version"`
 
+	// TLSReloadInterval is the interval between two checks of the renewal of
+	// the client and peer certificate files. When they are renewed, the
+	// established connections are closed for the clients and peers to
+	// reconnect with the renewed certificates. 0 disables the checks.
+	TLSReloadInterval time.Duration `json:"tls-reload-interval"`
+	// TLSReloadRecycleWindow is the duration over which the established
+	// client connections are closed once the certificates are renewed.
+	TLSReloadRecycleWindow time.Duration `json:"tls-reload-recycle-window"`
+
+	// ClientTLSMinVersion, ClientTLSMaxVersion and ClientCipherSuites
+	// override TlsMinVersion, TlsMaxVersion and CipherSuites for the client
+	// listeners, including the client listener groups. Empty values use the
+	// global ones.
+	ClientTLSMinVersion string   `json:"client-tls-min-version"`
+	ClientTLSMaxVersion string   `json:"client-tls-max-version"`
+	ClientCipherSuites  []string `json:"client-cipher-suites"`
+
+	// PeerTLSMinVersion, PeerTLSMaxVersion and PeerCipherSuites override
+	// TlsMinVersion, TlsMaxVersion and CipherSuites for the peer listeners and
+	// the connections to the peers. Empty values use the global ones.
+	PeerTLSMinVersion string   `json:"peer-tls-min-version"`
+	PeerTLSMaxVersion string   `json:"peer-tls-max-version"`
+	PeerCipherSuites  []string `json:"peer-cipher-suites"`
+
+	// MetricsTLSMinVersion, MetricsTLSMaxVersion and MetricsCipherSuites
+	// override the TLS versions and cipher suites of the client listeners for
+	// the https and unixs metrics listeners. Empty values use the ones of the
+	// client listeners.
+	MetricsTLSMinVersion string   `json:"metrics-tls-min-version"`
+	MetricsTLSMaxVersion string   `json:"metrics-tls-max-version"`
+	MetricsCipherSuites  []string `json:"metrics-cipher-suites"`
+
 	ClusterState          string `json:"initial-cluster-state"`
 	DNSCluster            string `json:"discovery-srv"`
 	DNSClusterServiceName string `json:"discovery-srv-name"`
@@ -266,18 +493,26 @@ type Config struct {
 
 	Durl         string                      `json:"discovery"`
 	DiscoveryCfg v3discovery.DiscoveryConfig `json:"discovery-config"`
+	// K8sDiscoveryCfg bootstraps the initial cluster from the pods listed by
+	// the Kubernetes API if its LabelSelector is set.
+	K8sDiscoveryCfg k8sdiscovery.Config `json:"discovery-k8s"`
 
 	InitialCluster      string `json:"initial-cluster"`
 	InitialClusterToken string `json:"initial-cluster-token"`
 	StrictReconfigCheck bool   `json:"strict-reconfig-check"`
 
-	// AutoCompactionMode is either 'periodic' or 'revision'.
+	// AutoCompactionMode is either 'periodic', 'revision' or 'size'.
 	AutoCompactionMode string `json:"auto-compaction-mode"`
 	// AutoCompactionRetention is either duration string with time unit
-	// (e.g. '5m' for 5-minute), or revision unit (e.g. '5000').
+	// (e.g. '5m' for 5-minute), revision unit (e.g. '5000'), or size
+	// in bytes with an optional unit (e.g. '1GB') for the 'size' mode.
 	// If no time unit is provided and compaction mode is 'periodic',
 	// the unit defaults to hour. For example, '5' translates into 5-hour.
 	AutoCompactionRetention string `json:"auto-compaction-retention"`
+	// CompactionControlKey is a key whose value, when written, is the
+	// revision the leader compacts the key-value store to. Empty disables
+	// compaction through the control key.
+	CompactionControlKey string `json:"compaction-control-key"`
 
 	// GRPCKeepAliveMinTime is the minimum interval that a client should
 	// wait before pinging server. When client pings "too fast", server
@@ -304,6 +539,22 @@ type Config struct {
 	// SocketOpts are socket options passed to listener config.
 	SocketOpts transport.SocketOpts `json:"socket-options"`
 
+	// ClientProxyProtocol reads the PROXY protocol header sent by the L4 load
+	// balancers at the start of the client connections, so that the client
+	// addresses are the addresses of the clients rather than of the load
+	// balancers. The header is only read from the connections of
+	// ClientProxyProtocolTrustedCIDRs, which are rejected without one.
+	ClientProxyProtocol bool `json:"client-proxy-protocol"`
+	// ClientProxyProtocolTrustedCIDRs are the networks of the load balancers
+	// sending the PROXY protocol header to the client listeners.
+	ClientProxyProtocolTrustedCIDRs []string `json:"client-proxy-protocol-trusted-cidrs"`
+	// PeerProxyProtocol reads the PROXY protocol header at the start of the
+	// peer connections.
+	PeerProxyProtocol bool `json:"peer-proxy-protocol"`
+	// PeerProxyProtocolTrustedCIDRs are the networks of the load balancers
+	// sending the PROXY protocol header to the peer listeners.
+	PeerProxyProtocolTrustedCIDRs []string `json:"peer-proxy-protocol-trusted-cidrs"`
+
 	// PreVote is true to enable Raft Pre-Vote.
 	// If enabled, Raft runs an additional election phase
 	// to check whether it would get enough votes to win
@@ -358,34 +609,262 @@ type Config struct {
 	// AuthTokenTTL in seconds of the simple token
 	AuthTokenTTL uint `json:"auth-token-ttl"`
 
+	// ClientCertRoleRules grant roles to the clients authenticated by their
+	// certificate, given as '<cn|ou|san>:<pattern>=<role>'.
+	ClientCertRoleRules []string `json:"client-cert-role-rules"`
+
+	// AuthLockoutUserFailures is the number of consecutive authentication
+	// failures locking out a user. 0 disables the lockout of users.
+	AuthLockoutUserFailures int `json:"auth-lockout-user-failures"`
+	// AuthLockoutSourceFailures is the number of consecutive authentication
+	// failures locking out a client address. 0 disables the lockout of addresses.
+	AuthLockoutSourceFailures int `json:"auth-lockout-source-failures"`
+	// AuthLockoutDuration is the duration of a first lockout, doubled on each
+	// further failure up to AuthLockoutMaxDuration.
+	AuthLockoutDuration    time.Duration `json:"auth-lockout-duration"`
+	AuthLockoutMaxDuration time.Duration `json:"auth-lockout-max-duration"`
+
+	// AuthPasswordMaxAge is the duration a password is valid for after it is
+	// set. 0 disables the expiry of passwords.
+	AuthPasswordMaxAge time.Duration `json:"auth-password-max-age"`
+	// AuthPasswordGracePeriod is the duration an expired password still
+	// authenticates its user for, so that the user can change it.
+	AuthPasswordGracePeriod time.Duration `json:"auth-password-grace-period"`
+
 	// CorruptCheckTime is the duration of time between cluster corruption check passes.
 	CorruptCheckTime time.Duration `json:"corrupt-check-time"`
 
 	// CompactHashCheckTime is the duration of time between leader checks followers compaction hashes.
 	CompactHashCheckTime time.Duration `json:"compact-hash-check-time"`
+	// CompactHashCheckQuarantine quarantines the members whose compaction
+	// hash diverged from the majority, instead of raising a cluster-wide
+	// corruption alarm: they reject client requests until the alarm is
+	// disarmed.
+	CompactHashCheckQuarantine bool `json:"compact-hash-check-quarantine"`
 	// CompactionBatchLimit Sets the maximum revisions deleted in each compaction batch.
 	CompactionBatchLimit int `json:"compaction-batch-limit"`
 	// CompactionSleepInterval is the sleep interval between every etcd compaction loop.
 	CompactionSleepInterval time.Duration `json:"compaction-sleep-interval"`
+	// CompactionTargetCommitLatency is the latency of the compaction batch commits
+	// above which the sleep between the batches grows from CompactionSleepInterval,
+	// so that compaction backs off while the disk is saturated. 0 disables it.
+	CompactionTargetCommitLatency time.Duration `json:"compaction-target-commit-latency"`
+	// CompactionWorkers is the number of workers compacting concurrently while the
+	// server serves no foreground requests.
+	CompactionWorkers int `json:"compaction-workers"`
+	// SlowWatcherMaxBacklog is the number of revisions a watcher failing to
+	// receive events may fall behind before SlowWatcherPolicy is applied.
+	// 0 disables the limit.
+	SlowWatcherMaxBacklog int64 `json:"slow-watcher-max-backlog"`
+	// SlowWatcherPolicy is the mitigation applied to watchers exceeding
+	// SlowWatcherMaxBacklog: "throttle", "compact" or "cancel".
+	SlowWatcherPolicy string `json:"slow-watcher-policy"`
+	// MaxWatchStreams is the maximum number of watch streams served; the
+	// streams opened past it are rejected. 0 disables the limit.
+	MaxWatchStreams int `json:"max-watch-streams"`
+	// MaxWatchersPerStream is the maximum number of watchers on a watch
+	// stream; the watchers created past it are canceled. 0 disables the limit.
+	MaxWatchersPerStream int `json:"max-watchers-per-stream"`
 	// WatchProgressNotifyInterval is the time duration of periodic watch progress notifications.
 	WatchProgressNotifyInterval time.Duration `json:"watch-progress-notify-interval"`
 	// WarningApplyDuration is the time duration after which a warning is generated if applying request
 	WarningApplyDuration time.Duration `json:"warning-apply-duration"`
+	// ApplyBacklogAlertThreshold is the number of committed but not yet applied
+	// entries above which the apply backlog is counted as dangerously backed up.
+	// 0 disables the alert.
+	ApplyBacklogAlertThreshold uint64 `json:"apply-backlog-alert-threshold"`
+	// ApplyBacklogBackpressureThreshold is the number of committed but not yet
+	// applied entries above which new proposals wait for the backlog to drain
+	// back to it. 0 disables the backpressure.
+	ApplyBacklogBackpressureThreshold uint64 `json:"apply-backlog-backpressure-threshold"`
+	// KeyAccessSampleRate is the fraction of range requests, between 0 and 1,
+	// whose keys get their last access time recorded. 0 disables the tracking.
+	KeyAccessSampleRate float64 `json:"key-access-sample-rate"`
+	// BackupURL is the URL of the sink the leader continuously backs up to,
+	// e.g. file:///var/backup/etcd. Empty disables the backup.
+	BackupURL string `json:"backup-url"`
+	// BackupInterval is the interval between two shipments of the committed
+	// raft entries to the backup sink, which bounds the data loss of a
+	// point in time restore.
+	BackupInterval time.Duration `json:"backup-interval"`
+	// BackupSnapshotInterval is the interval between two base snapshots
+	// shipped to the backup sink.
+	BackupSnapshotInterval time.Duration `json:"backup-snapshot-interval"`
+	// BackupRetention is the number of base snapshots kept in the backup
+	// sink, with the entries following them. 0 keeps all of them.
+	BackupRetention int `json:"backup-retention"`
+	// CDCSinkURLs are the URLs of the sinks the leader publishes the
+	// key-value events to, with at-least-once delivery, e.g.
+	// https://cdc.example.com/events. More sink schemes can be registered
+	// with v3cdc.RegisterSink. Empty disables the change data capture.
+	CDCSinkURLs []string `json:"cdc-sink-urls"`
+	// CDCCheckpointKeyPrefix is the prefix of the keys checkpointing the
+	// events published to each CDC sink.
+	CDCCheckpointKeyPrefix string `json:"cdc-checkpoint-key-prefix"`
+	// MirrorTargetEndpoints are the endpoints of the cluster the leader
+	// mirrors the keys to, as etcdctl make-mirror does. Empty disables the
+	// mirroring.
+	MirrorTargetEndpoints     []string `json:"mirror-target-endpoints"`
+	MirrorTargetCertFile      string   `json:"mirror-target-cert-file"`
+	MirrorTargetKeyFile       string   `json:"mirror-target-key-file"`
+	MirrorTargetTrustedCAFile string   `json:"mirror-target-trusted-ca-file"`
+	// MirrorPrefix is the prefix of the keys mirrored, all the keys if empty.
+	MirrorPrefix string `json:"mirror-prefix"`
+	// MirrorDestPrefix replaces MirrorPrefix in the keys of the target
+	// cluster. It defaults to MirrorPrefix unless MirrorNoDestPrefix is set.
+	MirrorDestPrefix   string `json:"mirror-dest-prefix"`
+	MirrorNoDestPrefix bool   `json:"mirror-no-dest-prefix"`
+	// MirrorCheckpointKey is the key of the target cluster holding the
+	// revision up to which the keys were mirrored.
+	MirrorCheckpointKey string `json:"mirror-checkpoint-key"`
+	// MirrorMaxTxnOps bounds the operations of a txn to the target cluster.
+	MirrorMaxTxnOps int `json:"mirror-max-txn-ops"`
+	// AutoSnapshotInterval is the interval between two snapshots of the
+	// backend saved to AutoSnapshotDir. 0 disables the auto snapshots.
+	AutoSnapshotInterval time.Duration `json:"auto-snapshot-interval"`
+	// AutoSnapshotDir is the directory of the auto snapshots, which can be
+	// restored like the snapshots saved by "etcdctl snapshot save".
+	AutoSnapshotDir string `json:"auto-snapshot-dir"`
+	// AutoSnapshotRetention is the number of auto snapshots of the member
+	// kept in AutoSnapshotDir. 0 keeps all of them.
+	AutoSnapshotRetention uint `json:"auto-snapshot-retention"`
+	// AutoSnapshotLeaderOnly saves the auto snapshots only while the member
+	// is the leader, instead of on every member.
+	AutoSnapshotLeaderOnly bool `json:"auto-snapshot-leader-only"`
+	// AutoDefragSchedule is the 5-field cron expression, in the local time of
+	// the member, of the times it defragments its backend. Empty disables the
+	// auto defragmentation.
+	AutoDefragSchedule string `json:"auto-defrag-schedule"`
+	// AutoDefragRatio is the minimum ratio of the backend size not in use for
+	// the auto defragmentation to run.
+	AutoDefragRatio float64 `json:"auto-defrag-ratio"`
+	// AutoDefragTransferLeadership moves the leadership to another member
+	// before the auto defragmentation of the leader, which skips it otherwise.
+	AutoDefragTransferLeadership bool `json:"auto-defrag-transfer-leadership"`
+	// AutoDefragLockKey is the key locked with a lease by the member running
+	// the auto defragmentation, so that the members defragment one at a time.
+	AutoDefragLockKey string `json:"auto-defrag-lock-key"`
+	// LeaseStewardPrefixes are the lock and election key prefixes (e.g. the
+	// prefixes of the clientv3 concurrency mutexes) the leader looks for
+	// keys without a live lease in: their holders are gone without deleting
+	// them, and they block the other candidates forever. Empty disables the
+	// lease steward.
+	LeaseStewardPrefixes []string `json:"lease-steward-prefixes"`
+	// LeaseStewardInterval is the interval between two scans of the
+	// LeaseStewardPrefixes.
+	LeaseStewardInterval time.Duration `json:"lease-steward-interval"`
+	// LeaseStewardDelete deletes the keys found without a live lease by two
+	// scans in a row, instead of only reporting them.
+	LeaseStewardDelete bool `json:"lease-steward-delete"`
+	// LearnerAutoPromoteDuration is the time a learner has to stay caught up
+	// with the leader before the leader promotes it to a voting member. 0
+	// disables the auto promotion.
+	LearnerAutoPromoteDuration time.Duration `json:"learner-auto-promote-duration"`
+	// LearnerAutoPromoteMaxLag is the maximum number of raft entries a
+	// learner caught up with the leader lags behind it.
+	LearnerAutoPromoteMaxLag uint64 `json:"learner-auto-promote-max-lag"`
+	// FollowerLagAlarmThreshold is the number of raft entries a voting
+	// follower may lag behind the leader before the leader raises a
+	// FOLLOWER_LAG alarm for it. 0 disables the alarm.
+	FollowerLagAlarmThreshold uint64 `json:"follower-lag-alarm-threshold"`
+	// FollowerLagAlarmDuration is the time a follower has to stay behind
+	// FollowerLagAlarmThreshold before the alarm is raised. The alarm is
+	// cleared once the follower caught up again.
+	FollowerLagAlarmDuration time.Duration `json:"follower-lag-alarm-duration"`
+	// LeaderLeaseReads lets the leader serve linearizable reads from its
+	// commit index while it holds the leader lease, an election timeout
+	// after a quorum last confirmed its leadership, instead of confirming it
+	// with a ReadIndex round trip for each batch of reads. It relies on the
+	// clocks of the members drifting by less than LeaderLeaseClockDrift over
+	// an election timeout.
+	LeaderLeaseReads bool `json:"leader-lease-reads"`
+	// LeaderLeaseClockDrift is the maximum clock drift between the members
+	// over an election timeout, by which the leader lease is shortened.
+	LeaderLeaseClockDrift time.Duration `json:"leader-lease-clock-drift"`
+	// Witness runs the member as a witness: it votes and keeps the raft log
+	// like any voting member, but stores no keys, serves no client requests
+	// and hands the leadership over to another member whenever elected. The
+	// witness is recorded in the membership of the cluster, so the member
+	// must always be started as a witness afterwards.
+	Witness bool `json:"witness"`
+	// EncryptionKEKFile is the file of the key encryption keys wrapping the
+	// data encryption keys of the backend, one "<id>:<base64 key>" line per
+	// key, the current key first. Setting it encrypts the backend and the
+	// WAL at rest.
+	EncryptionKEKFile string `json:"encryption-kek-file"`
+	// EncryptionKMSURL is the URL of a KMS webhook wrapping the data
+	// encryption keys of the backend and the WAL. Setting it encrypts the
+	// backend and the WAL at rest.
+	EncryptionKMSURL string `json:"encryption-kms-url"`
 	// BootstrapDefragThresholdMegabytes is the minimum number of megabytes needed to be freed for etcd server to
 	BootstrapDefragThresholdMegabytes uint `json:"bootstrap-defrag-threshold-megabytes"`
 	// WarningUnaryRequestDuration is the time duration after which a warning is generated if applying
 	// unary request takes more time than this value.
 	WarningUnaryRequestDuration time.Duration `json:"warning-unary-request-duration"`
+	// LogSlowRequestsAbove logs every unary request slower than it with the
+	// breakdown of its latency into queue wait, raft, apply and backend.
+	// 0 disables the logging.
+	LogSlowRequestsAbove time.Duration `json:"log-slow-requests-above"`
+	// LogSlowRequestsSampleInitial is the number of slow requests logged each
+	// second before sampling by LogSlowRequestsSampleThereafter kicks in.
+	LogSlowRequestsSampleInitial int `json:"log-slow-requests-sample-initial"`
+	// LogSlowRequestsSampleThereafter logs only every Nth of the remaining
+	// slow requests of each second. 0 drops them all.
+	LogSlowRequestsSampleThereafter int `json:"log-slow-requests-sample-thereafter"`
+	// EnableLeaderChangeEvents emits a structured log event with the old leader,
+	// the new leader and the term on every leadership change.
+	EnableLeaderChangeEvents bool `json:"enable-leader-change-events"`
+	// LeaderChangeEventKey is the key the newly elected leader writes the leader
+	// change event to, so that clients can watch for leadership changes.
+	LeaderChangeEventKey string `json:"leader-change-event-key"`
 	// MaxLearners sets a limit to the number of learner members that can exist in the cluster membership.
 	MaxLearners int `json:"max-learners"`
 
 	// ForceNewCluster starts a new cluster even if previously started; unsafe.
 	ForceNewCluster bool `json:"force-new-cluster"`
 
+	// EnableConfigReload enables the reload of the configuration file with a
+	// POST request to the client URL + "/config/reload". See ReloadConfig for
+	// the settings that are reloaded.
+	EnableConfigReload bool `json:"enable-config-reload"`
+
+	// EnableFailpointsEndpoint enables the failpoints at the client URL +
+	// "/debug/failpoints", for root users when auth is enabled. It requires a
+	// binary built with the failpoints tag.
+	EnableFailpointsEndpoint bool `json:"enable-failpoints-endpoint"`
+
+	// EnableLogEndpoint enables the change of the log level, of the levels of
+	// the raft, mvcc and auth subsystems and of the log sampling at runtime at
+	// the client URL + "/debug/log", for root users when auth is enabled.
+	EnableLogEndpoint bool `json:"enable-log-endpoint"`
+
+	// EnableSocketActivation serves the listening sockets passed by systemd
+	// socket activation (LISTEN_FDS) for the peer, client and metrics URLs
+	// they are bound to, instead of listening on these URLs again.
+	EnableSocketActivation bool `json:"enable-socket-activation"`
+
 	EnablePprof           bool   `json:"enable-pprof"`
 	Metrics               string `json:"metrics"`
 	ListenMetricsUrls     []url.URL
 	ListenMetricsUrlsJSON string `json:"listen-metrics-urls"`
+	// MetricsDenylist is a list of metric name prefixes that are not exposed
+	// on /metrics, e.g. to suppress high-cardinality histograms.
+	MetricsDenylist []string `json:"metrics-denylist"`
+	// MetricsKeyPrefixes are the key prefixes whose number of keys and total
+	// size of values are exported as gauges, kept up to date as the writes
+	// are applied.
+	MetricsKeyPrefixes []string `json:"metrics-key-prefixes"`
+	// GRPCHistogramBuckets are the bucket boundaries in seconds of the gRPC
+	// handling time histogram enabled by Metrics "extensive". Empty means the
+	// prometheus default buckets.
+	GRPCHistogramBuckets []float64 `json:"grpc-histogram-buckets"`
+	// SerializableHealthCheck makes /health check the local member with a
+	// serializable read instead of a quorum read, unless the request sets the
+	// "serializable" query parameter.
+	SerializableHealthCheck bool `json:"serializable-health-check"`
+	// HealthCheckTimeout bounds the read done by /health, unless the request
+	// sets the "timeout" query parameter. 0 means the server request timeout.
+	HealthCheckTimeout time.Duration `json:"health-check-timeout"`
 
 	// EnableDistributedTracing indicates if tracing using OpenTelemetry is enabled.
 	EnableDistributedTracing bool `json:"enable-distributed-tracing"`
@@ -404,6 +883,17 @@ type Config struct {
 	// Defaults to 0.
 	DistributedTracingSamplingRatePerMillion int `json:"distributed-tracing-sampling-rate"`
 
+	// EnableOTLPMetrics indicates if the metrics exposed on /metrics are also
+	// pushed to an OpenTelemetry Collector. The service name and instance ID
+	// of distributed tracing identify the member.
+	EnableOTLPMetrics bool `json:"enable-otlp-metrics"`
+	// OTLPMetricsAddress is the address of the OpenTelemetry Collector.
+	// Can only be used if EnableOTLPMetrics is true.
+	OTLPMetricsAddress string `json:"otlp-metrics-address"`
+	// OTLPMetricsInterval is the interval between metrics pushes.
+	// Can only be used if EnableOTLPMetrics is true.
+	OTLPMetricsInterval time.Duration `json:"otlp-metrics-interval"`
+
 	// Logger is logger options: currently only supports "zap".
 	// "capnslog" is removed in v3.5.
 	Logger string `json:"logger"`
@@ -425,15 +915,41 @@ type Config struct {
 	// ZapLoggerBuilder is used to build the zap logger.
 	ZapLoggerBuilder func(*Config) error
 
+	// AuditLogOutputs enables audit logging of mutating requests. Each
+	// output is either "stderr", "stdout", "syslog" or a file path to
+	// append JSON audit records to. Empty disables audit logging.
+	AuditLogOutputs []string `json:"audit-log-outputs"`
+	// AuditLogRotationConfigJSON configures the rotation of AuditLogOutputs
+	// file targets, in the same format as LogRotationConfigJSON.
+	AuditLogRotationConfigJSON string `json:"audit-log-rotation-config-json"`
+
 	// logger logs server-side operations. The default is nil,
 	// and "setupLogging" must be called before starting server.
 	// Do not set logger directly.
 	loggerMu *sync.RWMutex
 	logger   *zap.Logger
+	// logLevel is the level of logger, unset if logger is built by a custom
+	// ZapLoggerBuilder.
+	logLevel zap.AtomicLevel
+	// logControl changes the settings of logger at runtime, unset if logger
+	// is built by a custom ZapLoggerBuilder.
+	logControl *logControl
+
+	// configFile is the path of the file the configuration was loaded from,
+	// empty if it was not loaded from a file.
+	configFile string
+	// listenFDs are the listeners inherited from systemd socket activation,
+	// set while StartEtcd creates the listeners.
+	listenFDs *listenFDs
 	// EnableGRPCGateway enables grpc gateway.
 	// The gateway translates a RESTful HTTP API into gRPC.
 	EnableGRPCGateway bool `json:"enable-grpc-gateway"`
 
+	// EnableGRPCReflection registers the gRPC server reflection service on the
+	// client gRPC server, letting tools like grpcurl list and describe the etcd
+	// RPCs. It is meant for development environments.
+	EnableGRPCReflection bool `json:"enable-grpc-reflection"`
+
 	// UnsafeNoFsync disables all uses of fsync.
 	// Setting this is unsafe and will cause data loss.
 	UnsafeNoFsync bool `json:"unsafe-no-fsync"`
@@ -495,6 +1011,9 @@ type securityConfig struct {
 	AllowedCNs          []string `json:"allowed-cn"`
 	AllowedHostnames    []string `json:"allowed-hostname"`
 	SkipClientSANVerify bool     `json:"skip-client-san-verification,omitempty"`
+
+	AllowedSPIFFETrustDomains []string `json:"allowed-spiffe-trust-domain"`
+	AllowedSPIFFEIDs          []string `json:"allowed-spiffe-id"`
 }
 
 // NewConfig creates a new Config populated with default values.
@@ -504,8 +1023,9 @@ func NewConfig() *Config {
 	lcurl, _ := url.Parse(DefaultListenClientURLs)
 	acurl, _ := url.Parse(DefaultAdvertiseClientURLs)
 	cfg := &Config{
-		MaxSnapFiles: DefaultMaxSnapshots,
-		MaxWalFiles:  DefaultMaxWALs,
+		MaxSnapFiles:   DefaultMaxSnapshots,
+		MaxWalFiles:    DefaultMaxWALs,
+		WALCompression: wal.CompressionNone.String(),
 
 		Name: DefaultName,
 
@@ -515,7 +1035,26 @@ func NewConfig() *Config {
 		MaxTxnOps:            DefaultMaxTxnOps,
 		MaxRequestBytes:      DefaultMaxRequestBytes,
 		MaxConcurrentStreams: DefaultMaxConcurrentStreams,
-		WarningApplyDuration: DefaultWarningApplyDuration,
+		ExpensiveRangeLimit:  DefaultExpensiveRangeLimit,
+		MaxCallerLabels:      DefaultMaxCallerLabels,
+
+		NamespacesKeyPrefix: DefaultNamespacesKeyPrefix,
+
+		AdmissionWebhookTimeout:       DefaultAdmissionWebhookTimeout,
+		AdmissionWebhookFailurePolicy: admission.FailurePolicyFail,
+		WarningApplyDuration:          DefaultWarningApplyDuration,
+
+		ExperimentalStorageEngine: backend.EngineBbolt,
+
+		BackendBatchIntervalMin: DefaultBackendBatchIntervalMin,
+		BackendBatchIntervalMax: DefaultBackendBatchIntervalMax,
+		BackendBatchLimitMin:    DefaultBackendBatchLimitMin,
+		BackendBatchLimitMax:    DefaultBackendBatchLimitMax,
+		BackendReclaimThreshold: DefaultBackendReclaimThreshold,
+		BackendReclaimInterval:  DefaultBackendReclaimInterval,
+
+		LogSlowRequestsSampleInitial:    DefaultLogSlowRequestsSampleInitial,
+		LogSlowRequestsSampleThereafter: DefaultLogSlowRequestsSampleThereafter,
 
 		GRPCKeepAliveMinTime:  DefaultGRPCKeepAliveMinTime,
 		GRPCKeepAliveInterval: DefaultGRPCKeepAliveInterval,
@@ -547,8 +1086,11 @@ func NewConfig() *Config {
 		AuthToken:              DefaultAuthToken,
 		BcryptCost:             uint(bcrypt.DefaultCost),
 		AuthTokenTTL:           300,
+		AuthLockoutDuration:    auth.DefaultLockoutDuration,
+		AuthLockoutMaxDuration: auth.DefaultLockoutMaxDuration,
 		SelfSignedCertValidity: DefaultSelfSignedCertValidity,
 		TlsMinVersion:          DefaultTLSMinVersion,
+		TLSReloadRecycleWindow: DefaultTLSReloadRecycleWindow,
 
 		PreVote: true,
 
@@ -560,7 +1102,10 @@ func NewConfig() *Config {
 		LogLevel:              logutil.DefaultLogLevel,
 		EnableLogRotation:     false,
 		LogRotationConfigJSON: DefaultLogRotationConfig,
-		EnableGRPCGateway:     true,
+
+		AuditLogRotationConfigJSON: DefaultLogRotationConfig,
+
+		EnableGRPCGateway: true,
 
 		DowngradeCheckTime: DefaultDowngradeCheckTime,
 		MemoryMlock:        false,
@@ -569,8 +1114,31 @@ func NewConfig() *Config {
 		DistributedTracingAddress:     DefaultDistributedTracingAddress,
 		DistributedTracingServiceName: DefaultDistributedTracingServiceName,
 
+		OTLPMetricsAddress:  DefaultOTLPMetricsAddress,
+		OTLPMetricsInterval: DefaultOTLPMetricsInterval,
+
 		CompactHashCheckTime: DefaultCompactHashCheckTime,
 
+		BackupInterval:         DefaultBackupInterval,
+		BackupSnapshotInterval: DefaultBackupSnapshotInterval,
+		BackupRetention:        DefaultBackupRetention,
+
+		CDCCheckpointKeyPrefix: DefaultCDCCheckpointKeyPrefix,
+
+		MirrorCheckpointKey: DefaultMirrorCheckpointKey,
+		MirrorMaxTxnOps:     DefaultMirrorMaxTxnOps,
+
+		AutoSnapshotRetention: DefaultAutoSnapshotRetention,
+
+		AutoDefragRatio:          DefaultAutoDefragRatio,
+		LearnerAutoPromoteMaxLag: DefaultLearnerAutoPromoteMaxLag,
+		FollowerLagAlarmDuration: DefaultFollowerLagAlarmDuration,
+		LeaderLeaseClockDrift:    DefaultLeaderLeaseClockDrift,
+		AutoDefragLockKey:        DefaultAutoDefragLockKey,
+		LeaseStewardInterval:     DefaultLeaseStewardInterval,
+
+		PeerAddressRefreshInterval: DefaultPeerAddressRefreshInterval,
+
 		V2Deprecation: config.V2DeprDefault,
 
 		DiscoveryCfg: v3discovery.DiscoveryConfig{
@@ -586,6 +1154,11 @@ func NewConfig() *Config {
 				Auth: &clientv3.AuthConfig{},
 			},
 		},
+		K8sDiscoveryCfg: k8sdiscovery.Config{
+			PeerPort:      k8sdiscovery.DefaultPeerPort,
+			ClusterDomain: k8sdiscovery.DefaultClusterDomain,
+			Timeout:       k8sdiscovery.DefaultTimeout,
+		},
 
 		AutoCompactionMode:      DefaultAutoCompactionMode,
 		AutoCompactionRetention: DefaultAutoCompactionRetention,
@@ -620,15 +1193,30 @@ func (cfg *Config) AddFlags(fs *flag.FlagSet) {
 	)
 	fs.UintVar(&cfg.MaxSnapFiles, "max-snapshots", cfg.MaxSnapFiles, "Maximum number of snapshot files to retain (0 is unlimited). Deprecated in v3.6 and will be decommissioned in v3.7.")
 	fs.UintVar(&cfg.MaxWalFiles, "max-wals", cfg.MaxWalFiles, "Maximum number of wal files to retain (0 is unlimited).")
+	fs.StringVar(&cfg.WALCompression, "wal-compression", cfg.WALCompression, "Compression of the entries saved to the WAL ('none', 'snappy' or 'zstd').")
 	fs.StringVar(&cfg.Name, "name", cfg.Name, "Human-readable name for this member.")
 	fs.Uint64Var(&cfg.SnapshotCount, "snapshot-count", cfg.SnapshotCount, "Number of committed transactions to trigger a snapshot to disk. Deprecated in v3.6 and will be decommissioned in v3.7.")
 	fs.UintVar(&cfg.TickMs, "heartbeat-interval", cfg.TickMs, "Time (in milliseconds) of a heartbeat interval.")
 	fs.UintVar(&cfg.ElectionMs, "election-timeout", cfg.ElectionMs, "Time (in milliseconds) for an election to timeout.")
 	fs.BoolVar(&cfg.InitialElectionTickAdvance, "initial-election-tick-advance", cfg.InitialElectionTickAdvance, "Whether to fast-forward initial election ticks on boot for faster election.")
 	fs.Int64Var(&cfg.QuotaBackendBytes, "quota-backend-bytes", cfg.QuotaBackendBytes, "Raise alarms when backend size exceeds the given quota. 0 means use the default quota.")
+	fs.BoolVar(&cfg.QuotaBackendGrace, "quota-backend-grace", cfg.QuotaBackendGrace, "Check the quota against the backend size in use, and reject the writes exceeding it without raising the NOSPACE alarm, so that compactions make room for writes again.")
+	fs.Var(flags.NewFloat64sValue(cfg.QuotaBackendWarningThresholds...), "quota-backend-warning-thresholds", "Comma-separated list of fractions of the quota above which the backend size is warned about.")
+	fs.Var(flags.NewStringsValue(""), "key-quotas", "Comma-separated list of quotas bounding the keys of a prefix, of a namespace or of the ranges a role may write, as '<prefix|namespace|role>:<name>=<max-bytes>/<max-keys>'.")
+	fs.Var(flags.NewStringsValue(""), "namespaces", "Comma-separated list of namespaces the client requests may isolate their keys in.")
+	fs.StringVar(&cfg.NamespacesKeyPrefix, "namespaces-key-prefix", cfg.NamespacesKeyPrefix, "Prefix of the keys of the namespaces, each isolated under '<prefix><namespace>/'.")
+	fs.StringVar(&cfg.ExperimentalStorageEngine, "experimental-storage-engine", cfg.ExperimentalStorageEngine, "Storage engine of a new backend ('bbolt' or 'log'). An existing backend keeps the engine it was created with.")
 	fs.StringVar(&cfg.BackendFreelistType, "backend-bbolt-freelist-type", cfg.BackendFreelistType, "BackendFreelistType specifies the type of freelist that boltdb backend uses(array and map are supported types)")
 	fs.DurationVar(&cfg.BackendBatchInterval, "backend-batch-interval", cfg.BackendBatchInterval, "BackendBatchInterval is the maximum time before commit the backend transaction.")
 	fs.IntVar(&cfg.BackendBatchLimit, "backend-batch-limit", cfg.BackendBatchLimit, "BackendBatchLimit is the maximum operations before commit the backend transaction.")
+	fs.BoolVar(&cfg.BackendBatchAdaptive, "backend-batch-adaptive", cfg.BackendBatchAdaptive, "Adapt the backend batch interval to the commit latency and the batch limit to the write rate, within their bounds.")
+	fs.DurationVar(&cfg.BackendBatchIntervalMin, "backend-batch-interval-min", cfg.BackendBatchIntervalMin, "Minimum backend batch interval adapted by --backend-batch-adaptive.")
+	fs.DurationVar(&cfg.BackendBatchIntervalMax, "backend-batch-interval-max", cfg.BackendBatchIntervalMax, "Maximum backend batch interval adapted by --backend-batch-adaptive.")
+	fs.IntVar(&cfg.BackendBatchLimitMin, "backend-batch-limit-min", cfg.BackendBatchLimitMin, "Minimum backend batch limit adapted by --backend-batch-adaptive.")
+	fs.IntVar(&cfg.BackendBatchLimitMax, "backend-batch-limit-max", cfg.BackendBatchLimitMax, "Maximum backend batch limit adapted by --backend-batch-adaptive.")
+	fs.BoolVar(&cfg.BackendReclaimFreePages, "backend-reclaim-free-pages", cfg.BackendReclaimFreePages, "Return the free pages of the backend to the filesystem in the background, without defragmentation (linux only).")
+	fs.Float64Var(&cfg.BackendReclaimThreshold, "backend-reclaim-threshold", cfg.BackendReclaimThreshold, "Fraction of the backend that must be free for --backend-reclaim-free-pages to reclaim its free pages.")
+	fs.DurationVar(&cfg.BackendReclaimInterval, "backend-reclaim-interval", cfg.BackendReclaimInterval, "Interval between the rounds of --backend-reclaim-free-pages.")
 	fs.UintVar(&cfg.MaxTxnOps, "max-txn-ops", cfg.MaxTxnOps, "Maximum number of operations permitted in a transaction.")
 	fs.UintVar(&cfg.MaxRequestBytes, "max-request-bytes", cfg.MaxRequestBytes, "Maximum client request size in bytes the server will accept.")
 	fs.DurationVar(&cfg.GRPCKeepAliveMinTime, "grpc-keepalive-min-time", cfg.GRPCKeepAliveMinTime, "Minimum interval duration that a client should wait before pinging server.")
@@ -636,8 +1224,27 @@ func (cfg *Config) AddFlags(fs *flag.FlagSet) {
 	fs.DurationVar(&cfg.GRPCKeepAliveTimeout, "grpc-keepalive-timeout", cfg.GRPCKeepAliveTimeout, "Additional duration of wait before closing a non-responsive connection (0 to disable).")
 	fs.BoolVar(&cfg.SocketOpts.ReusePort, "socket-reuse-port", cfg.SocketOpts.ReusePort, "Enable to set socket option SO_REUSEPORT on listeners allowing rebinding of a port already in use.")
 	fs.BoolVar(&cfg.SocketOpts.ReuseAddress, "socket-reuse-address", cfg.SocketOpts.ReuseAddress, "Enable to set socket option SO_REUSEADDR on listeners allowing binding to an address in `TIME_WAIT` state.")
+	fs.BoolVar(&cfg.ClientProxyProtocol, "client-proxy-protocol", false, "Read the PROXY protocol (v1 or v2) header at the start of the client connections from the trusted networks. Their connections without a header are rejected.")
+	fs.Var(flags.NewStringsValue(""), "client-proxy-protocol-trusted-cidrs", "Comma-separated list of the networks, in CIDR notation, the PROXY protocol header of the client connections is read from.")
+	fs.BoolVar(&cfg.PeerProxyProtocol, "peer-proxy-protocol", false, "Read the PROXY protocol (v1 or v2) header at the start of the peer connections from the trusted networks. Their connections without a header are rejected.")
+	fs.Var(flags.NewStringsValue(""), "peer-proxy-protocol-trusted-cidrs", "Comma-separated list of the networks, in CIDR notation, the PROXY protocol header of the peer connections is read from.")
 
 	fs.Var(flags.NewUint32Value(cfg.MaxConcurrentStreams), "max-concurrent-streams", "Maximum concurrent streams that each client can open at a time.")
+	fs.DurationVar(&cfg.RequestDeadlineMargin, "request-deadline-margin", cfg.RequestDeadlineMargin, "Minimum time left before the client deadline for the server to start serving a unary request (0 to disable).")
+	fs.IntVar(&cfg.MaxConcurrentExpensiveRanges, "max-concurrent-expensive-ranges", cfg.MaxConcurrentExpensiveRanges, "Maximum number of expensive ranges read at a time, the others waiting for their turn (0 for no limit).")
+	fs.Int64Var(&cfg.ExpensiveRangeLimit, "expensive-range-limit", cfg.ExpensiveRangeLimit, "Limit above which a range over multiple keys is expensive for --max-concurrent-expensive-ranges. Ranges over multiple keys without a limit are always expensive.")
+	fs.IntVar(&cfg.MaxCallerLabels, "max-caller-labels", cfg.MaxCallerLabels, "Maximum number of distinct client caller labels tracked in the per-caller request metrics.")
+	fs.BoolVar(&cfg.ClientIdentityMetrics, "client-identity-metrics", false, "Label the request metrics and the slow request logs with the client identity, its auth user or else its certificate common name. The metrics track up to '--max-caller-labels' identities.")
+	fs.Float64Var(&cfg.ClientRateLimitQPS, "client-rate-limit-qps", cfg.ClientRateLimitQPS, "Maximum number of requests per second of each client identity, its auth user or else its certificate common name (0 to disable).")
+	fs.IntVar(&cfg.ClientRateLimitBurst, "client-rate-limit-burst", cfg.ClientRateLimitBurst, "Maximum burst of requests of each client identity (0 defaults to --client-rate-limit-qps).")
+	fs.Int64Var(&cfg.ClientRateLimitBytes, "client-rate-limit-bytes", cfg.ClientRateLimitBytes, "Maximum number of request and response bytes per second of each client identity (0 to disable).")
+	fs.IntVar(&cfg.QoSMaxInflightRequests, "qos-max-inflight-requests", cfg.QoSMaxInflightRequests, "Maximum number of unary client requests served at a time, the others waiting in a queue per priority class (0 to disable).")
+	fs.StringVar(&cfg.AdmissionWebhookURL, "admission-webhook-url", cfg.AdmissionWebhookURL, "HTTPS URL of a webhook reviewing the puts, deletes and txns with writes before they are committed (empty to disable).")
+	fs.StringVar(&cfg.AdmissionWebhookCAFile, "admission-webhook-ca-file", cfg.AdmissionWebhookCAFile, "Path to the CA verifying the certificate of the admission webhook (empty to use the system roots).")
+	fs.StringVar(&cfg.AdmissionWebhookCertFile, "admission-webhook-cert-file", cfg.AdmissionWebhookCertFile, "Path to the client certificate authenticating etcd to the admission webhook.")
+	fs.StringVar(&cfg.AdmissionWebhookKeyFile, "admission-webhook-key-file", cfg.AdmissionWebhookKeyFile, "Path to the key of the client certificate authenticating etcd to the admission webhook.")
+	fs.DurationVar(&cfg.AdmissionWebhookTimeout, "admission-webhook-timeout", cfg.AdmissionWebhookTimeout, "Maximum duration of an admission webhook review.")
+	fs.StringVar(&cfg.AdmissionWebhookFailurePolicy, "admission-webhook-failure-policy", cfg.AdmissionWebhookFailurePolicy, "Whether to reject ('fail') or admit ('ignore') the requests the admission webhook failed to review.")
 
 	// raft connection timeouts
 	fs.DurationVar(&rafthttp.ConnReadTimeout, "raft-read-timeout", rafthttp.DefaultConnReadTimeout, "Read timeout set on each rafthttp connection")
@@ -679,6 +1286,12 @@ func (cfg *Config) AddFlags(fs *flag.FlagSet) {
 	fs.StringVar(&cfg.Dproxy, "discovery-proxy", cfg.Dproxy, "HTTP proxy to use for traffic to discovery service. Will be deprecated in v3.7, and be decommissioned in v3.8.")
 	fs.StringVar(&cfg.DNSCluster, "discovery-srv", cfg.DNSCluster, "DNS domain used to bootstrap initial cluster.")
 	fs.StringVar(&cfg.DNSClusterServiceName, "discovery-srv-name", cfg.DNSClusterServiceName, "Service name to query when using DNS discovery.")
+	fs.StringVar(&cfg.K8sDiscoveryCfg.LabelSelector, "discovery-k8s-label-selector", cfg.K8sDiscoveryCfg.LabelSelector, "Kubernetes discovery: label selector of the pods of the members used to bootstrap initial cluster.")
+	fs.StringVar(&cfg.K8sDiscoveryCfg.Namespace, "discovery-k8s-namespace", cfg.K8sDiscoveryCfg.Namespace, "Kubernetes discovery: namespace of the pods of the members, by default the namespace of the local pod.")
+	fs.IntVar(&cfg.K8sDiscoveryCfg.ClusterSize, "discovery-k8s-cluster-size", cfg.K8sDiscoveryCfg.ClusterSize, "Kubernetes discovery: number of members of the initial cluster.")
+	fs.IntVar(&cfg.K8sDiscoveryCfg.PeerPort, "discovery-k8s-peer-port", cfg.K8sDiscoveryCfg.PeerPort, "Kubernetes discovery: port of the peer URLs of the members.")
+	fs.StringVar(&cfg.K8sDiscoveryCfg.ClusterDomain, "discovery-k8s-cluster-domain", cfg.K8sDiscoveryCfg.ClusterDomain, "Kubernetes discovery: DNS domain of the Kubernetes cluster.")
+	fs.DurationVar(&cfg.K8sDiscoveryCfg.Timeout, "discovery-k8s-timeout", cfg.K8sDiscoveryCfg.Timeout, "Kubernetes discovery: maximum duration to wait for the pods of the initial cluster.")
 	fs.StringVar(&cfg.InitialCluster, "initial-cluster", cfg.InitialCluster, "Initial cluster configuration for bootstrapping.")
 	fs.StringVar(&cfg.InitialClusterToken, "initial-cluster-token", cfg.InitialClusterToken, "Initial cluster token for the etcd cluster during bootstrap.")
 	fs.BoolVar(&cfg.StrictReconfigCheck, "strict-reconfig-check", cfg.StrictReconfigCheck, "Reject reconfiguration requests that would cause quorum loss.")
@@ -692,7 +1305,12 @@ func (cfg *Config) AddFlags(fs *flag.FlagSet) {
 	fs.StringVar(&cfg.ClientTLSInfo.ClientKeyFile, "client-key-file", "", "Path to an explicit peer client TLS key file otherwise key file will be used when client auth is required.")
 	fs.BoolVar(&cfg.ClientTLSInfo.ClientCertAuth, "client-cert-auth", false, "Enable client cert authentication.")
 	fs.StringVar(&cfg.ClientTLSInfo.CRLFile, "client-crl-file", "", "Path to the client certificate revocation list file.")
+	fs.DurationVar(&cfg.ClientTLSInfo.CRLRefreshInterval, "client-crl-refresh-interval", 0, "Duration the client certificate revocation list file is cached for before it is read again. 0 reads it on each handshake.")
+	fs.StringVar(&cfg.ClientTLSInfo.OCSPCheck, "client-ocsp-check", "", "Check the client certificates with their OCSP responder: 'soft-fail' accepts them when the responder cannot be queried, 'hard-fail' rejects them. Empty disables the check.")
 	fs.Var(flags.NewStringsValue(""), "client-cert-allowed-hostname", "Comma-separated list of allowed SAN hostnames for client cert authentication.")
+	fs.Var(flags.NewStringsValue(""), "client-cert-allowed-spiffe-trust-domain", "Comma-separated list of allowed trust domains of the SPIFFE ID of client certs.")
+	fs.Var(flags.NewStringsValue(""), "client-cert-allowed-spiffe-id", "Comma-separated list of allowed SPIFFE IDs of client certs, as path.Match patterns.")
+	fs.Var(flags.NewStringsValue(""), "client-cert-role-rules", "Comma-separated list of rules granting roles to client certs, as '<cn|ou|san|spiffe>:<pattern>=<role>'.")
 	fs.StringVar(&cfg.ClientTLSInfo.TrustedCAFile, "trusted-ca-file", "", "Path to the client server TLS trusted CA cert file.")
 	fs.BoolVar(&cfg.ClientAutoTLS, "auto-tls", false, "Client TLS using generated certificates")
 	fs.StringVar(&cfg.PeerTLSInfo.CertFile, "peer-cert-file", "", "Path to the peer server TLS cert file.")
@@ -704,12 +1322,27 @@ func (cfg *Config) AddFlags(fs *flag.FlagSet) {
 	fs.BoolVar(&cfg.PeerAutoTLS, "peer-auto-tls", false, "Peer TLS using generated certificates")
 	fs.UintVar(&cfg.SelfSignedCertValidity, "self-signed-cert-validity", 1, "The validity period of the client and peer certificates, unit is year")
 	fs.StringVar(&cfg.PeerTLSInfo.CRLFile, "peer-crl-file", "", "Path to the peer certificate revocation list file.")
+	fs.DurationVar(&cfg.PeerTLSInfo.CRLRefreshInterval, "peer-crl-refresh-interval", 0, "Duration the peer certificate revocation list file is cached for before it is read again. 0 reads it on each handshake.")
+	fs.StringVar(&cfg.PeerTLSInfo.OCSPCheck, "peer-ocsp-check", "", "Check the peer certificates with their OCSP responder: 'soft-fail' accepts them when the responder cannot be queried, 'hard-fail' rejects them. Empty disables the check.")
 	fs.Var(flags.NewStringsValue(""), "peer-cert-allowed-cn", "Comma-separated list of allowed CNs for inter-peer TLS authentication.")
 	fs.Var(flags.NewStringsValue(""), "peer-cert-allowed-hostname", "Comma-separated list of allowed SAN hostnames for inter-peer TLS authentication.")
+	fs.Var(flags.NewStringsValue(""), "peer-cert-allowed-spiffe-trust-domain", "Comma-separated list of allowed trust domains of the SPIFFE ID of peer certs for inter-peer TLS authentication.")
+	fs.Var(flags.NewStringsValue(""), "peer-cert-allowed-spiffe-id", "Comma-separated list of allowed SPIFFE IDs of peer certs for inter-peer TLS authentication, as path.Match patterns.")
 	fs.Var(flags.NewStringsValue(""), "cipher-suites", "Comma-separated list of supported TLS cipher suites between client/server and peers (empty will be auto-populated by Go).")
 	fs.BoolVar(&cfg.PeerTLSInfo.SkipClientSANVerify, "peer-skip-client-san-verification", false, "Skip verification of SAN field in client certificate for peer connections.")
 	fs.StringVar(&cfg.TlsMinVersion, "tls-min-version", string(tlsutil.TLSVersion12), "Minimum TLS version supported by etcd. Possible values: TLS1.2, TLS1.3.")
 	fs.StringVar(&cfg.TlsMaxVersion, "tls-max-version", string(tlsutil.TLSVersionDefault), "Maximum TLS version supported by etcd. Possible values: TLS1.2, TLS1.3 (empty defers to Go).")
+	fs.DurationVar(&cfg.TLSReloadInterval, "tls-reload-interval", 0, "Interval between two checks of the renewal of the client and peer certificate files, to close the connections established with the former certificates. 0 disables the checks.")
+	fs.DurationVar(&cfg.TLSReloadRecycleWindow, "tls-reload-recycle-window", cfg.TLSReloadRecycleWindow, "Duration over which the established client connections are closed once the certificates are renewed.")
+	fs.StringVar(&cfg.ClientTLSMinVersion, "client-tls-min-version", "", "Minimum TLS version of the client listeners, overriding --tls-min-version.")
+	fs.StringVar(&cfg.ClientTLSMaxVersion, "client-tls-max-version", "", "Maximum TLS version of the client listeners, overriding --tls-max-version.")
+	fs.Var(flags.NewStringsValue(""), "client-cipher-suites", "Comma-separated list of TLS cipher suites of the client listeners, overriding --cipher-suites.")
+	fs.StringVar(&cfg.PeerTLSMinVersion, "peer-tls-min-version", "", "Minimum TLS version between peers, overriding --tls-min-version.")
+	fs.StringVar(&cfg.PeerTLSMaxVersion, "peer-tls-max-version", "", "Maximum TLS version between peers, overriding --tls-max-version.")
+	fs.Var(flags.NewStringsValue(""), "peer-cipher-suites", "Comma-separated list of TLS cipher suites between peers, overriding --cipher-suites.")
+	fs.StringVar(&cfg.MetricsTLSMinVersion, "metrics-tls-min-version", "", "Minimum TLS version of the metrics listeners, overriding the one of the client listeners.")
+	fs.StringVar(&cfg.MetricsTLSMaxVersion, "metrics-tls-max-version", "", "Maximum TLS version of the metrics listeners, overriding the one of the client listeners.")
+	fs.Var(flags.NewStringsValue(""), "metrics-cipher-suites", "Comma-separated list of TLS cipher suites of the metrics listeners, overriding the ones of the client listeners.")
 
 	fs.Var(
 		flags.NewUniqueURLsWithExceptions("*", "*"),
@@ -725,15 +1358,27 @@ func (cfg *Config) AddFlags(fs *flag.FlagSet) {
 	fs.StringVar(&cfg.LogFormat, "log-format", logutil.DefaultLogFormat, "Configures log format. Only supports json, console. Default is 'json'.")
 	fs.BoolVar(&cfg.EnableLogRotation, "enable-log-rotation", false, "Enable log rotation of a single log-outputs file target.")
 	fs.StringVar(&cfg.LogRotationConfigJSON, "log-rotation-config-json", DefaultLogRotationConfig, "Configures log rotation if enabled with a JSON logger config. Default: MaxSize=100(MB), MaxAge=0(days,no limit), MaxBackups=0(no limit), LocalTime=false(UTC), Compress=false(gzip)")
+	fs.Var(flags.NewStringsValue(strings.Join(cfg.AuditLogOutputs, ",")), "audit-log-outputs", "Comma-separated list of audit log targets for mutating requests: 'stderr', 'stdout', 'syslog' or a file path (empty disables audit logging).")
+	fs.StringVar(&cfg.AuditLogRotationConfigJSON, "audit-log-rotation-config-json", cfg.AuditLogRotationConfigJSON, "Configures rotation of audit log file targets with a JSON logger config, in the same format as '--log-rotation-config-json'.")
 
 	fs.StringVar(&cfg.AutoCompactionRetention, "auto-compaction-retention", "0", "Auto compaction retention for mvcc key value store. 0 means disable auto compaction.")
-	fs.StringVar(&cfg.AutoCompactionMode, "auto-compaction-mode", "periodic", "interpret 'auto-compaction-retention' one of: periodic|revision. 'periodic' for duration based retention, defaulting to hours if no time unit is provided (e.g. '5m'). 'revision' for revision number based retention.")
+	fs.StringVar(&cfg.AutoCompactionMode, "auto-compaction-mode", "periodic", "interpret 'auto-compaction-retention' one of: periodic|revision|size. 'periodic' for duration based retention, defaulting to hours if no time unit is provided (e.g. '5m'). 'revision' for revision number based retention. 'size' for compacting once the reclaimable history exceeds a size in bytes (e.g. '1GB').")
+	fs.StringVar(&cfg.CompactionControlKey, "compaction-control-key", cfg.CompactionControlKey, "Key whose value, when written, is the revision to compact the key-value store to (empty disables).")
 
 	// pprof profiler via HTTP
-	fs.BoolVar(&cfg.EnablePprof, "enable-pprof", false, "Enable runtime profiling data via HTTP server. Address is at client URL + \"/debug/pprof/\"")
+	fs.BoolVar(&cfg.EnablePprof, "enable-pprof", false, "Enable runtime profiling data via HTTP server. Address is at client URL + \"/debug/pprof/\", with the full goroutine profile at \"/debug/fgprof\" and the block and mutex profile rates at \"/debug/pprof/rates\".")
+	fs.BoolVar(&cfg.EnableSocketActivation, "enable-socket-activation", false, "Serve the listening sockets passed by systemd socket activation (LISTEN_FDS) for the peer, client and metrics URLs they are bound to.")
+	fs.BoolVar(&cfg.EnableConfigReload, "enable-config-reload", false, "Enable the reload of the configuration file via HTTP server. Address is at client URL + \"/config/reload\"")
+	fs.BoolVar(&cfg.EnableLogEndpoint, "enable-log-endpoint", false, "Enable the change of the log level, of the levels of the raft, mvcc and auth subsystems and of the log sampling at runtime via HTTP server, for root users when auth is enabled. Address is at client URL + \"/debug/log\".")
+	fs.BoolVar(&cfg.EnableFailpointsEndpoint, "enable-failpoints-endpoint", false, "Enable the failpoints via HTTP server, for root users when auth is enabled. Address is at client URL + \"/debug/failpoints\". Requires a binary built with the failpoints tag.")
 
 	// additional metrics
 	fs.StringVar(&cfg.Metrics, "metrics", cfg.Metrics, "Set level of detail for exported metrics, specify 'extensive' to include server side grpc histogram metrics")
+	fs.Var(flags.NewFloat64sValue(cfg.GRPCHistogramBuckets...), "grpc-histogram-buckets", "Comma-separated list of bucket boundaries in seconds of the server side grpc histogram metrics (empty for the default buckets). Requires '--metrics=extensive'.")
+	fs.Var(flags.NewStringsValue(strings.Join(cfg.MetricsDenylist, ",")), "metrics-denylist", "Comma-separated list of metric name prefixes not to expose on /metrics (e.g. 'grpc_server_handling_seconds').")
+	fs.Var(flags.NewStringsValue(strings.Join(cfg.MetricsKeyPrefixes, ",")), "metrics-key-prefixes", "Comma-separated list of key prefixes whose number of keys and total size of values are exported as metrics.")
+	fs.BoolVar(&cfg.SerializableHealthCheck, "serializable-health-check", false, "Check the local member with a serializable read instead of a quorum read on /health, unless overridden by the 'serializable' query parameter.")
+	fs.DurationVar(&cfg.HealthCheckTimeout, "health-check-timeout", cfg.HealthCheckTimeout, "Timeout of the read done by /health, unless overridden by the 'timeout' query parameter (0 to use the server request timeout).")
 
 	fs.BoolVar(&cfg.EnableDistributedTracing, "enable-distributed-tracing", false, "Enable distributed tracing using OpenTelemetry Tracing.")
 	fs.StringVar(&cfg.DistributedTracingAddress, "distributed-tracing-address", cfg.DistributedTracingAddress, "Address for distributed tracing used for OpenTelemetry Tracing (if enabled with enable-distributed-tracing flag).")
@@ -741,26 +1386,92 @@ func (cfg *Config) AddFlags(fs *flag.FlagSet) {
 	fs.StringVar(&cfg.DistributedTracingServiceInstanceID, "distributed-tracing-instance-id", "", "Configures service instance ID for distributed tracing to be used to define service instance ID key for OpenTelemetry Tracing (if enabled with enable-distributed-tracing flag). There is no default value set. This ID must be unique per etcd instance.")
 	fs.IntVar(&cfg.DistributedTracingSamplingRatePerMillion, "distributed-tracing-sampling-rate", 0, "Number of samples to collect per million spans for OpenTelemetry Tracing (if enabled with enable-distributed-tracing flag).")
 
+	fs.BoolVar(&cfg.EnableOTLPMetrics, "enable-otlp-metrics", false, "Enable pushing the metrics exposed on /metrics to an OpenTelemetry Collector.")
+	fs.StringVar(&cfg.OTLPMetricsAddress, "otlp-metrics-address", cfg.OTLPMetricsAddress, "Address of the OpenTelemetry Collector to push metrics to (if enabled with enable-otlp-metrics flag).")
+	fs.DurationVar(&cfg.OTLPMetricsInterval, "otlp-metrics-interval", cfg.OTLPMetricsInterval, "Interval between metrics pushes to the OpenTelemetry Collector (if enabled with enable-otlp-metrics flag).")
+
 	// auth
 	fs.StringVar(&cfg.AuthToken, "auth-token", cfg.AuthToken, "Specify auth token specific options.")
 	fs.UintVar(&cfg.BcryptCost, "bcrypt-cost", cfg.BcryptCost, "Specify bcrypt algorithm cost factor for auth password hashing.")
 	fs.UintVar(&cfg.AuthTokenTTL, "auth-token-ttl", cfg.AuthTokenTTL, "The lifetime in seconds of the auth token.")
+	fs.IntVar(&cfg.AuthLockoutUserFailures, "auth-lockout-user-failures", cfg.AuthLockoutUserFailures, "Number of consecutive authentication failures locking out a user. 0 disables the lockout.")
+	fs.IntVar(&cfg.AuthLockoutSourceFailures, "auth-lockout-source-failures", cfg.AuthLockoutSourceFailures, "Number of consecutive authentication failures locking out a client address, except the loopback and proxied ones. 0 disables the lockout.")
+	fs.DurationVar(&cfg.AuthLockoutDuration, "auth-lockout-duration", cfg.AuthLockoutDuration, "Duration of a first authentication lockout, doubled on each further failure.")
+	fs.DurationVar(&cfg.AuthLockoutMaxDuration, "auth-lockout-max-duration", cfg.AuthLockoutMaxDuration, "Maximum duration of an authentication lockout, after which failures are also forgotten.")
+	fs.DurationVar(&cfg.AuthPasswordMaxAge, "auth-password-max-age", cfg.AuthPasswordMaxAge, "Duration a password is valid for after it is set. 0 disables the expiry of passwords.")
+	fs.DurationVar(&cfg.AuthPasswordGracePeriod, "auth-password-grace-period", cfg.AuthPasswordGracePeriod, "Duration an expired password still authenticates its user for, so that the user can change it.")
 
 	// gateway
 	fs.BoolVar(&cfg.EnableGRPCGateway, "enable-grpc-gateway", cfg.EnableGRPCGateway, "Enable GRPC gateway.")
+	fs.BoolVar(&cfg.EnableGRPCReflection, "enable-grpc-reflection", false, "Enable the gRPC server reflection service on the client gRPC server, for tools like grpcurl. Meant for development environments.")
 	fs.DurationVar(&cfg.CorruptCheckTime, "corrupt-check-time", cfg.CorruptCheckTime, "Duration of time between cluster corruption check passes.")
 	fs.DurationVar(&cfg.CompactHashCheckTime, "compact-hash-check-time", cfg.CompactHashCheckTime, "Duration of time between leader checks followers compaction hashes.")
+	fs.BoolVar(&cfg.CompactHashCheckQuarantine, "compact-hash-check-quarantine", cfg.CompactHashCheckQuarantine, "Quarantine the members whose compaction hash diverged from the majority instead of raising a cluster-wide corruption alarm.")
 
 	fs.IntVar(&cfg.CompactionBatchLimit, "compaction-batch-limit", cfg.CompactionBatchLimit, "Sets the maximum revisions deleted in each compaction batch.")
 	fs.DurationVar(&cfg.CompactionSleepInterval, "compaction-sleep-interval", cfg.CompactionSleepInterval, "Sets the sleep interval between each compaction batch.")
+	fs.DurationVar(&cfg.CompactionTargetCommitLatency, "compaction-target-commit-latency", 0, "Grows the sleep between compaction batches while their commits are slower than this latency. 0 keeps the sleep fixed.")
+	fs.IntVar(&cfg.CompactionWorkers, "compaction-workers", cfg.CompactionWorkers, "Sets the number of workers compacting concurrently while no foreground requests are served.")
+	fs.Int64Var(&cfg.SlowWatcherMaxBacklog, "slow-watcher-max-backlog", cfg.SlowWatcherMaxBacklog, "Maximum number of revisions a slow watcher may fall behind before the slow watcher policy is applied. 0 disables the limit.")
+	fs.StringVar(&cfg.SlowWatcherPolicy, "slow-watcher-policy", cfg.SlowWatcherPolicy, "Policy applied to slow watchers exceeding --slow-watcher-max-backlog: 'throttle', 'compact' or 'cancel'.")
+	fs.IntVar(&cfg.MaxWatchStreams, "max-watch-streams", cfg.MaxWatchStreams, "Maximum number of watch streams served. 0 disables the limit.")
+	fs.IntVar(&cfg.MaxWatchersPerStream, "max-watchers-per-stream", cfg.MaxWatchersPerStream, "Maximum number of watchers on each watch stream. 0 disables the limit.")
 	fs.DurationVar(&cfg.WatchProgressNotifyInterval, "watch-progress-notify-interval", cfg.WatchProgressNotifyInterval, "Duration of periodic watch progress notifications.")
 	fs.DurationVar(&cfg.DowngradeCheckTime, "downgrade-check-time", cfg.DowngradeCheckTime, "Duration of time between two downgrade status checks.")
 	fs.DurationVar(&cfg.WarningApplyDuration, "warning-apply-duration", cfg.WarningApplyDuration, "Time duration after which a warning is generated if watch progress takes more time.")
+	fs.Uint64Var(&cfg.ApplyBacklogAlertThreshold, "apply-backlog-alert-threshold", cfg.ApplyBacklogAlertThreshold, "Number of committed entries waiting to be applied above which etcd_server_apply_backlog_threshold_crossed_total is incremented (0 to disable).")
+	fs.Uint64Var(&cfg.ApplyBacklogBackpressureThreshold, "apply-backlog-backpressure-threshold", cfg.ApplyBacklogBackpressureThreshold, "Number of committed entries waiting to be applied above which new proposals wait for the backlog to drain (0 to disable).")
+	fs.Float64Var(&cfg.KeyAccessSampleRate, "key-access-sample-rate", cfg.KeyAccessSampleRate, "Fraction of range requests, between 0 and 1, whose keys get their last access time recorded (0 to disable).")
+	fs.StringVar(&cfg.BackupURL, "backup-url", cfg.BackupURL, "URL of the sink the leader continuously backs up to, e.g. file:///var/backup/etcd (empty to disable).")
+	fs.DurationVar(&cfg.BackupInterval, "backup-interval", cfg.BackupInterval, "Interval between two shipments of the committed raft entries to the backup sink.")
+	fs.DurationVar(&cfg.BackupSnapshotInterval, "backup-snapshot-interval", cfg.BackupSnapshotInterval, "Interval between two base snapshots shipped to the backup sink.")
+	fs.IntVar(&cfg.BackupRetention, "backup-retention", cfg.BackupRetention, "Number of base snapshots kept in the backup sink (0 to keep all).")
+	fs.Var(flags.NewStringsValue(strings.Join(cfg.CDCSinkURLs, ",")), "cdc-sink-urls", "Comma-separated list of URLs of the sinks the leader publishes the key-value events to, e.g. https://cdc.example.com/events (empty to disable).")
+	fs.StringVar(&cfg.CDCCheckpointKeyPrefix, "cdc-checkpoint-key-prefix", cfg.CDCCheckpointKeyPrefix, "Prefix of the keys checkpointing the events published to each CDC sink.")
+	fs.Var(flags.NewStringsValue(strings.Join(cfg.MirrorTargetEndpoints, ",")), "mirror-target-endpoints", "Comma-separated list of endpoints of the cluster the leader mirrors the keys to (empty to disable).")
+	fs.StringVar(&cfg.MirrorTargetCertFile, "mirror-target-cert-file", cfg.MirrorTargetCertFile, "Path to the client certificate for the connections to the mirror target cluster.")
+	fs.StringVar(&cfg.MirrorTargetKeyFile, "mirror-target-key-file", cfg.MirrorTargetKeyFile, "Path to the client key for the connections to the mirror target cluster.")
+	fs.StringVar(&cfg.MirrorTargetTrustedCAFile, "mirror-target-trusted-ca-file", cfg.MirrorTargetTrustedCAFile, "Path to the CA verifying the mirror target cluster.")
+	fs.StringVar(&cfg.MirrorPrefix, "mirror-prefix", cfg.MirrorPrefix, "Prefix of the keys mirrored (empty for all the keys).")
+	fs.StringVar(&cfg.MirrorDestPrefix, "mirror-dest-prefix", cfg.MirrorDestPrefix, "Prefix replacing --mirror-prefix in the keys of the mirror target cluster (defaults to --mirror-prefix).")
+	fs.BoolVar(&cfg.MirrorNoDestPrefix, "mirror-no-dest-prefix", cfg.MirrorNoDestPrefix, "Strip --mirror-prefix from the keys of the mirror target cluster.")
+	fs.StringVar(&cfg.MirrorCheckpointKey, "mirror-checkpoint-key", cfg.MirrorCheckpointKey, "Key of the mirror target cluster holding the revision up to which the keys were mirrored.")
+	fs.IntVar(&cfg.MirrorMaxTxnOps, "mirror-max-txn-ops", cfg.MirrorMaxTxnOps, "Maximum number of operations of a txn to the mirror target cluster.")
+	fs.DurationVar(&cfg.AutoSnapshotInterval, "auto-snapshot-interval", cfg.AutoSnapshotInterval, "Interval between two snapshots of the backend saved to --auto-snapshot-dir (0 to disable).")
+	fs.StringVar(&cfg.AutoSnapshotDir, "auto-snapshot-dir", cfg.AutoSnapshotDir, "Directory of the auto snapshots.")
+	fs.UintVar(&cfg.AutoSnapshotRetention, "auto-snapshot-retention", cfg.AutoSnapshotRetention, "Number of auto snapshots of the member kept in --auto-snapshot-dir (0 to keep all).")
+	fs.BoolVar(&cfg.AutoSnapshotLeaderOnly, "auto-snapshot-leader-only", cfg.AutoSnapshotLeaderOnly, "Save the auto snapshots only while the member is the leader.")
+	fs.StringVar(&cfg.AutoDefragSchedule, "auto-defrag-schedule", cfg.AutoDefragSchedule, "Cron expression (e.g. '0 3 * * *') of the times the member defragments its backend, in its local time (empty to disable).")
+	fs.Float64Var(&cfg.AutoDefragRatio, "auto-defrag-ratio", cfg.AutoDefragRatio, "Minimum ratio of the backend size not in use for the auto defragmentation to run.")
+	fs.BoolVar(&cfg.AutoDefragTransferLeadership, "auto-defrag-transfer-leadership", cfg.AutoDefragTransferLeadership, "Transfer the leadership before the auto defragmentation of the leader, which skips it otherwise.")
+	fs.StringVar(&cfg.AutoDefragLockKey, "auto-defrag-lock-key", cfg.AutoDefragLockKey, "Key locked by the member running the auto defragmentation, so that the members defragment one at a time.")
+	fs.Var(flags.NewStringsValue(strings.Join(cfg.LeaseStewardPrefixes, ",")), "lease-steward-prefixes", "Comma-separated list of lock and election key prefixes the leader looks for keys without a live lease in (empty to disable).")
+	fs.DurationVar(&cfg.LeaseStewardInterval, "lease-steward-interval", cfg.LeaseStewardInterval, "Interval between two scans of --lease-steward-prefixes.")
+	fs.BoolVar(&cfg.LeaseStewardDelete, "lease-steward-delete", cfg.LeaseStewardDelete, "Delete the keys found without a live lease by two scans in a row, instead of only reporting them.")
+	fs.DurationVar(&cfg.LearnerAutoPromoteDuration, "learner-auto-promote-duration", cfg.LearnerAutoPromoteDuration, "Time a learner has to stay caught up with the leader before being promoted to a voting member (0 to disable).")
+	fs.Uint64Var(&cfg.LearnerAutoPromoteMaxLag, "learner-auto-promote-max-lag", cfg.LearnerAutoPromoteMaxLag, "Maximum number of raft entries a learner caught up with the leader lags behind it.")
+	fs.Uint64Var(&cfg.FollowerLagAlarmThreshold, "follower-lag-alarm-threshold", cfg.FollowerLagAlarmThreshold, "Number of raft entries a voting follower may lag behind the leader before the leader raises a FOLLOWER_LAG alarm for it (0 to disable).")
+	fs.DurationVar(&cfg.FollowerLagAlarmDuration, "follower-lag-alarm-duration", cfg.FollowerLagAlarmDuration, "Time a follower has to stay behind --follower-lag-alarm-threshold before the FOLLOWER_LAG alarm is raised.")
+	fs.BoolVar(&cfg.LeaderLeaseReads, "leader-lease-reads", cfg.LeaderLeaseReads, "Serve linearizable reads on the leader from its leader lease instead of a ReadIndex round trip.")
+	fs.DurationVar(&cfg.LeaderLeaseClockDrift, "leader-lease-clock-drift", cfg.LeaderLeaseClockDrift, "Maximum clock drift between the members over an election timeout, by which the leader lease is shortened.")
+	fs.BoolVar(&cfg.Witness, "witness", cfg.Witness, "Run the member as a witness, voting in raft but storing no keys and serving no client requests.")
+	fs.StringVar(&cfg.EncryptionKEKFile, "encryption-kek-file", cfg.EncryptionKEKFile, "File of the key encryption keys of the backend and WAL encryption at rest, one '<id>:<base64 key>' line per key, the current key first.")
+	fs.StringVar(&cfg.EncryptionKMSURL, "encryption-kms-url", cfg.EncryptionKMSURL, "URL of the KMS webhook wrapping the data encryption keys of the backend and WAL encryption at rest.")
 	fs.DurationVar(&cfg.WarningUnaryRequestDuration, "warning-unary-request-duration", cfg.WarningUnaryRequestDuration, "Time duration after which a warning is generated if a unary request takes more time.")
+	fs.DurationVar(&cfg.LogSlowRequestsAbove, "log-slow-requests-above", cfg.LogSlowRequestsAbove, "Log every unary request slower than this duration with its queue wait, raft, apply and backend latency (0 to disable).")
+	fs.IntVar(&cfg.LogSlowRequestsSampleInitial, "log-slow-requests-sample-initial", cfg.LogSlowRequestsSampleInitial, "Number of slow requests logged each second before sampling with '--log-slow-requests-sample-thereafter'.")
+	fs.IntVar(&cfg.LogSlowRequestsSampleThereafter, "log-slow-requests-sample-thereafter", cfg.LogSlowRequestsSampleThereafter, "Log only every Nth of the remaining slow requests of each second (0 to drop them).")
+	fs.BoolVar(&cfg.EnableLeaderChangeEvents, "enable-leader-change-events", cfg.EnableLeaderChangeEvents, "Emit a structured log event on every leadership change.")
+	fs.StringVar(&cfg.LeaderChangeEventKey, "leader-change-event-key", cfg.LeaderChangeEventKey, "Key the newly elected leader writes leadership change events to (empty disables writing).")
 	fs.BoolVar(&cfg.MemoryMlock, "memory-mlock", cfg.MemoryMlock, "Enable to enforce etcd pages (in particular bbolt) to stay in RAM.")
 	fs.UintVar(&cfg.BootstrapDefragThresholdMegabytes, "bootstrap-defrag-threshold-megabytes", 0, "Enable the defrag during etcd server bootstrap on condition that it will free at least the provided threshold of disk space. Needs to be set to non-zero value to take effect.")
 	fs.IntVar(&cfg.MaxLearners, "max-learners", membership.DefaultMaxLearners, "Sets the maximum number of learners that can be available in the cluster membership.")
 	fs.Uint64Var(&cfg.SnapshotCatchUpEntries, "snapshot-catchup-entries", cfg.SnapshotCatchUpEntries, "Number of entries for a slow follower to catch up after compacting the raft storage entries.")
+	fs.Int64Var(&cfg.SnapshotSendRateLimit, "snapshot-send-rate-limit", cfg.SnapshotSendRateLimit, "Maximum number of bytes per second of the snapshots sent to clients. 0 disables the limit.")
+	fs.Int64Var(&cfg.PeerSnapshotSendRateLimit, "peer-snapshot-send-rate-limit", cfg.PeerSnapshotSendRateLimit, "Maximum number of bytes per second of the snapshots sent to peers. 0 disables the limit.")
+	fs.DurationVar(&cfg.PeerAddressRefreshInterval, "peer-address-refresh-interval", cfg.PeerAddressRefreshInterval, "Interval at which the host names of the peer URLs are re-resolved, resetting the connections with the peers whose addresses changed. 0 disables the re-resolution.")
+	fs.Var(flags.NewStringsValue(""), "peer-network-faults", "Comma-separated list of faults injected in the messages sent to peers, as '<peer-url>=<latency>/<jitter>/<drop-rate>'. For testing only.")
+	fs.Int64Var(&cfg.PeerNetworkFaultsSeed, "peer-network-faults-seed", cfg.PeerNetworkFaultsSeed, "Seed of the random faults injected in the messages sent to peers. For testing only.")
 
 	// unsafe
 	fs.BoolVar(&cfg.UnsafeNoFsync, "unsafe-no-fsync", false, "Disables fsync, unsafe, will cause data loss.")
@@ -775,6 +1486,7 @@ func ConfigFromFile(path string) (*Config, error) {
 	if err := cfg.configFromFile(path); err != nil {
 		return nil, err
 	}
+	cfg.configFile = path
 	return &cfg.Config, nil
 }
 
@@ -884,7 +1596,7 @@ func (cfg *configYAML) configFromFile(path string) error {
 	}
 
 	// If a discovery or discovery-endpoints flag is set, clear default initial cluster set by InitialClusterFromName
-	if (cfg.Durl != "" || cfg.DNSCluster != "" || len(cfg.DiscoveryCfg.Endpoints) > 0) && cfg.InitialCluster == defaultInitialCluster {
+	if (cfg.Durl != "" || cfg.DNSCluster != "" || len(cfg.DiscoveryCfg.Endpoints) > 0 || cfg.K8sDiscoveryCfg.LabelSelector != "") && cfg.InitialCluster == defaultInitialCluster {
 		cfg.InitialCluster = ""
 	}
 	if cfg.ClusterState == "" {
@@ -901,9 +1613,22 @@ func (cfg *configYAML) configFromFile(path string) error {
 		tls.AllowedCNs = ysc.AllowedCNs
 		tls.AllowedHostnames = ysc.AllowedHostnames
 		tls.SkipClientSANVerify = ysc.SkipClientSANVerify
+		tls.AllowedSPIFFETrustDomains = ysc.AllowedSPIFFETrustDomains
+		tls.AllowedSPIFFEIDs = ysc.AllowedSPIFFEIDs
 	}
 	copySecurityDetails(&cfg.ClientTLSInfo, &cfg.ClientSecurityJSON)
 	copySecurityDetails(&cfg.PeerTLSInfo, &cfg.PeerSecurityJSON)
+	for i := range cfg.ClientListenerGroups {
+		g := &cfg.ClientListenerGroups[i]
+		if g.ListenURLsJSON != "" {
+			u, err := types.NewURLs(strings.Split(g.ListenURLsJSON, ","))
+			if err != nil {
+				return fmt.Errorf("invalid listen-urls of client listener group %q: %w", g.Name, err)
+			}
+			g.ListenURLs = u
+		}
+		copySecurityDetails(&g.TLSInfo, &g.SecurityJSON)
+	}
 	cfg.ClientAutoTLS = cfg.ClientSecurityJSON.AutoTLS
 	cfg.PeerAutoTLS = cfg.PeerSecurityJSON.AutoTLS
 	if cfg.SelfSignedCertValidity == 0 {
@@ -926,6 +1651,76 @@ func updateCipherSuites(tls *transport.TLSInfo, ss []string) error {
 	return nil
 }
 
+// listenerTLS are the TLS versions and cipher suites of a kind of listeners.
+type listenerTLS struct {
+	minVersion   string
+	maxVersion   string
+	cipherSuites []string
+}
+
+// override returns the settings given for a kind of listeners, falling back on
+// lt for the ones not given.
+func (lt listenerTLS) override(minVersion, maxVersion string, cipherSuites []string) listenerTLS {
+	if minVersion != "" {
+		lt.minVersion = minVersion
+	}
+	if maxVersion != "" {
+		lt.maxVersion = maxVersion
+	}
+	if len(cipherSuites) > 0 {
+		lt.cipherSuites = cipherSuites
+	}
+	return lt
+}
+
+func (cfg *Config) clientListenerTLS() listenerTLS {
+	return listenerTLS{cfg.TlsMinVersion, cfg.TlsMaxVersion, cfg.CipherSuites}.override(cfg.ClientTLSMinVersion, cfg.ClientTLSMaxVersion, cfg.ClientCipherSuites)
+}
+
+func (cfg *Config) peerListenerTLS() listenerTLS {
+	return listenerTLS{cfg.TlsMinVersion, cfg.TlsMaxVersion, cfg.CipherSuites}.override(cfg.PeerTLSMinVersion, cfg.PeerTLSMaxVersion, cfg.PeerCipherSuites)
+}
+
+func (cfg *Config) metricsListenerTLS() listenerTLS {
+	return cfg.clientListenerTLS().override(cfg.MetricsTLSMinVersion, cfg.MetricsTLSMaxVersion, cfg.MetricsCipherSuites)
+}
+
+// validate checks the settings, prefix naming the kind of listeners in the
+// errors.
+func (lt listenerTLS) validate(prefix string) error {
+	minVersion, err := tlsutil.GetTLSVersion(lt.minVersion)
+	if err != nil {
+		return fmt.Errorf("%s%w", prefix, err)
+	}
+	maxVersion, err := tlsutil.GetTLSVersion(lt.maxVersion)
+	if err != nil {
+		return fmt.Errorf("%s%w", prefix, err)
+	}
+
+	// maxVersion == 0 means that Go selects the highest available version.
+	if maxVersion != 0 && minVersion > maxVersion {
+		return fmt.Errorf("%smin version (%s) is greater than max version (%s)", prefix, lt.minVersion, lt.maxVersion)
+	}
+
+	// Check if user attempted to configure ciphers for TLS1.3 only: Go does not support that currently.
+	if minVersion == tls.VersionTLS13 && len(lt.cipherSuites) > 0 {
+		return fmt.Errorf("%scipher suites cannot be configured when only TLS1.3 is enabled", prefix)
+	}
+	if _, err = tlsutil.GetCipherSuites(lt.cipherSuites); err != nil {
+		return fmt.Errorf("%s%w", prefix, err)
+	}
+	return nil
+}
+
+// apply sets the settings on info, whose cipher suites must not be set.
+func (lt listenerTLS) apply(info *transport.TLSInfo) error {
+	if err := updateCipherSuites(info, lt.cipherSuites); err != nil {
+		return err
+	}
+	updateMinMaxVersions(info, lt.minVersion, lt.maxVersion)
+	return nil
+}
+
 func updateMinMaxVersions(info *transport.TLSInfo, min, max string) {
 	// Validate() has been called to check the user input, so it should never fail.
 	var err error
@@ -951,9 +1746,23 @@ func (cfg *Config) Validate() error {
 	if err := checkBindURLs(cfg.ListenClientHttpUrls); err != nil {
 		return err
 	}
+	if err := cfg.validateQUICURLs(); err != nil {
+		return err
+	}
 	if len(cfg.ListenClientHttpUrls) == 0 {
 		cfg.logger.Warn("Running http and grpc server on single port. This is not recommended for production.")
 	}
+	groups := make(map[string]bool)
+	for i := range cfg.ClientListenerGroups {
+		g := &cfg.ClientListenerGroups[i]
+		if err := g.validate(); err != nil {
+			return err
+		}
+		if groups[g.Name] {
+			return fmt.Errorf("duplicate client listener group %q", g.Name)
+		}
+		groups[g.Name] = true
+	}
 	if err := checkBindURLs(cfg.ListenMetricsUrls); err != nil {
 		return err
 	}
@@ -967,7 +1776,7 @@ func (cfg *Config) Validate() error {
 	}
 	// Check if conflicting flags are passed.
 	nSet := 0
-	for _, v := range []bool{cfg.Durl != "", cfg.InitialCluster != "", cfg.DNSCluster != "", len(cfg.DiscoveryCfg.Endpoints) > 0} {
+	for _, v := range []bool{cfg.Durl != "", cfg.InitialCluster != "", cfg.DNSCluster != "", len(cfg.DiscoveryCfg.Endpoints) > 0, cfg.K8sDiscoveryCfg.LabelSelector != ""} {
 		if v {
 			nSet++
 		}
@@ -1002,6 +1811,9 @@ func (cfg *Config) Validate() error {
 	if (cfg.DiscoveryCfg.Token != "") != (len(cfg.DiscoveryCfg.Endpoints) > 0) {
 		return errors.New("both --discovery-token and --discovery-endpoints must be set")
 	}
+	if err := cfg.K8sDiscoveryCfg.Validate(); err != nil {
+		return err
+	}
 
 	if cfg.TickMs == 0 {
 		return fmt.Errorf("--heartbeat-interval must be >0 (set to %dms)", cfg.TickMs)
@@ -1021,8 +1833,12 @@ func (cfg *Config) Validate() error {
 		return ErrUnsetAdvertiseClientURLsFlag
 	}
 
+	if cfg.EnableFailpointsEndpoint && !etcdhttp.FailpointsSupported {
+		return errors.New("--enable-failpoints-endpoint requires a binary built with the failpoints tag")
+	}
+
 	switch cfg.AutoCompactionMode {
-	case CompactorModeRevision, CompactorModePeriodic:
+	case CompactorModeRevision, CompactorModePeriodic, CompactorModeSize:
 	case "":
 		return errors.New("undefined auto-compaction-mode")
 	default:
@@ -1036,6 +1852,40 @@ func (cfg *Config) Validate() error {
 		}
 	}
 
+	if len(cfg.GRPCHistogramBuckets) > 0 {
+		if cfg.Metrics != "extensive" {
+			return fmt.Errorf("--grpc-histogram-buckets requires --metrics=extensive (set to %q)", cfg.Metrics)
+		}
+		for i, b := range cfg.GRPCHistogramBuckets {
+			if b <= 0 || (i > 0 && b <= cfg.GRPCHistogramBuckets[i-1]) {
+				return fmt.Errorf("--grpc-histogram-buckets must be positive and strictly increasing (set to %v)", cfg.GRPCHistogramBuckets)
+			}
+		}
+	}
+
+	if cfg.LogSlowRequestsAbove < 0 {
+		return fmt.Errorf("--log-slow-requests-above must not be negative (set to %v)", cfg.LogSlowRequestsAbove)
+	}
+	if cfg.LogSlowRequestsAbove > 0 {
+		if cfg.LogSlowRequestsSampleInitial <= 0 {
+			return fmt.Errorf("--log-slow-requests-sample-initial must be positive (set to %d)", cfg.LogSlowRequestsSampleInitial)
+		}
+		if cfg.LogSlowRequestsSampleThereafter < 0 {
+			return fmt.Errorf("--log-slow-requests-sample-thereafter must not be negative (set to %d)", cfg.LogSlowRequestsSampleThereafter)
+		}
+	}
+
+	if err := validateAuditLogConfig(cfg); err != nil {
+		return err
+	}
+
+	// Validate OTLP metrics configuration but only if enabled.
+	if cfg.EnableOTLPMetrics {
+		if err := validateOTLPMetricsConfig(cfg); err != nil {
+			return err
+		}
+	}
+
 	if !cfg.ServerFeatureGate.Enabled(features.LeaseCheckpointPersist) && cfg.ServerFeatureGate.Enabled(features.LeaseCheckpoint) {
 		cfg.logger.Warn("Detected that checkpointing is enabled without persistence. Consider enabling feature gate LeaseCheckpointPersist")
 	}
@@ -1044,6 +1894,230 @@ func (cfg *Config) Validate() error {
 		return fmt.Errorf("enabling feature gate LeaseCheckpointPersist requires enabling feature gate LeaseCheckpoint")
 	}
 
+	if cfg.KeyAccessSampleRate < 0 || cfg.KeyAccessSampleRate > 1 {
+		return fmt.Errorf("--key-access-sample-rate must be between 0 and 1 (set to %v)", cfg.KeyAccessSampleRate)
+	}
+
+	if cfg.BackupURL != "" {
+		if cfg.BackupInterval <= 0 {
+			return fmt.Errorf("--backup-interval must be positive (set to %v)", cfg.BackupInterval)
+		}
+		if cfg.BackupSnapshotInterval <= 0 {
+			return fmt.Errorf("--backup-snapshot-interval must be positive (set to %v)", cfg.BackupSnapshotInterval)
+		}
+		if cfg.BackupRetention < 0 {
+			return fmt.Errorf("--backup-retention must not be negative (set to %d)", cfg.BackupRetention)
+		}
+	}
+
+	if len(cfg.CDCSinkURLs) > 0 && cfg.CDCCheckpointKeyPrefix == "" {
+		return fmt.Errorf("--cdc-checkpoint-key-prefix must be set with --cdc-sink-urls")
+	}
+	if len(cfg.MirrorTargetEndpoints) > 0 {
+		if cfg.MirrorCheckpointKey == "" {
+			return fmt.Errorf("--mirror-checkpoint-key must be set with --mirror-target-endpoints")
+		}
+		if cfg.MirrorMaxTxnOps < 2 {
+			return fmt.Errorf("--mirror-max-txn-ops must be at least 2 (set to %d)", cfg.MirrorMaxTxnOps)
+		}
+		if cfg.MirrorNoDestPrefix && cfg.MirrorDestPrefix != "" {
+			return fmt.Errorf("--mirror-no-dest-prefix and --mirror-dest-prefix cannot be both set")
+		}
+	}
+
+	if cfg.AutoSnapshotInterval < 0 {
+		return fmt.Errorf("--auto-snapshot-interval must not be negative (set to %v)", cfg.AutoSnapshotInterval)
+	}
+	if cfg.AutoSnapshotInterval > 0 && cfg.AutoSnapshotDir == "" {
+		return fmt.Errorf("--auto-snapshot-dir must be set with --auto-snapshot-interval")
+	}
+
+	if cfg.EncryptionKEKFile != "" && cfg.EncryptionKMSURL != "" {
+		return fmt.Errorf("--encryption-kek-file and --encryption-kms-url cannot be set at the same time")
+	}
+	if cfg.EncryptionKMSURL != "" {
+		if u, err := url.Parse(cfg.EncryptionKMSURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("--encryption-kms-url must be an http or https URL (set to %q)", cfg.EncryptionKMSURL)
+		}
+	}
+
+	if _, err := wal.ParseCompression(cfg.WALCompression); err != nil {
+		return fmt.Errorf("invalid --wal-compression: %w", err)
+	}
+
+	if !slices.Contains(backend.Engines, cfg.ExperimentalStorageEngine) {
+		return fmt.Errorf("invalid --experimental-storage-engine %q (must be one of %q)", cfg.ExperimentalStorageEngine, backend.Engines)
+	}
+
+	if cfg.AutoDefragSchedule != "" {
+		if _, err := schedule.ParseCron(cfg.AutoDefragSchedule); err != nil {
+			return fmt.Errorf("invalid --auto-defrag-schedule: %w", err)
+		}
+		if cfg.AutoDefragLockKey == "" {
+			return fmt.Errorf("--auto-defrag-lock-key must be set with --auto-defrag-schedule")
+		}
+	}
+	if cfg.AutoDefragRatio < 0 || cfg.AutoDefragRatio > 1 {
+		return fmt.Errorf("--auto-defrag-ratio must be in [0, 1] (set to %v)", cfg.AutoDefragRatio)
+	}
+
+	if len(cfg.LeaseStewardPrefixes) > 0 {
+		if slices.Contains(cfg.LeaseStewardPrefixes, "") {
+			return fmt.Errorf("--lease-steward-prefixes must not contain an empty prefix")
+		}
+		if cfg.LeaseStewardInterval <= 0 {
+			return fmt.Errorf("--lease-steward-interval must be positive (set to %v)", cfg.LeaseStewardInterval)
+		}
+	}
+
+	if cfg.LearnerAutoPromoteDuration < 0 {
+		return fmt.Errorf("--learner-auto-promote-duration must not be negative (set to %v)", cfg.LearnerAutoPromoteDuration)
+	}
+
+	if cfg.FollowerLagAlarmDuration < 0 {
+		return fmt.Errorf("--follower-lag-alarm-duration must not be negative (set to %v)", cfg.FollowerLagAlarmDuration)
+	}
+
+	if cfg.LeaderLeaseClockDrift < 0 {
+		return fmt.Errorf("--leader-lease-clock-drift must not be negative (set to %v)", cfg.LeaderLeaseClockDrift)
+	}
+	if cfg.LeaderLeaseReads && cfg.LeaderLeaseClockDrift >= time.Duration(cfg.ElectionMs)*time.Millisecond {
+		return fmt.Errorf("--leader-lease-clock-drift[%v] must be below --election-timeout[%vms]", cfg.LeaderLeaseClockDrift, cfg.ElectionMs)
+	}
+
+	if cfg.BackendBatchAdaptive {
+		if cfg.BackendBatchIntervalMin <= 0 || cfg.BackendBatchIntervalMin > cfg.BackendBatchIntervalMax {
+			return fmt.Errorf("--backend-batch-interval-min must be positive and not above --backend-batch-interval-max (set to %v and %v)", cfg.BackendBatchIntervalMin, cfg.BackendBatchIntervalMax)
+		}
+		if cfg.BackendBatchLimitMin <= 0 || cfg.BackendBatchLimitMin > cfg.BackendBatchLimitMax {
+			return fmt.Errorf("--backend-batch-limit-min must be positive and not above --backend-batch-limit-max (set to %d and %d)", cfg.BackendBatchLimitMin, cfg.BackendBatchLimitMax)
+		}
+	}
+
+	if cfg.BackendReclaimFreePages {
+		if cfg.BackendReclaimThreshold <= 0 || cfg.BackendReclaimThreshold > 1 {
+			return fmt.Errorf("--backend-reclaim-threshold must be above 0 and at most 1 (set to %v)", cfg.BackendReclaimThreshold)
+		}
+		if cfg.BackendReclaimInterval <= 0 {
+			return fmt.Errorf("--backend-reclaim-interval must be positive (set to %v)", cfg.BackendReclaimInterval)
+		}
+	}
+
+	if cfg.CompactionTargetCommitLatency < 0 {
+		return fmt.Errorf("--compaction-target-commit-latency must not be negative (set to %v)", cfg.CompactionTargetCommitLatency)
+	}
+
+	if cfg.CompactionWorkers < 0 {
+		return fmt.Errorf("--compaction-workers must not be negative (set to %d)", cfg.CompactionWorkers)
+	}
+
+	if cfg.SlowWatcherMaxBacklog < 0 {
+		return fmt.Errorf("--slow-watcher-max-backlog must not be negative (set to %d)", cfg.SlowWatcherMaxBacklog)
+	}
+	if _, err := mvcc.ParseSlowWatcherPolicy(cfg.SlowWatcherPolicy); err != nil {
+		return fmt.Errorf("--slow-watcher-policy: %w", err)
+	}
+	if cfg.SnapshotSendRateLimit < 0 {
+		return fmt.Errorf("--snapshot-send-rate-limit must not be negative (set to %d)", cfg.SnapshotSendRateLimit)
+	}
+	if cfg.PeerSnapshotSendRateLimit < 0 {
+		return fmt.Errorf("--peer-snapshot-send-rate-limit must not be negative (set to %d)", cfg.PeerSnapshotSendRateLimit)
+	}
+	if cfg.PeerAddressRefreshInterval < 0 {
+		return fmt.Errorf("--peer-address-refresh-interval must not be negative (set to %v)", cfg.PeerAddressRefreshInterval)
+	}
+	if _, err := rafthttp.ParsePeerFaults(cfg.PeerNetworkFaults); err != nil {
+		return fmt.Errorf("--peer-network-faults: %w", err)
+	}
+	if cfg.MaxWatchStreams < 0 {
+		return fmt.Errorf("--max-watch-streams must not be negative (set to %d)", cfg.MaxWatchStreams)
+	}
+	if cfg.MaxWatchersPerStream < 0 {
+		return fmt.Errorf("--max-watchers-per-stream must not be negative (set to %d)", cfg.MaxWatchersPerStream)
+	}
+
+	for _, t := range cfg.QuotaBackendWarningThresholds {
+		if t <= 0 || t > 1 {
+			return fmt.Errorf("--quota-backend-warning-thresholds must be above 0 and at most 1 (set to %v)", cfg.QuotaBackendWarningThresholds)
+		}
+	}
+
+	if err := validateProxyProtocol("client", cfg.ClientProxyProtocol, cfg.ClientProxyProtocolTrustedCIDRs); err != nil {
+		return err
+	}
+	if err := validateProxyProtocol("peer", cfg.PeerProxyProtocol, cfg.PeerProxyProtocolTrustedCIDRs); err != nil {
+		return err
+	}
+
+	quotas, err := storage.ParseKeyQuotas(cfg.KeyQuotas)
+	if err != nil {
+		return fmt.Errorf("--key-quotas: %w", err)
+	}
+	if err = validateNamespaces(cfg.Namespaces, cfg.NamespacesKeyPrefix, quotas); err != nil {
+		return err
+	}
+
+	if _, err := auth.ParseCertRoleRules(cfg.ClientCertRoleRules); err != nil {
+		return fmt.Errorf("--client-cert-role-rules: %w", err)
+	}
+	if cfg.AuthLockoutUserFailures < 0 || cfg.AuthLockoutSourceFailures < 0 {
+		return fmt.Errorf("--auth-lockout-user-failures and --auth-lockout-source-failures must not be negative")
+	}
+	if cfg.AuthLockoutDuration <= 0 || cfg.AuthLockoutMaxDuration < cfg.AuthLockoutDuration {
+		return fmt.Errorf("--auth-lockout-duration must be positive and not greater than --auth-lockout-max-duration (set to %v and %v)", cfg.AuthLockoutDuration, cfg.AuthLockoutMaxDuration)
+	}
+	if cfg.AuthPasswordMaxAge < 0 || cfg.AuthPasswordGracePeriod < 0 {
+		return fmt.Errorf("--auth-password-max-age and --auth-password-grace-period must not be negative (set to %v and %v)", cfg.AuthPasswordMaxAge, cfg.AuthPasswordGracePeriod)
+	}
+
+	if cfg.HealthCheckTimeout < 0 {
+		return fmt.Errorf("--health-check-timeout must not be negative (set to %v)", cfg.HealthCheckTimeout)
+	}
+	if cfg.MaxCallerLabels < 0 {
+		return fmt.Errorf("--max-caller-labels must not be negative (set to %d)", cfg.MaxCallerLabels)
+	}
+	if cfg.MaxConcurrentExpensiveRanges < 0 {
+		return fmt.Errorf("--max-concurrent-expensive-ranges must not be negative (set to %d)", cfg.MaxConcurrentExpensiveRanges)
+	}
+	if cfg.ExpensiveRangeLimit < 0 {
+		return fmt.Errorf("--expensive-range-limit must not be negative (set to %d)", cfg.ExpensiveRangeLimit)
+	}
+	if cfg.ClientRateLimitQPS < 0 || cfg.ClientRateLimitBurst < 0 || cfg.ClientRateLimitBytes < 0 {
+		return fmt.Errorf("--client-rate-limit-qps, --client-rate-limit-burst and --client-rate-limit-bytes must not be negative")
+	}
+	if cfg.QoSMaxInflightRequests < 0 {
+		return fmt.Errorf("--qos-max-inflight-requests must not be negative (set to %d)", cfg.QoSMaxInflightRequests)
+	}
+	if cfg.AdmissionWebhookURL != "" {
+		u, err := url.Parse(cfg.AdmissionWebhookURL)
+		if err != nil {
+			return fmt.Errorf("invalid --admission-webhook-url %q: %w", cfg.AdmissionWebhookURL, err)
+		}
+		if u.Scheme != "https" {
+			return fmt.Errorf("--admission-webhook-url must be an https URL (set to %q)", cfg.AdmissionWebhookURL)
+		}
+		if cfg.AdmissionWebhookTimeout <= 0 {
+			return fmt.Errorf("--admission-webhook-timeout must be positive (set to %v)", cfg.AdmissionWebhookTimeout)
+		}
+		if (cfg.AdmissionWebhookCertFile == "") != (cfg.AdmissionWebhookKeyFile == "") {
+			return fmt.Errorf("--admission-webhook-cert-file and --admission-webhook-key-file must be set together")
+		}
+		if p := cfg.AdmissionWebhookFailurePolicy; p != admission.FailurePolicyFail && p != admission.FailurePolicyIgnore {
+			return fmt.Errorf("--admission-webhook-failure-policy must be %q or %q (set to %q)", admission.FailurePolicyFail, admission.FailurePolicyIgnore, p)
+		}
+	}
+	for flag, mode := range map[string]string{"--client-ocsp-check": cfg.ClientTLSInfo.OCSPCheck, "--peer-ocsp-check": cfg.PeerTLSInfo.OCSPCheck} {
+		if mode != "" && mode != transport.OCSPCheckSoftFail && mode != transport.OCSPCheckHardFail {
+			return fmt.Errorf("%s must be %q or %q (set to %q)", flag, transport.OCSPCheckSoftFail, transport.OCSPCheckHardFail, mode)
+		}
+	}
+	if cfg.ClientTLSInfo.CRLRefreshInterval < 0 || cfg.PeerTLSInfo.CRLRefreshInterval < 0 {
+		return fmt.Errorf("--client-crl-refresh-interval and --peer-crl-refresh-interval must not be negative")
+	}
+	if cfg.TLSReloadInterval < 0 || cfg.TLSReloadRecycleWindow < 0 {
+		return fmt.Errorf("--tls-reload-interval and --tls-reload-recycle-window must not be negative")
+	}
+
 	if cfg.CompactHashCheckTime <= 0 {
 		return fmt.Errorf("--compact-hash-check-time must be >0 (set to %v)", cfg.CompactHashCheckTime)
 	}
@@ -1060,26 +2134,16 @@ func (cfg *Config) Validate() error {
 			zap.String("name", cfg.Name))
 	}
 
-	minVersion, err := tlsutil.GetTLSVersion(cfg.TlsMinVersion)
-	if err != nil {
+	if err := (listenerTLS{cfg.TlsMinVersion, cfg.TlsMaxVersion, cfg.CipherSuites}).validate(""); err != nil {
 		return err
 	}
-	maxVersion, err := tlsutil.GetTLSVersion(cfg.TlsMaxVersion)
-	if err != nil {
+	if err := cfg.clientListenerTLS().validate("client listeners: "); err != nil {
 		return err
 	}
-
-	// maxVersion == 0 means that Go selects the highest available version.
-	if maxVersion != 0 && minVersion > maxVersion {
-		return fmt.Errorf("min version (%s) is greater than max version (%s)", cfg.TlsMinVersion, cfg.TlsMaxVersion)
-	}
-
-	// Check if user attempted to configure ciphers for TLS1.3 only: Go does not support that currently.
-	if minVersion == tls.VersionTLS13 && len(cfg.CipherSuites) > 0 {
-		return fmt.Errorf("cipher suites cannot be configured when only TLS1.3 is enabled")
+	if err := cfg.peerListenerTLS().validate("peer listeners: "); err != nil {
+		return err
 	}
-
-	return nil
+	return cfg.metricsListenerTLS().validate("metrics listeners: ")
 }
 
 // PeerURLsMapAndToken sets up an initial peer URLsMap and cluster token for bootstrap or discovery.
@@ -1125,6 +2189,16 @@ func (cfg *Config) PeerURLsMapAndToken(which string) (urlsmap types.URLsMap, tok
 			}
 		}
 
+	case cfg.K8sDiscoveryCfg.LabelSelector != "":
+		clusterStrs, cerr := getK8sCluster(cfg.GetLogger(), cfg.K8sDiscoveryCfg, cfg.Name, cfg.AdvertisePeerUrls)
+		if cerr != nil {
+			return nil, "", cerr
+		}
+		for _, s := range clusterStrs {
+			cfg.GetLogger().Info("got bootstrap from Kubernetes for etcd-server", zap.String("node", s))
+		}
+		urlsmap, err = types.NewURLsMap(strings.Join(clusterStrs, ","))
+
 	default:
 		// We're statically configured, and cluster has appropriately been set.
 		urlsmap, err = types.NewURLsMap(cfg.InitialCluster)
@@ -1269,7 +2343,7 @@ func (cfg *Config) ClientSelfCert() (err error) {
 	if err != nil {
 		return err
 	}
-	return updateCipherSuites(&cfg.ClientTLSInfo, cfg.CipherSuites)
+	return updateCipherSuites(&cfg.ClientTLSInfo, cfg.clientListenerTLS().cipherSuites)
 }
 
 func (cfg *Config) PeerSelfCert() (err error) {
@@ -1288,7 +2362,7 @@ func (cfg *Config) PeerSelfCert() (err error) {
 	if err != nil {
 		return err
 	}
-	return updateCipherSuites(&cfg.PeerTLSInfo, cfg.CipherSuites)
+	return updateCipherSuites(&cfg.PeerTLSInfo, cfg.peerListenerTLS().cipherSuites)
 }
 
 // UpdateDefaultClusterFromName updates cluster advertise URLs with, if available, default host,
@@ -1331,6 +2405,59 @@ func (cfg *Config) UpdateDefaultClusterFromName(defaultInitialCluster string) (s
 	return dhost, defaultHostStatus
 }
 
+// validateQUICURLs checks that the quic:// client URLs are enabled by the
+// QUICClientListener feature gate. As the QUIC listeners only serve gRPC, they
+// are only allowed along with another client URL serving gRPC.
+func (cfg *Config) validateQUICURLs() error {
+	urls := append(append([]url.URL{}, cfg.ListenClientUrls...), cfg.ListenClientHttpUrls...)
+	for _, g := range cfg.ClientListenerGroups {
+		urls = append(urls, g.ListenURLs...)
+	}
+	quic := false
+	for _, u := range urls {
+		quic = quic || u.Scheme == "quic"
+	}
+	if !quic {
+		return nil
+	}
+	if !cfg.ServerFeatureGate.Enabled(features.QUICClientListener) {
+		return fmt.Errorf("quic client urls require enabling feature gate %s", features.QUICClientListener)
+	}
+	for _, u := range cfg.ListenClientHttpUrls {
+		if u.Scheme == "quic" {
+			return fmt.Errorf("--listen-client-http-urls %s cannot use the quic scheme", u.String())
+		}
+	}
+	for _, u := range cfg.ListenClientUrls {
+		if u.Scheme != "quic" {
+			return nil
+		}
+	}
+	return fmt.Errorf("--listen-client-urls must include a non-quic url")
+}
+
+// validateNamespaces returns an error if a namespace name cannot be part of
+// a key prefix, or a namespace key quota names an unknown namespace.
+func validateNamespaces(namespaces []string, keyPrefix string, quotas []storage.KeyQuota) error {
+	if len(namespaces) > 0 && keyPrefix == "" {
+		return fmt.Errorf("--namespaces-key-prefix must not be empty")
+	}
+	for i, ns := range namespaces {
+		if ns == "" || strings.Contains(ns, "/") {
+			return fmt.Errorf("invalid namespace %q in --namespaces: must be non-empty and must not contain '/'", ns)
+		}
+		if slices.Contains(namespaces[:i], ns) {
+			return fmt.Errorf("duplicate namespace %q in --namespaces", ns)
+		}
+	}
+	for _, q := range quotas {
+		if q.Kind == storage.KeyQuotaNamespace && !slices.Contains(namespaces, q.Name) {
+			return fmt.Errorf("--key-quotas: unknown namespace %q", q.Name)
+		}
+	}
+	return nil
+}
+
 // checkBindURLs returns an error if any URL uses a domain name.
 func checkBindURLs(urls []url.URL) error {
 	for _, url := range urls {
@@ -1413,3 +2540,40 @@ func parseBackendFreelistType(freelistType string) bolt.FreelistType {
 
 	return bolt.FreelistMapType
 }
+
+// validateProxyProtocol returns an error if the PROXY protocol is enabled on
+// the client or peer listeners without trusted networks, since any client
+// could then claim any address.
+func validateProxyProtocol(kind string, enabled bool, cidrs []string) error {
+	trusted, err := parseCIDRs(cidrs)
+	if err != nil {
+		return fmt.Errorf("--%s-proxy-protocol-trusted-cidrs: %w", kind, err)
+	}
+	if enabled && len(trusted) == 0 {
+		return fmt.Errorf("--%s-proxy-protocol requires --%s-proxy-protocol-trusted-cidrs", kind, kind)
+	}
+	return nil
+}
+
+// proxyProtocolTrusted returns the networks the PROXY protocol header is read
+// from, none if the PROXY protocol is disabled.
+func proxyProtocolTrusted(enabled bool, cidrs []string) []*net.IPNet {
+	if !enabled {
+		return nil
+	}
+	// the networks are checked by Validate.
+	trusted, _ := parseCIDRs(cidrs)
+	return trusted
+}
+
+func parseCIDRs(cidrs []string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
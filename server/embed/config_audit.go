@@ -0,0 +1,108 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"go.etcd.io/etcd/client/pkg/v3/logutil"
+)
+
+// SyslogAuditLogOutput sends audit records to the local syslog daemon.
+const SyslogAuditLogOutput = "syslog"
+
+func validateAuditLogConfig(cfg *Config) error {
+	hasFile := false
+	for _, v := range cfg.AuditLogOutputs {
+		switch v {
+		case "":
+			return fmt.Errorf("--audit-log-outputs must not contain an empty output")
+		case StdErrLogOutput, StdOutLogOutput, SyslogAuditLogOutput:
+		default:
+			hasFile = true
+		}
+	}
+	if !hasFile {
+		return nil
+	}
+	var rotation lumberjack.Logger
+	if err := json.Unmarshal([]byte(cfg.AuditLogRotationConfigJSON), &rotation); err != nil {
+		return fmt.Errorf("invalid --audit-log-rotation-config-json: %w", err)
+	}
+	return nil
+}
+
+// newAuditLogger builds the JSON logger audit records are written to. Every
+// file output is rotated according to AuditLogRotationConfigJSON. The
+// returned function flushes and closes all outputs.
+func newAuditLogger(cfg *Config) (*zap.Logger, func(), error) {
+	var (
+		syncers []zapcore.WriteSyncer
+		closers []io.Closer
+	)
+	closeAll := func() {
+		for _, c := range closers {
+			c.Close()
+		}
+	}
+	for _, v := range cfg.AuditLogOutputs {
+		switch v {
+		case StdErrLogOutput:
+			syncers = append(syncers, zapcore.Lock(os.Stderr))
+		case StdOutLogOutput:
+			syncers = append(syncers, zapcore.Lock(os.Stdout))
+		case SyslogAuditLogOutput:
+			w, err := newAuditSyslogWriter()
+			if err != nil {
+				closeAll()
+				return nil, nil, fmt.Errorf("failed to connect to syslog for audit logging: %w", err)
+			}
+			syncers = append(syncers, zapcore.AddSync(w))
+			closers = append(closers, w)
+		default:
+			rotation := &lumberjack.Logger{}
+			if err := json.Unmarshal([]byte(cfg.AuditLogRotationConfigJSON), rotation); err != nil {
+				closeAll()
+				return nil, nil, fmt.Errorf("invalid --audit-log-rotation-config-json: %w", err)
+			}
+			rotation.Filename = v
+			syncers = append(syncers, zapcore.AddSync(rotation))
+			closers = append(closers, rotation)
+		}
+	}
+	if len(syncers) == 0 {
+		return nil, nil, errors.New("no audit log outputs configured")
+	}
+
+	encoderConfig := logutil.DefaultZapLoggerConfig.EncoderConfig
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(encoderConfig),
+		zapcore.NewMultiWriteSyncer(syncers...),
+		zapcore.InfoLevel,
+	)
+	lg := zap.New(core)
+	return lg, func() {
+		lg.Sync()
+		closeAll()
+	}, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embed
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestValidateAuditLogConfig(t *testing.T) {
+	tcs := []struct {
+		name           string
+		outputs        []string
+		rotationConfig string
+		wantErr        bool
+	}{
+		{name: "disabled"},
+		{name: "stderr and syslog", outputs: []string{"stderr", "syslog"}},
+		{name: "file", outputs: []string{"/tmp/audit.log"}, rotationConfig: DefaultLogRotationConfig},
+		{name: "empty output", outputs: []string{""}, wantErr: true},
+		{name: "invalid rotation config", outputs: []string{"/tmp/audit.log"}, rotationConfig: `{"maxsize": "big"}`, wantErr: true},
+		{name: "rotation config ignored without file", outputs: []string{"stdout"}, rotationConfig: "{"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.AuditLogOutputs = tc.outputs
+			cfg.AuditLogRotationConfigJSON = tc.rotationConfig
+			err := validateAuditLogConfig(cfg)
+			if tc.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAuditLoggerFileOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "audit.log")
+	cfg := NewConfig()
+	cfg.AuditLogOutputs = []string{path}
+
+	lg, closeAuditLogger, err := newAuditLogger(cfg)
+	require.NoError(t, err)
+	lg.Info("audit", zap.String("rpc", "/etcdserverpb.KV/Put"), zap.String("result", "success"))
+	closeAuditLogger()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 1)
+	var record map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &record))
+	assert.Equal(t, "audit", record["msg"])
+	assert.Equal(t, "/etcdserverpb.KV/Put", record["rpc"])
+	assert.Equal(t, "success", record["result"])
+	assert.Contains(t, record, "ts")
+}
@@ -113,11 +113,17 @@ func (cfg *Config) setupLogging() error {
 			}
 			copied.Encoding = encoding
 			if cfg.ZapLoggerBuilder == nil {
-				lg, err := copied.Build()
+				// the level and the sampling are applied by the log control
+				lc := newLogControl(copied.Level, copied.Sampling)
+				copied.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
+				copied.Sampling = nil
+				lg, err := copied.Build(zap.WrapCore(lc.wrap))
 				if err != nil {
 					return err
 				}
 				cfg.ZapLoggerBuilder = NewZapLoggerBuilder(lg)
+				cfg.logLevel = lc.level
+				cfg.logControl = lc
 			}
 		} else {
 			if len(cfg.LogOutputs) > 1 {
@@ -153,10 +159,13 @@ func (cfg *Config) setupLogging() error {
 			cr := zapcore.NewCore(
 				encoder,
 				syncer,
-				lvl,
+				zapcore.DebugLevel,
 			)
 			if cfg.ZapLoggerBuilder == nil {
-				cfg.ZapLoggerBuilder = NewZapLoggerBuilder(zap.New(cr, zap.AddCaller(), zap.ErrorOutput(syncer)))
+				lc := newLogControl(lvl, nil)
+				cfg.ZapLoggerBuilder = NewZapLoggerBuilder(zap.New(lc.wrap(cr), zap.AddCaller(), zap.ErrorOutput(syncer)))
+				cfg.logLevel = lvl
+				cfg.logControl = lc
 			}
 		}
 
@@ -0,0 +1,249 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embed
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"go.etcd.io/etcd/server/v3/etcdserver/api/etcdhttp"
+)
+
+// logSubsystems are the subsystems that can log at another level than the
+// logger of the member, by the name of their logger.
+var logSubsystems = []string{"auth", "mvcc", "raft"}
+
+// logControl controls the logger of a member at runtime: its level, the
+// levels of its subsystems and its sampling.
+type logControl struct {
+	level zap.AtomicLevel
+
+	mu sync.Mutex
+	// subsystems are the levels of the subsystems logging at another level
+	// than level, replaced on change.
+	subsystems atomic.Pointer[map[string]zapcore.Level]
+	// minSubsystemLevel is the lowest level of subsystems.
+	minSubsystemLevel atomic.Int32
+	sampler           atomic.Pointer[logSampler]
+}
+
+func newLogControl(level zap.AtomicLevel, sampling *zap.SamplingConfig) *logControl {
+	lc := &logControl{level: level}
+	lc.subsystems.Store(&map[string]zapcore.Level{})
+	lc.minSubsystemLevel.Store(int32(zapcore.InvalidLevel))
+	if sampling != nil {
+		lc.sampler.Store(newLogSampler(sampling.Initial, sampling.Thereafter))
+	}
+	return lc
+}
+
+// wrap returns core logging through lc. The level of core must be the
+// lowest level, lc filters the entries.
+func (lc *logControl) wrap(core zapcore.Core) zapcore.Core {
+	return &controlCore{Core: core, lc: lc}
+}
+
+// enabled reports whether any subsystem logs at lvl.
+func (lc *logControl) enabled(lvl zapcore.Level) bool {
+	return lc.level.Enabled(lvl) || zapcore.Level(lc.minSubsystemLevel.Load()) <= lvl
+}
+
+// enabledFor reports whether the logger named name logs at lvl.
+func (lc *logControl) enabledFor(name string, lvl zapcore.Level) bool {
+	for s, l := range *lc.subsystems.Load() {
+		if name == s || strings.HasPrefix(name, s+".") {
+			return l <= lvl
+		}
+	}
+	return lc.level.Enabled(lvl)
+}
+
+func (lc *logControl) settings() etcdhttp.LogSettings {
+	s := etcdhttp.LogSettings{Level: lc.level.Level().String()}
+	for name, l := range *lc.subsystems.Load() {
+		if s.Subsystems == nil {
+			s.Subsystems = make(map[string]string)
+		}
+		s.Subsystems[name] = l.String()
+	}
+	if sampler := lc.sampler.Load(); sampler != nil {
+		s.Sampling = &etcdhttp.LogSampling{Initial: int(sampler.initial), Thereafter: int(sampler.thereafter)}
+	}
+	return s
+}
+
+// setSettings changes the settings set in s, see
+// etcdhttp.LogController.SetLogSettings. Nothing is changed if a setting of
+// s is invalid.
+func (lc *logControl) setSettings(s etcdhttp.LogSettings) error {
+	var lvl zapcore.Level
+	if s.Level != "" {
+		if err := lvl.Set(s.Level); err != nil {
+			return fmt.Errorf("invalid log level: %w", err)
+		}
+	}
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	subsystems := maps.Clone(*lc.subsystems.Load())
+	for name, l := range s.Subsystems {
+		if !slices.Contains(logSubsystems, name) {
+			return fmt.Errorf("unknown log subsystem %q, expected one of %v", name, logSubsystems)
+		}
+		if l == "" {
+			delete(subsystems, name)
+			continue
+		}
+		var sl zapcore.Level
+		if err := sl.Set(l); err != nil {
+			return fmt.Errorf("invalid log level of subsystem %q: %w", name, err)
+		}
+		subsystems[name] = sl
+	}
+	if s.Sampling != nil && (s.Sampling.Initial < 0 || s.Sampling.Thereafter < 0) {
+		return errors.New("log sampling must not be negative")
+	}
+
+	if s.Level != "" {
+		lc.level.SetLevel(lvl)
+	}
+	minLevel := zapcore.InvalidLevel
+	for _, l := range subsystems {
+		minLevel = min(minLevel, l)
+	}
+	lc.subsystems.Store(&subsystems)
+	lc.minSubsystemLevel.Store(int32(minLevel))
+	if s.Sampling != nil {
+		if s.Sampling.Initial == 0 {
+			lc.sampler.Store(nil)
+		} else {
+			lc.sampler.Store(newLogSampler(s.Sampling.Initial, s.Sampling.Thereafter))
+		}
+	}
+	return nil
+}
+
+// controlCore logs the entries of Core enabled and sampled by lc.
+type controlCore struct {
+	zapcore.Core
+	lc *logControl
+}
+
+func (c *controlCore) Enabled(lvl zapcore.Level) bool {
+	return c.lc.enabled(lvl)
+}
+
+// Level returns the lowest level logged, see zapcore.LevelOf.
+func (c *controlCore) Level() zapcore.Level {
+	return min(c.lc.level.Level(), zapcore.Level(c.lc.minSubsystemLevel.Load()))
+}
+
+func (c *controlCore) With(fields []zapcore.Field) zapcore.Core {
+	return &controlCore{Core: c.Core.With(fields), lc: c.lc}
+}
+
+func (c *controlCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.lc.enabledFor(ent.LoggerName, ent.Level) {
+		return ce
+	}
+	if s := c.lc.sampler.Load(); s != nil && !s.sample(ent) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+const logSamplerBuckets = 4096
+
+// logSampler samples the entries by level and message the way the zap
+// sampler does, with a tick of one second.
+type logSampler struct {
+	initial, thereafter uint64
+	counts              [zapcore.FatalLevel - zapcore.DebugLevel + 1][logSamplerBuckets]logSampleCounter
+}
+
+type logSampleCounter struct {
+	resetAt atomic.Int64
+	count   atomic.Uint64
+}
+
+func newLogSampler(initial, thereafter int) *logSampler {
+	return &logSampler{initial: uint64(initial), thereafter: uint64(thereafter)}
+}
+
+// sample reports whether ent is logged.
+func (s *logSampler) sample(ent zapcore.Entry) bool {
+	if ent.Level < zapcore.DebugLevel || ent.Level > zapcore.FatalLevel {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(ent.Message))
+	c := &s.counts[ent.Level-zapcore.DebugLevel][h.Sum32()%logSamplerBuckets]
+	n := c.inc(ent.Time)
+	return n <= s.initial || (s.thereafter > 0 && (n-s.initial)%s.thereafter == 0)
+}
+
+func (c *logSampleCounter) inc(t time.Time) uint64 {
+	now := t.UnixNano()
+	resetAt := c.resetAt.Load()
+	if resetAt > now {
+		return c.count.Add(1)
+	}
+	c.count.Store(1)
+	if !c.resetAt.CompareAndSwap(resetAt, now+time.Second.Nanoseconds()) {
+		return c.count.Add(1)
+	}
+	return 1
+}
+
+// LogSettings returns the settings of the logger of e that can be changed at
+// runtime.
+func (e *Etcd) LogSettings() etcdhttp.LogSettings {
+	if e.cfg.logControl == nil {
+		return etcdhttp.LogSettings{}
+	}
+	return e.cfg.logControl.settings()
+}
+
+// SetLogSettings changes the settings of the logger of e at runtime, see
+// etcdhttp.LogController.
+func (e *Etcd) SetLogSettings(s etcdhttp.LogSettings) error {
+	if e.cfg.logControl == nil {
+		return errors.New("the settings of a custom logger cannot be changed")
+	}
+	e.reloadMu.Lock()
+	defer e.reloadMu.Unlock()
+	if err := e.cfg.logControl.setSettings(s); err != nil {
+		return err
+	}
+	if s.Level != "" {
+		e.cfg.LogLevel = e.cfg.logControl.level.Level().String()
+	}
+	current := e.cfg.logControl.settings()
+	fields := []zap.Field{zap.String("log-level", current.Level), zap.Any("subsystems", current.Subsystems)}
+	if current.Sampling != nil {
+		fields = append(fields, zap.Int("sampling-initial", current.Sampling.Initial), zap.Int("sampling-thereafter", current.Sampling.Thereafter))
+	}
+	e.GetLogger().Info("changed log settings", fields...)
+	return nil
+}
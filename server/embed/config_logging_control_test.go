@@ -0,0 +1,81 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embed
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"go.etcd.io/etcd/server/v3/etcdserver/api/etcdhttp"
+)
+
+func TestLogControl(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	lc := newLogControl(zap.NewAtomicLevelAt(zapcore.InfoLevel), nil)
+	lg := zap.New(lc.wrap(core))
+	raft, mvcc := lg.Named("raft"), lg.Named("mvcc").With(zap.String("k", "v"))
+
+	count := func() int { return len(logs.TakeAll()) }
+
+	lg.Debug("debug")
+	raft.Debug("debug")
+	mvcc.Info("info")
+	assert.Equal(t, 1, count())
+
+	require.NoError(t, lc.setSettings(etcdhttp.LogSettings{Subsystems: map[string]string{"raft": "debug", "mvcc": "warn"}}))
+	lg.Debug("debug")
+	raft.Debug("debug")
+	raft.Named("node").Debug("debug")
+	mvcc.Info("info")
+	assert.Equal(t, 2, count())
+	assert.Equal(t, etcdhttp.LogSettings{Level: "info", Subsystems: map[string]string{"raft": "debug", "mvcc": "warn"}}, lc.settings())
+
+	require.NoError(t, lc.setSettings(etcdhttp.LogSettings{Level: "error", Subsystems: map[string]string{"raft": ""}}))
+	raft.Info("info")
+	lg.Warn("warn")
+	mvcc.Warn("warn")
+	assert.Equal(t, 1, count())
+
+	// an invalid setting leaves the settings unchanged
+	require.Error(t, lc.setSettings(etcdhttp.LogSettings{Level: "debug", Subsystems: map[string]string{"wal": "debug"}}))
+	require.Error(t, lc.setSettings(etcdhttp.LogSettings{Level: "debug", Sampling: &etcdhttp.LogSampling{Initial: -1}}))
+	assert.Equal(t, etcdhttp.LogSettings{Level: "error", Subsystems: map[string]string{"mvcc": "warn"}}, lc.settings())
+}
+
+func TestLogControlSampling(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	lc := newLogControl(zap.NewAtomicLevelAt(zapcore.InfoLevel), &zap.SamplingConfig{Initial: 2, Thereafter: 3})
+	lg := zap.New(lc.wrap(core))
+
+	for i := 0; i < 10; i++ {
+		lg.Info("sampled")
+	}
+	lg.Info("other")
+	// the entries 1, 2, 5 and 8 of the message are logged
+	assert.Equal(t, 4, logs.FilterMessage("sampled").Len())
+	assert.Equal(t, 1, logs.FilterMessage("other").Len())
+
+	require.NoError(t, lc.setSettings(etcdhttp.LogSettings{Sampling: &etcdhttp.LogSampling{}}))
+	assert.Nil(t, lc.settings().Sampling)
+	for i := 0; i < 10; i++ {
+		lg.Info("unsampled")
+	}
+	assert.Equal(t, 10, logs.FilterMessage("unsampled").Len())
+}
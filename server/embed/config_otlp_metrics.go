@@ -0,0 +1,95 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embed
+
+import (
+	"context"
+	"fmt"
+
+	otelprom "go.opentelemetry.io/contrib/bridges/prometheus"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	metricsdk "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.uber.org/zap"
+)
+
+func validateOTLPMetricsConfig(cfg *Config) error {
+	if cfg.OTLPMetricsAddress == "" {
+		return fmt.Errorf("--otlp-metrics-address must be set")
+	}
+	if cfg.OTLPMetricsInterval <= 0 {
+		return fmt.Errorf("--otlp-metrics-interval must be positive (set to %v)", cfg.OTLPMetricsInterval)
+	}
+	return nil
+}
+
+// otlpMetricsExporter periodically pushes every metric registered with the
+// default prometheus registry, i.e. everything exposed on /metrics, to an
+// OpenTelemetry Collector.
+type otlpMetricsExporter struct {
+	provider *metricsdk.MeterProvider
+}
+
+func newOTLPMetricsExporter(ctx context.Context, cfg *Config) (*otlpMetricsExporter, error) {
+	exporter, err := otlpmetricgrpc.New(ctx,
+		otlpmetricgrpc.WithInsecure(),
+		otlpmetricgrpc.WithEndpoint(cfg.OTLPMetricsAddress),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String(cfg.DistributedTracingServiceName),
+		),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if resWithIDKey := determineResourceWithIDKey(cfg.DistributedTracingServiceInstanceID); resWithIDKey != nil {
+		res, err = resource.Merge(res, resWithIDKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	reader := metricsdk.NewPeriodicReader(exporter,
+		metricsdk.WithInterval(cfg.OTLPMetricsInterval),
+		metricsdk.WithProducer(otelprom.NewMetricProducer()),
+	)
+	provider := metricsdk.NewMeterProvider(
+		metricsdk.WithReader(reader),
+		metricsdk.WithResource(res),
+	)
+
+	cfg.logger.Debug(
+		"otlp metrics export enabled",
+		zap.String("address", cfg.OTLPMetricsAddress),
+		zap.Duration("interval", cfg.OTLPMetricsInterval),
+		zap.String("service-name", cfg.DistributedTracingServiceName),
+		zap.String("service-instance-id", cfg.DistributedTracingServiceInstanceID),
+	)
+
+	return &otlpMetricsExporter{provider: provider}, nil
+}
+
+// Close pushes the metrics one last time and stops the exporter.
+func (me *otlpMetricsExporter) Close(ctx context.Context) {
+	if me.provider != nil {
+		me.provider.Shutdown(ctx)
+	}
+}
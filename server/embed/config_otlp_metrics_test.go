@@ -0,0 +1,119 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embed
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	collectormetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+)
+
+func TestOTLPMetricsConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		interval time.Duration
+		wantErr  bool
+	}{
+		{
+			name:     "valid - default",
+			address:  DefaultOTLPMetricsAddress,
+			interval: DefaultOTLPMetricsInterval,
+		},
+		{
+			name:     "invalid - address is empty",
+			interval: DefaultOTLPMetricsInterval,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid - interval is not positive",
+			address:  DefaultOTLPMetricsAddress,
+			interval: 0,
+			wantErr:  true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.OTLPMetricsAddress = tc.address
+			cfg.OTLPMetricsInterval = tc.interval
+			err := validateOTLPMetricsConfig(cfg)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+type fakeMetricsCollector struct {
+	collectormetricspb.UnimplementedMetricsServiceServer
+
+	mu      sync.Mutex
+	metrics map[string]struct{}
+}
+
+func (c *fakeMetricsCollector) Export(_ context.Context, req *collectormetricspb.ExportMetricsServiceRequest) (*collectormetricspb.ExportMetricsServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rm := range req.GetResourceMetrics() {
+		for _, sm := range rm.GetScopeMetrics() {
+			for _, m := range sm.GetMetrics() {
+				c.metrics[m.GetName()] = struct{}{}
+			}
+		}
+	}
+	return &collectormetricspb.ExportMetricsServiceResponse{}, nil
+}
+
+func (c *fakeMetricsCollector) has(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.metrics[name]
+	return ok
+}
+
+func TestOTLPMetricsExporter(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	collector := &fakeMetricsCollector{metrics: make(map[string]struct{})}
+	srv := grpc.NewServer()
+	collectormetricspb.RegisterMetricsServiceServer(srv, collector)
+	go srv.Serve(ln)
+	defer srv.Stop()
+
+	cfg := NewConfig()
+	cfg.logger = zaptest.NewLogger(t)
+	cfg.OTLPMetricsAddress = ln.Addr().String()
+	cfg.OTLPMetricsInterval = 50 * time.Millisecond
+	ctx := context.Background()
+	exporter, err := newOTLPMetricsExporter(ctx, cfg)
+	require.NoError(t, err)
+	defer exporter.Close(ctx)
+
+	// metrics registered with the default prometheus registry are pushed
+	require.Eventually(t, func() bool {
+		return collector.has("etcd_server_health_success")
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,131 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embed
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const configReloadPath = "/config/reload"
+
+var ErrNoConfigFile = errors.New("etcd was not configured from a configuration file")
+
+// ReloadConfig applies the settings of cfg that can be changed without
+// restarting the member: the log level, the TTL of the simple auth tokens,
+// the snapshot send rate limits, the auto compaction mode and retention, and
+// the CORS origins. The other settings of cfg are ignored.
+func (e *Etcd) ReloadConfig(cfg *Config) error {
+	var lvl zapcore.Level
+	if err := lvl.Set(cfg.LogLevel); err != nil {
+		return fmt.Errorf("invalid --log-level: %w", err)
+	}
+	if cfg.SnapshotSendRateLimit < 0 {
+		return fmt.Errorf("--snapshot-send-rate-limit must not be negative (set to %d)", cfg.SnapshotSendRateLimit)
+	}
+	if cfg.PeerSnapshotSendRateLimit < 0 {
+		return fmt.Errorf("--peer-snapshot-send-rate-limit must not be negative (set to %d)", cfg.PeerSnapshotSendRateLimit)
+	}
+	retention := cfg.AutoCompactionRetention
+	if retention == "" {
+		retention = "0"
+	}
+	compactionRetention, err := parseCompactionRetention(cfg.AutoCompactionMode, retention)
+	if err != nil {
+		return err
+	}
+
+	e.reloadMu.Lock()
+	defer e.reloadMu.Unlock()
+	lg := e.GetLogger()
+	var reloaded []string
+
+	if cfg.LogLevel != e.cfg.LogLevel {
+		if e.cfg.logLevel == (zap.AtomicLevel{}) {
+			return errors.New("the log level of a custom logger cannot be reloaded")
+		}
+		e.cfg.logLevel.SetLevel(lvl)
+		e.cfg.LogLevel = cfg.LogLevel
+		reloaded = append(reloaded, "log-level")
+	}
+	if cfg.AuthTokenTTL != e.cfg.AuthTokenTTL {
+		e.Server.SetAuthTokenTTL(time.Duration(cfg.AuthTokenTTL) * time.Second)
+		e.cfg.AuthTokenTTL = cfg.AuthTokenTTL
+		reloaded = append(reloaded, "auth-token-ttl")
+	}
+	if cfg.SnapshotSendRateLimit != e.cfg.SnapshotSendRateLimit {
+		e.Server.SetSnapshotSendRateLimit(cfg.SnapshotSendRateLimit)
+		e.cfg.SnapshotSendRateLimit = cfg.SnapshotSendRateLimit
+		reloaded = append(reloaded, "snapshot-send-rate-limit")
+	}
+	if cfg.PeerSnapshotSendRateLimit != e.cfg.PeerSnapshotSendRateLimit {
+		e.Server.SetPeerSnapshotSendRateLimit(cfg.PeerSnapshotSendRateLimit)
+		e.cfg.PeerSnapshotSendRateLimit = cfg.PeerSnapshotSendRateLimit
+		reloaded = append(reloaded, "peer-snapshot-send-rate-limit")
+	}
+	if cfg.AutoCompactionMode != e.cfg.AutoCompactionMode || retention != e.cfg.AutoCompactionRetention {
+		if err := e.Server.SetAutoCompaction(cfg.AutoCompactionMode, compactionRetention); err != nil {
+			return err
+		}
+		e.cfg.AutoCompactionMode, e.cfg.AutoCompactionRetention = cfg.AutoCompactionMode, retention
+		reloaded = append(reloaded, "auto-compaction-mode", "auto-compaction-retention")
+	}
+	if !maps.Equal(cfg.CORS, e.cfg.CORS) {
+		e.Server.AccessController.SetCORS(maps.Clone(cfg.CORS))
+		e.cfg.CORS = maps.Clone(cfg.CORS)
+		reloaded = append(reloaded, "cors")
+	}
+
+	lg.Info("reloaded configuration", zap.Strings("changed", reloaded))
+	return nil
+}
+
+// ReloadConfigFile reloads the configuration file the configuration of e was
+// loaded from with ReloadConfig.
+func (e *Etcd) ReloadConfigFile() error {
+	if e.cfg.configFile == "" {
+		return ErrNoConfigFile
+	}
+	cfg := &configYAML{Config: *NewConfig()}
+	// the running logger is kept, the reload only changes its level.
+	cfg.ZapLoggerBuilder = NewZapLoggerBuilder(e.GetLogger())
+	if err := cfg.configFromFile(e.cfg.configFile); err != nil {
+		return fmt.Errorf("cannot load %q: %w", e.cfg.configFile, err)
+	}
+	return e.ReloadConfig(&cfg.Config)
+}
+
+// configReloadHandler reloads the configuration file on POST requests.
+func (e *Etcd) configReloadHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err := e.ReloadConfigFile(); err != nil {
+			e.GetLogger().Warn("failed to reload configuration", zap.Error(err))
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embed
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/time/rate"
+)
+
+func TestReloadConfigFile(t *testing.T) {
+	cfg := NewConfig()
+	urls := newEmbedURLs(2)
+	curls := []url.URL{urls[0]}
+	purls := []url.URL{urls[1]}
+	cfg.ListenClientUrls, cfg.AdvertiseClientUrls = curls, curls
+	cfg.ListenPeerUrls, cfg.AdvertisePeerUrls = purls, purls
+	cfg.InitialCluster = "default=" + purls[0].String()
+	cfg.Dir = t.TempDir()
+
+	e, err := StartEtcd(cfg)
+	require.NoError(t, err)
+	defer e.Close()
+	<-e.Server.ReadyNotify()
+
+	require.ErrorIs(t, e.ReloadConfigFile(), ErrNoConfigFile)
+
+	path := filepath.Join(t.TempDir(), "etcd.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+log-level: debug
+cors: https://example.com:8443
+snapshot-send-rate-limit: 1048576
+auto-compaction-mode: revision
+auto-compaction-retention: "1000"
+`), 0o600))
+	e.cfg.configFile = path
+
+	w := httptest.NewRecorder()
+	e.configReloadHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, configReloadPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+
+	w = httptest.NewRecorder()
+	e.configReloadHandler().ServeHTTP(w, httptest.NewRequest(http.MethodPost, configReloadPath, nil))
+	require.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+
+	assert.Equal(t, zapcore.DebugLevel, e.cfg.logLevel.Level())
+	assert.True(t, e.Server.AccessController.OriginAllowed("https://example.com:8443"))
+	assert.False(t, e.Server.AccessController.OriginAllowed("https://example.org"))
+	assert.Equal(t, rate.Limit(1048576), e.Server.SnapshotSendLimiter().Limit())
+	assert.Equal(t, "revision", e.cfg.AutoCompactionMode)
+	assert.Equal(t, "1000", e.cfg.AutoCompactionRetention)
+
+	require.NoError(t, os.WriteFile(path, []byte("log-level: info\nsnapshot-send-rate-limit: -1\n"), 0o600))
+	require.Error(t, e.ReloadConfigFile())
+	assert.Equal(t, zapcore.DebugLevel, e.cfg.logLevel.Level())
+}
@@ -466,6 +466,12 @@ func TestAutoCompactionModeParse(t *testing.T) {
 		{"periodic", "1", false, time.Hour},
 		{"periodic", "a", true, 0},
 		{"revision", "-1", true, 0},
+		// size
+		{"size", "1000", false, 1000},
+		{"size", "1KiB", false, 1024},
+		{"size", "1GB", false, 1000 * 1000 * 1000},
+		{"size", "1h", true, 0},
+		{"size", "-1", true, 0},
 		// err mode
 		{"errmode", "1", false, 0},
 		{"errmode", "1h", false, time.Hour},
@@ -765,6 +771,95 @@ func TestTLSVersionMinMax(t *testing.T) {
 	}
 }
 
+func TestListenerTLSVersionsAndCipherSuites(t *testing.T) {
+	tests := []struct {
+		name        string
+		setup       func(cfg *Config)
+		expectError bool
+		client      listenerTLS
+		peer        listenerTLS
+		metrics     listenerTLS
+	}{
+		{
+			name: "Listeners inherit the global settings",
+			setup: func(cfg *Config) {
+				cfg.TlsMinVersion = "TLS1.2"
+				cfg.CipherSuites = []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}
+			},
+			client:  listenerTLS{"TLS1.2", "", []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}},
+			peer:    listenerTLS{"TLS1.2", "", []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}},
+			metrics: listenerTLS{"TLS1.2", "", []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}},
+		},
+		{
+			name: "Client TLS1.3 only with inherited cipher suites",
+			setup: func(cfg *Config) {
+				cfg.TlsMinVersion = "TLS1.2"
+				cfg.CipherSuites = []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}
+				cfg.ClientTLSMinVersion = "TLS1.3"
+			},
+			expectError: true,
+		},
+		{
+			name: "Client settings override the global ones",
+			setup: func(cfg *Config) {
+				cfg.TlsMinVersion = "TLS1.2"
+				cfg.ClientTLSMinVersion = "TLS1.3"
+				cfg.PeerTLSMaxVersion = "TLS1.2"
+				cfg.PeerCipherSuites = []string{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"}
+			},
+			client:  listenerTLS{"TLS1.3", "", nil},
+			peer:    listenerTLS{"TLS1.2", "TLS1.2", []string{"TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"}},
+			metrics: listenerTLS{"TLS1.3", "", nil},
+		},
+		{
+			name: "Metrics settings override the client ones",
+			setup: func(cfg *Config) {
+				cfg.ClientTLSMinVersion = "TLS1.3"
+				cfg.MetricsTLSMinVersion = "TLS1.2"
+				cfg.MetricsCipherSuites = []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}
+			},
+			client:  listenerTLS{"TLS1.3", "", nil},
+			peer:    listenerTLS{"TLS1.2", "", nil},
+			metrics: listenerTLS{"TLS1.2", "", []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"}},
+		},
+		{
+			name:        "Invalid peer TLS version",
+			setup:       func(cfg *Config) { cfg.PeerTLSMinVersion = "invalid version" },
+			expectError: true,
+		},
+		{
+			name: "Client min version greater than max version",
+			setup: func(cfg *Config) {
+				cfg.TlsMaxVersion = "TLS1.2"
+				cfg.ClientTLSMinVersion = "TLS1.3"
+			},
+			expectError: true,
+		},
+		{
+			name:        "Invalid metrics cipher suite",
+			setup:       func(cfg *Config) { cfg.MetricsCipherSuites = []string{"invalid cipher"} },
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			tt.setup(cfg)
+
+			err := cfg.Validate()
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.client, cfg.clientListenerTLS())
+			assert.Equal(t, tt.peer, cfg.peerListenerTLS())
+			assert.Equal(t, tt.metrics, cfg.metricsListenerTLS())
+		})
+	}
+}
+
 func TestUndefinedAutoCompactionModeValidate(t *testing.T) {
 	cfg := *NewConfig()
 	cfg.AutoCompactionMode = ""
@@ -772,6 +867,126 @@ func TestUndefinedAutoCompactionModeValidate(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestGRPCHistogramBucketsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		metrics string
+		buckets []float64
+		wantErr bool
+	}{
+		{name: "default buckets", metrics: "basic"},
+		{name: "custom buckets", metrics: "extensive", buckets: []float64{0.0001, 0.0005, 0.001}},
+		{name: "custom buckets without extensive metrics", metrics: "basic", buckets: []float64{0.0001}, wantErr: true},
+		{name: "non-positive bucket", metrics: "extensive", buckets: []float64{0, 0.001}, wantErr: true},
+		{name: "buckets not increasing", metrics: "extensive", buckets: []float64{0.001, 0.001}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := *NewConfig()
+			cfg.Metrics = tt.metrics
+			cfg.GRPCHistogramBuckets = tt.buckets
+			err := cfg.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLogSlowRequestsValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		above      time.Duration
+		initial    int
+		thereafter int
+		wantErr    bool
+	}{
+		{name: "disabled", initial: DefaultLogSlowRequestsSampleInitial, thereafter: DefaultLogSlowRequestsSampleThereafter},
+		{name: "enabled", above: time.Second, initial: 1, thereafter: 0},
+		{name: "negative threshold", above: -time.Second, initial: 1, wantErr: true},
+		{name: "no initial sample", above: time.Second, initial: 0, wantErr: true},
+		{name: "negative thereafter", above: time.Second, initial: 1, thereafter: -1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := *NewConfig()
+			cfg.LogSlowRequestsAbove = tt.above
+			cfg.LogSlowRequestsSampleInitial = tt.initial
+			cfg.LogSlowRequestsSampleThereafter = tt.thereafter
+			err := cfg.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestRevocationCheckValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		clientOCSP string
+		peerOCSP   string
+		refresh    time.Duration
+		wantErr    bool
+	}{
+		{name: "disabled"},
+		{name: "enabled", clientOCSP: transport.OCSPCheckSoftFail, peerOCSP: transport.OCSPCheckHardFail, refresh: time.Minute},
+		{name: "unknown client mode", clientOCSP: "strict", wantErr: true},
+		{name: "unknown peer mode", peerOCSP: "strict", wantErr: true},
+		{name: "negative refresh interval", refresh: -time.Minute, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := *NewConfig()
+			cfg.ClientTLSInfo.OCSPCheck = tt.clientOCSP
+			cfg.PeerTLSInfo.OCSPCheck = tt.peerOCSP
+			cfg.PeerTLSInfo.CRLRefreshInterval = tt.refresh
+			err := cfg.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProxyProtocolValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		clientProxy bool
+		clientCIDRs []string
+		peerProxy   bool
+		peerCIDRs   []string
+		wantErr     bool
+	}{
+		{name: "disabled"},
+		{name: "enabled", clientProxy: true, clientCIDRs: []string{"10.0.0.0/8"}, peerProxy: true, peerCIDRs: []string{"192.0.2.0/24", "2001:db8::/32"}},
+		{name: "client without trusted networks", clientProxy: true, wantErr: true},
+		{name: "peer without trusted networks", peerProxy: true, wantErr: true},
+		{name: "invalid network", clientProxy: true, clientCIDRs: []string{"10.0.0.1"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := *NewConfig()
+			cfg.ClientProxyProtocol = tt.clientProxy
+			cfg.ClientProxyProtocolTrustedCIDRs = tt.clientCIDRs
+			cfg.PeerProxyProtocol = tt.peerProxy
+			cfg.PeerProxyProtocolTrustedCIDRs = tt.peerCIDRs
+			err := cfg.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestMatchNewConfigAddFlags(t *testing.T) {
 	cfg := NewConfig()
 	fs := flag.NewFlagSet("etcd", flag.ContinueOnError)
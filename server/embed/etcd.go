@@ -31,6 +31,7 @@ import (
 	"sync"
 	"time"
 
+	humanize "github.com/dustin/go-humanize"
 	"github.com/soheilhy/cmux"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -45,10 +46,12 @@ import (
 	runtimeutil "go.etcd.io/etcd/pkg/v3/runtime"
 	"go.etcd.io/etcd/server/v3/config"
 	"go.etcd.io/etcd/server/v3/etcdserver"
+	"go.etcd.io/etcd/server/v3/etcdserver/admission"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/etcdhttp"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/rafthttp"
 	"go.etcd.io/etcd/server/v3/features"
 	"go.etcd.io/etcd/server/v3/storage"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
 	"go.etcd.io/etcd/server/v3/verify"
 )
 
@@ -75,11 +78,20 @@ type Etcd struct {
 	metricsListeners []net.Listener
 
 	tracingExporterShutdown func()
+	// otlpMetricsExporterShutdown pushes the metrics one last time and
+	// stops the exporter.
+	otlpMetricsExporterShutdown func()
+
+	// auditLoggerClose flushes and closes the audit log outputs.
+	auditLoggerClose func()
 
 	Server *etcdserver.EtcdServer
 
 	cfg Config
 
+	// reloadMu serializes the reloads of the configuration.
+	reloadMu sync.Mutex
+
 	// closeOnce is to ensure `stopc` is closed only once, no matter
 	// how many times the Close() method is called.
 	closeOnce sync.Once
@@ -102,6 +114,9 @@ type peerListener struct {
 	net.Listener
 	serve func() error
 	close func(context.Context) error
+	// connTracker tracks the connections of the listener to close them
+	// when the certificates are renewed, nil if not tracked.
+	connTracker *transport.ConnTracker
 }
 
 // StartEtcd launches the etcd server and HTTP handlers for client/server communication.
@@ -128,6 +143,14 @@ func StartEtcd(inCfg *Config) (e *Etcd, err error) {
 		e = nil
 	}()
 
+	if cfg.EnableSocketActivation {
+		if cfg.listenFDs, err = newListenFDs(cfg.logger); err != nil {
+			return e, err
+		}
+		// the inherited listeners are taken until the metrics listeners are
+		// created, the remaining ones match no listen URL.
+		defer cfg.listenFDs.closeUnused(cfg.logger)
+	}
 	if !cfg.SocketOpts.Empty() {
 		cfg.logger.Info(
 			"configuring socket options",
@@ -187,8 +210,14 @@ func StartEtcd(inCfg *Config) (e *Etcd, err error) {
 		DedicatedWALDir:                   cfg.WalDir,
 		SnapshotCount:                     cfg.SnapshotCount,
 		SnapshotCatchUpEntries:            cfg.SnapshotCatchUpEntries,
+		SnapshotSendRateLimit:             cfg.SnapshotSendRateLimit,
+		PeerSnapshotSendRateLimit:         cfg.PeerSnapshotSendRateLimit,
+		PeerAddressRefreshInterval:        cfg.PeerAddressRefreshInterval,
+		PeerNetworkFaults:                 cfg.PeerNetworkFaults,
+		PeerNetworkFaultsSeed:             cfg.PeerNetworkFaultsSeed,
 		MaxSnapFiles:                      cfg.MaxSnapFiles,
 		MaxWALFiles:                       cfg.MaxWalFiles,
+		WALCompression:                    cfg.WALCompression,
 		InitialPeerURLsMap:                urlsmap,
 		InitialClusterToken:               token,
 		DiscoveryURL:                      cfg.Durl,
@@ -201,35 +230,115 @@ func StartEtcd(inCfg *Config) (e *Etcd, err error) {
 		InitialElectionTickAdvance:        cfg.InitialElectionTickAdvance,
 		AutoCompactionRetention:           autoCompactionRetention,
 		AutoCompactionMode:                cfg.AutoCompactionMode,
+		CompactionControlKey:              cfg.CompactionControlKey,
 		QuotaBackendBytes:                 cfg.QuotaBackendBytes,
+		QuotaBackendGrace:                 cfg.QuotaBackendGrace,
+		QuotaBackendWarningThresholds:     cfg.QuotaBackendWarningThresholds,
+		KeyQuotas:                         cfg.KeyQuotas,
+		Namespaces:                        cfg.Namespaces,
+		NamespacesKeyPrefix:               cfg.NamespacesKeyPrefix,
 		BackendBatchLimit:                 cfg.BackendBatchLimit,
+		StorageEngine:                     cfg.ExperimentalStorageEngine,
 		BackendFreelistType:               backendFreelistType,
 		BackendBatchInterval:              cfg.BackendBatchInterval,
+		BackendBatchAdaptive:              cfg.BackendBatchAdaptive,
+		BackendBatchIntervalMin:           cfg.BackendBatchIntervalMin,
+		BackendBatchIntervalMax:           cfg.BackendBatchIntervalMax,
+		BackendBatchLimitMin:              cfg.BackendBatchLimitMin,
+		BackendBatchLimitMax:              cfg.BackendBatchLimitMax,
+		BackendReclaimFreePages:           cfg.BackendReclaimFreePages,
+		BackendReclaimThreshold:           cfg.BackendReclaimThreshold,
+		BackendReclaimInterval:            cfg.BackendReclaimInterval,
 		MaxTxnOps:                         cfg.MaxTxnOps,
 		MaxRequestBytes:                   cfg.MaxRequestBytes,
 		MaxConcurrentStreams:              cfg.MaxConcurrentStreams,
+		RequestDeadlineMargin:             cfg.RequestDeadlineMargin,
+		MaxConcurrentExpensiveRanges:      cfg.MaxConcurrentExpensiveRanges,
+		ExpensiveRangeLimit:               cfg.ExpensiveRangeLimit,
+		MaxCallerLabels:                   cfg.MaxCallerLabels,
+		ClientIdentityMetrics:             cfg.ClientIdentityMetrics,
+		ClientRateLimitQPS:                cfg.ClientRateLimitQPS,
+		ClientRateLimitBurst:              cfg.ClientRateLimitBurst,
+		ClientRateLimitBytes:              cfg.ClientRateLimitBytes,
+		QoSMaxInflightRequests:            cfg.QoSMaxInflightRequests,
+		SerializableHealthCheck:           cfg.SerializableHealthCheck,
+		HealthCheckTimeout:                cfg.HealthCheckTimeout,
 		SocketOpts:                        cfg.SocketOpts,
 		StrictReconfigCheck:               cfg.StrictReconfigCheck,
-		ClientCertAuthEnabled:             cfg.ClientTLSInfo.ClientCertAuth,
+		ClientCertAuthEnabled:             cfg.clientCertAuthEnabled(),
+		ClientCertRoleRules:               cfg.ClientCertRoleRules,
 		AuthToken:                         cfg.AuthToken,
 		BcryptCost:                        cfg.BcryptCost,
 		TokenTTL:                          cfg.AuthTokenTTL,
+		AuthLockoutUserFailures:           cfg.AuthLockoutUserFailures,
+		AuthLockoutSourceFailures:         cfg.AuthLockoutSourceFailures,
+		AuthLockoutDuration:               cfg.AuthLockoutDuration,
+		AuthLockoutMaxDuration:            cfg.AuthLockoutMaxDuration,
+		AuthPasswordMaxAge:                cfg.AuthPasswordMaxAge,
+		AuthPasswordGracePeriod:           cfg.AuthPasswordGracePeriod,
 		CORS:                              cfg.CORS,
 		HostWhitelist:                     cfg.HostWhitelist,
 		CorruptCheckTime:                  cfg.CorruptCheckTime,
 		CompactHashCheckTime:              cfg.CompactHashCheckTime,
+		CompactHashCheckQuarantine:        cfg.CompactHashCheckQuarantine,
 		PreVote:                           cfg.PreVote,
 		Logger:                            cfg.logger,
 		ForceNewCluster:                   cfg.ForceNewCluster,
 		EnableGRPCGateway:                 cfg.EnableGRPCGateway,
+		EnableGRPCReflection:              cfg.EnableGRPCReflection,
 		EnableDistributedTracing:          cfg.EnableDistributedTracing,
 		UnsafeNoFsync:                     cfg.UnsafeNoFsync,
 		CompactionBatchLimit:              cfg.CompactionBatchLimit,
 		CompactionSleepInterval:           cfg.CompactionSleepInterval,
+		CompactionTargetCommitLatency:     cfg.CompactionTargetCommitLatency,
+		CompactionWorkers:                 cfg.CompactionWorkers,
+		SlowWatcherMaxBacklog:             cfg.SlowWatcherMaxBacklog,
+		SlowWatcherPolicy:                 cfg.SlowWatcherPolicy,
+		MaxWatchStreams:                   cfg.MaxWatchStreams,
+		MaxWatchersPerStream:              cfg.MaxWatchersPerStream,
 		WatchProgressNotifyInterval:       cfg.WatchProgressNotifyInterval,
 		DowngradeCheckTime:                cfg.DowngradeCheckTime,
 		WarningApplyDuration:              cfg.WarningApplyDuration,
 		WarningUnaryRequestDuration:       cfg.WarningUnaryRequestDuration,
+		LogSlowRequestsAbove:              cfg.LogSlowRequestsAbove,
+		LogSlowRequestsSampleInitial:      cfg.LogSlowRequestsSampleInitial,
+		LogSlowRequestsSampleThereafter:   cfg.LogSlowRequestsSampleThereafter,
+		ApplyBacklogAlertThreshold:        cfg.ApplyBacklogAlertThreshold,
+		ApplyBacklogBackpressureThreshold: cfg.ApplyBacklogBackpressureThreshold,
+		KeyAccessSampleRate:               cfg.KeyAccessSampleRate,
+		BackupURL:                         cfg.BackupURL,
+		BackupInterval:                    cfg.BackupInterval,
+		BackupSnapshotInterval:            cfg.BackupSnapshotInterval,
+		BackupRetention:                   cfg.BackupRetention,
+		CDCSinkURLs:                       cfg.CDCSinkURLs,
+		CDCCheckpointKeyPrefix:            cfg.CDCCheckpointKeyPrefix,
+		MirrorTargetEndpoints:             cfg.MirrorTargetEndpoints,
+		MirrorPrefix:                      cfg.MirrorPrefix,
+		MirrorDestPrefix:                  cfg.MirrorDestPrefix,
+		MirrorCheckpointKey:               cfg.MirrorCheckpointKey,
+		MirrorMaxTxnOps:                   cfg.MirrorMaxTxnOps,
+		AutoSnapshotInterval:              cfg.AutoSnapshotInterval,
+		AutoSnapshotDir:                   cfg.AutoSnapshotDir,
+		AutoSnapshotRetention:             cfg.AutoSnapshotRetention,
+		AutoSnapshotLeaderOnly:            cfg.AutoSnapshotLeaderOnly,
+		AutoDefragSchedule:                cfg.AutoDefragSchedule,
+		AutoDefragRatio:                   cfg.AutoDefragRatio,
+		AutoDefragTransferLeadership:      cfg.AutoDefragTransferLeadership,
+		AutoDefragLockKey:                 cfg.AutoDefragLockKey,
+		LeaseStewardPrefixes:              cfg.LeaseStewardPrefixes,
+		LeaseStewardInterval:              cfg.LeaseStewardInterval,
+		LeaseStewardDelete:                cfg.LeaseStewardDelete,
+		LearnerAutoPromoteDuration:        cfg.LearnerAutoPromoteDuration,
+		LearnerAutoPromoteMaxLag:          cfg.LearnerAutoPromoteMaxLag,
+		FollowerLagAlarmThreshold:         cfg.FollowerLagAlarmThreshold,
+		FollowerLagAlarmDuration:          cfg.FollowerLagAlarmDuration,
+		LeaderLeaseReads:                  cfg.LeaderLeaseReads,
+		LeaderLeaseClockDrift:             cfg.LeaderLeaseClockDrift,
+		Witness:                           cfg.Witness,
+		EncryptionKEKFile:                 cfg.EncryptionKEKFile,
+		EncryptionKMSURL:                  cfg.EncryptionKMSURL,
+		EnableLeaderChangeEvents:          cfg.EnableLeaderChangeEvents,
+		LeaderChangeEventKey:              cfg.LeaderChangeEventKey,
 		MemoryMlock:                       cfg.MemoryMlock,
 		BootstrapDefragThresholdMegabytes: cfg.BootstrapDefragThresholdMegabytes,
 		MaxLearners:                       cfg.MaxLearners,
@@ -237,6 +346,8 @@ func StartEtcd(inCfg *Config) (e *Etcd, err error) {
 		LocalAddress:                      cfg.InferLocalAddr(),
 		ServerFeatureGate:                 cfg.ServerFeatureGate,
 		Metrics:                           cfg.Metrics,
+		GRPCHistogramBuckets:              cfg.GRPCHistogramBuckets,
+		MetricsKeyPrefixes:                cfg.MetricsKeyPrefixes,
 	}
 
 	if srvcfg.EnableDistributedTracing {
@@ -249,12 +360,86 @@ func StartEtcd(inCfg *Config) (e *Etcd, err error) {
 			tracingExporter.Close(tctx)
 		}
 		srvcfg.TracerOptions = tracingExporter.opts
+		srvcfg.TracerProvider = tracingExporter.provider
 
 		e.cfg.logger.Info(
 			"distributed tracing setup enabled",
 		)
 	}
 
+	if cfg.EnableOTLPMetrics {
+		mctx := context.Background()
+		metricsExporter, merr := newOTLPMetricsExporter(mctx, cfg)
+		if merr != nil {
+			return e, merr
+		}
+		e.otlpMetricsExporterShutdown = func() {
+			metricsExporter.Close(mctx)
+		}
+
+		e.cfg.logger.Info(
+			"otlp metrics export setup enabled",
+			zap.String("address", cfg.OTLPMetricsAddress),
+		)
+	}
+
+	if len(cfg.AuditLogOutputs) > 0 {
+		auditLogger, closeAuditLogger, aerr := newAuditLogger(cfg)
+		if aerr != nil {
+			return e, aerr
+		}
+		e.auditLoggerClose = closeAuditLogger
+		srvcfg.AuditLogger = auditLogger
+
+		e.cfg.logger.Info(
+			"audit logging enabled",
+			zap.Strings("audit-log-outputs", cfg.AuditLogOutputs),
+		)
+	}
+
+	if cfg.AdmissionWebhookURL != "" {
+		tlsInfo := transport.TLSInfo{
+			CertFile:      cfg.AdmissionWebhookCertFile,
+			KeyFile:       cfg.AdmissionWebhookKeyFile,
+			TrustedCAFile: cfg.AdmissionWebhookCAFile,
+			Logger:        cfg.logger,
+		}
+		tlsConfig, terr := tlsInfo.ClientConfig()
+		if terr != nil {
+			return e, fmt.Errorf("admission webhook TLS: %w", terr)
+		}
+		srvcfg.AdmissionWebhook = admission.NewWebhook(cfg.AdmissionWebhookURL, tlsConfig, cfg.AdmissionWebhookTimeout, cfg.AdmissionWebhookFailurePolicy)
+	}
+
+	if len(cfg.MirrorTargetEndpoints) > 0 {
+		if !cfg.MirrorNoDestPrefix && cfg.MirrorDestPrefix == "" {
+			srvcfg.MirrorDestPrefix = cfg.MirrorPrefix
+		}
+		tlsInfo := transport.TLSInfo{
+			CertFile:      cfg.MirrorTargetCertFile,
+			KeyFile:       cfg.MirrorTargetKeyFile,
+			TrustedCAFile: cfg.MirrorTargetTrustedCAFile,
+			Logger:        cfg.logger,
+		}
+		secure := !tlsInfo.Empty() || tlsInfo.TrustedCAFile != ""
+		for _, ep := range cfg.MirrorTargetEndpoints {
+			secure = secure || strings.HasPrefix(ep, "https://")
+		}
+		if secure {
+			if srvcfg.MirrorTargetTLS, err = tlsInfo.ClientConfig(); err != nil {
+				return e, fmt.Errorf("mirror target TLS: %w", err)
+			}
+		}
+	}
+
+	provider, err := encryption.NewKEKProvider(cfg.EncryptionKEKFile, cfg.EncryptionKMSURL)
+	if err != nil {
+		return e, err
+	}
+	if provider != nil {
+		srvcfg.EncryptionCodec = encryption.NewCodec(cfg.logger, provider)
+	}
+
 	srvcfg.PeerTLSInfo.LocalAddr = srvcfg.LocalAddress
 
 	print(e.cfg.logger, *cfg, srvcfg, memberInitialized)
@@ -267,8 +452,8 @@ func StartEtcd(inCfg *Config) (e *Etcd, err error) {
 	e.errc = make(chan error, len(e.Peers)+len(e.Clients)+2*len(e.sctxs))
 
 	// newly started member ("memberInitialized==false")
-	// does not need corruption check
-	if memberInitialized && srvcfg.ServerFeatureGate.Enabled(features.InitialCorruptCheck) {
+	// does not need corruption check, nor does a witness storing no keys
+	if memberInitialized && !srvcfg.Witness && srvcfg.ServerFeatureGate.Enabled(features.InitialCorruptCheck) {
 		if err = e.Server.CorruptionChecker().InitialCheck(); err != nil {
 			// set "EtcdServer" to nil, so that it does not block on "EtcdServer.Close()"
 			// (nothing to close since rafthttp transports have not been started)
@@ -289,6 +474,8 @@ func StartEtcd(inCfg *Config) (e *Etcd, err error) {
 		return e, err
 	}
 
+	e.watchCertFiles()
+
 	e.cfg.logger.Info(
 		"now serving peer/client/metrics",
 		zap.String("local-member-id", e.Server.MemberID().String()),
@@ -341,13 +528,57 @@ func print(lg *zap.Logger, ec Config, sc config.ServerConfig, memberInitialized
 		zap.Bool("initial-election-tick-advance", sc.InitialElectionTickAdvance),
 		zap.Uint64("snapshot-count", sc.SnapshotCount),
 		zap.Uint("max-wals", sc.MaxWALFiles),
+		zap.String("wal-compression", sc.WALCompression),
+		zap.String("storage-engine", sc.StorageEngine),
 		zap.Uint("max-snapshots", sc.MaxSnapFiles),
 		zap.Uint64("snapshot-catchup-entries", sc.SnapshotCatchUpEntries),
+		zap.Int64("snapshot-send-rate-limit", sc.SnapshotSendRateLimit),
+		zap.Int64("peer-snapshot-send-rate-limit", sc.PeerSnapshotSendRateLimit),
+		zap.Duration("peer-address-refresh-interval", sc.PeerAddressRefreshInterval),
+		zap.Strings("peer-network-faults", sc.PeerNetworkFaults),
+		zap.Uint64("apply-backlog-alert-threshold", sc.ApplyBacklogAlertThreshold),
+		zap.Uint64("apply-backlog-backpressure-threshold", sc.ApplyBacklogBackpressureThreshold),
+		zap.Float64("key-access-sample-rate", sc.KeyAccessSampleRate),
+		zap.String("backup-url", sc.BackupURL),
+		zap.Duration("backup-interval", sc.BackupInterval),
+		zap.Duration("backup-snapshot-interval", sc.BackupSnapshotInterval),
+		zap.Int("backup-retention", sc.BackupRetention),
+		zap.Int("cdc-sinks", len(sc.CDCSinkURLs)),
+		zap.String("cdc-checkpoint-key-prefix", sc.CDCCheckpointKeyPrefix),
+		zap.Strings("mirror-target-endpoints", sc.MirrorTargetEndpoints),
+		zap.String("mirror-prefix", sc.MirrorPrefix),
+		zap.String("mirror-dest-prefix", sc.MirrorDestPrefix),
+		zap.Duration("auto-snapshot-interval", sc.AutoSnapshotInterval),
+		zap.String("auto-snapshot-dir", sc.AutoSnapshotDir),
+		zap.Uint("auto-snapshot-retention", sc.AutoSnapshotRetention),
+		zap.Bool("auto-snapshot-leader-only", sc.AutoSnapshotLeaderOnly),
+		zap.String("auto-defrag-schedule", sc.AutoDefragSchedule),
+		zap.Float64("auto-defrag-ratio", sc.AutoDefragRatio),
+		zap.Bool("auto-defrag-transfer-leadership", sc.AutoDefragTransferLeadership),
+		zap.String("auto-defrag-lock-key", sc.AutoDefragLockKey),
+		zap.Strings("lease-steward-prefixes", sc.LeaseStewardPrefixes),
+		zap.Duration("lease-steward-interval", sc.LeaseStewardInterval),
+		zap.Bool("lease-steward-delete", sc.LeaseStewardDelete),
+		zap.Duration("learner-auto-promote-duration", sc.LearnerAutoPromoteDuration),
+		zap.Uint64("learner-auto-promote-max-lag", sc.LearnerAutoPromoteMaxLag),
+		zap.Uint64("follower-lag-alarm-threshold", sc.FollowerLagAlarmThreshold),
+		zap.Duration("follower-lag-alarm-duration", sc.FollowerLagAlarmDuration),
+		zap.Bool("leader-lease-reads", sc.LeaderLeaseReads),
+		zap.Duration("leader-lease-clock-drift", sc.LeaderLeaseClockDrift),
+		zap.Bool("witness", sc.Witness),
+		zap.String("encryption-kek-file", sc.EncryptionKEKFile),
+		zap.String("encryption-kms-url", sc.EncryptionKMSURL),
 		zap.Strings("initial-advertise-peer-urls", ec.getAdvertisePeerURLs()),
 		zap.Strings("listen-peer-urls", ec.getListenPeerURLs()),
 		zap.Strings("advertise-client-urls", ec.getAdvertiseClientURLs()),
 		zap.Strings("listen-client-urls", ec.getListenClientURLs()),
 		zap.Strings("listen-metrics-urls", ec.getMetricsURLs()),
+		zap.Bool("client-proxy-protocol", ec.ClientProxyProtocol),
+		zap.Strings("client-proxy-protocol-trusted-cidrs", ec.ClientProxyProtocolTrustedCIDRs),
+		zap.Bool("peer-proxy-protocol", ec.PeerProxyProtocol),
+		zap.Strings("peer-proxy-protocol-trusted-cidrs", ec.PeerProxyProtocolTrustedCIDRs),
+		zap.Duration("tls-reload-interval", ec.TLSReloadInterval),
+		zap.Duration("tls-reload-recycle-window", ec.TLSReloadRecycleWindow),
 		zap.String("local-address", sc.LocalAddress),
 		zap.Strings("cors", cors),
 		zap.Strings("host-whitelist", hss),
@@ -355,17 +586,39 @@ func print(lg *zap.Logger, ec Config, sc config.ServerConfig, memberInitialized
 		zap.String("initial-cluster-state", ec.ClusterState),
 		zap.String("initial-cluster-token", sc.InitialClusterToken),
 		zap.Int64("quota-backend-bytes", quota),
+		zap.Bool("quota-backend-grace", sc.QuotaBackendGrace),
+		zap.Float64s("quota-backend-warning-thresholds", sc.QuotaBackendWarningThresholds),
+		zap.Strings("key-quotas", sc.KeyQuotas),
+		zap.Strings("namespaces", sc.Namespaces),
+		zap.String("namespaces-key-prefix", sc.NamespacesKeyPrefix),
 		zap.Uint("max-request-bytes", sc.MaxRequestBytes),
 		zap.Uint32("max-concurrent-streams", sc.MaxConcurrentStreams),
+		zap.Duration("request-deadline-margin", sc.RequestDeadlineMargin),
+		zap.Int("max-concurrent-expensive-ranges", sc.MaxConcurrentExpensiveRanges),
+		zap.Int64("expensive-range-limit", sc.ExpensiveRangeLimit),
+		zap.Int("max-caller-labels", sc.MaxCallerLabels),
+		zap.Bool("client-identity-metrics", sc.ClientIdentityMetrics),
+		zap.Float64("client-rate-limit-qps", sc.ClientRateLimitQPS),
+		zap.Int("client-rate-limit-burst", sc.ClientRateLimitBurst),
+		zap.Int64("client-rate-limit-bytes", sc.ClientRateLimitBytes),
+		zap.Int("qos-max-inflight-requests", sc.QoSMaxInflightRequests),
+		zap.String("admission-webhook-url", ec.AdmissionWebhookURL),
+		zap.Duration("admission-webhook-timeout", ec.AdmissionWebhookTimeout),
+		zap.String("admission-webhook-failure-policy", ec.AdmissionWebhookFailurePolicy),
+		zap.Int("max-watch-streams", sc.MaxWatchStreams),
+		zap.Int("max-watchers-per-stream", sc.MaxWatchersPerStream),
 
 		zap.Bool("pre-vote", sc.PreVote),
 		zap.String(ServerFeatureGateFlagName, sc.ServerFeatureGate.String()),
 		zap.Bool("initial-corrupt-check", sc.InitialCorruptCheck),
 		zap.String("corrupt-check-time-interval", sc.CorruptCheckTime.String()),
 		zap.Duration("compact-check-time-interval", sc.CompactHashCheckTime),
+		zap.Bool("compact-hash-check-quarantine", sc.CompactHashCheckQuarantine),
+		zap.Strings("metrics-key-prefixes", sc.MetricsKeyPrefixes),
 		zap.String("auto-compaction-mode", sc.AutoCompactionMode),
 		zap.Duration("auto-compaction-retention", sc.AutoCompactionRetention),
 		zap.String("auto-compaction-interval", sc.AutoCompactionRetention.String()),
+		zap.String("compaction-control-key", sc.CompactionControlKey),
 		zap.String("discovery-url", sc.DiscoveryURL),
 		zap.String("discovery-proxy", sc.DiscoveryProxy),
 
@@ -384,6 +637,8 @@ func print(lg *zap.Logger, ec Config, sc config.ServerConfig, memberInitialized
 
 		zap.String("downgrade-check-interval", sc.DowngradeCheckTime.String()),
 		zap.Int("max-learners", sc.MaxLearners),
+		zap.Bool("enable-leader-change-events", sc.EnableLeaderChangeEvents),
+		zap.String("leader-change-event-key", sc.LeaderChangeEventKey),
 
 		zap.String("v2-deprecation", string(ec.V2Deprecation)),
 	)
@@ -470,11 +725,21 @@ func (e *Etcd) Close() {
 		e.tracingExporterShutdown()
 	}
 
+	// shutdown otlp metrics exporter
+	if e.otlpMetricsExporterShutdown != nil {
+		e.otlpMetricsExporterShutdown()
+	}
+
 	// close rafthttp transports
 	if e.Server != nil {
 		e.Server.Stop()
 	}
 
+	// flush audit records of the requests served so far
+	if e.auditLoggerClose != nil {
+		e.auditLoggerClose()
+	}
+
 	// close all idle connections in peer handler (wait up to 1-second)
 	for i := range e.Peers {
 		if e.Peers[i] != nil && e.Peers[i].close != nil {
@@ -533,18 +798,19 @@ func (e *Etcd) Err() <-chan error {
 }
 
 func configurePeerListeners(cfg *Config) (peers []*peerListener, err error) {
-	if err = updateCipherSuites(&cfg.PeerTLSInfo, cfg.CipherSuites); err != nil {
+	peerTLS := cfg.peerListenerTLS()
+	if err = updateCipherSuites(&cfg.PeerTLSInfo, peerTLS.cipherSuites); err != nil {
 		return nil, err
 	}
 	if err = cfg.PeerSelfCert(); err != nil {
 		cfg.logger.Fatal("failed to get peer self-signed certs", zap.Error(err))
 	}
-	updateMinMaxVersions(&cfg.PeerTLSInfo, cfg.TlsMinVersion, cfg.TlsMaxVersion)
+	updateMinMaxVersions(&cfg.PeerTLSInfo, peerTLS.minVersion, peerTLS.maxVersion)
 	if !cfg.PeerTLSInfo.Empty() {
 		cfg.logger.Info(
 			"starting with peer TLS",
 			zap.String("tls-info", fmt.Sprintf("%+v", cfg.PeerTLSInfo)),
-			zap.Strings("cipher-suites", cfg.CipherSuites),
+			zap.Strings("cipher-suites", peerTLS.cipherSuites),
 		)
 	}
 
@@ -577,11 +843,18 @@ func configurePeerListeners(cfg *Config) (peers []*peerListener, err error) {
 			}
 		}
 		peers[i] = &peerListener{close: func(context.Context) error { return nil }}
-		peers[i].Listener, err = transport.NewListenerWithOpts(u.Host, u.Scheme,
+		addr, _, network := resolveURL(u)
+		opts := []transport.ListenerOption{
 			transport.WithTLSInfo(&cfg.PeerTLSInfo),
 			transport.WithSocketOpts(&cfg.SocketOpts),
 			transport.WithTimeout(rafthttp.ConnReadTimeout, rafthttp.ConnWriteTimeout),
-		)
+			transport.WithProxyProtocol(proxyProtocolTrusted(cfg.PeerProxyProtocol, cfg.PeerProxyProtocolTrustedCIDRs)),
+		}
+		if cfg.TLSReloadInterval > 0 && u.Scheme == "https" && !cfg.PeerTLSInfo.Empty() {
+			peers[i].connTracker = transport.NewConnTracker()
+			opts = append(opts, transport.WithConnTracker(peers[i].connTracker))
+		}
+		peers[i].Listener, err = transport.NewListenerWithOpts(u.Host, u.Scheme, append(opts, cfg.inheritedListenerOpts(network, addr)...)...)
 		if err != nil {
 			cfg.logger.Error("creating peer listener failed", zap.Error(err))
 			return nil, err
@@ -647,13 +920,14 @@ func (e *Etcd) servePeers() {
 }
 
 func configureClientListeners(cfg *Config) (sctxs map[string]*serveCtx, err error) {
-	if err = updateCipherSuites(&cfg.ClientTLSInfo, cfg.CipherSuites); err != nil {
+	clientTLS := cfg.clientListenerTLS()
+	if err = updateCipherSuites(&cfg.ClientTLSInfo, clientTLS.cipherSuites); err != nil {
 		return nil, err
 	}
 	if err = cfg.ClientSelfCert(); err != nil {
 		cfg.logger.Fatal("failed to get client self-signed certs", zap.Error(err))
 	}
-	updateMinMaxVersions(&cfg.ClientTLSInfo, cfg.TlsMinVersion, cfg.TlsMaxVersion)
+	updateMinMaxVersions(&cfg.ClientTLSInfo, clientTLS.minVersion, clientTLS.maxVersion)
 	if cfg.EnablePprof {
 		cfg.logger.Info("pprof is enabled", zap.String("path", debugutil.HTTPPrefixPProf))
 	}
@@ -668,7 +942,7 @@ func configureClientListeners(cfg *Config) (sctxs map[string]*serveCtx, err erro
 				cfg.logger.Warn("scheme is http or unix while --client-cert-auth is enabled; ignoring client cert auth for this URL", zap.String("client-url", u.String()))
 			}
 		}
-		if (u.Scheme == "https" || u.Scheme == "unixs") && cfg.ClientTLSInfo.Empty() {
+		if (u.Scheme == "https" || u.Scheme == "unixs" || u.Scheme == "quic") && cfg.ClientTLSInfo.Empty() {
 			return nil, fmt.Errorf("TLS key/cert (--cert-file, --key-file) must be provided for client url %s with HTTPS scheme", u.String())
 		}
 	}
@@ -703,18 +977,59 @@ func configureClientListeners(cfg *Config) (sctxs map[string]*serveCtx, err erro
 		sctx.network = network
 		sctx.httpOnly = true
 	}
+	for i := range cfg.ClientListenerGroups {
+		g := &cfg.ClientListenerGroups[i]
+		if err = clientTLS.apply(&g.TLSInfo); err != nil {
+			return nil, err
+		}
+		for _, u := range g.ListenURLs {
+			addr, secure, network := resolveURL(u)
+			sctx := sctxs[addr]
+			if sctx == nil {
+				sctx = newServeCtx(cfg.logger.With(zap.String("client-listener-group", g.Name)))
+				sctxs[addr] = sctx
+			} else if sctx.group != g.Name {
+				return nil, fmt.Errorf("client listener group %q cannot bind the client url %s already bound", g.Name, u.String())
+			}
+			sctx.secure = sctx.secure || secure
+			sctx.insecure = sctx.insecure || !secure
+			sctx.scheme = u.Scheme
+			sctx.addr = addr
+			sctx.network = network
+			sctx.group = g.Name
+			sctx.tlsinfo = &g.TLSInfo
+			sctx.allowedRPCs = g.AllowedRPCs
+		}
+	}
 
 	for _, sctx := range sctxs {
-		if sctx.l, err = transport.NewListenerWithOpts(sctx.addr, sctx.scheme,
-			transport.WithSocketOpts(&cfg.SocketOpts),
-			transport.WithSkipTLSInfoCheck(true),
-		); err != nil {
+		if sctx.quic() {
+			tlsinfo := &cfg.ClientTLSInfo
+			if sctx.tlsinfo != nil {
+				tlsinfo = sctx.tlsinfo
+			}
+			sctx.l, err = transport.NewQUICListener(sctx.addr, tlsinfo)
+		} else {
+			opts := []transport.ListenerOption{
+				transport.WithSocketOpts(&cfg.SocketOpts),
+				transport.WithSkipTLSInfoCheck(true),
+				transport.WithProxyProtocol(proxyProtocolTrusted(cfg.ClientProxyProtocol, cfg.ClientProxyProtocolTrustedCIDRs)),
+			}
+			// the listener is not wrapped with TLS yet, see serveCtx.serve.
+			if cfg.TLSReloadInterval > 0 && sctx.secure && sctx.network == "tcp" {
+				sctx.connTracker = transport.NewConnTracker()
+				opts = append(opts, transport.WithConnTracker(sctx.connTracker))
+			}
+			sctx.l, err = transport.NewListenerWithOpts(sctx.addr, sctx.scheme, append(opts, cfg.inheritedListenerOpts(sctx.network, sctx.addr)...)...)
+		}
+		if err != nil {
 			return nil, err
 		}
 		// net.Listener will rewrite ipv4 0.0.0.0 to ipv6 [::], breaking
 		// hosts that disable ipv6. So, use the address given by the user.
 
-		if fdLimit, fderr := runtimeutil.FDLimit(); fderr == nil {
+		// the QUIC connections share the UDP socket of the listener.
+		if fdLimit, fderr := runtimeutil.FDLimit(); fderr == nil && !sctx.quic() {
 			if fdLimit <= reservedInternalFDNum {
 				cfg.logger.Fatal(
 					"file descriptor limit of etcd process is too low; please set higher",
@@ -757,7 +1072,10 @@ func resolveURL(u url.URL) (addr string, secure bool, network string) {
 		addr = u.Host + u.Path
 		network = "unix"
 	}
-	secure = u.Scheme == "https" || u.Scheme == "unixs"
+	if u.Scheme == "quic" {
+		network = "udp"
+	}
+	secure = u.Scheme == "https" || u.Scheme == "unixs" || u.Scheme == "quic"
 	return addr, secure, network
 }
 
@@ -766,7 +1084,7 @@ func (e *Etcd) serveClients() {
 		e.cfg.logger.Info(
 			"starting with client TLS",
 			zap.String("tls-info", fmt.Sprintf("%+v", e.cfg.ClientTLSInfo)),
-			zap.Strings("cipher-suites", e.cfg.CipherSuites),
+			zap.Strings("cipher-suites", e.cfg.clientListenerTLS().cipherSuites),
 		)
 	}
 
@@ -774,8 +1092,18 @@ func (e *Etcd) serveClients() {
 	mux := http.NewServeMux()
 	etcdhttp.HandleDebug(mux)
 	etcdhttp.HandleVersion(mux, e.Server)
-	etcdhttp.HandleMetrics(mux)
+	etcdhttp.HandleFilteredMetrics(mux, e.cfg.MetricsDenylist)
 	etcdhttp.HandleHealth(e.cfg.logger, mux, e.Server)
+	etcdhttp.HandleWatermarks(mux, e.Server)
+	if e.cfg.EnableConfigReload {
+		mux.Handle(configReloadPath, e.configReloadHandler())
+	}
+	if e.cfg.EnableLogEndpoint {
+		etcdhttp.HandleLog(e.cfg.logger, mux, e.Server, e)
+	}
+	if e.cfg.EnableFailpointsEndpoint {
+		etcdhttp.HandleFailpoints(e.cfg.logger, mux, e.Server)
+	}
 
 	var gopts []grpc.ServerOption
 	if e.cfg.GRPCKeepAliveMinTime > time.Duration(0) {
@@ -803,8 +1131,15 @@ func (e *Etcd) serveClients() {
 	// start client servers in each goroutine
 	for _, sctx := range e.sctxs {
 		s := sctx
+		// registered as a user handler to take precedence over the gRPC
+		// gateway serving the other '/v3/' paths.
+		s.registerUserHandler(etcdhttp.PathLeaseKeepAlive, etcdhttp.NewLeaseKeepAliveHandler(e.cfg.logger, e.Server))
+		tlsinfo := &e.cfg.ClientTLSInfo
+		if s.tlsinfo != nil {
+			tlsinfo = s.tlsinfo
+		}
 		e.startHandler(func() error {
-			return s.serve(e.Server, &e.cfg.ClientTLSInfo, mux, e.errHandler, e.grpcGatewayDial(splitHTTP), splitHTTP, gopts...)
+			return s.serve(e.Server, tlsinfo, mux, e.errHandler, e.grpcGatewayDial(splitHTTP), splitHTTP, gopts...)
 		})
 	}
 }
@@ -847,7 +1182,7 @@ func (e *Etcd) grpcGatewayDial(splitHTTP bool) (grpcDial func(ctx context.Contex
 
 func (e *Etcd) pickGRPCGatewayServeContext(splitHTTP bool) *serveCtx {
 	for _, sctx := range e.sctxs {
-		if !splitHTTP || !sctx.httpOnly {
+		if sctx.group == "" && !sctx.quic() && (!splitHTTP || !sctx.httpOnly) {
 			return sctx
 		}
 	}
@@ -857,25 +1192,32 @@ func (e *Etcd) pickGRPCGatewayServeContext(splitHTTP bool) *serveCtx {
 var ErrMissingClientTLSInfoForMetricsURL = errors.New("client TLS key/cert (--cert-file, --key-file) must be provided for metrics secure url")
 
 func (e *Etcd) createMetricsListener(murl url.URL) (net.Listener, error) {
-	tlsInfo := &e.cfg.ClientTLSInfo
+	var tlsInfo *transport.TLSInfo
 	switch murl.Scheme {
-	case "http":
-		tlsInfo = nil
 	case "https", "unixs":
 		if e.cfg.ClientTLSInfo.Empty() {
 			return nil, ErrMissingClientTLSInfoForMetricsURL
 		}
+		// the metrics listeners share the certificates of the client
+		// listeners, with their own TLS versions and cipher suites.
+		metricsTLSInfo := e.cfg.ClientTLSInfo
+		metricsTLSInfo.CipherSuites = nil
+		if err := e.cfg.metricsListenerTLS().apply(&metricsTLSInfo); err != nil {
+			return nil, err
+		}
+		tlsInfo = &metricsTLSInfo
 	}
-	return transport.NewListenerWithOpts(murl.Host, murl.Scheme,
+	addr, _, network := resolveURL(murl)
+	return transport.NewListenerWithOpts(murl.Host, murl.Scheme, append([]transport.ListenerOption{
 		transport.WithTLSInfo(tlsInfo),
 		transport.WithSocketOpts(&e.cfg.SocketOpts),
-	)
+	}, e.cfg.inheritedListenerOpts(network, addr)...)...)
 }
 
 func (e *Etcd) serveMetrics() (err error) {
 	if len(e.cfg.ListenMetricsUrls) > 0 {
 		metricsMux := http.NewServeMux()
-		etcdhttp.HandleMetrics(metricsMux)
+		etcdhttp.HandleFilteredMetrics(metricsMux, e.cfg.MetricsDenylist)
 		etcdhttp.HandleHealth(e.cfg.logger, metricsMux, e.Server)
 
 		for _, murl := range e.cfg.ListenMetricsUrls {
@@ -931,6 +1273,17 @@ func (e *Etcd) GetLogger() *zap.Logger {
 }
 
 func parseCompactionRetention(mode, retention string) (ret time.Duration, err error) {
+	if mode == CompactorModeSize {
+		// the retention is a number of bytes stored as a duration
+		b, err := humanize.ParseBytes(retention)
+		if err != nil {
+			return 0, fmt.Errorf("error parsing CompactionRetention: %w", err)
+		}
+		if b > math.MaxInt64 {
+			return 0, fmt.Errorf("CompactionRetention %q is too large", retention)
+		}
+		return time.Duration(b), nil
+	}
 	h, err := strconv.Atoi(retention)
 	if err == nil && h >= 0 {
 		switch mode {
@@ -0,0 +1,81 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embed
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+func TestGRPCReflection(t *testing.T) {
+	for _, enabled := range []bool{false, true} {
+		t.Run(map[bool]string{false: "disabled", true: "enabled"}[enabled], func(t *testing.T) {
+			cfg := NewConfig()
+			urls := newEmbedURLs(2)
+			curls, purls := []url.URL{urls[0]}, []url.URL{urls[1]}
+			cfg.ListenClientUrls, cfg.AdvertiseClientUrls = curls, curls
+			cfg.ListenPeerUrls, cfg.AdvertisePeerUrls = purls, purls
+			cfg.InitialCluster = "default=" + purls[0].String()
+			cfg.Dir = t.TempDir()
+			cfg.EnableGRPCReflection = enabled
+
+			e, err := StartEtcd(cfg)
+			require.NoError(t, err)
+			defer e.Close()
+			<-e.Server.ReadyNotify()
+
+			cli, err := clientv3.New(clientv3.Config{Endpoints: []string{curls[0].String()}, DialTimeout: 5 * time.Second})
+			require.NoError(t, err)
+			defer cli.Close()
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			stream, err := reflectionpb.NewServerReflectionClient(cli.ActiveConnection()).ServerReflectionInfo(ctx)
+			require.NoError(t, err)
+			require.NoError(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+				MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+			}))
+			resp, err := stream.Recv()
+			if !enabled {
+				require.Equal(t, codes.Unimplemented, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+			var services []string
+			for _, s := range resp.GetListServicesResponse().GetService() {
+				services = append(services, s.GetName())
+			}
+			assert.Contains(t, services, "etcdserverpb.KV")
+			assert.Contains(t, services, "etcdserverpb.Maintenance")
+
+			require.NoError(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+				MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: "etcdserverpb.KV"},
+			}))
+			resp, err = stream.Recv()
+			require.NoError(t, err)
+			assert.NotEmpty(t, resp.GetFileDescriptorResponse().GetFileDescriptorProto())
+		})
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embed
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/pkg/v3/featuregate"
+)
+
+func newQUICURL(t *testing.T) url.URL {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc.Close()
+	return url.URL{Scheme: "quic", Host: fmt.Sprintf("127.0.0.1:%d", pc.LocalAddr().(*net.UDPAddr).Port)}
+}
+
+func TestValidateQUICURLs(t *testing.T) {
+	quicURL := url.URL{Scheme: "quic", Host: "127.0.0.1:2379"}
+	unixURL := url.URL{Scheme: "unix", Host: "localhost:2379"}
+	tests := []struct {
+		name        string
+		gate        bool
+		listen      []url.URL
+		listenHTTP  []url.URL
+		expectedErr bool
+	}{
+		{name: "no quic", listen: []url.URL{unixURL}},
+		{name: "feature gate disabled", listen: []url.URL{unixURL, quicURL}, expectedErr: true},
+		{name: "quic along with unix", gate: true, listen: []url.URL{unixURL, quicURL}},
+		{name: "quic only", gate: true, listen: []url.URL{quicURL}, expectedErr: true},
+		{name: "quic http url", gate: true, listen: []url.URL{unixURL}, listenHTTP: []url.URL{quicURL}, expectedErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.ListenClientUrls = tt.listen
+			cfg.ListenClientHttpUrls = tt.listenHTTP
+			require.NoError(t, cfg.ServerFeatureGate.(featuregate.MutableFeatureGate).Set(fmt.Sprintf("QUICClientListener=%v", tt.gate)))
+			err := cfg.validateQUICURLs()
+			if tt.expectedErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestQUICClientListener(t *testing.T) {
+	cfg := NewConfig()
+	urls := newEmbedURLs(2)
+	quicURL := newQUICURL(t)
+	curls := []url.URL{urls[0], quicURL}
+	purls := []url.URL{urls[1]}
+	cfg.ListenClientUrls, cfg.AdvertiseClientUrls = curls, curls
+	cfg.ListenPeerUrls, cfg.AdvertisePeerUrls = purls, purls
+	cfg.InitialCluster = "default=" + purls[0].String()
+	cfg.Dir = t.TempDir()
+	cfg.ClientAutoTLS = true
+	require.NoError(t, cfg.ServerFeatureGate.(featuregate.MutableFeatureGate).Set("QUICClientListener=true"))
+
+	e, err := StartEtcd(cfg)
+	require.NoError(t, err)
+	defer e.Close()
+	<-e.Server.ReadyNotify()
+
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   []string{quicURL.String()},
+		DialTimeout: 5 * time.Second,
+		TLS:         &tls.Config{InsecureSkipVerify: true},
+	})
+	require.NoError(t, err)
+	defer cli.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = cli.Put(ctx, "foo", "bar")
+	require.NoError(t, err)
+	resp, err := cli.Get(ctx, "foo")
+	require.NoError(t, err)
+	require.Len(t, resp.Kvs, 1)
+	assert.Equal(t, "bar", string(resp.Kvs[0].Value))
+
+	require.NoError(t, cli.Sync(ctx))
+	assert.Equal(t, []string{quicURL.String()}, cli.Endpoints())
+}
@@ -61,6 +61,18 @@ type serveCtx struct {
 	insecure bool
 	httpOnly bool
 
+	// group is the name of the client listener group of the context, empty
+	// for ListenClientUrls and ListenClientHttpUrls.
+	group string
+	// tlsinfo is the TLS configuration of the group, nil for the client TLS
+	// configuration.
+	tlsinfo *transport.TLSInfo
+	// allowedRPCs are the RPCs served by the group, all if empty.
+	allowedRPCs []string
+	// connTracker tracks the connections of the secure listener to close
+	// them when the certificates are renewed, nil if not tracked.
+	connTracker *transport.ConnTracker
+
 	// ctx is used to control the grpc gateway. Terminate the grpc gateway
 	// by calling `cancel` when shutting down the etcd.
 	ctx    context.Context
@@ -80,6 +92,11 @@ type serveCtx struct {
 	wg sync.WaitGroup
 }
 
+// quic returns true if the context serves a QUIC listener.
+func (sctx *serveCtx) quic() bool {
+	return sctx.scheme == "quic"
+}
+
 func (sctx *serveCtx) startHandler(errHandler func(error), handler func() error) {
 	// start each handler in a separate goroutine
 	sctx.wg.Add(1)
@@ -139,7 +156,9 @@ func (sctx *serveCtx) serve(
 
 	m := cmux.New(sctx.l)
 	var server func() error
-	onlyGRPC := splitHTTP && !sctx.httpOnly
+	// the groups serve both the gRPC and HTTP requests they allow, the QUIC
+	// listeners only serve gRPC.
+	onlyGRPC := (splitHTTP && !sctx.httpOnly && sctx.group == "") || sctx.quic()
 	onlyHTTP := splitHTTP && sctx.httpOnly
 	grpcEnabled := !onlyHTTP
 	httpEnabled := !onlyGRPC
@@ -149,7 +168,7 @@ func (sctx *serveCtx) serve(
 	servLock := v3lock.NewLockServer(v3c)
 
 	var gwmux *gw.ServeMux
-	if s.Cfg.EnableGRPCGateway {
+	if s.Cfg.EnableGRPCGateway && httpEnabled {
 		// GRPC gateway connects to grpc server via connection provided by grpc dial.
 		gwmux, err = sctx.registerGateway(grpcDialForRestGatewayBackends)
 		if err != nil {
@@ -173,7 +192,7 @@ func (sctx *serveCtx) serve(
 		if httpEnabled {
 			httpmux := sctx.createMux(gwmux, handler)
 			srv = &http.Server{
-				Handler:  createAccessController(sctx.lg, s, httpmux),
+				Handler:  createAccessController(sctx.lg, s, sctx.restrictHTTP(httpmux)),
 				ErrorLog: logger, // do not log user error
 			}
 			if err = configureHTTPServer(srv, s.Cfg); err != nil {
@@ -182,7 +201,7 @@ func (sctx *serveCtx) serve(
 			}
 		}
 		if grpcEnabled {
-			gs = v3rpc.Server(s, nil, nil, gopts...)
+			gs = v3rpc.Server(s, nil, nil, append(gopts, sctx.restrictedGRPCOptions()...)...)
 			v3electionpb.RegisterElectionServer(gs, servElection)
 			v3lockpb.RegisterLockServer(gs, servLock)
 			if sctx.serviceRegister != nil {
@@ -234,7 +253,7 @@ func (sctx *serveCtx) serve(
 		}
 
 		if grpcEnabled {
-			gs = v3rpc.Server(s, tlscfg, nil, gopts...)
+			gs = v3rpc.Server(s, tlscfg, nil, append(gopts, sctx.restrictedGRPCOptions()...)...)
 			v3electionpb.RegisterElectionServer(gs, servElection)
 			v3lockpb.RegisterLockServer(gs, servLock)
 			if sctx.serviceRegister != nil {
@@ -255,7 +274,7 @@ func (sctx *serveCtx) serve(
 			httpmux := sctx.createMux(gwmux, handler)
 
 			srv = &http.Server{
-				Handler:   createAccessController(sctx.lg, s, httpmux),
+				Handler:   createAccessController(sctx.lg, s, sctx.restrictHTTP(httpmux)),
 				TLSConfig: tlscfg,
 				ErrorLog:  logger, // do not log user error
 			}
@@ -386,6 +405,33 @@ func (w wsProxyZapLogger) Debugln(i ...any) {
 	w.Debug(fmt.Sprint(i...))
 }
 
+// wsProxyForwardedHeader returns true for the headers of the WebSocket upgrade
+// requests forwarded to the gateway: the Authorization header of the clients
+// able to set it, along with the default Origin and Referer.
+func wsProxyForwardedHeader(header string) bool {
+	switch http.CanonicalHeaderKey(header) {
+	case "Authorization", "Origin", "Referer":
+		return true
+	}
+	return false
+}
+
+// wsProxyRequestMutator adapts the requests the WebSocket proxy makes to the
+// gateway for the streams.
+func wsProxyRequestMutator(_ *http.Request, outgoing *http.Request) *http.Request {
+	// Default to the POST method for streams
+	outgoing.Method = "POST"
+	// The browsers cannot set the Authorization header of a WebSocket, so
+	// the proxy takes the token from the "Bearer, <token>" subprotocols or the
+	// "token" cookie, as a bearer token. etcd expects the bare token.
+	if auth := outgoing.Header.Get("Authorization"); auth == "" {
+		outgoing.Header.Del("Authorization")
+	} else {
+		outgoing.Header.Set("Authorization", strings.TrimPrefix(auth, "Bearer "))
+	}
+	return outgoing
+}
+
 func (sctx *serveCtx) createMux(gwmux *gw.ServeMux, handler http.Handler) *http.ServeMux {
 	httpmux := http.NewServeMux()
 	for path, h := range sctx.userHandlers {
@@ -397,13 +443,8 @@ func (sctx *serveCtx) createMux(gwmux *gw.ServeMux, handler http.Handler) *http.
 			"/v3/",
 			wsproxy.WebsocketProxy(
 				gwmux,
-				wsproxy.WithRequestMutator(
-					// Default to the POST method for streams
-					func(_ *http.Request, outgoing *http.Request) *http.Request {
-						outgoing.Method = "POST"
-						return outgoing
-					},
-				),
+				wsproxy.WithRequestMutator(wsProxyRequestMutator),
+				wsproxy.WithForwardedHeaders(wsProxyForwardedHeader),
 				wsproxy.WithMaxRespBodyBufferSize(0x7fffffff),
 				wsproxy.WithLogger(wsProxyZapLogger{sctx.lg}),
 			),
@@ -419,7 +460,7 @@ func (sctx *serveCtx) createMux(gwmux *gw.ServeMux, handler http.Handler) *http.
 // - mutate gRPC gateway request paths
 // - check hostname whitelist
 // client HTTP requests goes here first
-func createAccessController(lg *zap.Logger, s *etcdserver.EtcdServer, mux *http.ServeMux) http.Handler {
+func createAccessController(lg *zap.Logger, s *etcdserver.EtcdServer, mux http.Handler) http.Handler {
 	if lg == nil {
 		lg = zap.NewNop()
 	}
@@ -429,7 +470,7 @@ func createAccessController(lg *zap.Logger, s *etcdserver.EtcdServer, mux *http.
 type accessController struct {
 	lg  *zap.Logger
 	s   *etcdserver.EtcdServer
-	mux *http.ServeMux
+	mux http.Handler
 }
 
 func (ac *accessController) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
@@ -15,14 +15,21 @@
 package embed
 
 import (
+	"context"
+	"encoding/base64"
 	"fmt"
+	"net"
 	"net/url"
 	"os"
 	"testing"
 
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/server/v3/auth"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3client"
 )
 
 // TestStartEtcdWrongToken ensures that StartEtcd with wrong configs returns with error.
@@ -57,3 +64,80 @@ func newEmbedURLs(n int) (urls []url.URL) {
 	}
 	return urls
 }
+
+func TestGRPCGatewayWebsocketWatch(t *testing.T) {
+	cfg := NewConfig()
+	urls := newEmbedURLs(2)
+	curls, purls := []url.URL{urls[0]}, []url.URL{urls[1]}
+	cfg.ListenClientUrls, cfg.AdvertiseClientUrls = curls, curls
+	cfg.ListenPeerUrls, cfg.AdvertisePeerUrls = purls, purls
+	cfg.InitialCluster = "default=" + purls[0].String()
+	cfg.Dir = t.TempDir()
+
+	e, err := StartEtcd(cfg)
+	require.NoError(t, err)
+	defer e.Close()
+	<-e.Server.ReadyNotify()
+
+	v3c := v3client.New(e.Server)
+	defer v3c.Close()
+	_, err = v3c.RoleAdd(t.Context(), "root")
+	require.NoError(t, err)
+	_, err = v3c.UserAdd(t.Context(), "root", "root")
+	require.NoError(t, err)
+	_, err = v3c.UserGrantRole(t.Context(), "root", "root")
+	require.NoError(t, err)
+	_, err = v3c.AuthEnable(t.Context())
+	require.NoError(t, err)
+
+	cli, err := clientv3.New(clientv3.Config{Endpoints: []string{curls[0].String()}, Username: "root", Password: "root"})
+	require.NoError(t, err)
+	defer cli.Close()
+	authResp, err := cli.Authenticate(t.Context(), "root", "root")
+	require.NoError(t, err)
+
+	dialer := websocket.Dialer{
+		NetDialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", curls[0].Host)
+		},
+	}
+	watch := func(t *testing.T, subprotocols ...string) *websocket.Conn {
+		dialer.Subprotocols = subprotocols
+		conn, _, derr := dialer.DialContext(t.Context(), "ws://localhost/v3/watch", nil)
+		require.NoError(t, derr)
+		require.NoError(t, conn.WriteJSON(map[string]any{
+			"create_request": map[string]any{"key": base64.StdEncoding.EncodeToString([]byte("foo"))},
+		}))
+		return conn
+	}
+
+	t.Run("without token", func(t *testing.T) {
+		conn := watch(t)
+		defer conn.Close()
+		_, msg, rerr := conn.ReadMessage()
+		require.NoError(t, rerr)
+		assert.Contains(t, string(msg), `"canceled":true`)
+		assert.Contains(t, string(msg), "user name is empty")
+	})
+
+	t.Run("bearer subprotocol", func(t *testing.T) {
+		conn := watch(t, "Bearer", authResp.Token)
+		defer conn.Close()
+		msgc := make(chan string)
+		go func() {
+			defer close(msgc)
+			for {
+				_, msg, rerr := conn.ReadMessage()
+				if rerr != nil {
+					return
+				}
+				msgc <- string(msg)
+			}
+		}()
+		assert.Contains(t, <-msgc, `"created":true`)
+
+		_, err = cli.Put(t.Context(), "foo", "bar")
+		require.NoError(t, err)
+		assert.Contains(t, <-msgc, base64.StdEncoding.EncodeToString([]byte("bar")))
+	})
+}
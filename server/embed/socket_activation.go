@@ -0,0 +1,138 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embed
+
+import (
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/coreos/go-systemd/v22/activation"
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+)
+
+// listenFDs holds the listening sockets passed by systemd socket activation
+// (LISTEN_FDS) that are not yet served by a peer, client or metrics listener.
+type listenFDs struct {
+	mu sync.Mutex
+	ls []net.Listener
+}
+
+// newListenFDs takes over the listening sockets passed by systemd. The
+// LISTEN_* environment variables are unset, so that they are not inherited by
+// the child processes.
+func newListenFDs(lg *zap.Logger) (*listenFDs, error) {
+	fds := &listenFDs{}
+	for _, f := range activation.Files(true) {
+		l, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			fds.closeUnused(lg)
+			return nil, err
+		}
+		fds.ls = append(fds.ls, l)
+	}
+	addrs := make([]string, 0, len(fds.ls))
+	for _, l := range fds.ls {
+		addrs = append(addrs, l.Addr().String())
+	}
+	lg.Info("inherited listeners from systemd socket activation", zap.Strings("addresses", addrs))
+	return fds, nil
+}
+
+// take removes and returns the inherited listener bound to addr on network,
+// or nil if there is none.
+func (fds *listenFDs) take(network, addr string) net.Listener {
+	if fds == nil {
+		return nil
+	}
+	fds.mu.Lock()
+	defer fds.mu.Unlock()
+	for i, l := range fds.ls {
+		if listensOn(l, network, addr) {
+			fds.ls = append(fds.ls[:i], fds.ls[i+1:]...)
+			return l
+		}
+	}
+	return nil
+}
+
+// closeUnused closes the inherited listeners that do not match any of the
+// configured URLs.
+func (fds *listenFDs) closeUnused(lg *zap.Logger) {
+	if fds == nil {
+		return
+	}
+	fds.mu.Lock()
+	defer fds.mu.Unlock()
+	for _, l := range fds.ls {
+		lg.Warn(
+			"closing inherited listener that matches no listen URL",
+			zap.String("address", l.Addr().String()),
+		)
+		l.Close()
+	}
+	fds.ls = nil
+}
+
+// listensOn returns true if l is bound to addr on network. The host of a TCP
+// address matches the IP the listener is bound to, or any of the IPs it
+// resolves to.
+func listensOn(l net.Listener, network, addr string) bool {
+	switch la := l.Addr().(type) {
+	case *net.UnixAddr:
+		return network == "unix" && la.Name == addr
+	case *net.TCPAddr:
+		if network != "tcp" {
+			return false
+		}
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || port != strconv.Itoa(la.Port) {
+			return false
+		}
+		if host == "" {
+			return la.IP.IsUnspecified()
+		}
+		if ip := net.ParseIP(host); ip != nil {
+			return ip.Equal(la.IP) || (ip.IsUnspecified() && la.IP.IsUnspecified())
+		}
+		ips, err := net.LookupIP(host)
+		if err != nil {
+			return false
+		}
+		for _, ip := range ips {
+			if ip.Equal(la.IP) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// inheritedListenerOpts returns the listener option serving the inherited
+// listener bound to addr on network, if any.
+func (cfg *Config) inheritedListenerOpts(network, addr string) []transport.ListenerOption {
+	if l := cfg.listenFDs.take(network, addr); l != nil {
+		cfg.logger.Info(
+			"serving inherited listener",
+			zap.String("network", network),
+			zap.String("address", addr),
+		)
+		return []transport.ListenerOption{transport.WithInheritedListener(l)}
+	}
+	return nil
+}
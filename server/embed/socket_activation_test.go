@@ -0,0 +1,72 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embed
+
+import (
+	"net"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestListenFDsTake(t *testing.T) {
+	tcp, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := strconv.Itoa(tcp.Addr().(*net.TCPAddr).Port)
+	sock := filepath.Join(t.TempDir(), "etcd.sock")
+	unix, err := net.Listen("unix", sock)
+	require.NoError(t, err)
+	fds := &listenFDs{ls: []net.Listener{tcp, unix}}
+
+	assert.Nil(t, fds.take("tcp", "127.0.0.2:"+port))
+	assert.Nil(t, fds.take("tcp", "127.0.0.1:1"))
+	assert.Nil(t, fds.take("unix", "127.0.0.1:"+port))
+	assert.Nil(t, fds.take("tcp", sock))
+	assert.Same(t, tcp, fds.take("tcp", "localhost:"+port))
+	assert.Nil(t, fds.take("tcp", "127.0.0.1:"+port), "listener taken twice")
+	assert.Same(t, unix, fds.take("unix", sock))
+	assert.Empty(t, fds.ls)
+	tcp.Close()
+	unix.Close()
+
+	var nilFDs *listenFDs
+	assert.Nil(t, nilFDs.take("tcp", "127.0.0.1:"+port))
+}
+
+func TestListensOnUnspecified(t *testing.T) {
+	l, err := net.Listen("tcp", "0.0.0.0:0")
+	require.NoError(t, err)
+	defer l.Close()
+	port := strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+
+	assert.True(t, listensOn(l, "tcp", "0.0.0.0:"+port))
+	assert.True(t, listensOn(l, "tcp", ":"+port))
+	assert.False(t, listensOn(l, "tcp", "127.0.0.1:"+port))
+}
+
+func TestListenFDsCloseUnused(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	fds := &listenFDs{ls: []net.Listener{l}}
+	fds.closeUnused(zaptest.NewLogger(t))
+	assert.Empty(t, fds.ls)
+
+	_, err = l.Accept()
+	require.ErrorIs(t, err, net.ErrClosed)
+}
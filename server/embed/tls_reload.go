@@ -0,0 +1,90 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embed
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+)
+
+// watchCertFiles closes the established peer and client connections when
+// their certificate files are renewed, every Config.TLSReloadInterval until
+// etcd is closed. The certificates are read again on each handshake, so only
+// the new connections use the renewed ones: the long-lived peer streams, gRPC
+// streams and keepalive connections would keep the former ones until they
+// expire. The client connections are closed over
+// Config.TLSReloadRecycleWindow, for the clients not to reconnect all at once.
+func (e *Etcd) watchCertFiles() {
+	if e.cfg.TLSReloadInterval <= 0 {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-e.stopc
+		cancel()
+	}()
+
+	var peerTrackers []*transport.ConnTracker
+	for _, p := range e.Peers {
+		if p.connTracker != nil {
+			peerTrackers = append(peerTrackers, p.connTracker)
+		}
+	}
+	if len(peerTrackers) > 0 {
+		go transport.WatchCertFiles(ctx, e.cfg.PeerTLSInfo, e.cfg.TLSReloadInterval, func() {
+			e.recycleConns("peer", "", peerTrackers, 0)
+			e.Server.ResetPeerConnections()
+		})
+	}
+
+	clientTrackers := make(map[*transport.TLSInfo][]*transport.ConnTracker)
+	groups := make(map[*transport.TLSInfo]string)
+	for _, sctx := range e.sctxs {
+		if sctx.connTracker == nil {
+			continue
+		}
+		tlsinfo := &e.cfg.ClientTLSInfo
+		if sctx.tlsinfo != nil {
+			tlsinfo = sctx.tlsinfo
+		}
+		clientTrackers[tlsinfo] = append(clientTrackers[tlsinfo], sctx.connTracker)
+		groups[tlsinfo] = sctx.group
+	}
+	for tlsinfo, trackers := range clientTrackers {
+		go transport.WatchCertFiles(ctx, *tlsinfo, e.cfg.TLSReloadInterval, func() {
+			e.recycleConns("client", groups[tlsinfo], trackers, e.cfg.TLSReloadRecycleWindow)
+		})
+	}
+}
+
+func (e *Etcd) recycleConns(kind, group string, trackers []*transport.ConnTracker, window time.Duration) {
+	n := 0
+	for _, t := range trackers {
+		n += t.Recycle(window)
+	}
+	fields := []zap.Field{
+		zap.String("listener", kind),
+		zap.Int("connections", n),
+		zap.Duration("window", window),
+	}
+	if group != "" {
+		fields = append(fields, zap.String("client-listener-group", group))
+	}
+	e.GetLogger().Info("certificate files renewed; closing the established connections", fields...)
+}
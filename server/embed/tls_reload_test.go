@@ -0,0 +1,98 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embed
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+)
+
+func TestTLSReloadRecycleClientConns(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	clientURL := url.URL{Scheme: "https", Host: l.Addr().String()}
+	l.Close()
+
+	selfCert := func() transport.TLSInfo {
+		info, cerr := transport.SelfCert(zaptest.NewLogger(t), t.TempDir(), []string{clientURL.Host}, 1)
+		require.NoError(t, cerr)
+		return info
+	}
+	tlsInfo := selfCert()
+
+	cfg := NewConfig()
+	urls := newEmbedURLs(1)
+	curls := []url.URL{clientURL}
+	cfg.ListenClientUrls, cfg.AdvertiseClientUrls = curls, curls
+	cfg.ListenPeerUrls, cfg.AdvertisePeerUrls = urls, urls
+	cfg.InitialCluster = "default=" + urls[0].String()
+	cfg.Dir = t.TempDir()
+	cfg.ClientTLSInfo = tlsInfo
+	cfg.TLSReloadInterval = 20 * time.Millisecond
+	cfg.TLSReloadRecycleWindow = 0
+
+	e, err := StartEtcd(cfg)
+	require.NoError(t, err)
+	defer e.Close()
+	<-e.Server.ReadyNotify()
+
+	dial := func() *tls.Conn {
+		conn, derr := tls.Dial("tcp", clientURL.Host, &tls.Config{InsecureSkipVerify: true, NextProtos: []string{"h2"}})
+		require.NoError(t, derr)
+		return conn
+	}
+	serial := func(conn *tls.Conn) string {
+		return fmt.Sprint(conn.ConnectionState().PeerCertificates[0].SerialNumber)
+	}
+	old := dial()
+	defer old.Close()
+	oldSerial := serial(old)
+
+	renewed := selfCert()
+	for _, f := range [][2]string{{renewed.KeyFile, tlsInfo.KeyFile}, {renewed.CertFile, tlsInfo.CertFile}} {
+		b, rerr := os.ReadFile(f[0])
+		require.NoError(t, rerr)
+		require.NoError(t, os.WriteFile(f[1], b, 0o600))
+	}
+
+	// the connection established with the former certificate is closed
+	require.NoError(t, old.SetReadDeadline(time.Now().Add(5*time.Second)))
+	buf := make([]byte, 1024)
+	for {
+		if _, err = old.Read(buf); err != nil {
+			break
+		}
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		require.False(t, netErr.Timeout(), "the connection was not closed")
+	}
+
+	conn := dial()
+	defer conn.Close()
+	assert.NotEqual(t, oldSerial, serial(conn))
+}
@@ -235,14 +235,36 @@ func (cfg *config) configFromCmdLine() error {
 	cfg.ec.HostWhitelist = flags.UniqueStringsMapFromFlag(cfg.cf.flagSet, "host-whitelist")
 
 	cfg.ec.ClientTLSInfo.AllowedHostnames = flags.StringsFromFlag(cfg.cf.flagSet, "client-cert-allowed-hostname")
+	cfg.ec.ClientTLSInfo.AllowedSPIFFETrustDomains = flags.StringsFromFlag(cfg.cf.flagSet, "client-cert-allowed-spiffe-trust-domain")
+	cfg.ec.ClientTLSInfo.AllowedSPIFFEIDs = flags.StringsFromFlag(cfg.cf.flagSet, "client-cert-allowed-spiffe-id")
+	cfg.ec.ClientCertRoleRules = flags.StringsFromFlag(cfg.cf.flagSet, "client-cert-role-rules")
+	cfg.ec.KeyQuotas = flags.StringsFromFlag(cfg.cf.flagSet, "key-quotas")
+	cfg.ec.ClientProxyProtocolTrustedCIDRs = flags.StringsFromFlag(cfg.cf.flagSet, "client-proxy-protocol-trusted-cidrs")
+	cfg.ec.PeerProxyProtocolTrustedCIDRs = flags.StringsFromFlag(cfg.cf.flagSet, "peer-proxy-protocol-trusted-cidrs")
+	cfg.ec.PeerNetworkFaults = flags.StringsFromFlag(cfg.cf.flagSet, "peer-network-faults")
 	cfg.ec.PeerTLSInfo.AllowedCNs = flags.StringsFromFlag(cfg.cf.flagSet, "peer-cert-allowed-cn")
 	cfg.ec.PeerTLSInfo.AllowedHostnames = flags.StringsFromFlag(cfg.cf.flagSet, "peer-cert-allowed-hostname")
+	cfg.ec.PeerTLSInfo.AllowedSPIFFETrustDomains = flags.StringsFromFlag(cfg.cf.flagSet, "peer-cert-allowed-spiffe-trust-domain")
+	cfg.ec.PeerTLSInfo.AllowedSPIFFEIDs = flags.StringsFromFlag(cfg.cf.flagSet, "peer-cert-allowed-spiffe-id")
 
 	cfg.ec.CipherSuites = flags.StringsFromFlag(cfg.cf.flagSet, "cipher-suites")
+	cfg.ec.ClientCipherSuites = flags.StringsFromFlag(cfg.cf.flagSet, "client-cipher-suites")
+	cfg.ec.PeerCipherSuites = flags.StringsFromFlag(cfg.cf.flagSet, "peer-cipher-suites")
+	cfg.ec.MetricsCipherSuites = flags.StringsFromFlag(cfg.cf.flagSet, "metrics-cipher-suites")
+
+	cfg.ec.MetricsDenylist = flags.StringsFromFlag(cfg.cf.flagSet, "metrics-denylist")
+	cfg.ec.MetricsKeyPrefixes = flags.StringsFromFlag(cfg.cf.flagSet, "metrics-key-prefixes")
+	cfg.ec.LeaseStewardPrefixes = flags.StringsFromFlag(cfg.cf.flagSet, "lease-steward-prefixes")
+	cfg.ec.GRPCHistogramBuckets = flags.Float64sFromFlag(cfg.cf.flagSet, "grpc-histogram-buckets")
+	cfg.ec.QuotaBackendWarningThresholds = flags.Float64sFromFlag(cfg.cf.flagSet, "quota-backend-warning-thresholds")
 
 	cfg.ec.MaxConcurrentStreams = flags.Uint32FromFlag(cfg.cf.flagSet, "max-concurrent-streams")
 
 	cfg.ec.LogOutputs = flags.UniqueStringsFromFlag(cfg.cf.flagSet, "log-outputs")
+	cfg.ec.AuditLogOutputs = flags.StringsFromFlag(cfg.cf.flagSet, "audit-log-outputs")
+	cfg.ec.CDCSinkURLs = flags.StringsFromFlag(cfg.cf.flagSet, "cdc-sink-urls")
+	cfg.ec.MirrorTargetEndpoints = flags.StringsFromFlag(cfg.cf.flagSet, "mirror-target-endpoints")
+	cfg.ec.Namespaces = flags.StringsFromFlag(cfg.cf.flagSet, "namespaces")
 
 	cfg.ec.ClusterState = cfg.cf.clusterState.String()
 
@@ -255,7 +277,7 @@ func (cfg *config) configFromCmdLine() error {
 	}
 
 	// disable default initial-cluster if discovery is set
-	if (cfg.ec.Durl != "" || cfg.ec.DNSCluster != "" || cfg.ec.DNSClusterServiceName != "" || len(cfg.ec.DiscoveryCfg.Endpoints) > 0) && !flags.IsSet(cfg.cf.flagSet, "initial-cluster") {
+	if (cfg.ec.Durl != "" || cfg.ec.DNSCluster != "" || cfg.ec.DNSClusterServiceName != "" || len(cfg.ec.DiscoveryCfg.Endpoints) > 0 || cfg.ec.K8sDiscoveryCfg.LabelSelector != "") && !flags.IsSet(cfg.cf.flagSet, "initial-cluster") {
 		cfg.ec.InitialCluster = ""
 	}
 
@@ -18,8 +18,10 @@ import (
 	errorspkg "errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"runtime"
 	"strings"
+	"syscall"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -212,9 +214,27 @@ func startEtcd(cfg *embed.Config) (<-chan struct{}, <-chan error, error) {
 	case <-e.Server.ReadyNotify(): // wait for e.Server to join the cluster
 	case <-e.Server.StopNotify(): // publish aborted from 'ErrStopped'
 	}
+	go reloadOnSIGHUP(e)
 	return e.Server.StopNotify(), e.Err(), nil
 }
 
+// reloadOnSIGHUP reloads the configuration file of e on SIGHUP until e stops.
+func reloadOnSIGHUP(e *embed.Etcd) {
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, syscall.SIGHUP)
+	defer signal.Stop(sigc)
+	for {
+		select {
+		case <-sigc:
+			if err := e.ReloadConfigFile(); err != nil {
+				e.GetLogger().Warn("failed to reload configuration on SIGHUP", zap.Error(err))
+			}
+		case <-e.Server.StopNotify():
+			return
+		}
+	}
+}
+
 // identifyDataDirOrDie returns the type of the data dir.
 // Dies if the datadir is invalid.
 func identifyDataDirOrDie(lg *zap.Logger, dir string) dirType {
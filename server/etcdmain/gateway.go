@@ -15,16 +15,23 @@
 package etcdmain
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
 	"net"
 	"net/url"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/client/pkg/v3/logutil"
+	"go.etcd.io/etcd/client/pkg/v3/tlsutil"
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/server/v3/proxy/tcpproxy"
 )
 
@@ -36,6 +43,9 @@ var (
 	gatewayInsecureDiscovery     bool
 	gatewayRetryDelay            time.Duration
 	gatewayCA                    string
+	gatewayHealthCheck           string
+	gatewayHealthCheckInterval   time.Duration
+	gatewaySyncInterval          time.Duration
 )
 
 var rootCmd = &cobra.Command{
@@ -75,6 +85,9 @@ func newGatewayStartCommand() *cobra.Command {
 	cmd.Flags().StringSliceVar(&gatewayEndpoints, "endpoints", []string{"127.0.0.1:2379"}, "comma separated etcd cluster endpoints")
 
 	cmd.Flags().DurationVar(&gatewayRetryDelay, "retry-delay", time.Minute, "duration of delay before retrying failed endpoints")
+	cmd.Flags().DurationVar(&gatewaySyncInterval, "endpoints-auto-sync-interval", 0, "interval of the refresh of the endpoints from the cluster member list (0 to disable)")
+	cmd.Flags().DurationVar(&gatewayHealthCheckInterval, "health-check-interval", 0, "interval of the endpoint health checks, which stop routing new connections to the unhealthy endpoints (0 to only retry failed endpoints every retry-delay)")
+	cmd.Flags().StringVar(&gatewayHealthCheck, "health-check", "tcp", "endpoint health check: 'tcp' (dial), 'http' or 'https' (GET /health), 'grpc' or 'grpcs' (gRPC health service); TLS checks verify the endpoints with trusted-ca-file")
 
 	return &cmd
 }
@@ -167,11 +180,23 @@ func startGateway(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	hc, err := gatewayHealthChecker(gatewayHealthCheck, gatewayCA)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
 	tp := tcpproxy.TCPProxy{
-		Logger:          lg,
-		Listener:        l,
-		Endpoints:       srvs.SRVs,
-		MonitorInterval: gatewayRetryDelay,
+		Logger:              lg,
+		Listener:            l,
+		Endpoints:           srvs.SRVs,
+		MonitorInterval:     gatewayRetryDelay,
+		HealthCheckInterval: gatewayHealthCheckInterval,
+		HealthCheck:         hc,
+	}
+
+	if gatewaySyncInterval > 0 {
+		go syncGatewayEndpoints(lg, &tp, srvs.Endpoints, gatewayCA, gatewaySyncInterval)
 	}
 
 	// At this point, etcd gateway listener is initialized
@@ -179,3 +204,96 @@ func startGateway(cmd *cobra.Command, args []string) {
 
 	tp.Run()
 }
+
+// gatewayHealthChecker returns the endpoint health check of the given kind,
+// whose TLS checks verify the endpoints with the CA file if given.
+func gatewayHealthChecker(kind, caFile string) (tcpproxy.HealthCheck, error) {
+	var tlsConfig *tls.Config
+	if kind == "https" || kind == "grpcs" {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		if caFile != "" {
+			pool, err := tlsutil.NewCertPool([]string{caFile})
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = pool
+		}
+	}
+	switch kind {
+	case "tcp":
+		return tcpproxy.TCPHealthCheck, nil
+	case "http", "https":
+		return tcpproxy.HTTPHealthCheck(tlsConfig), nil
+	case "grpc", "grpcs":
+		return tcpproxy.GRPCHealthCheck(tlsConfig), nil
+	default:
+		return nil, fmt.Errorf("unknown health-check %q (expected 'tcp', 'http', 'https', 'grpc' or 'grpcs')", kind)
+	}
+}
+
+// syncGatewayEndpoints replaces the endpoints of the gateway with the client
+// URLs of the cluster members every interval.
+func syncGatewayEndpoints(lg *zap.Logger, tp *tcpproxy.TCPProxy, eps []string, caFile string, interval time.Duration) {
+	cfg := clientv3.Config{
+		Endpoints:   eps,
+		DialTimeout: 5 * time.Second,
+		Logger:      lg.Named("endpoints-sync"),
+	}
+	if caFile != "" {
+		tlsInfo := transport.TLSInfo{TrustedCAFile: caFile}
+		tlscfg, err := tlsInfo.ClientConfig()
+		if err != nil {
+			lg.Error("failed to sync endpoints", zap.Error(err))
+			return
+		}
+		cfg.TLS = tlscfg
+	}
+	c, err := clientv3.New(cfg)
+	if err != nil {
+		lg.Error("failed to sync endpoints", zap.Error(err))
+		return
+	}
+	defer c.Close()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		resp, err := c.MemberList(ctx)
+		cancel()
+		if err != nil {
+			lg.Warn("failed to sync endpoints", zap.Error(err))
+			continue
+		}
+		srvs, addrs := memberEndpoints(resp.Members)
+		if len(srvs) == 0 {
+			lg.Warn("failed to sync endpoints", zap.String("error", "no member client URLs"))
+			continue
+		}
+		tp.SetEndpoints(srvs)
+		c.SetEndpoints(addrs...)
+	}
+}
+
+// memberEndpoints returns the TCP client endpoints of the started voting
+// members, as SRV records and addresses.
+func memberEndpoints(members []*etcdserverpb.Member) (srvs []*net.SRV, addrs []string) {
+	for _, m := range members {
+		if m.IsLearner {
+			continue
+		}
+		for _, u := range m.ClientURLs {
+			pu, err := url.Parse(u)
+			if err != nil || (pu.Scheme != "http" && pu.Scheme != "https") {
+				continue
+			}
+			port, err := strconv.ParseUint(pu.Port(), 10, 16)
+			if err != nil {
+				continue
+			}
+			srvs = append(srvs, &net.SRV{Target: pu.Hostname(), Port: uint16(port)})
+			addrs = append(addrs, pu.Host)
+		}
+	}
+	return srvs, addrs
+}
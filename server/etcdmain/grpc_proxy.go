@@ -55,6 +55,7 @@ import (
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3election/v3electionpb"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3lock/v3lockpb"
 	"go.etcd.io/etcd/server/v3/proxy/grpcproxy"
+	"go.etcd.io/etcd/server/v3/proxy/grpcproxy/cache"
 )
 
 var (
@@ -105,6 +106,10 @@ var (
 
 	grpcProxyDebug bool
 
+	grpcProxyCacheMaxEntries        int
+	grpcProxyCacheTTL               time.Duration
+	grpcProxyCacheWatchInvalidation bool
+
 	// GRPC keep alive related options.
 	grpcKeepAliveMinTime  time.Duration
 	grpcKeepAliveTimeout  time.Duration
@@ -151,7 +156,7 @@ func newGRPCProxyStartCommand() *cobra.Command {
 	cmd.Flags().StringVar(&grpcProxyResolverPrefix, "resolver-prefix", "", "prefix to use for registering proxy (must be shared with other grpc-proxy members)")
 	cmd.Flags().IntVar(&grpcProxyResolverTTL, "resolver-ttl", 0, "specify TTL, in seconds, when registering proxy endpoints")
 	cmd.Flags().StringVar(&grpcProxyNamespace, "namespace", "", "string to prefix to all keys for namespacing requests")
-	cmd.Flags().BoolVar(&grpcProxyEnablePprof, "enable-pprof", false, `Enable runtime profiling data via HTTP server. Address is at client URL + "/debug/pprof/"`)
+	cmd.Flags().BoolVar(&grpcProxyEnablePprof, "enable-pprof", false, `Enable runtime profiling data via HTTP server. Address is at client URL + "/debug/pprof/", with the full goroutine profile at "/debug/fgprof" and the block and mutex profile rates at "/debug/pprof/rates".`)
 	cmd.Flags().StringVar(&grpcProxyDataDir, "data-dir", "default.proxy", "Data directory for persistent data")
 	cmd.Flags().IntVar(&grpcMaxCallSendMsgSize, "max-send-bytes", defaultGRPCMaxCallSendMsgSize, "message send limits in bytes (default value is 1.5 MiB)")
 	cmd.Flags().IntVar(&grpcMaxCallRecvMsgSize, "max-recv-bytes", math.MaxInt32, "message receive limits in bytes (default value is math.MaxInt32)")
@@ -159,6 +164,11 @@ func newGRPCProxyStartCommand() *cobra.Command {
 	cmd.Flags().DurationVar(&grpcKeepAliveInterval, "grpc-keepalive-interval", embed.DefaultGRPCKeepAliveInterval, "Frequency duration of server-to-client ping to check if a connection is alive (0 to disable).")
 	cmd.Flags().DurationVar(&grpcKeepAliveTimeout, "grpc-keepalive-timeout", embed.DefaultGRPCKeepAliveTimeout, "Additional duration of wait before closing a non-responsive connection (0 to disable).")
 
+	// range response cache
+	cmd.Flags().IntVar(&grpcProxyCacheMaxEntries, "cache-max-entries", cache.DefaultMaxEntries, "Maximum number of range responses cached for serializable requests.")
+	cmd.Flags().DurationVar(&grpcProxyCacheTTL, "cache-ttl", 0, "Time after which a cached range response expires (0 to never expire).")
+	cmd.Flags().BoolVar(&grpcProxyCacheWatchInvalidation, "cache-watch-invalidation", false, "Invalidate the cached range responses from a watch on the keyspace, so that the writes not made through the proxy are not served from the cache.")
+
 	// client TLS for connecting to server
 	cmd.Flags().StringVar(&grpcProxyCert, "cert", "", "identify secure connections with etcd servers using this TLS certificate file")
 	cmd.Flags().StringVar(&grpcProxyKey, "key", "", "identify secure connections with etcd servers using this TLS key file")
@@ -311,6 +321,14 @@ func checkArgs() {
 		fmt.Fprintln(os.Stderr, fmt.Errorf("invalid advertise-client-url %q", grpcProxyAdvertiseClientURL))
 		os.Exit(1)
 	}
+	if grpcProxyCacheMaxEntries <= 0 {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("invalid cache-max-entries %d", grpcProxyCacheMaxEntries))
+		os.Exit(1)
+	}
+	if grpcProxyCacheTTL < 0 {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("invalid cache-ttl %v", grpcProxyCacheTTL))
+		os.Exit(1)
+	}
 	if grpcProxyListenAutoTLS && selfSignedCertValidity == 0 {
 		fmt.Fprintln(os.Stderr, fmt.Errorf("selfSignedCertValidity is invalid,it should be greater than 0"))
 		os.Exit(1)
@@ -491,7 +509,11 @@ func newGRPCProxyServer(lg *zap.Logger, client *clientv3.Client) *grpc.Server {
 		client.KV, _, _ = leasing.NewKV(client, grpcProxyLeasing)
 	}
 
-	kvp, _ := grpcproxy.NewKvProxy(client)
+	kvp, _ := grpcproxy.NewKvProxyWithConfig(client.Ctx(), client, grpcproxy.KvProxyConfig{
+		CacheMaxEntries:        grpcProxyCacheMaxEntries,
+		CacheTTL:               grpcProxyCacheTTL,
+		CacheWatchInvalidation: grpcProxyCacheWatchInvalidation,
+	})
 	watchp, _ := grpcproxy.NewWatchProxy(client.Ctx(), lg, client)
 	if grpcProxyResolverPrefix != "" {
 		grpcproxy.Register(lg, client, grpcProxyResolverPrefix, grpcProxyAdvertiseClientURL, grpcProxyResolverTTL)
@@ -24,6 +24,7 @@ import (
 
 	cconfig "go.etcd.io/etcd/server/v3/config"
 	"go.etcd.io/etcd/server/v3/embed"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/k8sdiscovery"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/rafthttp"
 	"go.etcd.io/etcd/server/v3/features"
 )
@@ -69,28 +70,89 @@ Member:
     List of URLs to listen on for peer traffic.
   --listen-client-urls 'http://localhost:2379'
     List of URLs to listen on for client grpc traffic and http as long as --listen-client-http-urls is not specified.
+    The quic:// URLs serve grpc traffic over QUIC and require the QUICClientListener feature gate.
   --listen-client-http-urls ''
     List of URLs to listen on for http only client traffic. Enabling this flag removes http services from --listen-client-urls.
   --max-snapshots '` + strconv.Itoa(embed.DefaultMaxSnapshots) + `'
     Maximum number of snapshot files to retain (0 is unlimited). Deprecated in v3.6 and will be decommissioned in v3.7.
   --max-wals '` + strconv.Itoa(embed.DefaultMaxWALs) + `'
     Maximum number of wal files to retain (0 is unlimited).
+  --wal-compression 'none'
+    Compression of the entries saved to the WAL ('none', 'snappy' or 'zstd'). The WAL files written before keep their compression.
   --memory-mlock
     Enable to enforce etcd pages (in particular bbolt) to stay in RAM.
   --quota-backend-bytes '0'
     Raise alarms when backend size exceeds the given quota (0 defaults to low space quota).
+  --quota-backend-grace 'false'
+    Check the quota against the backend size in use, and reject the writes exceeding it without raising the NOSPACE alarm, so that compactions make room for writes again.
+  --quota-backend-warning-thresholds ''
+    Comma-separated list of fractions of the quota above which the backend size is warned about.
+  --key-quotas ''
+    Comma-separated list of quotas bounding the keys of a prefix, of a namespace or of the ranges a role may write, as '<prefix|namespace|role>:<name>=<max-bytes>/<max-keys>'.
+  --namespaces ''
+    Comma-separated list of namespaces the client requests may isolate their keys in.
+  --namespaces-key-prefix '/namespaces/'
+    Prefix of the keys of the namespaces, each isolated under '<prefix><namespace>/'.
+  --experimental-storage-engine 'bbolt'
+    Storage engine of a new backend ('bbolt' or 'log'). An existing backend keeps the engine it was created with.
   --backend-bbolt-freelist-type 'map'
     BackendFreelistType specifies the type of freelist that boltdb backend uses(array and map are supported types).
   --backend-batch-interval ''
     BackendBatchInterval is the maximum time before commit the backend transaction.
   --backend-batch-limit '0'
     BackendBatchLimit is the maximum operations before commit the backend transaction.
+  --backend-batch-adaptive 'false'
+    Adapt the backend batch interval to the commit latency and the batch limit to the write rate, within their bounds.
+  --backend-batch-interval-min '10ms'
+    Minimum backend batch interval adapted by --backend-batch-adaptive.
+  --backend-batch-interval-max '1s'
+    Maximum backend batch interval adapted by --backend-batch-adaptive.
+  --backend-batch-limit-min '1000'
+    Minimum backend batch limit adapted by --backend-batch-adaptive.
+  --backend-batch-limit-max '100000'
+    Maximum backend batch limit adapted by --backend-batch-adaptive.
+  --backend-reclaim-free-pages 'false'
+    Return the free pages of the backend to the filesystem in the background, without defragmentation (linux only).
+  --backend-reclaim-threshold '0.5'
+    Fraction of the backend that must be free for --backend-reclaim-free-pages to reclaim its free pages.
+  --backend-reclaim-interval '10s'
+    Interval between the rounds of --backend-reclaim-free-pages.
   --max-txn-ops '128'
     Maximum number of operations permitted in a transaction.
   --max-request-bytes '1572864'
     Maximum client request size in bytes the server will accept.
   --max-concurrent-streams 'math.MaxUint32'
     Maximum concurrent streams that each client can open at a time.
+  --request-deadline-margin '0s'
+    Minimum time left before the client deadline for the server to start serving a unary request (0 to disable).
+  --max-concurrent-expensive-ranges '0'
+    Maximum number of expensive ranges read at a time, the others waiting for their turn (0 for no limit).
+  --expensive-range-limit '` + fmt.Sprint(embed.DefaultExpensiveRangeLimit) + `'
+    Limit above which a range over multiple keys is expensive for --max-concurrent-expensive-ranges. Ranges over multiple keys without a limit are always expensive.
+  --max-caller-labels '64'
+    Maximum number of distinct client caller labels tracked in the per-caller request metrics.
+  --client-identity-metrics 'false'
+    Label the request metrics and the slow request logs with the client identity, its auth user or else its certificate common name. The metrics track up to '--max-caller-labels' identities.
+  --client-rate-limit-qps '0'
+    Maximum number of requests per second of each client identity, its auth user or else its certificate common name (0 to disable).
+  --client-rate-limit-burst '0'
+    Maximum burst of requests of each client identity (0 defaults to --client-rate-limit-qps).
+  --client-rate-limit-bytes '0'
+    Maximum number of request and response bytes per second of each client identity (0 to disable).
+  --qos-max-inflight-requests '0'
+    Maximum number of unary client requests served at a time, the others waiting in a queue per priority class (0 to disable).
+  --admission-webhook-url ''
+    HTTPS URL of a webhook reviewing the puts, deletes and txns with writes before they are committed (empty to disable).
+  --admission-webhook-ca-file ''
+    Path to the CA verifying the certificate of the admission webhook (empty to use the system roots).
+  --admission-webhook-cert-file ''
+    Path to the client certificate authenticating etcd to the admission webhook.
+  --admission-webhook-key-file ''
+    Path to the key of the client certificate authenticating etcd to the admission webhook.
+  --admission-webhook-timeout '5s'
+    Maximum duration of an admission webhook review.
+  --admission-webhook-failure-policy 'fail'
+    Whether to reject ('fail') or admit ('ignore') the requests the admission webhook failed to review.
   --grpc-keepalive-min-time '5s'
     Minimum duration interval that a client should wait before pinging server.
   --grpc-keepalive-interval '2h'
@@ -101,8 +163,20 @@ Member:
     Enable to set socket option SO_REUSEPORT on listeners allowing rebinding of a port already in use.
   --socket-reuse-address 'false'
     Enable to set socket option SO_REUSEADDR on listeners allowing binding to an address in TIME_WAIT state.
+  --client-proxy-protocol 'false'
+    Read the PROXY protocol (v1 or v2) header at the start of the client connections from the trusted networks. Their connections without a header are rejected.
+  --client-proxy-protocol-trusted-cidrs ''
+    Comma-separated list of the networks, in CIDR notation, the PROXY protocol header of the client connections is read from.
+  --peer-proxy-protocol 'false'
+    Read the PROXY protocol (v1 or v2) header at the start of the peer connections from the trusted networks. Their connections without a header are rejected.
+  --peer-proxy-protocol-trusted-cidrs ''
+    Comma-separated list of the networks, in CIDR notation, the PROXY protocol header of the peer connections is read from.
+  --enable-socket-activation 'false'
+    Serve the listening sockets passed by systemd socket activation (LISTEN_FDS) for the peer, client and metrics URLs they are bound to.
   --enable-grpc-gateway
     Enable GRPC gateway.
+  --enable-grpc-reflection 'false'
+    Enable the gRPC server reflection service on the client gRPC server, for tools like grpcurl. Meant for development environments.
   --raft-read-timeout '` + rafthttp.DefaultConnReadTimeout.String() + `'
     Read timeout set on each rafthttp connection
   --raft-write-timeout '` + rafthttp.DefaultConnWriteTimeout.String() + `'
@@ -160,6 +234,18 @@ Clustering:
     DNS srv domain used to bootstrap the cluster.
   --discovery-srv-name ''
     Suffix to the dns srv name queried when bootstrapping.
+  --discovery-k8s-label-selector ''
+    Kubernetes discovery: label selector of the pods of the members used to bootstrap the cluster.
+  --discovery-k8s-namespace ''
+    Kubernetes discovery: namespace of the pods of the members, by default the namespace of the local pod.
+  --discovery-k8s-cluster-size 0
+    Kubernetes discovery: number of members of the initial cluster.
+  --discovery-k8s-peer-port ` + strconv.Itoa(k8sdiscovery.DefaultPeerPort) + `
+    Kubernetes discovery: port of the peer URLs of the members.
+  --discovery-k8s-cluster-domain '` + k8sdiscovery.DefaultClusterDomain + `'
+    Kubernetes discovery: DNS domain of the Kubernetes cluster.
+  --discovery-k8s-timeout '` + k8sdiscovery.DefaultTimeout.String() + `'
+    Kubernetes discovery: maximum duration to wait for the pods of the initial cluster.
   --strict-reconfig-check '` + strconv.FormatBool(embed.DefaultStrictReconfigCheck) + `'
     Reject reconfiguration requests that would cause quorum loss.
   --pre-vote 'true'
@@ -167,7 +253,9 @@ Clustering:
   --auto-compaction-retention '0'
     Auto compaction retention length. 0 means disable auto compaction.
   --auto-compaction-mode 'periodic'
-    Interpret 'auto-compaction-retention' one of: periodic|revision. 'periodic' for duration based retention, defaulting to hours if no time unit is provided (e.g. '5m'). 'revision' for revision number based retention.
+    Interpret 'auto-compaction-retention' one of: periodic|revision|size. 'periodic' for duration based retention, defaulting to hours if no time unit is provided (e.g. '5m'). 'revision' for revision number based retention. 'size' for compacting once the reclaimable history exceeds a size in bytes (e.g. '1GB').
+  --compaction-control-key ''
+    Key whose value, when written, is the revision to compact the key-value store to (empty disables).
   --v2-deprecation '` + string(cconfig.V2DeprDefault) + `'
     Phase of v2store deprecation. Deprecated and scheduled for removal in v3.8. The default value is enforced, ignoring user input.
     Supported values:
@@ -189,8 +277,18 @@ Security:
     Path to an explicit peer client TLS key file otherwise key file will be used when client auth is required.
   --client-crl-file ''
     Path to the client certificate revocation list file.
+  --client-crl-refresh-interval '0s'
+    Duration the client certificate revocation list file is cached for before it is read again. 0 reads it on each handshake.
+  --client-ocsp-check ''
+    Check the client certificates with their OCSP responder: 'soft-fail' accepts them when the responder cannot be queried, 'hard-fail' rejects them. Empty disables the check.
   --client-cert-allowed-hostname ''
     Comma-separated list of SAN hostnames for client cert authentication.
+  --client-cert-allowed-spiffe-trust-domain ''
+    Comma-separated list of allowed trust domains of the SPIFFE ID of client certs.
+  --client-cert-allowed-spiffe-id ''
+    Comma-separated list of allowed SPIFFE IDs of client certs, as path.Match patterns, e.g. 'spiffe://example.org/ns/prod/*'.
+  --client-cert-role-rules ''
+    Comma-separated list of rules granting roles to the clients authenticated by their cert, as '<cn|ou|san|spiffe>:<pattern>=<role>'. The clients authenticated by a cert without CN are the user named after its SPIFFE ID.
   --trusted-ca-file ''
     Path to the client server TLS trusted CA cert file.
   --auto-tls 'false'
@@ -211,12 +309,20 @@ Security:
     Comma-separated list of allowed CNs for inter-peer TLS authentication.
   --peer-cert-allowed-hostname ''
     Comma-separated list of allowed SAN hostnames for inter-peer TLS authentication.
+  --peer-cert-allowed-spiffe-trust-domain ''
+    Comma-separated list of allowed trust domains of the SPIFFE ID of peer certs for inter-peer TLS authentication.
+  --peer-cert-allowed-spiffe-id ''
+    Comma-separated list of allowed SPIFFE IDs of peer certs for inter-peer TLS authentication, as path.Match patterns.
   --peer-auto-tls 'false'
     Peer TLS using self-generated certificates if --peer-key-file and --peer-cert-file are not provided.
   --self-signed-cert-validity '1'
     The validity period of the client and peer certificates that are automatically generated by etcd when you specify ClientAutoTLS and PeerAutoTLS, the unit is year, and the default is 1.
   --peer-crl-file ''
     Path to the peer certificate revocation list file.
+  --peer-crl-refresh-interval '0s'
+    Duration the peer certificate revocation list file is cached for before it is read again. 0 reads it on each handshake.
+  --peer-ocsp-check ''
+    Check the peer certificates with their OCSP responder: 'soft-fail' accepts them when the responder cannot be queried, 'hard-fail' rejects them. Empty disables the check.
   --cipher-suites ''
     Comma-separated list of supported TLS cipher suites between client/server and peers (empty will be auto-populated by Go).
   --cors '*'
@@ -227,22 +333,72 @@ Security:
     Minimum TLS version supported by etcd. Possible values: TLS1.2, TLS1.3.
   --tls-max-version ''
     Maximum TLS version supported by etcd. Possible values: TLS1.2, TLS1.3 (empty will be auto-populated by Go).
+  --tls-reload-interval '0s'
+    Interval between two checks of the renewal of the client and peer certificate files. When renewed, the peer connections are re-established and the client connections closed, for them to use the renewed certificates. 0 disables the checks.
+  --tls-reload-recycle-window '` + embed.DefaultTLSReloadRecycleWindow.String() + `'
+    Duration over which the established client connections are closed once the certificates are renewed, for the clients not to reconnect all at once.
+  --client-tls-min-version ''
+    Minimum TLS version of the client listeners, overriding --tls-min-version.
+  --client-tls-max-version ''
+    Maximum TLS version of the client listeners, overriding --tls-max-version.
+  --client-cipher-suites ''
+    Comma-separated list of TLS cipher suites of the client listeners, overriding --cipher-suites.
+  --peer-tls-min-version ''
+    Minimum TLS version between peers, overriding --tls-min-version.
+  --peer-tls-max-version ''
+    Maximum TLS version between peers, overriding --tls-max-version.
+  --peer-cipher-suites ''
+    Comma-separated list of TLS cipher suites between peers, overriding --cipher-suites.
+  --metrics-tls-min-version ''
+    Minimum TLS version of the https metrics listeners, overriding the one of the client listeners.
+  --metrics-tls-max-version ''
+    Maximum TLS version of the https metrics listeners, overriding the one of the client listeners.
+  --metrics-cipher-suites ''
+    Comma-separated list of TLS cipher suites of the https metrics listeners, overriding the ones of the client listeners.
 
 Auth:
   --auth-token 'simple'
-    Specify a v3 authentication token type and its options ('simple' or 'jwt').
+    Specify a v3 authentication token type and its options ('simple', 'jwt' or 'oidc').
   --bcrypt-cost ` + fmt.Sprintf("%d", bcrypt.DefaultCost) + `
     Specify the cost / strength of the bcrypt algorithm for hashing auth passwords. Valid values are between ` + fmt.Sprintf("%d", bcrypt.MinCost) + ` and ` + fmt.Sprintf("%d", bcrypt.MaxCost) + `.
   --auth-token-ttl 300
     Time (in seconds) of the auth-token-ttl.
+  --auth-lockout-user-failures 0
+    Number of consecutive authentication failures locking out a user. 0 disables the lockout.
+  --auth-lockout-source-failures 0
+    Number of consecutive authentication failures locking out a client address, except the loopback and proxied ones. 0 disables the lockout.
+  --auth-lockout-duration '1s'
+    Duration of a first authentication lockout, doubled on each further failure.
+  --auth-lockout-max-duration '15m0s'
+    Maximum duration of an authentication lockout, after which failures are also forgotten.
+  --auth-password-max-age '0s'
+    Duration a password is valid for after it is set. 0 disables the expiry of passwords. Root's password expires too.
+  --auth-password-grace-period '0s'
+    Duration an expired password still authenticates its user for, so that the user can change it.
 
 Profiling and Monitoring:
   --enable-pprof 'false'
-    Enable runtime profiling data via HTTP server. Address is at client URL + "/debug/pprof/"
+    Enable runtime profiling data via HTTP server. Address is at client URL + "/debug/pprof/", with the full goroutine profile at "/debug/fgprof" and the block and mutex profile rates at "/debug/pprof/rates".
+  --enable-config-reload 'false'
+    Enable the reload of the configuration file via HTTP server. Address is at client URL + "/config/reload". The configuration file is also reloaded on SIGHUP.
+  --enable-log-endpoint 'false'
+    Enable the change of the log level, of the levels of the raft, mvcc and auth subsystems and of the log sampling at runtime via HTTP server, for root users when auth is enabled. Address is at client URL + "/debug/log".
+  --enable-failpoints-endpoint 'false'
+    Enable the failpoints via HTTP server, for root users when auth is enabled. Address is at client URL + "/debug/failpoints". Requires a binary built with the failpoints tag.
   --metrics 'basic'
     Set level of detail for exported metrics, specify 'extensive' to include server side grpc histogram metrics.
+  --grpc-histogram-buckets ''
+    Comma-separated list of bucket boundaries in seconds of the server side grpc histogram metrics (empty for the default buckets). Requires '--metrics=extensive'.
   --listen-metrics-urls ''
     List of URLs to listen on for the /metrics and /health endpoints. For https, the client URL TLS info is used.
+  --metrics-denylist ''
+    Comma-separated list of metric name prefixes not to expose on /metrics (e.g. 'grpc_server_handling_seconds').
+  --metrics-key-prefixes ''
+    Comma-separated list of key prefixes whose number of keys and total size of values are exported as metrics.
+  --serializable-health-check 'false'
+    Check the local member with a serializable read instead of a quorum read on /health, unless overridden by the 'serializable' query parameter.
+  --health-check-timeout '0s'
+    Timeout of the read done by /health, unless overridden by the 'timeout' query parameter (0 to use the server request timeout).
 
 Logging:
   --logger 'zap'
@@ -257,8 +413,18 @@ Logging:
     Enable log rotation of a single log-outputs file target.
   --log-rotation-config-json '{"maxsize": 100, "maxage": 0, "maxbackups": 0, "localtime": false, "compress": false}'
     Configures log rotation if enabled with a JSON logger config. MaxSize(MB), MaxAge(days,0=no limit), MaxBackups(0=no limit), LocalTime(use computers local time), Compress(gzip)".
+  --audit-log-outputs ''
+    Comma-separated list of audit log targets for mutating requests: 'stderr', 'stdout', 'syslog' or a file path (empty disables audit logging).
+  --audit-log-rotation-config-json '{"maxsize": 100, "maxage": 0, "maxbackups": 0, "localtime": false, "compress": false}'
+    Configures rotation of audit log file targets with a JSON logger config, in the same format as '--log-rotation-config-json'.
   --warning-unary-request-duration '300ms'
     Set time duration after which a warning is logged if a unary request takes more than this duration.
+  --log-slow-requests-above '0s'
+    Log every unary request slower than this duration with its queue wait, raft, apply and backend latency (0 to disable).
+  --log-slow-requests-sample-initial '10'
+    Number of slow requests logged each second before sampling with '--log-slow-requests-sample-thereafter'.
+  --log-slow-requests-sample-thereafter '100'
+    Log only every Nth of the remaining slow requests of each second (0 to drop them).
 
 Distributed tracing:
   --enable-distributed-tracing 'false'
@@ -271,12 +437,20 @@ Distributed tracing:
     Distributed tracing instance ID, must be unique per each etcd instance.
   --distributed-tracing-sampling-rate '0'
     Number of samples to collect per million spans for distributed tracing.
+  --enable-otlp-metrics 'false'
+    Enable pushing the metrics exposed on /metrics to an OpenTelemetry Collector.
+  --otlp-metrics-address 'localhost:4317'
+    Address of the OpenTelemetry Collector to push metrics to.
+  --otlp-metrics-interval '1m0s'
+    Interval between metrics pushes to the OpenTelemetry Collector.
 
 Features:
   --corrupt-check-time '0s'
     Duration of time between cluster corruption check passes.
   --compact-hash-check-time '1m'
     Duration of time between leader checks followers compaction hashes.
+  --compact-hash-check-quarantine 'false'
+    Quarantine the members whose compaction hash diverged from the majority, which reject client requests until the alarm is disarmed, instead of raising a cluster-wide corruption alarm.
   --compaction-batch-limit 1000
     CompactionBatchLimit sets the maximum revisions deleted in each compaction batch.
   --peer-skip-client-san-verification 'false'
@@ -285,16 +459,118 @@ Features:
     Duration of periodical watch progress notification.
   --warning-apply-duration '100ms'
     Warning is generated if requests take more than this duration.
+  --apply-backlog-alert-threshold '0'
+    Number of committed entries waiting to be applied above which etcd_server_apply_backlog_threshold_crossed_total is incremented (0 to disable).
+  --apply-backlog-backpressure-threshold '0'
+    Number of committed entries waiting to be applied above which new proposals wait for the backlog to drain (0 to disable).
+  --key-access-sample-rate '0'
+    Fraction of range requests, between 0 and 1, whose keys get their last access time recorded (0 to disable).
+  --backup-url ''
+    URL of the sink the leader continuously backs up to, e.g. file:///var/backup/etcd (empty to disable).
+  --backup-interval '10s'
+    Interval between two shipments of the committed raft entries to the backup sink.
+  --backup-snapshot-interval '24h0m0s'
+    Interval between two base snapshots shipped to the backup sink.
+  --backup-retention '7'
+    Number of base snapshots kept in the backup sink (0 to keep all).
+  --cdc-sink-urls ''
+    Comma-separated list of URLs of the sinks the leader publishes the key-value events to, e.g. https://cdc.example.com/events (empty to disable).
+  --cdc-checkpoint-key-prefix '/etcd/cdc-checkpoint/'
+    Prefix of the keys checkpointing the events published to each CDC sink.
+  --mirror-target-endpoints ''
+    Comma-separated list of endpoints of the cluster the leader mirrors the keys to (empty to disable).
+  --mirror-target-cert-file ''
+    Path to the client certificate for the connections to the mirror target cluster.
+  --mirror-target-key-file ''
+    Path to the client key for the connections to the mirror target cluster.
+  --mirror-target-trusted-ca-file ''
+    Path to the CA verifying the mirror target cluster.
+  --mirror-prefix ''
+    Prefix of the keys mirrored (empty for all the keys).
+  --mirror-dest-prefix ''
+    Prefix replacing --mirror-prefix in the keys of the mirror target cluster (defaults to --mirror-prefix).
+  --mirror-no-dest-prefix 'false'
+    Strip --mirror-prefix from the keys of the mirror target cluster.
+  --mirror-checkpoint-key '/etcd/mirror-checkpoint'
+    Key of the mirror target cluster holding the revision up to which the keys were mirrored.
+  --mirror-max-txn-ops '128'
+    Maximum number of operations of a txn to the mirror target cluster.
+  --auto-snapshot-interval '0s'
+    Interval between two snapshots of the backend saved to --auto-snapshot-dir (0 to disable).
+  --auto-snapshot-dir ''
+    Directory of the auto snapshots.
+  --auto-snapshot-retention '5'
+    Number of auto snapshots of the member kept in --auto-snapshot-dir (0 to keep all).
+  --auto-snapshot-leader-only 'false'
+    Save the auto snapshots only while the member is the leader.
+  --auto-defrag-schedule ''
+    Cron expression (e.g. '0 3 * * *') of the times the member defragments its backend, in its local time (empty to disable).
+  --auto-defrag-ratio '` + fmt.Sprint(embed.DefaultAutoDefragRatio) + `'
+    Minimum ratio of the backend size not in use for the auto defragmentation to run.
+  --auto-defrag-transfer-leadership 'false'
+    Transfer the leadership before the auto defragmentation of the leader, which skips it otherwise.
+  --auto-defrag-lock-key '` + embed.DefaultAutoDefragLockKey + `'
+    Key locked by the member running the auto defragmentation, so that the members defragment one at a time.
+  --lease-steward-prefixes ''
+    Comma-separated list of lock and election key prefixes the leader looks for keys without a live lease in (empty to disable).
+  --lease-steward-interval '` + embed.DefaultLeaseStewardInterval.String() + `'
+    Interval between two scans of --lease-steward-prefixes.
+  --lease-steward-delete 'false'
+    Delete the keys found without a live lease by two scans in a row, instead of only reporting them.
+  --learner-auto-promote-duration '0s'
+    Time a learner has to stay caught up with the leader before being promoted to a voting member (0 to disable).
+  --learner-auto-promote-max-lag '` + fmt.Sprint(embed.DefaultLearnerAutoPromoteMaxLag) + `'
+    Maximum number of raft entries a learner caught up with the leader lags behind it.
+  --follower-lag-alarm-threshold '0'
+    Number of raft entries a voting follower may lag behind the leader before the leader raises a FOLLOWER_LAG alarm for it (0 to disable).
+  --follower-lag-alarm-duration '` + embed.DefaultFollowerLagAlarmDuration.String() + `'
+    Time a follower has to stay behind --follower-lag-alarm-threshold before the FOLLOWER_LAG alarm is raised. The alarm is cleared once the follower caught up again.
+  --leader-lease-reads 'false'
+    Serve linearizable reads on the leader from its leader lease, an election timeout after a quorum last confirmed its leadership, instead of a ReadIndex round trip.
+  --leader-lease-clock-drift '` + embed.DefaultLeaderLeaseClockDrift.String() + `'
+    Maximum clock drift between the members over an election timeout, by which the leader lease is shortened. Must be below --election-timeout.
+  --witness 'false'
+    Run the member as a witness, voting in raft but storing no keys and serving no client requests. A witness never stays the leader, it hands the leadership over to another member. Once published to the cluster, it must always be started with --witness.
+  --encryption-kek-file ''
+    File of the key encryption keys of the backend and WAL encryption at rest, one '<id>:<base64 key>' line per key, the current key first. A key can be removed once no WAL file wraps a data encryption key with it.
+  --encryption-kms-url ''
+    URL of the KMS webhook wrapping the data encryption keys of the backend and WAL encryption at rest.
   --bootstrap-defrag-threshold-megabytes
     Enable the defrag during etcd server bootstrap on condition that it will free at least the provided threshold of disk space. Needs to be set to non-zero value to take effect.
   --max-learners '1'
     Set the max number of learner members allowed in the cluster membership.
   --compaction-sleep-interval
     Sets the sleep interval between each compaction batch.
+  --compaction-target-commit-latency 0
+    Grows the sleep between compaction batches while their commits are slower than this latency. 0 keeps the sleep fixed.
+  --compaction-workers
+    Sets the number of workers compacting concurrently while no foreground requests are served.
+  --slow-watcher-max-backlog 0
+    Maximum number of revisions a slow watcher may fall behind before the slow watcher policy is applied. 0 disables the limit.
+  --slow-watcher-policy 'throttle'
+    Policy applied to slow watchers exceeding --slow-watcher-max-backlog: 'throttle', 'compact' or 'cancel'.
+  --max-watch-streams 0
+    Maximum number of watch streams served; the streams opened past it are rejected. 0 disables the limit.
+  --max-watchers-per-stream 0
+    Maximum number of watchers on each watch stream; the watchers created past it are canceled. 0 disables the limit.
   --downgrade-check-time
     Duration of time between two downgrade status checks.
   --snapshot-catchup-entries
     Number of entries for a slow follower to catch up after compacting the raft storage entries.
+  --snapshot-send-rate-limit 0
+    Maximum number of bytes per second of the snapshots sent to clients. 0 disables the limit.
+  --peer-snapshot-send-rate-limit 0
+    Maximum number of bytes per second of the snapshots sent to peers. 0 disables the limit.
+  --peer-address-refresh-interval '` + embed.DefaultPeerAddressRefreshInterval.String() + `'
+    Interval at which the host names of the peer URLs are re-resolved, resetting the connections with the peers whose addresses changed. 0 disables the re-resolution.
+  --peer-network-faults ''
+    Comma-separated list of faults injected in the messages sent to peers, as '<peer-url>=<latency>/<jitter>/<drop-rate>'. For testing only.
+  --peer-network-faults-seed 0
+    Seed of the random faults injected in the messages sent to peers. For testing only.
+  --enable-leader-change-events 'false'
+    Emit a structured log event with the old leader, new leader and term on every leadership change.
+  --leader-change-event-key ''
+    Key the newly elected leader writes leadership change events to as JSON, so that clients can watch it. Empty disables writing.
 
 Unsafe feature:
   --force-new-cluster 'false'
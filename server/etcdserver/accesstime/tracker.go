@@ -0,0 +1,128 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package accesstime tracks when keys were last read, to help find cold keys.
+package accesstime
+
+import (
+	"bytes"
+	"container/list"
+	"math/rand/v2"
+	"sort"
+	"sync"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+)
+
+// Access is the last recorded read of a key.
+type Access struct {
+	Key        []byte
+	LastAccess time.Time
+}
+
+// maxKeys is the number of keys a Tracker records at most.
+const maxKeys = 1 << 20
+
+// Tracker records the last access time of the keys returned by a sample of
+// the reads. Once it records maxKeys keys, reading another key forgets the
+// least recently read one, which also eventually forgets the deleted keys.
+type Tracker struct {
+	sampleRate float64
+	maxKeys    int
+	now        func() time.Time
+
+	mu sync.Mutex
+	// last maps a key to its element in lru.
+	last map[string]*list.Element
+	// lru holds the recorded accesses, most recently read first.
+	lru *list.List
+}
+
+// access is a recorded read of key at the Unix time in nanoseconds ts.
+type access struct {
+	key string
+	ts  int64
+}
+
+// NewTracker returns a Tracker recording the given fraction of reads,
+// between 0 (none) and 1 (all).
+func NewTracker(sampleRate float64) *Tracker {
+	return &Tracker{
+		sampleRate: sampleRate,
+		maxKeys:    maxKeys,
+		now:        time.Now,
+		last:       make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+}
+
+// Observe records a read returning kvs, if the read is sampled.
+func (t *Tracker) Observe(kvs []*mvccpb.KeyValue) {
+	if len(kvs) == 0 || (t.sampleRate < 1 && rand.Float64() >= t.sampleRate) {
+		return
+	}
+	now := t.now().UnixNano()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, kv := range kvs {
+		if e, ok := t.last[string(kv.Key)]; ok {
+			e.Value.(*access).ts = now
+			t.lru.MoveToFront(e)
+			continue
+		}
+		if t.lru.Len() >= t.maxKeys {
+			oldest := t.lru.Back()
+			delete(t.last, oldest.Value.(*access).key)
+			t.lru.Remove(oldest)
+		}
+		t.last[string(kv.Key)] = t.lru.PushFront(&access{key: string(kv.Key), ts: now})
+	}
+}
+
+// Range returns the accesses of the recorded keys in [key, end), least
+// recently read first, with the same key and end conventions as a range
+// request. At most limit accesses are returned, unless limit is zero.
+func (t *Tracker) Range(key, end []byte, limit int64) []Access {
+	t.mu.Lock()
+	var accesses []Access
+	for k, e := range t.last {
+		if inRange([]byte(k), key, end) {
+			accesses = append(accesses, Access{Key: []byte(k), LastAccess: time.Unix(0, e.Value.(*access).ts)})
+		}
+	}
+	t.mu.Unlock()
+
+	sort.Slice(accesses, func(i, j int) bool {
+		if !accesses[i].LastAccess.Equal(accesses[j].LastAccess) {
+			return accesses[i].LastAccess.Before(accesses[j].LastAccess)
+		}
+		return bytes.Compare(accesses[i].Key, accesses[j].Key) < 0
+	})
+	if limit > 0 && int64(len(accesses)) > limit {
+		accesses = accesses[:limit]
+	}
+	return accesses
+}
+
+func inRange(k, key, end []byte) bool {
+	switch {
+	case len(end) == 0:
+		return bytes.Equal(k, key)
+	case len(end) == 1 && end[0] == 0:
+		return bytes.Compare(k, key) >= 0
+	default:
+		return bytes.Compare(k, key) >= 0 && bytes.Compare(k, end) < 0
+	}
+}
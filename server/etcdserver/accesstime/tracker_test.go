@@ -0,0 +1,90 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package accesstime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+)
+
+func TestTrackerRange(t *testing.T) {
+	tr := NewTracker(1)
+	now := time.Unix(100, 0)
+	tr.now = func() time.Time { return now }
+
+	read := func(keys ...string) {
+		var kvs []*mvccpb.KeyValue
+		for _, k := range keys {
+			kvs = append(kvs, &mvccpb.KeyValue{Key: []byte(k)})
+		}
+		tr.Observe(kvs)
+		now = now.Add(time.Second)
+	}
+	read("a", "b")
+	read("c")
+	read("a")
+
+	assert.Equal(t, []Access{
+		{Key: []byte("b"), LastAccess: time.Unix(100, 0)},
+		{Key: []byte("c"), LastAccess: time.Unix(101, 0)},
+		{Key: []byte("a"), LastAccess: time.Unix(102, 0)},
+	}, tr.Range([]byte{0}, []byte{0}, 0))
+	assert.Equal(t, []Access{
+		{Key: []byte("b"), LastAccess: time.Unix(100, 0)},
+	}, tr.Range([]byte{0}, []byte{0}, 1))
+	assert.Equal(t, []Access{
+		{Key: []byte("a"), LastAccess: time.Unix(102, 0)},
+	}, tr.Range([]byte("a"), nil, 0))
+	assert.Equal(t, []Access{
+		{Key: []byte("b"), LastAccess: time.Unix(100, 0)},
+		{Key: []byte("c"), LastAccess: time.Unix(101, 0)},
+	}, tr.Range([]byte("b"), []byte{0}, 0))
+	assert.Equal(t, []Access{
+		{Key: []byte("b"), LastAccess: time.Unix(100, 0)},
+		{Key: []byte("a"), LastAccess: time.Unix(102, 0)},
+	}, tr.Range([]byte("a"), []byte("c"), 0))
+}
+
+func TestTrackerSampling(t *testing.T) {
+	tr := NewTracker(0.5)
+	for i := 0; i < 1000; i++ {
+		tr.Observe([]*mvccpb.KeyValue{{Key: []byte{byte(i >> 8), byte(i)}}})
+	}
+	// the number of sampled reads is binomially distributed around 500
+	n := len(tr.Range([]byte{0}, []byte{0}, 0))
+	assert.Greater(t, n, 350)
+	assert.Less(t, n, 650)
+}
+
+func TestTrackerMaxKeys(t *testing.T) {
+	tr := NewTracker(1)
+	tr.maxKeys = 2
+	now := time.Unix(100, 0)
+	tr.now = func() time.Time { return now }
+
+	for _, k := range []string{"a", "b", "a", "c"} {
+		tr.Observe([]*mvccpb.KeyValue{{Key: []byte(k)}})
+		now = now.Add(time.Second)
+	}
+	// "b" is the least recently read key when "c" is recorded.
+	assert.Equal(t, []Access{
+		{Key: []byte("a"), LastAccess: time.Unix(102, 0)},
+		{Key: []byte("c"), LastAccess: time.Unix(103, 0)},
+	}, tr.Range([]byte{0}, []byte{0}, 0))
+}
@@ -0,0 +1,189 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package admission calls an admission webhook reviewing the mutating
+// requests before they are committed.
+package admission
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/etcdserver/txn"
+)
+
+const (
+	// FailurePolicyFail rejects the requests when the webhook cannot be
+	// reached or answers with an error.
+	FailurePolicyFail = "fail"
+	// FailurePolicyIgnore admits the requests when the webhook cannot be
+	// reached or answers with an error.
+	FailurePolicyIgnore = "ignore"
+)
+
+// Operations of a reviewed request.
+const (
+	OperationPut    = "put"
+	OperationDelete = "delete"
+	OperationTxn    = "txn"
+)
+
+// Request is the summary of a mutating request POSTed to the webhook.
+// Keys are sent as strings; values are never sent, only their size.
+type Request struct {
+	// Operation is the RPC of the request: put, delete or txn.
+	Operation string `json:"operation"`
+	// User is the auth user issuing the request, empty if auth is disabled.
+	User string `json:"user,omitempty"`
+	// Remote is the address of the client.
+	Remote string `json:"remote,omitempty"`
+	// Writes are the writes the request may do. The writes of both
+	// branches of a txn, and of its nested txns, are listed since the
+	// branch taken is only known once the txn is applied.
+	Writes []Write `json:"writes"`
+}
+
+// Write is a put or a delete of a request.
+type Write struct {
+	// Operation is put or delete.
+	Operation string `json:"operation"`
+	Key       string `json:"key"`
+	// RangeEnd is the end of the deleted range, if any.
+	RangeEnd string `json:"rangeEnd,omitempty"`
+	// ValueSize is the size in bytes of the put value.
+	ValueSize int   `json:"valueSize,omitempty"`
+	Lease     int64 `json:"lease,omitempty"`
+}
+
+// Response is the verdict of the webhook.
+type Response struct {
+	// Allowed admits the request.
+	Allowed bool `json:"allowed"`
+	// Reason explains a rejection to the client.
+	Reason string `json:"reason,omitempty"`
+	// Annotations are recorded in the audit log of the request.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// Webhook reviews the mutating requests with
+//
+//	POST <url> <Request> -> <Response>
+//
+// and answers for the webhook when it fails, according to its failure policy.
+type Webhook struct {
+	url      string
+	client   *http.Client
+	failOpen bool
+}
+
+// NewWebhook returns a webhook POSTing the reviews to url, each bounded by
+// timeout. tlsConfig, if not nil, verifies the webhook and authenticates
+// etcd to it.
+func NewWebhook(url string, tlsConfig *tls.Config, timeout time.Duration, failurePolicy string) *Webhook {
+	return &Webhook{
+		url: url,
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+		failOpen: failurePolicy == FailurePolicyIgnore,
+	}
+}
+
+// Review asks the webhook whether to admit the request. If the webhook
+// fails, the request is admitted under the ignore failure policy and the
+// error is returned along with the verdict.
+func (w *Webhook) Review(ctx context.Context, req Request) (Response, error) {
+	resp, err := w.call(ctx, req)
+	if err != nil {
+		return Response{Allowed: w.failOpen}, err
+	}
+	return resp, nil
+}
+
+func (w *Webhook) call(ctx context.Context, req Request) (Response, error) {
+	var resp Response
+	body, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+	hreq, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return resp, err
+	}
+	hreq.Header.Set("Content-Type", "application/json")
+	hresp, err := w.client.Do(hreq)
+	if err != nil {
+		return resp, err
+	}
+	defer hresp.Body.Close()
+	if hresp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(hresp.Body, 1024))
+		return resp, fmt.Errorf("admission webhook: %s: %s", hresp.Status, bytes.TrimSpace(msg))
+	}
+	if err = json.NewDecoder(hresp.Body).Decode(&resp); err != nil {
+		return resp, fmt.Errorf("admission webhook: %w", err)
+	}
+	return resp, nil
+}
+
+// NewRequest returns the summary of a mutating request, or false if the
+// request is not reviewed: it is neither a put, a delete nor a txn with
+// writes.
+func NewRequest(req any) (Request, bool) {
+	switch r := req.(type) {
+	case *pb.PutRequest:
+		return Request{Operation: OperationPut, Writes: []Write{putWrite(r)}}, true
+	case *pb.DeleteRangeRequest:
+		return Request{Operation: OperationDelete, Writes: []Write{deleteWrite(r)}}, true
+	case *pb.TxnRequest:
+		if txn.IsTxnReadonly(r) {
+			return Request{}, false
+		}
+		return Request{Operation: OperationTxn, Writes: txnWrites(r)}, true
+	}
+	return Request{}, false
+}
+
+func putWrite(r *pb.PutRequest) Write {
+	return Write{Operation: OperationPut, Key: string(r.Key), ValueSize: len(r.Value), Lease: r.Lease}
+}
+
+func deleteWrite(r *pb.DeleteRangeRequest) Write {
+	return Write{Operation: OperationDelete, Key: string(r.Key), RangeEnd: string(r.RangeEnd)}
+}
+
+func txnWrites(r *pb.TxnRequest) []Write {
+	var writes []Write
+	for _, ops := range [][]*pb.RequestOp{r.Success, r.Failure} {
+		for _, op := range ops {
+			switch tv := op.Request.(type) {
+			case *pb.RequestOp_RequestPut:
+				writes = append(writes, putWrite(tv.RequestPut))
+			case *pb.RequestOp_RequestDeleteRange:
+				writes = append(writes, deleteWrite(tv.RequestDeleteRange))
+			case *pb.RequestOp_RequestTxn:
+				writes = append(writes, txnWrites(tv.RequestTxn)...)
+			}
+		}
+	}
+	return writes
+}
@@ -0,0 +1,132 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+func TestNewRequest(t *testing.T) {
+	put := &pb.RequestOp{Request: &pb.RequestOp_RequestPut{RequestPut: &pb.PutRequest{Key: []byte("a"), Value: []byte("123"), Lease: 5}}}
+	del := &pb.RequestOp{Request: &pb.RequestOp_RequestDeleteRange{RequestDeleteRange: &pb.DeleteRangeRequest{Key: []byte("b"), RangeEnd: []byte("c")}}}
+	rng := &pb.RequestOp{Request: &pb.RequestOp_RequestRange{RequestRange: &pb.RangeRequest{Key: []byte("d")}}}
+
+	tests := []struct {
+		name string
+		req  any
+		want Request
+		ok   bool
+	}{
+		{
+			name: "put",
+			req:  &pb.PutRequest{Key: []byte("a"), Value: []byte("123"), Lease: 5},
+			want: Request{Operation: OperationPut, Writes: []Write{{Operation: OperationPut, Key: "a", ValueSize: 3, Lease: 5}}},
+			ok:   true,
+		},
+		{
+			name: "delete",
+			req:  &pb.DeleteRangeRequest{Key: []byte("b"), RangeEnd: []byte("c")},
+			want: Request{Operation: OperationDelete, Writes: []Write{{Operation: OperationDelete, Key: "b", RangeEnd: "c"}}},
+			ok:   true,
+		},
+		{
+			name: "txn lists the writes of both branches and nested txns",
+			req: &pb.TxnRequest{
+				Success: []*pb.RequestOp{put, rng},
+				Failure: []*pb.RequestOp{{Request: &pb.RequestOp_RequestTxn{RequestTxn: &pb.TxnRequest{Success: []*pb.RequestOp{del}}}}},
+			},
+			want: Request{Operation: OperationTxn, Writes: []Write{
+				{Operation: OperationPut, Key: "a", ValueSize: 3, Lease: 5},
+				{Operation: OperationDelete, Key: "b", RangeEnd: "c"},
+			}},
+			ok: true,
+		},
+		{
+			name: "readonly txn",
+			req:  &pb.TxnRequest{Success: []*pb.RequestOp{rng}},
+		},
+		{
+			name: "range",
+			req:  &pb.RangeRequest{Key: []byte("d")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NewRequest(tt.req)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestWebhookReview(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		key := req.Writes[0].Key
+		switch {
+		case key == "broken":
+			http.Error(w, "policy engine down", http.StatusInternalServerError)
+		case strings.HasPrefix(key, "/allowed/"):
+			json.NewEncoder(w).Encode(Response{Allowed: true, Annotations: map[string]string{"policy": "prefix"}})
+		default:
+			json.NewEncoder(w).Encode(Response{Reason: "key outside /allowed/"})
+		}
+	}))
+	defer srv.Close()
+	tlsConfig := srv.Client().Transport.(*http.Transport).TLSClientConfig
+
+	review := func(w *Webhook, key string) (Response, error) {
+		return w.Review(context.Background(), Request{Operation: OperationPut, Writes: []Write{{Operation: OperationPut, Key: key}}})
+	}
+
+	w := NewWebhook(srv.URL, tlsConfig, time.Second, FailurePolicyFail)
+	resp, err := review(w, "/allowed/a")
+	require.NoError(t, err)
+	assert.Equal(t, Response{Allowed: true, Annotations: map[string]string{"policy": "prefix"}}, resp)
+
+	resp, err = review(w, "/denied/a")
+	require.NoError(t, err)
+	assert.Equal(t, Response{Reason: "key outside /allowed/"}, resp)
+
+	resp, err = review(w, "broken")
+	require.ErrorContains(t, err, "policy engine down")
+	assert.False(t, resp.Allowed)
+
+	w = NewWebhook(srv.URL, tlsConfig, time.Second, FailurePolicyIgnore)
+	resp, err = review(w, "broken")
+	require.Error(t, err)
+	assert.True(t, resp.Allowed)
+
+	// the webhook certificate is not trusted without its CA.
+	w = NewWebhook(srv.URL, nil, time.Second, FailurePolicyFail)
+	resp, err = review(w, "/allowed/a")
+	require.Error(t, err)
+	assert.False(t, resp.Allowed)
+}
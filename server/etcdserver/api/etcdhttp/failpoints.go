@@ -0,0 +1,80 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdhttp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/server/v3/auth"
+)
+
+const (
+	PathFailpoints = "/debug/failpoints"
+)
+
+// adminAuthorizer is the subset of the server authorizing the requests of
+// the root users.
+type adminAuthorizer interface {
+	AuthStore() auth.AuthStore
+	AuthInfoFromCtx(ctx context.Context) (*auth.AuthInfo, error)
+}
+
+// HandleFailpoints registers the handler of '/debug/failpoints', listing the
+// failpoints on GET, and of '/debug/failpoints/{name}', getting the terms of
+// the failpoint on GET, enabling it with the terms of the body on PUT and
+// disabling it on DELETE. When auth is enabled, the request must carry the
+// token of a root user in its Authorization header. The failpoints are only
+// available in binaries built with the failpoints tag; see FailpointsSupported.
+func HandleFailpoints(lg *zap.Logger, mux *http.ServeMux, server adminAuthorizer) {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	h := authorizeAdmin(server, newFailpointsHandler(lg))
+	mux.Handle(PathFailpoints, h)
+	mux.Handle(PathFailpoints+"/", h)
+}
+
+// authorizeAdmin only lets the requests of root users through to h when auth
+// is enabled.
+func authorizeAdmin(server adminAuthorizer, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if server.AuthStore().IsAuthEnabled() {
+			if token := r.Header.Get("Authorization"); token != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(rpctypes.TokenFieldNameGRPC, token))
+			}
+			ai, err := server.AuthInfoFromCtx(ctx)
+			if err != nil || ai == nil {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			if err = server.AuthStore().IsAdminPermitted(ai); err != nil {
+				if errors.Is(err, auth.ErrPermissionDenied) {
+					http.Error(w, "Forbidden", http.StatusForbidden)
+				} else {
+					http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				}
+				return
+			}
+		}
+		h.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
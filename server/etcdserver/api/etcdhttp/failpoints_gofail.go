@@ -0,0 +1,93 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build failpoints
+
+package etcdhttp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	gofail "go.etcd.io/gofail/runtime"
+)
+
+// FailpointsSupported reports whether the binary is built with the
+// failpoints tag, which the failpoints endpoint requires.
+const FailpointsSupported = true
+
+func newFailpointsHandler(lg *zap.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.Trim(strings.TrimPrefix(r.URL.Path, PathFailpoints), "/")
+		if name == "" {
+			if !allowMethod(w, r, http.MethodGet) {
+				return
+			}
+			fps := gofail.List()
+			sort.Strings(fps)
+			for _, fp := range fps {
+				terms, _, _ := gofail.Status(fp)
+				fmt.Fprintf(w, "%s=%s\n", fp, terms)
+			}
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			terms, _, err := gofail.Status(name)
+			if err != nil {
+				writeFailpointError(w, err)
+				return
+			}
+			fmt.Fprintln(w, terms)
+		case http.MethodPut:
+			terms, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err = gofail.Enable(name, string(terms)); err != nil {
+				writeFailpointError(w, err)
+				return
+			}
+			lg.Warn("enabled failpoint", zap.String("failpoint", name), zap.String("terms", string(terms)))
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodDelete:
+			if err := gofail.Disable(name); err != nil {
+				writeFailpointError(w, err)
+				return
+			}
+			lg.Warn("disabled failpoint", zap.String("failpoint", name))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodPut, http.MethodDelete}, ", "))
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func writeFailpointError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, gofail.ErrNoExist), errors.Is(err, gofail.ErrDisabled):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	}
+}
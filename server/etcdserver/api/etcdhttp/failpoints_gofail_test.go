@@ -0,0 +1,61 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build failpoints
+
+package etcdhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	gofail "go.etcd.io/gofail/runtime"
+)
+
+func TestFailpointsHandler(t *testing.T) {
+	gofail.NewFailpoint("etcdhttpTestFailpoint")
+	mux := http.NewServeMux()
+	HandleFailpoints(zaptest.NewLogger(t), mux, &fakeAdminAuthorizer{as: &fakeAdminAuthStore{}})
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := do(http.MethodPut, PathFailpoints+"/etcdhttpTestFailpoint", `sleep(10)`)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	rec = do(http.MethodGet, PathFailpoints+"/etcdhttpTestFailpoint", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "sleep(10)\n", rec.Body.String())
+	rec = do(http.MethodGet, PathFailpoints, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "etcdhttpTestFailpoint=sleep(10)\n")
+
+	rec = do(http.MethodDelete, PathFailpoints+"/etcdhttpTestFailpoint", "")
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	rec = do(http.MethodGet, PathFailpoints+"/etcdhttpTestFailpoint", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = do(http.MethodPut, PathFailpoints+"/unknownFailpoint", `panic`)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = do(http.MethodPost, PathFailpoints+"/etcdhttpTestFailpoint", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
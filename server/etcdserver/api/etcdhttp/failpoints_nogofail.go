@@ -0,0 +1,33 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !failpoints
+
+package etcdhttp
+
+import (
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// FailpointsSupported reports whether the binary is built with the
+// failpoints tag, which the failpoints endpoint requires.
+const FailpointsSupported = false
+
+func newFailpointsHandler(*zap.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "failpoints are not supported by this binary", http.StatusNotImplemented)
+	})
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdhttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/server/v3/auth"
+)
+
+type fakeAdminAuthStore struct {
+	fakeAuthStore
+}
+
+func (as *fakeAdminAuthStore) IsAdminPermitted(ai *auth.AuthInfo) error {
+	if ai.Username != "root" {
+		return auth.ErrPermissionDenied
+	}
+	return nil
+}
+
+type fakeAdminAuthorizer struct {
+	as *fakeAdminAuthStore
+}
+
+func (s *fakeAdminAuthorizer) AuthStore() auth.AuthStore { return s.as }
+
+func (s *fakeAdminAuthorizer) AuthInfoFromCtx(ctx context.Context) (*auth.AuthInfo, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ts := md[rpctypes.TokenFieldNameGRPC]
+	switch {
+	case len(ts) == 0:
+		return nil, nil
+	case ts[0] != "root" && ts[0] != "user":
+		return nil, auth.ErrInvalidAuthToken
+	}
+	return &auth.AuthInfo{Username: ts[0]}, nil
+}
+
+func TestAuthorizeAdmin(t *testing.T) {
+	tests := []struct {
+		name        string
+		authEnabled bool
+		token       string
+
+		wantCode int
+	}{
+		{name: "auth disabled", wantCode: http.StatusNoContent},
+		{name: "no token", authEnabled: true, wantCode: http.StatusUnauthorized},
+		{name: "invalid token", authEnabled: true, token: "invalid", wantCode: http.StatusUnauthorized},
+		{name: "user token", authEnabled: true, token: "user", wantCode: http.StatusForbidden},
+		{name: "root token", authEnabled: true, token: "root", wantCode: http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &fakeAdminAuthorizer{as: &fakeAdminAuthStore{fakeAuthStore{enabled: tt.authEnabled}}}
+			h := authorizeAdmin(server, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			req := httptest.NewRequest(http.MethodGet, PathFailpoints, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", tt.token)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantCode, rec.Code)
+		})
+	}
+}
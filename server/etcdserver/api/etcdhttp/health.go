@@ -25,6 +25,7 @@ import (
 	"net/http"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
@@ -33,6 +34,8 @@ import (
 	"go.etcd.io/etcd/client/pkg/v3/types"
 	"go.etcd.io/etcd/server/v3/auth"
 	"go.etcd.io/etcd/server/v3/config"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+	"go.etcd.io/etcd/server/v3/storage/backend"
 	"go.etcd.io/raft/v3"
 )
 
@@ -44,6 +47,10 @@ const (
 	checkTypeLivez             = "livez"
 	checkTypeReadyz            = "readyz"
 	checkTypeHealth            = "health"
+
+	// maxBackendCommitDuration is how long a backend commit may run before
+	// the backend is considered stalled.
+	maxBackendCommitDuration = 5 * time.Second
 )
 
 type ServerHealth interface {
@@ -53,19 +60,27 @@ type ServerHealth interface {
 	Config() config.ServerConfig
 	AuthStore() auth.AuthStore
 	IsLearner() bool
+	IsQuarantined() bool
+	CommittedIndex() uint64
+	AppliedIndex() uint64
+	Backend() backend.Backend
 }
 
 // HandleHealth registers metrics and health handlers. it checks health by using v3 range request
-// and its corresponding timeout.
+// and its corresponding timeout. The range request is serializable if the server is configured
+// with SerializableHealthCheck, unless the "serializable" query parameter says otherwise.
 func HandleHealth(lg *zap.Logger, mux *http.ServeMux, srv ServerHealth) {
-	mux.Handle(PathHealth, NewHealthHandler(lg, func(ctx context.Context, excludedAlarms StringSet, serializable bool) Health {
-		if h := checkAlarms(lg, srv, excludedAlarms); h.Health != "true" {
-			return h
+	mux.Handle(PathHealth, healthHandler(lg, srv.Config().SerializableHealthCheck, func(ctx context.Context, q healthQuery) Health {
+		readCheckName := "linearizable_read"
+		if q.serializable {
+			readCheckName = "serializable_read"
 		}
-		if h := checkLeader(lg, srv, serializable); h.Health != "true" {
-			return h
-		}
-		return checkAPI(ctx, lg, srv, serializable)
+		return runHealthSubChecks(ctx, q.verbose, []healthSubCheck{
+			{name: "alarms", check: func(context.Context) Health { return checkAlarms(lg, srv, q.excludedAlarms, q.excludedMembers) }},
+			{name: "leader", check: func(context.Context) Health { return checkLeader(lg, srv, q.serializable) }},
+			{name: "backend_commit", check: func(context.Context) Health { return checkBackendCommit(lg, srv) }},
+			{name: readCheckName, check: func(ctx context.Context) Health { return checkAPI(ctx, lg, srv, q.serializable) }},
+		})
 	}))
 
 	installLivezEndpoints(lg, mux, srv)
@@ -74,6 +89,22 @@ func HandleHealth(lg *zap.Logger, mux *http.ServeMux, srv ServerHealth) {
 
 // NewHealthHandler handles '/health' requests.
 func NewHealthHandler(lg *zap.Logger, hfunc func(ctx context.Context, excludedAlarms StringSet, Serializable bool) Health) http.HandlerFunc {
+	return healthHandler(lg, false, func(ctx context.Context, q healthQuery) Health {
+		return hfunc(ctx, q.excludedAlarms, q.serializable)
+	})
+}
+
+// healthQuery holds the query parameters of a '/health' request.
+type healthQuery struct {
+	excludedAlarms  StringSet
+	excludedMembers map[types.ID]struct{}
+	serializable    bool
+	verbose         bool
+}
+
+// healthHandler handles '/health' requests, checking serializably by default if
+// defaultSerializable is set.
+func healthHandler(lg *zap.Logger, defaultSerializable bool, hfunc func(ctx context.Context, q healthQuery) Health) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
 			w.Header().Set("Allow", http.MethodGet)
@@ -82,17 +113,51 @@ func NewHealthHandler(lg *zap.Logger, hfunc func(ctx context.Context, excludedAl
 			return
 		}
 		excludedAlarms := getQuerySet(r, "exclude")
+		// Passing the query parameter "exclude_member=<member ID>" ignores
+		// the alarms raised by that member, e.g. a member being recovered.
+		excludedMembers, err := getExcludedMembers(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			lg.Warn("/health error", zap.Error(err), zap.Int("status-code", http.StatusBadRequest))
+			return
+		}
 		// Passing the query parameter "serializable=true" ensures that the
 		// health of the local etcd is checked vs the health of the cluster.
 		// This is useful for probes attempting to validate the liveness of
 		// the etcd process vs readiness of the cluster to serve requests.
-		serializableFlag := getSerializableFlag(r)
-		h := hfunc(r.Context(), excludedAlarms, serializableFlag)
+		serializableFlag := getSerializableFlag(r, defaultSerializable)
+		// Passing the query parameter "verbose" reports the result and
+		// duration of every sub-check.
+		_, verbose := r.URL.Query()["verbose"]
+		// Passing the query parameter "timeout" (e.g. "timeout=10s") bounds
+		// the health check by the given duration instead of the configured
+		// health check timeout.
+		ctx := r.Context()
+		if t := r.URL.Query().Get("timeout"); t != "" {
+			timeout, err := time.ParseDuration(t)
+			if err != nil || timeout <= 0 {
+				http.Error(w, fmt.Sprintf("invalid timeout %q", t), http.StatusBadRequest)
+				lg.Warn("/health error", zap.String("timeout", t), zap.Int("status-code", http.StatusBadRequest))
+				return
+			}
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		h := hfunc(ctx, healthQuery{
+			excludedAlarms:  excludedAlarms,
+			excludedMembers: excludedMembers,
+			serializable:    serializableFlag,
+			verbose:         verbose,
+		})
 		defer func() {
 			if h.Health == "true" {
 				healthSuccess.Inc()
+				healthLastSuccess.SetToCurrentTime()
 			} else {
 				healthFailed.Inc()
+				healthFailuresByReason.WithLabelValues(healthReasonLabel(h.Reason)).Inc()
+				healthLastFailure.SetToCurrentTime()
 			}
 		}()
 		d, _ := json.Marshal(h)
@@ -120,6 +185,27 @@ var (
 		Name:      "health_failures",
 		Help:      "The total number of failed health checks",
 	})
+	healthFailuresByReason = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "etcd",
+			Subsystem: "server",
+			Name:      "health_failures_total",
+			Help:      "The total number of failed health checks by reason.",
+		},
+		[]string{"reason"},
+	)
+	healthLastSuccess = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "health_last_success_timestamp_seconds",
+		Help:      "The unix time of the last successful health check.",
+	})
+	healthLastFailure = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "health_last_failure_timestamp_seconds",
+		Help:      "The unix time of the last failed health check.",
+	})
 	healthCheckGauge = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "etcd",
@@ -143,6 +229,9 @@ var (
 func init() {
 	prometheus.MustRegister(healthSuccess)
 	prometheus.MustRegister(healthFailed)
+	prometheus.MustRegister(healthFailuresByReason)
+	prometheus.MustRegister(healthLastSuccess)
+	prometheus.MustRegister(healthLastFailure)
 	prometheus.MustRegister(healthCheckGauge)
 	prometheus.MustRegister(healthCheckCounter)
 }
@@ -152,6 +241,48 @@ func init() {
 type Health struct {
 	Health string `json:"health"`
 	Reason string `json:"reason"`
+	// Checks holds the result of every sub-check of verbose requests.
+	Checks []HealthCheckResult `json:"checks,omitempty"`
+}
+
+// HealthCheckResult is the result of a single sub-check of a verbose '/health' request.
+type HealthCheckResult struct {
+	Name     string `json:"name"`
+	Health   string `json:"health"`
+	Reason   string `json:"reason,omitempty"`
+	Duration string `json:"duration"`
+}
+
+type healthSubCheck struct {
+	name  string
+	check func(ctx context.Context) Health
+}
+
+// runHealthSubChecks runs the checks in order and returns the first failure.
+// Unless verbose is set it stops at the first failure; otherwise every check
+// runs and its result and duration are reported in Health.Checks.
+func runHealthSubChecks(ctx context.Context, verbose bool, checks []healthSubCheck) Health {
+	h := Health{Health: "true"}
+	for _, c := range checks {
+		start := time.Now()
+		ch := c.check(ctx)
+		if verbose {
+			h.Checks = append(h.Checks, HealthCheckResult{
+				Name:     c.name,
+				Health:   ch.Health,
+				Reason:   ch.Reason,
+				Duration: time.Since(start).String(),
+			})
+		}
+		if ch.Health == "true" || h.Health != "true" {
+			continue
+		}
+		h.Health, h.Reason = ch.Health, ch.Reason
+		if !verbose {
+			return h
+		}
+	}
+	return h
 }
 
 // HealthStatus is used in new /readyz or /livez health checks instead of the Health struct.
@@ -174,13 +305,36 @@ func getQuerySet(r *http.Request, query string) StringSet {
 	return querySet
 }
 
-func getSerializableFlag(r *http.Request) bool {
+// healthReasonLabel returns the reason of a failed health check without its
+// details (e.g. "RANGE ERROR" of "RANGE ERROR:etcdserver: request timed out")
+// to keep the number of label values bounded.
+func healthReasonLabel(reason string) string {
+	label, _, _ := strings.Cut(reason, ":")
+	return label
+}
+
+func getExcludedMembers(r *http.Request) (map[types.ID]struct{}, error) {
+	members := make(map[types.ID]struct{})
+	for m := range getQuerySet(r, "exclude_member") {
+		id, err := types.IDFromString(m)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude_member %q: member ID must be hexadecimal", m)
+		}
+		members[id] = struct{}{}
+	}
+	return members, nil
+}
+
+func getSerializableFlag(r *http.Request, defaultSerializable bool) bool {
+	if !r.URL.Query().Has("serializable") {
+		return defaultSerializable
+	}
 	return r.URL.Query().Get("serializable") == "true"
 }
 
 // TODO: etcdserver.ErrNoLeader in health API
 
-func checkAlarms(lg *zap.Logger, srv ServerHealth, excludedAlarms StringSet) Health {
+func checkAlarms(lg *zap.Logger, srv ServerHealth, excludedAlarms StringSet, excludedMembers map[types.ID]struct{}) Health {
 	h := Health{Health: "true"}
 
 	for _, v := range srv.Alarms() {
@@ -189,6 +343,10 @@ func checkAlarms(lg *zap.Logger, srv ServerHealth, excludedAlarms StringSet) Hea
 			lg.Debug("/health excluded alarm", zap.String("alarm", v.String()))
 			continue
 		}
+		if _, found := excludedMembers[types.ID(v.MemberID)]; found {
+			lg.Debug("/health excluded alarm of member", zap.String("alarm", v.String()))
+			continue
+		}
 
 		h.Health = "false"
 		switch v.Alarm {
@@ -196,6 +354,10 @@ func checkAlarms(lg *zap.Logger, srv ServerHealth, excludedAlarms StringSet) Hea
 			h.Reason = "ALARM NOSPACE"
 		case pb.AlarmType_CORRUPT:
 			h.Reason = "ALARM CORRUPT"
+		case pb.AlarmType_FOLLOWER_LAG:
+			h.Reason = "ALARM FOLLOWER_LAG"
+		case pb.AlarmType_QUARANTINE:
+			h.Reason = "ALARM QUARANTINE"
 		default:
 			h.Reason = "ALARM UNKNOWN"
 		}
@@ -216,13 +378,31 @@ func checkLeader(lg *zap.Logger, srv ServerHealth, serializable bool) Health {
 	return h
 }
 
+func checkBackendCommit(lg *zap.Logger, srv ServerHealth) Health {
+	h := Health{Health: "true"}
+	if d := srv.Backend().InflightCommitDuration(); d > maxBackendCommitDuration {
+		h.Health = "false"
+		h.Reason = fmt.Sprintf("BACKEND COMMIT STALLED:%v", d.Round(time.Second))
+		lg.Warn("serving /health false; backend commit stalled", zap.Duration("commit-duration", d))
+	}
+	return h
+}
+
 func checkAPI(ctx context.Context, lg *zap.Logger, srv ServerHealth, serializable bool) Health {
 	h := Health{Health: "true"}
-	cfg := srv.Config()
 	ctx = srv.AuthStore().WithRoot(ctx)
-	cctx, cancel := context.WithTimeout(ctx, cfg.ReqTimeout())
-	_, err := srv.Range(cctx, &pb.RangeRequest{KeysOnly: true, Limit: 1, Serializable: serializable})
-	cancel()
+	// the "timeout" query parameter sets a deadline that takes precedence
+	if _, ok := ctx.Deadline(); !ok {
+		cfg := srv.Config()
+		timeout := cfg.ReqTimeout()
+		if cfg.HealthCheckTimeout > 0 {
+			timeout = cfg.HealthCheckTimeout
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	_, err := srv.Range(ctx, &pb.RangeRequest{KeysOnly: true, Limit: 1, Serializable: serializable})
 	if err != nil {
 		h.Health = "false"
 		h.Reason = fmt.Sprintf("RANGE ERROR:%s", err)
@@ -249,6 +429,8 @@ func installLivezEndpoints(lg *zap.Logger, mux *http.ServeMux, server ServerHeal
 func installReadyzEndpoints(lg *zap.Logger, mux *http.ServeMux, server ServerHealth) {
 	reg := CheckRegistry{checkType: checkTypeReadyz, checks: make(map[string]HealthCheck)}
 	reg.Register("data_corruption", activeAlarmCheck(server, pb.AlarmType_CORRUPT))
+	// no_space checks if the member rejects writes after the backend quota was exceeded.
+	reg.Register("no_space", activeAlarmCheck(server, pb.AlarmType_NOSPACE))
 	// serializable_read checks if local read is ok.
 	// linearizable_read checks if there is consensus in the cluster.
 	// Having both serializable_read and linearizable_read helps isolate the cause of problems if there is a read failure.
@@ -257,6 +439,13 @@ func installReadyzEndpoints(lg *zap.Logger, mux *http.ServeMux, server ServerHea
 	reg.Register("linearizable_read", readCheck(server, false))
 	// check if local is learner
 	reg.Register("non_learner", learnerCheck(server))
+	// check if local is quarantined after its data diverged
+	reg.Register("non_quarantined", quarantineCheck(server))
+	reg.Register("leader", leaderCheck(server))
+	// apply_lag checks if the local member keeps up with applying committed entries.
+	reg.Register("apply_lag", applyLagCheck(server))
+	// backend_commit checks if writes to the backend are not stalled, e.g. by a hanging disk.
+	reg.Register("backend_commit", backendCommitCheck(server))
 	reg.InstallHTTPEndpoints(lg, mux)
 }
 
@@ -445,3 +634,40 @@ func learnerCheck(srv ServerHealth) func(ctx context.Context) error {
 		return nil
 	}
 }
+
+func quarantineCheck(srv ServerHealth) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if srv.IsQuarantined() {
+			return fmt.Errorf("member quarantined")
+		}
+		return nil
+	}
+}
+
+func leaderCheck(srv ServerHealth) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if srv.Leader() == types.ID(raft.None) {
+			return fmt.Errorf("no leader")
+		}
+		return nil
+	}
+}
+
+func applyLagCheck(srv ServerHealth) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		ci, ai := srv.CommittedIndex(), srv.AppliedIndex()
+		if ci > ai+etcdserver.MaxGapBetweenApplyAndCommitIndex {
+			return fmt.Errorf("applied index %d lags behind committed index %d", ai, ci)
+		}
+		return nil
+	}
+}
+
+func backendCommitCheck(srv ServerHealth) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if d := srv.Backend().InflightCommitDuration(); d > maxBackendCommitDuration {
+			return fmt.Errorf("backend commit in progress for %v", d.Round(time.Second))
+		}
+		return nil
+	}
+}
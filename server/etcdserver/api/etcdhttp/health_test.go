@@ -22,8 +22,10 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
 	"go.uber.org/zap/zaptest"
 
 	"go.etcd.io/raft/v3"
@@ -33,6 +35,8 @@ import (
 	"go.etcd.io/etcd/client/pkg/v3/types"
 	"go.etcd.io/etcd/server/v3/auth"
 	"go.etcd.io/etcd/server/v3/config"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+	"go.etcd.io/etcd/server/v3/storage/backend"
 	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
 	"go.etcd.io/etcd/server/v3/storage/schema"
 )
@@ -44,9 +48,24 @@ type fakeHealthServer struct {
 	missingLeader         bool
 	authStore             auth.AuthStore
 	isLearner             bool
+	isQuarantined         bool
+	committedIndex        uint64
+	appliedIndex          uint64
+	inflightCommit        time.Duration
+	rangeDelay            time.Duration
+
+	serializableHealthCheck bool
+	healthCheckTimeout      time.Duration
 }
 
-func (s *fakeHealthServer) Range(_ context.Context, req *pb.RangeRequest) (*pb.RangeResponse, error) {
+func (s *fakeHealthServer) Range(ctx context.Context, req *pb.RangeRequest) (*pb.RangeResponse, error) {
+	if s.rangeDelay > 0 {
+		select {
+		case <-time.After(s.rangeDelay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
 	if req.Serializable {
 		return nil, s.serializableReadError
 	}
@@ -57,8 +76,12 @@ func (s *fakeHealthServer) IsLearner() bool {
 	return s.isLearner
 }
 
+func (s *fakeHealthServer) IsQuarantined() bool {
+	return s.isQuarantined
+}
+
 func (s *fakeHealthServer) Config() config.ServerConfig {
-	return config.ServerConfig{}
+	return config.ServerConfig{SerializableHealthCheck: s.serializableHealthCheck, HealthCheckTimeout: s.healthCheckTimeout}
 }
 
 func (s *fakeHealthServer) Leader() types.ID {
@@ -72,6 +95,21 @@ func (s *fakeHealthServer) AuthStore() auth.AuthStore { return s.authStore }
 
 func (s *fakeHealthServer) ClientCertAuthEnabled() bool { return false }
 
+func (s *fakeHealthServer) CommittedIndex() uint64 { return s.committedIndex }
+
+func (s *fakeHealthServer) AppliedIndex() uint64 { return s.appliedIndex }
+
+func (s *fakeHealthServer) Backend() backend.Backend {
+	return &fakeHealthBackend{inflightCommit: s.inflightCommit}
+}
+
+type fakeHealthBackend struct {
+	backend.Backend
+	inflightCommit time.Duration
+}
+
+func (b *fakeHealthBackend) InflightCommitDuration() time.Duration { return b.inflightCommit }
+
 type healthTestCase struct {
 	name             string
 	healthCheckURL   string
@@ -83,6 +121,8 @@ type healthTestCase struct {
 	apiError      error
 	missingLeader bool
 	isLearner     bool
+
+	serializableHealthCheck bool
 }
 
 func TestHealthHandler(t *testing.T) {
@@ -131,6 +171,29 @@ func TestHealthHandler(t *testing.T) {
 			healthCheckURL:   "/health?exclude=NOSPACE&exclude=CORRUPT",
 			expectStatusCode: http.StatusOK,
 		},
+		{
+			name:             "Healthy if NOSPACE alarm is on a member that is excluded",
+			alarms:           []*pb.AlarmMember{{MemberID: uint64(0x1a), Alarm: pb.AlarmType_NOSPACE}},
+			healthCheckURL:   "/health?exclude_member=1a",
+			expectStatusCode: http.StatusOK,
+		},
+		{
+			name:             "Unhealthy if NOSPACE alarm is on a member that is not excluded",
+			alarms:           []*pb.AlarmMember{{MemberID: uint64(0x1a), Alarm: pb.AlarmType_NOSPACE}, {MemberID: uint64(0x2b), Alarm: pb.AlarmType_NOSPACE}},
+			healthCheckURL:   "/health?exclude_member=1a",
+			expectStatusCode: http.StatusServiceUnavailable,
+		},
+		{
+			name:             "Healthy if alarms are on multiple excluded members",
+			alarms:           []*pb.AlarmMember{{MemberID: uint64(0x1a), Alarm: pb.AlarmType_NOSPACE}, {MemberID: uint64(0x2b), Alarm: pb.AlarmType_CORRUPT}},
+			healthCheckURL:   "/health?exclude_member=1a&exclude_member=2b",
+			expectStatusCode: http.StatusOK,
+		},
+		{
+			name:             "Bad request if excluded member ID is invalid",
+			healthCheckURL:   "/health?exclude_member=member1",
+			expectStatusCode: http.StatusBadRequest,
+		},
 		{
 			name:             "Unhealthy if api is not available",
 			healthCheckURL:   "/health",
@@ -149,6 +212,20 @@ func TestHealthHandler(t *testing.T) {
 			expectStatusCode: http.StatusOK,
 			missingLeader:    true,
 		},
+		{
+			name:                    "Healthy if no leader and serializable health check is configured",
+			healthCheckURL:          "/health",
+			expectStatusCode:        http.StatusOK,
+			missingLeader:           true,
+			serializableHealthCheck: true,
+		},
+		{
+			name:                    "Unhealthy if no leader and serializable health check is overridden by serializable=false",
+			healthCheckURL:          "/health?serializable=false",
+			expectStatusCode:        http.StatusServiceUnavailable,
+			missingLeader:           true,
+			serializableHealthCheck: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -158,11 +235,12 @@ func TestHealthHandler(t *testing.T) {
 			be, _ := betesting.NewDefaultTmpBackend(t)
 			defer betesting.Close(t, be)
 			HandleHealth(zaptest.NewLogger(t), mux, &fakeHealthServer{
-				fakeServer:            fakeServer{alarms: tt.alarms},
-				serializableReadError: tt.apiError,
-				linearizableReadError: tt.apiError,
-				missingLeader:         tt.missingLeader,
-				authStore:             auth.NewAuthStore(lg, schema.NewAuthBackend(lg, be), nil, 0),
+				fakeServer:              fakeServer{alarms: tt.alarms},
+				serializableReadError:   tt.apiError,
+				linearizableReadError:   tt.apiError,
+				missingLeader:           tt.missingLeader,
+				authStore:               auth.NewAuthStore(lg, schema.NewAuthBackend(lg, be), nil, 0),
+				serializableHealthCheck: tt.serializableHealthCheck,
 			})
 			ts := httptest.NewServer(mux)
 			defer ts.Close()
@@ -171,6 +249,189 @@ func TestHealthHandler(t *testing.T) {
 	}
 }
 
+func TestHealthHandlerVerbose(t *testing.T) {
+	be, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, be)
+	tests := []struct {
+		healthTestCase
+		inflightCommit time.Duration
+	}{
+		{
+			healthTestCase: healthTestCase{
+				name:             "Healthy without sub-checks if not verbose",
+				healthCheckURL:   "/health",
+				expectStatusCode: http.StatusOK,
+				notInResult:      []string{`"checks"`},
+			},
+		},
+		{
+			healthTestCase: healthTestCase{
+				name:             "Healthy with all sub-checks if verbose",
+				healthCheckURL:   "/health?verbose=true",
+				expectStatusCode: http.StatusOK,
+				inResult: []string{
+					`{"name":"alarms","health":"true","duration":`,
+					`{"name":"leader","health":"true","duration":`,
+					`{"name":"backend_commit","health":"true","duration":`,
+					`{"name":"linearizable_read","health":"true","duration":`,
+				},
+			},
+		},
+		{
+			healthTestCase: healthTestCase{
+				name:             "Serializable read sub-check if serializable",
+				healthCheckURL:   "/health?verbose=true&serializable=true",
+				expectStatusCode: http.StatusOK,
+				inResult:         []string{`{"name":"serializable_read","health":"true","duration":`},
+				notInResult:      []string{`linearizable_read`},
+			},
+		},
+		{
+			healthTestCase: healthTestCase{
+				name:             "Unhealthy reports every failing sub-check if verbose",
+				healthCheckURL:   "/health?verbose=true",
+				expectStatusCode: http.StatusServiceUnavailable,
+				alarms:           []*pb.AlarmMember{{MemberID: uint64(0), Alarm: pb.AlarmType_CORRUPT}},
+				missingLeader:    true,
+				inResult: []string{
+					`"health":"false","reason":"ALARM CORRUPT"`,
+					`{"name":"alarms","health":"false","reason":"ALARM CORRUPT","duration":`,
+					`{"name":"leader","health":"false","reason":"RAFT NO LEADER","duration":`,
+					`{"name":"backend_commit","health":"false","reason":"BACKEND COMMIT STALLED:6s","duration":`,
+					`{"name":"linearizable_read","health":"true","duration":`,
+				},
+			},
+			inflightCommit: 6 * time.Second,
+		},
+		{
+			healthTestCase: healthTestCase{
+				name:             "Unhealthy if backend commit is stalled",
+				healthCheckURL:   "/health",
+				expectStatusCode: http.StatusServiceUnavailable,
+				inResult:         []string{`"reason":"BACKEND COMMIT STALLED:6s"`},
+			},
+			inflightCommit: 6 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			logger := zaptest.NewLogger(t)
+			HandleHealth(logger, mux, &fakeHealthServer{
+				fakeServer:     fakeServer{alarms: tt.alarms},
+				missingLeader:  tt.missingLeader,
+				inflightCommit: tt.inflightCommit,
+				authStore:      auth.NewAuthStore(logger, schema.NewAuthBackend(logger, be), nil, 0),
+			})
+			ts := httptest.NewServer(mux)
+			defer ts.Close()
+			checkHTTPResponse(t, ts, tt.healthCheckURL, tt.expectStatusCode, tt.inResult, tt.notInResult)
+		})
+	}
+}
+
+func TestHealthHandlerTimeout(t *testing.T) {
+	be, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, be)
+	tests := []struct {
+		healthTestCase
+		healthCheckTimeout time.Duration
+	}{
+		{
+			healthTestCase: healthTestCase{
+				name:             "Unhealthy if the read exceeds the health check timeout",
+				healthCheckURL:   "/health",
+				expectStatusCode: http.StatusServiceUnavailable,
+				inResult:         []string{"RANGE ERROR:context deadline exceeded"},
+			},
+			healthCheckTimeout: 10 * time.Millisecond,
+		},
+		{
+			healthTestCase: healthTestCase{
+				name:             "Healthy if the timeout query parameter is longer than the read",
+				healthCheckURL:   "/health?timeout=10s",
+				expectStatusCode: http.StatusOK,
+			},
+			healthCheckTimeout: 10 * time.Millisecond,
+		},
+		{
+			healthTestCase: healthTestCase{
+				name:             "Unhealthy if the read exceeds the timeout query parameter",
+				healthCheckURL:   "/health?timeout=10ms",
+				expectStatusCode: http.StatusServiceUnavailable,
+				inResult:         []string{"RANGE ERROR:context deadline exceeded"},
+			},
+		},
+		{
+			healthTestCase: healthTestCase{
+				name:             "Bad request if the timeout query parameter is invalid",
+				healthCheckURL:   "/health?timeout=soon",
+				expectStatusCode: http.StatusBadRequest,
+				inResult:         []string{`invalid timeout "soon"`},
+			},
+		},
+		{
+			healthTestCase: healthTestCase{
+				name:             "Bad request if the timeout query parameter is not positive",
+				healthCheckURL:   "/health?timeout=0s",
+				expectStatusCode: http.StatusBadRequest,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			logger := zaptest.NewLogger(t)
+			HandleHealth(logger, mux, &fakeHealthServer{
+				rangeDelay:         200 * time.Millisecond,
+				healthCheckTimeout: tt.healthCheckTimeout,
+				authStore:          auth.NewAuthStore(logger, schema.NewAuthBackend(logger, be), nil, 0),
+			})
+			ts := httptest.NewServer(mux)
+			defer ts.Close()
+			checkHTTPResponse(t, ts, tt.healthCheckURL, tt.expectStatusCode, tt.inResult, tt.notInResult)
+		})
+	}
+}
+
+func TestHealthMetrics(t *testing.T) {
+	be, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, be)
+	logger := zaptest.NewLogger(t)
+	s := &fakeHealthServer{
+		authStore: auth.NewAuthStore(logger, schema.NewAuthBackend(logger, be), nil, 0),
+	}
+	mux := http.NewServeMux()
+	HandleHealth(logger, mux, s)
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	start := float64(time.Now().Unix())
+	checkHTTPResponse(t, ts, "/health", http.StatusOK, nil, nil)
+	if got := promtestutil.ToFloat64(healthLastSuccess); got < start {
+		t.Errorf("expected last success timestamp at least %v, got %v", start, got)
+	}
+
+	noSpace := promtestutil.ToFloat64(healthFailuresByReason.WithLabelValues("ALARM NOSPACE"))
+	rangeErr := promtestutil.ToFloat64(healthFailuresByReason.WithLabelValues("RANGE ERROR"))
+	s.alarms = []*pb.AlarmMember{{MemberID: uint64(0), Alarm: pb.AlarmType_NOSPACE}}
+	checkHTTPResponse(t, ts, "/health", http.StatusServiceUnavailable, nil, nil)
+	s.alarms = nil
+	s.linearizableReadError = fmt.Errorf("etcdserver: request timed out")
+	checkHTTPResponse(t, ts, "/health", http.StatusServiceUnavailable, nil, nil)
+	checkHTTPResponse(t, ts, "/health", http.StatusServiceUnavailable, nil, nil)
+
+	if got := promtestutil.ToFloat64(healthFailuresByReason.WithLabelValues("ALARM NOSPACE")) - noSpace; got != 1 {
+		t.Errorf("expected 1 ALARM NOSPACE failure, got %v", got)
+	}
+	if got := promtestutil.ToFloat64(healthFailuresByReason.WithLabelValues("RANGE ERROR")) - rangeErr; got != 2 {
+		t.Errorf("expected 2 RANGE ERROR failures, got %v", got)
+	}
+	if got := promtestutil.ToFloat64(healthLastFailure); got < start {
+		t.Errorf("expected last failure timestamp at least %v, got %v", start, got)
+	}
+}
+
 func TestHTTPSubPath(t *testing.T) {
 	be, _ := betesting.NewDefaultTmpBackend(t)
 	defer betesting.Close(t, be)
@@ -192,6 +453,11 @@ func TestHTTPSubPath(t *testing.T) {
 			healthCheckURL:   "/readyz/non_learner",
 			expectStatusCode: http.StatusOK,
 		},
+		{
+			name:             "/readyz/quarantine ok",
+			healthCheckURL:   "/readyz/non_quarantined",
+			expectStatusCode: http.StatusOK,
+		},
 		{
 			name:             "/readyz/non_exist 404",
 			healthCheckURL:   "/readyz/non_exist",
@@ -236,13 +502,13 @@ func TestDataCorruptionCheck(t *testing.T) {
 		{
 			name:             "ready if CORRUPT alarm is not on",
 			alarms:           []*pb.AlarmMember{{MemberID: uint64(0), Alarm: pb.AlarmType_NOSPACE}},
-			healthCheckURL:   "/readyz",
+			healthCheckURL:   "/readyz?exclude=no_space",
 			expectStatusCode: http.StatusOK,
 		},
 		{
 			name:             "ready if CORRUPT alarm is excluded",
 			alarms:           []*pb.AlarmMember{{MemberID: uint64(0), Alarm: pb.AlarmType_CORRUPT}, {MemberID: uint64(0), Alarm: pb.AlarmType_NOSPACE}},
-			healthCheckURL:   "/readyz?exclude=data_corruption",
+			healthCheckURL:   "/readyz?exclude=data_corruption&exclude=no_space",
 			expectStatusCode: http.StatusOK,
 		},
 		{
@@ -391,6 +657,118 @@ func TestLearnerReadyCheck(t *testing.T) {
 	}
 }
 
+func TestRaftReadyChecks(t *testing.T) {
+	be, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, be)
+	tests := []struct {
+		healthTestCase
+		committedIndex uint64
+		appliedIndex   uint64
+		inflightCommit time.Duration
+	}{
+		{
+			healthTestCase: healthTestCase{
+				name:             "ready normal",
+				healthCheckURL:   "/readyz?verbose",
+				expectStatusCode: http.StatusOK,
+				inResult:         []string{"[+]leader ok", "[+]apply_lag ok", "[+]backend_commit ok"},
+			},
+			committedIndex: 10,
+			appliedIndex:   10,
+			inflightCommit: time.Second,
+		},
+		{
+			healthTestCase: healthTestCase{
+				name:             "not ready without leader",
+				healthCheckURL:   "/readyz/leader",
+				expectStatusCode: http.StatusServiceUnavailable,
+				inResult:         []string{"[-]leader failed: no leader"},
+				missingLeader:    true,
+			},
+		},
+		{
+			healthTestCase: healthTestCase{
+				name:             "alive without leader",
+				healthCheckURL:   "/livez",
+				expectStatusCode: http.StatusOK,
+				missingLeader:    true,
+			},
+		},
+		{
+			healthTestCase: healthTestCase{
+				name:             "ready if apply lag is small",
+				healthCheckURL:   "/readyz/apply_lag",
+				expectStatusCode: http.StatusOK,
+			},
+			committedIndex: etcdserver.MaxGapBetweenApplyAndCommitIndex + 10,
+			appliedIndex:   10,
+		},
+		{
+			healthTestCase: healthTestCase{
+				name:             "not ready if apply lags behind",
+				healthCheckURL:   "/readyz/apply_lag",
+				expectStatusCode: http.StatusServiceUnavailable,
+				inResult:         []string{"[-]apply_lag failed: applied index 10 lags behind committed index 5011"},
+			},
+			committedIndex: etcdserver.MaxGapBetweenApplyAndCommitIndex + 11,
+			appliedIndex:   10,
+		},
+		{
+			healthTestCase: healthTestCase{
+				name:             "not ready if backend commit is stalled",
+				healthCheckURL:   "/readyz/backend_commit",
+				expectStatusCode: http.StatusServiceUnavailable,
+				inResult:         []string{"[-]backend_commit failed: backend commit in progress for 6s"},
+			},
+			inflightCommit: 6 * time.Second,
+		},
+		{
+			healthTestCase: healthTestCase{
+				name:             "ready if stalled backend commit is excluded",
+				healthCheckURL:   "/readyz?exclude=backend_commit",
+				expectStatusCode: http.StatusOK,
+			},
+			inflightCommit: 6 * time.Second,
+		},
+		{
+			healthTestCase: healthTestCase{
+				name:             "not ready if NOSPACE alarm is on",
+				alarms:           []*pb.AlarmMember{{MemberID: uint64(0), Alarm: pb.AlarmType_NOSPACE}},
+				healthCheckURL:   "/readyz",
+				expectStatusCode: http.StatusServiceUnavailable,
+				inResult:         []string{"[-]no_space failed: alarm activated: NOSPACE"},
+			},
+		},
+		{
+			healthTestCase: healthTestCase{
+				name:             "alive if NOSPACE alarm is on",
+				alarms:           []*pb.AlarmMember{{MemberID: uint64(0), Alarm: pb.AlarmType_NOSPACE}},
+				healthCheckURL:   "/livez",
+				expectStatusCode: http.StatusOK,
+				notInResult:      []string{"no_space"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			logger := zaptest.NewLogger(t)
+			s := &fakeHealthServer{
+				missingLeader:  tt.missingLeader,
+				committedIndex: tt.committedIndex,
+				appliedIndex:   tt.appliedIndex,
+				inflightCommit: tt.inflightCommit,
+				authStore:      auth.NewAuthStore(logger, schema.NewAuthBackend(logger, be), nil, 0),
+			}
+			s.alarms = tt.alarms
+			HandleHealth(logger, mux, s)
+			ts := httptest.NewServer(mux)
+			defer ts.Close()
+			checkHTTPResponse(t, ts, tt.healthCheckURL, tt.expectStatusCode, tt.inResult, tt.notInResult)
+		})
+	}
+}
+
 func checkHTTPResponse(t *testing.T, ts *httptest.Server, url string, expectStatusCode int, inResult []string, notInResult []string) {
 	res, err := ts.Client().Do(&http.Request{Method: http.MethodGet, URL: testutil.MustNewURL(t, ts.URL+url)})
 	if err != nil {
@@ -0,0 +1,115 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdhttp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/server/v3/auth"
+	"go.etcd.io/etcd/server/v3/lease"
+)
+
+const (
+	PathLeaseKeepAlive = "/v3/lease/keepalive/"
+)
+
+// leaseRenewer is the subset of the server renewing leases on behalf of
+// authenticated users.
+type leaseRenewer interface {
+	AuthStore() auth.AuthStore
+	AuthInfoFromCtx(ctx context.Context) (*auth.AuthInfo, error)
+	LeaseRenew(ctx context.Context, id lease.LeaseID) (int64, error)
+}
+
+type leaseKeepAliveResponse struct {
+	ID  int64 `json:"id"`
+	TTL int64 `json:"ttl"`
+}
+
+// NewLeaseKeepAliveHandler returns the handler of '/v3/lease/keepalive/{id}'
+// renewing the lease with the given decimal, or 0x prefixed hexadecimal, ID
+// once on POST, so that leases can be kept alive without a gRPC client. When
+// auth is enabled, the request must carry a token in its Authorization header.
+func NewLeaseKeepAliveHandler(lg *zap.Logger, server leaseRenewer) http.Handler {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+		id, err := parseLeaseID(strings.TrimPrefix(r.URL.Path, PathLeaseKeepAlive))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid lease ID (%v)", err), http.StatusBadRequest)
+			return
+		}
+
+		ctx := r.Context()
+		if server.AuthStore().IsAuthEnabled() {
+			if token := r.Header.Get("Authorization"); token != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(rpctypes.TokenFieldNameGRPC, token))
+			}
+			ai, aerr := server.AuthInfoFromCtx(ctx)
+			if aerr != nil || ai == nil {
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		ttl, err := server.LeaseRenew(ctx, id)
+		switch {
+		case errors.Is(err, lease.ErrLeaseNotFound):
+			http.Error(w, rpctypes.ErrLeaseNotFound.Error(), http.StatusNotFound)
+			return
+		case err != nil:
+			lg.Warn("failed to renew lease", zap.Int64("lease-id", int64(id)), zap.Error(err))
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		b, err := json.Marshal(&leaseKeepAliveResponse{ID: int64(id), TTL: ttl})
+		if err != nil {
+			panic(fmt.Sprintf("cannot marshal lease keepalive response to json (%v)", err))
+		}
+		w.Write(b)
+	})
+}
+
+func parseLeaseID(s string) (lease.LeaseID, error) {
+	var (
+		id  int64
+		err error
+	)
+	if hex, ok := strings.CutPrefix(s, "0x"); ok {
+		id, err = strconv.ParseInt(hex, 16, 64)
+	} else {
+		id, err = strconv.ParseInt(s, 10, 64)
+	}
+	if err != nil {
+		return lease.NoLease, err
+	}
+	return lease.LeaseID(id), nil
+}
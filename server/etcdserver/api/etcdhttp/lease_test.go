@@ -0,0 +1,155 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdhttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc/metadata"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/server/v3/auth"
+	"go.etcd.io/etcd/server/v3/lease"
+)
+
+type fakeAuthStore struct {
+	auth.AuthStore
+	enabled bool
+}
+
+func (as *fakeAuthStore) IsAuthEnabled() bool { return as.enabled }
+
+type fakeLeaseRenewer struct {
+	as     *fakeAuthStore
+	leases map[lease.LeaseID]int64
+}
+
+func (s *fakeLeaseRenewer) AuthStore() auth.AuthStore { return s.as }
+
+func (s *fakeLeaseRenewer) AuthInfoFromCtx(ctx context.Context) (*auth.AuthInfo, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ts := md[rpctypes.TokenFieldNameGRPC]
+	switch {
+	case len(ts) == 0:
+		return nil, nil
+	case ts[0] != "valid":
+		return nil, auth.ErrInvalidAuthToken
+	}
+	return &auth.AuthInfo{Username: "user"}, nil
+}
+
+func (s *fakeLeaseRenewer) LeaseRenew(ctx context.Context, id lease.LeaseID) (int64, error) {
+	ttl, ok := s.leases[id]
+	if !ok {
+		return -1, lease.ErrLeaseNotFound
+	}
+	return ttl, nil
+}
+
+func TestLeaseKeepAliveHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		authEnabled bool
+		token       string
+
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "decimal ID",
+			method:   http.MethodPost,
+			path:     "/v3/lease/keepalive/10",
+			wantCode: http.StatusOK,
+			wantBody: `{"id":10,"ttl":60}`,
+		},
+		{
+			name:     "hexadecimal ID",
+			method:   http.MethodPost,
+			path:     "/v3/lease/keepalive/0xa",
+			wantCode: http.StatusOK,
+			wantBody: `{"id":10,"ttl":60}`,
+		},
+		{
+			name:     "invalid ID",
+			method:   http.MethodPost,
+			path:     "/v3/lease/keepalive/a",
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "lease not found",
+			method:   http.MethodPost,
+			path:     "/v3/lease/keepalive/11",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "method not allowed",
+			method:   http.MethodGet,
+			path:     "/v3/lease/keepalive/10",
+			wantCode: http.StatusMethodNotAllowed,
+		},
+		{
+			name:        "auth without token",
+			method:      http.MethodPost,
+			path:        "/v3/lease/keepalive/10",
+			authEnabled: true,
+			wantCode:    http.StatusUnauthorized,
+		},
+		{
+			name:        "auth with invalid token",
+			method:      http.MethodPost,
+			path:        "/v3/lease/keepalive/10",
+			authEnabled: true,
+			token:       "invalid",
+			wantCode:    http.StatusUnauthorized,
+		},
+		{
+			name:        "auth with valid token",
+			method:      http.MethodPost,
+			path:        "/v3/lease/keepalive/10",
+			authEnabled: true,
+			token:       "valid",
+			wantCode:    http.StatusOK,
+			wantBody:    `{"id":10,"ttl":60}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &fakeLeaseRenewer{
+				as:     &fakeAuthStore{enabled: tt.authEnabled},
+				leases: map[lease.LeaseID]int64{10: 60},
+			}
+			mux := http.NewServeMux()
+			mux.Handle(PathLeaseKeepAlive, NewLeaseKeepAliveHandler(zaptest.NewLogger(t), s))
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", tt.token)
+			}
+			rw := httptest.NewRecorder()
+			mux.ServeHTTP(rw, req)
+			if rw.Code != tt.wantCode {
+				t.Fatalf("code = %d, want %d (%s)", rw.Code, tt.wantCode, rw.Body.String())
+			}
+			if tt.wantBody != "" && rw.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rw.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdhttp
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+const (
+	PathLog = "/debug/log"
+)
+
+// LogSettings are the settings of the logger of a member that can be changed
+// at runtime.
+type LogSettings struct {
+	// Level is the level of the logger.
+	Level string `json:"level,omitempty"`
+	// Subsystems are the levels of the subsystems logging at another level
+	// than the logger, by subsystem name.
+	Subsystems map[string]string `json:"subsystems,omitempty"`
+	// Sampling is the sampling of the logger, nil if it is disabled.
+	Sampling *LogSampling `json:"sampling,omitempty"`
+}
+
+// LogSampling is the sampling of the entries of a logger: each second, the
+// first Initial entries with the same level and message are logged, then
+// every Thereafter-th entry of them.
+type LogSampling struct {
+	Initial    int `json:"initial"`
+	Thereafter int `json:"thereafter"`
+}
+
+// LogController changes the settings of the logger of a member at runtime.
+type LogController interface {
+	LogSettings() LogSettings
+	// SetLogSettings changes the settings set in s: the level if it is not
+	// empty, the level of each subsystem of s, or back to the level of the
+	// logger if it is empty, and the sampling if it is not nil, disabling it
+	// if its Initial is 0.
+	SetLogSettings(s LogSettings) error
+}
+
+// HandleLog registers the handler of '/debug/log', returning the settings of
+// the logger on GET and changing them with the settings of the body on PUT.
+// When auth is enabled, the request must carry the token of a root user in
+// its Authorization header.
+func HandleLog(lg *zap.Logger, mux *http.ServeMux, server adminAuthorizer, lc LogController) {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	mux.Handle(PathLog, authorizeAdmin(server, &logHandler{lg: lg, lc: lc}))
+}
+
+type logHandler struct {
+	lg *zap.Logger
+	lc LogController
+}
+
+func (h *logHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var s LogSettings
+		if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+			http.Error(w, "error unmarshalling request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.lc.SetLogSettings(s); err != nil {
+			h.lg.Warn("failed to change log settings", zap.Error(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, PUT")
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	b, err := json.Marshal(h.lc.LogSettings())
+	if err != nil {
+		h.lg.Warn("failed to marshal log settings", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
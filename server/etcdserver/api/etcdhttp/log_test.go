@@ -0,0 +1,72 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdhttp
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+)
+
+type fakeLogController struct {
+	s LogSettings
+}
+
+func (lc *fakeLogController) LogSettings() LogSettings { return lc.s }
+
+func (lc *fakeLogController) SetLogSettings(s LogSettings) error {
+	if s.Level == "invalid" {
+		return errors.New("invalid log level")
+	}
+	if s.Level != "" {
+		lc.s.Level = s.Level
+	}
+	return nil
+}
+
+func TestLogHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		body   string
+
+		wantCode int
+		wantBody string
+	}{
+		{name: "get", method: http.MethodGet, wantCode: http.StatusOK, wantBody: `{"level":"info"}`},
+		{name: "put", method: http.MethodPut, body: `{"level":"debug"}`, wantCode: http.StatusOK, wantBody: `{"level":"debug"}`},
+		{name: "put invalid body", method: http.MethodPut, body: `{"level":`, wantCode: http.StatusBadRequest},
+		{name: "put invalid settings", method: http.MethodPut, body: `{"level":"invalid"}`, wantCode: http.StatusBadRequest},
+		{name: "delete", method: http.MethodDelete, wantCode: http.StatusMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			server := &fakeAdminAuthorizer{as: &fakeAdminAuthStore{}}
+			HandleLog(zaptest.NewLogger(t), mux, server, &fakeLogController{s: LogSettings{Level: "info"}})
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(tt.method, PathLog, strings.NewReader(tt.body)))
+			assert.Equal(t, tt.wantCode, rec.Code)
+			if tt.wantBody != "" {
+				assert.JSONEq(t, tt.wantBody, rec.Body.String())
+			}
+		})
+	}
+}
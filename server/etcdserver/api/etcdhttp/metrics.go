@@ -16,8 +16,11 @@ package etcdhttp
 
 import (
 	"net/http"
+	"strings"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	dto "github.com/prometheus/client_model/go"
 )
 
 const (
@@ -27,5 +30,59 @@ const (
 
 // HandleMetrics registers prometheus handler on '/metrics'.
 func HandleMetrics(mux *http.ServeMux) {
-	mux.Handle(PathMetrics, promhttp.Handler())
+	HandleFilteredMetrics(mux, nil)
+}
+
+// HandleFilteredMetrics registers prometheus handler on '/metrics' that does
+// not expose the metrics whose names start with any of the denylist prefixes.
+// Passing the query parameter "filter=<prefix>" (possibly repeated) exposes
+// only the metrics whose names start with one of the given prefixes.
+func HandleFilteredMetrics(mux *http.ServeMux, denylist []string) {
+	mux.Handle(PathMetrics, promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			g := &filteredGatherer{
+				Gatherer: prometheus.DefaultGatherer,
+				allow:    r.URL.Query()["filter"],
+				deny:     denylist,
+			}
+			promhttp.HandlerFor(g, promhttp.HandlerOpts{}).ServeHTTP(w, r)
+		}),
+	))
+}
+
+// filteredGatherer drops the metric families denied by name prefix, or not
+// allowed if any allowed prefix is given.
+type filteredGatherer struct {
+	prometheus.Gatherer
+	allow []string
+	deny  []string
+}
+
+func (g *filteredGatherer) Gather() ([]*dto.MetricFamily, error) {
+	mfs, err := g.Gatherer.Gather()
+	if len(g.allow) == 0 && len(g.deny) == 0 {
+		return mfs, err
+	}
+	filtered := mfs[:0]
+	for _, mf := range mfs {
+		name := mf.GetName()
+		if hasAnyPrefix(name, g.deny) {
+			continue
+		}
+		if len(g.allow) > 0 && !hasAnyPrefix(name, g.allow) {
+			continue
+		}
+		filtered = append(filtered, mf)
+	}
+	return filtered, err
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,80 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdhttp
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleFilteredMetrics(t *testing.T) {
+	for _, name := range []string{"etcd_test_filter_kept_total", "etcd_test_filter_denied_total", "etcd_test_other_total"} {
+		c := prometheus.NewCounter(prometheus.CounterOpts{Name: name, Help: name})
+		prometheus.MustRegister(c)
+		defer prometheus.Unregister(c)
+	}
+
+	mux := http.NewServeMux()
+	HandleFilteredMetrics(mux, []string{"etcd_test_filter_denied"})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	tests := []struct {
+		name        string
+		query       string
+		inResult    []string
+		notInResult []string
+	}{
+		{
+			name:        "denylist",
+			inResult:    []string{"etcd_test_filter_kept_total", "etcd_test_other_total", "go_goroutines"},
+			notInResult: []string{"etcd_test_filter_denied_total"},
+		},
+		{
+			name:        "filter",
+			query:       "?filter=etcd_test_filter",
+			inResult:    []string{"etcd_test_filter_kept_total"},
+			notInResult: []string{"etcd_test_filter_denied_total", "etcd_test_other_total", "go_goroutines"},
+		},
+		{
+			name:        "multiple filters",
+			query:       "?filter=etcd_test_filter&filter=etcd_test_other",
+			inResult:    []string{"etcd_test_filter_kept_total", "etcd_test_other_total"},
+			notInResult: []string{"etcd_test_filter_denied_total", "go_goroutines"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := ts.Client().Get(ts.URL + PathMetrics + tt.query)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			b, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			for _, s := range tt.inResult {
+				require.Truef(t, strings.Contains(string(b), s), "expected %q in /metrics", s)
+			}
+			for _, s := range tt.notInResult {
+				require.Falsef(t, strings.Contains(string(b), s), "unexpected %q in /metrics", s)
+			}
+		})
+	}
+}
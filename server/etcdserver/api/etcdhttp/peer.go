@@ -40,7 +40,7 @@ const (
 
 // NewPeerHandler generates an http.Handler to handle etcd peer requests.
 func NewPeerHandler(lg *zap.Logger, s etcdserver.ServerPeerV2) http.Handler {
-	return newPeerHandler(lg, s, s.RaftHandler(), s.LeaseHandler(), s.HashKVHandler(), s.DowngradeEnabledHandler())
+	return newPeerHandler(lg, s, s.RaftHandler(), s.LeaseHandler(), s.HashKVHandler(), s.DowngradeEnabledHandler(), s.DowngradeCheckHandler())
 }
 
 func newPeerHandler(
@@ -50,6 +50,7 @@ func newPeerHandler(
 	leaseHandler http.Handler,
 	hashKVHandler http.Handler,
 	downgradeEnabledHandler http.Handler,
+	downgradeCheckHandler http.Handler,
 ) http.Handler {
 	if lg == nil {
 		lg = zap.NewNop()
@@ -73,6 +74,9 @@ func newPeerHandler(
 	if hashKVHandler != nil {
 		mux.Handle(etcdserver.PeerHashKVPath, hashKVHandler)
 	}
+	if downgradeCheckHandler != nil {
+		mux.Handle(etcdserver.PeerDowngradeCheckPath, downgradeCheckHandler)
+	}
 	mux.HandleFunc(versionPath, versionHandler(s, serveVersion))
 	return mux
 }
@@ -144,6 +148,8 @@ func (h *peerMemberPromoteHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 			http.Error(w, err.Error(), http.StatusNotFound)
 		case errorspkg.Is(err, membership.ErrMemberNotLearner):
 			http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		case errorspkg.Is(err, membership.ErrMemberStandby):
+			http.Error(w, err.Error(), http.StatusPreconditionFailed)
 		case errorspkg.Is(err, errors.ErrLearnerNotReady):
 			http.Error(w, err.Error(), http.StatusPreconditionFailed)
 		default:
@@ -0,0 +1,65 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdhttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.etcd.io/etcd/server/v3/etcdserver"
+)
+
+const (
+	PathWatermarks = "/debug/watermarks"
+)
+
+// watermarksServer is the subset of the server that reports resource
+// high-watermarks.
+type watermarksServer interface {
+	adminAuthorizer
+	Watermarks() etcdserver.Watermarks
+	ResetWatermarks() etcdserver.Watermarks
+}
+
+// HandleWatermarks registers a handler on '/debug/watermarks' that reports
+// resource high-watermarks on GET, and resets them on DELETE returning the
+// high-watermarks from before the reset. When auth is enabled, a reset must
+// carry the token of a root user in its Authorization header.
+func HandleWatermarks(mux *http.ServeMux, server watermarksServer) {
+	reset := authorizeAdmin(server, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeWatermarks(w, server.ResetWatermarks())
+	}))
+	mux.HandleFunc(PathWatermarks, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			writeWatermarks(w, server.Watermarks())
+		case http.MethodDelete:
+			reset.ServeHTTP(w, r)
+		default:
+			w.Header().Set("Allow", "GET, DELETE")
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	})
+}
+
+func writeWatermarks(w http.ResponseWriter, wm etcdserver.Watermarks) {
+	w.Header().Set("Content-Type", "application/json")
+	b, err := json.Marshal(&wm)
+	if err != nil {
+		panic(fmt.Sprintf("cannot marshal watermarks to json (%v)", err))
+	}
+	w.Write(b)
+}
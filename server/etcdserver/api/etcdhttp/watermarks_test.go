@@ -0,0 +1,69 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.etcd.io/etcd/server/v3/etcdserver"
+)
+
+type fakeWatermarksServer struct {
+	fakeAdminAuthorizer
+	resets int
+}
+
+func (s *fakeWatermarksServer) Watermarks() etcdserver.Watermarks {
+	return etcdserver.Watermarks{Watchers: 1}
+}
+
+func (s *fakeWatermarksServer) ResetWatermarks() etcdserver.Watermarks {
+	s.resets++
+	return etcdserver.Watermarks{Watchers: 1}
+}
+
+func TestHandleWatermarksAuth(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		token      string
+		wantCode   int
+		wantResets int
+	}{
+		{name: "get without token", method: http.MethodGet, wantCode: http.StatusOK},
+		{name: "reset without token", method: http.MethodDelete, wantCode: http.StatusUnauthorized},
+		{name: "reset by non-root user", method: http.MethodDelete, token: "user", wantCode: http.StatusForbidden},
+		{name: "reset by root", method: http.MethodDelete, token: "root", wantCode: http.StatusOK, wantResets: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &fakeWatermarksServer{fakeAdminAuthorizer: fakeAdminAuthorizer{as: &fakeAdminAuthStore{fakeAuthStore{enabled: true}}}}
+			mux := http.NewServeMux()
+			HandleWatermarks(mux, server)
+			req := httptest.NewRequest(tt.method, PathWatermarks, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", tt.token)
+			}
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantResets, server.resets)
+		})
+	}
+}
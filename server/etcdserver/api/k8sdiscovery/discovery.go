@@ -0,0 +1,268 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package k8sdiscovery provides the bootstrap of the initial cluster from the
+// pods listed by the Kubernetes API, typically the pods of a StatefulSet.
+package k8sdiscovery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+	"go.etcd.io/etcd/client/pkg/v3/types"
+)
+
+const (
+	DefaultPeerPort      = 2380
+	DefaultClusterDomain = "cluster.local"
+	DefaultTimeout       = 5 * time.Minute
+
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+)
+
+var (
+	ErrNotInCluster  = errors.New("k8sdiscovery: not running in a Kubernetes cluster")
+	ErrTooManyPods   = errors.New("k8sdiscovery: more pods than the cluster size match the label selector")
+	ErrLocalNotFound = errors.New("k8sdiscovery: cannot find the pod of the local member")
+
+	// pollInterval is the interval at which the pods are listed until the
+	// cluster is complete.
+	pollInterval = 2 * time.Second
+)
+
+// Config configures the discovery of the initial cluster from the pods
+// matching LabelSelector in Namespace. Each pod is a member named after the
+// pod, whose peer URL is the DNS name given to the pod by its headless
+// service, or its IP if it has none.
+type Config struct {
+	// LabelSelector selects the pods of the members, e.g. "app=etcd".
+	// Discovery is disabled if empty.
+	LabelSelector string `json:"label-selector"`
+	// Namespace is the namespace of the pods, by default the namespace of
+	// the service account of the local pod.
+	Namespace string `json:"namespace"`
+	// ClusterSize is the number of members of the initial cluster. The
+	// discovery waits until as many pods are scheduled.
+	ClusterSize int `json:"cluster-size"`
+	// PeerPort is the port of the peer URLs of the members.
+	PeerPort int `json:"peer-port"`
+	// ClusterDomain is the DNS domain of the Kubernetes cluster.
+	ClusterDomain string `json:"cluster-domain"`
+	// Timeout is the maximum duration of the discovery.
+	Timeout time.Duration `json:"timeout"`
+}
+
+// Validate returns an error if the configuration is invalid.
+func (cfg *Config) Validate() error {
+	if cfg.LabelSelector == "" {
+		return nil
+	}
+	if cfg.ClusterSize <= 0 {
+		return fmt.Errorf("--discovery-k8s-cluster-size must be >0 (set to %d)", cfg.ClusterSize)
+	}
+	if cfg.PeerPort <= 0 || cfg.PeerPort > 65535 {
+		return fmt.Errorf("--discovery-k8s-peer-port %d is not a valid port", cfg.PeerPort)
+	}
+	if cfg.Timeout <= 0 {
+		return fmt.Errorf("--discovery-k8s-timeout must be >0 (set to %v)", cfg.Timeout)
+	}
+	return nil
+}
+
+// GetCluster lists the pods from the Kubernetes API of the cluster the local
+// member runs in until ClusterSize pods are scheduled, and returns the initial
+// cluster as "name=url" strings. The local member must run in the pod named
+// name, whose peer URLs are apurls.
+func GetCluster(lg *zap.Logger, cfg Config, name string, apurls types.URLs) ([]string, error) {
+	c, err := newInClusterClient()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Namespace == "" {
+		cfg.Namespace = c.namespace
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+	defer cancel()
+	return getCluster(ctx, lg, c, cfg, name, apurls)
+}
+
+func getCluster(ctx context.Context, lg *zap.Logger, c *client, cfg Config, name string, apurls types.URLs) ([]string, error) {
+	if len(apurls) == 0 {
+		return nil, errors.New("k8sdiscovery: no advertised peer URLs")
+	}
+	scheme := apurls[0].Scheme
+	for {
+		pods, err := c.listPods(ctx, cfg.Namespace, cfg.LabelSelector)
+		if err == nil {
+			var cluster []string
+			cluster, err = clusterOf(pods, cfg, scheme, name, apurls)
+			if err == nil {
+				return cluster, nil
+			}
+			if errors.Is(err, ErrTooManyPods) {
+				return nil, err
+			}
+		}
+		lg.Info(
+			"waiting for the pods of the initial cluster",
+			zap.String("namespace", cfg.Namespace),
+			zap.String("label-selector", cfg.LabelSelector),
+			zap.Int("cluster-size", cfg.ClusterSize),
+			zap.Error(err),
+		)
+		select {
+		case <-time.After(pollInterval):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("k8sdiscovery: %w (last error: %w)", ctx.Err(), err)
+		}
+	}
+}
+
+// clusterOf returns the initial cluster of the given pods, or an error if it
+// is not complete yet.
+func clusterOf(pods []pod, cfg Config, scheme, name string, apurls types.URLs) ([]string, error) {
+	var live []pod
+	for _, p := range pods {
+		if p.Metadata.DeletionTimestamp == "" {
+			live = append(live, p)
+		}
+	}
+	if len(live) > cfg.ClusterSize {
+		return nil, fmt.Errorf("%w: found %d pods, expected %d", ErrTooManyPods, len(live), cfg.ClusterSize)
+	}
+	if len(live) < cfg.ClusterSize {
+		return nil, fmt.Errorf("found %d of %d pods", len(live), cfg.ClusterSize)
+	}
+	sort.Slice(live, func(i, j int) bool { return live[i].Metadata.Name < live[j].Metadata.Name })
+
+	var cluster []string
+	local := false
+	for _, p := range live {
+		if p.Metadata.Name == name {
+			local = true
+			for _, u := range apurls {
+				cluster = append(cluster, fmt.Sprintf("%s=%s", name, u.String()))
+			}
+			continue
+		}
+		var host string
+		switch {
+		case p.Spec.Hostname != "" && p.Spec.Subdomain != "":
+			host = strings.Join([]string{p.Spec.Hostname, p.Spec.Subdomain, p.Metadata.Namespace, "svc", cfg.ClusterDomain}, ".")
+		case p.Status.PodIP != "":
+			host = p.Status.PodIP
+		default:
+			return nil, fmt.Errorf("pod %q has no address yet", p.Metadata.Name)
+		}
+		u := url.URL{Scheme: scheme, Host: net.JoinHostPort(host, strconv.Itoa(cfg.PeerPort))}
+		cluster = append(cluster, fmt.Sprintf("%s=%s", p.Metadata.Name, u.String()))
+	}
+	if !local {
+		return nil, fmt.Errorf("%w: no pod named %q", ErrLocalNotFound, name)
+	}
+	return cluster, nil
+}
+
+type pod struct {
+	Metadata struct {
+		Name              string `json:"name"`
+		Namespace         string `json:"namespace"`
+		DeletionTimestamp string `json:"deletionTimestamp"`
+	} `json:"metadata"`
+	Spec struct {
+		Hostname  string `json:"hostname"`
+		Subdomain string `json:"subdomain"`
+	} `json:"spec"`
+	Status struct {
+		PodIP string `json:"podIP"`
+	} `json:"status"`
+}
+
+type podList struct {
+	Items []pod `json:"items"`
+}
+
+// client is a minimal client of the Kubernetes API.
+type client struct {
+	host      string
+	token     string
+	namespace string
+	hc        *http.Client
+}
+
+// newInClusterClient returns a client authenticated with the service account
+// of the local pod.
+func newInClusterClient() (*client, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, ErrNotInCluster
+	}
+	token, err := os.ReadFile(serviceAccountDir + "/token")
+	if err != nil {
+		return nil, fmt.Errorf("k8sdiscovery: %w", err)
+	}
+	namespace, err := os.ReadFile(serviceAccountDir + "/namespace")
+	if err != nil {
+		return nil, fmt.Errorf("k8sdiscovery: %w", err)
+	}
+	tlsInfo := transport.TLSInfo{TrustedCAFile: serviceAccountDir + "/ca.crt"}
+	tlsCfg, err := tlsInfo.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("k8sdiscovery: %w", err)
+	}
+	return &client{
+		host:      "https://" + net.JoinHostPort(host, port),
+		token:     strings.TrimSpace(string(token)),
+		namespace: strings.TrimSpace(string(namespace)),
+		hc:        &http.Client{Transport: &http.Transport{TLSClientConfig: tlsCfg}},
+	}, nil
+}
+
+func (c *client) listPods(ctx context.Context, namespace, labelSelector string) ([]pod, error) {
+	u := c.host + "/api/v1/namespaces/" + url.PathEscape(namespace) + "/pods?" + url.Values{"labelSelector": {labelSelector}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("listing pods: %s", resp.Status)
+	}
+	var l podList
+	if err := json.NewDecoder(resp.Body).Decode(&l); err != nil {
+		return nil, err
+	}
+	return l.Items, nil
+}
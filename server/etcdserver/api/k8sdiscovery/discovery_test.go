@@ -0,0 +1,100 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sdiscovery
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/client/pkg/v3/types"
+)
+
+func newPod(name, hostname, subdomain, ip string) pod {
+	var p pod
+	p.Metadata.Name, p.Metadata.Namespace = name, "db"
+	p.Spec.Hostname, p.Spec.Subdomain = hostname, subdomain
+	p.Status.PodIP = ip
+	return p
+}
+
+func TestGetCluster(t *testing.T) {
+	defer func(d time.Duration) { pollInterval = d }(pollInterval)
+	pollInterval = 10 * time.Millisecond
+
+	var mu sync.Mutex
+	pods := []pod{newPod("etcd-0", "etcd-0", "etcd", "")}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/namespaces/db/pods", r.URL.Path)
+		assert.Equal(t, "app=etcd", r.URL.Query().Get("labelSelector"))
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		mu.Lock()
+		defer mu.Unlock()
+		assert.NoError(t, json.NewEncoder(w).Encode(podList{Items: pods}))
+		// the pods are scheduled one at a time.
+		switch len(pods) {
+		case 1:
+			pods = append(pods, newPod("etcd-2", "", "", "10.0.0.2"))
+		case 2:
+			pods = append(pods, newPod("etcd-1", "etcd-1", "etcd", ""))
+		}
+	}))
+	defer srv.Close()
+
+	c := &client{host: srv.URL, token: "secret", hc: srv.Client()}
+	cfg := Config{LabelSelector: "app=etcd", Namespace: "db", ClusterSize: 3, PeerPort: 2380, ClusterDomain: "cluster.local"}
+	apurls := types.MustNewURLs([]string{"https://etcd-1.etcd.db.svc.cluster.local:2380"})
+	cluster, err := getCluster(context.Background(), zaptest.NewLogger(t), c, cfg, "etcd-1", apurls)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"etcd-0=https://etcd-0.etcd.db.svc.cluster.local:2380",
+		"etcd-1=https://etcd-1.etcd.db.svc.cluster.local:2380",
+		"etcd-2=https://10.0.0.2:2380",
+	}, cluster)
+
+	// the local pod must be part of the cluster.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = getCluster(ctx, zaptest.NewLogger(t), c, cfg, "etcd-3", apurls)
+	require.ErrorIs(t, err, ErrLocalNotFound)
+
+	cfg.ClusterSize = 2
+	_, err = getCluster(context.Background(), zaptest.NewLogger(t), c, cfg, "etcd-1", apurls)
+	require.ErrorIs(t, err, ErrTooManyPods)
+}
+
+func TestConfigValidate(t *testing.T) {
+	valid := Config{LabelSelector: "app=etcd", ClusterSize: 3, PeerPort: DefaultPeerPort, Timeout: DefaultTimeout}
+	require.NoError(t, valid.Validate())
+	require.NoError(t, (&Config{}).Validate())
+
+	for _, mutate := range []func(*Config){
+		func(c *Config) { c.ClusterSize = 0 },
+		func(c *Config) { c.PeerPort = 0 },
+		func(c *Config) { c.Timeout = 0 },
+	} {
+		cfg := valid
+		mutate(&cfg)
+		require.Error(t, cfg.Validate())
+	}
+}
@@ -334,6 +334,9 @@ func (c *RaftCluster) ValidateConfigurationChange(cc raftpb.ConfChange, shouldAp
 			if !membersMap[id].IsLearner {
 				return ErrMemberNotLearner
 			}
+			if membersMap[id].IsStandby {
+				return ErrMemberStandby
+			}
 		} else { // adding a new member
 			if membersMap[id] != nil {
 				return ErrIDExists
@@ -419,6 +422,7 @@ func (c *RaftCluster) AddMember(m *Member, shouldApplyV3 ShouldApplyV3) {
 			zap.String("added-peer-id", m.ID.String()),
 			zap.Strings("added-peer-peer-urls", m.PeerURLs),
 			zap.Bool("added-peer-is-learner", m.IsLearner),
+			zap.Bool("added-peer-is-standby", m.IsStandby),
 		)
 	} else {
 		c.lg.Info(
@@ -540,6 +544,10 @@ func (c *RaftCluster) UpdateRaftAttributes(id types.ID, raftAttr RaftAttributes,
 	c.Lock()
 	defer c.Unlock()
 
+	// a standby member stays a standby learner when its peer URLs are updated
+	if c.members[id].IsStandby {
+		raftAttr.IsLearner, raftAttr.IsStandby = true, true
+	}
 	m := *(c.members[id])
 	m.RaftAttributes = raftAttr
 	mustUpdateMemberInStore(c.lg, c.v2store, &m)
@@ -789,6 +797,21 @@ func (c *RaftCluster) IsLocalMemberLearner() bool {
 	return localMember.IsLearner
 }
 
+// IsLocalMemberStandby returns if the local member is a standby member.
+func (c *RaftCluster) IsLocalMemberStandby() bool {
+	c.Lock()
+	defer c.Unlock()
+	localMember, ok := c.members[c.localID]
+	if !ok {
+		c.lg.Panic(
+			"failed to find local ID in cluster members",
+			zap.String("cluster-id", c.cid.String()),
+			zap.String("local-member-id", c.localID.String()),
+		)
+	}
+	return localMember.IsStandby
+}
+
 // DowngradeInfo returns the downgrade status of the cluster
 func (c *RaftCluster) DowngradeInfo() *serverversion.DowngradeInfo {
 	c.Lock()
@@ -821,6 +844,21 @@ func (c *RaftCluster) IsMemberExist(id types.ID) bool {
 	return ok
 }
 
+// LeaderCandidateIDs returns the ID of the voting members the leadership can
+// be moved to, all but the witnesses storing no keys.
+func (c *RaftCluster) LeaderCandidateIDs() []types.ID {
+	c.Lock()
+	defer c.Unlock()
+	var ids []types.ID
+	for _, m := range c.members {
+		if !m.IsLearner && !m.IsWitness {
+			ids = append(ids, m.ID)
+		}
+	}
+	sort.Sort(types.IDSlice(ids))
+	return ids
+}
+
 // VotingMemberIDs returns the ID of voting members in cluster.
 func (c *RaftCluster) VotingMemberIDs() []types.ID {
 	c.Lock()
@@ -95,6 +95,19 @@ func TestClusterMemberIDs(t *testing.T) {
 	}
 }
 
+func TestClusterLeaderCandidateIDs(t *testing.T) {
+	learner := newTestMember(4, nil, "", nil)
+	learner.IsLearner = true
+	witness := newTestMember(100, nil, "", nil)
+	witness.IsWitness = true
+	c := newTestCluster(t, []*Member{newTestMember(1, nil, "", nil), learner, witness, newTestMember(200, nil, "", nil)})
+	w := []types.ID{1, 200}
+	g := c.LeaderCandidateIDs()
+	if !reflect.DeepEqual(w, g) {
+		t.Errorf("IDs = %+v, want %+v", g, w)
+	}
+}
+
 func TestClusterPeerURLs(t *testing.T) {
 	tests := []struct {
 		mems  []*Member
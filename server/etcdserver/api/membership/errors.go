@@ -27,6 +27,7 @@ var (
 	ErrPeerURLexists    = errors.New("membership: peerURL exists")
 	ErrMemberNotLearner = errors.New("membership: can only promote a learner member")
 	ErrTooManyLearners  = errors.New("membership: too many learner members in cluster")
+	ErrMemberStandby    = errors.New("membership: cannot promote a standby member")
 )
 
 func isKeyNotFound(err error) bool {
@@ -32,12 +32,18 @@ type RaftAttributes struct {
 	PeerURLs []string `json:"peerURLs"`
 	// IsLearner indicates if the member is raft learner.
 	IsLearner bool `json:"isLearner,omitempty"`
+	// IsStandby indicates if the member is a standby member, a learner that
+	// serves reads and watches but is never promoted.
+	IsStandby bool `json:"isStandby,omitempty"`
 }
 
 // Attributes represents all the non-raft related attributes of an etcd member.
 type Attributes struct {
 	Name       string   `json:"name,omitempty"`
 	ClientURLs []string `json:"clientURLs,omitempty"`
+	// IsWitness indicates if the member is a witness, voting in raft but
+	// storing no keys.
+	IsWitness bool `json:"isWitness,omitempty"`
 }
 
 type Member struct {
@@ -60,6 +66,14 @@ func NewMemberAsLearner(name string, peerURLs types.URLs, clusterName string, no
 	return newMember(name, peerURLs, memberID, true)
 }
 
+// NewMemberAsStandby creates a standby Member without an ID and generates one based on the
+// cluster name, peer URLs, and time. This is used for adding new standby member.
+func NewMemberAsStandby(name string, peerURLs types.URLs, clusterName string, now *time.Time) *Member {
+	m := NewMemberAsLearner(name, peerURLs, clusterName, now)
+	m.IsStandby = true
+	return m
+}
+
 func computeMemberID(peerURLs types.URLs, clusterName string, now *time.Time) types.ID {
 	peerURLstrs := peerURLs.StringSlice()
 	sort.Strings(peerURLstrs)
@@ -95,9 +109,11 @@ func (m *Member) Clone() *Member {
 		ID: m.ID,
 		RaftAttributes: RaftAttributes{
 			IsLearner: m.IsLearner,
+			IsStandby: m.IsStandby,
 		},
 		Attributes: Attributes{
-			Name: m.Name,
+			Name:      m.Name,
+			IsWitness: m.IsWitness,
 		},
 	}
 	if m.PeerURLs != nil {
@@ -0,0 +1,211 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rafthttp
+
+import (
+	"fmt"
+	"math/rand"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/client/pkg/v3/types"
+	"go.etcd.io/raft/v3/raftpb"
+)
+
+// PeerFault is a degradation of the network injected in the raft messages
+// sent to a peer, so that the behavior of a cluster under asymmetric network
+// faults can be reproduced in tests. It is for testing only.
+type PeerFault struct {
+	// Latency delays each message.
+	Latency time.Duration
+	// Jitter is the maximum random deviation of the delay of a message from
+	// Latency. The messages are still delivered in order.
+	Jitter time.Duration
+	// DropRate is the probability, between 0 and 1, of dropping a message.
+	DropRate float64
+}
+
+// ParsePeerFaults parses faults given as '<peer-url>=<latency>/<jitter>/<drop-rate>',
+// where any of the latency, the jitter and the drop rate may be left out,
+// and returns them by the scheme and the host of the peer URL.
+func ParsePeerFaults(faults []string) (map[string]PeerFault, error) {
+	parsed := make(map[string]PeerFault, len(faults))
+	for _, s := range faults {
+		i := strings.LastIndex(s, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid peer fault %q: missing fault", s)
+		}
+		u, err := url.Parse(s[:i])
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("invalid peer fault %q: invalid peer URL %q", s, s[:i])
+		}
+		var f PeerFault
+		latency, rest, _ := strings.Cut(s[i+1:], "/")
+		jitter, dropRate, _ := strings.Cut(rest, "/")
+		if latency != "" {
+			if f.Latency, err = time.ParseDuration(latency); err != nil || f.Latency < 0 {
+				return nil, fmt.Errorf("invalid peer fault %q: invalid latency %q", s, latency)
+			}
+		}
+		if jitter != "" {
+			if f.Jitter, err = time.ParseDuration(jitter); err != nil || f.Jitter < 0 {
+				return nil, fmt.Errorf("invalid peer fault %q: invalid jitter %q", s, jitter)
+			}
+		}
+		if dropRate != "" {
+			if f.DropRate, err = strconv.ParseFloat(dropRate, 64); err != nil || f.DropRate < 0 || f.DropRate > 1 {
+				return nil, fmt.Errorf("invalid peer fault %q: invalid drop rate %q", s, dropRate)
+			}
+		}
+		if f == (PeerFault{}) {
+			return nil, fmt.Errorf("invalid peer fault %q: no fault", s)
+		}
+		parsed[peerFaultKey(*u)] = f
+	}
+	return parsed, nil
+}
+
+func peerFaultKey(u url.URL) string {
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host}).String()
+}
+
+// peerFault returns the fault injected in the messages sent to the peer
+// with the given URLs, if any of them is faulty.
+func (t *Transport) peerFault(urls types.URLs) (PeerFault, bool) {
+	for _, u := range urls {
+		if f, ok := t.PeerFaults[peerFaultKey(u)]; ok {
+			return f, true
+		}
+	}
+	return PeerFault{}, false
+}
+
+// faultInjector drops and delays the messages sent to a peer before writing
+// them. The random decisions are drawn in the order the messages are sent,
+// from a source seeded by the transport and the peer, so that a run with
+// the same messages injects the same faults.
+type faultInjector struct {
+	lg     *zap.Logger
+	peerID types.ID
+	fault  PeerFault
+	write  func(m raftpb.Message)
+
+	mu   sync.Mutex
+	rand *rand.Rand
+	// last is the time the last delayed message is delivered at.
+	last time.Time
+
+	msgc  chan delayedMessage
+	stopc chan struct{}
+	donec chan struct{}
+}
+
+type delayedMessage struct {
+	m  raftpb.Message
+	at time.Time
+}
+
+func startFaultInjector(lg *zap.Logger, peerID types.ID, fault PeerFault, seed int64, write func(m raftpb.Message)) *faultInjector {
+	if lg != nil {
+		lg.Warn(
+			"injecting faults in the messages sent to remote peer",
+			zap.String("remote-peer-id", peerID.String()),
+			zap.Duration("latency", fault.Latency),
+			zap.Duration("jitter", fault.Jitter),
+			zap.Float64("drop-rate", fault.DropRate),
+		)
+	}
+	f := &faultInjector{
+		lg:     lg,
+		peerID: peerID,
+		fault:  fault,
+		write:  write,
+		rand:   rand.New(rand.NewSource(seed ^ int64(peerID))),
+		msgc:   make(chan delayedMessage, streamBufSize),
+		stopc:  make(chan struct{}),
+		donec:  make(chan struct{}),
+	}
+	go f.run()
+	return f
+}
+
+// send drops the message, or writes it once its delay has elapsed.
+func (f *faultInjector) send(m raftpb.Message) {
+	f.mu.Lock()
+	if f.fault.DropRate > 0 && f.rand.Float64() < f.fault.DropRate {
+		f.mu.Unlock()
+		if f.lg != nil {
+			f.lg.Debug(
+				"dropped internal Raft message by injected fault",
+				zap.String("message-type", m.Type.String()),
+				zap.String("remote-peer-id", f.peerID.String()),
+			)
+		}
+		return
+	}
+	delay := f.fault.Latency
+	if f.fault.Jitter > 0 {
+		delay += time.Duration(f.rand.Int63n(2*int64(f.fault.Jitter)+1)) - f.fault.Jitter
+	}
+	if delay <= 0 && f.last.IsZero() {
+		f.mu.Unlock()
+		f.write(m)
+		return
+	}
+	at := time.Now().Add(delay)
+	if at.Before(f.last) {
+		// keep the messages in order, as the streams would
+		at = f.last
+	}
+	f.last = at
+	f.mu.Unlock()
+
+	select {
+	case f.msgc <- delayedMessage{m: m, at: at}:
+	default:
+		// the delayed messages overflow; drop it, as a congested link would
+	}
+}
+
+func (f *faultInjector) run() {
+	defer close(f.donec)
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+	for {
+		select {
+		case dm := <-f.msgc:
+			timer.Reset(time.Until(dm.at))
+			select {
+			case <-timer.C:
+			case <-f.stopc:
+				return
+			}
+			f.write(dm.m)
+		case <-f.stopc:
+			return
+		}
+	}
+}
+
+func (f *faultInjector) stop() {
+	close(f.stopc)
+	<-f.donec
+}
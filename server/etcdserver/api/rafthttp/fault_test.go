@@ -0,0 +1,135 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rafthttp
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/raft/v3/raftpb"
+)
+
+func TestParsePeerFaults(t *testing.T) {
+	tests := []struct {
+		faults []string
+
+		want    map[string]PeerFault
+		wantErr bool
+	}{
+		{
+			faults: []string{"http://127.0.0.1:2380=100ms/20ms/0.1", "https://10.0.0.2:2380/=/10ms/", "http://[::1]:2380=//1"},
+			want: map[string]PeerFault{
+				"http://127.0.0.1:2380": {Latency: 100 * time.Millisecond, Jitter: 20 * time.Millisecond, DropRate: 0.1},
+				"https://10.0.0.2:2380": {Jitter: 10 * time.Millisecond},
+				"http://[::1]:2380":     {DropRate: 1},
+			},
+		},
+		{faults: []string{"http://127.0.0.1:2380"}, wantErr: true},
+		{faults: []string{"127.0.0.1:2380=1s"}, wantErr: true},
+		{faults: []string{"http://127.0.0.1:2380="}, wantErr: true},
+		{faults: []string{"http://127.0.0.1:2380=-1s"}, wantErr: true},
+		{faults: []string{"http://127.0.0.1:2380=/x"}, wantErr: true},
+		{faults: []string{"http://127.0.0.1:2380=//1.5"}, wantErr: true},
+	}
+	for i, tt := range tests {
+		got, err := ParsePeerFaults(tt.faults)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("#%d: err = %v, want error %v", i, err, tt.wantErr)
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("#%d: faults = %+v, want %+v", i, got, tt.want)
+		}
+	}
+}
+
+type recordingWriter struct {
+	mu    sync.Mutex
+	msgs  []raftpb.Message
+	times []time.Time
+}
+
+func (w *recordingWriter) write(m raftpb.Message) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.msgs = append(w.msgs, m)
+	w.times = append(w.times, time.Now())
+}
+
+func (w *recordingWriter) indexes() []uint64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var idxs []uint64
+	for _, m := range w.msgs {
+		idxs = append(idxs, m.Index)
+	}
+	return idxs
+}
+
+// TestFaultInjectorDrop tests that the messages are dropped at the drop
+// rate, and that the same seed drops the same messages.
+func TestFaultInjectorDrop(t *testing.T) {
+	run := func(seed int64) []uint64 {
+		w := &recordingWriter{}
+		f := startFaultInjector(zaptest.NewLogger(t), 1, PeerFault{DropRate: 0.5}, seed, w.write)
+		defer f.stop()
+		for i := 1; i <= 1000; i++ {
+			f.send(raftpb.Message{Type: raftpb.MsgApp, Index: uint64(i)})
+		}
+		return w.indexes()
+	}
+
+	idxs := run(1)
+	if n := len(idxs); n < 400 || n > 600 {
+		t.Errorf("delivered %d messages out of 1000, want about 500", n)
+	}
+	if again := run(1); !reflect.DeepEqual(idxs, again) {
+		t.Errorf("delivered messages differ with the same seed")
+	}
+	if other := run(2); reflect.DeepEqual(idxs, other) {
+		t.Errorf("delivered messages are the same with another seed")
+	}
+}
+
+// TestFaultInjectorDelay tests that the messages are delivered in order,
+// no sooner than the latency minus the jitter.
+func TestFaultInjectorDelay(t *testing.T) {
+	w := &recordingWriter{}
+	fault := PeerFault{Latency: 50 * time.Millisecond, Jitter: 20 * time.Millisecond}
+	f := startFaultInjector(zaptest.NewLogger(t), 1, fault, 1, w.write)
+	defer f.stop()
+
+	start := time.Now()
+	want := []uint64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	for _, i := range want {
+		f.send(raftpb.Message{Type: raftpb.MsgApp, Index: i})
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(w.indexes()) < len(want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("delivered %d messages, want %d", len(w.indexes()), len(want))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := w.indexes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("delivered messages = %v, want %v", got, want)
+	}
+	if d := w.times[0].Sub(start); d < fault.Latency-fault.Jitter {
+		t.Errorf("first message delivered after %v, want at least %v", d, fault.Latency-fault.Jitter)
+	}
+}
@@ -85,6 +85,54 @@ func TestSendMessage(t *testing.T) {
 	}
 }
 
+// TestSendMessageWithPeerFault tests that the messages sent to a faulty
+// peer are delayed by the injected latency.
+func TestSendMessageWithPeerFault(t *testing.T) {
+	recvc := make(chan raftpb.Message, 1)
+	tr2 := &Transport{
+		ID:          types.ID(2),
+		ClusterID:   types.ID(1),
+		Raft:        &fakeRaft{recvc: recvc},
+		ServerStats: newServerStats(),
+		LeaderStats: stats.NewLeaderStats(zaptest.NewLogger(t), "2"),
+	}
+	tr2.Start()
+	srv2 := httptest.NewServer(tr2.Handler())
+	defer srv2.Close()
+
+	latency := 200 * time.Millisecond
+	tr := &Transport{
+		ID:          types.ID(1),
+		ClusterID:   types.ID(1),
+		Raft:        &fakeRaft{},
+		ServerStats: newServerStats(),
+		LeaderStats: stats.NewLeaderStats(zaptest.NewLogger(t), "1"),
+		PeerFaults:  map[string]PeerFault{srv2.URL: {Latency: latency}},
+	}
+	tr.Start()
+	srv := httptest.NewServer(tr.Handler())
+	defer srv.Close()
+
+	tr.AddPeer(types.ID(2), []string{srv2.URL})
+	defer tr.Stop()
+	tr2.AddPeer(types.ID(1), []string{srv.URL})
+	defer tr2.Stop()
+	if !waitStreamWorking(tr.Get(types.ID(2)).(*peer)) {
+		t.Fatalf("stream from 1 to 2 is not in work as expected")
+	}
+
+	m := raftpb.Message{Type: raftpb.MsgHeartbeat, From: 1, To: 2, Term: 1, Commit: 3}
+	start := time.Now()
+	tr.Send([]raftpb.Message{m})
+	msg := <-recvc
+	if d := time.Since(start); d < latency {
+		t.Errorf("message received after %v, want at least %v", d, latency)
+	}
+	if !reflect.DeepEqual(msg, m) {
+		t.Errorf("msg = %+v, want %+v", msg, m)
+	}
+}
+
 // TestSendMessageWhenStreamIsBroken tests that message can be sent to the
 // remote in a limited time when all underlying connections are broken.
 func TestSendMessageWhenStreamIsBroken(t *testing.T) {
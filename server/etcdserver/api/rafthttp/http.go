@@ -16,6 +16,7 @@ package rafthttp
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
@@ -166,6 +167,8 @@ type snapshotHandler struct {
 	tr          Transporter
 	r           Raft
 	snapshotter *snap.Snapshotter
+	// checkKeyring, if set, rejects the snapshots the member cannot decrypt.
+	checkKeyring func(keyring []byte) error
 
 	localID types.ID
 	cid     types.ID
@@ -173,12 +176,13 @@ type snapshotHandler struct {
 
 func newSnapshotHandler(t *Transport, r Raft, snapshotter *snap.Snapshotter, cid types.ID) http.Handler {
 	h := &snapshotHandler{
-		lg:          t.Logger,
-		tr:          t,
-		r:           r,
-		snapshotter: snapshotter,
-		localID:     t.ID,
-		cid:         cid,
+		lg:           t.Logger,
+		tr:           t,
+		r:            r,
+		snapshotter:  snapshotter,
+		checkKeyring: t.CheckEncryptionKeyring,
+		localID:      t.ID,
+		cid:          cid,
 	}
 	if h.lg == nil {
 		h.lg = zap.NewNop()
@@ -188,6 +192,20 @@ func newSnapshotHandler(t *Transport, r Raft, snapshotter *snap.Snapshotter, cid
 
 const unknownSnapshotSender = "UNKNOWN_SNAPSHOT_SENDER"
 
+// checkEncryptionKeyring returns an error if the snapshot is encrypted with
+// a keyring the member cannot unwrap.
+func (h *snapshotHandler) checkEncryptionKeyring(header http.Header) error {
+	v := header.Get(encryptionKeyringHeader)
+	if v == "" || h.checkKeyring == nil {
+		return nil
+	}
+	keyring, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return fmt.Errorf("invalid encryption keyring: %w", err)
+	}
+	return h.checkKeyring(keyring)
+}
+
 // ServeHTTP serves HTTP request to receive and process snapshot message.
 //
 // If request sender dies without closing underlying TCP connection,
@@ -215,6 +233,18 @@ func (h *snapshotHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := h.checkEncryptionKeyring(r.Header); err != nil {
+		h.lg.Warn(
+			"rejected database snapshot the member cannot decrypt",
+			zap.String("local-member-id", h.localID.String()),
+			zap.String("remote-snapshot-sender-id", r.Header.Get("X-Server-From")),
+			zap.Error(err),
+		)
+		http.Error(w, err.Error(), http.StatusPreconditionFailed)
+		snapshotReceiveFailures.WithLabelValues(unknownSnapshotSender).Inc()
+		return
+	}
+
 	addRemoteFromRequest(h.tr, r)
 
 	dec := &messageDecoder{r: r.Body}
@@ -362,6 +362,7 @@ type fakePeer struct {
 	peerURLs types.URLs
 	connc    chan *outgoingConn
 	paused   bool
+	resets   int
 }
 
 func newFakePeer() *fakePeer {
@@ -387,6 +388,8 @@ func (pr *fakePeer) sendSnap(m snap.Message) {
 }
 
 func (pr *fakePeer) update(urls types.URLs)                { pr.peerURLs = urls }
+func (pr *fakePeer) urls() types.URLs                      { return pr.peerURLs }
+func (pr *fakePeer) resetConnections()                     { pr.resets++ }
 func (pr *fakePeer) attachOutgoingConn(conn *outgoingConn) { pr.connc <- conn }
 func (pr *fakePeer) activeSince() time.Time                { return time.Time{} }
 func (pr *fakePeer) stop()                                 {}
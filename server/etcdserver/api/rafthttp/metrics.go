@@ -37,6 +37,16 @@ var (
 		[]string{"Local", "Remote"},
 	)
 
+	peerAddressChanges = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "etcd",
+			Subsystem: "network",
+			Name:      "peer_address_changes_total",
+			Help:      "The total number of times the addresses of the peer host names changed.",
+		},
+		[]string{"To"},
+	)
+
 	sentBytes = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: "etcd",
@@ -183,6 +193,7 @@ var (
 func init() {
 	prometheus.MustRegister(activePeers)
 	prometheus.MustRegister(disconnectedPeers)
+	prometheus.MustRegister(peerAddressChanges)
 	prometheus.MustRegister(sentBytes)
 	prometheus.MustRegister(receivedBytes)
 	prometheus.MustRegister(sentFailures)
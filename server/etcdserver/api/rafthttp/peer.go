@@ -74,6 +74,13 @@ type Peer interface {
 	// update updates the urls of remote peer.
 	update(urls types.URLs)
 
+	// urls returns the urls of remote peer.
+	urls() types.URLs
+
+	// resetConnections closes the stream connections with the remote peer,
+	// so that they are established again.
+	resetConnections()
+
 	// attachOutgoingConn attaches the outgoing connection to the peer for
 	// stream usage. After the call, the ownership of the outgoing
 	// connection hands over to the peer. The peer will close the connection
@@ -121,6 +128,9 @@ type peer struct {
 	recvc chan raftpb.Message
 	propc chan raftpb.Message
 
+	// fault, if not nil, drops and delays the messages sent to the peer.
+	fault *faultInjector
+
 	mu     sync.Mutex
 	paused bool
 
@@ -169,6 +179,10 @@ func startPeer(t *Transport, urls types.URLs, peerID types.ID, fs *stats.Followe
 		stopc:          make(chan struct{}),
 	}
 
+	if f, ok := t.peerFault(urls); ok {
+		p.fault = startFaultInjector(t.Logger, peerID, f, t.PeerFaultSeed, p.write)
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	p.cancel = cancel
 	go func() {
@@ -241,7 +255,14 @@ func (p *peer) send(m raftpb.Message) {
 	if paused {
 		return
 	}
+	if p.fault != nil {
+		p.fault.send(m)
+		return
+	}
+	p.write(m)
+}
 
+func (p *peer) write(m raftpb.Message) {
 	writec, name := p.pick(m)
 	select {
 	case writec <- m:
@@ -273,6 +294,15 @@ func (p *peer) update(urls types.URLs) {
 	p.picker.update(urls)
 }
 
+func (p *peer) urls() types.URLs { return p.picker.all() }
+
+func (p *peer) resetConnections() {
+	p.msgAppV2Writer.close()
+	p.writer.close()
+	p.msgAppV2Reader.reset()
+	p.msgAppReader.reset()
+}
+
 func (p *peer) attachOutgoingConn(conn *outgoingConn) {
 	var ok bool
 	switch conn.t {
@@ -322,6 +352,9 @@ func (p *peer) stop() {
 		}
 	}()
 
+	if p.fault != nil {
+		p.fault.stop()
+	}
 	close(p.stopc)
 	p.cancel()
 	p.msgAppV2Writer.stop()
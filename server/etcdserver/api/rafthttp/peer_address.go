@@ -0,0 +1,146 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rafthttp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/client/pkg/v3/types"
+)
+
+const (
+	// peerUnreachableRefreshInterval is the interval at which the host names
+	// of the inactive peers are re-resolved, unless PeerAddressRefreshInterval
+	// is shorter.
+	peerUnreachableRefreshInterval = 5 * time.Second
+	// peerAddressLookupTimeout is the maximum duration of the resolution of
+	// a host name.
+	peerAddressLookupTimeout = 5 * time.Second
+)
+
+// refreshPeerAddresses re-resolves the host names of the peer URLs every
+// PeerAddressRefreshInterval, and more often those of the inactive peers. The
+// connections with the peers whose addresses changed are reset, so that they
+// are dialed again to the new addresses rather than waiting for the
+// connections to the old ones to time out.
+func (t *Transport) refreshPeerAddresses() {
+	defer close(t.donec)
+
+	addrs := make(map[string][]string)
+	t.resolvePeerAddresses(addrs, true)
+	ticker := time.NewTicker(min(t.PeerAddressRefreshInterval, peerUnreachableRefreshInterval))
+	defer ticker.Stop()
+	last := time.Now()
+	for {
+		select {
+		case <-ticker.C:
+		case <-t.stopc:
+			return
+		}
+		all := time.Since(last) >= t.PeerAddressRefreshInterval
+		if all {
+			last = time.Now()
+		}
+		t.resolvePeerAddresses(addrs, all)
+	}
+}
+
+// resolvePeerAddresses resolves the host names of the URLs of all the peers,
+// or only of the inactive ones, and resets the connections of the peers whose
+// addresses differ from the ones recorded in addrs.
+func (t *Transport) resolvePeerAddresses(addrs map[string][]string, all bool) {
+	t.mu.RLock()
+	peers := make(map[types.ID]Peer, len(t.peers))
+	for id, p := range t.peers {
+		if all || p.activeSince().IsZero() {
+			peers[id] = p
+		}
+	}
+	t.mu.RUnlock()
+
+	resolved := make(map[string][]string)
+	var changed []Peer
+	for id, p := range peers {
+		peerChanged := false
+		for _, u := range p.urls() {
+			host := u.Hostname()
+			if u.Scheme == "unix" || u.Scheme == "unixs" || net.ParseIP(host) != nil {
+				continue
+			}
+			key := id.String() + "/" + host
+			prev, ok := addrs[key]
+			ctx, cancel := context.WithTimeout(context.Background(), peerAddressLookupTimeout)
+			cur, err := t.lookupHost(ctx, host)
+			cancel()
+			if err != nil {
+				if ok {
+					resolved[key] = prev
+				}
+				if t.Logger != nil {
+					t.Logger.Warn(
+						"failed to resolve remote peer address",
+						zap.String("remote-peer-id", id.String()),
+						zap.String("host", host),
+						zap.Error(err),
+					)
+				}
+				continue
+			}
+			slices.Sort(cur)
+			resolved[key] = cur
+			if !ok || slices.Equal(prev, cur) {
+				continue
+			}
+			peerChanged = true
+			if t.Logger != nil {
+				t.Logger.Info(
+					"remote peer address changed; resetting connections",
+					zap.String("remote-peer-id", id.String()),
+					zap.String("host", host),
+					zap.Strings("previous-addresses", prev),
+					zap.Strings("addresses", cur),
+				)
+			}
+		}
+		if peerChanged {
+			peerAddressChanges.WithLabelValues(id.String()).Inc()
+			changed = append(changed, p)
+		}
+	}
+	if all {
+		clear(addrs)
+	}
+	for key, cur := range resolved {
+		addrs[key] = cur
+	}
+
+	if len(changed) == 0 {
+		return
+	}
+	for _, rt := range []http.RoundTripper{t.pipelineRt, t.streamRt} {
+		if tr, ok := rt.(*http.Transport); ok {
+			tr.CloseIdleConnections()
+		}
+	}
+	for _, p := range changed {
+		p.resetConnections()
+	}
+}
@@ -17,6 +17,7 @@ package rafthttp
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"errors"
 	"io"
 	"net/http"
@@ -24,6 +25,7 @@ import (
 
 	"github.com/dustin/go-humanize"
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 
 	"go.etcd.io/etcd/client/pkg/v3/types"
 	"go.etcd.io/etcd/pkg/v3/httputil"
@@ -32,6 +34,10 @@ import (
 	"go.etcd.io/raft/v3"
 )
 
+// encryptionKeyringHeader carries the keyring of the encrypted database
+// snapshots.
+const encryptionKeyringHeader = "X-Etcd-Encryption-Keyring"
+
 // timeout for reading snapshot response body
 var snapResponseReadTimeout = 5 * time.Second
 
@@ -72,9 +78,20 @@ func (s *snapshotSender) send(merged snap.Message) {
 
 	body := createSnapBody(s.tr.Logger, merged)
 	defer body.Close()
+	if s.tr.snapshotLimiter != nil {
+		// unblocks the body waiting for the limiter once the send is over.
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		body = &rateLimitedReadCloser{ctx: ctx, ReadCloser: body, l: s.tr.snapshotLimiter}
+	}
 
 	u := s.picker.pick()
 	req := createPostRequest(s.tr.Logger, u, RaftSnapshotPrefix, body, "application/octet-stream", s.tr.URLs, s.from, s.cid)
+	if s.tr.EncryptionKeyring != nil {
+		if keyring := s.tr.EncryptionKeyring(); len(keyring) > 0 {
+			req.Header.Set(encryptionKeyringHeader, base64.StdEncoding.EncodeToString(keyring))
+		}
+	}
 
 	snapshotSizeVal := uint64(merged.TotalSize)
 	snapshotSize := humanize.Bytes(snapshotSizeVal)
@@ -197,3 +214,24 @@ func createSnapBody(lg *zap.Logger, merged snap.Message) io.ReadCloser {
 		Closer: merged.ReadCloser,
 	}
 }
+
+// rateLimitedReadCloser reads at the rate of its limiter, which may be shared
+// to bound the rate of several readers.
+type rateLimitedReadCloser struct {
+	ctx context.Context
+	io.ReadCloser
+	l *rate.Limiter
+}
+
+func (r *rateLimitedReadCloser) Read(p []byte) (int, error) {
+	if r.l.Limit() != rate.Inf && len(p) > r.l.Burst() {
+		p = p[:r.l.Burst()]
+	}
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		if werr := r.l.WaitN(r.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
@@ -15,6 +15,9 @@
 package rafthttp
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -25,6 +28,7 @@ import (
 	"time"
 
 	"go.uber.org/zap/zaptest"
+	"golang.org/x/time/rate"
 
 	"go.etcd.io/etcd/client/pkg/v3/types"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/snap"
@@ -83,7 +87,9 @@ func TestSnapshotSend(t *testing.T) {
 	}
 
 	for i, tt := range tests {
-		sent, files := testSnapshotSend(t, snap.NewMessage(tt.m, tt.rc, tt.size))
+		r := &fakeRaft{}
+		tr := &Transport{pipelineRt: &http.Transport{}, ClusterID: types.ID(1), Raft: r}
+		sent, files := testSnapshotSend(t, tr, snap.NewMessage(tt.m, tt.rc, tt.size))
 		if tt.wsent != sent {
 			t.Errorf("#%d: snapshot expected %v, got %v", i, tt.wsent, sent)
 		}
@@ -93,11 +99,53 @@ func TestSnapshotSend(t *testing.T) {
 	}
 }
 
-func testSnapshotSend(t *testing.T, sm *snap.Message) (bool, []os.DirEntry) {
+func TestSnapshotSendEncryptionKeyring(t *testing.T) {
+	tests := []struct {
+		keyring []byte
+		check   error
+
+		wsent  bool
+		wfiles int
+	}{
+		// plain snapshot
+		{nil, errors.New("unexpected check"), true, 1},
+		// keyring the receiver can decrypt
+		{[]byte("keyring"), nil, true, 1},
+		// keyring the receiver cannot decrypt
+		{[]byte("keyring"), errors.New("no KEK"), false, 0},
+	}
+
+	for i, tt := range tests {
+		var got []byte
+		r := &fakeRaft{}
+		tr := &Transport{
+			pipelineRt:        &http.Transport{},
+			ClusterID:         types.ID(1),
+			Raft:              r,
+			EncryptionKeyring: func() []byte { return tt.keyring },
+			CheckEncryptionKeyring: func(keyring []byte) error {
+				got = keyring
+				return tt.check
+			},
+		}
+		m := raftpb.Message{Type: raftpb.MsgSnap, To: 1, Snapshot: &raftpb.Snapshot{}}
+		sent, files := testSnapshotSend(t, tr, snap.NewMessage(m, strReaderCloser{strings.NewReader("hello")}, 5))
+		if tt.wsent != sent {
+			t.Errorf("#%d: snapshot expected %v, got %v", i, tt.wsent, sent)
+		}
+		if tt.wfiles != len(files) {
+			t.Errorf("#%d: expected %d files, got %d files", i, tt.wfiles, len(files))
+		}
+		if !bytes.Equal(tt.keyring, got) {
+			t.Errorf("#%d: checked keyring %q, want %q", i, got, tt.keyring)
+		}
+	}
+}
+
+func testSnapshotSend(t *testing.T, tr *Transport, sm *snap.Message) (bool, []os.DirEntry) {
 	d := t.TempDir()
 
-	r := &fakeRaft{}
-	tr := &Transport{pipelineRt: &http.Transport{}, ClusterID: types.ID(1), Raft: r}
+	r := tr.Raft
 	ch := make(chan struct{}, 1)
 	h := &syncHandler{newSnapshotHandler(tr, r, snap.New(zaptest.NewLogger(t), d), types.ID(1)), ch}
 	srv := httptest.NewServer(h)
@@ -140,3 +188,36 @@ func (sh *syncHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	sh.h.ServeHTTP(w, r)
 	sh.ch <- struct{}{}
 }
+
+func TestRateLimitedReadCloser(t *testing.T) {
+	const limit = 64 * 1024
+	data := strings.Repeat("a", 2*limit)
+	r := &rateLimitedReadCloser{
+		ctx:        t.Context(),
+		ReadCloser: strReaderCloser{strings.NewReader(data)},
+		l:          rate.NewLimiter(limit, limit),
+	}
+	start := time.Now()
+	b, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != data {
+		t.Fatalf("read %d bytes, want %d", len(b), len(data))
+	}
+	// the first second of bytes is the burst.
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Fatalf("read %d bytes in %v, want about 1s", len(data), elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	r = &rateLimitedReadCloser{
+		ctx:        ctx,
+		ReadCloser: strReaderCloser{strings.NewReader(data)},
+		l:          rate.NewLimiter(limit, limit),
+	}
+	if _, err = io.ReadAll(r); err == nil {
+		t.Fatal("expected the read to fail once the context is canceled")
+	}
+}
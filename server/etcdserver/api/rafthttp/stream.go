@@ -688,6 +688,13 @@ func (cr *streamReader) close() {
 	cr.closer = nil
 }
 
+// reset closes the connection of the stream, which is then dialed again.
+func (cr *streamReader) reset() {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.close()
+}
+
 func (cr *streamReader) pause() {
 	cr.mu.Lock()
 	defer cr.mu.Unlock()
@@ -16,6 +16,7 @@ package rafthttp
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"sync"
 	"time"
@@ -118,6 +119,26 @@ type Transport struct {
 	// When an error is received from ErrorC, user should stop raft state
 	// machine and thus stop the Transport.
 	ErrorC chan error
+	// SnapshotSendRateLimit is the number of bytes per second of the
+	// snapshots sent to all the peers; 0 is no limit.
+	SnapshotSendRateLimit int64
+	// PeerAddressRefreshInterval is the interval at which the host names of
+	// the peer URLs are re-resolved, resetting the connections with the peers
+	// whose addresses changed. The host names of the inactive peers are
+	// re-resolved more often. 0 disables the re-resolution.
+	PeerAddressRefreshInterval time.Duration
+	// PeerFaults are the faults injected in the messages sent to the peers,
+	// by the scheme and the host of their URLs, for testing only. The random
+	// faults are drawn from a source seeded with PeerFaultSeed.
+	PeerFaults    map[string]PeerFault
+	PeerFaultSeed int64
+	// EncryptionKeyring, if set, returns the keyring of the encrypted backend,
+	// sent with the database snapshots so that the peers check they can
+	// decrypt them before receiving them.
+	EncryptionKeyring func() []byte
+	// CheckEncryptionKeyring, if set, returns an error if the member cannot
+	// decrypt the database snapshots of the keyring, which are then rejected.
+	CheckEncryptionKeyring func(keyring []byte) error
 
 	streamRt   http.RoundTripper // roundTripper used by streams
 	pipelineRt http.RoundTripper // roundTripper used by pipelines
@@ -128,6 +149,12 @@ type Transport struct {
 
 	pipelineProber probing.Prober
 	streamProber   probing.Prober
+
+	snapshotLimiter *rate.Limiter // limits the snapshot sends, nil until started
+
+	lookupHost func(ctx context.Context, host string) ([]string, error)
+	stopc      chan struct{} // closed to stop refreshing the peer addresses
+	donec      chan struct{} // closed once the peer addresses are no longer refreshed
 }
 
 func (t *Transport) Start() error {
@@ -151,6 +178,15 @@ func (t *Transport) Start() error {
 	if t.DialRetryFrequency == 0 {
 		t.DialRetryFrequency = rate.Every(100 * time.Millisecond)
 	}
+	t.snapshotLimiter = rate.NewLimiter(rate.Inf, 0)
+	t.SetSnapshotSendRateLimit(t.SnapshotSendRateLimit)
+	if t.PeerAddressRefreshInterval > 0 {
+		if t.lookupHost == nil {
+			t.lookupHost = net.DefaultResolver.LookupHost
+		}
+		t.stopc, t.donec = make(chan struct{}), make(chan struct{})
+		go t.refreshPeerAddresses()
+	}
 	return nil
 }
 
@@ -208,7 +244,39 @@ func (t *Transport) Send(msgs []raftpb.Message) {
 	}
 }
 
+// SetSnapshotSendRateLimit changes the number of bytes per second of the
+// snapshots sent to the peers. 0 is no limit. It must be called after Start.
+func (t *Transport) SetSnapshotSendRateLimit(limit int64) {
+	if limit <= 0 {
+		t.snapshotLimiter.SetLimit(rate.Inf)
+		return
+	}
+	// a burst of a second, so that the reads are not split too finely.
+	t.snapshotLimiter.SetBurst(int(limit))
+	t.snapshotLimiter.SetLimit(rate.Limit(limit))
+}
+
+// ResetConnections closes the connections with the peers, e.g. for the
+// renewed certificates to be used: the peers reconnect right away.
+func (t *Transport) ResetConnections() {
+	for _, rt := range []http.RoundTripper{t.pipelineRt, t.streamRt} {
+		if tr, ok := rt.(*http.Transport); ok {
+			tr.CloseIdleConnections()
+		}
+	}
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, p := range t.peers {
+		p.resetConnections()
+	}
+}
+
 func (t *Transport) Stop() {
+	if t.stopc != nil {
+		close(t.stopc)
+		<-t.donec
+		t.stopc = nil
+	}
 	t.mu.Lock()
 	defer t.mu.Unlock()
 	for _, r := range t.remotes {
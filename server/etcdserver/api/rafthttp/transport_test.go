@@ -15,8 +15,11 @@
 package rafthttp
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"reflect"
+	"slices"
 	"testing"
 	"time"
 
@@ -204,3 +207,61 @@ func TestTransportErrorc(t *testing.T) {
 		t.Fatalf("cannot receive error from errorc")
 	}
 }
+
+// TestTransportResolvePeerAddresses tests that transport resets the
+// connections with the peers whose host names resolve to new addresses.
+func TestTransportResolvePeerAddresses(t *testing.T) {
+	peer1 := newFakePeer()
+	peer1.peerURLs = types.MustNewURLs([]string{"http://etcd-1:2380"})
+	peer2 := newFakePeer()
+	peer2.peerURLs = types.MustNewURLs([]string{"http://etcd-2:2380", "http://10.0.0.2:2380"})
+	peer3 := newFakePeer()
+	peer3.peerURLs = types.MustNewURLs([]string{"http://10.0.0.3:2380"})
+	hosts := map[string][]string{
+		"etcd-1": {"10.0.0.1"},
+		"etcd-2": {"10.0.0.2", "10.0.1.2"},
+	}
+	var lookups []string
+	tr := &Transport{
+		peers: map[types.ID]Peer{types.ID(1): peer1, types.ID(2): peer2, types.ID(3): peer3},
+		lookupHost: func(ctx context.Context, host string) ([]string, error) {
+			lookups = append(lookups, host)
+			if addrs, ok := hosts[host]; ok {
+				return append([]string(nil), addrs...), nil
+			}
+			return nil, errors.New("no such host")
+		},
+	}
+	addrs := make(map[string][]string)
+
+	tr.resolvePeerAddresses(addrs, true)
+	if peer1.resets != 0 || peer2.resets != 0 {
+		t.Fatalf("resets = %d, %d, want 0, 0 on the first resolution", peer1.resets, peer2.resets)
+	}
+	slices.Sort(lookups)
+	if want := []string{"etcd-1", "etcd-2"}; !reflect.DeepEqual(lookups, want) {
+		t.Fatalf("lookups = %v, want %v", lookups, want)
+	}
+
+	// the order of the addresses does not matter.
+	hosts["etcd-2"] = []string{"10.0.1.2", "10.0.0.2"}
+	tr.resolvePeerAddresses(addrs, true)
+	if peer1.resets != 0 || peer2.resets != 0 {
+		t.Fatalf("resets = %d, %d, want 0, 0 for unchanged addresses", peer1.resets, peer2.resets)
+	}
+
+	hosts["etcd-1"] = []string{"10.0.2.1"}
+	tr.resolvePeerAddresses(addrs, true)
+	if peer1.resets != 1 || peer2.resets != 0 {
+		t.Fatalf("resets = %d, %d, want 1, 0 after etcd-1 moved", peer1.resets, peer2.resets)
+	}
+
+	// a failed resolution keeps the known addresses.
+	delete(hosts, "etcd-1")
+	tr.resolvePeerAddresses(addrs, true)
+	hosts["etcd-1"] = []string{"10.0.3.1"}
+	tr.resolvePeerAddresses(addrs, false)
+	if peer1.resets != 2 {
+		t.Fatalf("resets = %d, want 2 after etcd-1 moved again", peer1.resets)
+	}
+}
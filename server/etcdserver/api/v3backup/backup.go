@@ -0,0 +1,232 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+	"go.etcd.io/raft/v3"
+)
+
+// maxSegmentBytes is the maximum size of the entries of a segment.
+const maxSegmentBytes = 16 * 1024 * 1024
+
+// Server is the etcd server being backed up.
+type Server interface {
+	// AppliedIndex returns the index of the last raft entry applied by the
+	// server. Only the applied entries are committed and get shipped.
+	AppliedIndex() uint64
+	Backend() backend.Backend
+}
+
+// Config is the configuration of a Backup.
+type Config struct {
+	// SnapshotInterval is the interval between two base snapshots.
+	SnapshotInterval time.Duration
+	// Retention is the number of base snapshots kept, with the segments
+	// following them. 0 keeps all of them.
+	Retention int
+	// TempDir is the directory of the base snapshots before they are
+	// shipped.
+	TempDir string
+}
+
+// Backup ships a base snapshot of a server to a sink, then the raft entries
+// applied after it, until the next base snapshot. Only one member is
+// expected to ship at a time, the shipping position is read from the sink
+// so that another member can take over.
+type Backup struct {
+	lg      *zap.Logger
+	sink    Sink
+	srv     Server
+	storage raft.Storage
+	cfg     Config
+	now     func() time.Time
+
+	// next is the index of the next entry to ship, 0 until it is read from
+	// the sink.
+	next uint64
+	// lastBase is the time of the latest base snapshot.
+	lastBase time.Time
+}
+
+// New returns a Backup of srv to sink, shipping the raft entries of storage.
+func New(lg *zap.Logger, sink Sink, srv Server, storage raft.Storage, cfg Config) *Backup {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	return &Backup{lg: lg, sink: sink, srv: srv, storage: storage, cfg: cfg, now: time.Now}
+}
+
+// Reset drops the shipping position, so that the next Ship reads it from the
+// sink. It is called when the member stops shipping, since another member
+// may advance the backup in the meantime.
+func (b *Backup) Reset() {
+	b.next = 0
+}
+
+// Ship ships the entries applied since the last call in a new segment. A
+// base snapshot is shipped first if none was shipped for SnapshotInterval,
+// or if the entries to ship were already compacted from the raft storage.
+func (b *Backup) Ship(ctx context.Context) error {
+	if b.next == 0 {
+		c, err := ListCatalog(ctx, b.sink)
+		if err != nil {
+			return fmt.Errorf("failed to list the backup: %w", err)
+		}
+		if len(c.Bases) > 0 {
+			b.next = c.lastIndex() + 1
+			b.lastBase = c.Bases[len(c.Bases)-1].Time
+		}
+	}
+	if b.next == 0 || b.now().Sub(b.lastBase) >= b.cfg.SnapshotInterval {
+		if err := b.shipBase(ctx); err != nil {
+			return err
+		}
+	}
+
+	applied := b.srv.AppliedIndex()
+	rebased := false
+	for b.next <= applied {
+		ents, err := b.storage.Entries(b.next, applied+1, maxSegmentBytes)
+		if errors.Is(err, raft.ErrCompacted) && !rebased {
+			b.lg.Warn("backup fell behind the raft log; shipping a new base snapshot", zap.Uint64("next-index", b.next))
+			if err = b.shipBase(ctx); err != nil {
+				return err
+			}
+			rebased = true
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read the raft entries from index %d: %w", b.next, err)
+		}
+		var buf bytes.Buffer
+		if err = writeSegment(&buf, ents); err != nil {
+			return err
+		}
+		first, last := ents[0].Index, ents[len(ents)-1].Index
+		if err = b.sink.Put(ctx, segmentName(first, last, b.now()), &buf); err != nil {
+			return fmt.Errorf("failed to ship the raft entries from index %d to %d: %w", first, last, err)
+		}
+		b.next = last + 1
+	}
+	return nil
+}
+
+// shipBase ships a snapshot of the backend, then removes the bases and the
+// segments beyond the retention.
+func (b *Backup) shipBase(ctx context.Context) error {
+	f, err := os.CreateTemp(b.cfg.TempDir, "backup-*.db")
+	if err != nil {
+		return err
+	}
+	path := f.Name()
+	defer os.Remove(path)
+	snap := b.srv.Backend().Snapshot()
+	_, err = snap.WriteTo(f)
+	if cerr := snap.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to snapshot the backend: %w", err)
+	}
+
+	// the consistent index is read from the snapshot itself, since the
+	// server keeps applying entries while it is taken.
+	be := backend.NewDefaultBackend(b.lg, path)
+	index, _ := schema.ReadConsistentIndex(be.ReadTx())
+	if err = be.Close(); err != nil {
+		return err
+	}
+	if err = appendHash(path); err != nil {
+		return err
+	}
+
+	f, err = os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	t := b.now()
+	name := baseName(index, t)
+	if err = b.sink.Put(ctx, name, f); err != nil {
+		return fmt.Errorf("failed to ship the base snapshot at index %d: %w", index, err)
+	}
+	b.lg.Info("shipped backup base snapshot", zap.String("name", name), zap.Uint64("index", index))
+	b.lastBase = t
+	if b.next <= index {
+		b.next = index + 1
+	}
+	return b.applyRetention(ctx)
+}
+
+func (b *Backup) applyRetention(ctx context.Context) error {
+	if b.cfg.Retention <= 0 {
+		return nil
+	}
+	c, err := ListCatalog(ctx, b.sink)
+	if err != nil {
+		return fmt.Errorf("failed to list the backup: %w", err)
+	}
+	if len(c.Bases) <= b.cfg.Retention {
+		return nil
+	}
+	oldest := c.Bases[len(c.Bases)-b.cfg.Retention]
+	for _, base := range c.Bases[:len(c.Bases)-b.cfg.Retention] {
+		if err = b.sink.Delete(ctx, base.Name); err != nil {
+			return err
+		}
+	}
+	for _, seg := range c.Segments {
+		if seg.Last > oldest.Index {
+			continue
+		}
+		if err = b.sink.Delete(ctx, seg.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendHash appends the sha256 integrity hash of the file at path to it.
+func appendHash(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return err
+	}
+	if _, err = f.Write(h.Sum(nil)); err != nil {
+		return err
+	}
+	return f.Sync()
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3backup
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/server/v3/storage/backend"
+	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+	"go.etcd.io/raft/v3"
+	"go.etcd.io/raft/v3/raftpb"
+)
+
+type fakeServer struct {
+	be      backend.Backend
+	storage *raft.MemoryStorage
+}
+
+func (s *fakeServer) AppliedIndex() uint64 {
+	index, _ := schema.ReadConsistentIndex(s.be.ReadTx())
+	return index
+}
+
+func (s *fakeServer) Backend() backend.Backend { return s.be }
+
+// apply appends the entries up to index to the raft storage and applies them
+// to the backend.
+func (s *fakeServer) apply(t *testing.T, index uint64) {
+	last, err := s.storage.LastIndex()
+	require.NoError(t, err)
+	var ents []raftpb.Entry
+	for i := last + 1; i <= index; i++ {
+		ents = append(ents, raftpb.Entry{Index: i, Term: 1, Data: []byte{byte(i)}})
+	}
+	require.NoError(t, s.storage.Append(ents))
+	tx := s.be.BatchTx()
+	tx.LockOutsideApply()
+	schema.UnsafeUpdateConsistentIndex(tx, index, 1)
+	tx.Unlock()
+	s.be.ForceCommit()
+}
+
+func newTestBackup(t *testing.T, retention int) (*Backup, *fakeServer, Sink, *time.Time) {
+	be, _ := betesting.NewDefaultTmpBackend(t)
+	t.Cleanup(func() { betesting.Close(t, be) })
+	tx := be.BatchTx()
+	tx.LockOutsideApply()
+	schema.UnsafeCreateMetaBucket(tx)
+	tx.Unlock()
+	srv := &fakeServer{be: be, storage: raft.NewMemoryStorage()}
+
+	sink, err := NewSink(zaptest.NewLogger(t), "file://"+t.TempDir())
+	require.NoError(t, err)
+	b := New(zaptest.NewLogger(t), sink, srv, srv.storage, Config{SnapshotInterval: time.Hour, Retention: retention, TempDir: t.TempDir()})
+	now := time.Unix(1000, 0)
+	b.now = func() time.Time { return now }
+	return b, srv, sink, &now
+}
+
+func TestBackupShip(t *testing.T) {
+	b, srv, sink, now := newTestBackup(t, 0)
+	srv.apply(t, 3)
+	require.NoError(t, b.Ship(t.Context()))
+	srv.apply(t, 5)
+	*now = now.Add(time.Minute)
+	require.NoError(t, b.Ship(t.Context()))
+	*now = now.Add(time.Minute)
+	require.NoError(t, b.Ship(t.Context()))
+
+	c, err := ListCatalog(t.Context(), sink)
+	require.NoError(t, err)
+	require.Len(t, c.Bases, 1)
+	assert.Equal(t, uint64(3), c.Bases[0].Index)
+	require.Len(t, c.Segments, 1)
+	assert.Equal(t, uint64(4), c.Segments[0].First)
+	assert.Equal(t, uint64(5), c.Segments[0].Last)
+	ents, err := ReadSegment(t.Context(), sink, c.Segments[0])
+	require.NoError(t, err)
+	require.Len(t, ents, 2)
+	assert.Equal(t, []byte{5}, ents[1].Data)
+
+	dbPath := filepath.Join(t.TempDir(), "db")
+	require.NoError(t, ReadBase(t.Context(), sink, c.Bases[0], dbPath))
+	be := backend.NewDefaultBackend(zaptest.NewLogger(t), dbPath)
+	index, _ := schema.ReadConsistentIndex(be.ReadTx())
+	require.NoError(t, be.Close())
+	assert.Equal(t, uint64(3), index)
+
+	// another member takes over from the position in the sink.
+	b2 := New(zaptest.NewLogger(t), sink, srv, srv.storage, b.cfg)
+	b2.now = b.now
+	srv.apply(t, 6)
+	require.NoError(t, b2.Ship(t.Context()))
+	c, err = ListCatalog(t.Context(), sink)
+	require.NoError(t, err)
+	require.Len(t, c.Bases, 1)
+	assert.Len(t, c.SegmentsAfter(c.Bases[0], time.Time{}), 2)
+	assert.Len(t, c.SegmentsAfter(c.Bases[0], time.Unix(1000, 0).Add(time.Minute)), 1)
+}
+
+func TestBackupShipCompacted(t *testing.T) {
+	b, srv, sink, now := newTestBackup(t, 1)
+	srv.apply(t, 3)
+	require.NoError(t, b.Ship(t.Context()))
+	srv.apply(t, 5)
+	require.NoError(t, b.Ship(t.Context()))
+
+	// the entries after the last shipment are compacted away.
+	srv.apply(t, 8)
+	require.NoError(t, srv.storage.Compact(7))
+	*now = now.Add(time.Minute)
+	require.NoError(t, b.Ship(t.Context()))
+	srv.apply(t, 9)
+	require.NoError(t, b.Ship(t.Context()))
+
+	c, err := ListCatalog(t.Context(), sink)
+	require.NoError(t, err)
+	require.Len(t, c.Bases, 1)
+	assert.Equal(t, uint64(8), c.Bases[0].Index)
+	require.Len(t, c.Segments, 1)
+	assert.Equal(t, uint64(9), c.Segments[0].First)
+}
+
+func TestSegmentsAfterGap(t *testing.T) {
+	c := Catalog{Segments: []Segment{
+		{First: 4, Last: 6},
+		{First: 5, Last: 8},
+		{First: 11, Last: 12},
+	}}
+	segs := c.SegmentsAfter(Base{Index: 3}, time.Time{})
+	require.Len(t, segs, 2)
+	assert.Equal(t, uint64(8), segs[1].Last)
+	assert.Empty(t, c.SegmentsAfter(Base{Index: 1}, time.Time{}))
+}
+
+func TestNewSinkUnsupportedScheme(t *testing.T) {
+	_, err := NewSink(nil, "s3://bucket/etcd")
+	require.ErrorContains(t, err, `unsupported backup URL scheme "s3"`)
+	_, err = NewSink(nil, "file://relative/path")
+	require.ErrorContains(t, err, "absolute path")
+}
@@ -0,0 +1,279 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3backup
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	"go.etcd.io/raft/v3/raftpb"
+)
+
+const (
+	basePrefix    = "base/"
+	segmentPrefix = "segments/"
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// Base is a snapshot of the backend, with its sha256 integrity hash appended
+// like "etcdctl snapshot save" does.
+type Base struct {
+	Name string
+	// Index is the consistent index of the snapshot: the index of the last
+	// raft entry applied to it.
+	Index uint64
+	Time  time.Time
+}
+
+// Segment holds the raft entries from First to Last, shipped at Time.
+type Segment struct {
+	Name  string
+	First uint64
+	Last  uint64
+	Time  time.Time
+}
+
+func baseName(index uint64, t time.Time) string {
+	return fmt.Sprintf("%s%016x-%016x.db", basePrefix, index, t.UnixNano())
+}
+
+func segmentName(first, last uint64, t time.Time) string {
+	return fmt.Sprintf("%s%016x-%016x-%016x.seg", segmentPrefix, first, last, t.UnixNano())
+}
+
+// parseName parses the n dash separated hexadecimal fields of the object
+// name between prefix and suffix.
+func parseName(name, prefix, suffix string, n int) ([]uint64, bool) {
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) {
+		return nil, false
+	}
+	parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(name, prefix), suffix), "-")
+	if len(parts) != n {
+		return nil, false
+	}
+	fields := make([]uint64, n)
+	for i, part := range parts {
+		v, err := strconv.ParseUint(part, 16, 64)
+		if err != nil {
+			return nil, false
+		}
+		fields[i] = v
+	}
+	return fields, true
+}
+
+// Catalog lists the bases and the segments of a backup, in index order.
+type Catalog struct {
+	Bases    []Base
+	Segments []Segment
+}
+
+// ListCatalog lists the bases and the segments stored in s. The objects with
+// unknown names are ignored.
+func ListCatalog(ctx context.Context, s Sink) (c Catalog, err error) {
+	names, err := s.List(ctx, basePrefix)
+	if err != nil {
+		return c, err
+	}
+	for _, name := range names {
+		fields, ok := parseName(name, basePrefix, ".db", 2)
+		if !ok {
+			continue
+		}
+		c.Bases = append(c.Bases, Base{Name: name, Index: fields[0], Time: time.Unix(0, int64(fields[1]))})
+	}
+	names, err = s.List(ctx, segmentPrefix)
+	if err != nil {
+		return c, err
+	}
+	for _, name := range names {
+		fields, ok := parseName(name, segmentPrefix, ".seg", 3)
+		if !ok || fields[0] > fields[1] {
+			continue
+		}
+		c.Segments = append(c.Segments, Segment{Name: name, First: fields[0], Last: fields[1], Time: time.Unix(0, int64(fields[2]))})
+	}
+	sort.Slice(c.Bases, func(i, j int) bool { return c.Bases[i].Index < c.Bases[j].Index })
+	sort.Slice(c.Segments, func(i, j int) bool {
+		if c.Segments[i].First != c.Segments[j].First {
+			return c.Segments[i].First < c.Segments[j].First
+		}
+		return c.Segments[i].Last < c.Segments[j].Last
+	})
+	return c, nil
+}
+
+// lastIndex returns the index of the last entry covered by the catalog, 0 if
+// it is empty.
+func (c Catalog) lastIndex() uint64 {
+	var last uint64
+	if len(c.Bases) > 0 {
+		last = c.Bases[len(c.Bases)-1].Index
+	}
+	for _, seg := range c.Segments {
+		last = max(last, seg.Last)
+	}
+	return last
+}
+
+// BasesBefore returns the bases taken before t, the newest first. All the
+// bases are returned if t is zero.
+func (c Catalog) BasesBefore(t time.Time) []Base {
+	var bases []Base
+	for i := len(c.Bases) - 1; i >= 0; i-- {
+		if t.IsZero() || !c.Bases[i].Time.After(t) {
+			bases = append(bases, c.Bases[i])
+		}
+	}
+	return bases
+}
+
+// SegmentsAfter returns the segments continuing b without gap, shipped before
+// t. Segments shipped concurrently by two members may overlap, the entries
+// of overlapping segments are the same committed entries. All the segments
+// are considered if t is zero.
+func (c Catalog) SegmentsAfter(b Base, t time.Time) []Segment {
+	var segs []Segment
+	next := b.Index + 1
+	for _, seg := range c.Segments {
+		if !t.IsZero() && seg.Time.After(t) {
+			continue
+		}
+		if seg.Last < next {
+			continue
+		}
+		if seg.First > next {
+			break
+		}
+		segs = append(segs, seg)
+		next = seg.Last + 1
+	}
+	return segs
+}
+
+// writeSegment encodes ents to w. Each entry is preceded by its length and
+// its crc32c checksum.
+func writeSegment(w io.Writer, ents []raftpb.Entry) error {
+	bw := bufio.NewWriter(w)
+	var hdr [8]byte
+	for i := range ents {
+		data, err := ents[i].Marshal()
+		if err != nil {
+			return err
+		}
+		binary.LittleEndian.PutUint32(hdr[:4], uint32(len(data)))
+		binary.LittleEndian.PutUint32(hdr[4:], crc32.Checksum(data, crcTable))
+		if _, err = bw.Write(hdr[:]); err != nil {
+			return err
+		}
+		if _, err = bw.Write(data); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ReadSegment reads the entries of seg from s and checks them against their
+// checksums and the indexes of the segment.
+func ReadSegment(ctx context.Context, s Sink, seg Segment) ([]raftpb.Entry, error) {
+	rc, err := s.Get(ctx, seg.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	br := bufio.NewReader(rc)
+	var ents []raftpb.Entry
+	var hdr [8]byte
+	for {
+		if _, err = io.ReadFull(br, hdr[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("segment %s: %w", seg.Name, err)
+		}
+		data := make([]byte, binary.LittleEndian.Uint32(hdr[:4]))
+		if _, err = io.ReadFull(br, data); err != nil {
+			return nil, fmt.Errorf("segment %s: %w", seg.Name, err)
+		}
+		if crc32.Checksum(data, crcTable) != binary.LittleEndian.Uint32(hdr[4:]) {
+			return nil, fmt.Errorf("segment %s: entry %d: crc mismatch", seg.Name, len(ents))
+		}
+		var e raftpb.Entry
+		if err = e.Unmarshal(data); err != nil {
+			return nil, fmt.Errorf("segment %s: %w", seg.Name, err)
+		}
+		if e.Index != seg.First+uint64(len(ents)) {
+			return nil, fmt.Errorf("segment %s: unexpected entry index %d", seg.Name, e.Index)
+		}
+		ents = append(ents, e)
+	}
+	if len(ents) == 0 || ents[len(ents)-1].Index != seg.Last {
+		return nil, fmt.Errorf("segment %s: entries do not reach index %d", seg.Name, seg.Last)
+	}
+	return ents, nil
+}
+
+// ReadBase writes the backend snapshot of b stored in s to path, without its
+// integrity hash, after checking the hash.
+func ReadBase(ctx context.Context, s Sink, b Base, path string) error {
+	rc, err := s.Get(ctx, b.Name)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, fileutil.PrivateFileMode)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	n, err := io.Copy(f, rc)
+	if err != nil {
+		return err
+	}
+	if n < sha256.Size {
+		return fmt.Errorf("base %s is truncated", b.Name)
+	}
+	sha := make([]byte, sha256.Size)
+	if _, err = f.ReadAt(sha, n-sha256.Size); err != nil {
+		return err
+	}
+	if err = f.Truncate(n - sha256.Size); err != nil {
+		return err
+	}
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	h := sha256.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return err
+	}
+	if string(h.Sum(nil)) != string(sha) {
+		return fmt.Errorf("base %s: sha256 mismatch", b.Name)
+	}
+	return f.Sync()
+}
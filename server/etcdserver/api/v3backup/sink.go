@@ -0,0 +1,154 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+)
+
+// Sink stores the backup objects under slash separated names.
+type Sink interface {
+	// Put stores the content of r under name, replacing any existing object.
+	Put(ctx context.Context, name string, r io.Reader) error
+	// Get returns the content of the object stored under name.
+	Get(ctx context.Context, name string) (io.ReadCloser, error)
+	// List returns the names of the objects starting with prefix.
+	List(ctx context.Context, prefix string) ([]string, error)
+	// Delete removes the object stored under name.
+	Delete(ctx context.Context, name string) error
+}
+
+// SinkFactory creates the Sink of a backup URL.
+type SinkFactory func(lg *zap.Logger, u *url.URL) (Sink, error)
+
+var (
+	sinksMu sync.RWMutex
+	sinks   = map[string]SinkFactory{
+		"file": newFileSink,
+	}
+)
+
+// RegisterSink makes the sinks created by f available to the backup URLs
+// with the given scheme, e.g. "s3" or "gs". The "file" scheme is built in.
+func RegisterSink(scheme string, f SinkFactory) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	sinks[scheme] = f
+}
+
+// NewSink returns the Sink of the backup URL rawURL.
+func NewSink(lg *zap.Logger, rawURL string) (Sink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup URL %q: %w", rawURL, err)
+	}
+	sinksMu.RLock()
+	f, ok := sinks[u.Scheme]
+	sinksMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported backup URL scheme %q", u.Scheme)
+	}
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	return f(lg, u)
+}
+
+// fileSink stores the objects as files under a directory.
+type fileSink struct {
+	lg  *zap.Logger
+	dir string
+}
+
+func newFileSink(lg *zap.Logger, u *url.URL) (Sink, error) {
+	if u.Host != "" || !filepath.IsAbs(u.Path) {
+		return nil, fmt.Errorf("file backup URL %q must be an absolute path, e.g. file:///var/backup/etcd", u.String())
+	}
+	if err := fileutil.TouchDirAll(lg, u.Path); err != nil {
+		return nil, err
+	}
+	return &fileSink{lg: lg, dir: u.Path}, nil
+}
+
+func (s *fileSink) path(name string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(name))
+}
+
+func (s *fileSink) Put(_ context.Context, name string, r io.Reader) error {
+	p := s.path(name)
+	if err := fileutil.TouchDirAll(s.lg, filepath.Dir(p)); err != nil {
+		return err
+	}
+	// write to a temporary file first so that an interrupted Put does not
+	// leave a partial object behind.
+	part := p + ".part"
+	f, err := os.OpenFile(part, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fileutil.PrivateFileMode)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(f, r); err == nil {
+		err = fileutil.Fsync(f)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(part)
+		return err
+	}
+	return os.Rename(part, p)
+}
+
+func (s *fileSink) Get(_ context.Context, name string) (io.ReadCloser, error) {
+	return os.Open(s.path(name))
+}
+
+func (s *fileSink) List(_ context.Context, prefix string) ([]string, error) {
+	var names []string
+	err := filepath.WalkDir(s.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || strings.HasSuffix(p, ".part") {
+			return err
+		}
+		rel, err := filepath.Rel(s.dir, p)
+		if err != nil {
+			return err
+		}
+		if name := filepath.ToSlash(rel); strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+		return nil
+	})
+	return names, err
+}
+
+func (s *fileSink) Delete(_ context.Context, name string) error {
+	err := os.Remove(s.path(name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,210 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3cdc
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+const (
+	// maxBatchEvents bounds the number of events of the watch responses
+	// gathered into a single Publish, a single response is never split.
+	maxBatchEvents = 1000
+	// checkpointInterval is the minimum interval between two checkpoints.
+	// The events published since the last checkpoint are published again
+	// after a failover.
+	checkpointInterval = time.Second
+
+	minRetryInterval = 100 * time.Millisecond
+	maxRetryInterval = 10 * time.Second
+)
+
+// Checkpoint stores the revision up to which the events were published.
+type Checkpoint interface {
+	// Load returns the checkpointed revision, 0 if there is none.
+	Load(ctx context.Context) (int64, error)
+	Save(ctx context.Context, rev int64) error
+}
+
+// Publisher tails the events of a key-value store and publishes them to a
+// sink. Only one member is expected to publish at a time, the publication
+// position is read from the checkpoint so that another member can take over.
+type Publisher struct {
+	lg   *zap.Logger
+	kv   mvcc.WatchableKV
+	sink Sink
+	cp   Checkpoint
+	// exclude is the prefix of the keys not published, e.g. the checkpoint
+	// keys themselves.
+	exclude []byte
+
+	// rev is the revision up to which the events were published, saved is
+	// the checkpointed one.
+	rev, saved int64
+	lastSave   time.Time
+}
+
+// NewPublisher returns a Publisher of the events of kv to sink, except the
+// events of the keys starting with exclude.
+func NewPublisher(lg *zap.Logger, kv mvcc.WatchableKV, sink Sink, cp Checkpoint, exclude []byte) *Publisher {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	return &Publisher{lg: lg, kv: kv, sink: sink, cp: cp, exclude: exclude}
+}
+
+// Run publishes the events after the checkpointed revision until ctx is
+// done. Without a checkpoint, the publication starts after the current
+// revision.
+func (p *Publisher) Run(ctx context.Context) error {
+	rev, err := p.cp.Load(ctx)
+	if err != nil {
+		return err
+	}
+	if rev == 0 {
+		rev = p.kv.Rev()
+		if err = p.cp.Save(ctx, rev); err != nil {
+			return err
+		}
+	}
+	p.rev, p.saved, p.lastSave = rev, rev, time.Now()
+	defer p.checkpoint(context.WithoutCancel(ctx), true)
+
+	ws := p.kv.NewWatchStream()
+	defer ws.Close()
+	for ctx.Err() == nil {
+		id, err := ws.Watch(clientv3.AutoWatchID, []byte{0}, []byte{}, p.rev+1, p.filter)
+		if err != nil {
+			return err
+		}
+		p.tail(ctx, ws)
+		ws.Cancel(id)
+	}
+	return nil
+}
+
+func (p *Publisher) filter(ev mvccpb.Event) bool {
+	return len(p.exclude) > 0 && bytes.HasPrefix(ev.Kv.Key, p.exclude)
+}
+
+// tail publishes the responses of the watcher of ws until ctx is done, or
+// the watcher is canceled and must be recreated.
+func (p *Publisher) tail(ctx context.Context, ws mvcc.WatchStream) {
+	ticker := time.NewTicker(checkpointInterval)
+	defer ticker.Stop()
+	for {
+		var wr mvcc.WatchResponse
+		select {
+		case wr = <-ws.Chan():
+		case <-ticker.C:
+			p.checkpoint(ctx, false)
+			continue
+		case <-ctx.Done():
+			return
+		}
+
+		events, rev, final := p.batch(ws, wr)
+		if len(events) > 0 {
+			if err := p.publish(ctx, events); err != nil {
+				return
+			}
+		}
+		p.rev = rev
+		p.checkpoint(ctx, false)
+
+		switch {
+		case final.CompactRevision != 0:
+			p.lg.Warn(
+				"events compacted before being published; resuming after the compaction",
+				zap.Int64("published-revision", p.rev),
+				zap.Int64("compact-revision", final.CompactRevision),
+			)
+			p.rev = max(p.rev, final.CompactRevision-1)
+			return
+		case final.Canceled:
+			return
+		}
+	}
+}
+
+// batch gathers the events of wr and of the responses already waiting on
+// ws, up to maxBatchEvents, and returns them with the revision they go up
+// to. It stops at a response canceling the watcher, returned as final.
+func (p *Publisher) batch(ws mvcc.WatchStream, wr mvcc.WatchResponse) (events []Event, rev int64, final mvcc.WatchResponse) {
+	rev = p.rev
+	for {
+		if wr.CompactRevision != 0 || wr.Canceled {
+			return events, rev, wr
+		}
+		for _, ev := range wr.Events {
+			events = append(events, newEvent(ev))
+		}
+		rev = max(rev, wr.Revision)
+		if len(events) >= maxBatchEvents {
+			return events, rev, final
+		}
+		select {
+		case wr = <-ws.Chan():
+		default:
+			return events, rev, final
+		}
+	}
+}
+
+// publish retries publishing the events until they are acknowledged by the
+// sink, or ctx is done.
+func (p *Publisher) publish(ctx context.Context, events []Event) error {
+	wait := minRetryInterval
+	for {
+		err := p.sink.Publish(ctx, events)
+		if err == nil {
+			return nil
+		}
+		p.lg.Warn(
+			"failed to publish events; retrying",
+			zap.Int("events", len(events)),
+			zap.Int64("first-revision", events[0].ModRevision),
+			zap.Duration("retry-after", wait),
+			zap.Error(err),
+		)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		wait = min(2*wait, maxRetryInterval)
+	}
+}
+
+// checkpoint saves the published revision if it changed, at most every
+// checkpointInterval unless forced.
+func (p *Publisher) checkpoint(ctx context.Context, force bool) {
+	if p.rev == p.saved || (!force && time.Since(p.lastSave) < checkpointInterval) {
+		return
+	}
+	if err := p.cp.Save(ctx, p.rev); err != nil {
+		p.lg.Warn("failed to checkpoint published events", zap.Int64("revision", p.rev), zap.Error(err))
+		return
+	}
+	p.saved, p.lastSave = p.rev, time.Now()
+}
@@ -0,0 +1,173 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3cdc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/pkg/v3/traceutil"
+	"go.etcd.io/etcd/server/v3/lease"
+	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+type memCheckpoint struct {
+	mu  sync.Mutex
+	rev int64
+}
+
+func (c *memCheckpoint) Load(context.Context) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rev, nil
+}
+
+func (c *memCheckpoint) Save(_ context.Context, rev int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rev = rev
+	return nil
+}
+
+// fakeSink fails its first publication, then records the events.
+type fakeSink struct {
+	mu     sync.Mutex
+	failed bool
+	events []Event
+}
+
+func (s *fakeSink) Publish(_ context.Context, events []Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.failed {
+		s.failed = true
+		return errors.New("sink unavailable")
+	}
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func (s *fakeSink) keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []string
+	for _, ev := range s.events {
+		keys = append(keys, ev.Type+" "+string(ev.Key))
+	}
+	return keys
+}
+
+// runPublisher runs a publisher of kv until the returned function is called.
+func runPublisher(t *testing.T, kv mvcc.WatchableKV, sink Sink, cp Checkpoint) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := NewPublisher(zaptest.NewLogger(t), kv, sink, cp, []byte("/checkpoint/"))
+	done := make(chan error)
+	go func() { done <- p.Run(ctx) }()
+	return func() {
+		cancel()
+		require.NoError(t, <-done)
+	}
+}
+
+func TestPublisher(t *testing.T) {
+	be, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, be)
+	kv := mvcc.New(zaptest.NewLogger(t), be, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer kv.Close()
+
+	kv.Put([]byte("before"), []byte("v"), lease.NoLease)
+	cp := &memCheckpoint{}
+	sink := &fakeSink{}
+	stop := runPublisher(t, kv, sink, cp)
+	// the publication starts after the current revision without checkpoint.
+	require.Eventually(t, func() bool { rev, _ := cp.Load(context.Background()); return rev == kv.Rev() }, 5*time.Second, 10*time.Millisecond)
+
+	kv.Put([]byte("a"), []byte("1"), lease.NoLease)
+	kv.Put([]byte("/checkpoint/x"), []byte("1"), lease.NoLease)
+	kv.DeleteRange([]byte("a"), nil)
+	want := []string{"PUT a", "DELETE a"}
+	require.Eventually(t, func() bool { return len(sink.keys()) == len(want) }, 5*time.Second, 10*time.Millisecond)
+	stop()
+	assert.Equal(t, want, sink.keys())
+	rev, _ := cp.Load(context.Background())
+	assert.Equal(t, kv.Rev(), rev)
+
+	// the next publisher resumes after the checkpoint.
+	kv.Put([]byte("b"), []byte("2"), lease.NoLease)
+	kv.Put([]byte("c"), []byte("3"), lease.NoLease)
+	sink = &fakeSink{failed: true}
+	stop = runPublisher(t, kv, sink, cp)
+	want = []string{"PUT b", "PUT c"}
+	require.Eventually(t, func() bool { return len(sink.keys()) == len(want) }, 5*time.Second, 10*time.Millisecond)
+	stop()
+	assert.Equal(t, want, sink.keys())
+	assert.Equal(t, []byte("3"), sink.events[1].Value)
+	assert.Equal(t, kv.Rev(), sink.events[1].ModRevision)
+}
+
+func TestPublisherCompacted(t *testing.T) {
+	be, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, be)
+	kv := mvcc.New(zaptest.NewLogger(t), be, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer kv.Close()
+
+	kv.Put([]byte("a"), []byte("1"), lease.NoLease)
+	cp := &memCheckpoint{rev: kv.Rev()}
+	kv.Put([]byte("b"), []byte("2"), lease.NoLease)
+	kv.Put([]byte("c"), []byte("3"), lease.NoLease)
+	done, err := kv.Compact(traceutil.TODO(), kv.Rev())
+	require.NoError(t, err)
+	<-done
+
+	// the events compacted away are skipped, the event at the compacted
+	// revision is kept.
+	sink := &fakeSink{failed: true}
+	stop := runPublisher(t, kv, sink, cp)
+	kv.Put([]byte("d"), []byte("4"), lease.NoLease)
+	want := []string{"PUT c", "PUT d"}
+	require.Eventually(t, func() bool { return len(sink.keys()) == len(want) }, 5*time.Second, 10*time.Millisecond)
+	stop()
+	assert.Equal(t, want, sink.keys())
+}
+
+func TestWebhookSink(t *testing.T) {
+	var got webhookMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	sink, err := NewSink(zaptest.NewLogger(t), srv.URL)
+	require.NoError(t, err)
+	events := []Event{{Type: "PUT", Key: []byte("a"), Value: []byte("1"), ModRevision: 2}}
+	require.NoError(t, sink.Publish(context.Background(), events))
+	assert.Equal(t, events, got.Events)
+
+	_, err = NewSink(zaptest.NewLogger(t), "kafka://broker:9092/topic")
+	require.ErrorContains(t, err, "unsupported CDC sink URL scheme")
+}
@@ -0,0 +1,19 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v3cdc publishes the events of the key-value store of an etcd
+// server to pluggable sinks, with at-least-once delivery: the revision of
+// the last published event is checkpointed, and the publication resumes
+// after it, possibly publishing some events again.
+package v3cdc
@@ -0,0 +1,148 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3cdc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+)
+
+// Event is a change of a key, as published to the sinks.
+type Event struct {
+	// Type is PUT or DELETE.
+	Type           string `json:"type"`
+	Key            []byte `json:"key"`
+	Value          []byte `json:"value,omitempty"`
+	CreateRevision int64  `json:"createRevision,omitempty"`
+	// ModRevision is the revision of the change. The consumers may use it
+	// to discard the events published again.
+	ModRevision int64 `json:"modRevision"`
+	Version     int64 `json:"version,omitempty"`
+	Lease       int64 `json:"lease,omitempty"`
+}
+
+func newEvent(ev mvccpb.Event) Event {
+	return Event{
+		Type:           ev.Type.String(),
+		Key:            ev.Kv.Key,
+		Value:          ev.Kv.Value,
+		CreateRevision: ev.Kv.CreateRevision,
+		ModRevision:    ev.Kv.ModRevision,
+		Version:        ev.Kv.Version,
+		Lease:          ev.Kv.Lease,
+	}
+}
+
+// Sink receives the published events.
+type Sink interface {
+	// Publish delivers the events, in revision order. The events are
+	// checkpointed once Publish returns nil, and published again otherwise.
+	Publish(ctx context.Context, events []Event) error
+}
+
+// SinkFactory creates the Sink of a CDC URL.
+type SinkFactory func(lg *zap.Logger, u *url.URL) (Sink, error)
+
+var (
+	sinksMu sync.RWMutex
+	sinks   = map[string]SinkFactory{
+		"http":  newWebhookSink,
+		"https": newWebhookSink,
+	}
+)
+
+// RegisterSink makes the sinks created by f available to the CDC URLs with
+// the given scheme, e.g. "kafka" or "nats". The "http" and "https" webhook
+// schemes are built in.
+func RegisterSink(scheme string, f SinkFactory) {
+	sinksMu.Lock()
+	defer sinksMu.Unlock()
+	sinks[scheme] = f
+}
+
+// NewSink returns the Sink of the CDC URL rawURL.
+func NewSink(lg *zap.Logger, rawURL string) (Sink, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CDC sink URL %q: %w", rawURL, err)
+	}
+	sinksMu.RLock()
+	f, ok := sinks[u.Scheme]
+	sinksMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported CDC sink URL scheme %q", u.Scheme)
+	}
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	return f(lg, u)
+}
+
+// webhookTimeout bounds the requests to a webhook sink.
+const webhookTimeout = 10 * time.Second
+
+// webhookSink POSTs the events to a webhook
+//
+//	POST <url> {"events": [<event>...]}
+//
+// acknowledging them with a 2xx status.
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func newWebhookSink(_ *zap.Logger, u *url.URL) (Sink, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("webhook CDC sink URL %q has no host", u.String())
+	}
+	return &webhookSink{url: u.String(), client: &http.Client{Timeout: webhookTimeout}}, nil
+}
+
+type webhookMessage struct {
+	Events []Event `json:"events"`
+}
+
+func (s *webhookSink) Publish(ctx context.Context, events []Event) error {
+	body, err := json.Marshal(webhookMessage{Events: events})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("cdc webhook: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
@@ -23,11 +23,13 @@ import (
 	"go.uber.org/zap"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
 )
 
 const (
 	ModePeriodic = "periodic"
 	ModeRevision = "revision"
+	ModeSize     = "size"
 )
 
 // Compactor purges old log from the storage periodically.
@@ -51,7 +53,13 @@ type RevGetter interface {
 	Rev() int64
 }
 
+// CompactEstimator estimates the history a compaction would delete.
+type CompactEstimator interface {
+	CompactEstimate(rev int64) (mvcc.CompactEstimate, error)
+}
+
 // New returns a new Compactor based on given "mode".
+// The "size" mode needs rg to also be a CompactEstimator.
 func New(
 	lg *zap.Logger,
 	mode string,
@@ -67,6 +75,12 @@ func New(
 		return newPeriodic(lg, clockwork.NewRealClock(), retention, rg, c), nil
 	case ModeRevision:
 		return newRevision(lg, clockwork.NewRealClock(), int64(retention), rg, c), nil
+	case ModeSize:
+		e, ok := rg.(CompactEstimator)
+		if !ok {
+			return nil, fmt.Errorf("compaction mode %s cannot estimate the compacted history", mode)
+		}
+		return newSize(lg, clockwork.NewRealClock(), int64(retention), rg, e, c), nil
 	default:
 		return nil, fmt.Errorf("unsupported compaction mode %s", mode)
 	}
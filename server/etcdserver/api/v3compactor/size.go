@@ -0,0 +1,150 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3compactor
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+// Size compacts the log to the current revision when the history the
+// compaction would delete exceeds the configured number of bytes.
+// The estimate is checked every 5 minutes.
+type Size struct {
+	lg *zap.Logger
+
+	clock     clockwork.Clock
+	retention int64
+
+	rg RevGetter
+	e  CompactEstimator
+	c  Compactable
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	paused bool
+}
+
+// newSize creates a new instance of size-based compactor that purges
+// the log once more than retention bytes of it can be reclaimed.
+func newSize(lg *zap.Logger, clock clockwork.Clock, retention int64, rg RevGetter, e CompactEstimator, c Compactable) *Size {
+	sc := &Size{
+		lg:        lg,
+		clock:     clock,
+		retention: retention,
+		rg:        rg,
+		e:         e,
+		c:         c,
+	}
+	sc.ctx, sc.cancel = context.WithCancel(context.Background())
+	return sc
+}
+
+// Run runs size-based compactor.
+func (sc *Size) Run() {
+	prev := int64(0)
+	go func() {
+		for {
+			select {
+			case <-sc.ctx.Done():
+				return
+			case <-sc.clock.After(revInterval):
+				sc.mu.Lock()
+				p := sc.paused
+				sc.mu.Unlock()
+				if p {
+					continue
+				}
+			}
+
+			rev := sc.rg.Rev()
+			if rev <= prev {
+				continue
+			}
+			est, err := sc.e.CompactEstimate(rev)
+			if err != nil {
+				if errors.Is(err, mvcc.ErrCompacted) {
+					prev = rev
+				} else {
+					sc.lg.Warn(
+						"failed to estimate auto size compaction",
+						zap.Int64("revision", rev),
+						zap.Duration("retry-interval", revInterval),
+						zap.Error(err),
+					)
+				}
+				continue
+			}
+			if est.Bytes < sc.retention {
+				continue
+			}
+
+			now := time.Now()
+			sc.lg.Info(
+				"starting auto size compaction",
+				zap.Int64("revision", rev),
+				zap.Int64("reclaimable-bytes", est.Bytes),
+				zap.Int64("size-compaction-retention-bytes", sc.retention),
+			)
+			_, err = sc.c.Compact(sc.ctx, &pb.CompactionRequest{Revision: rev})
+			if err == nil || errors.Is(err, mvcc.ErrCompacted) {
+				prev = rev
+				sc.lg.Info(
+					"completed auto size compaction",
+					zap.Int64("revision", rev),
+					zap.Int64("size-compaction-retention-bytes", sc.retention),
+					zap.Duration("took", time.Since(now)),
+				)
+			} else {
+				sc.lg.Warn(
+					"failed auto size compaction",
+					zap.Int64("revision", rev),
+					zap.Int64("size-compaction-retention-bytes", sc.retention),
+					zap.Duration("retry-interval", revInterval),
+					zap.Error(err),
+				)
+			}
+		}
+	}()
+}
+
+// Stop stops size-based compactor.
+func (sc *Size) Stop() {
+	sc.cancel()
+}
+
+// Pause pauses size-based compactor.
+func (sc *Size) Pause() {
+	sc.mu.Lock()
+	sc.paused = true
+	sc.mu.Unlock()
+}
+
+// Resume resumes size-based compactor.
+func (sc *Size) Resume() {
+	sc.mu.Lock()
+	sc.paused = false
+	sc.mu.Unlock()
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3compactor
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"go.uber.org/zap/zaptest"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/client/pkg/v3/testutil"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+type fakeCompactEstimator struct {
+	bytes int64
+}
+
+func (fe *fakeCompactEstimator) CompactEstimate(int64) (mvcc.CompactEstimate, error) {
+	return mvcc.CompactEstimate{Bytes: atomic.LoadInt64(&fe.bytes)}, nil
+}
+
+func (fe *fakeCompactEstimator) SetBytes(bytes int64) {
+	atomic.StoreInt64(&fe.bytes, bytes)
+}
+
+func TestSize(t *testing.T) {
+	fc := clockwork.NewFakeClock()
+	rg := &fakeRevGetter{testutil.NewRecorderStreamWithWaitTimout(10 * time.Millisecond), 99}
+	est := &fakeCompactEstimator{}
+	compactable := &fakeCompactable{testutil.NewRecorderStreamWithWaitTimout(10 * time.Millisecond)}
+	tb := newSize(zaptest.NewLogger(t), fc, 1000, rg, est, compactable)
+
+	tb.Run()
+	defer tb.Stop()
+
+	// not enough history to reclaim
+	est.SetBytes(999)
+	fc.BlockUntil(1)
+	fc.Advance(revInterval)
+	rg.Wait(1)
+	select {
+	case a := <-compactable.Chan():
+		t.Fatalf("unexpected action %v", a)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	est.SetBytes(1000)
+	fc.BlockUntil(1)
+	fc.Advance(revInterval)
+	rg.Wait(1)
+	a, err := compactable.Wait(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// compacts to the current revision
+	wreq := &pb.CompactionRequest{Revision: 101}
+	if !reflect.DeepEqual(a[0].Params[0], wreq) {
+		t.Errorf("compact request = %v, want %v", a[0].Params[0], wreq)
+	}
+}
+
+func TestSizePause(t *testing.T) {
+	fc := clockwork.NewFakeClock()
+	rg := &fakeRevGetter{testutil.NewRecorderStream(), 99} // will be 100
+	est := &fakeCompactEstimator{bytes: 1000}
+	compactable := &fakeCompactable{testutil.NewRecorderStream()}
+	tb := newSize(zaptest.NewLogger(t), fc, 1000, rg, est, compactable)
+
+	tb.Run()
+	tb.Pause()
+	defer tb.Stop()
+
+	fc.BlockUntil(1)
+	fc.Advance(revInterval)
+	select {
+	case a := <-compactable.Chan():
+		t.Fatalf("unexpected action %v", a)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	tb.Resume()
+	fc.BlockUntil(1)
+	fc.Advance(revInterval)
+	rg.Wait(1)
+	a, err := compactable.Wait(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wreq := &pb.CompactionRequest{Revision: 100}
+	if !reflect.DeepEqual(a[0].Params[0], wreq) {
+		t.Errorf("compact request = %v, want %v", a[0].Params[0], wreq)
+	}
+}
@@ -0,0 +1,21 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v3mirror mirrors the keys of the key-value store of an etcd server
+// to a target cluster, as etcdctl make-mirror does. The changes of each
+// revision are applied to the target in a txn which also checkpoints the
+// revision in the target, so that the mirroring resumes after it. The keys
+// are synchronized again in full when the revisions to resume from were
+// compacted.
+package v3mirror
@@ -0,0 +1,44 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3mirror
+
+import "github.com/prometheus/client_golang/prometheus"
+
+var (
+	lagRevisions = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "mirror_lag_revisions",
+		Help:      "The number of revisions of the key-value store not mirrored to the target cluster yet, reported by the leader.",
+	})
+	resyncsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "mirror_resyncs_total",
+		Help:      "The total number of full synchronizations of the mirrored keys to the target cluster.",
+	})
+	failuresTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "mirror_failures_total",
+		Help:      "The total number of failures mirroring the keys to the target cluster.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(lagRevisions)
+	prometheus.MustRegister(resyncsTotal)
+	prometheus.MustRegister(failuresTotal)
+}
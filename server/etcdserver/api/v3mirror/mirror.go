@@ -0,0 +1,332 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3mirror
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+// progressInterval is the interval between two progress requests of the
+// watcher, which advance the mirrored revision past the revisions changing
+// no mirrored key.
+const progressInterval = time.Second
+
+// Config configures the mirroring of the keys.
+type Config struct {
+	// SourcePrefix is the prefix of the keys mirrored, all the keys if empty.
+	SourcePrefix string
+	// DestPrefix replaces SourcePrefix in the keys written to the target
+	// cluster. The keys of the target cluster with this prefix are owned by
+	// the mirroring: a full synchronization deletes the ones not mirrored.
+	DestPrefix string
+	// CheckpointKey is the key of the target cluster holding the revision up
+	// to which the keys were mirrored. It is never mirrored.
+	CheckpointKey string
+	// MaxTxnOps bounds the operations of a txn to the target cluster. It must
+	// be at least 2, to update a key and the checkpoint in the same txn.
+	MaxTxnOps int
+}
+
+// Status is the progress of the mirroring.
+type Status struct {
+	// Revision is the revision up to which the keys were mirrored.
+	Revision int64
+	// Resyncs is the number of full synchronizations of the keys.
+	Resyncs int64
+	// Err is the error of the last failed mirroring, nil once the keys are
+	// mirrored again.
+	Err error
+}
+
+// Mirror mirrors the keys of a key-value store to a target cluster. Only
+// one member is expected to mirror the keys at a time; the mirroring
+// resumes from the checkpoint of the target cluster so that another member
+// can take over.
+type Mirror struct {
+	lg  *zap.Logger
+	kv  mvcc.WatchableKV
+	cfg Config
+
+	mu     sync.Mutex
+	status Status
+}
+
+// NewMirror returns a Mirror of the keys of kv.
+func NewMirror(lg *zap.Logger, kv mvcc.WatchableKV, cfg Config) *Mirror {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	return &Mirror{lg: lg, kv: kv, cfg: cfg}
+}
+
+// Status returns the progress of the mirroring.
+func (m *Mirror) Status() Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status
+}
+
+// Run mirrors the keys to target until ctx is done or the mirroring fails.
+// It resumes after the revision checkpointed in target, or synchronizes
+// all the keys if there is no checkpoint or the revisions after it were
+// compacted.
+func (m *Mirror) Run(ctx context.Context, target Target) error {
+	err := m.run(ctx, target)
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		failuresTotal.Inc()
+		m.mu.Lock()
+		m.status.Err = err
+		m.mu.Unlock()
+	}
+	return err
+}
+
+func (m *Mirror) run(ctx context.Context, target Target) error {
+	v, err := target.Get(ctx, m.cfg.CheckpointKey)
+	if err != nil {
+		return err
+	}
+	var rev int64
+	if v != nil {
+		if rev, err = strconv.ParseInt(string(v), 10, 64); err != nil {
+			return fmt.Errorf("invalid mirror checkpoint %q: %w", v, err)
+		}
+	}
+	resync := rev == 0 || rev > m.kv.Rev()
+	if !resync {
+		m.setRevision(rev)
+	}
+
+	key, end := m.sourceRange()
+	ws := m.kv.NewWatchStream()
+	defer ws.Close()
+	for ctx.Err() == nil {
+		if resync {
+			if err = m.resync(ctx, target); err != nil {
+				return err
+			}
+		}
+		id, err := ws.Watch(clientv3.AutoWatchID, key, end, m.Status().Revision+1, m.filter)
+		if err != nil {
+			return err
+		}
+		resync, err = m.tail(ctx, ws, id, target)
+		ws.Cancel(id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tail mirrors the changes watched by the watcher id of ws until ctx is
+// done, or the watcher is canceled, because of a compaction if resync is
+// true.
+func (m *Mirror) tail(ctx context.Context, ws mvcc.WatchStream, id mvcc.WatchID, target Target) (resync bool, err error) {
+	ticker := time.NewTicker(progressInterval)
+	defer ticker.Stop()
+	for {
+		var wr mvcc.WatchResponse
+		select {
+		case wr = <-ws.Chan():
+		case <-ticker.C:
+			ws.RequestProgress(id)
+			continue
+		case <-ctx.Done():
+			return false, nil
+		}
+
+		switch {
+		case wr.CompactRevision != 0:
+			m.lg.Warn(
+				"revisions compacted before being mirrored; synchronizing all the keys",
+				zap.Int64("mirrored-revision", m.Status().Revision),
+				zap.Int64("compact-revision", wr.CompactRevision),
+			)
+			return true, nil
+		case wr.Canceled:
+			return false, nil
+		}
+		if err = m.apply(ctx, target, wr.Events); err != nil {
+			return false, err
+		}
+		m.setRevision(max(m.Status().Revision, wr.Revision))
+	}
+}
+
+// apply applies the events to target, in a txn per revision which also
+// checkpoints the revision. The events of a revision exceeding MaxTxnOps
+// are split across txns, checkpointing the previous revision until the
+// last one.
+func (m *Mirror) apply(ctx context.Context, target Target, events []mvccpb.Event) error {
+	for len(events) > 0 {
+		rev := events[0].Kv.ModRevision
+		n := 1
+		for n < len(events) && events[n].Kv.ModRevision == rev {
+			n++
+		}
+		for i := 0; i < n; i += m.cfg.MaxTxnOps - 1 {
+			j := min(i+m.cfg.MaxTxnOps-1, n)
+			ops := make([]clientv3.Op, 0, j-i+1)
+			for _, ev := range events[i:j] {
+				switch ev.Type {
+				case mvccpb.PUT:
+					ops = append(ops, clientv3.OpPut(m.destKey(ev.Kv.Key), string(ev.Kv.Value)))
+				case mvccpb.DELETE:
+					ops = append(ops, clientv3.OpDelete(m.destKey(ev.Kv.Key)))
+				}
+			}
+			cp := rev
+			if j < n {
+				cp = rev - 1
+			}
+			ops = append(ops, clientv3.OpPut(m.cfg.CheckpointKey, strconv.FormatInt(cp, 10)))
+			if err := target.Commit(ctx, ops); err != nil {
+				return err
+			}
+		}
+		m.setRevision(rev)
+		events = events[n:]
+	}
+	return nil
+}
+
+// resync puts all the keys to target as of the current revision, deletes
+// the keys of target not mirrored, and checkpoints the revision.
+func (m *Mirror) resync(ctx context.Context, target Target) error {
+	rev := m.kv.Rev()
+	m.lg.Info("synchronizing all the mirrored keys", zap.Int64("revision", rev))
+	limit := int64(m.cfg.MaxTxnOps)
+
+	key, end := m.sourceRange()
+	for {
+		r, err := m.kv.Range(ctx, key, end, mvcc.RangeOptions{Rev: rev, Limit: limit})
+		if err != nil {
+			return err
+		}
+		ops := make([]clientv3.Op, 0, len(r.KVs))
+		for _, kv := range r.KVs {
+			if !m.excluded(kv.Key) {
+				ops = append(ops, clientv3.OpPut(m.destKey(kv.Key), string(kv.Value)))
+			}
+		}
+		if len(ops) > 0 {
+			if err = target.Commit(ctx, ops); err != nil {
+				return err
+			}
+		}
+		if int64(len(r.KVs)) < limit {
+			break
+		}
+		key = append(slices.Clone(r.KVs[len(r.KVs)-1].Key), 0)
+	}
+
+	dkey, dend := m.destRange()
+	for {
+		keys, err := target.Keys(ctx, dkey, dend, limit)
+		if err != nil {
+			return err
+		}
+		var ops []clientv3.Op
+		for _, k := range keys {
+			if k == m.cfg.CheckpointKey {
+				continue
+			}
+			r, err := m.kv.Range(ctx, []byte(m.sourceKey(k)), nil, mvcc.RangeOptions{Rev: rev, Count: true})
+			if err != nil {
+				return err
+			}
+			if r.Count == 0 {
+				ops = append(ops, clientv3.OpDelete(k))
+			}
+		}
+		if len(ops) > 0 {
+			if err = target.Commit(ctx, ops); err != nil {
+				return err
+			}
+		}
+		if int64(len(keys)) < limit {
+			break
+		}
+		dkey = keys[len(keys)-1] + "\x00"
+	}
+
+	if err := target.Commit(ctx, []clientv3.Op{clientv3.OpPut(m.cfg.CheckpointKey, strconv.FormatInt(rev, 10))}); err != nil {
+		return err
+	}
+	resyncsTotal.Inc()
+	m.mu.Lock()
+	m.status.Resyncs++
+	m.mu.Unlock()
+	m.setRevision(rev)
+	return nil
+}
+
+// setRevision records the mirrored revision, clearing the last error, and
+// updates the lag metric.
+func (m *Mirror) setRevision(rev int64) {
+	m.mu.Lock()
+	m.status.Revision, m.status.Err = rev, nil
+	m.mu.Unlock()
+	lagRevisions.Set(float64(max(m.kv.Rev()-rev, 0)))
+}
+
+func (m *Mirror) filter(ev mvccpb.Event) bool {
+	return m.excluded(ev.Kv.Key)
+}
+
+func (m *Mirror) excluded(key []byte) bool {
+	return string(key) == m.cfg.CheckpointKey
+}
+
+// sourceRange returns the range of the mirrored keys, as understood by the
+// key-value store.
+func (m *Mirror) sourceRange() (key, end []byte) {
+	if m.cfg.SourcePrefix == "" {
+		return []byte{0}, []byte{}
+	}
+	return []byte(m.cfg.SourcePrefix), []byte(clientv3.GetPrefixRangeEnd(m.cfg.SourcePrefix))
+}
+
+// destRange returns the range of the keys of the target cluster owned by
+// the mirroring, as understood by a client.
+func (m *Mirror) destRange() (key, end string) {
+	if m.cfg.DestPrefix == "" {
+		return "\x00", "\x00"
+	}
+	return m.cfg.DestPrefix, clientv3.GetPrefixRangeEnd(m.cfg.DestPrefix)
+}
+
+func (m *Mirror) destKey(key []byte) string {
+	return m.cfg.DestPrefix + string(key[len(m.cfg.SourcePrefix):])
+}
+
+func (m *Mirror) sourceKey(key string) string {
+	return m.cfg.SourcePrefix + key[len(m.cfg.DestPrefix):]
+}
@@ -0,0 +1,195 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3mirror
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/pkg/v3/traceutil"
+	"go.etcd.io/etcd/server/v3/lease"
+	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+const testCheckpointKey = "/mirror-checkpoint"
+
+// memTarget is a Target keeping the keys in memory.
+type memTarget struct {
+	mu      sync.Mutex
+	kvs     map[string]string
+	commits [][]clientv3.Op
+}
+
+func newMemTarget(kvs map[string]string) *memTarget {
+	return &memTarget{kvs: kvs}
+}
+
+func (t *memTarget) Get(_ context.Context, key string) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	v, ok := t.kvs[key]
+	if !ok {
+		return nil, nil
+	}
+	return []byte(v), nil
+}
+
+func (t *memTarget) Keys(_ context.Context, key, end string, limit int64) ([]string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var keys []string
+	for _, k := range slices.Sorted(maps.Keys(t.kvs)) {
+		if k >= key && (end == "\x00" || k < end) && int64(len(keys)) < limit {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (t *memTarget) Commit(_ context.Context, ops []clientv3.Op) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, op := range ops {
+		switch {
+		case op.IsPut():
+			t.kvs[string(op.KeyBytes())] = string(op.ValueBytes())
+		case op.IsDelete():
+			delete(t.kvs, string(op.KeyBytes()))
+		}
+	}
+	t.commits = append(t.commits, ops)
+	return nil
+}
+
+func (t *memTarget) get(key string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.kvs[key]
+}
+
+func (t *memTarget) snapshot() map[string]string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return maps.Clone(t.kvs)
+}
+
+// runMirror runs m until the returned function is called.
+func runMirror(t *testing.T, m *Mirror, target Target) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- m.Run(ctx, target) }()
+	return func() {
+		cancel()
+		require.NoError(t, <-done)
+	}
+}
+
+func newTestKV(t *testing.T) mvcc.WatchableKV {
+	be, _ := betesting.NewDefaultTmpBackend(t)
+	t.Cleanup(func() { betesting.Close(t, be) })
+	kv := mvcc.New(zaptest.NewLogger(t), be, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	t.Cleanup(func() { kv.Close() })
+	return kv
+}
+
+func waitMirrored(t *testing.T, m *Mirror, kv mvcc.WatchableKV) {
+	require.Eventually(t, func() bool { return m.Status().Revision == kv.Rev() }, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestMirror(t *testing.T) {
+	kv := newTestKV(t)
+	kv.Put([]byte("/a/1"), []byte("1"), lease.NoLease)
+	kv.Put([]byte("/b/1"), []byte("1"), lease.NoLease)
+	target := newMemTarget(map[string]string{"/x/stale": "v", "/y/other": "v"})
+	cfg := Config{SourcePrefix: "/a/", DestPrefix: "/x/", CheckpointKey: testCheckpointKey, MaxTxnOps: 128}
+
+	// the keys are synchronized without checkpoint, the stale keys of the
+	// destination prefix deleted.
+	m := NewMirror(zaptest.NewLogger(t), kv, cfg)
+	stop := runMirror(t, m, target)
+	waitMirrored(t, m, kv)
+	assert.Equal(t, map[string]string{"/x/1": "1", "/y/other": "v", testCheckpointKey: strconv.FormatInt(kv.Rev(), 10)}, target.snapshot())
+
+	kv.Put([]byte("/a/2"), []byte("2"), lease.NoLease)
+	kv.DeleteRange([]byte("/a/1"), nil)
+	kv.Put([]byte("/b/2"), []byte("2"), lease.NoLease)
+	waitMirrored(t, m, kv)
+	stop()
+	assert.Equal(t, int64(1), m.Status().Resyncs)
+	assert.Equal(t, map[string]string{"/x/2": "2", "/y/other": "v", testCheckpointKey: strconv.FormatInt(kv.Rev()-1, 10)}, target.snapshot())
+
+	// the next mirror resumes after the checkpoint.
+	kv.Put([]byte("/a/3"), []byte("3"), lease.NoLease)
+	m = NewMirror(zaptest.NewLogger(t), kv, cfg)
+	stop = runMirror(t, m, target)
+	waitMirrored(t, m, kv)
+	stop()
+	assert.Equal(t, int64(0), m.Status().Resyncs)
+	assert.Equal(t, "3", target.get("/x/3"))
+	assert.Equal(t, strconv.FormatInt(kv.Rev(), 10), target.get(testCheckpointKey))
+}
+
+func TestMirrorCompacted(t *testing.T) {
+	kv := newTestKV(t)
+	kv.Put([]byte("a"), []byte("1"), lease.NoLease)
+	target := newMemTarget(map[string]string{"a": "1", testCheckpointKey: strconv.FormatInt(kv.Rev(), 10)})
+	kv.Put([]byte("b"), []byte("2"), lease.NoLease)
+	kv.DeleteRange([]byte("a"), nil)
+	done, err := kv.Compact(traceutil.TODO(), kv.Rev())
+	require.NoError(t, err)
+	<-done
+
+	// the keys are synchronized again since the changes after the
+	// checkpoint were compacted.
+	m := NewMirror(zaptest.NewLogger(t), kv, Config{CheckpointKey: testCheckpointKey, MaxTxnOps: 128})
+	stop := runMirror(t, m, target)
+	waitMirrored(t, m, kv)
+	stop()
+	assert.Equal(t, int64(1), m.Status().Resyncs)
+	assert.Equal(t, map[string]string{"b": "2", testCheckpointKey: strconv.FormatInt(kv.Rev(), 10)}, target.snapshot())
+}
+
+func TestMirrorSplitRevision(t *testing.T) {
+	kv := newTestKV(t)
+	target := newMemTarget(map[string]string{testCheckpointKey: strconv.FormatInt(kv.Rev(), 10)})
+	m := NewMirror(zaptest.NewLogger(t), kv, Config{CheckpointKey: testCheckpointKey, MaxTxnOps: 3})
+	stop := runMirror(t, m, target)
+
+	txn := kv.Write(traceutil.TODO())
+	for _, k := range []string{"a", "b", "c"} {
+		txn.Put([]byte(k), []byte("v"), lease.NoLease)
+	}
+	txn.End()
+	waitMirrored(t, m, kv)
+	stop()
+
+	// the revision is checkpointed with its last change only.
+	require.Len(t, target.commits, 2)
+	assert.Len(t, target.commits[0], 3)
+	assert.Equal(t, strconv.FormatInt(kv.Rev()-1, 10), string(target.commits[0][2].ValueBytes()))
+	assert.Len(t, target.commits[1], 2)
+	assert.Equal(t, strconv.FormatInt(kv.Rev(), 10), string(target.commits[1][1].ValueBytes()))
+}
@@ -0,0 +1,67 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3mirror
+
+import (
+	"context"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// Target is the key-value store of the target cluster.
+type Target interface {
+	// Get returns the value of key, nil if the key does not exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Keys returns, in order, up to limit keys of the range [key, end),
+	// where an end of "\x00" is the end of the key space.
+	Keys(ctx context.Context, key, end string, limit int64) ([]string, error)
+	// Commit applies the puts and deletes ops atomically.
+	Commit(ctx context.Context, ops []clientv3.Op) error
+}
+
+type clientTarget struct {
+	kv clientv3.KV
+}
+
+// NewTarget returns the Target of the key-value store of a client of the
+// target cluster.
+func NewTarget(kv clientv3.KV) Target {
+	return &clientTarget{kv: kv}
+}
+
+func (t *clientTarget) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := t.kv.Get(ctx, key)
+	if err != nil || len(resp.Kvs) == 0 {
+		return nil, err
+	}
+	return resp.Kvs[0].Value, nil
+}
+
+func (t *clientTarget) Keys(ctx context.Context, key, end string, limit int64) ([]string, error) {
+	resp, err := t.kv.Get(ctx, key, clientv3.WithRange(end), clientv3.WithKeysOnly(), clientv3.WithLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, len(resp.Kvs))
+	for i, kv := range resp.Kvs {
+		keys[i] = string(kv.Key)
+	}
+	return keys, nil
+}
+
+func (t *clientTarget) Commit(ctx context.Context, ops []clientv3.Op) error {
+	_, err := t.kv.Txn(ctx).Then(ops...).Commit()
+	return err
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/server/v3/auth"
+	"go.etcd.io/etcd/server/v3/etcdserver/admission"
+)
+
+type admissionReviewer interface {
+	Review(ctx context.Context, req admission.Request) (admission.Response, error)
+}
+
+// newAdmissionUnaryInterceptor has the admission webhook review the puts,
+// deletes and txns with writes before they are proposed.
+func newAdmissionUnaryInterceptor(lg *zap.Logger, webhook admissionReviewer, authInfo func(ctx context.Context) (*auth.AuthInfo, error)) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		areq, ok := admission.NewRequest(req)
+		if !ok {
+			return handler(ctx, req)
+		}
+		if ai, err := authInfo(ctx); err == nil && ai != nil {
+			areq.User = ai.Username
+		}
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			areq.Remote = p.Addr.String()
+		}
+
+		resp, err := webhook.Review(ctx, areq)
+		if err != nil {
+			lg.Warn(
+				"admission webhook failed",
+				zap.String("rpc", info.FullMethod),
+				zap.Bool("allowed", resp.Allowed),
+				zap.Error(err),
+			)
+			if !resp.Allowed {
+				return nil, rpctypes.ErrGRPCAdmissionFailed
+			}
+			return handler(ctx, req)
+		}
+		annotateAudit(ctx, resp.Annotations)
+		if !resp.Allowed {
+			if resp.Reason == "" {
+				return nil, rpctypes.ErrGRPCAdmissionDenied
+			}
+			return nil, status.Error(codes.PermissionDenied, rpctypes.ErrorDesc(rpctypes.ErrGRPCAdmissionDenied)+": "+resp.Reason)
+		}
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/server/v3/auth"
+	"go.etcd.io/etcd/server/v3/etcdserver/admission"
+)
+
+type fakeAdmissionReviewer struct {
+	resp admission.Response
+	err  error
+	reqs []admission.Request
+}
+
+func (f *fakeAdmissionReviewer) Review(_ context.Context, req admission.Request) (admission.Response, error) {
+	f.reqs = append(f.reqs, req)
+	return f.resp, f.err
+}
+
+func TestAdmissionUnaryInterceptor(t *testing.T) {
+	authInfo := func(context.Context) (*auth.AuthInfo, error) { return &auth.AuthInfo{Username: "alice"}, nil }
+	info := &grpc.UnaryServerInfo{FullMethod: "/etcdserverpb.KV/Put"}
+
+	tests := []struct {
+		name        string
+		req         any
+		resp        admission.Response
+		err         error
+		wantReviews int
+		wantErr     error
+		wantServed  bool
+	}{
+		{
+			name:        "allowed",
+			req:         &pb.PutRequest{Key: []byte("a")},
+			resp:        admission.Response{Allowed: true},
+			wantReviews: 1,
+			wantServed:  true,
+		},
+		{
+			name:        "denied",
+			req:         &pb.DeleteRangeRequest{Key: []byte("a")},
+			wantReviews: 1,
+			wantErr:     rpctypes.ErrGRPCAdmissionDenied,
+		},
+		{
+			name:        "denied with a reason",
+			req:         &pb.PutRequest{Key: []byte("a")},
+			resp:        admission.Response{Reason: "bad key"},
+			wantReviews: 1,
+			wantErr:     status.Error(codes.PermissionDenied, "etcdserver: request denied by the admission webhook: bad key"),
+		},
+		{
+			name:        "webhook failed closed",
+			req:         &pb.PutRequest{Key: []byte("a")},
+			err:         errors.New("timeout"),
+			wantReviews: 1,
+			wantErr:     rpctypes.ErrGRPCAdmissionFailed,
+		},
+		{
+			name:        "webhook failed open",
+			req:         &pb.PutRequest{Key: []byte("a")},
+			resp:        admission.Response{Allowed: true},
+			err:         errors.New("timeout"),
+			wantReviews: 1,
+			wantServed:  true,
+		},
+		{
+			name:       "reads are not reviewed",
+			req:        &pb.RangeRequest{Key: []byte("a")},
+			wantServed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reviewer := &fakeAdmissionReviewer{resp: tt.resp, err: tt.err}
+			served := false
+			handler := func(ctx context.Context, req any) (any, error) {
+				served = true
+				return nil, nil
+			}
+			_, err := newAdmissionUnaryInterceptor(zap.NewNop(), reviewer, authInfo)(context.Background(), tt.req, info, handler)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr.Error(), err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantServed, served)
+			require.Len(t, reviewer.reqs, tt.wantReviews)
+			if tt.wantReviews > 0 {
+				assert.Equal(t, "alice", reviewer.reqs[0].User)
+			}
+		})
+	}
+}
+
+func TestAdmissionAnnotatesAudit(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	reviewer := &fakeAdmissionReviewer{resp: admission.Response{Allowed: true, Annotations: map[string]string{"policy": "v1"}}}
+	authInfo := func(context.Context) (*auth.AuthInfo, error) { return nil, nil }
+	info := &grpc.UnaryServerInfo{FullMethod: "/etcdserverpb.KV/Put"}
+	req := &pb.PutRequest{Key: []byte("a")}
+
+	ctx := context.WithValue(context.Background(), auditAnnotationsKey{}, &auditAnnotations{})
+	_, err := newAdmissionUnaryInterceptor(zap.NewNop(), reviewer, authInfo)(ctx, req, info, func(context.Context, any) (any, error) { return nil, nil })
+	require.NoError(t, err)
+	logAuditRecord(ctx, zap.New(core), info.FullMethod, "", req, nil, nil)
+
+	entries := logs.All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, map[string]string{"policy": "v1"}, entries[0].ContextMap()["annotations"])
+}
@@ -0,0 +1,227 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"context"
+	"maps"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/client/pkg/v3/types"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+	"go.etcd.io/etcd/server/v3/etcdserver/txn"
+)
+
+// newAuditUnaryInterceptor writes one record to the audit logger for every
+// mutating request, whether it succeeded or not.
+func newAuditUnaryInterceptor(s *etcdserver.EtcdServer, lg *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !isAuditedRequest(req) {
+			return handler(ctx, req)
+		}
+		// resolve the user before serving the request, which may revoke it
+		var user string
+		if ai, err := s.AuthInfoFromCtx(ctx); err == nil && ai != nil {
+			user = ai.Username
+		}
+		ctx = context.WithValue(ctx, auditAnnotationsKey{}, &auditAnnotations{})
+		resp, err := handler(ctx, req)
+		logAuditRecord(ctx, lg, info.FullMethod, user, req, resp, err)
+		return resp, err
+	}
+}
+
+func isAuditedRequest(req any) bool {
+	switch r := req.(type) {
+	case *pb.TxnRequest:
+		return !txn.IsTxnReadonly(r)
+	case *pb.PutRequest, *pb.DeleteRangeRequest, *pb.CompactionRequest,
+		*pb.AuthEnableRequest, *pb.AuthDisableRequest,
+		*pb.AuthUserAddRequest, *pb.AuthUserDeleteRequest, *pb.AuthUserChangePasswordRequest,
+		*pb.AuthUserGrantRoleRequest, *pb.AuthUserRevokeRoleRequest,
+		*pb.AuthRoleAddRequest, *pb.AuthRoleDeleteRequest,
+		*pb.AuthRoleGrantPermissionRequest, *pb.AuthRoleRevokePermissionRequest,
+		*pb.AuthTokenRevokeRequest,
+		*pb.MemberAddRequest, *pb.MemberRemoveRequest, *pb.MemberUpdateRequest, *pb.MemberPromoteRequest:
+		return true
+	}
+	return false
+}
+
+func logAuditRecord(ctx context.Context, lg *zap.Logger, method, user string, req, resp any, err error) {
+	remote := ""
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		remote = p.Addr.String()
+	}
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	var revision int64
+	if r, ok := resp.(interface{ GetHeader() *pb.ResponseHeader }); ok {
+		revision = r.GetHeader().GetRevision()
+	}
+
+	fields := []zap.Field{
+		zap.String("rpc", method),
+		zap.String("user", user),
+		zap.String("remote", remote),
+	}
+	fields = append(fields, auditRequestFields(req, resp)...)
+	fields = append(fields,
+		zap.Int64("revision", revision),
+		zap.String("result", result),
+	)
+	if ann, ok := ctx.Value(auditAnnotationsKey{}).(*auditAnnotations); ok && len(ann.m) > 0 {
+		fields = append(fields, zap.Any("annotations", ann.m))
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	lg.Info("audit", fields...)
+}
+
+type auditAnnotationsKey struct{}
+
+// auditAnnotations are added to the audit record of a request while it is
+// served, e.g. by the admission webhook.
+type auditAnnotations struct {
+	m map[string]string
+}
+
+// annotateAudit adds the annotations to the audit record of the request
+// served with ctx, if it is audited.
+func annotateAudit(ctx context.Context, annotations map[string]string) {
+	ann, ok := ctx.Value(auditAnnotationsKey{}).(*auditAnnotations)
+	if !ok || len(annotations) == 0 {
+		return
+	}
+	if ann.m == nil {
+		ann.m = make(map[string]string, len(annotations))
+	}
+	maps.Copy(ann.m, annotations)
+}
+
+// auditRequestFields describes what the request touches. Passwords and
+// values are never logged.
+func auditRequestFields(req, resp any) []zap.Field {
+	switch r := req.(type) {
+	case *pb.PutRequest:
+		return []zap.Field{zap.Array("keys", auditKeyRanges{{key: r.Key}})}
+	case *pb.DeleteRangeRequest:
+		return []zap.Field{zap.Array("keys", auditKeyRanges{{key: r.Key, rangeEnd: r.RangeEnd}})}
+	case *pb.TxnRequest:
+		txnResp, _ := resp.(*pb.TxnResponse)
+		return []zap.Field{zap.Array("keys", txnKeyRanges(r, txnResp))}
+	case *pb.CompactionRequest:
+		return []zap.Field{zap.Int64("compact-revision", r.Revision)}
+	case *pb.AuthUserAddRequest:
+		return []zap.Field{zap.String("target-user", r.Name)}
+	case *pb.AuthUserDeleteRequest:
+		return []zap.Field{zap.String("target-user", r.Name)}
+	case *pb.AuthUserChangePasswordRequest:
+		return []zap.Field{zap.String("target-user", r.Name)}
+	case *pb.AuthUserGrantRoleRequest:
+		return []zap.Field{zap.String("target-user", r.User), zap.String("target-role", r.Role)}
+	case *pb.AuthUserRevokeRoleRequest:
+		return []zap.Field{zap.String("target-user", r.Name), zap.String("target-role", r.Role)}
+	case *pb.AuthRoleAddRequest:
+		return []zap.Field{zap.String("target-role", r.Name)}
+	case *pb.AuthRoleDeleteRequest:
+		return []zap.Field{zap.String("target-role", r.Role)}
+	case *pb.AuthRoleGrantPermissionRequest:
+		fields := []zap.Field{zap.String("target-role", r.Name)}
+		if r.Perm != nil {
+			fields = append(fields,
+				zap.Array("keys", auditKeyRanges{{key: r.Perm.Key, rangeEnd: r.Perm.RangeEnd}}),
+				zap.String("permission", r.Perm.PermType.String()),
+			)
+		}
+		return fields
+	case *pb.AuthRoleRevokePermissionRequest:
+		return []zap.Field{zap.String("target-role", r.Role), zap.Array("keys", auditKeyRanges{{key: r.Key, rangeEnd: r.RangeEnd}})}
+	case *pb.MemberAddRequest:
+		fields := []zap.Field{zap.Strings("peer-urls", r.PeerURLs), zap.Bool("is-learner", r.IsLearner), zap.Bool("is-standby", r.IsStandby)}
+		if addResp, ok := resp.(*pb.MemberAddResponse); ok && addResp.GetMember() != nil {
+			fields = append(fields, zap.String("target-member-id", types.ID(addResp.Member.ID).String()))
+		}
+		return fields
+	case *pb.MemberRemoveRequest:
+		return []zap.Field{zap.String("target-member-id", types.ID(r.ID).String())}
+	case *pb.MemberUpdateRequest:
+		return []zap.Field{zap.String("target-member-id", types.ID(r.ID).String()), zap.Strings("peer-urls", r.PeerURLs)}
+	case *pb.MemberPromoteRequest:
+		return []zap.Field{zap.String("target-member-id", types.ID(r.ID).String())}
+	}
+	return nil
+}
+
+// txnKeyRanges returns the keys written by the branch the txn took, or by
+// both branches if the txn failed before being evaluated.
+func txnKeyRanges(r *pb.TxnRequest, resp *pb.TxnResponse) auditKeyRanges {
+	var ops []*pb.RequestOp
+	switch {
+	case resp == nil:
+		ops = append(append(ops, r.Success...), r.Failure...)
+	case resp.Succeeded:
+		ops = r.Success
+	default:
+		ops = r.Failure
+	}
+	var krs auditKeyRanges
+	for i, op := range ops {
+		switch tv := op.Request.(type) {
+		case *pb.RequestOp_RequestPut:
+			krs = append(krs, auditKeyRange{key: tv.RequestPut.Key})
+		case *pb.RequestOp_RequestDeleteRange:
+			krs = append(krs, auditKeyRange{key: tv.RequestDeleteRange.Key, rangeEnd: tv.RequestDeleteRange.RangeEnd})
+		case *pb.RequestOp_RequestTxn:
+			var nested *pb.TxnResponse
+			if resp != nil && i < len(resp.Responses) {
+				nested = resp.Responses[i].GetResponseTxn()
+			}
+			krs = append(krs, txnKeyRanges(tv.RequestTxn, nested)...)
+		}
+	}
+	return krs
+}
+
+type auditKeyRange struct {
+	key, rangeEnd []byte
+}
+
+type auditKeyRanges []auditKeyRange
+
+func (krs auditKeyRanges) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, kr := range krs {
+		if err := enc.AppendObject(kr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (kr auditKeyRange) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("key", string(kr.key))
+	if len(kr.rangeEnd) > 0 {
+		enc.AddString("range-end", string(kr.rangeEnd))
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc/peer"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+func TestIsAuditedRequest(t *testing.T) {
+	readOnlyTxn := &pb.TxnRequest{Success: []*pb.RequestOp{{Request: &pb.RequestOp_RequestRange{RequestRange: &pb.RangeRequest{Key: []byte("a")}}}}}
+	writeTxn := &pb.TxnRequest{Failure: []*pb.RequestOp{{Request: &pb.RequestOp_RequestPut{RequestPut: &pb.PutRequest{Key: []byte("a")}}}}}
+
+	assert.True(t, isAuditedRequest(&pb.PutRequest{}))
+	assert.True(t, isAuditedRequest(writeTxn))
+	assert.True(t, isAuditedRequest(&pb.AuthUserAddRequest{}))
+	assert.True(t, isAuditedRequest(&pb.MemberRemoveRequest{}))
+	assert.False(t, isAuditedRequest(&pb.RangeRequest{}))
+	assert.False(t, isAuditedRequest(readOnlyTxn))
+	assert.False(t, isAuditedRequest(&pb.AuthenticateRequest{}))
+	assert.False(t, isAuditedRequest(&pb.MemberListRequest{}))
+}
+
+func TestTxnKeyRanges(t *testing.T) {
+	put := func(k string) *pb.RequestOp {
+		return &pb.RequestOp{Request: &pb.RequestOp_RequestPut{RequestPut: &pb.PutRequest{Key: []byte(k)}}}
+	}
+	del := func(k, end string) *pb.RequestOp {
+		return &pb.RequestOp{Request: &pb.RequestOp_RequestDeleteRange{RequestDeleteRange: &pb.DeleteRangeRequest{Key: []byte(k), RangeEnd: []byte(end)}}}
+	}
+	rng := &pb.RequestOp{Request: &pb.RequestOp_RequestRange{RequestRange: &pb.RangeRequest{Key: []byte("r")}}}
+	nested := &pb.TxnRequest{Success: []*pb.RequestOp{put("n1")}, Failure: []*pb.RequestOp{put("n2")}}
+	req := &pb.TxnRequest{
+		Success: []*pb.RequestOp{rng, put("s"), {Request: &pb.RequestOp_RequestTxn{RequestTxn: nested}}},
+		Failure: []*pb.RequestOp{del("f", "g")},
+	}
+
+	tcs := []struct {
+		name string
+		resp *pb.TxnResponse
+		want auditKeyRanges
+	}{
+		{
+			name: "not evaluated",
+			want: auditKeyRanges{{key: []byte("s")}, {key: []byte("n1")}, {key: []byte("n2")}, {key: []byte("f"), rangeEnd: []byte("g")}},
+		},
+		{
+			name: "succeeded",
+			resp: &pb.TxnResponse{Succeeded: true, Responses: []*pb.ResponseOp{
+				{}, {}, {Response: &pb.ResponseOp_ResponseTxn{ResponseTxn: &pb.TxnResponse{Succeeded: false}}},
+			}},
+			want: auditKeyRanges{{key: []byte("s")}, {key: []byte("n2")}},
+		},
+		{
+			name: "failed",
+			resp: &pb.TxnResponse{Succeeded: false},
+			want: auditKeyRanges{{key: []byte("f"), rangeEnd: []byte("g")}},
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, txnKeyRanges(req, tc.resp))
+		})
+	}
+}
+
+func TestLogAuditRecord(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	lg := zap.New(core)
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 2379}})
+
+	logAuditRecord(ctx, lg, "/etcdserverpb.KV/DeleteRange", "root",
+		&pb.DeleteRangeRequest{Key: []byte("a"), RangeEnd: []byte("b")},
+		&pb.DeleteRangeResponse{Header: &pb.ResponseHeader{Revision: 7}}, nil)
+	logAuditRecord(ctx, lg, "/etcdserverpb.Auth/UserAdd", "",
+		&pb.AuthUserAddRequest{Name: "foo", Password: "secret"}, nil, errors.New("permission denied"))
+
+	entries := logs.All()
+	require.Len(t, entries, 2)
+
+	f := entries[0].ContextMap()
+	assert.Equal(t, "/etcdserverpb.KV/DeleteRange", f["rpc"])
+	assert.Equal(t, "root", f["user"])
+	assert.Equal(t, "127.0.0.1:2379", f["remote"])
+	assert.Equal(t, []any{map[string]any{"key": "a", "range-end": "b"}}, f["keys"])
+	assert.Equal(t, int64(7), f["revision"])
+	assert.Equal(t, "success", f["result"])
+
+	f = entries[1].ContextMap()
+	assert.Equal(t, "foo", f["target-user"])
+	assert.Equal(t, "failure", f["result"])
+	assert.Equal(t, "permission denied", f["error"])
+	for _, v := range f {
+		assert.NotEqual(t, "secret", v)
+	}
+}
@@ -102,6 +102,14 @@ func (as *AuthServer) RoleRevokePermission(ctx context.Context, r *pb.AuthRoleRe
 	return resp, nil
 }
 
+func (as *AuthServer) TokenRevoke(ctx context.Context, r *pb.AuthTokenRevokeRequest) (*pb.AuthTokenRevokeResponse, error) {
+	resp, err := as.authenticator.TokenRevoke(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	return resp, nil
+}
+
 func (as *AuthServer) RoleGrantPermission(ctx context.Context, r *pb.AuthRoleGrantPermissionRequest) (*pb.AuthRoleGrantPermissionResponse, error) {
 	resp, err := as.authenticator.RoleGrantPermission(ctx, r)
 	if err != nil {
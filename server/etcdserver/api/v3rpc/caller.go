@@ -0,0 +1,140 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"context"
+	"sync"
+	"unicode/utf8"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+)
+
+const (
+	// unknownCaller is the label of the requests that carry no valid caller.
+	unknownCaller = "unknown"
+	// otherCaller is the label of the requests of the callers seen after the
+	// number of tracked caller labels reached its limit.
+	otherCaller = "other"
+
+	maxCallerLabelLength = 64
+)
+
+// callerLabels bounds the number of distinct caller labels the per-caller
+// metrics are partitioned by, so that clients cannot blow up the metrics
+// cardinality by sending arbitrary labels.
+type callerLabels struct {
+	max int
+
+	mu     sync.RWMutex
+	labels map[string]struct{}
+}
+
+func newCallerLabels(maxLabels int) *callerLabels {
+	return &callerLabels{max: maxLabels, labels: make(map[string]struct{})}
+}
+
+// label returns the metrics label of the caller the incoming request
+// carried in its metadata.
+func (cl *callerLabels) label(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return unknownCaller
+	}
+	vs := md.Get(rpctypes.MetadataCallerKey)
+	if len(vs) == 0 || vs[0] == "" || len(vs[0]) > maxCallerLabelLength || !utf8.ValidString(vs[0]) {
+		return unknownCaller
+	}
+	return cl.bound(vs[0])
+}
+
+// bound returns l, or otherCaller if l is a new label past the limit.
+func (cl *callerLabels) bound(l string) string {
+	if l == unknownCaller || l == otherCaller {
+		return l
+	}
+
+	cl.mu.RLock()
+	_, ok := cl.labels[l]
+	cl.mu.RUnlock()
+	if ok {
+		return l
+	}
+
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if _, ok = cl.labels[l]; ok {
+		return l
+	}
+	if len(cl.labels) >= cl.max {
+		return otherCaller
+	}
+	cl.labels[l] = struct{}{}
+	return l
+}
+
+func newCallerUnaryInterceptor(cl *callerLabels) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		caller := cl.label(ctx)
+		callerRequests.WithLabelValues("unary", caller).Inc()
+		callerReceivedBytes.WithLabelValues(caller).Add(float64(messageSize(req)))
+
+		resp, err := handler(ctx, req)
+		if err == nil {
+			callerSentBytes.WithLabelValues(caller).Add(float64(messageSize(resp)))
+		}
+		return resp, err
+	}
+}
+
+func newCallerStreamInterceptor(cl *callerLabels) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		caller := cl.label(ss.Context())
+		callerRequests.WithLabelValues("stream", caller).Inc()
+		return handler(srv, &callerServerStream{ServerStream: ss, caller: caller})
+	}
+}
+
+// callerServerStream accounts the messages of a stream to its caller.
+type callerServerStream struct {
+	grpc.ServerStream
+	caller string
+}
+
+func (ss *callerServerStream) SendMsg(m any) error {
+	err := ss.ServerStream.SendMsg(m)
+	if err == nil {
+		callerSentBytes.WithLabelValues(ss.caller).Add(float64(messageSize(m)))
+	}
+	return err
+}
+
+func (ss *callerServerStream) RecvMsg(m any) error {
+	err := ss.ServerStream.RecvMsg(m)
+	if err == nil {
+		callerReceivedBytes.WithLabelValues(ss.caller).Add(float64(messageSize(m)))
+	}
+	return err
+}
+
+func messageSize(m any) int {
+	if s, ok := m.(interface{ Size() int }); ok {
+		return s.Size()
+	}
+	return 0
+}
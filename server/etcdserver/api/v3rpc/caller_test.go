@@ -0,0 +1,46 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+)
+
+func TestCallerLabels(t *testing.T) {
+	cl := newCallerLabels(2)
+	withCaller := func(caller string) context.Context {
+		return metadata.NewIncomingContext(t.Context(), metadata.Pairs(rpctypes.MetadataCallerKey, caller))
+	}
+
+	assert.Equal(t, unknownCaller, cl.label(t.Context()))
+	assert.Equal(t, unknownCaller, cl.label(metadata.NewIncomingContext(t.Context(), metadata.MD{})))
+	assert.Equal(t, unknownCaller, cl.label(withCaller("")))
+	assert.Equal(t, unknownCaller, cl.label(withCaller(strings.Repeat("a", maxCallerLabelLength+1))))
+	assert.Equal(t, unknownCaller, cl.label(withCaller("\xff")))
+
+	assert.Equal(t, "a", cl.label(withCaller("a")))
+	assert.Equal(t, "b", cl.label(withCaller("b")))
+	assert.Equal(t, otherCaller, cl.label(withCaller("c")))
+	// labels already tracked keep their series once the limit is reached.
+	assert.Equal(t, "a", cl.label(withCaller("a")))
+	assert.Equal(t, otherCaller, cl.label(withCaller("c")))
+}
@@ -25,6 +25,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/client/v3/credentials"
@@ -44,7 +45,11 @@ func Server(s *etcdserver.EtcdServer, tls *tls.Config, interceptor grpc.UnarySer
 
 	var mopts []grpc_prometheus.ServerMetricsOption
 	if s.Cfg.Metrics == "extensive" {
-		mopts = append(mopts, grpc_prometheus.WithServerHandlingTimeHistogram())
+		var hopts []grpc_prometheus.HistogramOption
+		if len(s.Cfg.GRPCHistogramBuckets) > 0 {
+			hopts = append(hopts, grpc_prometheus.WithHistogramBuckets(s.Cfg.GRPCHistogramBuckets))
+		}
+		mopts = append(mopts, grpc_prometheus.WithServerHandlingTimeHistogram(hopts...))
 	}
 	serverMetrics := grpc_prometheus.NewServerMetrics(mopts...)
 	err := prometheus.Register(serverMetrics)
@@ -52,19 +57,62 @@ func Server(s *etcdserver.EtcdServer, tls *tls.Config, interceptor grpc.UnarySer
 		s.Cfg.Logger.Warn("etcdserver: failed to register grpc metrics", zap.Error(err))
 	}
 
-	chainUnaryInterceptors := []grpc.UnaryServerInterceptor{
-		newLogUnaryInterceptor(s),
+	callers := newCallerLabels(s.Cfg.MaxCallerLabels)
+	var chainUnaryInterceptors []grpc.UnaryServerInterceptor
+	var chainStreamInterceptors []grpc.StreamServerInterceptor
+	if s.Cfg.ClientIdentityMetrics {
+		// first, so that the next interceptors log the client identity
+		identities := newCallerLabels(s.Cfg.MaxCallerLabels)
+		chainUnaryInterceptors = append(chainUnaryInterceptors, newIdentityUnaryInterceptor(identities, s.AuthInfoFromCtx))
+		chainStreamInterceptors = append(chainStreamInterceptors, newIdentityStreamInterceptor(identities, s.AuthInfoFromCtx))
+	}
+	chainUnaryInterceptors = append(chainUnaryInterceptors, newLogUnaryInterceptor(s))
+	if s.Cfg.LogSlowRequestsAbove > 0 {
+		chainUnaryInterceptors = append(chainUnaryInterceptors, newSlowRequestUnaryInterceptor(s.Logger(), s.Cfg.LogSlowRequestsAbove, s.Cfg.LogSlowRequestsSampleInitial, s.Cfg.LogSlowRequestsSampleThereafter))
+	}
+	var ns *namespaces
+	if len(s.Cfg.Namespaces) > 0 {
+		prefixes := make(map[string]string, len(s.Cfg.Namespaces))
+		for _, name := range s.Cfg.Namespaces {
+			prefixes[name] = s.Cfg.NamespaceKeyPrefix(name)
+		}
+		ns = newNamespaces(prefixes)
+		chainUnaryInterceptors = append(chainUnaryInterceptors, newNamespaceUnaryInterceptor(ns))
+	}
+	if s.Cfg.AuditLogger != nil {
+		chainUnaryInterceptors = append(chainUnaryInterceptors, newAuditUnaryInterceptor(s, s.Cfg.AuditLogger))
+	}
+	var rateLimiter *clientRateLimiter
+	if s.Cfg.ClientRateLimitQPS > 0 || s.Cfg.ClientRateLimitBytes > 0 {
+		rateLimiter = newClientRateLimiter(s.Cfg.ClientRateLimitQPS, s.Cfg.ClientRateLimitBurst, s.Cfg.ClientRateLimitBytes, s.Cfg.MaxCallerLabels, s.AuthInfoFromCtx)
+		chainUnaryInterceptors = append(chainUnaryInterceptors, newRateLimitUnaryInterceptor(rateLimiter))
+	}
+	if s.Cfg.QoSMaxInflightRequests > 0 {
+		chainUnaryInterceptors = append(chainUnaryInterceptors, newQoSUnaryInterceptor(newQoSScheduler(s.Cfg.QoSMaxInflightRequests)))
+	}
+	chainUnaryInterceptors = append(chainUnaryInterceptors,
 		newUnaryInterceptor(s),
+		newCallerUnaryInterceptor(callers),
 		serverMetrics.UnaryServerInterceptor(),
+	)
+	if s.Cfg.AdmissionWebhook != nil {
+		chainUnaryInterceptors = append(chainUnaryInterceptors, newAdmissionUnaryInterceptor(s.Logger(), s.Cfg.AdmissionWebhook, s.AuthInfoFromCtx))
 	}
 	if interceptor != nil {
 		chainUnaryInterceptors = append(chainUnaryInterceptors, interceptor)
 	}
 
-	chainStreamInterceptors := []grpc.StreamServerInterceptor{
+	if ns != nil {
+		chainStreamInterceptors = append(chainStreamInterceptors, newNamespaceStreamInterceptor(ns))
+	}
+	if rateLimiter != nil {
+		chainStreamInterceptors = append(chainStreamInterceptors, newRateLimitStreamInterceptor(rateLimiter))
+	}
+	chainStreamInterceptors = append(chainStreamInterceptors,
 		newStreamInterceptor(s),
+		newCallerStreamInterceptor(callers),
 		serverMetrics.StreamServerInterceptor(),
-	}
+	)
 
 	if s.Cfg.EnableDistributedTracing {
 		chainUnaryInterceptors = append(chainUnaryInterceptors, otelgrpc.UnaryServerInterceptor(s.Cfg.TracerOptions...))
@@ -91,6 +139,10 @@ func Server(s *etcdserver.EtcdServer, tls *tls.Config, interceptor grpc.UnarySer
 	healthpb.RegisterHealthServer(grpcServer, hsrv)
 	pb.RegisterMaintenanceServer(grpcServer, NewMaintenanceServer(s, healthNotifier))
 
+	if s.Cfg.EnableGRPCReflection {
+		reflection.Register(grpcServer)
+	}
+
 	// set zero values for metrics registered for this grpc server
 	serverMetrics.InitializeMetrics(grpcServer)
 
@@ -0,0 +1,92 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"go.etcd.io/etcd/server/v3/auth"
+)
+
+// anonymousClient is the identity shared by the clients with neither an auth
+// user nor a client certificate.
+const anonymousClient = "anonymous"
+
+// clientIdentity returns the identity of the client of the request: its auth
+// user, or else the common name of its client certificate.
+func clientIdentity(ctx context.Context, authInfo func(ctx context.Context) (*auth.AuthInfo, error)) string {
+	if ai, err := authInfo(ctx); err == nil && ai != nil && ai.Username != "" {
+		return "user:" + ai.Username
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			for _, chain := range tlsInfo.State.VerifiedChains {
+				if len(chain) > 0 && chain[0].Subject.CommonName != "" {
+					return "cn:" + chain[0].Subject.CommonName
+				}
+			}
+		}
+	}
+	return anonymousClient
+}
+
+type clientIdentityKey struct{}
+
+// clientIdentityFromContext returns the client identity the identity
+// interceptors stored in ctx, if any.
+func clientIdentityFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(clientIdentityKey{}).(string)
+	return id, ok
+}
+
+// newIdentityUnaryInterceptor stores the client identity of the requests in
+// their context for the logs, and accounts the requests to it in the metrics.
+// The identities past the limit of identities share the metrics label of
+// otherCaller.
+func newIdentityUnaryInterceptor(identities *callerLabels, authInfo func(ctx context.Context) (*auth.AuthInfo, error)) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		id := clientIdentity(ctx, authInfo)
+		label := identities.bound(id)
+		start := time.Now()
+		resp, err := handler(context.WithValue(ctx, clientIdentityKey{}, id), req)
+		clientIdentityHandled.WithLabelValues(label, "unary", info.FullMethod, status.Code(err).String()).Inc()
+		clientIdentityHandlingSeconds.WithLabelValues(label).Observe(time.Since(start).Seconds())
+		return resp, err
+	}
+}
+
+func newIdentityStreamInterceptor(identities *callerLabels, authInfo func(ctx context.Context) (*auth.AuthInfo, error)) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		id := clientIdentity(ctx, authInfo)
+		err := handler(srv, identityServerStream{ServerStream: ss, ctx: context.WithValue(ctx, clientIdentityKey{}, id)})
+		clientIdentityHandled.WithLabelValues(identities.bound(id), "stream", info.FullMethod, status.Code(err).String()).Inc()
+		return err
+	}
+}
+
+// identityServerStream carries the client identity in its context.
+type identityServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (ss identityServerStream) Context() context.Context { return ss.ctx }
@@ -0,0 +1,58 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/server/v3/auth"
+)
+
+func TestIdentityUnaryInterceptor(t *testing.T) {
+	const method = "/etcdserverpb.KV/TestIdentity"
+	interceptor := newIdentityUnaryInterceptor(newCallerLabels(1), func(ctx context.Context) (*auth.AuthInfo, error) {
+		if user, ok := ctx.Value(userKey{}).(string); ok {
+			return &auth.AuthInfo{Username: user}, nil
+		}
+		return nil, nil
+	})
+	call := func(user string, err error) string {
+		var id string
+		interceptor(context.WithValue(t.Context(), userKey{}, user), nil, &grpc.UnaryServerInfo{FullMethod: method},
+			func(ctx context.Context, req any) (any, error) {
+				id, _ = clientIdentityFromContext(ctx)
+				return nil, err
+			})
+		return id
+	}
+	handled := func(identity, code string) float64 {
+		return testutil.ToFloat64(clientIdentityHandled.WithLabelValues(identity, "unary", method, code))
+	}
+
+	// the logs get the identity of every client, the metrics only track up
+	// to the limit of identities.
+	require.Equal(t, "user:alice", call("alice", nil))
+	require.Equal(t, "user:bob", call("bob", rpctypes.ErrGRPCPermissionDenied))
+	assert.Equal(t, 1.0, handled("user:alice", "OK"))
+	assert.Equal(t, 1.0, handled(otherCaller, "PermissionDenied"))
+	assert.Equal(t, 0.0, handled("user:bob", "PermissionDenied"))
+}
@@ -36,6 +36,7 @@ import (
 const (
 	maxNoLeaderCnt = 3
 	snapshotMethod = "/etcdserverpb.Maintenance/Snapshot"
+	watchMethod    = "/etcdserverpb.Watch/Watch"
 )
 
 type streamsMap struct {
@@ -52,6 +53,19 @@ func newUnaryInterceptor(s *etcdserver.EtcdServer) grpc.UnaryServerInterceptor {
 		if s.IsMemberExist(s.MemberID()) && s.IsLearner() && !isRPCSupportedForLearner(req) {
 			return nil, rpctypes.ErrGRPCNotSupportedForLearner
 		}
+		if s.Cfg.Witness && !isRPCSupportedForWitness(info.FullMethod) {
+			return nil, rpctypes.ErrGRPCNotSupportedForWitness
+		}
+		if !isRPCSupportedWhenQuarantined(info.FullMethod) && s.IsQuarantined() {
+			return nil, rpctypes.ErrGRPCMemberQuarantined
+		}
+
+		// do not start work that cannot finish before the client gives up on it.
+		if margin := s.Cfg.RequestDeadlineMargin; margin > 0 {
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < margin {
+				return nil, rpctypes.ErrGRPCDeadlineTooShort
+			}
+		}
 
 		md, ok := metadata.FromIncomingContext(ctx)
 		if ok {
@@ -104,6 +118,9 @@ func logUnaryRequestStats(ctx context.Context, lg *zap.Logger, warnLatency time.
 	if ok {
 		remote = peerInfo.Addr.String()
 	}
+	if id, ok := clientIdentityFromContext(ctx); ok {
+		lg = lg.With(zap.String("client-identity", id))
+	}
 	responseType := info.FullMethod
 	var reqCount, respCount int64
 	var reqSize, respSize int
@@ -220,9 +237,15 @@ func newStreamInterceptor(s *etcdserver.EtcdServer) grpc.StreamServerInterceptor
 			return rpctypes.ErrGRPCNotCapable
 		}
 
-		if s.IsMemberExist(s.MemberID()) && s.IsLearner() && info.FullMethod != snapshotMethod { // learner does not support stream RPC except Snapshot
+		if s.IsMemberExist(s.MemberID()) && s.IsLearner() && !isStreamRPCSupportedForLearner(info.FullMethod, s.IsStandby()) {
 			return rpctypes.ErrGRPCNotSupportedForLearner
 		}
+		if s.Cfg.Witness && !isRPCSupportedForWitness(info.FullMethod) {
+			return rpctypes.ErrGRPCNotSupportedForWitness
+		}
+		if !isRPCSupportedWhenQuarantined(info.FullMethod) && s.IsQuarantined() {
+			return rpctypes.ErrGRPCMemberQuarantined
+		}
 
 		md, ok := metadata.FromIncomingContext(ss.Context())
 		if ok {
@@ -17,9 +17,13 @@ package v3rpc
 
 import (
 	"context"
+	"regexp"
+
+	"github.com/coreos/go-semver/semver"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/api/v3/version"
 	"go.etcd.io/etcd/pkg/v3/adt"
 	"go.etcd.io/etcd/server/v3/etcdserver"
 )
@@ -32,10 +36,12 @@ type kvServer struct {
 	// Txn.Success can have at most 128 operations,
 	// and Txn.Failure can have at most 128 operations.
 	maxTxnOps uint
+	// clusterVersion gates the compare targets the older members don't know.
+	clusterVersion func() *semver.Version
 }
 
 func NewKVServer(s *etcdserver.EtcdServer) pb.KVServer {
-	return &kvServer{hdr: newHeader(s), kv: s, maxTxnOps: s.Cfg.MaxTxnOps}
+	return &kvServer{hdr: newHeader(s), kv: s, maxTxnOps: s.Cfg.MaxTxnOps, clusterVersion: s.ClusterVersion}
 }
 
 func (s *kvServer) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeResponse, error) {
@@ -52,6 +58,52 @@ func (s *kvServer) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeResp
 	return resp, nil
 }
 
+// rangeStreamPageSize is the number of keys sent in each response of a
+// range stream.
+const rangeStreamPageSize = 1000
+
+func (s *kvServer) RangeStream(r *pb.RangeRequest, stream pb.KV_RangeStreamServer) error {
+	if err := checkRangeRequest(r); err != nil {
+		return err
+	}
+	// the pages are read one after the other, in key order
+	if r.SortTarget != pb.RangeRequest_KEY || r.SortOrder == pb.RangeRequest_DESCEND {
+		return rpctypes.ErrGRPCInvalidSortOption
+	}
+
+	page := *r
+	for first, remaining := true, r.Limit; ; first = false {
+		page.Limit = rangeStreamPageSize
+		if remaining > 0 && remaining < page.Limit {
+			page.Limit = remaining
+		}
+		resp, err := s.kv.Range(stream.Context(), &page)
+		if err != nil {
+			return togRPCError(err)
+		}
+		s.hdr.fill(resp.Header)
+		if !first {
+			// the count of the whole range is only in the first page
+			resp.Count = 0
+		}
+		if remaining > 0 {
+			remaining -= int64(len(resp.Kvs))
+		}
+		// the last page of a truncated range keeps more set
+		done := !resp.More || len(resp.NextToken) == 0 || remaining == 0 && r.Limit > 0
+		if err = stream.Send(&pb.RangeStreamResponse{RangeResponse: resp}); err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		// the following pages are read at the revision of the first one,
+		// which this member already applied
+		page.ContinueToken = resp.NextToken
+		page.Serializable = true
+	}
+}
+
 func (s *kvServer) Put(ctx context.Context, r *pb.PutRequest) (*pb.PutResponse, error) {
 	if err := checkPutRequest(r); err != nil {
 		return nil, err
@@ -81,7 +133,7 @@ func (s *kvServer) DeleteRange(ctx context.Context, r *pb.DeleteRangeRequest) (*
 }
 
 func (s *kvServer) Txn(ctx context.Context, r *pb.TxnRequest) (*pb.TxnResponse, error) {
-	if err := checkTxnRequest(r, int(s.maxTxnOps)); err != nil {
+	if err := checkTxnRequest(r, int(s.maxTxnOps), s.clusterVersion()); err != nil {
 		return nil, err
 	}
 	// check for forbidden put/del overlaps after checking request to avoid quadratic blowup
@@ -124,6 +176,12 @@ func checkRangeRequest(r *pb.RangeRequest) error {
 		return rpctypes.ErrGRPCInvalidSortOption
 	}
 
+	if r.KeyFilterRegex {
+		if _, err := regexp.Compile(string(r.KeyFilter)); err != nil {
+			return rpctypes.ErrGRPCInvalidKeyFilter
+		}
+	}
+
 	return nil
 }
 
@@ -137,6 +195,9 @@ func checkPutRequest(r *pb.PutRequest) error {
 	if r.IgnoreLease && r.Lease != 0 {
 		return rpctypes.ErrGRPCLeaseProvided
 	}
+	if r.Ttl < 0 || (r.Ttl != 0 && (r.Lease != 0 || r.IgnoreLease)) {
+		return rpctypes.ErrGRPCInvalidTTL
+	}
 	return nil
 }
 
@@ -147,7 +208,7 @@ func checkDeleteRequest(r *pb.DeleteRangeRequest) error {
 	return nil
 }
 
-func checkTxnRequest(r *pb.TxnRequest, maxTxnOps int) error {
+func checkTxnRequest(r *pb.TxnRequest, maxTxnOps int, cv *semver.Version) error {
 	opc := len(r.Compare)
 	if opc < len(r.Success) {
 		opc = len(r.Success)
@@ -163,14 +224,20 @@ func checkTxnRequest(r *pb.TxnRequest, maxTxnOps int) error {
 		if len(c.Key) == 0 {
 			return rpctypes.ErrGRPCEmptyKey
 		}
+		// the members older than 3.7 don't know the COUNT and VALUE_PREFIX
+		// targets and compare them as equal, so they could take the other
+		// branch of the txn.
+		if (c.Target == pb.Compare_COUNT || c.Target == pb.Compare_VALUE_PREFIX) && (cv == nil || version.LessThan(*cv, version.V3_7)) {
+			return rpctypes.ErrGRPCClusterVersionTooLow
+		}
 	}
 	for _, u := range r.Success {
-		if err := checkRequestOp(u, maxTxnOps-opc); err != nil {
+		if err := checkRequestOp(u, maxTxnOps-opc, cv); err != nil {
 			return err
 		}
 	}
 	for _, u := range r.Failure {
-		if err := checkRequestOp(u, maxTxnOps-opc); err != nil {
+		if err := checkRequestOp(u, maxTxnOps-opc, cv); err != nil {
 			return err
 		}
 	}
@@ -262,7 +329,7 @@ func checkIntervals(reqs []*pb.RequestOp) (map[string]struct{}, adt.IntervalTree
 	return puts, dels, nil
 }
 
-func checkRequestOp(u *pb.RequestOp, maxTxnOps int) error {
+func checkRequestOp(u *pb.RequestOp, maxTxnOps int, cv *semver.Version) error {
 	// TODO: ensure only one of the field is set.
 	switch uv := u.Request.(type) {
 	case *pb.RequestOp_RequestRange:
@@ -272,7 +339,7 @@ func checkRequestOp(u *pb.RequestOp, maxTxnOps int) error {
 	case *pb.RequestOp_RequestDeleteRange:
 		return checkDeleteRequest(uv.RequestDeleteRange)
 	case *pb.RequestOp_RequestTxn:
-		return checkTxnRequest(uv.RequestTxn, maxTxnOps)
+		return checkTxnRequest(uv.RequestTxn, maxTxnOps, cv)
 	default:
 		// empty op / nil entry
 		return rpctypes.ErrGRPCKeyNotFound
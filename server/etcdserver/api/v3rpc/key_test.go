@@ -17,8 +17,11 @@ package v3rpc
 import (
 	"testing"
 
+	"github.com/coreos/go-semver/semver"
+
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/api/v3/version"
 )
 
 func TestCheckRangeRequest(t *testing.T) {
@@ -59,6 +62,35 @@ func TestCheckRangeRequest(t *testing.T) {
 	}
 }
 
+func TestCheckTxnRequestCompareTarget(t *testing.T) {
+	tests := []struct {
+		target pb.Compare_CompareTarget
+		nested bool
+		cv     *semver.Version
+
+		expectedError error
+	}{
+		{target: pb.Compare_VALUE, cv: &version.V3_6},
+		{target: pb.Compare_COUNT, cv: &version.V3_7},
+		{target: pb.Compare_VALUE_PREFIX, cv: &version.V3_7},
+		{target: pb.Compare_COUNT, cv: &version.V3_6, expectedError: rpctypes.ErrGRPCClusterVersionTooLow},
+		{target: pb.Compare_VALUE_PREFIX, cv: &version.V3_6, expectedError: rpctypes.ErrGRPCClusterVersionTooLow},
+		{target: pb.Compare_VALUE_PREFIX, nested: true, cv: &version.V3_6, expectedError: rpctypes.ErrGRPCClusterVersionTooLow},
+		{target: pb.Compare_COUNT, expectedError: rpctypes.ErrGRPCClusterVersionTooLow},
+	}
+
+	for i, tt := range tests {
+		r := &pb.TxnRequest{Compare: []*pb.Compare{{Key: []byte("foo"), Target: tt.target}}}
+		if tt.nested {
+			r = &pb.TxnRequest{Success: []*pb.RequestOp{{Request: &pb.RequestOp_RequestTxn{RequestTxn: r}}}}
+		}
+		err := checkTxnRequest(r, 128, tt.cv)
+		if getError(err) != getError(tt.expectedError) {
+			t.Errorf("#%d: expected %q, got %q", i, getError(tt.expectedError), getError(err))
+		}
+	}
+}
+
 func getError(err error) string {
 	if err == nil {
 		return ""
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"sync"
 
 	"go.uber.org/zap"
 
@@ -27,14 +28,26 @@ import (
 	"go.etcd.io/etcd/server/v3/lease"
 )
 
+// maxLeaseBatchInflight is the maximum number of leases of a batch request
+// granted, revoked or renewed concurrently.
+const maxLeaseBatchInflight = 64
+
+type ReadOnlyGetter interface {
+	// IsReadOnly returns whether the cluster is in read-only mode.
+	IsReadOnly() bool
+}
+
 type LeaseServer struct {
 	lg  *zap.Logger
 	hdr header
 	le  etcdserver.Lessor
+	// ro rejects the revocations of the clients in read-only mode. The
+	// expired leases are still revoked by the leader.
+	ro ReadOnlyGetter
 }
 
 func NewLeaseServer(s *etcdserver.EtcdServer) pb.LeaseServer {
-	srv := &LeaseServer{lg: s.Cfg.Logger, le: s, hdr: newHeader(s)}
+	srv := &LeaseServer{lg: s.Cfg.Logger, le: s, hdr: newHeader(s), ro: s}
 	if srv.lg == nil {
 		srv.lg = zap.NewNop()
 	}
@@ -51,10 +64,14 @@ func (ls *LeaseServer) LeaseGrant(ctx context.Context, cr *pb.LeaseGrantRequest)
 }
 
 func (ls *LeaseServer) LeaseRevoke(ctx context.Context, rr *pb.LeaseRevokeRequest) (*pb.LeaseRevokeResponse, error) {
+	if ls.ro.IsReadOnly() {
+		return nil, rpctypes.ErrGRPCReadOnly
+	}
 	resp, err := ls.le.LeaseRevoke(ctx, rr)
 	if err != nil {
 		return nil, togRPCError(err)
 	}
+	leaseRevokedByClient.Inc()
 	ls.hdr.fill(resp.Header)
 	return resp, nil
 }
@@ -90,6 +107,143 @@ func (ls *LeaseServer) LeaseLeases(ctx context.Context, rr *pb.LeaseLeasesReques
 	return resp, nil
 }
 
+func (ls *LeaseServer) LeaseGrantBatch(ctx context.Context, br *pb.LeaseGrantBatchRequest) (*pb.LeaseGrantBatchResponse, error) {
+	resp := &pb.LeaseGrantBatchResponse{Header: &pb.ResponseHeader{}, Responses: make([]*pb.LeaseGrantResponse, len(br.Requests))}
+	err := forEachLease(ctx, len(br.Requests), func(ctx context.Context, i int) error {
+		cr := br.Requests[i]
+		if cr == nil {
+			cr = &pb.LeaseGrantRequest{}
+		}
+		gr, err := ls.le.LeaseGrant(ctx, cr)
+		switch {
+		case errors.Is(err, lease.ErrLeaseExists) || errors.Is(err, lease.ErrLeaseTTLTooLarge) || errors.Is(err, lease.ErrLeaseMetadataTooLarge):
+			// the other leases of the batch are granted nonetheless.
+			gr = &pb.LeaseGrantResponse{ID: cr.ID, Error: rpctypes.ErrorDesc(togRPCError(err))}
+		case err != nil:
+			return err
+		}
+		gr.Header = nil
+		resp.Responses[i] = gr
+		return nil
+	})
+	if err != nil {
+		// the client only learns the error, so the leases granted before it
+		// would never be revoked nor kept alive.
+		ls.revokeGranted(ctx, resp.Responses)
+		return nil, togRPCError(err)
+	}
+	ls.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+// revokeGranted revokes the granted leases of a failed batch. The leases whose
+// grant failed on an error, such as a timeout, are left to expire.
+func (ls *LeaseServer) revokeGranted(ctx context.Context, grs []*pb.LeaseGrantResponse) {
+	// the revocations outlive the request whose failure they clean up after.
+	ctx = context.WithoutCancel(ctx)
+	forEachLease(ctx, len(grs), func(ctx context.Context, i int) error {
+		gr := grs[i]
+		if gr == nil || gr.Error != "" {
+			return nil
+		}
+		if _, err := ls.le.LeaseRevoke(ctx, &pb.LeaseRevokeRequest{ID: gr.ID}); err != nil && !errors.Is(err, lease.ErrLeaseNotFound) {
+			ls.lg.Warn("failed to revoke lease of failed batch grant", zap.Int64("lease-id", gr.ID), zap.Error(err))
+		}
+		return nil
+	})
+}
+
+func (ls *LeaseServer) LeaseRevokeBatch(ctx context.Context, br *pb.LeaseRevokeBatchRequest) (*pb.LeaseRevokeBatchResponse, error) {
+	if ls.ro.IsReadOnly() {
+		return nil, rpctypes.ErrGRPCReadOnly
+	}
+	notFound := make([]bool, len(br.IDs))
+	err := forEachLease(ctx, len(br.IDs), func(ctx context.Context, i int) error {
+		_, err := ls.le.LeaseRevoke(ctx, &pb.LeaseRevokeRequest{ID: br.IDs[i]})
+		if errors.Is(err, lease.ErrLeaseNotFound) {
+			notFound[i] = true
+			return nil
+		}
+		if err == nil {
+			leaseRevokedByClient.Inc()
+		}
+		return err
+	})
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	resp := &pb.LeaseRevokeBatchResponse{Header: &pb.ResponseHeader{}}
+	for i, id := range br.IDs {
+		if notFound[i] {
+			resp.NotFound = append(resp.NotFound, id)
+		}
+	}
+	ls.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+func (ls *LeaseServer) LeaseKeepAliveBatch(ctx context.Context, br *pb.LeaseKeepAliveBatchRequest) (*pb.LeaseKeepAliveBatchResponse, error) {
+	// Like for LeaseKeepAlive, create the header before renewing the leases.
+	resp := &pb.LeaseKeepAliveBatchResponse{Header: &pb.ResponseHeader{}, Responses: make([]*pb.LeaseKeepAliveResponse, len(br.IDs))}
+	ls.hdr.fill(resp.Header)
+	err := forEachLease(ctx, len(br.IDs), func(ctx context.Context, i int) error {
+		ttl, err := ls.le.LeaseRenew(ctx, lease.LeaseID(br.IDs[i]))
+		if errors.Is(err, lease.ErrLeaseNotFound) {
+			err = nil
+			ttl = 0
+		}
+		if err != nil {
+			return err
+		}
+		resp.Responses[i] = &pb.LeaseKeepAliveResponse{ID: br.IDs[i], TTL: ttl}
+		return nil
+	})
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	return resp, nil
+}
+
+// forEachLease calls f for the indexes of a batch of n leases, at most
+// maxLeaseBatchInflight at a time. It stops at the first error, which it
+// returns.
+func forEachLease(ctx context.Context, n int, f func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg   sync.WaitGroup
+		once sync.Once
+		ferr error
+	)
+	sem := make(chan struct{}, maxLeaseBatchInflight)
+	for i := 0; i < n && ctx.Err() == nil; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			continue
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := f(ctx, i); err != nil {
+				once.Do(func() {
+					ferr = err
+					cancel()
+				})
+			}
+		}(i)
+	}
+	wg.Wait()
+	if ferr != nil {
+		return ferr
+	}
+	return ctx.Err()
+}
+
 func (ls *LeaseServer) LeaseKeepAlive(stream pb.Lease_LeaseKeepAliveServer) (err error) {
 	errc := make(chan error, 1)
 	go func() {
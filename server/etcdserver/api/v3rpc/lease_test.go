@@ -0,0 +1,75 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+	"go.etcd.io/etcd/server/v3/etcdserver/errors"
+	"go.etcd.io/etcd/server/v3/lease"
+)
+
+// fakeLessor grants the leases of positive TTLs, fails the others, and
+// records the revoked leases.
+type fakeLessor struct {
+	etcdserver.Lessor
+
+	mu      sync.Mutex
+	granted map[int64]bool
+}
+
+func (l *fakeLessor) LeaseGrant(ctx context.Context, r *pb.LeaseGrantRequest) (*pb.LeaseGrantResponse, error) {
+	if r.TTL <= 0 {
+		return nil, errors.ErrNoLeader
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.granted[r.ID] = true
+	return &pb.LeaseGrantResponse{Header: &pb.ResponseHeader{}, ID: r.ID, TTL: r.TTL}, nil
+}
+
+func (l *fakeLessor) LeaseRevoke(ctx context.Context, r *pb.LeaseRevokeRequest) (*pb.LeaseRevokeResponse, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.granted[r.ID] {
+		return nil, lease.ErrLeaseNotFound
+	}
+	delete(l.granted, r.ID)
+	return &pb.LeaseRevokeResponse{}, nil
+}
+
+func TestLeaseGrantBatchRevokesOnFailure(t *testing.T) {
+	le := &fakeLessor{granted: make(map[int64]bool)}
+	ls := &LeaseServer{lg: zaptest.NewLogger(t), le: le}
+
+	var reqs []*pb.LeaseGrantRequest
+	for id := int64(1); id <= 10; id++ {
+		reqs = append(reqs, &pb.LeaseGrantRequest{ID: id, TTL: 10})
+	}
+	reqs = append(reqs, &pb.LeaseGrantRequest{ID: 11})
+	_, err := ls.LeaseGrantBatch(t.Context(), &pb.LeaseGrantBatchRequest{Requests: reqs})
+	require.ErrorIs(t, err, rpctypes.ErrGRPCNoLeader)
+	// the leases granted before the failure are revoked.
+	assert.Empty(t, le.granted)
+}
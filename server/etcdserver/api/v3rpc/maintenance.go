@@ -19,19 +19,23 @@ import (
 	"crypto/sha256"
 	errorspkg "errors"
 	"io"
+	"strconv"
 	"time"
 
 	"github.com/dustin/go-humanize"
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	"go.etcd.io/etcd/api/v3/version"
 	"go.etcd.io/etcd/server/v3/config"
 	"go.etcd.io/etcd/server/v3/etcdserver"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3compactor"
 	"go.etcd.io/etcd/server/v3/etcdserver/apply"
 	"go.etcd.io/etcd/server/v3/etcdserver/errors"
 	serverversion "go.etcd.io/etcd/server/v3/etcdserver/version"
+	"go.etcd.io/etcd/server/v3/storage"
 	"go.etcd.io/etcd/server/v3/storage/backend"
 	"go.etcd.io/etcd/server/v3/storage/mvcc"
 	"go.etcd.io/etcd/server/v3/storage/schema"
@@ -55,6 +59,39 @@ type Alarmer interface {
 
 type Downgrader interface {
 	Downgrade(ctx context.Context, dr *pb.DowngradeRequest) (*pb.DowngradeResponse, error)
+	DowngradeCheck(ctx context.Context, r *pb.DowngradeCheckRequest) (*pb.DowngradeCheckResponse, error)
+}
+
+type KeyAccessTimer interface {
+	KeyAccessTimes(ctx context.Context, r *pb.KeyAccessTimesRequest) (*pb.KeyAccessTimesResponse, error)
+}
+
+type MembershipChecker interface {
+	CheckMembership(ctx context.Context) ([]etcdserver.MembershipView, []string)
+}
+
+type EncryptionKeyRotator interface {
+	RotateEncryptionKey(ctx context.Context, r *pb.RotateEncryptionKeyRequest) (*pb.RotateEncryptionKeyResponse, error)
+}
+
+type ReadOnlyToggler interface {
+	ReadOnly(ctx context.Context, r *pb.ReadOnlyRequest) (*pb.ReadOnlyResponse, error)
+}
+
+type FollowerLagReporter interface {
+	FollowerLag(ctx context.Context) (*pb.FollowerLagResponse, error)
+}
+
+type HashKVChecker interface {
+	CheckHashKV(ctx context.Context, rev int64) (int64, []etcdserver.MemberHashKV, error)
+}
+
+type MirrorStatusReporter interface {
+	MirrorStatus(ctx context.Context) (*pb.MirrorStatusResponse, error)
+}
+
+type Compactor interface {
+	Compact(ctx context.Context, r *pb.CompactionRequest) (*pb.CompactionResponse, error)
 }
 
 type LeaderTransferrer interface {
@@ -81,6 +118,17 @@ type maintenanceServer struct {
 	d      Downgrader
 	vs     serverversion.Server
 	cg     ConfigGetter
+	kat    KeyAccessTimer
+	mc     MembershipChecker
+	ekr    EncryptionKeyRotator
+	rot    ReadOnlyToggler
+	flr    FollowerLagReporter
+	hkc    HashKVChecker
+	msr    MirrorStatusReporter
+	cp     Compactor
+
+	// snapshotLimiter limits the snapshots sent to clients.
+	snapshotLimiter *rate.Limiter
 
 	healthNotifier notifier
 }
@@ -99,6 +147,16 @@ func NewMaintenanceServer(s *etcdserver.EtcdServer, healthNotifier notifier) pb.
 		vs:             etcdserver.NewServerVersionAdapter(s),
 		healthNotifier: healthNotifier,
 		cg:             s,
+		kat:            s,
+		mc:             s,
+		ekr:            s,
+		rot:            s,
+		flr:            s,
+		hkc:            s,
+		msr:            s,
+		cp:             s,
+
+		snapshotLimiter: s.SnapshotSendLimiter(),
 	}
 	if srv.lg == nil {
 		srv.lg = zap.NewNop()
@@ -107,16 +165,127 @@ func NewMaintenanceServer(s *etcdserver.EtcdServer, healthNotifier notifier) pb.
 }
 
 func (ms *maintenanceServer) Defragment(ctx context.Context, sr *pb.DefragmentRequest) (*pb.DefragmentResponse, error) {
+	if sr.Async {
+		// the running defragmentation is followed instead of queuing another
+		if !ms.bg.Backend().DefragStatus().Active {
+			go ms.defragment()
+		}
+		return &pb.DefragmentResponse{}, nil
+	}
+	if err := ms.defragment(); err != nil {
+		return nil, togRPCError(err)
+	}
+	return &pb.DefragmentResponse{}, nil
+}
+
+func (ms *maintenanceServer) defragment() error {
 	ms.lg.Info("starting defragment")
 	ms.healthNotifier.defragStarted()
 	defer ms.healthNotifier.defragFinished()
 	err := ms.bg.Backend().Defrag()
 	if err != nil {
 		ms.lg.Warn("failed to defragment", zap.Error(err))
-		return nil, togRPCError(err)
+		return err
 	}
 	ms.lg.Info("finished defragment")
-	return &pb.DefragmentResponse{}, nil
+	return nil
+}
+
+// defragStatusInterval is how often the progress of a defragmentation is sent
+// to the clients watching it.
+const defragStatusInterval = time.Second
+
+func (ms *maintenanceServer) DefragmentStatus(r *pb.DefragmentStatusRequest, srv pb.Maintenance_DefragmentStatusServer) error {
+	ticker := time.NewTicker(defragStatusInterval)
+	defer ticker.Stop()
+	for {
+		st := ms.bg.Backend().DefragStatus()
+		resp := &pb.DefragmentStatusResponse{
+			Header:          &pb.ResponseHeader{},
+			InProgress:      st.Active,
+			PercentComplete: uint32(st.PercentComplete()),
+		}
+		if !st.Start.IsZero() {
+			resp.StartTime = st.Start.Unix()
+		}
+		if !st.Finish.IsZero() {
+			resp.FinishTime = st.Finish.Unix()
+		}
+		if st.Err != nil {
+			resp.Error = st.Err.Error()
+		}
+		ms.hdr.fill(resp.Header)
+		if err := srv.Send(resp); err != nil {
+			return togRPCError(err)
+		}
+		if !r.Watch || !st.Active {
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-srv.Context().Done():
+			return togRPCError(srv.Context().Err())
+		}
+	}
+}
+
+func (ms *maintenanceServer) Reclaim(r *pb.ReclaimRequest, srv pb.Maintenance_ReclaimServer) error {
+	sizeBefore := ms.bg.Backend().Size()
+	send := func(resp *pb.ReclaimResponse) error {
+		resp.Header = &pb.ResponseHeader{}
+		resp.DbSizeBefore = sizeBefore
+		ms.hdr.fill(resp.Header)
+		if err := srv.Send(resp); err != nil {
+			return togRPCError(err)
+		}
+		return nil
+	}
+
+	if err := send(&pb.ReclaimResponse{Phase: pb.ReclaimResponse_COMPACTING}); err != nil {
+		return err
+	}
+	if r.Revision != 0 {
+		// the physical compaction returns once the member has removed the
+		// compacted revisions from its backend
+		_, err := ms.cp.Compact(srv.Context(), &pb.CompactionRequest{Revision: r.Revision, Physical: true})
+		if err != nil && !errorspkg.Is(err, mvcc.ErrCompacted) {
+			return togRPCError(err)
+		}
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- ms.defragment() }()
+	ticker := time.NewTicker(defragStatusInterval)
+	defer ticker.Stop()
+	resp := &pb.ReclaimResponse{Phase: pb.ReclaimResponse_DEFRAGMENTING}
+	for resp != nil {
+		if err := send(resp); err != nil {
+			return err
+		}
+		select {
+		case err := <-errc:
+			if err != nil {
+				return togRPCError(err)
+			}
+			resp = nil
+		case <-ticker.C:
+			resp = &pb.ReclaimResponse{Phase: pb.ReclaimResponse_DEFRAGMENTING}
+			if st := ms.bg.Backend().DefragStatus(); st.Active {
+				resp.PercentComplete = uint32(st.PercentComplete())
+			}
+		case <-srv.Context().Done():
+			// the defragmentation goes on, and is followed with DefragmentStatus
+			return togRPCError(srv.Context().Err())
+		}
+	}
+
+	sizeAfter := ms.bg.Backend().Size()
+	return send(&pb.ReclaimResponse{
+		Phase:           pb.ReclaimResponse_DONE,
+		PercentComplete: 100,
+		DbSizeAfter:     sizeAfter,
+		ReclaimedBytes:  sizeBefore - sizeAfter,
+	})
 }
 
 // big enough size to hold >1 OS pages in the buffer
@@ -128,6 +297,10 @@ func (ms *maintenanceServer) Snapshot(sr *pb.SnapshotRequest, srv pb.Maintenance
 	if ver != nil {
 		storageVersion = ver.String()
 	}
+	// the header of the first response carries the cluster, the member and
+	// the revision the snapshot is taken at, or a revision before it.
+	hdr := &pb.ResponseHeader{}
+	ms.hdr.fill(hdr)
 	snap := ms.bg.Backend().Snapshot()
 	pr, pw := io.Pipe()
 
@@ -168,6 +341,9 @@ func (ms *maintenanceServer) Snapshot(sr *pb.SnapshotRequest, srv pb.Maintenance
 			return togRPCError(err)
 		}
 		sent += int64(n)
+		if err = ms.snapshotLimiter.WaitN(srv.Context(), n); err != nil {
+			return togRPCError(err)
+		}
 
 		// if total is x * snapshotSendBufferSize. it is possible that
 		// resp.RemainingBytes == 0
@@ -182,6 +358,9 @@ func (ms *maintenanceServer) Snapshot(sr *pb.SnapshotRequest, srv pb.Maintenance
 			Blob:           buf[:n],
 			Version:        storageVersion,
 		}
+		if hdr != nil {
+			resp.Header, hdr = hdr, nil
+		}
 		if err = srv.Send(resp); err != nil {
 			return togRPCError(err)
 		}
@@ -303,6 +482,133 @@ func (ms *maintenanceServer) Downgrade(ctx context.Context, r *pb.DowngradeReque
 	return resp, nil
 }
 
+func (ms *maintenanceServer) DowngradeCheck(ctx context.Context, r *pb.DowngradeCheckRequest) (*pb.DowngradeCheckResponse, error) {
+	resp, err := ms.d.DowngradeCheck(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	ms.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+func (ms *maintenanceServer) Config(ctx context.Context, r *pb.ConfigRequest) (*pb.ConfigResponse, error) {
+	cfg := ms.cg.Config()
+	resp := &pb.ConfigResponse{
+		Header:                  &pb.ResponseHeader{},
+		AutoCompactionMode:      cfg.AutoCompactionMode,
+		AutoCompactionRetention: cfg.AutoCompactionRetention.String(),
+		QuotaBackendBytes:       cfg.QuotaBackendBytes,
+		MaxRequestBytes:         uint64(cfg.MaxRequestBytes),
+	}
+	if cfg.AutoCompactionMode == v3compactor.ModeRevision || cfg.AutoCompactionMode == v3compactor.ModeSize {
+		// the retention is a number of revisions or bytes stored as a duration
+		resp.AutoCompactionRetention = strconv.FormatInt(int64(cfg.AutoCompactionRetention), 10)
+	} else if cfg.AutoCompactionRetention == 0 {
+		resp.AutoCompactionRetention = "0"
+	}
+	switch {
+	case cfg.QuotaBackendBytes < 0:
+		resp.QuotaBackendBytes = 0
+	case cfg.QuotaBackendBytes == 0:
+		resp.QuotaBackendBytes = storage.DefaultQuotaBytes
+	}
+	ms.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+func (ms *maintenanceServer) KeyAccessTimes(ctx context.Context, r *pb.KeyAccessTimesRequest) (*pb.KeyAccessTimesResponse, error) {
+	resp, err := ms.kat.KeyAccessTimes(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	resp.Header = &pb.ResponseHeader{}
+	ms.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+func (ms *maintenanceServer) MembershipCheck(ctx context.Context, r *pb.MembershipCheckRequest) (*pb.MembershipCheckResponse, error) {
+	views, disagreements := ms.mc.CheckMembership(ctx)
+	resp := &pb.MembershipCheckResponse{
+		Header:        &pb.ResponseHeader{},
+		Disagreements: disagreements,
+		Consistent:    len(disagreements) == 0,
+	}
+	for _, v := range views {
+		view := &pb.MembershipView{MemberId: uint64(v.MemberID), Members: membersToProtoMembers(v.Members)}
+		if v.Err != nil {
+			view.Error = v.Err.Error()
+			resp.Consistent = false
+		}
+		resp.Views = append(resp.Views, view)
+	}
+	ms.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+func (ms *maintenanceServer) RotateEncryptionKey(ctx context.Context, r *pb.RotateEncryptionKeyRequest) (*pb.RotateEncryptionKeyResponse, error) {
+	resp, err := ms.ekr.RotateEncryptionKey(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	resp.Header = &pb.ResponseHeader{}
+	ms.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+func (ms *maintenanceServer) ReadOnly(ctx context.Context, r *pb.ReadOnlyRequest) (*pb.ReadOnlyResponse, error) {
+	if _, ok := pb.ReadOnlyRequest_ReadOnlyAction_name[int32(r.Action)]; !ok {
+		return nil, rpctypes.ErrGRPCInvalidReadOnlyAction
+	}
+	resp, err := ms.rot.ReadOnly(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	ms.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+func (ms *maintenanceServer) FollowerLag(ctx context.Context, r *pb.FollowerLagRequest) (*pb.FollowerLagResponse, error) {
+	resp, err := ms.flr.FollowerLag(ctx)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	ms.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+func (ms *maintenanceServer) HashKVCheck(ctx context.Context, r *pb.HashKVCheckRequest) (*pb.HashKVCheckResponse, error) {
+	rev, hashes, err := ms.hkc.CheckHashKV(ctx, r.Revision)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	resp := &pb.HashKVCheckResponse{
+		Header:     &pb.ResponseHeader{},
+		Revision:   rev,
+		Consistent: true,
+	}
+	for _, h := range hashes {
+		mh := &pb.MemberHashKV{MemberId: uint64(h.MemberID), Hash: h.Hash.Hash, CompactRevision: h.Hash.CompactRevision}
+		if h.Err != nil {
+			mh.Error = h.Err.Error()
+			resp.Consistent = false
+		} else if mh.Hash != hashes[0].Hash.Hash || mh.CompactRevision != hashes[0].Hash.CompactRevision {
+			resp.Consistent = false
+		}
+		resp.Hashes = append(resp.Hashes, mh)
+	}
+	ms.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+func (ms *maintenanceServer) MirrorStatus(ctx context.Context, r *pb.MirrorStatusRequest) (*pb.MirrorStatusResponse, error) {
+	resp, err := ms.msr.MirrorStatus(ctx)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	ms.hdr.fill(resp.Header)
+	return resp, nil
+}
+
 type authMaintenanceServer struct {
 	*maintenanceServer
 	*AuthAdmin
@@ -362,3 +668,91 @@ func (ams *authMaintenanceServer) Downgrade(ctx context.Context, r *pb.Downgrade
 
 	return ams.maintenanceServer.Downgrade(ctx, r)
 }
+
+func (ams *authMaintenanceServer) DowngradeCheck(ctx context.Context, r *pb.DowngradeCheckRequest) (*pb.DowngradeCheckResponse, error) {
+	if err := ams.isPermitted(ctx); err != nil {
+		return nil, togRPCError(err)
+	}
+
+	return ams.maintenanceServer.DowngradeCheck(ctx, r)
+}
+
+func (ams *authMaintenanceServer) Config(ctx context.Context, r *pb.ConfigRequest) (*pb.ConfigResponse, error) {
+	if err := ams.isPermitted(ctx); err != nil {
+		return nil, togRPCError(err)
+	}
+
+	return ams.maintenanceServer.Config(ctx, r)
+}
+
+func (ams *authMaintenanceServer) KeyAccessTimes(ctx context.Context, r *pb.KeyAccessTimesRequest) (*pb.KeyAccessTimesResponse, error) {
+	if err := ams.isPermitted(ctx); err != nil {
+		return nil, togRPCError(err)
+	}
+
+	return ams.maintenanceServer.KeyAccessTimes(ctx, r)
+}
+
+func (ams *authMaintenanceServer) MembershipCheck(ctx context.Context, r *pb.MembershipCheckRequest) (*pb.MembershipCheckResponse, error) {
+	if err := ams.isPermitted(ctx); err != nil {
+		return nil, togRPCError(err)
+	}
+
+	return ams.maintenanceServer.MembershipCheck(ctx, r)
+}
+
+func (ams *authMaintenanceServer) RotateEncryptionKey(ctx context.Context, r *pb.RotateEncryptionKeyRequest) (*pb.RotateEncryptionKeyResponse, error) {
+	if err := ams.isPermitted(ctx); err != nil {
+		return nil, togRPCError(err)
+	}
+
+	return ams.maintenanceServer.RotateEncryptionKey(ctx, r)
+}
+
+func (ams *authMaintenanceServer) ReadOnly(ctx context.Context, r *pb.ReadOnlyRequest) (*pb.ReadOnlyResponse, error) {
+	if err := ams.isPermitted(ctx); err != nil {
+		return nil, togRPCError(err)
+	}
+
+	return ams.maintenanceServer.ReadOnly(ctx, r)
+}
+
+func (ams *authMaintenanceServer) FollowerLag(ctx context.Context, r *pb.FollowerLagRequest) (*pb.FollowerLagResponse, error) {
+	if err := ams.isPermitted(ctx); err != nil {
+		return nil, togRPCError(err)
+	}
+
+	return ams.maintenanceServer.FollowerLag(ctx, r)
+}
+
+func (ams *authMaintenanceServer) DefragmentStatus(r *pb.DefragmentStatusRequest, srv pb.Maintenance_DefragmentStatusServer) error {
+	if err := ams.isPermitted(srv.Context()); err != nil {
+		return togRPCError(err)
+	}
+
+	return ams.maintenanceServer.DefragmentStatus(r, srv)
+}
+
+func (ams *authMaintenanceServer) HashKVCheck(ctx context.Context, r *pb.HashKVCheckRequest) (*pb.HashKVCheckResponse, error) {
+	if err := ams.isPermitted(ctx); err != nil {
+		return nil, togRPCError(err)
+	}
+
+	return ams.maintenanceServer.HashKVCheck(ctx, r)
+}
+
+func (ams *authMaintenanceServer) MirrorStatus(ctx context.Context, r *pb.MirrorStatusRequest) (*pb.MirrorStatusResponse, error) {
+	if err := ams.isPermitted(ctx); err != nil {
+		return nil, togRPCError(err)
+	}
+
+	return ams.maintenanceServer.MirrorStatus(ctx, r)
+}
+
+func (ams *authMaintenanceServer) Reclaim(r *pb.ReclaimRequest, srv pb.Maintenance_ReclaimServer) error {
+	if err := ams.isPermitted(srv.Context()); err != nil {
+		return togRPCError(err)
+	}
+
+	return ams.maintenanceServer.Reclaim(r, srv)
+}
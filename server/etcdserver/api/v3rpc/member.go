@@ -46,9 +46,12 @@ func (cs *ClusterServer) MemberAdd(ctx context.Context, r *pb.MemberAddRequest)
 
 	now := time.Now()
 	var m *membership.Member
-	if r.IsLearner {
+	switch {
+	case r.IsStandby:
+		m = membership.NewMemberAsStandby("", urls, "", &now)
+	case r.IsLearner:
 		m = membership.NewMemberAsLearner("", urls, "", &now)
-	} else {
+	default:
 		m = membership.NewMember("", urls, "", &now)
 	}
 	membs, merr := cs.server.AddMember(ctx, *m)
@@ -62,6 +65,7 @@ func (cs *ClusterServer) MemberAdd(ctx context.Context, r *pb.MemberAddRequest)
 			ID:        uint64(m.ID),
 			PeerURLs:  m.PeerURLs,
 			IsLearner: m.IsLearner,
+			IsStandby: m.IsStandby,
 		},
 		Members: membersToProtoMembers(membs),
 	}, nil
@@ -105,6 +109,29 @@ func (cs *ClusterServer) MemberPromote(ctx context.Context, r *pb.MemberPromoteR
 	return &pb.MemberPromoteResponse{Header: cs.header(), Members: membersToProtoMembers(membs)}, nil
 }
 
+func (cs *ClusterServer) MemberReplace(ctx context.Context, r *pb.MemberReplaceRequest) (*pb.MemberReplaceResponse, error) {
+	urls, err := types.NewURLs(r.PeerURLs)
+	if err != nil {
+		return nil, rpctypes.ErrGRPCMemberBadURLs
+	}
+
+	now := time.Now()
+	m := membership.NewMemberAsLearner("", urls, "", &now)
+	membs, merr := cs.server.ReplaceMember(ctx, r.ID, *m)
+	if merr != nil {
+		return nil, togRPCError(merr)
+	}
+
+	return &pb.MemberReplaceResponse{
+		Header: cs.header(),
+		Member: &pb.Member{
+			ID:       uint64(m.ID),
+			PeerURLs: m.PeerURLs,
+		},
+		Members: membersToProtoMembers(membs),
+	}, nil
+}
+
 func (cs *ClusterServer) header() *pb.ResponseHeader {
 	return &pb.ResponseHeader{ClusterId: uint64(cs.cluster.ID()), MemberId: uint64(cs.server.MemberID()), RaftTerm: cs.server.Term()}
 }
@@ -118,6 +145,7 @@ func membersToProtoMembers(membs []*membership.Member) []*pb.Member {
 			PeerURLs:   membs[i].PeerURLs,
 			ClientURLs: membs[i].ClientURLs,
 			IsLearner:  membs[i].IsLearner,
+			IsStandby:  membs[i].IsStandby,
 		}
 	}
 	return protoMembs
@@ -52,6 +52,171 @@ var (
 		},
 		[]string{"type", "client_api_version"},
 	)
+
+	callerRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "etcd",
+			Subsystem: "server",
+			Name:      "caller_requests_total",
+			Help:      "The total number of client requests per caller label.",
+		},
+		[]string{"type", "caller"},
+	)
+
+	callerReceivedBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "etcd",
+			Subsystem: "server",
+			Name:      "caller_received_bytes_total",
+			Help:      "The total number of request bytes received from clients per caller label.",
+		},
+		[]string{"caller"},
+	)
+
+	callerSentBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "etcd",
+			Subsystem: "server",
+			Name:      "caller_sent_bytes_total",
+			Help:      "The total number of response bytes sent to clients per caller label.",
+		},
+		[]string{"caller"},
+	)
+
+	clientIdentityRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "etcd",
+			Subsystem: "server",
+			Name:      "client_identity_requests_total",
+			Help:      "The total number of client requests admitted by the rate limiter per client identity.",
+		},
+		[]string{"identity"},
+	)
+
+	clientIdentityBytes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "etcd",
+			Subsystem: "server",
+			Name:      "client_identity_bytes_total",
+			Help:      "The total number of request and response bytes charged to the rate limit per client identity.",
+		},
+		[]string{"identity"},
+	)
+
+	clientIdentityHandled = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "etcd",
+			Subsystem: "server",
+			Name:      "client_identity_handled_total",
+			Help:      "The total number of client requests completed per client identity, gRPC type, method and code.",
+		},
+		[]string{"identity", "grpc_type", "grpc_method", "grpc_code"},
+	)
+
+	clientIdentityHandlingSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "etcd",
+			Subsystem: "server",
+			Name:      "client_identity_handling_seconds",
+			Help:      "The latency distribution of the unary client requests per client identity.",
+
+			// lowest bucket start of upper bound 0.0001 sec (0.1 ms) with factor 2
+			// highest bucket start of 0.0001 sec * 2^15 == 3.2768 sec
+			Buckets: prometheus.ExponentialBuckets(0.0001, 2, 16),
+		},
+		[]string{"identity"},
+	)
+
+	clientRateLimited = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "etcd",
+			Subsystem: "server",
+			Name:      "client_rate_limited_total",
+			Help:      "The total number of client requests rejected per client identity and exceeded limit ('requests' or 'bytes').",
+		},
+		[]string{"identity", "limit"},
+	)
+
+	qosRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "etcd",
+			Subsystem: "server",
+			Name:      "qos_requests_total",
+			Help:      "The total number of client requests served by the QoS scheduler per QoS class.",
+		},
+		[]string{"class"},
+	)
+	qosQueuedRequests = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "etcd",
+			Subsystem: "server",
+			Name:      "qos_queued_requests",
+			Help:      "The number of client requests waiting in the QoS scheduler per QoS class.",
+		},
+		[]string{"class"},
+	)
+	qosDroppedRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "etcd",
+			Subsystem: "server",
+			Name:      "qos_dropped_requests_total",
+			Help:      "The total number of client requests canceled or timed out while waiting in the QoS scheduler per QoS class.",
+		},
+		[]string{"class"},
+	)
+	qosQueueDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "etcd",
+			Subsystem: "server",
+			Name:      "qos_queue_duration_seconds",
+			Help:      "The latency distributions of the wait of client requests in the QoS scheduler per QoS class.",
+
+			// lowest bucket start of upper bound 0.0001 sec (0.1 ms) with factor 2
+			// highest bucket start of 0.0001 sec * 2^15 == 3.2768 sec
+			Buckets: prometheus.ExponentialBuckets(0.0001, 2, 16),
+		},
+		[]string{"class"},
+	)
+	namespaceRequests = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "etcd",
+			Subsystem: "server",
+			Name:      "namespace_requests_total",
+			Help:      "The total number of client requests per namespace and type ('unary' or 'stream').",
+		},
+		[]string{"namespace", "type"},
+	)
+
+	leaseRevokedByClient = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "etcd_debugging",
+		Subsystem: "server",
+		Name:      "lease_revoked_by_client_total",
+		Help:      "The total number of leases revoked by client requests, unlike the expired ones.",
+	})
+
+	watchStreams = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "watch_streams",
+		Help:      "The number of watch streams served.",
+	})
+
+	watchLimitRejected = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "etcd",
+			Subsystem: "server",
+			Name:      "watch_limit_rejected_total",
+			Help:      "The total number of watch streams and watchers rejected per exceeded limit ('streams' or 'watchers').",
+		},
+		[]string{"limit"},
+	)
+
+	watchCompactionSnapshots = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "watch_compaction_snapshots_total",
+		Help:      "The total number of snapshots sent instead of the compacted events to the watchers created with snapshot_on_compaction.",
+	})
 )
 
 func init() {
@@ -59,4 +224,21 @@ func init() {
 	prometheus.MustRegister(receivedBytes)
 	prometheus.MustRegister(streamFailures)
 	prometheus.MustRegister(clientRequests)
+	prometheus.MustRegister(callerRequests)
+	prometheus.MustRegister(callerReceivedBytes)
+	prometheus.MustRegister(callerSentBytes)
+	prometheus.MustRegister(clientIdentityRequests)
+	prometheus.MustRegister(clientIdentityBytes)
+	prometheus.MustRegister(clientIdentityHandled)
+	prometheus.MustRegister(clientIdentityHandlingSeconds)
+	prometheus.MustRegister(clientRateLimited)
+	prometheus.MustRegister(qosRequests)
+	prometheus.MustRegister(qosQueuedRequests)
+	prometheus.MustRegister(qosDroppedRequests)
+	prometheus.MustRegister(qosQueueDuration)
+	prometheus.MustRegister(namespaceRequests)
+	prometheus.MustRegister(leaseRevokedByClient)
+	prometheus.MustRegister(watchStreams)
+	prometheus.MustRegister(watchLimitRejected)
+	prometheus.MustRegister(watchCompactionSnapshots)
 }
@@ -0,0 +1,289 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"bytes"
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// namespaces isolates the keys of the requests carrying a namespace in
+// their metadata under the key prefix of the namespace: the keys of the
+// requests are prefixed, the keys of the responses are stripped of the
+// prefix. The auth permissions, key quotas and usage metrics of the
+// namespace apply to its key prefix. The requests not known to keep to the
+// keys of their namespace are rejected.
+type namespaces struct {
+	// prefixes are the key prefixes of the namespaces by name.
+	prefixes map[string][]byte
+}
+
+func newNamespaces(prefixes map[string]string) *namespaces {
+	n := &namespaces{prefixes: make(map[string][]byte, len(prefixes))}
+	for name, pfx := range prefixes {
+		n.prefixes[name] = []byte(pfx)
+	}
+	return n
+}
+
+// fromContext returns the namespace of the incoming request and its key
+// prefix, an empty namespace if the request has none.
+func (n *namespaces) fromContext(ctx context.Context) (string, []byte, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", nil, nil
+	}
+	vs := md.Get(rpctypes.MetadataNamespaceKey)
+	if len(vs) == 0 || vs[0] == "" {
+		return "", nil, nil
+	}
+	pfx, ok := n.prefixes[vs[0]]
+	if !ok {
+		return "", nil, rpctypes.ErrGRPCUnknownNamespace
+	}
+	return vs[0], pfx, nil
+}
+
+func newNamespaceUnaryInterceptor(n *namespaces) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ns, pfx, err := n.fromContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ns == "" {
+			return handler(ctx, req)
+		}
+		namespaceRequests.WithLabelValues(ns, "unary").Inc()
+		if err = prefixRequest(pfx, req); err != nil {
+			return nil, err
+		}
+		resp, err := handler(ctx, req)
+		if err == nil {
+			stripResponse(pfx, resp)
+		}
+		return resp, err
+	}
+}
+
+func newNamespaceStreamInterceptor(n *namespaces) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ns, pfx, err := n.fromContext(ss.Context())
+		if err != nil {
+			return err
+		}
+		if ns == "" {
+			return handler(srv, ss)
+		}
+		if info.FullMethod == snapshotMethod {
+			// the snapshot holds the keys of all the namespaces.
+			return rpctypes.ErrGRPCNotSupportedInNamespace
+		}
+		namespaceRequests.WithLabelValues(ns, "stream").Inc()
+		return handler(srv, &namespaceServerStream{ServerStream: ss, pfx: pfx})
+	}
+}
+
+// namespaceServerStream prefixes the keys of the watchers created on a
+// watch stream and of a streamed range, and strips the keys of the events
+// and of the range pages.
+type namespaceServerStream struct {
+	grpc.ServerStream
+	pfx []byte
+}
+
+func (ss *namespaceServerStream) RecvMsg(m any) error {
+	if err := ss.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	switch r := m.(type) {
+	case *pb.WatchRequest:
+		if cr := r.GetCreateRequest(); cr != nil {
+			cr.Key, cr.RangeEnd = prefixInterval(ss.pfx, cr.Key, cr.RangeEnd)
+		}
+	default:
+		return prefixRequest(ss.pfx, r)
+	}
+	return nil
+}
+
+func (ss *namespaceServerStream) SendMsg(m any) error {
+	switch r := m.(type) {
+	case *pb.WatchResponse:
+		for i, ev := range r.Events {
+			// the events are shared by the watchers of the same keys.
+			stripped := *ev
+			stripped.Kv = stripKeyValue(ss.pfx, ev.Kv)
+			stripped.PrevKv = stripKeyValue(ss.pfx, ev.PrevKv)
+			r.Events[i] = &stripped
+		}
+	case *pb.RangeStreamResponse:
+		if r.RangeResponse != nil {
+			stripResponse(ss.pfx, r.RangeResponse)
+		}
+	}
+	return ss.ServerStream.SendMsg(m)
+}
+
+// prefixRequest moves the keys of req into the namespace of prefix pfx.
+func prefixRequest(pfx []byte, req any) error {
+	switch r := req.(type) {
+	case *pb.RangeRequest:
+		if len(r.Key) == 0 {
+			return rpctypes.ErrGRPCEmptyKey
+		}
+		r.Key, r.RangeEnd = prefixInterval(pfx, r.Key, r.RangeEnd)
+	case *pb.PutRequest:
+		if len(r.Key) == 0 {
+			return rpctypes.ErrGRPCEmptyKey
+		}
+		r.Key = prefixKey(pfx, r.Key)
+	case *pb.DeleteRangeRequest:
+		if len(r.Key) == 0 {
+			return rpctypes.ErrGRPCEmptyKey
+		}
+		r.Key, r.RangeEnd = prefixInterval(pfx, r.Key, r.RangeEnd)
+	case *pb.TxnRequest:
+		for _, c := range r.Compare {
+			c.Key, c.RangeEnd = prefixInterval(pfx, c.Key, c.RangeEnd)
+		}
+		for _, ops := range [][]*pb.RequestOp{r.Success, r.Failure} {
+			for _, op := range ops {
+				var err error
+				switch tv := op.Request.(type) {
+				case *pb.RequestOp_RequestRange:
+					err = prefixRequest(pfx, tv.RequestRange)
+				case *pb.RequestOp_RequestPut:
+					err = prefixRequest(pfx, tv.RequestPut)
+				case *pb.RequestOp_RequestDeleteRange:
+					err = prefixRequest(pfx, tv.RequestDeleteRange)
+				case *pb.RequestOp_RequestTxn:
+					err = prefixRequest(pfx, tv.RequestTxn)
+				}
+				if err != nil {
+					return err
+				}
+			}
+		}
+	case *pb.KeyAccessTimesRequest:
+		r.Key, r.RangeEnd = prefixInterval(pfx, r.Key, r.RangeEnd)
+	case *pb.LeaseGrantRequest, *pb.LeaseRevokeRequest, *pb.LeaseKeepAliveRequest,
+		*pb.LeaseTimeToLiveRequest, *pb.LeaseLeasesRequest, *pb.LeaseGrantBatchRequest,
+		*pb.LeaseRevokeBatchRequest, *pb.LeaseKeepAliveBatchRequest:
+		// the leases are shared by all the namespaces, only the keys of the
+		// namespace are visible.
+	case *pb.MemberAddRequest, *pb.MemberRemoveRequest, *pb.MemberUpdateRequest,
+		*pb.MemberListRequest, *pb.MemberPromoteRequest, *pb.MemberReplaceRequest:
+	case *pb.AlarmRequest, *pb.StatusRequest, *pb.DefragmentRequest, *pb.DefragmentStatusRequest,
+		*pb.HashRequest, *pb.HashKVRequest, *pb.HashKVCheckRequest, *pb.MoveLeaderRequest,
+		*pb.DowngradeRequest, *pb.DowngradeCheckRequest, *pb.ConfigRequest,
+		*pb.MembershipCheckRequest, *pb.RotateEncryptionKeyRequest, *pb.ReadOnlyRequest,
+		*pb.FollowerLagRequest, *pb.MirrorStatusRequest:
+		// the maintenance requests hold no keys, the hashes do not reveal them.
+	case *pb.AuthEnableRequest, *pb.AuthDisableRequest, *pb.AuthStatusRequest,
+		*pb.AuthenticateRequest, *pb.AuthUserAddRequest, *pb.AuthUserGetRequest,
+		*pb.AuthUserListRequest, *pb.AuthUserDeleteRequest, *pb.AuthUserChangePasswordRequest,
+		*pb.AuthUserGrantRoleRequest, *pb.AuthUserRevokeRoleRequest, *pb.AuthRoleAddRequest,
+		*pb.AuthRoleGetRequest, *pb.AuthRoleListRequest, *pb.AuthRoleDeleteRequest,
+		*pb.AuthRoleGrantPermissionRequest, *pb.AuthRoleRevokePermissionRequest,
+		*pb.AuthTokenRevokeRequest:
+		// the permissions are granted on the keys of all the namespaces.
+	default:
+		// the compactions and reclamations compact the revisions shared by all
+		// the namespaces, and the requests not known to keep to the keys of
+		// the namespace could reach the keys of the others.
+		return rpctypes.ErrGRPCNotSupportedInNamespace
+	}
+	return nil
+}
+
+// stripResponse moves the keys of resp out of the namespace of prefix pfx.
+func stripResponse(pfx []byte, resp any) {
+	switch r := resp.(type) {
+	case *pb.RangeResponse:
+		for i, kv := range r.Kvs {
+			r.Kvs[i] = stripKeyValue(pfx, kv)
+		}
+	case *pb.PutResponse:
+		r.PrevKv = stripKeyValue(pfx, r.PrevKv)
+	case *pb.DeleteRangeResponse:
+		for i, kv := range r.PrevKvs {
+			r.PrevKvs[i] = stripKeyValue(pfx, kv)
+		}
+	case *pb.TxnResponse:
+		for _, op := range r.Responses {
+			switch tv := op.Response.(type) {
+			case *pb.ResponseOp_ResponseRange:
+				stripResponse(pfx, tv.ResponseRange)
+			case *pb.ResponseOp_ResponsePut:
+				stripResponse(pfx, tv.ResponsePut)
+			case *pb.ResponseOp_ResponseDeleteRange:
+				stripResponse(pfx, tv.ResponseDeleteRange)
+			case *pb.ResponseOp_ResponseTxn:
+				stripResponse(pfx, tv.ResponseTxn)
+			}
+		}
+	case *pb.KeyAccessTimesResponse:
+		for _, a := range r.Keys {
+			if bytes.HasPrefix(a.Key, pfx) {
+				a.Key = a.Key[len(pfx):]
+			}
+		}
+	case *pb.LeaseTimeToLiveResponse:
+		// the leases are shared by all the namespaces, only the keys of
+		// the namespace are visible.
+		keys := r.Keys[:0]
+		for _, k := range r.Keys {
+			if bytes.HasPrefix(k, pfx) {
+				keys = append(keys, k[len(pfx):])
+			}
+		}
+		r.Keys = keys
+	}
+}
+
+func prefixKey(pfx, key []byte) []byte {
+	return append(append(make([]byte, 0, len(pfx)+len(key)), pfx...), key...)
+}
+
+// prefixInterval moves the interval [key, end) into the namespace of prefix
+// pfx, where an end of "\x00" is the end of the namespace.
+func prefixInterval(pfx, key, end []byte) ([]byte, []byte) {
+	switch {
+	case len(end) == 0:
+		return prefixKey(pfx, key), end
+	case len(end) == 1 && end[0] == 0:
+		return prefixKey(pfx, key), []byte(clientv3.GetPrefixRangeEnd(string(pfx)))
+	}
+	return prefixKey(pfx, key), prefixKey(pfx, end)
+}
+
+// stripKeyValue returns a copy of kv with its key stripped of the prefix
+// pfx, which kv may share with other responses.
+func stripKeyValue(pfx []byte, kv *mvccpb.KeyValue) *mvccpb.KeyValue {
+	if kv == nil || !bytes.HasPrefix(kv.Key, pfx) {
+		return kv
+	}
+	stripped := *kv
+	stripped.Key = kv.Key[len(pfx):]
+	return &stripped
+}
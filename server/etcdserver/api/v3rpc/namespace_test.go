@@ -0,0 +1,127 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+)
+
+func TestPrefixInterval(t *testing.T) {
+	pfx := []byte("/ns/a/")
+	tests := []struct {
+		key, end         string
+		wantKey, wantEnd string
+	}{
+		{key: "k", wantKey: "/ns/a/k"},
+		{key: "k", end: "l", wantKey: "/ns/a/k", wantEnd: "/ns/a/l"},
+		{key: "k", end: "\x00", wantKey: "/ns/a/k", wantEnd: "/ns/a0"},
+		{key: "\x00", end: "\x00", wantKey: "/ns/a/\x00", wantEnd: "/ns/a0"},
+	}
+	for _, tt := range tests {
+		key, end := prefixInterval(pfx, []byte(tt.key), []byte(tt.end))
+		assert.Equal(t, tt.wantKey, string(key))
+		assert.Equal(t, tt.wantEnd, string(end))
+	}
+}
+
+func TestNamespaceUnaryInterceptor(t *testing.T) {
+	n := newNamespaces(map[string]string{"a": "/ns/a/"})
+	intercept := newNamespaceUnaryInterceptor(n)
+	info := &grpc.UnaryServerInfo{FullMethod: "/etcdserverpb.KV/Txn"}
+	nsCtx := func(ns string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(rpctypes.MetadataNamespaceKey, ns))
+	}
+
+	req := &pb.TxnRequest{
+		Compare: []*pb.Compare{{Key: []byte("x")}},
+		Success: []*pb.RequestOp{
+			{Request: &pb.RequestOp_RequestPut{RequestPut: &pb.PutRequest{Key: []byte("x")}}},
+			{Request: &pb.RequestOp_RequestRange{RequestRange: &pb.RangeRequest{Key: []byte("y"), RangeEnd: []byte{0}}}},
+		},
+	}
+	var served *pb.TxnRequest
+	handler := func(_ context.Context, req any) (any, error) {
+		served = req.(*pb.TxnRequest)
+		return &pb.TxnResponse{Responses: []*pb.ResponseOp{
+			{Response: &pb.ResponseOp_ResponsePut{ResponsePut: &pb.PutResponse{PrevKv: &mvccpb.KeyValue{Key: []byte("/ns/a/x")}}}},
+			{Response: &pb.ResponseOp_ResponseRange{ResponseRange: &pb.RangeResponse{Kvs: []*mvccpb.KeyValue{{Key: []byte("/ns/a/y")}}}}},
+		}}, nil
+	}
+	resp, err := intercept(nsCtx("a"), req, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "/ns/a/x", string(served.Compare[0].Key))
+	assert.Equal(t, "/ns/a/x", string(served.Success[0].GetRequestPut().Key))
+	assert.Equal(t, "/ns/a/y", string(served.Success[1].GetRequestRange().Key))
+	assert.Equal(t, "/ns/a0", string(served.Success[1].GetRequestRange().RangeEnd))
+	txnResp := resp.(*pb.TxnResponse)
+	assert.Equal(t, "x", string(txnResp.Responses[0].GetResponsePut().PrevKv.Key))
+	assert.Equal(t, "y", string(txnResp.Responses[1].GetResponseRange().Kvs[0].Key))
+
+	// the requests without a namespace are served as they are.
+	put := &pb.PutRequest{Key: []byte("x")}
+	_, err = intercept(context.Background(), put, info, func(context.Context, any) (any, error) { return &pb.PutResponse{}, nil })
+	require.NoError(t, err)
+	assert.Equal(t, "x", string(put.Key))
+
+	_, err = intercept(nsCtx("b"), put, info, handler)
+	require.ErrorIs(t, err, rpctypes.ErrGRPCUnknownNamespace)
+	_, err = intercept(nsCtx("a"), &pb.CompactionRequest{Revision: 1}, info, handler)
+	require.ErrorIs(t, err, rpctypes.ErrGRPCNotSupportedInNamespace)
+
+	// the requests not known to keep to the keys of the namespace are rejected,
+	// the ones holding no keys are served.
+	_, err = intercept(nsCtx("a"), &pb.ReclaimRequest{Revision: 1}, info, handler)
+	require.ErrorIs(t, err, rpctypes.ErrGRPCNotSupportedInNamespace)
+	_, err = intercept(nsCtx("a"), struct{}{}, info, handler)
+	require.ErrorIs(t, err, rpctypes.ErrGRPCNotSupportedInNamespace)
+	_, err = intercept(nsCtx("a"), &pb.StatusRequest{}, info, func(context.Context, any) (any, error) { return &pb.StatusResponse{}, nil })
+	require.NoError(t, err)
+}
+
+func TestNamespaceKeyAccessTimes(t *testing.T) {
+	intercept := newNamespaceUnaryInterceptor(newNamespaces(map[string]string{"a": "/ns/a/"}))
+	info := &grpc.UnaryServerInfo{FullMethod: "/etcdserverpb.Maintenance/KeyAccessTimes"}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(rpctypes.MetadataNamespaceKey, "a"))
+
+	req := &pb.KeyAccessTimesRequest{Key: []byte("x"), RangeEnd: []byte{0}}
+	var served *pb.KeyAccessTimesRequest
+	handler := func(_ context.Context, req any) (any, error) {
+		served = req.(*pb.KeyAccessTimesRequest)
+		return &pb.KeyAccessTimesResponse{Keys: []*pb.KeyAccess{{Key: []byte("/ns/a/x")}, {Key: []byte("/ns/a/y")}}}, nil
+	}
+	resp, err := intercept(ctx, req, info, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "/ns/a/x", string(served.Key))
+	assert.Equal(t, "/ns/a0", string(served.RangeEnd))
+	keys := resp.(*pb.KeyAccessTimesResponse).Keys
+	assert.Equal(t, "x", string(keys[0].Key))
+	assert.Equal(t, "y", string(keys[1].Key))
+}
+
+func TestStripResponseLeaseKeys(t *testing.T) {
+	resp := &pb.LeaseTimeToLiveResponse{Keys: [][]byte{[]byte("/ns/a/x"), []byte("/ns/b/y"), []byte("/ns/a/z")}}
+	stripResponse([]byte("/ns/a/"), resp)
+	assert.Equal(t, [][]byte{[]byte("x"), []byte("z")}, resp.Keys)
+}
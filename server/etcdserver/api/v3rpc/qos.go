@@ -0,0 +1,178 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+)
+
+// qosClasses are the QoS classes of the requests, with the weight of their
+// share of the requests served while requests of several classes wait.
+var qosClasses = []struct {
+	name   string
+	weight int
+}{
+	{rpctypes.PriorityCritical, 8},
+	{rpctypes.PriorityNormal, 4},
+	{rpctypes.PriorityBackground, 1},
+}
+
+// qosNormal is the index of the class of the requests without a valid
+// priority.
+const qosNormal = 1
+
+// qosClass returns the index of the QoS class of the incoming request.
+func qosClass(ctx context.Context) int {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if ps := md.Get(rpctypes.MetadataPriorityKey); len(ps) > 0 {
+			for i, c := range qosClasses {
+				if ps[0] == c.name {
+					return i
+				}
+			}
+		}
+	}
+	return qosNormal
+}
+
+// qosScheduler bounds the requests served at a time. The requests over the
+// bound wait in a queue per QoS class, and are served by smooth weighted
+// round robin across the classes, so that the requests of a class with a
+// higher weight are not starved by those of a lower one.
+type qosScheduler struct {
+	maxInflight int
+
+	mu       sync.Mutex
+	inflight int
+	queues   [][]*qosWaiter
+	// current is the current weight of each class in the smooth weighted
+	// round robin.
+	current []int
+}
+
+type qosWaiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+func newQoSScheduler(maxInflight int) *qosScheduler {
+	return &qosScheduler{
+		maxInflight: maxInflight,
+		queues:      make([][]*qosWaiter, len(qosClasses)),
+		current:     make([]int, len(qosClasses)),
+	}
+}
+
+// acquire waits until a request of the class may be served, or ctx is done.
+// Each successful acquire must be followed by a release.
+func (qs *qosScheduler) acquire(ctx context.Context, class int) error {
+	qs.mu.Lock()
+	if qs.inflight < qs.maxInflight {
+		qs.inflight++
+		qs.mu.Unlock()
+		return nil
+	}
+	w := &qosWaiter{ready: make(chan struct{})}
+	qs.queues[class] = append(qs.queues[class], w)
+	qs.mu.Unlock()
+
+	qosQueuedRequests.WithLabelValues(qosClasses[class].name).Inc()
+	defer qosQueuedRequests.WithLabelValues(qosClasses[class].name).Dec()
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	qs.mu.Lock()
+	if w.granted {
+		// granted while giving up; hand the request slot over
+		qs.mu.Unlock()
+		qs.release()
+	} else {
+		q := qs.queues[class]
+		for i := range q {
+			if q[i] == w {
+				qs.queues[class] = append(q[:i], q[i+1:]...)
+				break
+			}
+		}
+		qs.mu.Unlock()
+	}
+	qosDroppedRequests.WithLabelValues(qosClasses[class].name).Inc()
+	return ctx.Err()
+}
+
+// release ends a request, and hands its slot over to the next waiting
+// request, if any.
+func (qs *qosScheduler) release() {
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+	class := qs.next()
+	if class < 0 {
+		qs.inflight--
+		return
+	}
+	w := qs.queues[class][0]
+	qs.queues[class] = qs.queues[class][1:]
+	w.granted = true
+	close(w.ready)
+}
+
+// next returns the class of the next request to serve, or -1 if no request
+// waits. It must be called with mu held.
+func (qs *qosScheduler) next() int {
+	total, best := 0, -1
+	for i, c := range qosClasses {
+		if len(qs.queues[i]) == 0 {
+			continue
+		}
+		qs.current[i] += c.weight
+		total += c.weight
+		if best < 0 || qs.current[i] > qs.current[best] {
+			best = i
+		}
+	}
+	if best >= 0 {
+		qs.current[best] -= total
+	}
+	return best
+}
+
+// newQoSUnaryInterceptor serves the unary requests through the scheduler,
+// according to the priority in their metadata.
+func newQoSUnaryInterceptor(qs *qosScheduler) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if isRateLimitExempt(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		class := qosClass(ctx)
+		start := time.Now()
+		if err := qs.acquire(ctx, class); err != nil {
+			return nil, togRPCError(err)
+		}
+		defer qs.release()
+		qosQueueDuration.WithLabelValues(qosClasses[class].name).Observe(time.Since(start).Seconds())
+		qosRequests.WithLabelValues(qosClasses[class].name).Inc()
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+)
+
+func TestQoSClass(t *testing.T) {
+	withPriority := func(p string) context.Context {
+		return metadata.NewIncomingContext(t.Context(), metadata.Pairs(rpctypes.MetadataPriorityKey, p))
+	}
+	assert.Equal(t, qosNormal, qosClass(t.Context()))
+	assert.Equal(t, 0, qosClass(withPriority(rpctypes.PriorityCritical)))
+	assert.Equal(t, qosNormal, qosClass(withPriority(rpctypes.PriorityNormal)))
+	assert.Equal(t, 2, qosClass(withPriority(rpctypes.PriorityBackground)))
+	assert.Equal(t, qosNormal, qosClass(withPriority("urgent")))
+}
+
+func TestQoSSchedulerWeights(t *testing.T) {
+	qs := newQoSScheduler(1)
+	require.NoError(t, qs.acquire(t.Context(), qosNormal))
+	for i := range qs.queues {
+		for j := 0; j < 20; j++ {
+			qs.queues[i] = append(qs.queues[i], &qosWaiter{ready: make(chan struct{})})
+		}
+	}
+
+	// while all the classes wait, they are served in shares of their
+	// weights.
+	for i := 0; i < 26; i++ {
+		qs.release()
+	}
+	assert.Len(t, qs.queues[0], 20-16)
+	assert.Len(t, qs.queues[1], 20-8)
+	assert.Len(t, qs.queues[2], 20-2)
+
+	// the request slot is freed once no request waits.
+	for i := 0; i < 4+12+18; i++ {
+		qs.release()
+	}
+	assert.Empty(t, qs.queues[2])
+	assert.Equal(t, 1, qs.inflight)
+	qs.release()
+	assert.Equal(t, 0, qs.inflight)
+}
+
+func TestQoSSchedulerCancel(t *testing.T) {
+	qs := newQoSScheduler(1)
+	require.NoError(t, qs.acquire(t.Context(), qosNormal))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, qs.acquire(ctx, 0), context.DeadlineExceeded)
+	assert.Empty(t, qs.queues[0])
+
+	// a waiting request is served once a request ends.
+	done := make(chan error)
+	go func() { done <- qs.acquire(t.Context(), 2) }()
+	require.Eventually(t, func() bool {
+		qs.mu.Lock()
+		defer qs.mu.Unlock()
+		return len(qs.queues[2]) == 1
+	}, time.Second, time.Millisecond)
+	qs.release()
+	require.NoError(t, <-done)
+	qs.release()
+	assert.Equal(t, 0, qs.inflight)
+}
@@ -33,6 +33,9 @@ type quotaAlarmer struct {
 	q  storage.Quota
 	a  Alarmer
 	id types.ID
+	// grace rejects the request without raising the alarm, see
+	// storage.NewBackendQuota.
+	grace bool
 }
 
 // check whether request satisfies the quota. If there is not enough space,
@@ -41,6 +44,9 @@ func (qa *quotaAlarmer) check(ctx context.Context, r any) error {
 	if qa.q.Available(r) {
 		return nil
 	}
+	if qa.grace {
+		return rpctypes.ErrGRPCNoSpace
+	}
 	req := &pb.AlarmRequest{
 		MemberID: uint64(qa.id),
 		Action:   pb.AlarmRequest_ACTIVATE,
@@ -53,7 +59,7 @@ func (qa *quotaAlarmer) check(ctx context.Context, r any) error {
 func NewQuotaKVServer(s *etcdserver.EtcdServer) pb.KVServer {
 	return &quotaKVServer{
 		NewKVServer(s),
-		quotaAlarmer{newBackendQuota(s, "kv"), s, s.MemberID()},
+		quotaAlarmer{newBackendQuota(s, "kv"), s, s.MemberID(), s.Cfg.QuotaBackendGrace},
 	}
 }
 
@@ -83,13 +89,20 @@ func (s *quotaLeaseServer) LeaseGrant(ctx context.Context, cr *pb.LeaseGrantRequ
 	return s.LeaseServer.LeaseGrant(ctx, cr)
 }
 
+func (s *quotaLeaseServer) LeaseGrantBatch(ctx context.Context, br *pb.LeaseGrantBatchRequest) (*pb.LeaseGrantBatchResponse, error) {
+	if err := s.qa.check(ctx, br); err != nil {
+		return nil, err
+	}
+	return s.LeaseServer.LeaseGrantBatch(ctx, br)
+}
+
 func NewQuotaLeaseServer(s *etcdserver.EtcdServer) pb.LeaseServer {
 	return &quotaLeaseServer{
 		NewLeaseServer(s),
-		quotaAlarmer{newBackendQuota(s, "lease"), s, s.MemberID()},
+		quotaAlarmer{newBackendQuota(s, "lease"), s, s.MemberID(), s.Cfg.QuotaBackendGrace},
 	}
 }
 
 func newBackendQuota(s *etcdserver.EtcdServer, name string) storage.Quota {
-	return storage.NewBackendQuota(s.Logger(), s.Cfg.QuotaBackendBytes, s.Backend(), name)
+	return storage.NewBackendQuota(s.Logger(), s.Cfg.QuotaBackendBytes, s.Cfg.QuotaBackendGrace, s.Backend(), name)
 }
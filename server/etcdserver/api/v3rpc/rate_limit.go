@@ -0,0 +1,233 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/server/v3/auth"
+)
+
+const (
+	rateLimitRequests = "requests"
+	rateLimitBytes    = "bytes"
+
+	// clientLimiterIdleTTL is the time after which the limiter of a client
+	// sending no request is dropped.
+	clientLimiterIdleTTL = 10 * time.Minute
+)
+
+// clientRateLimiter bounds the requests per second and the request and
+// response bytes per second of each client identity: its auth user, or else
+// the common name of its client certificate.
+type clientRateLimiter struct {
+	qps, burst float64
+	bytes      float64
+	identities *callerLabels
+	authInfo   func(ctx context.Context) (*auth.AuthInfo, error)
+	now        func() time.Time
+
+	mu        sync.Mutex
+	limiters  map[string]*clientLimiter
+	lastSweep time.Time
+}
+
+type clientLimiter struct {
+	requests, bytes tokenBucket
+	lastSeen        time.Time
+}
+
+// tokenBucket holds tokens refilled at a rate up to a burst. It may go into
+// debt when charged after the fact, e.g. for the bytes of a response.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) refill(now time.Time, rate, burst float64) {
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+}
+
+// newClientRateLimiter returns a limiter of qps requests per second, in
+// bursts of up to burst requests, and of bytes bytes per second; a zero
+// limit is no limit. The identities past maxIdentities share the metrics
+// label of otherCaller.
+func newClientRateLimiter(qps float64, burst int, bytes int64, maxIdentities int, authInfo func(ctx context.Context) (*auth.AuthInfo, error)) *clientRateLimiter {
+	rl := &clientRateLimiter{
+		qps:        qps,
+		burst:      float64(burst),
+		bytes:      float64(bytes),
+		identities: newCallerLabels(maxIdentities),
+		authInfo:   authInfo,
+		now:        time.Now,
+		limiters:   make(map[string]*clientLimiter),
+	}
+	if rl.burst < rl.qps {
+		rl.burst = math.Ceil(rl.qps)
+	}
+	return rl
+}
+
+// identity returns the identity of the client of the request.
+func (rl *clientRateLimiter) identity(ctx context.Context) string {
+	if id, ok := clientIdentityFromContext(ctx); ok {
+		return id
+	}
+	return clientIdentity(ctx, rl.authInfo)
+}
+
+// admit charges a request of n bytes to the client, and returns
+// ErrGRPCRateLimited if the client exceeded one of its limits.
+func (rl *clientRateLimiter) admit(id string, n int) error {
+	label := rl.identities.bound(id)
+	now := rl.now()
+
+	rl.mu.Lock()
+	l := rl.limiter(id, now)
+	limit := ""
+	if rl.qps > 0 {
+		l.requests.refill(now, rl.qps, rl.burst)
+		if l.requests.tokens < 1 {
+			limit = rateLimitRequests
+		}
+	}
+	if rl.bytes > 0 && limit == "" {
+		l.bytes.refill(now, rl.bytes, rl.bytes)
+		if l.bytes.tokens <= 0 {
+			limit = rateLimitBytes
+		}
+	}
+	if limit == "" {
+		l.requests.tokens--
+		l.bytes.tokens -= float64(n)
+	}
+	rl.mu.Unlock()
+
+	if limit != "" {
+		clientRateLimited.WithLabelValues(label, limit).Inc()
+		return rpctypes.ErrGRPCRateLimited
+	}
+	clientIdentityRequests.WithLabelValues(label).Inc()
+	clientIdentityBytes.WithLabelValues(label).Add(float64(n))
+	return nil
+}
+
+// charge charges n more bytes to the client, once they are sent or received.
+func (rl *clientRateLimiter) charge(id string, n int) {
+	if n == 0 {
+		return
+	}
+	label := rl.identities.bound(id)
+	now := rl.now()
+	rl.mu.Lock()
+	l := rl.limiter(id, now)
+	l.bytes.refill(now, rl.bytes, rl.bytes)
+	l.bytes.tokens -= float64(n)
+	rl.mu.Unlock()
+	clientIdentityBytes.WithLabelValues(label).Add(float64(n))
+}
+
+// limiter returns the limiter of the client id, and drops the limiters idle
+// for clientLimiterIdleTTL. It must be called with mu held.
+func (rl *clientRateLimiter) limiter(id string, now time.Time) *clientLimiter {
+	if now.Sub(rl.lastSweep) > clientLimiterIdleTTL {
+		for k, l := range rl.limiters {
+			if now.Sub(l.lastSeen) > clientLimiterIdleTTL {
+				delete(rl.limiters, k)
+			}
+		}
+		rl.lastSweep = now
+	}
+	l, ok := rl.limiters[id]
+	if !ok {
+		l = &clientLimiter{
+			requests: tokenBucket{tokens: rl.burst, last: now},
+			bytes:    tokenBucket{tokens: rl.bytes, last: now},
+		}
+		rl.limiters[id] = l
+	}
+	l.lastSeen = now
+	return l
+}
+
+// isRateLimitExempt reports whether the requests of the method are let
+// through regardless of the limits, so that a limited client does not get the
+// member reported unhealthy.
+func isRateLimitExempt(method string) bool {
+	return strings.HasPrefix(method, "/grpc.health.v1.Health/")
+}
+
+func newRateLimitUnaryInterceptor(rl *clientRateLimiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if isRateLimitExempt(info.FullMethod) {
+			return handler(ctx, req)
+		}
+		id := rl.identity(ctx)
+		if err := rl.admit(id, messageSize(req)); err != nil {
+			return nil, err
+		}
+		resp, err := handler(ctx, req)
+		if err == nil {
+			rl.charge(id, messageSize(resp))
+		}
+		return resp, err
+	}
+}
+
+// newRateLimitStreamInterceptor admits the streams as requests, and charges
+// the bytes of their messages to the client, so that a client streaming over
+// its limit gets its next requests rejected.
+func newRateLimitStreamInterceptor(rl *clientRateLimiter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if isRateLimitExempt(info.FullMethod) {
+			return handler(srv, ss)
+		}
+		id := rl.identity(ss.Context())
+		if err := rl.admit(id, 0); err != nil {
+			return err
+		}
+		return handler(srv, &rateLimitServerStream{ServerStream: ss, rl: rl, id: id})
+	}
+}
+
+type rateLimitServerStream struct {
+	grpc.ServerStream
+	rl *clientRateLimiter
+	id string
+}
+
+func (ss *rateLimitServerStream) SendMsg(m any) error {
+	err := ss.ServerStream.SendMsg(m)
+	if err == nil {
+		ss.rl.charge(ss.id, messageSize(m))
+	}
+	return err
+}
+
+func (ss *rateLimitServerStream) RecvMsg(m any) error {
+	err := ss.ServerStream.RecvMsg(m)
+	if err == nil {
+		ss.rl.charge(ss.id, messageSize(m))
+	}
+	return err
+}
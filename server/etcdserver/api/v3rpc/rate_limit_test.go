@@ -0,0 +1,82 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/server/v3/auth"
+)
+
+type userKey struct{}
+
+func TestClientRateLimiterIdentity(t *testing.T) {
+	rl := newClientRateLimiter(1, 0, 0, 8, func(ctx context.Context) (*auth.AuthInfo, error) {
+		if user, ok := ctx.Value(userKey{}).(string); ok {
+			return &auth.AuthInfo{Username: user}, nil
+		}
+		return nil, nil
+	})
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: "app"}}
+	ctx := peer.NewContext(t.Context(), &peer.Peer{AuthInfo: credentials.TLSInfo{
+		State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}},
+	}})
+
+	assert.Equal(t, anonymousClient, rl.identity(t.Context()))
+	assert.Equal(t, "cn:app", rl.identity(ctx))
+	assert.Equal(t, "user:alice", rl.identity(context.WithValue(ctx, userKey{}, "alice")))
+}
+
+func TestClientRateLimiter(t *testing.T) {
+	rl := newClientRateLimiter(2, 3, 100, 8, func(context.Context) (*auth.AuthInfo, error) { return nil, nil })
+	now := time.Unix(0, 0)
+	rl.now = func() time.Time { return now }
+
+	// a client may send its burst of requests, and then qps requests per
+	// second, independently of the other clients.
+	for i := 0; i < 3; i++ {
+		require.NoError(t, rl.admit("a", 0))
+	}
+	require.ErrorIs(t, rl.admit("a", 0), rpctypes.ErrGRPCRateLimited)
+	require.NoError(t, rl.admit("b", 0))
+	now = now.Add(500 * time.Millisecond)
+	require.NoError(t, rl.admit("a", 0))
+	require.ErrorIs(t, rl.admit("a", 0), rpctypes.ErrGRPCRateLimited)
+
+	// the bytes of a response put the client into debt, until repaid.
+	now = now.Add(time.Second)
+	rl.charge("a", 200)
+	require.ErrorIs(t, rl.admit("a", 0), rpctypes.ErrGRPCRateLimited)
+	now = now.Add(time.Second)
+	require.ErrorIs(t, rl.admit("a", 0), rpctypes.ErrGRPCRateLimited)
+	now = now.Add(1100 * time.Millisecond)
+	require.NoError(t, rl.admit("a", 50))
+
+	// the limiters of the idle clients are dropped.
+	now = now.Add(2 * clientLimiterIdleTTL)
+	require.NoError(t, rl.admit("a", 0))
+	assert.Len(t, rl.limiters, 1)
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+)
+
+// newSlowRequestUnaryInterceptor logs the latency breakdown of the requests
+// slower than threshold. The logs are sampled so that an incident slowing
+// down every request does not flood the log.
+func newSlowRequestUnaryInterceptor(lg *zap.Logger, threshold time.Duration, initial, thereafter int) grpc.UnaryServerInterceptor {
+	lg = lg.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewSamplerWithOptions(core, time.Second, initial, thereafter)
+	}))
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		ctx, latency := etcdserver.WithRequestLatency(ctx)
+		resp, err := handler(ctx, req)
+		if took := time.Since(start); took > threshold {
+			logSlowRequest(ctx, lg, info.FullMethod, took, latency, req, err)
+		}
+		return resp, err
+	}
+}
+
+func logSlowRequest(ctx context.Context, lg *zap.Logger, method string, took time.Duration, latency *etcdserver.RequestLatency, req any, err error) {
+	remote := ""
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		remote = p.Addr.String()
+	}
+	fields := []zap.Field{
+		zap.String("rpc", method),
+		zap.String("remote", remote),
+		zap.Duration("took", took),
+		zap.Duration("queue", latency.Queue),
+		zap.Duration("raft", latency.Raft),
+		zap.Duration("apply", latency.Apply),
+		zap.Duration("backend", latency.Backend),
+		zap.String("request", loggableRequest(req)),
+	}
+	if id, ok := clientIdentityFromContext(ctx); ok {
+		fields = append(fields, zap.String("client-identity", id))
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	lg.Warn("slow request", fields...)
+}
+
+// loggableRequest describes the request with the values redacted.
+func loggableRequest(req any) string {
+	switch r := req.(type) {
+	case *pb.PutRequest:
+		return pb.NewLoggablePutRequest(r).String()
+	case *pb.TxnRequest:
+		return pb.NewLoggableTxnRequest(r).String()
+	case *pb.AuthUserAddRequest, *pb.AuthUserChangePasswordRequest, *pb.AuthenticateRequest,
+		*pb.AuthTokenRevokeRequest:
+		// never log passwords nor tokens
+		return ""
+	case fmt.Stringer:
+		return r.String()
+	}
+	return ""
+}
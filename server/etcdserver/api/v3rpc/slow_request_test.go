@@ -0,0 +1,74 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+func TestSlowRequestUnaryInterceptor(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	interceptor := newSlowRequestUnaryInterceptor(zap.New(core), 10*time.Millisecond, 2, 3)
+	info := &grpc.UnaryServerInfo{FullMethod: "/etcdserverpb.KV/Put"}
+	req := &pb.PutRequest{Key: []byte("foo"), Value: []byte("secret")}
+
+	fast := func(ctx context.Context, req any) (any, error) { return &pb.PutResponse{}, nil }
+	slow := func(ctx context.Context, req any) (any, error) {
+		time.Sleep(20 * time.Millisecond)
+		return &pb.PutResponse{}, nil
+	}
+
+	_, err := interceptor(context.Background(), req, info, fast)
+	require.NoError(t, err)
+	assert.Equal(t, 0, logs.Len())
+
+	// the first 2 slow requests are logged, then every 3rd one
+	for i := 0; i < 6; i++ {
+		_, err = interceptor(context.Background(), req, info, slow)
+		require.NoError(t, err)
+	}
+	entries := logs.All()
+	require.Len(t, entries, 3)
+
+	f := entries[0].ContextMap()
+	assert.Equal(t, "slow request", entries[0].Message)
+	assert.Equal(t, "/etcdserverpb.KV/Put", f["rpc"])
+	assert.GreaterOrEqual(t, f["took"], 20*time.Millisecond)
+	for _, k := range []string{"queue", "raft", "apply", "backend"} {
+		assert.Contains(t, f, k)
+	}
+	assert.Contains(t, f["request"], "foo")
+	assert.False(t, strings.Contains(f["request"].(string), "secret"))
+}
+
+func TestLoggableRequest(t *testing.T) {
+	assert.Empty(t, loggableRequest(&pb.AuthUserAddRequest{Name: "foo", Password: "secret"}))
+	assert.Empty(t, loggableRequest(&pb.AuthenticateRequest{Name: "foo", Password: "secret"}))
+	assert.Empty(t, loggableRequest(&pb.AuthTokenRevokeRequest{Token: "secret"}))
+	assert.Contains(t, loggableRequest(&pb.RangeRequest{Key: []byte("foo")}), "foo")
+	txn := &pb.TxnRequest{Success: []*pb.RequestOp{{Request: &pb.RequestOp_RequestPut{RequestPut: &pb.PutRequest{Key: []byte("foo"), Value: []byte("secret")}}}}}
+	assert.NotContains(t, loggableRequest(txn), "secret")
+}
@@ -27,6 +27,7 @@ import (
 	"go.etcd.io/etcd/server/v3/auth"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
 	"go.etcd.io/etcd/server/v3/etcdserver/errors"
+	"go.etcd.io/etcd/server/v3/etcdserver/txn"
 	"go.etcd.io/etcd/server/v3/etcdserver/version"
 	"go.etcd.io/etcd/server/v3/lease"
 	"go.etcd.io/etcd/server/v3/storage/mvcc"
@@ -39,14 +40,19 @@ var toGRPCErrorMap = map[error]error{
 	membership.ErrPeerURLexists:       rpctypes.ErrGRPCPeerURLExist,
 	membership.ErrMemberNotLearner:    rpctypes.ErrGRPCMemberNotLearner,
 	membership.ErrTooManyLearners:     rpctypes.ErrGRPCTooManyLearners,
+	membership.ErrMemberStandby:       rpctypes.ErrGRPCMemberStandby,
 	errors.ErrNotEnoughStartedMembers: rpctypes.ErrMemberNotEnoughStarted,
 	errors.ErrLearnerNotReady:         rpctypes.ErrGRPCLearnerNotReady,
 
-	mvcc.ErrCompacted:         rpctypes.ErrGRPCCompacted,
-	mvcc.ErrFutureRev:         rpctypes.ErrGRPCFutureRev,
-	errors.ErrRequestTooLarge: rpctypes.ErrGRPCRequestTooLarge,
-	errors.ErrNoSpace:         rpctypes.ErrGRPCNoSpace,
-	errors.ErrTooManyRequests: rpctypes.ErrTooManyRequests,
+	mvcc.ErrCompacted:           rpctypes.ErrGRPCCompacted,
+	mvcc.ErrFutureRev:           rpctypes.ErrGRPCFutureRev,
+	txn.ErrInvalidContinueToken: rpctypes.ErrGRPCInvalidContinueToken,
+	txn.ErrInvalidKeyFilter:     rpctypes.ErrGRPCInvalidKeyFilter,
+	errors.ErrRequestTooLarge:   rpctypes.ErrGRPCRequestTooLarge,
+	errors.ErrNoSpace:           rpctypes.ErrGRPCNoSpace,
+	errors.ErrKeyQuotaExceeded:  rpctypes.ErrGRPCKeyQuotaExceeded,
+	errors.ErrReadOnly:          rpctypes.ErrGRPCReadOnly,
+	errors.ErrTooManyRequests:   rpctypes.ErrTooManyRequests,
 
 	errors.ErrNoLeader:                   rpctypes.ErrGRPCNoLeader,
 	errors.ErrNotLeader:                  rpctypes.ErrGRPCNotLeader,
@@ -59,7 +65,12 @@ var toGRPCErrorMap = map[error]error{
 	errors.ErrUnhealthy:                  rpctypes.ErrGRPCUnhealthy,
 	errors.ErrKeyNotFound:                rpctypes.ErrGRPCKeyNotFound,
 	errors.ErrCorrupt:                    rpctypes.ErrGRPCCorrupt,
+	errors.ErrNotSupportedForWitness:     rpctypes.ErrGRPCNotSupportedForWitness,
+	errors.ErrClusterVersionTooLow:       rpctypes.ErrGRPCClusterVersionTooLow,
+	errors.ErrMemberQuarantined:          rpctypes.ErrGRPCMemberQuarantined,
 	errors.ErrBadLeaderTransferee:        rpctypes.ErrGRPCBadLeaderTransferee,
+	errors.ErrKeyAccessTrackingDisabled:  rpctypes.ErrGRPCKeyAccessTrackingDisabled,
+	errors.ErrEncryptionDisabled:         rpctypes.ErrGRPCEncryptionDisabled,
 
 	errors.ErrClusterVersionUnavailable:      rpctypes.ErrGRPCClusterVersionUnavailable,
 	errors.ErrWrongDowngradeVersionFormat:    rpctypes.ErrGRPCWrongDowngradeVersionFormat,
@@ -67,9 +78,10 @@ var toGRPCErrorMap = map[error]error{
 	version.ErrDowngradeInProcess:            rpctypes.ErrGRPCDowngradeInProcess,
 	version.ErrNoInflightDowngrade:           rpctypes.ErrGRPCNoInflightDowngrade,
 
-	lease.ErrLeaseNotFound:    rpctypes.ErrGRPCLeaseNotFound,
-	lease.ErrLeaseExists:      rpctypes.ErrGRPCLeaseExist,
-	lease.ErrLeaseTTLTooLarge: rpctypes.ErrGRPCLeaseTTLTooLarge,
+	lease.ErrLeaseNotFound:         rpctypes.ErrGRPCLeaseNotFound,
+	lease.ErrLeaseExists:           rpctypes.ErrGRPCLeaseExist,
+	lease.ErrLeaseTTLTooLarge:      rpctypes.ErrGRPCLeaseTTLTooLarge,
+	lease.ErrLeaseMetadataTooLarge: rpctypes.ErrGRPCLeaseMetadataTooLarge,
 
 	auth.ErrRootUserNotExist:     rpctypes.ErrGRPCRootUserNotExist,
 	auth.ErrRootRoleNotExist:     rpctypes.ErrGRPCRootRoleNotExist,
@@ -88,6 +100,8 @@ var toGRPCErrorMap = map[error]error{
 	auth.ErrInvalidAuthToken:     rpctypes.ErrGRPCInvalidAuthToken,
 	auth.ErrInvalidAuthMgmt:      rpctypes.ErrGRPCInvalidAuthMgmt,
 	auth.ErrAuthOldRevision:      rpctypes.ErrGRPCAuthOldRevision,
+	auth.ErrAuthLockedOut:        rpctypes.ErrGRPCAuthLockedOut,
+	auth.ErrAuthPasswordExpired:  rpctypes.ErrGRPCAuthPasswordExpired,
 
 	// In sync with status.FromContextError
 	context.Canceled:         rpctypes.ErrGRPCCanceled,
@@ -139,6 +153,36 @@ func isClientCtxErr(ctxErr error, err error) bool {
 }
 
 // in v3.4, learner is allowed to serve serializable read and endpoint status
+// isRPCSupportedForWitness reports whether a witness, which stores no keys,
+// serves the method: only its status and health are.
+func isRPCSupportedForWitness(method string) bool {
+	return method == "/etcdserverpb.Maintenance/Status" || strings.HasPrefix(method, "/grpc.health.v1.Health/")
+}
+
+// isRPCSupportedWhenQuarantined reports whether a quarantined member, whose
+// data diverged from the cluster, serves the method: only the maintenance,
+// cluster, auth and health services are, so that clients fail over for the
+// key space.
+func isRPCSupportedWhenQuarantined(method string) bool {
+	for _, prefix := range []string{
+		"/etcdserverpb.Maintenance/",
+		"/etcdserverpb.Cluster/",
+		"/etcdserverpb.Auth/",
+		"/grpc.health.v1.Health/",
+	} {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isStreamRPCSupportedForLearner returns if the stream RPC method is served by
+// a learner: a learner serves Snapshot, a standby member Watch as well.
+func isStreamRPCSupportedForLearner(method string, isStandby bool) bool {
+	return method == snapshotMethod || (method == watchMethod && isStandby)
+}
+
 func isRPCSupportedForLearner(req any) bool {
 	switch r := req.(type) {
 	case *pb.StatusRequest:
@@ -15,20 +15,27 @@
 package v3rpc
 
 import (
+	"bytes"
+	"container/list"
 	"context"
 	"errors"
 	"io"
+	"math"
 	"math/rand"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/api/v3/mvccpb"
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	"go.etcd.io/etcd/client/pkg/v3/verify"
 	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/pkg/v3/traceutil"
 	"go.etcd.io/etcd/server/v3/auth"
 	"go.etcd.io/etcd/server/v3/etcdserver"
 	"go.etcd.io/etcd/server/v3/etcdserver/apply"
@@ -45,6 +52,12 @@ type watchServer struct {
 
 	maxRequestBytes uint
 
+	// maxWatchStreams bounds the streams served, and maxWatchersPerStream
+	// the watchers of each of them; 0 is no limit.
+	maxWatchStreams      int64
+	maxWatchersPerStream int
+	streams              atomic.Int64
+
 	sg        apply.RaftStatusGetter
 	watchable mvcc.WatchableKV
 	ag        AuthGetter
@@ -60,6 +73,9 @@ func NewWatchServer(s *etcdserver.EtcdServer) pb.WatchServer {
 
 		maxRequestBytes: s.Cfg.MaxRequestBytesWithOverhead(),
 
+		maxWatchStreams:      int64(s.Cfg.MaxWatchStreams),
+		maxWatchersPerStream: s.Cfg.MaxWatchersPerStream,
+
 		sg:        s,
 		watchable: s.Watchable(),
 		ag:        s,
@@ -128,6 +144,12 @@ type serverWatchStream struct {
 
 	maxRequestBytes uint
 
+	// maxWatchers bounds the watchers of the stream, 0 is no limit.
+	// watchers is the number of watchers created and not yet canceled by
+	// the client; it is only accessed by recvLoop.
+	maxWatchers int
+	watchers    int
+
 	sg        apply.RaftStatusGetter
 	watchable mvcc.WatchableKV
 	ag        AuthGetter
@@ -136,7 +158,7 @@ type serverWatchStream struct {
 	watchStream mvcc.WatchStream
 	ctrlStream  chan *pb.WatchResponse
 
-	// mu protects progress, prevKV, fragment
+	// mu protects progress, prevKV, fragment, maxEventRate, bookmarkInterval, batch, snapshots
 	mu sync.RWMutex
 	// tracks the watchID that stream might need to send progress to
 	// TODO: combine progress and prevKV into a single struct?
@@ -145,6 +167,15 @@ type serverWatchStream struct {
 	prevKV map[mvcc.WatchID]bool
 	// records fragmented watch IDs
 	fragment map[mvcc.WatchID]bool
+	// records the max event rate of rate limited watch IDs
+	maxEventRate map[mvcc.WatchID]int64
+	// records the bookmark interval of watch IDs asking for bookmarks
+	bookmarkInterval map[mvcc.WatchID]time.Duration
+	// records the batching options of batched watch IDs
+	batch map[mvcc.WatchID]watchBatchOptions
+	// records the snapshots to send to the watch IDs created at a compacted
+	// revision with snapshot_on_compaction, until they are announced
+	snapshots map[mvcc.WatchID]*pb.WatchResponse
 
 	// closec indicates the stream is closed.
 	closec chan struct{}
@@ -154,6 +185,17 @@ type serverWatchStream struct {
 }
 
 func (ws *watchServer) Watch(stream pb.Watch_WatchServer) (err error) {
+	n := ws.streams.Add(1)
+	defer func() {
+		ws.streams.Add(-1)
+		watchStreams.Dec()
+	}()
+	watchStreams.Inc()
+	if ws.maxWatchStreams > 0 && n > ws.maxWatchStreams {
+		watchLimitRejected.WithLabelValues("streams").Inc()
+		return rpctypes.ErrGRPCTooManyWatchStreams
+	}
+
 	sws := serverWatchStream{
 		lg: ws.lg,
 
@@ -161,6 +203,7 @@ func (ws *watchServer) Watch(stream pb.Watch_WatchServer) (err error) {
 		memberID:  ws.memberID,
 
 		maxRequestBytes: ws.maxRequestBytes,
+		maxWatchers:     ws.maxWatchersPerStream,
 
 		sg:        ws.sg,
 		watchable: ws.watchable,
@@ -171,9 +214,14 @@ func (ws *watchServer) Watch(stream pb.Watch_WatchServer) (err error) {
 		// chan for sending control response like watcher created and canceled.
 		ctrlStream: make(chan *pb.WatchResponse, ctrlStreamBufLen),
 
-		progress: make(map[mvcc.WatchID]bool),
-		prevKV:   make(map[mvcc.WatchID]bool),
-		fragment: make(map[mvcc.WatchID]bool),
+		progress:     make(map[mvcc.WatchID]bool),
+		prevKV:       make(map[mvcc.WatchID]bool),
+		fragment:     make(map[mvcc.WatchID]bool),
+		maxEventRate: make(map[mvcc.WatchID]int64),
+
+		bookmarkInterval: make(map[mvcc.WatchID]time.Duration),
+		batch:            make(map[mvcc.WatchID]watchBatchOptions),
+		snapshots:        make(map[mvcc.WatchID]*pb.WatchResponse),
 
 		closec: make(chan struct{}),
 	}
@@ -227,7 +275,10 @@ func (ws *watchServer) Watch(stream pb.Watch_WatchServer) (err error) {
 	return err
 }
 
-func (sws *serverWatchStream) isWatchPermitted(wcr *pb.WatchCreateRequest) error {
+// isWatchPermitted checks the permission of a watch created at the revision
+// wsrev, which must be the revision the watch starts after when the request
+// has no start revision.
+func (sws *serverWatchStream) isWatchPermitted(wcr *pb.WatchCreateRequest, wsrev int64) error {
 	authInfo, err := sws.ag.AuthInfoFromCtx(sws.gRPCStream.Context())
 	if err != nil {
 		return err
@@ -236,7 +287,12 @@ func (sws *serverWatchStream) isWatchPermitted(wcr *pb.WatchCreateRequest) error
 		// if auth is enabled, IsRangePermitted() can cause an error
 		authInfo = &auth.AuthInfo{}
 	}
-	return sws.ag.AuthStore().IsRangePermitted(authInfo, wcr.Key, wcr.RangeEnd)
+	// watching from a past revision, or with the previous key-values, reads
+	// the history of the keys.
+	if wcr.PrevKv || (wcr.StartRevision > 0 && wcr.StartRevision <= wsrev) {
+		return sws.ag.AuthStore().IsRangePermitted(authInfo, wcr.Key, wcr.RangeEnd)
+	}
+	return sws.ag.AuthStore().IsWatchPermitted(authInfo, wcr.Key, wcr.RangeEnd)
 }
 
 func (sws *serverWatchStream) recvLoop() error {
@@ -270,7 +326,10 @@ func (sws *serverWatchStream) recvLoop() error {
 				creq.RangeEnd = []byte{}
 			}
 
-			err := sws.isWatchPermitted(creq)
+			// the revision is taken once, so that the watch does not start
+			// before the revision its permission was checked at.
+			wsrev := sws.watchStream.Rev()
+			err := sws.isWatchPermitted(creq, wsrev)
 			if err != nil {
 				var cancelReason string
 				switch {
@@ -303,15 +362,36 @@ func (sws *serverWatchStream) recvLoop() error {
 				}
 			}
 
+			if sws.maxWatchers > 0 && sws.watchers >= sws.maxWatchers {
+				watchLimitRejected.WithLabelValues("watchers").Inc()
+				wr := &pb.WatchResponse{
+					Header:       sws.newResponseHeader(sws.watchStream.Rev()),
+					WatchId:      clientv3.InvalidWatchID,
+					Canceled:     true,
+					Created:      true,
+					CancelReason: rpctypes.ErrorDesc(rpctypes.ErrGRPCTooManyWatchers),
+				}
+				select {
+				case sws.ctrlStream <- wr:
+					continue
+				case <-sws.closec:
+					return nil
+				}
+			}
+
 			filters := FiltersFromRequest(creq)
 
-			wsrev := sws.watchStream.Rev()
 			rev := creq.StartRevision
 			if rev == 0 {
 				rev = wsrev + 1
 			}
+			var snapshot *pb.WatchResponse
+			if creq.SnapshotOnCompaction && rev <= wsrev {
+				snapshot, rev = sws.compactionSnapshot(creq, rev, filters)
+			}
 			id, err := sws.watchStream.Watch(mvcc.WatchID(creq.WatchId), creq.Key, creq.RangeEnd, rev, filters...)
 			if err == nil {
+				sws.watchers++
 				sws.mu.Lock()
 				if creq.ProgressNotify {
 					sws.progress[id] = true
@@ -322,6 +402,22 @@ func (sws *serverWatchStream) recvLoop() error {
 				if creq.Fragment {
 					sws.fragment[id] = true
 				}
+				if creq.MaxEventRate > 0 {
+					sws.maxEventRate[id] = creq.MaxEventRate
+				}
+				if creq.BookmarkInterval > 0 {
+					sws.bookmarkInterval[id] = time.Duration(creq.BookmarkInterval) * time.Second
+				}
+				if creq.BatchIntervalMs > 0 {
+					sws.batch[id] = watchBatchOptions{
+						interval:  time.Duration(creq.BatchIntervalMs) * time.Millisecond,
+						maxEvents: max(int(creq.BatchMaxEvents), 0),
+					}
+				}
+				if snapshot != nil {
+					snapshot.WatchId = int64(id)
+					sws.snapshots[id] = snapshot
+				}
 				sws.mu.Unlock()
 			} else {
 				id = clientv3.InvalidWatchID
@@ -347,6 +443,7 @@ func (sws *serverWatchStream) recvLoop() error {
 				id := uv.CancelRequest.WatchId
 				err := sws.watchStream.Cancel(mvcc.WatchID(id))
 				if err == nil {
+					sws.watchers--
 					wr := &pb.WatchResponse{
 						Header:   sws.newResponseHeader(sws.watchStream.Rev()),
 						WatchId:  id,
@@ -362,6 +459,10 @@ func (sws *serverWatchStream) recvLoop() error {
 					delete(sws.progress, mvcc.WatchID(id))
 					delete(sws.prevKV, mvcc.WatchID(id))
 					delete(sws.fragment, mvcc.WatchID(id))
+					delete(sws.maxEventRate, mvcc.WatchID(id))
+					delete(sws.bookmarkInterval, mvcc.WatchID(id))
+					delete(sws.batch, mvcc.WatchID(id))
+					delete(sws.snapshots, mvcc.WatchID(id))
 					sws.mu.Unlock()
 				}
 			}
@@ -386,12 +487,69 @@ func (sws *serverWatchStream) sendLoop() {
 	ids := make(map[mvcc.WatchID]struct{})
 	// watch responses pending on a watch id creation message
 	pending := make(map[mvcc.WatchID][]*pb.WatchResponse)
+	// events held back for watch ids with a max event rate
+	limited := make(map[mvcc.WatchID]*eventRateLimiter)
+	// bookmark schedules of watch ids with a bookmark interval
+	bookmarks := make(map[mvcc.WatchID]*watchBookmark)
+	// events buffered for batched watch ids
+	batches := make(map[mvcc.WatchID]*eventBatch)
 
 	interval := GetProgressReportInterval()
 	progressTicker := time.NewTicker(interval)
 
+	// flushTimer fires when the next held back event may be sent
+	flushTimer := time.NewTimer(time.Hour)
+	flushTimer.Stop()
+
+	// bookmarkTimer fires when the next bookmark is due
+	bookmarkTimer := time.NewTimer(time.Hour)
+	bookmarkTimer.Stop()
+
+	// batchTimer fires when the next batch is due
+	batchTimer := time.NewTimer(time.Hour)
+	batchTimer.Stop()
+
+	// deliver sends a watch response, buffering its events first if
+	// its watch is batched. It returns false if the send failed.
+	deliver := func(wr *pb.WatchResponse) bool {
+		id := mvcc.WatchID(wr.WatchId)
+		b, ok := batches[id]
+		if !ok {
+			return sws.sendWatchResponse(wr)
+		}
+		if len(wr.Events) > 0 && !wr.Canceled {
+			now := time.Now()
+			b.add(wr.Events, wr.Header.Revision, now)
+			if !b.full() {
+				resetBatchTimer(batchTimer, batches, now)
+				return true
+			}
+		}
+		if len(b.events) > 0 {
+			evs, rev := b.take()
+			if wr.Canceled {
+				// deliver the batched events before the cancellation
+				wr.Events = append(evs, wr.Events...)
+			} else {
+				// the response announces the revision of the
+				// batched events, or its events joined the batch
+				bwr := &pb.WatchResponse{Header: sws.newResponseHeader(rev), WatchId: wr.WatchId, Events: evs}
+				if !sws.sendWatchResponse(bwr) {
+					return false
+				}
+				if len(wr.Events) > 0 {
+					return true
+				}
+			}
+		}
+		return sws.sendWatchResponse(wr)
+	}
+
 	defer func() {
 		progressTicker.Stop()
+		flushTimer.Stop()
+		bookmarkTimer.Stop()
+		batchTimer.Stop()
 		// drain the chan to clean up pending events
 		for ws := range sws.watchStream.Chan() {
 			mvcc.ReportEventReceived(len(ws.Events))
@@ -424,12 +582,16 @@ func (sws *serverWatchStream) sendLoop() {
 					opt := mvcc.RangeOptions{Rev: evs[i].Kv.ModRevision - 1}
 					r, err := sws.watchable.Range(context.TODO(), evs[i].Kv.Key, nil, opt)
 					if err == nil && len(r.KVs) != 0 {
-						events[i].PrevKv = &(r.KVs[0])
+						// the events are shared with the watchers of
+						// other streams watching the same key or range
+						ev := evs[i]
+						ev.PrevKv = &(r.KVs[0])
+						events[i] = &ev
 					}
 				}
 			}
 
-			canceled := wresp.CompactRevision != 0
+			canceled := wresp.CompactRevision != 0 || wresp.Canceled
 			wr := &pb.WatchResponse{
 				Header:          sws.newResponseHeader(wresp.Revision),
 				WatchId:         int64(wresp.WatchID),
@@ -437,6 +599,9 @@ func (sws *serverWatchStream) sendLoop() {
 				CompactRevision: wresp.CompactRevision,
 				Canceled:        canceled,
 			}
+			if wresp.Canceled {
+				wr.CancelReason = rpctypes.ErrorDesc(rpctypes.ErrGRPCSlowWatcher)
+			}
 
 			// Progress notifications can have WatchID -1
 			// if they announce on behalf of multiple watchers
@@ -451,34 +616,43 @@ func (sws *serverWatchStream) sendLoop() {
 
 			mvcc.ReportEventReceived(len(evs))
 
-			sws.mu.RLock()
-			fragmented, ok := sws.fragment[wresp.WatchID]
-			sws.mu.RUnlock()
-
-			var serr error
-			// gofail: var beforeSendWatchResponse struct{}
-			if !fragmented && !ok {
-				serr = sws.gRPCStream.Send(wr)
-			} else {
-				serr = sendFragments(wr, sws.maxRequestBytes, sws.gRPCStream.Send)
+			if wresp.WatchID == clientv3.InvalidWatchID && (heldBack(limited) || batched(batches)) {
+				// the progress notification would announce the
+				// revisions of events that are still held back
+				continue
 			}
-
-			if serr != nil {
-				if isClientCtxErr(sws.gRPCStream.Context().Err(), serr) {
-					sws.lg.Debug("failed to send watch response to gRPC stream", zap.Error(serr))
-				} else {
-					sws.lg.Warn("failed to send watch response to gRPC stream", zap.Error(serr))
-					streamFailures.WithLabelValues("send", "watch").Inc()
+			if lim, okLim := limited[wresp.WatchID]; okLim {
+				switch {
+				case canceled:
+					// deliver the held back events before the cancellation
+					wr.Events = append(lim.takeAll(), events...)
+				case len(events) == 0:
+					if lim.held.Len() > 0 {
+						continue
+					}
+				default:
+					now := time.Now()
+					lim.hold(events, wresp.Revision)
+					held, rev := lim.take(now)
+					resetFlushTimer(flushTimer, limited, now)
+					if len(held) == 0 {
+						continue
+					}
+					wr.Events = held
+					wr.Header = sws.newResponseHeader(rev)
 				}
-				return
 			}
 
-			sws.mu.Lock()
-			if len(evs) > 0 && sws.progress[wresp.WatchID] {
-				// elide next progress update if sent a key update
-				sws.progress[wresp.WatchID] = false
+			if bm, okBm := bookmarks[wresp.WatchID]; okBm && bm.requested && len(wr.Events) == 0 && !canceled {
+				// the response is the progress notification requested by
+				// the bookmark; the watcher is synced up to its revision
+				wr.Bookmark = true
+				bm.requested = false
+			}
+
+			if !deliver(wr) {
+				return
 			}
-			sws.mu.Unlock()
 
 		case c, ok := <-sws.ctrlStream:
 			if !ok {
@@ -502,9 +676,42 @@ func (sws *serverWatchStream) sendLoop() {
 
 			if c.Canceled && wid != clientv3.InvalidWatchID {
 				delete(ids, wid)
+				delete(limited, wid)
+				delete(bookmarks, wid)
+				delete(batches, wid)
 				continue
 			}
 			if c.Created {
+				sws.mu.RLock()
+				maxEventRate := sws.maxEventRate[wid]
+				bookmarkInterval := sws.bookmarkInterval[wid]
+				batchOpts, batchOK := sws.batch[wid]
+				snapshot := sws.snapshots[wid]
+				sws.mu.RUnlock()
+				if batchOK {
+					batches[wid] = &eventBatch{opts: batchOpts, maxBytes: int(sws.maxRequestBytes)}
+				}
+				if maxEventRate > 0 {
+					limited[wid] = newEventRateLimiter(maxEventRate)
+				}
+				if bookmarkInterval > 0 {
+					now := time.Now()
+					bookmarks[wid] = &watchBookmark{interval: bookmarkInterval, next: now.Add(bookmarkInterval)}
+					resetBookmarkTimer(bookmarkTimer, bookmarks, now)
+				}
+
+				// send the snapshot replacing the compacted events,
+				// followed by the events after it
+				if snapshot != nil {
+					sws.mu.Lock()
+					delete(sws.snapshots, wid)
+					sws.mu.Unlock()
+					watchCompactionSnapshots.Inc()
+					if !sws.sendSnapshot(snapshot) {
+						return
+					}
+				}
+
 				// flush buffered events
 				ids[wid] = struct{}{}
 				for _, v := range pending[wid] {
@@ -532,12 +739,339 @@ func (sws *serverWatchStream) sendLoop() {
 			}
 			sws.mu.Unlock()
 
+		case <-flushTimer.C:
+			now := time.Now()
+			for id, lim := range limited {
+				held, rev := lim.take(now)
+				if len(held) == 0 {
+					continue
+				}
+				wr := &pb.WatchResponse{
+					Header:  sws.newResponseHeader(rev),
+					WatchId: int64(id),
+					Events:  held,
+				}
+				if !deliver(wr) {
+					return
+				}
+			}
+			resetFlushTimer(flushTimer, limited, now)
+
+		case <-batchTimer.C:
+			now := time.Now()
+			for id, b := range batches {
+				if len(b.events) == 0 || now.Before(b.deadline) {
+					continue
+				}
+				evs, rev := b.take()
+				wr := &pb.WatchResponse{
+					Header:  sws.newResponseHeader(rev),
+					WatchId: int64(id),
+					Events:  evs,
+				}
+				if !sws.sendWatchResponse(wr) {
+					return
+				}
+			}
+			resetBatchTimer(batchTimer, batches, now)
+
+		case <-bookmarkTimer.C:
+			now := time.Now()
+			for id, bm := range bookmarks {
+				if now.Before(bm.next) {
+					continue
+				}
+				bm.next = now.Add(bm.interval)
+				if lim, okLim := limited[id]; okLim && lim.held.Len() > 0 {
+					// the bookmark would announce the revisions
+					// of events that are still held back
+					continue
+				}
+				// the watchable store only answers if the watcher is
+				// synced, otherwise the bookmark waits for the next interval
+				bm.requested = true
+				sws.watchStream.RequestProgress(id)
+			}
+			resetBookmarkTimer(bookmarkTimer, bookmarks, now)
+
 		case <-sws.closec:
 			return
 		}
 	}
 }
 
+// watchBatchOptions are the batching options of a watch.
+type watchBatchOptions struct {
+	interval time.Duration
+	// maxEvents flushes a batch once reached, if set.
+	maxEvents int
+}
+
+// eventBatch buffers the events of a batched watch until they are sent together.
+type eventBatch struct {
+	opts watchBatchOptions
+	// maxBytes flushes a batch once its events reach the request size limit.
+	maxBytes int
+
+	events []*mvccpb.Event
+	size   int
+	// rev is the revision of the latest watch response batched.
+	rev int64
+	// deadline is the time the batched events must be sent by.
+	deadline time.Time
+}
+
+// add buffers the events of a watch response at revision rev.
+func (b *eventBatch) add(evs []*mvccpb.Event, rev int64, now time.Time) {
+	if len(b.events) == 0 {
+		b.deadline = now.Add(b.opts.interval)
+	}
+	for _, ev := range evs {
+		b.size += ev.Size()
+	}
+	b.events = append(b.events, evs...)
+	b.rev = rev
+}
+
+// full reports whether the batch must be sent without waiting for its deadline.
+func (b *eventBatch) full() bool {
+	return (b.opts.maxEvents > 0 && len(b.events) >= b.opts.maxEvents) || b.size >= b.maxBytes
+}
+
+// take removes the batched events, returning them with the revision the
+// watch response should report.
+func (b *eventBatch) take() ([]*mvccpb.Event, int64) {
+	evs, rev := b.events, b.rev
+	b.events, b.size = nil, 0
+	return evs, rev
+}
+
+// batched reports whether any batched watch has buffered events.
+func batched(batches map[mvcc.WatchID]*eventBatch) bool {
+	for _, b := range batches {
+		if len(b.events) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// resetBatchTimer arms the timer for the earliest deadline of the buffered batches.
+func resetBatchTimer(t *time.Timer, batches map[mvcc.WatchID]*eventBatch, now time.Time) {
+	var next time.Time
+	for _, b := range batches {
+		if len(b.events) > 0 && (next.IsZero() || b.deadline.Before(next)) {
+			next = b.deadline
+		}
+	}
+	if !next.IsZero() {
+		t.Reset(max(next.Sub(now), 0))
+	}
+}
+
+// watchBookmark schedules the bookmarks of a watch.
+type watchBookmark struct {
+	interval time.Duration
+	// next is the time the next bookmark is due.
+	next time.Time
+	// requested is set once the progress notification backing the
+	// bookmark is requested, until it is sent.
+	requested bool
+}
+
+// resetBookmarkTimer arms the timer for the earliest time a bookmark is due.
+func resetBookmarkTimer(t *time.Timer, bookmarks map[mvcc.WatchID]*watchBookmark, now time.Time) {
+	var next time.Time
+	for _, bm := range bookmarks {
+		if next.IsZero() || bm.next.Before(next) {
+			next = bm.next
+		}
+	}
+	if !next.IsZero() {
+		t.Reset(max(next.Sub(now), 0))
+	}
+}
+
+// compactionSnapshot returns, if rev is compacted, the snapshot of the range
+// watched by creq to send instead of the compacted events, and the revision
+// to watch from after the snapshot. Otherwise, it returns rev unchanged.
+func (sws *serverWatchStream) compactionSnapshot(creq *pb.WatchCreateRequest, rev int64, filters []mvcc.FilterFunc) (*pb.WatchResponse, int64) {
+	txn := sws.watchable.Read(mvcc.ConcurrentReadTxMode, traceutil.TODO())
+	defer txn.End()
+	if rev >= txn.FirstRev() {
+		return nil, rev
+	}
+	r, err := txn.Range(sws.gRPCStream.Context(), creq.Key, creq.RangeEnd, mvcc.RangeOptions{})
+	if err != nil {
+		sws.lg.Warn("failed to read the snapshot of a compacted watch", zap.Int64("start-revision", rev), zap.Error(err))
+		return nil, rev
+	}
+	events := make([]*mvccpb.Event, 0, len(r.KVs))
+	for i := range r.KVs {
+		ev := mvccpb.Event{Type: mvccpb.PUT, Kv: &r.KVs[i]}
+		if !slices.ContainsFunc(filters, func(f mvcc.FilterFunc) bool { return f(ev) }) {
+			events = append(events, &ev)
+		}
+	}
+	wr := &pb.WatchResponse{
+		Header:   sws.newResponseHeader(r.Rev),
+		Events:   events,
+		Snapshot: true,
+	}
+	return wr, r.Rev + 1
+}
+
+// sendSnapshot sends the snapshot of a compacted watch. Holding a whole range,
+// it is always split into fragments below the max request size, whether or not
+// the watcher asked for them: the clients knowing snapshots reassemble them.
+// It returns false if the send failed.
+func (sws *serverWatchStream) sendSnapshot(wr *pb.WatchResponse) bool {
+	if err := sendFragments(wr, sws.maxRequestBytes, sws.gRPCStream.Send); err != nil {
+		if isClientCtxErr(sws.gRPCStream.Context().Err(), err) {
+			sws.lg.Debug("failed to send watch snapshot to gRPC stream", zap.Error(err))
+		} else {
+			sws.lg.Warn("failed to send watch snapshot to gRPC stream", zap.Error(err))
+			streamFailures.WithLabelValues("send", "watch").Inc()
+		}
+		return false
+	}
+	return true
+}
+
+// sendWatchResponse sends a watch response to the gRPC stream, splitting it into
+// fragments if the watch asked for it. It returns false if the send failed.
+func (sws *serverWatchStream) sendWatchResponse(wr *pb.WatchResponse) bool {
+	id := mvcc.WatchID(wr.WatchId)
+
+	sws.mu.RLock()
+	fragmented, ok := sws.fragment[id]
+	sws.mu.RUnlock()
+
+	var serr error
+	// gofail: var beforeSendWatchResponse struct{}
+	if !fragmented && !ok {
+		serr = sws.gRPCStream.Send(wr)
+	} else {
+		serr = sendFragments(wr, sws.maxRequestBytes, sws.gRPCStream.Send)
+	}
+
+	if serr != nil {
+		if isClientCtxErr(sws.gRPCStream.Context().Err(), serr) {
+			sws.lg.Debug("failed to send watch response to gRPC stream", zap.Error(serr))
+		} else {
+			sws.lg.Warn("failed to send watch response to gRPC stream", zap.Error(serr))
+			streamFailures.WithLabelValues("send", "watch").Inc()
+		}
+		return false
+	}
+
+	sws.mu.Lock()
+	if len(wr.Events) > 0 && sws.progress[id] {
+		// elide next progress update if sent a key update
+		sws.progress[id] = false
+	}
+	sws.mu.Unlock()
+	return true
+}
+
+// eventRateLimiter holds back the events of a watch that exceed its max event rate.
+// Held back events are coalesced per key: only the latest event of a key is kept.
+type eventRateLimiter struct {
+	limiter *rate.Limiter
+	// held is ordered by mod revision, since a newer event of a
+	// held back key replaces the older one at the back of the list.
+	held *list.List
+	keys map[string]*list.Element
+	// rev is the revision of the latest watch response held back.
+	rev int64
+}
+
+func newEventRateLimiter(maxEventRate int64) *eventRateLimiter {
+	return &eventRateLimiter{
+		limiter: rate.NewLimiter(rate.Limit(maxEventRate), int(maxEventRate)),
+		held:    list.New(),
+		keys:    make(map[string]*list.Element),
+	}
+}
+
+// hold adds the events of a watch response at revision rev to the held back events.
+func (l *eventRateLimiter) hold(evs []*mvccpb.Event, rev int64) {
+	for _, ev := range evs {
+		k := string(ev.Kv.Key)
+		if e, ok := l.keys[k]; ok {
+			l.held.Remove(e)
+		}
+		l.keys[k] = l.held.PushBack(ev)
+	}
+	l.rev = rev
+}
+
+// take removes the oldest held back events the rate allows to send at now.
+// It returns them with the revision the watch response should report.
+func (l *eventRateLimiter) take(now time.Time) ([]*mvccpb.Event, int64) {
+	n := min(int(l.limiter.TokensAt(now)), l.held.Len())
+	if n <= 0 {
+		return nil, 0
+	}
+	l.limiter.AllowN(now, n)
+	evs := l.pop(n)
+	if l.held.Len() > 0 {
+		// the remaining held back events have higher revisions
+		return evs, evs[n-1].Kv.ModRevision
+	}
+	return evs, l.rev
+}
+
+// takeAll removes all the held back events regardless of the rate.
+func (l *eventRateLimiter) takeAll() []*mvccpb.Event {
+	return l.pop(l.held.Len())
+}
+
+func (l *eventRateLimiter) pop(n int) []*mvccpb.Event {
+	evs := make([]*mvccpb.Event, 0, n)
+	for i := 0; i < n; i++ {
+		ev := l.held.Remove(l.held.Front()).(*mvccpb.Event)
+		delete(l.keys, string(ev.Kv.Key))
+		evs = append(evs, ev)
+	}
+	return evs
+}
+
+// delay returns how long after now the next held back event may be sent.
+func (l *eventRateLimiter) delay(now time.Time) time.Duration {
+	tokens := l.limiter.TokensAt(now)
+	if tokens >= 1 {
+		return 0
+	}
+	return time.Duration(math.Ceil((1 - tokens) / float64(l.limiter.Limit()) * float64(time.Second)))
+}
+
+// heldBack reports whether any rate limited watch has held back events.
+func heldBack(limited map[mvcc.WatchID]*eventRateLimiter) bool {
+	for _, lim := range limited {
+		if lim.held.Len() > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// resetFlushTimer arms the timer for the earliest time a held back event may be sent.
+func resetFlushTimer(t *time.Timer, limited map[mvcc.WatchID]*eventRateLimiter, now time.Time) {
+	next := time.Duration(-1)
+	for _, lim := range limited {
+		if lim.held.Len() == 0 {
+			continue
+		}
+		if d := lim.delay(now); next < 0 || d < next {
+			next = d
+		}
+	}
+	if next >= 0 {
+		t.Reset(next)
+	}
+}
+
 func IsCreateEvent(e mvccpb.Event) bool {
 	return e.Type == mvccpb.PUT && e.Kv.CreateRevision == e.Kv.ModRevision
 }
@@ -617,5 +1151,35 @@ func FiltersFromRequest(creq *pb.WatchCreateRequest) []mvcc.FilterFunc {
 		default:
 		}
 	}
+	if f := valueFilter(creq); f != nil {
+		filters = append(filters, f)
+	}
 	return filters
 }
+
+// valueFilter returns a filter dropping the put events whose value does not
+// match the value filters of the request, or nil if it sets none. Delete
+// events carry no value and are never filtered out by it.
+func valueFilter(creq *pb.WatchCreateRequest) mvcc.FilterFunc {
+	prefix, substr := creq.ValuePrefix, creq.ValueContains
+	minSize, maxSize := creq.MinValueSize, creq.MaxValueSize
+	if len(prefix) == 0 && len(substr) == 0 && minSize <= 0 && maxSize <= 0 {
+		return nil
+	}
+	return func(e mvccpb.Event) bool {
+		if e.Type != mvccpb.PUT {
+			return false
+		}
+		v := e.Kv.Value
+		switch {
+		case minSize > 0 && int64(len(v)) < minSize:
+			return true
+		case maxSize > 0 && int64(len(v)) > maxSize:
+			return true
+		case !bytes.HasPrefix(v, prefix):
+			return true
+		default:
+			return !bytes.Contains(v, substr)
+		}
+	}
+}
@@ -18,7 +18,9 @@ import (
 	"bytes"
 	"errors"
 	"math"
+	"slices"
 	"testing"
+	"time"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/api/v3/mvccpb"
@@ -83,6 +85,135 @@ func TestSendFragment(t *testing.T) {
 	}
 }
 
+func TestEventRateLimiter(t *testing.T) {
+	lim := newEventRateLimiter(2)
+	now := time.Now()
+
+	var evs []*mvccpb.Event
+	for i, k := range []string{"a", "b", "a", "c", "b"} {
+		evs = append(evs, &mvccpb.Event{Kv: &mvccpb.KeyValue{Key: []byte(k), ModRevision: int64(i + 1)}})
+	}
+	lim.hold(evs, 5)
+
+	// the burst allows two events, the others stay held back coalesced per key
+	held, rev := lim.take(now)
+	if got := eventRevisions(held); !slices.Equal(got, []int64{3, 4}) {
+		t.Errorf("expected events at revisions [3 4], got %v", got)
+	}
+	if rev != 4 {
+		t.Errorf("expected revision 4, got %d", rev)
+	}
+	if held, _ = lim.take(now); len(held) != 0 {
+		t.Errorf("expected no events beyond the rate, got %v", eventRevisions(held))
+	}
+	if d := lim.delay(now); d <= 0 || d > time.Second/2 {
+		t.Errorf("expected delay in (0, 500ms], got %v", d)
+	}
+
+	held, rev = lim.take(now.Add(time.Second))
+	if got := eventRevisions(held); !slices.Equal(got, []int64{5}) {
+		t.Errorf("expected events at revisions [5], got %v", got)
+	}
+	if rev != 5 {
+		t.Errorf("expected revision 5, got %d", rev)
+	}
+	if lim.held.Len() != 0 || len(lim.keys) != 0 {
+		t.Errorf("expected no held back events, got %d", lim.held.Len())
+	}
+}
+
+func TestEventBatch(t *testing.T) {
+	b := &eventBatch{opts: watchBatchOptions{interval: time.Second, maxEvents: 3}, maxBytes: math.MaxInt32}
+	now := time.Now()
+
+	var evs []*mvccpb.Event
+	for i := 1; i <= 3; i++ {
+		evs = append(evs, &mvccpb.Event{Kv: &mvccpb.KeyValue{Key: []byte("a"), ModRevision: int64(i)}})
+	}
+	b.add(evs[:2], 2, now)
+	if b.full() {
+		t.Fatal("expected batch of 2 events not to be full")
+	}
+	if !b.deadline.Equal(now.Add(time.Second)) {
+		t.Errorf("expected deadline %v, got %v", now.Add(time.Second), b.deadline)
+	}
+	// the deadline is set by the first batched event
+	b.add(evs[2:], 3, now.Add(time.Millisecond))
+	if !b.deadline.Equal(now.Add(time.Second)) {
+		t.Errorf("expected deadline %v, got %v", now.Add(time.Second), b.deadline)
+	}
+	if !b.full() {
+		t.Fatal("expected batch of 3 events to be full")
+	}
+	got, rev := b.take()
+	if revs := eventRevisions(got); !slices.Equal(revs, []int64{1, 2, 3}) {
+		t.Errorf("expected events at revisions [1 2 3], got %v", revs)
+	}
+	if rev != 3 {
+		t.Errorf("expected revision 3, got %d", rev)
+	}
+	if len(b.events) != 0 || b.size != 0 {
+		t.Errorf("expected empty batch, got %d events", len(b.events))
+	}
+
+	// the size limit flushes a batch regardless of its number of events
+	b.maxBytes = evs[0].Size() + 1
+	b.add(evs[:1], 1, now)
+	if b.full() {
+		t.Fatal("expected batch below the size limit not to be full")
+	}
+	b.add(evs[1:2], 2, now)
+	if !b.full() {
+		t.Fatal("expected batch reaching the size limit to be full")
+	}
+}
+
+func TestFiltersFromRequestValue(t *testing.T) {
+	put := func(v string) mvccpb.Event {
+		return mvccpb.Event{Type: mvccpb.PUT, Kv: &mvccpb.KeyValue{Key: []byte("k"), Value: []byte(v)}}
+	}
+	del := mvccpb.Event{Type: mvccpb.DELETE, Kv: &mvccpb.KeyValue{Key: []byte("k")}}
+
+	tests := []struct {
+		name     string
+		creq     *pb.WatchCreateRequest
+		ev       mvccpb.Event
+		filtered bool
+	}{
+		{"no filter", &pb.WatchCreateRequest{}, put("abc"), false},
+		{"prefix match", &pb.WatchCreateRequest{ValuePrefix: []byte("ab")}, put("abc"), false},
+		{"prefix mismatch", &pb.WatchCreateRequest{ValuePrefix: []byte("bc")}, put("abc"), true},
+		{"contains match", &pb.WatchCreateRequest{ValueContains: []byte("bc")}, put("abc"), false},
+		{"contains mismatch", &pb.WatchCreateRequest{ValueContains: []byte("x")}, put("abc"), true},
+		{"min size match", &pb.WatchCreateRequest{MinValueSize: 3}, put("abc"), false},
+		{"min size mismatch", &pb.WatchCreateRequest{MinValueSize: 4}, put("abc"), true},
+		{"max size match", &pb.WatchCreateRequest{MaxValueSize: 3}, put("abc"), false},
+		{"max size mismatch", &pb.WatchCreateRequest{MaxValueSize: 2}, put("abc"), true},
+		{"all match", &pb.WatchCreateRequest{ValuePrefix: []byte("a"), ValueContains: []byte("c"), MinValueSize: 1, MaxValueSize: 3}, put("abc"), false},
+		{"delete is kept", &pb.WatchCreateRequest{ValuePrefix: []byte("x"), MinValueSize: 1}, del, false},
+		{"delete with nodelete", &pb.WatchCreateRequest{ValuePrefix: []byte("x"), Filters: []pb.WatchCreateRequest_FilterType{pb.WatchCreateRequest_NODELETE}}, del, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := false
+			for _, f := range FiltersFromRequest(tt.creq) {
+				filtered = filtered || f(tt.ev)
+			}
+			if filtered != tt.filtered {
+				t.Errorf("expected filtered %v, got %v", tt.filtered, filtered)
+			}
+		})
+	}
+}
+
+func eventRevisions(evs []*mvccpb.Event) []int64 {
+	revs := make([]int64, len(evs))
+	for i, ev := range evs {
+		revs[i] = ev.Kv.ModRevision
+	}
+	return revs
+}
+
 func createResponse(dataSize, events int) (resp *pb.WatchResponse) {
 	resp = &pb.WatchResponse{Events: make([]*mvccpb.Event, events)}
 	for i := range resp.Events {
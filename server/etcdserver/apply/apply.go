@@ -38,6 +38,7 @@ import (
 	serverstorage "go.etcd.io/etcd/server/v3/storage"
 	"go.etcd.io/etcd/server/v3/storage/backend"
 	"go.etcd.io/etcd/server/v3/storage/mvcc"
+	"go.etcd.io/etcd/server/v3/storage/schema"
 )
 
 const (
@@ -61,20 +62,41 @@ type Result struct {
 	// Compaction requests.
 	Physc <-chan struct{}
 	Trace *traceutil.Trace
+	// ApplyStart is when the member started applying the request, and
+	// ApplyTook how long it took.
+	ApplyStart time.Time
+	ApplyTook  time.Duration
+	// BackendTook is the part of ApplyTook spent in the mvcc store and
+	// backend.
+	BackendTook time.Duration
 }
 
-type applyFunc func(*pb.InternalRaftRequest, membership.ShouldApplyV3) *Result
+type backendTookKey struct{}
+
+// withBackendTook returns a context in which the applier adds the time spent
+// in the mvcc store and backend to took.
+func withBackendTook(ctx context.Context, took *time.Duration) context.Context {
+	return context.WithValue(ctx, backendTookKey{}, took)
+}
+
+func observeBackendTook(ctx context.Context, start time.Time) {
+	if took, ok := ctx.Value(backendTookKey{}).(*time.Duration); ok {
+		*took += time.Since(start)
+	}
+}
+
+type applyFunc func(context.Context, *pb.InternalRaftRequest, membership.ShouldApplyV3) *Result
 
 // applierV3 is the interface for processing V3 raft messages
 type applierV3 interface {
 	// Apply executes the generic portion of application logic for the current applier, but
 	// delegates the actual execution to the applyFunc method.
-	Apply(r *pb.InternalRaftRequest, shouldApplyV3 membership.ShouldApplyV3, applyFunc applyFunc) *Result
+	Apply(ctx context.Context, r *pb.InternalRaftRequest, shouldApplyV3 membership.ShouldApplyV3, applyFunc applyFunc) *Result
 
-	Put(p *pb.PutRequest) (*pb.PutResponse, *traceutil.Trace, error)
-	Range(r *pb.RangeRequest) (*pb.RangeResponse, *traceutil.Trace, error)
-	DeleteRange(dr *pb.DeleteRangeRequest) (*pb.DeleteRangeResponse, *traceutil.Trace, error)
-	Txn(rt *pb.TxnRequest) (*pb.TxnResponse, *traceutil.Trace, error)
+	Put(ctx context.Context, p *pb.PutRequest) (*pb.PutResponse, *traceutil.Trace, error)
+	Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeResponse, *traceutil.Trace, error)
+	DeleteRange(ctx context.Context, dr *pb.DeleteRangeRequest) (*pb.DeleteRangeResponse, *traceutil.Trace, error)
+	Txn(ctx context.Context, rt *pb.TxnRequest) (*pb.TxnResponse, *traceutil.Trace, error)
 	Compaction(compaction *pb.CompactionRequest) (*pb.CompactionResponse, <-chan struct{}, *traceutil.Trace, error)
 
 	LeaseGrant(lc *pb.LeaseGrantRequest) (*pb.LeaseGrantResponse, error)
@@ -83,12 +105,14 @@ type applierV3 interface {
 	LeaseCheckpoint(lc *pb.LeaseCheckpointRequest) (*pb.LeaseCheckpointResponse, error)
 
 	Alarm(*pb.AlarmRequest) (*pb.AlarmResponse, error)
+	ReadOnly(*pb.ReadOnlyRequest) (*pb.ReadOnlyResponse, error)
 
 	Authenticate(r *pb.InternalAuthenticateRequest) (*pb.AuthenticateResponse, error)
 
 	AuthEnable() (*pb.AuthEnableResponse, error)
 	AuthDisable() (*pb.AuthDisableResponse, error)
 	AuthStatus() (*pb.AuthStatusResponse, error)
+	TokenRevoke(r *pb.InternalAuthTokenRevokeRequest) (*pb.AuthTokenRevokeResponse, error)
 
 	UserAdd(ua *pb.AuthUserAddRequest) (*pb.AuthUserAddResponse, error)
 	UserDelete(ua *pb.AuthUserDeleteRequest) (*pb.AuthUserDeleteResponse, error)
@@ -121,6 +145,7 @@ type ApplierOptions struct {
 	TxnModeWriteWithSharedBuffer bool
 	Backend                      backend.Backend
 	QuotaBackendBytesCfg         int64
+	QuotaBackendGrace            bool
 	WarningApplyDuration         time.Duration
 }
 
@@ -138,24 +163,31 @@ func newApplierV3Backend(opts ApplierOptions) applierV3 {
 	}
 }
 
-func (a *applierV3backend) Apply(r *pb.InternalRaftRequest, shouldApplyV3 membership.ShouldApplyV3, applyFunc applyFunc) *Result {
-	return applyFunc(r, shouldApplyV3)
+func (a *applierV3backend) Apply(ctx context.Context, r *pb.InternalRaftRequest, shouldApplyV3 membership.ShouldApplyV3, applyFunc applyFunc) *Result {
+	return applyFunc(ctx, r, shouldApplyV3)
 }
 
-func (a *applierV3backend) Put(p *pb.PutRequest) (resp *pb.PutResponse, trace *traceutil.Trace, err error) {
-	return mvcctxn.Put(context.TODO(), a.options.Logger, a.options.Lessor, a.options.KV, p)
+func (a *applierV3backend) Put(ctx context.Context, p *pb.PutRequest) (resp *pb.PutResponse, trace *traceutil.Trace, err error) {
+	defer observeBackendTook(ctx, time.Now())
+	defer a.options.Backend.LinkNextCommit(ctx)
+	return mvcctxn.Put(ctx, a.options.Logger, a.options.Lessor, a.options.KV, p)
 }
 
-func (a *applierV3backend) DeleteRange(dr *pb.DeleteRangeRequest) (*pb.DeleteRangeResponse, *traceutil.Trace, error) {
-	return mvcctxn.DeleteRange(context.TODO(), a.options.Logger, a.options.KV, dr)
+func (a *applierV3backend) DeleteRange(ctx context.Context, dr *pb.DeleteRangeRequest) (*pb.DeleteRangeResponse, *traceutil.Trace, error) {
+	defer observeBackendTook(ctx, time.Now())
+	defer a.options.Backend.LinkNextCommit(ctx)
+	return mvcctxn.DeleteRange(ctx, a.options.Logger, a.options.KV, dr)
 }
 
-func (a *applierV3backend) Range(r *pb.RangeRequest) (*pb.RangeResponse, *traceutil.Trace, error) {
-	return mvcctxn.Range(context.TODO(), a.options.Logger, a.options.KV, r)
+func (a *applierV3backend) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeResponse, *traceutil.Trace, error) {
+	defer observeBackendTook(ctx, time.Now())
+	return mvcctxn.Range(ctx, a.options.Logger, a.options.KV, r)
 }
 
-func (a *applierV3backend) Txn(rt *pb.TxnRequest) (*pb.TxnResponse, *traceutil.Trace, error) {
-	return mvcctxn.Txn(context.TODO(), a.options.Logger, rt, a.options.TxnModeWriteWithSharedBuffer, a.options.KV, a.options.Lessor)
+func (a *applierV3backend) Txn(ctx context.Context, rt *pb.TxnRequest) (*pb.TxnResponse, *traceutil.Trace, error) {
+	defer observeBackendTook(ctx, time.Now())
+	defer a.options.Backend.LinkNextCommit(ctx)
+	return mvcctxn.Txn(ctx, a.options.Logger, rt, a.options.TxnModeWriteWithSharedBuffer, a.options.KV, a.options.Lessor)
 }
 
 func (a *applierV3backend) Compaction(compaction *pb.CompactionRequest) (*pb.CompactionResponse, <-chan struct{}, *traceutil.Trace, error) {
@@ -177,7 +209,7 @@ func (a *applierV3backend) Compaction(compaction *pb.CompactionRequest) (*pb.Com
 }
 
 func (a *applierV3backend) LeaseGrant(lc *pb.LeaseGrantRequest) (*pb.LeaseGrantResponse, error) {
-	l, err := a.options.Lessor.Grant(lease.LeaseID(lc.ID), lc.TTL)
+	l, err := a.options.Lessor.GrantWithMetadata(lease.LeaseID(lc.ID), lc.TTL, lc.Metadata)
 	resp := &pb.LeaseGrantResponse{}
 	if err == nil {
 		resp.ID = int64(l.ID)
@@ -231,6 +263,20 @@ func (a *applierV3backend) Alarm(ar *pb.AlarmRequest) (*pb.AlarmResponse, error)
 	return resp, nil
 }
 
+// ReadOnly saves the read-only mode of the cluster to the backend, and returns
+// whether the cluster is in read-only mode.
+func (a *applierV3backend) ReadOnly(r *pb.ReadOnlyRequest) (*pb.ReadOnlyResponse, error) {
+	mb := schema.NewMembershipBackend(a.options.Logger, a.options.Backend)
+	switch r.Action {
+	case pb.ReadOnlyRequest_GET:
+	case pb.ReadOnlyRequest_ENABLE, pb.ReadOnlyRequest_DISABLE:
+		mb.MustSaveReadOnlyToBackend(r.Action == pb.ReadOnlyRequest_ENABLE)
+	default:
+		return nil, nil
+	}
+	return &pb.ReadOnlyResponse{Header: a.newHeader(), Enabled: mb.ReadOnlyFromBackend()}, nil
+}
+
 type applierV3Capped struct {
 	applierV3
 	q serverstorage.BackendQuota
@@ -240,15 +286,15 @@ type applierV3Capped struct {
 // with Puts so that the number of keys in the store is capped.
 func newApplierV3Capped(base applierV3) applierV3 { return &applierV3Capped{applierV3: base} }
 
-func (a *applierV3Capped) Put(_ *pb.PutRequest) (*pb.PutResponse, *traceutil.Trace, error) {
+func (a *applierV3Capped) Put(_ context.Context, _ *pb.PutRequest) (*pb.PutResponse, *traceutil.Trace, error) {
 	return nil, nil, errors.ErrNoSpace
 }
 
-func (a *applierV3Capped) Txn(r *pb.TxnRequest) (*pb.TxnResponse, *traceutil.Trace, error) {
+func (a *applierV3Capped) Txn(ctx context.Context, r *pb.TxnRequest) (*pb.TxnResponse, *traceutil.Trace, error) {
 	if a.q.Cost(r) > 0 {
 		return nil, nil, errors.ErrNoSpace
 	}
-	return a.applierV3.Txn(r)
+	return a.applierV3.Txn(ctx, r)
 }
 
 func (a *applierV3Capped) LeaseGrant(_ *pb.LeaseGrantRequest) (*pb.LeaseGrantResponse, error) {
@@ -283,6 +329,14 @@ func (a *applierV3backend) Authenticate(r *pb.InternalAuthenticateRequest) (*pb.
 	return resp, err
 }
 
+func (a *applierV3backend) TokenRevoke(r *pb.InternalAuthTokenRevokeRequest) (*pb.AuthTokenRevokeResponse, error) {
+	resp, err := a.options.AuthStore.TokenRevoke(r)
+	if resp != nil {
+		resp.Header = a.newHeader()
+	}
+	return resp, err
+}
+
 func (a *applierV3backend) UserAdd(r *pb.AuthUserAddRequest) (*pb.AuthUserAddResponse, error) {
 	resp, err := a.options.AuthStore.UserAdd(r)
 	if resp != nil {
@@ -410,6 +464,7 @@ func (a *applierV3backend) ClusterMemberAttrSet(r *membershippb.ClusterMemberAtt
 		membership.Attributes{
 			Name:       r.MemberAttributes.Name,
 			ClientURLs: r.MemberAttributes.ClientUrls,
+			IsWitness:  r.MemberAttributes.IsWitness,
 		},
 		shouldApplyV3,
 	)
@@ -428,22 +483,22 @@ type quotaApplierV3 struct {
 	q serverstorage.Quota
 }
 
-func newQuotaApplierV3(lg *zap.Logger, quotaBackendBytesCfg int64, be backend.Backend, app applierV3) applierV3 {
-	return &quotaApplierV3{app, serverstorage.NewBackendQuota(lg, quotaBackendBytesCfg, be, "v3-applier")}
+func newQuotaApplierV3(lg *zap.Logger, quotaBackendBytesCfg int64, grace bool, be backend.Backend, app applierV3) applierV3 {
+	return &quotaApplierV3{app, serverstorage.NewBackendQuota(lg, quotaBackendBytesCfg, grace, be, "v3-applier")}
 }
 
-func (a *quotaApplierV3) Put(p *pb.PutRequest) (*pb.PutResponse, *traceutil.Trace, error) {
+func (a *quotaApplierV3) Put(ctx context.Context, p *pb.PutRequest) (*pb.PutResponse, *traceutil.Trace, error) {
 	ok := a.q.Available(p)
-	resp, trace, err := a.applierV3.Put(p)
+	resp, trace, err := a.applierV3.Put(ctx, p)
 	if err == nil && !ok {
 		err = errors.ErrNoSpace
 	}
 	return resp, trace, err
 }
 
-func (a *quotaApplierV3) Txn(rt *pb.TxnRequest) (*pb.TxnResponse, *traceutil.Trace, error) {
+func (a *quotaApplierV3) Txn(ctx context.Context, rt *pb.TxnRequest) (*pb.TxnResponse, *traceutil.Trace, error) {
 	ok := a.q.Available(rt)
-	resp, trace, err := a.applierV3.Txn(rt)
+	resp, trace, err := a.applierV3.Txn(ctx, rt)
 	if err == nil && !ok {
 		err = errors.ErrNoSpace
 	}
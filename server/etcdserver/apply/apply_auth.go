@@ -15,6 +15,8 @@
 package apply
 
 import (
+	"context"
+	"slices"
 	"sync"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
@@ -41,7 +43,7 @@ func newAuthApplierV3(as auth.AuthStore, base applierV3, lessor lease.Lessor) *a
 	return &authApplierV3{applierV3: base, as: as, lessor: lessor}
 }
 
-func (aa *authApplierV3) Apply(r *pb.InternalRaftRequest, shouldApplyV3 membership.ShouldApplyV3, applyFunc applyFunc) *Result {
+func (aa *authApplierV3) Apply(ctx context.Context, r *pb.InternalRaftRequest, shouldApplyV3 membership.ShouldApplyV3, applyFunc applyFunc) *Result {
 	aa.mu.Lock()
 	defer aa.mu.Unlock()
 	if r.Header != nil {
@@ -49,22 +51,21 @@ func (aa *authApplierV3) Apply(r *pb.InternalRaftRequest, shouldApplyV3 membersh
 		// does not have header field
 		aa.authInfo.Username = r.Header.Username
 		aa.authInfo.Revision = r.Header.AuthRevision
+		aa.authInfo.Roles = r.Header.Roles
 	}
 	if needAdminPermission(r) {
 		if err := aa.as.IsAdminPermitted(&aa.authInfo); err != nil {
-			aa.authInfo.Username = ""
-			aa.authInfo.Revision = 0
+			aa.authInfo = auth.AuthInfo{}
 			return &Result{Err: err}
 		}
 	}
-	ret := aa.applierV3.Apply(r, shouldApplyV3, applyFunc)
-	aa.authInfo.Username = ""
-	aa.authInfo.Revision = 0
+	ret := aa.applierV3.Apply(ctx, r, shouldApplyV3, applyFunc)
+	aa.authInfo = auth.AuthInfo{}
 	return ret
 }
 
-func (aa *authApplierV3) Put(r *pb.PutRequest) (*pb.PutResponse, *traceutil.Trace, error) {
-	if err := aa.as.IsPutPermitted(&aa.authInfo, r.Key); err != nil {
+func (aa *authApplierV3) Put(ctx context.Context, r *pb.PutRequest) (*pb.PutResponse, *traceutil.Trace, error) {
+	if err := txn.CheckPutAuth(aa.as, &aa.authInfo, r); err != nil {
 		return nil, nil, err
 	}
 
@@ -82,17 +83,17 @@ func (aa *authApplierV3) Put(r *pb.PutRequest) (*pb.PutResponse, *traceutil.Trac
 			return nil, nil, err
 		}
 	}
-	return aa.applierV3.Put(r)
+	return aa.applierV3.Put(ctx, r)
 }
 
-func (aa *authApplierV3) Range(r *pb.RangeRequest) (*pb.RangeResponse, *traceutil.Trace, error) {
+func (aa *authApplierV3) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeResponse, *traceutil.Trace, error) {
 	if err := aa.as.IsRangePermitted(&aa.authInfo, r.Key, r.RangeEnd); err != nil {
 		return nil, nil, err
 	}
-	return aa.applierV3.Range(r)
+	return aa.applierV3.Range(ctx, r)
 }
 
-func (aa *authApplierV3) DeleteRange(r *pb.DeleteRangeRequest) (*pb.DeleteRangeResponse, *traceutil.Trace, error) {
+func (aa *authApplierV3) DeleteRange(ctx context.Context, r *pb.DeleteRangeRequest) (*pb.DeleteRangeResponse, *traceutil.Trace, error) {
 	if err := aa.as.IsDeleteRangePermitted(&aa.authInfo, r.Key, r.RangeEnd); err != nil {
 		return nil, nil, err
 	}
@@ -103,14 +104,14 @@ func (aa *authApplierV3) DeleteRange(r *pb.DeleteRangeRequest) (*pb.DeleteRangeR
 		}
 	}
 
-	return aa.applierV3.DeleteRange(r)
+	return aa.applierV3.DeleteRange(ctx, r)
 }
 
-func (aa *authApplierV3) Txn(rt *pb.TxnRequest) (*pb.TxnResponse, *traceutil.Trace, error) {
+func (aa *authApplierV3) Txn(ctx context.Context, rt *pb.TxnRequest) (*pb.TxnResponse, *traceutil.Trace, error) {
 	if err := txn.CheckTxnAuth(aa.as, &aa.authInfo, rt); err != nil {
 		return nil, nil, err
 	}
-	return aa.applierV3.Txn(rt)
+	return aa.applierV3.Txn(ctx, rt)
 }
 
 func (aa *authApplierV3) LeaseRevoke(lc *pb.LeaseRevokeRequest) (*pb.LeaseRevokeResponse, error) {
@@ -146,7 +147,8 @@ func (aa *authApplierV3) checkLeasePutsKeys(l *lease.Lease) error {
 
 func (aa *authApplierV3) UserGet(r *pb.AuthUserGetRequest) (*pb.AuthUserGetResponse, error) {
 	err := aa.as.IsAdminPermitted(&aa.authInfo)
-	if err != nil && r.Name != aa.authInfo.Username {
+	// a federated user has no user of its name in the store
+	if err != nil && (r.Name != aa.authInfo.Username || len(aa.authInfo.Roles) > 0) {
 		aa.authInfo.Username = ""
 		aa.authInfo.Revision = 0
 		return &pb.AuthUserGetResponse{}, err
@@ -155,9 +157,31 @@ func (aa *authApplierV3) UserGet(r *pb.AuthUserGetRequest) (*pb.AuthUserGetRespo
 	return aa.applierV3.UserGet(r)
 }
 
+// UserChangePassword lets users change their own passwords, and root change
+// any.
+func (aa *authApplierV3) UserChangePassword(r *pb.AuthUserChangePasswordRequest) (*pb.AuthUserChangePasswordResponse, error) {
+	// a federated user has no password of its name in the store
+	if r.Name != aa.authInfo.Username || len(aa.authInfo.Roles) > 0 {
+		if err := aa.as.IsAdminPermitted(&aa.authInfo); err != nil {
+			return nil, err
+		}
+	}
+	return aa.applierV3.UserChangePassword(r)
+}
+
+// TokenRevoke lets users revoke their own tokens, and root revoke any.
+func (aa *authApplierV3) TokenRevoke(r *pb.InternalAuthTokenRevokeRequest) (*pb.AuthTokenRevokeResponse, error) {
+	if r.Name != aa.authInfo.Username {
+		if err := aa.as.IsAdminPermitted(&aa.authInfo); err != nil {
+			return nil, err
+		}
+	}
+	return aa.applierV3.TokenRevoke(r)
+}
+
 func (aa *authApplierV3) RoleGet(r *pb.AuthRoleGetRequest) (*pb.AuthRoleGetResponse, error) {
 	err := aa.as.IsAdminPermitted(&aa.authInfo)
-	if err != nil && !aa.as.HasRole(aa.authInfo.Username, r.Role) {
+	if err != nil && !aa.hasRole(r.Role) {
 		aa.authInfo.Username = ""
 		aa.authInfo.Revision = 0
 		return &pb.AuthRoleGetResponse{}, err
@@ -166,6 +190,13 @@ func (aa *authApplierV3) RoleGet(r *pb.AuthRoleGetRequest) (*pb.AuthRoleGetRespo
 	return aa.applierV3.RoleGet(r)
 }
 
+func (aa *authApplierV3) hasRole(role string) bool {
+	if len(aa.authInfo.Roles) > 0 {
+		return slices.Contains(aa.authInfo.Roles, role)
+	}
+	return aa.as.HasRole(aa.authInfo.Username, role)
+}
+
 func needAdminPermission(r *pb.InternalRaftRequest) bool {
 	switch {
 	case r.AuthEnable != nil:
@@ -178,8 +209,6 @@ func needAdminPermission(r *pb.InternalRaftRequest) bool {
 		return true
 	case r.AuthUserDelete != nil:
 		return true
-	case r.AuthUserChangePassword != nil:
-		return true
 	case r.AuthUserGrantRole != nil:
 		return true
 	case r.AuthUserRevokeRole != nil:
@@ -15,6 +15,7 @@
 package apply
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -44,7 +45,7 @@ func dummyIndexWaiter(_ uint64) <-chan struct{} {
 	return ch
 }
 
-func dummyApplyFunc(_ *pb.InternalRaftRequest, shouldApplyV3 membership.ShouldApplyV3) *Result {
+func dummyApplyFunc(_ context.Context, _ *pb.InternalRaftRequest, shouldApplyV3 membership.ShouldApplyV3) *Result {
 	return &Result{}
 }
 
@@ -101,6 +102,7 @@ func defaultAuthApplierV3(t *testing.T) *authApplierV3 {
 		newApplierV3Backend(ApplierOptions{
 			Logger:                       lg,
 			KV:                           kv,
+			Backend:                      be,
 			AlarmStore:                   alarmStore,
 			ConsistentIndex:              consistentIndex,
 			AuthStore:                    authStore,
@@ -217,7 +219,7 @@ func TestAuthApplierV3_Apply(t *testing.T) {
 	mustCreateRolesAndEnableAuth(t, authApplier)
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
-			result := authApplier.Apply(tc.request, membership.ApplyBoth, dummyApplyFunc)
+			result := authApplier.Apply(context.Background(), tc.request, membership.ApplyBoth, dummyApplyFunc)
 			require.Equalf(t, result, tc.expectResult, "Apply: got %v, expect: %v", result, tc.expectResult)
 		})
 	}
@@ -332,9 +334,9 @@ func TestAuthApplierV3_AdminPermission(t *testing.T) {
 			adminPermissionNeeded: true,
 		},
 		{
-			name:                  "AuthUserChangePassword needs admin permission",
+			name:                  "AuthUserChangePassword does not need admin permission",
 			request:               &pb.InternalRaftRequest{AuthUserChangePassword: &pb.AuthUserChangePasswordRequest{}},
-			adminPermissionNeeded: true,
+			adminPermissionNeeded: false,
 		},
 		{
 			name:                  "AuthUserGrantRole needs admin permission",
@@ -384,7 +386,7 @@ func TestAuthApplierV3_AdminPermission(t *testing.T) {
 			if tc.adminPermissionNeeded {
 				tc.request.Header = &pb.RequestHeader{Username: userReadOnly}
 			}
-			result := authApplier.Apply(tc.request, membership.ApplyBoth, dummyApplyFunc)
+			result := authApplier.Apply(context.Background(), tc.request, membership.ApplyBoth, dummyApplyFunc)
 			require.Equalf(t, errors.Is(result.Err, auth.ErrPermissionDenied), tc.adminPermissionNeeded, "Admin permission needed")
 		})
 	}
@@ -440,7 +442,7 @@ func TestAuthApplierV3_Put(t *testing.T) {
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
 			setAuthInfo(authApplier, tc.userName)
-			_, _, err := authApplier.Put(tc.request)
+			_, _, err := authApplier.Put(context.Background(), tc.request)
 			require.Equalf(t, tc.expectError, err, "Put returned unexpected error (or lack thereof), expected: %v, got: %v", tc.expectError, err)
 		})
 	}
@@ -459,7 +461,7 @@ func TestAuthApplierV3_LeasePut(t *testing.T) {
 
 	// The user should be able to put the key
 	setAuthInfo(authApplier, userWriteOnly)
-	_, _, err = authApplier.Put(&pb.PutRequest{
+	_, _, err = authApplier.Put(context.Background(), &pb.PutRequest{
 		Key:   []byte(key),
 		Value: []byte("1"),
 		Lease: leaseID,
@@ -468,7 +470,7 @@ func TestAuthApplierV3_LeasePut(t *testing.T) {
 
 	// Put a key under the lease outside user's key range
 	setAuthInfo(authApplier, userRoot)
-	_, _, err = authApplier.Put(&pb.PutRequest{
+	_, _, err = authApplier.Put(context.Background(), &pb.PutRequest{
 		Key:   []byte(keyOutsideRange),
 		Value: []byte("1"),
 		Lease: leaseID,
@@ -477,7 +479,7 @@ func TestAuthApplierV3_LeasePut(t *testing.T) {
 
 	// The user should not be able to put the key anymore
 	setAuthInfo(authApplier, userWriteOnly)
-	_, _, err = authApplier.Put(&pb.PutRequest{
+	_, _, err = authApplier.Put(context.Background(), &pb.PutRequest{
 		Key:   []byte(key),
 		Value: []byte("1"),
 		Lease: leaseID,
@@ -523,7 +525,7 @@ func TestAuthApplierV3_Range(t *testing.T) {
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
 			setAuthInfo(authApplier, tc.userName)
-			_, _, err := authApplier.Range(tc.request)
+			_, _, err := authApplier.Range(context.Background(), tc.request)
 			require.Equalf(t, tc.expectError, err, "Range returned unexpected error (or lack thereof), expected: %v, got: %v", tc.expectError, err)
 		})
 	}
@@ -587,7 +589,7 @@ func TestAuthApplierV3_DeleteRange(t *testing.T) {
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
 			setAuthInfo(authApplier, tc.userName)
-			_, _, err := authApplier.DeleteRange(tc.request)
+			_, _, err := authApplier.DeleteRange(context.Background(), tc.request)
 			require.Equalf(t, tc.expectError, err, "Range returned unexpected error (or lack thereof), expected: %v, got: %v", tc.expectError, err)
 		})
 	}
@@ -657,7 +659,7 @@ func TestAuthApplierV3_Txn(t *testing.T) {
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
 			setAuthInfo(authApplier, tc.userName)
-			_, _, err := authApplier.Txn(tc.request)
+			_, _, err := authApplier.Txn(context.Background(), tc.request)
 			require.Equalf(t, tc.expectError, err, "Range returned unexpected error (or lack thereof), expected: %v, got: %v", tc.expectError, err)
 		})
 	}
@@ -690,7 +692,7 @@ func TestAuthApplierV3_LeaseRevoke(t *testing.T) {
 
 	// Put a key under the lease outside user's key range
 	setAuthInfo(authApplier, userRoot)
-	_, _, err = authApplier.Put(&pb.PutRequest{
+	_, _, err = authApplier.Put(context.Background(), &pb.PutRequest{
 		Key:   []byte(keyOutsideRange),
 		Value: []byte("1"),
 		Lease: leaseID,
@@ -744,6 +746,45 @@ func TestAuthApplierV3_UserGet(t *testing.T) {
 	}
 }
 
+// TestAuthApplierV3_UserChangePassword verifies UserChangePassword can only be performed by the user itself or the root
+func TestAuthApplierV3_UserChangePassword(t *testing.T) {
+	tcs := []struct {
+		name        string
+		userName    string
+		request     *pb.AuthUserChangePasswordRequest
+		expectError error
+	}{
+		{
+			name:        "UserChangePassword permission denied with non-root role and requests other user",
+			userName:    userWriteOnly,
+			request:     &pb.AuthUserChangePasswordRequest{Name: userReadOnly},
+			expectError: auth.ErrPermissionDenied,
+		},
+		{
+			name:        "UserChangePassword success with non-root role but requests itself",
+			userName:    userWriteOnly,
+			request:     &pb.AuthUserChangePasswordRequest{Name: userWriteOnly},
+			expectError: nil,
+		},
+		{
+			name:        "UserChangePassword success with root role",
+			userName:    userRoot,
+			request:     &pb.AuthUserChangePasswordRequest{Name: userWriteOnly},
+			expectError: nil,
+		},
+	}
+
+	authApplier := defaultAuthApplierV3(t)
+	mustCreateRolesAndEnableAuth(t, authApplier)
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			setAuthInfo(authApplier, tc.userName)
+			_, err := authApplier.UserChangePassword(tc.request)
+			require.Equalf(t, tc.expectError, err, "UserChangePassword returned unexpected error (or lack thereof), expected: %v, got: %v", tc.expectError, err)
+		})
+	}
+}
+
 // TestAuthApplierV3_RoleGet verifies RoleGet can only be performed by the user in the role itself or the root
 func TestAuthApplierV3_RoleGet(t *testing.T) {
 	tcs := []struct {
@@ -0,0 +1,56 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apply
+
+import (
+	"context"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/pkg/v3/traceutil"
+	"go.etcd.io/etcd/server/v3/etcdserver/errors"
+	mvcctxn "go.etcd.io/etcd/server/v3/etcdserver/txn"
+)
+
+// applierV3ReadOnly rejects the requests writing to the keyspace while the
+// cluster is in read-only mode. The leases are still revoked, so that the
+// expired ones are.
+type applierV3ReadOnly struct {
+	applierV3
+}
+
+func newApplierV3ReadOnly(a applierV3) *applierV3ReadOnly { return &applierV3ReadOnly{a} }
+
+func (a *applierV3ReadOnly) Put(_ context.Context, _ *pb.PutRequest) (*pb.PutResponse, *traceutil.Trace, error) {
+	return nil, nil, errors.ErrReadOnly
+}
+
+func (a *applierV3ReadOnly) DeleteRange(_ context.Context, _ *pb.DeleteRangeRequest) (*pb.DeleteRangeResponse, *traceutil.Trace, error) {
+	return nil, nil, errors.ErrReadOnly
+}
+
+func (a *applierV3ReadOnly) Txn(ctx context.Context, rt *pb.TxnRequest) (*pb.TxnResponse, *traceutil.Trace, error) {
+	if !mvcctxn.IsTxnReadonly(rt) {
+		return nil, nil, errors.ErrReadOnly
+	}
+	return a.applierV3.Txn(ctx, rt)
+}
+
+func (a *applierV3ReadOnly) Compaction(_ *pb.CompactionRequest) (*pb.CompactionResponse, <-chan struct{}, *traceutil.Trace, error) {
+	return nil, nil, nil, errors.ErrReadOnly
+}
+
+func (a *applierV3ReadOnly) LeaseGrant(_ *pb.LeaseGrantRequest) (*pb.LeaseGrantResponse, error) {
+	return nil, errors.ErrReadOnly
+}
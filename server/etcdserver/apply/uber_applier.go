@@ -15,6 +15,7 @@
 package apply
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -25,10 +26,11 @@ import (
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3alarm"
 	"go.etcd.io/etcd/server/v3/etcdserver/txn"
 	"go.etcd.io/etcd/server/v3/storage/mvcc"
+	"go.etcd.io/etcd/server/v3/storage/schema"
 )
 
 type UberApplier interface {
-	Apply(r *pb.InternalRaftRequest, shouldApplyV3 membership.ShouldApplyV3) *Result
+	Apply(ctx context.Context, r *pb.InternalRaftRequest, shouldApplyV3 membership.ShouldApplyV3) *Result
 }
 
 type uberApplier struct {
@@ -37,10 +39,15 @@ type uberApplier struct {
 	alarmStore           *v3alarm.AlarmStore
 	warningApplyDuration time.Duration
 
+	// readOnly is whether the cluster is in read-only mode.
+	readOnly bool
+
 	// This is the applier that is taking in consideration current alarms
+	// and the read-only mode
 	applyV3 applierV3
 
-	// This is the applier used for wrapping when alarms change
+	// This is the applier used for wrapping when alarms or the read-only mode
+	// change
 	applyV3base applierV3
 }
 
@@ -54,7 +61,10 @@ func NewUberApplier(opts ApplierOptions) UberApplier {
 		applyV3:              applyV3base,
 		applyV3base:          applyV3base,
 	}
-	ua.restoreAlarms()
+	if opts.Backend != nil {
+		ua.readOnly = schema.NewMembershipBackend(opts.Logger, opts.Backend).ReadOnlyFromBackend()
+	}
+	ua.restoreAppliers()
 	return ua
 }
 
@@ -62,37 +72,46 @@ func newApplierV3(opts ApplierOptions) applierV3 {
 	applierBackend := newApplierV3Backend(opts)
 	return newAuthApplierV3(
 		opts.AuthStore,
-		newQuotaApplierV3(opts.Logger, opts.QuotaBackendBytesCfg, opts.Backend, applierBackend),
+		newQuotaApplierV3(opts.Logger, opts.QuotaBackendBytesCfg, opts.QuotaBackendGrace, opts.Backend, applierBackend),
 		opts.Lessor,
 	)
 }
 
-func (a *uberApplier) restoreAlarms() {
+func (a *uberApplier) restoreAppliers() {
 	noSpaceAlarms := len(a.alarmStore.Get(pb.AlarmType_NOSPACE)) > 0
 	corruptAlarms := len(a.alarmStore.Get(pb.AlarmType_CORRUPT)) > 0
 	a.applyV3 = a.applyV3base
 	if noSpaceAlarms {
 		a.applyV3 = newApplierV3Capped(a.applyV3)
 	}
+	if a.readOnly {
+		a.applyV3 = newApplierV3ReadOnly(a.applyV3)
+	}
 	if corruptAlarms {
 		a.applyV3 = newApplierV3Corrupt(a.applyV3)
 	}
 }
 
-func (a *uberApplier) Apply(r *pb.InternalRaftRequest, shouldApplyV3 membership.ShouldApplyV3) *Result {
+func (a *uberApplier) Apply(ctx context.Context, r *pb.InternalRaftRequest, shouldApplyV3 membership.ShouldApplyV3) *Result {
 	// We first execute chain of Apply() calls down the hierarchy:
 	// (i.e. CorruptApplier -> CappedApplier -> Auth -> Quota -> Backend),
 	// then dispatch() unpacks the request to a specific method (like Put),
 	// that gets executed down the hierarchy again:
 	// i.e. CorruptApplier.Put(CappedApplier.Put(...(BackendApplier.Put(...)))).
-	return a.applyV3.Apply(r, shouldApplyV3, a.dispatch)
+	start := time.Now()
+	ar := a.applyV3.Apply(ctx, r, shouldApplyV3, a.dispatch)
+	if ar != nil {
+		ar.ApplyStart, ar.ApplyTook = start, time.Since(start)
+	}
+	return ar
 }
 
 // dispatch translates the request (r) into appropriate call (like Put) on
 // the underlying applyV3 object.
-func (a *uberApplier) dispatch(r *pb.InternalRaftRequest, shouldApplyV3 membership.ShouldApplyV3) *Result {
+func (a *uberApplier) dispatch(ctx context.Context, r *pb.InternalRaftRequest, shouldApplyV3 membership.ShouldApplyV3) *Result {
 	op := "unknown"
 	ar := &Result{}
+	ctx = withBackendTook(ctx, &ar.BackendTook)
 	defer func(start time.Time) {
 		success := ar.Err == nil || errors.Is(ar.Err, mvcc.ErrCompacted)
 		txn.ApplySecObserve(v3Version, op, success, time.Since(start))
@@ -130,16 +149,16 @@ func (a *uberApplier) dispatch(r *pb.InternalRaftRequest, shouldApplyV3 membersh
 	switch {
 	case r.Range != nil:
 		op = "Range"
-		ar.Resp, ar.Trace, ar.Err = a.applyV3.Range(r.Range)
+		ar.Resp, ar.Trace, ar.Err = a.applyV3.Range(ctx, r.Range)
 	case r.Put != nil:
 		op = "Put"
-		ar.Resp, ar.Trace, ar.Err = a.applyV3.Put(r.Put)
+		ar.Resp, ar.Trace, ar.Err = a.applyV3.Put(ctx, r.Put)
 	case r.DeleteRange != nil:
 		op = "DeleteRange"
-		ar.Resp, ar.Trace, ar.Err = a.applyV3.DeleteRange(r.DeleteRange)
+		ar.Resp, ar.Trace, ar.Err = a.applyV3.DeleteRange(ctx, r.DeleteRange)
 	case r.Txn != nil:
 		op = "Txn"
-		ar.Resp, ar.Trace, ar.Err = a.applyV3.Txn(r.Txn)
+		ar.Resp, ar.Trace, ar.Err = a.applyV3.Txn(ctx, r.Txn)
 	case r.Compaction != nil:
 		op = "Compaction"
 		ar.Resp, ar.Physc, ar.Trace, ar.Err = a.applyV3.Compaction(r.Compaction)
@@ -155,6 +174,9 @@ func (a *uberApplier) dispatch(r *pb.InternalRaftRequest, shouldApplyV3 membersh
 	case r.Alarm != nil:
 		op = "Alarm"
 		ar.Resp, ar.Err = a.Alarm(r.Alarm)
+	case r.ReadOnly != nil:
+		op = "ReadOnly"
+		ar.Resp, ar.Err = a.ReadOnly(r.ReadOnly)
 	case r.Authenticate != nil:
 		op = "Authenticate"
 		ar.Resp, ar.Err = a.applyV3.Authenticate(r.Authenticate)
@@ -166,6 +188,9 @@ func (a *uberApplier) dispatch(r *pb.InternalRaftRequest, shouldApplyV3 membersh
 		ar.Resp, ar.Err = a.applyV3.AuthDisable()
 	case r.AuthStatus != nil:
 		ar.Resp, ar.Err = a.applyV3.AuthStatus()
+	case r.AuthTokenRevoke != nil:
+		op = "AuthTokenRevoke"
+		ar.Resp, ar.Err = a.applyV3.TokenRevoke(r.AuthTokenRevoke)
 	case r.AuthUserAdd != nil:
 		op = "AuthUserAdd"
 		ar.Resp, ar.Err = a.applyV3.UserAdd(r.AuthUserAdd)
@@ -216,7 +241,17 @@ func (a *uberApplier) Alarm(ar *pb.AlarmRequest) (*pb.AlarmResponse, error) {
 
 	if ar.Action == pb.AlarmRequest_ACTIVATE ||
 		ar.Action == pb.AlarmRequest_DEACTIVATE {
-		a.restoreAlarms()
+		a.restoreAppliers()
+	}
+	return resp, err
+}
+
+func (a *uberApplier) ReadOnly(r *pb.ReadOnlyRequest) (*pb.ReadOnlyResponse, error) {
+	resp, err := a.applyV3.ReadOnly(r)
+	if resp != nil && resp.Enabled != a.readOnly {
+		a.readOnly = resp.Enabled
+		a.restoreAppliers()
+		a.lg.Info("changed read-only mode of the cluster", zap.Bool("enabled", a.readOnly))
 	}
 	return resp, err
 }
@@ -15,6 +15,7 @@
 package apply
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -124,7 +125,7 @@ func TestUberApplier_Alarm_Corrupt(t *testing.T) {
 	}
 
 	ua := defaultUberApplier(t)
-	result := ua.Apply(&pb.InternalRaftRequest{
+	result := ua.Apply(context.Background(), &pb.InternalRaftRequest{
 		Header: &pb.RequestHeader{},
 		Alarm: &pb.AlarmRequest{
 			Action:   pb.AlarmRequest_ACTIVATE,
@@ -137,7 +138,7 @@ func TestUberApplier_Alarm_Corrupt(t *testing.T) {
 
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
-			result = ua.Apply(tc.request, membership.ApplyBoth)
+			result = ua.Apply(context.Background(), tc.request, membership.ApplyBoth)
 			require.NotNil(t, result)
 			require.Equalf(t, tc.expectError, result.Err, "Apply: got %v, expect: %v", result.Err, tc.expectError)
 		})
@@ -226,7 +227,7 @@ func TestUberApplier_Alarm_Quota(t *testing.T) {
 	}
 
 	ua := defaultUberApplier(t)
-	result := ua.Apply(&pb.InternalRaftRequest{
+	result := ua.Apply(context.Background(), &pb.InternalRaftRequest{
 		Header: &pb.RequestHeader{},
 		Alarm: &pb.AlarmRequest{
 			Action:   pb.AlarmRequest_ACTIVATE,
@@ -239,7 +240,7 @@ func TestUberApplier_Alarm_Quota(t *testing.T) {
 
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
-			result = ua.Apply(tc.request, membership.ApplyBoth)
+			result = ua.Apply(context.Background(), tc.request, membership.ApplyBoth)
 			require.NotNil(t, result)
 			require.Equalf(t, tc.expectError, result.Err, "Apply: got %v, expect: %v", result.Err, tc.expectError)
 		})
@@ -249,7 +250,7 @@ func TestUberApplier_Alarm_Quota(t *testing.T) {
 // TestUberApplier_Alarm_Deactivate tests the applier should be able to apply after alarm is deactivated
 func TestUberApplier_Alarm_Deactivate(t *testing.T) {
 	ua := defaultUberApplier(t)
-	result := ua.Apply(&pb.InternalRaftRequest{
+	result := ua.Apply(context.Background(), &pb.InternalRaftRequest{
 		Header: &pb.RequestHeader{},
 		Alarm: &pb.AlarmRequest{
 			Action:   pb.AlarmRequest_ACTIVATE,
@@ -260,11 +261,11 @@ func TestUberApplier_Alarm_Deactivate(t *testing.T) {
 	require.NotNil(t, result)
 	require.NoError(t, result.Err)
 
-	result = ua.Apply(&pb.InternalRaftRequest{Put: &pb.PutRequest{Key: []byte(key)}}, membership.ApplyBoth)
+	result = ua.Apply(context.Background(), &pb.InternalRaftRequest{Put: &pb.PutRequest{Key: []byte(key)}}, membership.ApplyBoth)
 	require.NotNil(t, result)
 	require.Equalf(t, errors.ErrNoSpace, result.Err, "Apply: got %v, expect: %v", result.Err, errors.ErrNoSpace)
 
-	result = ua.Apply(&pb.InternalRaftRequest{
+	result = ua.Apply(context.Background(), &pb.InternalRaftRequest{
 		Header: &pb.RequestHeader{},
 		Alarm: &pb.AlarmRequest{
 			Action:   pb.AlarmRequest_DEACTIVATE,
@@ -275,7 +276,7 @@ func TestUberApplier_Alarm_Deactivate(t *testing.T) {
 	require.NotNil(t, result)
 	require.NoError(t, result.Err)
 
-	result = ua.Apply(&pb.InternalRaftRequest{Put: &pb.PutRequest{Key: []byte(key)}}, membership.ApplyBoth)
+	result = ua.Apply(context.Background(), &pb.InternalRaftRequest{Put: &pb.PutRequest{Key: []byte(key)}}, membership.ApplyBoth)
 	require.NotNil(t, result)
 	assert.NoError(t, result.Err)
 }
@@ -0,0 +1,85 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/server/v3/etcdserver/errors"
+)
+
+// updateApplyBacklog sets the apply backlog gauge to the number of committed
+// entries that are not applied yet. Each time the backlog rises above the
+// configured alert threshold, the threshold crossed counter is incremented;
+// it is not incremented again until the backlog has dropped back.
+func (s *EtcdServer) updateApplyBacklog() {
+	var backlog uint64
+	if ci, ai := s.getCommittedIndex(), s.getAppliedIndex(); ci > ai {
+		backlog = ci - ai
+	}
+	applyBacklog.Set(float64(backlog))
+	applyQueueDepth.Set(float64(s.applyQueue.Value()))
+
+	threshold := s.Cfg.ApplyBacklogAlertThreshold
+	if threshold == 0 {
+		return
+	}
+	if backlog <= threshold {
+		s.applyBacklogAlerting.Store(false)
+		return
+	}
+	if s.applyBacklogAlerting.CompareAndSwap(false, true) {
+		applyBacklogThresholdCrossed.Inc()
+		s.Logger().Warn(
+			"apply backlog crossed alert threshold",
+			zap.Uint64("apply-backlog", backlog),
+			zap.Uint64("apply-backlog-alert-threshold", threshold),
+		)
+	}
+}
+
+// waitApplyBacklog delays a new proposal while the apply backlog is above
+// the configured backpressure threshold, until enough committed entries are
+// applied to bring the backlog back to the threshold. The proposal is
+// rejected if the backlog does not drain within the request timeout.
+func (s *EtcdServer) waitApplyBacklog(ctx context.Context) error {
+	threshold := s.Cfg.ApplyBacklogBackpressureThreshold
+	if threshold == 0 {
+		return nil
+	}
+	ci, ai := s.getCommittedIndex(), s.getAppliedIndex()
+	if ci <= ai+threshold {
+		return nil
+	}
+	applyBackpressureDelays.Inc()
+
+	start := time.Now()
+	cctx, cancel := context.WithTimeout(ctx, s.Cfg.ReqTimeout())
+	defer cancel()
+	select {
+	case <-s.applyWait.Wait(ci - threshold):
+		return nil
+	case <-cctx.Done():
+		if ctx.Err() != nil {
+			return s.parseProposeCtxErr(ctx.Err(), start)
+		}
+		return errors.ErrTooManyRequests
+	case <-s.stopping:
+		return errors.ErrStopped
+	}
+}
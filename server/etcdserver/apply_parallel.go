@@ -0,0 +1,77 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"runtime"
+	"sync"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/etcdserver/txn"
+	"go.etcd.io/raft/v3/raftpb"
+)
+
+// maxPreparedEntries is the maximum number of entries prepared together.
+const maxPreparedEntries = 128
+
+// preparedEntry is a normal entry decoded, and with its request evaluated
+// ahead of its turn to be applied.
+type preparedEntry struct {
+	raftReq  pb.InternalRaftRequest
+	prepared *txn.Prepared
+}
+
+// prepareEntries evaluates the Put, DeleteRange and Txn requests at the head
+// of es concurrently, up to the first entry that is not one of them or that
+// conflicts with an entry before it. The entries are applied in order
+// afterwards, writing the outcome of their evaluation. A single entry is only
+// decoded, to be applied as usual.
+func (s *EtcdServer) prepareEntries(es []raftpb.Entry) []*preparedEntry {
+	var pes []*preparedEntry
+	fs := txn.NewFootprints()
+	for i := range es {
+		if len(pes) == maxPreparedEntries || es[i].Type != raftpb.EntryNormal || len(es[i].Data) == 0 {
+			break
+		}
+		pe := &preparedEntry{raftReq: s.unmarshalRaftRequest(es[i].Data)}
+		if pe.raftReq.Txn != nil && !s.w.IsRegistered(raftRequestID(&pe.raftReq)) {
+			removeNeedlessRangeReqs(pe.raftReq.Txn)
+		}
+		f, ok := txn.RequestFootprint(&pe.raftReq)
+		if !ok || !fs.Add(f) {
+			break
+		}
+		pes = append(pes, pe)
+	}
+	if len(pes) < 2 {
+		return pes
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, runtime.GOMAXPROCS(0))
+	for _, pe := range pes {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			pe.prepared = txn.Prepare(s.Logger(), s.lessor, s.KV(), &pe.raftReq)
+		}()
+	}
+	wg.Wait()
+	return pes
+}
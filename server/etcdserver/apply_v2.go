@@ -45,6 +45,7 @@ func v2ToV3Request(lg *zap.Logger, r *RequestV2) pb.InternalRaftRequest {
 				MemberAttributes: &membershippb.Attributes{
 					Name:       attr.Name,
 					ClientUrls: attr.ClientURLs,
+					IsWitness:  attr.IsWitness,
 				},
 			},
 		}
@@ -0,0 +1,210 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/pkg/v3/schedule"
+	"go.etcd.io/etcd/server/v3/etcdserver/errors"
+	"go.etcd.io/etcd/server/v3/lease"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+)
+
+const (
+	// autoDefragLockTTL is the TTL in seconds of the lease of the auto
+	// defragmentation lock, kept alive while the member defragments.
+	autoDefragLockTTL = 60
+	// autoDefragRetryInterval is the interval between two attempts to take
+	// the auto defragmentation lock held by another member.
+	autoDefragRetryInterval = time.Minute
+)
+
+// monitorAutoDefrag defragments the backend at the times of the
+// Cfg.AutoDefragSchedule cron expression, if its fragmentation exceeds
+// Cfg.AutoDefragRatio. The members take turns with a lock key, so that only
+// one of them defragments at a time: a member finding the lock held retries
+// until the next scheduled time.
+func (s *EtcdServer) monitorAutoDefrag() {
+	if s.Cfg.AutoDefragSchedule == "" {
+		return
+	}
+	lg := s.Logger()
+	cron, err := schedule.ParseCron(s.Cfg.AutoDefragSchedule)
+	if err != nil {
+		lg.Warn("invalid auto defragmentation schedule", zap.Error(err))
+		return
+	}
+	for next := cron.Next(time.Now()); !next.IsZero(); {
+		select {
+		case <-time.After(time.Until(next)):
+		case <-s.stopping:
+			lg.Info("server has stopped; stopping auto defragmentation")
+			return
+		}
+		next = cron.Next(time.Now())
+		for s.autoDefrag() && time.Until(next) > autoDefragRetryInterval {
+			select {
+			case <-time.After(autoDefragRetryInterval):
+			case <-s.stopping:
+				lg.Info("server has stopped; stopping auto defragmentation")
+				return
+			}
+		}
+	}
+}
+
+// autoDefrag defragments the backend if it is fragmented enough, moving
+// the leadership away first if the member is the leader. It returns true if
+// it should be retried because another member holds the lock.
+func (s *EtcdServer) autoDefrag() (retry bool) {
+	lg := s.Logger()
+	be := s.Backend()
+	ratio := fragmentation(be)
+	if ratio < s.Cfg.AutoDefragRatio {
+		lg.Info(
+			"skipped auto defragmentation; backend is not fragmented enough",
+			zap.Float64("fragmentation", ratio),
+			zap.Float64("auto-defrag-ratio", s.Cfg.AutoDefragRatio),
+		)
+		return false
+	}
+	// the leader of a single member cluster defragments, there is no other
+	// member to lead meanwhile.
+	if s.isLeader() && s.hasMultipleVotingMembers() && !s.Cfg.AutoDefragTransferLeadership {
+		lg.Info("skipped auto defragmentation; local member is leader")
+		return false
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+	id, ok, err := s.lockAutoDefrag(ctx)
+	if err != nil {
+		lg.Warn("failed to take auto defragmentation lock", zap.String("key", s.Cfg.AutoDefragLockKey), zap.Error(err))
+		return false
+	}
+	if !ok {
+		lg.Info("auto defragmentation lock is held by another member", zap.String("key", s.Cfg.AutoDefragLockKey))
+		return true
+	}
+	defer s.unlockAutoDefrag(id)
+	go s.keepAutoDefragLock(ctx, id)
+
+	if s.isLeader() && s.hasMultipleVotingMembers() {
+		if err = s.transferLeadershipForDefrag(); err != nil {
+			lg.Warn("skipped auto defragmentation; failed to transfer leadership", zap.Error(err))
+			return false
+		}
+	}
+
+	size, sizeInUse := be.Size(), be.SizeInUse()
+	start := time.Now()
+	if err = be.Defrag(); err != nil {
+		lg.Warn("failed auto defragmentation", zap.Error(err))
+		return false
+	}
+	lg.Info(
+		"finished auto defragmentation",
+		zap.Float64("fragmentation", ratio),
+		zap.Int64("reclaimed-bytes", size-be.Size()),
+		zap.Int64("size-in-use-diff", be.SizeInUse()-sizeInUse),
+		zap.Duration("took", time.Since(start)),
+	)
+	return false
+}
+
+// fragmentation returns the ratio of the size of the backend which is not
+// in use, and is reclaimed by defragmentation.
+func fragmentation(be backend.Backend) float64 {
+	size := be.Size()
+	if size <= 0 {
+		return 0
+	}
+	return float64(size-be.SizeInUse()) / float64(size)
+}
+
+// lockAutoDefrag creates the lock key with a lease, unless it exists. It
+// returns the lease and whether the lock is taken.
+func (s *EtcdServer) lockAutoDefrag(ctx context.Context) (lease.LeaseID, bool, error) {
+	rctx, cancel := context.WithTimeout(ctx, s.Cfg.ReqTimeout())
+	defer cancel()
+	lresp, err := s.LeaseGrant(rctx, &pb.LeaseGrantRequest{TTL: autoDefragLockTTL})
+	if err != nil {
+		return lease.NoLease, false, err
+	}
+	id := lease.LeaseID(lresp.ID)
+	key := []byte(s.Cfg.AutoDefragLockKey)
+	tresp, err := s.Txn(rctx, &pb.TxnRequest{
+		Compare: []*pb.Compare{{
+			Key:         key,
+			Target:      pb.Compare_CREATE,
+			Result:      pb.Compare_EQUAL,
+			TargetUnion: &pb.Compare_CreateRevision{CreateRevision: 0},
+		}},
+		Success: []*pb.RequestOp{{
+			Request: &pb.RequestOp_RequestPut{RequestPut: &pb.PutRequest{
+				Key:   key,
+				Value: []byte(s.MemberID().String()),
+				Lease: int64(id),
+			}},
+		}},
+	})
+	if err != nil || !tresp.Succeeded {
+		s.unlockAutoDefrag(id)
+		return lease.NoLease, false, err
+	}
+	return id, true, nil
+}
+
+// keepAutoDefragLock renews the lease of the lock until ctx is done.
+func (s *EtcdServer) keepAutoDefragLock(ctx context.Context, id lease.LeaseID) {
+	ticker := time.NewTicker(autoDefragLockTTL * time.Second / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		if _, err := s.LeaseRenew(ctx, id); err != nil && ctx.Err() == nil {
+			s.Logger().Warn("failed to renew auto defragmentation lock", zap.Error(err))
+		}
+	}
+}
+
+// unlockAutoDefrag revokes the lease of the lock, deleting the lock key.
+func (s *EtcdServer) unlockAutoDefrag(id lease.LeaseID) {
+	ctx, cancel := context.WithTimeout(s.ctx, s.Cfg.ReqTimeout())
+	defer cancel()
+	if _, err := s.LeaseRevoke(ctx, &pb.LeaseRevokeRequest{ID: int64(id)}); err != nil {
+		s.Logger().Warn("failed to release auto defragmentation lock", zap.Error(err))
+	}
+}
+
+// transferLeadershipForDefrag moves the leadership to the longest connected
+// voting member storing keys.
+func (s *EtcdServer) transferLeadershipForDefrag() error {
+	transferee, ok := longestConnected(s.r.transport, s.cluster.LeaderCandidateIDs())
+	if !ok {
+		return errors.ErrUnhealthy
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, s.Cfg.ReqTimeout())
+	defer cancel()
+	return s.MoveLeader(ctx, s.Lead(), uint64(transferee))
+}
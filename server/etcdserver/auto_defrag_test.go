@@ -0,0 +1,47 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.etcd.io/etcd/server/v3/storage/backend"
+)
+
+type fakeSizedBackend struct {
+	backend.Backend
+	size, sizeInUse int64
+}
+
+func (b *fakeSizedBackend) Size() int64      { return b.size }
+func (b *fakeSizedBackend) SizeInUse() int64 { return b.sizeInUse }
+
+func TestFragmentation(t *testing.T) {
+	tests := []struct {
+		size, sizeInUse int64
+		want            float64
+	}{
+		{0, 0, 0},
+		{100, 100, 0},
+		{100, 25, 0.75},
+		{4096, 1024, 0.75},
+	}
+	for _, tt := range tests {
+		be := &fakeSizedBackend{size: tt.size, sizeInUse: tt.sizeInUse}
+		assert.InDelta(t, tt.want, fragmentation(be), 1e-9)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+)
+
+// autoSnapshotTimeFormat makes the names of the snapshot files of a member
+// sort by time, so that the oldest ones are purged first.
+const autoSnapshotTimeFormat = "20060102T150405.000Z"
+
+// monitorAutoSnapshot saves a snapshot of the backend to Cfg.AutoSnapshotDir
+// every Cfg.AutoSnapshotInterval, then purges the snapshots of the member
+// beyond Cfg.AutoSnapshotRetention.
+func (s *EtcdServer) monitorAutoSnapshot() {
+	if s.Cfg.AutoSnapshotInterval <= 0 {
+		return
+	}
+	lg := s.Logger()
+	dir := s.Cfg.AutoSnapshotDir
+	// the member ID in the suffix keeps the members sharing the directory
+	// from purging the snapshots of each other.
+	suffix := fmt.Sprintf("-%s.db", s.MemberID())
+	for {
+		select {
+		case <-time.After(s.Cfg.AutoSnapshotInterval):
+		case <-s.stopping:
+			lg.Info("server has stopped; stopping auto snapshot")
+			return
+		}
+		if s.Cfg.AutoSnapshotLeaderOnly && !s.isLeader() {
+			continue
+		}
+		name := "etcd-snapshot-" + time.Now().UTC().Format(autoSnapshotTimeFormat) + suffix
+		start := time.Now()
+		size, err := saveSnapshotFile(s.Backend(), filepath.Join(dir, name))
+		if err != nil {
+			lg.Warn("failed to save auto snapshot", zap.String("path", filepath.Join(dir, name)), zap.Error(err))
+			continue
+		}
+		lg.Info(
+			"saved auto snapshot",
+			zap.String("path", filepath.Join(dir, name)),
+			zap.Int64("size", size),
+			zap.Duration("took", time.Since(start)),
+		)
+		if err = purgeSnapshotFiles(lg, dir, suffix, s.Cfg.AutoSnapshotRetention); err != nil {
+			lg.Warn("failed to purge auto snapshots", zap.String("dir", dir), zap.Error(err))
+		}
+	}
+}
+
+// purgeSnapshotFiles removes the oldest files of dir with the given suffix,
+// keeping the retention latest ones. All are kept if retention is 0.
+func purgeSnapshotFiles(lg *zap.Logger, dir, suffix string, retention uint) error {
+	if retention == 0 {
+		return nil
+	}
+	names, err := fileutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	var files []string
+	for _, name := range names {
+		if strings.HasSuffix(name, suffix) {
+			files = append(files, name)
+		}
+	}
+	for len(files) > int(retention) {
+		if err = os.Remove(filepath.Join(dir, files[0])); err != nil {
+			return err
+		}
+		lg.Info("purged auto snapshot", zap.String("path", filepath.Join(dir, files[0])))
+		files = files[1:]
+	}
+	return nil
+}
+
+// saveSnapshotFile saves a snapshot of be to path, followed by its sha256
+// integrity hash like "etcdctl snapshot save" does, so that it can be
+// restored with "etcdutl snapshot restore". It returns the size of the
+// snapshot.
+func saveSnapshotFile(be backend.Backend, path string) (int64, error) {
+	partPath := path + ".part"
+	f, err := os.OpenFile(partPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fileutil.PrivateFileMode)
+	if err != nil {
+		return 0, err
+	}
+	h := sha256.New()
+	snap := be.Snapshot()
+	n, err := snap.WriteTo(io.MultiWriter(f, h))
+	if cerr := snap.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		_, err = f.Write(h.Sum(nil))
+	}
+	if err == nil {
+		err = fileutil.Fsync(f)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(partPath, path)
+	}
+	if err != nil {
+		os.Remove(partPath)
+		return 0, err
+	}
+	return n, nil
+}
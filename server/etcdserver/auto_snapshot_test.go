@@ -0,0 +1,61 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+func TestSaveSnapshotFile(t *testing.T) {
+	be, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, be)
+	tx := be.BatchTx()
+	tx.LockOutsideApply()
+	schema.UnsafeCreateMetaBucket(tx)
+	tx.Unlock()
+	be.ForceCommit()
+
+	path := filepath.Join(t.TempDir(), "etcd-snapshot.db")
+	size, err := saveSnapshotFile(be, path)
+	require.NoError(t, err)
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, b, int(size)+sha256.Size)
+	sha := sha256.Sum256(b[:size])
+	assert.Equal(t, sha[:], b[size:])
+	assert.NoFileExists(t, path+".part")
+}
+
+func TestPurgeSnapshotFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"s-2-a.db", "s-1-a.db", "s-3-a.db", "s-1-b.db"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600))
+	}
+	require.NoError(t, purgeSnapshotFiles(zaptest.NewLogger(t), dir, "-a.db", 2))
+	names, err := fileutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"s-1-b.db", "s-2-a.db", "s-3-a.db"}, names)
+}
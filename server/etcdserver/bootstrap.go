@@ -102,6 +102,7 @@ func bootstrap(cfg config.ServerConfig) (b *bootstrappedServer, err error) {
 
 	if err = cluster.Finalize(cfg, s); err != nil {
 		backend.Close()
+		s.wal.w.Close()
 		return nil, err
 	}
 
@@ -455,6 +456,11 @@ func (c *bootstrappedCluster) Finalize(cfg config.ServerConfig, s *bootstrappedS
 			os.RemoveAll(bepath)
 			return fmt.Errorf("database file (%v) of the backend is missing", bepath)
 		}
+		// the backend of a witness has no keys, it would serve an empty
+		// keyspace as a normal member.
+		if m := c.cl.Member(c.nodeID); m != nil && m.IsWitness && !cfg.Witness {
+			return fmt.Errorf("member %s is a witness storing no keys, it must be started with --witness", c.nodeID)
+		}
 	}
 	scaleUpLearners := false
 	return membership.ValidateMaxLearnerConfig(cfg.MaxLearners, c.cl.Members(), scaleUpLearners)
@@ -603,6 +609,7 @@ func openWALFromSnapshot(cfg config.ServerConfig, snapshot *raftpb.Snapshot) (*w
 		if cfg.UnsafeNoFsync {
 			w.SetUnsafeNoFsync()
 		}
+		setWALFormat(cfg, w)
 		wmetadata, st, ents, err := w.ReadAll()
 		if err != nil {
 			w.Close()
@@ -627,6 +634,19 @@ func openWALFromSnapshot(cfg config.ServerConfig, snapshot *raftpb.Snapshot) (*w
 	}
 }
 
+// setWALFormat sets how the entries are saved to w. The WAL is encrypted with
+// the KEKs of the backend, if it is encrypted.
+func setWALFormat(cfg config.ServerConfig, w *wal.WAL) {
+	c, err := wal.ParseCompression(cfg.WALCompression)
+	if err != nil {
+		cfg.Logger.Fatal("failed to parse WAL compression", zap.Error(err))
+	}
+	w.SetCompression(c)
+	if cfg.EncryptionCodec != nil {
+		w.SetEncryption(cfg.EncryptionCodec.KEKProvider())
+	}
+}
+
 type snapshotMetadata struct {
 	nodeID, clusterID types.ID
 }
@@ -645,6 +665,7 @@ func bootstrapNewWAL(cfg config.ServerConfig, cl *bootstrappedCluster) *bootstra
 	if cfg.UnsafeNoFsync {
 		w.SetUnsafeNoFsync()
 	}
+	setWALFormat(cfg, w)
 	return &bootstrappedWAL{
 		lg: cfg.Logger,
 		w:  w,
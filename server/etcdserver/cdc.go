@@ -0,0 +1,121 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3cdc"
+)
+
+// cdcLeaderCheckInterval is the interval between two checks of whether the
+// member is the leader, which publishes the CDC events.
+const cdcLeaderCheckInterval = time.Second
+
+// cdcCheckpointKey returns the checkpoint key of the CDC sink at sinkURL,
+// named after a digest of the URL which may hold credentials.
+func cdcCheckpointKey(prefix, sinkURL string) string {
+	sum := sha256.Sum256([]byte(sinkURL))
+	return prefix + hex.EncodeToString(sum[:8])
+}
+
+// monitorCDC publishes the key-value events to the CDC sinks while the
+// member is the leader. The publication resumes from the checkpoint keys
+// on the next leader.
+func (s *EtcdServer) monitorCDC() {
+	if len(s.cdc) == 0 {
+		return
+	}
+	lg := s.Logger()
+	for {
+		select {
+		case <-time.After(cdcLeaderCheckInterval):
+		case <-s.stopping:
+			lg.Info("server has stopped; stopping change data capture")
+			return
+		}
+		if s.isLeader() {
+			s.publishCDC()
+		}
+	}
+}
+
+// publishCDC publishes the events to each CDC sink until the member loses
+// the leadership or stops.
+func (s *EtcdServer) publishCDC() {
+	lg := s.Logger()
+	ctx, cancel := context.WithCancel(s.ctx)
+	var wg sync.WaitGroup
+	for _, p := range s.cdc {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				if err := p.Run(ctx); err != nil && ctx.Err() == nil {
+					lg.Warn("failed to publish CDC events", zap.Error(err))
+					select {
+					case <-time.After(cdcLeaderCheckInterval):
+					case <-ctx.Done():
+					}
+				}
+			}
+		}()
+	}
+
+	for s.isLeader() {
+		select {
+		case <-time.After(cdcLeaderCheckInterval):
+			continue
+		case <-s.stopping:
+		}
+		break
+	}
+	cancel()
+	wg.Wait()
+}
+
+// cdcCheckpoint stores the published revision of a CDC sink in a key, so
+// that the next leader resumes the publication from it.
+type cdcCheckpoint struct {
+	s   *EtcdServer
+	key []byte
+}
+
+func (c *cdcCheckpoint) Load(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.s.Cfg.ReqTimeout())
+	defer cancel()
+	resp, err := c.s.Range(ctx, &pb.RangeRequest{Key: c.key})
+	if err != nil || len(resp.Kvs) == 0 {
+		return 0, err
+	}
+	return strconv.ParseInt(string(resp.Kvs[0].Value), 10, 64)
+}
+
+func (c *cdcCheckpoint) Save(ctx context.Context, rev int64) error {
+	ctx, cancel := context.WithTimeout(ctx, c.s.Cfg.ReqTimeout())
+	defer cancel()
+	_, err := c.s.Put(ctx, &pb.PutRequest{Key: c.key, Value: []byte(strconv.FormatInt(rev, 10))})
+	return err
+}
+
+var _ v3cdc.Checkpoint = (*cdcCheckpoint)(nil)
@@ -291,6 +291,65 @@ func getVersion(lg *zap.Logger, m *membership.Member, rt http.RoundTripper, time
 	return nil, err
 }
 
+// getMembers returns the member list the given member sees via its
+// peerURLs. Returns the last error if it fails to get the member list.
+func getMembers(ctx context.Context, lg *zap.Logger, m *membership.Member, rt http.RoundTripper, timeout time.Duration) ([]*membership.Member, error) {
+	cc := &http.Client{
+		Transport: rt,
+		Timeout:   timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	var (
+		err  error
+		req  *http.Request
+		resp *http.Response
+	)
+
+	for _, u := range m.PeerURLs {
+		addr := u + "/members"
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, addr, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err = cc.Do(req)
+		if err != nil {
+			lg.Warn(
+				"failed to reach the peer URL",
+				zap.String("address", addr),
+				zap.String("remote-member-id", m.ID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		var b []byte
+		b, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lg.Warn(
+				"failed to read body of response",
+				zap.String("address", addr),
+				zap.String("remote-member-id", m.ID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		var membs []*membership.Member
+		if err = json.Unmarshal(b, &membs); err != nil {
+			lg.Warn(
+				"failed to unmarshal response",
+				zap.String("address", addr),
+				zap.String("remote-member-id", m.ID.String()),
+				zap.Error(err),
+			)
+			continue
+		}
+		return membs, nil
+	}
+	return nil, err
+}
+
 func promoteMemberHTTP(ctx context.Context, url string, id uint64, peerRt http.RoundTripper) ([]*membership.Member, error) {
 	cc := &http.Client{
 		Transport: peerRt,
@@ -320,13 +379,16 @@ func promoteMemberHTTP(ctx context.Context, url string, id uint64, peerRt http.R
 		return nil, errors.ErrTimeout
 	}
 	if resp.StatusCode == http.StatusPreconditionFailed {
-		// both ErrMemberNotLearner and ErrLearnerNotReady have same http status code
+		// ErrMemberNotLearner, ErrMemberStandby and ErrLearnerNotReady have same http status code
 		if strings.Contains(string(b), errors.ErrLearnerNotReady.Error()) {
 			return nil, errors.ErrLearnerNotReady
 		}
 		if strings.Contains(string(b), membership.ErrMemberNotLearner.Error()) {
 			return nil, membership.ErrMemberNotLearner
 		}
+		if strings.Contains(string(b), membership.ErrMemberStandby.Error()) {
+			return nil, membership.ErrMemberStandby
+		}
 		return nil, fmt.Errorf("member promote: unknown error(%s)", b)
 	}
 	if resp.StatusCode == http.StatusNotFound {
@@ -0,0 +1,121 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+// watchCompactionControlKey compacts the key-value store to the revision
+// written to the configured control key. Every member watches the key, but
+// only the leader proposes the compaction; a member that becomes leader
+// proposes the latest requested revision that has not been compacted yet.
+func (s *EtcdServer) watchCompactionControlKey() {
+	lg := s.Logger()
+	key := []byte(s.Cfg.CompactionControlKey)
+
+	ws := s.Watchable().NewWatchStream()
+	defer ws.Close()
+	// watch before reading the current value so that no write is missed.
+	if _, err := ws.Watch(0, key, nil, 0); err != nil {
+		lg.Warn("failed to watch compaction control key", zap.String("key", string(key)), zap.Error(err))
+		return
+	}
+
+	var pending int64
+	request := func(kv *mvccpb.KeyValue) {
+		rev, err := parseCompactionControlValue(kv)
+		if err != nil {
+			lg.Warn(
+				"ignored invalid compaction control key value",
+				zap.String("key", string(key)),
+				zap.ByteString("value", kv.Value),
+				zap.Error(err),
+			)
+			return
+		}
+		if rev > pending {
+			pending = rev
+		}
+	}
+
+	if r, err := s.KV().Range(context.Background(), key, nil, mvcc.RangeOptions{}); err == nil && len(r.KVs) > 0 {
+		request(&r.KVs[0])
+	}
+	for {
+		if pending > 0 && s.isLeader() {
+			s.compactToControlRevision(pending)
+			// never retry a revision, otherwise a failing compaction would
+			// be proposed over and over again.
+			pending = 0
+		}
+
+		select {
+		case wr := <-ws.Chan():
+			for i := range wr.Events {
+				if wr.Events[i].Type == mvccpb.PUT {
+					request(wr.Events[i].Kv)
+				}
+			}
+		case <-s.leaderChanged.Receive():
+		case <-s.stopping:
+			return
+		}
+	}
+}
+
+// parseCompactionControlValue returns the revision requested by a write of
+// the compaction control key. The revision must not be newer than the write
+// itself, so that a write cannot request compacting itself away or keep
+// pointing at a revision that does not exist yet.
+func parseCompactionControlValue(kv *mvccpb.KeyValue) (int64, error) {
+	rev, err := strconv.ParseInt(strings.TrimSpace(string(kv.Value)), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if rev <= 0 {
+		return 0, errors.New("revision must be positive")
+	}
+	if rev >= kv.ModRevision {
+		return 0, errors.New("revision must be older than the revision of the control key write")
+	}
+	return rev, nil
+}
+
+func (s *EtcdServer) compactToControlRevision(rev int64) {
+	lg := s.Logger()
+	if rev <= s.KV().FirstRev() {
+		lg.Info("skipped compaction requested by control key; revision is already compacted", zap.Int64("revision", rev))
+		return
+	}
+	lg.Info("starting compaction requested by control key", zap.Int64("revision", rev))
+	ctx, cancel := context.WithTimeout(s.ctx, s.Cfg.ReqTimeout())
+	_, err := s.Compact(ctx, &pb.CompactionRequest{Revision: rev})
+	cancel()
+	if err != nil && !errors.Is(err, mvcc.ErrCompacted) {
+		lg.Warn("failed compaction requested by control key", zap.Int64("revision", rev), zap.Error(err))
+		return
+	}
+	lg.Info("completed compaction requested by control key", zap.Int64("revision", rev))
+}
@@ -33,6 +33,7 @@ import (
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	"go.etcd.io/etcd/client/pkg/v3/types"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/rafthttp"
+	servererrors "go.etcd.io/etcd/server/v3/etcdserver/errors"
 	"go.etcd.io/etcd/server/v3/storage/mvcc"
 )
 
@@ -46,6 +47,9 @@ type corruptionChecker struct {
 	lg *zap.Logger
 
 	hasher Hasher
+	// quarantine quarantines the members identified as corrupted by the
+	// compact hash check, instead of raising corruption alarms.
+	quarantine bool
 
 	mux                   sync.RWMutex
 	latestRevisionChecked int64
@@ -58,12 +62,14 @@ type Hasher interface {
 	PeerHashByRev(int64) []*peerHashKVResp
 	LinearizableReadNotify(context.Context) error
 	TriggerCorruptAlarm(types.ID)
+	TriggerQuarantineAlarm(types.ID)
 }
 
 func newCorruptionChecker(lg *zap.Logger, s *EtcdServer, storage mvcc.HashStorage) *corruptionChecker {
 	return &corruptionChecker{
-		lg:     lg,
-		hasher: hasherAdapter{s, storage},
+		lg:         lg,
+		hasher:     hasherAdapter{s, storage},
+		quarantine: s.Cfg.CompactHashCheckQuarantine,
 	}
 }
 
@@ -84,6 +90,10 @@ func (h hasherAdapter) TriggerCorruptAlarm(memberID types.ID) {
 	h.EtcdServer.triggerCorruptAlarm(memberID)
 }
 
+func (h hasherAdapter) TriggerQuarantineAlarm(memberID types.ID) {
+	h.EtcdServer.triggerQuarantineAlarm(memberID)
+}
+
 // InitialCheck compares initial hash values with its peers
 // before serving any peer/client traffic. Only mismatch when hashes
 // are different at requested revision, with same compact revision.
@@ -366,12 +376,17 @@ func (cm *corruptionChecker) checkPeerHashes(leaderHash mvcc.KeyValueHash, peers
 		cm.hasher.TriggerCorruptAlarm(0)
 	}
 
-	// Raise alarm for the left members if the quorum is present.
-	// But we should always generate error log for debugging.
+	// Raise alarm for the left members if the quorum is present, or
+	// quarantine them if configured so. But we should always generate error
+	// log for debugging.
 	for k, v := range hash2members {
 		if quorumExist {
 			for _, pid := range v {
-				cm.hasher.TriggerCorruptAlarm(pid)
+				if cm.quarantine {
+					cm.hasher.TriggerQuarantineAlarm(pid)
+				} else {
+					cm.hasher.TriggerCorruptAlarm(pid)
+				}
 			}
 		}
 
@@ -442,6 +457,32 @@ func (s *EtcdServer) triggerCorruptAlarm(id types.ID) {
 	})
 }
 
+// triggerQuarantineAlarm quarantines the member, which then rejects client
+// requests until the alarm is disarmed.
+func (s *EtcdServer) triggerQuarantineAlarm(id types.ID) {
+	a := &pb.AlarmRequest{
+		MemberID: uint64(id),
+		Action:   pb.AlarmRequest_ACTIVATE,
+		Alarm:    pb.AlarmType_QUARANTINE,
+	}
+	s.GoAttach(func() {
+		s.raftRequest(s.ctx, pb.InternalRaftRequest{Alarm: a})
+	})
+}
+
+// IsQuarantined returns true if the local member is quarantined.
+func (s *EtcdServer) IsQuarantined() bool {
+	if s.alarmStore == nil {
+		return false
+	}
+	for _, a := range s.alarmStore.Get(pb.AlarmType_QUARANTINE) {
+		if types.ID(a.MemberID) == s.MemberID() {
+			return true
+		}
+	}
+	return false
+}
+
 type peerInfo struct {
 	id  types.ID
 	eps []string
@@ -459,7 +500,8 @@ func (s *EtcdServer) getPeerHashKVs(rev int64) []*peerHashKVResp {
 	members := s.cluster.Members()
 	peers := make([]peerInfo, 0, len(members))
 	for _, m := range members {
-		if m.ID == s.MemberID() {
+		// the witnesses store no keys to hash.
+		if m.ID == s.MemberID() || m.IsWitness {
 			continue
 		}
 		peers = append(peers, peerInfo{id: m.ID, eps: m.PeerURLs})
@@ -533,6 +575,10 @@ func (h *hashKVHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, rafthttp.ErrClusterIDMismatch.Error(), http.StatusPreconditionFailed)
 		return
 	}
+	if h.server.Cfg.Witness {
+		http.Error(w, servererrors.ErrNotSupportedForWitness.Error(), http.StatusServiceUnavailable)
+		return
+	}
 
 	defer r.Body.Close()
 	b, err := io.ReadAll(r.Body)
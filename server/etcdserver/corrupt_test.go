@@ -252,6 +252,7 @@ func TestCompactHashCheck(t *testing.T) {
 		name                string
 		hasher              fakeHasher
 		lastRevisionChecked int64
+		quarantine          bool
 
 		expectError               bool
 		expectCorrupt             bool
@@ -325,6 +326,19 @@ func TestCompactHashCheck(t *testing.T) {
 			expectActions: []string{"MemberID()", "ReqTimeout()", "Hashes()", "PeerHashByRev(2)", "MemberID()", "TriggerCorruptAlarm(43)"},
 			expectCorrupt: true,
 		},
+		{
+			name: "Etcd quarantines single corrupted member in 3 member cluster",
+			hasher: fakeHasher{
+				hashes: []mvcc.KeyValueHash{{Revision: 1, CompactRevision: 1, Hash: 2}, {Revision: 2, CompactRevision: 1, Hash: 2}},
+				peerHashes: []*peerHashKVResp{
+					{peerInfo: peerInfo{id: 42}, resp: &pb.HashKVResponse{CompactRevision: 1, Hash: 2}},
+					{peerInfo: peerInfo{id: 43}, resp: &pb.HashKVResponse{CompactRevision: 1, Hash: 3}},
+				},
+			},
+			quarantine:    true,
+			expectActions: []string{"MemberID()", "ReqTimeout()", "Hashes()", "PeerHashByRev(2)", "MemberID()", "TriggerQuarantineAlarm(43)"},
+			expectCorrupt: true,
+		},
 		{
 			name: "Etcd can identify single corrupted member in 5 member cluster",
 			hasher: fakeHasher{
@@ -351,6 +365,19 @@ func TestCompactHashCheck(t *testing.T) {
 			expectActions: []string{"MemberID()", "ReqTimeout()", "Hashes()", "PeerHashByRev(2)", "MemberID()", "TriggerCorruptAlarm(0)"},
 			expectCorrupt: true,
 		},
+		{
+			name: "Etcd triggers corrupted alarm on whole cluster if it cannot identify the member to quarantine",
+			hasher: fakeHasher{
+				hashes: []mvcc.KeyValueHash{{Revision: 1, CompactRevision: 1, Hash: 2}, {Revision: 2, CompactRevision: 1, Hash: 2}},
+				peerHashes: []*peerHashKVResp{
+					{err: fmt.Errorf("failed getting hash")},
+					{peerInfo: peerInfo{id: 43}, resp: &pb.HashKVResponse{CompactRevision: 1, Hash: 3}},
+				},
+			},
+			quarantine:    true,
+			expectActions: []string{"MemberID()", "ReqTimeout()", "Hashes()", "PeerHashByRev(2)", "MemberID()", "TriggerCorruptAlarm(0)"},
+			expectCorrupt: true,
+		},
 		{
 			name: "Etcd triggers corrupted alarm on whole cluster if no quorum in 5 member cluster",
 			hasher: fakeHasher{
@@ -428,6 +455,7 @@ func TestCompactHashCheck(t *testing.T) {
 				latestRevisionChecked: tc.lastRevisionChecked,
 				lg:                    zaptest.NewLogger(t),
 				hasher:                &tc.hasher,
+				quarantine:            tc.quarantine,
 			}
 			monitor.CompactHashCheck()
 			if tc.hasher.alarmTriggered != tc.expectCorrupt {
@@ -507,6 +535,11 @@ func (f *fakeHasher) TriggerCorruptAlarm(memberID types.ID) {
 	f.alarmTriggered = true
 }
 
+func (f *fakeHasher) TriggerQuarantineAlarm(memberID types.ID) {
+	f.actions = append(f.actions, fmt.Sprintf("TriggerQuarantineAlarm(%d)", memberID))
+	f.alarmTriggered = true
+}
+
 func TestHashKVHandler(t *testing.T) {
 	remoteClusterID := 111195
 	localClusterID := 111196
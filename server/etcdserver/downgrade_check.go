@@ -0,0 +1,215 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/coreos/go-semver/semver"
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/client/pkg/v3/types"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/rafthttp"
+	serverversion "go.etcd.io/etcd/server/v3/etcdserver/version"
+	"go.etcd.io/etcd/server/v3/features"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+// PeerDowngradeCheckPath is the peer path checking a member against a
+// downgrade target version.
+const PeerDowngradeCheckPath = "/members/downgrade/check"
+
+// DowngradeCheck reports what blocks the cluster from being downgraded to the
+// version of the request, without enabling the downgrade: the downgrade must
+// be valid for the cluster version, and the storage schema, the WAL entries
+// and the enabled features of every member must be supported by the target
+// version.
+func (s *EtcdServer) DowngradeCheck(ctx context.Context, r *pb.DowngradeCheckRequest) (*pb.DowngradeCheckResponse, error) {
+	target, err := convertToClusterVersion(r.Version)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.DowngradeCheckResponse{Header: &pb.ResponseHeader{}}
+	if err = s.Version().DowngradeValidate(ctx, target); err != nil {
+		if !errors.Is(err, serverversion.ErrInvalidDowngradeTargetVersion) && !errors.Is(err, serverversion.ErrDowngradeInProcess) {
+			return nil, err
+		}
+		resp.Blockers = append(resp.Blockers, err.Error())
+	}
+
+	cc := &http.Client{
+		Transport: s.peerRt,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	for _, m := range s.cluster.Members() {
+		if m.ID == s.MemberID() {
+			resp.Members = append(resp.Members, s.checkDowngrade(*target))
+		} else {
+			resp.Members = append(resp.Members, s.getPeerDowngradeCheck(cc, m, r))
+		}
+	}
+	resp.Downgradable = len(resp.Blockers) == 0 && !slices.ContainsFunc(resp.Members, func(c *pb.MemberDowngradeCheck) bool {
+		return len(c.Blockers) != 0
+	})
+	return resp, nil
+}
+
+// checkDowngrade checks the storage schema, the WAL entries and the enabled
+// features of the local member against the downgrade target version.
+func (s *EtcdServer) checkDowngrade(target semver.Version) *pb.MemberDowngradeCheck {
+	c := &pb.MemberDowngradeCheck{MemberId: uint64(s.MemberID())}
+	if sv := s.StorageVersion(); sv == nil {
+		c.Blockers = append(c.Blockers, "cannot detect the storage schema version")
+	} else {
+		c.StorageVersion = sv.String()
+		if err := schema.ValidateMigration(s.lg, *sv, target); err != nil {
+			c.Blockers = append(c.Blockers, fmt.Sprintf("cannot migrate the storage schema from %s to %s: %v", sv, target, err))
+		}
+	}
+	// the entries since the last snapshot are replayed by the downgraded member
+	if wv := s.r.storage.MinimalEtcdVersion(); wv != nil {
+		c.WalVersion = wv.String()
+		if target.LessThan(*wv) {
+			c.Blockers = append(c.Blockers, fmt.Sprintf("the WAL contains entries requiring etcd %s", wv))
+		}
+	}
+	c.Features = features.EnabledAfter(s.Cfg.ServerFeatureGate, target)
+	for _, f := range c.Features {
+		c.Blockers = append(c.Blockers, fmt.Sprintf("the feature %s is enabled", f))
+	}
+	return c
+}
+
+// getPeerDowngradeCheck checks the peer m against the downgrade target
+// version. A peer that cannot be checked is reported with a blocker.
+func (s *EtcdServer) getPeerDowngradeCheck(cc *http.Client, m *membership.Member, r *pb.DowngradeCheckRequest) *pb.MemberDowngradeCheck {
+	lastErr := errors.New("no peer URL")
+	for _, ep := range m.PeerURLs {
+		ctx, cancel := context.WithTimeout(context.Background(), s.Cfg.ReqTimeout())
+		c, err := checkPeerDowngrade(ctx, s.cluster.ID(), cc, ep, r)
+		cancel()
+		if err == nil {
+			return c
+		}
+		lastErr = err
+		s.Logger().Warn(
+			"failed downgrade check request",
+			zap.String("local-member-id", s.MemberID().String()),
+			zap.String("remote-peer-endpoint", ep),
+			zap.Error(err),
+		)
+	}
+	return &pb.MemberDowngradeCheck{
+		MemberId: uint64(m.ID),
+		Blockers: []string{fmt.Sprintf("cannot check the member: %v", lastErr)},
+	}
+}
+
+type downgradeCheckHandler struct {
+	lg     *zap.Logger
+	server *EtcdServer
+}
+
+func (s *EtcdServer) DowngradeCheckHandler() http.Handler {
+	return &downgradeCheckHandler{lg: s.Logger(), server: s}
+}
+
+func (h *downgradeCheckHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if r.URL.Path != PeerDowngradeCheckPath {
+		http.Error(w, "bad path", http.StatusBadRequest)
+		return
+	}
+	if gcid := r.Header.Get("X-Etcd-Cluster-ID"); gcid != "" && gcid != h.server.cluster.ID().String() {
+		http.Error(w, rafthttp.ErrClusterIDMismatch.Error(), http.StatusPreconditionFailed)
+		return
+	}
+
+	defer r.Body.Close()
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "error reading body", http.StatusBadRequest)
+		return
+	}
+	req := &pb.DowngradeCheckRequest{}
+	if err = json.Unmarshal(b, req); err != nil {
+		h.lg.Warn("failed to unmarshal request", zap.Error(err))
+		http.Error(w, "error unmarshalling request", http.StatusBadRequest)
+		return
+	}
+	target, err := convertToClusterVersion(req.Version)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	respBytes, err := json.Marshal(h.server.checkDowngrade(*target))
+	if err != nil {
+		h.lg.Warn("failed to marshal downgrade check response", zap.Error(err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("X-Etcd-Cluster-ID", h.server.Cluster().ID().String())
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(respBytes)
+}
+
+// checkPeerDowngrade checks the member with the peer URL url against the
+// downgrade target version of r via an http call.
+func checkPeerDowngrade(ctx context.Context, cid types.ID, cc *http.Client, url string, r *pb.DowngradeCheckRequest) (*pb.MemberDowngradeCheck, error) {
+	reqBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url+PeerDowngradeCheckPath, bytes.NewReader(reqBytes))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Etcd-Cluster-ID", cid.String())
+
+	resp, err := cc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(b))
+	}
+	c := &pb.MemberDowngradeCheck{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
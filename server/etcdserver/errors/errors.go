@@ -35,13 +35,20 @@ var (
 	ErrNotLeader                   = errors.New("etcdserver: not leader")
 	ErrRequestTooLarge             = errors.New("etcdserver: request is too large")
 	ErrNoSpace                     = errors.New("etcdserver: no space")
+	ErrKeyQuotaExceeded            = errors.New("etcdserver: key quota exceeded")
+	ErrReadOnly                    = errors.New("etcdserver: cluster is in read-only mode")
 	ErrTooManyRequests             = errors.New("etcdserver: too many requests")
 	ErrUnhealthy                   = errors.New("etcdserver: unhealthy cluster")
 	ErrCorrupt                     = errors.New("etcdserver: corrupt cluster")
+	ErrNotSupportedForWitness      = errors.New("etcdserver: rpc not supported for witness")
+	ErrClusterVersionTooLow        = errors.New("etcdserver: request not supported by the cluster version")
+	ErrMemberQuarantined           = errors.New("etcdserver: member quarantined after its data diverged")
 	ErrBadLeaderTransferee         = errors.New("etcdserver: bad leader transferee")
 	ErrClusterVersionUnavailable   = errors.New("etcdserver: cluster version not found during downgrade")
 	ErrWrongDowngradeVersionFormat = errors.New("etcdserver: wrong downgrade target version format")
 	ErrKeyNotFound                 = errors.New("etcdserver: key not found")
+	ErrKeyAccessTrackingDisabled   = errors.New("etcdserver: key access tracking is disabled")
+	ErrEncryptionDisabled          = errors.New("etcdserver: backend encryption is disabled")
 )
 
 type DiscoveryError struct {
@@ -0,0 +1,64 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"context"
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+// expensiveRangeLimiter bounds the number of expensive ranges read at a
+// time. A large range holds its backend read transaction, and the boltdb
+// read transaction of its batch interval, for as long as it reads: many of
+// them at once keep the writes from committing.
+type expensiveRangeLimiter struct {
+	sem   chan struct{}
+	limit int64
+}
+
+func newExpensiveRangeLimiter(maxConcurrent int, limit int64) *expensiveRangeLimiter {
+	return &expensiveRangeLimiter{sem: make(chan struct{}, maxConcurrent), limit: limit}
+}
+
+// expensive returns true if r is a range over multiple keys without a
+// limit, or with a limit above l.limit, which does not return only the count.
+func (l *expensiveRangeLimiter) expensive(r *pb.RangeRequest) bool {
+	if len(r.RangeEnd) == 0 || r.CountOnly {
+		return false
+	}
+	return r.Limit == 0 || r.Limit > l.limit
+}
+
+// acquire waits until r may be read if it is expensive, and returns the
+// function to call once it is read. A nil limiter does not bound the ranges.
+func (l *expensiveRangeLimiter) acquire(ctx context.Context, r *pb.RangeRequest) (func(), error) {
+	if l == nil || !l.expensive(r) {
+		return func() {}, nil
+	}
+	start := time.Now()
+	select {
+	case l.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	expensiveRangeWaitSec.Observe(time.Since(start).Seconds())
+	expensiveRangesInflight.Inc()
+	return func() {
+		expensiveRangesInflight.Dec()
+		<-l.sem
+	}, nil
+}
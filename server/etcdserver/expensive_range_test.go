@@ -0,0 +1,90 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+func TestExpensiveRangeLimiterExpensive(t *testing.T) {
+	l := newExpensiveRangeLimiter(1, 100)
+	tests := []struct {
+		name string
+		r    *pb.RangeRequest
+		want bool
+	}{
+		{"single key", &pb.RangeRequest{Key: []byte("a")}, false},
+		{"unlimited range", &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("b")}, true},
+		{"range under the limit", &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("b"), Limit: 100}, false},
+		{"range above the limit", &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("b"), Limit: 101}, true},
+		{"count only", &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("b"), CountOnly: true}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, l.expensive(tt.r))
+		})
+	}
+}
+
+func TestExpensiveRangeLimiterAcquire(t *testing.T) {
+	l := newExpensiveRangeLimiter(1, 0)
+	expensive := &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("b")}
+
+	release, err := l.acquire(t.Context(), expensive)
+	require.NoError(t, err)
+
+	// the ranges which are not expensive are not bounded
+	cheap, err := l.acquire(t.Context(), &pb.RangeRequest{Key: []byte("a")})
+	require.NoError(t, err)
+	cheap()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+	_, err = l.acquire(ctx, expensive)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	acquired := make(chan func())
+	go func() {
+		r, aerr := l.acquire(t.Context(), expensive)
+		if aerr == nil {
+			acquired <- r
+		}
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("acquired an expensive range while another one is read")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release()
+	select {
+	case r := <-acquired:
+		r()
+	case <-time.After(5 * time.Second):
+		t.Fatal("the expensive range was not acquired once released")
+	}
+
+	// a nil limiter does not bound the ranges
+	var nilLimiter *expensiveRangeLimiter
+	r, err := nilLimiter.acquire(t.Context(), expensive)
+	require.NoError(t, err)
+	r()
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/client/pkg/v3/types"
+	"go.etcd.io/etcd/server/v3/etcdserver/errors"
+	"go.etcd.io/raft/v3"
+)
+
+// FollowerLag reports how far each follower lags behind the leader. It
+// returns ErrNotLeader if the member is not the leader.
+func (s *EtcdServer) FollowerLag(ctx context.Context) (*pb.FollowerLagResponse, error) {
+	rs := s.raftStatus()
+	// leader's raftStatus.Progress is not nil
+	if rs.Progress == nil {
+		return nil, errors.ErrNotLeader
+	}
+	return &pb.FollowerLagResponse{
+		Header:      &pb.ResponseHeader{},
+		CommitIndex: rs.Commit,
+		Followers:   followerLags(rs),
+	}, nil
+}
+
+// followerLags returns the lag of the followers, sorted by ID, from the raft
+// status of the leader.
+func followerLags(rs raft.Status) []*pb.FollowerLag {
+	var lags []*pb.FollowerLag
+	for id, pr := range rs.Progress {
+		if id == rs.ID {
+			continue
+		}
+		var lag uint64
+		if pr.Match < rs.Commit {
+			lag = rs.Commit - pr.Match
+		}
+		lags = append(lags, &pb.FollowerLag{
+			ID:         id,
+			IsLearner:  pr.IsLearner,
+			MatchIndex: pr.Match,
+			Lag:        lag,
+			State:      pr.State.String(),
+			Active:     pr.RecentActive,
+		})
+	}
+	sort.Slice(lags, func(i, j int) bool { return lags[i].ID < lags[j].ID })
+	return lags
+}
+
+// monitorFollowerLag reports, while the member is the leader, the lag of the
+// followers in the follower_lag_entries metric. If
+// Cfg.FollowerLagAlarmThreshold is set, it raises a FOLLOWER_LAG alarm for
+// the voting followers lagging by more entries for
+// Cfg.FollowerLagAlarmDuration, and clears it once they caught up.
+func (s *EtcdServer) monitorFollowerLag() {
+	lagging := make(map[uint64]time.Time)
+	reported := make(map[uint64]struct{})
+	for {
+		select {
+		case <-time.After(s.Cfg.ElectionTimeout()):
+		case <-s.stopping:
+			followerLagEntries.Reset()
+			return
+		}
+		rs := s.raftStatus()
+		if rs.Progress == nil {
+			clear(lagging)
+			clear(reported)
+			followerLagEntries.Reset()
+			continue
+		}
+		lags := followerLags(rs)
+		current := make(map[uint64]struct{}, len(lags))
+		for _, f := range lags {
+			current[f.ID] = struct{}{}
+			reported[f.ID] = struct{}{}
+			followerLagEntries.WithLabelValues(types.ID(f.ID).String()).Set(float64(f.Lag))
+		}
+		for id := range reported {
+			if _, ok := current[id]; !ok {
+				delete(reported, id)
+				followerLagEntries.DeleteLabelValues(types.ID(id).String())
+			}
+		}
+		if s.Cfg.FollowerLagAlarmThreshold != 0 {
+			s.checkFollowerLagAlarms(lags, lagging, time.Now())
+		}
+	}
+}
+
+// checkFollowerLagAlarms raises the FOLLOWER_LAG alarm of the voting
+// followers lagging behind the threshold for long enough, and clears the
+// alarm of the members which caught up or are no longer followers.
+func (s *EtcdServer) checkFollowerLagAlarms(lags []*pb.FollowerLag, lagging map[uint64]time.Time, now time.Time) {
+	lg := s.Logger()
+	alarmed := make(map[uint64]bool)
+	for _, a := range s.alarmStore.Get(pb.AlarmType_FOLLOWER_LAG) {
+		alarmed[a.MemberID] = true
+	}
+	behind := make(map[uint64]struct{})
+	for _, f := range lags {
+		if f.IsLearner || f.Lag <= s.Cfg.FollowerLagAlarmThreshold {
+			delete(lagging, f.ID)
+			continue
+		}
+		behind[f.ID] = struct{}{}
+		since, ok := lagging[f.ID]
+		if !ok {
+			lagging[f.ID] = now
+		}
+		if ok && !alarmed[f.ID] && now.Sub(since) >= s.Cfg.FollowerLagAlarmDuration {
+			lg.Warn(
+				"follower lags behind the leader; raising alarm",
+				zap.String("follower-member-id", types.ID(f.ID).String()),
+				zap.Uint64("lag", f.Lag),
+				zap.Uint64("threshold", s.Cfg.FollowerLagAlarmThreshold),
+				zap.Duration("lagging-for", now.Sub(since)),
+			)
+			followerLagAlarms.Inc()
+			s.requestFollowerLagAlarm(f.ID, pb.AlarmRequest_ACTIVATE)
+		}
+	}
+	for id := range lagging {
+		if _, ok := behind[id]; !ok {
+			delete(lagging, id)
+		}
+	}
+	for id := range alarmed {
+		if _, ok := behind[id]; ok {
+			continue
+		}
+		lg.Info("follower caught up with the leader; clearing alarm", zap.String("follower-member-id", types.ID(id).String()))
+		s.requestFollowerLagAlarm(id, pb.AlarmRequest_DEACTIVATE)
+	}
+}
+
+func (s *EtcdServer) requestFollowerLagAlarm(id uint64, action pb.AlarmRequest_AlarmAction) {
+	a := &pb.AlarmRequest{
+		MemberID: id,
+		Action:   action,
+		Alarm:    pb.AlarmType_FOLLOWER_LAG,
+	}
+	s.GoAttach(func() {
+		ctx, cancel := context.WithTimeout(s.ctx, s.Cfg.ReqTimeout())
+		defer cancel()
+		if _, err := s.raftRequest(ctx, pb.InternalRaftRequest{Alarm: a}); err != nil {
+			s.Logger().Warn(
+				"failed to update follower lag alarm",
+				zap.String("follower-member-id", types.ID(id).String()),
+				zap.String("action", action.String()),
+				zap.Error(err),
+			)
+		}
+	})
+}
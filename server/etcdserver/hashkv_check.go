@@ -0,0 +1,92 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/client/pkg/v3/types"
+	servererrors "go.etcd.io/etcd/server/v3/etcdserver/errors"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+// hashKVCheckRetryInterval is the wait before asking the members that have
+// not applied the checked revision yet for their hash again.
+const hashKVCheckRetryInterval = 100 * time.Millisecond
+
+// MemberHashKV is the hash of the key-value store of a member of the cluster.
+type MemberHashKV struct {
+	MemberID types.ID
+	Hash     mvcc.KeyValueHash
+	// Err is set if the hash could not be computed on the member.
+	Err error
+}
+
+// CheckHashKV computes the hash of the key-value store of every member of
+// the cluster up to the same revision, the local member first. If rev is 0,
+// the latest compacted revision of the local member is hashed, since its hash
+// does not change with later writes, or its latest revision if the key-value
+// store was never compacted. It returns the hashed revision.
+func (s *EtcdServer) CheckHashKV(ctx context.Context, rev int64) (int64, []MemberHashKV, error) {
+	if s.Cfg.Witness {
+		return 0, nil, servererrors.ErrNotSupportedForWitness
+	}
+	if rev == 0 {
+		if err := s.linearizableReadNotify(ctx); err != nil {
+			return 0, nil, err
+		}
+		rev = s.KV().FirstRev()
+		if rev <= 0 {
+			rev = s.KV().Rev()
+		}
+	}
+
+	hash, _, err := s.KV().HashStorage().HashByRev(rev)
+	if err != nil {
+		return 0, nil, err
+	}
+	hashes := []MemberHashKV{{MemberID: s.MemberID(), Hash: hash}}
+
+	var peers []*peerHashKVResp
+	for {
+		peers = s.getPeerHashKVs(rev)
+		// the local member may be ahead of the peers that have not applied
+		// the revision yet.
+		behind := slices.ContainsFunc(peers, func(p *peerHashKVResp) bool {
+			return errors.Is(p.err, rpctypes.ErrFutureRev)
+		})
+		if !behind {
+			break
+		}
+		select {
+		case <-time.After(hashKVCheckRetryInterval):
+			continue
+		case <-ctx.Done():
+		}
+		break
+	}
+	for _, p := range peers {
+		h := MemberHashKV{MemberID: p.id, Err: p.err}
+		if p.resp != nil {
+			h.Hash = mvcc.KeyValueHash{Hash: p.resp.Hash, CompactRevision: p.resp.CompactRevision, Revision: p.resp.HashRevision}
+		}
+		hashes = append(hashes, h)
+	}
+	return rev, hashes, nil
+}
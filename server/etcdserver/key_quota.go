@@ -0,0 +1,187 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"bytes"
+	"context"
+	"slices"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/auth"
+	"go.etcd.io/etcd/server/v3/etcdserver/errors"
+	serverstorage "go.etcd.io/etcd/server/v3/storage"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+// keyQuotaChecker rejects the puts that would take the keys of a prefix, or
+// of the key ranges a role of the user is permitted to write, over their
+// quota. Like the backend quota, it is checked before the request is
+// proposed, so that all the members apply the same writes whatever their
+// quotas.
+type keyQuotaChecker struct {
+	kv     mvcc.KV
+	as     auth.AuthStore
+	quotas []serverstorage.KeyQuota
+}
+
+// checkKeyQuotas returns ErrKeyQuotaExceeded if the puts of a Put or of a
+// write Txn, on either branch, take the keys of a quota over its bounds.
+func (s *EtcdServer) checkKeyQuotas(ctx context.Context, r *pb.InternalRaftRequest) error {
+	if len(s.keyQuotas) == 0 {
+		return nil
+	}
+	var puts []*pb.PutRequest
+	switch {
+	case r.Put != nil:
+		puts = []*pb.PutRequest{r.Put}
+	case r.Txn != nil:
+		puts = txnPuts(nil, r.Txn.Success)
+		puts = txnPuts(puts, r.Txn.Failure)
+	}
+	if len(puts) == 0 {
+		return nil
+	}
+	authInfo, err := s.AuthInfoFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+	c := keyQuotaChecker{kv: s.KV(), as: s.authStore, quotas: s.keyQuotas}
+	return c.check(ctx, authInfo, puts)
+}
+
+func txnPuts(puts []*pb.PutRequest, ops []*pb.RequestOp) []*pb.PutRequest {
+	for _, op := range ops {
+		switch r := op.Request.(type) {
+		case *pb.RequestOp_RequestPut:
+			puts = append(puts, r.RequestPut)
+		case *pb.RequestOp_RequestTxn:
+			puts = txnPuts(puts, r.RequestTxn.Success)
+			puts = txnPuts(puts, r.RequestTxn.Failure)
+		}
+	}
+	return puts
+}
+
+type keyRange struct {
+	key, end []byte
+}
+
+func (r keyRange) contains(key []byte) bool {
+	if r.end == nil {
+		return bytes.Equal(r.key, key)
+	}
+	return bytes.Compare(r.key, key) <= 0 && (len(r.end) == 0 || bytes.Compare(key, r.end) < 0)
+}
+
+// check returns ErrKeyQuotaExceeded if the puts of the user take the keys of
+// a quota over its bounds. The puts only shrinking the usage of a quota are
+// let through, even if it is exceeded.
+func (c *keyQuotaChecker) check(ctx context.Context, authInfo *auth.AuthInfo, puts []*pb.PutRequest) error {
+	for _, q := range c.quotas {
+		ranges := c.ranges(authInfo, q)
+		var keys, n int64
+		for _, p := range puts {
+			if !slices.ContainsFunc(ranges, func(r keyRange) bool { return r.contains(p.Key) }) {
+				continue
+			}
+			prev, err := c.kv.Range(ctx, p.Key, nil, mvcc.RangeOptions{})
+			if err != nil {
+				return err
+			}
+			switch {
+			case len(prev.KVs) == 0:
+				keys++
+				n += int64(len(p.Key) + len(p.Value))
+			case !p.IgnoreValue:
+				n += int64(len(p.Value) - len(prev.KVs[0].Value))
+			}
+		}
+		if keys <= 0 && n <= 0 {
+			continue
+		}
+		var u mvcc.Usage
+		for _, r := range ranges {
+			ru := c.kv.Usage(r.key, r.end)
+			u.Keys += ru.Keys
+			u.Bytes += ru.Bytes
+		}
+		if (q.MaxKeys > 0 && keys > 0 && u.Keys+keys > q.MaxKeys) || (q.MaxBytes > 0 && n > 0 && u.Bytes+n > q.MaxBytes) {
+			keyQuotaExceeded.WithLabelValues(q.String()).Inc()
+			return errors.ErrKeyQuotaExceeded
+		}
+	}
+	return nil
+}
+
+// ranges returns the key ranges of q the user writes to.
+func (c *keyQuotaChecker) ranges(authInfo *auth.AuthInfo, q serverstorage.KeyQuota) []keyRange {
+	if q.Kind == serverstorage.KeyQuotaPrefix {
+		return []keyRange{{key: []byte(q.Name), end: prefixEnd([]byte(q.Name))}}
+	}
+	if !c.as.IsAuthEnabled() || !slices.Contains(c.roles(authInfo), q.Name) {
+		return nil
+	}
+	role, err := c.as.RoleGet(&pb.AuthRoleGetRequest{Role: q.Name})
+	if err != nil {
+		return nil
+	}
+	var ranges []keyRange
+	for _, perm := range role.Perm {
+		if perm.PermType != authpb.WRITE && perm.PermType != authpb.READWRITE {
+			continue
+		}
+		r := keyRange{key: perm.Key}
+		switch {
+		case len(perm.RangeEnd) == 1 && perm.RangeEnd[0] == 0:
+			r.end = []byte{}
+		case len(perm.RangeEnd) > 0:
+			r.end = perm.RangeEnd
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+func (c *keyQuotaChecker) roles(authInfo *auth.AuthInfo) []string {
+	if authInfo == nil {
+		return nil
+	}
+	if len(authInfo.Roles) > 0 {
+		return authInfo.Roles
+	}
+	if authInfo.Username == "" {
+		return nil
+	}
+	user, err := c.as.UserGet(&pb.AuthUserGetRequest{Name: authInfo.Username})
+	if err != nil {
+		return nil
+	}
+	return user.Roles
+}
+
+// prefixEnd returns the end of the range of the keys with the given prefix.
+func prefixEnd(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	// the prefix is all 0xff: all the keys from it.
+	return []byte{}
+}
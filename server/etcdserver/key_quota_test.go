@@ -0,0 +1,134 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"golang.org/x/crypto/bcrypt"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/auth"
+	"go.etcd.io/etcd/server/v3/etcdserver/errors"
+	"go.etcd.io/etcd/server/v3/lease"
+	serverstorage "go.etcd.io/etcd/server/v3/storage"
+	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+func newTestKeyQuotaChecker(t *testing.T, quotas ...string) *keyQuotaChecker {
+	lg := zaptest.NewLogger(t)
+	be, _ := betesting.NewDefaultTmpBackend(t)
+	t.Cleanup(func() {
+		betesting.Close(t, be)
+	})
+
+	kv := mvcc.New(lg, be, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	t.Cleanup(func() { kv.Close() })
+	indexWaiter := func(uint64) <-chan struct{} {
+		ch := make(chan struct{}, 1)
+		ch <- struct{}{}
+		return ch
+	}
+	tp, err := auth.NewTokenProvider(lg, "simple", indexWaiter, 300*time.Second)
+	require.NoError(t, err)
+	keyQuotas, err := serverstorage.ParseKeyQuotas(quotas)
+	require.NoError(t, err)
+	return &keyQuotaChecker{
+		kv:     kv,
+		as:     auth.NewAuthStore(lg, schema.NewAuthBackend(lg, be), tp, bcrypt.MinCost),
+		quotas: keyQuotas,
+	}
+}
+
+func TestKeyQuotaChecker_Prefix(t *testing.T) {
+	c := newTestKeyQuotaChecker(t, "prefix:a/=/2", "prefix:b/=10")
+	check := func(puts ...*pb.PutRequest) error {
+		return c.check(context.Background(), nil, puts)
+	}
+	put := func(key, value string) error {
+		p := &pb.PutRequest{Key: []byte(key), Value: []byte(value)}
+		if err := check(p); err != nil {
+			return err
+		}
+		c.kv.Put(p.Key, p.Value, 0)
+		return nil
+	}
+
+	require.NoError(t, put("a/1", ""))
+	require.NoError(t, put("a/2", ""))
+	require.ErrorIs(t, put("a/3", ""), errors.ErrKeyQuotaExceeded)
+	// overwriting a key and writing out of the prefix are not bound.
+	require.NoError(t, put("a/1", "x"))
+	require.NoError(t, put("a0", ""))
+
+	// both branches of a txn must fit.
+	txn := &pb.TxnRequest{Failure: []*pb.RequestOp{{Request: &pb.RequestOp_RequestPut{
+		RequestPut: &pb.PutRequest{Key: []byte("a/3")},
+	}}}}
+	require.ErrorIs(t, check(txnPuts(nil, txn.Failure)...), errors.ErrKeyQuotaExceeded)
+	c.kv.DeleteRange([]byte("a/1"), nil)
+	require.NoError(t, check(txnPuts(nil, txn.Failure)...))
+
+	require.NoError(t, put("b/1", "xxxxx"))
+	require.NoError(t, put("b/1", "xxxxxxx"))
+	require.ErrorIs(t, put("b/1", "xxxxxxxx"), errors.ErrKeyQuotaExceeded)
+	require.NoError(t, put("b/1", "x"))
+	require.ErrorIs(t, put("b/2", "xxxxxx"), errors.ErrKeyQuotaExceeded)
+}
+
+func TestKeyQuotaChecker_Role(t *testing.T) {
+	c := newTestKeyQuotaChecker(t, "role:tenant=/1")
+	for _, user := range []string{"root", "alice"} {
+		_, err := c.as.UserAdd(&pb.AuthUserAddRequest{Name: user, Options: &authpb.UserAddOptions{NoPassword: true}})
+		require.NoError(t, err)
+	}
+	_, err := c.as.RoleAdd(&pb.AuthRoleAddRequest{Name: "root"})
+	require.NoError(t, err)
+	_, err = c.as.UserGrantRole(&pb.AuthUserGrantRoleRequest{User: "root", Role: "root"})
+	require.NoError(t, err)
+	_, err = c.as.RoleAdd(&pb.AuthRoleAddRequest{Name: "tenant"})
+	require.NoError(t, err)
+	_, err = c.as.RoleGrantPermission(&pb.AuthRoleGrantPermissionRequest{Name: "tenant", Perm: &authpb.Permission{
+		PermType: authpb.READWRITE,
+		Key:      []byte("t/"),
+		RangeEnd: []byte("t0"),
+	}})
+	require.NoError(t, err)
+	_, err = c.as.UserGrantRole(&pb.AuthUserGrantRoleRequest{User: "alice", Role: "tenant"})
+	require.NoError(t, err)
+	require.NoError(t, c.as.AuthEnable())
+
+	put := func(info *auth.AuthInfo, key string) error {
+		p := &pb.PutRequest{Key: []byte(key)}
+		if err := c.check(context.Background(), info, []*pb.PutRequest{p}); err != nil {
+			return err
+		}
+		c.kv.Put(p.Key, p.Value, 0)
+		return nil
+	}
+	alice := &auth.AuthInfo{Username: "alice"}
+	require.NoError(t, put(alice, "t/1"))
+	require.ErrorIs(t, put(alice, "t/2"), errors.ErrKeyQuotaExceeded)
+	require.ErrorIs(t, put(&auth.AuthInfo{Username: "oidc-user", Roles: []string{"tenant"}}, "t/2"), errors.ErrKeyQuotaExceeded)
+	// the users without the role are not bound.
+	require.NoError(t, put(&auth.AuthInfo{Username: "root"}, "t/2"))
+}
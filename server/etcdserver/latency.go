@@ -0,0 +1,80 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"context"
+	"time"
+
+	apply2 "go.etcd.io/etcd/server/v3/etcdserver/apply"
+)
+
+// RequestLatency is the breakdown of the time the server spent serving a
+// request.
+type RequestLatency struct {
+	// Queue is the time spent until raft accepted the proposal of the request.
+	Queue time.Duration
+	// Raft is the time spent until the request was committed and the member
+	// started applying it, or for linearizable reads, until the read index
+	// was confirmed.
+	Raft time.Duration
+	// Apply is the time spent applying the request, outside of the backend.
+	Apply time.Duration
+	// Backend is the time spent in the mvcc store and backend.
+	Backend time.Duration
+}
+
+type requestLatencyKey struct{}
+
+// WithRequestLatency returns a context in which the server records the
+// latency breakdown of the request it serves into the returned RequestLatency.
+func WithRequestLatency(ctx context.Context) (context.Context, *RequestLatency) {
+	l := &RequestLatency{}
+	return context.WithValue(ctx, requestLatencyKey{}, l), l
+}
+
+func requestLatencyFromContext(ctx context.Context) *RequestLatency {
+	l, _ := ctx.Value(requestLatencyKey{}).(*RequestLatency)
+	return l
+}
+
+func (l *RequestLatency) observeQueue(start time.Time) {
+	if l != nil {
+		l.Queue += time.Since(start)
+	}
+}
+
+func (l *RequestLatency) observeRaft(start time.Time) {
+	if l != nil {
+		l.Raft += time.Since(start)
+	}
+}
+
+func (l *RequestLatency) observeBackend(start time.Time) {
+	if l != nil {
+		l.Backend += time.Since(start)
+	}
+}
+
+// observeProposal records the latency of a proposal from the time it was
+// accepted by raft until its result was applied.
+func (l *RequestLatency) observeProposal(proposed time.Time, ar *apply2.Result) {
+	if l == nil || ar == nil || ar.ApplyStart.IsZero() {
+		return
+	}
+	l.Raft += ar.ApplyStart.Sub(proposed)
+	l.Apply += ar.ApplyTook - ar.BackendTook
+	l.Backend += ar.BackendTook
+}
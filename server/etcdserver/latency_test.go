@@ -0,0 +1,49 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apply2 "go.etcd.io/etcd/server/v3/etcdserver/apply"
+)
+
+func TestRequestLatencyObserveProposal(t *testing.T) {
+	ctx, l := WithRequestLatency(context.Background())
+	assert.Same(t, l, requestLatencyFromContext(ctx))
+
+	proposed := time.Now()
+	l.observeProposal(proposed, &apply2.Result{
+		ApplyStart:  proposed.Add(30 * time.Millisecond),
+		ApplyTook:   20 * time.Millisecond,
+		BackendTook: 15 * time.Millisecond,
+	})
+	assert.Equal(t, RequestLatency{Raft: 30 * time.Millisecond, Apply: 5 * time.Millisecond, Backend: 15 * time.Millisecond}, *l)
+
+	// results not produced by the applier leave the breakdown untouched
+	l.observeProposal(proposed, &apply2.Result{})
+	l.observeProposal(proposed, nil)
+	assert.Equal(t, 30*time.Millisecond, l.Raft)
+
+	// requests whose latency is not recorded are ignored
+	var unrecorded *RequestLatency
+	unrecorded.observeQueue(proposed)
+	unrecorded.observeProposal(proposed, &apply2.Result{ApplyStart: proposed})
+	assert.Nil(t, requestLatencyFromContext(context.Background()))
+}
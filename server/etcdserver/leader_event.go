@@ -0,0 +1,76 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/client/pkg/v3/types"
+)
+
+// LeaderChangeEvent describes a raft leadership change observed by a member.
+type LeaderChangeEvent struct {
+	// OldLeader is the leader known before the change, or 0 if there was none.
+	OldLeader types.ID `json:"old-leader"`
+	// NewLeader is the newly elected leader.
+	NewLeader types.ID `json:"new-leader"`
+	// Term is the raft term in which NewLeader was elected.
+	Term uint64 `json:"term"`
+	// Time is when the local member observed the change.
+	Time time.Time `json:"time"`
+}
+
+// emitLeaderChangeEvent logs the leader change event if enabled, and writes it to
+// the configured event key when the local member is the new leader.
+func (s *EtcdServer) emitLeaderChangeEvent(ev LeaderChangeEvent) {
+	if s.Cfg.EnableLeaderChangeEvents {
+		s.Logger().Info(
+			"leader changed",
+			zap.String("local-member-id", s.MemberID().String()),
+			zap.String("old-leader-member-id", ev.OldLeader.String()),
+			zap.String("new-leader-member-id", ev.NewLeader.String()),
+			zap.Uint64("term", ev.Term),
+		)
+	}
+	if s.Cfg.LeaderChangeEventKey == "" || ev.NewLeader != s.MemberID() {
+		return
+	}
+	s.GoAttach(func() { s.putLeaderChangeEvent(ev) })
+}
+
+func (s *EtcdServer) putLeaderChangeEvent(ev LeaderChangeEvent) {
+	lg := s.Logger()
+	value, err := json.Marshal(ev)
+	if err != nil {
+		lg.Warn("failed to marshal leader change event", zap.Error(err))
+		return
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, s.Cfg.ReqTimeout())
+	defer cancel()
+	_, err = s.Put(ctx, &pb.PutRequest{Key: []byte(s.Cfg.LeaderChangeEventKey), Value: value})
+	if err != nil {
+		lg.Warn(
+			"failed to write leader change event",
+			zap.String("key", s.Cfg.LeaderChangeEventKey),
+			zap.Uint64("term", ev.Term),
+			zap.Error(err),
+		)
+	}
+}
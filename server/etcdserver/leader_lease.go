@@ -0,0 +1,93 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"sync"
+	"time"
+
+	"go.etcd.io/raft/v3"
+)
+
+// leaderLease is the time until which the leader knows that no other member
+// can be elected, so that it can serve linearizable reads from its commit
+// index without confirming its leadership with a ReadIndex round trip.
+//
+// The lease is acquired by the ReadIndex requests acknowledged by a quorum:
+// with CheckQuorum, a follower rejects the votes for an election timeout after
+// hearing from the leader, so no other leader can be elected until an
+// election timeout after the request was sent. The lease is shortened by the
+// maximum clock drift between the members to account for followers whose
+// clocks run faster than the leader's.
+type leaderLease struct {
+	mu      sync.Mutex
+	term    uint64
+	expiry  time.Time
+	revoked time.Time
+}
+
+// extend extends the lease of the given term to expiry, provided that the
+// ReadIndex request acquiring it was sent at start after the last revoke.
+func (l *leaderLease) extend(term uint64, start, expiry time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if start.Before(l.revoked) {
+		return
+	}
+	if term != l.term || expiry.After(l.expiry) {
+		l.term, l.expiry = term, expiry
+	}
+}
+
+// valid returns true if the lease of the given term has not expired at now.
+func (l *leaderLease) valid(term uint64, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.term == term && now.Before(l.expiry)
+}
+
+// revoke revokes the lease, and discards the requests in flight acquiring it.
+// It must be called before transferring the leadership, as the transferee
+// campaigns regardless of the lease of the followers.
+func (l *leaderLease) revoke() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.expiry = time.Time{}
+	l.revoked = time.Now()
+}
+
+// readIndex returns the index the member has to apply before serving
+// linearizable reads. If Cfg.LeaderLeaseReads is set, the leader holding the
+// leader lease returns its commit index, and otherwise renews the lease with
+// the ReadIndex request.
+func (s *EtcdServer) readIndex(leaderChangedNotifier <-chan struct{}, requestID uint64) (uint64, error) {
+	if !s.Cfg.LeaderLeaseReads {
+		return s.requestCurrentIndex(leaderChangedNotifier, requestID)
+	}
+	rs := s.raftStatus()
+	if rs.RaftState != raft.StateLeader || rs.LeadTransferee != raft.None {
+		return s.requestCurrentIndex(leaderChangedNotifier, requestID)
+	}
+	start := time.Now()
+	if s.leaderLease.valid(rs.Term, start) {
+		leaderLeaseReads.Inc()
+		return rs.Commit, nil
+	}
+	index, err := s.requestCurrentIndex(leaderChangedNotifier, requestID)
+	if err == nil {
+		s.leaderLease.extend(rs.Term, start, start.Add(s.Cfg.ElectionTimeout()-s.Cfg.LeaderLeaseClockDrift))
+	}
+	return index, err
+}
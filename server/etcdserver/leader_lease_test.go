@@ -0,0 +1,47 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaderLease(t *testing.T) {
+	var l leaderLease
+	now := time.Now()
+	assert.False(t, l.valid(0, now))
+
+	l.extend(2, now, now.Add(time.Second))
+	assert.True(t, l.valid(2, now))
+	assert.False(t, l.valid(3, now), "the lease is only valid in its term")
+	assert.False(t, l.valid(2, now.Add(time.Second)), "the lease expires")
+
+	// a request sent earlier does not shorten the lease.
+	l.extend(2, now, now.Add(time.Millisecond))
+	assert.True(t, l.valid(2, now.Add(time.Millisecond)))
+
+	l.revoke()
+	assert.False(t, l.valid(2, now))
+	// the requests in flight while revoking do not extend the lease.
+	l.extend(2, now, now.Add(time.Second))
+	assert.False(t, l.valid(2, now))
+
+	later := time.Now().Add(time.Millisecond)
+	l.extend(3, later, later.Add(time.Second))
+	assert.True(t, l.valid(3, later))
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/client/pkg/v3/types"
+	"go.etcd.io/raft/v3"
+	"go.etcd.io/raft/v3/tracker"
+)
+
+// monitorLearnerAutoPromotion promotes, while the member is the leader, the
+// learners which lagged behind the leader by at most
+// Cfg.LearnerAutoPromoteMaxLag entries, without needing a snapshot, for
+// Cfg.LearnerAutoPromoteDuration.
+func (s *EtcdServer) monitorLearnerAutoPromotion() {
+	if s.Cfg.LearnerAutoPromoteDuration == 0 {
+		return
+	}
+	caughtUp := make(map[uint64]time.Time)
+	for {
+		select {
+		case <-time.After(s.Cfg.ElectionTimeout()):
+		case <-s.stopping:
+			return
+		}
+		if !s.isLeader() {
+			clear(caughtUp)
+			continue
+		}
+		rs := s.raftStatus()
+		if rs.Progress == nil {
+			clear(caughtUp)
+			continue
+		}
+		now := time.Now()
+		for _, m := range s.cluster.Members() {
+			id := uint64(m.ID)
+			lag, ok := learnerLag(rs, id)
+			if !m.IsLearner || m.IsStandby || !ok || lag > s.Cfg.LearnerAutoPromoteMaxLag {
+				delete(caughtUp, id)
+				continue
+			}
+			since, ok := caughtUp[id]
+			if !ok {
+				caughtUp[id] = now
+				continue
+			}
+			if now.Sub(since) < s.Cfg.LearnerAutoPromoteDuration {
+				continue
+			}
+			delete(caughtUp, id)
+			s.autoPromoteLearner(m.ID, m.Name, lag, now.Sub(since))
+		}
+	}
+}
+
+// learnerLag returns the number of entries the member lags behind the
+// leader, and false if it is unknown, the member needs a snapshot or it was
+// not heard from in the last election timeout: a crashed learner of an idle
+// cluster keeps matching the leader.
+func learnerLag(rs raft.Status, id uint64) (uint64, bool) {
+	pr, ok := rs.Progress[id]
+	if !ok || pr.State == tracker.StateSnapshot || !pr.RecentActive {
+		return 0, false
+	}
+	leaderMatch := rs.Progress[rs.ID].Match
+	if pr.Match >= leaderMatch {
+		return 0, true
+	}
+	return leaderMatch - pr.Match, true
+}
+
+func (s *EtcdServer) autoPromoteLearner(id types.ID, name string, lag uint64, caughtUpFor time.Duration) {
+	lg := s.Logger()
+	ctx, cancel := context.WithTimeout(s.ctx, s.Cfg.ReqTimeout())
+	defer cancel()
+	if _, err := s.promoteLearner(ctx, uint64(id)); err != nil {
+		learnerPromoteFailed.WithLabelValues(err.Error()).Inc()
+		lg.Warn(
+			"failed to auto-promote learner",
+			zap.String("learner-member-id", id.String()),
+			zap.String("learner-member-name", name),
+			zap.Error(err),
+		)
+		return
+	}
+	learnerPromoteSucceed.Inc()
+	learnerAutoPromotions.Inc()
+	lg.Info(
+		"auto-promoted learner",
+		zap.String("learner-member-id", id.String()),
+		zap.String("learner-member-name", name),
+		zap.Uint64("lag", lag),
+		zap.Duration("caught-up-for", caughtUpFor),
+	)
+}
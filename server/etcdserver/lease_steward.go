@@ -0,0 +1,177 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/server/v3/lease"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+// leaseStewardPageSize is the number of keys of a prefix read at a time by
+// the lease steward.
+const leaseStewardPageSize = 1000
+
+// orphanedKey is a key found orphaned by the lease steward.
+type orphanedKey struct {
+	prefix      string
+	modRevision int64
+}
+
+// monitorLeaseSteward looks, while the member is the leader, for the keys of
+// the Cfg.LeaseStewardPrefixes lock and election prefixes which are not
+// attached to a lease, or whose lease does not exist anymore: their holders
+// are gone without deleting them, and they block the other candidates
+// forever. The orphaned keys are logged and counted, and deleted if
+// Cfg.LeaseStewardDelete is set once they are found orphaned and unmodified
+// for a whole interval.
+func (s *EtcdServer) monitorLeaseSteward() {
+	if len(s.Cfg.LeaseStewardPrefixes) == 0 {
+		return
+	}
+	t := time.NewTicker(s.Cfg.LeaseStewardInterval)
+	defer t.Stop()
+
+	// the keys found orphaned by the previous scan
+	orphans := make(map[string]orphanedKey)
+	for {
+		select {
+		case <-s.stopping:
+			return
+		case <-t.C:
+		}
+		if !s.isLeader() {
+			clear(orphans)
+			for _, prefix := range s.Cfg.LeaseStewardPrefixes {
+				leaseStewardOrphanedKeys.WithLabelValues(prefix).Set(0)
+			}
+			continue
+		}
+		orphans = s.stewardLeases(orphans)
+	}
+}
+
+// stewardLeases scans the prefixes for orphaned keys, deleting the ones
+// already found by the previous scan if configured to. It returns the
+// orphaned keys found.
+func (s *EtcdServer) stewardLeases(prev map[string]orphanedKey) map[string]orphanedKey {
+	lg := s.Logger()
+	orphans := make(map[string]orphanedKey)
+	for _, prefix := range s.Cfg.LeaseStewardPrefixes {
+		count := 0
+		err := s.rangePrefix([]byte(prefix), func(kv *mvccpb.KeyValue) {
+			if kv.Lease != 0 && s.lessor.Lookup(lease.LeaseID(kv.Lease)) != nil {
+				return
+			}
+			count++
+			key := string(kv.Key)
+			orphans[key] = orphanedKey{prefix: prefix, modRevision: kv.ModRevision}
+			if p, ok := prev[key]; !ok || p.modRevision != kv.ModRevision {
+				lg.Warn(
+					"found orphaned lock key",
+					zap.String("prefix", prefix),
+					zap.String("key", key),
+					zap.String("lease-id", fmt.Sprintf("%016x", kv.Lease)),
+					zap.Int64("mod-revision", kv.ModRevision),
+					zap.Bool("delete", s.Cfg.LeaseStewardDelete),
+				)
+			}
+		})
+		if err != nil {
+			lg.Warn("failed to read lock keys", zap.String("prefix", prefix), zap.Error(err))
+			continue
+		}
+		leaseStewardOrphanedKeys.WithLabelValues(prefix).Set(float64(count))
+	}
+	if !s.Cfg.LeaseStewardDelete || !s.ensureLeadership() {
+		return orphans
+	}
+	for key, o := range orphans {
+		if p, ok := prev[key]; ok && p.modRevision == o.modRevision {
+			if s.deleteOrphanedKey([]byte(key), o) {
+				delete(orphans, key)
+			}
+		}
+	}
+	return orphans
+}
+
+// rangePrefix calls f with the latest key-values of the prefix, read a page
+// at a time.
+func (s *EtcdServer) rangePrefix(prefix []byte, f func(*mvccpb.KeyValue)) error {
+	key, end := prefix, prefixRangeEnd(prefix)
+	for {
+		r, err := s.KV().Range(s.ctx, key, end, mvcc.RangeOptions{Limit: leaseStewardPageSize})
+		if err != nil {
+			return err
+		}
+		for i := range r.KVs {
+			f(&r.KVs[i])
+		}
+		if len(r.KVs) < leaseStewardPageSize || r.Count == len(r.KVs) {
+			return nil
+		}
+		key = append(bytes.Clone(r.KVs[len(r.KVs)-1].Key), 0)
+	}
+}
+
+// deleteOrphanedKey deletes the key unless it was modified since it was
+// found orphaned. It returns true if the key was deleted.
+func (s *EtcdServer) deleteOrphanedKey(key []byte, o orphanedKey) bool {
+	ctx, cancel := context.WithTimeout(s.authStore.WithRoot(s.ctx), s.Cfg.ReqTimeout())
+	defer cancel()
+	resp, err := s.Txn(ctx, &pb.TxnRequest{
+		Compare: []*pb.Compare{{
+			Key:         key,
+			Target:      pb.Compare_MOD,
+			Result:      pb.Compare_EQUAL,
+			TargetUnion: &pb.Compare_ModRevision{ModRevision: o.modRevision},
+		}},
+		Success: []*pb.RequestOp{{
+			Request: &pb.RequestOp_RequestDeleteRange{RequestDeleteRange: &pb.DeleteRangeRequest{Key: key}},
+		}},
+	})
+	if err != nil {
+		s.Logger().Warn("failed to delete orphaned lock key", zap.String("key", string(key)), zap.Error(err))
+		return false
+	}
+	if !resp.Succeeded {
+		return false
+	}
+	s.Logger().Info("deleted orphaned lock key", zap.String("prefix", o.prefix), zap.String("key", string(key)))
+	leaseStewardDeletedKeys.WithLabelValues(o.prefix).Inc()
+	return true
+}
+
+// prefixRangeEnd returns the end of the range of the keys with the prefix.
+func prefixRangeEnd(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	// the prefix is all 0xff: all the keys from it.
+	return []byte{}
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"go.etcd.io/etcd/client/pkg/v3/types"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
+)
+
+// MembershipView is the member list a member of the cluster sees.
+type MembershipView struct {
+	MemberID types.ID
+	Members  []*membership.Member
+	// Err is set if the member list could not be collected from the member.
+	Err error
+}
+
+// CheckMembership collects the member list every member of the local view
+// of the cluster sees, and describes every difference in member IDs, peer
+// URLs or learner flags from the local view.
+func (s *EtcdServer) CheckMembership(ctx context.Context) ([]MembershipView, []string) {
+	local := MembershipView{MemberID: s.MemberID(), Members: s.cluster.Members()}
+	views := []MembershipView{local}
+	var disagreements []string
+	for _, m := range local.Members {
+		if m.ID == local.MemberID {
+			continue
+		}
+		membs, err := getMembers(ctx, s.Logger(), m, s.peerRt, s.Cfg.ReqTimeout())
+		view := MembershipView{MemberID: m.ID, Members: membs, Err: err}
+		views = append(views, view)
+		if err == nil {
+			disagreements = append(disagreements, membershipDisagreements(local, view)...)
+		}
+	}
+	return views, disagreements
+}
+
+// membershipDisagreements describes how the view of another member differs
+// from the local view.
+func membershipDisagreements(local, other MembershipView) []string {
+	theirs := make(map[types.ID]*membership.Member, len(other.Members))
+	for _, m := range other.Members {
+		theirs[m.ID] = m
+	}
+
+	var ds []string
+	for _, ours := range local.Members {
+		m, ok := theirs[ours.ID]
+		if !ok {
+			ds = append(ds, fmt.Sprintf("member %s does not see member %s", other.MemberID, ours.ID))
+			continue
+		}
+		delete(theirs, ours.ID)
+		if m.IsLearner != ours.IsLearner {
+			ds = append(ds, fmt.Sprintf("member %s sees member %s as learner=%t, member %s as learner=%t",
+				other.MemberID, m.ID, m.IsLearner, local.MemberID, ours.IsLearner))
+		}
+		if m.IsStandby != ours.IsStandby {
+			ds = append(ds, fmt.Sprintf("member %s sees member %s as standby=%t, member %s as standby=%t",
+				other.MemberID, m.ID, m.IsStandby, local.MemberID, ours.IsStandby))
+		}
+		if !slices.Equal(sortedURLs(m.PeerURLs), sortedURLs(ours.PeerURLs)) {
+			ds = append(ds, fmt.Sprintf("member %s sees member %s with peer URLs %v, member %s with peer URLs %v",
+				other.MemberID, m.ID, m.PeerURLs, local.MemberID, ours.PeerURLs))
+		}
+	}
+	for _, m := range other.Members {
+		if _, ok := theirs[m.ID]; ok {
+			ds = append(ds, fmt.Sprintf("member %s sees member %s that member %s does not see", other.MemberID, m.ID, local.MemberID))
+		}
+	}
+	return ds
+}
+
+func sortedURLs(urls []string) []string {
+	sorted := slices.Clone(urls)
+	slices.Sort(sorted)
+	return sorted
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/client/pkg/v3/types"
+	"go.etcd.io/etcd/server/v3/config"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
+)
+
+func newMembersPeer(t *testing.T, membs *[]*membership.Member) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/members", r.URL.Path)
+		assert.NoError(t, json.NewEncoder(w).Encode(*membs))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCheckMembership(t *testing.T) {
+	lg := zaptest.NewLogger(t)
+
+	var agreeing, diverging []*membership.Member
+	peer2, peer3 := newMembersPeer(t, &agreeing), newMembersPeer(t, &diverging)
+	member := func(id types.ID, learner bool, peerURLs ...string) *membership.Member {
+		return &membership.Member{ID: id, RaftAttributes: membership.RaftAttributes{PeerURLs: peerURLs, IsLearner: learner}}
+	}
+	local := []*membership.Member{
+		member(1, false, "http://127.0.0.1:1"),
+		member(2, false, peer2.URL),
+		member(3, true, peer3.URL),
+	}
+	agreeing = local
+	// member 3 missed the removal of member 1, the promotion of itself, and
+	// saw member 4 join with its peer URL.
+	diverging = []*membership.Member{
+		member(2, false, peer2.URL),
+		member(3, false, peer3.URL),
+		member(4, false, "http://127.0.0.1:4"),
+	}
+
+	s := &EtcdServer{
+		lgMu:     new(sync.RWMutex),
+		lg:       lg,
+		memberID: 1,
+		Cfg:      config.ServerConfig{TickMs: 1, ElectionTicks: 10},
+		cluster:  membership.NewClusterFromMembers(lg, types.ID(0), local),
+		peerRt:   http.DefaultTransport,
+	}
+
+	views, disagreements := s.CheckMembership(t.Context())
+	require.Len(t, views, 3)
+	for i, id := range []types.ID{1, 2, 3} {
+		assert.Equal(t, id, views[i].MemberID)
+		assert.NoError(t, views[i].Err)
+	}
+	assert.Len(t, views[2].Members, 3)
+	assert.Equal(t, []string{
+		"member 3 does not see member 1",
+		"member 3 sees member 3 as learner=false, member 1 as learner=true",
+		"member 3 sees member 4 that member 1 does not see",
+	}, disagreements)
+
+	// once member 3 catches up, all members agree.
+	diverging = local
+	views, disagreements = s.CheckMembership(t.Context())
+	require.Len(t, views, 3)
+	assert.Empty(t, disagreements)
+
+	// an unreachable member reports the error in its view.
+	peer3.Close()
+	views, disagreements = s.CheckMembership(t.Context())
+	require.Len(t, views, 3)
+	require.Error(t, views[2].Err)
+	assert.Nil(t, views[2].Members)
+	assert.Empty(t, disagreements)
+}
+
+func TestMembershipDisagreementsPeerURLs(t *testing.T) {
+	local := MembershipView{MemberID: 1, Members: []*membership.Member{
+		{ID: 1, RaftAttributes: membership.RaftAttributes{PeerURLs: []string{"http://a:2380", "http://b:2380"}}},
+	}}
+	// the order of the peer URLs does not matter
+	other := MembershipView{MemberID: 2, Members: []*membership.Member{
+		{ID: 1, RaftAttributes: membership.RaftAttributes{PeerURLs: []string{"http://b:2380", "http://a:2380"}}},
+	}}
+	assert.Empty(t, membershipDisagreements(local, other))
+
+	other.Members[0].PeerURLs = []string{"http://a:2380"}
+	assert.Equal(t, []string{
+		"member 2 sees member 1 with peer URLs [http://a:2380], member 1 with peer URLs [http://a:2380 http://b:2380]",
+	}, membershipDisagreements(local, other))
+}
@@ -59,6 +59,55 @@ var (
 		Name:      "learner_promote_successes",
 		Help:      "The total number of successful learner promotions while this member is leader.",
 	})
+	learnerAutoPromotions = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "learner_auto_promotions_total",
+		Help:      "The total number of learners promoted automatically once caught up with this member as leader.",
+	})
+	followerLagEntries = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "etcd",
+			Subsystem: "server",
+			Name:      "follower_lag_entries",
+			Help:      "The number of committed raft entries not replicated on each follower yet, reported by the leader.",
+		},
+		[]string{"follower"},
+	)
+	followerLagAlarms = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "follower_lag_alarms_total",
+		Help:      "The total number of FOLLOWER_LAG alarms raised by this member as leader.",
+	})
+	leaseStewardOrphanedKeys = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "lease_steward_orphaned_keys",
+		Help:      "The number of keys of a lock prefix without a live lease, found by the last scan of the leader.",
+	}, []string{"prefix"})
+	leaseStewardDeletedKeys = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "lease_steward_deleted_keys_total",
+		Help:      "The total number of orphaned keys of a lock prefix deleted by this member as leader.",
+	}, []string{"prefix"})
+	expensiveRangesInflight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "expensive_ranges_inflight",
+		Help:      "The number of expensive ranges being read, bounded by --max-concurrent-expensive-ranges.",
+	})
+	expensiveRangeWaitSec = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "expensive_range_wait_duration_seconds",
+		Help:      "The latency distribution of the wait of the expensive ranges for their turn to be read.",
+
+		// lowest bucket start of upper bound 0.001 sec (1 ms) with factor 2
+		// highest bucket start of 0.001 sec * 2^13 == 8.192 sec
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 14),
+	})
 	heartbeatSendFailures = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: "etcd",
 		Subsystem: "server",
@@ -89,6 +138,40 @@ var (
 		Name:      "proposals_pending",
 		Help:      "The current number of pending proposals to commit.",
 	})
+	applyBacklog = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "apply_backlog",
+		Help:      "The current number of committed entries waiting to be applied.",
+	})
+	applyBacklogThresholdCrossed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "apply_backlog_threshold_crossed_total",
+		Help:      "The total number of times the apply backlog rose above --apply-backlog-alert-threshold.",
+	})
+	applyBackpressureDelays = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "apply_backpressure_delays_total",
+		Help:      "The total number of proposals delayed while the apply backlog was above --apply-backlog-backpressure-threshold.",
+	})
+	applyQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "apply_queue_depth",
+		Help:      "The current number of batches of committed entries scheduled to be applied.",
+	})
+	applyWaitSec = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "apply_wait_duration_seconds",
+		Help:      "The latency distribution of the wait of the batches of committed entries before they are applied.",
+
+		// lowest bucket start of upper bound 0.0001 sec (0.1 ms) with factor 2
+		// highest bucket start of 0.0001 sec * 2^16 == 6.5536 sec
+		Buckets: prometheus.ExponentialBuckets(0.0001, 2, 17),
+	})
 	proposalsFailed = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: "etcd",
 		Subsystem: "server",
@@ -107,12 +190,34 @@ var (
 		Name:      "read_indexes_failed_total",
 		Help:      "The total number of failed read indexes seen.",
 	})
+	leaderLeaseReads = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "leader_lease_reads_total",
+		Help:      "The total number of linearizable reads the leader served from its leader lease without a read index.",
+	})
 	leaseExpired = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: "etcd_debugging",
 		Subsystem: "server",
 		Name:      "lease_expired_total",
 		Help:      "The total number of expired leases.",
 	})
+	leaseKeepAliveSec = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "etcd_debugging",
+		Subsystem: "server",
+		Name:      "lease_keepalive_duration_seconds",
+		Help:      "The latency distribution of lease keepalives, including their forwarding to the leader.",
+
+		// lowest bucket start of upper bound 0.001 sec (1 ms) with factor 2
+		// highest bucket start of 0.001 sec * 2^13 == 8.192 sec
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 14),
+	})
+	keyExpired = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "etcd_debugging",
+		Subsystem: "server",
+		Name:      "key_expired_total",
+		Help:      "The total number of keys deleted once their ttl elapsed.",
+	})
 	currentVersion = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: "etcd",
@@ -159,6 +264,12 @@ var (
 		Name:      "limit",
 		Help:      "The file descriptor limit.",
 	})
+	keyQuotaExceeded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "key_quota_exceeded_total",
+		Help:      "The total number of writes rejected for exceeding a key quota.",
+	}, []string{"quota"})
 )
 
 func init() {
@@ -170,18 +281,34 @@ func init() {
 	prometheus.MustRegister(proposalsCommitted)
 	prometheus.MustRegister(proposalsApplied)
 	prometheus.MustRegister(proposalsPending)
+	prometheus.MustRegister(applyBacklog)
+	prometheus.MustRegister(applyBacklogThresholdCrossed)
+	prometheus.MustRegister(applyBackpressureDelays)
+	prometheus.MustRegister(applyQueueDepth)
+	prometheus.MustRegister(applyWaitSec)
 	prometheus.MustRegister(proposalsFailed)
 	prometheus.MustRegister(slowReadIndex)
 	prometheus.MustRegister(readIndexFailed)
+	prometheus.MustRegister(leaderLeaseReads)
 	prometheus.MustRegister(leaseExpired)
+	prometheus.MustRegister(leaseKeepAliveSec)
+	prometheus.MustRegister(keyExpired)
 	prometheus.MustRegister(currentVersion)
 	prometheus.MustRegister(currentGoVersion)
 	prometheus.MustRegister(serverID)
 	prometheus.MustRegister(serverFeatureEnabled)
 	prometheus.MustRegister(learnerPromoteSucceed)
 	prometheus.MustRegister(learnerPromoteFailed)
+	prometheus.MustRegister(learnerAutoPromotions)
+	prometheus.MustRegister(followerLagEntries)
+	prometheus.MustRegister(followerLagAlarms)
+	prometheus.MustRegister(leaseStewardOrphanedKeys)
+	prometheus.MustRegister(leaseStewardDeletedKeys)
+	prometheus.MustRegister(expensiveRangesInflight)
+	prometheus.MustRegister(expensiveRangeWaitSec)
 	prometheus.MustRegister(fdUsed)
 	prometheus.MustRegister(fdLimit)
+	prometheus.MustRegister(keyQuotaExceeded)
 
 	currentVersion.With(prometheus.Labels{
 		"server_version": version.Version,
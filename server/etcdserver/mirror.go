@@ -0,0 +1,130 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3mirror"
+	"go.etcd.io/etcd/server/v3/etcdserver/errors"
+)
+
+const (
+	// mirrorLeaderCheckInterval is the interval between two checks of
+	// whether the member is the leader, which mirrors the keys.
+	mirrorLeaderCheckInterval = time.Second
+	// mirrorMaxRetryInterval bounds the interval between two attempts to
+	// mirror the keys after a failure.
+	mirrorMaxRetryInterval = 30 * time.Second
+)
+
+// monitorMirror mirrors the keys to the mirror target cluster while the
+// member is the leader. The mirroring resumes from the checkpoint of the
+// target cluster on the next leader.
+func (s *EtcdServer) monitorMirror() {
+	if s.mirror == nil {
+		return
+	}
+	lg := s.Logger()
+	for {
+		select {
+		case <-time.After(mirrorLeaderCheckInterval):
+		case <-s.stopping:
+			lg.Info("server has stopped; stopping mirroring")
+			return
+		}
+		if s.isLeader() {
+			s.runMirror()
+		}
+	}
+}
+
+// runMirror mirrors the keys, retrying after the failures, until the member
+// loses the leadership or stops.
+func (s *EtcdServer) runMirror() {
+	lg := s.Logger()
+	cli, err := clientv3.New(clientv3.Config{
+		Endpoints:   s.Cfg.MirrorTargetEndpoints,
+		TLS:         s.Cfg.MirrorTargetTLS,
+		DialTimeout: s.Cfg.ReqTimeout(),
+		Logger:      lg.Named("mirror"),
+	})
+	if err != nil {
+		lg.Warn("failed to create the mirror target client", zap.Error(err))
+		return
+	}
+	defer cli.Close()
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wait := mirrorLeaderCheckInterval
+		target := v3mirror.NewTarget(cli)
+		for ctx.Err() == nil {
+			if err := s.mirror.Run(ctx, target); err == nil {
+				wait = mirrorLeaderCheckInterval
+				continue
+			} else if ctx.Err() == nil {
+				lg.Warn("failed to mirror the keys; retrying", zap.Duration("retry-after", wait), zap.Error(err))
+			}
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+			}
+			wait = min(2*wait, mirrorMaxRetryInterval)
+		}
+	}()
+
+	for s.isLeader() {
+		select {
+		case <-time.After(mirrorLeaderCheckInterval):
+			continue
+		case <-s.stopping:
+		}
+		break
+	}
+	cancel()
+	<-done
+}
+
+// MirrorStatus reports the progress of the mirroring of the keys. It
+// returns ErrNotLeader if the mirroring is configured and the member is not
+// the leader.
+func (s *EtcdServer) MirrorStatus(ctx context.Context) (*pb.MirrorStatusResponse, error) {
+	resp := &pb.MirrorStatusResponse{Header: &pb.ResponseHeader{}}
+	if s.mirror == nil {
+		return resp, nil
+	}
+	if !s.isLeader() {
+		return nil, errors.ErrNotLeader
+	}
+	st := s.mirror.Status()
+	resp.TargetEndpoints = s.Cfg.MirrorTargetEndpoints
+	resp.SourcePrefix = s.Cfg.MirrorPrefix
+	resp.DestPrefix = s.Cfg.MirrorDestPrefix
+	resp.MirroredRevision = st.Revision
+	resp.Lag = max(s.KV().Rev()-st.Revision, 0)
+	resp.Resyncs = st.Resyncs
+	if st.Err != nil {
+		resp.Error = st.Err.Error()
+	}
+	return resp, nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"time"
+
+	"go.etcd.io/etcd/server/v3/storage"
+)
+
+// quotaWarningInterval is the interval between the checks of the backend
+// size against the warning thresholds of the quota.
+const quotaWarningInterval = 5 * time.Second
+
+// monitorQuotaWarnings periodically checks the size of the backend against
+// the Cfg.QuotaBackendWarningThresholds, if any.
+func (s *EtcdServer) monitorQuotaWarnings() {
+	w := storage.NewQuotaWarnings(s.Logger(), s.Cfg.QuotaBackendBytes, s.Cfg.QuotaBackendGrace, s.Cfg.QuotaBackendWarningThresholds, s.Backend())
+	if w == nil {
+		return
+	}
+	ticker := time.NewTicker(quotaWarningInterval)
+	defer ticker.Stop()
+	for {
+		w.Check()
+		select {
+		case <-ticker.C:
+		case <-s.stopping:
+			return
+		}
+	}
+}
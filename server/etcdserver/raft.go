@@ -176,16 +176,24 @@ func (r *raftNode) start(rh *raftReadyHandler) {
 	go func() {
 		defer r.onStop()
 		islead := false
+		// term tracks the latest persisted raft term, so that leader changes
+		// can be reported with the term they happened in.
+		var term uint64
 
 		for {
 			select {
 			case <-r.ticker.C:
 				r.tick()
 			case rd := <-r.Ready():
+				if !raft.IsEmptyHardState(rd.HardState) {
+					term = rd.HardState.Term
+				}
 				if rd.SoftState != nil {
-					newLeader := rd.SoftState.Lead != raft.None && rh.getLead() != rd.SoftState.Lead
+					oldLead := rh.getLead()
+					newLeader := rd.SoftState.Lead != raft.None && oldLead != rd.SoftState.Lead
 					if newLeader {
 						leaderChanges.Inc()
+						rh.notifyLeaderChange(oldLead, rd.SoftState.Lead, term)
 					}
 
 					if rd.SoftState.Lead == raft.None {
@@ -261,6 +261,81 @@ func TestConfigChangeBlocksApply(t *testing.T) {
 	}
 }
 
+// TestLeaderChangeNotification ensures that a leadership change observed in a
+// raft Ready is reported with the previous leader, the new leader and the term.
+func TestLeaderChangeNotification(t *testing.T) {
+	n := newNopReadyNode()
+	r := newRaftNode(raftNodeConfig{
+		lg:          zaptest.NewLogger(t),
+		Node:        n,
+		storage:     mockstorage.NewStorageRecorder(""),
+		raftStorage: raft.NewMemoryStorage(),
+		transport:   newNopTransporter(),
+	})
+
+	type leaderChange struct{ oldLead, newLead, term uint64 }
+	changes := make(chan leaderChange, 1)
+	var lead uint64
+	r.start(&raftReadyHandler{
+		getLead:          func() uint64 { return lead },
+		updateLead:       func(l uint64) { lead = l },
+		updateLeadership: func(bool) {},
+		notifyLeaderChange: func(oldLead, newLead, term uint64) {
+			changes <- leaderChange{oldLead, newLead, term}
+		},
+		updateCommittedIndex: func(uint64) {},
+	})
+	defer r.stop()
+
+	tests := []struct {
+		rd   raft.Ready
+		want leaderChange
+	}{
+		{
+			rd: raft.Ready{
+				SoftState: &raft.SoftState{Lead: 1, RaftState: raft.StateFollower},
+				HardState: raftpb.HardState{Term: 2, Vote: 1},
+			},
+			want: leaderChange{oldLead: 0, newLead: 1, term: 2},
+		},
+		{
+			// the term is not part of every Ready; the last persisted term is reported.
+			rd: raft.Ready{
+				SoftState: &raft.SoftState{Lead: 3, RaftState: raft.StateFollower},
+			},
+			want: leaderChange{oldLead: 1, newLead: 3, term: 2},
+		},
+		{
+			rd: raft.Ready{
+				SoftState: &raft.SoftState{Lead: 2, RaftState: raft.StateFollower},
+				HardState: raftpb.HardState{Term: 5},
+			},
+			want: leaderChange{oldLead: 3, newLead: 2, term: 5},
+		},
+	}
+	for i, tt := range tests {
+		n.readyc <- tt.rd
+		select {
+		case got := <-changes:
+			if got != tt.want {
+				t.Errorf("#%d: leader change = %+v, want %+v", i, got, tt.want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("#%d: leader change was not notified", i)
+		}
+		<-r.applyc
+	}
+
+	// a Ready without a new leader must not be reported.
+	n.readyc <- raft.Ready{SoftState: &raft.SoftState{Lead: 2, RaftState: raft.StateFollower}}
+	<-r.applyc
+	select {
+	case got := <-changes:
+		t.Fatalf("unexpected leader change %+v", got)
+	default:
+	}
+}
+
 func TestProcessDuplicatedAppRespMessage(t *testing.T) {
 	n := newNopReadyNode()
 	cl := membership.NewCluster(zaptest.NewLogger(t))
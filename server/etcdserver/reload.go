@@ -0,0 +1,103 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"go.etcd.io/etcd/server/v3/etcdserver/api/rafthttp"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3compactor"
+)
+
+// snapshotSendBurst is the minimum burst of the limiter of the snapshots sent
+// to clients, which must hold a whole response of the Maintenance Snapshot RPC.
+const snapshotSendBurst = 32 * 1024
+
+// newSnapshotSendLimiter returns the limiter of the snapshots sent to clients
+// at limit bytes per second, or unlimited if limit is 0.
+func newSnapshotSendLimiter(limit int64) *rate.Limiter {
+	l := rate.NewLimiter(rate.Inf, snapshotSendBurst)
+	setSnapshotSendRateLimit(l, limit)
+	return l
+}
+
+func setSnapshotSendRateLimit(l *rate.Limiter, limit int64) {
+	if limit <= 0 {
+		l.SetLimit(rate.Inf)
+		return
+	}
+	l.SetBurst(max(int(limit), snapshotSendBurst))
+	l.SetLimit(rate.Limit(limit))
+}
+
+// SnapshotSendLimiter returns the limiter of the snapshots sent to clients.
+func (s *EtcdServer) SnapshotSendLimiter() *rate.Limiter { return s.snapshotLimiter }
+
+// SetSnapshotSendRateLimit changes the number of bytes per second of the
+// snapshots sent to clients. 0 is no limit.
+func (s *EtcdServer) SetSnapshotSendRateLimit(limit int64) {
+	setSnapshotSendRateLimit(s.snapshotLimiter, limit)
+}
+
+// SetPeerSnapshotSendRateLimit changes the number of bytes per second of the
+// snapshots sent to peers. 0 is no limit.
+func (s *EtcdServer) SetPeerSnapshotSendRateLimit(limit int64) {
+	if tr, ok := s.r.transport.(*rafthttp.Transport); ok {
+		tr.SetSnapshotSendRateLimit(limit)
+	}
+}
+
+// ResetPeerConnections closes the connections with the peers, for the
+// renewed peer certificates to be used.
+func (s *EtcdServer) ResetPeerConnections() {
+	if tr, ok := s.r.transport.(*rafthttp.Transport); ok {
+		tr.ResetConnections()
+	}
+}
+
+// SetAuthTokenTTL changes the TTL of the simple auth tokens.
+func (s *EtcdServer) SetAuthTokenTTL(ttl time.Duration) {
+	if s.authStore != nil {
+		s.authStore.SetTokenTTL(ttl)
+	}
+}
+
+// SetAutoCompaction replaces the auto compactor by one of the given mode and
+// retention. A retention of 0 disables the auto compaction.
+func (s *EtcdServer) SetAutoCompaction(mode string, retention time.Duration) error {
+	var c v3compactor.Compactor
+	if retention != 0 {
+		var err error
+		if c, err = v3compactor.New(s.Logger(), mode, retention, s.kv, s); err != nil {
+			return err
+		}
+	}
+
+	s.compactorMu.Lock()
+	defer s.compactorMu.Unlock()
+	if s.compactor != nil {
+		s.compactor.Stop()
+	}
+	s.compactor = c
+	if c != nil {
+		if !s.isLeader() {
+			c.Pause()
+		}
+		c.Run()
+	}
+	return nil
+}
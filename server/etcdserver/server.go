@@ -24,6 +24,7 @@ import (
 	"net/http"
 	"path"
 	"regexp"
+	"slices"
 	"strconv"
 	"sync"
 	"sync/atomic"
@@ -32,7 +33,9 @@ import (
 	"github.com/coreos/go-semver/semver"
 	humanize "github.com/dustin/go-humanize"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/codes"
 	"go.uber.org/zap"
+	"golang.org/x/time/rate"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/api/v3/membershippb"
@@ -48,8 +51,10 @@ import (
 	"go.etcd.io/etcd/pkg/v3/schedule"
 	"go.etcd.io/etcd/pkg/v3/traceutil"
 	"go.etcd.io/etcd/pkg/v3/wait"
+	"go.etcd.io/etcd/pkg/v3/watermark"
 	"go.etcd.io/etcd/server/v3/auth"
 	"go.etcd.io/etcd/server/v3/config"
+	"go.etcd.io/etcd/server/v3/etcdserver/accesstime"
 	"go.etcd.io/etcd/server/v3/etcdserver/api"
 	httptypes "go.etcd.io/etcd/server/v3/etcdserver/api/etcdhttp/types"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
@@ -58,16 +63,21 @@ import (
 	stats "go.etcd.io/etcd/server/v3/etcdserver/api/v2stats"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v2store"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3alarm"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3backup"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3cdc"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3compactor"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3mirror"
 	"go.etcd.io/etcd/server/v3/etcdserver/apply"
 	"go.etcd.io/etcd/server/v3/etcdserver/cindex"
 	"go.etcd.io/etcd/server/v3/etcdserver/errors"
+	"go.etcd.io/etcd/server/v3/etcdserver/txn"
 	serverversion "go.etcd.io/etcd/server/v3/etcdserver/version"
 	"go.etcd.io/etcd/server/v3/features"
 	"go.etcd.io/etcd/server/v3/lease"
 	"go.etcd.io/etcd/server/v3/lease/leasehttp"
 	serverstorage "go.etcd.io/etcd/server/v3/storage"
 	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
 	"go.etcd.io/etcd/server/v3/storage/mvcc"
 	"go.etcd.io/etcd/server/v3/storage/schema"
 	"go.etcd.io/raft/v3"
@@ -102,6 +112,12 @@ const (
 	// maxPendingRevokes is the maximum number of outstanding expired lease revocations.
 	maxPendingRevokes = 16
 
+	// keyExpiryInterval is the interval at which the leader deletes the keys
+	// whose ttl elapsed.
+	keyExpiryInterval = 500 * time.Millisecond
+	// maxExpiredKeys is the maximum number of expired keys deleted per interval.
+	maxExpiredKeys = 1000
+
 	recommendedMaxRequestBytes = 10 * 1024 * 1024
 
 	// readyPercentThreshold is a threshold used to determine
@@ -172,6 +188,7 @@ type Server interface {
 	// return ErrIDNotFound if the member ID does not exist.
 	// return ErrLearnerNotReady if the member are not ready.
 	// return ErrMemberNotLearner if the member is not a learner.
+	// return ErrMemberStandby if the member is a standby member.
 	PromoteMember(ctx context.Context, id uint64) ([]*membership.Member, error)
 
 	// ClusterVersion is the cluster-wide minimum major.minor version.
@@ -232,6 +249,9 @@ type EtcdServer struct {
 	// readNotifier is used to notify the read routine that it can process the request
 	// when there is no error
 	readNotifier *notifier
+	// leaderLease allows the leader to serve linearizable reads without a
+	// ReadIndex round trip if Cfg.LeaderLeaseReads is set.
+	leaderLease leaderLease
 
 	// stop signals the run goroutine should shutdown.
 	stop chan struct{}
@@ -262,13 +282,47 @@ type EtcdServer struct {
 	beHooks    *serverstorage.BackendHooks
 	authStore  auth.AuthStore
 	alarmStore *v3alarm.AlarmStore
+	keyQuotas  []serverstorage.KeyQuota
 
 	stats  *stats.ServerStats
 	lstats *stats.LeaderStats
 
+	// applyQueue tracks the number of scheduled applyAll jobs, heap the
+	// size of the heap; both keep their high-watermarks. See Watermarks.
+	applyQueue watermark.Watermark
+	heap       watermark.Watermark
+	// applyBacklogAlerting is set while the apply backlog is above
+	// Cfg.ApplyBacklogAlertThreshold.
+	applyBacklogAlerting atomic.Bool
+	// parallelApply is whether the non-conflicting entries are prepared
+	// concurrently before they are applied. See prepareEntries.
+	parallelApply bool
+
+	// compactorMu protects compactor, which is replaced when the auto
+	// compaction settings are reloaded.
+	compactorMu sync.Mutex
 	// compactor is used to auto-compact the KV.
 	compactor v3compactor.Compactor
 
+	// snapshotLimiter limits the snapshots sent to clients.
+	snapshotLimiter *rate.Limiter
+
+	// accessTimes records when keys were last read; nil unless
+	// Cfg.KeyAccessSampleRate is set.
+	accessTimes *accesstime.Tracker
+
+	// expensiveRanges bounds the expensive ranges read at a time; nil
+	// unless Cfg.MaxConcurrentExpensiveRanges is set.
+	expensiveRanges *expensiveRangeLimiter
+
+	// backup continuously backs up the server while it is the leader; nil
+	// unless Cfg.BackupURL is set.
+	backup *v3backup.Backup
+	// cdc publishes the key-value events to each CDC sink.
+	cdc []*v3cdc.Publisher
+	// mirror mirrors the keys to the mirror target cluster.
+	mirror *v3mirror.Mirror
+
 	// peerRt used to send requests (version, lease) to peers.
 	peerRt   http.RoundTripper
 	reqIDGen *idutil.Generator
@@ -328,16 +382,18 @@ func NewServer(cfg config.ServerConfig) (srv *EtcdServer, err error) {
 		snapshotter:           b.ss,
 		r:                     *b.raft.newRaftNode(b.ss, b.storage.wal.w, b.cluster.cl),
 		memberID:              b.cluster.nodeID,
-		attributes:            membership.Attributes{Name: cfg.Name, ClientURLs: cfg.ClientURLs.StringSlice()},
+		attributes:            membership.Attributes{Name: cfg.Name, ClientURLs: cfg.ClientURLs.StringSlice(), IsWitness: cfg.Witness},
 		cluster:               b.cluster.cl,
 		stats:                 sstats,
 		lstats:                lstats,
 		peerRt:                b.prt,
 		reqIDGen:              idutil.NewGenerator(uint16(b.cluster.nodeID), time.Now()),
 		AccessController:      &AccessController{CORS: cfg.CORS, HostWhitelist: cfg.HostWhitelist},
+		snapshotLimiter:       newSnapshotSendLimiter(cfg.SnapshotSendRateLimit),
 		consistIndex:          b.storage.backend.ci,
 		firstCommitInTerm:     notify.NewNotifier(),
 		clusterVersionChanged: notify.NewNotifier(),
+		parallelApply:         cfg.ServerFeatureGate.Enabled(features.ParallelApply),
 	}
 
 	addFeatureGateMetrics(cfg.ServerFeatureGate, serverFeatureEnabled)
@@ -369,13 +425,46 @@ func NewServer(cfg config.ServerConfig) (srv *EtcdServer, err error) {
 	}
 
 	mvccStoreConfig := mvcc.StoreConfig{
-		CompactionBatchLimit:    cfg.CompactionBatchLimit,
-		CompactionSleepInterval: cfg.CompactionSleepInterval,
-	}
-	srv.kv = mvcc.New(srv.Logger(), srv.be, srv.lessor, mvccStoreConfig)
+		CompactionBatchLimit:          cfg.CompactionBatchLimit,
+		CompactionSleepInterval:       cfg.CompactionSleepInterval,
+		CompactionTargetCommitLatency: cfg.CompactionTargetCommitLatency,
+		CompactionWorkers:             cfg.CompactionWorkers,
+		SlowWatcherMaxBacklog:         cfg.SlowWatcherMaxBacklog,
+		SlowWatcherPolicy:             mvcc.SlowWatcherPolicy(cfg.SlowWatcherPolicy),
+		MetricsKeyPrefixes:            slices.Clone(cfg.MetricsKeyPrefixes),
+	}
+	for _, ns := range cfg.Namespaces {
+		mvccStoreConfig.MetricsKeyPrefixes = append(mvccStoreConfig.MetricsKeyPrefixes, cfg.NamespaceKeyPrefix(ns))
+	}
+	srv.kv = mvcc.New(srv.Logger().Named("mvcc"), srv.be, srv.lessor, mvccStoreConfig)
 	srv.corruptionChecker = newCorruptionChecker(cfg.Logger, srv, srv.kv.HashStorage())
 
-	srv.authStore = auth.NewAuthStore(srv.Logger(), schema.NewAuthBackend(srv.Logger(), srv.be), tp, int(cfg.BcryptCost))
+	if srv.keyQuotas, err = serverstorage.ParseKeyQuotas(cfg.KeyQuotas); err != nil {
+		return nil, err
+	}
+	for i, q := range srv.keyQuotas {
+		if q.Kind == serverstorage.KeyQuotaNamespace {
+			srv.keyQuotas[i].Kind, srv.keyQuotas[i].Name = serverstorage.KeyQuotaPrefix, cfg.NamespaceKeyPrefix(q.Name)
+		}
+	}
+
+	certRoleRules, err := auth.ParseCertRoleRules(cfg.ClientCertRoleRules)
+	if err != nil {
+		return nil, err
+	}
+	srv.authStore = auth.NewAuthStore(srv.Logger().Named("auth"), schema.NewAuthBackend(srv.Logger(), srv.be), tp, int(cfg.BcryptCost),
+		auth.WithCertRoleRules(certRoleRules),
+		auth.WithLockout(auth.LockoutConfig{
+			UserFailures:   cfg.AuthLockoutUserFailures,
+			SourceFailures: cfg.AuthLockoutSourceFailures,
+			Duration:       cfg.AuthLockoutDuration,
+			MaxDuration:    cfg.AuthLockoutMaxDuration,
+		}),
+		auth.WithPasswordPolicy(auth.PasswordPolicy{
+			MaxAge:      cfg.AuthPasswordMaxAge,
+			GracePeriod: cfg.AuthPasswordGracePeriod,
+		}),
+	)
 
 	newSrv := srv // since srv == nil in defer if srv is returned as nil
 	defer func() {
@@ -392,6 +481,44 @@ func NewServer(cfg config.ServerConfig) (srv *EtcdServer, err error) {
 		}
 		srv.compactor.Run()
 	}
+	if cfg.KeyAccessSampleRate > 0 {
+		srv.accessTimes = accesstime.NewTracker(cfg.KeyAccessSampleRate)
+	}
+	if cfg.MaxConcurrentExpensiveRanges > 0 {
+		srv.expensiveRanges = newExpensiveRangeLimiter(cfg.MaxConcurrentExpensiveRanges, cfg.ExpensiveRangeLimit)
+	}
+	if cfg.BackupURL != "" {
+		sink, serr := v3backup.NewSink(cfg.Logger, cfg.BackupURL)
+		if serr != nil {
+			return nil, serr
+		}
+		srv.backup = v3backup.New(cfg.Logger, sink, srv, srv.r.raftStorage, v3backup.Config{
+			SnapshotInterval: cfg.BackupSnapshotInterval,
+			Retention:        cfg.BackupRetention,
+			TempDir:          cfg.MemberDir(),
+		})
+	}
+	for _, u := range cfg.CDCSinkURLs {
+		sink, serr := v3cdc.NewSink(cfg.Logger, u)
+		if serr != nil {
+			return nil, serr
+		}
+		cp := &cdcCheckpoint{s: srv, key: []byte(cdcCheckpointKey(cfg.CDCCheckpointKeyPrefix, u))}
+		srv.cdc = append(srv.cdc, v3cdc.NewPublisher(cfg.Logger, srv.kv, sink, cp, []byte(cfg.CDCCheckpointKeyPrefix)))
+	}
+	if len(cfg.MirrorTargetEndpoints) > 0 {
+		srv.mirror = v3mirror.NewMirror(cfg.Logger, srv.kv, v3mirror.Config{
+			SourcePrefix:  cfg.MirrorPrefix,
+			DestPrefix:    cfg.MirrorDestPrefix,
+			CheckpointKey: cfg.MirrorCheckpointKey,
+			MaxTxnOps:     cfg.MirrorMaxTxnOps,
+		})
+	}
+	if cfg.AutoSnapshotInterval > 0 {
+		if err = fileutil.TouchDirAll(cfg.Logger, cfg.AutoSnapshotDir); err != nil {
+			return nil, fmt.Errorf("cannot access auto snapshot directory: %w", err)
+		}
+	}
 
 	if err = srv.restoreAlarms(); err != nil {
 		return nil, err
@@ -416,6 +543,10 @@ func NewServer(cfg config.ServerConfig) (srv *EtcdServer, err error) {
 	// the hook being called during the initialization process.
 	srv.be.SetTxPostLockInsideApplyHook(srv.getTxPostLockInsideApplyHook())
 
+	peerFaults, err := rafthttp.ParsePeerFaults(cfg.PeerNetworkFaults)
+	if err != nil {
+		return nil, err
+	}
 	// TODO: move transport initialization near the definition of remote
 	tr := &rafthttp.Transport{
 		Logger:      cfg.Logger,
@@ -429,6 +560,14 @@ func NewServer(cfg config.ServerConfig) (srv *EtcdServer, err error) {
 		ServerStats: sstats,
 		LeaderStats: lstats,
 		ErrorC:      srv.errorc,
+
+		SnapshotSendRateLimit:      cfg.PeerSnapshotSendRateLimit,
+		PeerAddressRefreshInterval: cfg.PeerAddressRefreshInterval,
+		PeerFaults:                 peerFaults,
+		PeerFaultSeed:              cfg.PeerNetworkFaultsSeed,
+
+		EncryptionKeyring:      srv.encryptionKeyring,
+		CheckEncryptionKeyring: srv.checkEncryptionKeyring,
 	}
 	if err = tr.Start(); err != nil {
 		return nil, err
@@ -541,12 +680,26 @@ func (s *EtcdServer) Start() {
 	s.GoAttach(func() { s.publishV3(s.Cfg.ReqTimeout()) })
 	s.GoAttach(s.purgeFile)
 	s.GoAttach(func() { monitorFileDescriptor(s.Logger(), s.stopping) })
+	s.GoAttach(s.monitorHeapWatermark)
+	s.GoAttach(s.monitorQuotaWarnings)
+	if s.Cfg.CompactionControlKey != "" {
+		s.GoAttach(s.watchCompactionControlKey)
+	}
 	s.GoAttach(s.monitorClusterVersions)
 	s.GoAttach(s.monitorStorageVersion)
 	s.GoAttach(s.linearizableReadLoop)
 	s.GoAttach(s.monitorKVHash)
 	s.GoAttach(s.monitorCompactHash)
+	s.GoAttach(s.monitorBackup)
+	s.GoAttach(s.monitorCDC)
+	s.GoAttach(s.monitorMirror)
+	s.GoAttach(s.monitorAutoSnapshot)
+	s.GoAttach(s.monitorAutoDefrag)
 	s.GoAttach(s.monitorDowngrade)
+	s.GoAttach(s.monitorLearnerAutoPromotion)
+	s.GoAttach(s.monitorFollowerLag)
+	s.GoAttach(s.expireKeys)
+	s.GoAttach(s.monitorLeaseSteward)
 }
 
 // start prepares and starts server in a new goroutine. It is no longer safe to
@@ -660,6 +813,7 @@ type ServerPeerV2 interface {
 	ServerPeer
 	HashKVHandler() http.Handler
 	DowngradeEnabledHandler() http.Handler
+	DowngradeCheckHandler() http.Handler
 }
 
 func (s *EtcdServer) DowngradeInfo() *serverversion.DowngradeInfo { return s.cluster.DowngradeInfo() }
@@ -758,6 +912,9 @@ type raftReadyHandler struct {
 	updateLead           func(lead uint64)
 	updateLeadership     func(newLeader bool)
 	updateCommittedIndex func(uint64)
+	// notifyLeaderChange is called with the previous leader, the new leader
+	// and the raft term whenever a new leader is observed.
+	notifyLeaderChange func(oldLead, newLead, term uint64)
 }
 
 func (s *EtcdServer) run() {
@@ -779,19 +936,26 @@ func (s *EtcdServer) run() {
 				if s.lessor != nil {
 					s.lessor.Demote()
 				}
+				s.compactorMu.Lock()
 				if s.compactor != nil {
 					s.compactor.Pause()
 				}
+				s.compactorMu.Unlock()
 			} else {
 				if newLeader {
 					t := time.Now()
 					s.leadTimeMu.Lock()
 					s.leadElectedTime = t
 					s.leadTimeMu.Unlock()
+					if s.Cfg.Witness {
+						s.GoAttach(s.handOverWitnessLeadership)
+					}
 				}
+				s.compactorMu.Lock()
 				if s.compactor != nil {
 					s.compactor.Resume()
 				}
+				s.compactorMu.Unlock()
 			}
 			if newLeader {
 				s.leaderChanged.Notify()
@@ -808,6 +972,14 @@ func (s *EtcdServer) run() {
 				s.setCommittedIndex(ci)
 			}
 		},
+		notifyLeaderChange: func(oldLead, newLead, term uint64) {
+			s.emitLeaderChangeEvent(LeaderChangeEvent{
+				OldLeader: types.ID(oldLead),
+				NewLeader: types.ID(newLead),
+				Term:      term,
+				Time:      time.Now(),
+			})
+		},
 	}
 	s.r.start(rh)
 
@@ -846,7 +1018,15 @@ func (s *EtcdServer) run() {
 	for {
 		select {
 		case ap := <-s.r.apply():
-			f := schedule.NewJob("server_applyAll", func(context.Context) { s.applyAll(&ep, &ap) })
+			scheduled := time.Now()
+			f := schedule.NewJob("server_applyAll", func(context.Context) {
+				applyWaitSec.Observe(time.Since(scheduled).Seconds())
+				s.applyAll(&ep, &ap)
+				s.applyQueue.Add(-1)
+				s.updateApplyBacklog()
+			})
+			s.applyQueue.Add(1)
+			s.updateApplyBacklog()
 			sched.Schedule(f)
 		case leases := <-expiredLeaseC:
 			s.revokeExpiredLeases(leases)
@@ -907,6 +1087,62 @@ func (s *EtcdServer) revokeExpiredLeases(leases []*lease.Lease) {
 	})
 }
 
+// expireKeys deletes the keys whose ttl elapsed, while the member is the
+// leader. Every key is deleted by a transaction conditioned on the key not
+// having been modified since the put that set its ttl.
+func (s *EtcdServer) expireKeys() {
+	t := time.NewTicker(keyExpiryInterval)
+	defer t.Stop()
+
+	lg := s.Logger()
+	for {
+		select {
+		case <-s.stopping:
+			return
+		case <-t.C:
+		}
+		// like expired leases, only the leader deletes the expired keys
+		if !s.isLeader() {
+			continue
+		}
+		keys := s.KV().ExpiredKeys(maxExpiredKeys)
+		if len(keys) == 0 || !s.ensureLeadership() {
+			continue
+		}
+
+		c := make(chan struct{}, maxPendingRevokes)
+		for _, k := range keys {
+			select {
+			case c <- struct{}{}:
+			case <-s.stopping:
+				return
+			}
+			s.GoAttach(func() {
+				defer func() { <-c }()
+				ctx, cancel := context.WithTimeout(s.authStore.WithRoot(s.ctx), s.Cfg.ReqTimeout())
+				defer cancel()
+				resp, err := s.Txn(ctx, &pb.TxnRequest{
+					Compare: []*pb.Compare{{
+						Key:         k.Key,
+						Target:      pb.Compare_MOD,
+						Result:      pb.Compare_EQUAL,
+						TargetUnion: &pb.Compare_ModRevision{ModRevision: k.ModRevision},
+					}},
+					Success: []*pb.RequestOp{{
+						Request: &pb.RequestOp_RequestDeleteRange{RequestDeleteRange: &pb.DeleteRangeRequest{Key: k.Key}},
+					}},
+				})
+				switch {
+				case err != nil:
+					lg.Warn("failed to delete expired key", zap.String("key", string(k.Key)), zap.Error(err))
+				case resp.Succeeded:
+					keyExpired.Inc()
+				}
+			})
+		}
+	}
+}
+
 // isActive checks if the etcd instance is still actively processing the
 // heartbeat message (ticks). It returns false if no heartbeat has been
 // received within 3 * tickMs.
@@ -963,9 +1199,11 @@ func (s *EtcdServer) Cleanup() {
 	if s.be != nil {
 		s.be.Close()
 	}
+	s.compactorMu.Lock()
 	if s.compactor != nil {
 		s.compactor.Stop()
 	}
+	s.compactorMu.Unlock()
 }
 
 func (s *EtcdServer) applyAll(ep *etcdProgress, apply *toApply) {
@@ -985,6 +1223,11 @@ func (s *EtcdServer) applyAll(ep *etcdProgress, apply *toApply) {
 	select {
 	// snapshot requested via send()
 	case m := <-s.r.msgSnapC:
+		// the backend of a witness has no keys to send.
+		if s.Cfg.Witness {
+			s.r.ReportSnapshot(m.To, raft.SnapshotFailure)
+			break
+		}
 		merged := s.createMergedSnapshotMessage(m, ep.appliedt, ep.appliedi, ep.confState)
 		s.sendMergedSnap(merged)
 	default:
@@ -1034,6 +1277,9 @@ func (s *EtcdServer) applySnapshot(ep *etcdProgress, toApply *toApply) {
 	if err != nil {
 		lg.Panic("failed to open snapshot backend", zap.Error(err))
 	}
+	if s.Cfg.Witness {
+		clearWitnessKeyspace(lg, newbe)
+	}
 
 	// We need to set the backend to consistIndex before recovering the lessor,
 	// because lessor.Recover will commit the boltDB transaction, accordingly it
@@ -1157,6 +1403,7 @@ func (s *EtcdServer) NewUberApplier() apply.UberApplier {
 		TxnModeWriteWithSharedBuffer: s.Cfg.ServerFeatureGate.Enabled(features.TxnModeWriteWithSharedBuffer),
 		Backend:                      s.be,
 		QuotaBackendBytesCfg:         s.Cfg.QuotaBackendBytes,
+		QuotaBackendGrace:            s.Cfg.QuotaBackendGrace,
 		WarningApplyDuration:         s.Cfg.WarningApplyDuration,
 	}
 	return apply.NewUberApplier(opts)
@@ -1238,7 +1485,7 @@ func (s *EtcdServer) isLeader() bool {
 // MoveLeader transfers the leader to the given transferee.
 func (s *EtcdServer) MoveLeader(ctx context.Context, lead, transferee uint64) error {
 	member := s.cluster.Member(types.ID(transferee))
-	if member == nil || member.IsLearner {
+	if member == nil || member.IsLearner || member.IsWitness {
 		return errors.ErrBadLeaderTransferee
 	}
 
@@ -1253,6 +1500,7 @@ func (s *EtcdServer) MoveLeader(ctx context.Context, lead, transferee uint64) er
 		zap.String("transferee-member-id", types.ID(transferee).String()),
 	)
 
+	s.leaderLease.revoke()
 	s.r.TransferLeadership(ctx, lead, transferee)
 	for s.Lead() != transferee {
 		select {
@@ -1294,7 +1542,7 @@ func (s *EtcdServer) TryTransferLeadershipOnShutdown() error {
 		return nil
 	}
 
-	transferee, ok := longestConnected(s.r.transport, s.cluster.VotingMemberIDs())
+	transferee, ok := longestConnected(s.r.transport, s.cluster.LeaderCandidateIDs())
 	if !ok {
 		return errors.ErrUnhealthy
 	}
@@ -1378,6 +1626,13 @@ func (s *EtcdServer) AddMember(ctx context.Context, memb membership.Member) ([]*
 	if err := s.checkMembershipOperationPermission(ctx); err != nil {
 		return nil, err
 	}
+	// the members older than 3.7 don't know the standby members, so they
+	// would add and later promote them as any learner.
+	if memb.IsStandby {
+		if err := s.checkClusterVersion(version.V3_7); err != nil {
+			return nil, err
+		}
+	}
 
 	// TODO: move Member to protobuf type
 	b, err := json.Marshal(memb)
@@ -1450,6 +1705,75 @@ func (s *EtcdServer) RemoveMember(ctx context.Context, id uint64) ([]*membership
 	return s.configure(ctx, cc)
 }
 
+// ReplaceMember replaces the member of the given id by memb, which must be a
+// learner: memb is added, promoted once caught up with the leader, and the
+// replaced member is then removed. memb is removed again if it fails to be
+// promoted before ctx is done.
+func (s *EtcdServer) ReplaceMember(ctx context.Context, id uint64, memb membership.Member) ([]*membership.Member, error) {
+	if err := s.checkMembershipOperationPermission(ctx); err != nil {
+		return nil, err
+	}
+	if s.cluster.Member(types.ID(id)) == nil {
+		return nil, membership.ErrIDNotFound
+	}
+	if !memb.IsLearner {
+		return nil, membership.ErrMemberNotLearner
+	}
+
+	lg := s.Logger()
+	if _, err := s.AddMember(ctx, memb); err != nil {
+		return nil, err
+	}
+	lg.Info(
+		"added learner replacing member",
+		zap.String("replaced-member-id", types.ID(id).String()),
+		zap.String("learner-member-id", memb.ID.String()),
+	)
+
+	if err := s.promoteReplacingLearner(ctx, uint64(memb.ID)); err != nil {
+		lg.Warn(
+			"failed to promote learner replacing member; removing learner",
+			zap.String("replaced-member-id", types.ID(id).String()),
+			zap.String("learner-member-id", memb.ID.String()),
+			zap.Error(err),
+		)
+		// ctx may be done already, use a fresh one to roll back.
+		rctx, cancel := context.WithTimeout(s.ctx, s.Cfg.ReqTimeout())
+		defer cancel()
+		if _, rerr := s.RemoveMember(rctx, uint64(memb.ID)); rerr != nil {
+			lg.Warn(
+				"failed to remove learner replacing member",
+				zap.String("learner-member-id", memb.ID.String()),
+				zap.Error(rerr),
+			)
+		}
+		return nil, err
+	}
+
+	return s.RemoveMember(ctx, id)
+}
+
+// promoteReplacingLearner retries promoting the learner until it caught up
+// with the leader or ctx is done.
+func (s *EtcdServer) promoteReplacingLearner(ctx context.Context, id uint64) error {
+	for {
+		_, err := s.PromoteMember(ctx, id)
+		if !errorspkg.Is(err, errors.ErrLearnerNotReady) {
+			return err
+		}
+		select {
+		case <-time.After(s.Cfg.ElectionTimeout()):
+		case <-ctx.Done():
+			if errorspkg.Is(ctx.Err(), context.DeadlineExceeded) {
+				return errors.ErrTimeout
+			}
+			return errors.ErrCanceled
+		case <-s.stopping:
+			return errors.ErrStopped
+		}
+	}
+}
+
 // PromoteMember promotes a learner node to a voting node.
 func (s *EtcdServer) PromoteMember(ctx context.Context, id uint64) ([]*membership.Member, error) {
 	// only raft leader has information on whether the to-be-promoted learner node is ready. If promoteMember call
@@ -1479,7 +1803,8 @@ func (s *EtcdServer) PromoteMember(ctx context.Context, id uint64) ([]*membershi
 				return resp, nil
 			}
 			// If member promotion failed, return early. Otherwise keep retry.
-			if errorspkg.Is(err, errors.ErrLearnerNotReady) || errorspkg.Is(err, membership.ErrIDNotFound) || errorspkg.Is(err, membership.ErrMemberNotLearner) {
+			if errorspkg.Is(err, errors.ErrLearnerNotReady) || errorspkg.Is(err, membership.ErrIDNotFound) || errorspkg.Is(err, membership.ErrMemberNotLearner) ||
+				errorspkg.Is(err, membership.ErrMemberStandby) {
 				return nil, err
 			}
 		}
@@ -1501,7 +1826,12 @@ func (s *EtcdServer) promoteMember(ctx context.Context, id uint64) ([]*membershi
 	if err := s.checkMembershipOperationPermission(ctx); err != nil {
 		return nil, err
 	}
+	return s.promoteLearner(ctx, id)
+}
 
+// promoteLearner promotes the learner if it is ready, without checking the
+// permission of the caller.
+func (s *EtcdServer) promoteLearner(ctx context.Context, id uint64) ([]*membership.Member, error) {
 	// check if we can promote this learner.
 	if err := s.mayPromoteMember(types.ID(id)); err != nil {
 		return nil, err
@@ -1531,6 +1861,10 @@ func (s *EtcdServer) promoteMember(ctx context.Context, id uint64) ([]*membershi
 
 func (s *EtcdServer) mayPromoteMember(id types.ID) error {
 	lg := s.Logger()
+	// a standby member is never promoted, there is no need to ask the leader
+	if m := s.cluster.Member(id); m != nil && m.IsStandby {
+		return membership.ErrMemberStandby
+	}
 	if err := s.isLearnerReady(lg, uint64(id)); err != nil {
 		return err
 	}
@@ -1785,6 +2119,7 @@ func (s *EtcdServer) publishV3(timeout time.Duration) {
 		MemberAttributes: &membershippb.Attributes{
 			Name:       s.attributes.Name,
 			ClientUrls: s.attributes.ClientURLs,
+			IsWitness:  s.attributes.IsWitness,
 		},
 	}
 	// gofail: var beforePublishing struct{}
@@ -1879,6 +2214,7 @@ func (s *EtcdServer) apply(
 	raftAdvancedC <-chan struct{},
 ) (appliedt uint64, appliedi uint64, shouldStop bool) {
 	s.lg.Debug("Applying entries", zap.Int("num-entries", len(es)))
+	var prepared []*preparedEntry
 	for i := range es {
 		e := es[i]
 		index := s.consistIndex.ConsistentIndex()
@@ -1899,7 +2235,14 @@ func (s *EtcdServer) apply(
 		switch e.Type {
 		case raftpb.EntryNormal:
 			// gofail: var beforeApplyOneEntryNormal struct{}
-			s.applyEntryNormal(&e, shouldApplyV3)
+			if s.parallelApply && len(prepared) == 0 && shouldApplyV3 == membership.ApplyBoth {
+				prepared = s.prepareEntries(es[i:])
+			}
+			var pe *preparedEntry
+			if len(prepared) != 0 {
+				pe, prepared = prepared[0], prepared[1:]
+			}
+			s.applyEntryNormal(&e, shouldApplyV3, pe)
 			s.setAppliedIndex(e.Index)
 			s.setTerm(e.Term)
 
@@ -1925,8 +2268,9 @@ func (s *EtcdServer) apply(
 	return appliedt, appliedi, shouldStop
 }
 
-// applyEntryNormal applies an EntryNormal type raftpb request to the EtcdServer
-func (s *EtcdServer) applyEntryNormal(e *raftpb.Entry, shouldApplyV3 membership.ShouldApplyV3) {
+// applyEntryNormal applies an EntryNormal type raftpb request to the EtcdServer.
+// The request is the one decoded and prepared by prepareEntries if pe is not nil.
+func (s *EtcdServer) applyEntryNormal(e *raftpb.Entry, shouldApplyV3 membership.ShouldApplyV3, pe *preparedEntry) {
 	var ar *apply.Result
 	if shouldApplyV3 {
 		defer func() {
@@ -1953,18 +2297,10 @@ func (s *EtcdServer) applyEntryNormal(e *raftpb.Entry, shouldApplyV3 membership.
 	}
 
 	var raftReq pb.InternalRaftRequest
-	if !pbutil.MaybeUnmarshal(&raftReq, e.Data) { // backward compatible
-		var r pb.Request
-		rp := &r
-		pbutil.MustUnmarshal(rp, e.Data)
-		s.lg.Debug("applyEntryNormal", zap.Stringer("V2request", rp))
-		raftReq = v2ToV3Request(s.lg, (*RequestV2)(rp))
-	}
-	s.lg.Debug("applyEntryNormal", zap.Stringer("raftReq", &raftReq))
-
-	if raftReq.V2 != nil {
-		req := (*RequestV2)(raftReq.V2)
-		raftReq = v2ToV3Request(s.lg, req)
+	if pe != nil {
+		raftReq = pe.raftReq
+	} else {
+		raftReq = s.unmarshalRaftRequest(e.Data)
 	}
 
 	id := raftReq.ID
@@ -1976,11 +2312,26 @@ func (s *EtcdServer) applyEntryNormal(e *raftpb.Entry, shouldApplyV3 membership.
 	}
 
 	needResult := s.w.IsRegistered(id)
+	// a witness stores no keys, it only applies the changes to the cluster.
+	if s.Cfg.Witness && !isClusterRequest(&raftReq) {
+		if needResult {
+			s.w.Trigger(id, &apply.Result{Err: errors.ErrNotSupportedForWitness})
+		}
+		return
+	}
 	if needResult || !noSideEffect(&raftReq) {
 		if !needResult && raftReq.Txn != nil {
 			removeNeedlessRangeReqs(raftReq.Txn)
 		}
-		ar = s.uberApply.Apply(&raftReq, shouldApplyV3)
+		ctx, span := s.startApplySpan(&raftReq, e.Index)
+		if pe != nil && pe.prepared != nil {
+			ctx = txn.WithPrepared(ctx, pe.prepared)
+		}
+		ar = s.uberApply.Apply(ctx, &raftReq, shouldApplyV3)
+		if ar != nil && ar.Err != nil {
+			span.SetStatus(codes.Error, ar.Err.Error())
+		}
+		span.End()
 	}
 
 	// do not re-toApply applied entries.
@@ -1992,7 +2343,9 @@ func (s *EtcdServer) applyEntryNormal(e *raftpb.Entry, shouldApplyV3 membership.
 		return
 	}
 
-	if !errorspkg.Is(ar.Err, errors.ErrNoSpace) || len(s.alarmStore.Get(pb.AlarmType_NOSPACE)) > 0 {
+	// in grace mode, the writes exceeding the quota are rejected without
+	// capping the cluster with the alarm.
+	if !errorspkg.Is(ar.Err, errors.ErrNoSpace) || s.Cfg.QuotaBackendGrace || len(s.alarmStore.Get(pb.AlarmType_NOSPACE)) > 0 {
 		s.w.Trigger(id, ar)
 		return
 	}
@@ -2016,6 +2369,34 @@ func (s *EtcdServer) applyEntryNormal(e *raftpb.Entry, shouldApplyV3 membership.
 	})
 }
 
+// unmarshalRaftRequest decodes the request of a normal entry.
+func (s *EtcdServer) unmarshalRaftRequest(data []byte) pb.InternalRaftRequest {
+	var raftReq pb.InternalRaftRequest
+	if !pbutil.MaybeUnmarshal(&raftReq, data) { // backward compatible
+		var r pb.Request
+		rp := &r
+		pbutil.MustUnmarshal(rp, data)
+		s.lg.Debug("applyEntryNormal", zap.Stringer("V2request", rp))
+		raftReq = v2ToV3Request(s.lg, (*RequestV2)(rp))
+	}
+	s.lg.Debug("applyEntryNormal", zap.Stringer("raftReq", &raftReq))
+
+	if raftReq.V2 != nil {
+		req := (*RequestV2)(raftReq.V2)
+		raftReq = v2ToV3Request(s.lg, req)
+	}
+	return raftReq
+}
+
+// raftRequestID returns the ID of r, which is set in its header by the
+// members newer than v3.4.
+func raftRequestID(r *pb.InternalRaftRequest) uint64 {
+	if r.ID == 0 && r.Header != nil {
+		return r.Header.ID
+	}
+	return r.ID
+}
+
 func noSideEffect(r *pb.InternalRaftRequest) bool {
 	return r.Range != nil || r.AuthUserGet != nil || r.AuthRoleGet != nil || r.AuthStatus != nil
 }
@@ -2226,6 +2607,16 @@ func (s *EtcdServer) ClusterVersion() *semver.Version {
 	return s.cluster.Version()
 }
 
+// checkClusterVersion rejects the requests that the members older than v would
+// not apply the same way, until every member runs at least v.
+func (s *EtcdServer) checkClusterVersion(v semver.Version) error {
+	cv := s.ClusterVersion()
+	if cv == nil || version.LessThan(*cv, v) {
+		return errors.ErrClusterVersionTooLow
+	}
+	return nil
+}
+
 func (s *EtcdServer) StorageVersion() *semver.Version {
 	// `applySnapshot` sets a new backend instance, so we need to acquire the bemu lock.
 	s.bemu.RLock()
@@ -2329,6 +2720,30 @@ func (s *EtcdServer) monitorCompactHash() {
 	}
 }
 
+// monitorBackup ships the committed raft entries to the backup sink every
+// Cfg.BackupInterval while the member is the leader.
+func (s *EtcdServer) monitorBackup() {
+	if s.backup == nil {
+		return
+	}
+	lg := s.Logger()
+	for {
+		select {
+		case <-time.After(s.Cfg.BackupInterval):
+		case <-s.stopping:
+			lg.Info("server has stopped; stopping backup")
+			return
+		}
+		if !s.isLeader() {
+			s.backup.Reset()
+			continue
+		}
+		if err := s.backup.Ship(s.ctx); err != nil {
+			lg.Warn("failed to ship backup", zap.Error(err))
+		}
+	}
+}
+
 func (s *EtcdServer) updateClusterVersionV3(ver string) {
 	lg := s.Logger()
 
@@ -2468,6 +2883,11 @@ func (s *EtcdServer) IsLearner() bool {
 	return s.cluster.IsLocalMemberLearner()
 }
 
+// IsStandby returns if the local member is a standby member
+func (s *EtcdServer) IsStandby() bool {
+	return s.cluster.IsLocalMemberStandby()
+}
+
 // IsMemberExist returns if the member with the given id exists in cluster.
 func (s *EtcdServer) IsMemberExist(id types.ID) bool {
 	return s.cluster.IsMemberExist(id)
@@ -2506,3 +2926,31 @@ func addFeatureGateMetrics(fg featuregate.FeatureGate, guageVec *prometheus.Gaug
 		guageVec.With(prometheus.Labels{"name": string(feature), "stage": string(featureSpec.PreRelease)}).Set(metricVal)
 	}
 }
+
+// encryptionKeyring returns the keyring of the encrypted backend, sent to the
+// peers with the database snapshots, or nil if the backend isn't encrypted.
+func (s *EtcdServer) encryptionKeyring() []byte {
+	if s.Cfg.EncryptionCodec == nil {
+		return nil
+	}
+	keyring, err := s.Cfg.EncryptionCodec.Keyring()
+	if err != nil {
+		s.Logger().Warn("failed to encode encryption keyring", zap.Error(err))
+		return nil
+	}
+	return keyring
+}
+
+// checkEncryptionKeyring returns an error if the member cannot decrypt the
+// database snapshots encrypted with the keyring of a peer.
+func (s *EtcdServer) checkEncryptionKeyring(keyring []byte) error {
+	if len(keyring) == 0 {
+		return nil
+	}
+	if s.Cfg.EncryptionCodec == nil {
+		return encryption.ErrKEKRequired
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.Cfg.ReqTimeout())
+	defer cancel()
+	return s.Cfg.EncryptionCodec.CheckKeyring(ctx, keyring)
+}
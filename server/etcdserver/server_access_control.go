@@ -63,3 +63,10 @@ func (ac *AccessController) IsHostWhitelisted(host string) bool {
 	_, ok = ac.HostWhitelist[host]
 	return ok
 }
+
+// SetCORS replaces the allowed CORS origins.
+func (ac *AccessController) SetCORS(cors map[string]struct{}) {
+	ac.corsMu.Lock()
+	defer ac.corsMu.Unlock()
+	ac.CORS = cors
+}
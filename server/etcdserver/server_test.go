@@ -20,6 +20,7 @@ import (
 	errorspkg "errors"
 	"fmt"
 	"math"
+	"net"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -37,6 +38,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/api/v3/membershippb"
@@ -148,7 +151,7 @@ func TestApplyRepeat(t *testing.T) {
 
 type uberApplierMock struct{}
 
-func (uberApplierMock) Apply(r *pb.InternalRaftRequest, shouldApplyV3 membership.ShouldApplyV3) *apply2.Result {
+func (uberApplierMock) Apply(_ context.Context, r *pb.InternalRaftRequest, shouldApplyV3 membership.ShouldApplyV3) *apply2.Result {
 	return &apply2.Result{}
 }
 
@@ -191,7 +194,7 @@ func TestV2SetMemberAttributes(t *testing.T) {
 	}
 	srv.applyEntryNormal(&raftpb.Entry{
 		Data: data,
-	}, membership.ApplyV2storeOnly)
+	}, membership.ApplyV2storeOnly, nil)
 	w := membership.Attributes{Name: "abc", ClientURLs: []string{"http://127.0.0.1:2379"}}
 	if g := cl.Member(1).Attributes; !reflect.DeepEqual(g, w) {
 		t.Errorf("attributes = %v, want %v", g, w)
@@ -237,7 +240,7 @@ func TestV2SetClusterVersion(t *testing.T) {
 	}
 	srv.applyEntryNormal(&raftpb.Entry{
 		Data: data,
-	}, membership.ApplyV2storeOnly)
+	}, membership.ApplyV2storeOnly, nil)
 	if g := cl.Version(); !reflect.DeepEqual(*g, version.V3_5) {
 		t.Errorf("attributes = %v, want %v", *g, version.V3_5)
 	}
@@ -1704,3 +1707,162 @@ func TestAddFeatureGateMetrics(t *testing.T) {
 	err := ptestutil.GatherAndCompare(prometheus.DefaultGatherer, strings.NewReader(expected), "etcd_server_feature_enabled")
 	require.NoErrorf(t, err, "unexpected metric collection result: \n%s", err)
 }
+
+func TestApplyBacklogThresholdCrossed(t *testing.T) {
+	srv := &EtcdServer{
+		lgMu: new(sync.RWMutex),
+		lg:   zaptest.NewLogger(t),
+		Cfg:  config.ServerConfig{ApplyBacklogAlertThreshold: 10},
+	}
+	crossed := ptestutil.ToFloat64(applyBacklogThresholdCrossed)
+
+	srv.setAppliedIndex(100)
+	srv.setCommittedIndex(105)
+	srv.updateApplyBacklog()
+	assert.InDelta(t, 5, ptestutil.ToFloat64(applyBacklog), 0)
+	assert.InDelta(t, crossed, ptestutil.ToFloat64(applyBacklogThresholdCrossed), 0)
+
+	// inject a backlog above the threshold
+	srv.setCommittedIndex(120)
+	srv.updateApplyBacklog()
+	assert.InDelta(t, 20, ptestutil.ToFloat64(applyBacklog), 0)
+	assert.InDelta(t, crossed+1, ptestutil.ToFloat64(applyBacklogThresholdCrossed), 0)
+
+	// staying above the threshold is a single crossing
+	srv.setCommittedIndex(130)
+	srv.updateApplyBacklog()
+	assert.InDelta(t, crossed+1, ptestutil.ToFloat64(applyBacklogThresholdCrossed), 0)
+
+	// draining the backlog rearms the alert
+	srv.setAppliedIndex(125)
+	srv.updateApplyBacklog()
+	srv.setCommittedIndex(140)
+	srv.updateApplyBacklog()
+	assert.InDelta(t, 15, ptestutil.ToFloat64(applyBacklog), 0)
+	assert.InDelta(t, crossed+2, ptestutil.ToFloat64(applyBacklogThresholdCrossed), 0)
+}
+
+func TestWaitApplyBacklog(t *testing.T) {
+	srv := &EtcdServer{
+		lgMu:      new(sync.RWMutex),
+		lg:        zaptest.NewLogger(t),
+		Cfg:       config.ServerConfig{ApplyBacklogBackpressureThreshold: 10, TickMs: 1, ElectionTicks: 1},
+		applyWait: wait.NewTimeList(),
+		stopping:  make(chan struct{}),
+	}
+	delays := ptestutil.ToFloat64(applyBackpressureDelays)
+
+	srv.setAppliedIndex(100)
+	srv.setCommittedIndex(105)
+	require.NoError(t, srv.waitApplyBacklog(t.Context()))
+	assert.InDelta(t, delays, ptestutil.ToFloat64(applyBackpressureDelays), 0)
+
+	// a backlog above the threshold delays the proposal until it drains
+	srv.setCommittedIndex(120)
+	errc := make(chan error, 1)
+	go func() { errc <- srv.waitApplyBacklog(t.Context()) }()
+	select {
+	case err := <-errc:
+		t.Fatalf("proposal not delayed (%v)", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+	srv.setAppliedIndex(110)
+	srv.applyWait.Trigger(110)
+	require.NoError(t, <-errc)
+	assert.InDelta(t, delays+1, ptestutil.ToFloat64(applyBackpressureDelays), 0)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	srv.setCommittedIndex(130)
+	require.ErrorIs(t, srv.waitApplyBacklog(ctx), errors.ErrCanceled)
+}
+
+func TestCheckClusterVersion(t *testing.T) {
+	be, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, be)
+	cl := newTestClusterWithBackend(t, []*membership.Member{}, be)
+	srv := &EtcdServer{cluster: cl}
+
+	// the cluster version is unknown until the members agree on one.
+	require.ErrorIs(t, srv.checkClusterVersion(version.V3_7), errors.ErrClusterVersionTooLow)
+	cl.SetVersion(semver.New("3.6.0"), api.UpdateCapability, membership.ApplyBoth)
+	require.ErrorIs(t, srv.checkClusterVersion(version.V3_7), errors.ErrClusterVersionTooLow)
+	cl.SetVersion(semver.New("3.7.0"), api.UpdateCapability, membership.ApplyBoth)
+	require.NoError(t, srv.checkClusterVersion(version.V3_7))
+}
+
+// TestAddStandbyMemberClusterVersion ensures that the standby members are
+// only added once all the members know them.
+func TestAddStandbyMemberClusterVersion(t *testing.T) {
+	be, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, be)
+	cl := newTestClusterWithBackend(t, []*membership.Member{}, be)
+	cl.SetVersion(semver.New("3.6.0"), api.UpdateCapability, membership.ApplyBoth)
+	srv := &EtcdServer{lgMu: new(sync.RWMutex), lg: zaptest.NewLogger(t), cluster: cl}
+
+	m := membership.Member{ID: 1234, RaftAttributes: membership.RaftAttributes{PeerURLs: []string{"foo"}, IsLearner: true, IsStandby: true}}
+	_, err := srv.AddMember(t.Context(), m)
+	require.ErrorIs(t, err, errors.ErrClusterVersionTooLow)
+}
+
+// TestPutTTLClusterVersion ensures that the puts with a ttl, alone or in a txn,
+// are only proposed once all the members know the ttl.
+func TestPutTTLClusterVersion(t *testing.T) {
+	be, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, be)
+	cl := newTestClusterWithBackend(t, []*membership.Member{}, be)
+	cl.SetVersion(semver.New("3.6.0"), api.UpdateCapability, membership.ApplyBoth)
+	srv := &EtcdServer{lgMu: new(sync.RWMutex), lg: zaptest.NewLogger(t), cluster: cl}
+
+	put := &pb.PutRequest{Key: []byte("foo"), Value: []byte("bar"), Ttl: 10}
+	_, err := srv.Put(t.Context(), put)
+	require.ErrorIs(t, err, errors.ErrClusterVersionTooLow)
+
+	nested := &pb.TxnRequest{Failure: []*pb.RequestOp{{Request: &pb.RequestOp_RequestPut{RequestPut: put}}}}
+	_, err = srv.Txn(t.Context(), &pb.TxnRequest{
+		Success: []*pb.RequestOp{{Request: &pb.RequestOp_RequestTxn{RequestTxn: nested}}},
+	})
+	require.ErrorIs(t, err, errors.ErrClusterVersionTooLow)
+}
+
+// TestLeaseGrantMetadataClusterVersion ensures that the leases with metadata
+// are only granted once all the members know the metadata.
+func TestLeaseGrantMetadataClusterVersion(t *testing.T) {
+	be, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, be)
+	cl := newTestClusterWithBackend(t, []*membership.Member{}, be)
+	cl.SetVersion(semver.New("3.6.0"), api.UpdateCapability, membership.ApplyBoth)
+	srv := &EtcdServer{lgMu: new(sync.RWMutex), lg: zaptest.NewLogger(t), cluster: cl}
+
+	_, err := srv.LeaseGrant(t.Context(), &pb.LeaseGrantRequest{ID: 1, TTL: 10, Metadata: []byte("owner=foo")})
+	require.ErrorIs(t, err, errors.ErrClusterVersionTooLow)
+}
+
+func TestAuthSource(t *testing.T) {
+	tests := []struct {
+		name string
+		addr net.Addr
+		md   metadata.MD
+		want string
+	}{
+		{name: "remote", addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 2379}, want: "10.0.0.1"},
+		{name: "loopback", addr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 2379}},
+		{name: "loopback ipv6", addr: &net.TCPAddr{IP: net.IPv6loopback, Port: 2379}},
+		{name: "unix", addr: &net.UnixAddr{Name: "etcd.sock", Net: "unix"}},
+		{
+			name: "forwarded",
+			addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 2379},
+			md:   metadata.Pairs(forwardedForKey, "10.0.0.2"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: tt.addr})
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			require.Equal(t, tt.want, authSource(ctx))
+		})
+	}
+	require.Empty(t, authSource(context.Background()))
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+const tracerName = "go.etcd.io/etcd/server/v3/etcdserver"
+
+// raftTracePropagator carries trace contexts across the raft boundary in
+// the headers of proposed requests.
+var raftTracePropagator = propagation.TraceContext{}
+
+func (s *EtcdServer) tracer() trace.Tracer {
+	tp := s.Cfg.TracerProvider
+	if tp == nil {
+		tp = noop.NewTracerProvider()
+	}
+	return tp.Tracer(tracerName)
+}
+
+// injectTraceContext records the sampled span of ctx, if any, in the header
+// so that every member applying the request can continue the trace.
+func injectTraceContext(ctx context.Context, h *pb.RequestHeader) {
+	if !trace.SpanContextFromContext(ctx).IsSampled() {
+		return
+	}
+	carrier := propagation.MapCarrier{}
+	raftTracePropagator.Inject(ctx, carrier)
+	if len(carrier) > 0 {
+		h.TraceContext = carrier
+	}
+}
+
+// startApplySpan starts the span of applying the request if its proposer
+// traced it, otherwise it returns the context unchanged and a no-op span.
+func (s *EtcdServer) startApplySpan(r *pb.InternalRaftRequest, index uint64) (context.Context, trace.Span) {
+	ctx := context.Background()
+	if r.Header == nil || len(r.Header.TraceContext) == 0 {
+		return ctx, trace.SpanFromContext(ctx)
+	}
+	ctx = raftTracePropagator.Extract(ctx, propagation.MapCarrier(r.Header.TraceContext))
+	return s.tracer().Start(ctx, "apply",
+		trace.WithAttributes(
+			attribute.String("etcd.member_id", s.MemberID().String()),
+			attribute.Int64("etcd.raft_index", int64(index)),
+		),
+	)
+}
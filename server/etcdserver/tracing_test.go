@@ -0,0 +1,72 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/config"
+)
+
+func TestTraceContextAcrossRaft(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(recorder))
+	s := &EtcdServer{Cfg: config.ServerConfig{TracerProvider: tp}, memberID: 1}
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "raft request")
+	r := &pb.InternalRaftRequest{Header: &pb.RequestHeader{ID: 1}, Put: &pb.PutRequest{Key: []byte("foo")}}
+	injectTraceContext(ctx, r.Header)
+	span.End()
+	require.Contains(t, r.Header.TraceContext, "traceparent")
+
+	// the request goes through raft as bytes
+	data, err := r.Marshal()
+	require.NoError(t, err)
+	var applied pb.InternalRaftRequest
+	require.NoError(t, applied.Unmarshal(data))
+
+	_, applySpan := s.startApplySpan(&applied, 10)
+	applySpan.End()
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 2)
+	assert.Equal(t, "apply", ended[1].Name())
+	assert.Equal(t, span.SpanContext().TraceID(), ended[1].SpanContext().TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), ended[1].Parent().SpanID())
+	assert.True(t, ended[1].Parent().IsRemote())
+}
+
+func TestTraceContextNotInjectedUnlessSampled(t *testing.T) {
+	tp := tracesdk.NewTracerProvider(tracesdk.WithSampler(tracesdk.NeverSample()))
+	s := &EtcdServer{Cfg: config.ServerConfig{TracerProvider: tp}}
+
+	ctx, span := tp.Tracer("test").Start(context.Background(), "raft request")
+	defer span.End()
+	h := &pb.RequestHeader{ID: 1}
+	injectTraceContext(ctx, h)
+	assert.Empty(t, h.TraceContext)
+
+	applyCtx, applySpan := s.startApplySpan(&pb.InternalRaftRequest{Header: h}, 10)
+	assert.False(t, applySpan.SpanContext().IsValid())
+	assert.False(t, trace.SpanContextFromContext(applyCtx).IsValid())
+}
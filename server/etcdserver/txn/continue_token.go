@@ -0,0 +1,86 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+// continueTokenVersion is the first byte of a continue token, so that its
+// encoding can change without misreading tokens issued by older members.
+const continueTokenVersion = 1
+
+var ErrInvalidContinueToken = errors.New("etcdserver: invalid continue token")
+
+// encodeContinueToken returns a token to resume a range after lastKey at rev.
+func encodeContinueToken(rev int64, lastKey []byte) []byte {
+	token := make([]byte, 1, 1+binary.MaxVarintLen64+len(lastKey))
+	token[0] = continueTokenVersion
+	token = binary.AppendUvarint(token, uint64(rev))
+	return append(token, lastKey...)
+}
+
+func decodeContinueToken(token []byte) (rev int64, lastKey []byte, err error) {
+	if len(token) == 0 || token[0] != continueTokenVersion {
+		return 0, nil, ErrInvalidContinueToken
+	}
+	urev, n := binary.Uvarint(token[1:])
+	if n <= 0 || urev == 0 || urev > 1<<63-1 {
+		return 0, nil, ErrInvalidContinueToken
+	}
+	return int64(urev), token[1+n:], nil
+}
+
+// keyAscending reports whether a range returns its keys sorted by ascending
+// key, which a continue token relies on to resume after the last key.
+func keyAscending(r *pb.RangeRequest) bool {
+	if r.SortOrder == pb.RangeRequest_NONE {
+		return r.SortTarget == pb.RangeRequest_KEY
+	}
+	return r.SortOrder == pb.RangeRequest_ASCEND && r.SortTarget == pb.RangeRequest_KEY
+}
+
+// resumeRange returns the range request resuming where the continue token
+// of r stops, reading the keys after the last returned key at its revision.
+// The token is not signed, so the resumed range must stay within the range
+// of r, which is the one the permissions and namespaces are checked against.
+func resumeRange(r *pb.RangeRequest) (*pb.RangeRequest, error) {
+	rev, lastKey, err := decodeContinueToken(r.ContinueToken)
+	if err != nil {
+		return nil, err
+	}
+	if len(r.RangeEnd) == 0 || !keyAscending(r) || (r.Revision != 0 && r.Revision != rev) {
+		return nil, ErrInvalidContinueToken
+	}
+	resumed := *r
+	resumed.Revision = rev
+	if start := append(append([]byte{}, lastKey...), 0); bytes.Compare(start, r.Key) > 0 {
+		if !isAllKeysEnd(r.RangeEnd) && bytes.Compare(start, r.RangeEnd) >= 0 {
+			return nil, ErrInvalidContinueToken
+		}
+		resumed.Key = start
+	}
+	return &resumed, nil
+}
+
+// isAllKeysEnd reports whether end is the range end of every key greater
+// than or equal to the range key.
+func isAllKeysEnd(end []byte) bool {
+	return len(end) == 1 && end[0] == 0
+}
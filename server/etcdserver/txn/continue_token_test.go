@@ -0,0 +1,73 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+func TestResumeRange(t *testing.T) {
+	token := encodeContinueToken(42, []byte("foo/b"))
+
+	r, err := resumeRange(&pb.RangeRequest{Key: []byte("foo/"), RangeEnd: []byte("foo0"), Limit: 2, ContinueToken: token})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("foo/b\x00"), r.Key)
+	assert.Equal(t, []byte("foo0"), r.RangeEnd)
+	assert.Equal(t, int64(42), r.Revision)
+	assert.Equal(t, int64(2), r.Limit)
+
+	// the token never moves the start of the range backwards
+	r, err = resumeRange(&pb.RangeRequest{Key: []byte("foo/c"), RangeEnd: []byte("foo0"), ContinueToken: token})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("foo/c"), r.Key)
+
+	r, err = resumeRange(&pb.RangeRequest{Key: []byte("foo/"), RangeEnd: []byte{0}, Revision: 42, ContinueToken: token})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("foo/b\x00"), r.Key)
+	assert.Equal(t, int64(42), r.Revision)
+}
+
+func TestResumeRangeInvalid(t *testing.T) {
+	token := encodeContinueToken(42, []byte("foo/b"))
+	tcs := []struct {
+		name string
+		r    *pb.RangeRequest
+	}{
+		{name: "garbage", r: &pb.RangeRequest{Key: []byte("foo/"), RangeEnd: []byte("foo0"), ContinueToken: []byte("garbage")}},
+		{name: "truncated", r: &pb.RangeRequest{Key: []byte("foo/"), RangeEnd: []byte("foo0"), ContinueToken: token[:1]}},
+		{name: "other revision", r: &pb.RangeRequest{Key: []byte("foo/"), RangeEnd: []byte("foo0"), Revision: 41, ContinueToken: token}},
+		{
+			name: "descending keys",
+			r:    &pb.RangeRequest{Key: []byte("foo/"), RangeEnd: []byte("foo0"), SortOrder: pb.RangeRequest_DESCEND, ContinueToken: token},
+		},
+		{
+			name: "sorted by value",
+			r:    &pb.RangeRequest{Key: []byte("foo/"), RangeEnd: []byte("foo0"), SortTarget: pb.RangeRequest_VALUE, ContinueToken: token},
+		},
+		{name: "single key", r: &pb.RangeRequest{Key: []byte("foo/"), ContinueToken: token}},
+		{name: "after the range end", r: &pb.RangeRequest{Key: []byte("bar/"), RangeEnd: []byte("bar0"), ContinueToken: token}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := resumeRange(tc.r)
+			require.ErrorIs(t, err, ErrInvalidContinueToken)
+		})
+	}
+}
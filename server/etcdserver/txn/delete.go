@@ -26,6 +26,12 @@ import (
 )
 
 func DeleteRange(ctx context.Context, lg *zap.Logger, kv mvcc.KV, dr *pb.DeleteRangeRequest) (resp *pb.DeleteRangeResponse, trace *traceutil.Trace, err error) {
+	if pr := preparedFor(ctx, dr); pr != nil {
+		pr.commit(kv)
+		return pr.resp.(*pb.DeleteRangeResponse), pr.trace, pr.err
+	}
+	ctx, span := startSpan(ctx, "mvcc delete range")
+	defer func() { endSpan(span, err) }()
 	ctx, trace = ensureTrace(ctx, lg, "delete_range",
 		traceutil.Field{Key: "key", Value: string(dr.Key)},
 		traceutil.Field{Key: "range_end", Value: string(dr.RangeEnd)},
@@ -0,0 +1,42 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+var ErrInvalidKeyFilter = errors.New("etcdserver: invalid key filter")
+
+// keyMatcher returns the function telling whether a key passes the key
+// filter of the range, or nil if the range has no key filter.
+func keyMatcher(r *pb.RangeRequest) (func(key []byte) bool, error) {
+	if len(r.KeyFilter) == 0 {
+		return nil, nil
+	}
+	if !r.KeyFilterRegex {
+		filter := r.KeyFilter
+		return func(key []byte) bool { return bytes.Contains(key, filter) }, nil
+	}
+	re, err := regexp.Compile(string(r.KeyFilter))
+	if err != nil {
+		return nil, ErrInvalidKeyFilter
+	}
+	return re.Match, nil
+}
@@ -0,0 +1,348 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/pkg/v3/adt"
+	"go.etcd.io/etcd/pkg/v3/traceutil"
+	"go.etcd.io/etcd/server/v3/lease"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+// Prepared is a Put, DeleteRange or Txn request evaluated ahead of its turn
+// to be applied. The request is evaluated on the current revision of the
+// store and its writes are staged; they are written when the request is
+// applied with a context returned by WithPrepared.
+//
+// The outcome of the evaluation is the one of applying the request in turn
+// as long as the requests applied in between do not write the keys read by
+// the request, which is checked with Footprints.
+type Prepared struct {
+	req   any
+	resp  any
+	trace *traceutil.Trace
+	err   error
+
+	// base is the revision the request is evaluated on.
+	base int64
+	// write is whether the request opens a write txn.
+	write bool
+	ops   []stagedOp
+}
+
+// stagedOp is a put, or a delete of the deleted keys of a range.
+type stagedOp struct {
+	key, end []byte
+	value    []byte
+	lease    lease.LeaseID
+	ttl      int64
+	delete   bool
+	deleted  int64
+}
+
+// Prepare evaluates the Put, DeleteRange or Txn of r on kv. It returns nil if
+// r is none of them.
+func Prepare(lg *zap.Logger, lessor lease.Lessor, kv mvcc.KV, r *pb.InternalRaftRequest) *Prepared {
+	p := &Prepared{}
+	skv := &stagedKV{KV: kv, p: p}
+	ctx := context.Background()
+	switch {
+	case r.Put != nil:
+		p.req = r.Put
+		p.resp, p.trace, p.err = Put(ctx, lg, lessor, skv, r.Put)
+	case r.DeleteRange != nil:
+		p.req = r.DeleteRange
+		p.resp, p.trace, p.err = DeleteRange(ctx, lg, skv, r.DeleteRange)
+	case r.Txn != nil:
+		p.req = r.Txn
+		p.resp, p.trace, p.err = Txn(ctx, lg, r.Txn, false, skv, lessor)
+	default:
+		return nil
+	}
+	return p
+}
+
+type preparedKey struct{}
+
+// WithPrepared returns a context applying the request of p with its outcome.
+func WithPrepared(ctx context.Context, p *Prepared) context.Context {
+	return context.WithValue(ctx, preparedKey{}, p)
+}
+
+// preparedFor returns the prepared outcome of req in ctx, if any.
+func preparedFor(ctx context.Context, req any) *Prepared {
+	p, _ := ctx.Value(preparedKey{}).(*Prepared)
+	if p == nil || p.req != req {
+		return nil
+	}
+	return p
+}
+
+// commit writes the staged writes of p to kv, and shifts the revisions of the
+// response by the revisions written since p was evaluated.
+func (p *Prepared) commit(kv mvcc.KV) {
+	var rev int64
+	if p.write {
+		txnWrite := kv.Write(p.trace)
+		rev = txnWrite.Rev()
+		for _, op := range p.ops {
+			if !op.delete {
+				txnWrite.PutWithTTL(op.key, op.value, op.lease, op.ttl)
+				continue
+			}
+			if n, _ := txnWrite.DeleteRange(op.key, op.end); n != op.deleted {
+				panic(fmt.Sprintf("prepared delete of %d keys deleted %d keys", op.deleted, n))
+			}
+		}
+		txnWrite.End()
+	} else {
+		rev = kv.Rev()
+	}
+	shiftRevision(p.resp, rev-p.base)
+}
+
+// shiftRevision adds delta to the set revisions of the headers of resp.
+func shiftRevision(resp any, delta int64) {
+	shift := func(h *pb.ResponseHeader) {
+		if h != nil && h.Revision != 0 {
+			h.Revision += delta
+		}
+	}
+	switch r := resp.(type) {
+	case *pb.PutResponse:
+		if r != nil {
+			shift(r.Header)
+		}
+	case *pb.DeleteRangeResponse:
+		if r != nil {
+			shift(r.Header)
+		}
+	case *pb.RangeResponse:
+		if r != nil {
+			shift(r.Header)
+		}
+	case *pb.TxnResponse:
+		if r == nil {
+			return
+		}
+		shift(r.Header)
+		for _, op := range r.Responses {
+			switch tv := op.Response.(type) {
+			case *pb.ResponseOp_ResponseRange:
+				shiftRevision(tv.ResponseRange, delta)
+			case *pb.ResponseOp_ResponsePut:
+				shiftRevision(tv.ResponsePut, delta)
+			case *pb.ResponseOp_ResponseDeleteRange:
+				shiftRevision(tv.ResponseDeleteRange, delta)
+			case *pb.ResponseOp_ResponseTxn:
+				shiftRevision(tv.ResponseTxn, delta)
+			}
+		}
+	}
+}
+
+// stagedKV reads from a KV, and stages the writes to it in a Prepared.
+type stagedKV struct {
+	mvcc.KV
+	p *Prepared
+}
+
+func (s *stagedKV) Read(_ mvcc.ReadTxMode, trace *traceutil.Trace) mvcc.TxnRead {
+	// the requests are prepared concurrently; never share the read buffer.
+	txnRead := s.KV.Read(mvcc.ConcurrentReadTxMode, trace)
+	s.p.base = txnRead.Rev()
+	return txnRead
+}
+
+func (s *stagedKV) Write(trace *traceutil.Trace) mvcc.TxnWrite {
+	s.p.write = true
+	return &stagedTxnWrite{TxnRead: s.Read(mvcc.ConcurrentReadTxMode, trace), p: s.p}
+}
+
+// stagedTxnWrite is a write txn reading the revision it is opened on. A
+// range does not see the writes of the txn, which Footprint rules out.
+type stagedTxnWrite struct {
+	mvcc.TxnRead
+	p       *Prepared
+	changes int
+}
+
+func (tw *stagedTxnWrite) rev() int64 {
+	if tw.changes > 0 {
+		return tw.TxnRead.Rev() + 1
+	}
+	return tw.TxnRead.Rev()
+}
+
+func (tw *stagedTxnWrite) Range(ctx context.Context, key, end []byte, ro mvcc.RangeOptions) (*mvcc.RangeResult, error) {
+	rev := tw.rev()
+	if ro.Rev == rev {
+		ro.Rev = 0
+	}
+	rr, err := tw.TxnRead.Range(ctx, key, end, ro)
+	if rr != nil {
+		rr.Rev = rev
+	}
+	return rr, err
+}
+
+func (tw *stagedTxnWrite) DeleteRange(key, end []byte) (n, rev int64) {
+	rr, err := tw.TxnRead.Range(context.TODO(), key, end, mvcc.RangeOptions{Count: true})
+	if err == nil {
+		n = int64(rr.Count)
+	}
+	if n != 0 {
+		tw.p.ops = append(tw.p.ops, stagedOp{key: key, end: end, delete: true, deleted: n})
+		tw.changes += int(n)
+	}
+	return n, tw.rev()
+}
+
+func (tw *stagedTxnWrite) Put(key, value []byte, lease lease.LeaseID) int64 {
+	return tw.PutWithTTL(key, value, lease, 0)
+}
+
+func (tw *stagedTxnWrite) PutWithTTL(key, value []byte, lease lease.LeaseID, ttl int64) int64 {
+	tw.p.ops = append(tw.p.ops, stagedOp{key: key, value: value, lease: lease, ttl: ttl})
+	tw.changes++
+	return tw.rev()
+}
+
+// Changes returns as many empty changes as the txn stages.
+func (tw *stagedTxnWrite) Changes() []mvccpb.KeyValue {
+	return make([]mvccpb.KeyValue, tw.changes)
+}
+
+// Footprint is the key ranges read and written by a request.
+type Footprint struct {
+	reads, writes []adt.Interval
+}
+
+// RequestFootprint returns the footprint of the Put, DeleteRange or Txn of r.
+// It returns false if the request cannot be prepared: it is not one of them,
+// it reads at a given revision, or it reads the keys it writes in a txn.
+func RequestFootprint(r *pb.InternalRaftRequest) (Footprint, bool) {
+	var f Footprint
+	switch {
+	case r.Put != nil:
+		f.writes = append(f.writes, keyInterval(r.Put.Key, nil))
+	case r.DeleteRange != nil:
+		f.writes = append(f.writes, keyInterval(r.DeleteRange.Key, r.DeleteRange.RangeEnd))
+	case r.Txn != nil:
+		for _, c := range r.Txn.Compare {
+			f.reads = append(f.reads, keyInterval(c.Key, c.RangeEnd))
+		}
+		for _, ops := range [][]*pb.RequestOp{r.Txn.Success, r.Txn.Failure} {
+			var branch Footprint
+			if !branch.addOps(ops) || branch.overlaps() {
+				return f, false
+			}
+			f.reads = append(f.reads, branch.reads...)
+			f.writes = append(f.writes, branch.writes...)
+		}
+	default:
+		return f, false
+	}
+	return f, true
+}
+
+// addOps adds the footprint of ops, including the compares and both branches
+// of the txns among them.
+func (f *Footprint) addOps(ops []*pb.RequestOp) bool {
+	for _, op := range ops {
+		switch tv := op.Request.(type) {
+		case *pb.RequestOp_RequestRange:
+			if tv.RequestRange.Revision != 0 {
+				return false
+			}
+			f.reads = append(f.reads, keyInterval(tv.RequestRange.Key, tv.RequestRange.RangeEnd))
+		case *pb.RequestOp_RequestPut:
+			f.writes = append(f.writes, keyInterval(tv.RequestPut.Key, nil))
+		case *pb.RequestOp_RequestDeleteRange:
+			f.writes = append(f.writes, keyInterval(tv.RequestDeleteRange.Key, tv.RequestDeleteRange.RangeEnd))
+		case *pb.RequestOp_RequestTxn:
+			for _, c := range tv.RequestTxn.Compare {
+				f.reads = append(f.reads, keyInterval(c.Key, c.RangeEnd))
+			}
+			if !f.addOps(tv.RequestTxn.Success) || !f.addOps(tv.RequestTxn.Failure) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// overlaps returns whether a write of f overlaps another of its writes or
+// one of its reads.
+func (f Footprint) overlaps() bool {
+	for i, w := range f.writes {
+		for _, v := range f.writes[i+1:] {
+			if w.Compare(&v) == 0 {
+				return true
+			}
+		}
+		for _, r := range f.reads {
+			if w.Compare(&r) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func keyInterval(key, end []byte) adt.Interval {
+	if len(end) == 0 {
+		return adt.NewBytesAffinePoint(key)
+	}
+	return adt.NewBytesAffineInterval(key, mkGteRange(end))
+}
+
+// Footprints is the footprints of the requests prepared together, none of
+// which conflicts with another.
+type Footprints struct {
+	reads, writes adt.IntervalTree
+}
+
+func NewFootprints() *Footprints {
+	return &Footprints{reads: adt.NewIntervalTree(), writes: adt.NewIntervalTree()}
+}
+
+// Add adds f, unless it conflicts with a footprint added before.
+func (fs *Footprints) Add(f Footprint) bool {
+	for _, w := range f.writes {
+		if fs.writes.Intersects(w) || fs.reads.Intersects(w) {
+			return false
+		}
+	}
+	for _, r := range f.reads {
+		if fs.writes.Intersects(r) {
+			return false
+		}
+	}
+	for _, w := range f.writes {
+		fs.writes.Insert(w, struct{}{})
+	}
+	for _, r := range f.reads {
+		fs.reads.Insert(r, struct{}{})
+	}
+	return true
+}
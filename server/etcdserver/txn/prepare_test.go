@@ -0,0 +1,199 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/lease"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+func putOp(key string) *pb.RequestOp {
+	return &pb.RequestOp{Request: &pb.RequestOp_RequestPut{RequestPut: &pb.PutRequest{Key: []byte(key), Value: []byte("v"), PrevKv: true}}}
+}
+
+func rangeOp(key, end string) *pb.RequestOp {
+	r := &pb.RangeRequest{Key: []byte(key)}
+	if end != "" {
+		r.RangeEnd = []byte(end)
+	}
+	return &pb.RequestOp{Request: &pb.RequestOp_RequestRange{RequestRange: r}}
+}
+
+func versionIs(key string, v int64) *pb.Compare {
+	return &pb.Compare{Key: []byte(key), Target: pb.Compare_VERSION, TargetUnion: &pb.Compare_Version{Version: v}}
+}
+
+func applyRequest(ctx context.Context, t *testing.T, kv mvcc.KV, lessor lease.Lessor, r *pb.InternalRaftRequest) any {
+	lg := zaptest.NewLogger(t)
+	switch {
+	case r.Put != nil:
+		resp, _, err := Put(ctx, lg, lessor, kv, r.Put)
+		require.NoError(t, err)
+		return resp
+	case r.DeleteRange != nil:
+		resp, _, err := DeleteRange(ctx, lg, kv, r.DeleteRange)
+		require.NoError(t, err)
+		return resp
+	default:
+		resp, _, err := Txn(ctx, lg, r.Txn, false, kv, lessor)
+		require.NoError(t, err)
+		return resp
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	reqs := []*pb.InternalRaftRequest{
+		{Put: &pb.PutRequest{Key: []byte("a"), Value: []byte("1"), PrevKv: true}},
+		{Txn: &pb.TxnRequest{
+			Compare: []*pb.Compare{versionIs("b", 0)},
+			Success: []*pb.RequestOp{putOp("b")},
+			Failure: []*pb.RequestOp{rangeOp("b", "")},
+		}},
+		{DeleteRange: &pb.DeleteRangeRequest{Key: []byte("c"), RangeEnd: []byte("d"), PrevKv: true}},
+		{Txn: &pb.TxnRequest{
+			Compare: []*pb.Compare{versionIs("e", 0)},
+			Success: []*pb.RequestOp{putOp("e")},
+			Failure: []*pb.RequestOp{putOp("g"), rangeOp("f", ""), rangeOp("h", "i")},
+		}},
+		{Txn: &pb.TxnRequest{Success: []*pb.RequestOp{rangeOp("h", "i")}}},
+		{DeleteRange: &pb.DeleteRangeRequest{Key: []byte("x")}},
+		{Put: &pb.PutRequest{Key: []byte("y"), Value: []byte("2")}},
+	}
+	fs := NewFootprints()
+	for _, r := range reqs {
+		f, ok := RequestFootprint(r)
+		require.True(t, ok)
+		require.True(t, fs.Add(f))
+	}
+
+	newKV := func() (mvcc.KV, lease.Lessor) {
+		kv, lessor := setup(t, testSetup{})
+		for _, k := range []string{"c1", "c2", "e", "f", "h"} {
+			kv.Put([]byte(k), []byte("0"), lease.NoLease)
+		}
+		return kv, lessor
+	}
+	serial, serialLessor := newKV()
+	kv, lessor := newKV()
+
+	// the requests are all evaluated on the same revision, and applied in
+	// order afterwards.
+	prepared := make([]*Prepared, len(reqs))
+	for i, r := range reqs {
+		prepared[i] = Prepare(zaptest.NewLogger(t), lessor, kv, r)
+	}
+	for i, r := range reqs {
+		want := applyRequest(t.Context(), t, serial, serialLessor, r)
+		got := applyRequest(WithPrepared(t.Context(), prepared[i]), t, kv, lessor, r)
+		assert.Equal(t, want, got, "request %d", i)
+	}
+
+	want, err := serial.Range(t.Context(), []byte{0}, []byte{}, mvcc.RangeOptions{})
+	require.NoError(t, err)
+	got, err := kv.Range(t.Context(), []byte{0}, []byte{}, mvcc.RangeOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestFootprints(t *testing.T) {
+	tests := []struct {
+		name     string
+		reqs     []*pb.InternalRaftRequest
+		prepared bool
+		added    bool
+	}{
+		{
+			name: "disjoint puts",
+			reqs: []*pb.InternalRaftRequest{
+				{Put: &pb.PutRequest{Key: []byte("a")}},
+				{Put: &pb.PutRequest{Key: []byte("b")}},
+			},
+			prepared: true,
+			added:    true,
+		},
+		{
+			name: "compare of a written key",
+			reqs: []*pb.InternalRaftRequest{
+				{Put: &pb.PutRequest{Key: []byte("a")}},
+				{Txn: &pb.TxnRequest{Compare: []*pb.Compare{versionIs("a", 0)}}},
+			},
+			prepared: true,
+		},
+		{
+			name: "reads of the same key",
+			reqs: []*pb.InternalRaftRequest{
+				{Txn: &pb.TxnRequest{Compare: []*pb.Compare{versionIs("a", 0)}, Success: []*pb.RequestOp{putOp("b")}}},
+				{Txn: &pb.TxnRequest{Compare: []*pb.Compare{versionIs("a", 0)}, Success: []*pb.RequestOp{putOp("c")}}},
+			},
+			prepared: true,
+			added:    true,
+		},
+		{
+			name: "delete of a range including a written key",
+			reqs: []*pb.InternalRaftRequest{
+				{Put: &pb.PutRequest{Key: []byte("z")}},
+				{DeleteRange: &pb.DeleteRangeRequest{Key: []byte("a"), RangeEnd: []byte{0}}},
+			},
+			prepared: true,
+		},
+		{
+			name: "range of the key written in the other branch",
+			reqs: []*pb.InternalRaftRequest{
+				{Txn: &pb.TxnRequest{Success: []*pb.RequestOp{putOp("a")}, Failure: []*pb.RequestOp{rangeOp("a", "")}}},
+			},
+			prepared: true,
+			added:    true,
+		},
+		{
+			name: "range of the key written in the same branch",
+			reqs: []*pb.InternalRaftRequest{
+				{Txn: &pb.TxnRequest{Success: []*pb.RequestOp{putOp("a"), rangeOp("a", "b")}}},
+			},
+		},
+		{
+			name: "range at a revision",
+			reqs: []*pb.InternalRaftRequest{
+				{Txn: &pb.TxnRequest{Success: []*pb.RequestOp{{Request: &pb.RequestOp_RequestRange{RequestRange: &pb.RangeRequest{Key: []byte("a"), Revision: 1}}}}}},
+			},
+		},
+		{
+			name: "lease grant",
+			reqs: []*pb.InternalRaftRequest{{LeaseGrant: &pb.LeaseGrantRequest{ID: 1}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := NewFootprints()
+			prepared, added := true, true
+			for _, r := range tt.reqs {
+				f, ok := RequestFootprint(r)
+				prepared = prepared && ok
+				if ok {
+					added = fs.Add(f)
+				}
+			}
+			assert.Equal(t, tt.prepared, prepared)
+			assert.Equal(t, tt.added, prepared && added)
+		})
+	}
+}
@@ -27,6 +27,12 @@ import (
 )
 
 func Put(ctx context.Context, lg *zap.Logger, lessor lease.Lessor, kv mvcc.KV, p *pb.PutRequest) (resp *pb.PutResponse, trace *traceutil.Trace, err error) {
+	if pr := preparedFor(ctx, p); pr != nil {
+		pr.commit(kv)
+		return pr.resp.(*pb.PutResponse), pr.trace, pr.err
+	}
+	ctx, span := startSpan(ctx, "mvcc put")
+	defer func() { endSpan(span, err) }()
 	ctx, trace = ensureTrace(ctx, lg, "put",
 		traceutil.Field{Key: "key", Value: string(p.Key)},
 		traceutil.Field{Key: "req_size", Value: p.Size()},
@@ -62,7 +68,7 @@ func put(ctx context.Context, txnWrite mvcc.TxnWrite, p *pb.PutRequest, prevKV *
 		}
 	}
 
-	resp.Header.Revision = txnWrite.Put(p.Key, val, leaseID)
+	resp.Header.Revision = txnWrite.PutWithTTL(p.Key, val, leaseID, p.Ttl)
 	trace.AddField(traceutil.Field{Key: "response_revision", Value: resp.Header.Revision})
 	return resp
 }
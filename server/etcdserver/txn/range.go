@@ -34,9 +34,21 @@ func Range(ctx context.Context, lg *zap.Logger, kv mvcc.KV, r *pb.RangeRequest)
 		success := err == nil
 		RangeSecObserve(success, time.Since(start))
 	}(time.Now())
+	if len(r.ContinueToken) > 0 {
+		if r, err = resumeRange(r); err != nil {
+			return nil, trace, err
+		}
+	}
 	txnRead := kv.Read(mvcc.ConcurrentReadTxMode, trace)
 	defer txnRead.End()
 	resp, err = executeRange(ctx, lg, txnRead, r)
+	if err == nil && resp.More && keyAscending(r) {
+		rev := r.Revision
+		if rev == 0 {
+			rev = resp.Header.Revision
+		}
+		resp.NextToken = encodeContinueToken(rev, resp.Kvs[len(resp.Kvs)-1].Key)
+	}
 	return resp, trace, err
 }
 
@@ -46,44 +58,72 @@ func executeRange(ctx context.Context, lg *zap.Logger, txnRead mvcc.TxnRead, r *
 	resp := &pb.RangeResponse{}
 	resp.Header = &pb.ResponseHeader{}
 
-	limit := r.Limit
-	if r.SortOrder != pb.RangeRequest_NONE ||
-		r.MinModRevision != 0 || r.MaxModRevision != 0 ||
-		r.MinCreateRevision != 0 || r.MaxCreateRevision != 0 {
-		// fetch everything; sort and truncate afterwards
-		limit = 0
-	}
-	if limit > 0 {
-		// fetch one extra for 'more' flag
-		limit = limit + 1
-	}
-
-	ro := mvcc.RangeOptions{
-		Limit: limit,
-		Rev:   r.Revision,
-		Count: r.CountOnly,
-	}
-
-	rr, err := txnRead.Range(ctx, r.Key, mkGteRange(r.RangeEnd), ro)
+	match, err := keyMatcher(r)
 	if err != nil {
 		return nil, err
 	}
 
-	if r.MaxModRevision != 0 {
-		f := func(kv *mvccpb.KeyValue) bool { return kv.ModRevision > r.MaxModRevision }
-		pruneKVs(rr, f)
-	}
-	if r.MinModRevision != 0 {
-		f := func(kv *mvccpb.KeyValue) bool { return kv.ModRevision < r.MinModRevision }
-		pruneKVs(rr, f)
-	}
-	if r.MaxCreateRevision != 0 {
-		f := func(kv *mvccpb.KeyValue) bool { return kv.CreateRevision > r.MaxCreateRevision }
-		pruneKVs(rr, f)
+	filtered := match != nil || r.LeaseFilter != 0 ||
+		r.MinModRevision != 0 || r.MaxModRevision != 0 ||
+		r.MinCreateRevision != 0 || r.MaxCreateRevision != 0
+	prune := func(rr *mvcc.RangeResult) {
+		if match != nil {
+			f := func(kv *mvccpb.KeyValue) bool { return !match(kv.Key) }
+			pruneKVs(rr, f)
+		}
+		if r.LeaseFilter != 0 {
+			f := func(kv *mvccpb.KeyValue) bool { return kv.Lease != r.LeaseFilter }
+			pruneKVs(rr, f)
+		}
+		if r.MaxModRevision != 0 {
+			f := func(kv *mvccpb.KeyValue) bool { return kv.ModRevision > r.MaxModRevision }
+			pruneKVs(rr, f)
+		}
+		if r.MinModRevision != 0 {
+			f := func(kv *mvccpb.KeyValue) bool { return kv.ModRevision < r.MinModRevision }
+			pruneKVs(rr, f)
+		}
+		if r.MaxCreateRevision != 0 {
+			f := func(kv *mvccpb.KeyValue) bool { return kv.CreateRevision > r.MaxCreateRevision }
+			pruneKVs(rr, f)
+		}
+		if r.MinCreateRevision != 0 {
+			f := func(kv *mvccpb.KeyValue) bool { return kv.CreateRevision < r.MinCreateRevision }
+			pruneKVs(rr, f)
+		}
 	}
-	if r.MinCreateRevision != 0 {
-		f := func(kv *mvccpb.KeyValue) bool { return kv.CreateRevision < r.MinCreateRevision }
-		pruneKVs(rr, f)
+
+	var rr *mvcc.RangeResult
+	if filtered && r.Limit > 0 && !r.CountOnly && !r.SizeOnly && keyAscending(r) {
+		// the keys need no sorting; read them in batches until the limit is
+		// reached instead of reading the whole range
+		rr, err = rangeFiltered(ctx, txnRead, r, prune)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		limit := r.Limit
+		if r.SortOrder != pb.RangeRequest_NONE || filtered {
+			// fetch everything; sort and truncate afterwards
+			limit = 0
+		}
+		if limit > 0 {
+			// fetch one extra for 'more' flag
+			limit = limit + 1
+		}
+
+		ro := mvcc.RangeOptions{
+			Limit: limit,
+			Rev:   r.Revision,
+			Count: r.CountOnly || r.SizeOnly,
+			Size:  r.SizeOnly,
+		}
+
+		rr, err = txnRead.Range(ctx, r.Key, mkGteRange(r.RangeEnd), ro)
+		if err != nil {
+			return nil, err
+		}
+		prune(rr)
 	}
 
 	sortOrder := r.SortOrder
@@ -111,6 +151,8 @@ func executeRange(ctx context.Context, lg *zap.Logger, txnRead mvcc.TxnRead, r *
 			sorter = &kvSortByMod{&kvSort{rr.KVs}}
 		case r.SortTarget == pb.RangeRequest_VALUE:
 			sorter = &kvSortByValue{&kvSort{rr.KVs}}
+		case r.SortTarget == pb.RangeRequest_LEASE:
+			sorter = &kvSortByLease{&kvSort{rr.KVs}}
 		default:
 			lg.Panic("unexpected sort target", zap.Int32("sort-target", int32(r.SortTarget)))
 		}
@@ -129,6 +171,7 @@ func executeRange(ctx context.Context, lg *zap.Logger, txnRead mvcc.TxnRead, r *
 	trace.Step("filter and sort the key-value pairs")
 	resp.Header.Revision = rr.Rev
 	resp.Count = int64(rr.Count)
+	resp.TotalSize = rr.Size
 	resp.Kvs = make([]*mvccpb.KeyValue, len(rr.KVs))
 	for i := range rr.KVs {
 		if r.KeysOnly {
@@ -140,16 +183,49 @@ func executeRange(ctx context.Context, lg *zap.Logger, txnRead mvcc.TxnRead, r *
 	return resp, nil
 }
 
+// rangeFiltered reads the keys of the range in key order, in batches of
+// the limit of the request, until more keys than the limit pass the filters
+// or the range is exhausted. The count is the one of the whole range.
+func rangeFiltered(ctx context.Context, txnRead mvcc.TxnRead, r *pb.RangeRequest, prune func(*mvcc.RangeResult)) (*mvcc.RangeResult, error) {
+	ro := mvcc.RangeOptions{Limit: r.Limit + 1, Rev: r.Revision}
+	key, end := r.Key, mkGteRange(r.RangeEnd)
+	var res *mvcc.RangeResult
+	for {
+		rr, err := txnRead.Range(ctx, key, end, ro)
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			res = &mvcc.RangeResult{Rev: rr.Rev, Count: rr.Count}
+		}
+		if ro.Rev == 0 {
+			// read the following batches at the same revision
+			ro.Rev = rr.Rev
+		}
+		n := len(rr.KVs)
+		if n == 0 {
+			return res, nil
+		}
+		lastKey := rr.KVs[n-1].Key
+		prune(rr)
+		res.KVs = append(res.KVs, rr.KVs...)
+		if int64(n) < ro.Limit || int64(len(res.KVs)) > r.Limit {
+			return res, nil
+		}
+		key = append(append([]byte{}, lastKey...), 0)
+	}
+}
+
 func checkRange(rv mvcc.ReadView, req *pb.RangeRequest) error {
 	switch {
 	case req.Revision == 0:
-		return nil
 	case req.Revision > rv.Rev():
 		return mvcc.ErrFutureRev
 	case req.Revision < rv.FirstRev():
 		return mvcc.ErrCompacted
 	}
-	return nil
+	_, err := keyMatcher(req)
+	return err
 }
 
 func pruneKVs(rr *mvcc.RangeResult, isPrunable func(*mvccpb.KeyValue) bool) {
@@ -201,3 +277,9 @@ type kvSortByValue struct{ *kvSort }
 func (s *kvSortByValue) Less(i, j int) bool {
 	return bytes.Compare(s.kvs[i].Value, s.kvs[j].Value) < 0
 }
+
+type kvSortByLease struct{ *kvSort }
+
+func (s *kvSortByLease) Less(i, j int) bool {
+	return s.kvs[i].Lease < s.kvs[j].Lease
+}
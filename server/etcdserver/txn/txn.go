@@ -19,6 +19,8 @@ import (
 	"context"
 	"fmt"
 
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
@@ -30,6 +32,12 @@ import (
 )
 
 func Txn(ctx context.Context, lg *zap.Logger, rt *pb.TxnRequest, txnModeWriteWithSharedBuffer bool, kv mvcc.KV, lessor lease.Lessor) (txnResp *pb.TxnResponse, trace *traceutil.Trace, err error) {
+	if pr := preparedFor(ctx, rt); pr != nil {
+		pr.commit(kv)
+		return pr.resp.(*pb.TxnResponse), pr.trace, pr.err
+	}
+	ctx, span := startSpan(ctx, "mvcc txn")
+	defer func() { endSpan(span, err) }()
 	ctx, trace = ensureTrace(ctx, lg, "transaction")
 	isWrite := !IsTxnReadonly(rt)
 	// When the transaction contains write operations, we use ReadTx instead of
@@ -260,12 +268,23 @@ func applyCompare(rv mvcc.ReadView, c *pb.Compare) bool {
 	// * rewrite rules for common patterns:
 	//	ex. "[a, b) createrev > 0" => "limit 1 /\ kvs > 0"
 	// * caching
+	if c.Target == pb.Compare_COUNT {
+		rr, err := rv.Range(context.TODO(), c.Key, mkGteRange(c.RangeEnd), mvcc.RangeOptions{Count: true})
+		if err != nil {
+			return false
+		}
+		var count int64
+		if tv, _ := c.TargetUnion.(*pb.Compare_Count); tv != nil {
+			count = tv.Count
+		}
+		return compareResult(c.Result, compareInt64(int64(rr.Count), count))
+	}
 	rr, err := rv.Range(context.TODO(), c.Key, mkGteRange(c.RangeEnd), mvcc.RangeOptions{})
 	if err != nil {
 		return false
 	}
 	if len(rr.KVs) == 0 {
-		if c.Target == pb.Compare_VALUE {
+		if c.Target == pb.Compare_VALUE || c.Target == pb.Compare_VALUE_PREFIX {
 			// Always fail if comparing a value on a key/keys that doesn't exist;
 			// nil == empty string in grpc; no way to represent missing value
 			return false
@@ -310,8 +329,18 @@ func compareKV(c *pb.Compare, ckv mvccpb.KeyValue) bool {
 			rev = tv.Lease
 		}
 		result = compareInt64(ckv.Lease, rev)
+	case pb.Compare_VALUE_PREFIX:
+		var v []byte
+		if tv, _ := c.TargetUnion.(*pb.Compare_ValuePrefix); tv != nil {
+			v = tv.ValuePrefix
+		}
+		result = bytes.Compare(ckv.Value[:min(len(ckv.Value), len(v))], v)
 	}
-	switch c.Result {
+	return compareResult(c.Result, result)
+}
+
+func compareResult(r pb.Compare_CompareResult, result int) bool {
+	switch r {
 	case pb.Compare_EQUAL:
 		return result == 0
 	case pb.Compare_NOT_EQUAL:
@@ -354,7 +383,7 @@ func IsTxnReadonly(r *pb.TxnRequest) bool {
 
 func CheckTxnAuth(as auth.AuthStore, ai *auth.AuthInfo, rt *pb.TxnRequest) error {
 	for _, c := range rt.Compare {
-		if err := as.IsRangePermitted(ai, c.Key, c.RangeEnd); err != nil {
+		if err := as.IsTxnComparePermitted(ai, c.Key, c.RangeEnd); err != nil {
 			return err
 		}
 	}
@@ -364,6 +393,15 @@ func CheckTxnAuth(as auth.AuthStore, ai *auth.AuthInfo, rt *pb.TxnRequest) error
 	return checkTxnReqsPermission(as, ai, rt.Failure)
 }
 
+// CheckPutAuth checks the permission of the put, which only needs to attach
+// leases when it keeps the value of the key.
+func CheckPutAuth(as auth.AuthStore, ai *auth.AuthInfo, r *pb.PutRequest) error {
+	if r.IgnoreValue && r.Lease != 0 {
+		return as.IsLeaseAttachPermitted(ai, r.Key)
+	}
+	return as.IsPutPermitted(ai, r.Key)
+}
+
 func checkTxnReqsPermission(as auth.AuthStore, ai *auth.AuthInfo, reqs []*pb.RequestOp) error {
 	for _, requ := range reqs {
 		switch tv := requ.Request.(type) {
@@ -381,7 +419,7 @@ func checkTxnReqsPermission(as auth.AuthStore, ai *auth.AuthInfo, reqs []*pb.Req
 				continue
 			}
 
-			if err := as.IsPutPermitted(ai, tv.RequestPut.Key); err != nil {
+			if err := CheckPutAuth(as, ai, tv.RequestPut); err != nil {
 				return err
 			}
 
@@ -407,6 +445,21 @@ func checkTxnReqsPermission(as auth.AuthStore, ai *auth.AuthInfo, reqs []*pb.Req
 	return nil
 }
 
+const tracerName = "go.etcd.io/etcd/server/v3/etcdserver/txn"
+
+// startSpan starts a child of the span of ctx. It is a no-op unless the
+// request is traced.
+func startSpan(ctx context.Context, name string) (context.Context, oteltrace.Span) {
+	return oteltrace.SpanFromContext(ctx).TracerProvider().Tracer(tracerName).Start(ctx, name)
+}
+
+func endSpan(span oteltrace.Span, err error) {
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
 func ensureTrace(ctx context.Context, lg *zap.Logger, operation string, fields ...traceutil.Field) (context.Context, *traceutil.Trace) {
 	trace := traceutil.Get(ctx)
 	if trace.IsEmpty() {
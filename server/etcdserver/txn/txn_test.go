@@ -17,6 +17,7 @@ package txn
 import (
 	"context"
 	"crypto/sha256"
+	"fmt"
 	"io"
 	"os"
 	"strings"
@@ -87,6 +88,18 @@ var rangeTestCases = []testCase{
 		},
 		expectError: "mvcc: required revision has been compacted",
 	},
+	{
+		name: "Range with invalid key filter regex should fail",
+		op: &pb.RequestOp{
+			Request: &pb.RequestOp_RequestRange{
+				RequestRange: &pb.RangeRequest{
+					KeyFilter:      []byte("a("),
+					KeyFilterRegex: true,
+				},
+			},
+		},
+		expectError: "etcdserver: invalid key filter",
+	},
 }
 
 var putTestCases = []testCase{
@@ -281,6 +294,80 @@ func TestCheckRange(t *testing.T) {
 	}
 }
 
+func TestRangeFilteredLimit(t *testing.T) {
+	s, _ := setup(t, testSetup{})
+	for i := 0; i < 50; i++ {
+		s.Put([]byte(fmt.Sprintf("k%02d", i)), []byte("v"), 0)
+	}
+	rev := s.Rev()
+	s.DeleteRange([]byte("k"), []byte("l"))
+
+	// the keys passing the filter are spread over several batches, all read
+	// at the revision of the request
+	r := &pb.RangeRequest{Key: []byte("k"), RangeEnd: []byte("l"), KeyFilter: []byte("5"), Limit: 3, Revision: rev}
+	resp, _, err := Range(t.Context(), zaptest.NewLogger(t), s, r)
+	require.NoError(t, err)
+	var keys []string
+	for _, kv := range resp.Kvs {
+		keys = append(keys, string(kv.Key))
+	}
+	assert.Equal(t, []string{"k05", "k15", "k25"}, keys)
+	assert.True(t, resp.More)
+	assert.Equal(t, int64(50), resp.Count)
+
+	r.ContinueToken = resp.NextToken
+	resp, _, err = Range(t.Context(), zaptest.NewLogger(t), s, r)
+	require.NoError(t, err)
+	assert.Len(t, resp.Kvs, 2)
+	assert.False(t, resp.More)
+}
+
+func TestRangeLease(t *testing.T) {
+	s, _ := setup(t, testSetup{})
+	s.Put([]byte("a"), []byte("v"), 2)
+	s.Put([]byte("b"), []byte("v"), 1)
+	s.Put([]byte("c"), []byte("v"), 0)
+	s.Put([]byte("d"), []byte("v"), 1)
+
+	r := &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("e"), SortTarget: pb.RangeRequest_LEASE}
+	resp, _, err := Range(t.Context(), zaptest.NewLogger(t), s, r)
+	require.NoError(t, err)
+	var leases []int64
+	for _, kv := range resp.Kvs {
+		leases = append(leases, kv.Lease)
+	}
+	assert.Equal(t, []int64{0, 1, 1, 2}, leases)
+
+	r = &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("e"), LeaseFilter: 1, Limit: 1}
+	resp, _, err = Range(t.Context(), zaptest.NewLogger(t), s, r)
+	require.NoError(t, err)
+	require.Len(t, resp.Kvs, 1)
+	assert.Equal(t, "b", string(resp.Kvs[0].Key))
+	assert.True(t, resp.More)
+	assert.Equal(t, int64(4), resp.Count)
+}
+
+func TestRangeSizeOnly(t *testing.T) {
+	s, _ := setup(t, testSetup{})
+	s.Put([]byte("a"), []byte("v"), 0)
+	s.Put([]byte("b"), []byte("vvvv"), 0)
+
+	r := &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("c")}
+	resp, _, err := Range(t.Context(), zaptest.NewLogger(t), s, r)
+	require.NoError(t, err)
+	var wsize int64
+	for _, kv := range resp.Kvs {
+		wsize += int64(kv.Size())
+	}
+
+	r = &pb.RangeRequest{Key: []byte("a"), RangeEnd: []byte("c"), SizeOnly: true, Limit: 1}
+	resp, _, err = Range(t.Context(), zaptest.NewLogger(t), s, r)
+	require.NoError(t, err)
+	assert.Empty(t, resp.Kvs)
+	assert.Equal(t, int64(2), resp.Count)
+	assert.Equal(t, wsize, resp.TotalSize)
+}
+
 func setup(t *testing.T, setup testSetup) (mvcc.KV, lease.Lessor) {
 	b, _ := betesting.NewDefaultTmpBackend(t)
 	t.Cleanup(func() {
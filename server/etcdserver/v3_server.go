@@ -20,13 +20,19 @@ import (
 	"encoding/base64"
 	"encoding/binary"
 	errorspkg "errors"
+	"net"
+	"slices"
 	"strconv"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
+	"go.opentelemetry.io/otel/codes"
 	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 
+	"go.etcd.io/etcd/api/v3/authpb"
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/api/v3/version"
 	"go.etcd.io/etcd/pkg/v3/traceutil"
@@ -39,6 +45,7 @@ import (
 	"go.etcd.io/etcd/server/v3/lease"
 	"go.etcd.io/etcd/server/v3/lease/leasehttp"
 	"go.etcd.io/etcd/server/v3/storage/mvcc"
+	"go.etcd.io/etcd/server/v3/storage/schema"
 	"go.etcd.io/raft/v3"
 )
 
@@ -47,10 +54,14 @@ const (
 	// the applied index and committed index.
 	// However, if the committed entries are very heavy to toApply, the gap might grow.
 	// We should stop accepting new proposals if the gap growing to a certain point.
-	maxGapBetweenApplyAndCommitIndex = 5000
+	MaxGapBetweenApplyAndCommitIndex = 5000
 	traceThreshold                   = 100 * time.Millisecond
 	readIndexRetryTime               = 500 * time.Millisecond
 
+	// forwardedForKey is the metadata the proxies, such as the grpc gateway,
+	// forward the client addresses in.
+	forwardedForKey = "x-forwarded-for"
+
 	// The timeout for the node to catch up its applied index, and is used in
 	// lease related operations, such as LeaseRenew and LeaseTimeToLive.
 	applyTimeout = time.Second
@@ -99,6 +110,7 @@ type Authenticator interface {
 	RoleDelete(ctx context.Context, r *pb.AuthRoleDeleteRequest) (*pb.AuthRoleDeleteResponse, error)
 	UserList(ctx context.Context, r *pb.AuthUserListRequest) (*pb.AuthUserListResponse, error)
 	RoleList(ctx context.Context, r *pb.AuthRoleListRequest) (*pb.AuthRoleListResponse, error)
+	TokenRevoke(ctx context.Context, r *pb.AuthTokenRevokeRequest) (*pb.AuthTokenRevokeResponse, error)
 }
 
 func (s *EtcdServer) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeResponse, error) {
@@ -108,6 +120,7 @@ func (s *EtcdServer) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeRe
 		traceutil.Field{Key: "range_end", Value: string(r.RangeEnd)},
 	)
 	ctx = context.WithValue(ctx, traceutil.TraceKey{}, trace)
+	latency := requestLatencyFromContext(ctx)
 
 	var resp *pb.RangeResponse
 	var err error
@@ -123,7 +136,9 @@ func (s *EtcdServer) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeRe
 	}(time.Now())
 
 	if !r.Serializable {
+		start := time.Now()
 		err = s.linearizableReadNotify(ctx)
+		latency.observeRaft(start)
 		trace.Step("agreement among raft nodes before linearized reading")
 		if err != nil {
 			return nil, err
@@ -133,17 +148,67 @@ func (s *EtcdServer) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeRe
 		return s.authStore.IsRangePermitted(ai, r.Key, r.RangeEnd)
 	}
 
-	get := func() { resp, _, err = txn.Range(ctx, s.Logger(), s.KV(), r) }
+	get := func() {
+		release, lerr := s.expensiveRanges.acquire(ctx, r)
+		if lerr != nil {
+			err = lerr
+			return
+		}
+		defer release()
+		defer latency.observeBackend(time.Now())
+		resp, _, err = txn.Range(ctx, s.Logger(), s.KV(), r)
+	}
 	if serr := s.doSerialize(ctx, chk, get); serr != nil {
 		err = serr
 		return nil, err
 	}
+	if s.accessTimes != nil && err == nil {
+		s.accessTimes.Observe(resp.Kvs)
+	}
 	return resp, err
 }
 
+// KeyAccessTimes returns when the keys of the requested range were last read,
+// least recently read first.
+func (s *EtcdServer) KeyAccessTimes(ctx context.Context, r *pb.KeyAccessTimesRequest) (*pb.KeyAccessTimesResponse, error) {
+	if s.accessTimes == nil {
+		return nil, errors.ErrKeyAccessTrackingDisabled
+	}
+	resp := &pb.KeyAccessTimesResponse{}
+	for _, a := range s.accessTimes.Range(r.Key, r.RangeEnd, r.Limit) {
+		resp.Keys = append(resp.Keys, &pb.KeyAccess{Key: a.Key, LastAccessTime: a.LastAccess.UnixNano()})
+	}
+	return resp, nil
+}
+
+// RotateEncryptionKey wraps the data encryption keys of the backend with the
+// current key encryption key, re-encrypting the backend with a new data
+// encryption key if requested.
+func (s *EtcdServer) RotateEncryptionKey(ctx context.Context, r *pb.RotateEncryptionKeyRequest) (*pb.RotateEncryptionKeyResponse, error) {
+	if s.Cfg.EncryptionCodec == nil {
+		return nil, errors.ErrEncryptionDisabled
+	}
+	res, err := s.Cfg.EncryptionCodec.Rotate(ctx, s.Backend(), r.Reencrypt)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.RotateEncryptionKeyResponse{DekId: uint64(res.DEKID), KekId: res.KEKID}, nil
+}
+
 func (s *EtcdServer) Put(ctx context.Context, r *pb.PutRequest) (*pb.PutResponse, error) {
+	// the members older than 3.7 drop the ttl, so they would neither expire
+	// the key nor hash it like the others.
+	if r.Ttl != 0 {
+		if err := s.checkClusterVersion(version.V3_7); err != nil {
+			return nil, err
+		}
+	}
+	ireq := pb.InternalRaftRequest{Put: r}
+	if err := s.checkKeyQuotas(ctx, &ireq); err != nil {
+		return nil, err
+	}
 	ctx = context.WithValue(ctx, traceutil.StartTimeKey{}, time.Now())
-	resp, err := s.raftRequest(ctx, pb.InternalRaftRequest{Put: r})
+	resp, err := s.raftRequest(ctx, ireq)
 	if err != nil {
 		return nil, err
 	}
@@ -165,8 +230,11 @@ func (s *EtcdServer) Txn(ctx context.Context, r *pb.TxnRequest) (*pb.TxnResponse
 			traceutil.Field{Key: "read_only", Value: true},
 		)
 		ctx = context.WithValue(ctx, traceutil.TraceKey{}, trace)
+		latency := requestLatencyFromContext(ctx)
 		if !txn.IsTxnSerializable(r) {
+			start := time.Now()
 			err := s.linearizableReadNotify(ctx)
+			latency.observeRaft(start)
 			trace.Step("agreement among raft nodes before linearized reading")
 			if err != nil {
 				return nil, err
@@ -184,6 +252,7 @@ func (s *EtcdServer) Txn(ctx context.Context, r *pb.TxnRequest) (*pb.TxnResponse
 		}(time.Now())
 
 		get := func() {
+			defer latency.observeBackend(time.Now())
 			resp, _, err = txn.Txn(ctx, s.Logger(), r, s.Cfg.ServerFeatureGate.Enabled(features.TxnModeWriteWithSharedBuffer), s.KV(), s.lessor)
 		}
 		if serr := s.doSerialize(ctx, chk, get); serr != nil {
@@ -192,8 +261,19 @@ func (s *EtcdServer) Txn(ctx context.Context, r *pb.TxnRequest) (*pb.TxnResponse
 		return resp, err
 	}
 
+	// the members older than 3.7 drop the ttl of the puts, as in Put.
+	puts := txnPuts(txnPuts(nil, r.Success), r.Failure)
+	if slices.ContainsFunc(puts, func(p *pb.PutRequest) bool { return p.Ttl != 0 }) {
+		if err := s.checkClusterVersion(version.V3_7); err != nil {
+			return nil, err
+		}
+	}
+	ireq := pb.InternalRaftRequest{Txn: r}
+	if err := s.checkKeyQuotas(ctx, &ireq); err != nil {
+		return nil, err
+	}
 	ctx = context.WithValue(ctx, traceutil.StartTimeKey{}, time.Now())
-	resp, err := s.raftRequest(ctx, pb.InternalRaftRequest{Txn: r})
+	resp, err := s.raftRequest(ctx, ireq)
 	if err != nil {
 		return nil, err
 	}
@@ -201,6 +281,9 @@ func (s *EtcdServer) Txn(ctx context.Context, r *pb.TxnRequest) (*pb.TxnResponse
 }
 
 func (s *EtcdServer) Compact(ctx context.Context, r *pb.CompactionRequest) (*pb.CompactionResponse, error) {
+	if r.DryRun {
+		return s.compactDryRun(ctx, r)
+	}
 	startTime := time.Now()
 	result, err := s.processInternalRaftRequestOnce(ctx, pb.InternalRaftRequest{Compaction: r})
 	trace := traceutil.TODO()
@@ -245,7 +328,35 @@ func (s *EtcdServer) Compact(ctx context.Context, r *pb.CompactionRequest) (*pb.
 	return resp, nil
 }
 
+// compactDryRun estimates what the compaction would delete on this member,
+// after a linearizable read so that the revision is checked against the
+// latest compaction.
+func (s *EtcdServer) compactDryRun(ctx context.Context, r *pb.CompactionRequest) (*pb.CompactionResponse, error) {
+	if err := s.linearizableReadNotify(ctx); err != nil {
+		return nil, err
+	}
+	est, err := s.kv.CompactEstimate(r.Revision)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CompactionResponse{
+		Header:               &pb.ResponseHeader{Revision: s.kv.Rev()},
+		ReclaimableRevisions: est.Revisions,
+		ReclaimableBytes:     est.Bytes,
+	}, nil
+}
+
 func (s *EtcdServer) LeaseGrant(ctx context.Context, r *pb.LeaseGrantRequest) (*pb.LeaseGrantResponse, error) {
+	if len(r.Metadata) > lease.MaxLeaseMetadataSize {
+		return nil, lease.ErrLeaseMetadataTooLarge
+	}
+	// the members older than 3.7 drop the metadata, so they would list and
+	// report the lease without it.
+	if len(r.Metadata) > 0 {
+		if err := s.checkClusterVersion(version.V3_7); err != nil {
+			return nil, err
+		}
+	}
 	// no id given? choose one
 	for r.ID == int64(lease.NoLease) {
 		// only use positive int64 id's
@@ -279,6 +390,8 @@ func (s *EtcdServer) LeaseRevoke(ctx context.Context, r *pb.LeaseRevokeRequest)
 }
 
 func (s *EtcdServer) LeaseRenew(ctx context.Context, id lease.LeaseID) (int64, error) {
+	start := time.Now()
+	defer func() { leaseKeepAliveSec.Observe(time.Since(start).Seconds()) }()
 	if s.isLeader() {
 		// If s.isLeader() returns true, but we fail to ensure the current
 		// member's leadership, there are a couple of possibilities:
@@ -368,7 +481,7 @@ func (s *EtcdServer) leaseTimeToLive(ctx context.Context, r *pb.LeaseTimeToLiveR
 			return nil, lease.ErrLeaseNotFound
 		}
 		// TODO: fill out ResponseHeader
-		resp := &pb.LeaseTimeToLiveResponse{Header: &pb.ResponseHeader{}, ID: r.ID, TTL: int64(le.Remaining().Seconds()), GrantedTTL: le.TTL()}
+		resp := &pb.LeaseTimeToLiveResponse{Header: &pb.ResponseHeader{}, ID: r.ID, TTL: int64(le.Remaining().Seconds()), GrantedTTL: le.TTL(), Metadata: le.Metadata()}
 		if r.Keys {
 			ks := le.Keys()
 			kbs := make([][]byte, len(ks))
@@ -450,11 +563,16 @@ func (s *EtcdServer) newHeader() *pb.ResponseHeader {
 }
 
 // LeaseLeases is really ListLeases !???
-func (s *EtcdServer) LeaseLeases(_ context.Context, _ *pb.LeaseLeasesRequest) (*pb.LeaseLeasesResponse, error) {
+func (s *EtcdServer) LeaseLeases(_ context.Context, r *pb.LeaseLeasesRequest) (*pb.LeaseLeasesResponse, error) {
 	ls := s.lessor.Leases()
-	lss := make([]*pb.LeaseStatus, len(ls))
-	for i := range ls {
-		lss[i] = &pb.LeaseStatus{ID: int64(ls[i].ID)}
+	lss := make([]*pb.LeaseStatus, 0, len(ls))
+	for _, l := range ls {
+		if !bytes.HasPrefix(l.Metadata(), r.MetadataPrefix) ||
+			r.MinTTL > 0 && l.TTL() < r.MinTTL ||
+			r.MaxTTL > 0 && l.TTL() > r.MaxTTL {
+			continue
+		}
+		lss = append(lss, &pb.LeaseStatus{ID: int64(l.ID), GrantedTTL: l.TTL(), Metadata: l.Metadata()})
 	}
 	return &pb.LeaseLeasesResponse{Header: s.newHeader(), Leases: lss}, nil
 }
@@ -487,6 +605,25 @@ func (s *EtcdServer) Alarm(ctx context.Context, r *pb.AlarmRequest) (*pb.AlarmRe
 	return resp.(*pb.AlarmResponse), nil
 }
 
+func (s *EtcdServer) ReadOnly(ctx context.Context, r *pb.ReadOnlyRequest) (*pb.ReadOnlyResponse, error) {
+	// the members older than 3.7 don't know the read-only mode, so they would
+	// keep accepting the writes while the others reject them.
+	if err := s.checkClusterVersion(version.V3_7); err != nil {
+		return nil, err
+	}
+	resp, err := s.raftRequestOnce(ctx, pb.InternalRaftRequest{ReadOnly: r})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.ReadOnlyResponse), nil
+}
+
+// IsReadOnly returns whether the cluster is in read-only mode as of the
+// entries applied by the member.
+func (s *EtcdServer) IsReadOnly() bool {
+	return schema.NewMembershipBackend(s.Logger(), s.Backend()).ReadOnlyFromBackend()
+}
+
 func (s *EtcdServer) AuthEnable(ctx context.Context, r *pb.AuthEnableRequest) (*pb.AuthEnableResponse, error) {
 	resp, err := s.raftRequestOnce(ctx, pb.InternalRaftRequest{AuthEnable: r})
 	if err != nil {
@@ -525,9 +662,10 @@ func (s *EtcdServer) Authenticate(ctx context.Context, r *pb.AuthenticateRequest
 		}
 	}()
 
+	source := authSource(ctx)
 	var resp proto.Message
 	for {
-		checkedRevision, err := s.AuthStore().CheckPassword(r.Name, r.Password)
+		checkedRevision, err := s.AuthStore().CheckPasswordFrom(r.Name, r.Password, source)
 		if err != nil {
 			if !errorspkg.Is(err, auth.ErrAuthNotEnabled) {
 				lg.Warn(
@@ -565,6 +703,30 @@ func (s *EtcdServer) Authenticate(ctx context.Context, r *pb.AuthenticateRequest
 	return resp.(*pb.AuthenticateResponse), nil
 }
 
+// authSource returns the host of the client address of an authentication
+// request, which the lockouts after repeated failures are tracked by. The
+// requests from the loopback or unix sockets and the ones forwarded by a
+// proxy, such as the grpc gateway, share the address of the proxy, so they
+// aren't tracked by source: only the lockout of the user applies to them.
+func authSource(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil || p.Addr.Network() == "unix" {
+		return ""
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(forwardedForKey)) > 0 {
+		return ""
+	}
+	addr := p.Addr.String()
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	if ip := net.ParseIP(host); ip != nil && ip.IsLoopback() {
+		return ""
+	}
+	return host
+}
+
 func (s *EtcdServer) UserAdd(ctx context.Context, r *pb.AuthUserAddRequest) (*pb.AuthUserAddResponse, error) {
 	if r.Options == nil || !r.Options.NoPassword {
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(r.Password), s.authStore.BcryptCost())
@@ -574,6 +736,7 @@ func (s *EtcdServer) UserAdd(ctx context.Context, r *pb.AuthUserAddRequest) (*pb
 		r.HashedPassword = base64.StdEncoding.EncodeToString(hashedPassword)
 		r.Password = ""
 	}
+	r.PasswordChangedTime = time.Now().Unix()
 
 	resp, err := s.raftRequest(ctx, pb.InternalRaftRequest{AuthUserAdd: r})
 	if err != nil {
@@ -591,6 +754,21 @@ func (s *EtcdServer) UserDelete(ctx context.Context, r *pb.AuthUserDeleteRequest
 }
 
 func (s *EtcdServer) UserChangePassword(ctx context.Context, r *pb.AuthUserChangePasswordRequest) (*pb.AuthUserChangePasswordResponse, error) {
+	authInfo, err := s.AuthStore().AuthInfoFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.AuthStore().IsAdminPermitted(authInfo); err != nil {
+		// the users change their own passwords only to renew them before
+		// they expire, and the members older than 3.7 only let root change
+		// the passwords.
+		if !s.AuthStore().PasswordExpiryEnabled() {
+			return nil, err
+		}
+		if err = s.checkClusterVersion(version.V3_7); err != nil {
+			return nil, err
+		}
+	}
 	if r.Password != "" {
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(r.Password), s.authStore.BcryptCost())
 		if err != nil {
@@ -599,6 +777,7 @@ func (s *EtcdServer) UserChangePassword(ctx context.Context, r *pb.AuthUserChang
 		r.HashedPassword = base64.StdEncoding.EncodeToString(hashedPassword)
 		r.Password = ""
 	}
+	r.PasswordChangedTime = time.Now().Unix()
 
 	resp, err := s.raftRequest(ctx, pb.InternalRaftRequest{AuthUserChangePassword: r})
 	if err != nil {
@@ -648,6 +827,14 @@ func (s *EtcdServer) RoleAdd(ctx context.Context, r *pb.AuthRoleAddRequest) (*pb
 }
 
 func (s *EtcdServer) RoleGrantPermission(ctx context.Context, r *pb.AuthRoleGrantPermissionRequest) (*pb.AuthRoleGrantPermissionResponse, error) {
+	// the members older than 3.7 ignore the finer grained permission types,
+	// so they would deny the deletes, lease attachments and compares they
+	// permit.
+	if r.Perm != nil && r.Perm.PermType > authpb.READWRITE {
+		if err := s.checkClusterVersion(version.V3_7); err != nil {
+			return nil, err
+		}
+	}
 	resp, err := s.raftRequest(ctx, pb.InternalRaftRequest{AuthRoleGrantPermission: r})
 	if err != nil {
 		return nil, err
@@ -679,6 +866,24 @@ func (s *EtcdServer) RoleRevokePermission(ctx context.Context, r *pb.AuthRoleRev
 	return resp.(*pb.AuthRoleRevokePermissionResponse), nil
 }
 
+func (s *EtcdServer) TokenRevoke(ctx context.Context, r *pb.AuthTokenRevokeRequest) (*pb.AuthTokenRevokeResponse, error) {
+	// the members older than 3.7 don't know the revocations, so they would
+	// keep accepting the revoked tokens.
+	if err := s.checkClusterVersion(version.V3_7); err != nil {
+		return nil, err
+	}
+	// the raft log records a digest of the token instead of the token
+	internalReq, err := s.AuthStore().PrepareTokenRevoke(ctx, r.Token)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.raftRequest(ctx, pb.InternalRaftRequest{AuthTokenRevoke: internalReq})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.AuthTokenRevokeResponse), nil
+}
+
 func (s *EtcdServer) RoleDelete(ctx context.Context, r *pb.AuthRoleDeleteRequest) (*pb.AuthRoleDeleteResponse, error) {
 	resp, err := s.raftRequest(ctx, pb.InternalRaftRequest{AuthRoleDelete: r})
 	if err != nil {
@@ -737,16 +942,23 @@ func (s *EtcdServer) doSerialize(ctx context.Context, chk func(*auth.AuthInfo) e
 }
 
 func (s *EtcdServer) processInternalRaftRequestOnce(ctx context.Context, r pb.InternalRaftRequest) (*apply2.Result, error) {
+	enqueued := time.Now()
 	ai := s.getAppliedIndex()
 	ci := s.getCommittedIndex()
-	if ci > ai+maxGapBetweenApplyAndCommitIndex {
+	if ci > ai+MaxGapBetweenApplyAndCommitIndex {
 		return nil, errors.ErrTooManyRequests
 	}
+	if err := s.waitApplyBacklog(ctx); err != nil {
+		return nil, err
+	}
 
 	r.Header = &pb.RequestHeader{
 		ID: s.reqIDGen.Next(),
 	}
 
+	ctx, span := s.tracer().Start(ctx, "raft request")
+	defer span.End()
+
 	// check authinfo if it is not InternalAuthenticateRequest
 	if r.Authenticate == nil {
 		authInfo, err := s.AuthInfoFromCtx(ctx)
@@ -754,11 +966,21 @@ func (s *EtcdServer) processInternalRaftRequestOnce(ctx context.Context, r pb.In
 			return nil, err
 		}
 		if authInfo != nil {
+			// the members older than 3.7 ignore the roles, checking the
+			// permissions of the user of the same name instead.
+			if len(authInfo.Roles) > 0 {
+				if err = s.checkClusterVersion(version.V3_7); err != nil {
+					return nil, err
+				}
+			}
 			r.Header.Username = authInfo.Username
 			r.Header.AuthRevision = authInfo.Revision
+			r.Header.Roles = authInfo.Roles
 		}
 	}
 
+	injectTraceContext(ctx, r.Header)
+
 	data, err := r.Marshal()
 	if err != nil {
 		return nil, err
@@ -778,22 +1000,34 @@ func (s *EtcdServer) processInternalRaftRequestOnce(ctx context.Context, r pb.In
 	defer cancel()
 
 	start := time.Now()
+	_, proposeSpan := s.tracer().Start(ctx, "raft propose")
 	err = s.r.Propose(cctx, data)
+	proposeSpan.End()
 	if err != nil {
 		proposalsFailed.Inc()
 		s.w.Trigger(id, nil) // GC wait
+		span.SetStatus(codes.Error, err.Error())
 		return nil, err
 	}
 	proposalsPending.Inc()
 	defer proposalsPending.Dec()
+	latency := requestLatencyFromContext(ctx)
+	latency.observeQueue(enqueued)
+	proposed := time.Now()
 
+	_, waitSpan := s.tracer().Start(ctx, "wait apply")
+	defer waitSpan.End()
 	select {
 	case x := <-ch:
-		return x.(*apply2.Result), nil
+		ar := x.(*apply2.Result)
+		latency.observeProposal(proposed, ar)
+		return ar, nil
 	case <-cctx.Done():
 		proposalsFailed.Inc()
 		s.w.Trigger(id, nil) // GC wait
-		return nil, s.parseProposeCtxErr(cctx.Err(), start)
+		err = s.parseProposeCtxErr(cctx.Err(), start)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
 	case <-s.done:
 		return nil, errors.ErrStopped
 	}
@@ -824,7 +1058,7 @@ func (s *EtcdServer) linearizableReadLoop() {
 		s.readNotifier = nextnr
 		s.readMu.Unlock()
 
-		confirmedIndex, err := s.requestCurrentIndex(leaderChangedNotifier, requestID)
+		confirmedIndex, err := s.readIndex(leaderChangedNotifier, requestID)
 		if isStopped(err) {
 			return
 		}
@@ -0,0 +1,83 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"runtime/metrics"
+	"time"
+)
+
+const (
+	heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+
+	heapWatermarkInterval = time.Second
+)
+
+// Watermarks reports the highest values of server resources observed since
+// the last reset.
+type Watermarks struct {
+	// ApplyQueueDepth is the peak number of committed entry batches waiting
+	// to be applied.
+	ApplyQueueDepth int64 `json:"apply-queue-depth"`
+	// Watchers is the peak number of active watchers.
+	Watchers int64 `json:"watchers"`
+	// HeapBytes is the peak number of bytes occupied by live and
+	// not-yet-swept heap objects.
+	HeapBytes int64 `json:"heap-bytes"`
+}
+
+// Watermarks returns the resource high-watermarks of the server.
+func (s *EtcdServer) Watermarks() Watermarks {
+	s.sampleHeap()
+	return Watermarks{
+		ApplyQueueDepth: s.applyQueue.Peak(),
+		Watchers:        s.KV().Watchers().Peak(),
+		HeapBytes:       s.heap.Peak(),
+	}
+}
+
+// ResetWatermarks lowers every high-watermark to the current value of its
+// resource and returns the high-watermarks from before the reset.
+func (s *EtcdServer) ResetWatermarks() Watermarks {
+	s.sampleHeap()
+	return Watermarks{
+		ApplyQueueDepth: s.applyQueue.Reset(),
+		Watchers:        s.KV().Watchers().Reset(),
+		HeapBytes:       s.heap.Reset(),
+	}
+}
+
+// monitorHeapWatermark periodically samples the heap size, since unlike
+// the other watermarks it does not change at well-defined points.
+func (s *EtcdServer) monitorHeapWatermark() {
+	ticker := time.NewTicker(heapWatermarkInterval)
+	defer ticker.Stop()
+	for {
+		s.sampleHeap()
+		select {
+		case <-ticker.C:
+		case <-s.stopping:
+			return
+		}
+	}
+}
+
+func (s *EtcdServer) sampleHeap() {
+	sample := []metrics.Sample{{Name: heapObjectsMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() == metrics.KindUint64 {
+		s.heap.Set(int64(sample[0].Value.Uint64()))
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/client/pkg/v3/types"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+// isClusterRequest reports whether the request changes the cluster rather
+// than the keyspace, and is applied by a witness.
+func isClusterRequest(r *pb.InternalRaftRequest) bool {
+	return r.V2 != nil ||
+		r.ClusterVersionSet != nil ||
+		r.ClusterMemberAttrSet != nil ||
+		r.DowngradeInfoSet != nil ||
+		r.DowngradeVersionTest != nil
+}
+
+// handOverWitnessLeadership moves the leadership of a witness to the longest
+// connected voting member, retrying until it is no longer the leader. A
+// witness leading the cluster would have no keys to serve, nor to send to
+// the members needing a snapshot.
+func (s *EtcdServer) handOverWitnessLeadership() {
+	lg := s.Logger()
+	for s.isLeader() {
+		var ids []types.ID
+		for _, id := range s.cluster.LeaderCandidateIDs() {
+			if id != s.MemberID() {
+				ids = append(ids, id)
+			}
+		}
+		if transferee, ok := longestConnected(s.r.transport, ids); ok {
+			ctx, cancel := context.WithTimeout(s.ctx, s.Cfg.ReqTimeout())
+			err := s.MoveLeader(ctx, s.Lead(), uint64(transferee))
+			cancel()
+			if err == nil {
+				lg.Info("witness handed over leadership", zap.String("transferee-member-id", transferee.String()))
+				return
+			}
+			lg.Warn("witness failed to hand over leadership", zap.String("transferee-member-id", transferee.String()), zap.Error(err))
+		}
+		select {
+		case <-time.After(s.Cfg.ElectionTimeout()):
+		case <-s.stopping:
+			return
+		}
+	}
+}
+
+// clearWitnessKeyspace drops the keys and the leases of a snapshot received
+// by a witness.
+func clearWitnessKeyspace(lg *zap.Logger, be backend.Backend) {
+	tx := be.BatchTx()
+	tx.LockOutsideApply()
+	for _, bucket := range []backend.Bucket{schema.Key, schema.Lease} {
+		tx.UnsafeDeleteBucket(bucket)
+		tx.UnsafeCreateBucket(bucket)
+	}
+	tx.Unlock()
+	be.ForceCommit()
+	if err := be.Defrag(); err != nil {
+		lg.Warn("failed to defragment witness backend", zap.Error(err))
+	}
+}
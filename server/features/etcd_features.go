@@ -16,9 +16,12 @@ package features
 
 import (
 	"fmt"
+	"sort"
 
+	"github.com/coreos/go-semver/semver"
 	"go.uber.org/zap"
 
+	"go.etcd.io/etcd/api/v3/version"
 	"go.etcd.io/etcd/pkg/v3/featuregate"
 )
 
@@ -68,6 +71,13 @@ const (
 	// main PR: https://github.com/etcd-io/etcd/pull/13508
 	// Deprecated: Enabled by default in v3.6, to be removed in v3.7.
 	LeaseCheckpointPersist featuregate.Feature = "LeaseCheckpointPersist"
+	// ParallelApply enables the committed Put, DeleteRange and Txn requests whose keys do not conflict
+	// to be evaluated concurrently, before they are applied in order.
+	// alpha: v3.7
+	ParallelApply featuregate.Feature = "ParallelApply"
+	// QUICClientListener enables serving the gRPC client API over QUIC on the quic:// client URLs.
+	// alpha: v3.7
+	QUICClientListener featuregate.Feature = "QUICClientListener"
 	// SetMemberLocalAddr enables using the first specified and non-loopback local address from initial-advertise-peer-urls as the local address when communicating with a peer.
 	// Requires SetMemberLocalAddr featuragate to be enabled.
 	// owner: @flawedmatrix
@@ -84,6 +94,35 @@ var DefaultEtcdServerFeatureGates = map[featuregate.Feature]featuregate.FeatureS
 	LeaseCheckpoint:              {Default: false, PreRelease: featuregate.Alpha},
 	LeaseCheckpointPersist:       {Default: false, PreRelease: featuregate.Alpha},
 	SetMemberLocalAddr:           {Default: false, PreRelease: featuregate.Alpha},
+	ParallelApply:                {Default: false, PreRelease: featuregate.Alpha},
+	QUICClientListener:           {Default: false, PreRelease: featuregate.Alpha},
+}
+
+// featureVersions are the versions of etcd that introduced the features. Every
+// feature gate should be listed here too.
+var featureVersions = map[featuregate.Feature]semver.Version{
+	StopGRPCServiceOnDefrag:      version.V3_6,
+	InitialCorruptCheck:          version.V3_6,
+	CompactHashCheck:             version.V3_6,
+	TxnModeWriteWithSharedBuffer: version.V3_5,
+	LeaseCheckpoint:              version.V3_6,
+	LeaseCheckpointPersist:       version.V3_6,
+	SetMemberLocalAddr:           version.V3_6,
+	ParallelApply:                version.V3_7,
+	QUICClientListener:           version.V3_7,
+}
+
+// EnabledAfter returns the sorted features enabled in fg that were introduced
+// after the version v, and so are unknown to an etcd of version v.
+func EnabledAfter(fg featuregate.FeatureGate, v semver.Version) []string {
+	var fs []string
+	for f, fv := range featureVersions {
+		if fg.Enabled(f) && v.LessThan(fv) {
+			fs = append(fs, string(f))
+		}
+	}
+	sort.Strings(fs)
+	return fs
 }
 
 func NewDefaultServerFeatureGate(name string, lg *zap.Logger) featuregate.FeatureGate {
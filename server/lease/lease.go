@@ -28,6 +28,7 @@ type Lease struct {
 	ID           LeaseID
 	ttl          int64 // time to live of the lease in seconds
 	remainingTTL int64 // remaining time to live in seconds, if zero valued it is considered unset and the full ttl should be used
+	metadata     []byte
 	// expiryMu protects concurrent accesses to expiry
 	expiryMu sync.RWMutex
 	// expiry is time when lease should expire. no expiration when expiry.IsZero() is true
@@ -53,7 +54,7 @@ func (l *Lease) expired() bool {
 }
 
 func (l *Lease) persistTo(b backend.Backend) {
-	lpb := leasepb.Lease{ID: int64(l.ID), TTL: l.ttl, RemainingTTL: l.remainingTTL}
+	lpb := leasepb.Lease{ID: int64(l.ID), TTL: l.ttl, RemainingTTL: l.remainingTTL, Metadata: l.metadata}
 	tx := b.BatchTx()
 	tx.LockInsideApply()
 	defer tx.Unlock()
@@ -65,6 +66,11 @@ func (l *Lease) TTL() int64 {
 	return l.ttl
 }
 
+// Metadata returns the metadata the lease was granted with.
+func (l *Lease) Metadata() []byte {
+	return l.metadata
+}
+
 // SetLeaseItem sets the given lease item, this func is thread-safe
 func (l *Lease) SetLeaseItem(item LeaseItem) {
 	l.mu.Lock()
@@ -39,6 +39,9 @@ const NoLease = LeaseID(0)
 // MaxLeaseTTL is the maximum lease TTL value
 const MaxLeaseTTL = 9000000000
 
+// MaxLeaseMetadataSize is the maximum size in bytes of the metadata of a lease.
+const MaxLeaseMetadataSize = 256
+
 var (
 	forever = time.Time{}
 
@@ -61,6 +64,8 @@ var (
 	ErrLeaseNotFound    = errors.New("lease not found")
 	ErrLeaseExists      = errors.New("lease already exists")
 	ErrLeaseTTLTooLarge = errors.New("too large lease TTL")
+
+	ErrLeaseMetadataTooLarge = errors.New("too large lease metadata")
 )
 
 // TxnDelete is a TxnWrite that only permits deletes. Defined here
@@ -90,6 +95,9 @@ type Lessor interface {
 
 	// Grant grants a lease that expires at least after TTL seconds.
 	Grant(id LeaseID, ttl int64) (*Lease, error)
+	// GrantWithMetadata grants a lease like Grant, storing the given metadata
+	// with it.
+	GrantWithMetadata(id LeaseID, ttl int64, metadata []byte) (*Lease, error)
 	// Revoke revokes a lease with given ID. The item attached to the
 	// given lease will be removed. If the ID does not exist, an error
 	// will be returned.
@@ -242,6 +250,10 @@ func newLessor(lg *zap.Logger, b backend.Backend, cluster cluster, cfg LessorCon
 	}
 	l.initAndRecover()
 
+	reportActiveTTLsMu.Lock()
+	reportActiveTTLs = l.activeTTLs
+	reportActiveTTLsMu.Unlock()
+
 	go l.runLoop()
 
 	return l
@@ -279,6 +291,10 @@ func (le *lessor) SetCheckpointer(cp Checkpointer) {
 }
 
 func (le *lessor) Grant(id LeaseID, ttl int64) (*Lease, error) {
+	return le.GrantWithMetadata(id, ttl, nil)
+}
+
+func (le *lessor) GrantWithMetadata(id LeaseID, ttl int64, metadata []byte) (*Lease, error) {
 	if id == NoLease {
 		return nil, ErrLeaseNotFound
 	}
@@ -287,9 +303,14 @@ func (le *lessor) Grant(id LeaseID, ttl int64) (*Lease, error) {
 		return nil, ErrLeaseTTLTooLarge
 	}
 
+	if len(metadata) > MaxLeaseMetadataSize {
+		return nil, ErrLeaseMetadataTooLarge
+	}
+
 	// TODO: when lessor is under high load, it should give out lease
 	// with longer TTL to reduce renew load.
 	l := NewLease(id, ttl)
+	l.metadata = metadata
 
 	le.mu.Lock()
 	defer le.mu.Unlock()
@@ -469,6 +490,17 @@ func (le *lessor) Leases() []*Lease {
 	return ls
 }
 
+// activeTTLs returns the TTLs of the active leases.
+func (le *lessor) activeTTLs() []int64 {
+	le.mu.RLock()
+	defer le.mu.RUnlock()
+	ttls := make([]int64, 0, len(le.leaseMap))
+	for _, l := range le.leaseMap {
+		ttls = append(ttls, l.ttl)
+	}
+	return ttls
+}
+
 func (le *lessor) Promote(extend time.Duration) {
 	le.mu.Lock()
 	defer le.mu.Unlock()
@@ -818,6 +850,7 @@ func (le *lessor) initAndRecover() {
 			expiry:       forever,
 			revokec:      make(chan struct{}),
 			remainingTTL: lpb.RemainingTTL,
+			metadata:     lpb.Metadata,
 		}
 	}
 	le.leaseExpiredNotifier.Init()
@@ -841,6 +874,10 @@ func (fl *FakeLessor) Grant(id LeaseID, ttl int64) (*Lease, error) {
 	return nil, nil
 }
 
+func (fl *FakeLessor) GrantWithMetadata(id LeaseID, ttl int64, metadata []byte) (*Lease, error) {
+	return fl.Grant(id, ttl)
+}
+
 func (fl *FakeLessor) Revoke(id LeaseID) error { return nil }
 
 func (fl *FakeLessor) Checkpoint(id LeaseID, remainingTTL int64) error { return nil }
@@ -15,6 +15,7 @@
 package lease
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -27,6 +28,7 @@ import (
 	"time"
 
 	"github.com/coreos/go-semver/semver"
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
 
@@ -44,6 +46,44 @@ const (
 // TestLessorGrant ensures Lessor can grant wanted lease.
 // The granted lease should have a unique ID with a term
 // that is greater than minLeaseTTL.
+// TestLessorActiveTTLs ensures the histogram of the TTLs of the active
+// leases follows the granted and revoked leases.
+func TestLessorActiveTTLs(t *testing.T) {
+	lg := zap.NewNop()
+	dir, be := NewTestBackend(t)
+	defer os.RemoveAll(dir)
+	defer be.Close()
+
+	le := newLessor(lg, be, clusterLatest(), LessorConfig{MinLeaseTTL: minLeaseTTL})
+	defer le.Stop()
+	le.SetRangeDeleter(func() TxnDelete { return newFakeDeleter(be) })
+
+	for i, ttl := range []int64{10, 100, 100} {
+		if _, err := le.Grant(LeaseID(i+1), ttl); err != nil {
+			t.Fatalf("could not grant lease %d (%v)", i+1, err)
+		}
+	}
+	if err := le.Revoke(2); err != nil {
+		t.Fatalf("could not revoke lease 2 (%v)", err)
+	}
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(leaseActiveTTLs)
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := mfs[0].GetMetric()[0].GetHistogram()
+	if h.GetSampleCount() != 2 || h.GetSampleSum() != 110 {
+		t.Fatalf("count, sum = %d, %v, want 2, 110", h.GetSampleCount(), h.GetSampleSum())
+	}
+	for _, b := range h.GetBucket() {
+		if b.GetUpperBound() == 16 && b.GetCumulativeCount() != 1 {
+			t.Errorf("leases with ttl <= 16 = %d, want 1", b.GetCumulativeCount())
+		}
+	}
+}
+
 func TestLessorGrant(t *testing.T) {
 	lg := zap.NewNop()
 	dir, be := NewTestBackend(t)
@@ -426,6 +466,34 @@ func TestLessorRecover(t *testing.T) {
 	}
 }
 
+// TestLessorMetadata ensures the metadata of a lease is persisted with it
+// and that too large metadata is rejected.
+func TestLessorMetadata(t *testing.T) {
+	lg := zap.NewNop()
+	dir, be := NewTestBackend(t)
+	defer os.RemoveAll(dir)
+	defer be.Close()
+
+	le := newLessor(lg, be, clusterLatest(), LessorConfig{MinLeaseTTL: minLeaseTTL})
+	defer le.Stop()
+	_, err := le.GrantWithMetadata(1, 10, bytes.Repeat([]byte("a"), MaxLeaseMetadataSize+1))
+	if !errors.Is(err, ErrLeaseMetadataTooLarge) {
+		t.Fatalf("err = %v, want %v", err, ErrLeaseMetadataTooLarge)
+	}
+	l, err := le.GrantWithMetadata(1, 10, []byte("owner"))
+	if err != nil {
+		t.Fatalf("could not grant lease (%v)", err)
+	}
+
+	// Create a new lessor with the same backend
+	nle := newLessor(lg, be, clusterLatest(), LessorConfig{MinLeaseTTL: minLeaseTTL})
+	defer nle.Stop()
+	nl := nle.Lookup(l.ID)
+	if nl == nil || !bytes.Equal(nl.Metadata(), []byte("owner")) {
+		t.Errorf("nl = %v, want metadata %q", nl, "owner")
+	}
+}
+
 func TestLessorExpire(t *testing.T) {
 	lg := zap.NewNop()
 	dir, be := NewTestBackend(t)
@@ -15,6 +15,8 @@
 package lease
 
 import (
+	"sync"
+
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -50,11 +52,52 @@ var (
 			Buckets: prometheus.ExponentialBuckets(1, 2, 24),
 		},
 	)
+
+	leaseActiveTTLs = &ttlCollector{
+		desc: prometheus.NewDesc(
+			"etcd_debugging_lease_active_ttl",
+			"Bucketed histogram of the TTLs of the active leases.",
+			nil, nil,
+		),
+		// 1 second -> 3 months
+		buckets: prometheus.ExponentialBuckets(1, 2, 24),
+	}
+	// overridden by lessor initialization
+	reportActiveTTLsMu sync.RWMutex
+	reportActiveTTLs   = func() []int64 { return nil }
 )
 
+// ttlCollector reports the distribution of the TTLs of the active leases,
+// computed on every collection since leases come and go.
+type ttlCollector struct {
+	desc    *prometheus.Desc
+	buckets []float64
+}
+
+func (c *ttlCollector) Describe(ch chan<- *prometheus.Desc) { ch <- c.desc }
+
+func (c *ttlCollector) Collect(ch chan<- prometheus.Metric) {
+	reportActiveTTLsMu.RLock()
+	ttls := reportActiveTTLs()
+	reportActiveTTLsMu.RUnlock()
+
+	counts := make(map[float64]uint64, len(c.buckets))
+	var sum float64
+	for _, ttl := range ttls {
+		sum += float64(ttl)
+		for _, b := range c.buckets {
+			if float64(ttl) <= b {
+				counts[b]++
+			}
+		}
+	}
+	ch <- prometheus.MustNewConstHistogram(c.desc, uint64(len(ttls)), sum, counts)
+}
+
 func init() {
 	prometheus.MustRegister(leaseGranted)
 	prometheus.MustRegister(leaseRevoked)
 	prometheus.MustRegister(leaseRenewed)
 	prometheus.MustRegister(leaseTotalTTLs)
+	prometheus.MustRegister(leaseActiveTTLs)
 }
@@ -64,6 +64,10 @@ func (s *as2ac) RoleRevokePermission(ctx context.Context, in *pb.AuthRoleRevokeP
 	return s.as.RoleRevokePermission(ctx, in)
 }
 
+func (s *as2ac) TokenRevoke(ctx context.Context, in *pb.AuthTokenRevokeRequest, opts ...grpc.CallOption) (*pb.AuthTokenRevokeResponse, error) {
+	return s.as.TokenRevoke(ctx, in)
+}
+
 func (s *as2ac) RoleGrantPermission(ctx context.Context, in *pb.AuthRoleGrantPermissionRequest, opts ...grpc.CallOption) (*pb.AuthRoleGrantPermissionResponse, error) {
 	return s.as.RoleGrantPermission(ctx, in)
 }
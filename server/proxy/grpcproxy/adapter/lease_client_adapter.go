@@ -53,6 +53,18 @@ func (c *ls2lc) LeaseLeases(ctx context.Context, in *pb.LeaseLeasesRequest, opts
 	return c.leaseServer.LeaseLeases(ctx, in)
 }
 
+func (c *ls2lc) LeaseGrantBatch(ctx context.Context, in *pb.LeaseGrantBatchRequest, opts ...grpc.CallOption) (*pb.LeaseGrantBatchResponse, error) {
+	return c.leaseServer.LeaseGrantBatch(ctx, in)
+}
+
+func (c *ls2lc) LeaseRevokeBatch(ctx context.Context, in *pb.LeaseRevokeBatchRequest, opts ...grpc.CallOption) (*pb.LeaseRevokeBatchResponse, error) {
+	return c.leaseServer.LeaseRevokeBatch(ctx, in)
+}
+
+func (c *ls2lc) LeaseKeepAliveBatch(ctx context.Context, in *pb.LeaseKeepAliveBatchRequest, opts ...grpc.CallOption) (*pb.LeaseKeepAliveBatchResponse, error) {
+	return c.leaseServer.LeaseKeepAliveBatch(ctx, in)
+}
+
 // ls2lcClientStream implements Lease_LeaseKeepAliveClient
 type ls2lcClientStream struct{ chanClientStream }
 
@@ -56,6 +56,42 @@ func (s *mts2mtc) Downgrade(ctx context.Context, r *pb.DowngradeRequest, opts ..
 	return s.mts.Downgrade(ctx, r)
 }
 
+func (s *mts2mtc) Config(ctx context.Context, r *pb.ConfigRequest, opts ...grpc.CallOption) (*pb.ConfigResponse, error) {
+	return s.mts.Config(ctx, r)
+}
+
+func (s *mts2mtc) KeyAccessTimes(ctx context.Context, r *pb.KeyAccessTimesRequest, opts ...grpc.CallOption) (*pb.KeyAccessTimesResponse, error) {
+	return s.mts.KeyAccessTimes(ctx, r)
+}
+
+func (s *mts2mtc) MembershipCheck(ctx context.Context, r *pb.MembershipCheckRequest, opts ...grpc.CallOption) (*pb.MembershipCheckResponse, error) {
+	return s.mts.MembershipCheck(ctx, r)
+}
+
+func (s *mts2mtc) FollowerLag(ctx context.Context, r *pb.FollowerLagRequest, opts ...grpc.CallOption) (*pb.FollowerLagResponse, error) {
+	return s.mts.FollowerLag(ctx, r)
+}
+
+func (s *mts2mtc) HashKVCheck(ctx context.Context, r *pb.HashKVCheckRequest, opts ...grpc.CallOption) (*pb.HashKVCheckResponse, error) {
+	return s.mts.HashKVCheck(ctx, r)
+}
+
+func (s *mts2mtc) MirrorStatus(ctx context.Context, r *pb.MirrorStatusRequest, opts ...grpc.CallOption) (*pb.MirrorStatusResponse, error) {
+	return s.mts.MirrorStatus(ctx, r)
+}
+
+func (s *mts2mtc) DowngradeCheck(ctx context.Context, r *pb.DowngradeCheckRequest, opts ...grpc.CallOption) (*pb.DowngradeCheckResponse, error) {
+	return s.mts.DowngradeCheck(ctx, r)
+}
+
+func (s *mts2mtc) RotateEncryptionKey(ctx context.Context, r *pb.RotateEncryptionKeyRequest, opts ...grpc.CallOption) (*pb.RotateEncryptionKeyResponse, error) {
+	return s.mts.RotateEncryptionKey(ctx, r)
+}
+
+func (s *mts2mtc) ReadOnly(ctx context.Context, r *pb.ReadOnlyRequest, opts ...grpc.CallOption) (*pb.ReadOnlyResponse, error) {
+	return s.mts.ReadOnly(ctx, r)
+}
+
 func (s *mts2mtc) Snapshot(ctx context.Context, in *pb.SnapshotRequest, opts ...grpc.CallOption) (pb.Maintenance_SnapshotClient, error) {
 	cs := newPipeStream(ctx, func(ss chanServerStream) error {
 		return s.mts.Snapshot(in, &ss2scServerStream{ss})
@@ -92,3 +128,53 @@ func (s *ss2scServerStream) Recv() (*pb.SnapshotRequest, error) {
 	}
 	return v.(*pb.SnapshotRequest), nil
 }
+
+func (s *mts2mtc) DefragmentStatus(ctx context.Context, in *pb.DefragmentStatusRequest, opts ...grpc.CallOption) (pb.Maintenance_DefragmentStatusClient, error) {
+	cs := newPipeStream(ctx, func(ss chanServerStream) error {
+		return s.mts.DefragmentStatus(in, &ds2dcServerStream{ss})
+	})
+	return &ds2dcClientStream{cs}, nil
+}
+
+// ds2dcClientStream implements Maintenance_DefragmentStatusClient
+type ds2dcClientStream struct{ chanClientStream }
+
+// ds2dcServerStream implements Maintenance_DefragmentStatusServer
+type ds2dcServerStream struct{ chanServerStream }
+
+func (s *ds2dcClientStream) Recv() (*pb.DefragmentStatusResponse, error) {
+	var v any
+	if err := s.RecvMsg(&v); err != nil {
+		return nil, err
+	}
+	return v.(*pb.DefragmentStatusResponse), nil
+}
+
+func (s *ds2dcServerStream) Send(rr *pb.DefragmentStatusResponse) error {
+	return s.SendMsg(rr)
+}
+
+func (s *mts2mtc) Reclaim(ctx context.Context, in *pb.ReclaimRequest, opts ...grpc.CallOption) (pb.Maintenance_ReclaimClient, error) {
+	cs := newPipeStream(ctx, func(ss chanServerStream) error {
+		return s.mts.Reclaim(in, &rcs2rccServerStream{ss})
+	})
+	return &rcs2rccClientStream{cs}, nil
+}
+
+// rcs2rccClientStream implements Maintenance_ReclaimClient
+type rcs2rccClientStream struct{ chanClientStream }
+
+// rcs2rccServerStream implements Maintenance_ReclaimServer
+type rcs2rccServerStream struct{ chanServerStream }
+
+func (s *rcs2rccClientStream) Recv() (*pb.ReclaimResponse, error) {
+	var v any
+	if err := s.RecvMsg(&v); err != nil {
+		return nil, err
+	}
+	return v.(*pb.ReclaimResponse), nil
+}
+
+func (s *rcs2rccServerStream) Send(rr *pb.ReclaimResponse) error {
+	return s.SendMsg(rr)
+}
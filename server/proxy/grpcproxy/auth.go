@@ -65,6 +65,10 @@ func (ap *AuthProxy) RoleRevokePermission(ctx context.Context, r *pb.AuthRoleRev
 	return ap.authClient.RoleRevokePermission(ctx, r)
 }
 
+func (ap *AuthProxy) TokenRevoke(ctx context.Context, r *pb.AuthTokenRevokeRequest) (*pb.AuthTokenRevokeResponse, error) {
+	return ap.authClient.TokenRevoke(ctx, r)
+}
+
 func (ap *AuthProxy) RoleGrantPermission(ctx context.Context, r *pb.AuthRoleGrantPermissionRequest) (*pb.AuthRoleGrantPermissionResponse, error) {
 	return ap.authClient.RoleGrantPermission(ctx, r)
 }
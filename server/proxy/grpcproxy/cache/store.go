@@ -19,6 +19,7 @@ package cache
 import (
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/golang/groupcache/lru"
 
@@ -32,15 +33,40 @@ var (
 	ErrCompacted      = rpctypes.ErrGRPCCompacted
 )
 
+// The reasons of the removal of cached responses reported to Config.OnEvict.
+const (
+	EvictCapacity    = "capacity"
+	EvictExpired     = "expired"
+	EvictInvalidated = "invalidated"
+	EvictCompacted   = "compacted"
+)
+
 type Cache interface {
 	Add(req *pb.RangeRequest, resp *pb.RangeResponse)
 	Get(req *pb.RangeRequest) (*pb.RangeResponse, error)
 	Compact(revision int64)
 	Invalidate(key []byte, endkey []byte)
+	// InvalidateRevision invalidates the cache entries intersecting with the
+	// range from key to endkey, which was modified at the given revision.
+	// The responses of an earlier revision are not cached afterwards.
+	InvalidateRevision(key []byte, endkey []byte, revision int64)
+	// Purge invalidates all the cache entries. The responses of a revision
+	// earlier than the given one are not cached afterwards.
+	Purge(revision int64)
 	Size() int
 	Close()
 }
 
+// Config configures a cache.
+type Config struct {
+	// MaxEntries is the maximum number of cached responses, DefaultMaxEntries if zero.
+	MaxEntries int
+	// TTL is the time after which a cached response expires, never if zero.
+	TTL time.Duration
+	// OnEvict is called with the reason of each removal of a cached response.
+	OnEvict func(reason string)
+}
+
 // keyFunc returns the key of a request, which is used to look up its caching response in the cache.
 func keyFunc(req *pb.RangeRequest) string {
 	// TODO: use marshalTo to reduce allocation
@@ -52,24 +78,62 @@ func keyFunc(req *pb.RangeRequest) string {
 }
 
 func NewCache(maxCacheEntries int) Cache {
-	return &cache{
-		lru:          lru.New(maxCacheEntries),
+	return NewCacheWithConfig(Config{MaxEntries: maxCacheEntries})
+}
+
+func NewCacheWithConfig(cfg Config) Cache {
+	if cfg.MaxEntries == 0 {
+		cfg.MaxEntries = DefaultMaxEntries
+	}
+	c := &cache{
+		lru:          lru.New(cfg.MaxEntries),
+		ttl:          cfg.TTL,
+		onEvict:      cfg.OnEvict,
 		cachedRanges: adt.NewIntervalTree(),
 		compactedRev: -1,
+		evictReason:  EvictCapacity,
 	}
+	c.lru.OnEvicted = func(lru.Key, any) {
+		if c.onEvict != nil {
+			c.onEvict(c.evictReason)
+		}
+	}
+	return c
 }
 
 func (c *cache) Close() {}
 
 // cache implements Cache
 type cache struct {
-	mu  sync.RWMutex
-	lru *lru.Cache
+	mu      sync.RWMutex
+	lru     *lru.Cache
+	ttl     time.Duration
+	onEvict func(reason string)
 
 	// a reverse index for cache invalidation
 	cachedRanges adt.IntervalTree
 
 	compactedRev int64
+	// invalidatedRev is the latest revision of an invalidation. The
+	// responses of an earlier revision may miss it, so they are not cached.
+	invalidatedRev int64
+
+	// evictReason is the reason reported for the removals from lru, which
+	// are due to its capacity unless removed by remove.
+	evictReason string
+}
+
+// entry is a cached response.
+type entry struct {
+	resp  *pb.RangeResponse
+	added time.Time
+}
+
+// remove removes a cached response for the given reason.
+func (c *cache) remove(key string, reason string) {
+	c.evictReason = reason
+	c.lru.Remove(key)
+	c.evictReason = EvictCapacity
 }
 
 // Add adds the response of a request to the cache if its revision is larger than the compacted revision of the cache.
@@ -79,8 +143,11 @@ func (c *cache) Add(req *pb.RangeRequest, resp *pb.RangeResponse) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if req.Revision == 0 && resp.GetHeader().GetRevision() < c.invalidatedRev {
+		return
+	}
 	if req.Revision > c.compactedRev {
-		c.lru.Add(key, resp)
+		c.lru.Add(key, &entry{resp: resp, added: time.Now()})
 	}
 	// we do not need to invalidate a request with a revision specified.
 	// so we do not need to add it into the reverse index.
@@ -119,12 +186,16 @@ func (c *cache) Get(req *pb.RangeRequest) (*pb.RangeResponse, error) {
 	defer c.mu.Unlock()
 
 	if req.Revision > 0 && req.Revision < c.compactedRev {
-		c.lru.Remove(key)
+		c.remove(key, EvictCompacted)
 		return nil, ErrCompacted
 	}
 
-	if resp, ok := c.lru.Get(key); ok {
-		return resp.(*pb.RangeResponse), nil
+	if v, ok := c.lru.Get(key); ok {
+		e := v.(*entry)
+		if c.ttl <= 0 || time.Since(e.added) < c.ttl {
+			return e.resp, nil
+		}
+		c.remove(key, EvictExpired)
 	}
 	return nil, errors.New("not exist")
 }
@@ -133,7 +204,19 @@ func (c *cache) Get(req *pb.RangeRequest) (*pb.RangeResponse, error) {
 func (c *cache) Invalidate(key, endkey []byte) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	c.invalidate(key, endkey)
+}
+
+func (c *cache) InvalidateRevision(key, endkey []byte, revision int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if revision > c.invalidatedRev {
+		c.invalidatedRev = revision
+	}
+	c.invalidate(key, endkey)
+}
 
+func (c *cache) invalidate(key, endkey []byte) {
 	var (
 		ivs []*adt.IntervalValue
 		ivl adt.Interval
@@ -148,7 +231,7 @@ func (c *cache) Invalidate(key, endkey []byte) {
 	for _, iv := range ivs {
 		keys := iv.Val.(map[string]struct{})
 		for key := range keys {
-			c.lru.Remove(key)
+			c.remove(key, EvictInvalidated)
 		}
 	}
 	// delete after removing all keys since it is destructive to 'ivs'
@@ -166,6 +249,19 @@ func (c *cache) Compact(revision int64) {
 	}
 }
 
+func (c *cache) Purge(revision int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if revision > c.invalidatedRev {
+		c.invalidatedRev = revision
+	}
+	c.evictReason = EvictInvalidated
+	c.lru.Clear()
+	c.evictReason = EvictCapacity
+	c.cachedRanges = adt.NewIntervalTree()
+}
+
 func (c *cache) Size() int {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -17,6 +17,8 @@ package grpcproxy
 import (
 	"context"
 	"errors"
+	"io"
+	"time"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -25,32 +27,97 @@ import (
 
 type kvProxy struct {
 	kv    clientv3.KV
+	kvc   pb.KVClient
 	cache cache.Cache
 }
 
+// KvProxyConfig configures the response cache of a kv proxy.
+type KvProxyConfig struct {
+	// CacheMaxEntries is the maximum number of cached range responses,
+	// cache.DefaultMaxEntries if zero.
+	CacheMaxEntries int
+	// CacheTTL is the time after which a cached range response expires,
+	// never if zero.
+	CacheTTL time.Duration
+	// CacheWatchInvalidation invalidates the cached range responses from a
+	// watch on the keyspace, so the writes not made through the proxy are
+	// not served from the cache.
+	CacheWatchInvalidation bool
+}
+
 func NewKvProxy(c *clientv3.Client) (pb.KVServer, <-chan struct{}) {
+	return NewKvProxyWithConfig(c.Ctx(), c, KvProxyConfig{})
+}
+
+// NewKvProxyWithConfig returns a kv proxy, whose cache invalidation watch,
+// if enabled, stops once ctx is done and then closes the returned channel.
+func NewKvProxyWithConfig(ctx context.Context, c *clientv3.Client, cfg KvProxyConfig) (pb.KVServer, <-chan struct{}) {
 	kv := &kvProxy{
-		kv:    c.KV,
-		cache: cache.NewCache(cache.DefaultMaxEntries),
+		kv:  c.KV,
+		kvc: pb.NewKVClient(c.ActiveConnection()),
+		cache: cache.NewCacheWithConfig(cache.Config{
+			MaxEntries: cfg.CacheMaxEntries,
+			TTL:        cfg.CacheTTL,
+			OnEvict:    func(reason string) { cacheEvictions.WithLabelValues(reason).Inc() },
+		}),
 	}
 	donec := make(chan struct{})
-	close(donec)
+	if !cfg.CacheWatchInvalidation {
+		close(donec)
+		return kv, donec
+	}
+	go func() {
+		defer close(donec)
+		kv.invalidateLoop(ctx, c.Watcher)
+	}()
 	return kv, donec
 }
 
+// invalidateLoop invalidates the cached responses of the keys modified in the
+// whole keyspace. The cache is purged whenever the watch is (re)created,
+// since the modifications made while it was not watching are unknown.
+func (p *kvProxy) invalidateLoop(ctx context.Context, w clientv3.Watcher) {
+	for ctx.Err() == nil {
+		wctx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+		for wresp := range w.Watch(wctx, "", clientv3.WithPrefix(), clientv3.WithCreatedNotify()) {
+			if wresp.Canceled || wresp.Err() != nil {
+				break
+			}
+			if wresp.Created {
+				p.cache.Purge(wresp.Header.Revision)
+			}
+			for _, ev := range wresp.Events {
+				p.cache.InvalidateRevision(ev.Kv.Key, nil, ev.Kv.ModRevision)
+			}
+			cacheKeys.Set(float64(p.cache.Size()))
+		}
+		cancel()
+		// the cache may have missed modifications until the watch is recreated.
+		p.cache.Purge(0)
+		cacheKeys.Set(float64(p.cache.Size()))
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+		}
+	}
+}
+
 func (p *kvProxy) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeResponse, error) {
 	if r.Serializable {
 		resp, err := p.cache.Get(r)
 		switch {
 		case err == nil:
 			cacheHits.Inc()
+			cacheRequests.WithLabelValues("hit").Inc()
 			return resp, nil
 		case errors.Is(err, cache.ErrCompacted):
 			cacheHits.Inc()
+			cacheRequests.WithLabelValues("hit").Inc()
 			return nil, err
 		}
 
 		cachedMisses.Inc()
+		cacheRequests.WithLabelValues("miss").Inc()
 	}
 
 	resp, err := p.kv.Do(ctx, RangeRequestToOp(r))
@@ -68,6 +135,31 @@ func (p *kvProxy) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeRespo
 	return gresp, nil
 }
 
+func (p *kvProxy) RangeStream(r *pb.RangeRequest, stream pb.KV_RangeStreamServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	ctx = withClientAuthToken(ctx, stream.Context())
+
+	sc, err := p.kvc.RangeStream(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	for {
+		rr, err := sc.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if err = stream.Send(rr); err != nil {
+			return err
+		}
+	}
+}
+
 func (p *kvProxy) Put(ctx context.Context, r *pb.PutRequest) (*pb.PutResponse, error) {
 	p.cache.Invalidate(r.Key, nil)
 	cacheKeys.Set(float64(p.cache.Size()))
@@ -177,9 +269,22 @@ func RangeRequestToOp(r *pb.RangeRequest) clientv3.Op {
 	opts = append(opts, clientv3.WithMinCreateRev(r.MinCreateRevision))
 	opts = append(opts, clientv3.WithMaxModRev(r.MaxModRevision))
 	opts = append(opts, clientv3.WithMinModRev(r.MinModRevision))
+	if len(r.KeyFilter) > 0 {
+		if r.KeyFilterRegex {
+			opts = append(opts, clientv3.WithKeyFilterRegex(string(r.KeyFilter)))
+		} else {
+			opts = append(opts, clientv3.WithKeyFilter(string(r.KeyFilter)))
+		}
+	}
+	if r.LeaseFilter != 0 {
+		opts = append(opts, clientv3.WithLeaseFilter(clientv3.LeaseID(r.LeaseFilter)))
+	}
 	if r.CountOnly {
 		opts = append(opts, clientv3.WithCountOnly())
 	}
+	if r.SizeOnly {
+		opts = append(opts, clientv3.WithSizeOnly())
+	}
 	if r.KeysOnly {
 		opts = append(opts, clientv3.WithKeysOnly())
 	}
@@ -199,6 +304,9 @@ func PutRequestToOp(r *pb.PutRequest) clientv3.Op {
 	if r.IgnoreLease {
 		opts = append(opts, clientv3.WithIgnoreLease())
 	}
+	if r.Ttl != 0 {
+		opts = append(opts, clientv3.WithTTL(r.Ttl))
+	}
 	if r.PrevKv {
 		opts = append(opts, clientv3.WithPrevKV())
 	}
@@ -19,8 +19,6 @@ import (
 	"errors"
 	"io"
 	"sync"
-	"sync/atomic"
-	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -42,6 +40,9 @@ type leaseProxy struct {
 
 	leader *leader
 
+	// keepAlives multiplexes the keepalive requests of all the streams.
+	keepAlives *keepAliveAggregator
+
 	// mu protects adding outstanding leaseProxyStream through wg.
 	mu sync.RWMutex
 
@@ -57,6 +58,7 @@ func NewLeaseProxy(ctx context.Context, c *clientv3.Client) (pb.LeaseServer, <-c
 		ctx:         cctx,
 		leader:      newLeader(cctx, c.Watcher),
 	}
+	lp.keepAlives = newKeepAliveAggregator(cctx, lp.leaseClient, lp.leader.gotLeader, &lp.wg)
 	ch := make(chan struct{})
 	go func() {
 		defer close(ch)
@@ -111,18 +113,21 @@ func (lp *leaseProxy) LeaseTimeToLive(ctx context.Context, rr *pb.LeaseTimeToLiv
 		TTL:        r.TTL,
 		GrantedTTL: r.GrantedTTL,
 		Keys:       r.Keys,
+		Metadata:   r.Metadata,
 	}
 	return rp, err
 }
 
 func (lp *leaseProxy) LeaseLeases(ctx context.Context, rr *pb.LeaseLeasesRequest) (*pb.LeaseLeasesResponse, error) {
-	r, err := lp.lessor.Leases(ctx)
+	r, err := lp.lessor.Leases(ctx,
+		clientv3.WithMetadataPrefix(rr.MetadataPrefix),
+		clientv3.WithGrantedTTLRange(rr.MinTTL, rr.MaxTTL))
 	if err != nil {
 		return nil, err
 	}
 	leases := make([]*pb.LeaseStatus, len(r.Leases))
-	for i := range r.Leases {
-		leases[i] = &pb.LeaseStatus{ID: int64(r.Leases[i].ID)}
+	for i, ls := range r.Leases {
+		leases[i] = &pb.LeaseStatus{ID: int64(ls.ID), GrantedTTL: ls.GrantedTTL, Metadata: ls.Metadata}
 	}
 	rp := &pb.LeaseLeasesResponse{
 		Header: r.ResponseHeader,
@@ -131,6 +136,33 @@ func (lp *leaseProxy) LeaseLeases(ctx context.Context, rr *pb.LeaseLeasesRequest
 	return rp, err
 }
 
+func (lp *leaseProxy) LeaseGrantBatch(ctx context.Context, br *pb.LeaseGrantBatchRequest) (*pb.LeaseGrantBatchResponse, error) {
+	rp, err := lp.leaseClient.LeaseGrantBatch(ctx, br, grpc.WaitForReady(true))
+	if err != nil {
+		return nil, err
+	}
+	lp.leader.gotLeader()
+	return rp, nil
+}
+
+func (lp *leaseProxy) LeaseRevokeBatch(ctx context.Context, br *pb.LeaseRevokeBatchRequest) (*pb.LeaseRevokeBatchResponse, error) {
+	rp, err := lp.leaseClient.LeaseRevokeBatch(ctx, br, grpc.WaitForReady(true))
+	if err != nil {
+		return nil, err
+	}
+	lp.leader.gotLeader()
+	return rp, nil
+}
+
+func (lp *leaseProxy) LeaseKeepAliveBatch(ctx context.Context, br *pb.LeaseKeepAliveBatchRequest) (*pb.LeaseKeepAliveBatchResponse, error) {
+	rp, err := lp.leaseClient.LeaseKeepAliveBatch(ctx, br, grpc.WaitForReady(true))
+	if err != nil {
+		return nil, err
+	}
+	lp.leader.gotLeader()
+	return rp, nil
+}
+
 func (lp *leaseProxy) LeaseKeepAlive(stream pb.Lease_LeaseKeepAliveServer) error {
 	lp.mu.Lock()
 	select {
@@ -143,13 +175,13 @@ func (lp *leaseProxy) LeaseKeepAlive(stream pb.Lease_LeaseKeepAliveServer) error
 	lp.mu.Unlock()
 
 	ctx, cancel := context.WithCancel(stream.Context())
-	lps := leaseProxyStream{
-		stream:          stream,
-		lessor:          lp.lessor,
-		keepAliveLeases: make(map[int64]*atomicCounter),
-		respc:           make(chan *pb.LeaseKeepAliveResponse),
-		ctx:             ctx,
-		cancel:          cancel,
+	lps := &leaseProxyStream{
+		stream:     stream,
+		keepAlives: lp.keepAlives,
+		leases:     make(map[int64]struct{}),
+		readyc:     make(chan struct{}, 1),
+		ctx:        ctx,
+		cancel:     cancel,
 	}
 
 	errc := make(chan error, 2)
@@ -229,15 +261,16 @@ func (lp *leaseProxy) LeaseKeepAlive(stream pb.Lease_LeaseKeepAliveServer) error
 type leaseProxyStream struct {
 	stream pb.Lease_LeaseKeepAliveServer
 
-	lessor clientv3.Lease
-	// wg tracks keepAliveLoop goroutines
-	wg sync.WaitGroup
-	// mu protects keepAliveLeases
-	mu sync.RWMutex
-	// keepAliveLeases tracks how many outstanding keepalive requests which need responses are on a lease.
-	keepAliveLeases map[int64]*atomicCounter
-	// respc receives lease keepalive responses from etcd backend
-	respc chan *pb.LeaseKeepAliveResponse
+	keepAlives *keepAliveAggregator
+
+	// mu protects leases and queue
+	mu sync.Mutex
+	// leases tracks the leases the stream requested keepalives of.
+	leases map[int64]struct{}
+	// queue holds the keepalive responses yet to be sent to the client.
+	queue []*pb.LeaseKeepAliveResponse
+	// readyc is signaled when queue becomes non-empty.
+	readyc chan struct{}
 
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -253,133 +286,51 @@ func (lps *leaseProxyStream) recvLoop() error {
 			return err
 		}
 		lps.mu.Lock()
-		neededResps, ok := lps.keepAliveLeases[rr.ID]
-		if !ok {
-			neededResps = &atomicCounter{}
-			lps.keepAliveLeases[rr.ID] = neededResps
-			lps.wg.Add(1)
-			go func() {
-				defer lps.wg.Done()
-				if err := lps.keepAliveLoop(rr.ID, neededResps); err != nil {
-					lps.cancel()
-				}
-			}()
-		}
-		neededResps.add(1)
+		lps.leases[rr.ID] = struct{}{}
 		lps.mu.Unlock()
+		lps.keepAlives.keepAlive(rr.ID, lps)
 	}
 }
 
-func (lps *leaseProxyStream) keepAliveLoop(leaseID int64, neededResps *atomicCounter) error {
-	cctx, ccancel := context.WithCancel(lps.ctx)
-	defer ccancel()
-	respc, err := lps.lessor.KeepAlive(cctx, clientv3.LeaseID(leaseID))
-	if err != nil {
-		return err
+// deliver queues n copies of a keepalive response, one for each request
+// which needed it.
+func (lps *leaseProxyStream) deliver(r *pb.LeaseKeepAliveResponse, n int) {
+	if lps.ctx.Err() != nil {
+		return
 	}
-	// ticker expires when loop hasn't received keepalive within TTL
-	var ticker <-chan time.Time
-	for {
-		select {
-		case <-ticker:
-			lps.mu.Lock()
-			// if there are outstanding keepAlive reqs at the moment of ticker firing,
-			// don't close keepAliveLoop(), let it continuing to process the KeepAlive reqs.
-			if neededResps.get() > 0 {
-				lps.mu.Unlock()
-				ticker = nil
-				continue
-			}
-			delete(lps.keepAliveLeases, leaseID)
-			lps.mu.Unlock()
-			return nil
-		case rp, ok := <-respc:
-			if !ok {
-				lps.mu.Lock()
-				delete(lps.keepAliveLeases, leaseID)
-				lps.mu.Unlock()
-				if neededResps.get() == 0 {
-					return nil
-				}
-				ttlResp, err := lps.lessor.TimeToLive(cctx, clientv3.LeaseID(leaseID))
-				if err != nil {
-					return err
-				}
-				r := &pb.LeaseKeepAliveResponse{
-					Header: ttlResp.ResponseHeader,
-					ID:     int64(ttlResp.ID),
-					TTL:    ttlResp.TTL,
-				}
-				for neededResps.get() > 0 {
-					select {
-					case lps.respc <- r:
-						neededResps.add(-1)
-					case <-lps.ctx.Done():
-						return nil
-					}
-				}
-				return nil
-			}
-			if neededResps.get() == 0 {
-				continue
-			}
-			ticker = time.After(time.Duration(rp.TTL) * time.Second)
-			r := &pb.LeaseKeepAliveResponse{
-				Header: rp.ResponseHeader,
-				ID:     int64(rp.ID),
-				TTL:    rp.TTL,
-			}
-			lps.replyToClient(r, neededResps)
-		}
+	lps.mu.Lock()
+	for ; n > 0; n-- {
+		lps.queue = append(lps.queue, r)
 	}
-}
-
-func (lps *leaseProxyStream) replyToClient(r *pb.LeaseKeepAliveResponse, neededResps *atomicCounter) {
-	timer := time.After(500 * time.Millisecond)
-	for neededResps.get() > 0 {
-		select {
-		case lps.respc <- r:
-			neededResps.add(-1)
-		case <-timer:
-			return
-		case <-lps.ctx.Done():
-			return
-		}
+	lps.mu.Unlock()
+	select {
+	case lps.readyc <- struct{}{}:
+	default:
 	}
 }
 
 func (lps *leaseProxyStream) sendLoop() error {
 	for {
 		select {
-		case lrp, ok := <-lps.respc:
-			if !ok {
-				return nil
-			}
+		case <-lps.readyc:
+		case <-lps.ctx.Done():
+			return lps.ctx.Err()
+		}
+		lps.mu.Lock()
+		q := lps.queue
+		lps.queue = nil
+		lps.mu.Unlock()
+		for _, lrp := range q {
 			if err := lps.stream.Send(lrp); err != nil {
 				return err
 			}
-		case <-lps.ctx.Done():
-			return lps.ctx.Err()
 		}
 	}
 }
 
 func (lps *leaseProxyStream) close() {
 	lps.cancel()
-	lps.wg.Wait()
-	// only close respc channel if all the keepAliveLoop() goroutines have finished
-	// this ensures those goroutines don't send resp to a closed resp channel
-	close(lps.respc)
-}
-
-type atomicCounter struct {
-	counter int64
-}
-
-func (ac *atomicCounter) add(delta int64) {
-	atomic.AddInt64(&ac.counter, delta)
-}
-
-func (ac *atomicCounter) get() int64 {
-	return atomic.LoadInt64(&ac.counter)
+	lps.mu.Lock()
+	defer lps.mu.Unlock()
+	lps.keepAlives.cancel(lps.leases, lps)
 }
@@ -0,0 +1,196 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcproxy
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+const (
+	// keepAliveUpstreams is the number of upstream keepalive streams the
+	// keepalive requests of all the client streams are multiplexed onto.
+	keepAliveUpstreams = 4
+	// keepAliveRetryInterval is the wait before reopening a failed upstream
+	// keepalive stream.
+	keepAliveRetryInterval = 500 * time.Millisecond
+)
+
+// keepAliveAggregator multiplexes the keepalive requests of the client streams
+// onto a few upstream streams, sharded by lease ID. The requests on a lease
+// are coalesced while one is outstanding upstream, and its response is fanned
+// out to all the client streams waiting for it.
+type keepAliveAggregator struct {
+	shards []*keepAliveShard
+}
+
+func newKeepAliveAggregator(ctx context.Context, lc pb.LeaseClient, gotLeader func(), wg *sync.WaitGroup) *keepAliveAggregator {
+	ka := &keepAliveAggregator{shards: make([]*keepAliveShard, keepAliveUpstreams)}
+	for i := range ka.shards {
+		s := &keepAliveShard{
+			lc:        lc,
+			gotLeader: gotLeader,
+			waiters:   make(map[int64]map[*leaseProxyStream]int),
+			readyc:    make(chan struct{}, 1),
+		}
+		ka.shards[i] = s
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.run(ctx)
+		}()
+	}
+	return ka
+}
+
+func (ka *keepAliveAggregator) shard(leaseID int64) *keepAliveShard {
+	return ka.shards[uint64(leaseID)%uint64(len(ka.shards))]
+}
+
+// keepAlive requests a keepalive of the lease for lps, which is sent the
+// response once it is received.
+func (ka *keepAliveAggregator) keepAlive(leaseID int64, lps *leaseProxyStream) {
+	ka.shard(leaseID).keepAlive(leaseID, lps)
+}
+
+// cancel drops the keepalive requests of lps on the given leases.
+func (ka *keepAliveAggregator) cancel(leaseIDs map[int64]struct{}, lps *leaseProxyStream) {
+	for id := range leaseIDs {
+		ka.shard(id).cancel(id, lps)
+	}
+}
+
+// keepAliveShard forwards the keepalive requests of its leases on one
+// upstream stream.
+type keepAliveShard struct {
+	lc        pb.LeaseClient
+	gotLeader func()
+
+	mu sync.Mutex
+	// waiters tracks, for each lease with an outstanding upstream request,
+	// how many responses each client stream is waiting for.
+	waiters map[int64]map[*leaseProxyStream]int
+	// queue holds the leases yet to be requested upstream.
+	queue []int64
+	// readyc is signaled when queue becomes non-empty.
+	readyc chan struct{}
+}
+
+func (s *keepAliveShard) keepAlive(leaseID int64, lps *leaseProxyStream) {
+	s.mu.Lock()
+	w, ok := s.waiters[leaseID]
+	if !ok {
+		w = make(map[*leaseProxyStream]int)
+		s.waiters[leaseID] = w
+		s.queue = append(s.queue, leaseID)
+	}
+	w[lps]++
+	s.mu.Unlock()
+	if !ok {
+		s.notify()
+	}
+}
+
+func (s *keepAliveShard) cancel(leaseID int64, lps *leaseProxyStream) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if w, ok := s.waiters[leaseID]; ok {
+		delete(w, lps)
+	}
+}
+
+func (s *keepAliveShard) notify() {
+	select {
+	case s.readyc <- struct{}{}:
+	default:
+	}
+}
+
+// run (re)opens the upstream stream until ctx is done. The outstanding
+// requests are sent again on each new stream, since their responses may
+// have been lost with the previous one.
+func (s *keepAliveShard) run(ctx context.Context) {
+	for ctx.Err() == nil {
+		sctx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+		stream, err := s.lc.LeaseKeepAlive(sctx, grpc.WaitForReady(true))
+		if err == nil {
+			s.mu.Lock()
+			s.queue = s.queue[:0]
+			for id := range s.waiters {
+				s.queue = append(s.queue, id)
+			}
+			s.mu.Unlock()
+			s.notify()
+
+			recvc := make(chan struct{})
+			go func() {
+				defer close(recvc)
+				s.recvLoop(stream)
+				cancel()
+			}()
+			s.sendLoop(sctx, stream)
+			cancel()
+			<-recvc
+		}
+		cancel()
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(keepAliveRetryInterval):
+		}
+	}
+}
+
+func (s *keepAliveShard) sendLoop(ctx context.Context, stream pb.Lease_LeaseKeepAliveClient) {
+	for {
+		select {
+		case <-s.readyc:
+		case <-ctx.Done():
+			return
+		}
+		s.mu.Lock()
+		q := s.queue
+		s.queue = nil
+		s.mu.Unlock()
+		for _, id := range q {
+			if err := stream.Send(&pb.LeaseKeepAliveRequest{ID: id}); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *keepAliveShard) recvLoop(stream pb.Lease_LeaseKeepAliveClient) {
+	for {
+		resp, err := stream.Recv()
+		if err != nil {
+			return
+		}
+		s.gotLeader()
+		s.mu.Lock()
+		w := s.waiters[resp.ID]
+		delete(s.waiters, resp.ID)
+		s.mu.Unlock()
+		for lps, n := range w {
+			lps.deliver(resp, n)
+		}
+	}
+}
@@ -86,3 +86,91 @@ func (mp *maintenanceProxy) MoveLeader(ctx context.Context, r *pb.MoveLeaderRequ
 func (mp *maintenanceProxy) Downgrade(ctx context.Context, r *pb.DowngradeRequest) (*pb.DowngradeResponse, error) {
 	return mp.maintenanceClient.Downgrade(ctx, r)
 }
+
+func (mp *maintenanceProxy) Config(ctx context.Context, r *pb.ConfigRequest) (*pb.ConfigResponse, error) {
+	return mp.maintenanceClient.Config(ctx, r)
+}
+
+func (mp *maintenanceProxy) KeyAccessTimes(ctx context.Context, r *pb.KeyAccessTimesRequest) (*pb.KeyAccessTimesResponse, error) {
+	return mp.maintenanceClient.KeyAccessTimes(ctx, r)
+}
+
+func (mp *maintenanceProxy) MembershipCheck(ctx context.Context, r *pb.MembershipCheckRequest) (*pb.MembershipCheckResponse, error) {
+	return mp.maintenanceClient.MembershipCheck(ctx, r)
+}
+
+func (mp *maintenanceProxy) FollowerLag(ctx context.Context, r *pb.FollowerLagRequest) (*pb.FollowerLagResponse, error) {
+	return mp.maintenanceClient.FollowerLag(ctx, r)
+}
+
+func (mp *maintenanceProxy) HashKVCheck(ctx context.Context, r *pb.HashKVCheckRequest) (*pb.HashKVCheckResponse, error) {
+	return mp.maintenanceClient.HashKVCheck(ctx, r)
+}
+
+func (mp *maintenanceProxy) MirrorStatus(ctx context.Context, r *pb.MirrorStatusRequest) (*pb.MirrorStatusResponse, error) {
+	return mp.maintenanceClient.MirrorStatus(ctx, r)
+}
+
+func (mp *maintenanceProxy) DowngradeCheck(ctx context.Context, r *pb.DowngradeCheckRequest) (*pb.DowngradeCheckResponse, error) {
+	return mp.maintenanceClient.DowngradeCheck(ctx, r)
+}
+
+func (mp *maintenanceProxy) RotateEncryptionKey(ctx context.Context, r *pb.RotateEncryptionKeyRequest) (*pb.RotateEncryptionKeyResponse, error) {
+	return mp.maintenanceClient.RotateEncryptionKey(ctx, r)
+}
+
+func (mp *maintenanceProxy) ReadOnly(ctx context.Context, r *pb.ReadOnlyRequest) (*pb.ReadOnlyResponse, error) {
+	return mp.maintenanceClient.ReadOnly(ctx, r)
+}
+
+func (mp *maintenanceProxy) DefragmentStatus(r *pb.DefragmentStatusRequest, stream pb.Maintenance_DefragmentStatusServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	ctx = withClientAuthToken(ctx, stream.Context())
+
+	sc, err := mp.maintenanceClient.DefragmentStatus(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	for {
+		rr, err := sc.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		err = stream.Send(rr)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (mp *maintenanceProxy) Reclaim(r *pb.ReclaimRequest, stream pb.Maintenance_ReclaimServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	ctx = withClientAuthToken(ctx, stream.Context())
+
+	sc, err := mp.maintenanceClient.Reclaim(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	for {
+		rr, err := sc.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		err = stream.Send(rr)
+		if err != nil {
+			return err
+		}
+	}
+}
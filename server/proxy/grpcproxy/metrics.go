@@ -59,6 +59,18 @@ var (
 		Name:      "cache_misses_total",
 		Help:      "Total number of cache misses",
 	})
+	cacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "etcd",
+		Subsystem: "grpc_proxy",
+		Name:      "cache_requests_total",
+		Help:      "Total number of serializable range requests looked up in the cache, by result (hit or miss).",
+	}, []string{"result"})
+	cacheEvictions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "etcd",
+		Subsystem: "grpc_proxy",
+		Name:      "cache_evictions_total",
+		Help:      "Total number of responses removed from the cache, by reason (capacity, expired, invalidated or compacted).",
+	}, []string{"reason"})
 )
 
 func init() {
@@ -67,6 +79,8 @@ func init() {
 	prometheus.MustRegister(cacheKeys)
 	prometheus.MustRegister(cacheHits)
 	prometheus.MustRegister(cachedMisses)
+	prometheus.MustRegister(cacheRequests)
+	prometheus.MustRegister(cacheEvictions)
 }
 
 // HandleMetrics performs a GET request against etcd endpoint and returns '/metrics'.
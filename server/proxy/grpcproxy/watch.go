@@ -17,6 +17,7 @@ package grpcproxy
 import (
 	"context"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
@@ -256,6 +257,8 @@ func (wps *watchProxyStream) recvLoop() error {
 				progress: cr.ProgressNotify,
 				prevKV:   cr.PrevKv,
 				filters:  v3rpc.FiltersFromRequest(cr),
+
+				bookmarkInterval: time.Duration(cr.BookmarkInterval) * time.Second,
 			}
 			if !w.wr.valid() {
 				w.post(&pb.WatchResponse{WatchId: clientv3.InvalidWatchID, Created: true, Canceled: true})
@@ -25,11 +25,18 @@ import (
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
 
+// watchBroadcastBookmarkInterval is the interval between the bookmarks a
+// broadcast serving watchers with a bookmark interval asks the server for.
+const watchBroadcastBookmarkInterval = time.Second
+
 // watchBroadcast broadcasts a server watcher to many client watchers.
 type watchBroadcast struct {
 	// cancel stops the underlying etcd server watcher and closes ch.
 	cancel context.CancelFunc
 	donec  chan struct{}
+	// bookmarks is set if the server watcher sends bookmarks, so that
+	// the broadcast can serve watchers with a bookmark interval.
+	bookmarks bool
 
 	// mu protects rev and receivers.
 	mu sync.RWMutex
@@ -49,6 +56,7 @@ func newWatchBroadcast(lg *zap.Logger, wp *watchProxy, w *watcher, update func(*
 		nextrev:   w.nextrev,
 		receivers: make(map[*watcher]struct{}),
 		donec:     make(chan struct{}),
+		bookmarks: w.bookmarkInterval > 0,
 		lg:        lg,
 	}
 	wb.add(w)
@@ -62,6 +70,9 @@ func newWatchBroadcast(lg *zap.Logger, wp *watchProxy, w *watcher, update func(*
 			clientv3.WithPrevKV(),
 			clientv3.WithCreatedNotify(),
 		}
+		if wb.bookmarks {
+			opts = append(opts, clientv3.WithBookmarkInterval(watchBroadcastBookmarkInterval))
+		}
 
 		cctx = withClientAuthToken(cctx, w.wps.stream.Context())
 
@@ -97,6 +108,10 @@ func (wb *watchBroadcast) bcast(wr clientv3.WatchResponse) {
 func (wb *watchBroadcast) add(w *watcher) bool {
 	wb.mu.Lock()
 	defer wb.mu.Unlock()
+	if w.bookmarkInterval > 0 && !wb.bookmarks {
+		// the server watcher sends no bookmarks
+		return false
+	}
 	if wb.nextrev > w.nextrev || (wb.nextrev == 0 && w.nextrev != 0) {
 		// wb is too far ahead, w will miss events
 		// or wb is being established with a current watcher
@@ -64,7 +64,8 @@ func (wbs *watchBroadcasts) coalesce(wb *watchBroadcast) {
 		// 1. check if wbswb is behind wb so it won't skip any events in wb
 		// 2. ensure wbswb started; nextrev == 0 may mean wbswb is waiting
 		// for a current watcher and expects a create event from the server.
-		if wb.nextrev >= wbswb.nextrev && wbswb.responses > 0 {
+		// 3. ensure wbswb sends bookmarks if the watchers of wb may need them.
+		if wb.nextrev >= wbswb.nextrev && wbswb.responses > 0 && (!wb.bookmarks || wbswb.bookmarks) {
 			for w := range wb.receivers {
 				wbswb.receivers[w] = struct{}{}
 				wbs.watchers[w] = wbswb
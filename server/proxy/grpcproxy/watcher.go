@@ -38,6 +38,8 @@ type watcher struct {
 	filters  []mvcc.FilterFunc
 	progress bool
 	prevKV   bool
+	// bookmarkInterval is the interval between the bookmarks of the watcher.
+	bookmarkInterval time.Duration
 
 	// id is the id returned to the client on its watch stream.
 	id int64
@@ -45,6 +47,8 @@ type watcher struct {
 	nextrev int64
 	// lastHeader has the last header sent over the stream.
 	lastHeader pb.ResponseHeader
+	// nextBookmark is the earliest time the next bookmark may be sent.
+	nextBookmark time.Time
 
 	// wps is the parent.
 	wps *watchProxyStream
@@ -53,6 +57,10 @@ type watcher struct {
 // send filters out repeated events by discarding revisions older
 // than the last one sent over the watch channel.
 func (w *watcher) send(wr clientv3.WatchResponse) {
+	if wr.Bookmark {
+		w.sendBookmark(wr)
+		return
+	}
 	if wr.IsProgressNotify() && !w.progress {
 		return
 	}
@@ -116,6 +124,25 @@ func (w *watcher) send(wr clientv3.WatchResponse) {
 	})
 }
 
+// sendBookmark forwards a bookmark of the broadcast if the watcher asked for
+// bookmarks and the next one is due.
+func (w *watcher) sendBookmark(wr clientv3.WatchResponse) {
+	if w.bookmarkInterval == 0 || wr.Header.Revision+1 < w.nextrev {
+		return
+	}
+	now := time.Now()
+	if now.Before(w.nextBookmark) {
+		return
+	}
+	w.nextBookmark = now.Add(w.bookmarkInterval)
+	w.lastHeader = wr.Header
+	w.post(&pb.WatchResponse{
+		Header:   &wr.Header,
+		WatchId:  w.id,
+		Bookmark: true,
+	})
+}
+
 // post puts a watch response on the watcher's proxy stream channel
 func (w *watcher) post(wr *pb.WatchResponse) bool {
 	select {
@@ -15,6 +15,7 @@
 package tcpproxy
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"math/rand"
@@ -38,6 +39,15 @@ func (r *remote) inactivate() {
 	r.inactive = true
 }
 
+// setActive sets whether the remote is active, returning whether it changed.
+func (r *remote) setActive(active bool) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	changed := r.inactive == active
+	r.inactive = !active
+	return changed
+}
+
 func (r *remote) tryReactivate() error {
 	conn, err := net.Dial("tcp", r.addr)
 	if err != nil {
@@ -62,6 +72,14 @@ type TCPProxy struct {
 	Endpoints       []*net.SRV
 	MonitorInterval time.Duration
 
+	// HealthCheckInterval is the interval at which all the endpoints are
+	// health checked. The unhealthy endpoints are not picked for new
+	// connections until they pass a health check. If zero, the endpoints are
+	// only deactivated on dial errors, and retried every MonitorInterval.
+	HealthCheckInterval time.Duration
+	// HealthCheck probes the endpoints, TCPHealthCheck if nil.
+	HealthCheck HealthCheck
+
 	donec chan struct{}
 
 	mu        sync.Mutex // guards the following fields
@@ -76,16 +94,25 @@ func (tp *TCPProxy) Run() error {
 	}
 
 	var eps []string // for logging
+	tp.mu.Lock()
 	for _, srv := range tp.Endpoints {
 		addr := net.JoinHostPort(srv.Target, fmt.Sprintf("%d", srv.Port))
 		tp.remotes = append(tp.remotes, &remote{srv: srv, addr: addr})
 		eps = append(eps, addr)
 	}
+	tp.mu.Unlock()
 	if tp.Logger != nil {
 		tp.Logger.Info("ready to proxy client requests", zap.Strings("endpoints", eps))
 	}
 
-	go tp.runMonitor()
+	if tp.HealthCheckInterval > 0 {
+		if tp.HealthCheck == nil {
+			tp.HealthCheck = TCPHealthCheck
+		}
+		go tp.runHealthCheck()
+	} else {
+		go tp.runMonitor()
+	}
 	for {
 		in, err := tp.Listener.Accept()
 		if err != nil {
@@ -96,6 +123,36 @@ func (tp *TCPProxy) Run() error {
 	}
 }
 
+// SetEndpoints replaces the endpoints new connections are routed to. The
+// endpoints kept keep their active state.
+func (tp *TCPProxy) SetEndpoints(srvs []*net.SRV) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	current := make(map[string]*remote, len(tp.remotes))
+	for _, r := range tp.remotes {
+		current[r.addr] = r
+	}
+	var remotes []*remote
+	var eps []string // for logging
+	changed := len(srvs) != len(tp.remotes)
+	for _, srv := range srvs {
+		addr := net.JoinHostPort(srv.Target, fmt.Sprintf("%d", srv.Port))
+		r, ok := current[addr]
+		if !ok {
+			r = &remote{addr: addr}
+			changed = true
+		}
+		r.srv = srv
+		remotes = append(remotes, r)
+		eps = append(eps, addr)
+	}
+	tp.Endpoints, tp.remotes = srvs, remotes
+	if changed && tp.Logger != nil {
+		tp.Logger.Info("updated endpoints", zap.Strings("endpoints", eps))
+	}
+}
+
 func (tp *TCPProxy) pick() *remote {
 	var weighted []*remote
 	var unweighted []*remote
@@ -221,6 +278,51 @@ func (tp *TCPProxy) runMonitor() {
 	}
 }
 
+func (tp *TCPProxy) runHealthCheck() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-tp.donec
+		cancel()
+	}()
+
+	for {
+		tp.mu.Lock()
+		remotes := tp.remotes
+		tp.mu.Unlock()
+
+		var wg sync.WaitGroup
+		for _, r := range remotes {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				tp.checkHealth(ctx, r)
+			}()
+		}
+		wg.Wait()
+
+		select {
+		case <-time.After(tp.HealthCheckInterval):
+		case <-tp.donec:
+			return
+		}
+	}
+}
+
+func (tp *TCPProxy) checkHealth(ctx context.Context, r *remote) {
+	cctx, cancel := context.WithTimeout(ctx, tp.HealthCheckInterval)
+	err := tp.HealthCheck(cctx, r.addr)
+	cancel()
+	if ctx.Err() != nil || !r.setActive(err == nil) || tp.Logger == nil {
+		return
+	}
+	if err != nil {
+		tp.Logger.Warn("deactivated unhealthy endpoint", zap.String("address", r.addr), zap.Duration("interval", tp.HealthCheckInterval), zap.Error(err))
+	} else {
+		tp.Logger.Info("activated healthy endpoint", zap.String("address", r.addr))
+	}
+}
+
 func (tp *TCPProxy) Stop() {
 	// graceful shutdown?
 	// shutdown current connections?
@@ -21,7 +21,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestUserspaceProxy(t *testing.T) {
@@ -129,3 +131,141 @@ func TestUserspaceProxyPriority(t *testing.T) {
 		t.Errorf("got = %s, want %s", got, want)
 	}
 }
+
+func TestUserspaceProxyHealthCheck(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	var healthy atomic.Bool
+	backends := []struct {
+		Payload  string
+		Priority uint16
+		Healthy  func() bool
+	}{
+		{"hello proxy 1", 1, healthy.Load},
+		{"hello proxy 2", 2, func() bool { return true }},
+	}
+
+	var eps []*net.SRV
+	var front *url.URL
+	for _, b := range backends {
+		backend := b
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/health" {
+				if !backend.Healthy() {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+				return
+			}
+			fmt.Fprint(w, backend.Payload)
+		}))
+		defer ts.Close()
+
+		front, err = url.Parse(ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var port uint16
+		fmt.Sscanf(front.Port(), "%d", &port)
+
+		eps = append(eps, &net.SRV{Target: front.Hostname(), Port: port, Priority: backend.Priority})
+	}
+
+	p := TCPProxy{
+		Listener:            l,
+		Endpoints:           eps,
+		HealthCheckInterval: 10 * time.Millisecond,
+		HealthCheck:         HTTPHealthCheck(nil),
+	}
+	go p.Run()
+	defer p.Stop()
+
+	front.Host = l.Addr().String()
+	waitPayload := func(want string) {
+		t.Helper()
+		var got string
+		for i := 0; i < 100; i++ {
+			tr := &http.Transport{DisableKeepAlives: true}
+			res, err := (&http.Client{Transport: tr}).Get(front.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, gerr := io.ReadAll(res.Body)
+			res.Body.Close()
+			if gerr != nil {
+				t.Fatal(gerr)
+			}
+			if got = string(b); got == want {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("got = %s, want %s", got, want)
+	}
+
+	// the unhealthy endpoint of the best priority is skipped.
+	waitPayload("hello proxy 2")
+	healthy.Store(true)
+	waitPayload("hello proxy 1")
+}
+
+func TestUserspaceProxySetEndpoints(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	var eps []*net.SRV
+	var front *url.URL
+	for _, payload := range []string{"hello proxy 1", "hello proxy 2"} {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, payload)
+		}))
+		defer ts.Close()
+
+		front, err = url.Parse(ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var port uint16
+		fmt.Sscanf(front.Port(), "%d", &port)
+		eps = append(eps, &net.SRV{Target: front.Hostname(), Port: port})
+	}
+
+	p := TCPProxy{
+		Listener:  l,
+		Endpoints: eps[:1],
+	}
+	go p.Run()
+	defer p.Stop()
+
+	front.Host = l.Addr().String()
+	get := func() string {
+		t.Helper()
+		tr := &http.Transport{DisableKeepAlives: true}
+		res, err := (&http.Client{Transport: tr}).Get(front.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, gerr := io.ReadAll(res.Body)
+		res.Body.Close()
+		if gerr != nil {
+			t.Fatal(gerr)
+		}
+		return string(got)
+	}
+
+	if got, want := get(), "hello proxy 1"; got != want {
+		t.Errorf("got = %s, want %s", got, want)
+	}
+	p.SetEndpoints(eps[1:])
+	if got, want := get(), "hello proxy 2"; got != want {
+		t.Errorf("got = %s, want %s", got, want)
+	}
+}
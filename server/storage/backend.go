@@ -15,6 +15,7 @@
 package storage
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
@@ -32,6 +33,7 @@ func newBackend(cfg config.ServerConfig, hooks backend.Hooks) backend.Backend {
 	bcfg := backend.DefaultBackendConfig(cfg.Logger)
 	bcfg.Path = cfg.BackendPath()
 	bcfg.UnsafeNoFsync = cfg.UnsafeNoFsync
+	bcfg.Engine = cfg.StorageEngine
 	if cfg.BackendBatchLimit != 0 {
 		bcfg.BatchLimit = cfg.BackendBatchLimit
 		if cfg.Logger != nil {
@@ -44,6 +46,34 @@ func newBackend(cfg config.ServerConfig, hooks backend.Hooks) backend.Backend {
 			cfg.Logger.Info("setting backend batch interval", zap.Duration("batch interval", cfg.BackendBatchInterval))
 		}
 	}
+	if cfg.BackendBatchAdaptive {
+		bcfg.BatchTuning = &backend.BatchTuning{
+			MinInterval: cfg.BackendBatchIntervalMin,
+			MaxInterval: cfg.BackendBatchIntervalMax,
+			MinLimit:    cfg.BackendBatchLimitMin,
+			MaxLimit:    cfg.BackendBatchLimitMax,
+		}
+		if cfg.Logger != nil {
+			cfg.Logger.Info("adapting backend batch interval and limit",
+				zap.Duration("min-batch-interval", cfg.BackendBatchIntervalMin),
+				zap.Duration("max-batch-interval", cfg.BackendBatchIntervalMax),
+				zap.Int("min-batch-limit", cfg.BackendBatchLimitMin),
+				zap.Int("max-batch-limit", cfg.BackendBatchLimitMax),
+			)
+		}
+	}
+	if cfg.BackendReclaimFreePages {
+		bcfg.FreePageReclaim = &backend.FreePageReclaim{
+			Threshold: cfg.BackendReclaimThreshold,
+			Interval:  cfg.BackendReclaimInterval,
+		}
+		if cfg.Logger != nil {
+			cfg.Logger.Info("reclaiming backend free pages",
+				zap.Float64("reclaim-threshold", cfg.BackendReclaimThreshold),
+				zap.Duration("reclaim-interval", cfg.BackendReclaimInterval),
+			)
+		}
+	}
 	bcfg.BackendFreelistType = cfg.BackendFreelistType
 	bcfg.Logger = cfg.Logger
 	if cfg.QuotaBackendBytes > 0 && cfg.QuotaBackendBytes != DefaultQuotaBytes {
@@ -52,7 +82,18 @@ func newBackend(cfg config.ServerConfig, hooks backend.Hooks) backend.Backend {
 	}
 	bcfg.Mlock = cfg.MemoryMlock
 	bcfg.Hooks = hooks
-	return backend.New(bcfg)
+	bcfg.TracerProvider = cfg.TracerProvider
+	if cfg.EncryptionCodec == nil {
+		return backend.New(bcfg)
+	}
+	bcfg.Codec = cfg.EncryptionCodec
+	be := backend.New(bcfg)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ReqTimeout())
+	defer cancel()
+	if err := cfg.EncryptionCodec.Load(ctx, be); err != nil {
+		cfg.Logger.Fatal("failed to load the data encryption keys of the backend", zap.Error(err))
+	}
+	return be
 }
 
 // OpenSnapshotBackend renames a snapshot db to the current etcd db and opens it.
@@ -15,6 +15,7 @@
 package backend
 
 import (
+	"context"
 	"fmt"
 	"hash/crc32"
 	"io"
@@ -25,6 +26,8 @@ import (
 	"time"
 
 	humanize "github.com/dustin/go-humanize"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	"go.uber.org/zap"
 
 	bolt "go.etcd.io/bbolt"
@@ -36,6 +39,10 @@ var (
 	defaultBatchInterval = 100 * time.Millisecond
 
 	defragLimit = 10000
+	// defragCatchUpRounds is the maximum number of rounds defragmentation
+	// replays the writes made while it copies the database, before pausing
+	// the backend to replay the remaining ones.
+	defragCatchUpRounds = 10
 
 	// InitialMmapSize is the initial size of the mmapped region. Setting this larger than
 	// the potential max db size can prevent writer from blocking reader.
@@ -66,7 +73,16 @@ type Backend interface {
 	SizeInUse() int64
 	// OpenReadTxN returns the number of currently open read transactions in the backend.
 	OpenReadTxN() int64
+	// InflightCommitDuration returns how long the commit currently being
+	// written has been running, or 0 if no commit is in progress.
+	InflightCommitDuration() time.Duration
+	// LinkNextCommit links the sampled span of ctx, if any, to the span of
+	// the next commit, which makes the writes done so far durable.
+	LinkNextCommit(ctx context.Context)
 	Defrag() error
+	// DefragStatus returns the progress of the running defragmentation, or
+	// the result of the last one.
+	DefragStatus() DefragStatus
 	ForceCommit()
 	Close() error
 
@@ -99,18 +115,38 @@ type backend struct {
 	sizeInUse int64
 	// commits counts number of commits since start
 	commits int64
+	// commitStart is the start time in unix nanoseconds of the in-flight
+	// commit, or 0 if no commit is in progress
+	commitStart int64
 	// openReadTxN is the number of currently open read transactions in the backend
 	openReadTxN int64
 	// mlock prevents backend database file to be swapped
 	mlock bool
 
-	mu    sync.RWMutex
-	bopts *bolt.Options
-	db    *bolt.DB
+	mu sync.RWMutex
+	// engineName is the storage engine of the database, opened with bopts
+	// if it is bbolt.
+	engineName string
+	bopts      *bolt.Options
+	noSync     bool
+	engine     engine
+	// defragMu serializes the defragmentations, which copy the database
+	// without holding mu.
+	defragMu sync.Mutex
+	// defragStatus is the status of the running or last defragmentation.
+	// While it runs, its copied keys are counted by defragCopied.
+	defragStatusMu sync.Mutex
+	defragStatus   DefragStatus
+	defragCopied   atomic.Int64
 
 	batchInterval time.Duration
 	batchLimit    int
-	batchTx       *batchTxBuffered
+	// batchTuner adapts the batch interval and limit, if set. The limit is
+	// updated on commit, under the batch tx lock.
+	batchTuner *batchTuner
+	batchTx    *batchTxBuffered
+	// reclaim returns the free pages to the filesystem, if set.
+	reclaim *freePageReclaimer
 
 	readTx *readTx
 	// txReadBufferCache mirrors "txReadBuffer" within "readTx" -- readTx.baseReadTx.buf.
@@ -123,10 +159,17 @@ type backend struct {
 	donec chan struct{}
 
 	hooks Hooks
+	// codec transforms the values written to and read from bolt, if any.
+	codec ValueCodec
 
 	// txPostLockInsideApplyHook is called each time right after locking the tx.
 	txPostLockInsideApplyHook func()
 
+	tracer trace.Tracer
+	// commitLinks are the spans waiting for the next commit.
+	commitLinksMu sync.Mutex
+	commitLinks   []trace.Link
+
 	lg *zap.Logger
 }
 
@@ -137,6 +180,12 @@ type BackendConfig struct {
 	BatchInterval time.Duration
 	// BatchLimit is the maximum puts before flushing the BatchTx.
 	BatchLimit int
+	// BatchTuning, if set, adapts the batch interval and limit within its
+	// bounds, starting from BatchInterval and BatchLimit.
+	BatchTuning *BatchTuning
+	// Engine is the storage engine of a new database, EngineBbolt if empty.
+	// An existing database is opened with the engine it was created with.
+	Engine string
 	// BackendFreelistType is the backend boltdb's freelist type.
 	BackendFreelistType bolt.FreelistType
 	// MmapSize is the number of bytes to mmap for the backend.
@@ -150,6 +199,14 @@ type BackendConfig struct {
 
 	// Hooks are getting executed during lifecycle of Backend's transactions.
 	Hooks Hooks
+	// TracerProvider creates the spans of commits linked to traced writes.
+	TracerProvider trace.TracerProvider
+	// Codec transforms the values of the buckets stored in bolt, e.g. to
+	// encrypt them. The values are stored as is if nil.
+	Codec ValueCodec
+	// FreePageReclaim, if set, returns the free pages of a bbolt database to
+	// the filesystem in the background. It is only supported on linux.
+	FreePageReclaim *FreePageReclaim
 }
 
 type BackendConfigOption func(*BackendConfig)
@@ -173,6 +230,12 @@ func WithMmapSize(size uint64) BackendConfigOption {
 	}
 }
 
+func WithCodec(codec ValueCodec) BackendConfigOption {
+	return func(bcfg *BackendConfig) {
+		bcfg.Codec = codec
+	}
+}
+
 func NewDefaultBackend(lg *zap.Logger, path string, opts ...BackendConfigOption) Backend {
 	bcfg := DefaultBackendConfig(lg)
 	bcfg.Path = path
@@ -192,6 +255,9 @@ func newBackend(bcfg BackendConfig) *backend {
 	if bcfg.Logger == nil {
 		bcfg.Logger = zap.NewNop()
 	}
+	if bcfg.TracerProvider == nil {
+		bcfg.TracerProvider = noop.NewTracerProvider()
+	}
 
 	bopts.InitialMmapSize = bcfg.mmapSize()
 	bopts.FreelistType = bcfg.BackendFreelistType
@@ -200,16 +266,30 @@ func newBackend(bcfg BackendConfig) *backend {
 	bopts.Mlock = bcfg.Mlock
 	bopts.Logger = newBoltLoggerZap(bcfg)
 
-	db, err := bolt.Open(bcfg.Path, 0o600, bopts)
+	engineName, err := detectEngine(bcfg.Path)
 	if err != nil {
-		bcfg.Logger.Panic("failed to open database", zap.String("path", bcfg.Path), zap.Error(err))
+		bcfg.Logger.Panic("failed to detect the storage engine of database", zap.String("path", bcfg.Path), zap.Error(err))
+	}
+	switch {
+	case engineName == "" && bcfg.Engine == "":
+		engineName = EngineBbolt
+	case engineName == "":
+		engineName = bcfg.Engine
+	case bcfg.Engine != "" && bcfg.Engine != engineName:
+		bcfg.Logger.Warn(
+			"opening database with the storage engine it was created with",
+			zap.String("path", bcfg.Path),
+			zap.String("storage-engine", engineName),
+			zap.String("configured-storage-engine", bcfg.Engine),
+		)
 	}
 
 	// In future, may want to make buffering optional for low-concurrency systems
 	// or dynamically swap between buffered/non-buffered depending on workload.
 	b := &backend{
-		bopts: bopts,
-		db:    db,
+		engineName: engineName,
+		bopts:      bopts,
+		noSync:     bcfg.UnsafeNoFsync,
 
 		batchInterval: bcfg.BatchInterval,
 		batchLimit:    bcfg.BatchLimit,
@@ -221,9 +301,10 @@ func newBackend(bcfg BackendConfig) *backend {
 					txBuffer:   txBuffer{make(map[BucketID]*bucketBuffer)},
 					bufVersion: 0,
 				},
-				buckets: make(map[BucketID]*bolt.Bucket),
+				buckets: make(map[BucketID]engineBucket),
 				txWg:    new(sync.WaitGroup),
 				txMu:    new(sync.RWMutex),
+				codec:   bcfg.Codec,
 			},
 		},
 		txReadBufferCache: txReadBufferCache{
@@ -235,14 +316,38 @@ func newBackend(bcfg BackendConfig) *backend {
 		stopc: make(chan struct{}),
 		donec: make(chan struct{}),
 
+		tracer: bcfg.TracerProvider.Tracer("go.etcd.io/etcd/server/v3/storage/backend"),
+
+		codec: bcfg.Codec,
+
 		lg: bcfg.Logger,
 	}
 
+	if b.engine, err = b.openEngine(bcfg.Path, nil); err != nil {
+		bcfg.Logger.Panic("failed to open database", zap.String("path", bcfg.Path), zap.Error(err))
+	}
+
+	if bt := bcfg.BatchTuning; bt != nil {
+		b.batchTuner = newBatchTuner(*bt, bcfg.BatchInterval)
+		b.batchLimit = min(max(bcfg.BatchLimit, bt.MinLimit), bt.MaxLimit)
+	}
+
 	b.batchTx = newBatchTxBuffered(b)
 	// We set it after newBatchTxBuffered to skip the 'empty' commit.
 	b.hooks = bcfg.Hooks
 
 	go b.run()
+	if rc := bcfg.FreePageReclaim; rc != nil {
+		switch {
+		case !reclaimSupported:
+			b.lg.Warn("reclaiming free pages is not supported on this platform")
+		case b.engineName != EngineBbolt:
+			b.lg.Warn("reclaiming free pages is only supported by the bbolt storage engine", zap.String("storage-engine", b.engineName))
+		default:
+			b.reclaim = &freePageReclaimer{FreePageReclaim: *rc, donec: make(chan struct{})}
+			go b.runReclaim()
+		}
+	}
 	return b
 }
 
@@ -298,7 +403,7 @@ func (b *backend) ConcurrentReadTx() ReadTx {
 	case isEmptyCache:
 		// perform safe copy of buffer while holding "b.txReadBufferCache.mu.Lock"
 		// this is only supposed to run once so there won't be much overhead
-		curBuf := b.readTx.buf.unsafeCopy()
+		curBuf := copyReadBuffer(&b.readTx.buf)
 		buf = &curBuf
 	case isStaleCache:
 		// to maximize the concurrency, try unsafe copy of buffer
@@ -306,7 +411,7 @@ func (b *backend) ConcurrentReadTx() ReadTx {
 		// get overwritten by someone else.
 		// therefore, we need to check the readTx buffer version again
 		b.txReadBufferCache.mu.Unlock()
-		curBuf := b.readTx.buf.unsafeCopy()
+		curBuf := copyReadBuffer(&b.readTx.buf)
 		b.txReadBufferCache.mu.Lock()
 		buf = &curBuf
 	default:
@@ -329,18 +434,30 @@ func (b *backend) ConcurrentReadTx() ReadTx {
 
 	b.txReadBufferCache.mu.Unlock()
 
+	concurrentReadTxs.Inc()
 	// concurrentReadTx is not supposed to write to its txReadBuffer
 	return &concurrentReadTx{
+		start: time.Now(),
 		baseReadTx: baseReadTx{
 			buf:     *buf,
 			txMu:    b.readTx.txMu,
 			tx:      b.readTx.tx,
 			buckets: b.readTx.buckets,
 			txWg:    b.readTx.txWg,
+			codec:   b.codec,
 		},
 	}
 }
 
+// copyReadBuffer copies the read buffer shared by the concurrent read
+// transactions, recording the time it takes.
+func copyReadBuffer(buf *txReadBuffer) txReadBuffer {
+	defer func(start time.Time) {
+		readBufferCopySec.Observe(time.Since(start).Seconds())
+	}(time.Now())
+	return buf.unsafeCopy()
+}
+
 // ForceCommit forces the current batching tx to commit.
 func (b *backend) ForceCommit() {
 	b.batchTx.Commit()
@@ -351,7 +468,7 @@ func (b *backend) Snapshot() Snapshot {
 
 	b.mu.RLock()
 	defer b.mu.RUnlock()
-	tx, err := b.db.Begin(false)
+	tx, err := b.engine.Begin(false)
 	if err != nil {
 		b.lg.Fatal("failed to begin tx", zap.Error(err))
 	}
@@ -395,23 +512,20 @@ func (b *backend) Hash(ignores func(bucketName, keyName []byte) bool) (uint32, e
 
 	b.mu.RLock()
 	defer b.mu.RUnlock()
-	err := b.db.View(func(tx *bolt.Tx) error {
-		c := tx.Cursor()
-		for next, _ := c.First(); next != nil; next, _ = c.Next() {
-			b := tx.Bucket(next)
-			if b == nil {
-				return fmt.Errorf("cannot get hash of bucket %s", next)
+	tx, err := b.engine.Begin(false)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	err = tx.ForEach(func(next []byte, b engineBucket) error {
+		h.Write(next)
+		return b.ForEach(func(k, v []byte) error {
+			if ignores != nil && !ignores(next, k) {
+				h.Write(k)
+				h.Write(v)
 			}
-			h.Write(next)
-			b.ForEach(func(k, v []byte) error {
-				if ignores != nil && !ignores(next, k) {
-					h.Write(k)
-					h.Write(v)
-				}
-				return nil
-			})
-		}
-		return nil
+			return nil
+		})
 	})
 	if err != nil {
 		return 0, err
@@ -430,7 +544,7 @@ func (b *backend) SizeInUse() int64 {
 
 func (b *backend) run() {
 	defer close(b.donec)
-	t := time.NewTimer(b.batchInterval)
+	t := time.NewTimer(b.nextBatchInterval())
 	defer t.Stop()
 	for {
 		select {
@@ -442,16 +556,30 @@ func (b *backend) run() {
 		if b.batchTx.safePending() != 0 {
 			b.batchTx.Commit()
 		}
-		t.Reset(b.batchInterval)
+		t.Reset(b.nextBatchInterval())
 	}
 }
 
+// nextBatchInterval returns the time until the next periodic commit.
+func (b *backend) nextBatchInterval() time.Duration {
+	if b.batchTuner != nil {
+		return b.batchTuner.batchInterval()
+	}
+	return b.batchInterval
+}
+
 func (b *backend) Close() error {
+	// wait for the defragmentation copying the database, if any
+	b.defragMu.Lock()
+	defer b.defragMu.Unlock()
 	close(b.stopc)
+	if b.reclaim != nil {
+		<-b.reclaim.donec
+	}
 	<-b.donec
 	b.mu.Lock()
 	defer b.mu.Unlock()
-	return b.db.Close()
+	return b.engine.Close()
 }
 
 // Commits returns total number of commits since start
@@ -459,51 +587,126 @@ func (b *backend) Commits() int64 {
 	return atomic.LoadInt64(&b.commits)
 }
 
+func (b *backend) InflightCommitDuration() time.Duration {
+	start := atomic.LoadInt64(&b.commitStart)
+	if start == 0 {
+		return 0
+	}
+	return time.Since(time.Unix(0, start))
+}
+
+func (b *backend) LinkNextCommit(ctx context.Context) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsSampled() {
+		return
+	}
+	b.commitLinksMu.Lock()
+	b.commitLinks = append(b.commitLinks, trace.Link{SpanContext: sc})
+	b.commitLinksMu.Unlock()
+}
+
+// startCommitSpan starts the span of a commit if traced writes are waiting
+// for it, otherwise it returns nil. The span is a child of the first traced
+// write, so that it is sampled along with it, and links to all of them.
+func (b *backend) startCommitSpan() trace.Span {
+	b.commitLinksMu.Lock()
+	links := b.commitLinks
+	b.commitLinks = nil
+	b.commitLinksMu.Unlock()
+	if len(links) == 0 {
+		return nil
+	}
+	ctx := trace.ContextWithRemoteSpanContext(context.Background(), links[0].SpanContext)
+	_, span := b.tracer.Start(ctx, "backend commit", trace.WithLinks(links...))
+	return span
+}
+
+// DefragStatus is the progress of a defragmentation of the backend.
+type DefragStatus struct {
+	// Active is set while the defragmentation is running.
+	Active bool
+	// CopiedKeys is the number of keys copied to the new file, out of the
+	// TotalKeys of the database when the copy started.
+	CopiedKeys int64
+	TotalKeys  int64
+	Start      time.Time
+	// Finish is zero while the defragmentation is running.
+	Finish time.Time
+	// Err is the error the defragmentation failed with, if any.
+	Err error
+}
+
+// PercentComplete returns the percentage of the keys copied to the new file.
+func (s DefragStatus) PercentComplete() int {
+	switch {
+	case !s.Active && !s.Finish.IsZero() && s.Err == nil:
+		return 100
+	case s.TotalKeys == 0:
+		return 0
+	}
+	p := int(s.CopiedKeys * 100 / s.TotalKeys)
+	// the keys put meanwhile are copied by the catch-up rounds
+	return min(p, 99)
+}
+
+// Defrag rewrites the database to a new file to reclaim its free pages. The
+// file is written while the backend serves reads and writes; the backend only
+// pauses to replay the last writes and to swap the files.
 func (b *backend) Defrag() error {
-	return b.defrag()
+	b.defragMu.Lock()
+	defer b.defragMu.Unlock()
+
+	b.defragCopied.Store(0)
+	b.setDefragStatus(DefragStatus{Active: true, Start: time.Now()})
+	err := b.defrag()
+	b.defragStatusMu.Lock()
+	b.defragStatus.Active = false
+	b.defragStatus.CopiedKeys = b.defragCopied.Load()
+	b.defragStatus.Finish = time.Now()
+	b.defragStatus.Err = err
+	b.defragStatusMu.Unlock()
+	return err
+}
+
+func (b *backend) DefragStatus() DefragStatus {
+	b.defragStatusMu.Lock()
+	defer b.defragStatusMu.Unlock()
+	s := b.defragStatus
+	if s.Active {
+		s.CopiedKeys = b.defragCopied.Load()
+	}
+	return s
+}
+
+func (b *backend) setDefragStatus(s DefragStatus) {
+	b.defragStatusMu.Lock()
+	b.defragStatus = s
+	b.defragStatusMu.Unlock()
+}
+
+func (b *backend) setDefragTotalKeys(n int64) {
+	b.defragStatusMu.Lock()
+	b.defragStatus.TotalKeys = n
+	b.defragStatusMu.Unlock()
 }
 
 func (b *backend) defrag() error {
 	verify.Assert(b.lg != nil, "the logger should not be nil")
+
 	now := time.Now()
 	isDefragActive.Set(1)
 	defer isDefragActive.Set(0)
 
-	// TODO: make this non-blocking?
-	// lock batchTx to ensure nobody is using previous tx, and then
-	// close previous ongoing tx.
-	b.batchTx.LockOutsideApply()
-	defer b.batchTx.Unlock()
-
-	// lock database after lock tx to avoid deadlock.
-	b.mu.Lock()
-	defer b.mu.Unlock()
-
-	// block concurrent read requests while resetting tx
-	b.readTx.Lock()
-	defer b.readTx.Unlock()
-
 	// Create a temporary file to ensure we start with a clean slate.
 	// Snapshotter.cleanupSnapdir cleans up any of these that are found during startup.
-	dir := filepath.Dir(b.db.Path())
+	dir := filepath.Dir(b.engine.Path())
 	temp, err := os.CreateTemp(dir, "db.tmp.*")
 	if err != nil {
 		return err
 	}
 
-	options := bolt.Options{}
-	if boltOpenOptions != nil {
-		options = *boltOpenOptions
-	}
-	options.OpenFile = func(_ string, _ int, _ os.FileMode) (file *os.File, err error) {
-		// gofail: var defragOpenFileError string
-		// return nil, fmt.Errorf(defragOpenFileError)
-		return temp, nil
-	}
-	// Don't load tmp db into memory regardless of opening options
-	options.Mlock = false
 	tdbp := temp.Name()
-	tmpdb, err := bolt.Open(tdbp, 0o600, &options)
+	tmpdb, err := b.openEngine(tdbp, temp)
 	if err != nil {
 		temp.Close()
 		if rmErr := os.Remove(temp.Name()); rmErr != nil {
@@ -516,8 +719,14 @@ func (b *backend) defrag() error {
 
 		return err
 	}
+	removeTmpdb := func() {
+		tmpdb.Close()
+		if rmErr := os.RemoveAll(tmpdb.Path()); rmErr != nil {
+			b.lg.Error("failed to remove db.tmp after defragmentation completed", zap.Error(rmErr))
+		}
+	}
 
-	dbp := b.db.Path()
+	dbp := b.engine.Path()
 	size1, sizeInUse1 := b.Size(), b.SizeInUse()
 	b.lg.Info(
 		"defragmenting",
@@ -528,6 +737,56 @@ func (b *backend) defrag() error {
 		zap.String("current-db-size-in-use", humanize.Bytes(uint64(sizeInUse1))),
 	)
 
+	// The database is copied from a read tx while the backend keeps serving
+	// reads and writes. The writes committed after the read tx began are
+	// recorded by the batch tx and replayed on the copy, in rounds while
+	// the backend is serving, then for the last ones while it is paused.
+	b.batchTx.LockOutsideApply()
+	b.batchTx.commit(false)
+	tx := b.begin(false)
+	b.batchTx.journal.enabled = true
+	b.batchTx.Unlock()
+	b.setDefragTotalKeys(countKeys(tx))
+
+	// gofail: var defragBeforeCopy struct{}
+	err = defragdb(tx, tmpdb, defragLimit, b.codec, &b.defragCopied)
+	if rerr := tx.Rollback(); rerr != nil {
+		b.lg.Fatal("failed to rollback tx", zap.Error(rerr))
+	}
+	rounds := 0
+	for err == nil && rounds < defragCatchUpRounds {
+		b.batchTx.LockOutsideApply()
+		ops := b.batchTx.journal.drain()
+		b.batchTx.Unlock()
+		err = replayDefragOps(tmpdb, ops, defragLimit, b.codec)
+		rounds++
+		// the writes made meanwhile are few enough to be replayed paused
+		if len(ops) <= defragLimit {
+			break
+		}
+	}
+	if err != nil {
+		b.batchTx.LockOutsideApply()
+		b.batchTx.journal.stop()
+		b.batchTx.Unlock()
+		removeTmpdb()
+		return err
+	}
+
+	// lock batchTx to ensure nobody is using previous tx, and then
+	// close previous ongoing tx.
+	b.batchTx.LockOutsideApply()
+	defer b.batchTx.Unlock()
+
+	// lock database after lock tx to avoid deadlock.
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// block concurrent read requests while resetting tx
+	b.readTx.Lock()
+	defer b.readTx.Unlock()
+
+	pauseStart := time.Now()
 	defer func() {
 		// NOTE: We should exit as soon as possible because that tx
 		// might be closed. The inflight request might use invalid
@@ -542,22 +801,20 @@ func (b *backend) defrag() error {
 	b.batchTx.unsafeCommit(true)
 	b.batchTx.tx = nil
 
-	// gofail: var defragBeforeCopy struct{}
-	err = defragdb(b.db, tmpdb, defragLimit)
+	ops := b.batchTx.journal.drain()
+	b.batchTx.journal.stop()
+	err = replayDefragOps(tmpdb, ops, defragLimit, b.codec)
 	if err != nil {
-		tmpdb.Close()
-		if rmErr := os.RemoveAll(tmpdb.Path()); rmErr != nil {
-			b.lg.Error("failed to remove db.tmp after defragmentation completed", zap.Error(rmErr))
-		}
+		removeTmpdb()
 
-		// restore the bbolt transactions if defragmentation fails
+		// restore the engine transactions if defragmentation fails
 		b.batchTx.tx = b.unsafeBegin(true)
 		b.readTx.tx = b.unsafeBegin(false)
 
 		return err
 	}
 
-	err = b.db.Close()
+	err = b.engine.Close()
 	if err != nil {
 		b.lg.Fatal("failed to close database", zap.Error(err))
 	}
@@ -571,7 +828,7 @@ func (b *backend) defrag() error {
 		b.lg.Fatal("failed to rename tmp database", zap.Error(err))
 	}
 
-	b.db, err = bolt.Open(dbp, 0o600, b.bopts)
+	b.engine, err = b.openEngine(dbp, nil)
 	if err != nil {
 		b.lg.Fatal("failed to open database", zap.String("path", dbp), zap.Error(err))
 	}
@@ -580,11 +837,12 @@ func (b *backend) defrag() error {
 	b.readTx.reset()
 	b.readTx.tx = b.unsafeBegin(false)
 
-	size := b.readTx.tx.Size()
-	db := b.readTx.tx.DB()
+	size, sizeInUse := b.readTx.tx.Usage()
 	atomic.StoreInt64(&b.size, size)
-	atomic.StoreInt64(&b.sizeInUse, size-(int64(db.Stats().FreePageN)*int64(db.Info().PageSize)))
+	atomic.StoreInt64(&b.sizeInUse, sizeInUse)
 
+	pause := time.Since(pauseStart)
+	defragPauseSec.Observe(pause.Seconds())
 	took := time.Since(now)
 	defragSec.Observe(took.Seconds())
 
@@ -598,12 +856,28 @@ func (b *backend) defrag() error {
 		zap.Int64("current-db-size-in-use-bytes-diff", sizeInUse2-sizeInUse1),
 		zap.Int64("current-db-size-in-use-bytes", sizeInUse2),
 		zap.String("current-db-size-in-use", humanize.Bytes(uint64(sizeInUse2))),
+		zap.Int("catch-up-rounds", rounds),
+		zap.Int("paused-replayed-writes", len(ops)),
+		zap.Duration("pause", pause),
 		zap.Duration("took", took),
 	)
 	return nil
 }
 
-func defragdb(odb, tmpdb *bolt.DB, limit int) error {
+// countKeys returns the number of keys of the buckets read by tx.
+func countKeys(tx engineTx) int64 {
+	var n int64
+	tx.ForEach(func(_ []byte, b engineBucket) error {
+		n += int64(b.KeyN())
+		return nil
+	})
+	return n
+}
+
+// defragdb copies the buckets read by tx to tmpdb, adding the number of
+// copied keys to copied. The values are re-encoded with codec, if any, so
+// that defragmentation also migrates them to its current encoding.
+func defragdb(tx engineTx, tmpdb engine, limit int, codec ValueCodec, copied *atomic.Int64) error {
 	// gofail: var defragdbFail string
 	// return fmt.Errorf(defragdbFail)
 
@@ -618,29 +892,15 @@ func defragdb(odb, tmpdb *bolt.DB, limit int) error {
 		}
 	}()
 
-	// open a tx on old db for read
-	tx, err := odb.Begin(false)
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
-	c := tx.Cursor()
-
 	count := 0
-	for next, _ := c.First(); next != nil; next, _ = c.Next() {
-		b := tx.Bucket(next)
-		if b == nil {
-			return fmt.Errorf("backend: cannot defrag bucket %s", next)
-		}
-
+	err = tx.ForEach(func(next []byte, b engineBucket) error {
 		tmpb, berr := tmptx.CreateBucketIfNotExists(next)
 		if berr != nil {
 			return berr
 		}
-		tmpb.FillPercent = 0.9 // for bucket2seq write in for each
+		tmpb.SetFillPercent(0.9) // for bucket2seq write in for each
 
-		if err = b.ForEach(func(k, v []byte) error {
+		return b.ForEach(func(k, v []byte) error {
 			count++
 			if count > limit {
 				err = tmptx.Commit()
@@ -652,37 +912,46 @@ func defragdb(odb, tmpdb *bolt.DB, limit int) error {
 					return err
 				}
 				tmpb = tmptx.Bucket(next)
-				tmpb.FillPercent = 0.9 // for bucket2seq write in for each
+				tmpb.SetFillPercent(0.9) // for bucket2seq write in for each
 
 				count = 0
 			}
+			if codec != nil {
+				if v, err = codec.Decode(next, k, v); err != nil {
+					return err
+				}
+				if v, err = codec.Encode(next, k, v); err != nil {
+					return err
+				}
+			}
+			copied.Add(1)
 			return tmpb.Put(k, v)
-		}); err != nil {
-			return err
-		}
+		})
+	})
+	if err != nil {
+		return err
 	}
 
 	return tmptx.Commit()
 }
 
-func (b *backend) begin(write bool) *bolt.Tx {
+func (b *backend) begin(write bool) engineTx {
 	b.mu.RLock()
 	tx := b.unsafeBegin(write)
+	e := b.engine
 	b.mu.RUnlock()
 
-	size := tx.Size()
-	db := tx.DB()
-	stats := db.Stats()
+	size, sizeInUse := tx.Usage()
 	atomic.StoreInt64(&b.size, size)
-	atomic.StoreInt64(&b.sizeInUse, size-(int64(stats.FreePageN)*int64(db.Info().PageSize)))
-	atomic.StoreInt64(&b.openReadTxN, int64(stats.OpenTxN))
+	atomic.StoreInt64(&b.sizeInUse, sizeInUse)
+	atomic.StoreInt64(&b.openReadTxN, int64(e.OpenReadTxN()))
 
 	return tx
 }
 
-func (b *backend) unsafeBegin(write bool) *bolt.Tx {
+func (b *backend) unsafeBegin(write bool) engineTx {
 	// gofail: var beforeStartDBTxn struct{}
-	tx, err := b.db.Begin(write)
+	tx, err := b.engine.Begin(write)
 	// gofail: var afterStartDBTxn struct{}
 	if err != nil {
 		b.lg.Fatal("failed to begin tx", zap.Error(err))
@@ -694,8 +963,34 @@ func (b *backend) OpenReadTxN() int64 {
 	return atomic.LoadInt64(&b.openReadTxN)
 }
 
+// openEngine opens the database at path with the storage engine of the
+// backend. The database is opened on temp if set, as a temporary database.
+func (b *backend) openEngine(path string, temp *os.File) (engine, error) {
+	switch b.engineName {
+	case EngineBbolt:
+		if temp == nil {
+			return openBoltEngine(path, b.bopts)
+		}
+		options := bolt.Options{}
+		if boltOpenOptions != nil {
+			options = *boltOpenOptions
+		}
+		options.OpenFile = func(_ string, _ int, _ os.FileMode) (file *os.File, err error) {
+			// gofail: var defragOpenFileError string
+			// return nil, fmt.Errorf(defragOpenFileError)
+			return temp, nil
+		}
+		// Don't load tmp db into memory regardless of opening options
+		options.Mlock = false
+		return openBoltEngine(path, &options)
+	case EngineLog:
+		return openLogEngine(path, temp, b.noSync)
+	}
+	return nil, fmt.Errorf("backend: unknown storage engine %q", b.engineName)
+}
+
 type snapshot struct {
-	*bolt.Tx
+	engineTx
 	stopc chan struct{}
 	donec chan struct{}
 }
@@ -703,7 +998,7 @@ type snapshot struct {
 func (s *snapshot) Close() error {
 	close(s.stopc)
 	<-s.donec
-	return s.Tx.Rollback()
+	return s.engineTx.Rollback()
 }
 
 func newBoltLoggerZap(bcfg BackendConfig) bolt.Logger {
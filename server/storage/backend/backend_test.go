@@ -15,14 +15,18 @@
 package backend_test
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"go.uber.org/zap/zaptest"
 
 	bolt "go.etcd.io/bbolt"
@@ -124,6 +128,32 @@ func TestBackendBatchIntervalCommit(t *testing.T) {
 	}))
 }
 
+func TestBackendBatchTuning(t *testing.T) {
+	bcfg := backend.DefaultBackendConfig(zaptest.NewLogger(t))
+	bcfg.Path = filepath.Join(t.TempDir(), "db")
+	bcfg.BatchTuning = &backend.BatchTuning{
+		MinInterval: 10 * time.Millisecond,
+		MaxInterval: 50 * time.Millisecond,
+		MinLimit:    1,
+		MaxLimit:    1000,
+	}
+	b := backend.New(bcfg)
+	defer betesting.Close(t, b)
+	// the configured interval and limit are bounded.
+	assert.Equal(t, 50*time.Millisecond, backend.BatchIntervalForTest(b))
+	assert.Equal(t, 1000, backend.BatchLimitForTest(b))
+
+	tx := b.BatchTx()
+	tx.Lock()
+	tx.UnsafeCreateBucket(schema.Test)
+	tx.UnsafePut(schema.Test, []byte("foo"), []byte("bar"))
+	tx.Unlock()
+	b.ForceCommit()
+	// a fast commit of few writes shortens the interval and lowers the limit.
+	assert.Less(t, backend.BatchIntervalForTest(b), 50*time.Millisecond)
+	assert.Less(t, backend.BatchLimitForTest(b), 1000)
+}
+
 func TestBackendDefrag(t *testing.T) {
 	bcfg := backend.DefaultBackendConfig(zaptest.NewLogger(t))
 	// Make sure we change BackendFreelistType
@@ -168,6 +198,12 @@ func TestBackendDefrag(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	st := b.DefragStatus()
+	assert.False(t, st.Active)
+	assert.False(t, st.Finish.IsZero())
+	assert.Equal(t, st.TotalKeys, st.CopiedKeys)
+	assert.Positive(t, st.TotalKeys)
+	assert.Equal(t, 100, st.PercentComplete())
 
 	nh, err := b.Hash(nil)
 	if err != nil {
@@ -195,6 +231,107 @@ func TestBackendDefrag(t *testing.T) {
 	b.ForceCommit()
 }
 
+// TestBackendLogEngine ensures the backend keeps its buckets with the log
+// storage engine, which defragmentation compacts, and that the database is
+// reopened with the engine it was created with.
+func TestBackendLogEngine(t *testing.T) {
+	bcfg := backend.DefaultBackendConfig(zaptest.NewLogger(t))
+	bcfg.Path = filepath.Join(t.TempDir(), "db")
+	bcfg.Engine = backend.EngineLog
+	b := backend.New(bcfg)
+
+	tx := b.BatchTx()
+	tx.Lock()
+	tx.UnsafeCreateBucket(schema.Test)
+	tx.Unlock()
+	for i := 0; i < 10; i++ {
+		tx.Lock()
+		for j := 0; j < 100; j++ {
+			tx.UnsafePut(schema.Test, []byte(fmt.Sprintf("foo_%d", j)), []byte(fmt.Sprintf("bar_%d", i)))
+		}
+		tx.Unlock()
+		b.ForceCommit()
+	}
+	// the overwritten values are not in use, until the log is compacted.
+	assert.Greater(t, b.Size(), 2*b.SizeInUse())
+	oh, err := b.Hash(nil)
+	require.NoError(t, err)
+	require.NoError(t, b.Defrag())
+	assert.Equal(t, b.Size(), b.SizeInUse())
+	nh, err := b.Hash(nil)
+	require.NoError(t, err)
+	assert.Equal(t, oh, nh)
+	betesting.Close(t, b)
+
+	bcfg.Engine = backend.EngineBbolt
+	b = backend.New(bcfg)
+	defer betesting.Close(t, b)
+	rtx := b.ReadTx()
+	rtx.RLock()
+	_, vals := rtx.UnsafeRange(schema.Test, []byte("foo_42"), nil, 0)
+	rtx.RUnlock()
+	assert.Equal(t, [][]byte{[]byte("bar_9")}, vals)
+	nh, err = b.Hash(nil)
+	require.NoError(t, err)
+	assert.Equal(t, oh, nh)
+}
+
+// TestBackendDefragConcurrentWrites ensures the writes made while the backend
+// is defragmented are kept.
+func TestBackendDefragConcurrentWrites(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b)
+
+	want := make(map[string]string)
+	tx := b.BatchTx()
+	tx.Lock()
+	tx.UnsafeCreateBucket(schema.Test)
+	for i := 0; i < 5*backend.DefragLimitForTest(); i++ {
+		k, v := fmt.Sprintf("foo_%d", i), fmt.Sprintf("bar_%d", i)
+		tx.UnsafePut(schema.Test, []byte(k), []byte(v))
+		want[k] = v
+	}
+	tx.Unlock()
+	b.ForceCommit()
+
+	donec := make(chan struct{})
+	writtenc := make(chan int)
+	go func() {
+		i := 0
+		for ; ; i++ {
+			select {
+			case <-donec:
+				writtenc <- i
+				return
+			default:
+			}
+			tx.Lock()
+			tx.UnsafePut(schema.Test, []byte(fmt.Sprintf("foo_%d", i)), []byte(fmt.Sprintf("baz_%d", i)))
+			tx.UnsafeDelete(schema.Test, []byte(fmt.Sprintf("foo_%d", 5*backend.DefragLimitForTest()-1-i)))
+			tx.Unlock()
+		}
+	}()
+	err := b.Defrag()
+	close(donec)
+	written := <-writtenc
+	require.NoError(t, err)
+
+	for i := 0; i < written; i++ {
+		want[fmt.Sprintf("foo_%d", i)] = fmt.Sprintf("baz_%d", i)
+		delete(want, fmt.Sprintf("foo_%d", 5*backend.DefragLimitForTest()-1-i))
+	}
+	got := make(map[string]string)
+	rtx := b.ConcurrentReadTx()
+	rtx.RLock()
+	err = rtx.UnsafeForEach(schema.Test, func(k, v []byte) error {
+		got[string(k)] = string(v)
+		return nil
+	})
+	rtx.RUnlock()
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
 // TestBackendWriteback ensures writes are stored to the read txn on write txn unlock.
 func TestBackendWriteback(t *testing.T) {
 	b, _ := betesting.NewDefaultTmpBackend(t)
@@ -349,3 +486,108 @@ func TestBackendWritebackForEach(t *testing.T) {
 		t.Fatalf("expected %q, got %q", seq, partialSeq)
 	}
 }
+
+func TestBackendCommitSpanLinksTracedWrites(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	bcfg := backend.DefaultBackendConfig(zaptest.NewLogger(t))
+	bcfg.BatchInterval = time.Hour
+	bcfg.TracerProvider = tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(recorder))
+	b, _ := betesting.NewTmpBackendFromCfg(t, bcfg)
+	defer betesting.Close(t, b)
+
+	commitSpans := func() (spans []tracesdk.ReadOnlySpan) {
+		for _, s := range recorder.Ended() {
+			if s.Name() == "backend commit" {
+				spans = append(spans, s)
+			}
+		}
+		return spans
+	}
+
+	ctx, span := bcfg.TracerProvider.Tracer("test").Start(context.Background(), "write")
+	span.End()
+
+	tx := b.BatchTx()
+	tx.Lock()
+	tx.UnsafeCreateBucket(schema.Test)
+	tx.UnsafePut(schema.Test, []byte("foo"), []byte("bar"))
+	tx.Unlock()
+	b.LinkNextCommit(ctx)
+	b.LinkNextCommit(context.Background())
+	b.ForceCommit()
+
+	spans := commitSpans()
+	require.Len(t, spans, 1)
+	require.Len(t, spans[0].Links(), 1)
+	assert.Equal(t, span.SpanContext(), spans[0].Links()[0].SpanContext)
+	assert.Equal(t, span.SpanContext().SpanID(), spans[0].Parent().SpanID())
+
+	// the links are consumed by the commit they were waiting for
+	b.ForceCommit()
+	assert.Len(t, commitSpans(), 1)
+}
+
+// versionCodec prefixes the values with the version of the codec.
+type versionCodec struct {
+	version byte
+}
+
+func (c *versionCodec) Encode(_, _, value []byte) ([]byte, error) {
+	return append([]byte{c.version}, value...), nil
+}
+
+func (c *versionCodec) Decode(_, _, stored []byte) ([]byte, error) {
+	if len(stored) == 0 || stored[0] > c.version {
+		return nil, fmt.Errorf("unknown version of %q", stored)
+	}
+	return stored[1:], nil
+}
+
+func TestBackendCodec(t *testing.T) {
+	codec := &versionCodec{version: 1}
+	bcfg := backend.DefaultBackendConfig(zaptest.NewLogger(t))
+	bcfg.Codec = codec
+	b, _ := betesting.NewTmpBackendFromCfg(t, bcfg)
+	defer betesting.Close(t, b)
+
+	tx := b.BatchTx()
+	tx.Lock()
+	tx.UnsafeCreateBucket(schema.Test)
+	tx.UnsafePut(schema.Test, []byte("foo"), []byte("bar"))
+	tx.Unlock()
+	b.ForceCommit()
+
+	stored := func() string {
+		var v []byte
+		require.NoError(t, backend.DbFromBackendForTest(b).View(func(tx *bolt.Tx) error {
+			v = tx.Bucket(schema.Test.Name()).Get([]byte("foo"))
+			return nil
+		}))
+		return string(v)
+	}
+	read := func() {
+		rtx := b.ConcurrentReadTx()
+		rtx.RLock()
+		defer rtx.RUnlock()
+		_, vals := rtx.UnsafeRange(schema.Test, []byte("foo"), nil, 0)
+		assert.Equal(t, [][]byte{[]byte("bar")}, vals)
+		require.NoError(t, rtx.UnsafeForEach(schema.Test, func(k, v []byte) error {
+			assert.Equal(t, "bar", string(v))
+			return nil
+		}))
+
+		tx := b.BatchTx()
+		tx.Lock()
+		defer tx.Unlock()
+		_, vals = tx.UnsafeRange(schema.Test, []byte("foo"), nil, 0)
+		assert.Equal(t, [][]byte{[]byte("bar")}, vals)
+	}
+	assert.Equal(t, "\x01bar", stored())
+	read()
+
+	// defrag re-encodes the values with the current version.
+	codec.version = 2
+	require.NoError(t, b.Defrag())
+	assert.Equal(t, "\x02bar", stored())
+	read()
+}
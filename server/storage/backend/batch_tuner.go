@@ -0,0 +1,96 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// batchIntervalLatencyFactor is the batch interval the tuner aims at,
+	// in commit latencies: the periodic commits keep the disk busy a tenth
+	// of the time. With the latency of a spinning disk, about 10ms, it is
+	// the default batch interval.
+	batchIntervalLatencyFactor = 10
+	// batchLimitIntervalFactor is the batch limit the tuner aims at, in
+	// writes per batch interval: the limit only commits the bursts of
+	// writes, and the batch interval commits the steady ones.
+	batchLimitIntervalFactor = 2
+	// batchTunerWeight is the weight of the last commit in the moving
+	// averages of the commit latency and the write rate.
+	batchTunerWeight = 0.2
+)
+
+// BatchTuning is the bounds within which the batch interval and the batch
+// limit adapt to the observed commit latency and write rate.
+type BatchTuning struct {
+	MinInterval, MaxInterval time.Duration
+	MinLimit, MaxLimit       int
+}
+
+// batchTuner adapts the batch interval and limit after each commit. The
+// interval follows the commit latency, mostly the fsync latency of the disk:
+// slow disks commit less often, to keep up with the writes, and fast disks
+// more often, to keep the batches small. The limit follows the write rate, so
+// that a batch is mostly committed by the interval, and by the limit only on
+// bursts of writes.
+type batchTuner struct {
+	BatchTuning
+
+	// latency is the moving average of the commit latency in seconds, and
+	// rate the one of the writes per second. They are updated under the
+	// batch tx lock.
+	latency, rate float64
+	lastCommit    time.Time
+
+	interval atomic.Int64
+}
+
+func newBatchTuner(bt BatchTuning, interval time.Duration) *batchTuner {
+	t := &batchTuner{BatchTuning: bt, lastCommit: time.Now()}
+	t.interval.Store(int64(min(max(interval, bt.MinInterval), bt.MaxInterval)))
+	return t
+}
+
+// batchInterval returns the current batch interval.
+func (t *batchTuner) batchInterval() time.Duration {
+	return time.Duration(t.interval.Load())
+}
+
+// observe adapts the batch interval to the latency of a commit of pending
+// writes, and returns the batch limit.
+func (t *batchTuner) observe(pending int, latency time.Duration, now time.Time) int {
+	elapsed := now.Sub(t.lastCommit).Seconds()
+	t.lastCommit = now
+	rate := 0.0
+	if elapsed > 0 {
+		rate = float64(pending) / elapsed
+	}
+	if t.latency == 0 {
+		t.latency, t.rate = latency.Seconds(), rate
+	} else {
+		t.latency += batchTunerWeight * (latency.Seconds() - t.latency)
+		t.rate += batchTunerWeight * (rate - t.rate)
+	}
+
+	interval := min(max(time.Duration(batchIntervalLatencyFactor*t.latency*float64(time.Second)), t.MinInterval), t.MaxInterval)
+	t.interval.Store(int64(interval))
+	limit := min(max(int(batchLimitIntervalFactor*t.rate*interval.Seconds()), t.MinLimit), t.MaxLimit)
+
+	batchIntervalSec.Set(interval.Seconds())
+	batchLimitGauge.Set(float64(limit))
+	return limit
+}
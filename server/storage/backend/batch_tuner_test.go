@@ -0,0 +1,64 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchTuner(t *testing.T) {
+	bt := BatchTuning{
+		MinInterval: 10 * time.Millisecond,
+		MaxInterval: time.Second,
+		MinLimit:    100,
+		MaxLimit:    10000,
+	}
+	tuner := newBatchTuner(bt, 5*time.Second)
+	assert.Equal(t, time.Second, tuner.batchInterval())
+
+	// commits of few writes on a fast disk.
+	now := tuner.lastCommit
+	for i := 0; i < 20; i++ {
+		now = now.Add(10 * time.Millisecond)
+		assert.Equal(t, 100, tuner.observe(10, 100*time.Microsecond, now))
+	}
+	assert.Equal(t, 10*time.Millisecond, tuner.batchInterval())
+
+	// the disk gets slower: the interval follows the commit latency.
+	for i := 0; i < 30; i++ {
+		now = now.Add(tuner.batchInterval())
+		tuner.observe(10, 20*time.Millisecond, now)
+	}
+	assert.InDelta(t, 200*time.Millisecond, tuner.batchInterval(), float64(5*time.Millisecond))
+
+	// the writes increase: the limit follows the writes per interval.
+	var limit int
+	for i := 0; i < 30; i++ {
+		now = now.Add(tuner.batchInterval())
+		limit = tuner.observe(int(tuner.batchInterval()/time.Millisecond)*20, 20*time.Millisecond, now)
+	}
+	assert.InDelta(t, 8000, limit, 200)
+
+	// and are capped by the bounds.
+	for i := 0; i < 30; i++ {
+		now = now.Add(tuner.batchInterval())
+		limit = tuner.observe(100000, 10*time.Second, now)
+	}
+	assert.Equal(t, time.Second, tuner.batchInterval())
+	assert.Equal(t, 10000, limit)
+}
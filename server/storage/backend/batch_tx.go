@@ -16,16 +16,12 @@ package backend
 
 import (
 	"bytes"
-	"errors"
 	"math"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
-
-	bolt "go.etcd.io/bbolt"
-	bolterrors "go.etcd.io/bbolt/errors"
 )
 
 type BucketID int
@@ -72,10 +68,15 @@ type UnsafeWriter interface {
 
 type batchTx struct {
 	sync.Mutex
-	tx      *bolt.Tx
+	tx      engineTx
 	backend *backend
 
 	pending int
+	// freedPages is set once the tx frees pages before its commit, which it
+	// only does when it deletes a bucket.
+	freedPages bool
+	// journal records the writes while the backend is being defragmented.
+	journal defragJournal
 }
 
 // Lock is supposed to be called only by the unit test.
@@ -121,18 +122,20 @@ func (t *batchTx) UnsafeCreateBucket(bucket Bucket) {
 			zap.Error(err),
 		)
 	}
+	t.journal.record(defragOpCreateBucket, bucket.Name(), nil, nil)
 	t.pending++
 }
 
 func (t *batchTx) UnsafeDeleteBucket(bucket Bucket) {
-	err := t.tx.DeleteBucket(bucket.Name())
-	if err != nil && !errors.Is(err, bolterrors.ErrBucketNotFound) {
+	if err := t.tx.DeleteBucket(bucket.Name()); err != nil {
 		t.backend.lg.Fatal(
 			"failed to delete a bucket",
 			zap.Stringer("bucket-name", bucket),
 			zap.Error(err),
 		)
 	}
+	t.journal.record(defragOpDeleteBucket, bucket.Name(), nil, nil)
+	t.freedPages = true
 	t.pending++
 }
 
@@ -158,7 +161,17 @@ func (t *batchTx) unsafePut(bucketType Bucket, key []byte, value []byte, seq boo
 	if seq {
 		// it is useful to increase fill percent when the workloads are mostly append-only.
 		// this can delay the page split and reduce space usage.
-		bucket.FillPercent = 0.9
+		bucket.SetFillPercent(0.9)
+	}
+	if codec := t.backend.codec; codec != nil {
+		var err error
+		if value, err = codec.Encode(bucketType.Name(), key, value); err != nil {
+			t.backend.lg.Fatal(
+				"failed to encode a value",
+				zap.Stringer("bucket-name", bucketType),
+				zap.Error(err),
+			)
+		}
 	}
 	if err := bucket.Put(key, value); err != nil {
 		t.backend.lg.Fatal(
@@ -167,6 +180,7 @@ func (t *batchTx) unsafePut(bucketType Bucket, key []byte, value []byte, seq boo
 			zap.Error(err),
 		)
 	}
+	t.journal.record(defragOpPut, bucketType.Name(), key, value)
 	t.pending++
 }
 
@@ -180,10 +194,12 @@ func (t *batchTx) UnsafeRange(bucketType Bucket, key, endKey []byte, limit int64
 			zap.Stack("stack"),
 		)
 	}
-	return unsafeRange(bucket.Cursor(), key, endKey, limit)
+	keys, vals := unsafeRange(bucket.Cursor(), key, endKey, limit)
+	decodeValues(t.backend.codec, bucketType, keys, vals)
+	return keys, vals
 }
 
-func unsafeRange(c *bolt.Cursor, key, endKey []byte, limit int64) (keys [][]byte, vs [][]byte) {
+func unsafeRange(c engineCursor, key, endKey []byte, limit int64) (keys [][]byte, vs [][]byte) {
 	if limit <= 0 {
 		limit = math.MaxInt64
 	}
@@ -223,15 +239,16 @@ func (t *batchTx) UnsafeDelete(bucketType Bucket, key []byte) {
 			zap.Error(err),
 		)
 	}
+	t.journal.record(defragOpDelete, bucketType.Name(), key, nil)
 	t.pending++
 }
 
 // UnsafeForEach must be called holding the lock on the tx.
 func (t *batchTx) UnsafeForEach(bucket Bucket, visitor func(k, v []byte) error) error {
-	return unsafeForEach(t.tx, bucket, visitor)
+	return unsafeForEach(t.tx, bucket, decodingVisitor(t.backend.codec, bucket, visitor))
 }
 
-func unsafeForEach(tx *bolt.Tx, bucket Bucket, visitor func(k, v []byte) error) error {
+func unsafeForEach(tx engineTx, bucket Bucket, visitor func(k, v []byte) error) error {
 	if b := tx.Bucket(bucket.Name()); b != nil {
 		return b.ForEach(visitor)
 	}
@@ -266,18 +283,30 @@ func (t *batchTx) commit(stop bool) {
 		}
 
 		start := time.Now()
+		atomic.StoreInt64(&t.backend.commitStart, start.UnixNano())
+		span := t.backend.startCommitSpan()
 
 		// gofail: var beforeCommit struct{}
 		err := t.tx.Commit()
 		// gofail: var afterCommit struct{}
+		atomic.StoreInt64(&t.backend.commitStart, 0)
+		if span != nil {
+			span.End()
+		}
 
-		rebalanceSec.Observe(t.tx.Stats().RebalanceTime.Seconds())
-		spillSec.Observe(t.tx.Stats().SpillTime.Seconds())
-		writeSec.Observe(t.tx.Stats().WriteTime.Seconds())
-		commitSec.Observe(time.Since(start).Seconds())
+		stats := t.tx.Stats()
+		rebalanceSec.Observe(stats.Rebalance.Seconds())
+		spillSec.Observe(stats.Spill.Seconds())
+		writeSec.Observe(stats.Write.Seconds())
+		latency := time.Since(start)
+		commitSec.Observe(latency.Seconds())
 		atomic.AddInt64(&t.backend.commits, 1)
+		if t.backend.batchTuner != nil && !stop {
+			t.backend.batchLimit = t.backend.batchTuner.observe(t.pending, latency, time.Now())
+		}
 
 		t.pending = 0
+		t.freedPages = false
 		if err != nil {
 			t.backend.lg.Fatal("failed to commit tx", zap.Error(err))
 		}
@@ -368,7 +397,7 @@ func (t *batchTxBuffered) unsafeCommit(stop bool) {
 	if t.backend.readTx.tx != nil {
 		// wait all store read transactions using the current boltdb tx to finish,
 		// then close the boltdb tx
-		go func(tx *bolt.Tx, wg *sync.WaitGroup) {
+		go func(tx engineTx, wg *sync.WaitGroup) {
 			wg.Wait()
 			if err := tx.Rollback(); err != nil {
 				t.backend.lg.Fatal("failed to rollback tx", zap.Error(err))
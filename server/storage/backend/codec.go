@@ -0,0 +1,64 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"fmt"
+)
+
+// ValueCodec transforms the values of the buckets between the form used by
+// the callers of the backend and the form stored in bolt, e.g. to encrypt
+// them at rest. Keys are always stored as is. The transaction buffers hold
+// the values of the callers, so that the codec is only involved when the
+// values are written to or read from bolt. Defrag decodes and re-encodes all
+// the values, e.g. to re-encrypt them with a new key.
+type ValueCodec interface {
+	// Encode returns the stored form of the value of key in the bucket of
+	// name bucketName.
+	Encode(bucketName, key, value []byte) ([]byte, error)
+	// Decode returns the value of key in the bucket of name bucketName from
+	// its stored form.
+	Decode(bucketName, key, stored []byte) ([]byte, error)
+}
+
+// decodeValues decodes in place the values of keys read from bucket. A value
+// that cannot be decoded is either corrupted or was encoded with keys that
+// are no longer available, so it panics like a read of a corrupted bolt page.
+func decodeValues(codec ValueCodec, bucket Bucket, keys, vals [][]byte) {
+	if codec == nil {
+		return
+	}
+	for i := range vals {
+		v, err := codec.Decode(bucket.Name(), keys[i], vals[i])
+		if err != nil {
+			panic(fmt.Errorf("failed to decode the value of key %q in bucket %s: %w", keys[i], bucket, err))
+		}
+		vals[i] = v
+	}
+}
+
+// decodingVisitor wraps visitor to pass it the decoded values of bucket.
+func decodingVisitor(codec ValueCodec, bucket Bucket, visitor func(k, v []byte) error) func(k, v []byte) error {
+	if codec == nil {
+		return visitor
+	}
+	return func(k, v []byte) error {
+		dv, err := codec.Decode(bucket.Name(), k, v)
+		if err != nil {
+			return fmt.Errorf("failed to decode the value of key %q in bucket %s: %w", k, bucket, err)
+		}
+		return visitor(k, dv)
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"fmt"
+)
+
+type defragOpType uint8
+
+const (
+	defragOpPut defragOpType = iota
+	defragOpDelete
+	defragOpCreateBucket
+	defragOpDeleteBucket
+)
+
+// defragOp is a write to the engine made while an online defragmentation copies
+// the database. The value is the one stored in the engine, encoded by the codec
+// if any.
+type defragOp struct {
+	typ    defragOpType
+	bucket []byte
+	key    []byte
+	value  []byte
+}
+
+// defragJournal records the writes of the batch tx while it is enabled, so
+// that defragmentation can replay them on the copy of the database. It is
+// protected by the lock of the batch tx.
+type defragJournal struct {
+	enabled bool
+	ops     []defragOp
+}
+
+func (j *defragJournal) record(typ defragOpType, bucket, key, value []byte) {
+	if !j.enabled {
+		return
+	}
+	// the caller may reuse the slices once the engine tx is committed
+	j.ops = append(j.ops, defragOp{
+		typ:    typ,
+		bucket: bucket,
+		key:    append([]byte(nil), key...),
+		value:  append([]byte(nil), value...),
+	})
+}
+
+// drain returns the recorded writes and clears the journal.
+func (j *defragJournal) drain() []defragOp {
+	ops := j.ops
+	j.ops = nil
+	return ops
+}
+
+func (j *defragJournal) stop() {
+	j.enabled = false
+	j.ops = nil
+}
+
+// replayDefragOps applies ops to tmpdb in transactions of at most limit
+// writes. The values are re-encoded with codec, if any, like the ones copied
+// by defragdb.
+func replayDefragOps(tmpdb engine, ops []defragOp, limit int, codec ValueCodec) error {
+	for len(ops) > 0 {
+		n := min(len(ops), limit)
+		if err := engineUpdate(tmpdb, func(tx engineTx) error {
+			for _, op := range ops[:n] {
+				if err := replayDefragOp(tx, op, codec); err != nil {
+					return err
+				}
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		ops = ops[n:]
+	}
+	return nil
+}
+
+func replayDefragOp(tx engineTx, op defragOp, codec ValueCodec) error {
+	switch op.typ {
+	case defragOpCreateBucket:
+		_, err := tx.CreateBucketIfNotExists(op.bucket)
+		return err
+	case defragOpDeleteBucket:
+		return tx.DeleteBucket(op.bucket)
+	}
+	b := tx.Bucket(op.bucket)
+	if b == nil {
+		return fmt.Errorf("backend: cannot replay a write to missing bucket %s", op.bucket)
+	}
+	if op.typ == defragOpDelete {
+		return b.Delete(op.key)
+	}
+	v := op.value
+	if codec != nil {
+		var err error
+		if v, err = codec.Decode(op.bucket, op.key, v); err != nil {
+			return err
+		}
+		if v, err = codec.Encode(op.bucket, op.key, v); err != nil {
+			return err
+		}
+	}
+	return b.Put(op.key, v)
+}
@@ -0,0 +1,141 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+const (
+	// EngineBbolt keeps the buckets in the B+tree of a bbolt database. It is
+	// the default storage engine.
+	EngineBbolt = "bbolt"
+	// EngineLog appends the committed writes to a log, and keeps the keys in
+	// memory. Writes never rewrite pages, which suits write-heavy workloads
+	// and large values; the space of the overwritten and deleted values is
+	// reclaimed by defragmentation, which compacts the log.
+	EngineLog = "log"
+)
+
+// Engines is the storage engines the backend can keep its buckets in.
+var Engines = []string{EngineBbolt, EngineLog}
+
+// engine is a store of named buckets of ordered key-value pairs, kept in a
+// single file, with serializable transactions: at most one writable
+// transaction at a time, and any number of read-only ones, each reading the
+// database as of its beginning.
+type engine interface {
+	Path() string
+	// Begin begins a transaction. A writable transaction waits for the
+	// previous one to end.
+	Begin(writable bool) (engineTx, error)
+	// OpenReadTxN returns the number of read-only transactions open.
+	OpenReadTxN() int
+	// Close closes the engine once all its transactions have ended.
+	Close() error
+}
+
+// engineTx is a transaction of an engine. The keys and values it returns are
+// only valid until it ends, and must not be modified.
+type engineTx interface {
+	// Bucket returns the bucket of the given name, or nil if there is none.
+	Bucket(name []byte) engineBucket
+	CreateBucketIfNotExists(name []byte) (engineBucket, error)
+	// DeleteBucket deletes the bucket of the given name, if any.
+	DeleteBucket(name []byte) error
+	// ForEach calls fn with the buckets, in name order.
+	ForEach(fn func(name []byte, b engineBucket) error) error
+
+	// Size returns the size of the database written by WriteTo.
+	Size() int64
+	// Usage returns the size of the database file as of the transaction,
+	// and how much of it is in use.
+	Usage() (size, inUse int64)
+	// WriteTo writes the database, as read by the transaction, in the file
+	// format of the engine.
+	WriteTo(w io.Writer) (int64, error)
+	// Stats returns the time spent by the commit of the transaction.
+	Stats() engineTxStats
+
+	Commit() error
+	Rollback() error
+}
+
+type engineTxStats struct {
+	Rebalance, Spill, Write time.Duration
+}
+
+type engineBucket interface {
+	Cursor() engineCursor
+	// ForEach calls fn with the key-value pairs of the bucket, in key order.
+	ForEach(fn func(k, v []byte) error) error
+	Put(key, value []byte) error
+	Delete(key []byte) error
+	// KeyN returns the number of keys of the bucket.
+	KeyN() int
+	// SetFillPercent hints at how full the engine fills its pages when
+	// they split; it is ignored by the engines without pages.
+	SetFillPercent(p float64)
+}
+
+// engineCursor iterates the keys of a bucket in order. Seek and Next return
+// a nil key past the last key.
+type engineCursor interface {
+	// Seek moves to the first key not below key.
+	Seek(key []byte) (k, v []byte)
+	Next() (k, v []byte)
+}
+
+// detectEngine returns the storage engine of the database file at path, or
+// an empty string if there is none yet.
+func detectEngine(path string) (string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	header := make([]byte, len(logMagic))
+	n, err := io.ReadFull(f, header)
+	switch {
+	case n == 0 && (err == nil || errors.Is(err, io.EOF)):
+		return "", nil
+	case bytes.Equal(header, logMagic):
+		return EngineLog, nil
+	case err != nil && !errors.Is(err, io.ErrUnexpectedEOF):
+		return "", err
+	}
+	return EngineBbolt, nil
+}
+
+// engineUpdate runs fn in a writable transaction of e, committed if fn
+// succeeds.
+func engineUpdate(e engine, fn func(tx engineTx) error) error {
+	tx, err := e.Begin(true)
+	if err != nil {
+		return err
+	}
+	if err = fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
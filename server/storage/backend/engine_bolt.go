@@ -0,0 +1,109 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"errors"
+
+	bolt "go.etcd.io/bbolt"
+	bolterrors "go.etcd.io/bbolt/errors"
+)
+
+// boltEngine is the engine keeping the buckets in a bbolt database.
+type boltEngine struct {
+	db *bolt.DB
+}
+
+func openBoltEngine(path string, opts *bolt.Options) (*boltEngine, error) {
+	db, err := bolt.Open(path, 0o600, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &boltEngine{db: db}, nil
+}
+
+func (e *boltEngine) Path() string { return e.db.Path() }
+
+func (e *boltEngine) Begin(writable bool) (engineTx, error) {
+	tx, err := e.db.Begin(writable)
+	if err != nil {
+		return nil, err
+	}
+	return &boltTx{tx}, nil
+}
+
+func (e *boltEngine) OpenReadTxN() int { return e.db.Stats().OpenTxN }
+
+func (e *boltEngine) Close() error { return e.db.Close() }
+
+type boltTx struct {
+	*bolt.Tx
+}
+
+func (tx *boltTx) Bucket(name []byte) engineBucket {
+	if b := tx.Tx.Bucket(name); b != nil {
+		return &boltBucket{b}
+	}
+	return nil
+}
+
+func (tx *boltTx) CreateBucketIfNotExists(name []byte) (engineBucket, error) {
+	b, err := tx.Tx.CreateBucketIfNotExists(name)
+	if err != nil {
+		return nil, err
+	}
+	return &boltBucket{b}, nil
+}
+
+func (tx *boltTx) DeleteBucket(name []byte) error {
+	if err := tx.Tx.DeleteBucket(name); err != nil && !errors.Is(err, bolterrors.ErrBucketNotFound) {
+		return err
+	}
+	return nil
+}
+
+func (tx *boltTx) ForEach(fn func(name []byte, b engineBucket) error) error {
+	return tx.Tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+		return fn(name, &boltBucket{b})
+	})
+}
+
+func (tx *boltTx) Usage() (size, inUse int64) {
+	db := tx.DB()
+	size = tx.Tx.Size()
+	// the pages freed by the last commits are pending until the read-only
+	// transactions before them end, but are no longer in use
+	stats := db.Stats()
+	return size, size - int64(stats.FreePageN+stats.PendingPageN)*int64(db.Info().PageSize)
+}
+
+func (tx *boltTx) Stats() engineTxStats {
+	stats := tx.Tx.Stats()
+	return engineTxStats{
+		Rebalance: stats.RebalanceTime,
+		Spill:     stats.SpillTime,
+		Write:     stats.WriteTime,
+	}
+}
+
+type boltBucket struct {
+	*bolt.Bucket
+}
+
+func (b *boltBucket) Cursor() engineCursor { return b.Bucket.Cursor() }
+
+func (b *boltBucket) KeyN() int { return b.Bucket.Stats().KeyN }
+
+func (b *boltBucket) SetFillPercent(p float64) { b.FillPercent = p }
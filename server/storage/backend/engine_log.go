@@ -0,0 +1,718 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"maps"
+	"math"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/btree"
+
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+)
+
+// The log engine file starts with logMagic, followed by the records of the
+// committed transactions. A record is the length and the CRC-32C of its
+// payload, followed by the payload: the writes of the transaction, each of
+// them its type, its bucket and, for the writes of keys, its key and value,
+// all of them prefixed by their length as uvarints.
+var logMagic = []byte("etcdlog\x01")
+
+const (
+	logRecordHeaderSize = 8
+	// logInlineValueSize is the size of the largest values kept in memory;
+	// the larger ones are read from the file.
+	logInlineValueSize = 512
+	// logCursorBatch is the number of keys a cursor collects at once.
+	logCursorBatch = 64
+	// logCompactBatch is the number of writes of a record of a compacted
+	// log.
+	logCompactBatch = 256
+	// logCompactAlign is the alignment of the size of a compacted log, like
+	// the pages of bbolt, which the clients saving snapshots rely on to
+	// find the checksum appended to them.
+	logCompactAlign = 512
+	logTreeDegree   = 32
+)
+
+type logOpType byte
+
+const (
+	logOpPut logOpType = iota + 1
+	logOpDelete
+	logOpCreateBucket
+	logOpDeleteBucket
+	// logOpPad pads a record to the end of its payload.
+	logOpPad
+)
+
+var (
+	errLogEngineClosed   = errors.New("backend: log engine closed")
+	errLogTxClosed       = errors.New("backend: log engine tx closed")
+	errLogTxNotWritable  = errors.New("backend: log engine tx not writable")
+	errLogBucketNotFound = errors.New("backend: log engine bucket not found")
+
+	logCrcTable = crc32.MakeTable(crc32.Castagnoli)
+)
+
+// logItem is a key of a bucket, and where to find its value.
+type logItem struct {
+	key []byte
+	// value is the value if it is kept in memory, or nil if it is read
+	// from the file, at off.
+	value []byte
+	off   int64
+	n     int
+}
+
+func logItemLess(a, b logItem) bool { return bytes.Compare(a.key, b.key) < 0 }
+
+type logTree = btree.BTreeG[logItem]
+
+// logEngine is the engine keeping the buckets in a log of the writes of the
+// committed transactions. The keys are indexed in memory, along with the
+// small values. A transaction writes a single record to the end of the file,
+// and never rewrites a previous one; defragmentation compacts the log to the
+// live keys, like the snapshots written by WriteTo.
+type logEngine struct {
+	path   string
+	f      *os.File
+	noSync bool
+
+	// closeMu is read locked by the transactions while they are open, and
+	// locked by Close.
+	closeMu sync.RWMutex
+	closed  bool
+	// writeMu is held by the writable transaction.
+	writeMu sync.Mutex
+
+	// mu protects the committed state. The trees of the buckets are never
+	// modified once committed: the writable transaction modifies clones of
+	// them, which are committed in turn.
+	mu      sync.RWMutex
+	buckets map[string]*logTree
+	// size is the size of the file, and live the size of the writes of the
+	// buckets and keys of the database, plus the magic, as written by
+	// WriteTo without the record headers.
+	size, live int64
+
+	openReadTxN atomic.Int64
+}
+
+// openLogEngine opens the log engine database at path, or on file if set.
+func openLogEngine(path string, file *os.File, noSync bool) (*logEngine, error) {
+	if file == nil {
+		lf, err := fileutil.LockFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+		if err != nil {
+			return nil, err
+		}
+		file = lf.File
+	}
+	e := &logEngine{path: path, f: file, noSync: noSync, buckets: make(map[string]*logTree)}
+	if err := e.load(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+// load replays the records of the file. A torn record at the end of the
+// file, written by a commit interrupted by a crash, is truncated.
+func (e *logEngine) load() error {
+	st, err := e.f.Stat()
+	if err != nil {
+		return err
+	}
+	if st.Size() == 0 {
+		if _, err = e.f.WriteAt(logMagic, 0); err != nil {
+			return err
+		}
+		e.size, e.live = int64(len(logMagic)), int64(len(logMagic))
+		return e.sync()
+	}
+
+	r := bufio.NewReaderSize(io.NewSectionReader(e.f, 0, st.Size()), 1024*1024)
+	magic := make([]byte, len(logMagic))
+	if _, err = io.ReadFull(r, magic); err != nil || !bytes.Equal(magic, logMagic) {
+		return fmt.Errorf("backend: %s is not a database of the %s storage engine", e.path, EngineLog)
+	}
+	tx := &logTx{e: e, writable: true, buckets: e.buckets, cloned: make(map[string]bool), live: int64(len(logMagic))}
+	off := int64(len(logMagic))
+	header := make([]byte, logRecordHeaderSize)
+	var payload []byte
+	for {
+		if _, err = io.ReadFull(r, header); err != nil {
+			break
+		}
+		n := binary.LittleEndian.Uint32(header)
+		if int64(n) > st.Size()-off-logRecordHeaderSize {
+			break
+		}
+		payload = slices.Grow(payload[:0], int(n))[:n]
+		if _, err = io.ReadFull(r, payload); err != nil || crc32.Checksum(payload, logCrcTable) != binary.LittleEndian.Uint32(header[4:]) {
+			break
+		}
+		if err = tx.replay(payload, off+logRecordHeaderSize); err != nil {
+			return fmt.Errorf("backend: corrupted record at offset %d of %s: %w", off, e.path, err)
+		}
+		off += logRecordHeaderSize + int64(n)
+	}
+	if off < st.Size() {
+		if err = e.f.Truncate(off); err != nil {
+			return err
+		}
+		if err = e.sync(); err != nil {
+			return err
+		}
+	}
+	e.size, e.live = off, tx.live
+	return nil
+}
+
+func (e *logEngine) sync() error {
+	if e.noSync {
+		return nil
+	}
+	return fileutil.Fdatasync(e.f)
+}
+
+func (e *logEngine) Path() string { return e.path }
+
+func (e *logEngine) Begin(writable bool) (engineTx, error) {
+	e.closeMu.RLock()
+	if e.closed {
+		e.closeMu.RUnlock()
+		return nil, errLogEngineClosed
+	}
+	if writable {
+		e.writeMu.Lock()
+	} else {
+		e.openReadTxN.Add(1)
+	}
+	e.mu.RLock()
+	tx := &logTx{e: e, writable: writable, buckets: e.buckets, size: e.size, live: e.live}
+	e.mu.RUnlock()
+	if writable {
+		tx.buckets = maps.Clone(tx.buckets)
+		tx.cloned = make(map[string]bool)
+		tx.buf = make([]byte, logRecordHeaderSize, 4096)
+	}
+	return tx, nil
+}
+
+func (e *logEngine) OpenReadTxN() int { return int(e.openReadTxN.Load()) }
+
+func (e *logEngine) Close() error {
+	e.closeMu.Lock()
+	defer e.closeMu.Unlock()
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.f.Close()
+}
+
+// readValue reads the value of it from the file. The file only shrinks when
+// it is replaced by defragmentation, once all transactions have ended.
+func (e *logEngine) readValue(it logItem) []byte {
+	v := make([]byte, it.n)
+	if _, err := e.f.ReadAt(v, it.off); err != nil {
+		panic(fmt.Errorf("backend: failed to read the value at offset %d of %s: %w", it.off, e.path, err))
+	}
+	return v
+}
+
+type logTx struct {
+	e        *logEngine
+	writable bool
+	closed   bool
+
+	buckets map[string]*logTree
+	// cloned is the buckets the writable transaction can modify: the ones
+	// it cloned or created.
+	cloned map[string]bool
+	// size and live are the ones of the engine, as updated by the writes
+	// of the transaction.
+	size, live int64
+
+	// buf is the record of the writes of the writable transaction, starting
+	// with room for its header.
+	buf []byte
+	// large is the keys of the values put by the transaction that are not
+	// kept in memory once committed.
+	large []logLargeValue
+	stats engineTxStats
+}
+
+type logLargeValue struct {
+	bucket string
+	key    []byte
+	off    int64
+}
+
+func (tx *logTx) Bucket(name []byte) engineBucket {
+	if _, ok := tx.buckets[string(name)]; !ok {
+		return nil
+	}
+	return &logBucket{tx: tx, name: string(name)}
+}
+
+func (tx *logTx) CreateBucketIfNotExists(name []byte) (engineBucket, error) {
+	if b := tx.Bucket(name); b != nil {
+		return b, nil
+	}
+	if !tx.writable {
+		return nil, errLogTxNotWritable
+	}
+	tx.appendOp(logOpCreateBucket, string(name), nil, nil)
+	tx.createBucket(string(name))
+	return &logBucket{tx: tx, name: string(name)}, nil
+}
+
+func (tx *logTx) DeleteBucket(name []byte) error {
+	if _, ok := tx.buckets[string(name)]; !ok {
+		return nil
+	}
+	if !tx.writable {
+		return errLogTxNotWritable
+	}
+	tx.appendOp(logOpDeleteBucket, string(name), nil, nil)
+	tx.deleteBucket(string(name))
+	return nil
+}
+
+func (tx *logTx) ForEach(fn func(name []byte, b engineBucket) error) error {
+	for _, name := range slices.Sorted(maps.Keys(tx.buckets)) {
+		if err := fn([]byte(name), &logBucket{tx: tx, name: name}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Size returns the size of the compacted log: the writes of the database,
+// in records of logCompactBatch writes, padded to logCompactAlign.
+func (tx *logTx) Size() int64 {
+	size := tx.live
+	for _, t := range tx.buckets {
+		size += int64((t.Len()+logCompactBatch)/logCompactBatch) * logRecordHeaderSize
+	}
+	return size + logPadSize(size)
+}
+
+// logPadSize returns the size of the record padding a log of the given size
+// to logCompactAlign, if it is not aligned.
+func logPadSize(size int64) int64 {
+	pad := (logCompactAlign - size%logCompactAlign) % logCompactAlign
+	if pad != 0 && pad <= logRecordHeaderSize {
+		pad += logCompactAlign
+	}
+	return pad
+}
+
+// Usage returns the size of the compacted log as in use. It can need a few
+// more record headers than the log, e.g. after a large transaction; it is
+// capped by the size of the file then.
+func (tx *logTx) Usage() (size, inUse int64) { return tx.size, min(tx.Size(), tx.size) }
+
+// WriteTo writes the compacted log of the database: the writes of its buckets
+// and keys, in order, in records of logCompactBatch writes.
+func (tx *logTx) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriterSize(w, 1024*1024)
+	n, err := bw.Write(logMagic)
+	written := int64(n)
+	rec := make([]byte, logRecordHeaderSize)
+	ops := 0
+	flush := func() error {
+		payload := rec[logRecordHeaderSize:]
+		binary.LittleEndian.PutUint32(rec, uint32(len(payload)))
+		binary.LittleEndian.PutUint32(rec[4:], crc32.Checksum(payload, logCrcTable))
+		n, err := bw.Write(rec)
+		written += int64(n)
+		rec, ops = rec[:logRecordHeaderSize], 0
+		return err
+	}
+	appendOp := func(typ logOpType, bucket string, key, value []byte) error {
+		rec = appendLogOp(rec, typ, bucket, key, value)
+		if ops++; ops == logCompactBatch {
+			return flush()
+		}
+		return nil
+	}
+	for _, name := range slices.Sorted(maps.Keys(tx.buckets)) {
+		if err != nil {
+			break
+		}
+		if err = appendOp(logOpCreateBucket, name, nil, nil); err != nil {
+			break
+		}
+		tx.buckets[name].Ascend(func(it logItem) bool {
+			err = appendOp(logOpPut, name, it.key, tx.value(it))
+			return err == nil
+		})
+		if err == nil && ops > 0 {
+			err = flush()
+		}
+	}
+	if pad := logPadSize(written); err == nil && pad != 0 {
+		rec = append(rec, byte(logOpPad))
+		rec = append(rec, make([]byte, pad-logRecordHeaderSize-1)...)
+		err = flush()
+	}
+	if err == nil {
+		err = bw.Flush()
+	}
+	return written, err
+}
+
+func (tx *logTx) Stats() engineTxStats { return tx.stats }
+
+func (tx *logTx) Commit() error {
+	if tx.closed {
+		return errLogTxClosed
+	}
+	if !tx.writable {
+		return errLogTxNotWritable
+	}
+	defer tx.end()
+	if payload := tx.buf[logRecordHeaderSize:]; len(payload) > 0 {
+		if len(payload) > math.MaxUint32 {
+			return fmt.Errorf("backend: log engine tx of %d bytes is too large", len(payload))
+		}
+		binary.LittleEndian.PutUint32(tx.buf, uint32(len(payload)))
+		binary.LittleEndian.PutUint32(tx.buf[4:], crc32.Checksum(payload, logCrcTable))
+		start := time.Now()
+		if _, err := tx.e.f.WriteAt(tx.buf, tx.size); err != nil {
+			return err
+		}
+		if err := tx.e.sync(); err != nil {
+			return err
+		}
+		tx.stats.Write = time.Since(start)
+		tx.size += int64(len(tx.buf))
+		tx.dropLargeValues()
+	}
+	tx.e.mu.Lock()
+	tx.e.buckets, tx.e.size, tx.e.live = tx.buckets, tx.size, tx.live
+	tx.e.mu.Unlock()
+	return nil
+}
+
+func (tx *logTx) Rollback() error {
+	if tx.closed {
+		return errLogTxClosed
+	}
+	tx.end()
+	return nil
+}
+
+func (tx *logTx) end() {
+	tx.closed = true
+	if tx.writable {
+		tx.e.writeMu.Unlock()
+	} else {
+		tx.e.openReadTxN.Add(-1)
+	}
+	tx.e.closeMu.RUnlock()
+}
+
+// dropLargeValues stops keeping in memory the large values put by the
+// committed transaction, which are read from the file from now on.
+func (tx *logTx) dropLargeValues() {
+	for _, l := range tx.large {
+		t := tx.buckets[l.bucket]
+		if t == nil {
+			continue
+		}
+		if it, ok := t.Get(logItem{key: l.key}); ok && it.off == l.off {
+			it.value = nil
+			t.ReplaceOrInsert(it)
+		}
+	}
+	tx.large = nil
+}
+
+func (tx *logTx) value(it logItem) []byte {
+	if it.value != nil {
+		return it.value
+	}
+	return tx.e.readValue(it)
+}
+
+// tree returns the tree of bucket, cloned first if the transaction is about
+// to modify it.
+func (tx *logTx) tree(bucket string, modify bool) *logTree {
+	t := tx.buckets[bucket]
+	if t != nil && modify && !tx.cloned[bucket] {
+		t = t.Clone()
+		tx.buckets[bucket] = t
+		tx.cloned[bucket] = true
+	}
+	return t
+}
+
+// appendOp appends a write to the record of the transaction. It returns the
+// offset in the file of the value of a put.
+func (tx *logTx) appendOp(typ logOpType, bucket string, key, value []byte) int64 {
+	tx.buf = appendLogOp(tx.buf, typ, bucket, key, value)
+	return tx.size + int64(len(tx.buf)-len(value))
+}
+
+func appendLogOp(buf []byte, typ logOpType, bucket string, key, value []byte) []byte {
+	buf = append(buf, byte(typ))
+	buf = binary.AppendUvarint(buf, uint64(len(bucket)))
+	buf = append(buf, bucket...)
+	if typ == logOpPut || typ == logOpDelete {
+		buf = binary.AppendUvarint(buf, uint64(len(key)))
+		buf = append(buf, key...)
+	}
+	if typ == logOpPut {
+		buf = binary.AppendUvarint(buf, uint64(len(value)))
+		buf = append(buf, value...)
+	}
+	return buf
+}
+
+// replay applies the writes of the record payload read at offset off of the
+// file.
+func (tx *logTx) replay(payload []byte, off int64) error {
+	errTruncated := errors.New("truncated write")
+	readBytes := func(p []byte) ([]byte, []byte, error) {
+		n, l := binary.Uvarint(p)
+		if l <= 0 || uint64(len(p)-l) < n {
+			return nil, nil, errTruncated
+		}
+		return p[l : l+int(n)], p[l+int(n):], nil
+	}
+	for p := payload; len(p) > 0; {
+		typ := logOpType(p[0])
+		if typ == logOpPad {
+			return nil
+		}
+		bucket, rest, err := readBytes(p[1:])
+		if err != nil {
+			return err
+		}
+		p = rest
+		switch typ {
+		case logOpCreateBucket:
+			if _, ok := tx.buckets[string(bucket)]; !ok {
+				tx.createBucket(string(bucket))
+			}
+			continue
+		case logOpDeleteBucket:
+			if _, ok := tx.buckets[string(bucket)]; ok {
+				tx.deleteBucket(string(bucket))
+			}
+			continue
+		case logOpPut, logOpDelete:
+		default:
+			return fmt.Errorf("unknown write type %d", typ)
+		}
+		t := tx.tree(string(bucket), true)
+		if t == nil {
+			return fmt.Errorf("write to missing bucket %s", bucket)
+		}
+		var key, value []byte
+		if key, p, err = readBytes(p); err != nil {
+			return err
+		}
+		key = bytes.Clone(key)
+		if typ == logOpDelete {
+			tx.delete(t, string(bucket), key)
+			continue
+		}
+		if value, p, err = readBytes(p); err != nil {
+			return err
+		}
+		valueOff := off + int64(len(payload)-len(p)-len(value))
+		it := logItem{key: key, off: valueOff, n: len(value)}
+		if len(value) <= logInlineValueSize {
+			it.value = append([]byte{}, value...)
+		}
+		tx.put(t, string(bucket), it)
+	}
+	return nil
+}
+
+func (tx *logTx) createBucket(name string) {
+	tx.buckets[name] = btree.NewG(logTreeDegree, logItemLess)
+	tx.cloned[name] = true
+	tx.live += logOpSize(logOpCreateBucket, name, nil, 0)
+}
+
+func (tx *logTx) deleteBucket(name string) {
+	tx.live -= logOpSize(logOpCreateBucket, name, nil, 0)
+	tx.buckets[name].Ascend(func(it logItem) bool {
+		tx.live -= logOpSize(logOpPut, name, it.key, it.n)
+		return true
+	})
+	delete(tx.buckets, name)
+	delete(tx.cloned, name)
+}
+
+func (tx *logTx) put(t *logTree, bucket string, it logItem) {
+	if old, ok := t.ReplaceOrInsert(it); ok {
+		tx.live -= logOpSize(logOpPut, bucket, old.key, old.n)
+	}
+	tx.live += logOpSize(logOpPut, bucket, it.key, it.n)
+}
+
+func (tx *logTx) delete(t *logTree, bucket string, key []byte) bool {
+	old, ok := t.Delete(logItem{key: key})
+	if ok {
+		tx.live -= logOpSize(logOpPut, bucket, old.key, old.n)
+	}
+	return ok
+}
+
+// logOpSize returns the size of a write in a record.
+func logOpSize(typ logOpType, bucket string, key []byte, n int) int64 {
+	size := 1 + uvarintLen(len(bucket)) + len(bucket)
+	if typ == logOpPut {
+		size += uvarintLen(len(key)) + len(key) + uvarintLen(n) + n
+	}
+	return int64(size)
+}
+
+func uvarintLen(n int) int {
+	var buf [binary.MaxVarintLen64]byte
+	return binary.PutUvarint(buf[:], uint64(n))
+}
+
+type logBucket struct {
+	tx   *logTx
+	name string
+}
+
+func (b *logBucket) Cursor() engineCursor {
+	return &logCursor{tx: b.tx, tree: b.tx.tree(b.name, false)}
+}
+
+func (b *logBucket) ForEach(fn func(k, v []byte) error) error {
+	t := b.tx.tree(b.name, false)
+	if t == nil {
+		return errLogBucketNotFound
+	}
+	var err error
+	t.Ascend(func(it logItem) bool {
+		err = fn(it.key, b.tx.value(it))
+		return err == nil
+	})
+	return err
+}
+
+func (b *logBucket) Put(key, value []byte) error {
+	if !b.tx.writable {
+		return errLogTxNotWritable
+	}
+	t := b.tx.tree(b.name, true)
+	if t == nil {
+		return errLogBucketNotFound
+	}
+	it := logItem{
+		key:   bytes.Clone(key),
+		value: append([]byte{}, value...),
+		off:   b.tx.appendOp(logOpPut, b.name, key, value),
+		n:     len(value),
+	}
+	if len(value) > logInlineValueSize {
+		b.tx.large = append(b.tx.large, logLargeValue{bucket: b.name, key: it.key, off: it.off})
+	}
+	b.tx.put(t, b.name, it)
+	return nil
+}
+
+func (b *logBucket) Delete(key []byte) error {
+	if !b.tx.writable {
+		return errLogTxNotWritable
+	}
+	t := b.tx.tree(b.name, true)
+	if t == nil {
+		return errLogBucketNotFound
+	}
+	if b.tx.delete(t, b.name, key) {
+		b.tx.appendOp(logOpDelete, b.name, key, nil)
+	}
+	return nil
+}
+
+func (b *logBucket) KeyN() int {
+	if t := b.tx.tree(b.name, false); t != nil {
+		return t.Len()
+	}
+	return 0
+}
+
+func (b *logBucket) SetFillPercent(float64) {}
+
+// logCursor iterates a tree in batches of keys.
+type logCursor struct {
+	tx    *logTx
+	tree  *logTree
+	items []logItem
+	i     int
+}
+
+func (c *logCursor) Seek(key []byte) ([]byte, []byte) {
+	c.fill(key, true)
+	return c.current()
+}
+
+func (c *logCursor) Next() ([]byte, []byte) {
+	if c.i >= len(c.items) {
+		return nil, nil
+	}
+	c.i++
+	if c.i == len(c.items) && len(c.items) == logCursorBatch {
+		c.fill(c.items[c.i-1].key, false)
+	}
+	return c.current()
+}
+
+// fill collects the next keys from key on.
+func (c *logCursor) fill(key []byte, inclusive bool) {
+	c.items, c.i = c.items[:0], 0
+	if c.tree == nil {
+		return
+	}
+	c.tree.AscendGreaterOrEqual(logItem{key: key}, func(it logItem) bool {
+		if !inclusive && bytes.Equal(it.key, key) {
+			return true
+		}
+		c.items = append(c.items, it)
+		return len(c.items) < logCursorBatch
+	})
+}
+
+func (c *logCursor) current() ([]byte, []byte) {
+	if c.i >= len(c.items) {
+		return nil, nil
+	}
+	it := c.items[c.i]
+	return it.key, c.tx.value(it)
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readLogBucket(t *testing.T, e engine, bucket string) map[string]string {
+	tx, err := e.Begin(false)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	b := tx.Bucket([]byte(bucket))
+	if b == nil {
+		return nil
+	}
+	kvs := make(map[string]string)
+	require.NoError(t, b.ForEach(func(k, v []byte) error {
+		kvs[string(k)] = string(v)
+		return nil
+	}))
+	return kvs
+}
+
+func TestLogEngine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db")
+	e, err := openLogEngine(path, nil, false)
+	require.NoError(t, err)
+
+	large := string(bytes.Repeat([]byte("v"), 2*logInlineValueSize))
+	require.NoError(t, engineUpdate(e, func(tx engineTx) error {
+		b, err := tx.CreateBucketIfNotExists([]byte("b"))
+		require.NoError(t, err)
+		for i := 0; i < 3*logCursorBatch; i++ {
+			require.NoError(t, b.Put([]byte(fmt.Sprintf("k%03d", i)), []byte("v")))
+		}
+		require.NoError(t, b.Put([]byte("large"), []byte(large)))
+		_, err = tx.CreateBucketIfNotExists([]byte("deleted"))
+		return err
+	}))
+
+	// a read tx reads the database as of its beginning.
+	rtx, err := e.Begin(false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, e.OpenReadTxN())
+	require.NoError(t, engineUpdate(e, func(tx engineTx) error {
+		b := tx.Bucket([]byte("b"))
+		require.NoError(t, b.Delete([]byte("k000")))
+		require.NoError(t, b.Put([]byte("k001"), []byte("w")))
+		require.NoError(t, b.Put([]byte("large"), []byte(large)))
+		return tx.DeleteBucket([]byte("deleted"))
+	}))
+	k, v := rtx.Bucket([]byte("b")).Cursor().Seek([]byte("k000"))
+	assert.Equal(t, "k000", string(k))
+	assert.Equal(t, "v", string(v))
+	assert.NotNil(t, rtx.Bucket([]byte("deleted")))
+	require.NoError(t, rtx.Rollback())
+
+	// a cursor iterates all the keys, across the batches it collects.
+	tx, err := e.Begin(false)
+	require.NoError(t, err)
+	c := tx.Bucket([]byte("b")).Cursor()
+	n := 0
+	for k, _ := c.Seek(nil); k != nil; k, _ = c.Next() {
+		n++
+	}
+	assert.Equal(t, 3*logCursorBatch, n)
+	// the large values are read from the file once committed.
+	it, ok := tx.(*logTx).buckets["b"].Get(logItem{key: []byte("large")})
+	require.True(t, ok)
+	assert.Nil(t, it.value)
+	size := tx.Size()
+	require.NoError(t, tx.Rollback())
+	want := readLogBucket(t, e, "b")
+	assert.Equal(t, large, want["large"])
+	assert.Equal(t, "w", want["k001"])
+	assert.NotContains(t, want, "k000")
+	require.NoError(t, e.Close())
+
+	// the writes are replayed, and the torn record of an interrupted commit
+	// is truncated.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{100, 0, 0, 0, 1, 2, 3, 4, 5})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	e, err = openLogEngine(path, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, want, readLogBucket(t, e, "b"))
+	assert.Nil(t, readLogBucket(t, e, "deleted"))
+
+	// the database written by a tx is the compacted log of its keys.
+	snapPath := filepath.Join(t.TempDir(), "snap")
+	sf, err := os.Create(snapPath)
+	require.NoError(t, err)
+	tx, err = e.Begin(false)
+	require.NoError(t, err)
+	written, err := tx.WriteTo(sf)
+	require.NoError(t, err)
+	assert.Equal(t, size, written)
+	fileSize, inUse := tx.Usage()
+	assert.Equal(t, size, inUse)
+	assert.Greater(t, fileSize, inUse)
+	require.NoError(t, tx.Rollback())
+	require.NoError(t, sf.Close())
+	require.NoError(t, e.Close())
+
+	engineName, err := detectEngine(snapPath)
+	require.NoError(t, err)
+	assert.Equal(t, EngineLog, engineName)
+	e, err = openLogEngine(snapPath, nil, false)
+	require.NoError(t, err)
+	defer e.Close()
+	assert.Equal(t, want, readLogBucket(t, e, "b"))
+	st, err := os.Stat(snapPath)
+	require.NoError(t, err)
+	assert.Equal(t, size, st.Size())
+}
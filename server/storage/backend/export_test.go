@@ -14,10 +14,14 @@
 
 package backend
 
-import bolt "go.etcd.io/bbolt"
+import (
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
 
 func DbFromBackendForTest(b Backend) *bolt.DB {
-	return b.(*backend).db
+	return b.(*backend).engine.(*boltEngine).db
 }
 
 func DefragLimitForTest() int {
@@ -27,3 +31,15 @@ func DefragLimitForTest() int {
 func CommitsForTest(b Backend) int64 {
 	return b.(*backend).Commits()
 }
+
+func BatchLimitForTest(b Backend) int {
+	return b.(*backend).batchLimit
+}
+
+func BatchIntervalForTest(b Backend) time.Duration {
+	return b.(*backend).nextBatchInterval()
+}
+
+func ReclaimFreePagesForTest(b Backend) (int64, error) {
+	return b.(*backend).reclaimFreePages()
+}
@@ -73,6 +73,17 @@ var (
 		Buckets: prometheus.ExponentialBuckets(.1, 2, 13),
 	})
 
+	defragPauseSec = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "etcd",
+		Subsystem: "disk",
+		Name:      "backend_defrag_pause_duration_seconds",
+		Help:      "The latency distribution of the pause of the backend at the end of defragmentation.",
+
+		// lowest bucket start of upper bound 0.001 sec (1 ms) with factor 2
+		// highest bucket start of 0.001 sec * 2^13 == 8.192 sec
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 14),
+	})
+
 	snapshotTransferSec = prometheus.NewHistogram(prometheus.HistogramOpts{
 		Namespace: "etcd",
 		Subsystem: "disk",
@@ -84,6 +95,56 @@ var (
 		Buckets: prometheus.ExponentialBuckets(.01, 2, 17),
 	})
 
+	batchIntervalSec = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "etcd_debugging",
+		Subsystem: "disk",
+		Name:      "backend_batch_interval_seconds",
+		Help:      "The backend batch interval adapted to the commit latency.",
+	})
+
+	batchLimitGauge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "etcd_debugging",
+		Subsystem: "disk",
+		Name:      "backend_batch_limit",
+		Help:      "The backend batch limit adapted to the write rate.",
+	})
+
+	concurrentReadTxs = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "etcd_debugging",
+		Subsystem: "disk",
+		Name:      "backend_concurrent_read_txs",
+		Help:      "The number of open concurrent read transactions, which keep the bboltdb read transaction of their batch interval open.",
+	})
+
+	concurrentReadTxSec = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "etcd_debugging",
+		Subsystem: "disk",
+		Name:      "backend_concurrent_read_tx_duration_seconds",
+		Help:      "The latency distributions of the concurrent read transactions, from their creation to their end.",
+
+		// lowest bucket start of upper bound 0.001 sec (1 ms) with factor 2
+		// highest bucket start of 0.001 sec * 2^16 == 65.536 sec
+		Buckets: prometheus.ExponentialBuckets(0.001, 2, 17),
+	})
+
+	readBufferCopySec = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "etcd_debugging",
+		Subsystem: "disk",
+		Name:      "backend_read_buffer_copy_duration_seconds",
+		Help:      "The latency distributions of the copies of the read buffer shared by the concurrent read transactions.",
+
+		// lowest bucket start of upper bound 0.00001 sec (10 µs) with factor 2
+		// highest bucket start of 0.00001 sec * 2^15 == 0.32768 sec
+		Buckets: prometheus.ExponentialBuckets(0.00001, 2, 16),
+	})
+
+	reclaimedBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "etcd_debugging",
+		Subsystem: "disk",
+		Name:      "backend_reclaimed_bytes_total",
+		Help:      "The total number of bytes of free pages returned to the filesystem without defragmentation.",
+	})
+
 	isDefragActive = prometheus.NewGauge(prometheus.GaugeOpts{
 		Namespace: "etcd",
 		Subsystem: "disk",
@@ -98,6 +159,13 @@ func init() {
 	prometheus.MustRegister(spillSec)
 	prometheus.MustRegister(writeSec)
 	prometheus.MustRegister(defragSec)
+	prometheus.MustRegister(defragPauseSec)
 	prometheus.MustRegister(snapshotTransferSec)
+	prometheus.MustRegister(batchIntervalSec)
+	prometheus.MustRegister(batchLimitGauge)
+	prometheus.MustRegister(concurrentReadTxs)
+	prometheus.MustRegister(concurrentReadTxSec)
+	prometheus.MustRegister(readBufferCopySec)
+	prometheus.MustRegister(reclaimedBytes)
 	prometheus.MustRegister(isDefragActive)
 }
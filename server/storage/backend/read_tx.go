@@ -17,8 +17,7 @@ package backend
 import (
 	"math"
 	"sync"
-
-	bolt "go.etcd.io/bbolt"
+	"time"
 )
 
 // IsSafeRangeBucket is a hack to avoid inadvertently reading duplicate keys;
@@ -45,10 +44,12 @@ type baseReadTx struct {
 	// TODO: group and encapsulate {txMu, tx, buckets, txWg}, as they share the same lifecycle.
 	// txMu protects accesses to buckets and tx on Range requests.
 	txMu    *sync.RWMutex
-	tx      *bolt.Tx
-	buckets map[BucketID]*bolt.Bucket
+	tx      engineTx
+	buckets map[BucketID]engineBucket
 	// txWg protects tx from being rolled back at the end of a batch interval until all reads using this tx are done.
 	txWg *sync.WaitGroup
+	// codec decodes the values read from tx, if any.
+	codec ValueCodec
 }
 
 func (baseReadTx *baseReadTx) UnsafeForEach(bucket Bucket, visitor func(k, v []byte) error) error {
@@ -57,11 +58,12 @@ func (baseReadTx *baseReadTx) UnsafeForEach(bucket Bucket, visitor func(k, v []b
 		dups[string(k)] = struct{}{}
 		return nil
 	}
+	visitStored := decodingVisitor(baseReadTx.codec, bucket, visitor)
 	visitNoDup := func(k, v []byte) error {
 		if _, ok := dups[string(k)]; ok {
 			return nil
 		}
-		return visitor(k, v)
+		return visitStored(k, v)
 	}
 	if err := baseReadTx.buf.ForEach(bucket, getDups); err != nil {
 		return err
@@ -118,6 +120,7 @@ func (baseReadTx *baseReadTx) UnsafeRange(bucketType Bucket, key, endKey []byte,
 	baseReadTx.txMu.Unlock()
 
 	k2, v2 := unsafeRange(c, key, endKey, limit-int64(len(keys)))
+	decodeValues(baseReadTx.codec, bucketType, k2, v2)
 	return append(k2, keys...), append(v2, vals...)
 }
 
@@ -132,13 +135,14 @@ func (rt *readTx) RUnlock() { rt.mu.RUnlock() }
 
 func (rt *readTx) reset() {
 	rt.buf.reset()
-	rt.buckets = make(map[BucketID]*bolt.Bucket)
+	rt.buckets = make(map[BucketID]engineBucket)
 	rt.tx = nil
 	rt.txWg = new(sync.WaitGroup)
 }
 
 type concurrentReadTx struct {
 	baseReadTx
+	start time.Time
 }
 
 func (rt *concurrentReadTx) Lock()   {}
@@ -148,4 +152,8 @@ func (rt *concurrentReadTx) Unlock() {}
 func (rt *concurrentReadTx) RLock() {}
 
 // RUnlock signals the end of concurrentReadTx.
-func (rt *concurrentReadTx) RUnlock() { rt.txWg.Done() }
+func (rt *concurrentReadTx) RUnlock() {
+	concurrentReadTxs.Dec()
+	concurrentReadTxSec.Observe(time.Since(rt.start).Seconds())
+	rt.txWg.Done()
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"os"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"go.uber.org/zap"
+)
+
+// reclaimRoundPages is the number of pages a round of reclamation scans,
+// holding the batch tx lock.
+var reclaimRoundPages = 16384
+
+// FreePageReclaim is when the free pages of a bbolt database are returned to
+// the filesystem in the background. Unlike a defragmentation, which rewrites
+// the whole database, the reclamation only punches holes in the free pages
+// of the file, a few at a time, so that the backend never pauses for long.
+// The size of the file is kept, but the free pages no longer take space on
+// the disk until bbolt reuses them.
+type FreePageReclaim struct {
+	// Threshold is the fraction of the database, between 0 and 1, that must
+	// be free for its free pages to be reclaimed.
+	Threshold float64
+	// Interval is the time between the rounds of reclamation, each scanning
+	// reclaimRoundPages pages.
+	Interval time.Duration
+}
+
+// freePageReclaimer reclaims the free pages of the database in rounds,
+// scanning the pages from the one after the last scanned, and starting over
+// past the last page.
+type freePageReclaimer struct {
+	FreePageReclaim
+	// next is the id of the next page to scan. It is only used by the
+	// rounds, which are serialized by the defragmentation lock.
+	next  int
+	donec chan struct{}
+}
+
+func (b *backend) runReclaim() {
+	defer close(b.reclaim.donec)
+	t := time.NewTicker(b.reclaim.Interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+		case <-b.stopc:
+			return
+		}
+		if _, err := b.reclaimFreePages(); err != nil {
+			b.lg.Warn("failed to reclaim free pages", zap.Error(err))
+		}
+	}
+}
+
+// reclaimFreePages runs a round of reclamation if enough of the database is
+// free, and returns the number of bytes of the free pages it punched.
+//
+// A free page is only punched while no transaction may read it: the batch tx
+// must not have freed pages yet, which it only does when it deletes a bucket
+// before its commit, and the only read-only transaction open must be the one
+// of the backend, which began after the last commit, so that the pages freed
+// by the commits are unreachable. The page is written again in full before it
+// is read, if bbolt reuses it.
+func (b *backend) reclaimFreePages() (int64, error) {
+	// the defragmentation swaps the database file
+	if !b.defragMu.TryLock() {
+		return 0, nil
+	}
+	defer b.defragMu.Unlock()
+
+	b.batchTx.LockOutsideApply()
+	defer b.batchTx.Unlock()
+	select {
+	case <-b.stopc:
+		return 0, nil
+	default:
+	}
+	if b.batchTx.freedPages || b.engine.OpenReadTxN() > 1 {
+		return 0, nil
+	}
+	tx := b.batchTx.tx.(*boltTx)
+	pageSize := int64(tx.DB().Info().PageSize)
+	size, sizeInUse := tx.Usage()
+	if size == 0 || float64(size-sizeInUse) < b.reclaim.Threshold*float64(size) {
+		return 0, nil
+	}
+
+	f, err := os.OpenFile(b.engine.Path(), os.O_WRONLY, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	start := time.Now()
+	first := b.reclaim.next
+	// the pages 0 and 1 are the meta pages
+	first = max(first, 2)
+	last := first + reclaimRoundPages
+	var punched int64
+	run := -1
+	punch := func(end int) error {
+		if run < 0 {
+			return nil
+		}
+		off, n := int64(run)*pageSize, int64(end-run)*pageSize
+		run = -1
+		if err := punchHole(f, off, n); err != nil {
+			return err
+		}
+		punched += n
+		return nil
+	}
+	id := first
+	for ; id < last; id++ {
+		p, err := tx.Page(id)
+		if err != nil {
+			return punched, err
+		}
+		if p == nil {
+			// past the last page; start over in the next round
+			break
+		}
+		if p.Type != "free" {
+			if err := punch(id); err != nil {
+				return punched, err
+			}
+			continue
+		}
+		if run < 0 {
+			run = id
+		}
+	}
+	if err := punch(id); err != nil {
+		return punched, err
+	}
+	if id < last {
+		b.reclaim.next = 0
+	} else {
+		b.reclaim.next = id
+	}
+
+	reclaimedBytes.Add(float64(punched))
+	if punched > 0 {
+		b.lg.Debug(
+			"reclaimed free pages",
+			zap.Int("first-page-id", first),
+			zap.Int("last-page-id", id-1),
+			zap.Int64("reclaimed-bytes", punched),
+			zap.String("reclaimed", humanize.Bytes(uint64(punched))),
+			zap.Duration("took", time.Since(start)),
+		)
+	}
+	return punched, nil
+}
@@ -0,0 +1,37 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package backend
+
+import (
+	"os"
+	"syscall"
+)
+
+const (
+	// see FALLOC_FL_KEEP_SIZE and FALLOC_FL_PUNCH_HOLE
+	fallocKeepSize  = 0x1
+	fallocPunchHole = 0x2
+)
+
+// reclaimSupported is whether holes can be punched in the database file.
+const reclaimSupported = true
+
+// punchHole deallocates the n bytes of f at off, which then read as zeros,
+// without changing the size of f.
+func punchHole(f *os.File, off, n int64) error {
+	return syscall.Fallocate(int(f.Fd()), fallocKeepSize|fallocPunchHole, off, n)
+}
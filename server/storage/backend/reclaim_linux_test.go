@@ -0,0 +1,150 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package backend_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	bolt "go.etcd.io/bbolt"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+// TestBackendReclaimFreePages ensures the free pages of the database are
+// punched out of the file without changing its content, and that bbolt can
+// still reuse them.
+func TestBackendReclaimFreePages(t *testing.T) {
+	bcfg := backend.DefaultBackendConfig(zaptest.NewLogger(t))
+	bcfg.BatchInterval = time.Hour
+	bcfg.FreePageReclaim = &backend.FreePageReclaim{Threshold: 0.5, Interval: time.Hour}
+	b, path := betesting.NewTmpBackendFromCfg(t, bcfg)
+	defer betesting.Close(t, b)
+
+	value := bytes.Repeat([]byte("v"), 64*1024)
+	tx := b.BatchTx()
+	tx.Lock()
+	tx.UnsafeCreateBucket(schema.Test)
+	for i := 0; i < 200; i++ {
+		tx.UnsafePut(schema.Test, []byte(fmt.Sprintf("foo_%d", i)), value)
+	}
+	tx.UnsafePut(schema.Test, []byte("kept"), []byte("bar"))
+	tx.Unlock()
+	b.ForceCommit()
+
+	// below the threshold, nothing is reclaimed
+	n, err := backend.ReclaimFreePagesForTest(b)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	tx.Lock()
+	for i := 0; i < 200; i++ {
+		tx.UnsafeDelete(schema.Test, []byte(fmt.Sprintf("foo_%d", i)))
+	}
+	tx.Unlock()
+	b.ForceCommit()
+	size := b.Size()
+	allocated := allocatedBytes(t, path)
+	oh, err := b.Hash(nil)
+	require.NoError(t, err)
+
+	// the read tx of the previous commit is closed asynchronously
+	require.Eventually(t, func() bool {
+		n, err = backend.ReclaimFreePagesForTest(b)
+		require.NoError(t, err)
+		return n > 0
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Greater(t, n, int64(100*len(value)))
+	assert.Equal(t, size, b.Size())
+	assert.Less(t, allocatedBytes(t, path), allocated-n/2)
+	nh, err := b.Hash(nil)
+	require.NoError(t, err)
+	assert.Equal(t, oh, nh)
+
+	// the reclaimed pages are written again when bbolt reuses them
+	tx.Lock()
+	for i := 0; i < 100; i++ {
+		tx.UnsafePut(schema.Test, []byte(fmt.Sprintf("bar_%d", i)), value)
+	}
+	tx.Unlock()
+	b.ForceCommit()
+	require.NoError(t, backend.DbFromBackendForTest(b).View(func(tx *bolt.Tx) error {
+		for err := range tx.Check() {
+			return err
+		}
+		return nil
+	}))
+	rtx := b.ReadTx()
+	rtx.RLock()
+	defer rtx.RUnlock()
+	_, vals := rtx.UnsafeRange(schema.Test, []byte("bar_42"), nil, 0)
+	require.Len(t, vals, 1)
+	assert.Equal(t, value, vals[0])
+	_, vals = rtx.UnsafeRange(schema.Test, []byte("kept"), nil, 0)
+	assert.Equal(t, [][]byte{[]byte("bar")}, vals)
+}
+
+// TestBackendReclaimFreePagesWithOpenReadTx ensures the free pages are not
+// reclaimed while an older transaction may still read them.
+func TestBackendReclaimFreePagesWithOpenReadTx(t *testing.T) {
+	bcfg := backend.DefaultBackendConfig(zaptest.NewLogger(t))
+	bcfg.BatchInterval = time.Hour
+	bcfg.FreePageReclaim = &backend.FreePageReclaim{Threshold: 0.1, Interval: time.Hour}
+	b, _ := betesting.NewTmpBackendFromCfg(t, bcfg)
+	defer betesting.Close(t, b)
+
+	value := bytes.Repeat([]byte("v"), 64*1024)
+	tx := b.BatchTx()
+	tx.Lock()
+	tx.UnsafeCreateBucket(schema.Test)
+	for i := 0; i < 50; i++ {
+		tx.UnsafePut(schema.Test, []byte(fmt.Sprintf("foo_%d", i)), value)
+	}
+	tx.Unlock()
+	b.ForceCommit()
+
+	snap := b.Snapshot()
+	tx.Lock()
+	for i := 0; i < 50; i++ {
+		tx.UnsafeDelete(schema.Test, []byte(fmt.Sprintf("foo_%d", i)))
+	}
+	tx.Unlock()
+	b.ForceCommit()
+
+	n, err := backend.ReclaimFreePagesForTest(b)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	var buf bytes.Buffer
+	_, err = snap.WriteTo(&buf)
+	require.NoError(t, err)
+	require.NoError(t, snap.Close())
+}
+
+func allocatedBytes(t *testing.T, path string) int64 {
+	fi, err := os.Stat(path)
+	require.NoError(t, err)
+	return fi.Sys().(*syscall.Stat_t).Blocks * 512
+}
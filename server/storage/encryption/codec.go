@@ -0,0 +1,432 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package encryption encrypts at rest the values of the backend holding user
+// data: the key-values, the leases and the auth users and roles.
+//
+// The values are encrypted with AES-256-GCM by data encryption keys (DEKs),
+// authenticating the bucket and the key they are stored at. The DEKs are
+// stored in the meta bucket of the backend, wrapped by a key encryption key
+// (KEK) of a KEKProvider, so that a copy of the backend, e.g. a snapshot, can
+// only be read with access to the KEK.
+//
+// The keys of the buckets, the meta bucket and the membership are stored as
+// is. Values stored before encryption was enabled stay readable, and are
+// encrypted by the next defragmentation.
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+const (
+	// dekSize is the size of the AES-256 data encryption keys.
+	dekSize = 32
+	// headerLen is the length of the header of the encrypted values: a zero
+	// byte, which no protobuf encoded value starts with, the version of the
+	// format, the ID of the DEK and the nonce.
+	headerLen   = 2 + 4 + 12
+	formatV1    = 1
+	nonceOffset = 6
+)
+
+// keyringKeyName is the key of the keyring in the meta bucket.
+var keyringKeyName = []byte("encryptionKeyring")
+
+var (
+	ErrUnknownDEK      = errors.New("encryption: unknown data encryption key")
+	ErrInvalidValue    = errors.New("encryption: invalid encrypted value")
+	ErrRotationRunning = errors.New("encryption: key rotation is already running")
+	ErrKEKRequired     = errors.New("encryption: the backend is encrypted, a key encryption key is required")
+)
+
+// encryptedBuckets are the buckets holding user data.
+var encryptedBuckets = [][]byte{
+	schema.Key.Name(),
+	schema.Lease.Name(),
+	schema.AuthUsers.Name(),
+	schema.AuthRoles.Name(),
+}
+
+func isEncrypted(bucketName []byte) bool {
+	for _, name := range encryptedBuckets {
+		if bytes.Equal(bucketName, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// keyring is the form of the DEKs stored in the meta bucket.
+type keyring struct {
+	// Active is the ID of the DEK encrypting the values.
+	Active uint32       `json:"active"`
+	Keys   []wrappedKey `json:"keys"`
+}
+
+type wrappedKey struct {
+	ID uint32 `json:"id"`
+	// KEKID is the ID of the KEK the DEK is wrapped with.
+	KEKID   string `json:"kekID"`
+	Wrapped []byte `json:"wrapped"`
+}
+
+type dek struct {
+	key  []byte
+	aead cipher.AEAD
+}
+
+// Codec is a backend.ValueCodec encrypting the values of the buckets holding
+// user data. A codec is shared by the backends opened by a member: Load adds
+// the DEKs of each of them, and makes the DEK active in the last loaded one
+// encrypt the new values.
+type Codec struct {
+	lg       *zap.Logger
+	provider KEKProvider
+
+	mu sync.RWMutex
+	// deks are all the DEKs loaded, including the ones removed from the
+	// keyring by a rotation, which may still be read by open transactions.
+	deks   map[uint32]*dek
+	active uint32
+	ring   keyring
+
+	rotating sync.Mutex
+}
+
+var _ backend.ValueCodec = (*Codec)(nil)
+
+// NewCodec returns a codec wrapping its DEKs with the KEKs of provider.
+func NewCodec(lg *zap.Logger, provider KEKProvider) *Codec {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	return &Codec{lg: lg, provider: provider, deks: make(map[uint32]*dek)}
+}
+
+// KEKProvider returns the provider wrapping the DEKs of the codec.
+func (c *Codec) KEKProvider() KEKProvider {
+	return c.provider
+}
+
+func newDEK(key []byte) (*dek, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &dek{key: key, aead: aead}, nil
+}
+
+// additionalData binds an encrypted value to the bucket and key it is stored
+// at, so that it cannot be moved to another key.
+func additionalData(bucketName, key []byte) []byte {
+	ad := make([]byte, 0, 1+len(bucketName)+len(key))
+	ad = append(ad, byte(len(bucketName)))
+	ad = append(ad, bucketName...)
+	return append(ad, key...)
+}
+
+func (c *Codec) Encode(bucketName, key, value []byte) ([]byte, error) {
+	if !isEncrypted(bucketName) {
+		return value, nil
+	}
+	c.mu.RLock()
+	id, d := c.active, c.deks[c.active]
+	c.mu.RUnlock()
+	if d == nil {
+		return nil, ErrUnknownDEK
+	}
+	out := make([]byte, headerLen, headerLen+len(value)+d.aead.Overhead())
+	out[1] = formatV1
+	binary.BigEndian.PutUint32(out[2:nonceOffset], id)
+	nonce := out[nonceOffset:headerLen]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return d.aead.Seal(out, nonce, value, additionalData(bucketName, key)), nil
+}
+
+func (c *Codec) Decode(bucketName, key, stored []byte) ([]byte, error) {
+	// values stored before the encryption was enabled are protobuf encoded,
+	// which never starts with a zero byte.
+	if !isEncrypted(bucketName) || len(stored) == 0 || stored[0] != 0 {
+		return stored, nil
+	}
+	if len(stored) < headerLen || stored[1] != formatV1 {
+		return nil, ErrInvalidValue
+	}
+	id := binary.BigEndian.Uint32(stored[2:nonceOffset])
+	c.mu.RLock()
+	d := c.deks[id]
+	c.mu.RUnlock()
+	if d == nil {
+		return nil, fmt.Errorf("%w %d", ErrUnknownDEK, id)
+	}
+	v, err := d.aead.Open(nil, stored[nonceOffset:headerLen], stored[headerLen:], additionalData(bucketName, key))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidValue, err)
+	}
+	return v, nil
+}
+
+// Load loads the keyring of be, unwrapping its DEKs with the KEK provider. A
+// keyring with a new DEK is created if be has none.
+func (c *Codec) Load(ctx context.Context, be backend.Backend) error {
+	tx := be.BatchTx()
+	tx.LockOutsideApply()
+	tx.UnsafeCreateBucket(schema.Meta)
+	_, vs := tx.UnsafeRange(schema.Meta, keyringKeyName, nil, 0)
+	tx.Unlock()
+
+	if len(vs) == 0 {
+		c.mu.Lock()
+		c.ring = keyring{}
+		c.mu.Unlock()
+		id, err := c.addDEK(ctx)
+		if err != nil {
+			return err
+		}
+		c.lg.Info("created data encryption key", zap.Uint32("dek-id", id))
+		return c.save(be)
+	}
+
+	ring, deks, err := c.unwrapKeyring(ctx, vs[0])
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	for id, d := range deks {
+		c.deks[id] = d
+	}
+	c.active, c.ring = ring.Active, ring
+	c.mu.Unlock()
+	c.lg.Info("loaded data encryption keys", zap.Uint32("active-dek-id", ring.Active), zap.Int("dek-count", len(ring.Keys)))
+	return nil
+}
+
+// unwrapKeyring decodes the keyring and unwraps its DEKs.
+func (c *Codec) unwrapKeyring(ctx context.Context, b []byte) (keyring, map[uint32]*dek, error) {
+	var ring keyring
+	if err := json.Unmarshal(b, &ring); err != nil {
+		return ring, nil, fmt.Errorf("encryption: failed to decode keyring: %w", err)
+	}
+	deks := make(map[uint32]*dek, len(ring.Keys))
+	for _, k := range ring.Keys {
+		key, err := c.provider.Unwrap(ctx, k.Wrapped, k.KEKID)
+		if err != nil {
+			return ring, nil, fmt.Errorf("encryption: failed to unwrap data encryption key %d with key encryption key %q: %w", k.ID, k.KEKID, err)
+		}
+		if deks[k.ID], err = newDEK(key); err != nil {
+			return ring, nil, err
+		}
+	}
+	if deks[ring.Active] == nil {
+		return ring, nil, fmt.Errorf("%w %d", ErrUnknownDEK, ring.Active)
+	}
+	return ring, deks, nil
+}
+
+// Keyring returns the encoded keyring of the codec. It holds the DEKs wrapped
+// with the KEKs, not the keys themselves.
+func (c *Codec) Keyring() ([]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return json.Marshal(c.ring)
+}
+
+// CheckKeyring returns an error unless the KEK provider of the codec unwraps
+// all the DEKs of the encoded keyring, e.g. of a snapshot sent by a peer.
+func (c *Codec) CheckKeyring(ctx context.Context, b []byte) error {
+	_, _, err := c.unwrapKeyring(ctx, b)
+	return err
+}
+
+// OpenBackend opens the backend at path like backend.NewDefaultBackend. If
+// the backend holds a keyring, its values are decrypted with the KEKs of
+// provider, and ErrKEKRequired is returned if provider is nil. Unlike Load, no
+// keyring is created in the backends without one, which are opened as is.
+func OpenBackend(ctx context.Context, lg *zap.Logger, path string, provider KEKProvider, opts ...backend.BackendConfigOption) (backend.Backend, error) {
+	be := backend.NewDefaultBackend(lg, path, opts...)
+	if !HasKeyring(be) {
+		return be, nil
+	}
+	if err := be.Close(); err != nil {
+		return nil, err
+	}
+	if provider == nil {
+		return nil, fmt.Errorf("%s: %w", path, ErrKEKRequired)
+	}
+	c := NewCodec(lg, provider)
+	be = backend.NewDefaultBackend(lg, path, append(opts, backend.WithCodec(c))...)
+	if err := c.Load(ctx, be); err != nil {
+		be.Close()
+		return nil, err
+	}
+	return be, nil
+}
+
+// HasKeyring returns true if be holds a keyring, i.e. if its values may be
+// encrypted.
+func HasKeyring(be backend.Backend) bool {
+	tx := be.ReadTx()
+	tx.RLock()
+	defer tx.RUnlock()
+	_, vs := tx.UnsafeRange(schema.Meta, keyringKeyName, nil, 0)
+	return len(vs) > 0
+}
+
+// addDEK generates a new DEK, wraps it with the current KEK and makes it the
+// active one of the keyring.
+func (c *Codec) addDEK(ctx context.Context) (uint32, error) {
+	key := make([]byte, dekSize)
+	if _, err := rand.Read(key); err != nil {
+		return 0, err
+	}
+	d, err := newDEK(key)
+	if err != nil {
+		return 0, err
+	}
+	wrapped, kekID, err := c.provider.Wrap(ctx, key)
+	if err != nil {
+		return 0, fmt.Errorf("encryption: failed to wrap data encryption key: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var id uint32
+	for id == 0 || c.deks[id] != nil {
+		var b [4]byte
+		if _, err = rand.Read(b[:]); err != nil {
+			return 0, err
+		}
+		id = binary.BigEndian.Uint32(b[:])
+	}
+	c.deks[id] = d
+	c.active = id
+	c.ring.Active = id
+	c.ring.Keys = append(c.ring.Keys, wrappedKey{ID: id, KEKID: kekID, Wrapped: wrapped})
+	return id, nil
+}
+
+// save stores the keyring in be and commits it.
+func (c *Codec) save(be backend.Backend) error {
+	c.mu.RLock()
+	b, err := json.Marshal(c.ring)
+	c.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+	tx := be.BatchTx()
+	tx.LockOutsideApply()
+	tx.UnsafePut(schema.Meta, keyringKeyName, b)
+	tx.Unlock()
+	be.ForceCommit()
+	return nil
+}
+
+// RotateResult is the keyring after a rotation.
+type RotateResult struct {
+	// DEKID is the ID of the active DEK.
+	DEKID uint32
+	// KEKID is the ID of the KEK wrapping the DEKs.
+	KEKID string
+}
+
+// Rotate wraps the DEKs of the keyring of be with the current KEK of the
+// provider, so that the previous KEKs can be retired. If reencrypt is set, a
+// new DEK is generated and all the values are re-encrypted with it by
+// defragmenting be, after which the previous DEKs are removed from the
+// keyring.
+func (c *Codec) Rotate(ctx context.Context, be backend.Backend, reencrypt bool) (RotateResult, error) {
+	if !c.rotating.TryLock() {
+		return RotateResult{}, ErrRotationRunning
+	}
+	defer c.rotating.Unlock()
+
+	c.mu.RLock()
+	keys := append([]wrappedKey(nil), c.ring.Keys...)
+	c.mu.RUnlock()
+	var kekID string
+	for i, k := range keys {
+		c.mu.RLock()
+		d := c.deks[k.ID]
+		c.mu.RUnlock()
+		wrapped, id, err := c.provider.Wrap(ctx, d.key)
+		if err != nil {
+			return RotateResult{}, fmt.Errorf("encryption: failed to wrap data encryption key %d: %w", k.ID, err)
+		}
+		keys[i].Wrapped, keys[i].KEKID, kekID = wrapped, id, id
+	}
+	c.mu.Lock()
+	c.ring.Keys = keys
+	c.mu.Unlock()
+	if !reencrypt {
+		if err := c.save(be); err != nil {
+			return RotateResult{}, err
+		}
+		c.lg.Info("rewrapped data encryption keys", zap.String("kek-id", kekID), zap.Int("dek-count", len(keys)))
+		return RotateResult{DEKID: c.activeID(), KEKID: kekID}, nil
+	}
+
+	id, err := c.addDEK(ctx)
+	if err != nil {
+		return RotateResult{}, err
+	}
+	// the new DEK must be stored before the values encrypted with it.
+	if err = c.save(be); err != nil {
+		return RotateResult{}, err
+	}
+	c.lg.Info("re-encrypting backend", zap.Uint32("dek-id", id))
+	if err = be.Defrag(); err != nil {
+		return RotateResult{}, fmt.Errorf("encryption: failed to re-encrypt backend: %w", err)
+	}
+	c.mu.Lock()
+	for _, k := range c.ring.Keys {
+		if k.ID == id {
+			kekID = k.KEKID
+			c.ring.Keys = []wrappedKey{k}
+			break
+		}
+	}
+	c.mu.Unlock()
+	if err = c.save(be); err != nil {
+		return RotateResult{}, err
+	}
+	c.lg.Info("re-encrypted backend", zap.Uint32("dek-id", id), zap.String("kek-id", kekID))
+	return RotateResult{DEKID: id, KEKID: kekID}, nil
+}
+
+func (c *Codec) activeID() uint32 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.active
+}
@@ -0,0 +1,281 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	bolt "go.etcd.io/bbolt"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+func writeKEKFile(t *testing.T, path string, ids ...string) {
+	var b bytes.Buffer
+	for _, id := range ids {
+		key := make([]byte, 32)
+		// derive the key from the ID, so that rewriting a file keeps the keys.
+		copy(key, id)
+		b.WriteString(id + ":" + base64.StdEncoding.EncodeToString(key) + "\n")
+	}
+	require.NoError(t, os.WriteFile(path, b.Bytes(), 0o600))
+}
+
+func newTestCodec(t *testing.T, kekPath string) *Codec {
+	p, err := NewFileKEKProvider(kekPath)
+	require.NoError(t, err)
+	return NewCodec(zaptest.NewLogger(t), p)
+}
+
+func openBackend(t *testing.T, path string, c *Codec) backend.Backend {
+	bcfg := backend.DefaultBackendConfig(zaptest.NewLogger(t))
+	bcfg.Path = path
+	bcfg.Codec = c
+	be := backend.New(bcfg)
+	require.NoError(t, c.Load(t.Context(), be))
+	return be
+}
+
+func put(be backend.Backend, bucket backend.Bucket, key, value string) {
+	tx := be.BatchTx()
+	tx.LockOutsideApply()
+	tx.UnsafeCreateBucket(bucket)
+	tx.UnsafePut(bucket, []byte(key), []byte(value))
+	tx.Unlock()
+	be.ForceCommit()
+}
+
+func get(be backend.Backend, bucket backend.Bucket, key string) string {
+	tx := be.ReadTx()
+	tx.RLock()
+	defer tx.RUnlock()
+	_, vs := tx.UnsafeRange(bucket, []byte(key), nil, 0)
+	if len(vs) == 0 {
+		return ""
+	}
+	return string(vs[0])
+}
+
+// stored returns the value of key in bucket as stored in the file at path.
+func stored(t *testing.T, path string, bucket backend.Bucket, key string) []byte {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{ReadOnly: true})
+	require.NoError(t, err)
+	defer db.Close()
+	var v []byte
+	require.NoError(t, db.View(func(tx *bolt.Tx) error {
+		v = bytes.Clone(tx.Bucket(bucket.Name()).Get([]byte(key)))
+		return nil
+	}))
+	return v
+}
+
+func TestCodec(t *testing.T) {
+	kekPath := filepath.Join(t.TempDir(), "kek")
+	writeKEKFile(t, kekPath, "kek1")
+	c := newTestCodec(t, kekPath)
+	be, path := betesting.NewDefaultTmpBackend(t)
+	// a value stored before the encryption is enabled.
+	put(be, schema.Key, "legacy", "\x0aplain")
+	require.NoError(t, be.Close())
+
+	be = openBackend(t, path, c)
+	put(be, schema.Key, "foo", "secret")
+	put(be, schema.Alarm, "alarm", "public")
+	assert.Equal(t, "secret", get(be, schema.Key, "foo"))
+	assert.Equal(t, "\x0aplain", get(be, schema.Key, "legacy"))
+	require.NoError(t, be.Close())
+
+	assert.NotContains(t, string(stored(t, path, schema.Key, "foo")), "secret")
+	assert.Equal(t, "public", string(stored(t, path, schema.Alarm, "alarm")))
+
+	// an encrypted value cannot be moved to another key.
+	_, err := c.Decode(schema.Key.Name(), []byte("bar"), stored(t, path, schema.Key, "foo"))
+	require.ErrorIs(t, err, ErrInvalidValue)
+
+	// the keyring is reloaded by a new codec.
+	be = openBackend(t, path, newTestCodec(t, kekPath))
+	assert.Equal(t, "secret", get(be, schema.Key, "foo"))
+
+	// defragmentation encrypts the values stored before the encryption.
+	require.NoError(t, be.Defrag())
+	assert.Equal(t, "\x0aplain", get(be, schema.Key, "legacy"))
+	require.NoError(t, be.Close())
+	assert.NotContains(t, string(stored(t, path, schema.Key, "legacy")), "plain")
+}
+
+func TestCodecLoadUnknownKEK(t *testing.T) {
+	dir := t.TempDir()
+	writeKEKFile(t, filepath.Join(dir, "kek1"), "kek1")
+	writeKEKFile(t, filepath.Join(dir, "kek2"), "kek2")
+	be, path := betesting.NewDefaultTmpBackend(t)
+	require.NoError(t, be.Close())
+	be = openBackend(t, path, newTestCodec(t, filepath.Join(dir, "kek1")))
+	require.NoError(t, be.Close())
+
+	be = backend.NewDefaultBackend(zaptest.NewLogger(t), path)
+	defer be.Close()
+	err := newTestCodec(t, filepath.Join(dir, "kek2")).Load(t.Context(), be)
+	require.ErrorContains(t, err, `unknown key encryption key "kek1"`)
+}
+
+func TestCodecRotate(t *testing.T) {
+	kekPath := filepath.Join(t.TempDir(), "kek")
+	writeKEKFile(t, kekPath, "kek1")
+	c := newTestCodec(t, kekPath)
+	be, path := betesting.NewDefaultTmpBackend(t)
+	require.NoError(t, be.Close())
+	be = openBackend(t, path, c)
+	put(be, schema.Key, "foo", "secret")
+	dek := c.activeID()
+
+	// rewrap the DEK with a new KEK, then retire the previous KEK.
+	writeKEKFile(t, kekPath, "kek2", "kek1")
+	res, err := c.Rotate(t.Context(), be, false)
+	require.NoError(t, err)
+	assert.Equal(t, RotateResult{DEKID: dek, KEKID: "kek2"}, res)
+	require.NoError(t, be.Close())
+	writeKEKFile(t, kekPath, "kek2")
+	c = newTestCodec(t, kekPath)
+	be = openBackend(t, path, c)
+	assert.Equal(t, "secret", get(be, schema.Key, "foo"))
+
+	// re-encrypt the values with a new DEK, then drop the previous DEK.
+	res, err = c.Rotate(t.Context(), be, true)
+	require.NoError(t, err)
+	assert.NotEqual(t, dek, res.DEKID)
+	assert.Equal(t, "kek2", res.KEKID)
+	assert.Equal(t, "secret", get(be, schema.Key, "foo"))
+	require.NoError(t, be.Close())
+
+	c = newTestCodec(t, kekPath)
+	be = openBackend(t, path, c)
+	defer be.Close()
+	assert.Equal(t, "secret", get(be, schema.Key, "foo"))
+	assert.Len(t, c.ring.Keys, 1)
+	_, ok := c.deks[dek]
+	assert.False(t, ok)
+}
+
+func TestWebhookKEKProvider(t *testing.T) {
+	kek := make([]byte, 32)
+	_, err := rand.Read(kek)
+	require.NoError(t, err)
+	aead, err := kekAEAD(kek)
+	require.NoError(t, err)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req webhookMessage
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		nonce := make([]byte, aead.NonceSize())
+		var resp webhookMessage
+		switch r.URL.Path {
+		case "/kms/wrap":
+			resp = webhookMessage{Ciphertext: aead.Seal(nil, nonce, req.Plaintext, nil), KeyID: "kms-1"}
+		case "/kms/unwrap":
+			pt, oerr := aead.Open(nil, nonce, req.Ciphertext, nil)
+			if req.KeyID != "kms-1" || oerr != nil {
+				http.Error(w, "cannot unwrap", http.StatusBadRequest)
+				return
+			}
+			resp = webhookMessage{Plaintext: pt}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(resp))
+	}))
+	defer srv.Close()
+
+	p := NewWebhookKEKProvider(srv.URL + "/kms/")
+	dek := make([]byte, dekSize)
+	_, err = rand.Read(dek)
+	require.NoError(t, err)
+	wrapped, id, err := p.Wrap(t.Context(), dek)
+	require.NoError(t, err)
+	assert.Equal(t, "kms-1", id)
+	got, err := p.Unwrap(t.Context(), wrapped, id)
+	require.NoError(t, err)
+	assert.Equal(t, dek, got)
+	_, err = p.Unwrap(t.Context(), wrapped, "kms-2")
+	require.ErrorContains(t, err, "400 Bad Request: cannot unwrap")
+}
+
+func TestOpenBackend(t *testing.T) {
+	dir := t.TempDir()
+	kekPath := filepath.Join(dir, "kek")
+	writeKEKFile(t, kekPath, "kek1")
+	p, err := NewFileKEKProvider(kekPath)
+	require.NoError(t, err)
+	lg := zaptest.NewLogger(t)
+
+	// a backend without keyring is opened as is, without creating one.
+	plainPath := filepath.Join(dir, "plain")
+	be := backend.NewDefaultBackend(lg, plainPath)
+	put(be, schema.Key, "foo", "\x0aplain")
+	require.NoError(t, be.Close())
+	be, err = OpenBackend(t.Context(), lg, plainPath, p)
+	require.NoError(t, err)
+	assert.False(t, HasKeyring(be))
+	assert.Equal(t, "\x0aplain", get(be, schema.Key, "foo"))
+	require.NoError(t, be.Close())
+
+	encPath := filepath.Join(dir, "encrypted")
+	be = openBackend(t, encPath, NewCodec(lg, p))
+	put(be, schema.Key, "foo", "secret")
+	require.NoError(t, be.Close())
+
+	_, err = OpenBackend(t.Context(), lg, encPath, nil)
+	require.ErrorIs(t, err, ErrKEKRequired)
+	otherPath := filepath.Join(dir, "other")
+	writeKEKFile(t, otherPath, "kek2")
+	other, err := NewFileKEKProvider(otherPath)
+	require.NoError(t, err)
+	_, err = OpenBackend(t.Context(), lg, encPath, other)
+	require.ErrorContains(t, err, "unknown key encryption key")
+
+	be, err = OpenBackend(t.Context(), lg, encPath, p)
+	require.NoError(t, err)
+	defer be.Close()
+	assert.Equal(t, "secret", get(be, schema.Key, "foo"))
+}
+
+func TestNewKEKProvider(t *testing.T) {
+	kekPath := filepath.Join(t.TempDir(), "kek")
+	writeKEKFile(t, kekPath, "kek1")
+
+	p, err := NewKEKProvider("", "")
+	require.NoError(t, err)
+	assert.Nil(t, p)
+	p, err = NewKEKProvider(kekPath, "")
+	require.NoError(t, err)
+	assert.IsType(t, &fileKEKProvider{}, p)
+	p, err = NewKEKProvider("", "http://127.0.0.1:1")
+	require.NoError(t, err)
+	assert.IsType(t, &webhookKEKProvider{}, p)
+	_, err = NewKEKProvider(kekPath, "http://127.0.0.1:1")
+	require.Error(t, err)
+}
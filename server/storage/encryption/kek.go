@@ -0,0 +1,228 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// KEKProvider wraps the data encryption keys with key encryption keys.
+type KEKProvider interface {
+	// Wrap encrypts dek with the current KEK and returns the ID of the KEK.
+	Wrap(ctx context.Context, dek []byte) (wrapped []byte, kekID string, err error)
+	// Unwrap decrypts dek wrapped with the KEK of ID kekID.
+	Unwrap(ctx context.Context, wrapped []byte, kekID string) ([]byte, error)
+}
+
+// NewKEKProvider returns the provider of the KEKs of the file at kekFile, or
+// of the KMS webhook at kmsURL, and nil if neither is set.
+func NewKEKProvider(kekFile, kmsURL string) (KEKProvider, error) {
+	switch {
+	case kekFile != "" && kmsURL != "":
+		return nil, fmt.Errorf("encryption: a key encryption key file and a KMS URL cannot be both set")
+	case kekFile != "":
+		return NewFileKEKProvider(kekFile)
+	case kmsURL != "":
+		return NewWebhookKEKProvider(kmsURL), nil
+	}
+	return nil, nil
+}
+
+// fileKEKProvider reads its KEKs from a file of "<id>:<base64 key>" lines.
+// The first KEK is the current one, the others are only used to unwrap the
+// DEKs wrapped before a rotation. The file is read on every call, so that
+// the KEKs can be rotated without restarting the member.
+type fileKEKProvider struct {
+	path string
+}
+
+// NewFileKEKProvider returns a provider of the AES-256 KEKs of the file at
+// path, one "<id>:<base64 key>" line per KEK, the current KEK first. Empty
+// lines and lines starting with "#" are ignored.
+func NewFileKEKProvider(path string) (KEKProvider, error) {
+	p := &fileKEKProvider{path: path}
+	if _, _, err := p.keys(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *fileKEKProvider) keys() (current string, keys map[string][]byte, err error) {
+	f, err := os.Open(p.path)
+	if err != nil {
+		return "", nil, err
+	}
+	defer f.Close()
+	keys = make(map[string][]byte)
+	s := bufio.NewScanner(f)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		id, enc, ok := strings.Cut(line, ":")
+		if !ok || id == "" {
+			return "", nil, fmt.Errorf("%s:%d: expected <id>:<base64 key>", p.path, n)
+		}
+		key, derr := base64.StdEncoding.DecodeString(enc)
+		if derr != nil || len(key) != dekSize {
+			return "", nil, fmt.Errorf("%s:%d: key %q is not a base64 encoded 32 bytes key", p.path, n, id)
+		}
+		if current == "" {
+			current = id
+		}
+		keys[id] = key
+	}
+	if err = s.Err(); err != nil {
+		return "", nil, err
+	}
+	if current == "" {
+		return "", nil, fmt.Errorf("%s: no key encryption key", p.path)
+	}
+	return current, keys, nil
+}
+
+func kekAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (p *fileKEKProvider) Wrap(_ context.Context, dek []byte) ([]byte, string, error) {
+	id, keys, err := p.keys()
+	if err != nil {
+		return nil, "", err
+	}
+	aead, err := kekAEAD(keys[id])
+	if err != nil {
+		return nil, "", err
+	}
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(dek)+aead.Overhead())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, "", err
+	}
+	return aead.Seal(nonce, nonce, dek, []byte(id)), id, nil
+}
+
+func (p *fileKEKProvider) Unwrap(_ context.Context, wrapped []byte, kekID string) ([]byte, error) {
+	_, keys, err := p.keys()
+	if err != nil {
+		return nil, err
+	}
+	key, ok := keys[kekID]
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown key encryption key %q", p.path, kekID)
+	}
+	aead, err := kekAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, ErrInvalidValue
+	}
+	return aead.Open(nil, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], []byte(kekID))
+}
+
+// webhookTimeout bounds the requests to a KMS webhook.
+const webhookTimeout = 10 * time.Second
+
+// webhookKEKProvider delegates the wrapping of the DEKs to a KMS exposing
+//
+//	POST <url>/wrap   {"plaintext": <base64>} -> {"ciphertext": <base64>, "keyID": <id>}
+//	POST <url>/unwrap {"ciphertext": <base64>, "keyID": <id>} -> {"plaintext": <base64>}
+//
+// so that the KEKs never leave the KMS.
+type webhookKEKProvider struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookKEKProvider returns a provider wrapping the DEKs with the KMS
+// webhook at url.
+func NewWebhookKEKProvider(url string) KEKProvider {
+	return &webhookKEKProvider{
+		url:    strings.TrimSuffix(url, "/"),
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+type webhookMessage struct {
+	Plaintext  []byte `json:"plaintext,omitempty"`
+	Ciphertext []byte `json:"ciphertext,omitempty"`
+	KeyID      string `json:"keyID,omitempty"`
+}
+
+func (p *webhookKEKProvider) call(ctx context.Context, op string, req webhookMessage) (webhookMessage, error) {
+	var resp webhookMessage
+	body, err := json.Marshal(req)
+	if err != nil {
+		return resp, err
+	}
+	hreq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url+"/"+op, bytes.NewReader(body))
+	if err != nil {
+		return resp, err
+	}
+	hreq.Header.Set("Content-Type", "application/json")
+	hresp, err := p.client.Do(hreq)
+	if err != nil {
+		return resp, err
+	}
+	defer hresp.Body.Close()
+	if hresp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(hresp.Body, 1024))
+		return resp, fmt.Errorf("kms %s: %s: %s", op, hresp.Status, bytes.TrimSpace(msg))
+	}
+	if err = json.NewDecoder(hresp.Body).Decode(&resp); err != nil {
+		return resp, fmt.Errorf("kms %s: %w", op, err)
+	}
+	return resp, nil
+}
+
+func (p *webhookKEKProvider) Wrap(ctx context.Context, dek []byte) ([]byte, string, error) {
+	resp, err := p.call(ctx, "wrap", webhookMessage{Plaintext: dek})
+	if err != nil {
+		return nil, "", err
+	}
+	if len(resp.Ciphertext) == 0 || resp.KeyID == "" {
+		return nil, "", fmt.Errorf("kms wrap: missing ciphertext or keyID")
+	}
+	return resp.Ciphertext, resp.KeyID, nil
+}
+
+func (p *webhookKEKProvider) Unwrap(ctx context.Context, wrapped []byte, kekID string) ([]byte, error) {
+	resp, err := p.call(ctx, "unwrap", webhookMessage{Ciphertext: wrapped, KeyID: kekID})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Plaintext) != dekSize {
+		return nil, fmt.Errorf("kms unwrap: got a %d bytes key, want %d", len(resp.Plaintext), dekSize)
+	}
+	return resp.Plaintext, nil
+}
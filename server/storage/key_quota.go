@@ -0,0 +1,91 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	humanize "github.com/dustin/go-humanize"
+)
+
+const (
+	KeyQuotaPrefix    = "prefix"
+	KeyQuotaNamespace = "namespace"
+	KeyQuotaRole      = "role"
+)
+
+// KeyQuota bounds the keys of a key prefix, of a namespace, or of the key
+// ranges an auth role is permitted to write, so that the writes of a tenant
+// of a shared cluster are rejected before the backend quota is exceeded.
+type KeyQuota struct {
+	// Kind is KeyQuotaPrefix, KeyQuotaNamespace or KeyQuotaRole.
+	Kind string
+	// Name is the key prefix, the namespace, or the role, of the quota.
+	Name string
+	// MaxBytes bounds the bytes of the keys and their values. 0 is no bound.
+	MaxBytes int64
+	// MaxKeys bounds the number of keys. 0 is no bound.
+	MaxKeys int64
+}
+
+func (q KeyQuota) String() string { return q.Kind + ":" + q.Name }
+
+// ParseKeyQuotas parses quotas given as '<prefix|namespace|role>:<name>=<max-bytes>/<max-keys>',
+// where either bound may be left out or 0 for no bound; max-bytes takes the
+// units of humanize, e.g. '64MiB'.
+func ParseKeyQuotas(quotas []string) ([]KeyQuota, error) {
+	var parsed []KeyQuota
+	for _, s := range quotas {
+		kind, rest, ok := strings.Cut(s, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid key quota %q: missing kind", s)
+		}
+		i := strings.LastIndex(rest, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid key quota %q: missing bounds", s)
+		}
+		q := KeyQuota{Kind: strings.ToLower(kind), Name: rest[:i]}
+		switch q.Kind {
+		case KeyQuotaPrefix, KeyQuotaNamespace, KeyQuotaRole:
+		default:
+			return nil, fmt.Errorf("invalid key quota %q: unknown kind %q", s, kind)
+		}
+		if q.Name == "" {
+			return nil, fmt.Errorf("invalid key quota %q: empty %s", s, q.Kind)
+		}
+		maxBytes, maxKeys, _ := strings.Cut(rest[i+1:], "/")
+		if maxBytes != "" {
+			n, err := humanize.ParseBytes(maxBytes)
+			if err != nil {
+				return nil, fmt.Errorf("invalid key quota %q: %w", s, err)
+			}
+			q.MaxBytes = int64(n)
+		}
+		if maxKeys != "" {
+			n, err := strconv.ParseInt(maxKeys, 10, 64)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid key quota %q: invalid max keys %q", s, maxKeys)
+			}
+			q.MaxKeys = n
+		}
+		if q.MaxBytes == 0 && q.MaxKeys == 0 {
+			return nil, fmt.Errorf("invalid key quota %q: no bound", s)
+		}
+		parsed = append(parsed, q)
+	}
+	return parsed, nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeyQuotas(t *testing.T) {
+	quotas, err := ParseKeyQuotas([]string{"prefix:/tenants/a/=64MiB/1000", "ROLE:b=1kB", "prefix:/a=b/=/10", "namespace:c=1MB"})
+	require.NoError(t, err)
+	require.Equal(t, []KeyQuota{
+		{Kind: KeyQuotaPrefix, Name: "/tenants/a/", MaxBytes: 64 << 20, MaxKeys: 1000},
+		{Kind: KeyQuotaRole, Name: "b", MaxBytes: 1000},
+		{Kind: KeyQuotaPrefix, Name: "/a=b/", MaxKeys: 10},
+		{Kind: KeyQuotaNamespace, Name: "c", MaxBytes: 1000 * 1000},
+	}, quotas)
+
+	for _, quota := range []string{
+		"/tenants/a/=64MiB",
+		"prefix:/tenants/a/",
+		"user:a=64MiB",
+		"role:=64MiB",
+		"role:a=",
+		"role:a=0/0",
+		"role:a=64XB",
+		"role:a=/-1",
+	} {
+		_, err = ParseKeyQuotas([]string{quota})
+		require.Errorf(t, err, "expected quota %q to be invalid", quota)
+	}
+}
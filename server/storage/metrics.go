@@ -15,16 +15,55 @@
 package storage
 
 import (
+	"sync/atomic"
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 )
 
-var quotaBackendBytes = prometheus.NewGauge(prometheus.GaugeOpts{
-	Namespace: "etcd",
-	Subsystem: "server",
-	Name:      "quota_backend_bytes",
-	Help:      "Current backend storage quota size in bytes.",
-})
+var (
+	quotaBackendBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "quota_backend_bytes",
+		Help:      "Current backend storage quota size in bytes.",
+	})
+
+	quotaBackendWarningThreshold = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "quota_backend_warning_threshold",
+		Help:      "Highest warning threshold, as a fraction of the backend storage quota, crossed by the backend size. 0 if none is crossed.",
+	})
+
+	// lastSnapshotTime is the unix time in nanoseconds of the last snapshot
+	// saved to disk, or of the storage creation if none was saved since.
+	lastSnapshotTime atomic.Int64
+
+	timeSinceLastSnapshot = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "time_since_last_snapshot_seconds",
+		Help:      "Time elapsed since the last snapshot was saved to disk, or since startup if there was none.",
+	}, func() float64 {
+		t := lastSnapshotTime.Load()
+		if t == 0 {
+			return 0
+		}
+		return time.Since(time.Unix(0, t)).Seconds()
+	})
+
+	walEntriesSinceLastSnapshot = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "wal_entries_since_last_snapshot",
+		Help:      "Number of entries appended to the WAL since the last snapshot was saved to disk, or since startup if there was none.",
+	})
+)
 
 func init() {
 	prometheus.MustRegister(quotaBackendBytes)
+	prometheus.MustRegister(quotaBackendWarningThreshold)
+	prometheus.MustRegister(timeSinceLastSnapshot)
+	prometheus.MustRegister(walEntriesSinceLastSnapshot)
 }
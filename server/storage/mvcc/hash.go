@@ -44,13 +44,22 @@ type kvHasher struct {
 	compactRevision int64
 	revision        int64
 	keep            map[Revision]struct{}
+	// size is the number of bytes written to hash.
+	size int64
 }
 
 func newKVHasher(compactRev, rev int64, keep map[Revision]struct{}) kvHasher {
-	h := crc32.New(crc32.MakeTable(crc32.Castagnoli))
-	h.Write(schema.Key.Name())
+	h := newKVSegmentHasher(compactRev, rev, keep)
+	h.hash.Write(schema.Key.Name())
+	return h
+}
+
+// newKVSegmentHasher returns a hasher for a range of the key bucket that does
+// not start at its first key. Its hash can be appended to the hash of the
+// preceding range with combineKVHashers.
+func newKVSegmentHasher(compactRev, rev int64, keep map[Revision]struct{}) kvHasher {
 	return kvHasher{
-		hash:            h,
+		hash:            crc32.New(crc32.MakeTable(crc32.Castagnoli)),
 		compactRevision: compactRev,
 		revision:        rev,
 		keep:            keep,
@@ -87,12 +96,85 @@ func (h *kvHasher) WriteKeyValue(k, v []byte) {
 
 	h.hash.Write(k)
 	h.hash.Write(v)
+	h.size += int64(len(k) + len(v))
 }
 
 func (h *kvHasher) Hash() KeyValueHash {
 	return KeyValueHash{Hash: h.hash.Sum32(), CompactRevision: h.compactRevision, Revision: h.revision}
 }
 
+// combineKVHashers returns the hash of the key-values written to the given
+// hashers one after the other. All hashers but the first must be segment
+// hashers.
+func combineKVHashers(hs []kvHasher) KeyValueHash {
+	kvh := hs[0].Hash()
+	for _, h := range hs[1:] {
+		kvh.Hash = crc32Combine(crc32.Castagnoli, kvh.Hash, h.hash.Sum32(), h.size)
+	}
+	return kvh
+}
+
+// crc32Combine returns the CRC-32 of two concatenated blocks of data, given
+// the CRC-32 of each block and the length of the second one. It is a port of
+// crc32_combine from zlib.
+func crc32Combine(poly, crc1, crc2 uint32, len2 int64) uint32 {
+	if len2 <= 0 {
+		return crc1
+	}
+
+	even := make([]uint32, 32) // even-power-of-two zeros operator
+	odd := make([]uint32, 32)  // odd-power-of-two zeros operator
+
+	// put operator for one zero bit in odd
+	odd[0] = poly
+	row := uint32(1)
+	for n := 1; n < 32; n++ {
+		odd[n] = row
+		row <<= 1
+	}
+	// put operator for two zero bits in even, then four zero bits in odd
+	gf2MatrixSquare(even, odd)
+	gf2MatrixSquare(odd, even)
+
+	// apply len2 zeros to crc1 (the first square puts the operator for one
+	// zero byte, eight zero bits, in even)
+	for {
+		gf2MatrixSquare(even, odd)
+		if len2&1 != 0 {
+			crc1 = gf2MatrixTimes(even, crc1)
+		}
+		len2 >>= 1
+		if len2 == 0 {
+			break
+		}
+		gf2MatrixSquare(odd, even)
+		if len2&1 != 0 {
+			crc1 = gf2MatrixTimes(odd, crc1)
+		}
+		len2 >>= 1
+		if len2 == 0 {
+			break
+		}
+	}
+	return crc1 ^ crc2
+}
+
+func gf2MatrixTimes(mat []uint32, vec uint32) uint32 {
+	var sum uint32
+	for i := 0; vec != 0; i, vec = i+1, vec>>1 {
+		if vec&1 != 0 {
+			sum ^= mat[i]
+		}
+	}
+	return sum
+}
+
+func gf2MatrixSquare(square, mat []uint32) {
+	for n := 0; n < 32; n++ {
+		square[n] = gf2MatrixTimes(mat, mat[n])
+	}
+}
+
 type KeyValueHash struct {
 	Hash            uint32
 	CompactRevision int64
@@ -17,6 +17,7 @@ package mvcc
 import (
 	"context"
 	"fmt"
+	"hash/crc32"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -141,6 +142,25 @@ func TestCompactionHash(t *testing.T) {
 	testutil.TestCompactionHash(t.Context(), t, hashTestCase{s}, s.cfg.CompactionBatchLimit)
 }
 
+// TestCompactionHashConcurrent ensures compaction with several workers
+// computes the same hash as a single worker.
+func TestCompactionHashConcurrent(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	s := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{CompactionBatchLimit: 7, CompactionWorkers: 4})
+	defer cleanup(s, b)
+
+	testutil.TestCompactionHash(t.Context(), t, hashTestCase{s}, s.cfg.CompactionBatchLimit)
+}
+
+func TestCRC32Combine(t *testing.T) {
+	data := []byte("the quick brown fox jumps over the lazy dog")
+	table := crc32.MakeTable(crc32.Castagnoli)
+	for i := 0; i <= len(data); i++ {
+		crc1, crc2 := crc32.Checksum(data[:i], table), crc32.Checksum(data[i:], table)
+		assert.Equal(t, crc32.Checksum(data, table), crc32Combine(crc32.Castagnoli, crc1, crc2, int64(len(data)-i)), "split at %d", i)
+	}
+}
+
 type hashTestCase struct {
 	*store
 }
@@ -0,0 +1,129 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvcc
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// expiredKeyRetryInterval is the time after which a key returned by
+// ExpiredKeys is returned again if it has not been deleted meanwhile.
+const expiredKeyRetryInterval = 3 * time.Second
+
+// ExpiredKey is a key whose ttl elapsed, with the revision of the put that
+// set the ttl.
+type ExpiredKey struct {
+	Key         []byte
+	ModRevision int64
+}
+
+type expiringKey struct {
+	key    string
+	modRev int64
+	time   time.Time
+	index  int
+}
+
+type expiringKeyQueue []*expiringKey
+
+func (pq expiringKeyQueue) Len() int { return len(pq) }
+
+func (pq expiringKeyQueue) Less(i, j int) bool {
+	return pq[i].time.Before(pq[j].time)
+}
+
+func (pq expiringKeyQueue) Swap(i, j int) {
+	pq[i], pq[j] = pq[j], pq[i]
+	pq[i].index = i
+	pq[j].index = j
+}
+
+func (pq *expiringKeyQueue) Push(x any) {
+	item := x.(*expiringKey)
+	item.index = len(*pq)
+	*pq = append(*pq, item)
+}
+
+func (pq *expiringKeyQueue) Pop() any {
+	old := *pq
+	n := len(old)
+	item := old[n-1]
+	item.index = -1 // for safety
+	*pq = old[0 : n-1]
+	return item
+}
+
+// keyExpiry tracks the deadlines of the keys put with a ttl. The deadlines
+// are local to the member: they start when the member applies the put, or
+// restores the key from the backend, so a key never expires before its ttl
+// elapsed on the member deleting it.
+type keyExpiry struct {
+	mu    sync.Mutex
+	m     map[string]*expiringKey
+	queue expiringKeyQueue
+}
+
+func newKeyExpiry() *keyExpiry {
+	return &keyExpiry{m: make(map[string]*expiringKey)}
+}
+
+// track sets the deadline of key, put at modRev with the given ttl in
+// seconds.
+func (ke *keyExpiry) track(key string, modRev, ttl int64) {
+	t := time.Now().Add(time.Duration(ttl) * time.Second)
+	ke.mu.Lock()
+	defer ke.mu.Unlock()
+	if old, ok := ke.m[key]; ok {
+		old.modRev, old.time = modRev, t
+		heap.Fix(&ke.queue, old.index)
+		return
+	}
+	item := &expiringKey{key: key, modRev: modRev, time: t}
+	heap.Push(&ke.queue, item)
+	ke.m[key] = item
+}
+
+func (ke *keyExpiry) untrack(key string) {
+	ke.mu.Lock()
+	defer ke.mu.Unlock()
+	if item, ok := ke.m[key]; ok {
+		heap.Remove(&ke.queue, item.index)
+		delete(ke.m, key)
+	}
+}
+
+// expired returns up to limit keys whose deadline passed. They are returned
+// again after expiredKeyRetryInterval unless deleted or put meanwhile.
+func (ke *keyExpiry) expired(limit int) []ExpiredKey {
+	now := time.Now()
+	ke.mu.Lock()
+	defer ke.mu.Unlock()
+	var keys []ExpiredKey
+	for len(keys) < limit && len(ke.queue) > 0 && !ke.queue[0].time.After(now) {
+		item := ke.queue[0]
+		keys = append(keys, ExpiredKey{Key: []byte(item.key), ModRevision: item.modRev})
+		item.time = now.Add(expiredKeyRetryInterval)
+		heap.Fix(&ke.queue, 0)
+	}
+	return keys
+}
+
+func (ke *keyExpiry) len() int {
+	ke.mu.Lock()
+	defer ke.mu.Unlock()
+	return len(ke.m)
+}
@@ -19,6 +19,7 @@ import (
 
 	"go.etcd.io/etcd/api/v3/mvccpb"
 	"go.etcd.io/etcd/pkg/v3/traceutil"
+	"go.etcd.io/etcd/pkg/v3/watermark"
 	"go.etcd.io/etcd/server/v3/lease"
 	"go.etcd.io/etcd/server/v3/storage/backend"
 )
@@ -27,12 +28,16 @@ type RangeOptions struct {
 	Limit int64
 	Rev   int64
 	Count bool
+	// Size, with Count, also sums the sizes of the key-value pairs of the
+	// range as they are encoded in the backend, without decoding them.
+	Size bool
 }
 
 type RangeResult struct {
 	KVs   []mvccpb.KeyValue
 	Rev   int64
 	Count int
+	Size  int64
 }
 
 type ReadView interface {
@@ -79,6 +84,11 @@ type WriteView interface {
 	// A put also increases the rev of the store, and generates one event in the event history.
 	// The returned rev is the current revision of the KV when the operation is executed.
 	Put(key, value []byte, lease lease.LeaseID) (rev int64)
+
+	// PutWithTTL puts the given key, value into the store like Put, with a time to live in
+	// seconds after which the key is reported by ExpiredKeys of the KV. A ttl of 0 means
+	// no expiry.
+	PutWithTTL(key, value []byte, lease lease.LeaseID, ttl int64) (rev int64)
 }
 
 // TxnWrite represents a transaction that can modify the store.
@@ -96,6 +106,9 @@ func (trw *txnReadWrite) DeleteRange(key, end []byte) (n, rev int64) { panic("un
 func (trw *txnReadWrite) Put(key, value []byte, lease lease.LeaseID) (rev int64) {
 	panic("unexpected Put")
 }
+func (trw *txnReadWrite) PutWithTTL(key, value []byte, lease lease.LeaseID, ttl int64) (rev int64) {
+	panic("unexpected PutWithTTL")
+}
 func (trw *txnReadWrite) Changes() []mvccpb.KeyValue { return nil }
 
 func NewReadOnlyTxnWrite(txn TxnRead) TxnWrite { return &txnReadWrite{txn} }
@@ -123,8 +136,25 @@ type KV interface {
 	HashStorage() HashStorage
 
 	// Compact frees all superseded keys with revisions less than rev.
+	// The latest revision of every key that is not deleted at rev is always kept,
+	// no matter how old it is, so keys that are never updated are not removed.
 	Compact(trace *traceutil.Trace, rev int64) (<-chan struct{}, error)
 
+	// CompactEstimate estimates what compacting at rev would delete, without
+	// compacting.
+	CompactEstimate(rev int64) (CompactEstimate, error)
+
+	// ExpiredKeys returns up to limit keys whose ttl elapsed on this member.
+	// The keys are not deleted; a key returned is returned again after a
+	// while unless it is deleted or put meanwhile.
+	ExpiredKeys(limit int) []ExpiredKey
+
+	// Usage returns the number of keys of the range [key, end), and the
+	// bytes of their keys and latest values. A nil end is the single key
+	// key, and an empty end all the keys from key. The first call for a
+	// range reads its keys; it must not be made during a write transaction.
+	Usage(key, end []byte) Usage
+
 	// Commit commits outstanding txns into the underlying backend.
 	Commit()
 
@@ -137,6 +167,9 @@ type KV interface {
 type WatchableKV interface {
 	KV
 	Watchable
+
+	// Watchers tracks the number of active watchers and its high-watermark.
+	Watchers() *watermark.Watermark
 }
 
 // Watchable is the interface that wraps the NewWatchStream function.
@@ -257,6 +257,43 @@ func testKVRangeLimit(t *testing.T, f rangeFunc) {
 	}
 }
 
+func TestKVRangeSize(t *testing.T)    { testKVRangeSize(t, normalRangeFunc) }
+func TestKVTxnRangeSize(t *testing.T) { testKVRangeSize(t, txnRangeFunc) }
+
+func testKVRangeSize(t *testing.T, f rangeFunc) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	s := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	defer cleanup(s, b)
+
+	kvs := put3TestKVs(s)
+	var wsize int64
+	for i := range kvs[:2] {
+		wsize += int64(kvs[i].Size())
+	}
+
+	r, err := f(s, []byte("foo"), []byte("foo2"), RangeOptions{Count: true, Size: true})
+	if err != nil {
+		t.Fatalf("range error (%v)", err)
+	}
+	if len(r.KVs) != 0 {
+		t.Errorf("kvs = %+v, want none", r.KVs)
+	}
+	if r.Count != 2 {
+		t.Errorf("count = %d, want 2", r.Count)
+	}
+	if r.Size != wsize {
+		t.Errorf("size = %d, want %d", r.Size, wsize)
+	}
+
+	r, err = f(s, []byte("foo"), []byte("foo3"), RangeOptions{Count: true, Size: true, Rev: 2})
+	if err != nil {
+		t.Fatalf("range error (%v)", err)
+	}
+	if r.Count != 1 || r.Size != int64(kvs[0].Size()) {
+		t.Errorf("count, size = %d, %d, want 1, %d", r.Count, r.Size, kvs[0].Size())
+	}
+}
+
 func TestKVPutMultipleTimes(t *testing.T)    { testKVPutMultipleTimes(t, normalPutFunc) }
 func TestKVTxnPutMultipleTimes(t *testing.T) { testKVPutMultipleTimes(t, txnPutFunc) }
 
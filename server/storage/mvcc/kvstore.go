@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
@@ -42,11 +43,29 @@ var (
 	restoreChunkKeys               = 10000 // non-const for testing
 	defaultCompactionBatchLimit    = 1000
 	defaultCompactionSleepInterval = 10 * time.Millisecond
+	maxCompactionSleepInterval     = time.Second
 )
 
 type StoreConfig struct {
 	CompactionBatchLimit    int
 	CompactionSleepInterval time.Duration
+	// CompactionTargetCommitLatency is the latency of the compaction batch
+	// commits above which the sleep between the batches grows from
+	// CompactionSleepInterval. 0 keeps the sleep fixed.
+	CompactionTargetCommitLatency time.Duration
+	// CompactionWorkers is the number of workers compacting disjoint revision
+	// ranges of the backend concurrently while the store serves no foreground
+	// transactions. Under foreground load compaction falls back to a single
+	// worker.
+	CompactionWorkers int
+	// SlowWatcherMaxBacklog is the number of revisions a watcher failing to
+	// receive events may fall behind the store before SlowWatcherPolicy is
+	// applied to it. 0 disables the limit.
+	SlowWatcherMaxBacklog int64
+	SlowWatcherPolicy     SlowWatcherPolicy
+	// MetricsKeyPrefixes are the key prefixes whose number of keys and size
+	// of values are reported by the prefix gauges.
+	MetricsKeyPrefixes []string
 }
 
 type store struct {
@@ -62,6 +81,10 @@ type store struct {
 	kvindex index
 
 	le lease.Lessor
+	// expiry tracks the keys put with a ttl.
+	expiry *keyExpiry
+	// usage tracks the usage of the key ranges asked for by Usage.
+	usage *usageTracker
 
 	// revMuLock protects currentRev and compactMainRev.
 	// Locked at end of write txn and released after write txn unlock lock.
@@ -74,6 +97,10 @@ type store struct {
 
 	fifoSched schedule.Scheduler
 
+	// foregroundTxns counts the read and write transactions served by the
+	// store, so that compaction can back off under foreground load.
+	foregroundTxns atomic.Int64
+
 	stopc chan struct{}
 
 	lg     *zap.Logger
@@ -93,12 +120,17 @@ func NewStore(lg *zap.Logger, b backend.Backend, le lease.Lessor, cfg StoreConfi
 	if cfg.CompactionSleepInterval == 0 {
 		cfg.CompactionSleepInterval = defaultCompactionSleepInterval
 	}
+	if cfg.CompactionWorkers == 0 {
+		cfg.CompactionWorkers = 1
+	}
 	s := &store{
 		cfg:     cfg,
 		b:       b,
 		kvindex: newTreeIndex(lg),
 
-		le: le,
+		le:     le,
+		expiry: newKeyExpiry(),
+		usage:  newUsageTracker(),
 
 		currentRev:     1,
 		compactMainRev: -1,
@@ -297,6 +329,8 @@ func (s *store) Restore(b backend.Backend) error {
 
 	s.b = b
 	s.kvindex = newTreeIndex(s.lg)
+	s.expiry = newKeyExpiry()
+	s.usage = newUsageTracker()
 
 	{
 		// During restore the metrics might report 'special' values
@@ -321,6 +355,7 @@ func (s *store) restore() error {
 	max = RevToBytes(Revision{Main: math.MaxInt64, Sub: math.MaxInt64}, max)
 
 	keyToLease := make(map[string]lease.LeaseID)
+	keyToTTL := make(map[string]mvccpb.KeyValue)
 
 	// restore index
 	tx := s.b.ReadTx()
@@ -349,7 +384,7 @@ func (s *store) restore() error {
 		}
 		// rkvc blocks if the total pending keys exceeds the restore
 		// chunk size to keep keys from consuming too much memory.
-		restoreChunk(s.lg, rkvc, keys, vals, keyToLease)
+		restoreChunk(s.lg, rkvc, keys, vals, keyToLease, keyToTTL)
 		if len(keys) < restoreChunkKeys {
 			// partial set implies final set
 			break
@@ -389,6 +424,11 @@ func (s *store) restore() error {
 		scheduledCompact = 0
 	}
 
+	// the ttl of the restored keys starts over
+	for key, kv := range keyToTTL {
+		s.expiry.track(key, kv.ModRevision, kv.Ttl)
+	}
+
 	for key, lid := range keyToLease {
 		if s.le == nil {
 			tx.RUnlock()
@@ -407,6 +447,8 @@ func (s *store) restore() error {
 
 	s.lg.Info("kvstore restored", zap.Int64("current-rev", s.currentRev))
 
+	s.trackPrefixUsage()
+
 	if scheduledCompact != 0 {
 		if _, err := s.compactLockfree(scheduledCompact); err != nil {
 			s.lg.Warn("compaction encountered error",
@@ -488,7 +530,7 @@ func restoreIntoIndex(lg *zap.Logger, idx index) (chan<- revKeyValue, <-chan int
 	return rkvc, revc
 }
 
-func restoreChunk(lg *zap.Logger, kvc chan<- revKeyValue, keys, vals [][]byte, keyToLease map[string]lease.LeaseID) {
+func restoreChunk(lg *zap.Logger, kvc chan<- revKeyValue, keys, vals [][]byte, keyToLease map[string]lease.LeaseID, keyToTTL map[string]mvccpb.KeyValue) {
 	for i, key := range keys {
 		rkv := revKeyValue{key: key}
 		if err := rkv.kv.Unmarshal(vals[i]); err != nil {
@@ -502,10 +544,21 @@ func restoreChunk(lg *zap.Logger, kvc chan<- revKeyValue, keys, vals [][]byte, k
 		} else {
 			delete(keyToLease, rkv.kstr)
 		}
+		if !isTombstone(key) && rkv.kv.Ttl > 0 {
+			keyToTTL[rkv.kstr] = mvccpb.KeyValue{ModRevision: rkv.kv.ModRevision, Ttl: rkv.kv.Ttl}
+		} else {
+			delete(keyToTTL, rkv.kstr)
+		}
 		kvc <- rkv
 	}
 }
 
+func (s *store) ExpiredKeys(limit int) []ExpiredKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.expiry.expired(limit)
+}
+
 func (s *store) Close() error {
 	close(s.stopc)
 	s.fifoSched.Stop()
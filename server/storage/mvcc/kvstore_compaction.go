@@ -16,7 +16,8 @@ package mvcc
 
 import (
 	"encoding/binary"
-	"fmt"
+	"errors"
+	"sync"
 	"time"
 
 	humanize "github.com/dustin/go-humanize"
@@ -36,6 +37,15 @@ func (s *store) scheduleCompaction(compactMainRev, prevCompactRev int64) (KeyVal
 	defer func() { dbCompactionKeysCounter.Add(float64(keyCompactions)) }()
 	defer func() { dbCompactionLast.Set(float64(time.Now().Unix())) }()
 
+	sleep := newCompactionBackoff(s.cfg.CompactionSleepInterval, s.cfg.CompactionTargetCommitLatency)
+	if s.cfg.CompactionWorkers > 1 {
+		hash, err := s.compactConcurrently(compactMainRev, prevCompactRev, keep, sleep, &keyCompactions)
+		if err == nil {
+			s.logFinishedCompaction(compactMainRev, totalStart, hash)
+		}
+		return hash, err
+	}
+
 	end := make([]byte, 8)
 	binary.BigEndian.PutUint64(end, uint64(compactMainRev+1))
 
@@ -67,17 +77,7 @@ func (s *store) scheduleCompaction(compactMainRev, prevCompactRev int64) (KeyVal
 			dbCompactionPauseMs.Observe(float64(time.Since(start) / time.Millisecond))
 			// gofail: var compactAfterSetFinishedCompact struct{}
 			hash := h.Hash()
-			size, sizeInUse := s.b.Size(), s.b.SizeInUse()
-			s.lg.Info(
-				"finished scheduled compaction",
-				zap.Int64("compact-revision", compactMainRev),
-				zap.Duration("took", time.Since(totalStart)),
-				zap.Uint32("hash", hash.Hash),
-				zap.Int64("current-db-size-bytes", size),
-				zap.String("current-db-size", humanize.Bytes(uint64(size))),
-				zap.Int64("current-db-size-in-use-bytes", sizeInUse),
-				zap.String("current-db-size-in-use", humanize.Bytes(uint64(sizeInUse))),
-			)
+			s.logFinishedCompaction(compactMainRev, totalStart, hash)
 			return hash, nil
 		}
 
@@ -86,14 +86,311 @@ func (s *store) scheduleCompaction(compactMainRev, prevCompactRev int64) (KeyVal
 		last = RevToBytes(Revision{Main: rev.Main, Sub: rev.Sub + 1}, last)
 		// Immediately commit the compaction deletes instead of letting them accumulate in the write buffer
 		// gofail: var compactBeforeCommitBatch struct{}
+		commitStart := time.Now()
 		s.b.ForceCommit()
+		sleep.observe(time.Since(commitStart))
 		// gofail: var compactAfterCommitBatch struct{}
 		dbCompactionPauseMs.Observe(float64(time.Since(start) / time.Millisecond))
 
 		select {
-		case <-time.After(s.cfg.CompactionSleepInterval):
+		case <-time.After(sleep.interval()):
 		case <-s.stopc:
-			return KeyValueHash{}, fmt.Errorf("interrupted due to stop signal")
+			return KeyValueHash{}, errCompactionStopped
+		}
+	}
+}
+
+func (s *store) logFinishedCompaction(compactMainRev int64, totalStart time.Time, hash KeyValueHash) {
+	size, sizeInUse := s.b.Size(), s.b.SizeInUse()
+	s.lg.Info(
+		"finished scheduled compaction",
+		zap.Int64("compact-revision", compactMainRev),
+		zap.Duration("took", time.Since(totalStart)),
+		zap.Uint32("hash", hash.Hash),
+		zap.Int64("current-db-size-bytes", size),
+		zap.String("current-db-size", humanize.Bytes(uint64(size))),
+		zap.Int64("current-db-size-in-use-bytes", sizeInUse),
+		zap.String("current-db-size-in-use", humanize.Bytes(uint64(sizeInUse))),
+	)
+}
+
+var errCompactionStopped = errors.New("interrupted due to stop signal")
+
+// CompactEstimate is the estimated effect of a compaction.
+type CompactEstimate struct {
+	// Revisions is the number of key revisions the compaction deletes.
+	Revisions int64
+	// Bytes is the size of the keys and values of these revisions in the key
+	// bucket. The space is reused by the backend, and released to the file
+	// system by defragmentation.
+	Bytes int64
+}
+
+// CompactEstimate scans the key bucket up to rev like a compaction at rev
+// would, counting the revisions it would delete. The scan reads from a
+// concurrent read transaction in batches of CompactionBatchLimit, so it
+// doesn't block writes.
+func (s *store) CompactEstimate(rev int64) (CompactEstimate, error) {
+	s.mu.RLock()
+	s.revMu.RLock()
+	compactRev, currentRev := s.compactMainRev, s.currentRev
+	s.revMu.RUnlock()
+	if rev <= compactRev {
+		s.mu.RUnlock()
+		return CompactEstimate{}, ErrCompacted
+	}
+	if rev > currentRev {
+		s.mu.RUnlock()
+		return CompactEstimate{}, ErrFutureRev
+	}
+	keep := s.kvindex.Keep(rev)
+	tx := s.b.ConcurrentReadTx()
+	tx.RLock()
+	defer tx.RUnlock()
+	s.mu.RUnlock()
+
+	end := make([]byte, 8)
+	binary.BigEndian.PutUint64(end, uint64(rev+1))
+	last := make([]byte, 8+1+8)
+	batchNum := s.cfg.CompactionBatchLimit
+	var est CompactEstimate
+	for {
+		var rev Revision
+		keys, values := tx.UnsafeRange(schema.Key, last, end, int64(batchNum))
+		for i := range keys {
+			rev = BytesToRev(keys[i])
+			if _, ok := keep[rev]; !ok {
+				est.Revisions++
+				est.Bytes += int64(len(keys[i]) + len(values[i]))
+			}
+		}
+		if len(keys) < batchNum {
+			return est, nil
+		}
+		last = RevToBytes(Revision{Main: rev.Main, Sub: rev.Sub + 1}, last)
+	}
+}
+
+// compactConcurrently compacts the key bucket up to compactMainRev with
+// CompactionWorkers workers, each compacting its own range of revisions.
+func (s *store) compactConcurrently(compactMainRev, prevCompactRev int64, keep map[Revision]struct{}, sleep *compactionBackoff, keyCompactions *int) (KeyValueHash, error) {
+	bounds := compactionBounds(prevCompactRev, compactMainRev, s.cfg.CompactionWorkers)
+	workers := len(bounds) - 1
+	pacer := newCompactionPacer(sleep, workers, s.foregroundTxns.Load)
+
+	hashers := make([]kvHasher, workers)
+	counts := make([]int, workers)
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		if i == 0 {
+			hashers[i] = newKVHasher(prevCompactRev, compactMainRev, keep)
+		} else {
+			hashers[i] = newKVSegmentHasher(prevCompactRev, compactMainRev, keep)
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer pacer.done(i)
+			counts[i], errs[i] = s.compactRange(bounds[i], bounds[i+1], keep, &hashers[i], pacer, i)
+		}(i)
+	}
+	wg.Wait()
+	for _, n := range counts {
+		*keyCompactions += n
+	}
+	for _, err := range errs {
+		if err != nil {
+			return KeyValueHash{}, err
+		}
+	}
+
+	start := time.Now()
+	tx := s.b.BatchTx()
+	tx.LockOutsideApply()
+	UnsafeSetFinishedCompact(tx, compactMainRev)
+	tx.Unlock()
+	dbCompactionPauseMs.Observe(float64(time.Since(start) / time.Millisecond))
+	return combineKVHashers(hashers), nil
+}
+
+// compactionBounds splits the main revisions up to compactMainRev into at most
+// workers ranges. The first range also holds the revisions kept by previous
+// compactions, the others evenly split the revisions compacted this time.
+func compactionBounds(prevCompactRev, compactMainRev int64, workers int) []int64 {
+	from := max(prevCompactRev, 0) + 1
+	span := compactMainRev + 1 - from
+	if int64(workers) > span {
+		workers = int(max(span, 1))
+	}
+	bounds := make([]int64, workers+1)
+	for i := 1; i < workers; i++ {
+		bounds[i] = from + span*int64(i)/int64(workers)
+	}
+	bounds[workers] = compactMainRev + 1
+	return bounds
+}
+
+// compactRange deletes the revisions of the key bucket with a main revision
+// in [fromRev, toRev) that are not kept, in batches of CompactionBatchLimit.
+func (s *store) compactRange(fromRev, toRev int64, keep map[Revision]struct{}, h *kvHasher, pacer *compactionPacer, worker int) (int, error) {
+	last := RevToBytes(Revision{Main: fromRev}, make([]byte, 8+1+8))
+	end := make([]byte, 8)
+	binary.BigEndian.PutUint64(end, uint64(toRev))
+
+	batchNum := s.cfg.CompactionBatchLimit
+	keyCompactions := 0
+	for batch := 0; ; batch++ {
+		if err := pacer.wait(worker, batch == 0, s.stopc); err != nil {
+			return keyCompactions, err
+		}
+
+		var rev Revision
+		start := time.Now()
+
+		tx := s.b.BatchTx()
+		tx.LockOutsideApply()
+		keys, values := tx.UnsafeRange(schema.Key, last, end, int64(batchNum))
+		for i := range keys {
+			rev = BytesToRev(keys[i])
+			if _, ok := keep[rev]; !ok {
+				tx.UnsafeDelete(schema.Key, keys[i])
+				keyCompactions++
+			}
+			h.WriteKeyValue(keys[i], values[i])
+		}
+		tx.Unlock()
+
+		if len(keys) < batchNum {
+			dbCompactionPauseMs.Observe(float64(time.Since(start) / time.Millisecond))
+			return keyCompactions, nil
+		}
+
+		last = RevToBytes(Revision{Main: rev.Main, Sub: rev.Sub + 1}, last)
+		commitStart := time.Now()
+		s.b.ForceCommit()
+		pacer.sleep.observe(time.Since(commitStart))
+		dbCompactionPauseMs.Observe(float64(time.Since(start) / time.Millisecond))
+	}
+}
+
+// compactionPacer spaces the batches of the compaction workers by the
+// compaction sleep interval. While the store serves no foreground
+// transactions all workers compact concurrently; as soon as it does, only
+// the lowest numbered worker still running carries on, so that compaction
+// falls back to the pace of a single worker. Compaction starts at that pace
+// until a sleep interval passed without foreground transactions.
+type compactionPacer struct {
+	sleep *compactionBackoff
+	txns  func() int64
+
+	mu        sync.Mutex
+	running   []bool
+	lastTxns  int64
+	lastCheck time.Time
+	loaded    bool
+}
+
+func newCompactionPacer(sleep *compactionBackoff, workers int, txns func() int64) *compactionPacer {
+	running := make([]bool, workers)
+	for i := range running {
+		running[i] = true
+	}
+	return &compactionPacer{
+		sleep:     sleep,
+		txns:      txns,
+		running:   running,
+		lastTxns:  txns(),
+		lastCheck: time.Now(),
+		loaded:    true,
+	}
+}
+
+// wait blocks the worker until it may compact its next batch. The first
+// batch of a worker does not wait for the sleep interval.
+func (p *compactionPacer) wait(worker int, first bool, stopc <-chan struct{}) error {
+	if first && p.mayRun(worker) {
+		return nil
+	}
+	for {
+		select {
+		case <-time.After(p.sleep.interval()):
+		case <-stopc:
+			return errCompactionStopped
+		}
+		if p.mayRun(worker) {
+			return nil
 		}
 	}
 }
+
+func (p *compactionPacer) mayRun(worker int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if now := time.Now(); now.Sub(p.lastCheck) >= p.sleep.interval() {
+		txns := p.txns()
+		p.loaded = txns != p.lastTxns
+		p.lastTxns, p.lastCheck = txns, now
+	}
+	if !p.loaded {
+		return true
+	}
+	for i := 0; i < worker; i++ {
+		if p.running[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// done marks the worker as finished, letting the next worker carry on under
+// foreground load.
+func (p *compactionPacer) done(worker int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.running[worker] = false
+}
+
+// compactionBackoff is the sleep between compaction batches. With a target
+// commit latency, the sleep adapts to the time the backend takes to commit
+// the batches: it doubles, up to maxCompactionSleepInterval, after a commit
+// slower than the target, and halves back to the configured interval after
+// a faster one. Slow commits mean the disk is saturated, and the foreground
+// requests waiting on it see the latency of the compaction.
+type compactionBackoff struct {
+	min, max time.Duration
+	target   time.Duration
+
+	mu  sync.Mutex
+	cur time.Duration
+}
+
+func newCompactionBackoff(interval, target time.Duration) *compactionBackoff {
+	return &compactionBackoff{
+		min:    interval,
+		max:    max(interval, maxCompactionSleepInterval),
+		target: target,
+		cur:    interval,
+	}
+}
+
+func (b *compactionBackoff) interval() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.cur
+}
+
+// observe adapts the sleep to the latency of a batch commit.
+func (b *compactionBackoff) observe(commit time.Duration) {
+	if b.target <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if commit > b.target {
+		b.cur = min(max(2*b.cur, time.Millisecond), b.max)
+	} else {
+		b.cur = max(b.cur/2, b.min)
+	}
+	dbCompactionSleepMs.Set(float64(b.cur) / float64(time.Millisecond))
+}
@@ -15,14 +15,19 @@
 package mvcc
 
 import (
+	"errors"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
 
 	"go.etcd.io/etcd/pkg/v3/traceutil"
 	"go.etcd.io/etcd/server/v3/lease"
+	"go.etcd.io/etcd/server/v3/storage/backend"
 	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
 	"go.etcd.io/etcd/server/v3/storage/schema"
 )
@@ -147,3 +152,273 @@ func TestCompactAllAndRestore(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+// TestCompactKeepsStaleLiveKey ensures that the only version of a key that was
+// written long before the compaction revision survives an aggressive compaction,
+// while superseded and deleted versions are removed.
+func TestCompactKeepsStaleLiveKey(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	s0 := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	defer b.Close()
+
+	s0.Put([]byte("stale"), []byte("v"), lease.NoLease)
+	s0.Put([]byte("deleted"), []byte("v"), lease.NoLease)
+	s0.DeleteRange([]byte("deleted"), nil)
+	for i := 0; i < 100; i++ {
+		s0.Put([]byte("hot"), []byte{byte(i)}, lease.NoLease)
+	}
+
+	rev := s0.Rev()
+	done, err := s0.Compact(traceutil.TODO(), rev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for compaction to finish")
+	}
+
+	check := func(s *store) {
+		r, err := s.Range(t.Context(), []byte("stale"), nil, RangeOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r.KVs) != 1 || r.KVs[0].ModRevision != 2 || string(r.KVs[0].Value) != "v" {
+			t.Errorf("stale key = %+v, want the version written at revision 2", r.KVs)
+		}
+		r, err = s.Range(t.Context(), []byte("deleted"), nil, RangeOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r.KVs) != 0 {
+			t.Errorf("deleted key = %+v, want none", r.KVs)
+		}
+		r, err = s.Range(t.Context(), []byte("hot"), nil, RangeOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r.KVs) != 1 || r.KVs[0].ModRevision != rev || r.KVs[0].Version != 100 {
+			t.Errorf("hot key = %+v, want the version written at revision %d", r.KVs, rev)
+		}
+		if _, err = s.Range(t.Context(), []byte("hot"), nil, RangeOptions{Rev: rev - 1}); !errors.Is(err, ErrCompacted) {
+			t.Errorf("range at compacted revision error = %v, want %v", err, ErrCompacted)
+		}
+	}
+	check(s0)
+	if err = s0.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the kept versions must also be restored from the backend.
+	s1 := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	check(s1)
+	if err = s1.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestCompactEstimate ensures the estimate of a compaction counts the
+// revisions and bytes the compaction then deletes.
+func TestCompactEstimate(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	s := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{CompactionBatchLimit: 3})
+	defer b.Close()
+	defer s.Close()
+
+	s.Put([]byte("stale"), []byte("v"), lease.NoLease)
+	s.Put([]byte("deleted"), []byte("v"), lease.NoLease)
+	s.DeleteRange([]byte("deleted"), nil)
+	for i := 0; i < 10; i++ {
+		s.Put([]byte("hot"), []byte{byte(i)}, lease.NoLease)
+	}
+	rev := s.Rev()
+	s.Put([]byte("hot"), []byte("new"), lease.NoLease)
+
+	bucket := func() (n, size int64) {
+		tx := s.b.ReadTx()
+		tx.RLock()
+		defer tx.RUnlock()
+		require.NoError(t, tx.UnsafeForEach(schema.Key, func(k, v []byte) error {
+			n++
+			size += int64(len(k) + len(v))
+			return nil
+		}))
+		return n, size
+	}
+
+	est, err := s.CompactEstimate(rev)
+	require.NoError(t, err)
+	// the revisions of "deleted" and the 9 superseded revisions of "hot".
+	assert.Equal(t, int64(11), est.Revisions)
+	n, size := bucket()
+	// revisions start at 2.
+	assert.Equal(t, s.Rev()-1, n, "the estimate must not compact")
+
+	done, err := s.Compact(traceutil.TODO(), rev)
+	require.NoError(t, err)
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for compaction to finish")
+	}
+	nAfter, sizeAfter := bucket()
+	assert.Equal(t, est.Revisions, n-nAfter)
+	assert.Equal(t, est.Bytes, size-sizeAfter)
+
+	_, err = s.CompactEstimate(rev)
+	require.ErrorIs(t, err, ErrCompacted)
+	_, err = s.CompactEstimate(s.Rev() + 1)
+	require.ErrorIs(t, err, ErrFutureRev)
+}
+
+// TestCompactionWorkersThroughput ensures compaction with several workers
+// finishes in a fraction of the time a single worker takes when the store
+// serves no foreground traffic, and removes the same revisions.
+func TestCompactionWorkersThroughput(t *testing.T) {
+	compact := func(workers int) (time.Duration, *store, backend.Backend) {
+		b, _ := betesting.NewDefaultTmpBackend(t)
+		s := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{
+			CompactionBatchLimit:    10,
+			CompactionSleepInterval: 20 * time.Millisecond,
+			CompactionWorkers:       workers,
+		})
+		for i := 0; i < 400; i++ {
+			s.Put([]byte("foo"), []byte{byte(i)}, lease.NoLease)
+		}
+		start := time.Now()
+		done, err := s.Compact(traceutil.TODO(), s.Rev())
+		require.NoError(t, err)
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("timeout waiting for compaction to finish")
+		}
+		return time.Since(start), s, b
+	}
+
+	single, s1, b1 := compact(1)
+	defer cleanup(s1, b1)
+	concurrent, s4, b4 := compact(4)
+	defer cleanup(s4, b4)
+
+	assert.Lessf(t, concurrent, single/2, "compaction with 4 workers took %v, with 1 worker %v", concurrent, single)
+
+	for _, s := range []*store{s1, s4} {
+		r, err := s.Range(t.Context(), []byte("foo"), nil, RangeOptions{})
+		require.NoError(t, err)
+		require.Len(t, r.KVs, 1)
+		assert.Equal(t, int64(400), r.KVs[0].Version)
+		tx := s.b.ReadTx()
+		tx.RLock()
+		keys, _ := tx.UnsafeRange(schema.Key, RevToBytes(Revision{}, NewRevBytes()), RevToBytes(Revision{Main: s.Rev() + 1}, NewRevBytes()), 0)
+		tx.RUnlock()
+		assert.Len(t, keys, 1)
+	}
+}
+
+// TestCompactionPacerYieldsToLoad ensures only the lowest numbered running
+// worker may compact while the store serves foreground transactions.
+func TestCompactionPacerYieldsToLoad(t *testing.T) {
+	var txns atomic.Int64
+
+	// compaction starts at the pace of a single worker
+	p := newCompactionPacer(newCompactionBackoff(time.Hour, 0), 3, txns.Load)
+	assert.True(t, p.mayRun(0))
+	assert.False(t, p.mayRun(1))
+
+	p = newCompactionPacer(newCompactionBackoff(0, 0), 3, txns.Load)
+	// no foreground traffic, all workers run
+	for i := 0; i < 3; i++ {
+		assert.True(t, p.mayRun(i))
+	}
+
+	// simulate foreground traffic
+	txns.Add(1)
+	assert.False(t, p.mayRun(2))
+	txns.Add(1)
+	assert.False(t, p.mayRun(1))
+	txns.Add(1)
+	assert.True(t, p.mayRun(0))
+
+	// the next worker carries on once the first finishes
+	p.done(0)
+	txns.Add(1)
+	assert.True(t, p.mayRun(1))
+	txns.Add(1)
+	assert.False(t, p.mayRun(2))
+
+	// all workers run again once the traffic stops
+	assert.True(t, p.mayRun(2))
+	assert.True(t, p.mayRun(1))
+}
+
+// TestCompactionBackoff ensures the sleep between compaction batches grows
+// while the batch commits are slower than the target latency.
+func TestCompactionBackoff(t *testing.T) {
+	b := newCompactionBackoff(10*time.Millisecond, 0)
+	b.observe(time.Hour)
+	assert.Equal(t, 10*time.Millisecond, b.interval())
+
+	b = newCompactionBackoff(10*time.Millisecond, 50*time.Millisecond)
+	b.observe(60 * time.Millisecond)
+	assert.Equal(t, 20*time.Millisecond, b.interval())
+	for i := 0; i < 10; i++ {
+		b.observe(time.Second)
+	}
+	assert.Equal(t, maxCompactionSleepInterval, b.interval())
+
+	b.observe(50 * time.Millisecond)
+	assert.Equal(t, maxCompactionSleepInterval/2, b.interval())
+	for i := 0; i < 10; i++ {
+		b.observe(time.Millisecond)
+	}
+	assert.Equal(t, 10*time.Millisecond, b.interval())
+}
+
+// TestCompactionWorkersBackOffUnderLoad ensures compaction with several
+// workers does not go faster than a single worker while the store serves
+// foreground transactions.
+func TestCompactionWorkersBackOffUnderLoad(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	s := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{
+		CompactionBatchLimit:    10,
+		CompactionSleepInterval: 20 * time.Millisecond,
+		CompactionWorkers:       4,
+	})
+	defer cleanup(s, b)
+	for i := 0; i < 200; i++ {
+		s.Put([]byte("foo"), []byte{byte(i)}, lease.NoLease)
+	}
+
+	stopc := make(chan struct{})
+	loadDone := make(chan struct{})
+	go func() {
+		defer close(loadDone)
+		for {
+			select {
+			case <-stopc:
+				return
+			default:
+			}
+			s.Range(t.Context(), []byte("foo"), nil, RangeOptions{})
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	defer func() {
+		close(stopc)
+		<-loadDone
+	}()
+
+	start := time.Now()
+	done, err := s.Compact(traceutil.TODO(), s.Rev())
+	require.NoError(t, err)
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("timeout waiting for compaction to finish")
+	}
+	// 200 revisions in batches of 10 take about 20 sleep intervals at the
+	// pace of a single worker, and 5 with four workers.
+	assert.GreaterOrEqual(t, time.Since(start), 15*20*time.Millisecond)
+}
@@ -16,6 +16,8 @@ package mvcc
 
 import (
 	"bytes"
+	"container/heap"
+	"context"
 	"crypto/rand"
 	"encoding/binary"
 	"errors"
@@ -29,6 +31,9 @@ import (
 	"testing"
 	"time"
 
+	ptestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
 
@@ -730,6 +735,107 @@ func TestTxnPut(t *testing.T) {
 }
 
 // TestConcurrentReadNotBlockingWrite ensures Read does not blocking Write after its creation
+func TestStorePutWithTTL(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	s := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	defer b.Close()
+
+	s.PutWithTTL([]byte("a"), []byte("1"), lease.NoLease, 10)
+	s.PutWithTTL([]byte("b"), []byte("1"), lease.NoLease, 10)
+	s.PutWithTTL([]byte("c"), []byte("1"), lease.NoLease, 10)
+	// putting without a ttl or deleting a key stops its expiry
+	s.Put([]byte("b"), []byte("2"), lease.NoLease)
+	s.DeleteRange([]byte("c"), nil)
+
+	r, err := s.Range(t.Context(), []byte("a"), []byte("d"), RangeOptions{})
+	require.NoError(t, err)
+	require.Len(t, r.KVs, 2)
+	assert.Equal(t, int64(10), r.KVs[0].Ttl)
+	assert.Equal(t, int64(0), r.KVs[1].Ttl)
+	assert.Equal(t, 1, s.expiry.len())
+	assert.Empty(t, s.ExpiredKeys(10))
+
+	expire(s.expiry, "a")
+	assert.Equal(t, []ExpiredKey{{Key: []byte("a"), ModRevision: 2}}, s.ExpiredKeys(10))
+	// the key is returned again only after a while
+	assert.Empty(t, s.ExpiredKeys(10))
+	s.Close()
+
+	// the expiry of the keys is restored with a new deadline
+	ns := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	defer ns.Close()
+	assert.Equal(t, 1, ns.expiry.len())
+	assert.Empty(t, ns.ExpiredKeys(10))
+	expire(ns.expiry, "a")
+	assert.Equal(t, []ExpiredKey{{Key: []byte("a"), ModRevision: 2}}, ns.ExpiredKeys(10))
+}
+
+// expire moves the deadline of key to the past.
+func expire(ke *keyExpiry, key string) {
+	ke.mu.Lock()
+	defer ke.mu.Unlock()
+	ke.m[key].time = time.Now().Add(-time.Second)
+	heap.Fix(&ke.queue, ke.m[key].index)
+}
+
+func TestStoreUsage(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	s := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	defer cleanup(s, b)
+
+	s.Put([]byte("a/1"), []byte("xx"), lease.NoLease)
+	s.Put([]byte("a/2"), []byte("xxxx"), lease.NoLease)
+	s.Put([]byte("b/1"), []byte("x"), lease.NoLease)
+	assert.Equal(t, Usage{Keys: 2, Bytes: 12, ValueBytes: 6}, s.Usage([]byte("a/"), []byte("a0")))
+	assert.Equal(t, Usage{Keys: 3, Bytes: 16, ValueBytes: 7}, s.Usage([]byte("a"), []byte{}))
+	assert.Equal(t, Usage{Keys: 1, Bytes: 4, ValueBytes: 1}, s.Usage([]byte("b/1"), nil))
+
+	// the usage of the tracked ranges follows the puts and deletes.
+	txn := s.Write(traceutil.TODO())
+	txn.Put([]byte("a/1"), []byte("x"), lease.NoLease)
+	txn.Put([]byte("a/1"), []byte("xxx"), lease.NoLease)
+	txn.Put([]byte("a/3"), []byte("x"), lease.NoLease)
+	txn.DeleteRange([]byte("a/2"), nil)
+	txn.End()
+	s.DeleteRange([]byte("b/"), []byte("b0"))
+	assert.Equal(t, Usage{Keys: 2, Bytes: 10, ValueBytes: 4}, s.Usage([]byte("a/"), []byte("a0")))
+	assert.Equal(t, Usage{Keys: 2, Bytes: 10, ValueBytes: 4}, s.Usage([]byte("a"), []byte{}))
+	assert.Equal(t, Usage{}, s.Usage([]byte("b/1"), nil))
+
+	// and is read again once the store is restored.
+	require.NoError(t, s.Restore(b))
+	assert.Equal(t, Usage{Keys: 2, Bytes: 10, ValueBytes: 4}, s.Usage([]byte("a/"), []byte("a0")))
+}
+
+func TestStorePrefixMetrics(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	s := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	s.Put([]byte("tenant1/a"), []byte("xx"), lease.NoLease)
+	s.Put([]byte("tenant2/a"), []byte("x"), lease.NoLease)
+	s.Close()
+
+	// the keys already in the backend are counted once the store is opened.
+	s = NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{MetricsKeyPrefixes: []string{"tenant1/", "tenant3/"}})
+	defer cleanup(s, b)
+	assert.InDelta(t, 1, ptestutil.ToFloat64(prefixKeysGauge.WithLabelValues("tenant1/")), 0)
+	assert.InDelta(t, 2, ptestutil.ToFloat64(prefixValueBytesGauge.WithLabelValues("tenant1/")), 0)
+	assert.InDelta(t, 0, ptestutil.ToFloat64(prefixKeysGauge.WithLabelValues("tenant3/")), 0)
+
+	// and the gauges follow the puts and deletes.
+	s.Put([]byte("tenant1/a"), []byte("xxxx"), lease.NoLease)
+	s.Put([]byte("tenant1/b"), []byte("x"), lease.NoLease)
+	s.Put([]byte("tenant3/a"), []byte("xxx"), lease.NoLease)
+	s.DeleteRange([]byte("tenant2/a"), nil)
+	assert.InDelta(t, 2, ptestutil.ToFloat64(prefixKeysGauge.WithLabelValues("tenant1/")), 0)
+	assert.InDelta(t, 5, ptestutil.ToFloat64(prefixValueBytesGauge.WithLabelValues("tenant1/")), 0)
+	assert.InDelta(t, 1, ptestutil.ToFloat64(prefixKeysGauge.WithLabelValues("tenant3/")), 0)
+	assert.InDelta(t, 3, ptestutil.ToFloat64(prefixValueBytesGauge.WithLabelValues("tenant3/")), 0)
+
+	s.DeleteRange([]byte("tenant1/"), []byte("tenant10"))
+	assert.InDelta(t, 0, ptestutil.ToFloat64(prefixKeysGauge.WithLabelValues("tenant1/")), 0)
+	assert.InDelta(t, 0, ptestutil.ToFloat64(prefixValueBytesGauge.WithLabelValues("tenant1/")), 0)
+}
+
 func TestConcurrentReadNotBlockingWrite(t *testing.T) {
 	b, _ := betesting.NewDefaultTmpBackend(t)
 	s := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
@@ -922,6 +1028,8 @@ func newFakeStore(lg *zap.Logger) *store {
 		},
 		b:              b,
 		le:             &lease.FakeLessor{},
+		expiry:         newKeyExpiry(),
+		usage:          newUsageTracker(),
 		kvindex:        newFakeIndex(),
 		currentRev:     0,
 		compactMainRev: -1,
@@ -997,9 +1105,12 @@ func (b *fakeBackend) Hash(func(bucketName, keyName []byte) bool) (uint32, error
 func (b *fakeBackend) Size() int64                                                { return 0 }
 func (b *fakeBackend) SizeInUse() int64                                           { return 0 }
 func (b *fakeBackend) OpenReadTxN() int64                                         { return 0 }
+func (b *fakeBackend) InflightCommitDuration() time.Duration                      { return 0 }
+func (b *fakeBackend) LinkNextCommit(context.Context)                             {}
 func (b *fakeBackend) Snapshot() backend.Snapshot                                 { return nil }
 func (b *fakeBackend) ForceCommit()                                               {}
 func (b *fakeBackend) Defrag() error                                              { return nil }
+func (b *fakeBackend) DefragStatus() backend.DefragStatus                         { return backend.DefragStatus{} }
 func (b *fakeBackend) Close() error                                               { return nil }
 func (b *fakeBackend) SetTxPostLockInsideApplyHook(func())                        {}
 
@@ -43,6 +43,7 @@ type storeTxnCommon struct {
 }
 
 func (s *store) Read(mode ReadTxMode, trace *traceutil.Trace) TxnRead {
+	s.foregroundTxns.Add(1)
 	s.mu.RLock()
 	s.revMu.RLock()
 	// For read-only workloads, we use shared buffer by copying transaction read buffer
@@ -80,6 +81,9 @@ func (tr *storeTxnCommon) rangeKeys(ctx context.Context, key, end []byte, curRev
 	if rev < tr.s.compactMainRev {
 		return &RangeResult{KVs: nil, Count: -1, Rev: 0}, ErrCompacted
 	}
+	if ro.Count && ro.Size {
+		return tr.sizeKeys(ctx, key, end, rev, curRev)
+	}
 	if ro.Count {
 		total := tr.s.kvindex.CountRevisions(key, end, rev)
 		tr.trace.Step("count revisions from in-memory index tree")
@@ -131,6 +135,29 @@ func (tr *storeTxnCommon) rangeKeys(ctx context.Context, key, end []byte, curRev
 	return &RangeResult{KVs: kvs, Count: total, Rev: curRev}, nil
 }
 
+// sizeKeys counts the keys of the range from the in-memory index tree and
+// sums the sizes of their encoded key-value pairs in the backend.
+func (tr *storeTxnCommon) sizeKeys(ctx context.Context, key, end []byte, rev, curRev int64) (*RangeResult, error) {
+	revpairs, total := tr.s.kvindex.Revisions(key, end, rev, 0)
+	tr.trace.Step("range keys from in-memory index tree")
+	var size int64
+	revBytes := NewRevBytes()
+	for _, revpair := range revpairs {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("sizeKeys: context cancelled: %w", ctx.Err())
+		default:
+		}
+		revBytes = RevToBytes(revpair, revBytes)
+		_, vs := tr.tx.UnsafeRange(schema.Key, revBytes, nil, 0)
+		if len(vs) == 1 {
+			size += int64(len(vs[0]))
+		}
+	}
+	tr.trace.Step("size keys from bolt db")
+	return &RangeResult{KVs: nil, Count: total, Size: size, Rev: curRev}, nil
+}
+
 func (tr *storeTxnRead) End() {
 	tr.tx.RUnlock() // RUnlock signals the end of concurrentReadTx.
 	tr.s.mu.RUnlock()
@@ -145,6 +172,7 @@ type storeTxnWrite struct {
 }
 
 func (s *store) Write(trace *traceutil.Trace) TxnWrite {
+	s.foregroundTxns.Add(1)
 	s.mu.RLock()
 	tx := s.b.BatchTx()
 	tx.LockInsideApply()
@@ -175,7 +203,12 @@ func (tw *storeTxnWrite) DeleteRange(key, end []byte) (int64, int64) {
 }
 
 func (tw *storeTxnWrite) Put(key, value []byte, lease lease.LeaseID) int64 {
-	tw.put(key, value, lease)
+	tw.put(key, value, lease, 0)
+	return tw.beginRev + 1
+}
+
+func (tw *storeTxnWrite) PutWithTTL(key, value []byte, lease lease.LeaseID, ttl int64) int64 {
+	tw.put(key, value, lease, ttl)
 	return tw.beginRev + 1
 }
 
@@ -193,19 +226,26 @@ func (tw *storeTxnWrite) End() {
 	tw.s.mu.RUnlock()
 }
 
-func (tw *storeTxnWrite) put(key, value []byte, leaseID lease.LeaseID) {
+func (tw *storeTxnWrite) put(key, value []byte, leaseID lease.LeaseID, ttl int64) {
 	rev := tw.beginRev + 1
 	c := rev
 	oldLease := lease.NoLease
 
 	// if the key exists before, use its previous created and
 	// get its previous leaseID
-	_, created, ver, err := tw.s.kvindex.Get(key, rev)
+	modified, created, ver, err := tw.s.kvindex.Get(key, rev)
 	if err == nil {
 		c = created.Main
 		oldLease = tw.s.le.GetLease(lease.LeaseItem{Key: string(key)})
 		tw.trace.Step("get key's previous created_revision and leaseID")
 	}
+	if tw.s.usage.tracks(key) {
+		if err == nil {
+			tw.s.usage.update(key, 0, int64(len(value))-tw.valueSize(key, modified))
+		} else {
+			tw.s.usage.update(key, 1, int64(len(key)+len(value)))
+		}
+	}
 	ibytes := NewRevBytes()
 	idxRev := Revision{Main: rev, Sub: int64(len(tw.changes))}
 	ibytes = RevToBytes(idxRev, ibytes)
@@ -218,6 +258,7 @@ func (tw *storeTxnWrite) put(key, value []byte, leaseID lease.LeaseID) {
 		ModRevision:    rev,
 		Version:        ver,
 		Lease:          int64(leaseID),
+		Ttl:            ttl,
 	}
 
 	d, err := kv.Marshal()
@@ -234,6 +275,12 @@ func (tw *storeTxnWrite) put(key, value []byte, leaseID lease.LeaseID) {
 	tw.changes = append(tw.changes, kv)
 	tw.trace.Step("store kv pair into bolt db")
 
+	if ttl > 0 {
+		tw.s.expiry.track(string(key), rev, ttl)
+	} else {
+		tw.s.expiry.untrack(string(key))
+	}
+
 	if oldLease == leaseID {
 		tw.trace.Step("attach lease to kv pair")
 		return
@@ -268,11 +315,14 @@ func (tw *storeTxnWrite) deleteRange(key, end []byte) int64 {
 	if len(tw.changes) > 0 {
 		rrev++
 	}
-	keys, _ := tw.s.kvindex.Range(key, end, rrev)
+	keys, revs := tw.s.kvindex.Range(key, end, rrev)
 	if len(keys) == 0 {
 		return 0
 	}
-	for _, key := range keys {
+	for i, key := range keys {
+		if tw.s.usage.tracks(key) {
+			tw.s.usage.update(key, -1, -int64(len(key))-tw.valueSize(key, revs[i]))
+		}
 		tw.delete(key)
 	}
 	return int64(len(keys))
@@ -303,6 +353,7 @@ func (tw *storeTxnWrite) delete(key []byte) {
 		)
 	}
 	tw.changes = append(tw.changes, kv)
+	tw.s.expiry.untrack(string(key))
 
 	item := lease.LeaseItem{Key: string(key)}
 	leaseID := tw.s.le.GetLease(item)
@@ -93,6 +93,42 @@ var (
 		},
 	)
 
+	watchFanoutSizeHistogram = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "etcd_debugging",
+			Subsystem: "mvcc",
+			Name:      "watch_fanout_size",
+			Help:      "Bucketed histogram of the number of synced watchers sharing the evaluation of an event on the same key or range.",
+
+			// lowest bucket start of upper bound 1 with factor 4
+			// highest bucket start of 1 * 4^9 == 262144
+			Buckets: prometheus.ExponentialBuckets(1, 4, 10),
+		},
+	)
+
+	slowWatcherBacklog = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Namespace: "etcd_debugging",
+			Subsystem: "mvcc",
+			Name:      "slow_watcher_backlog_revisions",
+			Help:      "Bucketed histogram of the revisions slow watchers fell behind the store when failing to receive events.",
+
+			// lowest bucket start of upper bound 1 with factor 4
+			// highest bucket start of 1 * 4^11 == 4194304
+			Buckets: prometheus.ExponentialBuckets(1, 4, 12),
+		},
+	)
+
+	slowWatcherMitigatedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "etcd_debugging",
+			Subsystem: "mvcc",
+			Name:      "slow_watcher_mitigated_total",
+			Help:      "Total number of slow watchers whose backlog exceeded the limit, by mitigation policy.",
+		},
+		[]string{"policy"},
+	)
+
 	totalEventsCounter = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Namespace: "etcd_debugging",
@@ -159,6 +195,15 @@ var (
 		},
 	)
 
+	dbCompactionSleepMs = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "etcd_debugging",
+			Subsystem: "mvcc",
+			Name:      "db_compaction_sleep_interval_milliseconds",
+			Help:      "The sleep between db compaction batches adapted to the batch commit latency.",
+		},
+	)
+
 	dbCompactionKeysCounter = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Namespace: "etcd_debugging",
@@ -277,6 +322,26 @@ var (
 	reportCompactRevMu sync.RWMutex
 	reportCompactRev   = func() float64 { return 0 }
 
+	prefixKeysGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "etcd",
+			Subsystem: "mvcc",
+			Name:      "prefix_keys_total",
+			Help:      "Total number of keys with the prefix, for the prefixes of --metrics-key-prefixes.",
+		},
+		[]string{"prefix"},
+	)
+
+	prefixValueBytesGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: "etcd",
+			Subsystem: "mvcc",
+			Name:      "prefix_value_size_bytes",
+			Help:      "Total size of the values of the keys with the prefix, for the prefixes of --metrics-key-prefixes.",
+		},
+		[]string{"prefix"},
+	)
+
 	totalPutSizeGauge = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Namespace: "etcd_debugging",
@@ -295,6 +360,9 @@ func init() {
 	prometheus.MustRegister(watchStreamGauge)
 	prometheus.MustRegister(watcherGauge)
 	prometheus.MustRegister(slowWatcherGauge)
+	prometheus.MustRegister(slowWatcherBacklog)
+	prometheus.MustRegister(watchFanoutSizeHistogram)
+	prometheus.MustRegister(slowWatcherMitigatedCounter)
 	prometheus.MustRegister(totalEventsCounter)
 	prometheus.MustRegister(pendingEventsGauge)
 	prometheus.MustRegister(indexCompactionPauseMs)
@@ -302,6 +370,7 @@ func init() {
 	prometheus.MustRegister(dbCompactionTotalMs)
 	prometheus.MustRegister(dbCompactionLast)
 	prometheus.MustRegister(dbCompactionKeysCounter)
+	prometheus.MustRegister(dbCompactionSleepMs)
 	prometheus.MustRegister(dbTotalSize)
 	prometheus.MustRegister(dbTotalSizeInUse)
 	prometheus.MustRegister(dbOpenReadTxN)
@@ -310,6 +379,13 @@ func init() {
 	prometheus.MustRegister(currentRev)
 	prometheus.MustRegister(compactRev)
 	prometheus.MustRegister(totalPutSizeGauge)
+	prometheus.MustRegister(prefixKeysGauge)
+	prometheus.MustRegister(prefixValueBytesGauge)
+}
+
+func reportPrefixUsage(prefix string, u Usage) {
+	prefixKeysGauge.WithLabelValues(prefix).Set(float64(u.Keys))
+	prefixValueBytesGauge.WithLabelValues(prefix).Set(float64(u.ValueBytes))
 }
 
 // ReportEventReceived reports that an event is received.
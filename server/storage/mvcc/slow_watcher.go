@@ -0,0 +1,97 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvcc
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// SlowWatcherPolicy is the mitigation applied to a slow watcher whose
+// backlog exceeds StoreConfig.SlowWatcherMaxBacklog.
+type SlowWatcherPolicy string
+
+const (
+	// SlowWatcherThrottle keeps the pending events of the watcher but
+	// retries sending them at most once per slowWatcherRetryInterval.
+	SlowWatcherThrottle SlowWatcherPolicy = "throttle"
+	// SlowWatcherCompact drops the pending events of the watcher and ends
+	// it with a compacted response, so that the client re-reads the state
+	// it missed and watches again from there.
+	SlowWatcherCompact SlowWatcherPolicy = "compact"
+	// SlowWatcherCancel drops the pending events of the watcher and
+	// cancels it.
+	SlowWatcherCancel SlowWatcherPolicy = "cancel"
+)
+
+// slowWatcherRetryInterval is the interval at which the pending events of
+// a throttled watcher are sent again.
+const slowWatcherRetryInterval = time.Second
+
+// ParseSlowWatcherPolicy returns the policy named s; the empty string is
+// the throttle policy.
+func ParseSlowWatcherPolicy(s string) (SlowWatcherPolicy, error) {
+	switch p := SlowWatcherPolicy(s); p {
+	case "":
+		return SlowWatcherThrottle, nil
+	case SlowWatcherThrottle, SlowWatcherCompact, SlowWatcherCancel:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown slow watcher policy %q", s)
+	}
+}
+
+// mitigateVictim applies the slow watcher policy to a victim that failed to
+// receive its pending events, with curRev the current revision of the store.
+// It returns true if the watcher stops being a victim: its events are
+// dropped and w.final is set to the response ending it.
+func (s *watchableStore) mitigateVictim(w *watcher, eb *eventBatch, curRev int64, now time.Time) bool {
+	if len(eb.evs) == 0 {
+		return false
+	}
+	backlog := curRev - eb.evs[0].Kv.ModRevision + 1
+	slowWatcherBacklog.Observe(float64(backlog))
+	limit := s.store.cfg.SlowWatcherMaxBacklog
+	if limit <= 0 || backlog <= limit {
+		return false
+	}
+
+	policy := s.store.cfg.SlowWatcherPolicy
+	switch policy {
+	case SlowWatcherCompact:
+		w.final = &WatchResponse{WatchID: w.id, CompactRevision: curRev + 1}
+	case SlowWatcherCancel:
+		w.final = &WatchResponse{WatchID: w.id, Canceled: true}
+	default:
+		policy = SlowWatcherThrottle
+		if !w.retryAt.IsZero() {
+			// already throttled
+			w.retryAt = now.Add(slowWatcherRetryInterval)
+			return false
+		}
+		w.retryAt = now.Add(slowWatcherRetryInterval)
+	}
+	slowWatcherMitigatedCounter.WithLabelValues(string(policy)).Inc()
+	s.store.lg.Warn(
+		"mitigating slow watcher",
+		zap.Int64("watch-id", int64(w.id)),
+		zap.Int64("backlog-revisions", backlog),
+		zap.Int64("max-backlog-revisions", limit),
+		zap.String("policy", string(policy)),
+	)
+	return w.final != nil
+}
@@ -0,0 +1,224 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvcc
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+// Usage is the number of keys of a key range, and the bytes of their keys and
+// latest values.
+type Usage struct {
+	Keys  int64
+	Bytes int64
+	// ValueBytes is the bytes of the latest values alone.
+	ValueBytes int64
+}
+
+// usageRange is a key range, either the single key of key, or [key, end), an
+// empty end meaning all the keys from key.
+type usageRange struct {
+	key, end string
+	single   bool
+}
+
+func newUsageRange(key, end []byte) usageRange {
+	return usageRange{key: string(key), end: string(end), single: end == nil}
+}
+
+// newPrefixUsageRange returns the range of the keys with the given prefix.
+func newPrefixUsageRange(prefix string) usageRange {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return usageRange{key: prefix, end: string(end[:i+1])}
+		}
+	}
+	// the prefix is all 0xff: all the keys from it.
+	return usageRange{key: prefix}
+}
+
+func (r usageRange) contains(key []byte) bool {
+	k := string(key)
+	if r.single {
+		return k == r.key
+	}
+	return r.key <= k && (r.end == "" || k < r.end)
+}
+
+// usageTracker keeps the usage of the key ranges asked for up to date with the
+// puts and deletes of the store.
+type usageTracker struct {
+	mu     sync.Mutex
+	ranges map[usageRange]*Usage
+	// prefixes are the prefixes of the ranges whose usage is reported by the
+	// prefix gauges.
+	prefixes map[usageRange]string
+}
+
+func newUsageTracker() *usageTracker {
+	return &usageTracker{
+		ranges:   make(map[usageRange]*Usage),
+		prefixes: make(map[usageRange]string),
+	}
+}
+
+func (t *usageTracker) get(r usageRange) (Usage, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	u, ok := t.ranges[r]
+	if !ok {
+		return Usage{}, false
+	}
+	return *u, true
+}
+
+func (t *usageTracker) track(r usageRange, u Usage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ranges[r] = &u
+	if prefix, ok := t.prefixes[r]; ok {
+		reportPrefixUsage(prefix, u)
+	}
+}
+
+// trackPrefix reports the usage of the range of prefix in the prefix gauges
+// once it is tracked.
+func (t *usageTracker) trackPrefix(r usageRange, prefix string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.prefixes[r] = prefix
+}
+
+// tracks reports whether key is in a tracked range, so that the writes out of
+// them don't pay for reading the previous value of the keys.
+func (t *usageTracker) tracks(key []byte) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for r := range t.ranges {
+		if r.contains(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// update adds keys keys and n bytes, the key bytes of the added or removed keys
+// included, to the usage of the ranges containing key.
+func (t *usageTracker) update(key []byte, keys, n int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for r, u := range t.ranges {
+		if r.contains(key) {
+			u.Keys += keys
+			u.Bytes += n
+			u.ValueBytes += n - keys*int64(len(key))
+			if prefix, ok := t.prefixes[r]; ok {
+				reportPrefixUsage(prefix, *u)
+			}
+		}
+	}
+}
+
+// Usage reads the keys of a range the first time it is asked for, and then
+// keeps its usage up to date with the writes.
+func (s *store) Usage(key, end []byte) Usage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r := newUsageRange(key, end)
+	if u, ok := s.usage.get(r); ok {
+		return u
+	}
+	u := s.readUsage(r)
+	s.usage.track(r, u)
+	return u
+}
+
+// trackPrefixUsage reads the usage of the keys of the prefixes of
+// cfg.MetricsKeyPrefixes, which the writes then keep up to date in the prefix
+// gauges.
+func (s *store) trackPrefixUsage() {
+	for _, prefix := range s.cfg.MetricsKeyPrefixes {
+		r := newPrefixUsageRange(prefix)
+		s.usage.trackPrefix(r, prefix)
+		if _, ok := s.usage.get(r); !ok {
+			s.usage.track(r, s.readUsage(r))
+		}
+	}
+}
+
+// readUsage reads the usage of the keys of r as of the current revision.
+func (s *store) readUsage(r usageRange) Usage {
+	key, end := []byte(r.key), []byte(r.end)
+	if r.single {
+		end = nil
+	}
+	s.revMu.RLock()
+	rev := s.currentRev
+	s.revMu.RUnlock()
+	keys, revs := s.kvindex.Range(key, end, rev)
+
+	var u Usage
+	tx := s.b.ReadTx()
+	tx.RLock()
+	rb := NewRevBytes()
+	for i := range keys {
+		rb = RevToBytes(revs[i], rb)
+		_, vs := tx.UnsafeRange(schema.Key, rb, nil, 0)
+		if len(vs) != 1 {
+			s.lg.Fatal(
+				"failed to read the value of a key",
+				zap.String("key", string(keys[i])),
+				zap.Int64("revision-main", revs[i].Main),
+				zap.Int("values", len(vs)),
+			)
+		}
+		var kv mvccpb.KeyValue
+		if err := kv.Unmarshal(vs[0]); err != nil {
+			s.lg.Fatal("failed to unmarshal mvccpb.KeyValue", zap.Error(err))
+		}
+		u.Keys++
+		u.Bytes += int64(len(kv.Key) + len(kv.Value))
+		u.ValueBytes += int64(len(kv.Value))
+	}
+	tx.RUnlock()
+	return u
+}
+
+// valueSize returns the size of the value of key as of the revision rev the
+// index returned for it.
+func (tw *storeTxnWrite) valueSize(key []byte, rev Revision) int64 {
+	_, vs := tw.tx.UnsafeRange(schema.Key, RevToBytes(rev, NewRevBytes()), nil, 0)
+	if len(vs) != 1 {
+		tw.s.lg.Fatal(
+			"failed to read the previous value of a key",
+			zap.String("key", string(key)),
+			zap.Int64("revision-main", rev.Main),
+			zap.Int("values", len(vs)),
+		)
+	}
+	var kv mvccpb.KeyValue
+	if err := kv.Unmarshal(vs[0]); err != nil {
+		tw.s.lg.Fatal("failed to unmarshal mvccpb.KeyValue", zap.Error(err))
+	}
+	return int64(len(kv.Value))
+}
@@ -23,6 +23,7 @@ import (
 	"go.etcd.io/etcd/api/v3/mvccpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/pkg/v3/traceutil"
+	"go.etcd.io/etcd/pkg/v3/watermark"
 	"go.etcd.io/etcd/server/v3/lease"
 	"go.etcd.io/etcd/server/v3/storage/backend"
 	"go.etcd.io/etcd/server/v3/storage/schema"
@@ -66,6 +67,9 @@ type watchableStore struct {
 	// The key of the map is the key that the watcher watches on.
 	synced watcherGroup
 
+	// watchers tracks the number of watchers and its high-watermark.
+	watchers watermark.Watermark
+
 	stopc chan struct{}
 	wg    sync.WaitGroup
 }
@@ -120,6 +124,8 @@ func (s *watchableStore) NewWatchStream() WatchStream {
 	}
 }
 
+func (s *watchableStore) Watchers() *watermark.Watermark { return &s.watchers }
+
 func (s *watchableStore) watch(key, end []byte, startRev int64, id WatchID, ch chan<- WatchResponse, fcs ...FilterFunc) (*watcher, cancelFunc) {
 	wa := &watcher{
 		key:    key,
@@ -147,6 +153,7 @@ func (s *watchableStore) watch(key, end []byte, startRev int64, id WatchID, ch c
 	s.mu.Unlock()
 
 	watcherGauge.Inc()
+	s.watchers.Add(1)
 
 	return wa, func() { s.cancelWatcher(wa) }
 }
@@ -158,15 +165,18 @@ func (s *watchableStore) cancelWatcher(wa *watcher) {
 		if s.unsynced.delete(wa) {
 			slowWatcherGauge.Dec()
 			watcherGauge.Dec()
+			s.watchers.Add(-1)
 			break
 		} else if s.synced.delete(wa) {
 			watcherGauge.Dec()
+			s.watchers.Add(-1)
 			break
 		} else if wa.ch == nil {
 			// already canceled (e.g., cancel/close race)
 			break
 		} else if wa.compacted {
 			watcherGauge.Dec()
+			s.watchers.Add(-1)
 			break
 		}
 
@@ -185,6 +195,7 @@ func (s *watchableStore) cancelWatcher(wa *watcher) {
 		if victimBatch != nil {
 			slowWatcherGauge.Dec()
 			watcherGauge.Dec()
+			s.watchers.Add(-1)
 			delete(victimBatch, wa)
 			break
 		}
@@ -284,19 +295,32 @@ func (s *watchableStore) moveVictims() (moved int) {
 	s.victims = nil
 	s.mu.Unlock()
 
+	now := time.Now()
 	var newVictim watcherBatch
 	for _, wb := range victims {
 		// try to send responses again
 		for w, eb := range wb {
+			if now.Before(w.retryAt) {
+				// throttled; stays victim
+				if newVictim == nil {
+					newVictim = make(watcherBatch)
+				}
+				newVictim[w] = eb
+				continue
+			}
 			// watcher has observed the store up to, but not including, w.minRev
 			rev := w.minRev - 1
 			if !w.send(WatchResponse{WatchID: w.id, Events: eb.evs, Revision: rev}) {
+				if s.mitigateVictim(w, eb, s.rev(), now) {
+					continue
+				}
 				if newVictim == nil {
 					newVictim = make(watcherBatch)
 				}
 				newVictim[w] = eb
 				continue
 			}
+			w.retryAt = time.Time{}
 			pendingEventsGauge.Add(float64(len(eb.evs)))
 			moved++
 		}
@@ -311,6 +335,12 @@ func (s *watchableStore) moveVictims() (moved int) {
 				continue
 			}
 			w.victim = false
+			if w.final != nil {
+				// dropped by the slow watcher policy; the unsynced loop
+				// sends the final response
+				s.unsynced.add(w)
+				continue
+			}
 			if eb.moreRev != 0 {
 				w.minRev = eb.moreRev
 			}
@@ -362,9 +392,9 @@ func (s *watchableStore) syncWatchers(evs []mvccpb.Event) (int, []mvccpb.Event)
 	victims := make(watcherBatch)
 	wb := newWatcherBatch(wg, evs)
 	for w := range wg.watchers {
-		if w.minRev < compactionRev {
-			// Skip the watcher that failed to send compacted watch response due to w.ch is full.
-			// Next retry of syncWatchers would try to resend the compacted watch response to w.ch
+		if w.minRev < compactionRev || w.final != nil {
+			// Skip the watcher that failed to send compacted or final watch response due to w.ch is full.
+			// Next retry of syncWatchers would try to resend the watch response to w.ch
 			continue
 		}
 		w.minRev = curRev + 1
@@ -483,26 +513,35 @@ func kvsToEvents(lg *zap.Logger, revs, vals [][]byte) (evs []mvccpb.Event) {
 // watchers that watch on the key of the event.
 func (s *watchableStore) notify(rev int64, evs []mvccpb.Event) {
 	victim := make(watcherBatch)
-	for w, eb := range newWatcherBatch(&s.synced, evs) {
+	for fo, eb := range newFanoutBatch(&s.synced, evs) {
 		if eb.revs != 1 {
 			s.store.lg.Panic(
 				"unexpected multiple revisions in watch notification",
 				zap.Int("number-of-revisions", eb.revs),
 			)
 		}
-		if w.send(WatchResponse{WatchID: w.id, Events: eb.evs, Revision: rev}) {
-			pendingEventsGauge.Add(float64(len(eb.evs)))
-		} else {
-			// move slow watcher to victims
-			w.victim = true
-			victim[w] = eb
-			s.synced.delete(w)
-			slowWatcherGauge.Inc()
+		watchFanoutSizeHistogram.Observe(float64(len(fo.watchers)))
+		// the events are shared by the watchers of the fanout, and must
+		// not be modified
+		for w := range fo.watchers {
+			if rev < w.minRev {
+				// don't double notify
+				continue
+			}
+			if w.send(WatchResponse{WatchID: w.id, Events: eb.evs, Revision: rev}) {
+				pendingEventsGauge.Add(float64(len(eb.evs)))
+			} else {
+				// move slow watcher to victims
+				w.victim = true
+				victim[w] = eb
+				s.synced.delete(w)
+				slowWatcherGauge.Inc()
+			}
+			// always update minRev
+			// in case 'send' returns true and watcher stays synced, this is needed for Restore when all watchers become unsynced
+			// in case 'send' returns false, this is needed for syncWatchers
+			w.minRev = rev + 1
 		}
-		// always update minRev
-		// in case 'send' returns true and watcher stays synced, this is needed for Restore when all watchers become unsynced
-		// in case 'send' returns false, this is needed for syncWatchers
-		w.minRev = rev + 1
 	}
 	s.addVictim(victim)
 }
@@ -562,8 +601,17 @@ type watcher struct {
 	victim bool
 
 	// compacted is set when the watcher is removed because of compaction
+	// or by the slow watcher policy
 	compacted bool
 
+	// final is the response ending a watcher dropped by the slow watcher
+	// policy, sent once the watch channel has room.
+	final *WatchResponse
+
+	// retryAt is the earliest time a throttled victim is sent its pending
+	// events again.
+	retryAt time.Time
+
 	// restore is true when the watcher is being restored from leader snapshot
 	// which means that this watcher has just been moved from "synced" to "unsynced"
 	// watcher group, possibly with a future revision when it was first added
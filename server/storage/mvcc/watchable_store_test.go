@@ -847,6 +847,51 @@ func TestNewMapwatcherToEventMap(t *testing.T) {
 	}
 }
 
+// TestWatchFanout tests that the synced watchers of the same range share the
+// events matched once for the range, and each apply their filters and start
+// revision.
+func TestWatchFanout(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	s := New(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	defer cleanup(s, b)
+
+	key, end := []byte("foo"), []byte("fop")
+	filterPut := func(e mvccpb.Event) bool { return e.Type == mvccpb.PUT }
+	var streams []WatchStream
+	for i := 0; i < 4; i++ {
+		ws := s.NewWatchStream()
+		defer ws.Close()
+		streams = append(streams, ws)
+	}
+	_, err := streams[0].Watch(0, key, end, 0)
+	require.NoError(t, err)
+	_, err = streams[1].Watch(0, key, end, 0)
+	require.NoError(t, err)
+	_, err = streams[2].Watch(0, key, end, 0, filterPut)
+	require.NoError(t, err)
+	// watches from a future revision, in the synced group
+	_, err = streams[3].Watch(0, key, end, s.Rev()+2)
+	require.NoError(t, err)
+
+	rev := s.Put([]byte("foo1"), []byte("bar"), lease.NoLease)
+	r0, r1 := <-streams[0].Chan(), <-streams[1].Chan()
+	require.Len(t, r0.Events, 1)
+	require.Len(t, r1.Events, 1)
+	assert.Equal(t, rev, r0.Events[0].Kv.ModRevision)
+	assert.Same(t, &r0.Events[0], &r1.Events[0], "the events of the range are matched once")
+
+	s.DeleteRange([]byte("foo1"), nil)
+	for i, ws := range streams {
+		select {
+		case r := <-ws.Chan():
+			require.Len(t, r.Events, 1, "stream %d", i)
+			assert.Equal(t, mvccpb.DELETE, r.Events[0].Type, "stream %d", i)
+		case <-time.After(time.Second):
+			t.Fatalf("stream %d: failed to receive the delete event", i)
+		}
+	}
+}
+
 // TestWatchVictims tests that watchable store delivers watch events
 // when the watch channel is temporarily clogged with too many events.
 func TestWatchVictims(t *testing.T) {
@@ -928,6 +973,70 @@ func TestWatchVictims(t *testing.T) {
 
 // TestStressWatchCancelClose tests closing a watch stream while
 // canceling its watches.
+// TestWatchSlowWatcherPolicy ensures that a victim falling further behind
+// than SlowWatcherMaxBacklog is throttled, compacted or canceled.
+func TestWatchSlowWatcherPolicy(t *testing.T) {
+	tests := []struct {
+		policy SlowWatcherPolicy
+
+		wcompacted bool
+		wcanceled  bool
+	}{
+		{policy: SlowWatcherThrottle},
+		{policy: SlowWatcherCompact, wcompacted: true},
+		{policy: SlowWatcherCancel, wcanceled: true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			oldChanBufLen := chanBufLen
+			defer func() { chanBufLen = oldChanBufLen }()
+			chanBufLen = 1
+
+			b, _ := betesting.NewDefaultTmpBackend(t)
+			s := New(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{SlowWatcherMaxBacklog: 2, SlowWatcherPolicy: tt.policy})
+			defer cleanup(s, b)
+
+			w := s.NewWatchStream()
+			defer w.Close()
+			wt, _ := w.Watch(0, []byte("foo"), nil, 0)
+
+			numPuts := 5
+			for i := 0; i < numPuts; i++ {
+				s.Put([]byte("foo"), []byte("bar"), lease.NoLease)
+			}
+			// let the victim fall behind before draining the channel
+			time.Sleep(100 * time.Millisecond)
+
+			var evs []mvccpb.Event
+			for len(evs) < numPuts {
+				select {
+				case resp := <-w.Chan():
+					require.Equal(t, wt, resp.WatchID)
+					if tt.wcompacted || tt.wcanceled {
+						if resp.CompactRevision == 0 && !resp.Canceled {
+							evs = append(evs, resp.Events...)
+							continue
+						}
+						assert.Equal(t, tt.wcanceled, resp.Canceled)
+						if tt.wcompacted {
+							assert.Greater(t, resp.CompactRevision, int64(numPuts))
+						}
+						assert.Less(t, len(evs), numPuts)
+						return
+					}
+					evs = append(evs, resp.Events...)
+				case <-time.After(5 * time.Second):
+					t.Fatalf("failed to receive response (timeout)")
+				}
+			}
+			require.False(t, tt.wcompacted || tt.wcanceled, "expected the watcher to be ended")
+			for i, ev := range evs {
+				assert.Equal(t, int64(i+2), ev.Kv.ModRevision)
+			}
+		})
+	}
+}
+
 func TestStressWatchCancelClose(t *testing.T) {
 	b, _ := betesting.NewDefaultTmpBackend(t)
 	s := New(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
@@ -92,6 +92,10 @@ type WatchResponse struct {
 
 	// CompactRevision is set when the watcher is cancelled due to compaction.
 	CompactRevision int64
+
+	// Canceled is set when the watcher is cancelled for falling behind the
+	// store.
+	Canceled bool
 }
 
 // watchStream contains a collection of watchers that share
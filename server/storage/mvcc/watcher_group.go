@@ -93,6 +93,29 @@ func newWatcherBatch(wg *watcherGroup, evs []mvccpb.Event) watcherBatch {
 	return wb
 }
 
+// newFanoutBatch maps the fanouts of the group to their matched events. The
+// events of a key or range are matched once, however many watchers watch it.
+func newFanoutBatch(wg *watcherGroup, evs []mvccpb.Event) map[*watcherFanout]*eventBatch {
+	if len(wg.watchers) == 0 {
+		return nil
+	}
+
+	fb := make(map[*watcherFanout]*eventBatch)
+	var fos []*watcherFanout
+	for _, ev := range evs {
+		fos = wg.fanoutsByKey(string(ev.Kv.Key), fos[:0])
+		for _, fo := range fos {
+			eb := fb[fo]
+			if eb == nil {
+				eb = &eventBatch{}
+				fb[fo] = eb
+			}
+			eb.add(ev)
+		}
+	}
+	return fb
+}
+
 type watcherSet map[*watcher]struct{}
 
 func (w watcherSet) add(wa *watcher) {
@@ -115,23 +138,34 @@ func (w watcherSet) delete(wa *watcher) {
 	delete(w, wa)
 }
 
-type watcherSetByKey map[string]watcherSet
+// watcherFanout is the set of watchers of a group watching the same key or
+// range. The events on the key or range are matched once for all of its
+// watchers, and then fanned out to the watch stream of each watcher.
+type watcherFanout struct {
+	watchers watcherSet
+}
+
+func newWatcherFanout() *watcherFanout {
+	return &watcherFanout{watchers: make(watcherSet)}
+}
+
+type watcherSetByKey map[string]*watcherFanout
 
 func (w watcherSetByKey) add(wa *watcher) {
-	set := w[string(wa.key)]
-	if set == nil {
-		set = make(watcherSet)
-		w[string(wa.key)] = set
+	fo := w[string(wa.key)]
+	if fo == nil {
+		fo = newWatcherFanout()
+		w[string(wa.key)] = fo
 	}
-	set.add(wa)
+	fo.watchers.add(wa)
 }
 
 func (w watcherSetByKey) delete(wa *watcher) bool {
 	k := string(wa.key)
-	if v, ok := w[k]; ok {
-		if _, ok := v[wa]; ok {
-			delete(v, wa)
-			if len(v) == 0 {
+	if fo, ok := w[k]; ok {
+		if _, ok := fo.watchers[wa]; ok {
+			delete(fo.watchers, wa)
+			if len(fo.watchers) == 0 {
 				// remove the set; nothing left
 				delete(w, k)
 			}
@@ -170,14 +204,14 @@ func (wg *watcherGroup) add(wa *watcher) {
 	// interval already registered?
 	ivl := adt.NewStringAffineInterval(string(wa.key), string(wa.end))
 	if iv := wg.ranges.Find(ivl); iv != nil {
-		iv.Val.(watcherSet).add(wa)
+		iv.Val.(*watcherFanout).watchers.add(wa)
 		return
 	}
 
 	// not registered, put in interval tree
-	ws := make(watcherSet)
-	ws.add(wa)
-	wg.ranges.Insert(ivl, ws)
+	fo := newWatcherFanout()
+	fo.watchers.add(wa)
+	wg.ranges.Insert(ivl, fo)
 }
 
 // contains is whether the given key has a watcher in the group.
@@ -206,7 +240,7 @@ func (wg *watcherGroup) delete(wa *watcher) bool {
 		return false
 	}
 
-	ws := iv.Val.(watcherSet)
+	ws := iv.Val.(*watcherFanout).watchers
 	delete(ws, wa)
 	if len(ws) == 0 {
 		// remove interval missing watchers
@@ -237,6 +271,16 @@ func (wg *watcherGroup) choose(maxWatchers int, curRev, compactRev int64) (*watc
 func (wg *watcherGroup) chooseAll(curRev, compactRev int64) int64 {
 	minRev := int64(math.MaxInt64)
 	for w := range wg.watchers {
+		if w.final != nil {
+			select {
+			case w.ch <- *w.final:
+				w.compacted = true
+				wg.delete(w)
+			default:
+				// retry next time
+			}
+			continue
+		}
 		if w.minRev > curRev {
 			// after network partition, possibly choosing future revision watcher from restore operation
 			// with watch key "proxy-namespace__lostleader" and revision "math.MaxInt64 - 2"
@@ -267,7 +311,10 @@ func (wg *watcherGroup) chooseAll(curRev, compactRev int64) int64 {
 
 // watcherSetByKey gets the set of watchers that receive events on the given key.
 func (wg *watcherGroup) watcherSetByKey(key string) watcherSet {
-	wkeys := wg.keyWatchers[key]
+	var wkeys watcherSet
+	if fo := wg.keyWatchers[key]; fo != nil {
+		wkeys = fo.watchers
+	}
 	wranges := wg.ranges.Stab(adt.NewStringAffinePoint(key))
 
 	// zero-copy cases
@@ -278,14 +325,26 @@ func (wg *watcherGroup) watcherSetByKey(key string) watcherSet {
 	case len(wranges) == 0 && len(wkeys) == 0:
 		return nil
 	case len(wranges) == 1 && len(wkeys) == 0:
-		return wranges[0].Val.(watcherSet)
+		return wranges[0].Val.(*watcherFanout).watchers
 	}
 
 	// copy case
 	ret := make(watcherSet)
-	ret.union(wg.keyWatchers[key])
+	ret.union(wkeys)
 	for _, item := range wranges {
-		ret.union(item.Val.(watcherSet))
+		ret.union(item.Val.(*watcherFanout).watchers)
 	}
 	return ret
 }
+
+// fanoutsByKey appends the fanouts whose watchers receive events on the
+// given key to fos.
+func (wg *watcherGroup) fanoutsByKey(key string, fos []*watcherFanout) []*watcherFanout {
+	if fo := wg.keyWatchers[key]; fo != nil {
+		fos = append(fos, fo)
+	}
+	for _, item := range wg.ranges.Stab(adt.NewStringAffinePoint(key)) {
+		fos = append(fos, item.Val.(*watcherFanout))
+	}
+	return fos
+}
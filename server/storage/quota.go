@@ -15,6 +15,7 @@
 package storage
 
 import (
+	"slices"
 	"sync"
 
 	humanize "github.com/dustin/go-humanize"
@@ -54,6 +55,9 @@ func (*passthroughQuota) Remaining() int64   { return 1 }
 type BackendQuota struct {
 	be              backend.Backend
 	maxBackendBytes int64
+	// grace checks the quota against the size of the backend in use rather
+	// than its physical size, see quotaSize.
+	grace bool
 }
 
 const (
@@ -71,8 +75,10 @@ var (
 	maxQuotaSize     = humanize.Bytes(uint64(MaxQuotaBytes))
 )
 
-// NewBackendQuota creates a quota layer with the given storage limit.
-func NewBackendQuota(lg *zap.Logger, quotaBackendBytesCfg int64, be backend.Backend, name string) Quota {
+// NewBackendQuota creates a quota layer with the given storage limit. In
+// grace mode, the limit applies to the size of the backend in use, so that
+// the compactions make room for new writes without a defragmentation.
+func NewBackendQuota(lg *zap.Logger, quotaBackendBytesCfg int64, grace bool, be backend.Backend, name string) Quota {
 	quotaBackendBytes.Set(float64(quotaBackendBytesCfg))
 	if quotaBackendBytesCfg < 0 {
 		// disable quotas if negative
@@ -99,7 +105,7 @@ func NewBackendQuota(lg *zap.Logger, quotaBackendBytesCfg int64, be backend.Back
 			}
 		})
 		quotaBackendBytes.Set(float64(DefaultQuotaBytes))
-		return &BackendQuota{be, DefaultQuotaBytes}
+		return &BackendQuota{be, DefaultQuotaBytes, grace}
 	}
 
 	quotaLogOnce.Do(func() {
@@ -120,7 +126,7 @@ func NewBackendQuota(lg *zap.Logger, quotaBackendBytesCfg int64, be backend.Back
 			zap.String("quota-size", humanize.Bytes(uint64(quotaBackendBytesCfg))),
 		)
 	})
-	return &BackendQuota{be, quotaBackendBytesCfg}
+	return &BackendQuota{be, quotaBackendBytesCfg, grace}
 }
 
 func (b *BackendQuota) Available(v any) bool {
@@ -130,7 +136,7 @@ func (b *BackendQuota) Available(v any) bool {
 		return true
 	}
 	// TODO: maybe optimize Backend.Size()
-	return b.be.Size()+int64(cost) < b.maxBackendBytes
+	return quotaSize(b.be, b.grace)+int64(cost) < b.maxBackendBytes
 }
 
 func (b *BackendQuota) Cost(v any) int {
@@ -141,6 +147,8 @@ func (b *BackendQuota) Cost(v any) int {
 		return costTxn(r)
 	case *pb.LeaseGrantRequest:
 		return leaseOverhead
+	case *pb.LeaseGrantBatchRequest:
+		return leaseOverhead * len(r.Requests)
 	default:
 		panic("unexpected cost")
 	}
@@ -172,5 +180,80 @@ func costTxn(r *pb.TxnRequest) int {
 }
 
 func (b *BackendQuota) Remaining() int64 {
-	return b.maxBackendBytes - b.be.Size()
+	return b.maxBackendBytes - quotaSize(b.be, b.grace)
+}
+
+// quotaSize returns the size of the backend the quota applies to: its
+// physical size, or in grace mode the size in use.
+func quotaSize(be backend.Backend, grace bool) int64 {
+	if grace {
+		return be.SizeInUse()
+	}
+	return be.Size()
+}
+
+// QuotaWarnings warns as the size of the backend crosses thresholds below
+// its quota, so that operators can compact and defragment before the quota
+// is exceeded.
+type QuotaWarnings struct {
+	lg *zap.Logger
+	be backend.Backend
+	// quotaBytes is the quota the thresholds are fractions of.
+	quotaBytes int64
+	grace      bool
+	// thresholds are in increasing order; crossed is the number of them
+	// crossed at the last check.
+	thresholds []float64
+	crossed    int
+}
+
+// NewQuotaWarnings returns the warnings of the thresholds, fractions of the
+// quota between 0 and 1, or nil if there are none or the quota is disabled.
+func NewQuotaWarnings(lg *zap.Logger, quotaBackendBytesCfg int64, grace bool, thresholds []float64, be backend.Backend) *QuotaWarnings {
+	if len(thresholds) == 0 || quotaBackendBytesCfg < 0 {
+		return nil
+	}
+	if quotaBackendBytesCfg == 0 {
+		quotaBackendBytesCfg = DefaultQuotaBytes
+	}
+	return &QuotaWarnings{
+		lg:         lg,
+		be:         be,
+		quotaBytes: quotaBackendBytesCfg,
+		grace:      grace,
+		thresholds: slices.Sorted(slices.Values(thresholds)),
+	}
+}
+
+// Check compares the size of the backend to the thresholds. It warns when
+// the size crosses thresholds upwards, and reports the highest threshold
+// crossed.
+func (w *QuotaWarnings) Check() {
+	size := quotaSize(w.be, w.grace)
+	ratio := float64(size) / float64(w.quotaBytes)
+	crossed := 0
+	for crossed < len(w.thresholds) && ratio >= w.thresholds[crossed] {
+		crossed++
+	}
+	if crossed == w.crossed {
+		return
+	}
+	highest := 0.0
+	if crossed > 0 {
+		highest = w.thresholds[crossed-1]
+	}
+	quotaBackendWarningThreshold.Set(highest)
+	fields := []zap.Field{
+		zap.Float64("threshold", highest),
+		zap.Int64("size-bytes", size),
+		zap.String("size", humanize.Bytes(uint64(size))),
+		zap.Int64("quota-size-bytes", w.quotaBytes),
+		zap.String("quota-size", humanize.Bytes(uint64(w.quotaBytes))),
+	}
+	if crossed > w.crossed {
+		w.lg.Warn("backend size crossed a warning threshold of its quota; compact and defragment it before the quota is exceeded", fields...)
+	} else {
+		w.lg.Info("backend size fell below a warning threshold of its quota", fields...)
+	}
+	w.crossed = crossed
 }
@@ -0,0 +1,78 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+)
+
+type sizedBackend struct {
+	backend.Backend
+	size, sizeInUse int64
+}
+
+func (b *sizedBackend) Size() int64      { return b.size }
+func (b *sizedBackend) SizeInUse() int64 { return b.sizeInUse }
+
+func TestBackendQuotaGrace(t *testing.T) {
+	be := &sizedBackend{size: 900, sizeInUse: 100}
+	put := &pb.PutRequest{Key: []byte("foo"), Value: make([]byte, 200)}
+
+	q := NewBackendQuota(zaptest.NewLogger(t), 1000, false, be, "test")
+	require.False(t, q.Available(put))
+	require.Equal(t, int64(100), q.Remaining())
+
+	q = NewBackendQuota(zaptest.NewLogger(t), 1000, true, be, "test")
+	require.True(t, q.Available(put))
+	require.Equal(t, int64(900), q.Remaining())
+}
+
+func TestQuotaWarnings(t *testing.T) {
+	require.Nil(t, NewQuotaWarnings(zap.NewNop(), 1000, false, nil, &sizedBackend{}))
+	require.Nil(t, NewQuotaWarnings(zap.NewNop(), -1, false, []float64{0.8}, &sizedBackend{}))
+
+	core, logs := observer.New(zap.InfoLevel)
+	be := &sizedBackend{size: 100}
+	w := NewQuotaWarnings(zap.New(core), 1000, false, []float64{0.9, 0.8}, be)
+	w.Check()
+	require.Zero(t, logs.Len())
+	require.Zero(t, testutil.ToFloat64(quotaBackendWarningThreshold))
+
+	be.size = 950
+	w.Check()
+	require.Equal(t, 1, logs.FilterLevelExact(zap.WarnLevel).Len())
+	require.InDelta(t, 0.9, testutil.ToFloat64(quotaBackendWarningThreshold), 0)
+	// a warning is only logged once the size crosses a threshold
+	w.Check()
+	require.Equal(t, 1, logs.Len())
+
+	be.size = 850
+	w.Check()
+	require.Equal(t, 1, logs.FilterLevelExact(zap.InfoLevel).Len())
+	require.InDelta(t, 0.8, testutil.ToFloat64(quotaBackendWarningThreshold), 0)
+
+	be.size = 100
+	w.Check()
+	require.Zero(t, testutil.ToFloat64(quotaBackendWarningThreshold))
+}
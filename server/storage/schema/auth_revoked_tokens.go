@@ -0,0 +1,61 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/binary"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/server/v3/storage/backend"
+)
+
+// The revoked tokens are keyed by the digest of the token, and valued by the
+// unix time in seconds the token expires at. Their bucket is created with the
+// first revoked token, for the backends without any to be left unchanged.
+
+func (atx *authBatchTx) UnsafePutRevokedToken(hash []byte, expireTime int64) {
+	atx.tx.UnsafeCreateBucket(AuthRevokedTokens)
+	v := make([]byte, 8)
+	binary.BigEndian.PutUint64(v, uint64(expireTime))
+	atx.tx.UnsafePut(AuthRevokedTokens, hash, v)
+}
+
+func (atx *authBatchTx) UnsafeDeleteRevokedToken(hash []byte) {
+	atx.tx.UnsafeDelete(AuthRevokedTokens, hash)
+}
+
+func (atx *authBatchTx) UnsafeGetAllRevokedTokens() map[string]int64 {
+	return unsafeGetAllRevokedTokens(atx.lg, atx.tx)
+}
+
+func (atx *authReadTx) UnsafeGetAllRevokedTokens() map[string]int64 {
+	return unsafeGetAllRevokedTokens(atx.lg, atx.tx)
+}
+
+func unsafeGetAllRevokedTokens(lg *zap.Logger, tx backend.UnsafeReader) map[string]int64 {
+	tokens := make(map[string]int64)
+	err := tx.UnsafeForEach(AuthRevokedTokens, func(k []byte, v []byte) error {
+		if len(v) != 8 {
+			lg.Panic("invalid revoked token expiry", zap.Int("length", len(v)))
+		}
+		tokens[string(k)] = int64(binary.BigEndian.Uint64(v))
+		return nil
+	})
+	if err != nil {
+		lg.Panic("failed to get revoked tokens", zap.Error(err))
+	}
+	return tokens
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/server/v3/storage/backend"
+	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
+)
+
+func TestRevokedTokens(t *testing.T) {
+	lg := zaptest.NewLogger(t)
+	be, tmpPath := betesting.NewTmpBackend(t, time.Microsecond, 10)
+	abe := NewAuthBackend(lg, be)
+	abe.CreateAuthBuckets()
+
+	tx := abe.BatchTx()
+	tx.Lock()
+	tx.UnsafePutRevokedToken([]byte("hash1"), 100)
+	tx.UnsafePutRevokedToken([]byte("hash2"), 200)
+	tx.UnsafePutRevokedToken([]byte("hash3"), 300)
+	tx.UnsafeDeleteRevokedToken([]byte("hash2"))
+	tx.Unlock()
+	abe.ForceCommit()
+	be.Close()
+
+	be2 := backend.NewDefaultBackend(lg, tmpPath)
+	defer be2.Close()
+	abe2 := NewAuthBackend(lg, be2)
+	rtx := abe2.ReadTx()
+	rtx.RLock()
+	defer rtx.RUnlock()
+	assert.Equal(t, map[string]int64{"hash1": 100, "hash3": 300}, rtx.UnsafeGetAllRevokedTokens())
+}
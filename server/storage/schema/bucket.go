@@ -36,6 +36,8 @@ var (
 	authUsersBucketName = []byte("authUsers")
 	authRolesBucketName = []byte("authRoles")
 
+	authRevokedTokensBucketName = []byte("authRevokedTokens")
+
 	testBucketName = []byte("test")
 )
 
@@ -53,6 +55,8 @@ var (
 	AuthUsers = backend.Bucket(bucket{id: 21, name: authUsersBucketName, safeRangeBucket: false})
 	AuthRoles = backend.Bucket(bucket{id: 22, name: authRolesBucketName, safeRangeBucket: false})
 
+	AuthRevokedTokens = backend.Bucket(bucket{id: 23, name: authRevokedTokensBucketName, safeRangeBucket: false})
+
 	Test = backend.Bucket(bucket{id: 100, name: testBucketName, safeRangeBucket: false})
 
 	AllBuckets = []backend.Bucket{Key, Meta, Lease, Alarm, Cluster, Members, MembersRemoved, Auth, AuthUsers, AuthRoles}
@@ -83,6 +87,8 @@ var (
 	ClusterDowngradeKeyName      = []byte("downgrade")
 	// Since v3.6
 	MetaStorageVersionName = []byte("storageVersion")
+	// Since v3.7
+	ClusterReadOnlyKeyName = []byte("readOnly")
 	// Before adding new meta key please update server/etcdserver/version
 )
 
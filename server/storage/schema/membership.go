@@ -168,6 +168,19 @@ func (s *membershipBackend) MustSaveDowngradeToBackend(downgrade *version.Downgr
 	tx.UnsafePut(Cluster, dkey, dvalue)
 }
 
+// MustSaveReadOnlyToBackend saves whether the cluster is in read-only mode
+// to backend. The field is populated since etcd v3.7.
+func (s *membershipBackend) MustSaveReadOnlyToBackend(enabled bool) {
+	v := []byte("false")
+	if enabled {
+		v = []byte("true")
+	}
+	tx := s.be.BatchTx()
+	tx.LockInsideApply()
+	defer tx.Unlock()
+	tx.UnsafePut(Cluster, ClusterReadOnlyKeyName, v)
+}
+
 func (s *membershipBackend) MustCreateBackendBuckets() {
 	tx := s.be.BatchTx()
 	tx.LockOutsideApply()
@@ -239,3 +252,13 @@ func (s *membershipBackend) DowngradeInfoFromBackend() *version.DowngradeInfo {
 	}
 	return &d
 }
+
+// ReadOnlyFromBackend reads whether the cluster is in read-only mode from
+// backend. The field is populated since etcd v3.7.
+func (s *membershipBackend) ReadOnlyFromBackend() bool {
+	tx := s.be.ReadTx()
+	tx.RLock()
+	defer tx.RUnlock()
+	_, vals := tx.UnsafeRange(Cluster, ClusterReadOnlyKeyName, nil, 0)
+	return len(vals) == 1 && string(vals[0]) == "true"
+}
@@ -78,6 +78,13 @@ func UnsafeMigrate(lg *zap.Logger, tx backend.UnsafeReadWriter, w wal.Version, t
 	return plan.unsafeExecute(lg, tx)
 }
 
+// ValidateMigration returns an error if there is no migration of the storage
+// schema from the current to the target version.
+func ValidateMigration(lg *zap.Logger, current, target semver.Version) error {
+	_, err := newPlan(lg, current, target)
+	return err
+}
+
 // DetectSchemaVersion returns version of storage schema. Returned value depends on etcd version that created the backend. For
 // * v3.6 and newer will return storage version.
 // * v3.5 will return it's version if it includes all storage fields added in v3.5 (might require a snapshot).
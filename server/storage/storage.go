@@ -17,6 +17,7 @@ package storage
 import (
 	"errors"
 	"sync"
+	"time"
 
 	"github.com/coreos/go-semver/semver"
 	"go.uber.org/zap"
@@ -53,6 +54,8 @@ type storage struct {
 }
 
 func NewStorage(lg *zap.Logger, w *wal.WAL, s *snap.Snapshotter) Storage {
+	lastSnapshotTime.Store(time.Now().UnixNano())
+	walEntriesSinceLastSnapshot.Set(0)
 	return &storage{lg: lg, w: w, s: s}
 }
 
@@ -74,7 +77,12 @@ func (st *storage) SaveSnap(snap raftpb.Snapshot) error {
 	}
 	// gofail: var raftBeforeWALSaveSnaphot struct{}
 
-	return st.w.SaveSnapshot(walsnap)
+	if err = st.w.SaveSnapshot(walsnap); err != nil {
+		return err
+	}
+	lastSnapshotTime.Store(time.Now().UnixNano())
+	walEntriesSinceLastSnapshot.Set(0)
+	return nil
 }
 
 // Release releases resources older than the given snap and are no longer needed:
@@ -92,7 +100,11 @@ func (st *storage) Release(snap raftpb.Snapshot) error {
 func (st *storage) Save(s raftpb.HardState, ents []raftpb.Entry) error {
 	st.mux.RLock()
 	defer st.mux.RUnlock()
-	return st.w.Save(s, ents)
+	if err := st.w.Save(s, ents); err != nil {
+		return err
+	}
+	walEntriesSinceLastSnapshot.Add(float64(len(ents)))
+	return nil
 }
 
 func (st *storage) Close() error {
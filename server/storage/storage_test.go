@@ -0,0 +1,61 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/snap"
+	"go.etcd.io/etcd/server/v3/storage/wal"
+	"go.etcd.io/raft/v3/raftpb"
+)
+
+func TestSnapshotMetrics(t *testing.T) {
+	lg := zaptest.NewLogger(t)
+	dir := t.TempDir()
+	snapDir := filepath.Join(dir, "snap")
+	require.NoError(t, fileutil.TouchDirAll(lg, snapDir))
+	w, err := wal.Create(lg, filepath.Join(dir, "wal"), nil)
+	require.NoError(t, err)
+	st := NewStorage(lg, w, snap.New(lg, snapDir))
+	defer st.Close()
+
+	require.Zero(t, testutil.ToFloat64(walEntriesSinceLastSnapshot))
+	hs := raftpb.HardState{Term: 1, Commit: 3}
+	require.NoError(t, st.Save(hs, []raftpb.Entry{{Term: 1, Index: 1}, {Term: 1, Index: 2}}))
+	require.NoError(t, st.Save(hs, []raftpb.Entry{{Term: 1, Index: 3}}))
+	require.Equal(t, float64(3), testutil.ToFloat64(walEntriesSinceLastSnapshot))
+
+	time.Sleep(10 * time.Millisecond)
+	since := testutil.ToFloat64(timeSinceLastSnapshot)
+	require.GreaterOrEqual(t, since, (10 * time.Millisecond).Seconds())
+
+	require.NoError(t, st.SaveSnap(raftpb.Snapshot{
+		Data:     []byte("data"),
+		Metadata: raftpb.SnapshotMetadata{Term: 1, Index: 3},
+	}))
+	require.Zero(t, testutil.ToFloat64(walEntriesSinceLastSnapshot))
+	require.Less(t, testutil.ToFloat64(timeSinceLastSnapshot), since)
+
+	require.NoError(t, st.Save(hs, []raftpb.Entry{{Term: 1, Index: 4}}))
+	require.Equal(t, float64(1), testutil.ToFloat64(walEntriesSinceLastSnapshot))
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wal
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression is the algorithm compressing the payloads of the entry records
+// of a WAL file.
+type Compression uint8
+
+const (
+	CompressionNone Compression = iota
+	CompressionSnappy
+	CompressionZstd
+)
+
+// ParseCompression returns the compression named s, one of "none", "snappy"
+// and "zstd". The empty string is "none".
+func ParseCompression(s string) (Compression, error) {
+	switch s {
+	case "", "none":
+		return CompressionNone, nil
+	case "snappy":
+		return CompressionSnappy, nil
+	case "zstd":
+		return CompressionZstd, nil
+	}
+	return CompressionNone, fmt.Errorf("unknown WAL compression %q (expected none, snappy or zstd)", s)
+}
+
+func (c Compression) String() string {
+	switch c {
+	case CompressionNone:
+		return "none"
+	case CompressionSnappy:
+		return "snappy"
+	case CompressionZstd:
+		return "zstd"
+	}
+	return fmt.Sprintf("unknown(%d)", uint8(c))
+}
+
+// The zstd encoder and decoder are safe for concurrent use with EncodeAll and
+// DecodeAll, so they are shared by all the WALs.
+var (
+	zstdEncoder = sync.OnceValue(func() *zstd.Encoder {
+		e, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedFastest), zstd.WithEncoderConcurrency(1))
+		if err != nil {
+			panic(err)
+		}
+		return e
+	})
+	zstdDecoder = sync.OnceValue(func() *zstd.Decoder {
+		d, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+		if err != nil {
+			panic(err)
+		}
+		return d
+	})
+)
+
+// compress returns the compressed form of src, reusing the memory of dst
+// when it is large enough.
+func (c Compression) compress(dst, src []byte) []byte {
+	switch c {
+	case CompressionSnappy:
+		return s2.EncodeSnappy(dst[:cap(dst)], src)
+	case CompressionZstd:
+		return zstdEncoder().EncodeAll(src, dst[:0])
+	}
+	return src
+}
+
+func (c Compression) decompress(src []byte) ([]byte, error) {
+	switch c {
+	case CompressionSnappy:
+		return s2.Decode(nil, src)
+	case CompressionZstd:
+		return zstdDecoder().DecodeAll(src, nil)
+	}
+	return src, nil
+}
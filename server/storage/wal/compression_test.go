@@ -0,0 +1,81 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wal
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/server/v3/storage/wal/walpb"
+	"go.etcd.io/raft/v3/raftpb"
+)
+
+func TestSaveWithCompression(t *testing.T) {
+	for _, c := range []Compression{CompressionSnappy, CompressionZstd} {
+		t.Run(c.String(), func(t *testing.T) {
+			p := t.TempDir()
+			data := bytes.Repeat([]byte("compressible "), 512)
+			state := raftpb.HardState{Term: 1}
+
+			w, err := Create(zaptest.NewLogger(t), p, []byte("metadata"))
+			require.NoError(t, err)
+			w.SetCompression(c)
+			var ents []raftpb.Entry
+			for i := uint64(1); i <= 10; i++ {
+				ents = append(ents, raftpb.Entry{Index: i, Term: 1, Data: data})
+			}
+			state.Commit = 10
+			require.NoError(t, w.Save(state, ents))
+			// the entries are saved to a new file with a compression header.
+			assert.Equal(t, walName(1, 1), filepath.Base(w.tail().Name()))
+			require.NoError(t, w.Close())
+
+			raw, err := os.ReadFile(filepath.Join(p, walName(1, 1)))
+			require.NoError(t, err)
+			assert.False(t, bytes.Contains(raw, data))
+
+			// a file keeps its compression when the WAL is reopened without it.
+			w, err = Open(zaptest.NewLogger(t), p, walpb.Snapshot{})
+			require.NoError(t, err)
+			_, st, got, err := w.ReadAll()
+			require.NoError(t, err)
+			assert.Equal(t, state, st)
+			assert.Equal(t, ents, got)
+			ent := raftpb.Entry{Index: 11, Term: 1, Data: data}
+			ents = append(ents, ent)
+			state.Commit = 11
+			require.NoError(t, w.Save(state, []raftpb.Entry{ent}))
+			assert.Equal(t, walName(2, 11), filepath.Base(w.tail().Name()))
+			require.NoError(t, w.Close())
+
+			w, err = Open(zaptest.NewLogger(t), p, walpb.Snapshot{})
+			require.NoError(t, err)
+			defer w.Close()
+			_, st, got, err = w.ReadAll()
+			require.NoError(t, err)
+			assert.Equal(t, state, st)
+			assert.Equal(t, ents, got)
+
+			_, err = Verify(zaptest.NewLogger(t), p, walpb.Snapshot{})
+			require.NoError(t, err)
+		})
+	}
+}
@@ -39,6 +39,9 @@ type Decoder interface {
 	LastOffset() int64
 	LastCRC() uint32
 	UpdateCRC(prevCrc uint32)
+	// Compression returns the compression of the entries of the file being
+	// decoded, as recorded by its header.
+	Compression() Compression
 }
 
 type decoder struct {
@@ -48,6 +51,13 @@ type decoder struct {
 	// lastValidOff file offset following the last valid decoded record
 	lastValidOff int64
 	crc          hash.Hash32
+	// format of the entries of the file being decoded
+	format fileFormat
+	// kek unwraps the DEKs of the encrypted files.
+	kek KEKProvider
+	// rawEntries returns the entry payloads as stored, for the readers
+	// ignoring them, so that they need no key to read encrypted files.
+	rawEntries bool
 
 	// continueOnCrcError - causes the decoder to continue working even in case of crc mismatch.
 	// This is a desired mode for tools performing inspection of the corrupted WAL logs.
@@ -56,6 +66,10 @@ type decoder struct {
 }
 
 func NewDecoderAdvanced(continueOnCrcError bool, r ...fileutil.FileReader) Decoder {
+	return newDecoder(continueOnCrcError, r...)
+}
+
+func newDecoder(continueOnCrcError bool, r ...fileutil.FileReader) *decoder {
 	readers := make([]*fileutil.FileBufReader, len(r))
 	for i := range r {
 		readers[i] = fileutil.NewFileBufReader(r[i])
@@ -67,6 +81,14 @@ func NewDecoderAdvanced(continueOnCrcError bool, r ...fileutil.FileReader) Decod
 	}
 }
 
+// newRawEntriesDecoder returns a decoder of the records of r which returns
+// the entry payloads as stored.
+func newRawEntriesDecoder(r ...fileutil.FileReader) Decoder {
+	d := newDecoder(false, r...)
+	d.rawEntries = true
+	return d
+}
+
 func NewDecoder(r ...fileutil.FileReader) Decoder {
 	return NewDecoderAdvanced(false, r...)
 }
@@ -97,6 +119,7 @@ func (d *decoder) decodeRecord(rec *walpb.Record) error {
 			return io.EOF
 		}
 		d.lastValidOff = 0
+		d.format = fileFormat{}
 		return d.decodeRecord(rec)
 	}
 	if err != nil {
@@ -148,7 +171,24 @@ func (d *decoder) decodeRecord(rec *walpb.Record) error {
 		}
 	}
 	// record decoded as valid; point last valid offset to end of record
+	recOff := d.lastValidOff
 	d.lastValidOff += frameSizeBytes + recBytes + padBytes
+
+	switch {
+	case rec.Type == HeaderType:
+		// the header only changes how the following records of the file
+		// are decoded, it is not returned.
+		if err = d.readHeader(rec.Data); err != nil {
+			return fmt.Errorf("%w: in file '%s'", err, fileBufReader.FileInfo().Name())
+		}
+		rec.Reset()
+		return d.decodeRecord(rec)
+	case rec.Type == EntryType && !d.rawEntries:
+		if rec.Data, err = d.format.decodePayload(rec.Data); err != nil {
+			return fmt.Errorf("wal: failed to decode the entry in file '%s' at position %d: %w",
+				fileBufReader.FileInfo().Name(), recOff, err)
+		}
+	}
 	return nil
 }
 
@@ -210,6 +250,29 @@ func (d *decoder) LastCRC() uint32 {
 
 func (d *decoder) LastOffset() int64 { return d.lastValidOff }
 
+func (d *decoder) Compression() Compression { return d.format.compression }
+
+// decoderFormat returns the format of the file being decoded by d.
+func decoderFormat(d Decoder) fileFormat {
+	if dd, ok := d.(*decoder); ok {
+		return dd.format
+	}
+	return fileFormat{compression: d.Compression()}
+}
+
+func (d *decoder) readHeader(data []byte) error {
+	h, err := decodeHeader(data)
+	if err != nil {
+		return err
+	}
+	if d.rawEntries {
+		d.format = fileFormat{compression: h.compression}
+		return nil
+	}
+	d.format, err = unwrapFormat(d.kek, h)
+	return err
+}
+
 func MustUnmarshalEntry(d []byte) raftpb.Entry {
 	var e raftpb.Entry
 	pbutil.MustUnmarshal(&e, d)
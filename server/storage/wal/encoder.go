@@ -39,6 +39,11 @@ type encoder struct {
 	crc       hash.Hash32
 	buf       []byte
 	uint64buf []byte
+
+	// format of the payloads of the entry records of the file.
+	format fileFormat
+	cbuf   []byte
+	ebuf   []byte
 }
 
 func newEncoder(w io.Writer, prevCrc uint32, pageOffset int) *encoder {
@@ -52,25 +57,32 @@ func newEncoder(w io.Writer, prevCrc uint32, pageOffset int) *encoder {
 }
 
 // newFileEncoder creates a new encoder with current file offset for the page writer.
-func newFileEncoder(f *os.File, prevCrc uint32) (*encoder, error) {
+func newFileEncoder(f *os.File, prevCrc uint32, format fileFormat) (*encoder, error) {
 	offset, err := f.Seek(0, io.SeekCurrent)
 	if err != nil {
 		return nil, err
 	}
-	return newEncoder(f, prevCrc, int(offset)), nil
+	e := newEncoder(f, prevCrc, int(offset))
+	e.format = format
+	return e, nil
 }
 
 func (e *encoder) encode(rec *walpb.Record) error {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	e.crc.Write(rec.Data)
-	rec.Crc = e.crc.Sum32()
 	var (
 		data []byte
 		err  error
 		n    int
 	)
+	if rec.Type == EntryType {
+		if rec.Data, err = e.storedPayload(rec.Data); err != nil {
+			return err
+		}
+	}
+	e.crc.Write(rec.Data)
+	rec.Crc = e.crc.Sum32()
 
 	if rec.Size() > len(e.buf) {
 		data, err = rec.Marshal()
@@ -90,6 +102,21 @@ func (e *encoder) encode(rec *walpb.Record) error {
 	return write(e.bw, e.uint64buf, data, lenField)
 }
 
+// storedPayload returns the payload of an entry record in the format of the
+// file. The returned slice is only valid until the next call.
+func (e *encoder) storedPayload(payload []byte) ([]byte, error) {
+	if e.format.compression != CompressionNone {
+		e.cbuf = e.format.compression.compress(e.cbuf, payload)
+		payload = e.cbuf
+	}
+	if e.format.aead == nil {
+		return payload, nil
+	}
+	var err error
+	e.ebuf, err = seal(e.format.aead, e.ebuf, payload)
+	return e.ebuf, err
+}
+
 func encodeFrameSize(dataBytes int) (lenField uint64, padBytes int) {
 	lenField = uint64(dataBytes)
 	// force 8 byte alignment so length never gets a torn write
@@ -0,0 +1,100 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wal
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// dekSize is the size of the AES-256 data encryption keys of the files.
+const dekSize = 32
+
+var ErrNoKEKProvider = errors.New("wal: the file is encrypted but no key encryption key provider is set")
+
+// KEKProvider wraps the data encryption keys (DEKs) of the encrypted WAL
+// files with key encryption keys (KEKs). It has the method set of
+// encryption.KEKProvider, so that the WAL and the backend share a provider.
+type KEKProvider interface {
+	// Wrap encrypts dek with the current KEK and returns the ID of the KEK.
+	Wrap(ctx context.Context, dek []byte) (wrapped []byte, kekID string, err error)
+	// Unwrap decrypts dek wrapped with the KEK of ID kekID.
+	Unwrap(ctx context.Context, wrapped []byte, kekID string) ([]byte, error)
+}
+
+func newAEAD(dek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dek)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// newEncryptedFormat generates the DEK of a new file, and returns the format
+// and the header of the file.
+func newEncryptedFormat(kek KEKProvider, c Compression) (fileFormat, fileHeader, error) {
+	dek := make([]byte, dekSize)
+	if _, err := rand.Read(dek); err != nil {
+		return fileFormat{}, fileHeader{}, err
+	}
+	wrapped, kekID, err := kek.Wrap(context.Background(), dek)
+	if err != nil {
+		return fileFormat{}, fileHeader{}, fmt.Errorf("wal: failed to wrap the data encryption key: %w", err)
+	}
+	aead, err := newAEAD(dek)
+	if err != nil {
+		return fileFormat{}, fileHeader{}, err
+	}
+	return fileFormat{compression: c, aead: aead}, fileHeader{compression: c, kekID: kekID, wrappedDEK: wrapped}, nil
+}
+
+// unwrapFormat returns the format of a file from its header.
+func unwrapFormat(kek KEKProvider, h fileHeader) (fileFormat, error) {
+	f := fileFormat{compression: h.compression}
+	if !h.encrypted() {
+		return f, nil
+	}
+	if kek == nil {
+		return f, ErrNoKEKProvider
+	}
+	dek, err := kek.Unwrap(context.Background(), h.wrappedDEK, h.kekID)
+	if err != nil {
+		return f, fmt.Errorf("wal: failed to unwrap the data encryption key with %q: %w", h.kekID, err)
+	}
+	f.aead, err = newAEAD(dek)
+	return f, err
+}
+
+// seal appends the random nonce and the encryption of payload to dst[:0].
+func seal(aead cipher.AEAD, dst, payload []byte) ([]byte, error) {
+	n := aead.NonceSize()
+	dst = append(dst[:0], make([]byte, n)...)
+	if _, err := rand.Read(dst); err != nil {
+		return nil, err
+	}
+	return aead.Seal(dst, dst[:n], payload, nil), nil
+}
+
+func open(aead cipher.AEAD, stored []byte) ([]byte, error) {
+	n := aead.NonceSize()
+	if len(stored) < n {
+		return nil, errors.New("wal: truncated encrypted payload")
+	}
+	return aead.Open(nil, stored[:n], stored[n:], nil)
+}
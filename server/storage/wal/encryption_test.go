@@ -0,0 +1,138 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wal
+
+import (
+	"bytes"
+	"context"
+	"crypto/cipher"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/server/v3/storage/wal/walpb"
+	"go.etcd.io/raft/v3/raftpb"
+)
+
+// testKEKProvider wraps the DEKs with the KEK current of keys.
+type testKEKProvider struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+func newTestKEKProvider(t *testing.T, ids ...string) *testKEKProvider {
+	p := &testKEKProvider{current: ids[0], keys: make(map[string]cipher.AEAD)}
+	for _, id := range ids {
+		aead, err := newAEAD(bytes.Repeat([]byte(id[len(id)-1:]), dekSize))
+		require.NoError(t, err)
+		p.keys[id] = aead
+	}
+	return p
+}
+
+func (p *testKEKProvider) Wrap(_ context.Context, dek []byte) ([]byte, string, error) {
+	wrapped, err := seal(p.keys[p.current], nil, dek)
+	return wrapped, p.current, err
+}
+
+func (p *testKEKProvider) Unwrap(_ context.Context, wrapped []byte, kekID string) ([]byte, error) {
+	aead, ok := p.keys[kekID]
+	if !ok {
+		return nil, fmt.Errorf("unknown key encryption key %q", kekID)
+	}
+	return open(aead, wrapped)
+}
+
+func TestSaveWithEncryption(t *testing.T) {
+	p := t.TempDir()
+	secret := []byte("secret value")
+	kek := newTestKEKProvider(t, "kek1", "kek2")
+	state := raftpb.HardState{Term: 1}
+
+	w, err := Create(zaptest.NewLogger(t), p, []byte("metadata"))
+	require.NoError(t, err)
+	w.SetEncryption(kek)
+	w.SetCompression(CompressionSnappy)
+	var ents []raftpb.Entry
+	save := func(from, to uint64) {
+		var batch []raftpb.Entry
+		for i := from; i <= to; i++ {
+			batch = append(batch, raftpb.Entry{Index: i, Term: 1, Data: secret})
+		}
+		ents = append(ents, batch...)
+		state.Commit = to
+		require.NoError(t, w.Save(state, batch))
+	}
+	save(1, 5)
+	assert.Equal(t, walName(1, 1), filepath.Base(w.tail().Name()))
+
+	// the entries after a snapshot are saved to a new file, with a DEK
+	// wrapped by the current KEK.
+	kek.current = "kek2"
+	snap := walpb.Snapshot{Index: 5, Term: 1, ConfState: &raftpb.ConfState{}}
+	require.NoError(t, w.SaveSnapshot(snap))
+	save(6, 10)
+	assert.Equal(t, walName(2, 6), filepath.Base(w.tail().Name()))
+	save(11, 12)
+	assert.Equal(t, walName(2, 6), filepath.Base(w.tail().Name()))
+	require.NoError(t, w.Close())
+
+	for _, name := range []string{walName(1, 1), walName(2, 6)} {
+		raw, rerr := os.ReadFile(filepath.Join(p, name))
+		require.NoError(t, rerr)
+		assert.False(t, bytes.Contains(raw, secret))
+	}
+
+	// reading the entries needs the KEKs.
+	w, err = Open(zaptest.NewLogger(t), p, walpb.Snapshot{})
+	require.NoError(t, err)
+	_, _, _, err = w.ReadAll()
+	require.ErrorIs(t, err, ErrNoKEKProvider)
+	require.NoError(t, w.Close())
+
+	w, err = Open(zaptest.NewLogger(t), p, walpb.Snapshot{})
+	require.NoError(t, err)
+	w.SetEncryption(kek)
+	w.SetCompression(CompressionSnappy)
+	_, st, got, err := w.ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, state, st)
+	assert.Equal(t, ents, got)
+	// the tail file is appended with its DEK.
+	save(13, 13)
+	assert.Equal(t, walName(2, 6), filepath.Base(w.tail().Name()))
+	require.NoError(t, w.Close())
+
+	// the records read to validate the WAL are not encrypted.
+	_, err = Verify(zaptest.NewLogger(t), p, walpb.Snapshot{})
+	require.NoError(t, err)
+	snaps, err := ValidSnapshotEntries(zaptest.NewLogger(t), p)
+	require.NoError(t, err)
+	assert.Equal(t, []walpb.Snapshot{{}, snap}, snaps)
+
+	// the files after the snapshot do not need the retired KEK.
+	w, err = OpenForRead(zaptest.NewLogger(t), p, walpb.Snapshot{Index: 6, Term: 1})
+	require.NoError(t, err)
+	defer w.Close()
+	w.SetEncryption(newTestKEKProvider(t, "kek2"))
+	_, _, got, err = w.ReadAll()
+	require.ErrorIs(t, err, ErrSnapshotNotFound)
+	assert.Equal(t, ents[6:], got)
+}
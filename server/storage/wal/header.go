@@ -0,0 +1,118 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wal
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	headerCompressionMask uint64 = 0xff
+	headerEncrypted       uint64 = 1 << 8
+
+	headerFlagsBytes = 8
+	headerKEKIDBytes = 2
+)
+
+// fileHeader is the data of a HeaderType record: a little endian uint64 of
+// flags, followed for the encrypted files by the DEK of the file wrapped by a
+// key encryption key:
+//
+//	flags (8 bytes) | len(kekID) (2 bytes) | kekID | wrapped DEK
+//
+// The lowest byte of the flags is the compression of the entries of the file,
+// the next bit is set for the encrypted files. The other bits are reserved
+// and must be zero.
+type fileHeader struct {
+	compression Compression
+	// kekID and wrappedDEK are only set for the encrypted files.
+	kekID      string
+	wrappedDEK []byte
+}
+
+func (h fileHeader) encrypted() bool { return h.wrappedDEK != nil }
+
+func encodeHeader(h fileHeader) []byte {
+	flags := uint64(h.compression)
+	if !h.encrypted() {
+		return binary.LittleEndian.AppendUint64(nil, flags)
+	}
+	b := make([]byte, 0, headerFlagsBytes+headerKEKIDBytes+len(h.kekID)+len(h.wrappedDEK))
+	b = binary.LittleEndian.AppendUint64(b, flags|headerEncrypted)
+	b = binary.LittleEndian.AppendUint16(b, uint16(len(h.kekID)))
+	b = append(b, h.kekID...)
+	return append(b, h.wrappedDEK...)
+}
+
+func decodeHeader(b []byte) (fileHeader, error) {
+	var h fileHeader
+	if len(b) < headerFlagsBytes {
+		return h, fmt.Errorf("wal: invalid file header of %d bytes", len(b))
+	}
+	flags := binary.LittleEndian.Uint64(b)
+	if flags&^(headerCompressionMask|headerEncrypted) != 0 {
+		return h, fmt.Errorf("wal: unsupported file header flags %#x", flags)
+	}
+	h.compression = Compression(flags & headerCompressionMask)
+	if h.compression > CompressionZstd {
+		return h, fmt.Errorf("wal: unsupported compression %s", h.compression)
+	}
+	b = b[headerFlagsBytes:]
+	if flags&headerEncrypted == 0 {
+		if len(b) != 0 {
+			return h, errors.New("wal: unexpected data after the file header flags")
+		}
+		return h, nil
+	}
+	if len(b) < headerKEKIDBytes {
+		return h, errors.New("wal: truncated file header")
+	}
+	n := int(binary.LittleEndian.Uint16(b))
+	b = b[headerKEKIDBytes:]
+	if len(b) <= n {
+		return h, errors.New("wal: truncated file header")
+	}
+	h.kekID, h.wrappedDEK = string(b[:n]), b[n:]
+	return h, nil
+}
+
+// fileFormat is how the entry payloads of a WAL file are stored: compressed,
+// then encrypted. The crc of a record covers its stored payload, so that it
+// is validated before being decrypted and decompressed.
+type fileFormat struct {
+	compression Compression
+	// aead encrypts the payloads with the DEK of the file, it is nil if they
+	// are not encrypted.
+	aead cipher.AEAD
+}
+
+// matches returns whether the entries compressed with c, and encrypted if
+// encrypted is set, can be appended to a file of format f.
+func (f fileFormat) matches(c Compression, encrypted bool) bool {
+	return f.compression == c && (f.aead != nil) == encrypted
+}
+
+func (f fileFormat) decodePayload(stored []byte) ([]byte, error) {
+	var err error
+	if f.aead != nil {
+		if stored, err = open(f.aead, stored); err != nil {
+			return nil, err
+		}
+	}
+	return f.compression.decompress(stored)
+}
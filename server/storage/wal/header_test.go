@@ -0,0 +1,49 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeHeader(t *testing.T) {
+	for _, h := range []fileHeader{
+		{compression: CompressionZstd},
+		{compression: CompressionSnappy, kekID: "kek1", wrappedDEK: []byte("wrapped")},
+		{kekID: "", wrappedDEK: []byte("wrapped")},
+	} {
+		got, err := decodeHeader(encodeHeader(h))
+		require.NoError(t, err)
+		assert.Equal(t, h, got)
+	}
+
+	tcs := []struct {
+		data    []byte
+		wantErr string
+	}{
+		{data: []byte{1, 0, 0, 0, 0, 0, 0, 1}, wantErr: "unsupported file header flags"},
+		{data: []byte{9, 0, 0, 0, 0, 0, 0, 0}, wantErr: "unsupported compression unknown(9)"},
+		{data: []byte{1}, wantErr: "invalid file header"},
+		{data: []byte{0, 0, 0, 0, 0, 0, 0, 0, 1}, wantErr: "unexpected data after the file header flags"},
+		{data: []byte{0, 1, 0, 0, 0, 0, 0, 0, 4, 0, 'k', 'e', 'k'}, wantErr: "truncated file header"},
+	}
+	for _, tc := range tcs {
+		_, err := decodeHeader(tc.data)
+		require.ErrorContains(t, err, tc.wantErr)
+	}
+}
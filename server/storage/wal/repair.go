@@ -42,7 +42,7 @@ func Repair(lg *zap.Logger, dirpath string) bool {
 	lg.Info("repairing", zap.String("path", f.Name()))
 
 	rec := &walpb.Record{}
-	decoder := NewDecoder(fileutil.NewFileReader(f.File))
+	decoder := newRawEntriesDecoder(fileutil.NewFileReader(f.File))
 	for {
 		lastOffset := decoder.LastOffset()
 		err := decoder.Decode(rec)
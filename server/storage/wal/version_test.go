@@ -142,6 +142,11 @@ func TestEtcdVersionFromMessage(t *testing.T) {
 			input:  &etcdserverpb.RequestHeader{AuthRevision: 1, Username: "Alice"},
 			expect: &version.V3_1,
 		},
+		{
+			name:   "RequestHeader TraceContext set implies v3.7",
+			input:  &etcdserverpb.RequestHeader{TraceContext: map[string]string{"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"}},
+			expect: &version.V3_7,
+		},
 		{
 			name:   "Setting a RequestHeader AuthRevision in subfield implies v3.1",
 			input:  &etcdserverpb.InternalRaftRequest{Header: &etcdserverpb.RequestHeader{AuthRevision: 1}},
@@ -172,6 +177,16 @@ func TestEtcdVersionFromMessage(t *testing.T) {
 			input:  &etcdserverpb.Compare{TargetUnion: &etcdserverpb.Compare_Lease{}},
 			expect: &version.V3_3,
 		},
+		{
+			name:   "Enum CompareTarget set to COUNT implies v3.7",
+			input:  &etcdserverpb.Compare{Target: etcdserverpb.Compare_COUNT, TargetUnion: &etcdserverpb.Compare_Count{}},
+			expect: &version.V3_7,
+		},
+		{
+			name:   "Oneof Compare value_prefix set implies v3.7",
+			input:  &etcdserverpb.Compare{TargetUnion: &etcdserverpb.Compare_ValuePrefix{}},
+			expect: &version.V3_7,
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.name, func(t *testing.T) {
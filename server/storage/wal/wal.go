@@ -41,6 +41,11 @@ const (
 	StateType
 	CrcType
 	SnapshotType
+	// HeaderType records the format of the file it is written to, right
+	// after its CRC record. It is only written to the files using a
+	// non-default format, so that the files written by older versions,
+	// which have none, still replay.
+	HeaderType
 
 	// warnSyncDuration is the amount of time allotted to an fsync before
 	// logging a warning
@@ -84,7 +89,10 @@ type WAL struct {
 	decoder   Decoder        // decoder to Decode records
 	readClose func() error   // closer for Decode reader
 
-	unsafeNoSync bool // if set, do not fsync
+	unsafeNoSync bool        // if set, do not fsync
+	compression  Compression // compression of the entries of the files cut
+	kek          KEKProvider // if set, the entries of the files cut are encrypted
+	rekey        bool        // if set, the next entries are saved to a new file
 
 	mu      sync.Mutex
 	enti    uint64   // index of the last entry saved to the wal
@@ -158,7 +166,7 @@ func Create(lg *zap.Logger, dirpath string, metadata []byte) (*WAL, error) {
 		dir:      dirpath,
 		metadata: metadata,
 	}
-	w.encoder, err = newFileEncoder(f.File, 0)
+	w.encoder, err = newFileEncoder(f.File, 0, fileFormat{})
 	if err != nil {
 		return nil, err
 	}
@@ -270,6 +278,30 @@ func (w *WAL) SetUnsafeNoFsync() {
 	w.unsafeNoSync = true
 }
 
+// SetCompression sets the compression of the entries saved to the WAL. The
+// compression is recorded in the header of each file, so a file is never
+// written with two compressions: if the file being appended was written
+// with another one, a new file is cut before saving the next entries.
+func (w *WAL) SetCompression(c Compression) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.compression = c
+}
+
+// SetEncryption encrypts the entries saved to the WAL with a data encryption
+// key (DEK) per file, stored in the header of the file wrapped by a key
+// encryption key of kek, which must also unwrap the DEKs of the files read
+// by ReadAll. A new DEK is used after each snapshot: a key encryption key can
+// be retired once the files wrapping a DEK with it are released.
+func (w *WAL) SetEncryption(kek KEKProvider) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.kek = kek
+	if d, ok := w.decoder.(*decoder); ok {
+		d.kek = kek
+	}
+}
+
 func (w *WAL) cleanupWAL(lg *zap.Logger) {
 	var err error
 	if err = w.Close(); err != nil {
@@ -580,7 +612,8 @@ func (w *WAL) ReadAll() (metadata []byte, state raftpb.HardState, ents []raftpb.
 
 	if w.tail() != nil {
 		// create encoder (chain crc with the decoder), enable appending
-		w.encoder, err = newFileEncoder(w.tail().File, w.decoder.LastCRC())
+		// in the format of the tail file
+		w.encoder, err = newFileEncoder(w.tail().File, w.decoder.LastCRC(), decoderFormat(w.decoder))
 		if err != nil {
 			return nil, state, nil, err
 		}
@@ -615,8 +648,9 @@ func ValidSnapshotEntries(lg *zap.Logger, walDir string) ([]walpb.Snapshot, erro
 		}
 	}()
 
-	// create a new decoder from the readers on the WAL files
-	decoder := NewDecoder(rs...)
+	// create a new decoder from the readers on the WAL files, the entries
+	// are ignored so their payloads are not decoded
+	decoder := newRawEntriesDecoder(rs...)
 
 	for err = decoder.Decode(rec); err == nil; err = decoder.Decode(rec) {
 		switch rec.Type {
@@ -689,8 +723,9 @@ func Verify(lg *zap.Logger, walDir string, snap walpb.Snapshot) (*raftpb.HardSta
 		}
 	}()
 
-	// create a new decoder from the readers on the WAL files
-	decoder := NewDecoder(rs...)
+	// create a new decoder from the readers on the WAL files, the entries
+	// are ignored so their payloads are not decoded
+	decoder := newRawEntriesDecoder(rs...)
 
 	for err = decoder.Decode(rec); err == nil; err = decoder.Decode(rec) {
 		switch rec.Type {
@@ -767,8 +802,16 @@ func (w *WAL) cut() error {
 
 	// update writer and save the previous crc
 	w.locks = append(w.locks, newTail)
+	format, header := fileFormat{compression: w.compression}, fileHeader{compression: w.compression}
+	if w.kek != nil {
+		if format, header, err = newEncryptedFormat(w.kek, w.compression); err != nil {
+			return err
+		}
+	}
+	w.rekey = false
+
 	prevCrc := w.encoder.crc.Sum32()
-	w.encoder, err = newFileEncoder(w.tail().File, prevCrc)
+	w.encoder, err = newFileEncoder(w.tail().File, prevCrc, format)
 	if err != nil {
 		return err
 	}
@@ -777,6 +820,12 @@ func (w *WAL) cut() error {
 		return err
 	}
 
+	if header.compression != CompressionNone || header.encrypted() {
+		if err = w.encoder.encode(&walpb.Record{Type: HeaderType, Data: encodeHeader(header)}); err != nil {
+			return err
+		}
+	}
+
 	if err = w.encoder.encode(&walpb.Record{Type: MetadataType, Data: w.metadata}); err != nil {
 		return err
 	}
@@ -817,7 +866,7 @@ func (w *WAL) cut() error {
 	w.locks[len(w.locks)-1] = newTail
 
 	prevCrc = w.encoder.crc.Sum32()
-	w.encoder, err = newFileEncoder(w.tail().File, prevCrc)
+	w.encoder, err = newFileEncoder(w.tail().File, prevCrc, format)
 	if err != nil {
 		return err
 	}
@@ -963,6 +1012,12 @@ func (w *WAL) Save(st raftpb.HardState, ents []raftpb.Entry) error {
 
 	mustSync := raft.MustSync(st, w.state, len(ents))
 
+	if len(ents) > 0 && (w.rekey || !w.encoder.format.matches(w.compression, w.kek != nil)) {
+		if err := w.cut(); err != nil {
+			return err
+		}
+	}
+
 	// TODO(xiangli): no more reference operator
 	for i := range ents {
 		if err := w.saveEntry(&ents[i]); err != nil {
@@ -1008,6 +1063,8 @@ func (w *WAL) SaveSnapshot(e walpb.Snapshot) error {
 	if w.enti < e.Index {
 		w.enti = e.Index
 	}
+	// the entries after a snapshot are encrypted with a new DEK
+	w.rekey = w.kek != nil
 	return w.sync()
 }
 
@@ -95,6 +95,10 @@ func TestKVGet(t *testing.T) {
 					{begin: "", options: config.GetOptions{Prefix: true, Order: clientv3.SortNone, SortBy: clientv3.SortByCreateRevision}, wkv: wantKvs},
 					{begin: "", options: config.GetOptions{Prefix: true, Order: clientv3.SortDescend, SortBy: clientv3.SortByCreateRevision}, wkv: reversedKvs},
 					{begin: "", options: config.GetOptions{Prefix: true, Order: clientv3.SortDescend, SortBy: clientv3.SortByKey}, wkv: reversedKvs},
+					{begin: "", options: config.GetOptions{Prefix: true, MinCreateRev: 7}, wkv: []string{"foo", "foo/abc", "fop"}},
+					{begin: "", options: config.GetOptions{Prefix: true, MaxCreateRev: 3}, wkv: []string{"a", "b"}},
+					{begin: "", options: config.GetOptions{Prefix: true, MinModRev: 6}, wkv: []string{"c", "foo", "foo/abc", "fop"}},
+					{begin: "", options: config.GetOptions{Prefix: true, MaxModRev: 5}, wkv: []string{"a", "b"}},
 				}
 				for _, tt := range tests {
 					resp, err := cc.Get(ctx, tt.begin, tt.options)
@@ -0,0 +1,40 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/pkg/v3/expect"
+	"go.etcd.io/etcd/tests/v3/framework/e2e"
+)
+
+func TestCtlV3CheckPerfCustomWorkload(t *testing.T) { testCtl(t, checkPerfCustomWorkloadTest) }
+
+func checkPerfCustomWorkloadTest(cx ctlCtx) {
+	args := append(cx.PrefixArgs(), "check", "perf", "--qps", "20", "--clients", "2", "--duration", "2s", "--val-size", "64", "--key-count", "10")
+	require.NoError(cx.t, e2e.SpawnWithExpects(args, cx.envMap, expect.ExpectedResponse{Value: "PASS: Throughput is"}, expect.ExpectedResponse{Value: "PASS"}))
+
+	jsonArgs := append(append(cx.PrefixArgs(), "-w", "json"), args[len(cx.PrefixArgs()):]...)
+	require.NoError(cx.t, e2e.SpawnWithExpects(jsonArgs, cx.envMap, expect.ExpectedResponse{
+		Value:         `\{"workload":\{"qps":20,"clients":2,"duration":"2s","value_size":64,"key_count":10\},.*"checks":\[\{"name":"errors","pass":true.*\],"pass":true\}`,
+		IsRegularExpr: true,
+	}))
+
+	require.ErrorContains(cx.t, e2e.SpawnWithExpects(append(cx.PrefixArgs(), "check", "perf", "--duration", "100ms"), cx.envMap,
+		expect.ExpectedResponse{Value: "--duration must be at least 1s"}), "unexpected exit code")
+}
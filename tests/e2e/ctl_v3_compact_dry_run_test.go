@@ -0,0 +1,48 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/pkg/v3/expect"
+	"go.etcd.io/etcd/tests/v3/framework/e2e"
+)
+
+func TestCtlV3CompactDryRun(t *testing.T) { testCtl(t, compactDryRunTest) }
+
+func compactDryRunTest(cx ctlCtx) {
+	for _, v := range []string{"v1", "v2", "v3"} { // revs 2 to 4
+		require.NoError(cx.t, ctlV3Put(cx, "key", v, ""))
+	}
+	require.NoError(cx.t, ctlV3Put(cx, "other", "v", "")) // rev 5
+
+	dryRun := append(cx.PrefixArgs(), "compaction", "--dry-run", "4")
+	expected := expect.ExpectedResponse{
+		Value:         `compacting revision 4 would delete 2 revisions, reclaiming approximately .* \([1-9][0-9]* bytes\)`,
+		IsRegularExpr: true,
+	}
+	require.NoError(cx.t, e2e.SpawnWithExpects(dryRun, cx.envMap, expected))
+	// the dry run doesn't compact.
+	require.NoError(cx.t, e2e.SpawnWithExpects(dryRun, cx.envMap, expected))
+
+	require.NoError(cx.t, e2e.SpawnWithExpects(append(cx.PrefixArgs(), "compaction", "4"), cx.envMap,
+		expect.ExpectedResponse{Value: "compacted revision 4"}))
+	require.ErrorContains(cx.t, e2e.SpawnWithExpects(dryRun, cx.envMap, expected), "required revision has been compacted")
+	require.NoError(cx.t, e2e.SpawnWithExpects(append(cx.PrefixArgs(), "compaction", "--dry-run", "5"), cx.envMap,
+		expect.ExpectedResponse{Value: "compacting revision 5 would delete 0 revisions, reclaiming approximately 0 B (0 bytes)"}))
+}
@@ -15,6 +15,7 @@
 package e2e
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -27,6 +28,26 @@ func TestCtlV3DefragOffline(t *testing.T) {
 	testCtlWithOffline(t, maintenanceInitKeys, defragOfflineTest)
 }
 
+func TestCtlV3DefragStructuredOutput(t *testing.T) { testCtl(t, defragStructuredOutputTest) }
+
+func defragStructuredOutputTest(cx ctlCtx) {
+	ep := cx.epc.EndpointsGRPC()[0]
+	require.NoError(cx.t, e2e.SpawnWithExpects(append(cx.PrefixArgs(), "-w", "json", "defrag"), cx.envMap, expect.ExpectedResponse{
+		Value:         `\{"endpoint":"` + regexp.QuoteMeta(ep) + `","async":false,"took":"[^"]+"\}`,
+		IsRegularExpr: true,
+	}))
+	require.NoError(cx.t, e2e.SpawnWithExpects(append(cx.PrefixArgs(), "-w", "fields", "defrag", "status"), cx.envMap,
+		expect.ExpectedResponse{Value: `"Endpoint" : "` + ep + `"`},
+		expect.ExpectedResponse{Value: `"InProgress" : false`},
+		expect.ExpectedResponse{Value: `"Error" : ""`},
+	))
+
+	// the errors are structured too
+	require.ErrorContains(cx.t, e2e.SpawnWithExpects(append(cx.PrefixArgs(), "-w", "json", "move-leader"), cx.envMap, expect.ExpectedResponse{
+		Value: `{"error":{"exitCode":128,"message":"move-leader command needs 1 argument"}}`,
+	}), "unexpected exit code")
+}
+
 func maintenanceInitKeys(cx ctlCtx) {
 	kvs := []kv{{"key", "val1"}, {"key", "val2"}, {"key", "val3"}}
 	for i := range kvs {
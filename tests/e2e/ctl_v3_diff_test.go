@@ -0,0 +1,51 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/pkg/v3/expect"
+	"go.etcd.io/etcd/tests/v3/framework/e2e"
+)
+
+func TestCtlV3DiffRevisions(t *testing.T) { testCtl(t, diffRevisionsTest) }
+
+func diffRevisionsTest(cx ctlCtx) {
+	require.NoError(cx.t, ctlV3Put(cx, "key1", "val1", ""))  // rev 2
+	require.NoError(cx.t, ctlV3Put(cx, "key2", "val2", ""))  // rev 3
+	require.NoError(cx.t, ctlV3Put(cx, "other", "x", ""))    // rev 4
+	require.NoError(cx.t, ctlV3Put(cx, "key1", "new1", ""))  // rev 5
+	require.NoError(cx.t, ctlV3Put(cx, "key3", "val3", ""))  // rev 6
+	require.NoError(cx.t, ctlV3Del(cx, []string{"key2"}, 1)) // rev 7
+
+	cmdArgs := append(cx.PrefixArgs(), "diff", "--rev", "4", "--batch-size", "1", "key")
+	require.NoError(cx.t, e2e.SpawnWithExpects(cmdArgs, cx.envMap,
+		expect.ExpectedResponse{Value: "~ key1"},
+		expect.ExpectedResponse{Value: "- key2"},
+		expect.ExpectedResponse{Value: "+ key3"},
+		expect.ExpectedResponse{Value: "1 added, 1 removed, 1 changed between revision 4 and revision 7"},
+	))
+
+	cmdArgs = append(cx.PrefixArgs(), "diff", "--rev", "2", "--rev", "5", "--show-values", "key1")
+	require.NoError(cx.t, e2e.SpawnWithExpects(cmdArgs, cx.envMap,
+		expect.ExpectedResponse{Value: "~ key1"},
+		expect.ExpectedResponse{Value: `  - "val1"`},
+		expect.ExpectedResponse{Value: `  + "new1"`},
+		expect.ExpectedResponse{Value: "0 added, 0 removed, 1 changed between revision 2 and revision 5"},
+	))
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/pkg/v3/expect"
+	"go.etcd.io/etcd/tests/v3/framework/e2e"
+)
+
+func TestCtlV3DowngradeValidateDryRun(t *testing.T) { testCtl(t, downgradeValidateDryRunTest) }
+
+func downgradeValidateDryRunTest(cx ctlCtx) {
+	dryRun := func(target string) []string {
+		return append(cx.PrefixArgs(), "downgrade", "validate", "--dry-run", target)
+	}
+
+	// the storage version is only set once the cluster version is.
+	lines := make([]expect.ExpectedResponse, cx.cfg.ClusterSize)
+	for i := range lines {
+		// member ID, storage version, WAL version, features, blockers
+		lines[i] = expect.ExpectedResponse{Value: `[0-9a-f]+, 3\.7\.0, 3\.\d\.0, ,`, IsRegularExpr: true}
+	}
+	lines = append(lines, expect.ExpectedResponse{Value: "PASSED: nothing blocks the downgrade"})
+	require.Eventually(cx.t, func() bool {
+		return e2e.SpawnWithExpects(dryRun("3.6"), cx.envMap, lines...) == nil
+	}, 10*time.Second, 500*time.Millisecond)
+
+	err := e2e.SpawnWithExpects(dryRun("3.4"), cx.envMap,
+		expect.ExpectedResponse{Value: "FAILED: etcdserver: invalid downgrade target version"})
+	require.ErrorContains(cx.t, err, "downgrade to 3.4 is blocked")
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/pkg/v3/expect"
+	"go.etcd.io/etcd/tests/v3/framework/e2e"
+)
+
+func TestCtlV3ExportImportJSON(t *testing.T)     { testCtl(t, exportImportTest("json")) }
+func TestCtlV3ExportImportProtobuf(t *testing.T) { testCtl(t, exportImportTest("protobuf")) }
+
+func exportImportTest(format string) func(cx ctlCtx) {
+	return func(cx ctlCtx) {
+		leaseID, err := ctlV3LeaseGrant(cx, 300)
+		require.NoError(cx.t, err)
+		require.NoError(cx.t, ctlV3Put(cx, "key1", "val1", ""))
+		require.NoError(cx.t, ctlV3Put(cx, "key2", "val2", leaseID))
+		require.NoError(cx.t, ctlV3Put(cx, "other", "val3", ""))
+
+		path := filepath.Join(cx.t.TempDir(), "export")
+		cmdArgs := append(cx.PrefixArgs(), "export", "--prefix", "key", "--format", format, "--output", path, "--batch-size", "1")
+		require.NoError(cx.t, e2e.SpawnWithExpectWithEnv(cmdArgs, cx.envMap, expect.ExpectedResponse{Value: "Exported 2 keys at revision 4"}))
+
+		require.NoError(cx.t, ctlV3Del(cx, []string{"", "--prefix"}, 3))
+		cmdArgs = append(cx.PrefixArgs(), "lease", "revoke", leaseID)
+		require.NoError(cx.t, e2e.SpawnWithExpectWithEnv(cmdArgs, cx.envMap, expect.ExpectedResponse{Value: "revoked"}))
+
+		cmdArgs = append(cx.PrefixArgs(), "import", "--format", format, path)
+		require.NoError(cx.t, e2e.SpawnWithExpectWithEnv(cmdArgs, cx.envMap, expect.ExpectedResponse{Value: "Imported 2 keys"}))
+		require.NoError(cx.t, ctlV3Get(cx, []string{"", "--prefix"}, kv{"key1", "val1"}, kv{"key2", "val2"}))
+
+		// the lease of key2 was granted anew
+		cmdArgs = append(cx.PrefixArgs(), "lease", "list")
+		require.NoError(cx.t, e2e.SpawnWithExpectWithEnv(cmdArgs, cx.envMap, expect.ExpectedResponse{Value: "found 1 leases"}))
+	}
+}
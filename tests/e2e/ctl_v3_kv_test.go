@@ -137,6 +137,8 @@ func getTest(cx ctlCtx) {
 		{[]string{"key", "--prefix", "--sort-by=CREATE"}, kvs}, // ASCEND by default
 		{[]string{"key", "--prefix", "--order=DESCEND", "--sort-by=CREATE"}, revkvs},
 		{[]string{"key", "--prefix", "--order=DESCEND", "--sort-by=KEY"}, revkvs},
+		{[]string{"key", "--prefix", "--filter-key=2"}, kvs[1:2]},
+		{[]string{"key", "--prefix", "--filter-key=[13]$", "--filter-key-regex", "--limit=1"}, kvs[:1]},
 	}
 	for i, tt := range tests {
 		if err := ctlV3Get(cx, tt.args, tt.wkv...); err != nil {
@@ -35,6 +35,7 @@ import (
 
 func TestCtlV3MemberList(t *testing.T)        { testCtl(t, memberListTest) }
 func TestCtlV3MemberListWithHex(t *testing.T) { testCtl(t, memberListWithHexTest) }
+func TestCtlV3MemberListHealth(t *testing.T)  { testCtl(t, memberListHealthTest) }
 func TestCtlV3MemberListSerializable(t *testing.T) {
 	cfg := e2e.NewConfig(
 		e2e.WithClusterSize(1),
@@ -175,6 +176,16 @@ func ctlV3MemberList(cx ctlCtx) error {
 	return e2e.SpawnWithExpects(cmdArgs, cx.envMap, lines...)
 }
 
+func memberListHealthTest(cx ctlCtx) {
+	cmdArgs := append(cx.PrefixArgs(), "member", "list", "--health")
+	lines := make([]expect.ExpectedResponse, cx.cfg.ClusterSize)
+	for i := range lines {
+		// ID, Status, Name, Peer Addrs, Client Addrs, Is Learner, Health, DB Size, Raft Index, Raft Lag, Took, Error
+		lines[i] = expect.ExpectedResponse{Value: `started, .*, false, true, \d+ k?B, \d+, \d+, `, IsRegularExpr: true}
+	}
+	require.NoError(cx.t, e2e.SpawnWithExpects(cmdArgs, cx.envMap, lines...))
+}
+
 func getMemberList(cx ctlCtx, serializable bool) (etcdserverpb.MemberListResponse, error) {
 	cmdArgs := append(cx.PrefixArgs(), "--write-out", "json", "member", "list")
 	if serializable {
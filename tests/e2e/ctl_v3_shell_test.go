@@ -0,0 +1,57 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/tests/v3/framework/e2e"
+)
+
+func TestCtlV3Shell(t *testing.T) { testCtl(t, shellTest) }
+
+func shellTest(cx ctlCtx) {
+	historyFile := filepath.Join(cx.t.TempDir(), "history")
+	cmdArgs := append(cx.PrefixArgs(), "shell", "--history-file", historyFile)
+	proc, err := e2e.SpawnCmd(cmdArgs, cx.envMap)
+	require.NoError(cx.t, err)
+
+	for _, step := range []struct{ line, expected string }{
+		{line: "put foo bar", expected: "OK"},
+		{line: "get foo", expected: "bar"},
+		// tab completes the key
+		{line: "get f\t", expected: "bar"},
+		// a failing command doesn't end the shell
+		{line: "get", expected: "get command needs one argument"},
+		{line: "set write-out json", expected: "> "},
+		{line: "get foo", expected: `"kvs"`},
+		{line: "context", expected: "write-out = json"},
+		{line: "!2", expected: `"kvs"`},
+	} {
+		require.NoError(cx.t, proc.Send(step.line+"\r"))
+		_, err = proc.Expect(step.expected)
+		require.NoError(cx.t, err, step.line)
+	}
+	require.NoError(cx.t, proc.Send("exit\r"))
+	require.NoError(cx.t, proc.Close())
+
+	b, err := os.ReadFile(historyFile)
+	require.NoError(cx.t, err)
+	require.Equal(cx.t, "put foo bar\nget foo\nget\nset write-out json\nget foo\ncontext\nget foo\nexit\n", string(b))
+}
@@ -129,6 +129,67 @@ func snapshotStatusBeforeRestoreTest(cx ctlCtx) {
 	require.NoError(cx.t, serr)
 }
 
+// TestCtlV3SnapshotTrim ensures that a trimmed snapshot lacks the removed
+// keys and restores with its integrity hash.
+func TestCtlV3SnapshotTrim(t *testing.T) { testCtl(t, snapshotTrimTest) }
+
+func snapshotTrimTest(cx ctlCtx) {
+	for _, key := range []string{"trim/1", "trim/2", "keep"} {
+		require.NoError(cx.t, ctlV3Put(cx, key, "v", ""))
+	}
+	fpath := filepath.Join(cx.t.TempDir(), "snapshot")
+	require.NoError(cx.t, ctlV3SnapshotSave(cx, fpath))
+
+	trimmed := filepath.Join(cx.t.TempDir(), "trimmed")
+	require.NoError(cx.t, e2e.SpawnWithExpectWithEnv(
+		append(cx.PrefixArgsUtl(), "snapshot", "trim", "--output", trimmed, "--remove-prefix", "trim/", fpath),
+		cx.envMap,
+		expect.ExpectedResponse{Value: fmt.Sprintf("Trimmed snapshot saved at %s: removed 2 key revisions, reset 0 leases", trimmed)}))
+
+	st, err := getSnapshotStatus(cx, trimmed)
+	require.NoError(cx.t, err)
+	assert.Equal(cx.t, 1, st.TotalKey)
+
+	require.NoError(cx.t, e2e.SpawnWithExpectWithEnv(
+		append(cx.PrefixArgsUtl(), "snapshot", "restore", "--data-dir", cx.t.TempDir(), trimmed),
+		cx.envMap,
+		expect.ExpectedResponse{Value: "added member"}))
+}
+
+// TestCtlV3SnapshotExportImport ensures that a snapshot imported from an
+// export has the exported keys and restores with its integrity hash.
+func TestCtlV3SnapshotExportImport(t *testing.T) { testCtl(t, snapshotExportImportTest) }
+
+func snapshotExportImportTest(cx ctlCtx) {
+	for _, key := range []string{"a", "b", "a"} {
+		require.NoError(cx.t, ctlV3Put(cx, key, "v", ""))
+	}
+	require.NoError(cx.t, ctlV3Del(cx, []string{"b"}, 1))
+	fpath := filepath.Join(cx.t.TempDir(), "snapshot")
+	require.NoError(cx.t, ctlV3SnapshotSave(cx, fpath))
+
+	exported := filepath.Join(cx.t.TempDir(), "snapshot.json")
+	require.NoError(cx.t, e2e.SpawnWithExpectWithEnv(
+		append(cx.PrefixArgsUtl(), "snapshot", "export", "--format", "json", "--output", exported, fpath),
+		cx.envMap,
+		expect.ExpectedResponse{Value: fmt.Sprintf("Snapshot exported at %s: revision 5, 1 keys, 0 leases, 0 users, 0 roles", exported)}))
+
+	imported := filepath.Join(cx.t.TempDir(), "snapshot.db")
+	require.NoError(cx.t, e2e.SpawnWithExpectWithEnv(
+		append(cx.PrefixArgsUtl(), "snapshot", "import", "--format", "json", "--output", imported, exported),
+		cx.envMap,
+		expect.ExpectedResponse{Value: fmt.Sprintf("Snapshot imported at %s: revision 5, 1 keys, 0 leases, 0 users, 0 roles", imported)}))
+
+	st, err := getSnapshotStatus(cx, imported)
+	require.NoError(cx.t, err)
+	assert.Equal(cx.t, int64(5), st.Revision)
+
+	require.NoError(cx.t, e2e.SpawnWithExpectWithEnv(
+		append(cx.PrefixArgsUtl(), "snapshot", "restore", "--data-dir", cx.t.TempDir(), imported),
+		cx.envMap,
+		expect.ExpectedResponse{Value: "added member"}))
+}
+
 func ctlV3SnapshotSave(cx ctlCtx, fpath string) error {
 	cmdArgs := append(cx.PrefixArgs(), "snapshot", "save", fpath)
 	return e2e.SpawnWithExpectWithEnv(cmdArgs, cx.envMap, expect.ExpectedResponse{Value: fmt.Sprintf("Snapshot saved at %s", fpath)})
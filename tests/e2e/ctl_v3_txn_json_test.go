@@ -0,0 +1,63 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/pkg/v3/expect"
+	"go.etcd.io/etcd/tests/v3/framework/e2e"
+)
+
+func TestCtlV3TxnJSONFile(t *testing.T) { testCtl(t, txnJSONFileTest) }
+
+func txnJSONFileTest(cx ctlCtx) {
+	require.NoError(cx.t, ctlV3Put(cx, "key1", "val1", ""))
+
+	path := filepath.Join(cx.t.TempDir(), "txn.json")
+	doc := `{
+		"compare": [{"key": "key1", "target": "value", "result": "=", "value": "val1"}],
+		"success": [
+			{"put": {"key": "key1", "value": "val2"}},
+			{"get": {"key": "key", "prefix": true}}
+		],
+		"failure": [{"delete": {"key": "key1"}}]
+	}`
+	require.NoError(cx.t, os.WriteFile(path, []byte(doc), 0o600))
+
+	cmdArgs := append(cx.PrefixArgs(), "txn", "--file", path)
+	require.NoError(cx.t, e2e.SpawnWithExpects(cmdArgs, cx.envMap,
+		expect.ExpectedResponse{Value: "SUCCESS"},
+		expect.ExpectedResponse{Value: "OK"},
+		expect.ExpectedResponse{Value: "key1"},
+		expect.ExpectedResponse{Value: "val2"},
+	))
+
+	// the compare now fails, deleting key1
+	require.NoError(cx.t, e2e.SpawnWithExpects(cmdArgs, cx.envMap,
+		expect.ExpectedResponse{Value: "FAILURE"},
+		expect.ExpectedResponse{Value: "1"},
+	))
+	require.NoError(cx.t, ctlV3Get(cx, []string{"key", "--prefix"}))
+
+	// unknown fields are rejected
+	require.NoError(cx.t, os.WriteFile(path, []byte(`{"compares": []}`), 0o600))
+	err := e2e.SpawnWithExpects(cmdArgs, cx.envMap, expect.ExpectedResponse{Value: `unknown field "compares"`})
+	require.ErrorContains(cx.t, err, `unknown field "compares"`)
+}
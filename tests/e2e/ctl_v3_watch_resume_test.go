@@ -0,0 +1,72 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/tests/v3/framework/config"
+	"go.etcd.io/etcd/tests/v3/framework/e2e"
+)
+
+func TestCtlV3WatchResumeFile(t *testing.T) { testCtl(t, watchResumeFileTest) }
+
+func watchResumeFileTest(cx ctlCtx) {
+	path := filepath.Join(cx.t.TempDir(), "resume")
+	args := append(cx.PrefixArgs(), "watch", "--prefix", "key", "--rev", "2", "--resume-file", path)
+
+	// watchUntil runs the watch until it prints the expected lines and
+	// checkpoints the revision, and returns all the lines it printed.
+	watchUntil := func(rev int64, expected ...string) []string {
+		proc, err := e2e.SpawnCmd(args, cx.envMap)
+		require.NoError(cx.t, err)
+		for _, s := range expected {
+			_, err = proc.Expect(s)
+			require.NoError(cx.t, err)
+		}
+		require.Eventually(cx.t, func() bool {
+			b, err := os.ReadFile(path)
+			return err == nil && string(b) == fmt.Sprintf("%d\n", rev)
+		}, 5*time.Second, 10*time.Millisecond)
+		require.NoError(cx.t, proc.Stop())
+		return proc.Lines()
+	}
+
+	require.NoError(cx.t, ctlV3Put(cx, "key1", "val1", "")) // rev 2
+	require.NoError(cx.t, ctlV3Put(cx, "key2", "val2", "")) // rev 3
+	watchUntil(3, "val1", "val2")
+
+	// the watch resumes after the checkpointed revision, ignoring --rev
+	require.NoError(cx.t, ctlV3Put(cx, "key3", "val3", "")) // rev 4
+	lines := watchUntil(4, "val3")
+	require.NotContains(cx.t, strings.Join(lines, "\n"), "val1")
+
+	// the watch resumes from the compact revision when the checkpointed
+	// revision was compacted
+	require.NoError(cx.t, ctlV3Put(cx, "key4", "val4", "")) // rev 5
+	require.NoError(cx.t, ctlV3Put(cx, "key5", "val5", "")) // rev 6
+	_, err := cx.epc.Etcdctl().Compact(context.TODO(), 6, config.CompactOption{Physical: true})
+	require.NoError(cx.t, err)
+	lines = watchUntil(6, "resuming the watch from the compact revision 6", "val5")
+	require.NotContains(cx.t, strings.Join(lines, "\n"), "val4")
+}
@@ -194,6 +194,10 @@ var defaultHealthCheckConfigs = []healthCheckConfig{
 		expectedRespSubStrings: []string{
 			`[+]serializable_read ok`,
 			`[+]data_corruption ok`,
+			`[+]no_space ok`,
+			`[+]leader ok`,
+			`[+]apply_lag ok`,
+			`[+]backend_commit ok`,
 		},
 	},
 }
@@ -17,6 +17,7 @@ package e2e
 import (
 	"context"
 	"fmt"
+	"math"
 	"net/url"
 	"testing"
 	"time"
@@ -189,11 +190,18 @@ func TestNoMetricsMissing(t *testing.T) {
 			"etcd_network_client_grpc_received_bytes_total",
 			"etcd_network_client_grpc_sent_bytes_total",
 			"etcd_network_known_peers",
+			"etcd_server_apply_backlog",
+			"etcd_server_apply_backlog_threshold_crossed_total",
 			"etcd_server_apply_duration_seconds",
+			"etcd_server_caller_received_bytes_total",
+			"etcd_server_caller_requests_total",
+			"etcd_server_caller_sent_bytes_total",
 			"etcd_server_client_requests_total",
 			"etcd_server_go_version",
 			"etcd_server_has_leader",
 			"etcd_server_health_failures",
+			"etcd_server_health_last_failure_timestamp_seconds",
+			"etcd_server_health_last_success_timestamp_seconds",
 			"etcd_server_health_success",
 			"etcd_server_heartbeat_send_failures_total",
 			"etcd_server_id",
@@ -211,7 +219,9 @@ func TestNoMetricsMissing(t *testing.T) {
 			"etcd_server_slow_apply_total",
 			"etcd_server_slow_read_indexes_total",
 			"etcd_server_snapshot_apply_in_progress_total",
+			"etcd_server_time_since_last_snapshot_seconds",
 			"etcd_server_version",
+			"etcd_server_wal_entries_since_last_snapshot",
 			"etcd_snap_db_fsync_duration_seconds",
 			"etcd_snap_db_save_total_duration_seconds",
 			"etcd_snap_fsync_duration_seconds",
@@ -336,6 +346,50 @@ func TestNoMetricsMissing(t *testing.T) {
 	}
 }
 
+func TestGRPCHistogramBuckets(t *testing.T) {
+	e2e.BeforeTest(t)
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+
+	buckets := []float64{0.0001, 0.0005, 0.001, 0.01}
+	epc, err := e2e.NewEtcdProcessCluster(ctx, t,
+		e2e.WithClusterSize(1),
+		e2e.WithExtensiveMetrics(),
+		func(c *e2e.EtcdProcessClusterConfig) { c.ServerConfig.GRPCHistogramBuckets = buckets },
+	)
+	require.NoError(t, err)
+	defer epc.Close()
+
+	_, err = epc.Procs[0].Etcdctl().Status(ctx)
+	require.NoError(t, err)
+
+	metricsURL, err := url.JoinPath(epc.Procs[0].Config().ClientURL, "metrics")
+	require.NoError(t, err)
+	mfs, err := e2e.GetMetrics(metricsURL)
+	require.NoError(t, err)
+
+	mf, ok := mfs["grpc_server_handling_seconds"]
+	require.Truef(t, ok, "grpc_server_handling_seconds is missing")
+	var found bool
+	for _, m := range mf.GetMetric() {
+		labels := map[string]string{}
+		for _, l := range m.GetLabel() {
+			labels[l.GetName()] = l.GetValue()
+		}
+		if labels["grpc_service"] != "etcdserverpb.Maintenance" || labels["grpc_method"] != "Status" {
+			continue
+		}
+		found = true
+		var bounds []float64
+		for _, b := range m.GetHistogram().GetBucket() {
+			bounds = append(bounds, b.GetUpperBound())
+		}
+		require.Equal(t, append(buckets, math.Inf(1)), bounds)
+		require.Equal(t, uint64(1), m.GetHistogram().GetSampleCount())
+	}
+	require.Truef(t, found, "no grpc_server_handling_seconds series for the Maintenance Status RPC")
+}
+
 // formatMetrics is only for test purpose
 /*func formatMetrics(metrics []string) string {
 	quoted := make([]string, len(metrics))
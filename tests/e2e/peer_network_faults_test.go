@@ -0,0 +1,60 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !cluster_proxy
+
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/tests/v3/framework/config"
+	"go.etcd.io/etcd/tests/v3/framework/e2e"
+)
+
+// TestPeerNetworkFaultsDelayCommit tests that the latency injected in the
+// messages the leader sends to its followers delays the commit of a put.
+func TestPeerNetworkFaultsDelayCommit(t *testing.T) {
+	e2e.BeforeTest(t)
+	ctx, cancel := context.WithTimeout(t.Context(), 30*time.Second)
+	defer cancel()
+
+	latency := 500 * time.Millisecond
+	epc, err := e2e.NewEtcdProcessCluster(ctx, t,
+		e2e.WithClusterSize(3),
+		e2e.WithInitialLeaderIndex(0),
+		e2e.WithPeerNetworkFault(e2e.PeerNetworkFault{From: 0, To: 1, Latency: latency, Jitter: 50 * time.Millisecond}),
+		e2e.WithPeerNetworkFault(e2e.PeerNetworkFault{From: 0, To: 2, Latency: latency, Jitter: 50 * time.Millisecond}),
+	)
+	require.NoError(t, err)
+	defer epc.Close()
+
+	leader := epc.Procs[epc.WaitLeader(t)]
+	require.Equal(t, epc.Procs[0].Config().Name, leader.Config().Name)
+
+	start := time.Now()
+	require.NoError(t, leader.Etcdctl().Put(ctx, "foo", "bar", config.PutOptions{}))
+	require.GreaterOrEqual(t, time.Since(start), latency-50*time.Millisecond)
+
+	// the followers still replicate the put
+	for _, proc := range epc.Procs[1:] {
+		resp, err := proc.Etcdctl().Get(ctx, "foo", config.GetOptions{})
+		require.NoError(t, err)
+		require.Len(t, resp.Kvs, 1)
+	}
+}
@@ -37,6 +37,10 @@ type GetOptions struct {
 	Limit        int
 	Order        clientv3.SortOrder
 	SortBy       clientv3.SortTarget
+	MinCreateRev int64
+	MaxCreateRev int64
+	MinModRev    int64
+	MaxModRev    int64
 	Timeout      time.Duration
 }
 
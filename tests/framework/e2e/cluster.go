@@ -146,6 +146,9 @@ type EtcdProcessClusterConfig struct {
 	GoFailClientTimeout time.Duration
 	LazyFSEnabled       bool
 	PeerProxy           bool
+	// PeerNetworkFaults are the faults injected in the messages sent from
+	// a member to another.
+	PeerNetworkFaults []PeerNetworkFault
 
 	// Process config
 
@@ -378,6 +381,19 @@ func WithPeerProxy(enabled bool) EPClusterOption {
 	return func(c *EtcdProcessClusterConfig) { c.PeerProxy = enabled }
 }
 
+// PeerNetworkFault delays and drops the messages sent from the member From to
+// the member To, by their index in the cluster.
+type PeerNetworkFault struct {
+	From, To int
+	Latency  time.Duration
+	Jitter   time.Duration
+	DropRate float64
+}
+
+func WithPeerNetworkFault(fault PeerNetworkFault) EPClusterOption {
+	return func(c *EtcdProcessClusterConfig) { c.PeerNetworkFaults = append(c.PeerNetworkFaults, fault) }
+}
+
 func WithClientHTTPSeparate(enabled bool) EPClusterOption {
 	return func(c *EtcdProcessClusterConfig) { c.ClientHTTPSeparate = enabled }
 }
@@ -526,7 +542,6 @@ func (cfg *EtcdProcessClusterConfig) EtcdServerProcessConfig(tb testing.TB, i in
 	clientPort := port
 	peerPort := port + 1
 	metricsPort := port + 2
-	peer2Port := port + 3
 	clientHTTPPort := port + 4
 
 	if cfg.Client.ConnectionType == ClientTLSAndNonTLS {
@@ -538,13 +553,12 @@ func (cfg *EtcdProcessClusterConfig) EtcdServerProcessConfig(tb testing.TB, i in
 	}
 
 	peerListenURL := url.URL{Scheme: cfg.PeerScheme(), Host: fmt.Sprintf("localhost:%d", peerPort)}
-	peerAdvertiseURL := url.URL{Scheme: cfg.PeerScheme(), Host: fmt.Sprintf("localhost:%d", peerPort)}
+	peerAdvertiseURL := cfg.peerAdvertiseURL(i)
 	var proxyCfg *proxy.ServerConfig
 	if cfg.PeerProxy {
 		if !cfg.IsPeerTLS {
 			panic("Can't use peer proxy without peer TLS as it can result in malformed packets")
 		}
-		peerAdvertiseURL.Host = fmt.Sprintf("localhost:%d", peer2Port)
 		proxyCfg = &proxy.ServerConfig{
 			Logger: zap.NewNop(),
 			To:     peerListenURL,
@@ -609,6 +623,17 @@ func (cfg *EtcdProcessClusterConfig) EtcdServerProcessConfig(tb testing.TB, i in
 		args = append(args, "--discovery="+cfg.Discovery)
 	}
 
+	var faults []string
+	for _, f := range cfg.PeerNetworkFaults {
+		if f.From == i {
+			to := cfg.peerAdvertiseURL(f.To)
+			faults = append(faults, fmt.Sprintf("%s=%v/%v/%v", to.String(), f.Latency, f.Jitter, f.DropRate))
+		}
+	}
+	if len(faults) > 0 {
+		args = append(args, "--peer-network-faults="+strings.Join(faults, ","))
+	}
+
 	execPath := cfg.binaryPath(i)
 
 	if cfg.ServerConfig.SnapshotCatchUpEntries != etcdserver.DefaultSnapshotCatchUpEntries {
@@ -668,6 +693,16 @@ func (cfg *EtcdProcessClusterConfig) EtcdServerProcessConfig(tb testing.TB, i in
 	}
 }
 
+// peerAdvertiseURL returns the peer URL the ith member advertises, the one
+// of its proxy if the peer traffic is proxied.
+func (cfg *EtcdProcessClusterConfig) peerAdvertiseURL(i int) url.URL {
+	port := cfg.BasePort + 5*i + 1
+	if cfg.PeerProxy {
+		port += 2
+	}
+	return url.URL{Scheme: cfg.PeerScheme(), Host: fmt.Sprintf("localhost:%d", port)}
+}
+
 func (cfg *EtcdProcessClusterConfig) binaryPath(i int) string {
 	var execPath string
 	switch cfg.Version {
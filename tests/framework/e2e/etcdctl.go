@@ -124,6 +124,18 @@ func (ctl *EtcdctlV3) Get(ctx context.Context, key string, o config.GetOptions)
 	if o.FromKey {
 		args = append(args, "--from-key")
 	}
+	if o.MinCreateRev != 0 {
+		args = append(args, fmt.Sprintf("--min-create-rev=%d", o.MinCreateRev))
+	}
+	if o.MaxCreateRev != 0 {
+		args = append(args, fmt.Sprintf("--max-create-rev=%d", o.MaxCreateRev))
+	}
+	if o.MinModRev != 0 {
+		args = append(args, fmt.Sprintf("--min-mod-rev=%d", o.MinModRev))
+	}
+	if o.MaxModRev != 0 {
+		args = append(args, fmt.Sprintf("--max-mod-rev=%d", o.MaxModRev))
+	}
 	if o.CountOnly {
 		args = append(args, "-w", "fields", "--count-only")
 	} else {
@@ -65,6 +65,7 @@ import (
 	lockpb "go.etcd.io/etcd/server/v3/etcdserver/api/v3lock/v3lockpb"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3rpc"
 	"go.etcd.io/etcd/server/v3/features"
+	"go.etcd.io/etcd/server/v3/storage/encryption"
 	"go.etcd.io/etcd/server/v3/verify"
 	framecfg "go.etcd.io/etcd/tests/v3/framework/config"
 	"go.etcd.io/etcd/tests/v3/framework/testutils"
@@ -142,13 +143,53 @@ type ClusterConfig struct {
 	DiscoveryURL string
 
 	AuthToken string
+	// AuthLockoutUserFailures is the number of authentication failures
+	// locking out a user, 0 disabling the lockout.
+	AuthLockoutUserFailures int
+	// AuthPasswordMaxAge and AuthPasswordGracePeriod configure the expiry
+	// of the passwords, 0 disabling it.
+	AuthPasswordMaxAge      time.Duration
+	AuthPasswordGracePeriod time.Duration
 
 	QuotaBackendBytes    int64
+	KeyQuotas            []string
+	Namespaces           []string
 	BackendBatchInterval time.Duration
 
+	// MirrorTargetEndpoints enables the mirroring of the keys with
+	// MirrorPrefix to the cluster of these endpoints.
+	MirrorTargetEndpoints []string
+	MirrorPrefix          string
+
+	AutoCompactionMode      string
+	AutoCompactionRetention time.Duration
+
 	MaxTxnOps       uint
 	MaxRequestBytes uint
 
+	RequestDeadlineMargin time.Duration
+	MaxCallerLabels       int
+
+	ClientRateLimitQPS   float64
+	ClientRateLimitBurst int
+
+	QoSMaxInflightRequests int
+
+	ClientIdentityMetrics bool
+
+	MaxWatchStreams      int
+	MaxWatchersPerStream int
+
+	SnapshotSendRateLimit int64
+
+	CompactionControlKey string
+
+	KeyAccessSampleRate float64
+
+	// EncryptionKEKFile encrypts the backends of the members with the key
+	// encryption keys of the file.
+	EncryptionKEKFile string
+
 	SnapshotCount          uint64
 	SnapshotCatchUpEntries uint64
 
@@ -172,11 +213,22 @@ type ClusterConfig struct {
 	LeaseCheckpointInterval time.Duration
 	LeaseCheckpointPersist  bool
 
+	EnableParallelApply bool
+
 	WatchProgressNotifyInterval time.Duration
 	MaxLearners                 int
 	DisableStrictReconfigCheck  bool
 	CorruptCheckTime            time.Duration
 	Metrics                     string
+
+	LearnerAutoPromoteDuration time.Duration
+	LearnerAutoPromoteMaxLag   uint64
+	FollowerLagAlarmThreshold  uint64
+	FollowerLagAlarmDuration   time.Duration
+	LeaderLeaseReads           bool
+	LeaseStewardPrefixes       []string
+	LeaseStewardInterval       time.Duration
+	LeaseStewardDelete         bool
 }
 
 type Cluster struct {
@@ -270,12 +322,33 @@ func (c *Cluster) MustNewMember(t testutil.TB) *Member {
 			Name:                        fmt.Sprintf("m%v", memberNumber),
 			MemberNumber:                memberNumber,
 			AuthToken:                   c.Cfg.AuthToken,
+			AuthLockoutUserFailures:     c.Cfg.AuthLockoutUserFailures,
+			AuthPasswordMaxAge:          c.Cfg.AuthPasswordMaxAge,
+			AuthPasswordGracePeriod:     c.Cfg.AuthPasswordGracePeriod,
 			PeerTLS:                     c.Cfg.PeerTLS,
 			ClientTLS:                   c.Cfg.ClientTLS,
 			QuotaBackendBytes:           c.Cfg.QuotaBackendBytes,
+			KeyQuotas:                   c.Cfg.KeyQuotas,
+			Namespaces:                  c.Cfg.Namespaces,
+			MirrorTargetEndpoints:       c.Cfg.MirrorTargetEndpoints,
+			MirrorPrefix:                c.Cfg.MirrorPrefix,
 			BackendBatchInterval:        c.Cfg.BackendBatchInterval,
+			AutoCompactionMode:          c.Cfg.AutoCompactionMode,
+			AutoCompactionRetention:     c.Cfg.AutoCompactionRetention,
 			MaxTxnOps:                   c.Cfg.MaxTxnOps,
 			MaxRequestBytes:             c.Cfg.MaxRequestBytes,
+			RequestDeadlineMargin:       c.Cfg.RequestDeadlineMargin,
+			MaxCallerLabels:             c.Cfg.MaxCallerLabels,
+			ClientRateLimitQPS:          c.Cfg.ClientRateLimitQPS,
+			ClientRateLimitBurst:        c.Cfg.ClientRateLimitBurst,
+			QoSMaxInflightRequests:      c.Cfg.QoSMaxInflightRequests,
+			ClientIdentityMetrics:       c.Cfg.ClientIdentityMetrics,
+			MaxWatchStreams:             c.Cfg.MaxWatchStreams,
+			MaxWatchersPerStream:        c.Cfg.MaxWatchersPerStream,
+			SnapshotSendRateLimit:       c.Cfg.SnapshotSendRateLimit,
+			CompactionControlKey:        c.Cfg.CompactionControlKey,
+			KeyAccessSampleRate:         c.Cfg.KeyAccessSampleRate,
+			EncryptionKEKFile:           c.Cfg.EncryptionKEKFile,
 			SnapshotCount:               c.Cfg.SnapshotCount,
 			SnapshotCatchUpEntries:      c.Cfg.SnapshotCatchUpEntries,
 			GRPCKeepAliveMinTime:        c.Cfg.GRPCKeepAliveMinTime,
@@ -290,11 +363,20 @@ func (c *Cluster) MustNewMember(t testutil.TB) *Member {
 			EnableLeaseCheckpoint:       c.Cfg.EnableLeaseCheckpoint,
 			LeaseCheckpointInterval:     c.Cfg.LeaseCheckpointInterval,
 			LeaseCheckpointPersist:      c.Cfg.LeaseCheckpointPersist,
+			EnableParallelApply:         c.Cfg.EnableParallelApply,
 			WatchProgressNotifyInterval: c.Cfg.WatchProgressNotifyInterval,
 			MaxLearners:                 c.Cfg.MaxLearners,
 			DisableStrictReconfigCheck:  c.Cfg.DisableStrictReconfigCheck,
 			CorruptCheckTime:            c.Cfg.CorruptCheckTime,
 			Metrics:                     c.Cfg.Metrics,
+			LearnerAutoPromoteDuration:  c.Cfg.LearnerAutoPromoteDuration,
+			LearnerAutoPromoteMaxLag:    c.Cfg.LearnerAutoPromoteMaxLag,
+			FollowerLagAlarmThreshold:   c.Cfg.FollowerLagAlarmThreshold,
+			FollowerLagAlarmDuration:    c.Cfg.FollowerLagAlarmDuration,
+			LeaderLeaseReads:            c.Cfg.LeaderLeaseReads,
+			LeaseStewardPrefixes:        c.Cfg.LeaseStewardPrefixes,
+			LeaseStewardInterval:        c.Cfg.LeaseStewardInterval,
+			LeaseStewardDelete:          c.Cfg.LeaseStewardDelete,
 		})
 	m.DiscoveryURL = c.Cfg.DiscoveryURL
 	return m
@@ -598,10 +680,31 @@ type MemberConfig struct {
 	PeerTLS                     *transport.TLSInfo
 	ClientTLS                   *transport.TLSInfo
 	AuthToken                   string
+	AuthLockoutUserFailures     int
+	AuthPasswordMaxAge          time.Duration
+	AuthPasswordGracePeriod     time.Duration
 	QuotaBackendBytes           int64
+	KeyQuotas                   []string
+	Namespaces                  []string
+	MirrorTargetEndpoints       []string
+	MirrorPrefix                string
 	BackendBatchInterval        time.Duration
+	AutoCompactionMode          string
+	AutoCompactionRetention     time.Duration
 	MaxTxnOps                   uint
 	MaxRequestBytes             uint
+	RequestDeadlineMargin       time.Duration
+	MaxCallerLabels             int
+	ClientRateLimitQPS          float64
+	ClientRateLimitBurst        int
+	QoSMaxInflightRequests      int
+	ClientIdentityMetrics       bool
+	MaxWatchStreams             int
+	MaxWatchersPerStream        int
+	SnapshotSendRateLimit       int64
+	CompactionControlKey        string
+	KeyAccessSampleRate         float64
+	EncryptionKEKFile           string
 	SnapshotCount               uint64
 	SnapshotCatchUpEntries      uint64
 	GRPCKeepAliveMinTime        time.Duration
@@ -616,11 +719,20 @@ type MemberConfig struct {
 	EnableLeaseCheckpoint       bool
 	LeaseCheckpointInterval     time.Duration
 	LeaseCheckpointPersist      bool
+	EnableParallelApply         bool
 	WatchProgressNotifyInterval time.Duration
 	MaxLearners                 int
 	DisableStrictReconfigCheck  bool
 	CorruptCheckTime            time.Duration
 	Metrics                     string
+	LearnerAutoPromoteDuration  time.Duration
+	LearnerAutoPromoteMaxLag    uint64
+	FollowerLagAlarmThreshold   uint64
+	FollowerLagAlarmDuration    time.Duration
+	LeaderLeaseReads            bool
+	LeaseStewardPrefixes        []string
+	LeaseStewardInterval        time.Duration
+	LeaseStewardDelete          bool
 }
 
 // MustNewMember return an inited member with the given name. If peerTLS is
@@ -673,7 +785,17 @@ func MustNewMember(t testutil.TB, mcfg MemberConfig) *Member {
 	m.TickMs = uint(framecfg.TickDuration / time.Millisecond)
 	m.PreVote = true
 	m.QuotaBackendBytes = mcfg.QuotaBackendBytes
+	m.KeyQuotas = mcfg.KeyQuotas
+	m.Namespaces = mcfg.Namespaces
+	m.NamespacesKeyPrefix = embed.DefaultNamespacesKeyPrefix
+	m.MirrorTargetEndpoints = mcfg.MirrorTargetEndpoints
+	m.MirrorPrefix = mcfg.MirrorPrefix
+	m.MirrorDestPrefix = mcfg.MirrorPrefix
+	m.MirrorCheckpointKey = embed.DefaultMirrorCheckpointKey
+	m.MirrorMaxTxnOps = embed.DefaultMirrorMaxTxnOps
 	m.BackendBatchInterval = mcfg.BackendBatchInterval
+	m.AutoCompactionMode = mcfg.AutoCompactionMode
+	m.AutoCompactionRetention = mcfg.AutoCompactionRetention
 	m.MaxTxnOps = mcfg.MaxTxnOps
 	if m.MaxTxnOps == 0 {
 		m.MaxTxnOps = embed.DefaultMaxTxnOps
@@ -682,6 +804,20 @@ func MustNewMember(t testutil.TB, mcfg MemberConfig) *Member {
 	if m.MaxRequestBytes == 0 {
 		m.MaxRequestBytes = embed.DefaultMaxRequestBytes
 	}
+	m.RequestDeadlineMargin = mcfg.RequestDeadlineMargin
+	m.MaxCallerLabels = mcfg.MaxCallerLabels
+	m.ClientRateLimitQPS = mcfg.ClientRateLimitQPS
+	m.ClientRateLimitBurst = mcfg.ClientRateLimitBurst
+	m.QoSMaxInflightRequests = mcfg.QoSMaxInflightRequests
+	m.ClientIdentityMetrics = mcfg.ClientIdentityMetrics
+	m.MaxWatchStreams = mcfg.MaxWatchStreams
+	m.MaxWatchersPerStream = mcfg.MaxWatchersPerStream
+	m.SnapshotSendRateLimit = mcfg.SnapshotSendRateLimit
+	if m.MaxCallerLabels == 0 {
+		m.MaxCallerLabels = embed.DefaultMaxCallerLabels
+	}
+	m.CompactionControlKey = mcfg.CompactionControlKey
+	m.KeyAccessSampleRate = mcfg.KeyAccessSampleRate
 	m.SnapshotCount = etcdserver.DefaultSnapshotCount
 	if mcfg.SnapshotCount != 0 {
 		m.SnapshotCount = mcfg.SnapshotCount
@@ -696,6 +832,9 @@ func MustNewMember(t testutil.TB, mcfg MemberConfig) *Member {
 	if mcfg.AuthToken != "" {
 		m.AuthToken = mcfg.AuthToken
 	}
+	m.AuthLockoutUserFailures = mcfg.AuthLockoutUserFailures
+	m.AuthPasswordMaxAge = mcfg.AuthPasswordMaxAge
+	m.AuthPasswordGracePeriod = mcfg.AuthPasswordGracePeriod
 
 	m.BcryptCost = uint(bcrypt.MinCost) // use min bcrypt cost to speedy up integration testing
 
@@ -734,16 +873,44 @@ func MustNewMember(t testutil.TB, mcfg MemberConfig) *Member {
 		m.MaxLearners = mcfg.MaxLearners
 	}
 	m.Metrics = mcfg.Metrics
+	m.LearnerAutoPromoteDuration = mcfg.LearnerAutoPromoteDuration
+	m.LearnerAutoPromoteMaxLag = embed.DefaultLearnerAutoPromoteMaxLag
+	if mcfg.LearnerAutoPromoteMaxLag != 0 {
+		m.LearnerAutoPromoteMaxLag = mcfg.LearnerAutoPromoteMaxLag
+	}
+	m.FollowerLagAlarmThreshold = mcfg.FollowerLagAlarmThreshold
+	m.FollowerLagAlarmDuration = embed.DefaultFollowerLagAlarmDuration
+	if mcfg.FollowerLagAlarmDuration != 0 {
+		m.FollowerLagAlarmDuration = mcfg.FollowerLagAlarmDuration
+	}
+	m.LeaderLeaseReads = mcfg.LeaderLeaseReads
+	m.LeaseStewardPrefixes = mcfg.LeaseStewardPrefixes
+	m.LeaseStewardInterval = embed.DefaultLeaseStewardInterval
+	if mcfg.LeaseStewardInterval != 0 {
+		m.LeaseStewardInterval = mcfg.LeaseStewardInterval
+	}
+	m.LeaseStewardDelete = mcfg.LeaseStewardDelete
+	// the default drift exceeds the election timeout of the test members.
+	m.LeaderLeaseClockDrift = m.ServerConfig.ElectionTimeout() / 10
 	m.V2Deprecation = config.V2_DEPR_DEFAULT
 	m.GRPCServerRecorder = &grpctesting.GRPCRecorder{}
 
 	m.Logger, m.LogObserver = memberLogger(t, mcfg.Name)
 	m.ServerFeatureGate = features.NewDefaultServerFeatureGate(m.Name, m.Logger)
-	featureGates := fmt.Sprintf("LeaseCheckpoint=%v,LeaseCheckpointPersist=%v", mcfg.EnableLeaseCheckpoint, mcfg.LeaseCheckpointPersist)
+	featureGates := fmt.Sprintf("LeaseCheckpoint=%v,LeaseCheckpointPersist=%v,ParallelApply=%v", mcfg.EnableLeaseCheckpoint, mcfg.LeaseCheckpointPersist, mcfg.EnableParallelApply)
 	if err := m.ServerFeatureGate.(featuregate.MutableFeatureGate).Set(featureGates); err != nil {
 		t.Fatalf("Set FeatureGate FAILED: %v", err)
 	}
 
+	if mcfg.EncryptionKEKFile != "" {
+		m.EncryptionKEKFile = mcfg.EncryptionKEKFile
+		provider, err := encryption.NewFileKEKProvider(mcfg.EncryptionKEKFile)
+		if err != nil {
+			t.Fatalf("NewFileKEKProvider FAILED: %v", err)
+		}
+		m.EncryptionCodec = encryption.NewCodec(m.Logger, provider)
+	}
+
 	m.StrictReconfigCheck = !mcfg.DisableStrictReconfigCheck
 	if err := m.listenGRPC(); err != nil {
 		t.Fatalf("listenGRPC FAILED: %v", err)
@@ -1046,6 +1213,7 @@ func (m *Member) Launch() error {
 		etcdhttp.HandleVersion(handler, m.Server)
 		etcdhttp.HandleMetrics(handler)
 		etcdhttp.HandleHealth(m.Logger, handler, m.Server)
+		etcdhttp.HandleWatermarks(handler, m.Server)
 		hs := &httptest.Server{
 			Listener: ln,
 			Config: &http.Server{
@@ -64,7 +64,7 @@ func ToGRPC(c *clientv3.Client) GRPCAPI {
 	c.Watcher = namespace.NewWatcher(c.Watcher, proxyNamespace)
 	c.Lease = namespace.NewLease(c.Lease, proxyNamespace)
 	// test coalescing/caching proxy
-	kvp, kvpch := grpcproxy.NewKvProxy(c)
+	kvp, kvpch := grpcproxy.NewKvProxyWithConfig(ctx, c, grpcproxy.KvProxyConfig{CacheWatchInvalidation: true})
 	wp, wpch := grpcproxy.NewWatchProxy(ctx, lg, c)
 	lp, lpch := grpcproxy.NewLeaseProxy(ctx, c)
 	mp := grpcproxy.NewMaintenanceProxy(c)
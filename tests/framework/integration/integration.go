@@ -162,6 +162,18 @@ func (c integrationClient) Get(ctx context.Context, key string, o config.GetOpti
 	if o.CountOnly {
 		clientOpts = append(clientOpts, clientv3.WithCountOnly())
 	}
+	if o.MinCreateRev != 0 {
+		clientOpts = append(clientOpts, clientv3.WithMinCreateRev(o.MinCreateRev))
+	}
+	if o.MaxCreateRev != 0 {
+		clientOpts = append(clientOpts, clientv3.WithMaxCreateRev(o.MaxCreateRev))
+	}
+	if o.MinModRev != 0 {
+		clientOpts = append(clientOpts, clientv3.WithMinModRev(o.MinModRev))
+	}
+	if o.MaxModRev != 0 {
+		clientOpts = append(clientOpts, clientv3.WithMaxModRev(o.MaxModRev))
+	}
 	if o.SortBy != clientv3.SortByKey || o.Order != clientv3.SortNone {
 		clientOpts = append(clientOpts, clientv3.WithSort(o.SortBy, o.Order))
 	}
@@ -271,6 +283,10 @@ func (c integrationClient) TimeToLive(ctx context.Context, id clientv3.LeaseID,
 	return c.Client.TimeToLive(ctx, id, leaseOpts...)
 }
 
+func (c integrationClient) Grant(ctx context.Context, ttl int64) (*clientv3.LeaseGrantResponse, error) {
+	return c.Client.Grant(ctx, ttl)
+}
+
 func (c integrationClient) Leases(ctx context.Context) (*clientv3.LeaseLeasesResponse, error) {
 	return c.Client.Leases(ctx)
 }
@@ -0,0 +1,86 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	integration2 "go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestBulkKV ensures that batched operations are applied in the order they
+// were queued, including the ones writing the same keys.
+func TestBulkKV(t *testing.T) {
+	integration2.BeforeTest(t)
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	cli := clus.RandClient()
+	bkv := clientv3.NewBulkKV(cli.KV, clientv3.BulkConfig{})
+	defer bkv.Close()
+	ctx := context.TODO()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				_, err := bkv.Put(ctx, fmt.Sprintf("foo/%d/%d", i, j), "v")
+				assert.NoError(t, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	resp, err := cli.Get(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithCountOnly())
+	require.NoError(t, err)
+	require.Equal(t, int64(500), resp.Count)
+
+	var results []*clientv3.BulkResult
+	for _, op := range []clientv3.Op{
+		clientv3.OpPut("bar", "1"),
+		clientv3.OpPut("bar", "2"),
+		clientv3.OpDelete("foo/", clientv3.WithPrefix()),
+		clientv3.OpPut("foo/0/0", "3"),
+	} {
+		r, err := bkv.Enqueue(ctx, op)
+		require.NoError(t, err)
+		results = append(results, r)
+	}
+	require.NoError(t, bkv.Flush(ctx))
+	for _, r := range results {
+		_, err := r.Wait(ctx)
+		require.NoError(t, err)
+	}
+	del, err := results[2].Wait(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(500), del.Del().Deleted)
+
+	resp, err = cli.Get(ctx, "bar")
+	require.NoError(t, err)
+	require.Equal(t, "2", string(resp.Kvs[0].Value))
+	resp, err = cli.Get(ctx, "foo/", clientv3.WithPrefix())
+	require.NoError(t, err)
+	require.Len(t, resp.Kvs, 1)
+	require.Equal(t, "3", string(resp.Kvs[0].Value))
+}
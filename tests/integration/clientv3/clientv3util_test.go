@@ -0,0 +1,405 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/clientv3util"
+	integration2 "go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+func TestGetOrWaitExistingKey(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	cli := clus.RandClient()
+	_, err := cli.Put(context.TODO(), "config", "v1")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	kv, err := clientv3util.GetOrWait(ctx, cli, "config")
+	require.NoError(t, err)
+	require.Equal(t, "v1", string(kv.Value))
+}
+
+func TestGetOrWaitKeyCreatedLater(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	cli := clus.RandClient()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	type result struct {
+		kv  *mvccpb.KeyValue
+		err error
+	}
+	donec := make(chan result, 1)
+	go func() {
+		kv, err := clientv3util.GetOrWait(ctx, cli, "config")
+		donec <- result{kv, err}
+	}()
+
+	select {
+	case r := <-donec:
+		t.Fatalf("GetOrWait returned before the key was created: %v, %v", r.kv, r.err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// a delete of the missing key must not wake up the waiter.
+	_, err := cli.Delete(context.TODO(), "config")
+	require.NoError(t, err)
+	_, err = cli.Put(context.TODO(), "other", "x")
+	require.NoError(t, err)
+	_, err = cli.Put(context.TODO(), "config", "v1")
+	require.NoError(t, err)
+
+	select {
+	case r := <-donec:
+		require.NoError(t, r.err)
+		require.Equal(t, "config", string(r.kv.Key))
+		require.Equal(t, "v1", string(r.kv.Value))
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetOrWait did not return after the key was created")
+	}
+}
+
+func TestGetOrWaitContextExpired(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	_, err := clientv3util.GetOrWait(ctx, clus.RandClient(), "config")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestGetOrWaitPermissionDenied(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	cli := clus.RandClient()
+	_, err := cli.UserAdd(context.TODO(), "user", "123")
+	require.NoError(t, err)
+	authSetupRoot(t, cli.Auth)
+
+	cfg := clientv3.Config{
+		Endpoints:   cli.Endpoints(),
+		DialTimeout: 5 * time.Second,
+		Username:    "user",
+		Password:    "123",
+	}
+	ucli, err := integration2.NewClient(t, cfg)
+	require.NoError(t, err)
+	defer ucli.Close()
+
+	// the denied permission is returned instead of being retried until the
+	// context expires.
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = clientv3util.GetOrWait(ctx, ucli, "config")
+	require.ErrorIs(t, err, rpctypes.ErrPermissionDenied)
+}
+
+// compactingKV compacts past the revision of its first Get, before the caller
+// gets to watch after it.
+type compactingKV struct {
+	clientv3.KV
+	cli  *clientv3.Client
+	once sync.Once
+	err  error
+}
+
+func (kv *compactingKV) Get(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	resp, err := kv.KV.Get(ctx, key, opts...)
+	if err != nil {
+		return nil, err
+	}
+	kv.once.Do(func() {
+		var presp *clientv3.PutResponse
+		for i := 0; i < 2; i++ {
+			if presp, kv.err = kv.cli.Put(ctx, "other", "x"); kv.err != nil {
+				return
+			}
+		}
+		_, kv.err = kv.cli.Compact(ctx, presp.Header.Revision)
+	})
+	return resp, nil
+}
+
+func TestGetOrWaitCompacted(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	cli := clus.RandClient()
+	wcli, err := integration2.NewClient(t, clientv3.Config{Endpoints: cli.Endpoints(), DialTimeout: 5 * time.Second})
+	require.NoError(t, err)
+	defer wcli.Close()
+	ckv := &compactingKV{KV: wcli.KV, cli: cli}
+	wcli.KV = ckv
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	type result struct {
+		kv  *mvccpb.KeyValue
+		err error
+	}
+	donec := make(chan result, 1)
+	go func() {
+		kv, err := clientv3util.GetOrWait(ctx, wcli, "config")
+		donec <- result{kv, err}
+	}()
+
+	// the watch after the compacted revision of the first Get is retried
+	// instead of failing.
+	select {
+	case r := <-donec:
+		t.Fatalf("GetOrWait returned before the key was created: %v, %v", r.kv, r.err)
+	case <-time.After(500 * time.Millisecond):
+	}
+	require.NoError(t, ckv.err)
+
+	_, err = cli.Put(context.TODO(), "config", "v1")
+	require.NoError(t, err)
+	select {
+	case r := <-donec:
+		require.NoError(t, r.err)
+		require.Equal(t, "v1", string(r.kv.Value))
+	case <-time.After(5 * time.Second):
+		t.Fatal("GetOrWait did not return after the key was created")
+	}
+}
+
+func TestDiffRevisions(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	cli := clus.RandClient()
+	ctx := context.TODO()
+	for _, k := range []string{"a/unchanged", "a/modified", "a/deleted", "a/recreated", "b/outside"} {
+		_, err := cli.Put(ctx, k, "v1")
+		require.NoError(t, err)
+	}
+	resp, err := cli.Get(ctx, "a/")
+	require.NoError(t, err)
+	revA := resp.Header.Revision
+
+	_, err = cli.Put(ctx, "a/modified", "v2")
+	require.NoError(t, err)
+	_, err = cli.Delete(ctx, "a/deleted")
+	require.NoError(t, err)
+	_, err = cli.Delete(ctx, "a/recreated")
+	require.NoError(t, err)
+	_, err = cli.Put(ctx, "a/recreated", "v2")
+	require.NoError(t, err)
+	_, err = cli.Put(ctx, "a/added", "v1")
+	require.NoError(t, err)
+	_, err = cli.Put(ctx, "b/outside", "v2")
+	require.NoError(t, err)
+	resp, err = cli.Get(ctx, "a/")
+	require.NoError(t, err)
+	revB := resp.Header.Revision
+
+	keys := func(kvs []*mvccpb.KeyValue) (ks []string) {
+		for _, kv := range kvs {
+			ks = append(ks, string(kv.Key))
+		}
+		return ks
+	}
+
+	diff, err := clientv3util.DiffRevisions(ctx, cli, "a/", revA, revB)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/added"}, keys(diff.Added))
+	require.Equal(t, []string{"a/modified", "a/recreated"}, keys(diff.Modified))
+	require.Equal(t, "v2", string(diff.Modified[0].Value))
+	require.Equal(t, []string{"a/deleted"}, keys(diff.Deleted))
+	require.Equal(t, "v1", string(diff.Deleted[0].Value))
+
+	// revision 0 stands for the current revision.
+	current, err := clientv3util.DiffRevisions(ctx, cli, "a/", revA, 0)
+	require.NoError(t, err)
+	require.Equal(t, diff, current)
+
+	// swapping the revisions swaps added and deleted keys.
+	reverse, err := clientv3util.DiffRevisions(ctx, cli, "a/", revB, revA)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/deleted"}, keys(reverse.Added))
+	require.Equal(t, []string{"a/added"}, keys(reverse.Deleted))
+
+	none, err := clientv3util.DiffRevisions(ctx, cli, "a/", revB, revB)
+	require.NoError(t, err)
+	require.Empty(t, none.Added)
+	require.Empty(t, none.Modified)
+	require.Empty(t, none.Deleted)
+}
+
+func TestDiffRevisionsCompacted(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	cli := clus.RandClient()
+	ctx := context.TODO()
+	presp, err := cli.Put(ctx, "a/key", "v1")
+	require.NoError(t, err)
+	revA := presp.Header.Revision
+	presp, err = cli.Put(ctx, "a/key", "v2")
+	require.NoError(t, err)
+	_, err = cli.Compact(ctx, presp.Header.Revision)
+	require.NoError(t, err)
+
+	_, err = clientv3util.DiffRevisions(ctx, cli, "a/", revA, 0)
+	require.ErrorIs(t, err, rpctypes.ErrCompacted)
+	require.ErrorContains(t, err, "compacted")
+}
+
+func TestBulkPutChunked(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1, MaxTxnOps: 4})
+	defer clus.Terminate(t)
+
+	cli := clus.RandClient()
+	ctx := context.TODO()
+	startRev := clus.Members[0].Server.KV().Rev()
+
+	var puts []clientv3.Op
+	for i := 0; i < 10; i++ {
+		puts = append(puts, clientv3.OpPut(fmt.Sprintf("bulk/%02d", i), fmt.Sprint(i)))
+	}
+	var progress []int
+	n, err := clientv3util.BulkPut(ctx, cli, puts,
+		clientv3util.WithChunkSize(4),
+		clientv3util.WithProgress(func(done, total int) {
+			require.Equal(t, len(puts), total)
+			progress = append(progress, done)
+		}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, len(puts), n)
+	require.Equal(t, []int{4, 8, 10}, progress)
+
+	resp, err := cli.Get(ctx, "bulk/", clientv3.WithPrefix())
+	require.NoError(t, err)
+	require.Len(t, resp.Kvs, len(puts))
+	for i, kv := range resp.Kvs {
+		require.Equal(t, fmt.Sprint(i), string(kv.Value))
+		// every chunk is committed in its own transaction, in order
+		require.Equal(t, startRev+1+int64(i/4), kv.ModRevision)
+	}
+
+	// chunks larger than the server limit fail before anything is applied
+	n, err = clientv3util.BulkPut(ctx, cli, puts, clientv3util.WithChunkSize(5))
+	require.ErrorIs(t, err, rpctypes.ErrTooManyOps)
+	require.Zero(t, n)
+}
+
+func TestBulkPutAtomic(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	cli := clus.RandClient()
+	ctx := context.TODO()
+
+	puts := []clientv3.Op{
+		clientv3.OpPut("atomic/a", "1"),
+		clientv3.OpPut("atomic/b", "2"),
+		clientv3.OpPut("atomic/c", "3"),
+	}
+	n, err := clientv3util.BulkPut(ctx, cli, puts, clientv3util.WithChunkSize(2), clientv3util.WithAtomic())
+	require.ErrorIs(t, err, clientv3util.ErrBulkPutTooLarge)
+	require.Zero(t, n)
+	resp, err := cli.Get(ctx, "atomic/", clientv3.WithPrefix(), clientv3.WithCountOnly())
+	require.NoError(t, err)
+	require.Zero(t, resp.Count)
+
+	n, err = clientv3util.BulkPut(ctx, cli, puts, clientv3util.WithAtomic())
+	require.NoError(t, err)
+	require.Equal(t, len(puts), n)
+	resp, err = cli.Get(ctx, "atomic/", clientv3.WithPrefix())
+	require.NoError(t, err)
+	require.Len(t, resp.Kvs, len(puts))
+	require.Equal(t, resp.Kvs[0].ModRevision, resp.Kvs[2].ModRevision)
+
+	_, err = clientv3util.BulkPut(ctx, cli, []clientv3.Op{clientv3.OpGet("atomic/a")})
+	require.Error(t, err)
+}
+
+func TestGetFromLeader(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 3})
+	defer clus.Terminate(t)
+
+	lead := clus.WaitLeader(t)
+	var followerEps []string
+	for i, m := range clus.Members {
+		if i != lead {
+			followerEps = append(followerEps, m.GRPCURL)
+		}
+	}
+	// list the followers first so the leader is never the obvious choice
+	eps := append(append([]string{}, followerEps...), clus.Members[lead].GRPCURL)
+
+	cli, err := integration2.NewClient(t, clientv3.Config{Endpoints: eps})
+	require.NoError(t, err)
+	defer cli.Close()
+
+	ctx := context.TODO()
+	_, err = cli.Put(ctx, "foo", "bar")
+	require.NoError(t, err)
+
+	ep, err := clientv3util.LeaderEndpoint(ctx, cli)
+	require.NoError(t, err)
+	require.Equal(t, clus.Members[lead].GRPCURL, ep)
+
+	resp, err := clientv3util.GetFromLeader(ctx, cli, "foo")
+	require.NoError(t, err)
+	require.Equal(t, uint64(clus.Members[lead].ID()), resp.Header.MemberId)
+	require.Len(t, resp.Kvs, 1)
+	require.Equal(t, "bar", string(resp.Kvs[0].Value))
+
+	fcli, err := integration2.NewClient(t, clientv3.Config{Endpoints: followerEps})
+	require.NoError(t, err)
+	defer fcli.Close()
+	_, err = clientv3util.GetFromLeader(ctx, fcli, "foo")
+	require.ErrorIs(t, err, clientv3util.ErrNoLeaderEndpoint)
+}
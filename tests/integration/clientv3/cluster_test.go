@@ -23,9 +23,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	"go.etcd.io/etcd/client/pkg/v3/types"
+	clientv3 "go.etcd.io/etcd/client/v3"
 	integration2 "go.etcd.io/etcd/tests/v3/framework/integration"
 )
 
@@ -293,6 +297,273 @@ func TestMemberPromote(t *testing.T) {
 	}
 }
 
+// TestMemberAutoPromote ensures that the leader promotes a learner once it
+// stayed caught up for the configured duration.
+func TestMemberAutoPromote(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{
+		Size:                       3,
+		DisableStrictReconfigCheck: true,
+		LearnerAutoPromoteDuration: time.Second,
+	})
+	defer clus.Terminate(t)
+
+	capi := clus.RandClient()
+	learnerMember := clus.MustNewMember(t)
+	memberAddResp, err := capi.MemberAddAsLearner(context.Background(), learnerMember.PeerURLs.StringSlice())
+	require.NoError(t, err)
+	learnerID := memberAddResp.Member.ID
+
+	// the learner is not promoted until it is started and caught up.
+	time.Sleep(2 * time.Second)
+	resp, err := capi.MemberList(context.Background())
+	require.NoError(t, err)
+	for _, m := range resp.Members {
+		if m.ID == learnerID {
+			require.True(t, m.IsLearner)
+		}
+	}
+
+	clus.InitializeMemberWithResponse(t, learnerMember, memberAddResp)
+	require.NoError(t, learnerMember.Launch())
+
+	require.Eventually(t, func() bool {
+		resp, err := capi.MemberList(context.Background())
+		if err != nil {
+			return false
+		}
+		for _, m := range resp.Members {
+			if m.ID == learnerID {
+				return !m.IsLearner
+			}
+		}
+		return false
+	}, 10*time.Second, 100*time.Millisecond)
+}
+
+// TestMemberAutoPromoteStoppedLearner ensures that a learner which stopped
+// before the end of the promotion duration is not promoted, even though it
+// still matches the leader of the idle cluster.
+func TestMemberAutoPromoteStoppedLearner(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{
+		Size:                       3,
+		DisableStrictReconfigCheck: true,
+		LearnerAutoPromoteDuration: 3 * time.Second,
+	})
+	defer clus.Terminate(t)
+
+	capi := clus.RandClient()
+	learnerMember := clus.MustNewMember(t)
+	memberAddResp, err := capi.MemberAddAsLearner(context.Background(), learnerMember.PeerURLs.StringSlice())
+	require.NoError(t, err)
+	learnerID := memberAddResp.Member.ID
+	clus.InitializeMemberWithResponse(t, learnerMember, memberAddResp)
+	require.NoError(t, learnerMember.Launch())
+
+	leader := clus.Members[clus.WaitLeader(t)]
+	require.Eventually(t, func() bool {
+		return learnerMember.Server.AppliedIndex() >= leader.Server.AppliedIndex()
+	}, 2*time.Second, 10*time.Millisecond)
+	learnerMember.Stop(t)
+
+	time.Sleep(4 * time.Second)
+	resp, err := capi.MemberList(context.Background())
+	require.NoError(t, err)
+	for _, m := range resp.Members {
+		if m.ID == learnerID {
+			require.True(t, m.IsLearner)
+		}
+	}
+}
+
+// TestMemberReplace ensures that a member is replaced by a new member once the
+// new member caught up, and that the new member is removed if it does not.
+func TestMemberReplace(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 3, DisableStrictReconfigCheck: true})
+	defer clus.Terminate(t)
+
+	capi := clus.Client(0)
+	oldID := uint64(clus.Members[2].ID())
+
+	// the new member is never started, so it is removed again.
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	_, err := capi.MemberReplace(ctx, oldID, []string{"http://127.0.0.1:1"})
+	cancel()
+	require.Error(t, err)
+	// the server rolls back once it noticed the deadline.
+	require.Eventually(t, func() bool {
+		resp, err := capi.MemberList(context.Background())
+		return err == nil && len(resp.Members) == 3
+	}, 5*time.Second, 100*time.Millisecond)
+
+	newMember := clus.MustNewMember(t)
+	errc := make(chan error, 1)
+	var replaceResp *clientv3.MemberReplaceResponse
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+		defer cancel()
+		var rerr error
+		replaceResp, rerr = capi.MemberReplace(ctx, oldID, newMember.PeerURLs.StringSlice())
+		errc <- rerr
+	}()
+
+	var learner *etcdserverpb.Member
+	require.Eventually(t, func() bool {
+		resp, err := capi.MemberList(context.Background())
+		if err != nil {
+			return false
+		}
+		for _, m := range resp.Members {
+			if m.IsLearner {
+				learner = m
+				return true
+			}
+		}
+		return false
+	}, 10*time.Second, 100*time.Millisecond)
+	clus.InitializeMemberWithResponse(t, newMember, &clientv3.MemberAddResponse{Member: learner})
+	require.NoError(t, newMember.Launch())
+
+	require.NoError(t, <-errc)
+	assert.Equal(t, learner.ID, replaceResp.Member.ID)
+	require.Len(t, replaceResp.Members, 3)
+	for _, m := range replaceResp.Members {
+		assert.NotEqual(t, oldID, m.ID)
+		assert.False(t, m.IsLearner)
+	}
+}
+
+// TestMemberWitness ensures that a witness votes, but stores no keys, serves
+// no client requests and does not stay the leader.
+func TestMemberWitness(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 2, DisableStrictReconfigCheck: true})
+	defer clus.Terminate(t)
+
+	ctx := context.Background()
+	capi := clus.Client(0)
+	_, err := capi.Put(ctx, "foo", "bar")
+	require.NoError(t, err)
+
+	witness := clus.MustNewMember(t)
+	witness.Witness = true
+	resp, err := capi.MemberAdd(ctx, witness.PeerURLs.StringSlice())
+	require.NoError(t, err)
+	clus.InitializeMemberWithResponse(t, witness, resp)
+	require.NoError(t, witness.Launch())
+	clus.WaitMembersForLeader(t, clus.Members)
+
+	wcli, err := integration2.NewClient(t, clientv3.Config{Endpoints: []string{witness.GRPCURL}})
+	require.NoError(t, err)
+	defer wcli.Close()
+	_, err = wcli.Get(ctx, "foo")
+	require.ErrorIs(t, err, rpctypes.ErrNotSupportedForWitness)
+	_, err = wcli.Status(ctx, witness.GRPCURL)
+	require.NoError(t, err)
+
+	// the members know the witness from its published attributes, and do
+	// not move the leadership to it.
+	require.Eventually(t, func() bool {
+		m := clus.Members[0].Server.Cluster().Member(witness.ID())
+		return m != nil && m.IsWitness
+	}, 10*time.Second, 100*time.Millisecond)
+	_, err = clus.Client(clus.WaitLeader(t)).MoveLeader(ctx, uint64(witness.ID()))
+	require.ErrorIs(t, err, rpctypes.ErrBadLeaderTransferee)
+
+	// the witness and a member make a quorum.
+	leadIdx := clus.WaitLeader(t)
+	clus.Members[1-leadIdx].Stop(t)
+	_, err = clus.Client(leadIdx).Put(ctx, "foo", "baz")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return witness.Server.AppliedIndex() == clus.Members[leadIdx].Server.AppliedIndex()
+	}, 10*time.Second, 100*time.Millisecond)
+	assert.Equal(t, int64(1), witness.Server.KV().Rev())
+
+	// the data directory of the witness has no keys to serve as a normal
+	// member.
+	witness.Stop(t)
+	witness.Witness = false
+	require.ErrorContains(t, witness.Restart(t), "must be started with --witness")
+}
+
+// TestMemberStandby ensures that a standby member serves serializable reads
+// and watches, but no linearizable reads or writes, and is never promoted.
+func TestMemberStandby(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{
+		Size:                       3,
+		DisableStrictReconfigCheck: true,
+		LearnerAutoPromoteDuration: time.Second,
+	})
+	defer clus.Terminate(t)
+
+	ctx := context.Background()
+	capi := clus.Client(0)
+	_, err := capi.Put(ctx, "foo", "bar")
+	require.NoError(t, err)
+
+	standby := clus.MustNewMember(t)
+	resp, err := capi.MemberAddAsStandby(ctx, standby.PeerURLs.StringSlice())
+	require.NoError(t, err)
+	require.True(t, resp.Member.IsLearner)
+	require.True(t, resp.Member.IsStandby)
+	standbyID := resp.Member.ID
+	clus.InitializeMemberWithResponse(t, standby, resp)
+	require.NoError(t, standby.Launch())
+	clus.WaitMembersForLeader(t, clus.Members)
+
+	scli, err := integration2.NewClient(t, clientv3.Config{Endpoints: []string{standby.GRPCURL}})
+	require.NoError(t, err)
+	defer scli.Close()
+	require.Eventually(t, func() bool {
+		gresp, gerr := scli.Get(ctx, "foo", clientv3.WithSerializable())
+		return gerr == nil && len(gresp.Kvs) == 1 && string(gresp.Kvs[0].Value) == "bar"
+	}, 10*time.Second, 100*time.Millisecond)
+	_, err = scli.Get(ctx, "foo")
+	require.ErrorContains(t, err, rpctypes.ErrorDesc(rpctypes.ErrGRPCNotSupportedForLearner))
+	_, err = scli.Put(ctx, "foo", "baz")
+	require.ErrorContains(t, err, rpctypes.ErrorDesc(rpctypes.ErrGRPCNotSupportedForLearner))
+
+	wctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	wch := scli.Watch(wctx, "foo", clientv3.WithCreatedNotify())
+	wresp := <-wch
+	require.NoError(t, wresp.Err())
+	require.True(t, wresp.Created)
+	_, err = capi.Put(ctx, "foo", "baz")
+	require.NoError(t, err)
+	wresp = <-wch
+	require.NoError(t, wresp.Err())
+	require.Len(t, wresp.Events, 1)
+	assert.Equal(t, "baz", string(wresp.Events[0].Kv.Value))
+
+	// neither promoted on request, nor automatically once caught up, nor
+	// after its peer URLs are updated.
+	_, err = clus.Client(1).MemberPromote(ctx, standbyID)
+	require.ErrorIs(t, err, rpctypes.ErrMemberStandby)
+	_, err = capi.MemberUpdate(ctx, standbyID, standby.PeerURLs.StringSlice())
+	require.NoError(t, err)
+	time.Sleep(2 * time.Second)
+	lresp, err := capi.MemberList(ctx)
+	require.NoError(t, err)
+	for _, m := range lresp.Members {
+		if m.ID == standbyID {
+			assert.True(t, m.IsLearner)
+			assert.True(t, m.IsStandby)
+		}
+	}
+	_, err = capi.MemberPromote(ctx, standbyID)
+	require.ErrorIs(t, err, rpctypes.ErrMemberStandby)
+}
+
 // TestMemberPromoteMemberNotLearner ensures that promoting a voting member fails.
 func TestMemberPromoteMemberNotLearner(t *testing.T) {
 	integration2.BeforeTest(t, integration2.WithFailpoint("raftBeforeAdvance", `sleep(100)`))
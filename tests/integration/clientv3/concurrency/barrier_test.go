@@ -0,0 +1,153 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrency_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+	integration2 "go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+func newBarrierSessions(t *testing.T, cli *clientv3.Client, n int) []*concurrency.Session {
+	ss := make([]*concurrency.Session, n)
+	for i := range ss {
+		s, err := concurrency.NewSession(cli)
+		require.NoError(t, err)
+		t.Cleanup(func() { s.Close() })
+		ss[i] = s
+	}
+	return ss
+}
+
+func waitBarrier(b *concurrency.Barrier) <-chan error {
+	errc := make(chan error, 1)
+	go func() { errc <- b.Wait(context.TODO()) }()
+	return errc
+}
+
+func requireBlocked(t *testing.T, errcs ...<-chan error) {
+	for _, errc := range errcs {
+		select {
+		case err := <-errc:
+			t.Fatalf("barrier released early (%v)", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func requireReleased(t *testing.T, want error, errcs ...<-chan error) {
+	for _, errc := range errcs {
+		select {
+		case err := <-errc:
+			require.ErrorIs(t, err, want)
+		case <-time.After(5 * time.Second):
+			t.Fatal("barrier not released")
+		}
+	}
+}
+
+func TestBarrierAllArrive(t *testing.T) {
+	cli, err := integration2.NewClient(t, clientv3.Config{Endpoints: exampleEndpoints()})
+	require.NoError(t, err)
+	defer cli.Close()
+
+	pfx := fmt.Sprintf("/barrier/%s", t.Name())
+	ss := newBarrierSessions(t, cli, 4)
+	var errcs []<-chan error
+	for _, s := range ss[:2] {
+		errcs = append(errcs, waitBarrier(concurrency.NewBarrier(s, pfx, 3, concurrency.BarrierAbortOnFailure)))
+	}
+	requireBlocked(t, errcs...)
+
+	require.NoError(t, concurrency.NewBarrier(ss[2], pfx, 3, concurrency.BarrierAbortOnFailure).Wait(context.TODO()))
+	requireReleased(t, nil, errcs...)
+
+	err = concurrency.NewBarrier(ss[3], pfx, 3, concurrency.BarrierAbortOnFailure).Wait(context.TODO())
+	require.ErrorIs(t, err, concurrency.ErrBarrierFull)
+}
+
+func TestBarrierAbortOnFailure(t *testing.T) {
+	cli, err := integration2.NewClient(t, clientv3.Config{Endpoints: exampleEndpoints()})
+	require.NoError(t, err)
+	defer cli.Close()
+
+	pfx := fmt.Sprintf("/barrier/%s", t.Name())
+	ss := newBarrierSessions(t, cli, 3)
+	errc0 := waitBarrier(concurrency.NewBarrier(ss[0], pfx, 3, concurrency.BarrierAbortOnFailure))
+	errc1 := waitBarrier(concurrency.NewBarrier(ss[1], pfx, 3, concurrency.BarrierAbortOnFailure))
+	requireBlocked(t, errc0, errc1)
+
+	// the second participant fails, revoking its waiter key.
+	_, err = cli.Revoke(context.TODO(), ss[1].Lease())
+	require.NoError(t, err)
+	requireReleased(t, concurrency.ErrBarrierAborted, errc0, errc1)
+
+	err = concurrency.NewBarrier(ss[2], pfx, 3, concurrency.BarrierAbortOnFailure).Wait(context.TODO())
+	require.ErrorIs(t, err, concurrency.ErrBarrierAborted)
+}
+
+func TestBarrierAbortOnFailureLastArrival(t *testing.T) {
+	cli, err := integration2.NewClient(t, clientv3.Config{Endpoints: exampleEndpoints()})
+	require.NoError(t, err)
+	defer cli.Close()
+
+	pfx := fmt.Sprintf("/barrier/%s", t.Name())
+	ss := newBarrierSessions(t, cli, 2)
+	ctx, cancel := context.WithCancel(context.TODO())
+	errc0 := make(chan error, 1)
+	go func() { errc0 <- concurrency.NewBarrier(ss[0], pfx, 2, concurrency.BarrierAbortOnFailure).Wait(ctx) }()
+	requireBlocked(t, errc0)
+
+	// the first participant stops waiting and fails, so only the last one to
+	// arrive can observe its missing waiter key.
+	cancel()
+	requireReleased(t, context.Canceled, errc0)
+	_, err = cli.Revoke(context.TODO(), ss[0].Lease())
+	require.NoError(t, err)
+
+	err = concurrency.NewBarrier(ss[1], pfx, 2, concurrency.BarrierAbortOnFailure).Wait(context.TODO())
+	require.ErrorIs(t, err, concurrency.ErrBarrierAborted)
+}
+
+func TestBarrierProceedOnFailure(t *testing.T) {
+	cli, err := integration2.NewClient(t, clientv3.Config{Endpoints: exampleEndpoints()})
+	require.NoError(t, err)
+	defer cli.Close()
+
+	pfx := fmt.Sprintf("/barrier/%s", t.Name())
+	ss := newBarrierSessions(t, cli, 3)
+	errc0 := waitBarrier(concurrency.NewBarrier(ss[0], pfx, 3, concurrency.BarrierProceedOnFailure))
+	ctx, cancel := context.WithCancel(context.TODO())
+	errc1 := make(chan error, 1)
+	go func() { errc1 <- concurrency.NewBarrier(ss[1], pfx, 3, concurrency.BarrierProceedOnFailure).Wait(ctx) }()
+	requireBlocked(t, errc0, errc1)
+
+	// the second participant fails after arriving; it is still counted.
+	cancel()
+	requireReleased(t, context.Canceled, errc1)
+	_, err = cli.Revoke(context.TODO(), ss[1].Lease())
+	require.NoError(t, err)
+	requireBlocked(t, errc0)
+
+	require.NoError(t, concurrency.NewBarrier(ss[2], pfx, 3, concurrency.BarrierProceedOnFailure).Wait(context.TODO()))
+	requireReleased(t, nil, errc0)
+}
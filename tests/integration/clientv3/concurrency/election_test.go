@@ -16,6 +16,7 @@ package concurrency_test
 
 import (
 	"context"
+	"fmt"
 	"log"
 	"strings"
 	"testing"
@@ -103,3 +104,65 @@ func TestResumeElection(t *testing.T) {
 		t.Errorf("expected new leader to be 'candidate1' got %q", string(kv.Value))
 	}
 }
+
+func TestElectionCampaignWithPriority(t *testing.T) {
+	cli, err := integration2.NewClient(t, clientv3.Config{Endpoints: exampleEndpoints()})
+	require.NoError(t, err)
+	// registered first, to close after the sessions.
+	t.Cleanup(func() { cli.Close() })
+
+	pfx := fmt.Sprintf("/election/%s", t.Name())
+	sessions := make(map[string]*concurrency.Session)
+	campaign := func(val string, priority int64) (*concurrency.Election, <-chan error) {
+		s, err := concurrency.NewSession(cli)
+		require.NoError(t, err)
+		t.Cleanup(func() { s.Close() })
+		sessions[val] = s
+		e := concurrency.NewElection(s, pfx)
+		errc := make(chan error, 1)
+		go func() { errc <- e.CampaignWithPriority(context.TODO(), val, priority) }()
+		return e, errc
+	}
+	requireElected := func(e *concurrency.Election, errc <-chan error, val string) {
+		select {
+		case err := <-errc:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s not elected", val)
+		}
+		resp, err := e.Leader(context.TODO())
+		require.NoError(t, err)
+		require.Equal(t, val, string(resp.Kvs[0].Value))
+	}
+	requireWaiting := func(errcs ...<-chan error) {
+		for _, errc := range errcs {
+			select {
+			case err := <-errc:
+				t.Fatalf("candidate elected early (%v)", err)
+			case <-time.After(100 * time.Millisecond):
+			}
+		}
+	}
+
+	low, lowc := campaign("low", 0)
+	requireElected(low, lowc, "low")
+
+	// the leader is not preempted by candidates of higher priority.
+	mid, midc := campaign("mid", 5)
+	requireWaiting(midc)
+	high, highc := campaign("high", 10)
+	mid2, mid2c := campaign("mid2", 5)
+	requireWaiting(midc, highc, mid2c)
+
+	// the highest priority wins, although it campaigned later.
+	require.NoError(t, low.Resign(context.TODO()))
+	requireElected(high, highc, "high")
+	requireWaiting(midc, mid2c)
+
+	// ties go to the earliest candidate, also when the leader expires.
+	require.NoError(t, sessions["high"].Close())
+	requireElected(mid, midc, "mid")
+	requireWaiting(mid2c)
+	require.NoError(t, mid.Resign(context.TODO()))
+	requireElected(mid2, mid2c, "mid2")
+}
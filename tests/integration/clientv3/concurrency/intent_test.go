@@ -0,0 +1,107 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrency_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+	integration2 "go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+func TestWriteIntentsLockUnlock(t *testing.T) {
+	cli, err := integration2.NewClient(t, clientv3.Config{Endpoints: exampleEndpoints()})
+	require.NoError(t, err)
+	defer cli.Close()
+
+	s1, err := concurrency.NewSession(cli)
+	require.NoError(t, err)
+	defer s1.Close()
+	w1 := concurrency.NewWriteIntents(s1, "/intents-unlock")
+
+	s2, err := concurrency.NewSession(cli)
+	require.NoError(t, err)
+	defer s2.Close()
+	w2 := concurrency.NewWriteIntents(s2, "/intents-unlock")
+
+	require.ErrorIs(t, w1.Unlock(context.TODO(), "a"), concurrency.ErrLockReleased)
+	require.NoError(t, w1.Lock(context.TODO(), "a"))
+	// keys sharing a prefix do not conflict.
+	require.NoError(t, w2.Lock(context.TODO(), "a/b"))
+
+	locked := make(chan error, 1)
+	go func() { locked <- w2.Lock(context.TODO(), "a") }()
+	select {
+	case err = <-locked:
+		t.Fatalf("lock acquired while held by another session (err %v)", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	require.NoError(t, w1.Unlock(context.TODO(), "a"))
+	require.NoError(t, <-locked)
+	require.True(t, w2.Held("a"))
+	require.False(t, w1.Held("a"))
+	require.NoError(t, w2.UnlockAll(context.TODO()))
+	require.False(t, w2.Held("a/b"))
+}
+
+// TestWriteIntentsDeadlock constructs a two-party deadlock and ensures that
+// exactly one side is aborted while the other acquires both keys.
+func TestWriteIntentsDeadlock(t *testing.T) {
+	cli, err := integration2.NewClient(t, clientv3.Config{Endpoints: exampleEndpoints()})
+	require.NoError(t, err)
+	defer cli.Close()
+
+	s1, err := concurrency.NewSession(cli)
+	require.NoError(t, err)
+	defer s1.Close()
+	w1 := concurrency.NewWriteIntents(s1, "/intents-deadlock")
+
+	s2, err := concurrency.NewSession(cli)
+	require.NoError(t, err)
+	defer s2.Close()
+	w2 := concurrency.NewWriteIntents(s2, "/intents-deadlock")
+
+	require.NoError(t, w1.Lock(context.TODO(), "a"))
+	require.NoError(t, w2.Lock(context.TODO(), "b"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	errc1, errc2 := make(chan error, 1), make(chan error, 1)
+	go func() { errc1 <- w1.Lock(ctx, "b") }()
+	go func() { errc2 <- w2.Lock(ctx, "a") }()
+	err1, err2 := <-errc1, <-errc2
+
+	winner, victim, victimErr := w1, w2, err2
+	if s1.Lease() > s2.Lease() {
+		winner, victim, victimErr = w2, w1, err1
+		require.NoError(t, err2)
+	} else {
+		require.NoError(t, err1)
+	}
+	if !errors.Is(victimErr, concurrency.ErrDeadlock) {
+		t.Fatalf("expected %v for the session with the highest lease, got %v", concurrency.ErrDeadlock, victimErr)
+	}
+	require.True(t, winner.Held("a"))
+	require.True(t, winner.Held("b"))
+	require.False(t, victim.Held("a"))
+	require.False(t, victim.Held("b"))
+}
@@ -0,0 +1,151 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package concurrency_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+	integration2 "go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// countingMetrics counts the lock events of an RWMutex.
+type countingMetrics struct {
+	mu                            sync.Mutex
+	acquired, contended, released map[bool]int
+}
+
+func newCountingMetrics() *countingMetrics {
+	return &countingMetrics{acquired: map[bool]int{}, contended: map[bool]int{}, released: map[bool]int{}}
+}
+
+func (m *countingMetrics) Acquired(write bool, _ time.Duration) { m.inc(m.acquired, write) }
+func (m *countingMetrics) Contended(write bool)                 { m.inc(m.contended, write) }
+func (m *countingMetrics) Released(write bool, _ time.Duration) { m.inc(m.released, write) }
+
+func (m *countingMetrics) inc(counts map[bool]int, write bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counts[write]++
+}
+
+func newRWMutexes(t *testing.T, cli *clientv3.Client, n int, opts ...concurrency.RWMutexOption) []*concurrency.RWMutex {
+	pfx := fmt.Sprintf("/rwmutex/%s", t.Name())
+	rws := make([]*concurrency.RWMutex, n)
+	for i := range rws {
+		s, err := concurrency.NewSession(cli)
+		require.NoError(t, err)
+		t.Cleanup(func() { s.Close() })
+		rws[i] = concurrency.NewRWMutex(s, pfx, opts...)
+	}
+	return rws
+}
+
+func lockAsync(lock func(context.Context) error) <-chan error {
+	errc := make(chan error, 1)
+	go func() { errc <- lock(context.TODO()) }()
+	return errc
+}
+
+func requireLockBlocked(t *testing.T, errcs ...<-chan error) {
+	for _, errc := range errcs {
+		select {
+		case err := <-errc:
+			t.Fatalf("lock acquired early (%v)", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func requireLockAcquired(t *testing.T, errcs ...<-chan error) {
+	for _, errc := range errcs {
+		select {
+		case err := <-errc:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("lock not acquired")
+		}
+	}
+}
+
+func TestRWMutexWriterPreference(t *testing.T) {
+	cli, err := integration2.NewClient(t, clientv3.Config{Endpoints: exampleEndpoints()})
+	require.NoError(t, err)
+	// registered first, to close after the sessions.
+	t.Cleanup(func() { cli.Close() })
+
+	rws := newRWMutexes(t, cli, 4)
+	r1, r2, w, r3 := rws[0], rws[1], rws[2], rws[3]
+
+	// readers share the lock.
+	require.NoError(t, r1.RLock(context.TODO()))
+	require.NoError(t, r2.RLock(context.TODO()))
+
+	// the writer waits for the readers, and the reader arriving after the
+	// writer waits for the writer.
+	wc := lockAsync(w.Lock)
+	requireLockBlocked(t, wc)
+	r3c := lockAsync(r3.RLock)
+	requireLockBlocked(t, r3c)
+
+	require.NoError(t, r1.RUnlock(context.TODO()))
+	requireLockBlocked(t, wc)
+	require.NoError(t, r2.RUnlock(context.TODO()))
+	requireLockAcquired(t, wc)
+	requireLockBlocked(t, r3c)
+
+	require.NoError(t, w.Unlock(context.TODO()))
+	requireLockAcquired(t, r3c)
+	require.NoError(t, r3.RUnlock(context.TODO()))
+	require.ErrorIs(t, r3.RUnlock(context.TODO()), concurrency.ErrLockReleased)
+}
+
+func TestRWMutexTryLock(t *testing.T) {
+	cli, err := integration2.NewClient(t, clientv3.Config{Endpoints: exampleEndpoints()})
+	require.NoError(t, err)
+	t.Cleanup(func() { cli.Close() })
+
+	m := newCountingMetrics()
+	rws := newRWMutexes(t, cli, 3, concurrency.WithRWMutexMetrics(m))
+	r1, r2, w := rws[0], rws[1], rws[2]
+
+	require.NoError(t, r1.TryRLock(context.TODO()))
+	require.NoError(t, r2.TryRLock(context.TODO()))
+	require.ErrorIs(t, w.TryLock(context.TODO()), concurrency.ErrLocked)
+	// the failed attempt left no key behind to block the readers.
+	require.NoError(t, r1.RUnlock(context.TODO()))
+	require.NoError(t, r1.TryRLock(context.TODO()))
+
+	require.NoError(t, r1.RUnlock(context.TODO()))
+	require.NoError(t, r2.RUnlock(context.TODO()))
+	require.NoError(t, w.TryLock(context.TODO()))
+	require.ErrorIs(t, r1.TryRLock(context.TODO()), concurrency.ErrLocked)
+	require.ErrorIs(t, w.RUnlock(context.TODO()), concurrency.ErrLockReleased)
+	require.NoError(t, w.Unlock(context.TODO()))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	assert.Equal(t, map[bool]int{false: 3, true: 1}, m.acquired)
+	assert.Equal(t, map[bool]int{false: 1, true: 1}, m.contended)
+	assert.Equal(t, map[bool]int{false: 3, true: 1}, m.released)
+}
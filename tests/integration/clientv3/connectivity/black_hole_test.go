@@ -209,3 +209,47 @@ func testBalancerUnderBlackholeNoKeepAlive(t *testing.T, op func(*clientv3.Clien
 	}
 	require.NoError(t, err)
 }
+
+// TestBalancerCircuitBreakerUnderBlackhole ensures that once the requests to a
+// blackholed endpoint hit their deadline, the endpoint is ejected and the
+// following requests go to the healthy endpoints.
+func TestBalancerCircuitBreakerUnderBlackhole(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{
+		Size:      3,
+		UseBridge: true,
+	})
+	defer clus.Terminate(t)
+
+	ccfg := clientv3.Config{
+		Endpoints:      []string{clus.Members[0].GRPCURL, clus.Members[1].GRPCURL, clus.Members[2].GRPCURL},
+		DialTimeout:    time.Second,
+		DialOptions:    []grpc.DialOption{grpc.WithBlock()},
+		CircuitBreaker: &clientv3.CircuitBreakerConfig{FailureThreshold: 1, Cooldown: time.Minute},
+	}
+	cli, err := integration2.NewClient(t, ccfg)
+	require.NoError(t, err)
+	defer cli.Close()
+
+	// connect to every endpoint before blackholing one of them.
+	for i := 0; i < 6; i++ {
+		_, err = cli.Get(context.TODO(), "foo", clientv3.WithSerializable())
+		require.NoError(t, err)
+	}
+
+	clus.Members[0].Bridge().Blackhole()
+	defer clus.Members[0].Bridge().Unblackhole()
+
+	failures := 0
+	for i := 0; i < 10; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		_, err = cli.Get(ctx, "foo", clientv3.WithSerializable())
+		cancel()
+		if err != nil {
+			t.Logf("#%d: failed with error %v", i, err)
+			failures++
+		}
+	}
+	require.LessOrEqualf(t, failures, 1, "requests kept being sent to the blackholed endpoint")
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !cluster_proxy
+
+package connectivity_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	integration2 "go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestBalancerFollowerReads ensures that the writes and linearizable reads are
+// sent to the leader, following it across leadership transfers, while the
+// serializable reads are served by any endpoint.
+func TestBalancerFollowerReads(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 3})
+	defer clus.Terminate(t)
+
+	ccfg := clientv3.Config{
+		Endpoints:     []string{clus.Members[0].GRPCURL, clus.Members[1].GRPCURL, clus.Members[2].GRPCURL},
+		DialTimeout:   time.Second,
+		FollowerReads: &clientv3.FollowerReadsConfig{ProbeInterval: 100 * time.Millisecond},
+	}
+	cli, err := integration2.NewClient(t, ccfg)
+	require.NoError(t, err)
+	defer cli.Close()
+
+	ctx := context.TODO()
+	servedByLeader := func() bool {
+		lead := uint64(clus.Members[clus.WaitLeader(t)].ID())
+		presp, perr := cli.Put(ctx, "foo", "bar")
+		require.NoError(t, perr)
+		gresp, gerr := cli.Get(ctx, "foo")
+		require.NoError(t, gerr)
+		return presp.Header.MemberId == lead && gresp.Header.MemberId == lead
+	}
+	require.Eventually(t, servedByLeader, 5*time.Second, 100*time.Millisecond)
+	for i := 0; i < 5; i++ {
+		require.True(t, servedByLeader())
+	}
+
+	for i := 0; i < 5; i++ {
+		resp, gerr := cli.Get(ctx, "foo", clientv3.WithSerializable())
+		require.NoError(t, gerr)
+		require.Len(t, resp.Kvs, 1)
+	}
+
+	leadIdx := clus.WaitLeader(t)
+	target := uint64(clus.Members[(leadIdx+1)%3].ID())
+	_, err = clus.Client(leadIdx).MoveLeader(ctx, target)
+	require.NoError(t, err)
+	require.Eventually(t, servedByLeader, 5*time.Second, 100*time.Millisecond)
+}
+
+func TestFollowerReadsExcludesCircuitBreaker(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	_, err := integration2.NewClient(t, clientv3.Config{
+		Endpoints:      []string{clus.Members[0].GRPCURL},
+		CircuitBreaker: &clientv3.CircuitBreakerConfig{},
+		FollowerReads:  &clientv3.FollowerReadsConfig{},
+	})
+	require.Error(t, err)
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
@@ -461,6 +462,275 @@ func TestKVCompact(t *testing.T) {
 	}
 }
 
+// TestKVGetContinueToken ensures a paginated scan resumed with continue tokens
+// observes every key exactly once at the revision of the first page, even if
+// the range is modified between pages.
+func TestKVGetContinueToken(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	kv := clus.RandClient()
+	ctx := context.TODO()
+
+	keys := []string{"a", "b", "c", "d", "e"}
+	for _, k := range keys {
+		_, err := kv.Put(ctx, "foo/"+k, k)
+		require.NoError(t, err)
+	}
+
+	resp, err := kv.Get(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithLimit(2))
+	require.NoError(t, err)
+	require.True(t, resp.More)
+	require.NotEmpty(t, resp.NextToken)
+	rev := resp.Header.Revision
+
+	var got []string
+	for _, kv := range resp.Kvs {
+		got = append(got, string(kv.Key))
+	}
+
+	// modify the range between pages; none of it should be visible.
+	_, err = kv.Delete(ctx, "foo/c")
+	require.NoError(t, err)
+	_, err = kv.Put(ctx, "foo/bb", "bb")
+	require.NoError(t, err)
+
+	for len(resp.NextToken) > 0 {
+		resp, err = kv.Get(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithLimit(2), clientv3.WithContinueToken(resp.NextToken))
+		require.NoError(t, err)
+		for _, kv := range resp.Kvs {
+			require.LessOrEqual(t, kv.ModRevision, rev)
+			got = append(got, string(kv.Key))
+		}
+	}
+	require.Equal(t, []string{"foo/a", "foo/b", "foo/c", "foo/d", "foo/e"}, got)
+	require.False(t, resp.More)
+}
+
+// TestKVGetContinueTokenError ensures malformed and compacted continue tokens,
+// and tokens resuming outside of the requested range, are rejected.
+func TestKVGetContinueTokenError(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	kv := clus.RandClient()
+	ctx := context.TODO()
+
+	for i := 0; i < 3; i++ {
+		_, err := kv.Put(ctx, fmt.Sprintf("foo/%d", i), "bar")
+		require.NoError(t, err)
+	}
+
+	_, err := kv.Get(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithContinueToken([]byte("garbage")))
+	require.ErrorIs(t, err, rpctypes.ErrInvalidContinueToken)
+
+	resp, err := kv.Get(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithLimit(1))
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.NextToken)
+
+	_, err = kv.Get(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithLimit(1), clientv3.WithSort(clientv3.SortByKey, clientv3.SortDescend), clientv3.WithContinueToken(resp.NextToken))
+	require.ErrorIs(t, err, rpctypes.ErrInvalidContinueToken)
+
+	// a token never resumes outside of the requested range, which is the one
+	// the permissions are checked against.
+	_, err = kv.Get(ctx, "foo/", clientv3.WithContinueToken(resp.NextToken))
+	require.ErrorIs(t, err, rpctypes.ErrInvalidContinueToken)
+	_, err = kv.Get(ctx, "a", clientv3.WithRange("foo/"), clientv3.WithContinueToken(resp.NextToken))
+	require.ErrorIs(t, err, rpctypes.ErrInvalidContinueToken)
+
+	_, err = kv.Put(ctx, "foo/3", "bar")
+	require.NoError(t, err)
+	_, err = kv.Compact(ctx, resp.Header.Revision+1)
+	require.NoError(t, err)
+
+	_, err = kv.Get(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithLimit(1), clientv3.WithContinueToken(resp.NextToken))
+	require.ErrorIs(t, err, rpctypes.ErrCompacted)
+}
+
+// TestKVPager ensures a Pager reads every key of the range once, at the
+// revision of its first page, and can be resumed from its token.
+func TestKVPager(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	kv := clus.RandClient()
+	ctx := context.TODO()
+
+	for i := 0; i < 7; i++ {
+		_, err := kv.Put(ctx, fmt.Sprintf("foo/%d", i), "bar")
+		require.NoError(t, err)
+	}
+
+	var got []string
+	p := clientv3.NewPager(kv, "foo/", 3, clientv3.WithPrefix())
+	resp, err := p.Next(ctx)
+	require.NoError(t, err)
+	for _, kv := range resp.Kvs {
+		got = append(got, string(kv.Key))
+	}
+	rev := resp.Header.Revision
+	_, err = kv.Delete(ctx, "foo/5")
+	require.NoError(t, err)
+
+	// resume from the token, like another process would.
+	p = clientv3.ResumePager(kv, "foo/", 3, p.Token(), clientv3.WithPrefix())
+	for !p.Done() {
+		resp, err = p.Next(ctx)
+		require.NoError(t, err)
+		for _, kv := range resp.Kvs {
+			require.LessOrEqual(t, kv.ModRevision, rev)
+			got = append(got, string(kv.Key))
+		}
+	}
+	require.Equal(t, []string{"foo/0", "foo/1", "foo/2", "foo/3", "foo/4", "foo/5", "foo/6"}, got)
+	_, err = p.Next(ctx)
+	require.ErrorIs(t, err, clientv3.ErrPagerDone)
+}
+
+// TestKVPutTTL ensures keys put with a ttl are deleted once it elapses,
+// unless modified meanwhile.
+func TestKVPutTTL(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 3})
+	defer clus.Terminate(t)
+
+	kv := clus.RandClient()
+	ctx := context.TODO()
+
+	wch := kv.Watch(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithFilterPut())
+	_, err := kv.Put(ctx, "foo/a", "bar", clientv3.WithTTL(1))
+	require.NoError(t, err)
+	_, err = kv.Put(ctx, "foo/b", "bar", clientv3.WithTTL(1))
+	require.NoError(t, err)
+	_, err = kv.Put(ctx, "foo/b", "baz")
+	require.NoError(t, err)
+
+	resp, err := kv.Get(ctx, "foo/a")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), resp.Kvs[0].Ttl)
+
+	select {
+	case wr := <-wch:
+		require.Len(t, wr.Events, 1)
+		require.Equal(t, "foo/a", string(wr.Events[0].Kv.Key))
+	case <-time.After(10 * time.Second):
+		t.Fatal("expired key not deleted")
+	}
+	resp, err = kv.Get(ctx, "foo/", clientv3.WithPrefix())
+	require.NoError(t, err)
+	require.Len(t, resp.Kvs, 1)
+	require.Equal(t, "foo/b", string(resp.Kvs[0].Key))
+
+	_, err = kv.Put(ctx, "foo/c", "bar", clientv3.WithTTL(-1))
+	require.ErrorIs(t, err, rpctypes.ErrInvalidTTL)
+	_, err = kv.Put(ctx, "foo/c", "bar", clientv3.WithTTL(1), clientv3.WithIgnoreLease())
+	require.ErrorIs(t, err, rpctypes.ErrInvalidTTL)
+}
+
+// TestKVGetKeyFilter ensures the keys are filtered by substring or regular
+// expression before the limit is applied, and invalid expressions are rejected.
+func TestKVGetKeyFilter(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	kv := clus.RandClient()
+	ctx := context.TODO()
+
+	for _, k := range []string{"foo/a1", "foo/b1", "foo/b2", "foo/c1", "foo/c22"} {
+		_, err := kv.Put(ctx, k, "bar")
+		require.NoError(t, err)
+	}
+
+	keys := func(resp *clientv3.GetResponse) (ks []string) {
+		for _, kv := range resp.Kvs {
+			ks = append(ks, string(kv.Key))
+		}
+		return ks
+	}
+
+	resp, err := kv.Get(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithKeyFilter("1"), clientv3.WithLimit(2))
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo/a1", "foo/b1"}, keys(resp))
+	require.True(t, resp.More)
+	require.Equal(t, int64(5), resp.Count)
+
+	resp, err = kv.Get(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithKeyFilterRegex(`^foo/[bc]\d$`))
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo/b1", "foo/b2", "foo/c1"}, keys(resp))
+
+	tresp, err := kv.Txn(ctx).Then(clientv3.OpGet("foo/", clientv3.WithPrefix(), clientv3.WithKeyFilter("22"))).Commit()
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo/c22"}, keys((*clientv3.GetResponse)(tresp.Responses[0].GetResponseRange())))
+
+	_, err = kv.Get(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithKeyFilterRegex("foo/("))
+	require.ErrorIs(t, err, rpctypes.ErrInvalidKeyFilter)
+	_, err = kv.Txn(ctx).Then(clientv3.OpGet("foo/", clientv3.WithPrefix(), clientv3.WithKeyFilterRegex("foo/("))).Commit()
+	require.ErrorIs(t, err, rpctypes.ErrInvalidKeyFilter)
+}
+
+func TestKVRangeStream(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	cli := clus.RandClient()
+	ctx := context.TODO()
+
+	// more keys than a single page of the stream
+	var ops []clientv3.Op
+	for i := 0; i < 2500; i++ {
+		ops = append(ops, clientv3.OpPut(fmt.Sprintf("foo/%04d", i), "bar"))
+		if len(ops) == 100 {
+			_, err := cli.Txn(ctx).Then(ops...).Commit()
+			require.NoError(t, err)
+			ops = nil
+		}
+	}
+
+	read := func(opts ...clientv3.OpOption) (it *clientv3.RangeIterator, ks []string) {
+		it, err := clientv3.RangeStream(ctx, cli, "foo/", append(opts, clientv3.WithPrefix())...)
+		require.NoError(t, err)
+		defer it.Close()
+		for it.Next() {
+			ks = append(ks, string(it.KeyValue().Key))
+		}
+		require.NoError(t, it.Err())
+		return it, ks
+	}
+
+	it, ks := read()
+	require.Len(t, ks, 2500)
+	require.True(t, sort.StringsAreSorted(ks))
+	require.Equal(t, int64(2500), it.Count())
+	require.False(t, it.More())
+
+	// the stream is read at the revision of its first page
+	hrev := it.Header().Revision
+	_, err := cli.Delete(ctx, "foo/", clientv3.WithPrefix())
+	require.NoError(t, err)
+	it, ks = read(clientv3.WithRev(hrev), clientv3.WithLimit(1500))
+	require.Len(t, ks, 1500)
+	require.Equal(t, "foo/1499", ks[1499])
+	require.True(t, it.More())
+
+	it, ks = read(clientv3.WithRev(hrev), clientv3.WithKeyFilterRegex(`^foo/\d\d\d5$`))
+	require.Len(t, ks, 250)
+	require.Equal(t, int64(2500), it.Count())
+
+	_, err = clientv3.RangeStream(ctx, cli, "foo/", clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByValue, clientv3.SortAscend))
+	require.ErrorIs(t, err, rpctypes.ErrInvalidSortOption)
+}
+
 // TestKVGetRetry ensures get will retry on disconnect.
 func TestKVGetRetry(t *testing.T) {
 	integration2.BeforeTest(t)
@@ -750,6 +1020,42 @@ func TestKVLargeRequests(t *testing.T) {
 	}
 }
 
+// TestKVAutoMaxRequestBytes ensures a client with AutoMaxRequestBytes rejects
+// requests over the server limit before sending them.
+func TestKVAutoMaxRequestBytes(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	maxRequestBytes := uint(64 * 1024)
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 3, MaxRequestBytes: maxRequestBytes})
+	defer clus.Terminate(t)
+
+	var eps []string
+	for _, m := range clus.Members {
+		eps = append(eps, m.GRPCURL)
+	}
+	cli, err := integration2.NewClient(t, clientv3.Config{Endpoints: eps, AutoMaxRequestBytes: true})
+	require.NoError(t, err)
+	defer cli.Close()
+
+	ctx := context.TODO()
+	large := strings.Repeat("a", int(maxRequestBytes)+100)
+	wantErr := fmt.Sprintf("exceeds the server limit of %d bytes", maxRequestBytes)
+
+	_, err = cli.Put(ctx, "foo", large)
+	require.ErrorIs(t, err, rpctypes.ErrRequestTooLarge)
+	require.ErrorContains(t, err, wantErr)
+
+	_, err = cli.Txn(ctx).Then(clientv3.OpPut("foo", "bar"), clientv3.OpPut("baz", large)).Commit()
+	require.ErrorIs(t, err, rpctypes.ErrRequestTooLarge)
+	require.ErrorContains(t, err, wantErr)
+
+	_, err = cli.Put(ctx, "foo", "bar")
+	require.NoError(t, err)
+	resp, err := cli.Get(ctx, "baz")
+	require.NoError(t, err)
+	require.Empty(t, resp.Kvs)
+}
+
 // TestKVForLearner ensures learner member only accepts serializable read request.
 func TestKVForLearner(t *testing.T) {
 	integration2.BeforeTest(t)
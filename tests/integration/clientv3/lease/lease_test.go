@@ -601,6 +601,114 @@ func TestLeaseLeases(t *testing.T) {
 	}
 }
 
+// TestLeaseMetadata ensures the metadata of leases is returned by TimeToLive
+// and Leases, which filters leases by metadata prefix and granted TTL.
+func TestLeaseMetadata(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	cli := clus.RandClient()
+
+	_, err := cli.Grant(context.Background(), 10, clientv3.WithLeaseMetadata(make([]byte, 257)))
+	require.ErrorIs(t, err, rpctypes.ErrLeaseMetadataTooLarge)
+
+	l1, err := cli.Grant(context.Background(), 10, clientv3.WithLeaseMetadata([]byte("job/a")))
+	require.NoError(t, err)
+	l2, err := cli.Grant(context.Background(), 20, clientv3.WithLeaseMetadata([]byte("job/b")))
+	require.NoError(t, err)
+	l3, err := cli.Grant(context.Background(), 30)
+	require.NoError(t, err)
+
+	tresp, err := cli.TimeToLive(context.Background(), l1.ID)
+	require.NoError(t, err)
+	require.Equal(t, []byte("job/a"), tresp.Metadata)
+
+	tests := []struct {
+		opts []clientv3.LeaseOption
+		want []clientv3.LeaseStatus
+	}{
+		{
+			nil,
+			[]clientv3.LeaseStatus{
+				{ID: l1.ID, GrantedTTL: 10, Metadata: []byte("job/a")},
+				{ID: l2.ID, GrantedTTL: 20, Metadata: []byte("job/b")},
+				{ID: l3.ID, GrantedTTL: 30},
+			},
+		},
+		{
+			[]clientv3.LeaseOption{clientv3.WithMetadataPrefix([]byte("job/"))},
+			[]clientv3.LeaseStatus{
+				{ID: l1.ID, GrantedTTL: 10, Metadata: []byte("job/a")},
+				{ID: l2.ID, GrantedTTL: 20, Metadata: []byte("job/b")},
+			},
+		},
+		{
+			[]clientv3.LeaseOption{clientv3.WithGrantedTTLRange(15, 0)},
+			[]clientv3.LeaseStatus{
+				{ID: l2.ID, GrantedTTL: 20, Metadata: []byte("job/b")},
+				{ID: l3.ID, GrantedTTL: 30},
+			},
+		},
+		{
+			[]clientv3.LeaseOption{clientv3.WithMetadataPrefix([]byte("job/")), clientv3.WithGrantedTTLRange(0, 15)},
+			[]clientv3.LeaseStatus{
+				{ID: l1.ID, GrantedTTL: 10, Metadata: []byte("job/a")},
+			},
+		},
+	}
+	for i, tt := range tests {
+		resp, err := cli.Leases(context.Background(), tt.opts...)
+		require.NoError(t, err)
+		sort.Slice(resp.Leases, func(i, j int) bool { return resp.Leases[i].GrantedTTL < resp.Leases[j].GrantedTTL })
+		require.Equalf(t, tt.want, resp.Leases, "#%d", i)
+	}
+}
+
+// TestLeaseBatch ensures leases are granted, kept alive and revoked in batches,
+// with the leases failing or missing reported per lease.
+func TestLeaseBatch(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	cli := clus.RandClient()
+
+	gresp, err := cli.GrantBatch(context.Background(), []int64{10, clientv3.MaxLeaseTTL + 1, 20})
+	require.NoError(t, err)
+	require.Len(t, gresp.Leases, 3)
+	require.Empty(t, gresp.Leases[0].Error)
+	require.Equal(t, int64(10), gresp.Leases[0].TTL)
+	require.Equal(t, rpctypes.ErrLeaseTTLTooLarge.Error(), gresp.Leases[1].Error)
+	require.Empty(t, gresp.Leases[2].Error)
+	require.Equal(t, int64(20), gresp.Leases[2].TTL)
+	ids := []clientv3.LeaseID{gresp.Leases[0].ID, gresp.Leases[2].ID}
+
+	lresp, err := cli.Leases(context.Background())
+	require.NoError(t, err)
+	require.Len(t, lresp.Leases, 2)
+
+	kresp, err := cli.KeepAliveBatch(context.Background(), append(ids, clientv3.LeaseID(500)))
+	require.NoError(t, err)
+	require.Len(t, kresp.Leases, 3)
+	require.Equal(t, ids[0], kresp.Leases[0].ID)
+	require.Equal(t, int64(10), kresp.Leases[0].TTL)
+	require.Equal(t, ids[1], kresp.Leases[1].ID)
+	require.Equal(t, int64(20), kresp.Leases[1].TTL)
+	require.Equal(t, clientv3.LeaseID(500), kresp.Leases[2].ID)
+	require.Zero(t, kresp.Leases[2].TTL)
+
+	rresp, err := cli.RevokeBatch(context.Background(), append(ids, clientv3.LeaseID(500)))
+	require.NoError(t, err)
+	require.Equal(t, []clientv3.LeaseID{500}, rresp.NotFound)
+
+	lresp, err = cli.Leases(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, lresp.Leases)
+}
+
 // TestLeaseRenewLostQuorum ensures keepalives work after losing quorum
 // for a while.
 func TestLeaseRenewLostQuorum(t *testing.T) {
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
@@ -34,9 +35,11 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	"go.etcd.io/etcd/api/v3/version"
 	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
 	"go.etcd.io/etcd/server/v3/lease"
 	"go.etcd.io/etcd/server/v3/storage"
 	"go.etcd.io/etcd/server/v3/storage/backend"
@@ -393,6 +396,27 @@ func TestMaintenanceSnapshotContentDigest(t *testing.T) {
 	require.Equal(t, checksumInBytes, actualChecksum)
 }
 
+func TestMaintenanceSnapshotRateLimit(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	const limit = 1024 * 1024
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1, SnapshotSendRateLimit: limit})
+	defer clus.Terminate(t)
+
+	populateDataIntoCluster(t, clus, 3, 1024*1024)
+
+	start := time.Now()
+	rc, err := clus.RandClient().Snapshot(context.Background())
+	require.NoError(t, err)
+	defer rc.Close()
+	n, err := io.Copy(io.Discard, rc)
+	require.NoError(t, err)
+
+	// the first second of bytes is the burst.
+	want := time.Duration(float64(n-limit) / limit * float64(time.Second))
+	require.GreaterOrEqualf(t, time.Since(start), want*9/10, "received %d bytes", n)
+}
+
 func TestMaintenanceStatus(t *testing.T) {
 	integration2.BeforeTest(t)
 
@@ -440,3 +464,621 @@ func TestMaintenanceStatus(t *testing.T) {
 		t.Fatal("no leader found")
 	}
 }
+
+func TestMaintenanceConfig(t *testing.T) {
+	tcs := []struct {
+		name string
+		cfg  integration2.ClusterConfig
+
+		wantMode      string
+		wantRetention string
+		wantQuota     int64
+		wantMaxBytes  uint64
+	}{
+		{
+			name:          "default",
+			cfg:           integration2.ClusterConfig{Size: 1},
+			wantRetention: "0",
+			wantQuota:     storage.DefaultQuotaBytes,
+			wantMaxBytes:  embed.DefaultMaxRequestBytes,
+		},
+		{
+			name: "periodic",
+			cfg: integration2.ClusterConfig{
+				Size:                    1,
+				AutoCompactionMode:      "periodic",
+				AutoCompactionRetention: 90 * time.Minute,
+				QuotaBackendBytes:       1 << 30,
+				MaxRequestBytes:         64 * 1024,
+			},
+			wantMode:      "periodic",
+			wantRetention: "1h30m0s",
+			wantQuota:     1 << 30,
+			wantMaxBytes:  64 * 1024,
+		},
+		{
+			name: "revision",
+			cfg: integration2.ClusterConfig{
+				Size:                    1,
+				AutoCompactionMode:      "revision",
+				AutoCompactionRetention: 1000,
+				QuotaBackendBytes:       -1,
+			},
+			wantMode:      "revision",
+			wantRetention: "1000",
+			wantQuota:     0,
+			wantMaxBytes:  embed.DefaultMaxRequestBytes,
+		},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			integration2.BeforeTest(t)
+
+			clus := integration2.NewCluster(t, &tc.cfg)
+			defer clus.Terminate(t)
+
+			resp, err := clus.RandClient().Config(context.TODO(), clus.Members[0].GRPCURL)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(clus.Members[0].ID()), resp.Header.MemberId)
+			assert.Equal(t, tc.wantMode, resp.AutoCompactionMode)
+			assert.Equal(t, tc.wantRetention, resp.AutoCompactionRetention)
+			assert.Equal(t, tc.wantQuota, resp.QuotaBackendBytes)
+			assert.Equal(t, tc.wantMaxBytes, resp.MaxRequestBytes)
+		})
+	}
+}
+
+func TestMaintenanceKeyAccessTimes(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1, KeyAccessSampleRate: 1})
+	defer clus.Terminate(t)
+
+	cli := clus.RandClient()
+	ep := clus.Members[0].GRPCURL
+	ctx := context.TODO()
+	for _, k := range []string{"k/a", "k/b", "k/c"} {
+		_, err := cli.Put(ctx, k, "v")
+		require.NoError(t, err)
+	}
+
+	before := time.Now()
+	_, err := cli.Get(ctx, "k/a")
+	require.NoError(t, err)
+	_, err = cli.Get(ctx, "k/b", clientv3.WithSerializable())
+	require.NoError(t, err)
+	resp, err := cli.KeyAccessTimes(ctx, ep, "k/", clientv3.WithPrefix())
+	require.NoError(t, err)
+	require.Len(t, resp.Keys, 2)
+	assert.Equal(t, "k/a", string(resp.Keys[0].Key))
+	assert.Equal(t, "k/b", string(resp.Keys[1].Key))
+	firstA := resp.Keys[0].LastAccessTime
+	assert.GreaterOrEqual(t, firstA, before.UnixNano())
+
+	// reading a key again updates its access time
+	_, err = cli.Get(ctx, "k/", clientv3.WithPrefix())
+	require.NoError(t, err)
+	_, err = cli.Get(ctx, "k/a")
+	require.NoError(t, err)
+	resp, err = cli.KeyAccessTimes(ctx, ep, "k/", clientv3.WithPrefix())
+	require.NoError(t, err)
+	require.Len(t, resp.Keys, 3)
+	assert.Equal(t, "k/a", string(resp.Keys[2].Key))
+	assert.Greater(t, resp.Keys[2].LastAccessTime, firstA)
+
+	resp, err = cli.KeyAccessTimes(ctx, ep, "k/", clientv3.WithPrefix(), clientv3.WithLimit(1))
+	require.NoError(t, err)
+	require.Len(t, resp.Keys, 1)
+}
+
+func TestMaintenanceKeyAccessTimesDisabled(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	_, err := clus.RandClient().KeyAccessTimes(context.TODO(), clus.Members[0].GRPCURL, "k/", clientv3.WithPrefix())
+	require.ErrorIs(t, err, rpctypes.ErrKeyAccessTrackingDisabled)
+}
+
+func TestMaintenanceMembershipCheck(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 3})
+	defer clus.Terminate(t)
+
+	m := clus.Members[0]
+	resp, err := clus.RandClient().MembershipCheck(context.TODO(), m.GRPCURL)
+	require.NoError(t, err)
+	require.True(t, resp.Consistent)
+	require.Empty(t, resp.Disagreements)
+	require.Len(t, resp.Views, 3)
+	require.Equal(t, uint64(m.Server.MemberID()), resp.Views[0].MemberId)
+	for _, v := range resp.Views {
+		require.Empty(t, v.Error)
+		require.Len(t, v.Members, 3)
+	}
+
+	// a member that cannot be reached makes the check inconclusive.
+	clus.Members[2].Stop(t)
+	resp, err = clus.RandClient().MembershipCheck(context.TODO(), m.GRPCURL)
+	require.NoError(t, err)
+	require.False(t, resp.Consistent)
+	require.Empty(t, resp.Disagreements)
+	var unreachable int
+	for _, v := range resp.Views {
+		if v.Error != "" {
+			require.Equal(t, uint64(clus.Members[2].Server.MemberID()), v.MemberId)
+			unreachable++
+		}
+	}
+	require.Equal(t, 1, unreachable)
+}
+
+func writeKEKFile(t *testing.T, path string, ids ...string) {
+	var b bytes.Buffer
+	for _, id := range ids {
+		key := sha256.Sum256([]byte(id))
+		fmt.Fprintf(&b, "%s:%s\n", id, base64.StdEncoding.EncodeToString(key[:]))
+	}
+	require.NoError(t, os.WriteFile(path, b.Bytes(), 0o600))
+}
+
+func TestMaintenanceRotateEncryptionKey(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	kekPath := filepath.Join(t.TempDir(), "kek")
+	writeKEKFile(t, kekPath, "kek1")
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 3, EncryptionKEKFile: kekPath})
+	defer clus.Terminate(t)
+
+	ctx := context.TODO()
+	cli := clus.RandClient()
+	_, err := cli.Put(ctx, "foo", "secret-value")
+	require.NoError(t, err)
+
+	// the WAL entries are encrypted too.
+	for _, m := range clus.Members {
+		files, gerr := filepath.Glob(filepath.Join(m.WALDir(), "*.wal"))
+		require.NoError(t, gerr)
+		require.NotEmpty(t, files)
+		for _, f := range files {
+			b, rerr := os.ReadFile(f)
+			require.NoError(t, rerr)
+			assert.NotContains(t, string(b), "secret-value")
+		}
+	}
+
+	// wrap the data encryption keys with a new key encryption key.
+	writeKEKFile(t, kekPath, "kek2", "kek1")
+	deks := map[uint64]struct{}{}
+	for _, m := range clus.Members {
+		resp, rerr := cli.RotateEncryptionKey(ctx, m.GRPCURL, false)
+		require.NoError(t, rerr)
+		assert.Equal(t, "kek2", resp.KekId)
+		deks[resp.DekId] = struct{}{}
+	}
+	assert.Len(t, deks, 3)
+
+	// re-encrypt the backend of a member with a new data encryption key.
+	m := clus.Members[0]
+	resp, err := cli.RotateEncryptionKey(ctx, m.GRPCURL, true)
+	require.NoError(t, err)
+	assert.NotContains(t, deks, resp.DekId)
+
+	// the backend no longer needs the previous key encryption key, but the
+	// WAL files written before the rotation still do until they are purged.
+	writeKEKFile(t, kekPath, "kek2", "kek1")
+	m.Stop(t)
+	require.NoError(t, m.Restart(t))
+	clus.WaitLeader(t)
+	gresp, err := clus.Client(0).Get(ctx, "foo", clientv3.WithSerializable())
+	require.NoError(t, err)
+	require.Len(t, gresp.Kvs, 1)
+	assert.Equal(t, "secret-value", string(gresp.Kvs[0].Value))
+
+	// the members hash the decrypted values.
+	var hashes []uint32
+	for _, m := range clus.Members {
+		hresp, herr := cli.HashKV(ctx, m.GRPCURL, gresp.Header.Revision)
+		require.NoError(t, herr)
+		hashes = append(hashes, hresp.Hash)
+	}
+	assert.Equal(t, hashes[0], hashes[1])
+	assert.Equal(t, hashes[0], hashes[2])
+}
+
+func TestMaintenanceRotateEncryptionKeyDisabled(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	_, err := clus.RandClient().RotateEncryptionKey(context.TODO(), clus.Members[0].GRPCURL, false)
+	require.ErrorIs(t, err, rpctypes.ErrEncryptionDisabled)
+}
+
+func TestMaintenanceDefragmentAsync(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	ctx := context.TODO()
+	cli := clus.RandClient()
+	ep := clus.Members[0].GRPCURL
+	for i := 0; i < 100; i++ {
+		_, err := cli.Put(ctx, fmt.Sprintf("foo%d", i), "bar")
+		require.NoError(t, err)
+	}
+
+	resp, err := cli.DefragmentStatus(ctx, ep)
+	require.NoError(t, err)
+	assert.False(t, resp.InProgress)
+	assert.Zero(t, resp.StartTime)
+
+	_, err = cli.DefragmentAsync(ctx, ep)
+	require.NoError(t, err)
+	var last *clientv3.DefragmentStatusResponse
+	require.Eventually(t, func() bool {
+		require.NoError(t, cli.WatchDefragmentStatus(ctx, ep, func(r *clientv3.DefragmentStatusResponse) { last = r }))
+		return last.FinishTime != 0
+	}, 10*time.Second, 100*time.Millisecond)
+	assert.False(t, last.InProgress)
+	assert.Equal(t, uint32(100), last.PercentComplete)
+	assert.NotZero(t, last.StartTime)
+	assert.GreaterOrEqual(t, last.FinishTime, last.StartTime)
+	assert.Empty(t, last.Error)
+
+	gresp, err := cli.Get(ctx, "foo", clientv3.WithPrefix(), clientv3.WithCountOnly())
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), gresp.Count)
+}
+
+func TestMaintenanceReadOnly(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 3})
+	defer clus.Terminate(t)
+
+	ctx := context.TODO()
+	cli := clus.Client(0)
+	_, err := cli.Put(ctx, "foo", "bar")
+	require.NoError(t, err)
+	lresp, err := cli.Grant(ctx, 60)
+	require.NoError(t, err)
+	wch := clus.Client(1).Watch(ctx, "foo", clientv3.WithRev(2))
+
+	resp, err := cli.ReadOnly(ctx, clientv3.ReadOnlyEnable)
+	require.NoError(t, err)
+	assert.True(t, resp.Enabled)
+
+	// the writes are rejected.
+	_, err = cli.Put(ctx, "foo", "baz")
+	require.ErrorIs(t, err, rpctypes.ErrReadOnly)
+	_, err = cli.Delete(ctx, "foo")
+	require.ErrorIs(t, err, rpctypes.ErrReadOnly)
+	_, err = cli.Txn(ctx).Then(clientv3.OpPut("foo", "baz")).Commit()
+	require.ErrorIs(t, err, rpctypes.ErrReadOnly)
+	_, err = cli.Compact(ctx, 1)
+	require.ErrorIs(t, err, rpctypes.ErrReadOnly)
+	_, err = cli.Grant(ctx, 60)
+	require.ErrorIs(t, err, rpctypes.ErrReadOnly)
+	_, err = cli.Revoke(ctx, lresp.ID)
+	require.ErrorIs(t, err, rpctypes.ErrReadOnly)
+
+	// the reads and the lease keepalives are not.
+	gresp, err := cli.Get(ctx, "foo")
+	require.NoError(t, err)
+	require.Len(t, gresp.Kvs, 1)
+	assert.Equal(t, "bar", string(gresp.Kvs[0].Value))
+	_, err = cli.Txn(ctx).Then(clientv3.OpGet("foo")).Commit()
+	require.NoError(t, err)
+	_, err = cli.KeepAliveOnce(ctx, lresp.ID)
+	require.NoError(t, err)
+
+	resp, err = clus.Client(2).ReadOnly(ctx, clientv3.ReadOnlyGet)
+	require.NoError(t, err)
+	assert.True(t, resp.Enabled)
+
+	// the mode survives a restart.
+	clus.Members[0].Stop(t)
+	require.NoError(t, clus.Members[0].Restart(t))
+	clus.WaitLeader(t)
+	_, err = clus.Client(0).Put(ctx, "foo", "baz")
+	require.ErrorIs(t, err, rpctypes.ErrReadOnly)
+
+	resp, err = clus.Client(0).ReadOnly(ctx, clientv3.ReadOnlyDisable)
+	require.NoError(t, err)
+	assert.False(t, resp.Enabled)
+	_, err = clus.Client(0).Put(ctx, "foo", "baz")
+	require.NoError(t, err)
+
+	wresp := <-wch
+	require.NoError(t, wresp.Err())
+	require.Len(t, wresp.Events, 1)
+	assert.Equal(t, "bar", string(wresp.Events[0].Kv.Value))
+
+	// the unknown actions are rejected.
+	mc := pb.NewMaintenanceClient(clus.Client(0).ActiveConnection())
+	_, err = mc.ReadOnly(ctx, &pb.ReadOnlyRequest{Action: pb.ReadOnlyRequest_ReadOnlyAction(3)})
+	require.ErrorIs(t, err, rpctypes.ErrGRPCInvalidReadOnlyAction)
+}
+
+func TestMaintenanceFollowerLag(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{
+		Size:                      3,
+		FollowerLagAlarmThreshold: 1,
+		FollowerLagAlarmDuration:  500 * time.Millisecond,
+	})
+	defer clus.Terminate(t)
+
+	ctx := context.TODO()
+	leadIdx := clus.WaitLeader(t)
+	followerIdx := (leadIdx + 1) % 3
+	follower := uint64(clus.Members[followerIdx].ID())
+	cli := clus.Client(leadIdx)
+
+	_, err := clus.Client(followerIdx).FollowerLag(ctx, clus.Members[followerIdx].GRPCURL)
+	require.ErrorIs(t, err, rpctypes.ErrNotLeader)
+
+	resp, err := cli.FollowerLag(ctx, clus.Members[leadIdx].GRPCURL)
+	require.NoError(t, err)
+	require.Len(t, resp.Followers, 2)
+	for _, f := range resp.Followers {
+		assert.NotEqual(t, uint64(clus.Members[leadIdx].ID()), f.ID)
+		assert.False(t, f.IsLearner)
+	}
+
+	// a stopped follower falls behind and raises the alarm.
+	clus.Members[followerIdx].Stop(t)
+	for i := 0; i < 5; i++ {
+		_, err = cli.Put(ctx, "foo", fmt.Sprint(i))
+		require.NoError(t, err)
+	}
+	resp, err = cli.FollowerLag(ctx, clus.Members[leadIdx].GRPCURL)
+	require.NoError(t, err)
+	for _, f := range resp.Followers {
+		if f.ID == follower {
+			assert.GreaterOrEqual(t, f.Lag, uint64(5))
+		}
+	}
+	followerLagAlarmed := func() bool {
+		aresp, aerr := cli.AlarmList(ctx)
+		require.NoError(t, aerr)
+		for _, a := range aresp.Alarms {
+			if a.Alarm == pb.AlarmType_FOLLOWER_LAG && a.MemberID == follower {
+				return true
+			}
+		}
+		return false
+	}
+	require.Eventually(t, followerLagAlarmed, 10*time.Second, 50*time.Millisecond)
+
+	// the alarm is cleared once the follower caught up.
+	require.NoError(t, clus.Members[followerIdx].Restart(t))
+	require.Eventually(t, func() bool { return !followerLagAlarmed() }, 10*time.Second, 50*time.Millisecond)
+}
+
+func TestMaintenanceHashKVCheck(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 3})
+	defer clus.Terminate(t)
+
+	cli := clus.RandClient()
+	for i := 0; i < 10; i++ {
+		_, err := cli.Put(context.TODO(), fmt.Sprintf("k%d", i), "v")
+		require.NoError(t, err)
+	}
+	m := clus.Members[0]
+
+	// the latest revision is hashed if the key-value store was never compacted.
+	resp, err := cli.HashKVCheck(context.TODO(), m.GRPCURL, 0)
+	require.NoError(t, err)
+	require.True(t, resp.Consistent)
+	require.Equal(t, int64(11), resp.Revision)
+	require.Len(t, resp.Hashes, 3)
+	require.Equal(t, uint64(m.Server.MemberID()), resp.Hashes[0].MemberId)
+
+	// the latest compacted revision is hashed once it was compacted.
+	_, err = cli.Compact(context.TODO(), 6, clientv3.WithCompactPhysical())
+	require.NoError(t, err)
+	resp, err = cli.HashKVCheck(context.TODO(), m.GRPCURL, 0)
+	require.NoError(t, err)
+	require.True(t, resp.Consistent)
+	require.Equal(t, int64(6), resp.Revision)
+	for _, h := range resp.Hashes {
+		require.Empty(t, h.Error)
+		require.Equal(t, resp.Hashes[0].CompactRevision, h.CompactRevision)
+		require.Equal(t, resp.Hashes[0].Hash, h.Hash)
+	}
+
+	resp, err = cli.HashKVCheck(context.TODO(), m.GRPCURL, 8)
+	require.NoError(t, err)
+	require.True(t, resp.Consistent)
+	require.Equal(t, int64(8), resp.Revision)
+
+	// a member that cannot be reached fails the check.
+	clus.Members[2].Stop(t)
+	resp, err = cli.HashKVCheck(context.TODO(), m.GRPCURL, 0)
+	require.NoError(t, err)
+	require.False(t, resp.Consistent)
+	var failed int
+	for _, h := range resp.Hashes {
+		if h.Error != "" {
+			require.Equal(t, uint64(clus.Members[2].Server.MemberID()), h.MemberId)
+			failed++
+		}
+	}
+	require.Equal(t, 1, failed)
+}
+
+func TestMaintenanceMirrorStatus(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	dst := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1, UseTCP: true})
+	defer dst.Terminate(t)
+	dcli := dst.Client(0)
+	_, err := dcli.Put(context.TODO(), "/app/stale", "v")
+	require.NoError(t, err)
+
+	src := integration2.NewCluster(t, &integration2.ClusterConfig{
+		Size:                  3,
+		MirrorTargetEndpoints: []string{dst.Members[0].GRPCURL},
+		MirrorPrefix:          "/app/",
+	})
+	defer src.Terminate(t)
+	leadIdx := src.WaitLeader(t)
+	followerIdx := (leadIdx + 1) % 3
+	cli := src.Client(leadIdx)
+
+	for i := 0; i < 5; i++ {
+		_, err = cli.Put(context.TODO(), fmt.Sprintf("/app/k%d", i), "v")
+		require.NoError(t, err)
+	}
+	_, err = cli.Put(context.TODO(), "/other", "v")
+	require.NoError(t, err)
+	_, err = cli.Delete(context.TODO(), "/app/k0")
+	require.NoError(t, err)
+
+	// the keys with the prefix are mirrored, the stale ones deleted.
+	require.Eventually(t, func() bool {
+		resp, err := cli.MirrorStatus(context.TODO(), src.Members[leadIdx].GRPCURL)
+		return err == nil && resp.Lag == 0 && resp.MirroredRevision == src.Members[leadIdx].Server.KV().Rev()
+	}, 10*time.Second, 50*time.Millisecond)
+	resp, err := dcli.Get(context.TODO(), "/", clientv3.WithPrefix())
+	require.NoError(t, err)
+	var keys []string
+	for _, kv := range resp.Kvs {
+		keys = append(keys, string(kv.Key))
+	}
+	require.Equal(t, []string{"/app/k1", "/app/k2", "/app/k3", "/app/k4", embed.DefaultMirrorCheckpointKey}, keys)
+
+	sresp, err := cli.MirrorStatus(context.TODO(), src.Members[leadIdx].GRPCURL)
+	require.NoError(t, err)
+	require.Equal(t, []string{dst.Members[0].GRPCURL}, sresp.TargetEndpoints)
+	require.Equal(t, "/app/", sresp.SourcePrefix)
+	require.Equal(t, "/app/", sresp.DestPrefix)
+	require.Empty(t, sresp.Error)
+
+	_, err = src.Client(followerIdx).MirrorStatus(context.TODO(), src.Members[followerIdx].GRPCURL)
+	require.ErrorIs(t, err, rpctypes.ErrNotLeader)
+
+	// a cluster not mirroring its keys reports an empty status.
+	sresp, err = dcli.MirrorStatus(context.TODO(), dst.Members[0].GRPCURL)
+	require.NoError(t, err)
+	require.Empty(t, sresp.TargetEndpoints)
+}
+
+func TestMaintenanceReclaim(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	ctx := context.TODO()
+	cli := clus.RandClient()
+	ep := clus.Members[0].GRPCURL
+	val := string(bytes.Repeat([]byte{'a'}, 64*1024))
+	var rev int64
+	for i := 0; i < 100; i++ {
+		resp, err := cli.Put(ctx, "foo", val)
+		require.NoError(t, err)
+		rev = resp.Header.Revision
+	}
+
+	var phases []pb.ReclaimResponse_Phase
+	var last *clientv3.ReclaimResponse
+	require.NoError(t, cli.Reclaim(ctx, ep, rev, func(r *clientv3.ReclaimResponse) {
+		if len(phases) == 0 || phases[len(phases)-1] != r.Phase {
+			phases = append(phases, r.Phase)
+		}
+		last = r
+	}))
+	assert.Equal(t, []pb.ReclaimResponse_Phase{pb.ReclaimResponse_COMPACTING, pb.ReclaimResponse_DEFRAGMENTING, pb.ReclaimResponse_DONE}, phases)
+	assert.Equal(t, uint32(100), last.PercentComplete)
+	assert.Greater(t, last.DbSizeBefore, last.DbSizeAfter)
+	assert.Equal(t, last.DbSizeBefore-last.DbSizeAfter, last.ReclaimedBytes)
+	assert.Greater(t, last.ReclaimedBytes, int64(50*len(val)))
+
+	_, err := cli.Get(ctx, "foo", clientv3.WithRev(rev-1))
+	require.ErrorIs(t, err, rpctypes.ErrCompacted)
+	gresp, err := cli.Get(ctx, "foo")
+	require.NoError(t, err)
+	require.Len(t, gresp.Kvs, 1)
+	assert.Equal(t, val, string(gresp.Kvs[0].Value))
+
+	// the compaction is skipped once the revision is compacted
+	require.NoError(t, cli.Reclaim(ctx, ep, rev, func(r *clientv3.ReclaimResponse) { last = r }))
+	assert.Equal(t, pb.ReclaimResponse_DONE, last.Phase)
+
+	err = cli.Reclaim(ctx, ep, rev+100, func(*clientv3.ReclaimResponse) {})
+	require.ErrorIs(t, err, rpctypes.ErrFutureRev)
+}
+
+func TestMaintenanceDowngradeCheck(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 3})
+	defer clus.Terminate(t)
+	cli := clus.RandClient()
+	_, err := cli.Put(context.TODO(), "foo", "bar")
+	require.NoError(t, err)
+
+	// the storage version is only set once the cluster version is.
+	var resp *clientv3.DowngradeCheckResponse
+	require.Eventually(t, func() bool {
+		var err error
+		resp, err = cli.DowngradeCheck(context.TODO(), "3.6")
+		require.NoError(t, err)
+		return resp.Downgradable
+	}, 5*time.Second, 50*time.Millisecond)
+	require.Empty(t, resp.Blockers)
+	require.Len(t, resp.Members, 3)
+	for _, m := range resp.Members {
+		require.Empty(t, m.Blockers)
+		require.NotEmpty(t, m.StorageVersion)
+	}
+
+	// the target must be the minor version below the cluster version.
+	resp, err = cli.DowngradeCheck(context.TODO(), "3.4")
+	require.NoError(t, err)
+	require.False(t, resp.Downgradable)
+	require.Equal(t, []string{"etcdserver: invalid downgrade target version"}, resp.Blockers)
+
+	// a member that cannot be checked blocks the downgrade.
+	clus.Members[2].Stop(t)
+	resp, err = clus.Client(0).DowngradeCheck(context.TODO(), "3.6")
+	require.NoError(t, err)
+	require.False(t, resp.Downgradable)
+	require.Empty(t, resp.Blockers)
+	for _, m := range resp.Members {
+		if m.MemberId == uint64(clus.Members[2].Server.MemberID()) {
+			require.Len(t, m.Blockers, 1)
+		} else {
+			require.Empty(t, m.Blockers)
+		}
+	}
+}
+
+func TestMaintenanceDowngradeCheckFeatures(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1, EnableParallelApply: true})
+	defer clus.Terminate(t)
+
+	var resp *clientv3.DowngradeCheckResponse
+	require.Eventually(t, func() bool {
+		var err error
+		resp, err = clus.RandClient().DowngradeCheck(context.TODO(), "3.6")
+		require.NoError(t, err)
+		return resp.Members[0].StorageVersion != ""
+	}, 5*time.Second, 50*time.Millisecond)
+	require.False(t, resp.Downgradable)
+	require.Len(t, resp.Members, 1)
+	require.Equal(t, []string{"ParallelApply"}, resp.Members[0].Features)
+	require.Equal(t, []string{"the feature ParallelApply is enabled"}, resp.Members[0].Blockers)
+}
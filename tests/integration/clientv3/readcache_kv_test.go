@@ -0,0 +1,70 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/readcache"
+	integration2 "go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestReadCacheInvalidation ensures that a write by another client drops
+// the cached responses of the ranges it changed.
+func TestReadCacheInvalidation(t *testing.T) {
+	integration2.BeforeTest(t)
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	cli := clus.Client(0)
+	kv, closeCache := readcache.NewKV(cli.KV, cli.Watcher, readcache.DefaultMaxRanges)
+	defer closeCache()
+	ctx := context.TODO()
+
+	_, err := cli.Put(ctx, "foo/a", "1")
+	require.NoError(t, err)
+	resp, err := kv.Get(ctx, "foo", clientv3.WithPrefix(), clientv3.WithSerializable())
+	require.NoError(t, err)
+	require.Len(t, resp.Kvs, 1)
+
+	// the cached response is returned as is.
+	cached, err := kv.Get(ctx, "foo", clientv3.WithPrefix(), clientv3.WithSerializable())
+	require.NoError(t, err)
+	require.Same(t, resp, cached)
+
+	// a write by another client eventually invalidates the cached range.
+	other, err := clus.NewClientV3(0)
+	require.NoError(t, err)
+	defer other.Close()
+	_, err = other.Put(ctx, "foo/b", "2")
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		resp, err := kv.Get(ctx, "foo", clientv3.WithPrefix(), clientv3.WithSerializable())
+		require.NoError(t, err)
+		return len(resp.Kvs) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// writes through the cache are read right away.
+	_, err = kv.Put(ctx, "foo/c", "3")
+	require.NoError(t, err)
+	resp, err = kv.Get(ctx, "foo", clientv3.WithPrefix(), clientv3.WithSerializable())
+	require.NoError(t, err)
+	require.Len(t, resp.Kvs, 3)
+}
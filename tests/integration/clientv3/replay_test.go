@@ -0,0 +1,85 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/replay"
+	integration2 "go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+func TestReplayRecordedSession(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	// session issues the same sequence of operations against any client.
+	session := func(cli *clientv3.Client) []any {
+		ctx := context.TODO()
+		var out []any
+		put, err := cli.Put(ctx, "foo", "bar")
+		require.NoError(t, err)
+		out = append(out, put)
+		txn, err := cli.Txn(ctx).
+			If(clientv3.Compare(clientv3.Value("foo"), "=", "bar")).
+			Then(clientv3.OpPut("foo", "baz")).
+			Commit()
+		require.NoError(t, err)
+		out = append(out, txn)
+		get, err := cli.Get(ctx, "foo")
+		require.NoError(t, err)
+		out = append(out, get)
+		del, err := cli.Delete(ctx, "foo")
+		require.NoError(t, err)
+		out = append(out, del)
+		_, err = cli.Compact(ctx, 100)
+		require.Error(t, err)
+		out = append(out, err.Error())
+		return out
+	}
+
+	var buf bytes.Buffer
+	rec := replay.NewRecorder(&buf)
+	recCli, err := integration2.NewClient(t, clientv3.Config{
+		Endpoints:   []string{clus.Members[0].GRPCURL},
+		DialOptions: rec.DialOptions(),
+	})
+	require.NoError(t, err)
+	defer recCli.Close()
+	recorded := session(recCli)
+	require.NoError(t, rec.Err())
+
+	// replay against an endpoint nothing listens on.
+	p, err := replay.NewPlayer(&buf)
+	require.NoError(t, err)
+	clus.Members[0].Stop(t)
+	playCli, err := integration2.NewClient(t, clientv3.Config{
+		Endpoints:   []string{clus.Members[0].GRPCURL},
+		DialOptions: p.DialOptions(),
+	})
+	require.NoError(t, err)
+	defer playCli.Close()
+	replayed := session(playCli)
+
+	require.Equal(t, recorded, replayed)
+	require.Equal(t, 0, p.Remaining())
+}
@@ -16,6 +16,7 @@ package snapshot_test
 
 import (
 	"context"
+	"crypto/ed25519"
 	"fmt"
 	"math/rand"
 	"net/url"
@@ -66,6 +67,43 @@ func TestSaveSnapshotVersion(t *testing.T) {
 	require.Equalf(t, "3.7.0", ver, "expected snapshot version %s, got %s:", "3.7.0", ver)
 }
 
+// TestSaveSnapshotManifest ensures that the manifest describes the saved
+// snapshot, with the revision and the cluster of the snapshot.
+func TestSaveSnapshotManifest(t *testing.T) {
+	testutil.SkipTestIfShortMode(t,
+		"Snapshot creation tests are depending on embedded etcd server so are integration-level tests.")
+
+	cfg := newEmbedConfig(t)
+	srv, err := embed.StartEtcd(cfg)
+	require.NoError(t, err)
+	defer srv.Close()
+	select {
+	case <-srv.Server.ReadyNotify():
+	case <-time.After(3 * time.Second):
+		t.Fatalf("failed to start embed.Etcd for creating snapshots")
+	}
+
+	ccfg := clientv3.Config{Endpoints: []string{cfg.AdvertiseClientUrls[0].String()}}
+	cli, err := integration2.NewClient(t, ccfg)
+	require.NoError(t, err)
+	defer cli.Close()
+	putResp, err := cli.Put(context.Background(), "foo", "bar")
+	require.NoError(t, err)
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+	dbPath := filepath.Join(t.TempDir(), "snapshot.db")
+	m, err := snapshot.SaveWithManifest(context.Background(), zaptest.NewLogger(t), ccfg, dbPath, priv)
+	require.NoError(t, err)
+	require.Equal(t, putResp.Header.Revision, m.Revision)
+	require.Equal(t, fmt.Sprintf("%x", putResp.Header.ClusterId), m.ClusterID)
+
+	m, err = snapshot.ReadManifest(dbPath + snapshot.ManifestSuffix)
+	require.NoError(t, err)
+	require.NoError(t, m.VerifySignature(pub))
+	require.NoError(t, m.VerifyFile(dbPath))
+}
+
 type kv struct {
 	k, v string
 }
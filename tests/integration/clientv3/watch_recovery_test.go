@@ -0,0 +1,68 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	integration2 "go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestWatchWithRecoveryCompacted ensures that a watch resuming from a
+// compacted revision receives the state of the range, then the following
+// changes.
+func TestWatchWithRecoveryCompacted(t *testing.T) {
+	integration2.BeforeTest(t)
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	cli := clus.RandClient()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := cli.Put(ctx, "foo/a", "1")
+	require.NoError(t, err)
+	startRev := resp.Header.Revision
+	_, err = cli.Put(ctx, "foo/b", "1")
+	require.NoError(t, err)
+	_, err = cli.Delete(ctx, "foo/a")
+	require.NoError(t, err)
+	resp, err = cli.Put(ctx, "foo/b", "2")
+	require.NoError(t, err)
+	_, err = cli.Compact(ctx, resp.Header.Revision)
+	require.NoError(t, err)
+
+	wch := clientv3.WatchWithRecovery(ctx, cli.KV, cli.Watcher, "foo/", clientv3.WithPrefix(), clientv3.WithRev(startRev))
+	wr := <-wch
+	require.NoError(t, wr.Err())
+	require.Len(t, wr.Events, 1)
+	require.Equal(t, mvccpb.PUT, wr.Events[0].Type)
+	require.Equal(t, "foo/b", string(wr.Events[0].Kv.Key))
+	require.Equal(t, "2", string(wr.Events[0].Kv.Value))
+
+	_, err = cli.Delete(ctx, "foo/b")
+	require.NoError(t, err)
+	wr = <-wch
+	require.NoError(t, wr.Err())
+	require.Len(t, wr.Events, 1)
+	require.Equal(t, mvccpb.DELETE, wr.Events[0].Type)
+	require.Equal(t, "foo/b", string(wr.Events[0].Kv.Key))
+}
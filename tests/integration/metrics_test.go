@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	"go.etcd.io/etcd/client/pkg/v3/transport"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/server/v3/storage"
@@ -193,3 +194,156 @@ func TestMetricsRangeDurationSeconds(t *testing.T) {
 	require.GreaterOrEqualf(t, rangeDuration, 0.0, "expected etcd_server_range_duration_seconds to be between 0 and %f, got %f", maxRangeDuration, rangeDuration)
 	require.LessOrEqualf(t, rangeDuration, maxRangeDuration, "expected etcd_server_range_duration_seconds to be between 0 and %f, got %f", maxRangeDuration, rangeDuration)
 }
+
+// TestMetricsCaller ensures client requests are partitioned by their caller
+// label in the per-caller metrics, with unlabeled requests counted as unknown
+// and the callers beyond the label limit counted as other.
+func TestMetricsCaller(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1, MaxCallerLabels: 2})
+	defer clus.Terminate(t)
+
+	m := clus.Members[0]
+	counter := func(name string, labels ...string) float64 {
+		v, err := m.Metric(name, labels...)
+		require.NoError(t, err)
+		if v == "" {
+			return 0
+		}
+		f, err := strconv.ParseFloat(v, 64)
+		require.NoError(t, err)
+		return f
+	}
+	requests := func(caller string) float64 {
+		return counter("etcd_server_caller_requests_total", `type="unary"`, fmt.Sprintf("caller=%q", caller))
+	}
+
+	unknownBefore, otherBefore := requests("unknown"), requests("other")
+
+	cli, err := integration.NewClient(t, clientv3.Config{
+		Endpoints:   []string{m.GRPCURL},
+		CallerLabel: "metrics-caller-a",
+	})
+	require.NoError(t, err)
+	defer cli.Close()
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		_, err = cli.Put(ctx, "foo", "bar")
+		require.NoError(t, err)
+	}
+	_, err = cli.Get(clientv3.WithCallerLabel(ctx, "metrics-caller-b"), "foo")
+	require.NoError(t, err)
+	// the label limit is reached, so another caller does not get its own series.
+	_, err = cli.Get(clientv3.WithCallerLabel(ctx, "metrics-caller-c"), "foo")
+	require.NoError(t, err)
+	_, err = clus.RandClient().Get(ctx, "foo")
+	require.NoError(t, err)
+
+	require.Equal(t, 3.0, requests("metrics-caller-a"))
+	require.Equal(t, 1.0, requests("metrics-caller-b"))
+	require.Zero(t, requests("metrics-caller-c"))
+	require.Equal(t, otherBefore+1, requests("other"))
+	require.GreaterOrEqual(t, requests("unknown"), unknownBefore+1)
+
+	require.Positive(t, counter("etcd_server_caller_received_bytes_total", `caller="metrics-caller-a"`))
+	require.Positive(t, counter("etcd_server_caller_sent_bytes_total", `caller="metrics-caller-a"`))
+}
+
+// TestClientRateLimit ensures the requests of a client identity over its rate
+// limit are rejected, without limiting the other identities.
+func TestClientRateLimit(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1, ClientRateLimitQPS: 0.01, ClientRateLimitBurst: 20})
+	defer clus.Terminate(t)
+
+	m := clus.Members[0]
+	authSetupUsers(t, integration.ToGRPC(clus.Client(0)).Auth, []user{{name: "alice", password: "123", role: "alice", key: "foo"}})
+	authSetupRoot(t, integration.ToGRPC(clus.Client(0)).Auth)
+	newClient := func(name string) *clientv3.Client {
+		cli, err := integration.NewClient(t, clientv3.Config{Endpoints: []string{m.GRPCURL}, Username: name, Password: "123"})
+		require.NoError(t, err)
+		return cli
+	}
+	alice, root := newClient("alice"), newClient("root")
+	defer alice.Close()
+	defer root.Close()
+
+	ctx := context.Background()
+	var err error
+	for i := 0; i < 21 && err == nil; i++ {
+		_, err = alice.Put(ctx, "foo", "bar")
+	}
+	require.ErrorIs(t, err, rpctypes.ErrRateLimited)
+	_, err = root.Get(ctx, "foo")
+	require.NoError(t, err)
+
+	v, err := m.Metric("etcd_server_client_rate_limited_total", `identity="user:alice"`, `limit="requests"`)
+	require.NoError(t, err)
+	require.Equal(t, "1", v)
+}
+
+// TestQoSPriority ensures the requests are served in the QoS class of their
+// priority, and the requests without a valid priority in the normal class.
+func TestQoSPriority(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1, QoSMaxInflightRequests: 1})
+	defer clus.Terminate(t)
+
+	m := clus.Members[0]
+	cli := clus.Client(0)
+	requests := func(class string) int {
+		v, err := m.Metric("etcd_server_qos_requests_total", fmt.Sprintf(`class="%s"`, class))
+		require.NoError(t, err)
+		if v == "" {
+			return 0
+		}
+		n, err := strconv.Atoi(v)
+		require.NoError(t, err)
+		return n
+	}
+	normalBefore := requests("normal")
+
+	ctx := context.Background()
+	_, err := cli.Put(clientv3.WithPriority(ctx, rpctypes.PriorityCritical), "foo", "bar")
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		_, err = cli.Get(clientv3.WithPriority(ctx, rpctypes.PriorityBackground), "foo")
+		require.NoError(t, err)
+	}
+	_, err = cli.Get(clientv3.WithPriority(ctx, "urgent"), "foo")
+	require.NoError(t, err)
+
+	require.Equal(t, 1, requests("critical"))
+	require.Equal(t, 2, requests("background"))
+	require.Equal(t, normalBefore+1, requests("normal"))
+}
+
+// TestClientIdentityMetrics ensures the requests are accounted to the auth
+// user of their client.
+func TestClientIdentityMetrics(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1, ClientIdentityMetrics: true})
+	defer clus.Terminate(t)
+
+	m := clus.Members[0]
+	authSetupUsers(t, integration.ToGRPC(clus.Client(0)).Auth, []user{{name: "alice", password: "123", role: "alice", key: "foo"}})
+	authSetupRoot(t, integration.ToGRPC(clus.Client(0)).Auth)
+	alice, err := integration.NewClient(t, clientv3.Config{Endpoints: []string{m.GRPCURL}, Username: "alice", Password: "123"})
+	require.NoError(t, err)
+	defer alice.Close()
+
+	ctx := context.Background()
+	_, err = alice.Put(ctx, "foo", "bar")
+	require.NoError(t, err)
+	_, err = alice.Put(ctx, "zoo", "bar")
+	require.ErrorIs(t, err, rpctypes.ErrPermissionDenied)
+
+	handled := func(code string) string {
+		v, err := m.Metric("etcd_server_client_identity_handled_total", `identity="user:alice"`, `grpc_method="/etcdserverpb.KV/Put"`, fmt.Sprintf(`grpc_code="%s"`, code))
+		require.NoError(t, err)
+		return v
+	}
+	require.Equal(t, "1", handled("OK"))
+	require.Equal(t, "1", handled("PermissionDenied"))
+}
@@ -50,6 +50,54 @@ func TestKVProxyRange(t *testing.T) {
 	client.Close()
 }
 
+func TestKVProxyCacheInvalidation(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	tests := []struct {
+		name string
+		cfg  grpcproxy.KvProxyConfig
+	}{
+		{name: "watch", cfg: grpcproxy.KvProxyConfig{CacheWatchInvalidation: true}},
+		{name: "ttl", cfg: grpcproxy.KvProxyConfig{CacheTTL: 100 * time.Millisecond}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := integration2.NewClient(t, clientv3.Config{
+				Endpoints:   []string{clus.Members[0].GRPCURL},
+				DialTimeout: 5 * time.Second,
+			})
+			require.NoError(t, err)
+			defer client.Close()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			kvp, donec := grpcproxy.NewKvProxyWithConfig(ctx, client, tt.cfg)
+			defer func() {
+				cancel()
+				<-donec
+			}()
+
+			key := "foo-" + tt.name
+			_, err = clus.Client(0).Put(context.Background(), key, "bar")
+			require.NoError(t, err)
+			get := func() string {
+				resp, rerr := kvp.Range(context.Background(), &pb.RangeRequest{Key: []byte(key), Serializable: true})
+				require.NoError(t, rerr)
+				require.Len(t, resp.Kvs, 1)
+				return string(resp.Kvs[0].Value)
+			}
+			require.Eventually(t, func() bool { return get() == "bar" }, 5*time.Second, 10*time.Millisecond)
+
+			// a write not made through the proxy must not be hidden by the cache.
+			_, err = clus.Client(0).Put(context.Background(), key, "baz")
+			require.NoError(t, err)
+			require.Eventually(t, func() bool { return get() == "baz" }, 5*time.Second, 10*time.Millisecond)
+		})
+	}
+}
+
 type kvproxyTestServer struct {
 	kp     pb.KVServer
 	c      *clientv3.Client
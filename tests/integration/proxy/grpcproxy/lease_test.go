@@ -0,0 +1,112 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcproxy
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/proxy/grpcproxy"
+	integration2 "go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+func TestLeaseProxyKeepAliveAggregation(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	client, err := integration2.NewClient(t, clientv3.Config{
+		Endpoints:   []string{clus.Members[0].GRPCURL},
+		DialTimeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lp, donec := grpcproxy.NewLeaseProxy(ctx, client)
+	server := grpc.NewServer()
+	pb.RegisterLeaseServer(server, lp)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(l)
+	defer func() {
+		cancel()
+		server.Stop()
+		<-donec
+	}()
+
+	var leases []clientv3.LeaseID
+	for range 3 {
+		resp, gerr := clus.Client(0).Grant(context.Background(), 60)
+		require.NoError(t, gerr)
+		leases = append(leases, resp.ID)
+	}
+
+	// many clients keep the same leases alive through the proxy.
+	const clients = 20
+	var wg sync.WaitGroup
+	errc := make(chan error, clients*len(leases))
+	for range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c, cerr := integration2.NewClient(t, clientv3.Config{
+				Endpoints:   []string{l.Addr().String()},
+				DialTimeout: 5 * time.Second,
+			})
+			if cerr != nil {
+				errc <- cerr
+				return
+			}
+			defer c.Close()
+			for _, id := range leases {
+				kctx, kcancel := context.WithTimeout(context.Background(), 5*time.Second)
+				resp, kerr := c.KeepAliveOnce(kctx, id)
+				kcancel()
+				if kerr == nil && (resp.ID != id || resp.TTL <= 0) {
+					t.Errorf("unexpected keepalive response %+v for lease %x", resp, id)
+				}
+				errc <- kerr
+			}
+		}()
+	}
+	wg.Wait()
+	close(errc)
+	for err := range errc {
+		require.NoError(t, err)
+	}
+
+	// a revoked lease is reported expired.
+	_, err = clus.Client(0).Revoke(context.Background(), leases[0])
+	require.NoError(t, err)
+	c, err := integration2.NewClient(t, clientv3.Config{
+		Endpoints:   []string{l.Addr().String()},
+		DialTimeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+	defer c.Close()
+	_, err = c.KeepAliveOnce(context.Background(), leases[0])
+	require.ErrorIs(t, err, rpctypes.ErrLeaseNotFound)
+}
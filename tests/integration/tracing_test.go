@@ -16,7 +16,10 @@ package integration
 
 import (
 	"context"
+	"maps"
 	"net"
+	"slices"
+	"sync"
 	"testing"
 	"time"
 
@@ -114,6 +117,101 @@ func TestTracing(t *testing.T) {
 	}
 }
 
+// TestTracingApplyPath ensures that the trace of a Put continues through the
+// raft proposal, the apply on the member and the backend commit.
+func TestTracingApplyPath(t *testing.T) {
+	testutil.SkipTestIfShortMode(t,
+		"Wal creation tests are depending on embedded etcd server so are integration-level tests.")
+	listener, err := net.Listen("tcp", "localhost:")
+	require.NoError(t, err)
+
+	traceFound := make(chan struct{})
+	defer close(traceFound)
+
+	var (
+		mu    sync.Mutex
+		found bool
+		// seen maps span names to their trace IDs
+		seen = map[string][]byte{}
+		want = []string{"etcdserverpb.KV/Put", "raft request", "raft propose", "wait apply", "apply", "mvcc put", "backend commit"}
+	)
+	srv := grpc.NewServer()
+	traceservice.RegisterTraceServiceServer(srv, &traceServer{
+		traceFound: traceFound,
+		filterFunc: func(req *traceservice.ExportTraceServiceRequest) bool {
+			mu.Lock()
+			defer mu.Unlock()
+			for _, resourceSpans := range req.GetResourceSpans() {
+				for _, scoped := range resourceSpans.GetScopeSpans() {
+					for _, span := range scoped.GetSpans() {
+						seen[span.GetName()] = span.GetTraceId()
+					}
+				}
+			}
+			for _, name := range want {
+				if _, ok := seen[name]; !ok {
+					return false
+				}
+			}
+			if found {
+				return false
+			}
+			found = true
+			return true
+		},
+	})
+
+	go srv.Serve(listener)
+	defer srv.Stop()
+
+	cfg := integration.NewEmbedConfig(t, "default")
+	cfg.EnableDistributedTracing = true
+	cfg.DistributedTracingAddress = listener.Addr().String()
+	cfg.DistributedTracingServiceName = "integration-test-tracing"
+	// sample only the requests of traced clients
+	cfg.DistributedTracingSamplingRatePerMillion = 0
+
+	etcdSrv, err := embed.StartEtcd(cfg)
+	require.NoError(t, err)
+	defer etcdSrv.Close()
+
+	select {
+	case <-etcdSrv.Server.ReadyNotify():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("failed to start embed.Etcd for test")
+	}
+
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	defer tracer.Shutdown(context.TODO())
+	tracingOpts := []otelgrpc.Option{
+		otelgrpc.WithTracerProvider(tracer),
+		otelgrpc.WithPropagators(propagation.TraceContext{}),
+	}
+	ccfg := clientv3.Config{
+		DialOptions: []grpc.DialOption{grpc.WithUnaryInterceptor(otelgrpc.UnaryClientInterceptor(tracingOpts...))},
+		Endpoints:   []string{cfg.AdvertiseClientUrls[0].String()},
+	}
+	cli, err := integration.NewClient(t, ccfg)
+	require.NoError(t, err)
+	defer cli.Close()
+
+	_, err = cli.Put(context.TODO(), "key", "value")
+	require.NoError(t, err)
+
+	select {
+	case <-traceFound:
+	case <-time.After(30 * time.Second):
+		mu.Lock()
+		defer mu.Unlock()
+		t.Fatalf("Timed out waiting for the apply path spans, got %v", slices.Sorted(maps.Keys(seen)))
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	for _, name := range want {
+		require.Equalf(t, seen["etcdserverpb.KV/Put"], seen[name], "span %q is not part of the trace of the Put", name)
+	}
+}
+
 func containsNodeListSpan(req *traceservice.ExportTraceServiceRequest) bool {
 	for _, resourceSpans := range req.GetResourceSpans() {
 		for _, attr := range resourceSpans.GetResource().GetAttributes() {
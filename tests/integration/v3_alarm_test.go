@@ -16,6 +16,7 @@ package integration
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"sync"
@@ -126,6 +127,59 @@ func TestV3StorageQuotaApply(t *testing.T) {
 	require.Errorf(t, err, "alarmed instance should reject put after reset")
 }
 
+// TestV3StorageQuotaGrace ensures that in grace mode, the writes exceeding
+// the quota are rejected without raising the NOSPACE alarm, and admitted
+// again once a compaction makes room.
+func TestV3StorageQuotaGrace(t *testing.T) {
+	integration.BeforeTest(t)
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+	clus.Members[0].QuotaBackendBytes = int64(64 * os.Getpagesize())
+	clus.Members[0].QuotaBackendGrace = true
+	clus.Members[0].Stop(t)
+	clus.Members[0].Restart(t)
+	clus.WaitMembersForLeader(t, clus.Members)
+	kvc := integration.ToGRPC(clus.Client(0)).KV
+	waitForRestart(t, kvc)
+
+	value := make([]byte, 4*os.Getpagesize())
+	var err error
+	for i := 0; i < 64 && err == nil; i++ {
+		_, err = kvc.Put(context.TODO(), &pb.PutRequest{Key: []byte(fmt.Sprintf("foo%d", i)), Value: value})
+	}
+	require.ErrorIs(t, err, rpctypes.ErrGRPCNoSpace)
+	requireNoAlarm(t, clus.Members[0])
+
+	// the deletes and the compactions are admitted above the quota
+	_, err = kvc.DeleteRange(context.TODO(), &pb.DeleteRangeRequest{Key: []byte("foo"), RangeEnd: []byte("fop")})
+	require.NoError(t, err)
+	resp, err := kvc.Put(context.TODO(), &pb.PutRequest{Key: []byte("bar")})
+	if err == nil {
+		_, err = kvc.Compact(context.TODO(), &pb.CompactionRequest{Revision: resp.Header.Revision, Physical: true})
+	} else {
+		var rresp *pb.RangeResponse
+		rresp, err = kvc.Range(context.TODO(), &pb.RangeRequest{Key: []byte("bar")})
+		require.NoError(t, err)
+		_, err = kvc.Compact(context.TODO(), &pb.CompactionRequest{Revision: rresp.Header.Revision, Physical: true})
+	}
+	require.NoError(t, err)
+
+	// the freed pages are counted once the commits release them
+	require.Eventually(t, func() bool {
+		_, err = kvc.Put(context.TODO(), &pb.PutRequest{Key: []byte("foo"), Value: value})
+		return err == nil
+	}, 5*time.Second, 100*time.Millisecond)
+	requireNoAlarm(t, clus.Members[0])
+}
+
+func requireNoAlarm(t *testing.T, m *integration.Member) {
+	t.Helper()
+	resp, err := m.Server.Alarm(context.TODO(), &pb.AlarmRequest{Action: pb.AlarmRequest_GET})
+	require.NoError(t, err)
+	require.Empty(t, resp.Alarms)
+}
+
 // TestV3AlarmDeactivate ensures that space alarms can be deactivated so puts go through.
 func TestV3AlarmDeactivate(t *testing.T) {
 	integration.BeforeTest(t)
@@ -17,12 +17,14 @@ package integration
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
 
 	"go.etcd.io/etcd/api/v3/authpb"
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
@@ -422,3 +424,208 @@ func TestV3AuthWatchErrorAndWatchId0(t *testing.T) {
 
 	<-watchEndCh
 }
+
+// TestV3AuthFineGrainedPermissions ensures that the watch, delete, lease
+// attach and txn compare permissions only grant their operation.
+func TestV3AuthFineGrainedPermissions(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+	defer cancel()
+
+	authc := integration.ToGRPC(clus.Client(0)).Auth
+	authSetupUsers(t, authc, []user{{name: "user1", password: "user1-123", role: "role1"}})
+	for _, permType := range []authpb.Permission_Type{authpb.WATCH, authpb.DELETE, authpb.LEASE_ATTACH, authpb.TXN_COMPARE} {
+		// each type is granted on its own prefix, as a range has one permission type
+		key := strings.ToLower(permType.String()) + "/"
+		perm := &authpb.Permission{PermType: permType, Key: []byte(key), RangeEnd: []byte(clientv3.GetPrefixRangeEnd(key))}
+		_, err := authc.RoleGrantPermission(ctx, &pb.AuthRoleGrantPermissionRequest{Name: "role1", Perm: perm})
+		require.NoError(t, err)
+	}
+	authSetupRoot(t, authc)
+
+	rootc, cerr := integration.NewClient(t, clientv3.Config{Endpoints: clus.Client(0).Endpoints(), Username: "root", Password: "123"})
+	require.NoError(t, cerr)
+	defer rootc.Close()
+	c, cerr := integration.NewClient(t, clientv3.Config{Endpoints: clus.Client(0).Endpoints(), Username: "user1", Password: "user1-123"})
+	require.NoError(t, cerr)
+	defer c.Close()
+
+	leaseResp, err := rootc.Grant(ctx, 90)
+	require.NoError(t, err)
+	for _, key := range []string{"watch/a", "delete/a", "lease_attach/a", "txn_compare/a"} {
+		_, err = rootc.Put(ctx, key, "val")
+		require.NoError(t, err)
+	}
+
+	// watch
+	wch := c.Watch(ctx, "watch/", clientv3.WithPrefix())
+	_, err = rootc.Put(ctx, "watch/b", "val")
+	require.NoError(t, err)
+	wresp := <-wch
+	require.NoError(t, wresp.Err())
+	require.Len(t, wresp.Events, 1)
+	wresp = <-c.Watch(ctx, "watch/", clientv3.WithPrefix(), clientv3.WithRev(1))
+	require.ErrorContains(t, wresp.Err(), rpctypes.ErrPermissionDenied.Error())
+	wresp = <-c.Watch(ctx, "watch/", clientv3.WithPrefix(), clientv3.WithPrevKV())
+	require.ErrorContains(t, wresp.Err(), rpctypes.ErrPermissionDenied.Error())
+	_, err = c.Get(ctx, "watch/a")
+	require.ErrorIs(t, err, rpctypes.ErrPermissionDenied)
+
+	// delete
+	_, err = c.Delete(ctx, "delete/a")
+	require.NoError(t, err)
+	_, err = c.Put(ctx, "delete/a", "val")
+	require.ErrorIs(t, err, rpctypes.ErrPermissionDenied)
+	_, err = c.Delete(ctx, "watch/a")
+	require.ErrorIs(t, err, rpctypes.ErrPermissionDenied)
+
+	// lease attach
+	_, err = c.Put(ctx, "lease_attach/a", "", clientv3.WithIgnoreValue(), clientv3.WithLease(leaseResp.ID))
+	require.NoError(t, err)
+	_, err = c.Put(ctx, "lease_attach/a", "val", clientv3.WithLease(leaseResp.ID))
+	require.ErrorIs(t, err, rpctypes.ErrPermissionDenied)
+
+	// txn compare
+	_, err = c.Txn(ctx).If(clientv3.Compare(clientv3.Value("txn_compare/a"), "=", "val")).Commit()
+	require.NoError(t, err)
+	_, err = c.Txn(ctx).If(clientv3.Compare(clientv3.Value("delete/a"), "=", "val")).Commit()
+	require.ErrorIs(t, err, rpctypes.ErrPermissionDenied)
+}
+
+// TestV3AuthLockout ensures that a user failing to authenticate repeatedly is
+// locked out, even with the right password.
+func TestV3AuthLockout(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1, AuthLockoutUserFailures: 3})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+	defer cancel()
+
+	authc := integration.ToGRPC(clus.Client(0)).Auth
+	authSetupRoot(t, authc)
+
+	for range 3 {
+		_, err := authc.Authenticate(ctx, &pb.AuthenticateRequest{Name: "root", Password: "wrong"})
+		require.Truef(t, eqErrGRPC(err, rpctypes.ErrGRPCAuthFailed), "got %v, expected %v", err, rpctypes.ErrGRPCAuthFailed)
+	}
+	_, err := authc.Authenticate(ctx, &pb.AuthenticateRequest{Name: "root", Password: "123"})
+	require.Truef(t, eqErrGRPC(err, rpctypes.ErrGRPCAuthLockedOut), "got %v, expected %v", err, rpctypes.ErrGRPCAuthLockedOut)
+
+	// the lockout expires after the lockout duration
+	require.Eventually(t, func() bool {
+		_, err = authc.Authenticate(ctx, &pb.AuthenticateRequest{Name: "root", Password: "123"})
+		return err == nil
+	}, 5*time.Second, 100*time.Millisecond)
+}
+
+func TestV3AuthTokenRevoke(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+	defer cancel()
+
+	authc := integration.ToGRPC(clus.Client(0)).Auth
+	kvc := integration.ToGRPC(clus.Client(0)).KV
+	authSetupUsers(t, authc, []user{{name: "user1", password: "user1-123", role: "role1", key: "foo"}})
+	authSetupRoot(t, authc)
+
+	authenticate := func(name, password string) context.Context {
+		resp, err := authc.Authenticate(ctx, &pb.AuthenticateRequest{Name: name, Password: password})
+		require.NoError(t, err)
+		return metadata.AppendToOutgoingContext(ctx, rpctypes.TokenFieldNameGRPC, resp.Token)
+	}
+	tokenOf := func(ctx context.Context) string {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		return md.Get(rpctypes.TokenFieldNameGRPC)[0]
+	}
+
+	user1Ctx := authenticate("user1", "user1-123")
+	otherCtx := authenticate("user1", "user1-123")
+	rootCtx := authenticate("root", "123")
+
+	// a user may revoke its own tokens
+	_, err := authc.TokenRevoke(user1Ctx, &pb.AuthTokenRevokeRequest{Token: tokenOf(user1Ctx)})
+	require.NoError(t, err)
+	_, err = kvc.Range(user1Ctx, &pb.RangeRequest{Key: []byte("foo")})
+	require.Truef(t, eqErrGRPC(err, rpctypes.ErrGRPCInvalidAuthToken), "got %v, expected %v", err, rpctypes.ErrGRPCInvalidAuthToken)
+	_, err = kvc.Range(otherCtx, &pb.RangeRequest{Key: []byte("foo")})
+	require.NoError(t, err)
+
+	// revoking the tokens of the other users requires the root role
+	_, err = authc.TokenRevoke(otherCtx, &pb.AuthTokenRevokeRequest{Token: tokenOf(rootCtx)})
+	require.Truef(t, eqErrGRPC(err, rpctypes.ErrGRPCPermissionDenied), "got %v, expected %v", err, rpctypes.ErrGRPCPermissionDenied)
+	_, err = authc.TokenRevoke(rootCtx, &pb.AuthTokenRevokeRequest{Token: tokenOf(otherCtx)})
+	require.NoError(t, err)
+	_, err = kvc.Range(otherCtx, &pb.RangeRequest{Key: []byte("foo")})
+	require.Truef(t, eqErrGRPC(err, rpctypes.ErrGRPCInvalidAuthToken), "got %v, expected %v", err, rpctypes.ErrGRPCInvalidAuthToken)
+
+	_, err = authc.TokenRevoke(rootCtx, &pb.AuthTokenRevokeRequest{Token: "invalid"})
+	require.Truef(t, eqErrGRPC(err, rpctypes.ErrGRPCInvalidAuthToken), "got %v, expected %v", err, rpctypes.ErrGRPCInvalidAuthToken)
+}
+
+func TestV3AuthPasswordExpiry(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1, AuthPasswordMaxAge: 2 * time.Second, AuthPasswordGracePeriod: 3 * time.Second})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 20*time.Second)
+	defer cancel()
+
+	authc := integration.ToGRPC(clus.Client(0)).Auth
+	authSetupUsers(t, authc, []user{{name: "user1", password: "user1-123", role: "role1", key: "foo"}})
+	authSetupRoot(t, authc)
+
+	resp, err := authc.Authenticate(ctx, &pb.AuthenticateRequest{Name: "user1", Password: "user1-123"})
+	require.NoError(t, err)
+	require.Greater(t, resp.PasswordExpireTime, time.Now().Unix()-1)
+
+	// an expired password authenticates during its grace period, so that
+	// the user can change it
+	require.Eventually(t, func() bool {
+		resp, err = authc.Authenticate(ctx, &pb.AuthenticateRequest{Name: "user1", Password: "user1-123"})
+		require.NoError(t, err)
+		return resp.PasswordExpireTime <= time.Now().Unix()
+	}, 5*time.Second, 100*time.Millisecond)
+	userCtx := metadata.AppendToOutgoingContext(ctx, rpctypes.TokenFieldNameGRPC, resp.Token)
+	_, err = authc.UserChangePassword(userCtx, &pb.AuthUserChangePasswordRequest{Name: "user1", Password: "user1-456"})
+	require.NoError(t, err)
+	resp, err = authc.Authenticate(ctx, &pb.AuthenticateRequest{Name: "user1", Password: "user1-456"})
+	require.NoError(t, err)
+	require.Greater(t, resp.PasswordExpireTime, time.Now().Unix())
+
+	// users can't change the passwords of the others
+	_, err = authc.UserChangePassword(metadata.AppendToOutgoingContext(ctx, rpctypes.TokenFieldNameGRPC, resp.Token), &pb.AuthUserChangePasswordRequest{Name: "root", Password: "456"})
+	require.Truef(t, eqErrGRPC(err, rpctypes.ErrGRPCPermissionDenied), "got %v, expected %v", err, rpctypes.ErrGRPCPermissionDenied)
+
+	// the password of root was not changed in its grace period
+	require.Eventually(t, func() bool {
+		_, err = authc.Authenticate(ctx, &pb.AuthenticateRequest{Name: "root", Password: "123"})
+		return err != nil && eqErrGRPC(err, rpctypes.ErrGRPCAuthPasswordExpired)
+	}, 10*time.Second, 100*time.Millisecond)
+}
+
+func TestV3AuthChangeOwnPasswordWithoutExpiry(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 20*time.Second)
+	defer cancel()
+
+	authc := integration.ToGRPC(clus.Client(0)).Auth
+	authSetupUsers(t, authc, []user{{name: "user1", password: "user1-123", role: "role1", key: "foo"}})
+	authSetupRoot(t, authc)
+
+	// the passwords don't expire, so only root changes them
+	resp, err := authc.Authenticate(ctx, &pb.AuthenticateRequest{Name: "user1", Password: "user1-123"})
+	require.NoError(t, err)
+	userCtx := metadata.AppendToOutgoingContext(ctx, rpctypes.TokenFieldNameGRPC, resp.Token)
+	_, err = authc.UserChangePassword(userCtx, &pb.AuthUserChangePasswordRequest{Name: "user1", Password: "user1-456"})
+	require.Truef(t, eqErrGRPC(err, rpctypes.ErrGRPCPermissionDenied), "got %v, expected %v", err, rpctypes.ErrGRPCPermissionDenied)
+}
@@ -135,6 +135,49 @@ func TestV3CompactCurrentRev(t *testing.T) {
 	require.NoErrorf(t, err, "couldn't get serialized key after compaction")
 }
 
+// TestV3CompactionControlKey ensures that writing a revision to the compaction
+// control key compacts the store to that revision, and that invalid values are
+// ignored.
+func TestV3CompactionControlKey(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 3, CompactionControlKey: "/control/compact"})
+	defer clus.Terminate(t)
+
+	kvc := integration.ToGRPC(clus.RandClient()).KV
+	preq := &pb.PutRequest{Key: []byte("foo"), Value: []byte("bar")}
+	for i := 0; i < 5; i++ {
+		_, err := kvc.Put(context.Background(), preq)
+		require.NoErrorf(t, err, "couldn't put key")
+	}
+
+	// ranges at revision 3 succeed until the store is compacted to 4.
+	compacted := func() bool {
+		_, err := kvc.Range(context.Background(), &pb.RangeRequest{Key: []byte("foo"), Revision: 3})
+		if err != nil {
+			require.Truef(t, eqErrGRPC(err, rpctypes.ErrGRPCCompacted), "unexpected range error %v", err)
+			return true
+		}
+		return false
+	}
+
+	for _, v := range []string{"not-a-revision", "-1", "1000"} {
+		_, err := kvc.Put(context.Background(), &pb.PutRequest{Key: []byte("/control/compact"), Value: []byte(v)})
+		require.NoError(t, err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	require.Falsef(t, compacted(), "invalid control key values must not compact the store")
+
+	_, err := kvc.Put(context.Background(), &pb.PutRequest{Key: []byte("/control/compact"), Value: []byte("4")})
+	require.NoError(t, err)
+	require.Eventually(t, compacted, 5*time.Second, 10*time.Millisecond)
+
+	_, err = kvc.Range(context.Background(), &pb.RangeRequest{Key: []byte("foo"), Revision: 4})
+	require.NoErrorf(t, err, "revision 4 must be kept")
+	for _, m := range clus.Members {
+		require.Eventually(t, func() bool { return m.Server.KV().FirstRev() == 4 }, 5*time.Second, 10*time.Millisecond)
+	}
+}
+
 // TestV3HashKV ensures that multiple calls of HashKV on same node return same hash and compact rev.
 func TestV3HashKV(t *testing.T) {
 	integration.BeforeTest(t)
@@ -572,6 +615,72 @@ func TestV3TxnRangeCompare(t *testing.T) {
 			},
 			true,
 		},
+		{
+			// prefix /a/* has 3 keys
+			pb.Compare{
+				Key:         []byte("/a/"),
+				RangeEnd:    []byte("/a0"),
+				Target:      pb.Compare_COUNT,
+				Result:      pb.Compare_EQUAL,
+				TargetUnion: &pb.Compare_Count{Count: 3},
+			},
+			true,
+		},
+		{
+			// prefix /a/* has no more than 3 keys
+			pb.Compare{
+				Key:         []byte("/a/"),
+				RangeEnd:    []byte("/a0"),
+				Target:      pb.Compare_COUNT,
+				Result:      pb.Compare_GREATER,
+				TargetUnion: &pb.Compare_Count{Count: 3},
+			},
+			false,
+		},
+		{
+			// prefix /b/* has no keys
+			pb.Compare{
+				Key:         []byte("/b/"),
+				RangeEnd:    []byte("/b0"),
+				Target:      pb.Compare_COUNT,
+				Result:      pb.Compare_EQUAL,
+				TargetUnion: &pb.Compare_Count{Count: 0},
+			},
+			true,
+		},
+		{
+			// all values start with the prefix
+			pb.Compare{
+				Key:         []byte("/a/"),
+				RangeEnd:    []byte("/a0"),
+				Target:      pb.Compare_VALUE_PREFIX,
+				Result:      pb.Compare_EQUAL,
+				TargetUnion: &pb.Compare_ValuePrefix{ValuePrefix: []byte("x")},
+			},
+			true,
+		},
+		{
+			// the prefix is longer than the values
+			pb.Compare{
+				Key:         []byte("/a/"),
+				RangeEnd:    []byte("/a0"),
+				Target:      pb.Compare_VALUE_PREFIX,
+				Result:      pb.Compare_EQUAL,
+				TargetUnion: &pb.Compare_ValuePrefix{ValuePrefix: []byte("xy")},
+			},
+			false,
+		},
+		{
+			// does not exist, does not succeed
+			pb.Compare{
+				Key:         []byte("/b/"),
+				RangeEnd:    []byte("/b0"),
+				Target:      pb.Compare_VALUE_PREFIX,
+				Result:      pb.Compare_NOT_EQUAL,
+				TargetUnion: &pb.Compare_ValuePrefix{ValuePrefix: []byte("x")},
+			},
+			false,
+		},
 	}
 
 	kvc := integration.ToGRPC(clus.Client(0)).KV
@@ -1273,6 +1382,71 @@ func TestV3StorageQuotaAPI(t *testing.T) {
 	}
 }
 
+// TestV3KeyQuota ensures the writes over a key quota are rejected with a
+// dedicated error, while the other keys can still be written.
+func TestV3KeyQuota(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 3, KeyQuotas: []string{"prefix:tenant/=1KiB/2"}})
+	defer clus.Terminate(t)
+	kvc := integration.ToGRPC(clus.RandClient()).KV
+
+	for _, key := range []string{"tenant/1", "tenant/2"} {
+		_, err := kvc.Put(t.Context(), &pb.PutRequest{Key: []byte(key), Value: []byte("v")})
+		require.NoError(t, err)
+	}
+	_, err := kvc.Put(t.Context(), &pb.PutRequest{Key: []byte("tenant/3"), Value: []byte("v")})
+	require.Truef(t, eqErrGRPC(err, rpctypes.ErrGRPCKeyQuotaExceeded), "got %v, expected %v", err, rpctypes.ErrGRPCKeyQuotaExceeded)
+	_, err = kvc.Put(t.Context(), &pb.PutRequest{Key: []byte("tenant/1"), Value: make([]byte, 1024)})
+	require.Truef(t, eqErrGRPC(err, rpctypes.ErrGRPCKeyQuotaExceeded), "got %v, expected %v", err, rpctypes.ErrGRPCKeyQuotaExceeded)
+
+	_, err = kvc.Put(t.Context(), &pb.PutRequest{Key: []byte("other/1"), Value: make([]byte, 1024)})
+	require.NoError(t, err)
+	_, err = kvc.DeleteRange(t.Context(), &pb.DeleteRangeRequest{Key: []byte("tenant/2")})
+	require.NoError(t, err)
+	_, err = kvc.Put(t.Context(), &pb.PutRequest{Key: []byte("tenant/3"), Value: []byte("v")})
+	require.NoError(t, err)
+}
+
+// TestV3Namespace ensures the keys of the requests of a namespace are
+// isolated under its key prefix, and bounded by its key quota.
+func TestV3Namespace(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1, Namespaces: []string{"a", "b"}, KeyQuotas: []string{"namespace:a=1KiB/1"}})
+	defer clus.Terminate(t)
+	cli := clus.Client(0)
+	ctxA := clientv3.WithNamespace(t.Context(), "a")
+	ctxB := clientv3.WithNamespace(t.Context(), "b")
+
+	wch := cli.Watch(ctxA, "", clientv3.WithPrefix())
+	_, err := cli.Put(ctxA, "k", "va")
+	require.NoError(t, err)
+	_, err = cli.Put(ctxB, "k", "vb")
+	require.NoError(t, err)
+
+	resp, err := cli.Get(ctxA, "", clientv3.WithPrefix())
+	require.NoError(t, err)
+	require.Len(t, resp.Kvs, 1)
+	require.Equal(t, "k", string(resp.Kvs[0].Key))
+	require.Equal(t, "va", string(resp.Kvs[0].Value))
+	wresp := <-wch
+	require.NoError(t, wresp.Err())
+	require.Len(t, wresp.Events, 1)
+	require.Equal(t, "k", string(wresp.Events[0].Kv.Key))
+
+	resp, err = cli.Get(t.Context(), "/namespaces/", clientv3.WithPrefix())
+	require.NoError(t, err)
+	require.Len(t, resp.Kvs, 2)
+	require.Equal(t, "/namespaces/a/k", string(resp.Kvs[0].Key))
+	require.Equal(t, "/namespaces/b/k", string(resp.Kvs[1].Key))
+
+	_, err = cli.Put(ctxA, "k2", "v")
+	require.ErrorIs(t, err, rpctypes.ErrKeyQuotaExceeded)
+	_, err = cli.Put(clientv3.WithNamespace(t.Context(), "c"), "k", "v")
+	require.ErrorIs(t, err, rpctypes.ErrUnknownNamespace)
+	_, err = cli.Compact(ctxA, resp.Header.Revision)
+	require.ErrorIs(t, err, rpctypes.ErrNotSupportedInNamespace)
+}
+
 func TestV3RangeRequest(t *testing.T) {
 	integration.BeforeTest(t)
 	tests := []struct {
@@ -1895,6 +2069,40 @@ func TestV3LargeRequests(t *testing.T) {
 	}
 }
 
+// TestV3RequestDeadlineMargin ensures that the server rejects a request
+// without serving it when the client deadline is closer than the margin.
+func TestV3RequestDeadlineMargin(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1, RequestDeadlineMargin: 2 * time.Second})
+	defer clus.Terminate(t)
+
+	kvcli := integration.ToGRPC(clus.Client(0)).KV
+	for i := 0; i < 100; i++ {
+		reqput := &pb.PutRequest{Key: []byte(fmt.Sprintf("foo%03d", i)), Value: make([]byte, 1024)}
+		_, err := kvcli.Put(context.TODO(), reqput)
+		require.NoError(t, err)
+	}
+	reqget := &pb.RangeRequest{Key: []byte("foo"), RangeEnd: []byte("fop")}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	_, err := kvcli.Range(ctx, reqget)
+	cancel()
+	if !eqErrGRPC(err, rpctypes.ErrGRPCDeadlineTooShort) {
+		t.Fatalf("expected error %v, got %v", rpctypes.ErrGRPCDeadlineTooShort, err)
+	}
+	require.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	resp, err := kvcli.Range(ctx, reqget)
+	cancel()
+	require.NoError(t, err)
+	require.Len(t, resp.Kvs, 100)
+
+	// requests without a deadline are always served.
+	_, err = kvcli.Range(context.TODO(), reqget)
+	require.NoError(t, err)
+}
+
 // TestV3AdditionalGRPCOptions ensures that configurable GRPCAdditionalServerOptions works as intended.
 func TestV3AdditionalGRPCOptions(t *testing.T) {
 	integration.BeforeTest(t)
@@ -16,8 +16,12 @@ package integration
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"sync"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -66,3 +70,94 @@ func TestKVWithEmptyValue(t *testing.T) {
 	require.NoError(t, err)
 	t.Logf("delete keys:%d", respDel.Deleted)
 }
+
+// TestKVParallelApply ensures that the writes applied with the ParallelApply
+// feature get a revision each, and leave the members with the same data.
+func TestKVParallelApply(t *testing.T) {
+	integration.BeforeTest(t)
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 3, EnableParallelApply: true})
+	defer clus.Terminate(t)
+
+	const writers, writes, counters = 8, 50, 2
+	var (
+		mu   sync.Mutex
+		revs = make(map[int64]struct{})
+		wg   sync.WaitGroup
+	)
+	record := func(rev int64) {
+		mu.Lock()
+		defer mu.Unlock()
+		_, ok := revs[rev]
+		assert.Falsef(t, ok, "revision %d is returned twice", rev)
+		revs[rev] = struct{}{}
+	}
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cli := clus.Client(w % 3)
+			ctx := context.Background()
+			counter := fmt.Sprintf("counter%d", w%counters)
+			for i := 0; i < writes; i++ {
+				presp, err := cli.Put(ctx, fmt.Sprintf("key-%d-%d", w, i), "v")
+				if !assert.NoError(t, err) {
+					return
+				}
+				record(presp.Header.Revision)
+
+				// increment the counter shared with other writers.
+				for {
+					gresp, err := cli.Get(ctx, counter)
+					if !assert.NoError(t, err) {
+						return
+					}
+					var n, modRev int64
+					if len(gresp.Kvs) != 0 {
+						n, _ = strconv.ParseInt(string(gresp.Kvs[0].Value), 10, 64)
+						modRev = gresp.Kvs[0].ModRevision
+					}
+					tresp, err := cli.Txn(ctx).
+						If(clientv3.Compare(clientv3.ModRevision(counter), "=", modRev)).
+						Then(clientv3.OpPut(counter, strconv.FormatInt(n+1, 10))).
+						Commit()
+					if !assert.NoError(t, err) {
+						return
+					}
+					if tresp.Succeeded {
+						record(tresp.Header.Revision)
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	var total int64
+	for c := 0; c < counters; c++ {
+		resp, err := clus.Client(0).Get(context.Background(), fmt.Sprintf("counter%d", c))
+		require.NoError(t, err)
+		require.Len(t, resp.Kvs, 1)
+		n, err := strconv.ParseInt(string(resp.Kvs[0].Value), 10, 64)
+		require.NoError(t, err)
+		total += n
+	}
+	assert.Equal(t, int64(writers*writes), total)
+
+	resp, err := clus.Client(0).Get(context.Background(), "foo")
+	require.NoError(t, err)
+	rev := resp.Header.Revision
+	assert.Len(t, revs, 2*writers*writes)
+	assert.Equal(t, int64(2*writers*writes+1), rev)
+
+	var hash uint32
+	for i, m := range clus.Members {
+		hresp, err := clus.Client(0).HashKV(context.Background(), m.GRPCURL, rev)
+		require.NoError(t, err)
+		if i == 0 {
+			hash = hresp.Hash
+		}
+		assert.Equal(t, hash, hresp.Hash)
+	}
+}
@@ -270,3 +270,45 @@ func checkFirstCommitNotification(
 
 	return nil
 }
+
+// TestLeaderLeaseReads ensures that the leader serves linearizable reads from
+// its leader lease, and that the reads stay linearizable across a leadership
+// transfer.
+func TestLeaderLeaseReads(t *testing.T) {
+	integration.BeforeTest(t)
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 3, LeaderLeaseReads: true})
+	defer clus.Terminate(t)
+
+	ctx := context.TODO()
+	oldLeadIdx := clus.WaitLeader(t)
+	cli := clus.Client(oldLeadIdx)
+	_, err := cli.Put(ctx, "foo", "bar")
+	require.NoError(t, err)
+
+	before, err := clus.Members[oldLeadIdx].Metric("etcd_server_leader_lease_reads_total")
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		resp, gerr := cli.Get(ctx, "foo")
+		require.NoError(t, gerr)
+		require.Len(t, resp.Kvs, 1)
+		require.Equal(t, "bar", string(resp.Kvs[0].Value))
+	}
+	after, err := clus.Members[oldLeadIdx].Metric("etcd_server_leader_lease_reads_total")
+	require.NoError(t, err)
+	require.NotEqual(t, before, after, "expected reads served from the leader lease")
+
+	// the old leader reads the writes of the new leader once it stepped down.
+	newLeadIdx := (oldLeadIdx + 1) % 3
+	target := uint64(clus.Members[newLeadIdx].Server.MemberID())
+	_, err = cli.MoveLeader(ctx, target)
+	require.NoError(t, err)
+	require.Equal(t, newLeadIdx, clus.WaitLeader(t))
+
+	_, err = clus.Client(newLeadIdx).Put(ctx, "foo", "baz")
+	require.NoError(t, err)
+	resp, err := cli.Get(ctx, "foo")
+	require.NoError(t, err)
+	require.Len(t, resp.Kvs, 1)
+	require.Equal(t, "baz", string(resp.Kvs[0].Value))
+}
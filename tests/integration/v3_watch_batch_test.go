@@ -0,0 +1,72 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !cluster_proxy
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestV3WatchEventBatching ensures the server batches the events of a watch created
+// with batching, flushing a batch once it holds the maximum number of events or
+// once the batch interval elapsed.
+func TestV3WatchEventBatching(t *testing.T) {
+	integration.BeforeTest(t)
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cli := clus.RandClient()
+	wch := cli.Watch(ctx, "foo", clientv3.WithPrefix(), clientv3.WithCreatedNotify(), clientv3.WithEventBatching(2*time.Second, 10))
+	wresp := <-wch
+	require.True(t, wresp.Created)
+
+	for i := 0; i < 25; i++ {
+		_, err := cli.Put(ctx, fmt.Sprintf("foo%d", i), "bar")
+		require.NoError(t, err)
+	}
+
+	var sizes []int
+	var lastRev int64
+	for received := 0; received < 25; {
+		select {
+		case wresp = <-wch:
+			require.NoError(t, wresp.Err())
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for events, got %d", received)
+		}
+		for _, ev := range wresp.Events {
+			require.Greater(t, ev.Kv.ModRevision, lastRev)
+			lastRev = ev.Kv.ModRevision
+		}
+		require.Equal(t, lastRev, wresp.Header.Revision)
+		sizes = append(sizes, len(wresp.Events))
+		received += len(wresp.Events)
+	}
+	// the last 5 events are flushed by the batch interval
+	require.Equal(t, []int{10, 10, 5}, sizes)
+}
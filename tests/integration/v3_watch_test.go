@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -1344,6 +1345,318 @@ func TestV3WatchWithPrevKV(t *testing.T) {
 	}
 }
 
+// TestV3WatchMaxEventRate ensures the server caps the rate of events sent to a
+// watch with a max event rate and delivers the latest value of each key.
+func TestV3WatchMaxEventRate(t *testing.T) {
+	integration.BeforeTest(t)
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	const (
+		keys         = 5
+		puts         = 500
+		maxEventRate = 20
+	)
+	cli := clus.RandClient()
+	wch := cli.Watch(ctx, "foo", clientv3.WithPrefix(), clientv3.WithMaxEventRate(maxEventRate), clientv3.WithCreatedNotify())
+	wresp := <-wch
+	require.True(t, wresp.Created)
+
+	start := time.Now()
+	for i := 0; i < puts; i++ {
+		_, err := cli.Put(ctx, fmt.Sprintf("foo%d", i%keys), fmt.Sprint(i))
+		require.NoError(t, err)
+	}
+
+	latest := make(map[string]string)
+	received := 0
+	for !wantLatest(latest, keys, puts) {
+		select {
+		case wresp = <-wch:
+			require.NoError(t, wresp.Err())
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for the latest values, got %v", latest)
+		}
+		for _, ev := range wresp.Events {
+			latest[string(ev.Kv.Key)] = string(ev.Kv.Value)
+			received++
+		}
+		// the burst allows one second worth of events on top of the rate
+		elapsed := time.Since(start).Seconds()
+		limit := maxEventRate * (elapsed + 1)
+		require.LessOrEqualf(t, float64(received), limit, "received %d events in %v", received, time.Since(start))
+	}
+	require.Less(t, received, puts)
+}
+
+func wantLatest(latest map[string]string, keys, puts int) bool {
+	for i := puts - keys; i < puts; i++ {
+		if latest[fmt.Sprintf("foo%d", i%keys)] != fmt.Sprint(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// TestV3WatchValueFilter ensures the server only sends the put events whose
+// value matches the value filters of the watch, and all the delete events.
+func TestV3WatchValueFilter(t *testing.T) {
+	integration.BeforeTest(t)
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cli := clus.RandClient()
+	wch := cli.Watch(ctx, "foo", clientv3.WithPrefix(), clientv3.WithCreatedNotify(),
+		clientv3.WithValuePrefix("v"), clientv3.WithValueContains("ok"), clientv3.WithMinValueSize(4), clientv3.WithMaxValueSize(8))
+	wresp := <-wch
+	require.True(t, wresp.Created)
+
+	for _, v := range []string{"okay", "v-ok", "v-ok-too-large", "v-no", "vok", "v-ok-2"} {
+		_, err := cli.Put(ctx, "foo", v)
+		require.NoError(t, err)
+	}
+	_, err := cli.Delete(ctx, "foo")
+	require.NoError(t, err)
+
+	var got []string
+	for len(got) < 3 {
+		select {
+		case wresp = <-wch:
+			require.NoError(t, wresp.Err())
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for events, got %v", got)
+		}
+		for _, ev := range wresp.Events {
+			got = append(got, fmt.Sprintf("%s %s", ev.Type, ev.Kv.Value))
+		}
+	}
+	require.Equal(t, []string{"PUT v-ok", "PUT v-ok-2", "DELETE "}, got)
+}
+
+// TestV3WatchBookmark ensures the server sends bookmarks to a watch created with a
+// bookmark interval while events keep arriving, and that no event of the watch up to
+// the revision of a bookmark is sent after it.
+func TestV3WatchBookmark(t *testing.T) {
+	integration.BeforeTest(t)
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	cli := clus.RandClient()
+	wch := cli.Watch(ctx, "foo", clientv3.WithPrefix(), clientv3.WithCreatedNotify(), clientv3.WithBookmarkInterval(time.Second))
+	wresp := <-wch
+	require.True(t, wresp.Created)
+
+	// keep writing so that progress notifications would be elided
+	donec := make(chan struct{})
+	go func() {
+		defer close(donec)
+		for i := 0; ctx.Err() == nil; i++ {
+			if _, err := cli.Put(ctx, fmt.Sprintf("foo%d", i%10), fmt.Sprint(i)); err != nil {
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}()
+	defer func() {
+		cancel()
+		<-donec
+	}()
+
+	var lastEventRev, bookmarkRev int64
+	for bookmarks := 0; bookmarks < 2; {
+		select {
+		case wresp = <-wch:
+			require.NoError(t, wresp.Err())
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for bookmarks, got %d", bookmarks)
+		}
+		for _, ev := range wresp.Events {
+			require.Greater(t, ev.Kv.ModRevision, bookmarkRev)
+			lastEventRev = ev.Kv.ModRevision
+		}
+		if wresp.Bookmark {
+			require.Empty(t, wresp.Events)
+			require.GreaterOrEqual(t, wresp.Header.Revision, lastEventRev)
+			bookmarkRev = wresp.Header.Revision
+			bookmarks++
+		}
+	}
+	require.NotZero(t, lastEventRev)
+}
+
+// TestV3WatchLimits ensures the watch streams and the watchers of a stream
+// past their limits are rejected, and admitted again once others are closed.
+func TestV3WatchLimits(t *testing.T) {
+	integration.BeforeTest(t)
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1, MaxWatchStreams: 2, MaxWatchersPerStream: 2})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	wapi := integration.ToGRPC(clus.RandClient()).Watch
+	create := func(ws pb.Watch_WatchClient) (*pb.WatchResponse, error) {
+		err := ws.Send(&pb.WatchRequest{RequestUnion: &pb.WatchRequest_CreateRequest{
+			CreateRequest: &pb.WatchCreateRequest{Key: []byte("foo")},
+		}})
+		if err != nil {
+			return nil, err
+		}
+		return ws.Recv()
+	}
+
+	ws, err := wapi.Watch(ctx)
+	require.NoError(t, err)
+	var ids []int64
+	for i := 0; i < 2; i++ {
+		wresp, cerr := create(ws)
+		require.NoError(t, cerr)
+		require.False(t, wresp.Canceled)
+		ids = append(ids, wresp.WatchId)
+	}
+	wresp, err := create(ws)
+	require.NoError(t, err)
+	require.True(t, wresp.Canceled)
+	require.Equal(t, rpctypes.ErrorDesc(rpctypes.ErrGRPCTooManyWatchers), wresp.CancelReason)
+
+	require.NoError(t, ws.Send(&pb.WatchRequest{RequestUnion: &pb.WatchRequest_CancelRequest{
+		CancelRequest: &pb.WatchCancelRequest{WatchId: ids[0]},
+	}}))
+	wresp, err = ws.Recv()
+	require.NoError(t, err)
+	require.True(t, wresp.Canceled)
+	wresp, err = create(ws)
+	require.NoError(t, err)
+	require.False(t, wresp.Canceled)
+
+	sctx, scancel := context.WithCancel(ctx)
+	ws2, err := wapi.Watch(sctx)
+	require.NoError(t, err)
+	_, err = create(ws2)
+	require.NoError(t, err)
+	ws3, err := wapi.Watch(ctx)
+	require.NoError(t, err)
+	_, err = create(ws3)
+	require.ErrorIs(t, rpctypes.Error(err), rpctypes.ErrTooManyWatchStreams)
+
+	// the stream slot is released once the stream is closed.
+	scancel()
+	require.Eventually(t, func() bool {
+		ws4, werr := wapi.Watch(ctx)
+		if werr != nil {
+			return false
+		}
+		_, werr = create(ws4)
+		return werr == nil
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+// TestV3WatchSnapshotOnCompaction ensures a watch created with snapshot on compaction
+// at a compacted revision gets a snapshot of the range at the current revision instead
+// of the compacted error, followed by the events after it.
+func TestV3WatchSnapshotOnCompaction(t *testing.T) {
+	integration.BeforeTest(t)
+	if integration.ThroughProxy {
+		t.Skip("grpc proxy currently does not support snapshots on compaction")
+	}
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cli := clus.RandClient()
+	for _, k := range []string{"foo1", "foo2", "foo3", "bar"} {
+		_, err := cli.Put(ctx, k, "v-"+k)
+		require.NoError(t, err)
+	}
+	_, err := cli.Delete(ctx, "foo2")
+	require.NoError(t, err)
+	presp, err := cli.Put(ctx, "foo3", "v-foo3-2")
+	require.NoError(t, err)
+	rev := presp.Header.Revision
+	_, err = cli.Compact(ctx, rev)
+	require.NoError(t, err)
+
+	// a watch without the option is still compacted
+	wresp := <-cli.Watch(ctx, "foo", clientv3.WithPrefix(), clientv3.WithRev(2))
+	require.ErrorIs(t, wresp.Err(), rpctypes.ErrCompacted)
+
+	wch := cli.Watch(ctx, "foo", clientv3.WithPrefix(), clientv3.WithRev(2), clientv3.WithSnapshotOnCompaction())
+	wresp = <-wch
+	require.NoError(t, wresp.Err())
+	require.True(t, wresp.Snapshot)
+	require.Equal(t, rev, wresp.Header.Revision)
+	var got []string
+	for _, ev := range wresp.Events {
+		got = append(got, fmt.Sprintf("%s %s=%s", ev.Type, ev.Kv.Key, ev.Kv.Value))
+	}
+	require.Equal(t, []string{"PUT foo1=v-foo1", "PUT foo3=v-foo3-2"}, got)
+
+	presp, err = cli.Put(ctx, "foo4", "v-foo4")
+	require.NoError(t, err)
+	wresp = <-wch
+	require.NoError(t, wresp.Err())
+	require.False(t, wresp.Snapshot)
+	require.Len(t, wresp.Events, 1)
+	require.Equal(t, presp.Header.Revision, wresp.Events[0].Kv.ModRevision)
+
+	// a watch at a revision which is not compacted gets the events
+	wresp = <-cli.Watch(ctx, "foo", clientv3.WithPrefix(), clientv3.WithRev(rev), clientv3.WithSnapshotOnCompaction())
+	require.NoError(t, wresp.Err())
+	require.False(t, wresp.Snapshot)
+	require.Equal(t, rev, wresp.Events[0].Kv.ModRevision)
+}
+
+// TestV3WatchSnapshotOnCompactionFragment ensures a snapshot larger than the max request
+// size is split into fragments, even if the watcher did not ask for them.
+func TestV3WatchSnapshotOnCompactionFragment(t *testing.T) {
+	integration.BeforeTest(t)
+	if integration.ThroughProxy {
+		t.Skip("grpc proxy currently does not support snapshots on compaction")
+	}
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{
+		Size:                     1,
+		MaxRequestBytes:          64 * 1024,
+		ClientMaxCallRecvMsgSize: 1024 * 1024,
+	})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cli := clus.RandClient()
+	var rev int64
+	for i := 0; i < 20; i++ {
+		presp, err := cli.Put(ctx, fmt.Sprintf("foo%02d", i), strings.Repeat("a", 60*1024))
+		require.NoError(t, err)
+		rev = presp.Header.Revision
+	}
+	_, err := cli.Compact(ctx, rev)
+	require.NoError(t, err)
+
+	wresp := <-cli.Watch(ctx, "foo", clientv3.WithPrefix(), clientv3.WithRev(2), clientv3.WithSnapshotOnCompaction())
+	require.NoError(t, wresp.Err())
+	require.True(t, wresp.Snapshot)
+	require.Equal(t, rev, wresp.Header.Revision)
+	require.Len(t, wresp.Events, 20)
+}
+
 // TestV3WatchCancellation ensures that watch cancellation frees up server resources.
 func TestV3WatchCancellation(t *testing.T) {
 	integration.BeforeTest(t)
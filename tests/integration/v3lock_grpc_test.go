@@ -69,3 +69,31 @@ func TestV3LockLockWaiter(t *testing.T) {
 	case <-lockc:
 	}
 }
+
+// TestV3LockLeaseStewardOrphanedKey tests that the lease steward deletes a
+// key without lease under a lock prefix, which blocks the waiters of the lock.
+func TestV3LockLeaseStewardOrphanedKey(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{
+		Size:                 1,
+		LeaseStewardPrefixes: []string{"foo/"},
+		LeaseStewardInterval: 100 * time.Millisecond,
+		LeaseStewardDelete:   true,
+	})
+	defer clus.Terminate(t)
+
+	kvc := integration.ToGRPC(clus.Client(0)).KV
+	_, err := kvc.Put(t.Context(), &pb.PutRequest{Key: []byte("foo/orphan")})
+	require.NoError(t, err)
+	lresp, err := integration.ToGRPC(clus.Client(0)).Lease.LeaseGrant(t.Context(), &pb.LeaseGrantRequest{TTL: 30})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	_, err = integration.ToGRPC(clus.Client(0)).Lock.Lock(ctx, &lockpb.LockRequest{Name: []byte("foo"), Lease: lresp.ID})
+	require.NoError(t, err)
+
+	resp, err := kvc.Range(t.Context(), &pb.RangeRequest{Key: []byte("foo/orphan")})
+	require.NoError(t, err)
+	require.Empty(t, resp.Kvs)
+}
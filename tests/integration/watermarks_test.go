@@ -0,0 +1,97 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/etcdserver"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/etcdhttp"
+	"go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestWatermarks drives load against a member, checks the reported
+// high-watermarks, and ensures that a reset clears them.
+func TestWatermarks(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	tr, err := transport.NewTimeoutTransport(transport.TLSInfo{}, time.Second, time.Second, time.Second)
+	require.NoError(t, err)
+	hc := &http.Client{Transport: tr}
+	url := clus.Members[0].ClientURLs[0].String() + etcdhttp.PathWatermarks
+	watermarks := func(method string) etcdserver.Watermarks {
+		req, err := http.NewRequest(method, url, nil)
+		require.NoError(t, err)
+		resp, err := hc.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var wm etcdserver.Watermarks
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&wm))
+		return wm
+	}
+
+	cli := clus.RandClient()
+	numWatchers := 10
+	ctx, cancel := context.WithCancel(context.Background())
+	for i := 0; i < numWatchers; i++ {
+		wch := cli.Watch(ctx, fmt.Sprintf("foo%d", i), clientv3.WithCreatedNotify())
+		<-wch
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				_, err := cli.Put(context.TODO(), fmt.Sprintf("foo%d", j), "bar")
+				if err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	wm := watermarks(http.MethodGet)
+	require.GreaterOrEqual(t, wm.ApplyQueueDepth, int64(1))
+	require.GreaterOrEqual(t, wm.Watchers, int64(numWatchers))
+	require.Positive(t, wm.HeapBytes)
+
+	// cancel the watchers so that the watcher count drops back to zero.
+	cancel()
+	require.Eventually(t, func() bool {
+		watermarks(http.MethodDelete)
+		return clus.Members[0].Server.KV().Watchers().Value() == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	wm = watermarks(http.MethodGet)
+	require.Zero(t, wm.ApplyQueueDepth)
+	require.Zero(t, wm.Watchers)
+	require.Positive(t, wm.HeapBytes)
+}
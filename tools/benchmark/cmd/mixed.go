@@ -0,0 +1,311 @@
+// Copyright 2025 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cheggaaa/pb/v3"
+	"github.com/spf13/cobra"
+	"golang.org/x/time/rate"
+
+	v3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/pkg/v3/report"
+)
+
+// mixedCmd represents the mixed command
+var mixedCmd = &cobra.Command{
+	Use:   "mixed",
+	Short: "Benchmark a mixed load of gets, ranges, puts and deletes.",
+	Long: `Benchmark a mixed load of gets, ranges, puts and deletes.
+
+The requests are drawn at the ratios given by --ratio, on keys drawn from
+the key space with the distribution given by --key-distribution. The first
+--warmup-total requests are not measured. The latencies of each kind of
+request are reported in the format given by --output-format:
+
+  simple       the summary and the histogram of the other commands
+  hdr          the percentile distribution format of HdrHistogram, in milliseconds
+  openmetrics  the OpenMetrics text format, as the histogram
+               benchmark_request_seconds by the label op
+`,
+
+	Run: mixedFunc,
+}
+
+var (
+	mixedTotal           int
+	mixedWarmupTotal     int
+	mixedRate            int
+	mixedRatio           string
+	mixedKeyDistribution string
+	mixedZipfS           float64
+	mixedZipfV           float64
+	mixedKeySpaceSize    int
+	mixedRangeLimit      int64
+	mixedSeed            int64
+	mixedOutputFormat    string
+	mixedOutputFile      string
+)
+
+// mixedOps are the kinds of requests of the mixed load, in report order.
+var mixedOps = []string{"get", "range", "put", "delete"}
+
+func init() {
+	RootCmd.AddCommand(mixedCmd)
+	mixedCmd.Flags().IntVar(&keySize, "key-size", 8, "Key size of the requests")
+	mixedCmd.Flags().IntVar(&valSize, "val-size", 8, "Value size of the puts")
+	mixedCmd.Flags().IntVar(&mixedRate, "rate", 0, "Maximum requests per second (0 is no limit)")
+	mixedCmd.Flags().IntVar(&mixedTotal, "total", 10000, "Total number of measured requests")
+	mixedCmd.Flags().IntVar(&mixedWarmupTotal, "warmup-total", 0, "Number of requests sent before the measured ones, which are not measured")
+	mixedCmd.Flags().StringVar(&mixedRatio, "ratio", "get=1,put=1", "Ratios of the kinds of requests, as comma-separated '<get|range|put|delete>=<weight>'")
+	mixedCmd.Flags().IntVar(&mixedKeySpaceSize, "key-space-size", 1000, "Maximum possible keys")
+	mixedCmd.Flags().StringVar(&mixedKeyDistribution, "key-distribution", "uniform", "Distribution of the keys of the requests ('uniform' or 'zipfian')")
+	mixedCmd.Flags().Float64Var(&mixedZipfS, "zipf-s", 1.1, "Skew of the zipfian distribution of the keys (must be greater than 1)")
+	mixedCmd.Flags().Float64Var(&mixedZipfV, "zipf-v", 1, "Offset of the zipfian distribution of the keys (must be at least 1)")
+	mixedCmd.Flags().Int64Var(&mixedRangeLimit, "range-limit", 100, "Maximum number of keys read by a range, from its key")
+	mixedCmd.Flags().StringVar(&rangeConsistency, "consistency", "l", "Linearizable(l) or Serializable(s)")
+	mixedCmd.Flags().Int64Var(&mixedSeed, "seed", 1, "Seed of the random requests and keys, so that runs send the same requests")
+	mixedCmd.Flags().StringVar(&mixedOutputFormat, "output-format", "simple", "Format of the latencies ('simple', 'hdr' or 'openmetrics')")
+	mixedCmd.Flags().StringVar(&mixedOutputFile, "output-file", "", "File the latencies are written to (default stdout)")
+}
+
+type mixedRequest struct {
+	kind    string
+	op      v3.Op
+	measure bool
+}
+
+func mixedFunc(cmd *cobra.Command, _ []string) {
+	if mixedKeySpaceSize <= 0 {
+		fmt.Fprintf(os.Stderr, "expected positive --key-space-size, got (%v)\n", mixedKeySpaceSize)
+		os.Exit(1)
+	}
+	if rangeConsistency != "l" && rangeConsistency != "s" {
+		fmt.Fprintln(os.Stderr, cmd.Usage())
+		os.Exit(1)
+	}
+	switch mixedOutputFormat {
+	case "simple", "hdr", "openmetrics":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown --output-format %q\n", mixedOutputFormat)
+		os.Exit(1)
+	}
+	weights, err := parseMixedRatio(mixedRatio)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --ratio: %v\n", err)
+		os.Exit(1)
+	}
+	rnd := rand.New(rand.NewSource(mixedSeed))
+	nextKey, err := newKeyGenerator(rnd, mixedKeyDistribution, mixedKeySpaceSize)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --key-distribution: %v\n", err)
+		os.Exit(1)
+	}
+	out := io.Writer(os.Stdout)
+	if mixedOutputFile != "" {
+		f, ferr := os.Create(mixedOutputFile)
+		if ferr != nil {
+			fmt.Fprintf(os.Stderr, "failed to create --output-file: %v\n", ferr)
+			os.Exit(1)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	requests := make(chan mixedRequest, totalClients)
+	if mixedRate == 0 {
+		mixedRate = math.MaxInt32
+	}
+	limit := rate.NewLimiter(rate.Limit(mixedRate), 1)
+	clients := mustCreateClients(totalClients, totalConns)
+	v := string(mustRandBytes(valSize))
+
+	bar = pb.New(mixedWarmupTotal + mixedTotal)
+	bar.Start()
+
+	reports := make(map[string]report.Report)
+	for kind, w := range weights {
+		if w > 0 {
+			reports[kind] = newReport()
+		}
+	}
+	for i := range clients {
+		wg.Add(1)
+		go func(c *v3.Client) {
+			defer wg.Done()
+			for req := range requests {
+				limit.Wait(context.Background())
+				st := time.Now()
+				_, err := c.Do(context.Background(), req.op)
+				if req.measure {
+					reports[req.kind].Results() <- report.Result{Err: err, Start: st, End: time.Now()}
+				}
+				bar.Increment()
+			}
+		}(clients[i])
+	}
+
+	go func() {
+		k := make([]byte, keySize)
+		for i := 0; i < mixedWarmupTotal+mixedTotal; i++ {
+			kind := pickMixedOp(rnd, weights)
+			binary.PutVarint(k, int64(nextKey()))
+			req := mixedRequest{kind: kind, measure: i >= mixedWarmupTotal}
+			switch kind {
+			case "get", "range":
+				var opts []v3.OpOption
+				if kind == "range" {
+					opts = append(opts, v3.WithFromKey(), v3.WithLimit(mixedRangeLimit))
+				}
+				if rangeConsistency == "s" {
+					opts = append(opts, v3.WithSerializable())
+				}
+				req.op = v3.OpGet(string(k), opts...)
+			case "put":
+				req.op = v3.OpPut(string(k), v)
+			case "delete":
+				req.op = v3.OpDelete(string(k))
+			}
+			requests <- req
+		}
+		close(requests)
+	}()
+
+	if mixedOutputFormat == "simple" {
+		rcs := make(map[string]<-chan string)
+		for kind, r := range reports {
+			rcs[kind] = r.Run()
+		}
+		wg.Wait()
+		for _, r := range reports {
+			close(r.Results())
+		}
+		bar.Finish()
+		for _, kind := range mixedOps {
+			if rc, ok := rcs[kind]; ok {
+				fmt.Fprintf(out, "%s:", kind)
+				fmt.Fprintln(out, <-rc)
+			}
+		}
+		return
+	}
+
+	scs := make(map[string]<-chan report.Stats)
+	for kind, r := range reports {
+		scs[kind] = r.Stats()
+	}
+	wg.Wait()
+	for _, r := range reports {
+		close(r.Results())
+	}
+	bar.Finish()
+	stats := make(map[string]report.Stats)
+	for kind, sc := range scs {
+		stats[kind] = <-sc
+	}
+	if mixedOutputFormat == "openmetrics" {
+		err = report.WriteOpenMetrics(out, "benchmark_request", "op", stats)
+	} else {
+		for _, kind := range mixedOps {
+			s, ok := stats[kind]
+			if !ok {
+				continue
+			}
+			fmt.Fprintf(out, "# %s\n", kind)
+			if err = s.WriteHDR(out); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write the latencies: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// parseMixedRatio parses the weights of the kinds of requests, given as
+// comma-separated '<kind>=<weight>'.
+func parseMixedRatio(s string) (map[string]float64, error) {
+	weights := make(map[string]float64)
+	var total float64
+	for _, r := range strings.Split(s, ",") {
+		kind, w, ok := strings.Cut(strings.TrimSpace(r), "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not '<kind>=<weight>'", r)
+		}
+		if !slices.Contains(mixedOps, kind) {
+			return nil, fmt.Errorf("unknown kind of request %q", kind)
+		}
+		weight, err := strconv.ParseFloat(w, 64)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight %q of %s", w, kind)
+		}
+		weights[kind] = weight
+		total += weight
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("no request has a positive weight")
+	}
+	return weights, nil
+}
+
+func pickMixedOp(rnd *rand.Rand, weights map[string]float64) string {
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	x := rnd.Float64() * total
+	var last string
+	for _, kind := range mixedOps {
+		w := weights[kind]
+		if w == 0 {
+			continue
+		}
+		if x < w {
+			return kind
+		}
+		x -= w
+		last = kind
+	}
+	return last
+}
+
+// newKeyGenerator returns a function drawing the indexes of the keys of the
+// requests in the key space with the given distribution.
+func newKeyGenerator(rnd *rand.Rand, distribution string, size int) (func() uint64, error) {
+	switch distribution {
+	case "uniform":
+		return func() uint64 { return uint64(rnd.Intn(size)) }, nil
+	case "zipfian":
+		z := rand.NewZipf(rnd, mixedZipfS, mixedZipfV, uint64(size-1))
+		if z == nil {
+			return nil, fmt.Errorf("invalid zipfian parameters (--zipf-s %v must be greater than 1, --zipf-v %v at least 1)", mixedZipfS, mixedZipfV)
+		}
+		return z.Uint64, nil
+	default:
+		return nil, fmt.Errorf("unknown distribution %q", distribution)
+	}
+}